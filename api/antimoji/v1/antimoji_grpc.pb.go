@@ -0,0 +1,235 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: antimoji.proto
+
+// Package antimoji.v1 is the gRPC interface of antimoji serve. It scans and cleans
+// content with the policy of the profile the server was started with, like the
+// HTTP API, for clients that send many files.
+
+package antimojiv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Antimoji_Scan_FullMethodName       = "/antimoji.v1.Antimoji/Scan"
+	Antimoji_Clean_FullMethodName      = "/antimoji.v1.Antimoji/Clean"
+	Antimoji_StreamScan_FullMethodName = "/antimoji.v1.Antimoji/StreamScan"
+)
+
+// AntimojiClient is the client API for Antimoji service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Antimoji applies the emoji policy of a profile to the files sent.
+type AntimojiClient interface {
+	// Scan reports the emojis the policy does not allow in the files sent.
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error)
+	// Clean returns the files sent with their emojis removed.
+	Clean(ctx context.Context, in *CleanRequest, opts ...grpc.CallOption) (*CleanResponse, error)
+	// StreamScan reports the emojis of each file as it is received: one ScannedFile,
+	// in order, for each File sent.
+	StreamScan(ctx context.Context, opts ...grpc.CallOption) (Antimoji_StreamScanClient, error)
+}
+
+type antimojiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAntimojiClient(cc grpc.ClientConnInterface) AntimojiClient {
+	return &antimojiClient{cc}
+}
+
+func (c *antimojiClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScanResponse)
+	err := c.cc.Invoke(ctx, Antimoji_Scan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *antimojiClient) Clean(ctx context.Context, in *CleanRequest, opts ...grpc.CallOption) (*CleanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CleanResponse)
+	err := c.cc.Invoke(ctx, Antimoji_Clean_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *antimojiClient) StreamScan(ctx context.Context, opts ...grpc.CallOption) (Antimoji_StreamScanClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Antimoji_ServiceDesc.Streams[0], Antimoji_StreamScan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &antimojiStreamScanClient{ClientStream: stream}
+	return x, nil
+}
+
+type Antimoji_StreamScanClient interface {
+	Send(*File) error
+	Recv() (*ScannedFile, error)
+	grpc.ClientStream
+}
+
+type antimojiStreamScanClient struct {
+	grpc.ClientStream
+}
+
+func (x *antimojiStreamScanClient) Send(m *File) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *antimojiStreamScanClient) Recv() (*ScannedFile, error) {
+	m := new(ScannedFile)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AntimojiServer is the server API for Antimoji service.
+// All implementations must embed UnimplementedAntimojiServer
+// for forward compatibility
+//
+// Antimoji applies the emoji policy of a profile to the files sent.
+type AntimojiServer interface {
+	// Scan reports the emojis the policy does not allow in the files sent.
+	Scan(context.Context, *ScanRequest) (*ScanResponse, error)
+	// Clean returns the files sent with their emojis removed.
+	Clean(context.Context, *CleanRequest) (*CleanResponse, error)
+	// StreamScan reports the emojis of each file as it is received: one ScannedFile,
+	// in order, for each File sent.
+	StreamScan(Antimoji_StreamScanServer) error
+	mustEmbedUnimplementedAntimojiServer()
+}
+
+// UnimplementedAntimojiServer must be embedded to have forward compatible implementations.
+type UnimplementedAntimojiServer struct {
+}
+
+func (UnimplementedAntimojiServer) Scan(context.Context, *ScanRequest) (*ScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedAntimojiServer) Clean(context.Context, *CleanRequest) (*CleanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Clean not implemented")
+}
+func (UnimplementedAntimojiServer) StreamScan(Antimoji_StreamScanServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamScan not implemented")
+}
+func (UnimplementedAntimojiServer) mustEmbedUnimplementedAntimojiServer() {}
+
+// UnsafeAntimojiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AntimojiServer will
+// result in compilation errors.
+type UnsafeAntimojiServer interface {
+	mustEmbedUnimplementedAntimojiServer()
+}
+
+func RegisterAntimojiServer(s grpc.ServiceRegistrar, srv AntimojiServer) {
+	s.RegisterService(&Antimoji_ServiceDesc, srv)
+}
+
+func _Antimoji_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AntimojiServer).Scan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Antimoji_Scan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AntimojiServer).Scan(ctx, req.(*ScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Antimoji_Clean_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AntimojiServer).Clean(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Antimoji_Clean_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AntimojiServer).Clean(ctx, req.(*CleanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Antimoji_StreamScan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AntimojiServer).StreamScan(&antimojiStreamScanServer{ServerStream: stream})
+}
+
+type Antimoji_StreamScanServer interface {
+	Send(*ScannedFile) error
+	Recv() (*File, error)
+	grpc.ServerStream
+}
+
+type antimojiStreamScanServer struct {
+	grpc.ServerStream
+}
+
+func (x *antimojiStreamScanServer) Send(m *ScannedFile) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *antimojiStreamScanServer) Recv() (*File, error) {
+	m := new(File)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Antimoji_ServiceDesc is the grpc.ServiceDesc for Antimoji service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Antimoji_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "antimoji.v1.Antimoji",
+	HandlerType: (*AntimojiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Scan",
+			Handler:    _Antimoji_Scan_Handler,
+		},
+		{
+			MethodName: "Clean",
+			Handler:    _Antimoji_Clean_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamScan",
+			Handler:       _Antimoji_StreamScan_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "antimoji.proto",
+}
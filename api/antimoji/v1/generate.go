@@ -0,0 +1,3 @@
+package antimojiv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative antimoji.proto
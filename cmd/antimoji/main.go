@@ -43,13 +43,22 @@ func main() {
 		ServiceVersion: serviceVersion,
 	}
 
+	// Apply --log-level and --log-format before the logger is created
+	if err := app.ApplyLogFlags(config, os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(app.ExitConfigError)
+	}
+
 	// Create dependencies
 	deps, err := app.NewDependencies(config)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize application dependencies: %v\n", err)
-		os.Exit(1)
+		os.Exit(app.ExitConfigError)
 	}
 
+	// Packages without injected dependencies, such as the processor, log globally
+	logging.SetGlobalLogger(deps.Logger)
+
 	// Ensure resources are released on exit
 	defer func() {
 		if err := deps.Close(context.Background()); err != nil {
@@ -61,12 +70,12 @@ func main() {
 	application, err := app.New(deps)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create application: %v\n", err)
-		os.Exit(1)
+		os.Exit(app.ExitConfigError)
 	}
 
 	// Run application
 	if err := application.Run(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(app.ExitCode(err))
 	}
 }
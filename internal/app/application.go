@@ -4,21 +4,35 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/antimoji/antimoji/internal/app/commands"
+	"github.com/antimoji/antimoji/internal/infra/release"
+	"github.com/antimoji/antimoji/internal/infra/remoteconfig"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/antimoji/antimoji/internal/observability/tracing"
 	"github.com/spf13/cobra"
 )
 
 // Application represents the main application with its dependencies.
 type Application struct {
-	deps    *Dependencies
-	rootCmd *cobra.Command
-	ctx     context.Context
-	cancel  context.CancelFunc
+	deps          *Dependencies
+	rootCmd       *cobra.Command
+	ctx           context.Context
+	cancel        context.CancelFunc
+	metricsServer *metrics.Server
+	stopTracing   func(context.Context) error
+	// releases reads releases for the update check; when nil, one is built from the
+	// configuration
+	releases *release.Client
+	// updateNotice receives the newest release found by the update check, which is
+	// reported on noticeOut (defaults to stderr)
+	updateNotice chan string
+	noticeOut    io.Writer
 }
 
 // New creates a new Application instance with the given dependencies.
@@ -54,13 +68,85 @@ func (a *Application) Run(args []string) error {
 	a.rootCmd.SetArgs(args)
 
 	// Execute the command
-	if err := a.rootCmd.ExecuteContext(a.ctx); err != nil {
+	err := a.rootCmd.ExecuteContext(a.ctx)
+	a.printUpdateNotice()
+	a.stopMetrics()
+	a.flushTracing()
+	if err != nil {
 		return fmt.Errorf("command execution failed: %w", err)
 	}
 
 	return nil
 }
 
+// startMetrics serves the application metrics on addr until the command finishes.
+func (a *Application) startMetrics(addr string) error {
+	if a.deps.Metrics == nil {
+		return fmt.Errorf("metrics are not available")
+	}
+
+	server, err := metrics.Serve(addr, a.deps.Metrics.Registry())
+	if err != nil {
+		return fmt.Errorf("failed to start metrics endpoint: %w", err)
+	}
+
+	a.metricsServer = server
+	a.deps.Logger.Info(a.ctx, "Metrics endpoint started", "addr", server.Addr())
+	return nil
+}
+
+// startTracing exports spans to the OTLP endpoint, falling back to the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT variable.
+func (a *Application) startTracing(endpoint string) error {
+	if endpoint == "" {
+		endpoint = os.Getenv(tracing.EndpointEnv)
+	}
+	if endpoint == "" {
+		return nil
+	}
+
+	stop, err := tracing.Setup(tracing.Config{
+		Endpoint:       endpoint,
+		ServiceName:    "antimoji",
+		ServiceVersion: a.getBuildVersion(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start tracing: %w", err)
+	}
+
+	a.stopTracing = stop
+	a.deps.Logger.Info(a.ctx, "Tracing enabled", "endpoint", endpoint)
+	return nil
+}
+
+// flushTracing exports any buffered spans and stops tracing, if it was started.
+func (a *Application) flushTracing() {
+	if a.stopTracing == nil {
+		return
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := a.stopTracing(shutdownCtx); err != nil {
+		a.deps.Logger.Warn(a.ctx, "Failed to export traces", "error", err)
+	}
+	a.stopTracing = nil
+}
+
+// stopMetrics shuts down the metrics endpoint, if one was started.
+func (a *Application) stopMetrics() {
+	if a.metricsServer == nil {
+		return
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := a.metricsServer.Shutdown(shutdownCtx); err != nil {
+		a.deps.Logger.Warn(a.ctx, "Failed to stop metrics endpoint", "error", err)
+	}
+	a.metricsServer = nil
+}
+
 // Shutdown gracefully shuts down the application.
 func (a *Application) Shutdown() error {
 	// Create a fresh timeout-bound context for cleanup
@@ -132,22 +218,72 @@ Built with Go using functional programming principles, Antimoji provides:
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		Version:       a.getBuildVersion(),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Hooks pinned by setup-lint --pin-version fail before running another version
+			if required, _ := cmd.Flags().GetString("require-version"); required != "" {
+				if err := commands.RequireVersion(required, a.getBuildVersion()); err != nil {
+					return err
+				}
+			}
+			// Remote configs are resolved deep inside config loading, which reads the
+			// environment like it does for the cache directory
+			if offline, _ := cmd.Flags().GetBool("offline"); offline {
+				if err := os.Setenv(remoteconfig.EnvOffline, "1"); err != nil {
+					return err
+				}
+			}
+			a.startUpdateCheck(cmd)
+			endpoint, _ := cmd.Flags().GetString("otel-endpoint")
+			if err := a.startTracing(endpoint); err != nil {
+				return err
+			}
+			if addr, _ := cmd.Flags().GetString("metrics-addr"); addr != "" {
+				return a.startMetrics(addr)
+			}
+			return nil
+		},
 	}
 
 	// Add global persistent flags
-	cmd.PersistentFlags().String("config", "", "config file path")
+	cmd.PersistentFlags().String("config", "", "config file path, https:// URL or github:owner/repo//path@ref")
+	cmd.PersistentFlags().Bool("offline", false, "resolve remote configs from the local cache only")
 	cmd.PersistentFlags().String("profile", "default", "configuration profile")
 	cmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output (deprecated, use --log-level=info)")
 	cmd.PersistentFlags().BoolP("quiet", "q", false, "quiet mode (deprecated, use --log-level=silent)")
 	cmd.PersistentFlags().Bool("dry-run", false, "show what would be changed without modifying files")
 	cmd.PersistentFlags().String("log-level", "silent", "log level (silent, debug, info, warn, error)")
 	cmd.PersistentFlags().String("log-format", "json", "log format (json, text)")
+	cmd.PersistentFlags().String("otel-endpoint", "", "export OpenTelemetry traces to this OTLP/HTTP endpoint (e.g. http://localhost:4318; default $OTEL_EXPORTER_OTLP_ENDPOINT)")
+	cmd.PersistentFlags().String("metrics-addr", "", "serve Prometheus metrics on this address while running (e.g. :9090)")
+	cmd.PersistentFlags().Bool("strict-config", false, "fail when the config file contains unknown keys")
+	cmd.PersistentFlags().StringArray("set", nil, "override a profile field (key=value, repeatable; e.g. --set max_file_size=10MB)")
+	cmd.PersistentFlags().String("require-version", "", "fail unless this is the given antimoji release (e.g. v1.2.3); set by hooks generated with setup-lint --pin-version")
 
 	// Add subcommands with dependency injection
 	cmd.AddCommand(a.createScanCommand())
 	cmd.AddCommand(a.createCleanCommand())
+	cmd.AddCommand(a.createFilterCommand())
+	cmd.AddCommand(a.createTailCommand())
+	cmd.AddCommand(a.createCheckCommand())
+	cmd.AddCommand(a.createUndoCommand())
 	cmd.AddCommand(a.createGenerateCommand())
+	cmd.AddCommand(a.createAllowlistCommand())
 	cmd.AddCommand(a.createSetupLintCommand())
+	cmd.AddCommand(a.createInitCommand())
+	cmd.AddCommand(a.createHookCommand())
+	cmd.AddCommand(a.createCacheCommand())
+	cmd.AddCommand(a.createConfigCommand())
+	cmd.AddCommand(a.createStatsCommand())
+	cmd.AddCommand(a.createLintCommand())
+	cmd.AddCommand(a.createTrendCommand())
+	cmd.AddCommand(a.createExplainCommand())
+	cmd.AddCommand(a.createEmojiCommand())
+	cmd.AddCommand(a.createBenchCommand())
+	cmd.AddCommand(a.createDaemonCommand())
+	cmd.AddCommand(a.createServeCommand())
+	cmd.AddCommand(a.createBotCommand())
+	cmd.AddCommand(a.createDoctorCommand())
+	cmd.AddCommand(a.createUpgradeCommand())
 	cmd.AddCommand(a.createVersionCommand())
 
 	return cmd
@@ -163,12 +299,32 @@ func (a *Application) getBuildVersion() string {
 // as we refactor each command to use dependency injection.
 
 func (a *Application) createScanCommand() *cobra.Command {
-	handler := commands.NewScanHandler(a.deps.Logger, a.deps.UI)
+	handler := commands.NewScanHandler(a.deps.Logger, a.deps.UI).WithMetrics(a.deps.Metrics)
 	return handler.CreateCommand()
 }
 
 func (a *Application) createCleanCommand() *cobra.Command {
-	handler := commands.NewCleanHandler(a.deps.Logger, a.deps.UI)
+	handler := commands.NewCleanHandler(a.deps.Logger, a.deps.UI).WithMetrics(a.deps.Metrics)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createFilterCommand() *cobra.Command {
+	handler := commands.NewFilterHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createServeCommand() *cobra.Command {
+	handler := commands.NewServeHandler(a.deps.Logger, a.deps.UI).WithMetrics(a.deps.Metrics)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createBotCommand() *cobra.Command {
+	handler := commands.NewBotHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createTailCommand() *cobra.Command {
+	handler := commands.NewTailHandler(a.deps.Logger, a.deps.UI).WithMetrics(a.deps.Metrics)
 	return handler.CreateCommand()
 }
 
@@ -177,8 +333,88 @@ func (a *Application) createGenerateCommand() *cobra.Command {
 	return handler.CreateCommand()
 }
 
+func (a *Application) createAllowlistCommand() *cobra.Command {
+	handler := commands.NewAllowlistHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
 func (a *Application) createSetupLintCommand() *cobra.Command {
-	handler := commands.NewSetupLintHandler(a.deps.Logger, a.deps.UI)
+	handler := commands.NewSetupLintHandler(a.deps.Logger, a.deps.UI).WithVersion(a.getBuildVersion())
+	return handler.CreateCommand()
+}
+
+func (a *Application) createHookCommand() *cobra.Command {
+	handler := commands.NewHookHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createCacheCommand() *cobra.Command {
+	handler := commands.NewCacheHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createConfigCommand() *cobra.Command {
+	handler := commands.NewConfigHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createStatsCommand() *cobra.Command {
+	handler := commands.NewStatsHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createUndoCommand() *cobra.Command {
+	handler := commands.NewUndoHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createCheckCommand() *cobra.Command {
+	handler := commands.NewCheckHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createLintCommand() *cobra.Command {
+	handler := commands.NewLintHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createTrendCommand() *cobra.Command {
+	handler := commands.NewTrendHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createInitCommand() *cobra.Command {
+	handler := commands.NewInitHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createDoctorCommand() *cobra.Command {
+	handler := commands.NewDoctorHandler(a.deps.Logger, a.deps.UI).WithVersion(a.getBuildVersion())
+	return handler.CreateCommand()
+}
+
+func (a *Application) createUpgradeCommand() *cobra.Command {
+	handler := commands.NewUpgradeHandler(a.deps.Logger, a.deps.UI).WithVersion(a.getBuildVersion())
+	return handler.CreateCommand()
+}
+
+func (a *Application) createExplainCommand() *cobra.Command {
+	handler := commands.NewExplainHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createEmojiCommand() *cobra.Command {
+	handler := commands.NewEmojiHandler(a.deps.Logger, a.deps.UI)
+	return handler.CreateCommand()
+}
+
+func (a *Application) createBenchCommand() *cobra.Command {
+	handler := commands.NewBenchHandler(a.deps.Logger, a.deps.UI).WithVersion(a.getBuildVersion())
+	return handler.CreateCommand()
+}
+
+func (a *Application) createDaemonCommand() *cobra.Command {
+	handler := commands.NewDaemonHandler(a.deps.Logger, a.deps.UI).WithMetrics(a.deps.Metrics)
 	return handler.CreateCommand()
 }
 
@@ -1,8 +1,18 @@
 package app
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/infra/release"
+	"github.com/antimoji/antimoji/internal/infra/remoteconfig"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -48,6 +58,50 @@ func TestApplication_Run(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("serves metrics while a command runs", func(t *testing.T) {
+		deps := NewTestDependencies()
+		app, err := New(deps)
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("// 🚀\n"), 0600))
+
+		err = app.Run([]string{"--metrics-addr", "127.0.0.1:0", "scan", dir})
+		assert.NoError(t, err)
+		assert.Nil(t, app.metricsServer, "endpoint is stopped when the command finishes")
+
+		var out bytes.Buffer
+		require.NoError(t, deps.Metrics.Registry().WriteText(&out))
+		assert.Contains(t, out.String(), "antimoji_files_scanned_total 1\n")
+		assert.Contains(t, out.String(), "antimoji_emojis_found_total 1\n")
+	})
+
+	t.Run("reports an unusable metrics address", func(t *testing.T) {
+		app, err := New(NewTestDependencies())
+		require.NoError(t, err)
+
+		err = app.Run([]string{"--metrics-addr", "not-an-address", "version"})
+		assert.ErrorContains(t, err, "failed to start metrics endpoint")
+	})
+
+	t.Run("reports an invalid tracing endpoint", func(t *testing.T) {
+		app, err := New(NewTestDependencies())
+		require.NoError(t, err)
+
+		err = app.Run([]string{"--otel-endpoint", "ftp://collector", "version"})
+		assert.ErrorContains(t, err, "failed to start tracing")
+	})
+
+	t.Run("fails when another version is required", func(t *testing.T) {
+		app, err := New(NewTestDependencies())
+		require.NoError(t, err)
+
+		assert.NoError(t, app.Run([]string{"--require-version=v0.9.16", "version"}))
+		err = app.Run([]string{"--require-version=v1.0.0", "version"})
+		assert.ErrorContains(t, err, "antimoji v1.0.0 is required")
+		assert.Equal(t, ExitConfigError, ExitCode(err))
+	})
+
 	t.Run("scan command works with dependency injection", func(t *testing.T) {
 		deps := NewTestDependencies()
 		app, err := New(deps)
@@ -74,10 +128,12 @@ func TestApplication_Run(t *testing.T) {
 		app, err := New(deps)
 		require.NoError(t, err)
 
-		// Generate command should work with DI but return placeholder error
-		err = app.Run([]string{"generate", "."})
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "complex implementation pending")
+		// Generate command merges the generated profile into a config file
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Launch ✅\n"), 0600))
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, app.Run([]string{"generate", "--merge-into", configPath, dir}))
+		assert.FileExists(t, configPath)
 	})
 
 	t.Run("setup-lint command uses dependency injection", func(t *testing.T) {
@@ -85,10 +141,64 @@ func TestApplication_Run(t *testing.T) {
 		app, err := New(deps)
 		require.NoError(t, err)
 
-		// Setup-lint command should work with DI but return placeholder error
-		err = app.Run([]string{"setup-lint", "."})
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "complex implementation pending")
+		dir := t.TempDir()
+		require.NoError(t, app.Run([]string{"setup-lint", "--skip-precommit", dir}))
+		assert.FileExists(t, filepath.Join(dir, ".antimoji.yaml"))
+		assert.FileExists(t, filepath.Join(dir, ".pre-commit-config.yaml"))
+	})
+}
+
+func TestApplication_UpdateCheck(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`[{"tag_name": "v1.0.0-rc.1", "prerelease": true}, {"tag_name": "v0.9.17"}]`))
+	}))
+	defer server.Close()
+	run := func(t *testing.T, args ...string) string {
+		app, err := New(NewTestDependencies())
+		require.NoError(t, err)
+		app.releases = release.NewClient().WithClient(server.Client()).WithAPIURL(server.URL)
+		var notice bytes.Buffer
+		app.noticeOut = &notice
+		require.NoError(t, app.Run(args))
+		return notice.String()
+	}
+
+	t.Run("is off unless enabled", func(t *testing.T) {
+		t.Setenv(cache.EnvDir, t.TempDir())
+		assert.Empty(t, run(t, "version"))
+		assert.Zero(t, requests.Load())
+	})
+
+	t.Run("notes a newer release at most once a day", func(t *testing.T) {
+		t.Setenv(cache.EnvDir, t.TempDir())
+		t.Setenv(release.EnvUpdateCheck, "true")
+		requests.Store(0)
+
+		assert.Equal(t, "antimoji v0.9.17 is available (this is 0.9.16-refactor); run 'antimoji upgrade' to install it\n", run(t, "version"))
+		assert.Contains(t, run(t, "version"), "v0.9.17 is available")
+		assert.Equal(t, int32(1), requests.Load(), "the second run reuses the recorded check")
+		assert.Empty(t, run(t, "--quiet", "version"))
+	})
+
+	t.Run("follows the configured channel", func(t *testing.T) {
+		t.Setenv(cache.EnvDir, t.TempDir())
+		configFile := filepath.Join(t.TempDir(), ".antimoji.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("upgrade:\n  check: true\n  channel: prerelease\nprofiles:\n  default: {}\n"), 0600))
+		assert.Contains(t, run(t, "--config", configFile, "version"), "v1.0.0-rc.1 is available")
+
+		t.Setenv(release.EnvUpdateCheck, "false")
+		assert.Empty(t, run(t, "--config", configFile, "version"), "the environment disables the check for CI")
+	})
+
+	t.Run("is off in offline mode", func(t *testing.T) {
+		t.Setenv(cache.EnvDir, t.TempDir())
+		t.Setenv(release.EnvUpdateCheck, "true")
+		t.Setenv(remoteconfig.EnvOffline, "")
+		requests.Store(0)
+		assert.Empty(t, run(t, "--offline", "version"))
+		assert.Zero(t, requests.Load())
 	})
 }
 
@@ -126,3 +236,28 @@ func TestApplication_Shutdown(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestAllowlistFlagParity(t *testing.T) {
+	app, err := New(NewTestDependencies())
+	require.NoError(t, err)
+
+	var withFlags []string
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if cmd.Flags().Lookup("ignore-allowlist") != nil {
+			withFlags = append(withFlags, cmd.CommandPath())
+			respect := cmd.Flags().Lookup("respect-allowlist")
+			if assert.NotNil(t, respect, cmd.CommandPath()) {
+				assert.NotEmpty(t, respect.Deprecated, cmd.CommandPath())
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(app.GetRootCommand())
+
+	for _, command := range []string{"scan", "lint", "check", "clean", "filter", "tail", "hook commit-msg", "explain", "emoji test", "serve", "bot github", "generate"} {
+		assert.Contains(t, withFlags, "antimoji "+command)
+	}
+}
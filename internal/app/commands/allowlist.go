@@ -0,0 +1,565 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// AllowlistAuditOptions holds the options for the allowlist audit command.
+type AllowlistAuditOptions struct {
+	Recursive    bool
+	Format       string
+	Prune        bool
+	FailOnStale  bool
+	ConfigFile   string
+	ProfileName  string
+	Overrides    []string
+	StrictConfig bool
+}
+
+// AllowlistOptions holds the options for the allowlist add, remove and list commands.
+type AllowlistOptions struct {
+	Format       string
+	ConfigFile   string
+	ProfileName  string
+	StrictConfig bool
+}
+
+// AllowlistHandler handles the allowlist command with dependency injection.
+type AllowlistHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewAllowlistHandler creates a new allowlist command handler.
+func NewAllowlistHandler(logger logging.Logger, ui ui.UserOutput) *AllowlistHandler {
+	return &AllowlistHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the audit report and lists (defaults to stdout).
+func (h *AllowlistHandler) WithOutput(out io.Writer) *AllowlistHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the allowlist cobra command and its subcommands.
+func (h *AllowlistHandler) CreateCommand() *cobra.Command {
+	opts := &AllowlistAuditOptions{}
+	editOpts := &AllowlistOptions{}
+	// rootOptions reads the global flags the add, remove and list commands take
+	rootOptions := func(cmd *cobra.Command) {
+		editOpts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+		editOpts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+		editOpts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+	}
+
+	cmd := &cobra.Command{
+		Use:   "allowlist",
+		Short: "Manage and inspect the emoji allowlists of the configuration",
+		Long: `Manage the emoji_allowlist of a profile in the configuration file, and audit
+allowlists against the project.
+
+add and remove edit the file in place, keeping its comments and layout, so hooks and
+bots can manage the allowlist without rewriting the file. They edit --config, or
+.antimoji.yaml when it is not given, and the profile --profile selects.
+
+Examples:
+  antimoji allowlist add 🚀 ✅ --profile ci      # Allow emojis in the ci profile
+  antimoji allowlist remove 🚀 --profile ci      # Stop allowing an emoji
+  antimoji allowlist list --profile ci           # One allowed emoji per line
+  antimoji allowlist audit .                     # Find stale entries`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:           "add emoji...",
+		Short:         "Add emojis to the allowlist of a profile",
+		Args:          cobra.MinimumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootOptions(cmd)
+			return h.ExecuteAdd(cmd.Context(), args, editOpts)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:           "remove emoji...",
+		Short:         "Remove emojis from the allowlist of a profile",
+		Args:          cobra.MinimumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootOptions(cmd)
+			return h.ExecuteRemove(cmd.Context(), args, editOpts)
+		},
+	})
+	list := &cobra.Command{
+		Use:           "list",
+		Short:         "List the allowlist of a profile",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootOptions(cmd)
+			return h.ExecuteList(cmd.Context(), editOpts)
+		},
+	}
+	list.Flags().StringVar(&editOpts.Format, "format", "text", "output format (text, json)")
+	cmd.AddCommand(list)
+
+	audit := &cobra.Command{
+		Use:   "audit [flags] [path...]",
+		Short: "Find allowlist entries that are stale or wider than they need to be",
+		Long: `Scan the project and check each emoji_allowlist entry of the selected profile,
+and of the profiles the configuration's rules route files to, against where the
+emoji is actually used.
+
+An entry is reported as:
+  unused   - no file checked under the profile uses it; remove it
+  outside  - it is also used in files routed to profiles that do not allow it
+  scoped   - every use is under one directory; allow it there instead, e.g. with
+             'antimoji generate --per-directory'
+  used     - it is needed as it is
+
+Examples:
+  antimoji allowlist audit .                          # Report on every entry
+  antimoji allowlist audit --prune .                  # Remove the unused entries from --config
+  antimoji allowlist audit --fail-on-stale .          # Fail in CI while unused entries remain
+  antimoji allowlist audit --format json . > audit.json`,
+		Args:          cobra.MinimumNArgs(0),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.ExecuteAudit(cmd.Context(), args, opts)
+		},
+	}
+	audit.Flags().BoolVarP(&opts.Recursive, "recursive", "r", true, "scan directories recursively")
+	audit.Flags().StringVar(&opts.Format, "format", "table", "output format (table, json)")
+	audit.Flags().BoolVar(&opts.Prune, "prune", false, "remove the unused entries from the config file")
+	audit.Flags().BoolVar(&opts.FailOnStale, "fail-on-stale", false, "exit with the violations code when unused entries remain")
+	cmd.AddCommand(audit)
+
+	return cmd
+}
+
+// editTarget returns the configuration file and profile add, remove and list work on.
+func (o *AllowlistOptions) editTarget() (configPath, profileName string) {
+	configPath, profileName = o.ConfigFile, o.ProfileName
+	if configPath == "" {
+		configPath = defaultConfigFile
+	}
+	if profileName == "" {
+		profileName = "default"
+	}
+	return configPath, profileName
+}
+
+// ExecuteAdd adds emojis to the allowlist of the selected profile in the configuration
+// file, creating the file or profile when missing.
+func (h *AllowlistHandler) ExecuteAdd(parentCtx context.Context, emojis []string, opts *AllowlistOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "allowlist-add")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	configPath, profileName := opts.editTarget()
+	added, err := config.AddToAllowlist(configPath, profileName, emojis)
+	if err != nil {
+		return classify(ErrConfig, fmt.Errorf("failed to update %s: %w", configPath, err))
+	}
+
+	h.logger.Info(ctx, "Allowlist updated", "config_file", configPath, "profile", profileName, "added", added)
+	if len(added) == 0 {
+		h.ui.Info(ctx, "Profile %s in %s already allows %s", profileName, configPath, strings.Join(emojis, " "))
+		return nil
+	}
+	h.ui.Success(ctx, "Added %s to profile %s in %s", strings.Join(added, " "), profileName, configPath)
+	return nil
+}
+
+// ExecuteRemove removes emojis from the allowlist of the selected profile in the
+// configuration file.
+func (h *AllowlistHandler) ExecuteRemove(parentCtx context.Context, emojis []string, opts *AllowlistOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "allowlist-remove")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	configPath, profileName := opts.editTarget()
+	if _, err := os.Stat(configPath); err != nil {
+		return classify(ErrConfig, fmt.Errorf("failed to update %s: %w", configPath, err))
+	}
+	removed, err := config.RemoveFromAllowlist(configPath, profileName, emojis)
+	if err != nil {
+		return classify(ErrConfig, fmt.Errorf("failed to update %s: %w", configPath, err))
+	}
+
+	h.logger.Info(ctx, "Allowlist updated", "config_file", configPath, "profile", profileName, "removed", removed)
+	if len(removed) == 0 {
+		h.ui.Info(ctx, "Profile %s in %s does not list %s", profileName, configPath, strings.Join(emojis, " "))
+		return nil
+	}
+	h.ui.Success(ctx, "Removed %s from profile %s in %s", strings.Join(removed, " "), profileName, configPath)
+	return nil
+}
+
+// ExecuteList prints the allowlist of the selected profile, one emoji per line or as
+// a JSON array.
+func (h *AllowlistHandler) ExecuteList(parentCtx context.Context, opts *AllowlistOptions) error {
+	format := strings.ToLower(opts.Format)
+	if format != "text" && format != "json" {
+		return classify(ErrConfig, fmt.Errorf("unsupported format %q; supported: text, json", opts.Format))
+	}
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "allowlist-list")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	configPath, profileName := opts.editTarget()
+	configResult := loadConfigFile(configPath, opts.StrictConfig)
+	if configResult.IsErr() {
+		return classify(ErrConfig, fmt.Errorf("failed to load config: %w", configResult.Error()))
+	}
+	cfg := configResult.Unwrap()
+	showConfigWarnings(ctx, h.ui, cfg)
+	profileResult := config.GetProfile(cfg, profileName)
+	if profileResult.IsErr() {
+		return classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", profileName, profileResult.Error()))
+	}
+	emojis := profileResult.Unwrap().EmojiAllowlist
+	if emojis == nil {
+		emojis = []string{}
+	}
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(emojis)
+	}
+	for _, emoji := range emojis {
+		if _, err := fmt.Fprintln(out, emoji); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Audit statuses of an allowlist entry, from the most to the least pressing.
+const (
+	auditUnused  = "unused"
+	auditOutside = "outside"
+	auditScoped  = "scoped"
+	auditUsed    = "used"
+)
+
+// auditEntry is what the audit found out about one allowlist entry of a profile.
+type auditEntry struct {
+	Profile string `json:"profile"`
+	Emoji   string `json:"emoji"`
+	Status  string `json:"status"`
+	// Uses and Files count the uses in the files checked under the profile
+	Uses  int `json:"uses"`
+	Files int `json:"files"`
+	// Scope is the directory holding every use, when narrower than the profile
+	Scope string `json:"scope,omitempty"`
+	// OutsideUses and OutsideFiles count the uses in files routed to profiles that do
+	// not allow the emoji
+	OutsideUses  int `json:"outside_uses,omitempty"`
+	OutsideFiles int `json:"outside_files,omitempty"`
+}
+
+// suggestion returns what to do about the entry, "" when nothing.
+func (e auditEntry) suggestion() string {
+	switch e.Status {
+	case auditUnused:
+		return fmt.Sprintf("remove from profiles.%s.emoji_allowlist", e.Profile)
+	case auditOutside:
+		return fmt.Sprintf("also used %d times in %d files routed to profiles that do not allow it", e.OutsideUses, e.OutsideFiles)
+	case auditScoped:
+		return fmt.Sprintf("only used under %s/; allow it there instead", e.Scope)
+	}
+	return ""
+}
+
+// auditProfile is a profile whose allowlist is audited.
+type auditProfile struct {
+	name    string
+	entries []string
+	// paths are those of the rules routing files to the profile
+	paths []string
+}
+
+// ExecuteAudit runs the allowlist audit command logic with dependency injection.
+func (h *AllowlistHandler) ExecuteAudit(parentCtx context.Context, args []string, opts *AllowlistAuditOptions) error {
+	format := strings.ToLower(opts.Format)
+	if format != "table" && format != "json" {
+		return classify(ErrConfig, fmt.Errorf("unsupported format %q; supported: table, json", opts.Format))
+	}
+	if opts.Prune && opts.ConfigFile == "" {
+		return classify(ErrConfig, fmt.Errorf("--prune needs the config file to edit; pass --config"))
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "allowlist-audit")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	h.logger.Info(ctx, "Starting allowlist audit", "paths", args, "options", opts)
+
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return classify(ErrConfig, fmt.Errorf("failed to load config: %w", configResult.Error()))
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileName := opts.ProfileName
+	if profileName == "" {
+		profileName = "default"
+	}
+	profileResult := config.GetProfile(cfg, profileName)
+	if profileResult.IsErr() {
+		return classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", profileName, profileResult.Error()))
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return err
+	}
+	profile := resolution.Profile
+
+	router, profiles, err := h.router(ctx, cfg, opts.ConfigFile != "", profileName, profile)
+	if err != nil {
+		return err
+	}
+
+	filePaths, err := filtering.DiscoverFiles(args, filtering.DiscoveryOptions{Recursive: opts.Recursive}, profile)
+	if err != nil {
+		h.logger.Error(ctx, "File discovery failed", "error", err, "paths", args)
+		return classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	patterns, err := emojidata.PatternsForProfile(ctx, detector.DefaultEmojiPatterns(), profile)
+	if err != nil {
+		return fmt.Errorf("failed to load emoji data: %w", err)
+	}
+	results := processor.ProcessFiles(filePaths, patterns, config.ToProcessingConfig(profile))
+
+	entries := auditAllowlists(profiles, router, results)
+	stale := make(map[string][]string)
+	var unused int
+	for _, entry := range entries {
+		if entry.Status == auditUnused {
+			stale[entry.Profile] = append(stale[entry.Profile], entry.Emoji)
+			unused++
+		}
+	}
+	h.logger.Info(ctx, "Allowlist audit completed", "files", len(results), "entries", len(entries), "unused", unused)
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		err = encoder.Encode(entries)
+	} else {
+		err = writeAuditTable(out, entries)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.Prune {
+		for _, p := range profiles {
+			if len(stale[p.name]) == 0 {
+				continue
+			}
+			removed, err := config.RemoveFromAllowlist(opts.ConfigFile, p.name, stale[p.name])
+			if err != nil {
+				return classify(ErrConfig, fmt.Errorf("failed to prune profile %s: %w", p.name, err))
+			}
+			unused -= len(removed)
+			h.logger.Info(ctx, "Allowlist pruned", "config_file", opts.ConfigFile, "profile", p.name, "removed", removed)
+			if len(removed) > 0 {
+				h.ui.Success(ctx, "Removed %d unused emojis from profile %s in %s", len(removed), p.name, opts.ConfigFile)
+			}
+		}
+	} else if unused > 0 {
+		h.ui.Info(ctx, "Run with --prune to remove the %d unused entries", unused)
+	}
+
+	if opts.FailOnStale && unused > 0 {
+		return classify(ErrViolations, fmt.Errorf("%d unused allowlist entries", unused))
+	}
+	return nil
+}
+
+// router returns the router sending files to the profiles of the rules of cfg and the
+// others to profile, the selected one named profileName, with the profiles to audit:
+// the selected one first, then the routed ones in rule order.
+func (h *AllowlistHandler) router(ctx context.Context, cfg config.Config, fromFile bool, profileName string, profile config.Profile) (*policy.Router, []auditProfile, error) {
+	opts := policy.Options{Operation: "allowlist-audit"}
+	engine, err := policy.New(ctx, profile, opts)
+	if err != nil {
+		return nil, nil, classify(ErrConfig, err)
+	}
+	engines := map[string]*policy.Engine{profileName: engine}
+	profiles := []auditProfile{{name: profileName, entries: profile.EmojiAllowlist}}
+	index := map[string]int{profileName: 0}
+
+	routes := make([]policy.Route, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		routed, ok := engines[rule.Profile]
+		if !ok {
+			profileResult := config.GetProfile(cfg, rule.Profile)
+			if profileResult.IsErr() {
+				return nil, nil, classify(ErrConfig, fmt.Errorf("rules: %w", profileResult.Error()))
+			}
+			resolution, err := resolveProfile(profileResult.Unwrap(), fromFile, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			if routed, err = policy.New(ctx, resolution.Profile, opts); err != nil {
+				return nil, nil, classify(ErrConfig, fmt.Errorf("profile %s: %w", rule.Profile, err))
+			}
+			engines[rule.Profile] = routed
+			index[rule.Profile] = len(profiles)
+			profiles = append(profiles, auditProfile{name: rule.Profile, entries: resolution.Profile.EmojiAllowlist})
+		}
+		profiles[index[rule.Profile]].paths = append(profiles[index[rule.Profile]].paths, rule.Paths...)
+		routes = append(routes, policy.Route{Paths: rule.Paths, Profile: rule.Profile, Engine: routed})
+	}
+	return policy.NewRouter(profileName, engine, routes), profiles, nil
+}
+
+// auditAllowlists checks each allowlist entry of profiles against the emojis of
+// results, which the allowlist was not applied to, and returns an entry for each in
+// order.
+func auditAllowlists(profiles []auditProfile, router *policy.Router, results []types.ProcessResult) []auditEntry {
+	var entries []auditEntry
+	for _, p := range profiles {
+		for _, emoji := range p.entries {
+			matcher := allowlist.NewAllowlist([]string{emoji}).Unwrap()
+			entry := auditEntry{Profile: p.name, Emoji: emoji}
+			var files []string
+			for _, result := range results {
+				if result.Error != nil {
+					continue
+				}
+				routedTo, engine := router.Route(result.FilePath)
+				uses, outside := 0, 0
+				for _, match := range result.DetectionResult.Emojis {
+					if !matcher.IsAllowed(match.Emoji) {
+						continue
+					}
+					if routedTo == p.name {
+						uses++
+					} else if engine.IsViolation(match.Emoji) {
+						outside++
+					}
+				}
+				if uses > 0 {
+					entry.Uses += uses
+					files = append(files, routePath(result.FilePath))
+				}
+				if outside > 0 {
+					entry.OutsideUses += outside
+					entry.OutsideFiles++
+				}
+			}
+			entry.Files = len(files)
+			if dir := commonDir(files); dir != "" && !coversDir(p.paths, dir) {
+				entry.Scope = dir
+			}
+
+			switch {
+			case entry.Uses == 0:
+				entry.Status = auditUnused
+			case entry.OutsideUses > 0:
+				entry.Status = auditOutside
+			case entry.Scope != "":
+				entry.Status = auditScoped
+			default:
+				entry.Status = auditUsed
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// coversDir reports whether the rule paths of a profile are those of directory dir, so
+// that uses under dir only are not narrower than the profile.
+func coversDir(paths []string, dir string) bool {
+	for _, pattern := range paths {
+		if pattern == dir || pattern == dir+"/**" || pattern == dir+"/**/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAuditTable prints the audit as an aligned table.
+func writeAuditTable(out io.Writer, entries []auditEntry) error {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		counts[entry.Status]++
+	}
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Audited %d allowlist entries: %d unused, %d used outside their profile, %d narrower than their profile\n",
+		len(entries), counts[auditUnused], counts[auditOutside], counts[auditScoped])
+	if len(entries) == 0 {
+		return tw.Flush()
+	}
+
+	_, _ = fmt.Fprintf(tw, "\nPROFILE\tEMOJI\tSTATUS\tUSES\tFILES\tSUGGESTION\n")
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\n", entry.Profile, entry.Emoji, entry.Status, entry.Uses, entry.Files, entry.suggestion())
+	}
+	return tw.Flush()
+}
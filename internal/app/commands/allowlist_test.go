@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditProject writes a project whose default profile allows 🚀 everywhere, 🎉
+// nowhere and ✨ only in scripts, and whose docs profile allows 🔥, also used outside
+// docs, and 💡, used nowhere. It returns the path of its configuration, kept out of
+// the project so that its emojis are not scanned.
+func auditProject(t *testing.T) string {
+	configPath := filepath.Join(t.TempDir(), ".antimoji.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`# Team configuration
+rules:
+  - paths: ["docs/**"]
+    profile: docs
+profiles:
+  default:
+    emoji_allowlist: ["🚀", "🎉", "✨"]
+  docs:
+    emoji_allowlist: ["🔥", "💡"]
+`), 0600))
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"README.md":          "# Launch 🚀\n",
+		"main.go":            "// 🚀 🔥\n",
+		"scripts/release.sh": "# ✨ ✨\n",
+		"docs/guide.md":      "# Hot 🔥\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+	return configPath
+}
+
+func runAudit(t *testing.T, opts AllowlistAuditOptions) (string, error) {
+	opts.Recursive = true
+	if opts.Format == "" {
+		opts.Format = "table"
+	}
+	var out bytes.Buffer
+	err := NewAllowlistHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out).
+		ExecuteAudit(context.Background(), []string{"."}, &opts)
+	return out.String(), err
+}
+
+func TestAllowlistHandler_Audit(t *testing.T) {
+	configPath := auditProject(t)
+
+	out, err := runAudit(t, AllowlistAuditOptions{ConfigFile: configPath, Format: "json"})
+	require.NoError(t, err)
+	var entries []auditEntry
+	require.NoError(t, json.Unmarshal([]byte(out), &entries))
+	assert.Equal(t, []auditEntry{
+		{Profile: "default", Emoji: "🚀", Status: auditUsed, Uses: 2, Files: 2},
+		{Profile: "default", Emoji: "🎉", Status: auditUnused},
+		{Profile: "default", Emoji: "✨", Status: auditScoped, Uses: 2, Files: 1, Scope: "scripts"},
+		{Profile: "docs", Emoji: "🔥", Status: auditOutside, Uses: 1, Files: 1, OutsideUses: 1, OutsideFiles: 1},
+		{Profile: "docs", Emoji: "💡", Status: auditUnused},
+	}, entries)
+
+	out, err = runAudit(t, AllowlistAuditOptions{ConfigFile: configPath})
+	require.NoError(t, err)
+	assert.Contains(t, out, "Audited 5 allowlist entries: 2 unused, 1 used outside their profile, 1 narrower than their profile")
+	assert.Contains(t, out, "remove from profiles.docs.emoji_allowlist")
+	assert.Contains(t, out, "only used under scripts/")
+
+	_, err = runAudit(t, AllowlistAuditOptions{ConfigFile: configPath, FailOnStale: true})
+	assert.ErrorIs(t, err, ErrViolations)
+
+	t.Run("prune", func(t *testing.T) {
+		_, err := runAudit(t, AllowlistAuditOptions{ConfigFile: configPath, Prune: true, FailOnStale: true})
+		require.NoError(t, err)
+
+		raw, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), "# Team configuration")
+		cfg := config.LoadConfigStrict(configPath).Unwrap()
+		assert.Equal(t, []string{"🚀", "✨"}, cfg.Profiles["default"].EmojiAllowlist)
+		assert.Equal(t, []string{"🔥"}, cfg.Profiles["docs"].EmojiAllowlist)
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		_, err := runAudit(t, AllowlistAuditOptions{Format: "csv"})
+		assert.ErrorIs(t, err, ErrConfig)
+		_, err = runAudit(t, AllowlistAuditOptions{Prune: true})
+		assert.ErrorIs(t, err, ErrConfig)
+	})
+}
+
+func TestAllowlistHandler_Edit(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".antimoji.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`# Team configuration
+profiles:
+  default:
+    max_total: 3 # agreed in review
+  ci:
+    # reviewed allowlist
+    emoji_allowlist: ["✅"]
+`), 0600))
+	handler := NewAllowlistHandler(logging.NewMockLogger(), quietOutput())
+	opts := &AllowlistOptions{ConfigFile: configPath, ProfileName: "ci"}
+	list := func(format string) string {
+		var out bytes.Buffer
+		require.NoError(t, handler.WithOutput(&out).ExecuteList(context.Background(), &AllowlistOptions{ConfigFile: configPath, ProfileName: "ci", Format: format}))
+		return out.String()
+	}
+
+	require.NoError(t, handler.ExecuteAdd(context.Background(), []string{"🚀", "✅", "🎉"}, opts))
+	assert.Equal(t, "✅\n🚀\n🎉\n", list("text"))
+
+	require.NoError(t, handler.ExecuteRemove(context.Background(), []string{"✅", "🔥"}, opts))
+	assert.Equal(t, "[\"🚀\",\"🎉\"]\n", list("json"))
+
+	raw, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "# Team configuration")
+	assert.Contains(t, string(raw), "# agreed in review")
+	assert.Contains(t, string(raw), "# reviewed allowlist")
+	assert.Equal(t, 3, config.LoadConfigStrict(configPath).Unwrap().Profiles["default"].MaxTotal)
+
+	t.Run("adds a missing profile", func(t *testing.T) {
+		require.NoError(t, handler.ExecuteAdd(context.Background(), []string{"✨"}, &AllowlistOptions{ConfigFile: configPath, ProfileName: "docs"}))
+		assert.Equal(t, []string{"✨"}, config.LoadConfigStrict(configPath).Unwrap().Profiles["docs"].EmojiAllowlist)
+	})
+
+	t.Run("rejects a missing file and unknown formats", func(t *testing.T) {
+		missing := &AllowlistOptions{ConfigFile: filepath.Join(t.TempDir(), "missing.yaml")}
+		assert.ErrorIs(t, handler.ExecuteRemove(context.Background(), []string{"🚀"}, missing), ErrConfig)
+		assert.ErrorIs(t, handler.ExecuteList(context.Background(), &AllowlistOptions{ConfigFile: configPath, Format: "csv"}), ErrConfig)
+	})
+}
@@ -0,0 +1,269 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/infra/bench"
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// BenchOptions holds the options for the bench command.
+type BenchOptions struct {
+	Duration      time.Duration
+	Workers       []int
+	Files         int
+	FileSize      int
+	Format        string
+	Baseline      string
+	MaxRegression float64
+	ConfigFile    string
+	ProfileName   string
+	Overrides     []string
+	StrictConfig  bool
+}
+
+// BenchHandler handles the bench command with dependency injection.
+type BenchHandler struct {
+	logger  logging.Logger
+	ui      ui.UserOutput
+	out     io.Writer
+	version string
+}
+
+// NewBenchHandler creates a new bench command handler.
+func NewBenchHandler(logger logging.Logger, ui ui.UserOutput) *BenchHandler {
+	return &BenchHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the report (defaults to stdout).
+func (h *BenchHandler) WithOutput(out io.Writer) *BenchHandler {
+	h.out = out
+	return h
+}
+
+// WithVersion sets the version of the running binary, recorded in reports.
+func (h *BenchHandler) WithVersion(version string) *BenchHandler {
+	h.version = version
+	return h
+}
+
+// CreateCommand creates the bench cobra command.
+func (h *BenchHandler) CreateCommand() *cobra.Command {
+	opts := &BenchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bench [flags] [path...]",
+		Short: "Benchmark detection and cleaning throughput",
+		Long: `Measure how fast antimoji detects and cleans emojis, with the patterns of the
+selected profile.
+
+The corpus is a reproducible set of synthetic files, or the files under the
+given paths, read into memory before measuring. Detection is measured with each
+--workers count to help size worker counts, cleaning with one worker. Each
+benchmark runs for --duration and reports operations (passes over the whole
+corpus), files and megabytes per second, and allocations per operation.
+
+With --baseline, the report is compared with a report saved by an earlier run,
+and the command fails when the throughput of a benchmark dropped by more than
+--max-regression percent.
+
+Examples:
+  antimoji bench                                    # Synthetic corpus, JSON report
+  antimoji bench --format table .                   # Benchmark on this repository
+  antimoji bench > bench.json                       # Save a baseline
+  antimoji bench --baseline bench.json --max-regression 5`,
+		Args:          cobra.MinimumNArgs(0),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.Execute(cmd.Context(), args, opts)
+		},
+	}
+
+	workers := []int{1}
+	if runtime.NumCPU() > 1 {
+		workers = append(workers, runtime.NumCPU())
+	}
+	cmd.Flags().DurationVar(&opts.Duration, "duration", time.Second, "how long each benchmark runs")
+	cmd.Flags().IntSliceVar(&opts.Workers, "workers", workers, "worker counts to benchmark detection with")
+	cmd.Flags().IntVar(&opts.Files, "files", 200, "number of files in the synthetic corpus")
+	cmd.Flags().IntVar(&opts.FileSize, "file-size", 16*1024, "approximate size in bytes of synthetic files")
+	cmd.Flags().StringVar(&opts.Format, "format", "json", "output format (json, table)")
+	cmd.Flags().StringVar(&opts.Baseline, "baseline", "", "JSON report of an earlier run to compare with")
+	cmd.Flags().Float64Var(&opts.MaxRegression, "max-regression", 10, "throughput drop from the baseline tolerated, in percent")
+
+	return cmd
+}
+
+// Execute runs the benchmarks, prints the report and compares it with the baseline.
+func (h *BenchHandler) Execute(parentCtx context.Context, args []string, opts *BenchOptions) error {
+	format := strings.ToLower(opts.Format)
+	if format != "json" && format != "table" {
+		return classify(ErrConfig, fmt.Errorf("unsupported format %q; supported: json, table", opts.Format))
+	}
+	if err := validateBenchOptions(opts); err != nil {
+		return classify(ErrConfig, err)
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "bench")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	var baseline *bench.Report
+	if opts.Baseline != "" {
+		report, err := readBenchReport(opts.Baseline)
+		if err != nil {
+			return err
+		}
+		baseline = &report
+	}
+
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return classify(ErrConfig, fmt.Errorf("failed to load config: %w", configResult.Error()))
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error()))
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return err
+	}
+	profile := resolution.Profile
+
+	corpus := bench.Synthetic(opts.Files, opts.FileSize)
+	if len(args) > 0 {
+		filePaths, err := filtering.DiscoverFiles(args, filtering.DiscoveryOptions{Recursive: true}, profile)
+		if err != nil {
+			return classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+		}
+		if corpus, err = bench.Load(strings.Join(args, " "), filePaths); err != nil {
+			return classify(ErrIO, fmt.Errorf("failed to read corpus: %w", err))
+		}
+		if len(corpus.Files) == 0 {
+			return classify(ErrConfig, fmt.Errorf("no files to benchmark in %s", strings.Join(args, ", ")))
+		}
+	}
+
+	patterns, err := emojidata.PatternsForProfile(ctx, detector.DefaultEmojiPatterns(), profile)
+	if err != nil {
+		return fmt.Errorf("failed to load emoji data: %w", err)
+	}
+
+	h.logger.Info(ctx, "Starting benchmarks", "corpus", corpus.Name, "files", len(corpus.Files),
+		"bytes", corpus.Bytes, "duration", opts.Duration, "workers", opts.Workers)
+	report := bench.Run(ctx, corpus, patterns, config.ToProcessingConfig(profile), bench.Options{
+		Duration: opts.Duration,
+		Workers:  opts.Workers,
+	})
+	report.Version = h.version
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = writeBenchTable(out, report)
+	}
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to write benchmark report: %w", err))
+	}
+
+	if baseline == nil {
+		return nil
+	}
+	regressions := bench.Compare(*baseline, report, opts.MaxRegression/100)
+	for _, regression := range regressions {
+		h.ui.Error(ctx, "Performance regression: %s", regression)
+	}
+	if len(regressions) > 0 {
+		return classify(ErrViolations, fmt.Errorf("%d benchmarks regressed by more than %g%% from %s",
+			len(regressions), opts.MaxRegression, opts.Baseline))
+	}
+	h.logger.Info(ctx, "No performance regressions", "baseline", opts.Baseline)
+	return nil
+}
+
+// validateBenchOptions checks the numeric flags of the bench command.
+func validateBenchOptions(opts *BenchOptions) error {
+	switch {
+	case opts.Duration <= 0:
+		return fmt.Errorf("--duration must be positive")
+	case len(opts.Workers) == 0:
+		return fmt.Errorf("--workers needs at least one worker count")
+	case opts.Files <= 0 || opts.FileSize <= 0:
+		return fmt.Errorf("--files and --file-size must be positive")
+	case opts.MaxRegression < 0:
+		return fmt.Errorf("--max-regression cannot be negative")
+	}
+	for _, workers := range opts.Workers {
+		if workers <= 0 {
+			return fmt.Errorf("--workers must be positive, got %d", workers)
+		}
+	}
+	return nil
+}
+
+// readBenchReport reads a JSON report saved by bench.
+func readBenchReport(path string) (bench.Report, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path given on the command line
+	if err != nil {
+		return bench.Report{}, classify(ErrIO, fmt.Errorf("failed to read baseline: %w", err))
+	}
+	var report bench.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return bench.Report{}, classify(ErrConfig, fmt.Errorf("invalid baseline %s: %w", path, err))
+	}
+	return report, nil
+}
+
+// writeBenchTable prints the report as an aligned table.
+func writeBenchTable(out io.Writer, report bench.Report) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Corpus %s: %d files, %d bytes (%s %s/%s, %d CPUs)\n\n",
+		report.Corpus.Name, report.Corpus.Files, report.Corpus.Bytes, report.GoVersion, report.OS, report.Arch, report.CPUs)
+	_, _ = fmt.Fprintf(tw, "BENCHMARK\tWORKERS\tOPS/SEC\tFILES/SEC\tMB/SEC\tALLOCS/OP\tBYTES/OP\n")
+	for _, result := range report.Results {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%.2f\t%.0f\t%.2f\t%d\t%d\n", result.Name, result.Workers,
+			result.OpsPerSec, result.FilesPerSec, result.MBPerSec, result.AllocsPerOp, result.BytesPerOp)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/bench"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func benchOptions() *BenchOptions {
+	return &BenchOptions{
+		Duration:      time.Millisecond,
+		Workers:       []int{1},
+		Files:         2,
+		FileSize:      256,
+		Format:        "json",
+		MaxRegression: 10,
+	}
+}
+
+func TestBenchHandler_Execute(t *testing.T) {
+	run := func(t *testing.T, args []string, opts *BenchOptions) (bench.Report, error) {
+		var out bytes.Buffer
+		handler := NewBenchHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out).WithVersion("1.2.3")
+		err := handler.Execute(context.Background(), args, opts)
+		var report bench.Report
+		if out.Len() > 0 {
+			require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		}
+		return report, err
+	}
+
+	t.Run("synthetic corpus", func(t *testing.T) {
+		report, err := run(t, nil, benchOptions())
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3", report.Version)
+		assert.Equal(t, bench.SyntheticCorpus, report.Corpus.Name)
+		assert.Equal(t, 2, report.Corpus.Files)
+		require.Len(t, report.Results, 2)
+		assert.Equal(t, bench.Detect, report.Results[0].Name)
+		assert.Equal(t, bench.Clean, report.Results[1].Name)
+	})
+
+	t.Run("files under a path", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// 🚀\n"), 0600))
+		report, err := run(t, []string{dir}, benchOptions())
+		require.NoError(t, err)
+		assert.Equal(t, bench.CorpusInfo{Name: dir, Files: 1, Bytes: 8}, report.Corpus)
+	})
+
+	t.Run("fails on a regression from the baseline", func(t *testing.T) {
+		baseline := filepath.Join(t.TempDir(), "baseline.json")
+		data, err := json.Marshal(bench.Report{Results: []bench.Result{{Name: bench.Detect, Workers: 1, MBPerSec: 1e9}}})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(baseline, data, 0600))
+
+		opts := benchOptions()
+		opts.Baseline = baseline
+		_, err = run(t, nil, opts)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrViolations))
+		assert.Contains(t, err.Error(), "1 benchmarks regressed by more than 10%")
+	})
+
+	t.Run("passes within the tolerance", func(t *testing.T) {
+		baseline := filepath.Join(t.TempDir(), "baseline.json")
+		data, err := json.Marshal(bench.Report{Results: []bench.Result{{Name: bench.Detect, Workers: 1, MBPerSec: 1e-9}}})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(baseline, data, 0600))
+
+		opts := benchOptions()
+		opts.Baseline = baseline
+		_, err = run(t, nil, opts)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for name, modify := range map[string]func(*BenchOptions){
+			"format":   func(o *BenchOptions) { o.Format = "xml" },
+			"duration": func(o *BenchOptions) { o.Duration = 0 },
+			"workers":  func(o *BenchOptions) { o.Workers = []int{0} },
+			"baseline": func(o *BenchOptions) { o.Baseline = filepath.Join(t.TempDir(), "missing.json") },
+		} {
+			opts := benchOptions()
+			modify(opts)
+			_, err := run(t, nil, opts)
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestBenchHandler_Table(t *testing.T) {
+	var out bytes.Buffer
+	opts := benchOptions()
+	opts.Format = "table"
+	require.NoError(t, NewBenchHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out).Execute(context.Background(), nil, opts))
+	assert.Contains(t, out.String(), "Corpus synthetic: 2 files")
+	assert.Contains(t, out.String(), "BENCHMARK  WORKERS  OPS/SEC")
+}
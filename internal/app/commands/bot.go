@@ -0,0 +1,526 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/git"
+	"github.com/antimoji/antimoji/internal/infra/github"
+	"github.com/antimoji/antimoji/internal/infra/httpclient"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// EnvGitHubWebhookSecret names the secret webhook deliveries are signed with.
+	EnvGitHubWebhookSecret = "GITHUB_WEBHOOK_SECRET"
+
+	// EnvGitHubToken names the token the bot authenticates with when no App is given.
+	EnvGitHubToken = "GITHUB_TOKEN"
+
+	// maxWebhookSize is the largest webhook payload GitHub delivers.
+	maxWebhookSize = 25 << 20
+)
+
+// BotOptions holds the options for the bot github command.
+type BotOptions struct {
+	AppID           int64
+	PrivateKey      string
+	InstallationID  int64
+	APIURL          string
+	Listen          string
+	Repo            string
+	PullRequest     int
+	StatusContext   string
+	IgnoreAllowlist bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+}
+
+// BotHandler handles the bot commands with dependency injection.
+type BotHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+}
+
+// NewBotHandler creates a new bot command handler.
+func NewBotHandler(logger logging.Logger, ui ui.UserOutput) *BotHandler {
+	return &BotHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// CreateCommand creates the bot cobra command.
+func (h *BotHandler) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bot",
+		Short: "Enforce the emoji policy on pull requests",
+		Long: `Run antimoji as a bot that reviews pull requests on a code host.
+
+The bot scans only the lines a pull request adds, comments on each line with an
+emoji the policy does not allow, and sets a commit status that fails when the
+violations exceed the profile's max_total (none by default).`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(h.createGitHubCommand())
+	return cmd
+}
+
+// createGitHubCommand creates the bot github cobra command.
+func (h *BotHandler) createGitHubCommand() *cobra.Command {
+	opts := &BotOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "github",
+		Short: "Review GitHub pull requests as a GitHub App",
+		Long: `Review GitHub pull requests as a GitHub App.
+
+With --listen, the bot serves the App's webhook: each pull_request event that
+opens, reopens or pushes to a pull request starts a review of the lines it adds.
+Deliveries must be signed with the App's webhook secret, read from
+$GITHUB_WEBHOOK_SECRET. Point the App's webhook URL at the server and subscribe
+it to pull request events; it needs read access to contents and pull requests,
+and write access to pull requests and commit statuses.
+
+With --repo and --pr, the bot reviews a single pull request and exits 1 when its
+status fails, for polling from cron or CI.
+
+The bot authenticates as the installation of the App given by --app-id and
+--private-key; --installation-id selects the installation for --pr, while webhook
+events carry their own. Without --app-id, $GITHUB_TOKEN is used instead.
+
+Review comments the bot already posted are not posted again. The profile is the
+bot's own configuration, not one read from the reviewed repositories.
+
+Examples:
+  antimoji bot github --listen :8080 --app-id 12345 --private-key app.pem
+  antimoji bot github --repo acme/api --pr 42 --app-id 12345 --private-key app.pem --installation-id 678
+  GITHUB_TOKEN=... antimoji bot github --repo acme/api --pr 42`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.ExecuteGitHub(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().Int64Var(&opts.AppID, "app-id", 0, "ID of the GitHub App to authenticate as")
+	cmd.Flags().StringVar(&opts.PrivateKey, "private-key", "", "PEM private key file of the GitHub App")
+	cmd.Flags().Int64Var(&opts.InstallationID, "installation-id", 0, "App installation to act as with --pr")
+	cmd.Flags().StringVar(&opts.APIURL, "api-url", "", "GitHub API URL, for GitHub Enterprise Server (default https://api.github.com)")
+	cmd.Flags().StringVar(&opts.Listen, "listen", "", "serve the webhook on this address (e.g. :8080)")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "repository of the pull request to review, as owner/name")
+	cmd.Flags().IntVar(&opts.PullRequest, "pr", 0, "number of the pull request to review")
+	cmd.Flags().StringVar(&opts.StatusContext, "status-context", "antimoji", "name of the commit status set")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+
+	return cmd
+}
+
+// ExecuteGitHub serves the GitHub webhook, or reviews the pull request opts name.
+func (h *BotHandler) ExecuteGitHub(parentCtx context.Context, opts *BotOptions) error {
+	if (opts.Listen == "") == (opts.Repo == "" && opts.PullRequest == 0) {
+		return classify(ErrConfig, fmt.Errorf("give either --listen, or --repo and --pr"))
+	}
+	if opts.Listen == "" && (opts.Repo == "" || opts.PullRequest <= 0) {
+		return classify(ErrConfig, fmt.Errorf("--repo and --pr must be given together"))
+	}
+	if opts.StatusContext == "" {
+		opts.StatusContext = "antimoji"
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = ctxutil.WithOperation(ctx, "bot")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	bot, err := h.bot(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if opts.Listen != "" {
+		return h.serveWebhook(ctx, bot, opts)
+	}
+
+	client, err := bot.client(opts.InstallationID)
+	if err != nil {
+		return classify(ErrConfig, err)
+	}
+	pr, err := client.PullRequest(ctx, opts.Repo, opts.PullRequest)
+	if err != nil {
+		return classify(ErrIO, err)
+	}
+	review, err := bot.review(ctx, client, opts.Repo, opts.PullRequest, pr.Head.SHA)
+	if err != nil {
+		return classify(ErrIO, err)
+	}
+	h.ui.Result(ctx, "%s#%d: %s (%d new review comments)", opts.Repo, opts.PullRequest, review.Description, review.Comments)
+	if review.State == github.StateFailure {
+		return classify(ErrViolations, fmt.Errorf("%s#%d: %s", opts.Repo, opts.PullRequest, review.Description))
+	}
+	return nil
+}
+
+// bot resolves the profile and the GitHub credentials of opts.
+func (h *BotHandler) bot(ctx context.Context, opts *BotOptions) (*githubBot, error) {
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return nil, classify(ErrConfig, fmt.Errorf("failed to load config: %w", configResult.Error()))
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return nil, classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error()))
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return nil, err
+	}
+	engine, err := policy.New(ctx, resolution.Profile, policy.Options{
+		Operation:       "bot",
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       policy.ResolveThreshold(0, false, profileThreshold(resolution), 0),
+		Rules:           resolution.Policy.Rules(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := httpclient.New(httpclient.CAFile(""), 0)
+	if err != nil {
+		return nil, classify(ErrConfig, err)
+	}
+	bot := &githubBot{
+		logger:        h.logger,
+		engine:        engine,
+		patterns:      patterns,
+		statusContext: opts.StatusContext,
+	}
+	switch {
+	case opts.AppID != 0:
+		if opts.PrivateKey == "" {
+			return nil, classify(ErrConfig, fmt.Errorf("--app-id needs --private-key"))
+		}
+		key, err := os.ReadFile(opts.PrivateKey)
+		if err != nil {
+			return nil, classify(ErrConfig, fmt.Errorf("failed to read the private key: %w", err))
+		}
+		bot.app, err = github.NewApp(httpClient, opts.APIURL, opts.AppID, key)
+		if err != nil {
+			return nil, classify(ErrConfig, fmt.Errorf("invalid private key %s: %w", opts.PrivateKey, err))
+		}
+	case os.Getenv(EnvGitHubToken) != "":
+		bot.token = github.NewClient(httpClient, opts.APIURL, os.Getenv(EnvGitHubToken))
+	default:
+		return nil, classify(ErrConfig, fmt.Errorf("give --app-id and --private-key, or set $%s", EnvGitHubToken))
+	}
+	return bot, nil
+}
+
+// serveWebhook reviews the pull requests of the webhook events delivered to
+// opts.Listen until ctx is cancelled.
+func (h *BotHandler) serveWebhook(ctx context.Context, bot *githubBot, opts *BotOptions) error {
+	secret := os.Getenv(EnvGitHubWebhookSecret)
+	if secret == "" {
+		return classify(ErrConfig, fmt.Errorf("--listen needs the webhook secret in $%s", EnvGitHubWebhookSecret))
+	}
+	listener, err := net.Listen("tcp", opts.Listen)
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to listen on %s: %w", opts.Listen, err))
+	}
+
+	// Reviews outlive the deliveries starting them, but not the server
+	var reviews sync.WaitGroup
+	server := &http.Server{
+		Handler:           bot.webhook(ctx, []byte(secret), &reviews),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	h.logger.Info(ctx, "GitHub bot started", "addr", listener.Addr().String())
+	h.ui.Info(ctx, "Antimoji GitHub bot listening on %s", listener.Addr())
+	err = server.Serve(listener)
+	reviews.Wait()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return classify(ErrIO, err)
+	}
+	h.logger.Info(ctx, "GitHub bot stopped", "addr", listener.Addr().String())
+	return nil
+}
+
+// githubBot reviews GitHub pull requests with the policy of a profile.
+type githubBot struct {
+	logger        logging.Logger
+	engine        *policy.Engine
+	patterns      types.EmojiPatterns
+	statusContext string
+	// app authenticates as the installations of a GitHub App; token is used instead
+	// when there is no App
+	app   *github.App
+	token *github.Client
+}
+
+// client returns the client acting as installation id of the App, or the token
+// client without an App.
+func (b *githubBot) client(installationID int64) (*github.Client, error) {
+	if b.app == nil {
+		return b.token, nil
+	}
+	if installationID == 0 {
+		return nil, fmt.Errorf("the GitHub App needs an installation ID; give --installation-id")
+	}
+	return b.app.Installation(installationID), nil
+}
+
+// pullRequestReview is the outcome of the review of a pull request.
+type pullRequestReview struct {
+	State       string
+	Description string
+	Violations  int
+	Warnings    int
+	// Comments counts the review comments posted, leaving out those already on the
+	// pull request
+	Comments int
+}
+
+// review reviews the lines pull request number of repo adds at commit head, posting
+// review comments on those with emojis and setting the commit status.
+func (b *githubBot) review(ctx context.Context, client *github.Client, repo string, number int, head string) (pullRequestReview, error) {
+	b.logger.Info(ctx, "Reviewing pull request", "repo", repo, "number", number, "head", head)
+	pending := github.Status{State: github.StatePending, Context: b.statusContext, Description: "Checking the added lines for emojis"}
+	if err := client.CreateStatus(ctx, repo, head, pending); err != nil {
+		return pullRequestReview{}, err
+	}
+
+	comments, review, err := b.findings(ctx, client, repo, number)
+	if err == nil {
+		comments, err = b.newComments(ctx, client, repo, number, comments)
+	}
+	if err == nil && len(comments) > 0 {
+		review.Comments = len(comments)
+		err = client.CreateReview(ctx, repo, number, github.Review{
+			CommitID: head,
+			Body:     fmt.Sprintf("antimoji found emojis on %d added lines.", len(comments)),
+			Event:    "COMMENT",
+			Comments: comments,
+		})
+	}
+	if err != nil {
+		failed := github.Status{State: github.StateError, Context: b.statusContext, Description: "The emoji check failed to run"}
+		_ = client.CreateStatus(ctx, repo, head, failed)
+		return pullRequestReview{}, err
+	}
+
+	review.State, review.Description = github.StateSuccess, "No emojis in the added lines"
+	switch {
+	case b.engine.Evaluate(review.Violations) != nil:
+		review.State = github.StateFailure
+		review.Description = fmt.Sprintf("%d emojis in the added lines", review.Violations)
+		if b.engine.Threshold() > 0 {
+			review.Description += fmt.Sprintf(" (limit %d)", b.engine.Threshold())
+		}
+	case review.Violations > 0:
+		review.Description = fmt.Sprintf("%d emojis in the added lines, within the limit of %d", review.Violations, b.engine.Threshold())
+	case review.Warnings > 0:
+		review.Description = fmt.Sprintf("%d discouraged emojis in the added lines", review.Warnings)
+	}
+	final := github.Status{State: review.State, Context: b.statusContext, Description: review.Description}
+	if err := client.CreateStatus(ctx, repo, head, final); err != nil {
+		return pullRequestReview{}, err
+	}
+	b.logger.Info(ctx, "Reviewed pull request", "repo", repo, "number", number,
+		"state", review.State, "violations", review.Violations, "comments", review.Comments)
+	return review, nil
+}
+
+// findings returns a review comment for each line pull request number of repo adds
+// with emojis, and counts their violations and warnings.
+func (b *githubBot) findings(ctx context.Context, client *github.Client, repo string, number int) ([]github.ReviewComment, pullRequestReview, error) {
+	var review pullRequestReview
+	files, err := client.PullRequestFiles(ctx, repo, number)
+	if err != nil {
+		return nil, review, err
+	}
+
+	processing := b.engine.ProcessingConfig()
+	var comments []github.ReviewComment
+	for _, file := range files {
+		// Removed, binary and very large files have no patch to review
+		if file.Patch == "" || file.Status == "removed" || !b.engine.FileFilter().ShouldInclude(file.Filename).Include {
+			continue
+		}
+		lines, err := git.PatchAddedLines(file.Filename, file.Patch)
+		if err != nil {
+			b.logger.Warn(ctx, "Skipping unreadable patch", "repo", repo, "path", file.Filename, "error", err)
+			continue
+		}
+		for _, line := range lines {
+			detection := processor.DetectContent([]byte(line.Text), b.patterns, processing)
+			if detection.IsErr() {
+				continue
+			}
+			result := b.engine.Apply([]types.ProcessResult{{FilePath: line.Path, DetectionResult: detection.Unwrap()}})[0]
+			matches := result.DetectionResult.Emojis
+			if len(matches) == 0 {
+				continue
+			}
+			for _, match := range matches {
+				if match.Severity == types.SeverityWarn {
+					review.Warnings++
+				} else {
+					review.Violations++
+				}
+			}
+			comments = append(comments, github.ReviewComment{
+				Path: line.Path,
+				Line: line.Line,
+				Side: "RIGHT",
+				Body: reviewCommentBody(matches),
+			})
+		}
+	}
+	return comments, review, nil
+}
+
+// reviewCommentBody describes the emojis of a line in a review comment.
+func reviewCommentBody(matches []types.EmojiMatch) string {
+	var errs, warnings []string
+	for _, match := range matches {
+		if match.Severity == types.SeverityWarn {
+			warnings = append(warnings, "`"+match.Display()+"`")
+		} else {
+			errs = append(errs, "`"+match.Display()+"`")
+		}
+	}
+	sentence := func(emojis []string, verdict string) string {
+		if len(emojis) == 1 {
+			return "Emoji " + emojis[0] + " is " + verdict + "."
+		}
+		return "Emojis " + strings.Join(emojis, ", ") + " are " + verdict + "."
+	}
+	var sentences []string
+	if len(errs) > 0 {
+		sentences = append(sentences, sentence(errs, "not allowed here"))
+	}
+	if len(warnings) > 0 {
+		sentences = append(sentences, sentence(warnings, "discouraged"))
+	}
+	return strings.Join(sentences, " ") + " (antimoji)"
+}
+
+// newComments leaves out of comments those already on pull request number of repo.
+func (b *githubBot) newComments(ctx context.Context, client *github.Client, repo string, number int, comments []github.ReviewComment) ([]github.ReviewComment, error) {
+	if len(comments) == 0 {
+		return nil, nil
+	}
+	existing, err := client.ReviewComments(ctx, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	posted := make(map[github.ReviewComment]bool, len(existing))
+	for _, comment := range existing {
+		posted[github.ReviewComment{Path: comment.Path, Line: comment.Line, Body: comment.Body}] = true
+	}
+	fresh := comments[:0]
+	for _, comment := range comments {
+		if !posted[github.ReviewComment{Path: comment.Path, Line: comment.Line, Body: comment.Body}] {
+			fresh = append(fresh, comment)
+		}
+	}
+	return fresh, nil
+}
+
+// webhook returns the handler of the webhook deliveries signed with secret. Reviews
+// run in the background, tracked by reviews, under ctx.
+func (b *githubBot) webhook(ctx context.Context, secret []byte, reviews *sync.WaitGroup) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("POST /", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookSize))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("failed to read the delivery: %w", err))
+			return
+		}
+		if err := github.VerifySignature(secret, body, r.Header.Get(github.HeaderSignature)); err != nil {
+			b.logger.Warn(r.Context(), "Rejected webhook delivery", "error", err)
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		event := r.Header.Get(github.HeaderEvent)
+		if event != "pull_request" {
+			writeJSON(w, http.StatusOK, map[string]string{"status": "ignored", "event": event})
+			return
+		}
+		payload, err := github.ParsePullRequestEvent(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if !payload.Changed() {
+			writeJSON(w, http.StatusOK, map[string]string{"status": "ignored", "action": payload.Action})
+			return
+		}
+		client, err := b.client(payload.Installation.ID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// GitHub gives up on deliveries after ten seconds, so reviews finish later
+		reviews.Add(1)
+		go func() {
+			defer reviews.Done()
+			_, err := b.review(ctx, client, payload.Repository.FullName, payload.Number, payload.PullRequest.Head.SHA)
+			if err != nil {
+				b.logger.Error(ctx, "Failed to review pull request",
+					"repo", payload.Repository.FullName, "number", payload.Number, "error", err)
+			}
+		}()
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "reviewing"})
+	})
+	return mux
+}
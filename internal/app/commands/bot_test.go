@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/github"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub is a GitHub API serving pull request 7 of acme/api and recording the
+// reviews and statuses posted.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	files    []github.PullRequestFile
+	comments []github.ReviewComment
+	reviews  []github.Review
+	statuses []github.Status
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method + " " + r.URL.Path {
+	case "GET /repos/acme/api/pulls/7":
+		_, _ = w.Write([]byte(`{"number": 7, "state": "open", "head": {"sha": "abc"}}`))
+	case "GET /repos/acme/api/pulls/7/files":
+		_ = json.NewEncoder(w).Encode(f.files)
+	case "GET /repos/acme/api/pulls/7/comments":
+		_ = json.NewEncoder(w).Encode(f.comments)
+	case "POST /repos/acme/api/pulls/7/reviews":
+		var review github.Review
+		_ = json.NewDecoder(r.Body).Decode(&review)
+		f.reviews = append(f.reviews, review)
+		f.comments = append(f.comments, review.Comments...)
+	case "POST /repos/acme/api/statuses/abc":
+		var status github.Status
+		_ = json.NewDecoder(r.Body).Decode(&status)
+		f.statuses = append(f.statuses, status)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newFakeGitHub serves a fake GitHub API with the pull request changing files.
+func newFakeGitHub(t *testing.T, files ...github.PullRequestFile) (*fakeGitHub, *BotOptions) {
+	fake := &fakeGitHub{files: files}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	t.Setenv(EnvGitHubToken, "token")
+	return fake, &BotOptions{APIURL: server.URL, ProfileName: "default"}
+}
+
+func TestBotHandler_PullRequest(t *testing.T) {
+	fake, opts := newFakeGitHub(t,
+		github.PullRequestFile{Filename: "main.go", Status: "modified", Patch: "@@ -1,2 +1,3 @@\n package main\n-// old 🎉\n+// new 🚀\n+// fine"},
+		github.PullRequestFile{Filename: "logo.png", Status: "added"},
+		github.PullRequestFile{Filename: "gone.md", Status: "removed", Patch: "@@ -1 +0,0 @@\n-🔥"},
+	)
+	opts.Repo, opts.PullRequest = "acme/api", 7
+	handler := NewBotHandler(logging.NewMockLogger(), quietOutput())
+
+	err := handler.ExecuteGitHub(context.Background(), opts)
+	assert.ErrorIs(t, err, ErrViolations)
+	assert.Contains(t, err.Error(), "1 emojis in the added lines")
+
+	require.Len(t, fake.reviews, 1)
+	assert.Equal(t, "abc", fake.reviews[0].CommitID)
+	assert.Equal(t, "COMMENT", fake.reviews[0].Event)
+	assert.Equal(t, []github.ReviewComment{{
+		Path: "main.go", Line: 2, Side: "RIGHT",
+		Body: "Emoji `🚀` is not allowed here. (antimoji)",
+	}}, fake.reviews[0].Comments)
+	require.Len(t, fake.statuses, 2)
+	assert.Equal(t, github.StatePending, fake.statuses[0].State)
+	assert.Equal(t, github.StateFailure, fake.statuses[1].State)
+	assert.Equal(t, "antimoji", fake.statuses[1].Context)
+
+	// Reviewing again does not repeat the comment
+	assert.ErrorIs(t, handler.ExecuteGitHub(context.Background(), opts), ErrViolations)
+	assert.Len(t, fake.reviews, 1)
+	assert.Len(t, fake.statuses, 4)
+}
+
+func TestBotHandler_PullRequestPasses(t *testing.T) {
+	fake, opts := newFakeGitHub(t,
+		github.PullRequestFile{Filename: "main.go", Status: "added", Patch: "@@ -0,0 +1 @@\n+package main"},
+	)
+	opts.Repo, opts.PullRequest = "acme/api", 7
+
+	require.NoError(t, NewBotHandler(logging.NewMockLogger(), quietOutput()).ExecuteGitHub(context.Background(), opts))
+	assert.Empty(t, fake.reviews)
+	require.Len(t, fake.statuses, 2)
+	assert.Equal(t, github.StateSuccess, fake.statuses[1].State)
+	assert.Equal(t, "No emojis in the added lines", fake.statuses[1].Description)
+}
+
+func TestBotHandler_Webhook(t *testing.T) {
+	fake, opts := newFakeGitHub(t,
+		github.PullRequestFile{Filename: "README.md", Status: "modified", Patch: "@@ -1 +1 @@\n-# API\n+# API 🎉 🔥"},
+	)
+	bot, err := NewBotHandler(logging.NewMockLogger(), quietOutput()).bot(context.Background(), opts)
+	require.NoError(t, err)
+	var reviews sync.WaitGroup
+	webhook := bot.webhook(context.Background(), []byte("secret"), &reviews)
+
+	deliver := func(event, body, secret string) *httptest.ResponseRecorder {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		request := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		request.Header.Set(github.HeaderEvent, event)
+		request.Header.Set(github.HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		recorder := httptest.NewRecorder()
+		webhook.ServeHTTP(recorder, request)
+		return recorder
+	}
+	payload := `{"action": "%s", "number": 7, "pull_request": {"head": {"sha": "abc"}}, "repository": {"full_name": "acme/api"}}`
+
+	assert.Equal(t, http.StatusUnauthorized, deliver("pull_request", strings.Replace(payload, "%s", "opened", 1), "wrong").Code)
+	assert.Equal(t, http.StatusOK, deliver("ping", `{}`, "secret").Code)
+	assert.Equal(t, http.StatusOK, deliver("pull_request", strings.Replace(payload, "%s", "closed", 1), "secret").Code)
+	assert.Equal(t, http.StatusBadRequest, deliver("pull_request", `{"action": "opened"}`, "secret").Code)
+
+	assert.Equal(t, http.StatusAccepted, deliver("pull_request", strings.Replace(payload, "%s", "opened", 1), "secret").Code)
+	reviews.Wait()
+	require.Len(t, fake.reviews, 1)
+	assert.Equal(t, "Emojis `🎉`, `🔥` are not allowed here. (antimoji)", fake.reviews[0].Comments[0].Body)
+	assert.Equal(t, github.StateFailure, fake.statuses[len(fake.statuses)-1].State)
+}
+
+func TestBotHandler_InvalidOptions(t *testing.T) {
+	handler := NewBotHandler(logging.NewMockLogger(), quietOutput())
+	t.Setenv(EnvGitHubToken, "")
+	for _, opts := range []*BotOptions{
+		{},
+		{Listen: ":0", Repo: "acme/api", PullRequest: 7},
+		{Repo: "acme/api"},
+		{Repo: "acme/api", PullRequest: 7, ProfileName: "default"},
+		{Repo: "acme/api", PullRequest: 7, ProfileName: "default", AppID: 1},
+	} {
+		assert.ErrorIs(t, handler.ExecuteGitHub(context.Background(), opts), ErrConfig)
+	}
+}
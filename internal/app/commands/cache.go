@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// CacheOptions holds the options for the cache commands.
+type CacheOptions struct {
+	CacheDir string
+}
+
+// CacheHandler handles the cache command with dependency injection.
+type CacheHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+}
+
+// NewCacheHandler creates a new cache command handler.
+func NewCacheHandler(logger logging.Logger, ui ui.UserOutput) *CacheHandler {
+	return &CacheHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// CreateCommand creates the cache cobra command and its subcommands.
+func (h *CacheHandler) CreateCommand() *cobra.Command {
+	opts := &CacheOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the scan result cache",
+		Long: `Manage the on-disk cache used by 'antimoji scan --cache'.
+
+The cache maps file content hashes to detection results so repeated scans skip
+unchanged files. Results are stored per detection configuration, so changing
+profiles, patterns or emoji data never reuses stale results.
+
+Examples:
+  antimoji cache clear                       # Remove all cached results
+  antimoji cache clear --cache-dir .cache    # Clear a specific cache directory`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.CacheDir, "cache-dir", "", "cache directory (default $ANTIMOJI_CACHE_DIR or the user cache directory)")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:           "clear",
+		Short:         "Remove all cached scan results",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return h.ExecuteClear(cmd.Context(), opts)
+		},
+	})
+
+	return cmd
+}
+
+// ExecuteClear removes all cached scan results.
+func (h *CacheHandler) ExecuteClear(parentCtx context.Context, opts *CacheOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "cache-clear")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	dir := opts.CacheDir
+	if dir == "" {
+		defaultDir, err := cache.DefaultDir()
+		if err != nil {
+			return err
+		}
+		dir = defaultDir
+	}
+
+	removed, err := cache.Clear(dir)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to clear cache", "cache_dir", dir, "error", err)
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	h.logger.Info(ctx, "Cache cleared", "cache_dir", dir, "removed", removed)
+	h.ui.Success(ctx, "Removed %d cache files from %s", removed, dir)
+	return nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheHandler_ExecuteClear(t *testing.T) {
+	dir := t.TempDir()
+	c := cache.Open(dir, cache.Fingerprint(types.EmojiPatterns{}, types.ProcessingConfig{})).Unwrap()
+	c.Put("hash", types.DetectionResult{})
+	require.NoError(t, c.Save())
+
+	handler := NewCacheHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: io.Discard, ErrorWriter: io.Discard}))
+	require.NoError(t, handler.ExecuteClear(context.Background(), &CacheOptions{CacheDir: dir}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	t.Run("missing directory is not an error", func(t *testing.T) {
+		assert.NoError(t, handler.ExecuteClear(context.Background(), &CacheOptions{CacheDir: filepath.Join(dir, "missing")}))
+	})
+}
@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// CheckOptions holds the options for the check command.
+type CheckOptions struct {
+	Recursive       bool
+	Fix             bool
+	IncludePattern  string
+	ExcludePattern  string
+	IgnoreAllowlist bool
+	Threshold       int
+	// ThresholdSet is set when --threshold was given rather than defaulted
+	ThresholdSet bool
+	SummaryFile  string
+	DryRun       bool
+	ConfigFile   string
+	ProfileName  string
+	Overrides    []string
+	StrictConfig bool
+}
+
+// CheckHandler handles the check command with dependency injection.
+type CheckHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewCheckHandler creates a new check command handler.
+func NewCheckHandler(logger logging.Logger, ui ui.UserOutput) *CheckHandler {
+	return &CheckHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the remaining findings (defaults to stdout).
+func (h *CheckHandler) WithOutput(out io.Writer) *CheckHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the check cobra command.
+func (h *CheckHandler) CreateCommand() *cobra.Command {
+	opts := &CheckOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "check [flags] [path...]",
+		Short: "Clean and verify files in one run",
+		Long: `Check files against the emoji policy and, with --fix, clean them first.
+
+Cleaning and verification share one configuration, profile and policy, so the
+files cleaned are exactly the files verified. The emojis left after cleaning are
+printed as path:line:col: message [rule], followed by one summary of the run.
+
+Exits 1 when more emojis remain than --threshold allows, 2 on configuration
+errors, 3 when no file could be read and 4 when some files could not be read.
+
+Examples:
+  antimoji check .                                  # Verify only, like scan
+  antimoji check --fix .                            # Clean in place, then verify
+  antimoji check --fix --threshold 5 src/           # Tolerate up to 5 remaining emojis
+  antimoji check --fix --summary-file .antimoji-summary.json .  # Also write the summary as JSON`,
+		Args:          cobra.MinimumNArgs(0),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.DryRun, _ = cmd.Root().PersistentFlags().GetBool("dry-run")
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			opts.ThresholdSet = cmd.Flags().Changed("threshold")
+			return h.Execute(cmd.Context(), args, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.Recursive, "recursive", "r", true, "check directories recursively")
+	cmd.Flags().BoolVar(&opts.Fix, "fix", false, "remove the emojis the policy does not allow before verifying")
+	cmd.Flags().StringVar(&opts.IncludePattern, "include", "", "include files matching pattern")
+	cmd.Flags().StringVar(&opts.ExcludePattern, "exclude", "", "exclude files matching pattern")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+	cmd.Flags().IntVar(&opts.Threshold, "threshold", 0, "number of remaining emojis tolerated (0 = none, -1 = no limit; default: the profile's max_total, or none)")
+	cmd.Flags().StringVar(&opts.SummaryFile, "summary-file", "", "write a JSON summary of the run (files checked and modified, violations remaining, duration) to this file")
+
+	return cmd
+}
+
+// Execute runs the check command logic with dependency injection.
+func (h *CheckHandler) Execute(parentCtx context.Context, args []string, opts *CheckOptions) error {
+	startTime := time.Now()
+	if opts.Fix && opts.DryRun {
+		return fmt.Errorf("--fix cannot be combined with --dry-run; check without --fix modifies nothing")
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "check")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	h.logger.Info(ctx, "Starting check operation", "paths", args, "options", opts)
+
+	// Resolve the configuration once, for cleaning and verification alike
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return fmt.Errorf("failed to load config: %w", configResult.Error())
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error())
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return err
+	}
+
+	// The profile's max_total applies when --threshold is not given, as in scan
+	threshold := policy.ResolveThreshold(opts.Threshold, opts.ThresholdSet, profileThreshold(resolution), 0)
+	if opts.ThresholdSet {
+		if err := lockedThresholdError(resolution, threshold); err != nil {
+			return err
+		}
+	}
+
+	policyOpts := policy.Options{
+		Operation:       "check",
+		Recursive:       opts.Recursive,
+		IncludePattern:  opts.IncludePattern,
+		ExcludePattern:  opts.ExcludePattern,
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       threshold,
+		Rules:           resolution.Policy.Rules(),
+	}
+	engine, err := policy.New(ctx, resolution.Profile, policyOpts)
+	if err != nil {
+		return err
+	}
+	if err := routeRules(ctx, engine, cfg, opts.ConfigFile != "", opts.ProfileName, policyOpts, opts.ThresholdSet, os.Environ()); err != nil {
+		return err
+	}
+
+	discovery, err := engine.SelectFiles(args)
+	if err != nil {
+		h.logger.Error(ctx, "File discovery failed", "error", err, "paths", args)
+		return classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	reportSkippedSymlinks(ctx, h.ui, discovery.Symlinks)
+	if len(discovery.Files) == 0 {
+		h.ui.Warning(ctx, "No files found matching the criteria")
+		return nil
+	}
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return err
+	}
+
+	summary := RunSummary{Operation: "check", FilesScanned: len(discovery.Files)}
+	failed := map[string]bool{}
+
+	if opts.Fix {
+		results := processor.ModifyFiles(discovery.Files, patterns, policyModifyConfig(engine), engine.Allowlist())
+		for _, result := range results {
+			switch {
+			case result.Error != nil:
+				if isFileFailure(result.Error) {
+					failed[result.FilePath] = true
+				}
+				h.ui.Error(ctx, "Error cleaning %s: %v", result.FilePath, result.Error)
+			case result.Modified:
+				summary.FilesModified++
+				summary.EmojisRemoved += result.EmojisRemoved
+				h.ui.Success(ctx, "Cleaned %s: %d emojis removed", result.FilePath, result.EmojisRemoved)
+			}
+		}
+		h.logger.Info(ctx, "Check clean completed", "files", len(results), "modified", summary.FilesModified)
+	}
+
+	// Verify the files as cleaned
+	results, err := detectRouted(ctx, engine, discovery.Files)
+	if err != nil {
+		return err
+	}
+	findings := lintFindings(engine, results)
+	for _, result := range results {
+		if result.Error != nil && isFileFailure(result.Error) && !failed[result.FilePath] {
+			failed[result.FilePath] = true
+			h.ui.Error(ctx, "%s: %v", result.FilePath, result.Error)
+		}
+	}
+	if err := h.writeFindings(findings); err != nil {
+		return err
+	}
+
+	summary.FilesFailed = len(failed)
+	summary.ViolationsRemaining = len(findings)
+	summary.DurationMS = time.Since(startTime).Milliseconds()
+	h.logger.Info(ctx, "Check completed",
+		"files", summary.FilesScanned, "modified", summary.FilesModified, "remaining", summary.ViolationsRemaining)
+	if opts.Fix {
+		h.ui.Result(ctx, "Checked %d files: cleaned %d (%d emojis removed), %d emojis remaining",
+			summary.FilesScanned, summary.FilesModified, summary.EmojisRemoved, summary.ViolationsRemaining)
+	} else {
+		h.ui.Result(ctx, "Checked %d files: %d emojis found", summary.FilesScanned, summary.ViolationsRemaining)
+	}
+	if err := reportSummary(ctx, h.ui, summary, opts.SummaryFile); err != nil {
+		return err
+	}
+
+	// The threshold and budgets decide the exit code, as they do for scan; warnings
+	// never fail the check
+	violation := evaluateRouted(engine, results)
+	if violation != nil {
+		h.ui.Error(ctx, "Emoji check failed: %v", violation)
+		violation = classify(ErrViolations, violation)
+	}
+	if len(failed) > 0 {
+		return fileFailureError(len(failed), len(discovery.Files), violation)
+	}
+	return violation
+}
+
+// writeFindings prints the remaining findings one per line in the lint format.
+func (h *CheckHandler) writeFindings(findings []LintFinding) error {
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	format := template.Must(template.New("finding").Parse(DefaultLintFormat + "\n"))
+	for _, finding := range findings {
+		if err := format.Execute(out, finding); err != nil {
+			return classify(ErrIO, fmt.Errorf("failed to write finding: %w", err))
+		}
+	}
+	return nil
+}
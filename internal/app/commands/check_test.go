@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHandler_Execute(t *testing.T) {
+	setup := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "main.go")
+		require.NoError(t, os.WriteFile(file, []byte("package main\n\n// ship it 🚀 ✅\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "plain.go"), []byte("package main\n"), 0600))
+		configFile := filepath.Join(t.TempDir(), "antimoji.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte(
+			"profiles:\n  default:\n    unicode_emojis: true\n    text_emoticons: false\n    emoji_allowlist: [\"✅\"]\n"), 0600))
+		return dir, configFile
+	}
+	run := func(t *testing.T, dir string, opts *CheckOptions) (string, error) {
+		var out bytes.Buffer
+		opts.Recursive = true
+		err := NewCheckHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out).Execute(context.Background(), []string{dir}, opts)
+		return out.String(), err
+	}
+
+	t.Run("verifies without modifying", func(t *testing.T) {
+		dir, configFile := setup(t)
+		out, err := run(t, dir, &CheckOptions{ConfigFile: configFile})
+		assert.ErrorIs(t, err, ErrViolations)
+		assert.Equal(t, filepath.Join(dir, "main.go")+":3:12: emoji 🚀 is not allowed [unicode]\n", out)
+
+		content, err := os.ReadFile(filepath.Join(dir, "main.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "🚀")
+	})
+
+	t.Run("fix cleans and verifies with one profile", func(t *testing.T) {
+		dir, configFile := setup(t)
+		summaryFile := filepath.Join(t.TempDir(), "summary.json")
+		out, err := run(t, dir, &CheckOptions{Fix: true, ConfigFile: configFile, SummaryFile: summaryFile})
+		require.NoError(t, err)
+		assert.Empty(t, out)
+
+		content, err := os.ReadFile(filepath.Join(dir, "main.go"))
+		require.NoError(t, err)
+		assert.Equal(t, "package main\n\n// ship it  ✅\n", string(content), "allowlisted emojis are kept")
+
+		data, err := os.ReadFile(summaryFile)
+		require.NoError(t, err)
+		var summary RunSummary
+		require.NoError(t, json.Unmarshal(data, &summary))
+		assert.Equal(t, RunSummary{
+			Operation: "check", FilesScanned: 2, FilesModified: 1, EmojisRemoved: 1, DurationMS: summary.DurationMS,
+		}, summary)
+	})
+
+	t.Run("threshold tolerates remaining emojis", func(t *testing.T) {
+		dir, configFile := setup(t)
+		_, err := run(t, dir, &CheckOptions{ConfigFile: configFile, Threshold: 1, ThresholdSet: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("fix and dry run conflict", func(t *testing.T) {
+		dir, _ := setup(t)
+		_, err := run(t, dir, &CheckOptions{Fix: true, DryRun: true})
+		assert.ErrorContains(t, err, "--fix cannot be combined with --dry-run")
+	})
+}
@@ -3,37 +3,77 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/antimoji/antimoji/internal/config"
 	"github.com/antimoji/antimoji/internal/core/allowlist"
-	"github.com/antimoji/antimoji/internal/core/detector"
 	"github.com/antimoji/antimoji/internal/core/processor"
-	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/infra/quarantine"
+	"github.com/antimoji/antimoji/internal/infra/undo"
 	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
 	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/antimoji/antimoji/internal/observability/tracing"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
 	"github.com/antimoji/antimoji/internal/ui"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // CleanOptions holds the options for the clean command.
 type CleanOptions struct {
-	Recursive        bool
-	Backup           bool
-	Replace          string
-	InPlace          bool
-	RespectAllowlist bool
-	IgnoreAllowlist  bool
-	Stats            bool
-	Benchmark        bool
-	DryRun           bool
+	Recursive       bool
+	Backup          bool
+	Replace         string
+	InPlace         bool
+	IgnoreAllowlist bool
+	Only            []string
+	Except          []string
+	Stats           bool
+	Benchmark       bool
+	DryRun          bool
+	Interactive     bool
+	Diff            bool
+	PatchFile       string
+	IncludeNames    bool
+	Rename          bool
+	Check           bool
+	MaxWorkers      int
+	IncludePattern  string
+	ExcludePattern  string
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+	Verbose         bool
+	MaxErrors       int
+	ErrorReport     string
+	SummaryFile     string
+	Atomic          bool
+	// QuarantineDir, when set, receives the files with violations instead of them
+	// being cleaned
+	QuarantineDir string
+	// ProgressAllowed lets the profile's show_progress draw progress on stderr
+	ProgressAllowed bool
 }
 
+// ErrCleanCheckFailed indicates clean --check found files that would be modified.
+var ErrCleanCheckFailed = errors.New("clean check failed")
+
 // CleanHandler handles the clean command with dependency injection.
 type CleanHandler struct {
-	logger logging.Logger
-	ui     ui.UserOutput
+	logger   logging.Logger
+	ui       ui.UserOutput
+	prompter *ui.Prompter
+	out      io.Writer
+	metrics  *metrics.Metrics
 }
 
 // NewCleanHandler creates a new clean command handler.
@@ -44,6 +84,24 @@ func NewCleanHandler(logger logging.Logger, ui ui.UserOutput) *CleanHandler {
 	}
 }
 
+// WithOutput sets the writer used for diff output (defaults to stdout).
+func (h *CleanHandler) WithOutput(out io.Writer) *CleanHandler {
+	h.out = out
+	return h
+}
+
+// WithMetrics sets the metrics that clean runs are recorded in (defaults to none).
+func (h *CleanHandler) WithMetrics(m *metrics.Metrics) *CleanHandler {
+	h.metrics = m
+	return h
+}
+
+// WithPrompter sets the prompter used by interactive mode (defaults to stdin/stdout).
+func (h *CleanHandler) WithPrompter(prompter *ui.Prompter) *CleanHandler {
+	h.prompter = prompter
+	return h
+}
+
 // CreateCommand creates the clean cobra command.
 func (h *CleanHandler) CreateCommand() *cobra.Command {
 	opts := &CleanOptions{}
@@ -62,13 +120,31 @@ Examples:
   antimoji clean --in-place .               # Clean current directory in-place
   antimoji clean --backup --in-place src/   # Clean with backup creation
   antimoji clean --replace "[EMOJI]" .      # Replace emojis with text
-  antimoji clean --respect-allowlist .      # Keep allowlisted emojis
-  antimoji clean --dry-run .                # Preview changes without modifying`,
+  antimoji clean --ignore-allowlist .       # Remove allowlisted emojis too
+  antimoji clean --only 🚀,🔥 --in-place .  # Remove only these emojis
+  antimoji clean --except ✅ --in-place .   # Remove every emoji but these
+  antimoji clean --interactive --in-place . # Decide per emoji (keep/remove/replace/always-allow)
+  antimoji clean --dry-run .                # Preview changes without modifying
+  antimoji clean --diff . | git apply       # Print a unified diff instead of modifying
+  antimoji clean --patch-file out.patch .   # Write the diff to a patch file
+  antimoji clean --include-names --dry-run .  # Also report emojis in file and directory names
+  antimoji clean --rename --in-place .      # Strip emojis from file and directory names
+  antimoji clean --check .                  # List files that would change; exit 1 if any
+  antimoji clean --in-place --max-errors 50 --error-report errors.json .  # Stop early, listing the failures
+  antimoji clean --atomic --in-place .      # Modify every file or, if any fails, none
+  antimoji clean --in-place --summary-file .antimoji-summary.json .  # Record what the run did
+  antimoji clean --quarantine-dir ./quarantine .  # Move files with emojis aside instead of editing them`,
 		Args: cobra.MinimumNArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Get dry-run from persistent flag (parent command)
 			dryRun, _ := cmd.Root().PersistentFlags().GetBool("dry-run")
 			opts.DryRun = dryRun
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			opts.Verbose, _ = cmd.Root().PersistentFlags().GetBool("verbose")
+			opts.ProgressAllowed = progressAllowed(cmd, h.ui)
 			return h.Execute(cmd.Context(), args, opts)
 		},
 	}
@@ -76,27 +152,45 @@ Examples:
 	// Add clean-specific flags
 	cmd.Flags().BoolVarP(&opts.Recursive, "recursive", "r", true, "clean directories recursively")
 	cmd.Flags().BoolVar(&opts.Backup, "backup", false, "create backup files")
-	cmd.Flags().StringVar(&opts.Replace, "replace", "", "replacement text for emojis")
+	cmd.Flags().StringVar(&opts.Replace, "replace", "", "replacement text for emojis not in the profile replacement_map")
 	cmd.Flags().BoolVarP(&opts.InPlace, "in-place", "i", false, "modify files in place")
-	cmd.Flags().BoolVar(&opts.RespectAllowlist, "respect-allowlist", true, "respect configured emoji allowlist during cleaning (deprecated, use --ignore-allowlist)")
-	cmd.Flags().BoolVar(&opts.IgnoreAllowlist, "ignore-allowlist", false, "ignore configured emoji allowlist (overrides --respect-allowlist)")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+	cmd.Flags().StringSliceVar(&opts.Only, "only", nil, "remove only these emojis (comma-separated); allowlisted emojis are still kept")
+	cmd.Flags().StringSliceVar(&opts.Except, "except", nil, "keep these emojis (comma-separated) in addition to the allowlist")
 	cmd.Flags().BoolVar(&opts.Stats, "stats", false, "show performance statistics")
 	cmd.Flags().BoolVar(&opts.Benchmark, "benchmark", false, "run in benchmark mode with detailed metrics")
+	cmd.Flags().BoolVar(&opts.Diff, "diff", false, "print a unified diff of proposed changes without modifying files")
+	cmd.Flags().StringVar(&opts.PatchFile, "patch-file", "", "write a unified diff of proposed changes to this file without modifying files")
+	cmd.Flags().BoolVar(&opts.Interactive, "interactive", false, "prompt for each detected emoji (keep/remove/replace/always-allow)")
+	cmd.Flags().BoolVar(&opts.IncludeNames, "include-names", false, "also report file and directory names containing emojis")
+	cmd.Flags().StringVar(&opts.IncludePattern, "include", "", "include file patterns (glob)")
+	cmd.Flags().StringVar(&opts.ExcludePattern, "exclude", "", "exclude file patterns (glob)")
+	cmd.Flags().BoolVar(&opts.Check, "check", false, "list files that would be cleaned and exit with status 1 if any (implies --dry-run)")
+	cmd.Flags().BoolVar(&opts.Rename, "rename", false, "rename files and directories by stripping emojis from their names (implies --include-names)")
+	cmd.Flags().IntVar(&opts.MaxWorkers, "max-workers", 0, "maximum files cleaned concurrently (0 = profile max_workers, or one per CPU)")
+	cmd.Flags().IntVar(&opts.MaxErrors, "max-errors", 0, "stop after this many files could not be processed (0 = never)")
+	cmd.Flags().StringVar(&opts.ErrorReport, "error-report", "", "write the files that could not be processed, with the reasons, to this JSON file")
+	cmd.Flags().StringVar(&opts.SummaryFile, "summary-file", "", "write a JSON summary of the run (files scanned and modified, violations remaining, duration) to this file")
+	cmd.Flags().BoolVar(&opts.Atomic, "atomic", false, "write no file unless every file is cleaned successfully")
+	cmd.Flags().StringVar(&opts.QuarantineDir, "quarantine-dir", "", "move files containing emojis into this directory, keeping their relative paths, instead of editing them")
 
 	return cmd
 }
 
 // Execute runs the clean command logic with dependency injection.
-func (h *CleanHandler) Execute(parentCtx context.Context, args []string, opts *CleanOptions) error {
+func (h *CleanHandler) Execute(parentCtx context.Context, args []string, opts *CleanOptions) (err error) {
 	startTime := time.Now()
 
 	// Create component context for better tracing
 	ctx := ctxutil.NewComponentContext("clean", "cli")
+	ctx, span := tracing.Start(ctx, "clean", attribute.StringSlice("antimoji.paths", args))
+	defer func() { tracing.End(span, err) }()
 
 	h.logger.Info(ctx, "Starting clean operation",
 		"dry_run", opts.DryRun,
 		"recursive", opts.Recursive,
 		"backup", opts.Backup,
+		"atomic", opts.Atomic,
 		"args", args)
 
 	// Validate options
@@ -105,6 +199,9 @@ func (h *CleanHandler) Execute(parentCtx context.Context, args []string, opts *C
 		h.ui.Error(ctx, "Invalid options: %v", err)
 		return err
 	}
+	if opts.Check {
+		opts.DryRun = true
+	}
 
 	// If no paths provided, use current directory
 	if len(args) == 0 {
@@ -112,9 +209,19 @@ func (h *CleanHandler) Execute(parentCtx context.Context, args []string, opts *C
 		h.logger.Debug(ctx, "No paths provided, using current directory")
 	}
 
-	// TODO: For now, use default config - this will be replaced with proper CLI flag parsing
+	// Load configuration
 	cfg := config.DefaultConfig()
-	profileName := "default"
+	if opts.ConfigFile != "" {
+		h.logger.Debug(ctx, "Loading configuration file", "config_file", opts.ConfigFile)
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			h.logger.Error(ctx, "Failed to load configuration", "config_file", opts.ConfigFile, "error", configResult.Error())
+			return fmt.Errorf("failed to load config: %w", configResult.Error())
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileName := opts.ProfileName
 
 	h.logger.Debug(ctx, "Loading profile", "profile_name", profileName)
 	profileResult := config.GetProfile(cfg, profileName)
@@ -123,39 +230,64 @@ func (h *CleanHandler) Execute(parentCtx context.Context, args []string, opts *C
 		return fmt.Errorf("failed to get profile '%s': %w", profileName, profileResult.Error())
 	}
 
-	profile := profileResult.Unwrap()
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return err
+	}
+	profile := resolution.Profile
 	h.logger.Debug(ctx, "Profile loaded successfully", "profile_name", profileName)
 
-	// Create allowlist for processing
-	h.logger.Debug(ctx, "Creating allowlist for processing")
-	allowlistOpts := allowlist.ProcessingOptions{
-		IgnoreAllowlist:  opts.IgnoreAllowlist,
-		RespectAllowlist: opts.RespectAllowlist && !opts.IgnoreAllowlist,
-		Operation:        "clean",
+	// The policy engine decides which files are checked and what counts as a violation,
+	// exactly as it does for scan; --check tolerates none
+	threshold := policy.NoThreshold
+	if opts.Check {
+		threshold = 0
 	}
-
-	emojiAllowlist, err := allowlist.CreateAllowlistForProcessing(ctx, profile, allowlistOpts)
+	policyOpts := policy.Options{
+		Operation:       "clean",
+		Recursive:       opts.Recursive,
+		IncludePattern:  opts.IncludePattern,
+		ExcludePattern:  opts.ExcludePattern,
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Only:            opts.Only,
+		Except:          opts.Except,
+		Threshold:       threshold,
+	}
+	engine, err := policy.New(ctx, profile, policyOpts)
 	if err != nil {
-		h.logger.Error(ctx, "Failed to create allowlist", "error", err)
-		return fmt.Errorf("failed to create allowlist: %w", err)
+		h.logger.Error(ctx, "Failed to create policy", "error", err)
+		return err
 	}
-
-	shouldUseAllowlist := emojiAllowlist != nil
-	h.logger.Debug(ctx, "Allowlist created", "should_use_allowlist", shouldUseAllowlist)
+	// Files the config's rules route to other profiles keep what those profiles allow
+	if err := routeRules(ctx, engine, cfg, opts.ConfigFile != "", profileName, policyOpts, opts.Check, os.Environ()); err != nil {
+		return err
+	}
+	if !opts.IgnoreAllowlist && len(opts.Only) > 0 {
+		configured := allowlist.NewAllowlist(profile.EmojiAllowlist).Unwrap()
+		for _, emoji := range opts.Only {
+			if configured.IsAllowed(emoji) {
+				h.ui.Warning(ctx, "%s is in the allowlist and will be kept; use --ignore-allowlist to remove it", emoji)
+			}
+		}
+	}
+	emojiAllowlist := engine.Allowlist()
+	h.logger.Debug(ctx, "Policy created", "should_use_allowlist", emojiAllowlist != nil)
 
 	// Start file discovery
 	h.logger.Debug(ctx, "Starting file discovery", "paths", args, "recursive", opts.Recursive)
 
-	discoveryOptions := filtering.DiscoveryOptions{
-		Recursive:      opts.Recursive,
-		IncludePattern: "", // TODO: Add CLI support for include/exclude patterns
-		ExcludePattern: "",
-	}
-
-	filePaths, err := filtering.DiscoverFiles(args, discoveryOptions, profile)
+	_, discoverySpan := tracing.Start(ctx, "discovery")
+	discovery, err := engine.SelectFiles(args)
+	filePaths := discovery.Files
+	discoverySpan.SetAttributes(attribute.Int("antimoji.files", len(filePaths)))
+	tracing.End(discoverySpan, err)
 	if err != nil {
 		h.logger.Error(ctx, "File discovery failed", "error", err, "paths", args)
-		return fmt.Errorf("file discovery failed: %w", err)
+		return classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	reportSkippedSymlinks(ctx, h.ui, discovery.Symlinks)
+	if opts.QuarantineDir != "" {
+		filePaths = outsideQuarantine(opts.QuarantineDir, filePaths)
 	}
 
 	if len(filePaths) == 0 {
@@ -167,65 +299,509 @@ func (h *CleanHandler) Execute(parentCtx context.Context, args []string, opts *C
 
 	// Create modification configuration
 	h.logger.Debug(ctx, "Creating modification configuration")
-	modifyConfig := processor.ModifyConfig{
-		DryRun:              opts.DryRun,
-		CreateBackup:        opts.Backup,
-		RespectAllowlist:    shouldUseAllowlist,
-		Replacement:         opts.Replace,
-		PreservePermissions: true,
+	previewOnly := opts.Diff || opts.PatchFile != ""
+	modifyConfig := policyModifyConfig(engine)
+	// Quarantining only needs to know which files have emojis to remove
+	modifyConfig.DryRun = opts.DryRun || previewOnly || opts.QuarantineDir != ""
+	modifyConfig.GenerateDiff = previewOnly
+	modifyConfig.CreateBackup = opts.Backup && !previewOnly
+	modifyConfig.Replacement = opts.Replace
+	if opts.MaxWorkers != 0 {
+		modifyConfig.MaxWorkers = opts.MaxWorkers
 	}
 
 	h.logger.Debug(ctx, "Modification configuration created",
 		"dry_run", modifyConfig.DryRun,
 		"create_backup", modifyConfig.CreateBackup,
-		"preserve_permissions", modifyConfig.PreservePermissions)
+		"preserve_permissions", modifyConfig.PreservePermissions,
+		"max_workers", modifyConfig.MaxWorkers)
 
 	// Create emoji patterns
-	patterns := detector.DefaultEmojiPatterns()
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to load supplemental emoji data", "error", err)
+		return err
+	}
 	h.logger.Debug(ctx, "Emoji patterns created", "unicode_ranges", len(patterns.UnicodeRanges))
 
+	// Attach the interactive session if requested
+	var session *interactiveSession
+	if opts.Interactive {
+		prompter := h.prompter
+		if prompter == nil {
+			prompter = ui.NewPrompter(os.Stdin, os.Stdout)
+		}
+		session = newInteractiveSession(prompter)
+		modifyConfig.Decide = session.decide
+	}
+
+	// Report progress unless the user is being prompted
+	progress := newProgress(opts.ProgressAllowed && profile.ShowProgress && session == nil, len(filePaths))
+	modifyConfig.Progress = progressFunc(progress)
+	modifyConfig.MaxErrors = opts.MaxErrors
+	modifyConfig.Atomic = opts.Atomic
+
 	// Process files for modification
 	h.logger.Info(ctx, "Starting file modification process", "total_files", len(filePaths))
+	_, modifySpan := tracing.Start(ctx, "modification",
+		attribute.Int("antimoji.files", len(filePaths)), attribute.Bool("antimoji.dry_run", modifyConfig.DryRun))
 	results := processor.ModifyFiles(filePaths, patterns, modifyConfig, emojiAllowlist)
+	modifySpan.End()
+	finishProgress(progress)
 	h.logger.Info(ctx, "File modification process completed", "total_results", len(results))
+	h.observeClean(results)
+	if modifyConfig.CreateBackup && !modifyConfig.DryRun {
+		h.recordUndo(ctx, results, startTime)
+	}
+	if opts.Verbose {
+		for _, result := range results {
+			reportBinaryFile(ctx, h.ui, result.FilePath, result.BinaryReason)
+			reportSuppressedRegions(ctx, h.ui, result.FilePath, result.SuppressedRegions)
+		}
+	}
+
+	// Persist always-allow decisions to the configuration allowlist
+	if session != nil && len(session.allowed) > 0 {
+		if err := h.persistAllowed(ctx, opts, session.allowed); err != nil {
+			h.logger.Error(ctx, "Failed to persist allowlist decisions", "error", err)
+			return classify(ErrIO, fmt.Errorf("failed to update allowlist: %w", err))
+		}
+	}
+
+	// Summarize the failures and list them for --error-report in every output mode
+	if err := reportFailures(ctx, h.ui, newErrorReport("clean", len(results), modifyFailures(results)), opts.MaxErrors, opts.ErrorReport); err != nil {
+		return err
+	}
+	if opts.SummaryFile != "" {
+		summary := cleanSummary(results, engine, patterns, modifyConfig.DryRun, time.Since(startTime))
+		if err := reportSummary(ctx, h.ui, summary, opts.SummaryFile); err != nil {
+			return err
+		}
+	}
+
+	// Emit diffs instead of the usual summary when previewing as a patch
+	if previewOnly {
+		if err := h.writeDiffs(ctx, results, opts); err != nil {
+			h.logger.Error(ctx, "Failed to write diff", "error", err)
+			return classify(ErrIO, fmt.Errorf("failed to write diff: %w", err))
+		}
+		if opts.Diff {
+			h.logger.Info(ctx, "Clean diff completed", "total_results", len(results))
+			return nil
+		}
+	}
+
+	if opts.Check {
+		return h.checkResults(ctx, results, engine)
+	}
+	if opts.QuarantineDir != "" {
+		return h.quarantine(ctx, results, opts, startTime)
+	}
 
-	// Display results
-	if err := h.displayResults(ctx, results, opts, time.Since(startTime)); err != nil {
+	// Display results; a preview only written to --patch-file modified nothing
+	displayOpts := opts
+	if previewOnly {
+		preview := *opts
+		preview.DryRun = true
+		displayOpts = &preview
+	}
+	if err := h.displayResults(ctx, results, displayOpts, time.Since(startTime)); err != nil {
 		h.logger.Error(ctx, "Failed to display results", "error", err)
-		return fmt.Errorf("failed to display results: %w", err)
+		return classify(ErrIO, fmt.Errorf("failed to display results: %w", err))
+	}
+
+	// Check file and directory names once their contents are cleaned
+	if opts.IncludeNames || opts.Rename {
+		findings := findEmojiNames(namedPaths(args, filePaths), patterns, engine.ProcessingConfig(), emojiAllowlist)
+		h.logger.Info(ctx, "Name check completed", "names_with_emojis", len(findings))
+		if opts.Rename {
+			h.renameFindings(ctx, findings, patterns, emojiAllowlist, opts, session)
+		} else {
+			displayNameFindings(ctx, h.ui, findings)
+		}
+	}
+
+	if failed := countModifyFailures(results); failed > 0 {
+		return fileFailureError(failed, len(results), nil)
 	}
 
 	h.logger.Info(ctx, "Clean operation completed successfully")
 	return nil
 }
 
+// renameFindings strips emojis from the names of the given files and directories and
+// reports each rename.
+func (h *CleanHandler) renameFindings(ctx context.Context, findings []NameFinding, patterns types.EmojiPatterns,
+	emojiAllowlist *allowlist.Allowlist, opts *CleanOptions, session *interactiveSession) {
+
+	paths := make([]string, len(findings))
+	for i, finding := range findings {
+		paths[i] = finding.Path
+	}
+
+	var confirm func(from, to string) bool
+	if session != nil {
+		confirm = session.confirmRename
+	}
+
+	renamed := 0
+	for _, result := range processor.RenamePaths(paths, patterns, emojiAllowlist, opts.DryRun, confirm) {
+		switch {
+		case result.Error != nil:
+			h.logger.Error(ctx, "Rename failed", "path", result.From, "error", result.Error)
+			h.ui.Error(ctx, "Cannot rename: %v", result.Error)
+		case !result.Renamed:
+			h.ui.Info(ctx, "Kept %s", result.From)
+		case opts.DryRun:
+			renamed++
+			h.ui.Info(ctx, "Would rename %s to %s", result.From, result.To)
+		default:
+			renamed++
+			h.logger.Info(ctx, "Path renamed", "from", result.From, "to", result.To)
+			h.ui.Success(ctx, "Renamed %s to %s", result.From, result.To)
+		}
+	}
+
+	if opts.DryRun {
+		h.ui.Result(ctx, "Names: would rename %d of %d paths containing emojis", renamed, len(paths))
+	} else {
+		h.ui.Result(ctx, "Names: renamed %d of %d paths containing emojis", renamed, len(paths))
+	}
+}
+
+// recordUndo writes the manifest of the files the run cleaned after backing them up, so
+// 'antimoji undo' can revert the whole run. The files are cleaned already, so failing
+// to record the manifest is only a warning.
+func (h *CleanHandler) recordUndo(ctx context.Context, results []processor.ModifyResult, startTime time.Time) {
+	var files []undo.File
+	for _, result := range results {
+		if result.Error != nil || !result.Modified || result.BackupPath == "" {
+			continue
+		}
+		file, err := undo.NewFile(result.FilePath, result.BackupPath)
+		if err != nil {
+			h.logger.Warn(ctx, "Failed to record file for undo", "file", result.FilePath, "error", err)
+			h.ui.Warning(ctx, "This run cannot be undone: %v", err)
+			return
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	manifest := undo.Manifest{RunID: undo.NewRunID(startTime), Time: startTime.UTC(), Files: files}
+	if err := undo.Record(undo.DefaultDir, manifest); err != nil {
+		h.logger.Warn(ctx, "Failed to record undo manifest", "error", err)
+		h.ui.Warning(ctx, "This run cannot be undone: %v", err)
+		return
+	}
+	h.logger.Info(ctx, "Undo manifest recorded", "run_id", manifest.RunID, "files", len(files))
+	h.ui.Result(ctx, "Undo this run with: antimoji undo %s", manifest.RunID)
+}
+
+// policyModifyConfig returns the modification settings that follow from the policy:
+// the allowlist and exemptions, the replacement map and the detection settings of the
+// profile.
+func policyModifyConfig(engine *policy.Engine) processor.ModifyConfig {
+	processing := engine.ProcessingConfig()
+	profile := engine.Profile()
+	return processor.ModifyConfig{
+		RespectAllowlist:    engine.Allowlist() != nil,
+		Violations:          engine.FileViolations,
+		ReplacementMap:      profile.ReplacementMap,
+		PreservePermissions: true,
+		Preserve:            processor.PreserveConfig{Ownership: profile.PreserveOwnership, Xattrs: profile.PreserveXattrs},
+		HardLinks:           hardLinkPolicy(profile.HardlinkPolicy),
+		StripBOM:            profile.StripBOM,
+		Sniff:               processing.Sniff,
+		Retry:               processing.Retry,
+		MaxWorkers:          profile.MaxWorkers,
+
+		PreserveMarkdownCode: processing.PreserveMarkdownCode,
+		Structured:           processing.Structured,
+		NormalizeShortcodes:  processing.NormalizeShortcodes,
+		DecodeEscapes:        processing.EnableEscapes,
+		Languages:            processing.Languages,
+	}
+}
+
+// hardLinkPolicy returns the processor policy for a profile's hardlink_policy.
+func hardLinkPolicy(name string) processor.HardLinkPolicy {
+	switch name {
+	case config.HardlinkInPlace:
+		return processor.HardLinkInPlace
+	case config.HardlinkSkip:
+		return processor.HardLinkSkip
+	default:
+		return processor.HardLinkWarn
+	}
+}
+
+// persistAllowed writes always-allow decisions to the configuration file.
+func (h *CleanHandler) persistAllowed(ctx context.Context, opts *CleanOptions, emojis []string) error {
+	if opts.DryRun {
+		h.ui.Info(ctx, "Dry run: would add %d emojis to the allowlist", len(emojis))
+		return nil
+	}
+
+	configPath := opts.ConfigFile
+	if configPath == "" {
+		configPath = defaultConfigFile
+	}
+
+	added, err := config.AddToAllowlist(configPath, opts.ProfileName, emojis)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info(ctx, "Allowlist updated", "config_file", configPath, "added", added)
+	if len(added) > 0 {
+		h.ui.Success(ctx, "Added %d emojis to the allowlist in %s", len(added), configPath)
+	}
+	return nil
+}
+
+// writeDiffs writes the collected unified diffs to stdout and/or the patch file.
+func (h *CleanHandler) writeDiffs(ctx context.Context, results []processor.ModifyResult, opts *CleanOptions) error {
+	var patch strings.Builder
+	changed := 0
+	for _, result := range results {
+		if result.Diff == "" {
+			continue
+		}
+		patch.WriteString(result.Diff)
+		changed++
+	}
+
+	if opts.Diff {
+		out := h.out
+		if out == nil {
+			out = os.Stdout
+		}
+		if _, err := io.WriteString(out, patch.String()); err != nil {
+			return err
+		}
+	}
+
+	if opts.PatchFile != "" {
+		if err := os.WriteFile(opts.PatchFile, []byte(patch.String()), 0644); err != nil { // #nosec G306 - patch files are meant to be shared
+			return err
+		}
+		h.logger.Info(ctx, "Patch file written", "patch_file", opts.PatchFile, "files_changed", changed)
+		h.ui.Info(ctx, "Wrote patch for %d files to %s", changed, opts.PatchFile)
+	}
+
+	return nil
+}
+
+// checkResults lists the files clean would modify, one per line with the number of
+// emojis it would remove, and fails when any of them is an error-level finding, like
+// gofmt -l.
+func (h *CleanHandler) checkResults(ctx context.Context, results []processor.ModifyResult, engine *policy.Engine) error {
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	changed, removals, failed, normalized := 0, 0, 0, 0
+	var toClean []string
+	for _, result := range results {
+		switch {
+		case result.Error != nil:
+			failed++
+			if !errors.Is(result.Error, processor.ErrTooManyErrors) {
+				h.ui.Error(ctx, "Error processing %s: %v", result.FilePath, result.Error)
+			}
+		case result.Modified:
+			// Normalizing a shortcode changes the file like removing an emoji does
+			changed++
+			removals += result.EmojisRemoved + result.EmojisNormalized
+			normalized += result.EmojisNormalized
+			toClean = append(toClean, result.FilePath)
+			if _, err := fmt.Fprintf(out, "%s: %d emojis\n", result.FilePath, result.EmojisRemoved+result.EmojisNormalized); err != nil {
+				return classify(ErrIO, fmt.Errorf("failed to write check results: %w", err))
+			}
+		}
+	}
+
+	h.logger.Info(ctx, "Clean check completed",
+		"total_files", len(results), "files_to_clean", changed, "emojis_to_remove", removals, "errors", failed)
+
+	// Only error-level findings fail the check; files with warnings alone are listed
+	// but pass, as they do for scan
+	detected, err := detectRouted(ctx, engine, toClean)
+	if err != nil {
+		return err
+	}
+	errorsFound, _ := policy.CountSeverities(engine.Apply(detected))
+
+	var violation error
+	if engine.Evaluate(errorsFound+normalized) != nil {
+		violation = fmt.Errorf("%w: %d files would be cleaned (%d emojis)", ErrCleanCheckFailed, changed, removals)
+	}
+	if failed > 0 {
+		return fileFailureError(failed, len(results), violation)
+	}
+	return violation
+}
+
+// quarantine moves the files with emojis to remove into the quarantine directory,
+// unchanged, and records them in its manifest.
+func (h *CleanHandler) quarantine(ctx context.Context, results []processor.ModifyResult, opts *CleanOptions, startTime time.Time) error {
+	var files []quarantine.File
+	emojis, failed := 0, 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+			continue
+		}
+		if result.EmojisRemoved == 0 {
+			continue
+		}
+		if opts.DryRun {
+			files = append(files, quarantine.File{Path: result.FilePath, Emojis: result.EmojisRemoved})
+			emojis += result.EmojisRemoved
+			h.ui.Info(ctx, "Would quarantine %s (%d emojis)", result.FilePath, result.EmojisRemoved)
+			continue
+		}
+		file, err := quarantine.Move(opts.QuarantineDir, result.FilePath, result.EmojisRemoved, startTime)
+		if err != nil {
+			failed++
+			h.logger.Error(ctx, "Failed to quarantine file", "file", result.FilePath, "error", err)
+			h.ui.Error(ctx, "Cannot quarantine %s: %v", result.FilePath, err)
+			continue
+		}
+		files = append(files, file)
+		emojis += file.Emojis
+		h.ui.Success(ctx, "Quarantined %s (%d emojis) to %s", file.Path, file.Emojis,
+			filepath.Join(opts.QuarantineDir, filepath.FromSlash(file.Quarantined)))
+	}
+
+	if !opts.DryRun && len(files) > 0 {
+		if err := quarantine.Record(opts.QuarantineDir, files); err != nil {
+			h.logger.Error(ctx, "Failed to record quarantine manifest", "error", err)
+			return classify(ErrIO, fmt.Errorf("failed to record quarantine manifest: %w", err))
+		}
+	}
+
+	h.logger.Info(ctx, "Quarantine completed", "total_files", len(results), "quarantined", len(files),
+		"emojis", emojis, "errors", failed, "dry_run", opts.DryRun)
+	if opts.DryRun {
+		h.ui.Result(ctx, "Summary: would quarantine %d of %d files (%d emojis)", len(files), len(results), emojis)
+	} else {
+		h.ui.Result(ctx, "Summary: quarantined %d of %d files (%d emojis)", len(files), len(results), emojis)
+	}
+	return fileFailureError(failed, len(results), nil)
+}
+
+// outsideQuarantine returns the file paths that are not inside the quarantine
+// directory, so files quarantined by an earlier run are left there.
+func outsideQuarantine(dir string, filePaths []string) []string {
+	kept := filePaths[:0:0]
+	for _, path := range filePaths {
+		if !quarantine.Contains(dir, path) {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
+
 // validateCleanOptions validates the clean command options.
 func (h *CleanHandler) validateCleanOptions(opts *CleanOptions) error {
+	if opts.MaxErrors < 0 {
+		return fmt.Errorf("--max-errors cannot be negative")
+	}
+	if opts.MaxWorkers < 0 {
+		return fmt.Errorf("--max-workers cannot be negative")
+	}
+	if opts.Check {
+		switch {
+		case opts.InPlace:
+			return fmt.Errorf("--check cannot be combined with --in-place")
+		case opts.Interactive:
+			return fmt.Errorf("--check cannot be combined with --interactive")
+		case opts.Diff || opts.PatchFile != "":
+			return fmt.Errorf("--check cannot be combined with --diff or --patch-file")
+		case opts.Backup:
+			return fmt.Errorf("--check cannot be combined with --backup")
+		case opts.IncludeNames || opts.Rename:
+			return fmt.Errorf("--check cannot be combined with --include-names or --rename")
+		case opts.QuarantineDir != "":
+			return fmt.Errorf("--check cannot be combined with --quarantine-dir")
+		}
+		return nil
+	}
+	if opts.QuarantineDir != "" {
+		switch {
+		case opts.InPlace:
+			return fmt.Errorf("--quarantine-dir cannot be combined with --in-place")
+		case opts.Interactive:
+			return fmt.Errorf("--quarantine-dir cannot be combined with --interactive")
+		case opts.Diff || opts.PatchFile != "":
+			return fmt.Errorf("--quarantine-dir cannot be combined with --diff or --patch-file")
+		case opts.Backup || opts.Atomic:
+			return fmt.Errorf("--quarantine-dir cannot be combined with --backup or --atomic")
+		case opts.Rename || opts.IncludeNames:
+			return fmt.Errorf("--quarantine-dir cannot be combined with --include-names or --rename")
+		case opts.SummaryFile != "":
+			return fmt.Errorf("--quarantine-dir cannot be combined with --summary-file")
+		}
+		return nil
+	}
+	if opts.Diff || opts.PatchFile != "" {
+		if opts.InPlace {
+			return fmt.Errorf("--diff and --patch-file cannot be combined with --in-place")
+		}
+		if opts.Interactive {
+			return fmt.Errorf("--diff and --patch-file cannot be combined with --interactive")
+		}
+		if opts.IncludeNames || opts.Rename {
+			return fmt.Errorf("--diff and --patch-file cannot be combined with --include-names or --rename")
+		}
+		return nil
+	}
 	if !opts.InPlace && !opts.DryRun {
 		return fmt.Errorf("must specify --in-place to modify files, or --dry-run to preview changes")
 	}
 	return nil
 }
 
+// observeClean records a clean run in the handler's metrics.
+func (h *CleanHandler) observeClean(results []processor.ModifyResult) {
+	modified, failed, removed := 0, 0, 0
+	for _, result := range results {
+		switch {
+		case result.Error != nil:
+			failed++
+		case result.Modified:
+			modified++
+		}
+		removed += result.EmojisRemoved
+	}
+	h.metrics.ObserveClean(len(results), modified, failed, removed)
+}
+
 // displayResults displays the clean operation results.
 func (h *CleanHandler) displayResults(ctx context.Context, results []processor.ModifyResult, opts *CleanOptions, duration time.Duration) error {
 	h.logger.Debug(ctx, "Displaying clean results", "total_results", len(results), "stats", opts.Stats)
 
-	// Count statistics
-	totalFiles := len(results)
-	modifiedFiles := 0
-	errorCount := 0
-	totalEmojisRemoved := 0
-
+	rolledBack := 0
 	for _, result := range results {
+		if errors.Is(result.Error, processor.ErrRolledBack) {
+			rolledBack++
+			continue
+		}
+		if errors.Is(result.Error, processor.ErrTooManyErrors) {
+			// Counted in the error summary instead of listed
+			continue
+		}
 		if result.Error != nil {
-			errorCount++
 			h.logger.Error(ctx, "File processing error",
 				"file_path", result.FilePath,
 				"error", result.Error)
 			h.ui.Error(ctx, "Error processing %s: %v", result.FilePath, result.Error)
 		} else if result.Modified {
-			modifiedFiles++
 			h.logger.Info(ctx, "File modified",
 				"file_path", result.FilePath,
 				"emojis_removed", result.EmojisRemoved)
@@ -235,8 +811,20 @@ func (h *CleanHandler) displayResults(ctx context.Context, results []processor.M
 			} else {
 				h.ui.Info(ctx, "Would clean %s: %d emojis to remove", result.FilePath, result.EmojisRemoved)
 			}
+			if result.EmojisNormalized > 0 {
+				h.ui.Info(ctx, "Normalized %d shortcodes and emojis in %s", result.EmojisNormalized, result.FilePath)
+			}
+			if result.BOMStripped {
+				h.ui.Info(ctx, "Removed byte order mark from %s", result.FilePath)
+			}
 
-			totalEmojisRemoved += result.EmojisRemoved
+			if result.HardLinks > 1 {
+				h.ui.Warning(ctx, "%s has %d hard links; cleaning detaches it from the others (hardlink_policy: in_place keeps them)",
+					result.FilePath, result.HardLinks)
+			}
+			for _, warning := range result.MetadataWarnings {
+				h.ui.Warning(ctx, "%s: %s", result.FilePath, warning)
+			}
 
 			// Show backup information if created
 			if result.BackupPath != "" {
@@ -245,31 +833,41 @@ func (h *CleanHandler) displayResults(ctx context.Context, results []processor.M
 					"backup_file", result.BackupPath)
 				h.ui.Info(ctx, "Backup created: %s", result.BackupPath)
 			}
+		} else if result.HardLinks > 1 {
+			h.ui.Warning(ctx, "Skipped %s: it has %d hard links (hardlink_policy: skip)", result.FilePath, result.HardLinks)
 		}
 	}
 
+	if rolledBack > 0 {
+		h.ui.Error(ctx, "Atomic clean rolled back: %d files were left unmodified", rolledBack)
+	}
+
 	// Display summary
+	summary := processor.SummarizeModify(results)
 	if opts.DryRun {
 		h.ui.Result(ctx, "Summary: would remove %d emojis from %d files (%d modified, %d errors)",
-			totalEmojisRemoved, totalFiles, modifiedFiles, errorCount)
+			summary.EmojisRemoved, summary.Files, summary.FilesModified, summary.Errors)
 	} else {
 		h.ui.Result(ctx, "Summary: removed %d emojis from %d files (%d modified, %d errors)",
-			totalEmojisRemoved, totalFiles, modifiedFiles, errorCount)
+			summary.EmojisRemoved, summary.Files, summary.FilesModified, summary.Errors)
+	}
+	if summary.EmojisNormalized > 0 {
+		h.ui.Result(ctx, "Normalized %d shortcodes and emojis", summary.EmojisNormalized)
 	}
 
 	// Show performance statistics if requested
 	if opts.Stats {
 		h.ui.Info(ctx, "Processing time: %v", duration)
-		if totalFiles > 0 {
-			h.ui.Info(ctx, "Files per second: %.2f", float64(totalFiles)/duration.Seconds())
+		if summary.Files > 0 {
+			h.ui.Info(ctx, "Files per second: %.2f", float64(summary.Files)/duration.Seconds())
 		}
 	}
 
 	h.logger.Info(ctx, "Clean operation completed",
-		"total_files", totalFiles,
-		"modified_files", modifiedFiles,
-		"errors", errorCount,
-		"emojis_removed", totalEmojisRemoved,
+		"total_files", summary.Files,
+		"modified_files", summary.FilesModified,
+		"errors", summary.Errors,
+		"emojis_removed", summary.EmojisRemoved,
 		"duration", duration)
 
 	return nil
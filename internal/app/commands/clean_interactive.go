@@ -0,0 +1,107 @@
+// Package commands provides CLI command implementations using dependency injection.
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+)
+
+// defaultConfigFile is the project configuration file used when --config is not given.
+const defaultConfigFile = ".antimoji.yaml"
+
+// interactiveSession asks the user how to handle each detected emoji during clean.
+type interactiveSession struct {
+	prompter *ui.Prompter
+
+	// alwaysAllowed holds emojis the user chose to always allow for the rest of the run
+	alwaysAllowed map[string]bool
+
+	// allowed lists always-allow decisions in the order they were made, for persistence
+	allowed []string
+
+	// quit is set once the user stops the session; remaining emojis are kept
+	quit bool
+}
+
+// newInteractiveSession creates a session that prompts through the given prompter.
+func newInteractiveSession(prompter *ui.Prompter) *interactiveSession {
+	return &interactiveSession{
+		prompter:      prompter,
+		alwaysAllowed: make(map[string]bool),
+	}
+}
+
+// decide implements processor.MatchDecider by prompting the user.
+func (s *interactiveSession) decide(filePath, content string, match types.EmojiMatch) processor.MatchDecision {
+	if s.quit || s.alwaysAllowed[match.Emoji] {
+		return processor.MatchDecision{Action: processor.ActionKeep}
+	}
+
+	s.prompter.Printf("\n%s:%d:%d  %s\n", filePath, match.Line, match.Column, match.Emoji)
+	s.prompter.Printf("  %s\n", matchContext(content, match))
+
+	for {
+		choice, err := s.prompter.Choose("Remove, keep, replace, always allow, or quit?", []string{"r", "k", "p", "a", "q"})
+		if err != nil {
+			// Input closed: stop asking and leave the remaining emojis untouched
+			s.quit = true
+			return processor.MatchDecision{Action: processor.ActionKeep}
+		}
+
+		switch choice {
+		case "r":
+			return processor.MatchDecision{Action: processor.ActionRemove}
+		case "k":
+			return processor.MatchDecision{Action: processor.ActionKeep}
+		case "p":
+			replacement, err := s.prompter.Ask("Replacement text: ")
+			if err != nil {
+				s.quit = true
+				return processor.MatchDecision{Action: processor.ActionKeep}
+			}
+			return processor.MatchDecision{Action: processor.ActionReplace, Replacement: replacement}
+		case "a":
+			s.alwaysAllowed[match.Emoji] = true
+			s.allowed = append(s.allowed, match.Emoji)
+			return processor.MatchDecision{Action: processor.ActionKeep}
+		case "q":
+			s.quit = true
+			return processor.MatchDecision{Action: processor.ActionKeep}
+		}
+	}
+}
+
+// confirmRename asks whether a file or directory should be renamed.
+func (s *interactiveSession) confirmRename(from, to string) bool {
+	if s.quit {
+		return false
+	}
+
+	s.prompter.Printf("\n%s\n", from)
+	choice, err := s.prompter.Choose(fmt.Sprintf("Rename to %s?", filepath.Base(to)), []string{"y", "n"})
+	if err != nil {
+		s.quit = true
+		return false
+	}
+	return choice == "y"
+}
+
+// matchContext returns the line containing the match with the emoji bracketed.
+func matchContext(content string, match types.EmojiMatch) string {
+	if match.Start < 0 || match.End > len(content) || match.Start >= match.End {
+		return ""
+	}
+
+	lineStart := strings.LastIndexByte(content[:match.Start], '\n') + 1
+	lineEnd := len(content)
+	if idx := strings.IndexByte(content[match.End:], '\n'); idx >= 0 {
+		lineEnd = match.End + idx
+	}
+
+	return strings.TrimSpace(content[lineStart:match.Start] + ">>" + content[match.Start:match.End] + "<<" + content[match.End:lineEnd])
+}
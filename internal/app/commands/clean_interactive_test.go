@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInteractiveSession_Decide(t *testing.T) {
+	content := "first line\nhello 🚀 world\n"
+	match := types.EmojiMatch{Emoji: "🚀", Start: 17, End: 21, Line: 2, Column: 7}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected processor.MatchDecision
+	}{
+		{"remove", "r\n", processor.MatchDecision{Action: processor.ActionRemove}},
+		{"default is remove", "\n", processor.MatchDecision{Action: processor.ActionRemove}},
+		{"keep", "k\n", processor.MatchDecision{Action: processor.ActionKeep}},
+		{"replace", "p\n[rocket]\n", processor.MatchDecision{Action: processor.ActionReplace, Replacement: "[rocket]"}},
+		{"invalid answer is asked again", "x\nk\n", processor.MatchDecision{Action: processor.ActionKeep}},
+		{"closed input keeps", "", processor.MatchDecision{Action: processor.ActionKeep}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			session := newInteractiveSession(ui.NewPrompter(strings.NewReader(tt.input), &out))
+
+			assert.Equal(t, tt.expected, session.decide("file.go", content, match))
+			assert.Contains(t, out.String(), "file.go:2:7")
+			assert.Contains(t, out.String(), "hello >>🚀<< world")
+		})
+	}
+
+	t.Run("always allow applies to later matches", func(t *testing.T) {
+		var out bytes.Buffer
+		session := newInteractiveSession(ui.NewPrompter(strings.NewReader("a\n"), &out))
+
+		assert.Equal(t, processor.ActionKeep, session.decide("file.go", content, match).Action)
+		assert.Equal(t, processor.ActionKeep, session.decide("other.go", content, match).Action)
+		assert.Equal(t, []string{"🚀"}, session.allowed)
+	})
+
+	t.Run("quit keeps remaining matches", func(t *testing.T) {
+		var out bytes.Buffer
+		session := newInteractiveSession(ui.NewPrompter(strings.NewReader("q\nr\n"), &out))
+
+		assert.Equal(t, processor.ActionKeep, session.decide("file.go", content, match).Action)
+		assert.Equal(t, processor.ActionKeep, session.decide("file.go", content, match).Action)
+	})
+}
+
+func TestCleanHandler_Interactive(t *testing.T) {
+	tempDir := t.TempDir()
+	target := filepath.Join(tempDir, "main.go")
+	require.NoError(t, os.WriteFile(target, []byte("// 🚀 launch ✨ sparkle 🐛 bug\n"), 0644))
+	configPath := filepath.Join(tempDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("# project settings\nprofiles:\n  default:\n    unicode_emojis: true\n"), 0644))
+
+	var out bytes.Buffer
+	handler := NewCleanHandler(logging.NewMockLogger(), ui.NewUserOutput(ui.DefaultConfig())).
+		WithPrompter(ui.NewPrompter(strings.NewReader("r\na\np\n[bug]\n"), &out))
+
+	opts := &CleanOptions{
+		InPlace:     true,
+		Recursive:   true,
+		Interactive: true,
+		ConfigFile:  configPath,
+		ProfileName: "default",
+	}
+	require.NoError(t, handler.Execute(context.Background(), []string{target}, opts))
+
+	cleaned, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "//  launch ✨ sparkle [bug] bug\n", string(cleaned))
+
+	// The always-allow decision is persisted and comments survive
+	raw, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "# project settings")
+
+	cfg := config.LoadConfig(configPath).Unwrap()
+	assert.Equal(t, []string{"✨"}, cfg.Profiles["default"].EmojiAllowlist)
+}
@@ -1,13 +1,17 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/antimoji/antimoji/internal/infra/quarantine"
 	"github.com/antimoji/antimoji/internal/observability/logging"
 	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -227,6 +231,11 @@ func TestCleanHandler_validateCleanOptions(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "must specify --in-place")
 	})
+
+	t.Run("rejects negative max workers", func(t *testing.T) {
+		err := handler.validateCleanOptions(&CleanOptions{DryRun: true, MaxWorkers: -1})
+		assert.ErrorContains(t, err, "--max-workers cannot be negative")
+	})
 }
 
 // Note: displayResults is tested indirectly through the Execute integration tests
@@ -247,3 +256,276 @@ func TestCleanOptions_Struct(t *testing.T) {
 		assert.True(t, opts.Recursive)
 	})
 }
+
+func TestCleanHandler_Diff(t *testing.T) {
+	tempDir := t.TempDir()
+	target := filepath.Join(tempDir, "main.go")
+	original := "package main\n\n// Launch 🚀\nfunc main() {}\n"
+	require.NoError(t, os.WriteFile(target, []byte(original), 0644))
+
+	t.Run("prints diff without modifying files", func(t *testing.T) {
+		var out bytes.Buffer
+		handler := NewCleanHandler(logging.NewMockLogger(), ui.NewUserOutput(ui.DefaultConfig())).WithOutput(&out)
+
+		err := handler.Execute(context.Background(), []string{target}, &CleanOptions{Recursive: true, Diff: true})
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), "-// Launch 🚀\n+// Launch \n")
+		content, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, original, string(content))
+	})
+
+	t.Run("writes patch file", func(t *testing.T) {
+		patchPath := filepath.Join(tempDir, "out.patch")
+		var out bytes.Buffer
+		handler := NewCleanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+
+		err := handler.Execute(context.Background(), []string{target}, &CleanOptions{Recursive: true, PatchFile: patchPath})
+		require.NoError(t, err)
+
+		patch, err := os.ReadFile(patchPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(patch), "@@ -1,4 +1,4 @@")
+
+		content, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, original, string(content))
+
+		// Nothing was written, so the run is reported as a preview
+		assert.Contains(t, out.String(), "Would clean "+target+": 1 emojis to remove")
+		assert.Contains(t, out.String(), "Summary: would remove 1 emojis")
+		assert.NotContains(t, out.String(), "Cleaned "+target)
+	})
+
+	t.Run("rejects diff with in-place", func(t *testing.T) {
+		handler := NewCleanHandler(logging.NewMockLogger(), ui.NewUserOutput(ui.DefaultConfig()))
+		err := handler.Execute(context.Background(), []string{target}, &CleanOptions{Diff: true, InPlace: true})
+		assert.Error(t, err)
+	})
+}
+
+func TestCleanHandler_OnlyExcept(t *testing.T) {
+	original := "// 🚀 launch 🔥 done ✅\n"
+	run := func(t *testing.T, opts *CleanOptions) string {
+		target := filepath.Join(t.TempDir(), "main.go")
+		require.NoError(t, os.WriteFile(target, []byte(original), 0644))
+		opts.Recursive, opts.InPlace = true, true
+
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+		require.NoError(t, handler.Execute(context.Background(), []string{target}, opts))
+		content, err := os.ReadFile(target)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	assert.Equal(t, "//  launch 🔥 done ✅\n", run(t, &CleanOptions{Only: []string{"🚀"}}))
+	assert.Equal(t, "//  launch  done ✅\n", run(t, &CleanOptions{Except: []string{"✅"}}))
+}
+
+func TestCleanHandler_Check(t *testing.T) {
+	tempDir := t.TempDir()
+	dirty := filepath.Join(tempDir, "main.go")
+	original := "package main\n\n// Launch 🚀 🎉\nfunc main() {}\n"
+	require.NoError(t, os.WriteFile(dirty, []byte(original), 0644))
+	clean := filepath.Join(tempDir, "clean.go")
+	require.NoError(t, os.WriteFile(clean, []byte("package main\n"), 0644))
+
+	t.Run("lists files that would change and fails", func(t *testing.T) {
+		var out bytes.Buffer
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+
+		err := handler.Execute(context.Background(), []string{tempDir}, &CleanOptions{Recursive: true, Check: true})
+		assert.ErrorIs(t, err, ErrCleanCheckFailed)
+		assert.ErrorContains(t, err, "1 files would be cleaned (2 emojis)")
+		assert.Equal(t, dirty+": 2 emojis\n", out.String())
+
+		content, err := os.ReadFile(dirty)
+		require.NoError(t, err)
+		assert.Equal(t, original, string(content))
+	})
+
+	t.Run("passes when nothing would change", func(t *testing.T) {
+		var out bytes.Buffer
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+
+		err := handler.Execute(context.Background(), []string{clean}, &CleanOptions{Recursive: true, Check: true})
+		require.NoError(t, err)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("passes when only warnings would change", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(
+			"profiles:\n  default:\n    unicode_emojis: true\n    severity:\n      unicode: warn\n"), 0600))
+		var out bytes.Buffer
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+
+		err := handler.Execute(context.Background(), []string{tempDir}, &CleanOptions{Recursive: true, Check: true, ConfigFile: configPath})
+		require.NoError(t, err)
+		assert.Equal(t, dirty+": 2 emojis\n", out.String())
+	})
+
+	t.Run("rejects check with in-place", func(t *testing.T) {
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+		err := handler.Execute(context.Background(), []string{dirty}, &CleanOptions{Check: true, InPlace: true})
+		assert.ErrorContains(t, err, "--check cannot be combined with --in-place")
+	})
+}
+
+func TestCleanAndScanAgree(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(target, []byte("// done ✅ :) 🚀 🎉\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("# 🎉\n"), 0644))
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(
+		"profiles:\n  default:\n    unicode_emojis: true\n    text_emoticons: false\n    emoji_allowlist: [\"✅\"]\n"), 0600))
+
+	scan := func() error {
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", configPath, "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		return handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{
+			Recursive: true, Format: "table", Threshold: 1, ExcludePattern: "*.md"})
+	}
+	clean := func(opts CleanOptions) error {
+		opts.Recursive, opts.ConfigFile, opts.ExcludePattern = true, configPath, "*.md"
+		return NewCleanHandler(logging.NewMockLogger(), quietOutput()).WithOutput(io.Discard).
+			Execute(context.Background(), []string{dir}, &opts)
+	}
+
+	// Allowlisted emojis, disabled emoticons and excluded files are not violations for either
+	assert.ErrorIs(t, scan(), ErrEmojiThresholdExceeded)
+	assert.ErrorContains(t, clean(CleanOptions{Check: true}), "1 files would be cleaned (2 emojis)")
+
+	require.NoError(t, clean(CleanOptions{InPlace: true}))
+	assert.NoError(t, clean(CleanOptions{Check: true}))
+	assert.NoError(t, scan())
+
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "// done ✅ :)  \n", string(content))
+}
+
+func TestCleanHandler_Exemptions(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+	require.NoError(t, os.MkdirAll("legacy", 0755))
+	original := "// Launch 🚀\n"
+	require.NoError(t, os.WriteFile(filepath.Join("legacy", "old.go"), []byte(original), 0644))
+	require.NoError(t, os.WriteFile(".antimoji.yaml", []byte(
+		"profiles:\n  default:\n    unicode_emojis: true\n    exemptions:\n      - path: legacy/**\n        expires: \"2999-12-31\"\n        reason: migration\n"), 0600))
+
+	clean := func(opts CleanOptions) error {
+		opts.Recursive, opts.ConfigFile = true, ".antimoji.yaml"
+		return NewCleanHandler(logging.NewMockLogger(), quietOutput()).WithOutput(io.Discard).
+			Execute(context.Background(), []string{"legacy"}, &opts)
+	}
+
+	// Exempted findings are not violations, so clean neither fails on nor removes them
+	assert.NoError(t, clean(CleanOptions{Check: true}))
+	require.NoError(t, clean(CleanOptions{InPlace: true}))
+	content, err := os.ReadFile(filepath.Join("legacy", "old.go"))
+	require.NoError(t, err)
+	assert.Equal(t, original, string(content))
+}
+
+func TestCleanHandler_Atomic(t *testing.T) {
+	dir := t.TempDir()
+	original := "// Launch 🚀\n"
+	for _, name := range []string{"a.go", "b.go"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(original), 0644))
+	}
+	// A replacement a Latin-1 file cannot hold fails that file
+	latin1 := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(latin1, []byte("caf\xe9 :)\n"), 0644))
+	clean := func(backup bool) error {
+		return NewCleanHandler(logging.NewMockLogger(), quietOutput()).Execute(context.Background(), []string{dir},
+			&CleanOptions{Recursive: true, InPlace: true, Backup: backup, Atomic: true, Replace: "→"})
+	}
+	readAll := func() (string, string) {
+		a, err := os.ReadFile(filepath.Join(dir, "a.go"))
+		require.NoError(t, err)
+		b, err := os.ReadFile(filepath.Join(dir, "b.go"))
+		require.NoError(t, err)
+		return string(a), string(b)
+	}
+
+	err := clean(true)
+	assert.ErrorIs(t, err, ErrIO)
+	a, b := readAll()
+	assert.Equal(t, original, a)
+	assert.Equal(t, original, b)
+	backups, err := filepath.Glob(filepath.Join(dir, "*.backup.*"))
+	require.NoError(t, err)
+	assert.Empty(t, backups, "backups of rolled back files are removed")
+
+	require.NoError(t, os.Remove(latin1))
+	require.NoError(t, clean(false))
+	a, b = readAll()
+	assert.Equal(t, "// Launch →\n", a)
+	assert.Equal(t, "// Launch →\n", b)
+}
+
+func TestCleanHandler_Quarantine(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+	original := "package main\n\n// Launch 🚀 🎉\nfunc main() {}\n"
+	require.NoError(t, os.MkdirAll("src", 0755))
+	dirty := filepath.Join("src", "main.go")
+	require.NoError(t, os.WriteFile(dirty, []byte(original), 0644))
+	clean := filepath.Join("src", "clean.go")
+	require.NoError(t, os.WriteFile(clean, []byte("package main\n"), 0644))
+
+	t.Run("previews with dry-run", func(t *testing.T) {
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+		err := handler.Execute(context.Background(), []string{"."}, &CleanOptions{Recursive: true, DryRun: true, QuarantineDir: "quarantine"})
+		require.NoError(t, err)
+		assert.FileExists(t, dirty)
+		assert.NoDirExists(t, "quarantine")
+	})
+
+	t.Run("moves files with emojis unchanged", func(t *testing.T) {
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+		err := handler.Execute(context.Background(), []string{"."}, &CleanOptions{Recursive: true, QuarantineDir: "quarantine"})
+		require.NoError(t, err)
+
+		assert.NoFileExists(t, dirty)
+		assert.FileExists(t, clean)
+		content, err := os.ReadFile(filepath.Join("quarantine", "src", "main.go"))
+		require.NoError(t, err)
+		assert.Equal(t, original, string(content))
+
+		manifest, err := quarantine.Load("quarantine")
+		require.NoError(t, err)
+		require.Len(t, manifest.Files, 1)
+		assert.Equal(t, "src/main.go", manifest.Files[0].Quarantined)
+		assert.Equal(t, 2, manifest.Files[0].Emojis)
+
+		// Quarantined files are not scanned again
+		err = handler.Execute(context.Background(), []string{"."}, &CleanOptions{Recursive: true, QuarantineDir: "quarantine"})
+		require.NoError(t, err)
+		manifest, err = quarantine.Load("quarantine")
+		require.NoError(t, err)
+		assert.Len(t, manifest.Files, 1)
+	})
+
+	t.Run("rejects quarantine with in-place", func(t *testing.T) {
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+		err := handler.Execute(context.Background(), []string{"."}, &CleanOptions{InPlace: true, QuarantineDir: "quarantine"})
+		assert.ErrorContains(t, err, "--quarantine-dir cannot be combined with --in-place")
+	})
+}
@@ -0,0 +1,438 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/antimoji/antimoji/internal/config"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// ConfigOptions holds the options for the config commands.
+type ConfigOptions struct {
+	Effective   bool
+	Format      string
+	Strict      bool
+	DryRun      bool
+	ConfigFile  string
+	ProfileName string
+	Overrides   []string
+}
+
+// ConfigHandler handles the config command group with dependency injection.
+type ConfigHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewConfigHandler creates a new config command handler.
+func NewConfigHandler(logger logging.Logger, ui ui.UserOutput) *ConfigHandler {
+	return &ConfigHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for configuration values (defaults to stdout).
+func (h *ConfigHandler) WithOutput(out io.Writer) *ConfigHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the config cobra command and its subcommands.
+func (h *ConfigHandler) CreateCommand() *cobra.Command {
+	opts := &ConfigOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect, edit and validate configuration",
+		Long: `Inspect, edit and validate antimoji configuration.
+
+Settings are resolved as defaults < config file < ANTIMOJI_* environment
+variables < --set flags. 'config show --effective' reports where each value
+came from. Every subcommand reads and writes the file given with --config, or
+.antimoji.yaml in the working directory; show and get use the defaults when
+neither exists.
+
+Examples:
+  antimoji config show --effective --profile ci       # Resolved settings with provenance
+  antimoji config get max_file_size                    # Effective value of one field
+  antimoji config set profiles.ci.max_total 3  # Edit .antimoji.yaml, keeping comments
+  antimoji config lint --config .antimoji.yaml         # Run the configuration validator
+  antimoji config migrate --dry-run                    # Preview a schema upgrade`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	show := &cobra.Command{
+		Use:           "show",
+		Short:         "Print the settings of a profile",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			readRootOptions(cmd, opts)
+			return h.ExecuteShow(cmd.Context(), opts)
+		},
+	}
+	show.Flags().BoolVar(&opts.Effective, "effective", false, "apply environment and --set overrides and show where each value came from")
+	show.Flags().StringVar(&opts.Format, "format", "text", "output format (text, json)")
+
+	get := &cobra.Command{
+		Use:           "get <field>",
+		Short:         "Print the effective value of a profile field",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			readRootOptions(cmd, opts)
+			return h.ExecuteGet(cmd.Context(), args[0], opts)
+		},
+	}
+
+	set := &cobra.Command{
+		Use:   "set <field> <value>",
+		Short: "Set a profile field in the configuration file",
+		Long: `Set a profile field in the configuration file, preserving comments and layout.
+
+The field is either profiles.<name>.<field> or a bare field name of the profile
+selected with --profile. Lists are comma separated and sizes accept units such as
+10MB. The file (--config, default .antimoji.yaml) is only written when the result
+is a valid configuration.`,
+		Args:          cobra.ExactArgs(2),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			readRootOptions(cmd, opts)
+			return h.ExecuteSet(cmd.Context(), args[0], args[1], opts)
+		},
+	}
+
+	lint := &cobra.Command{
+		Use:           "lint",
+		Short:         "Validate the configuration file",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			readRootOptions(cmd, opts)
+			return h.ExecuteLint(cmd.Context(), opts)
+		},
+	}
+	lint.Flags().BoolVar(&opts.Strict, "strict", false, "fail on warnings as well as errors")
+
+	migrate := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the configuration file to the current schema",
+		Long: `Upgrade the configuration file (--config, default .antimoji.yaml) to the
+current schema, rewriting deprecated keys while preserving comments and layout.
+
+Each transformation is printed; with --dry-run the file is left unchanged.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			readRootOptions(cmd, opts)
+			return h.ExecuteMigrate(cmd.Context(), opts)
+		},
+	}
+	migrate.Flags().BoolVar(&opts.DryRun, "dry-run", false, "print the transformations without writing the file")
+
+	cmd.AddCommand(show, get, set, lint, migrate)
+	return cmd
+}
+
+// fieldReport is one line of 'config show'.
+type fieldReport struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source,omitempty"`
+}
+
+// ExecuteShow prints every field of the selected profile.
+func (h *ConfigHandler) ExecuteShow(parentCtx context.Context, opts *ConfigOptions) error {
+	ctx := h.context(parentCtx, "config-show")
+
+	var overrides [][]config.Override
+	if opts.Effective {
+		flagOverrides, err := config.ParseSetFlags(opts.Overrides)
+		if err != nil {
+			return err
+		}
+		overrides = [][]config.Override{config.EnvOverrides(os.Environ()), flagOverrides}
+	}
+
+	resolution, err := h.load(opts, overrides...)
+	if err != nil {
+		return err
+	}
+
+	fields := make([]fieldReport, 0, len(config.ProfileFields()))
+	for _, key := range config.ProfileFields() {
+		value, _ := config.FieldValue(resolution.Profile, key)
+		report := fieldReport{Key: key, Value: value}
+		if opts.Effective {
+			report.Source = resolution.Sources[key].String()
+		}
+		fields = append(fields, report)
+	}
+	h.logger.Debug(ctx, "Showing configuration", "profile", profileOrDefault(opts.ProfileName), "effective", opts.Effective)
+
+	out := h.output()
+	switch strings.ToLower(opts.Format) {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(struct {
+			Profile    string        `json:"profile"`
+			ConfigFile string        `json:"config_file,omitempty"`
+			Fields     []fieldReport `json:"fields"`
+		}{profileOrDefault(opts.ProfileName), readConfigFile(opts), fields})
+
+	case "text":
+		tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		for _, field := range fields {
+			if opts.Effective {
+				_, _ = fmt.Fprintf(tw, "%s:\t%s\t(%s)\n", field.Key, formatFieldValue(field.Value), field.Source)
+			} else {
+				_, _ = fmt.Fprintf(tw, "%s:\t%s\n", field.Key, formatFieldValue(field.Value))
+			}
+		}
+		return tw.Flush()
+
+	default:
+		return fmt.Errorf("unsupported format %q; supported: text, json", opts.Format)
+	}
+}
+
+// ExecuteGet prints the effective value of a single field.
+func (h *ConfigHandler) ExecuteGet(parentCtx context.Context, field string, opts *ConfigOptions) error {
+	ctx := h.context(parentCtx, "config-get")
+
+	profileName, key, err := h.fieldPath(field, opts)
+	if err != nil {
+		return err
+	}
+
+	flagOverrides, err := config.ParseSetFlags(opts.Overrides)
+	if err != nil {
+		return err
+	}
+
+	scoped := *opts
+	scoped.ProfileName = profileName
+	resolution, err := h.load(&scoped, config.EnvOverrides(os.Environ()), flagOverrides)
+	if err != nil {
+		return err
+	}
+
+	value, _ := config.FieldValue(resolution.Profile, key)
+	h.logger.Debug(ctx, "Configuration value read", "profile", profileName, "field", key, "source", resolution.Sources[key].String())
+	_, err = fmt.Fprintln(h.output(), formatFieldValue(value))
+	return err
+}
+
+// ExecuteSet writes a field to the configuration file.
+func (h *ConfigHandler) ExecuteSet(parentCtx context.Context, field, value string, opts *ConfigOptions) error {
+	ctx := h.context(parentCtx, "config-set")
+
+	profileName, key, err := h.fieldPath(field, opts)
+	if err != nil {
+		return err
+	}
+
+	configPath := configFile(opts)
+
+	path := "profiles." + profileName + "." + key
+	if err := config.SetValue(configPath, path, value); err != nil {
+		h.logger.Error(ctx, "Failed to set configuration value", "config_file", configPath, "path", path, "error", err)
+		return fmt.Errorf("failed to set %s: %w", path, err)
+	}
+
+	h.logger.Info(ctx, "Configuration value set", "config_file", configPath, "path", path)
+	h.ui.Success(ctx, "Set %s in %s", path, configPath)
+	return nil
+}
+
+// ExecuteLint runs the configuration validator and fails on errors (or warnings when strict).
+func (h *ConfigHandler) ExecuteLint(parentCtx context.Context, opts *ConfigOptions) error {
+	ctx := h.context(parentCtx, "config-lint")
+
+	configPath := configFile(opts)
+
+	result := config.ValidateConfigFile(configPath)
+	h.logger.Info(ctx, "Configuration validated", "config_file", configPath,
+		"errors", result.Summary.Errors, "warnings", result.Summary.Warnings)
+
+	if _, err := fmt.Fprint(h.output(), result.String()); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(result.String(), "\n") {
+		_, _ = fmt.Fprintln(h.output())
+	}
+
+	if result.HasErrors() {
+		return fmt.Errorf("%s: %d configuration errors", configPath, result.Summary.Errors)
+	}
+	if opts.Strict && result.Summary.Warnings > 0 {
+		return fmt.Errorf("%s: %d configuration warnings (strict)", configPath, result.Summary.Warnings)
+	}
+	return nil
+}
+
+// ExecuteMigrate upgrades the configuration file to the current schema and prints the
+// transformations made.
+func (h *ConfigHandler) ExecuteMigrate(parentCtx context.Context, opts *ConfigOptions) error {
+	ctx := h.context(parentCtx, "config-migrate")
+
+	configPath := configFile(opts)
+
+	result, err := config.MigrateFile(configPath, opts.DryRun)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to migrate configuration", "config_file", configPath, "error", err)
+		return classify(ErrConfig, fmt.Errorf("failed to migrate %s: %w", configPath, err))
+	}
+	if !result.NeedsMigration() {
+		h.ui.Info(ctx, "%s already uses configuration schema %d", configPath, result.ToVersion)
+		return nil
+	}
+
+	for _, change := range result.Changes {
+		if _, err := fmt.Fprintf(h.output(), "  %s\n", change); err != nil {
+			return err
+		}
+	}
+
+	h.logger.Info(ctx, "Configuration migrated", "config_file", configPath,
+		"from", result.FromVersion, "to", result.ToVersion, "changes", len(result.Changes), "dry_run", opts.DryRun)
+	if opts.DryRun {
+		h.ui.Info(ctx, "Would migrate %s from schema %d to %d (dry run)", configPath, result.FromVersion, result.ToVersion)
+		return nil
+	}
+	h.ui.Success(ctx, "Migrated %s from schema %d to %d", configPath, result.FromVersion, result.ToVersion)
+	return nil
+}
+
+// readRootOptions copies the global --config, --profile and --set flags into opts.
+func readRootOptions(cmd *cobra.Command, opts *ConfigOptions) {
+	opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+	opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+	opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+}
+
+// configFile returns the configuration file the config subcommands read and write:
+// --config, or .antimoji.yaml in the working directory.
+func configFile(opts *ConfigOptions) string {
+	if opts.ConfigFile != "" {
+		return opts.ConfigFile
+	}
+	return defaultConfigFile
+}
+
+// readConfigFile returns the configuration file profiles are read from: configFile,
+// or "" for the built-in defaults when no --config is given and .antimoji.yaml does
+// not exist.
+func readConfigFile(opts *ConfigOptions) string {
+	path := configFile(opts)
+	if opts.ConfigFile == "" {
+		if _, err := os.Stat(path); err != nil {
+			return ""
+		}
+	}
+	return path
+}
+
+// load resolves the selected profile with the given override layers and the
+// organization policy.
+func (h *ConfigHandler) load(opts *ConfigOptions, overrides ...[]config.Override) (config.Resolution, error) {
+	resolved := config.LoadResolution(readConfigFile(opts), opts.ProfileName, overrides...)
+	if resolved.IsErr() {
+		return config.Resolution{}, resolved.Error()
+	}
+	return enforceOrgPolicy(resolved.Unwrap(), os.Environ())
+}
+
+// fieldPath accepts profiles.<name>.<field> or a bare field of the selected profile.
+func (h *ConfigHandler) fieldPath(field string, opts *ConfigOptions) (profileName, key string, err error) {
+	if !strings.HasPrefix(field, "profiles.") {
+		field = "profiles." + profileOrDefault(opts.ProfileName) + "." + field
+	}
+	return config.ParseFieldPath(field)
+}
+
+// context derives the command context for a config operation.
+func (h *ConfigHandler) context(parentCtx context.Context, operation string) context.Context {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, operation)
+	return ctxutil.WithComponent(ctx, "cli")
+}
+
+// output returns the writer for configuration values.
+func (h *ConfigHandler) output() io.Writer {
+	if h.out == nil {
+		return os.Stdout
+	}
+	return h.out
+}
+
+// profileOrDefault returns name, or "default" when empty.
+func profileOrDefault(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+// formatFieldValue renders a profile value on one line: lists as ["a", "b"], maps as
+// {"k": "v"} with sorted keys and structs, such as exemptions, as JSON objects.
+func formatFieldValue(value interface{}) string {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatFieldValue(v.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Struct:
+		var encoded bytes.Buffer
+		encoder := json.NewEncoder(&encoded)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Sprint(value)
+		}
+		return strings.TrimSuffix(encoded.String(), "\n")
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = strconv.Quote(k) + ": " + formatFieldValue(v.MapIndex(reflect.ValueOf(k)).Interface())
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case reflect.String:
+		return strconv.Quote(v.String())
+	default:
+		return fmt.Sprint(value)
+	}
+}
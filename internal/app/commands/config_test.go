@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `version: "0.5.0"
+profiles:
+  default:
+    recursive: true
+    unicode_emojis: true
+    # Maximum number of emojis allowed
+    max_total: 2 # keep low
+    emoji_allowlist:
+      - "✅"
+`
+
+func newTestConfigHandler(out *bytes.Buffer) *ConfigHandler {
+	return NewConfigHandler(logging.NewMockLogger(), quietOutput()).WithOutput(out)
+}
+
+func TestConfigHandler_Show(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfigYAML), 0600))
+
+	t.Run("text", func(t *testing.T) {
+		var out bytes.Buffer
+		err := newTestConfigHandler(&out).ExecuteShow(context.Background(), &ConfigOptions{ConfigFile: path, Format: "text"})
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "max_total:")
+		assert.Contains(t, out.String(), `["✅"]`)
+		assert.NotContains(t, out.String(), "(file)")
+	})
+
+	t.Run("effective json reports sources", func(t *testing.T) {
+		t.Setenv("ANTIMOJI_MAX_WORKERS", "3")
+		var out bytes.Buffer
+		opts := &ConfigOptions{ConfigFile: path, Format: "json", Effective: true, Overrides: []string{"threshold=5"}}
+		require.NoError(t, newTestConfigHandler(&out).ExecuteShow(context.Background(), opts))
+
+		var report struct {
+			Profile string        `json:"profile"`
+			Fields  []fieldReport `json:"fields"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		assert.Equal(t, "default", report.Profile)
+
+		sources := map[string]string{}
+		for _, field := range report.Fields {
+			sources[field.Key] = field.Source
+		}
+		assert.Equal(t, "flag", sources["max_total"])
+		assert.Equal(t, "env", sources["max_workers"])
+		assert.Equal(t, "file", sources["recursive"])
+		assert.Equal(t, "default", sources["buffer_size"])
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		var out bytes.Buffer
+		err := newTestConfigHandler(&out).ExecuteShow(context.Background(), &ConfigOptions{ConfigFile: path, Format: "xml"})
+		assert.ErrorContains(t, err, "unsupported format")
+	})
+}
+
+func TestConfigHandler_Get(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfigYAML), 0600))
+
+	var out bytes.Buffer
+	handler := newTestConfigHandler(&out)
+	require.NoError(t, handler.ExecuteGet(context.Background(), "threshold", &ConfigOptions{ConfigFile: path}))
+	assert.Equal(t, "2\n", out.String())
+
+	out.Reset()
+	opts := &ConfigOptions{ConfigFile: path, Overrides: []string{"max_total=9"}}
+	require.NoError(t, handler.ExecuteGet(context.Background(), "profiles.default.max_total", opts))
+	assert.Equal(t, "9\n", out.String())
+
+	assert.Error(t, handler.ExecuteGet(context.Background(), "no_such_field", &ConfigOptions{ConfigFile: path}))
+
+	t.Run("structs", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`profiles:
+  default:
+    exemptions:
+      - path: docs/**
+        expires: "2999-12-31"
+        reason: migration
+`), 0600))
+
+		var out bytes.Buffer
+		require.NoError(t, newTestConfigHandler(&out).ExecuteGet(context.Background(), "exemptions", &ConfigOptions{ConfigFile: path}))
+		assert.Equal(t, `[{"path":"docs/**","expires":"2999-12-31","reason":"migration"}]`+"\n", out.String())
+	})
+}
+
+func TestConfigHandler_Set(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfigYAML), 0600))
+
+	var out bytes.Buffer
+	handler := newTestConfigHandler(&out)
+	require.NoError(t, handler.ExecuteSet(context.Background(), "max_total", "4", &ConfigOptions{ConfigFile: path, ProfileName: "default"}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# Maximum number of emojis allowed")
+	assert.Contains(t, string(content), "max_total: 4 # keep low")
+
+	err = handler.ExecuteSet(context.Background(), "max_workers", "-2", &ConfigOptions{ConfigFile: path})
+	assert.Error(t, err)
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, unchanged)
+}
+
+func TestConfigHandler_DefaultFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, defaultConfigFile), []byte(testConfigYAML), 0600))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+	// Without --config, set and get use .antimoji.yaml alike
+	var out bytes.Buffer
+	handler := newTestConfigHandler(&out)
+	require.NoError(t, handler.ExecuteSet(context.Background(), "profiles.ci.max_total", "3", &ConfigOptions{}))
+	require.NoError(t, handler.ExecuteGet(context.Background(), "profiles.ci.max_total", &ConfigOptions{}))
+	assert.Equal(t, "3\n", out.String())
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteShow(context.Background(), &ConfigOptions{ProfileName: "ci", Format: "json"}))
+	assert.Contains(t, out.String(), `"config_file": "`+defaultConfigFile+`"`)
+}
+
+func TestConfigHandler_Lint(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(testConfigYAML), 0600))
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("profiles:\n  default:\n    max_workers: -1\n"), 0600))
+
+	var out bytes.Buffer
+	handler := newTestConfigHandler(&out)
+
+	assert.Error(t, handler.ExecuteLint(context.Background(), &ConfigOptions{ConfigFile: invalid}))
+	assert.NotEmpty(t, out.String())
+
+	out.Reset()
+	assert.NoError(t, handler.ExecuteLint(context.Background(), &ConfigOptions{ConfigFile: valid}), out.String())
+}
+
+func TestConfigHandler_Migrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	legacy := "version: \"0.5.0\"\nprofiles:\n  default:\n    follow_symlinks: true # legacy\n"
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0600))
+
+	var out bytes.Buffer
+	opts := &ConfigOptions{ConfigFile: path, DryRun: true}
+	require.NoError(t, newTestConfigHandler(&out).ExecuteMigrate(context.Background(), opts))
+	assert.Contains(t, out.String(), "profiles.default.follow_symlinks: replaced by symlink_policy: follow")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(content))
+
+	out.Reset()
+	opts.DryRun = false
+	require.NoError(t, newTestConfigHandler(&out).ExecuteMigrate(context.Background(), opts))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "version: 3\nprofiles:\n  default:\n    symlink_policy: follow # legacy\n", string(content))
+
+	require.NoError(t, os.WriteFile(path, []byte("version: 9\n"), 0600))
+	err = newTestConfigHandler(&out).ExecuteMigrate(context.Background(), opts)
+	assert.ErrorIs(t, err, ErrConfig)
+}
@@ -0,0 +1,365 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/infra/daemon"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// forwardedRootFlags are the root persistent flags a scan sends to the daemon.
+var forwardedRootFlags = map[string]bool{
+	"config":         true,
+	"profile":        true,
+	"verbose":        true,
+	setFlag:          true,
+	strictConfigFlag: true,
+}
+
+// DaemonOptions holds the options for the daemon command.
+type DaemonOptions struct {
+	Socket      string
+	IdleTimeout time.Duration
+}
+
+// DaemonHandler handles the daemon command with dependency injection.
+type DaemonHandler struct {
+	logger  logging.Logger
+	ui      ui.UserOutput
+	metrics *metrics.Metrics
+	warm    *warmState
+}
+
+// NewDaemonHandler creates a new daemon command handler.
+func NewDaemonHandler(logger logging.Logger, ui ui.UserOutput) *DaemonHandler {
+	return &DaemonHandler{
+		logger: logger,
+		ui:     ui,
+		warm:   newWarmState(),
+	}
+}
+
+// WithMetrics sets the metrics that the daemon's scans are recorded in (defaults to none).
+func (h *DaemonHandler) WithMetrics(m *metrics.Metrics) *DaemonHandler {
+	h.metrics = m
+	return h
+}
+
+// CreateCommand creates the daemon cobra command.
+func (h *DaemonHandler) CreateCommand() *cobra.Command {
+	opts := &DaemonOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Serve scans from a long-running process",
+		Long: `Run antimoji in the foreground and serve 'antimoji scan --via-daemon'
+requests over a Unix socket.
+
+The daemon keeps configuration files, emoji patterns and detection results in
+memory between requests, so hooks that scan a few files at a time skip the
+start-up work of a fresh process. Configuration files are reloaded when they
+change; restart the daemon to pick up changed emoji data sources.
+
+The socket defaults to a per-user path derived from the directory the daemon
+is started in, so start it in the repository root, where pre-commit runs its
+hooks. Stop it with Ctrl-C or let --idle-timeout end it.
+
+Examples:
+  antimoji daemon &                          # Serve scans for this repository
+  antimoji daemon --idle-timeout 30m &       # Exit after 30 idle minutes
+  antimoji scan --via-daemon file.go         # Scan through the daemon`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return h.Execute(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Socket, "socket", "", "socket path (default $"+daemon.EnvSocket+" or one derived from the working directory)")
+	cmd.Flags().DurationVar(&opts.IdleTimeout, "idle-timeout", 0, "exit after this long without requests (0 = never)")
+
+	return cmd
+}
+
+// Execute runs the daemon until it is interrupted or idle for opts.IdleTimeout.
+func (h *DaemonHandler) Execute(parentCtx context.Context, opts *DaemonOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	socket := opts.Socket
+	if socket == "" {
+		socket = daemon.DefaultSocketPath(".")
+	}
+	listener, err := daemon.Listen(socket)
+	if err != nil {
+		return classify(ErrIO, err)
+	}
+
+	h.logger.Info(ctx, "Daemon started", "socket", socket, "idle_timeout", opts.IdleTimeout)
+	h.ui.Info(ctx, "Antimoji daemon listening on %s", socket)
+	if err := daemon.Serve(ctx, listener, h.serve, opts.IdleTimeout); err != nil {
+		return classify(ErrIO, err)
+	}
+	h.logger.Info(ctx, "Daemon stopped", "socket", socket)
+	return nil
+}
+
+// serve runs the scan of a request in the request's working directory.
+func (h *DaemonHandler) serve(ctx context.Context, req daemon.Request) daemon.Response {
+	startTime := time.Now()
+	if len(req.Args) == 0 || req.Args[0] != "scan" {
+		return daemon.Response{Error: fmt.Sprintf("unsupported daemon command %q; supported: scan", req.Args), Class: className(ErrConfig)}
+	}
+
+	// Requests are served one at a time, so the working directory can follow them
+	wd, err := os.Getwd()
+	if err != nil {
+		return daemon.Response{Error: fmt.Sprintf("failed to get daemon working directory: %v", err), Class: className(ErrIO)}
+	}
+	if err := os.Chdir(req.Dir); err != nil {
+		return daemon.Response{Error: fmt.Sprintf("failed to enter %s: %v", req.Dir, err), Class: className(ErrIO)}
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	var stdout, stderr bytes.Buffer
+	output := ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &stdout, ErrorWriter: &stderr})
+	scan := NewScanHandler(h.logger, output).WithMetrics(h.metrics)
+	scan.warm = h.warm
+	scan.environ = append([]string{}, req.Env...)
+
+	root := &cobra.Command{Use: "antimoji", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", "", "config file path")
+	root.PersistentFlags().String("profile", "default", "configuration profile")
+	root.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	root.PersistentFlags().Bool(strictConfigFlag, false, "fail when the config file contains unknown keys")
+	root.PersistentFlags().StringArray(setFlag, nil, "override a profile field")
+	root.AddCommand(scan.CreateCommand())
+	root.SetArgs(req.Args)
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+
+	err = root.ExecuteContext(ctx)
+	resp := daemon.Response{Stdout: stdout.String(), Stderr: stderr.String()}
+	if err != nil {
+		resp.Error = err.Error()
+		resp.ThresholdExceeded = errors.Is(err, policy.ErrThresholdExceeded)
+		resp.Class = className(err)
+	}
+	h.logger.Info(ctx, "Daemon request served",
+		"dir", req.Dir,
+		"args", req.Args,
+		"error", resp.Error,
+		"duration", time.Since(startTime))
+	return resp
+}
+
+// warmState keeps what a scan loads before detection between the requests of a
+// daemon. It is not safe for concurrent use; the daemon serves one request at a time.
+type warmState struct {
+	// configs are the managers of the configuration files loaded, by directory, path,
+	// profile and strictness
+	configs  map[string]*config.Manager
+	patterns map[string]types.EmojiPatterns
+	// caches are the result caches opened for --cache, by directory and fingerprint
+	caches map[string]*cache.Cache
+	// memory are the in-memory result caches used without --cache, by fingerprint
+	memory map[string]*cache.Cache
+}
+
+func newWarmState() *warmState {
+	return &warmState{
+		configs:  make(map[string]*config.Manager),
+		patterns: make(map[string]types.EmojiPatterns),
+		caches:   make(map[string]*cache.Cache),
+		memory:   make(map[string]*cache.Cache),
+	}
+}
+
+// memoryCache returns the in-memory result cache for fingerprint.
+func (w *warmState) memoryCache(fingerprint string) *cache.Cache {
+	c, ok := w.memory[fingerprint]
+	if !ok {
+		c = cache.NewMemory(fingerprint)
+		w.memory[fingerprint] = c
+	}
+	return c
+}
+
+// loadConfig loads a configuration file, reusing the daemon's copy while the file and
+// the local files it extends are unchanged. Files that cannot be managed, such as remote ones or those without
+// profileName, are loaded afresh.
+func (h *ScanHandler) loadConfig(path, profileName string, strict bool) types.Result[config.Config] {
+	if h.warm == nil {
+		return loadConfigFile(path, strict)
+	}
+
+	key := fmt.Sprintf("%s\x00%s\x00%t", path, profileName, strict)
+	if abs, err := os.Getwd(); err == nil {
+		key = abs + "\x00" + key
+	}
+	if manager, ok := h.warm.configs[key]; ok {
+		if _, err := manager.Reload(); err == nil {
+			return types.Ok(manager.Current().Config)
+		}
+		// Report the error a fresh load gives, as a scan outside the daemon would
+		delete(h.warm.configs, key)
+		return loadConfigFile(path, strict)
+	}
+
+	manager, err := config.NewManagerWithLoader(path, profileName, configLoader(strict))
+	if err != nil {
+		return loadConfigFile(path, strict)
+	}
+	h.warm.configs[key] = manager
+	return types.Ok(manager.Current().Config)
+}
+
+// patterns returns the emoji patterns of engine, reusing the daemon's copy for the
+// same emoji dataset, data sources and detection settings.
+func (h *ScanHandler) patterns(ctx context.Context, engine *policy.Engine) (types.EmojiPatterns, error) {
+	if h.warm == nil {
+		return engine.Patterns(ctx)
+	}
+
+	profile := engine.Profile()
+	keyData, _ := json.Marshal(struct {
+		Dataset   string
+		Sources   []string
+		PublicKey string
+		Config    types.ProcessingConfig
+	}{profile.EmojiDataset, profile.EmojiDataSources, profile.EmojiDataPublicKey, engine.ProcessingConfig()})
+	key := string(keyData)
+	if patterns, ok := h.warm.patterns[key]; ok {
+		return patterns, nil
+	}
+
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return patterns, err
+	}
+	h.warm.patterns[key] = patterns
+	return patterns, nil
+}
+
+// env returns the environment of the scan.
+func (h *ScanHandler) env() []string {
+	if h.environ != nil {
+		return h.environ
+	}
+	return os.Environ()
+}
+
+// executeViaDaemon sends the scan to the daemon and relays its output. It reports
+// false when no daemon is running, leaving the scan to run in-process.
+func (h *ScanHandler) executeViaDaemon(ctx context.Context, cmd *cobra.Command, args []string, opts *ScanOptions) (bool, error) {
+	if h.warm != nil {
+		// Already inside the daemon
+		return false, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return true, classify(ErrIO, fmt.Errorf("failed to get working directory: %w", err))
+	}
+	socket := opts.DaemonSocket
+	if socket == "" {
+		socket = daemon.DefaultSocketPath(dir)
+	}
+
+	argv := []string{"scan"}
+	forward := func(flag *pflag.Flag) {
+		if flag.Name == "via-daemon" || flag.Name == "daemon-socket" {
+			return
+		}
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range values.GetSlice() {
+				argv = append(argv, "--"+flag.Name+"="+value)
+			}
+			return
+		}
+		argv = append(argv, "--"+flag.Name+"="+flag.Value.String())
+	}
+	local := cmd.LocalFlags()
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if local.Lookup(flag.Name) != nil || forwardedRootFlags[flag.Name] {
+			forward(flag)
+		}
+	})
+	if opts.Cache && opts.CacheDir == "" {
+		// The client's environment decides the default cache directory
+		if cacheDir, err := cache.DefaultDir(); err == nil {
+			argv = append(argv, "--cache-dir="+cacheDir)
+		}
+	}
+	argv = append(append(argv, "--"), args...)
+
+	resp, err := daemon.Call(ctx, socket, daemon.Request{Dir: dir, Args: argv, Env: daemon.Environ(os.Environ())})
+	if errors.Is(err, daemon.ErrNotRunning) {
+		h.logger.Debug(ctx, "No daemon running, scanning in-process", "socket", socket)
+		return false, nil
+	}
+	if err != nil {
+		return true, classify(ErrIO, err)
+	}
+
+	out, errOut := h.out, h.errOut
+	if out == nil {
+		out = os.Stdout
+	}
+	if errOut == nil {
+		errOut = os.Stderr
+	}
+	_, _ = io.WriteString(out, resp.Stdout)
+	_, _ = io.WriteString(errOut, resp.Stderr)
+	if resp.Error != "" {
+		return true, daemonError{message: resp.Error, thresholdExceeded: resp.ThresholdExceeded, class: classes[resp.Class]}
+	}
+	return true, nil
+}
+
+// daemonError is an error a command failed with in the daemon.
+type daemonError struct {
+	message           string
+	thresholdExceeded bool
+	// class is the error class of the failure, nil if the daemon did not name one
+	class error
+}
+
+func (e daemonError) Error() string {
+	return e.message
+}
+
+// Is matches ErrEmojiThresholdExceeded when the daemon's error wrapped it.
+func (e daemonError) Is(target error) bool {
+	return e.thresholdExceeded && target == ErrEmojiThresholdExceeded
+}
+
+// Unwrap returns the error class of the failure, so Classify sees it.
+func (e daemonError) Unwrap() error {
+	return e.class
+}
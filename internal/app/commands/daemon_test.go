@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/daemon"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startDaemon serves a daemon on a fresh socket until the test ends.
+func startDaemon(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "amd")
+	require.NoError(t, err)
+	socket := filepath.Join(dir, "d.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewDaemonHandler(logging.NewMockLogger(), quietOutput()).Execute(ctx, &DaemonOptions{Socket: socket})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+		_ = os.RemoveAll(dir)
+	})
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(socket)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return socket
+}
+
+// scanViaDaemon runs 'scan --via-daemon' with args and returns its output.
+func scanViaDaemon(t *testing.T, socket string, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	rootCmd := &cobra.Command{Use: "antimoji", SilenceErrors: true, SilenceUsage: true}
+	rootCmd.PersistentFlags().String("config", "", "config file path")
+	rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+	rootCmd.PersistentFlags().StringArray(setFlag, nil, "override a profile field")
+	handler := NewScanHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out, &out)
+	rootCmd.AddCommand(handler.CreateCommand())
+	rootCmd.SetArgs(append([]string{"scan", "--via-daemon", "--daemon-socket", socket}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestScanHandler_ViaDaemon(t *testing.T) {
+	socket := startDaemon(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(file, []byte("// 🚀 🎉\n"), 0600))
+
+	t.Run("relays the scan output", func(t *testing.T) {
+		out, err := scanViaDaemon(t, socket, file)
+		require.NoError(t, err)
+		assert.Contains(t, out, "found 2 emojis in 1 files")
+	})
+
+	t.Run("forwards flags and overrides", func(t *testing.T) {
+		out, err := scanViaDaemon(t, socket, "--set", "emoji_allowlist=🚀", file)
+		require.NoError(t, err)
+		assert.Contains(t, out, "found 1 emojis in 1 files")
+	})
+
+	t.Run("keeps the threshold error", func(t *testing.T) {
+		out, err := scanViaDaemon(t, socket, "--threshold", "1", file)
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+		assert.Contains(t, out, "Emoji limit exceeded")
+	})
+
+	t.Run("keeps the error class", func(t *testing.T) {
+		_, err := scanViaDaemon(t, socket, filepath.Join(filepath.Dir(file), "missing.go"))
+		assert.Equal(t, ErrIO, Classify(err))
+	})
+
+	t.Run("reuses results between requests", func(t *testing.T) {
+		out, err := scanViaDaemon(t, socket, "--stats", file)
+		require.NoError(t, err)
+		assert.Contains(t, out, "Cache hits: 1, misses: 0")
+	})
+
+	t.Run("sees configuration changes", func(t *testing.T) {
+		configFile := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("profiles:\n  default:\n    emoji_allowlist: [\"🎉\"]\n"), 0600))
+		out, err := scanViaDaemon(t, socket, "--config", configFile, file)
+		require.NoError(t, err)
+		assert.Contains(t, out, "found 1 emojis in 1 files")
+
+		require.NoError(t, os.WriteFile(configFile, []byte("profiles:\n  default:\n    emoji_allowlist: [\"🎉\", \"🚀\"]\n"), 0600))
+		out, err = scanViaDaemon(t, socket, "--config", configFile, file)
+		require.NoError(t, err)
+		assert.Contains(t, out, "found 0 emojis")
+
+		// A broken edit fails the scan, as it would outside the daemon
+		require.NoError(t, os.WriteFile(configFile, []byte("profiles: [\n"), 0600))
+		_, err = scanViaDaemon(t, socket, "--config", configFile, file)
+		assert.Equal(t, ErrConfig, Classify(err))
+	})
+}
+
+func TestDaemonHandler_Metrics(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// 🚀 🎉\n"), 0600))
+
+	m := metrics.New()
+	h := NewDaemonHandler(logging.NewMockLogger(), quietOutput()).WithMetrics(m)
+	resp := h.serve(context.Background(), daemon.Request{Dir: dir, Args: []string{"scan", "main.go"}})
+	require.Empty(t, resp.Error)
+
+	var out bytes.Buffer
+	require.NoError(t, m.Registry().WriteText(&out))
+	assert.Contains(t, out.String(), "antimoji_files_scanned_total 1\n")
+	assert.Contains(t, out.String(), "antimoji_emojis_found_total 2\n")
+}
+
+func TestScanHandler_ViaDaemonFallsBack(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(file, []byte("// 🚀 🎉\n"), 0600))
+
+	// Without a daemon the scan runs in-process and still enforces the threshold
+	_, err := scanViaDaemon(t, filepath.Join(t.TempDir(), "missing.sock"), "--threshold", "1", file)
+	assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+}
@@ -0,0 +1,568 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/infra/git"
+	"github.com/antimoji/antimoji/internal/infra/remoteconfig"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// preCommitConfigFile is the pre-commit configuration file in the repository root.
+const preCommitConfigFile = ".pre-commit-config.yaml"
+
+// installHint is how to install the antimoji binary.
+const installHint = "go install github.com/jamesainslie/antimoji/cmd/antimoji@latest"
+
+// DoctorOptions holds the options for the doctor command.
+type DoctorOptions struct {
+	ConfigFile  string
+	ProfileName string
+	Overrides   []string
+	CacheDir    string
+}
+
+// DoctorStatus is the outcome of a doctor check.
+type DoctorStatus string
+
+const (
+	DoctorOK      DoctorStatus = "ok"
+	DoctorWarning DoctorStatus = "warn"
+	DoctorProblem DoctorStatus = "fail"
+)
+
+// DoctorCheck is the result of one environment check with the fix for anything not ok.
+type DoctorCheck struct {
+	Name    string
+	Status  DoctorStatus
+	Message string
+	Fix     string
+}
+
+// DoctorHandler handles the doctor command with dependency injection.
+type DoctorHandler struct {
+	logger  logging.Logger
+	ui      ui.UserOutput
+	out     io.Writer
+	version string
+	// lookPath and executable locate binaries; tests replace them
+	lookPath   func(file string) (string, error)
+	executable func() (string, error)
+}
+
+// NewDoctorHandler creates a new doctor command handler.
+func NewDoctorHandler(logger logging.Logger, ui ui.UserOutput) *DoctorHandler {
+	return &DoctorHandler{
+		logger:     logger,
+		ui:         ui,
+		lookPath:   exec.LookPath,
+		executable: os.Executable,
+	}
+}
+
+// WithOutput sets the writer used for the check results (defaults to stdout).
+func (h *DoctorHandler) WithOutput(out io.Writer) *DoctorHandler {
+	h.out = out
+	return h
+}
+
+// WithVersion sets the version of the running binary, compared with the version
+// pre-commit hooks pin.
+func (h *DoctorHandler) WithVersion(version string) *DoctorHandler {
+	h.version = version
+	return h
+}
+
+// CreateCommand creates the doctor cobra command.
+func (h *DoctorHandler) CreateCommand() *cobra.Command {
+	opts := &DoctorOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor [path]",
+		Short: "Diagnose the antimoji setup of a repository",
+		Long: `Check the environment antimoji runs in and print how to fix what is wrong.
+
+The checks cover the configuration file, the selected profile with its
+environment and --set overrides, the antimoji binary on PATH, the git
+repository, the pre-commit hooks (including configuration files they reference
+and the version they pin) and the scan result cache.
+
+Problems make the command fail; warnings do not.
+
+Examples:
+  antimoji doctor                                  # Check the current repository
+  antimoji doctor --config .antimoji.yaml --profile ci  # Check the profile CI uses`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			return h.Execute(cmd.Context(), dir, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.CacheDir, "cache-dir", "", "cache directory to check (default $ANTIMOJI_CACHE_DIR or the user cache directory)")
+
+	return cmd
+}
+
+// Execute runs the checks for the repository in dir and prints their results. It fails
+// when any check finds a problem.
+func (h *DoctorHandler) Execute(parentCtx context.Context, dir string, opts *DoctorOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "doctor")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	checks := h.Checks(ctx, dir, opts)
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	counts := make(map[DoctorStatus]int)
+	for _, check := range checks {
+		counts[check.Status]++
+		h.logger.Info(ctx, "Doctor check completed", "check", check.Name, "status", check.Status, "message", check.Message)
+		if _, err := fmt.Fprintf(out, "%-5s %-11s %s\n", check.Status, check.Name, check.Message); err != nil {
+			return classify(ErrIO, fmt.Errorf("failed to write doctor results: %w", err))
+		}
+		if check.Fix != "" {
+			if _, err := fmt.Fprintf(out, "%-17s fix: %s\n", "", check.Fix); err != nil {
+				return classify(ErrIO, fmt.Errorf("failed to write doctor results: %w", err))
+			}
+		}
+	}
+
+	h.ui.Result(ctx, "Doctor: %d ok, %d warnings, %d problems", counts[DoctorOK], counts[DoctorWarning], counts[DoctorProblem])
+	if counts[DoctorProblem] > 0 {
+		return classify(ErrConfig, fmt.Errorf("doctor found %d problems", counts[DoctorProblem]))
+	}
+	return nil
+}
+
+// Checks runs every check for the repository in dir.
+func (h *DoctorHandler) Checks(ctx context.Context, dir string, opts *DoctorOptions) []DoctorCheck {
+	configCheck, cfg, fromFile := h.checkConfig(dir, opts)
+	checks := []DoctorCheck{
+		configCheck,
+		h.checkProfile(cfg, fromFile, opts),
+		h.checkBinary(),
+	}
+
+	repo := git.NewRepository(dir)
+	gitCheck, root := h.checkGit(ctx, repo)
+	checks = append(checks, gitCheck)
+	if root == "" {
+		root = dir
+	}
+	checks = append(checks, h.checkPreCommit(ctx, repo, root)...)
+
+	return append(checks, h.checkCache(opts))
+}
+
+// checkConfig validates the configuration file and returns the configuration the
+// other checks use, and whether it came from a file.
+func (h *DoctorHandler) checkConfig(dir string, opts *DoctorOptions) (DoctorCheck, config.Config, bool) {
+	check := DoctorCheck{Name: "config"}
+	path := opts.ConfigFile
+	if path == "" {
+		path = filepath.Join(dir, defaultConfigFile)
+		if _, err := os.Stat(path); err != nil {
+			check.Status = DoctorWarning
+			check.Message = fmt.Sprintf("no %s; built-in defaults are used", defaultConfigFile)
+			check.Fix = "run 'antimoji setup-lint' or 'antimoji generate' to create one"
+			return check, config.DefaultConfig(), false
+		}
+	}
+
+	result := config.ValidateConfigFile(path)
+	switch {
+	case result.HasErrors():
+		check.Status = DoctorProblem
+		check.Message = fmt.Sprintf("%s has %d errors: %s", path, result.Summary.Errors, firstIssue(result, config.ValidationLevelError))
+		check.Fix = fmt.Sprintf("run 'antimoji config lint --config %s' for details", path)
+	case result.Summary.Warnings > 0:
+		check.Status = DoctorWarning
+		check.Message = fmt.Sprintf("%s has %d warnings: %s", path, result.Summary.Warnings, firstIssue(result, config.ValidationLevelWarning))
+		check.Fix = fmt.Sprintf("run 'antimoji config lint --config %s' for details", path)
+	default:
+		check.Status = DoctorOK
+		check.Message = fmt.Sprintf("%s is valid", path)
+	}
+	if opts.ConfigFile == "" && check.Status == DoctorOK {
+		check.Message += fmt.Sprintf(" (commands read it with --config %s)", path)
+	}
+
+	loaded := loadConfigFile(path, false)
+	if loaded.IsErr() {
+		return check, config.DefaultConfig(), false
+	}
+	if warnings := loaded.Unwrap().Warnings; len(warnings) > 0 && check.Status == DoctorOK {
+		check.Status = DoctorWarning
+		check.Message = fmt.Sprintf("%s uses an older configuration schema with deprecated keys", path)
+		check.Fix = fmt.Sprintf("run 'antimoji config migrate --config %s'", path)
+	}
+	return check, loaded.Unwrap(), true
+}
+
+// firstIssue returns the message of the first issue at level.
+func firstIssue(result config.ValidationResult, level config.ValidationLevel) string {
+	for _, issue := range result.Issues {
+		if issue.Level == level {
+			return fmt.Sprintf("%s: %s", issue.Field, issue.Message)
+		}
+	}
+	return ""
+}
+
+// checkProfile resolves the selected profile with its environment and --set overrides.
+func (h *DoctorHandler) checkProfile(cfg config.Config, fromFile bool, opts *DoctorOptions) DoctorCheck {
+	check := DoctorCheck{Name: "profile"}
+	name := profileOrDefault(opts.ProfileName)
+
+	profileResult := config.GetProfile(cfg, name)
+	if profileResult.IsErr() {
+		profiles := make([]string, 0, len(cfg.Profiles))
+		for profile := range cfg.Profiles {
+			profiles = append(profiles, profile)
+		}
+		sort.Strings(profiles)
+		check.Status = DoctorProblem
+		check.Message = fmt.Sprintf("profile %q does not exist", name)
+		check.Fix = fmt.Sprintf("use --profile with one of: %s", strings.Join(profiles, ", "))
+		return check
+	}
+
+	resolution, err := resolveProfile(profileResult.Unwrap(), fromFile, opts.Overrides)
+	if err != nil {
+		check.Status = DoctorProblem
+		check.Message = fmt.Sprintf("profile %q does not resolve: %v", name, err)
+		check.Fix = "fix the ANTIMOJI_* environment variables or --set flags; 'antimoji config show --effective' lists them"
+		return check
+	}
+
+	overridden := 0
+	for _, source := range resolution.Sources {
+		if source >= config.SourceEnv {
+			overridden++
+		}
+	}
+	check.Status = DoctorOK
+	check.Message = fmt.Sprintf("profile %q resolves", name)
+	if overridden > 0 {
+		check.Message += fmt.Sprintf(" with %d fields overridden by the environment or --set", overridden)
+	}
+	return check
+}
+
+// checkBinary reports which antimoji binary PATH resolves to.
+func (h *DoctorHandler) checkBinary() DoctorCheck {
+	check := DoctorCheck{Name: "binary"}
+	onPath, err := h.lookPath("antimoji")
+	if err != nil {
+		check.Status = DoctorWarning
+		check.Message = "antimoji is not on PATH; hooks with entry 'antimoji' cannot run"
+		check.Fix = installHint
+		return check
+	}
+
+	self, err := h.executable()
+	if err == nil && !samePath(self, onPath) {
+		check.Status = DoctorWarning
+		check.Message = fmt.Sprintf("PATH runs %s, not this binary (%s)", onPath, self)
+		check.Fix = "remove the other binary or put the one you want first on PATH"
+		return check
+	}
+
+	check.Status = DoctorOK
+	check.Message = fmt.Sprintf("antimoji on PATH is %s", onPath)
+	return check
+}
+
+// samePath reports whether a and b name the same file after resolving symlinks.
+func samePath(a, b string) bool {
+	resolve := func(path string) string {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		return path
+	}
+	return resolve(a) == resolve(b)
+}
+
+// checkGit reports the state of the git repository and returns its root.
+func (h *DoctorHandler) checkGit(ctx context.Context, repo *git.Repository) (DoctorCheck, string) {
+	check := DoctorCheck{Name: "git"}
+	if _, err := h.lookPath("git"); err != nil {
+		check.Status = DoctorWarning
+		check.Message = "git is not on PATH; --rev-range, --commit-messages and hooks need it"
+		check.Fix = "install git"
+		return check, ""
+	}
+
+	root, err := repo.TopLevel(ctx)
+	if err != nil {
+		check.Status = DoctorWarning
+		check.Message = "not inside a git repository; pre-commit hooks cannot run"
+		check.Fix = "run 'git init' or run antimoji doctor in the repository"
+		return check, ""
+	}
+
+	check.Status = DoctorOK
+	check.Message = fmt.Sprintf("git repository at %s", root)
+	if branch, err := repo.CurrentBranch(ctx); err == nil && branch == "" {
+		check.Message += " (detached HEAD)"
+	}
+	return check, root
+}
+
+// preCommitConfig is the part of .pre-commit-config.yaml the doctor reads.
+type preCommitConfig struct {
+	Repos []struct {
+		Repo  string `yaml:"repo"`
+		Rev   string `yaml:"rev"`
+		Hooks []struct {
+			ID    string   `yaml:"id"`
+			Entry string   `yaml:"entry"`
+			Args  []string `yaml:"args"`
+		} `yaml:"hooks"`
+	} `yaml:"repos"`
+}
+
+// antimojiHook is an antimoji hook in the pre-commit configuration.
+type antimojiHook struct {
+	id    string
+	repo  string
+	rev   string
+	words []string
+}
+
+// checkPreCommit checks the antimoji hooks of the pre-commit configuration in root.
+func (h *DoctorHandler) checkPreCommit(ctx context.Context, repo *git.Repository, root string) []DoctorCheck {
+	check := DoctorCheck{Name: "pre-commit"}
+	path := filepath.Join(root, preCommitConfigFile)
+	data, err := os.ReadFile(path) // #nosec G304 - the path is the repository's pre-commit configuration
+	if err != nil {
+		check.Status = DoctorWarning
+		check.Message = fmt.Sprintf("no %s; emojis are not checked before commits", preCommitConfigFile)
+		check.Fix = "run 'antimoji setup-lint'"
+		return []DoctorCheck{check}
+	}
+
+	var parsed preCommitConfig
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		check.Status = DoctorProblem
+		check.Message = fmt.Sprintf("%s is not valid YAML: %v", path, err)
+		check.Fix = "fix the file or regenerate it with 'antimoji setup-lint --force'"
+		return []DoctorCheck{check}
+	}
+
+	var hooks []antimojiHook
+	for _, r := range parsed.Repos {
+		for _, hook := range r.Hooks {
+			words := append(strings.Fields(hook.Entry), hook.Args...)
+			if strings.HasPrefix(hook.ID, "antimoji") || strings.Contains(r.Repo, "antimoji") {
+				hooks = append(hooks, antimojiHook{id: hook.ID, repo: r.Repo, rev: r.Rev, words: words})
+			}
+		}
+	}
+	if len(hooks) == 0 {
+		check.Status = DoctorWarning
+		check.Message = fmt.Sprintf("%s has no antimoji hooks", path)
+		check.Fix = "run 'antimoji setup-lint' to add them"
+		return []DoctorCheck{check}
+	}
+
+	checks := []DoctorCheck{h.checkHookConfigs(root, hooks), h.checkHookVersion(hooks)}
+
+	// Without a repository the git check already explains why hooks cannot run
+	if hooksDir, err := repo.HooksDir(ctx); err == nil {
+		installed := DoctorCheck{Name: "pre-commit", Status: DoctorOK, Message: "git pre-commit hook is installed"}
+		if _, err := os.Stat(filepath.Join(hooksDir, "pre-commit")); err != nil {
+			installed.Status = DoctorWarning
+			installed.Message = fmt.Sprintf("%s exists but the git pre-commit hook is not installed", preCommitConfigFile)
+			installed.Fix = "run 'pre-commit install'"
+		}
+		checks = append(checks, installed)
+	}
+	return checks
+}
+
+// checkHookConfigs checks that the configuration files antimoji hooks pass with
+// --config exist.
+func (h *DoctorHandler) checkHookConfigs(root string, hooks []antimojiHook) DoctorCheck {
+	check := DoctorCheck{Name: "pre-commit"}
+	for _, hook := range hooks {
+		for i, word := range hook.words {
+			var path string
+			switch {
+			case strings.HasPrefix(word, "--config="):
+				path = strings.TrimPrefix(word, "--config=")
+			case word == "--config" && i+1 < len(hook.words):
+				path = hook.words[i+1]
+			default:
+				continue
+			}
+			if remoteconfig.IsRemote(path) {
+				continue
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(root, path)
+			}
+			if _, err := os.Stat(path); err != nil {
+				check.Status = DoctorProblem
+				check.Message = fmt.Sprintf("hook %s uses %s, which does not exist", hook.id, path)
+				check.Fix = "run 'antimoji setup-lint --repair' or fix the hook's --config"
+				return check
+			}
+		}
+	}
+
+	check.Status = DoctorOK
+	check.Message = fmt.Sprintf("%d antimoji hooks configured", len(hooks))
+	return check
+}
+
+// checkHookVersion compares the antimoji version hooks pin, with the rev of a remote
+// repository or --require-version, with this binary.
+func (h *DoctorHandler) checkHookVersion(hooks []antimojiHook) DoctorCheck {
+	check := DoctorCheck{Name: "pre-commit", Status: DoctorOK, Message: "hooks run the antimoji on PATH"}
+	for _, hook := range hooks {
+		rev := requiredHookVersion(hook.words)
+		fix := fmt.Sprintf("install antimoji %s or regenerate the hooks with 'antimoji setup-lint --force --pin-version v%s'", rev, releaseVersion(h.version))
+		if rev == "" {
+			if hook.repo == "local" || hook.rev == "" {
+				continue
+			}
+			rev = hook.rev
+			fix = fmt.Sprintf("run 'pre-commit autoupdate --repo %s' or install the pinned version", hook.repo)
+		}
+		pinned, current := releaseVersion(rev), releaseVersion(h.version)
+		if pinned != "" && current != "" && pinned != current {
+			check.Status = DoctorWarning
+			check.Message = fmt.Sprintf("hooks pin %s but this is antimoji %s", rev, h.version)
+			check.Fix = fix
+			return check
+		}
+		check.Message = fmt.Sprintf("hooks pin %s", rev)
+	}
+	return check
+}
+
+// requiredHookVersion returns the version a hook passes with --require-version, or "".
+func requiredHookVersion(words []string) string {
+	for i, word := range words {
+		switch {
+		case strings.HasPrefix(word, "--require-version="):
+			return strings.TrimPrefix(word, "--require-version=")
+		case word == "--require-version" && i+1 < len(words):
+			return words[i+1]
+		}
+	}
+	return ""
+}
+
+// releaseVersion returns the numeric part of a version such as v1.2.3 or 1.2.3-rc1, or
+// "" when version is not a release version.
+func releaseVersion(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" || strings.Trim(version, "0123456789.") != "" {
+		return ""
+	}
+	return version
+}
+
+// RequireVersion fails with a configuration error unless current, the version of the
+// running binary, is the release required by --require-version, which hooks generated
+// with 'setup-lint --pin-version' pass.
+func RequireVersion(required, current string) error {
+	want := releaseVersion(required)
+	if want == "" {
+		return classify(ErrConfig, fmt.Errorf("invalid --require-version %q: must be a release version such as v1.2.3", required))
+	}
+	if releaseVersion(current) != want {
+		return classify(ErrConfig, fmt.Errorf("antimoji v%s is required but this is antimoji %s; install it with 'go install github.com/antimoji/antimoji/cmd/antimoji@v%s'",
+			want, current, want))
+	}
+	return nil
+}
+
+// checkCache reports on the scan result cache.
+func (h *DoctorHandler) checkCache(opts *DoctorOptions) DoctorCheck {
+	check := DoctorCheck{Name: "cache"}
+	dir := opts.CacheDir
+	if dir == "" {
+		defaultDir, err := cache.DefaultDir()
+		if err != nil {
+			check.Status = DoctorWarning
+			check.Message = fmt.Sprintf("no cache directory: %v", err)
+			check.Fix = fmt.Sprintf("set $%s or pass --cache-dir to scan", cache.EnvDir)
+			return check
+		}
+		dir = defaultDir
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		check.Status = DoctorOK
+		check.Message = fmt.Sprintf("%s does not exist yet; scan --cache creates it", dir)
+		return check
+	}
+	usage, err := cache.Inspect(dir)
+	if err == nil {
+		err = checkWritable(dir)
+	}
+	if err != nil {
+		check.Status = DoctorProblem
+		check.Message = fmt.Sprintf("cache directory %s is unusable: %v", dir, err)
+		check.Fix = fmt.Sprintf("fix the permissions of %s or set $%s", dir, cache.EnvDir)
+		return check
+	}
+
+	check.Status = DoctorOK
+	check.Message = fmt.Sprintf("%s holds %d results in %d files (%d bytes)", dir, usage.Entries, usage.Files, usage.Bytes)
+	if usage.Stale > 0 {
+		check.Status = DoctorWarning
+		check.Message += fmt.Sprintf(", %d of them stale", usage.Stale)
+		check.Fix = "run 'antimoji cache clear'"
+	}
+	return check
+}
+
+// checkWritable reports whether files can be created in dir.
+func checkWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".antimoji-doctor-*")
+	if err != nil {
+		return err
+	}
+	name := file.Name()
+	_ = file.Close()
+	return os.Remove(name)
+}
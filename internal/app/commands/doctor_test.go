@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoctorHandler_Checks(t *testing.T) {
+	newHandler := func(onPath string) *DoctorHandler {
+		h := NewDoctorHandler(logging.NewMockLogger(), quietOutput()).WithVersion("1.2.0")
+		h.lookPath = func(file string) (string, error) {
+			if file == "antimoji" && onPath == "" {
+				return "", errors.New("not found")
+			}
+			return onPath, nil
+		}
+		h.executable = func() (string, error) { return "/usr/local/bin/antimoji", nil }
+		return h
+	}
+	byName := func(checks []DoctorCheck, name string) []DoctorCheck {
+		var found []DoctorCheck
+		for _, check := range checks {
+			if check.Name == name {
+				found = append(found, check)
+			}
+		}
+		return found
+	}
+
+	t.Run("missing files are warnings", func(t *testing.T) {
+		dir := t.TempDir()
+		checks := newHandler("").Checks(context.Background(), dir, &DoctorOptions{CacheDir: filepath.Join(dir, "cache")})
+
+		assert.Equal(t, DoctorWarning, byName(checks, "config")[0].Status)
+		assert.Equal(t, DoctorOK, byName(checks, "profile")[0].Status)
+		assert.Equal(t, DoctorWarning, byName(checks, "binary")[0].Status)
+		assert.Equal(t, DoctorWarning, byName(checks, "pre-commit")[0].Status)
+		assert.Equal(t, DoctorOK, byName(checks, "cache")[0].Status)
+	})
+
+	t.Run("reports configuration problems", func(t *testing.T) {
+		dir := t.TempDir()
+		configFile := filepath.Join(dir, ".antimoji.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("profiles:\n  default:\n    max_workers: -1\n"), 0600))
+
+		checks := newHandler("/usr/local/bin/antimoji").Checks(context.Background(), dir,
+			&DoctorOptions{ConfigFile: configFile, ProfileName: "ci", CacheDir: dir})
+
+		config := byName(checks, "config")[0]
+		assert.Equal(t, DoctorProblem, config.Status)
+		assert.Contains(t, config.Fix, "antimoji config lint")
+		profile := byName(checks, "profile")[0]
+		assert.Equal(t, DoctorProblem, profile.Status)
+		assert.Contains(t, profile.Fix, "default")
+		assert.Equal(t, DoctorOK, byName(checks, "binary")[0].Status)
+	})
+
+	t.Run("reports hook problems", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, preCommitConfigFile), []byte(`repos:
+  - repo: https://github.com/jamesainslie/antimoji
+    rev: v1.1.0
+    hooks:
+      - id: antimoji-verify
+        args: [--config=missing.yaml]
+`), 0600))
+
+		checks := byName(newHandler("/opt/bin/antimoji").Checks(context.Background(), dir, &DoctorOptions{CacheDir: dir}), "pre-commit")
+		require.GreaterOrEqual(t, len(checks), 2)
+		assert.Equal(t, DoctorProblem, checks[0].Status)
+		assert.Contains(t, checks[0].Message, "missing.yaml")
+		assert.Equal(t, DoctorWarning, checks[1].Status)
+		assert.Contains(t, checks[1].Fix, "pre-commit autoupdate")
+	})
+
+	t.Run("reports hooks requiring another version", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, preCommitConfigFile), []byte(`repos:
+  - repo: local
+    hooks:
+      - id: antimoji-check
+        entry: antimoji --require-version=v1.1.0
+        args: [check, --fix]
+`), 0600))
+
+		checks := byName(newHandler("/opt/bin/antimoji").Checks(context.Background(), dir, &DoctorOptions{CacheDir: dir}), "pre-commit")
+		require.GreaterOrEqual(t, len(checks), 2)
+		assert.Equal(t, DoctorWarning, checks[1].Status)
+		assert.Equal(t, "hooks pin v1.1.0 but this is antimoji 1.2.0", checks[1].Message)
+		assert.Contains(t, checks[1].Fix, "--pin-version v1.2.0")
+	})
+
+	t.Run("reports stale cache files", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0000000000000000000000000000000000000000000000000000000000000000.json"), []byte("{"), 0600))
+
+		cache := byName(newHandler("").Checks(context.Background(), t.TempDir(), &DoctorOptions{CacheDir: dir}), "cache")[0]
+		assert.Equal(t, DoctorWarning, cache.Status)
+		assert.Contains(t, cache.Fix, "antimoji cache clear")
+	})
+}
+
+func TestDoctorHandler_Execute(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+	h := NewDoctorHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+
+	err := h.Execute(context.Background(), dir, &DoctorOptions{ProfileName: "missing", CacheDir: dir})
+	require.Error(t, err)
+	assert.Equal(t, ErrConfig, Classify(err))
+	assert.Contains(t, out.String(), `fail  profile     profile "missing" does not exist`)
+	assert.Contains(t, out.String(), "fix: use --profile with one of: default")
+}
+
+func TestReleaseVersion(t *testing.T) {
+	assert.Equal(t, "0.9.16", releaseVersion("v0.9.16"))
+	assert.Equal(t, "0.9.16", releaseVersion("0.9.16-refactor"))
+	assert.Equal(t, "", releaseVersion("dev"))
+	assert.Equal(t, "", releaseVersion("main"))
+}
+
+func TestRequireVersion(t *testing.T) {
+	assert.NoError(t, RequireVersion("v0.9.16", "0.9.16-refactor"))
+
+	err := RequireVersion("v1.0.0", "0.9.16")
+	assert.ErrorIs(t, err, ErrConfig)
+	assert.ErrorContains(t, err, "antimoji v1.0.0 is required but this is antimoji 0.9.16")
+	assert.ErrorContains(t, err, "cmd/antimoji@v1.0.0")
+
+	assert.ErrorIs(t, RequireVersion("latest", "0.9.16"), ErrConfig)
+}
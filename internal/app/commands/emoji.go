@@ -0,0 +1,466 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/core/shortcode"
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// EmojiOptions holds the options for the emoji commands.
+type EmojiOptions struct {
+	Format   string
+	Category string
+
+	IgnoreAllowlist bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+}
+
+// EmojiHandler handles the emoji reference commands with dependency injection.
+type EmojiHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewEmojiHandler creates a new emoji command handler.
+func NewEmojiHandler(logger logging.Logger, ui ui.UserOutput) *EmojiHandler {
+	return &EmojiHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the listings (defaults to stdout).
+func (h *EmojiHandler) WithOutput(out io.Writer) *EmojiHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the emoji cobra command and its subcommands.
+func (h *EmojiHandler) CreateCommand() *cobra.Command {
+	opts := &EmojiOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "emoji",
+		Short: "Inspect the built-in emoji database",
+		Long: `Inspect the emojis and shortcodes antimoji knows about.
+
+Lists the categories of the built-in shortcode table, looks emojis up by name
+or by the emoji itself, showing their codepoints and aliases, and tests
+whether text would be detected under a profile. Useful when writing
+allowlists.
+
+Unicode emojis are detected with a dataset generated from the emoji-test.txt
+file of a Unicode release. A newer dataset can be used without upgrading by
+setting emoji_dataset in the profile; db-version shows which one is in use.
+
+Examples:
+  antimoji emoji list                        # Categories and their sizes
+  antimoji emoji list --category Flags       # Emojis of one category
+  antimoji emoji lookup rocket               # Search shortcodes by name
+  antimoji emoji lookup 🚀                   # Codepoints and aliases of an emoji
+  antimoji emoji test "🚀" --profile ci      # Would the ci profile report it?
+  antimoji emoji db-version                  # Unicode version of the emoji dataset`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.Format, "format", "text", "output format (text, json)")
+
+	list := &cobra.Command{
+		Use:           "list",
+		Short:         "List the emoji categories, or the emojis of one",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return h.ExecuteList(cmd.Context(), opts)
+		},
+	}
+	list.Flags().StringVar(&opts.Category, "category", "", "list the emojis of this category")
+	cmd.AddCommand(list)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:           "lookup <name|emoji>",
+		Short:         "Show the codepoints and shortcodes of matching emojis",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return h.ExecuteLookup(cmd.Context(), args[0], opts)
+		},
+	})
+
+	test := &cobra.Command{
+		Use:           "test <text>",
+		Short:         "Test whether text would be detected under a profile",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.ExecuteTest(cmd.Context(), args[0], opts)
+		},
+	}
+	addAllowlistFlags(test.Flags(), &opts.IgnoreAllowlist)
+	cmd.AddCommand(test)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:           "db-version",
+		Short:         "Show the version of the emoji dataset",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.ExecuteDBVersion(cmd.Context(), opts)
+		},
+	})
+
+	return cmd
+}
+
+// EmojiCategory is one category of the built-in emoji database.
+type EmojiCategory struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// EmojiInfo describes one emoji of the built-in database.
+type EmojiInfo struct {
+	shortcode.Entry
+
+	// Codepoints are the Unicode codepoints of the emoji, such as U+1F680
+	Codepoints []string `json:"codepoints"`
+}
+
+// EmojiTestReport is the output of the emoji test command.
+type EmojiTestReport struct {
+	Profile  string         `json:"profile"`
+	Text     string         `json:"text"`
+	Detected bool           `json:"detected"`
+	Matches  []EmojiTestHit `json:"matches"`
+}
+
+// EmojiDatasetReport is the output of the emoji db-version command.
+type EmojiDatasetReport struct {
+	Version string `json:"version"`
+	Date    string `json:"date,omitempty"`
+	// Source is the dataset file, or "embedded" for the dataset compiled into the binary
+	Source          string `json:"source"`
+	Emojis          int    `json:"emojis,omitempty"`
+	Ranges          int    `json:"ranges"`
+	EmbeddedVersion string `json:"embedded_version"`
+}
+
+// EmojiTestHit is one emoji detected in the tested text.
+type EmojiTestHit struct {
+	Emoji      string              `json:"emoji"`
+	Category   types.EmojiCategory `json:"category"`
+	Codepoints []string            `json:"codepoints"`
+	Shortcode  string              `json:"shortcode,omitempty"`
+	Allowed    bool                `json:"allowed"`
+}
+
+// ExecuteList lists the categories of the emoji database, or the emojis of one.
+func (h *EmojiHandler) ExecuteList(parentCtx context.Context, opts *EmojiOptions) error {
+	format, err := emojiFormat(opts)
+	if err != nil {
+		return err
+	}
+	ctx := h.context(parentCtx, "emoji-list")
+
+	entries := shortcode.Entries()
+	h.logger.Debug(ctx, "Listing emojis", "category", opts.Category)
+	if opts.Category == "" {
+		categories := make([]EmojiCategory, 0, len(shortcode.Categories()))
+		for _, name := range shortcode.Categories() {
+			category := EmojiCategory{Name: name}
+			for _, entry := range entries {
+				if entry.Category == name {
+					category.Count++
+				}
+			}
+			categories = append(categories, category)
+		}
+		if format == "json" {
+			return h.writeJSON(categories)
+		}
+		tw := tabwriter.NewWriter(h.output(), 0, 4, 2, ' ', 0)
+		for _, category := range categories {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\n", category.Name, category.Count)
+		}
+		return tw.Flush()
+	}
+
+	var infos []EmojiInfo
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Category, opts.Category) {
+			infos = append(infos, emojiInfo(entry))
+		}
+	}
+	if infos == nil {
+		return classify(ErrConfig, fmt.Errorf("unknown emoji category %q; categories: %s",
+			opts.Category, strings.Join(shortcode.Categories(), ", ")))
+	}
+	return h.writeInfos(format, infos)
+}
+
+// ExecuteLookup shows the emojis whose shortcodes contain query, or the emoji query is.
+func (h *EmojiHandler) ExecuteLookup(parentCtx context.Context, query string, opts *EmojiOptions) error {
+	format, err := emojiFormat(opts)
+	if err != nil {
+		return err
+	}
+	ctx := h.context(parentCtx, "emoji-lookup")
+
+	infos := make([]EmojiInfo, 0)
+	for _, entry := range shortcode.Search(query) {
+		infos = append(infos, emojiInfo(entry))
+	}
+	if len(infos) == 0 && !isASCII(query) {
+		// An emoji without a shortcode still has codepoints worth showing
+		infos = append(infos, emojiInfo(shortcode.Entry{Emoji: strings.TrimSpace(query)}))
+	}
+	h.logger.Debug(ctx, "Emoji lookup", "query", query, "matches", len(infos))
+	if len(infos) == 0 {
+		return fmt.Errorf("no emoji matches %q", query)
+	}
+	return h.writeInfos(format, infos)
+}
+
+// ExecuteTest detects the emojis in text under the resolved profile and reports
+// whether each would count against it.
+func (h *EmojiHandler) ExecuteTest(parentCtx context.Context, text string, opts *EmojiOptions) error {
+	format, err := emojiFormat(opts)
+	if err != nil {
+		return err
+	}
+	ctx := h.context(parentCtx, "emoji-test")
+
+	profile, err := h.profile(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	engine, err := policy.New(ctx, profile, policy.Options{
+		Operation:       "emoji-test",
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       policy.NoThreshold,
+	})
+	if err != nil {
+		return err
+	}
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return err
+	}
+	detection := processor.DetectContent([]byte(text), patterns, engine.ProcessingConfig())
+	if detection.IsErr() {
+		return detection.Error()
+	}
+
+	report := EmojiTestReport{
+		Profile: profileOrDefault(opts.ProfileName),
+		Text:    text,
+		Matches: make([]EmojiTestHit, 0),
+	}
+	for _, match := range detection.Unwrap().Emojis {
+		hit := EmojiTestHit{
+			Emoji:      match.Emoji,
+			Category:   match.Category,
+			Codepoints: codepoints(match.Emoji),
+			Allowed:    !engine.IsViolation(match.Emoji),
+		}
+		hit.Shortcode, _ = shortcode.For(match.Emoji)
+		report.Matches = append(report.Matches, hit)
+		report.Detected = report.Detected || !hit.Allowed
+	}
+	h.logger.Debug(ctx, "Emoji text tested", "matches", len(report.Matches), "detected", report.Detected)
+
+	if format == "json" {
+		return h.writeJSON(report)
+	}
+	out := h.output()
+	if len(report.Matches) == 0 {
+		_, _ = fmt.Fprintf(out, "No emojis detected under profile %s\n", report.Profile)
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Profile: %s\n", report.Profile)
+	for _, hit := range report.Matches {
+		verdict := "violation"
+		if hit.Allowed {
+			verdict = "allowed"
+		}
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", hit.Emoji, strings.Join(hit.Codepoints, " "), hit.Category, hit.Shortcode, verdict)
+	}
+	return tw.Flush()
+}
+
+// ExecuteDBVersion shows the version of the emoji dataset the profile detects with
+// and of the dataset embedded in the binary.
+func (h *EmojiHandler) ExecuteDBVersion(parentCtx context.Context, opts *EmojiOptions) error {
+	format, err := emojiFormat(opts)
+	if err != nil {
+		return err
+	}
+	ctx := h.context(parentCtx, "emoji-db-version")
+
+	profile, err := h.profile(ctx, opts)
+	if err != nil {
+		return err
+	}
+	result := emojidata.Dataset(ctx, profile)
+	if result.IsErr() {
+		return classify(ErrConfig, result.Error())
+	}
+	dataset := result.Unwrap()
+	report := EmojiDatasetReport{
+		Version:         dataset.Version,
+		Date:            dataset.Date,
+		Source:          dataset.Source,
+		Emojis:          dataset.Emojis,
+		Ranges:          len(dataset.Ranges),
+		EmbeddedVersion: emojidata.Embedded().Version,
+	}
+	h.logger.Debug(ctx, "Emoji dataset loaded", "source", report.Source, "version", report.Version)
+
+	if format == "json" {
+		return h.writeJSON(report)
+	}
+	tw := tabwriter.NewWriter(h.output(), 0, 4, 2, ' ', 0)
+	version := report.Version
+	if report.Date != "" {
+		version += " (" + report.Date + ")"
+	}
+	_, _ = fmt.Fprintf(tw, "Emoji dataset:\t%s\n", version)
+	_, _ = fmt.Fprintf(tw, "Source:\t%s\n", report.Source)
+	if report.Emojis > 0 {
+		_, _ = fmt.Fprintf(tw, "Emojis:\t%d\n", report.Emojis)
+	}
+	_, _ = fmt.Fprintf(tw, "Ranges:\t%d\n", report.Ranges)
+	if report.Source != emojidata.EmbeddedSource {
+		_, _ = fmt.Fprintf(tw, "Embedded dataset:\t%s\n", report.EmbeddedVersion)
+	}
+	return tw.Flush()
+}
+
+// profile loads and resolves the profile named in opts.
+func (h *EmojiHandler) profile(ctx context.Context, opts *EmojiOptions) (config.Profile, error) {
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return config.Profile{}, fmt.Errorf("failed to load config: %w", configResult.Error())
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return config.Profile{}, classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", profileOrDefault(opts.ProfileName), profileResult.Error()))
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return config.Profile{}, err
+	}
+	return resolution.Profile, nil
+}
+
+// context adds the operation and component of an emoji subcommand to parentCtx.
+func (h *EmojiHandler) context(parentCtx context.Context, operation string) context.Context {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, operation)
+	return ctxutil.WithComponent(ctx, "cli")
+}
+
+func (h *EmojiHandler) output() io.Writer {
+	if h.out == nil {
+		return os.Stdout
+	}
+	return h.out
+}
+
+func (h *EmojiHandler) writeJSON(v any) error {
+	encoder := json.NewEncoder(h.output())
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
+}
+
+// writeInfos writes emojis with their codepoints and shortcodes.
+func (h *EmojiHandler) writeInfos(format string, infos []EmojiInfo) error {
+	if format == "json" {
+		return h.writeJSON(infos)
+	}
+	tw := tabwriter.NewWriter(h.output(), 0, 4, 2, ' ', 0)
+	for _, info := range infos {
+		names := make([]string, 0, 1+len(info.Aliases))
+		if info.Shortcode != "" {
+			for _, name := range info.Names() {
+				names = append(names, ":"+name+":")
+			}
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Emoji, strings.Join(info.Codepoints, " "), strings.Join(names, " "), info.Category)
+	}
+	return tw.Flush()
+}
+
+// emojiFormat validates the output format of the emoji commands.
+func emojiFormat(opts *EmojiOptions) (string, error) {
+	format := strings.ToLower(opts.Format)
+	if format != "text" && format != "json" {
+		return "", fmt.Errorf("unsupported format %q; supported: text, json", opts.Format)
+	}
+	return format, nil
+}
+
+func emojiInfo(entry shortcode.Entry) EmojiInfo {
+	return EmojiInfo{Entry: entry, Codepoints: codepoints(entry.Emoji)}
+}
+
+// codepoints returns the codepoints of s in U+XXXX notation.
+func codepoints(s string) []string {
+	points := make([]string, 0, len(s))
+	for _, r := range s {
+		points = append(points, fmt.Sprintf("U+%04X", r))
+	}
+	return points
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > 0x7F {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmojiHandler_List(t *testing.T) {
+	var out bytes.Buffer
+	handler := NewEmojiHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+
+	require.NoError(t, handler.ExecuteList(context.Background(), &EmojiOptions{Format: "json"}))
+	var categories []EmojiCategory
+	require.NoError(t, json.Unmarshal(out.Bytes(), &categories))
+	require.NotEmpty(t, categories)
+	assert.Equal(t, "Smileys", categories[0].Name)
+	for _, category := range categories {
+		assert.Positive(t, category.Count, category.Name)
+	}
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteList(context.Background(), &EmojiOptions{Format: "text", Category: "flags"}))
+	assert.Contains(t, out.String(), "🏁")
+	assert.Contains(t, out.String(), ":checkered_flag:")
+	assert.NotContains(t, out.String(), "🚀")
+
+	err := handler.ExecuteList(context.Background(), &EmojiOptions{Format: "text", Category: "Nope"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrConfig)
+	assert.Contains(t, err.Error(), "Smileys")
+}
+
+func TestEmojiHandler_Lookup(t *testing.T) {
+	run := func(t *testing.T, query, format string) string {
+		var out bytes.Buffer
+		handler := NewEmojiHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+		require.NoError(t, handler.ExecuteLookup(context.Background(), query, &EmojiOptions{Format: format}))
+		return out.String()
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		var infos []EmojiInfo
+		require.NoError(t, json.Unmarshal([]byte(run(t, "rocket", "json")), &infos))
+		require.NotEmpty(t, infos)
+		assert.Equal(t, "🚀", infos[0].Emoji)
+		assert.Equal(t, []string{"U+1F680"}, infos[0].Codepoints)
+	})
+
+	t.Run("by emoji", func(t *testing.T) {
+		out := run(t, "👍", "text")
+		assert.Contains(t, out, "U+1F44D")
+		assert.Contains(t, out, ":+1: :thumbsup:")
+	})
+
+	t.Run("emoji without a shortcode", func(t *testing.T) {
+		out := run(t, "🧑‍💻", "text")
+		assert.Contains(t, out, "U+1F9D1 U+200D U+1F4BB")
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		handler := NewEmojiHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&bytes.Buffer{})
+		err := handler.ExecuteLookup(context.Background(), "no_such_emoji_name", &EmojiOptions{Format: "text"})
+		assert.ErrorContains(t, err, "no emoji matches")
+	})
+}
+
+func TestEmojiHandler_Test(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`profiles:
+  default:
+    unicode_emojis: true
+    text_emoticons: true
+  ci:
+    unicode_emojis: true
+    emoji_allowlist: ["✅"]
+`), 0600))
+
+	run := func(t *testing.T, text string, opts *EmojiOptions) EmojiTestReport {
+		var out bytes.Buffer
+		handler := NewEmojiHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+		opts.Format = "json"
+		opts.ConfigFile = configFile
+		require.NoError(t, handler.ExecuteTest(context.Background(), text, opts))
+		var report EmojiTestReport
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		return report
+	}
+
+	report := run(t, "ship it 🚀 ✅", &EmojiOptions{ProfileName: "ci"})
+	assert.Equal(t, "ci", report.Profile)
+	assert.True(t, report.Detected)
+	assert.Equal(t, []EmojiTestHit{
+		{Emoji: "🚀", Category: "unicode", Codepoints: []string{"U+1F680"}, Shortcode: ":rocket:"},
+		{Emoji: "✅", Category: "unicode", Codepoints: []string{"U+2705"}, Shortcode: ":white_check_mark:", Allowed: true},
+	}, report.Matches)
+
+	report = run(t, "✅", &EmojiOptions{ProfileName: "ci"})
+	assert.False(t, report.Detected, "an allowlisted emoji is not reported")
+
+	report = run(t, "✅", &EmojiOptions{ProfileName: "ci", IgnoreAllowlist: true})
+	assert.True(t, report.Detected)
+
+	report = run(t, "🚀 :)", &EmojiOptions{Overrides: []string{"unicode_emojis=false"}})
+	require.Len(t, report.Matches, 1, "--set applies to the tested profile")
+	assert.Equal(t, ":)", report.Matches[0].Emoji)
+	assert.Equal(t, "default", report.Profile)
+}
+
+func TestEmojiHandler_DBVersion(t *testing.T) {
+	dir := t.TempDir()
+	dataset := filepath.Join(dir, "dataset.yaml")
+	require.NoError(t, os.WriteFile(dataset, []byte(`version: "99.0"
+date: "2099-01-01"
+emojis: 1
+ranges:
+  - start: U+1FAE9
+`), 0600))
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`profiles:
+  default:
+    unicode_emojis: true
+  future:
+    unicode_emojis: true
+    emoji_dataset: `+dataset+`
+`), 0600))
+
+	run := func(t *testing.T, opts *EmojiOptions) string {
+		var out bytes.Buffer
+		handler := NewEmojiHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+		opts.ConfigFile = configFile
+		require.NoError(t, handler.ExecuteDBVersion(context.Background(), opts))
+		return out.String()
+	}
+
+	var report EmojiDatasetReport
+	require.NoError(t, json.Unmarshal([]byte(run(t, &EmojiOptions{Format: "json"})), &report))
+	assert.Equal(t, "embedded", report.Source)
+	assert.Equal(t, report.EmbeddedVersion, report.Version)
+	assert.Positive(t, report.Ranges)
+
+	out := run(t, &EmojiOptions{Format: "text", ProfileName: "future"})
+	assert.Contains(t, out, "99.0 (2099-01-01)")
+	assert.Contains(t, out, dataset)
+	assert.Contains(t, out, "Embedded dataset:")
+
+	handler := NewEmojiHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&bytes.Buffer{})
+	err := handler.ExecuteDBVersion(context.Background(), &EmojiOptions{
+		Format: "text", Overrides: []string{"emoji_dataset=" + filepath.Join(dir, "missing.yaml")},
+	})
+	assert.ErrorIs(t, err, ErrConfig)
+}
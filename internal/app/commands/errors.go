@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Error classes of the exit code contract. Every error a command returns belongs to
+// one of them; Classify tells which.
+var (
+	// ErrViolations means the command ran and found emojis the policy does not allow.
+	ErrViolations = errors.New("violations found")
+	// ErrConfig means the configuration or the command line is invalid.
+	ErrConfig = errors.New("configuration error")
+	// ErrIO means reading or writing failed, so nothing could be checked.
+	ErrIO = errors.New("I/O error")
+	// ErrPartialFailure means some files were processed but others failed.
+	ErrPartialFailure = errors.New("partial failure")
+)
+
+// classes names the error classes for transport, e.g. from the daemon to its clients.
+var classes = map[string]error{
+	"violations":      ErrViolations,
+	"config":          ErrConfig,
+	"io":              ErrIO,
+	"partial_failure": ErrPartialFailure,
+}
+
+// classifiedError places err in an error class without changing its message.
+type classifiedError struct {
+	err   error
+	class error
+}
+
+func (e classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e classifiedError) Unwrap() []error {
+	return []error{e.err, e.class}
+}
+
+// classify returns err in class, or nil when err is nil.
+func classify(class, err error) error {
+	if err == nil {
+		return nil
+	}
+	return classifiedError{err: err, class: class}
+}
+
+// Classify returns the class of err: ErrPartialFailure, ErrIO, ErrConfig or
+// ErrViolations, or nil for a nil error. Errors not classified where they arise are
+// I/O errors when they come from the file system and configuration or usage errors
+// otherwise.
+func Classify(err error) error {
+	var pathErr *fs.PathError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrPartialFailure):
+		return ErrPartialFailure
+	case errors.Is(err, ErrIO):
+		return ErrIO
+	case errors.Is(err, ErrConfig):
+		return ErrConfig
+	case errors.Is(err, ErrViolations), errors.Is(err, policy.ErrThresholdExceeded), errors.Is(err, ErrCleanCheckFailed):
+		return ErrViolations
+	case errors.As(err, &pathErr):
+		return ErrIO
+	default:
+		return ErrConfig
+	}
+}
+
+// className returns the transport name of the class of err.
+func className(err error) string {
+	class := Classify(err)
+	for name, c := range classes {
+		if c == class {
+			return name
+		}
+	}
+	return ""
+}
+
+// isFileFailure reports whether a per-file error means the file could not be
+// processed, as opposed to being skipped by policy.
+func isFileFailure(err error) bool {
+	return err != nil && !errors.Is(err, processor.ErrFileTooLarge)
+}
+
+// countFileFailures returns the number of results whose file could not be processed.
+func countFileFailures(results []types.ProcessResult) int {
+	failed := 0
+	for _, result := range results {
+		if isFileFailure(result.Error) {
+			failed++
+		}
+	}
+	return failed
+}
+
+// countModifyFailures returns the number of modify results whose file could not be
+// processed.
+func countModifyFailures(results []processor.ModifyResult) int {
+	failed := 0
+	for _, result := range results {
+		if isFileFailure(result.Error) {
+			failed++
+		}
+	}
+	return failed
+}
+
+// fileFailureError returns the error for failed of total files not being processed:
+// ErrIO when none was, ErrPartialFailure when some were, nil when none failed. A
+// violation found in the other files stays part of the error.
+func fileFailureError(failed, total int, violation error) error {
+	if failed == 0 {
+		return nil
+	}
+	class := ErrPartialFailure
+	if failed == total {
+		class = ErrIO
+	}
+	err := fmt.Errorf("%d of %d files could not be processed", failed, total)
+	if violation != nil {
+		err = fmt.Errorf("%w; %w", err, violation)
+	}
+	return classify(class, err)
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	_, pathErr := os.Open("does-not-exist")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"threshold", fmt.Errorf("scan: %w", policy.ErrThresholdExceeded), ErrViolations},
+		{"clean check", fmt.Errorf("%w: 1 files would be cleaned", ErrCleanCheckFailed), ErrViolations},
+		{"classified config", classify(ErrConfig, pathErr), ErrConfig},
+		{"file system", fmt.Errorf("failed: %w", pathErr), ErrIO},
+		{"usage", errors.New("unknown flag: --bogus"), ErrConfig},
+		{"partial failure with violations", fileFailureError(1, 2, policy.ErrThresholdExceeded), ErrPartialFailure},
+		{"daemon", daemonError{message: "failed", class: classes[className(ErrIO)]}, ErrIO},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.err))
+		})
+	}
+}
+
+func TestFileFailureError(t *testing.T) {
+	assert.NoError(t, fileFailureError(0, 3, nil))
+	assert.Equal(t, ErrIO, Classify(fileFailureError(3, 3, nil)))
+
+	err := fileFailureError(1, 3, policy.ErrThresholdExceeded)
+	assert.Equal(t, ErrPartialFailure, Classify(err))
+	assert.ErrorIs(t, err, policy.ErrThresholdExceeded)
+	assert.Equal(t, "1 of 3 files could not be processed; emoji threshold exceeded", err.Error())
+}
+
+func TestIsFileFailure(t *testing.T) {
+	assert.False(t, isFileFailure(nil))
+	assert.False(t, isFileFailure(processor.ErrFileTooLarge))
+	assert.True(t, isFileFailure(errors.New("permission denied")))
+}
@@ -0,0 +1,327 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/infra/fs"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// explainFields are the profile fields that decide file selection and detection.
+var explainFields = []string{
+	"include_patterns",
+	"exclude_patterns",
+	"file_ignore_list",
+	"directory_ignore_list",
+	"respect_gitignore",
+	"max_file_size",
+	"binary_sample_size",
+	"binary_null_ratio",
+	"binary_control_ratio",
+	"unicode_emojis",
+	"text_emoticons",
+	"custom_patterns",
+	"shortcode_policy",
+	"kaomoji",
+	"decorative_symbols",
+	"escaped_emojis",
+	"evasion_detection",
+	"unicode_hygiene",
+	"emoji_dataset",
+	"emoji_allowlist",
+}
+
+// ExplainOptions holds the options for the explain command.
+type ExplainOptions struct {
+	Format          string
+	IncludePattern  string
+	ExcludePattern  string
+	IgnoreAllowlist bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+}
+
+// ExplainHandler handles the explain command with dependency injection.
+type ExplainHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewExplainHandler creates a new explain command handler.
+func NewExplainHandler(logger logging.Logger, ui ui.UserOutput) *ExplainHandler {
+	return &ExplainHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the explanation (defaults to stdout).
+func (h *ExplainHandler) WithOutput(out io.Writer) *ExplainHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the explain cobra command.
+func (h *ExplainHandler) CreateCommand() *cobra.Command {
+	opts := &ExplainOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "explain [flags] <path...>",
+		Short: "Explain how antimoji treats a file",
+		Long: `Explain the decisions scan and clean make for each file.
+
+Prints the include, exclude and ignore rules checked in order and which one
+decided, the profile settings involved and where they came from, whether the
+file would be scanned, and which of its emojis the allowlist permits.
+
+A file named on the command line is only subject to the include and exclude
+rules; when it is found by scanning a directory, git ignore rules and excluded
+parent directories apply as well.
+
+Examples:
+  antimoji explain src/main.go                   # Decision chain as text
+  antimoji explain --profile ci docs/README.md   # Explain for another profile
+  antimoji explain --format json src/main.go     # Machine-readable output`,
+		Args:          cobra.MinimumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.Execute(cmd.Context(), args, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "output format (text, json)")
+	cmd.Flags().StringVar(&opts.IncludePattern, "include", "", "include pattern, as for scan and clean")
+	cmd.Flags().StringVar(&opts.ExcludePattern, "exclude", "", "exclude pattern, as for scan and clean")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+
+	return cmd
+}
+
+// ExplainReport is the output of the explain command.
+type ExplainReport struct {
+	Profile    string            `json:"profile"`
+	ConfigFile string            `json:"config_file,omitempty"`
+	Settings   []fieldReport     `json:"settings"`
+	Files      []FileExplanation `json:"files"`
+}
+
+// FileExplanation explains the treatment of one file.
+type FileExplanation struct {
+	filtering.Explanation
+
+	// Scanned reports whether a scan of a directory containing the file checks it
+	Scanned bool `json:"scanned"`
+	// Reason explains why the file is not scanned
+	Reason   string `json:"reason,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	// Emojis are the emojis detected in the file, whether or not it is scanned
+	Emojis []ExplainedEmoji `json:"emojis"`
+}
+
+// ExplainedEmoji is one distinct emoji detected in a file.
+type ExplainedEmoji struct {
+	Emoji    string              `json:"emoji"`
+	Category types.EmojiCategory `json:"category"`
+	Count    int                 `json:"count"`
+	Allowed  bool                `json:"allowed"`
+}
+
+// Execute runs the explain command logic with dependency injection.
+func (h *ExplainHandler) Execute(parentCtx context.Context, args []string, opts *ExplainOptions) error {
+	format := strings.ToLower(opts.Format)
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unsupported format %q; supported: text, json", opts.Format)
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "explain")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	if opts.ConfigFile != "" && opts.StrictConfig {
+		if configResult := loadConfigFile(opts.ConfigFile, true); configResult.IsErr() {
+			return fmt.Errorf("failed to load config: %w", configResult.Error())
+		}
+	}
+
+	// Resolved like 'config show --effective' so each setting names its own source
+	flagOverrides, err := config.ParseSetFlags(opts.Overrides)
+	if err != nil {
+		return err
+	}
+	resolved := config.LoadResolution(opts.ConfigFile, opts.ProfileName, config.EnvOverrides(os.Environ()), flagOverrides)
+	if resolved.IsErr() {
+		return fmt.Errorf("failed to resolve profile '%s': %w", profileOrDefault(opts.ProfileName), resolved.Error())
+	}
+	resolution, err := enforceOrgPolicy(resolved.Unwrap(), os.Environ())
+	if err != nil {
+		return err
+	}
+
+	engine, err := policy.New(ctx, resolution.Profile, policy.Options{
+		Operation:       "explain",
+		Recursive:       true,
+		IncludePattern:  opts.IncludePattern,
+		ExcludePattern:  opts.ExcludePattern,
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       policy.NoThreshold,
+	})
+	if err != nil {
+		return err
+	}
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := ExplainReport{
+		Profile:    profileOrDefault(opts.ProfileName),
+		ConfigFile: opts.ConfigFile,
+		Settings:   make([]fieldReport, 0, len(explainFields)),
+		Files:      make([]FileExplanation, 0, len(args)),
+	}
+	for _, key := range explainFields {
+		value, _ := config.FieldValue(resolution.Profile, key)
+		report.Settings = append(report.Settings, fieldReport{Key: key, Value: value, Source: resolution.Sources[key].String()})
+	}
+	for _, path := range args {
+		explanation := explainFile(engine, patterns, path)
+		h.logger.Debug(ctx, "File explained", "path", path, "scanned", explanation.Scanned, "rule", explanation.Walk.Rule)
+		report.Files = append(report.Files, explanation)
+	}
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(report)
+	}
+	return writeExplanation(out, report)
+}
+
+// explainFile follows the checks of a scan for path and detects its emojis.
+func explainFile(engine *policy.Engine, patterns types.EmojiPatterns, path string) FileExplanation {
+	explanation := FileExplanation{Explanation: engine.Explain(path), Emojis: []ExplainedEmoji{}}
+	// The first check a scan fails is the reason it skips the file
+	skip := func(reason string) FileExplanation {
+		if explanation.Reason == "" {
+			explanation.Reason = reason
+		}
+		return explanation
+	}
+	if !explanation.Walk.Include {
+		skip("excluded: " + explanation.Walk.Reason)
+	}
+
+	if info, err := os.Stat(path); err != nil {
+		return skip(err.Error())
+	} else if info.IsDir() {
+		return skip("is a directory")
+	}
+
+	result := processor.ProcessFile(path, patterns, engine.ProcessingConfig()).Unwrap()
+	switch {
+	case result.Error != nil:
+		return skip(result.Error.Error())
+	case result.BinaryReason != "":
+		return skip("binary file: " + result.BinaryReason)
+	}
+	explanation.Encoding = result.Encoding
+	if explanation.Encoding == "" {
+		explanation.Encoding = string(fs.EncodingUTF8)
+	}
+	explanation.Scanned = explanation.Walk.Include
+
+	index := make(map[string]int)
+	for _, match := range result.DetectionResult.Emojis {
+		i, seen := index[match.Emoji]
+		if !seen {
+			i = len(explanation.Emojis)
+			index[match.Emoji] = i
+			explanation.Emojis = append(explanation.Emojis, ExplainedEmoji{
+				Emoji:    match.Emoji,
+				Category: match.Category,
+				Allowed:  !engine.IsViolation(match.Emoji),
+			})
+		}
+		explanation.Emojis[i].Count++
+	}
+	return explanation
+}
+
+// writeExplanation writes the report as text.
+func writeExplanation(out io.Writer, report ExplainReport) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Profile: %s\n", report.Profile)
+	if report.ConfigFile != "" {
+		_, _ = fmt.Fprintf(tw, "Config:  %s\n", report.ConfigFile)
+	}
+	_, _ = fmt.Fprintln(tw, "\nSettings:")
+	for _, field := range report.Settings {
+		_, _ = fmt.Fprintf(tw, "  %s:\t%s\t(%s)\n", field.Key, formatFieldValue(field.Value), field.Source)
+	}
+
+	for _, file := range report.Files {
+		_, _ = fmt.Fprintf(tw, "\n%s\n", file.Path)
+		_, _ = fmt.Fprintln(tw, "  Rules:")
+		for _, check := range file.Checks {
+			result := "no match"
+			if check.Matched {
+				result = "matched " + check.Pattern
+			}
+			_, _ = fmt.Fprintf(tw, "    %s\t%s\n", check.Rule, result)
+		}
+		_, _ = fmt.Fprintf(tw, "  Named on the command line:\t%s\n", file.Decision)
+		_, _ = fmt.Fprintf(tw, "  Found in a directory:\t%s\n", file.Walk)
+
+		scanned := "yes"
+		if !file.Scanned {
+			scanned = "no (" + file.Reason + ")"
+		} else if file.Encoding != string(fs.EncodingUTF8) {
+			scanned = "yes (" + file.Encoding + ")"
+		}
+		_, _ = fmt.Fprintf(tw, "  Scanned:\t%s\n", scanned)
+
+		if len(file.Emojis) == 0 {
+			_, _ = fmt.Fprintln(tw, "  Emojis:\tnone")
+			continue
+		}
+		_, _ = fmt.Fprintln(tw, "  Emojis:")
+		for _, emoji := range file.Emojis {
+			verdict := "violation"
+			if emoji.Allowed {
+				verdict = "allowed"
+			}
+			_, _ = fmt.Fprintf(tw, "    %s\t%d\t%s\t%s\n", emoji.Emoji, emoji.Count, emoji.Category, verdict)
+		}
+	}
+	return tw.Flush()
+}
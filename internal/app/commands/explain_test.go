@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainHandler_Execute(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(source, []byte("// 🚀 ✅ ✅\n"), 0600))
+	minified := filepath.Join(dir, "app.min.js")
+	require.NoError(t, os.WriteFile(minified, []byte("// 🚀\n"), 0600))
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`profiles:
+  default:
+    unicode_emojis: true
+    emoji_allowlist: ["✅"]
+    file_ignore_list: ["*.min.js"]
+`), 0600))
+
+	run := func(t *testing.T, opts *ExplainOptions, paths ...string) string {
+		var out bytes.Buffer
+		handler := NewExplainHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+		require.NoError(t, handler.Execute(context.Background(), paths, opts))
+		return out.String()
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out := run(t, &ExplainOptions{Format: "json", ConfigFile: configFile}, source, minified)
+
+		var report ExplainReport
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		require.Len(t, report.Files, 2)
+		assert.Contains(t, report.Settings, fieldReport{Key: "emoji_allowlist", Value: []interface{}{"✅"}, Source: "file"})
+		assert.Contains(t, report.Settings, fieldReport{Key: "max_file_size", Value: float64(104857600), Source: "default"})
+
+		scanned := report.Files[0]
+		assert.True(t, scanned.Scanned)
+		assert.Empty(t, scanned.Reason)
+		assert.Equal(t, []ExplainedEmoji{
+			{Emoji: "🚀", Category: "unicode", Count: 1},
+			{Emoji: "✅", Category: "unicode", Count: 2, Allowed: true},
+		}, scanned.Emojis)
+
+		ignored := report.Files[1]
+		assert.False(t, ignored.Scanned)
+		assert.Equal(t, "profile.file_ignore_list", ignored.Decision.Rule)
+		assert.Contains(t, ignored.Reason, "*.min.js")
+		assert.Len(t, ignored.Emojis, 1, "emojis are reported for excluded files too")
+	})
+
+	t.Run("text", func(t *testing.T) {
+		out := run(t, &ExplainOptions{Format: "text", ConfigFile: configFile, IgnoreAllowlist: true, ExcludePattern: "*.go"}, source)
+
+		assert.Contains(t, out, "emoji_allowlist:")
+		assert.Contains(t, out, "command_line.exclude")
+		assert.Contains(t, out, "matched *.go")
+		assert.Contains(t, out, "Scanned:")
+		assert.Contains(t, out, "no (excluded: matches command-line exclude pattern: *.go)")
+		assert.NotContains(t, out, "allowed", "--ignore-allowlist makes every emoji a violation")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		out := run(t, &ExplainOptions{Format: "json"}, filepath.Join(dir, "missing.go"))
+
+		var report ExplainReport
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		assert.False(t, report.Files[0].Scanned)
+		assert.Contains(t, report.Files[0].Reason, "no such file")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		handler := NewExplainHandler(logging.NewMockLogger(), quietOutput())
+		err := handler.Execute(context.Background(), []string{source}, &ExplainOptions{Format: "xml"})
+		assert.ErrorContains(t, err, "unsupported format")
+	})
+}
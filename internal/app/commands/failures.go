@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/fs"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+)
+
+// Categories of the files that could not be processed.
+const (
+	failurePermission   = "permission_denied"
+	failureNotFound     = "not_found"
+	failureTooLarge     = "too_large"
+	failureEncoding     = "encoding"
+	failureExtraction   = "extraction"
+	failureIO           = "io"
+	failureRetries      = "retries_exhausted"
+	failureNotProcessed = "not_processed"
+	failureRolledBack   = "rolled_back"
+	failureOther        = "other"
+)
+
+// FileFailure is a file that could not be processed, as listed in the error report.
+type FileFailure struct {
+	Path     string `json:"path"`
+	Category string `json:"category"`
+	Reason   string `json:"reason"`
+}
+
+// ErrorReport is the document written by --error-report.
+type ErrorReport struct {
+	Operation   string         `json:"operation"`
+	TotalFiles  int            `json:"total_files"`
+	FailedFiles int            `json:"failed_files"`
+	Aborted     bool           `json:"aborted"`
+	Categories  map[string]int `json:"categories"`
+	Files       []FileFailure  `json:"files"`
+}
+
+// failureCategory returns the category of the error of a file.
+func failureCategory(err error) string {
+	var pathErr *iofs.PathError
+	switch {
+	case errors.Is(err, processor.ErrTooManyErrors):
+		return failureNotProcessed
+	case errors.Is(err, processor.ErrRolledBack):
+		return failureRolledBack
+	case errors.Is(err, fs.ErrRetriesExhausted):
+		return failureRetries
+	case errors.Is(err, processor.ErrFileTooLarge):
+		return failureTooLarge
+	case errors.Is(err, iofs.ErrPermission):
+		return failurePermission
+	case errors.Is(err, iofs.ErrNotExist):
+		return failureNotFound
+	case errors.Is(err, fs.ErrUnencodable), errors.Is(err, fs.ErrBinaryContent):
+		return failureEncoding
+	case errors.Is(err, processor.ErrExtractText):
+		return failureExtraction
+	case errors.As(err, &pathErr):
+		return failureIO
+	default:
+		return failureOther
+	}
+}
+
+// newFileFailure describes the error err of the file at path.
+func newFileFailure(path string, err error) FileFailure {
+	return FileFailure{Path: path, Category: failureCategory(err), Reason: err.Error()}
+}
+
+// scanFailures returns the files of results that could not be processed.
+func scanFailures(results []types.ProcessResult) []FileFailure {
+	var failures []FileFailure
+	for _, result := range results {
+		if result.Error != nil {
+			failures = append(failures, newFileFailure(result.FilePath, result.Error))
+		}
+	}
+	return failures
+}
+
+// modifyFailures returns the files of results that could not be processed.
+func modifyFailures(results []processor.ModifyResult) []FileFailure {
+	var failures []FileFailure
+	for _, result := range results {
+		if result.Error != nil {
+			failures = append(failures, newFileFailure(result.FilePath, result.Error))
+		}
+	}
+	return failures
+}
+
+// newErrorReport builds the error report of an operation over total files.
+func newErrorReport(operation string, total int, failures []FileFailure) ErrorReport {
+	report := ErrorReport{
+		Operation:   operation,
+		TotalFiles:  total,
+		FailedFiles: len(failures),
+		Categories:  map[string]int{},
+		Files:       failures,
+	}
+	if report.Files == nil {
+		report.Files = []FileFailure{}
+	}
+	for _, failure := range failures {
+		report.Categories[failure.Category]++
+		if failure.Category == failureNotProcessed {
+			report.Aborted = true
+		}
+	}
+	return report
+}
+
+// writeErrorReport writes report as JSON to path.
+func writeErrorReport(path string, report ErrorReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) // #nosec G306 - reports are meant to be shared
+}
+
+// formatCategories lists the categories of report by decreasing count, e.g.
+// "permission_denied: 12, not_found: 1".
+func formatCategories(report ErrorReport) string {
+	names := make([]string, 0, len(report.Categories))
+	for name := range report.Categories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if report.Categories[names[i]] != report.Categories[names[j]] {
+			return report.Categories[names[i]] > report.Categories[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %d", name, report.Categories[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reportFailures shows the failures of report by category, noting an early stop, and
+// writes report to reportFile when one is given.
+func reportFailures(ctx context.Context, output ui.UserOutput, report ErrorReport, maxErrors int, reportFile string) error {
+	if report.FailedFiles > 0 {
+		output.Error(ctx, "Errors by category: %s", formatCategories(report))
+	}
+	if report.Aborted {
+		output.Error(ctx, "Stopped after %d errors (--max-errors); %d files were not processed",
+			maxErrors, report.Categories[failureNotProcessed])
+	}
+
+	if reportFile == "" {
+		return nil
+	}
+	if err := writeErrorReport(reportFile, report); err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to write error report: %w", err))
+	}
+	output.Info(ctx, "Error report for %d files written to %s", report.FailedFiles, reportFile)
+	return nil
+}
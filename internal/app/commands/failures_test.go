@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/fs"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailureCategory(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{&iofs.PathError{Op: "open", Path: "a", Err: iofs.ErrPermission}, failurePermission},
+		{&iofs.PathError{Op: "stat", Path: "a", Err: iofs.ErrNotExist}, failureNotFound},
+		{&iofs.PathError{Op: "read", Path: "a", Err: errors.New("input/output error")}, failureIO},
+		{processor.ErrFileTooLarge, failureTooLarge},
+		{processor.ErrTooManyErrors, failureNotProcessed},
+		{processor.ErrRolledBack, failureRolledBack},
+		{fmt.Errorf("failed to encode file: %w", fs.ErrUnencodable), failureEncoding},
+		{fmt.Errorf("%w: zip: not a valid zip file", processor.ErrExtractText), failureExtraction},
+		{fmt.Errorf("%w after 3 attempts: %w", fs.ErrRetriesExhausted, &iofs.PathError{Op: "read", Path: "a", Err: errors.New("input/output error")}), failureRetries},
+		{errors.New("something else"), failureOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, failureCategory(tt.err))
+		})
+	}
+}
+
+func TestNewErrorReport(t *testing.T) {
+	report := newErrorReport("clean", 10, []FileFailure{
+		newFileFailure("a.go", &iofs.PathError{Op: "open", Path: "a.go", Err: iofs.ErrPermission}),
+		newFileFailure("b.go", &iofs.PathError{Op: "open", Path: "b.go", Err: iofs.ErrPermission}),
+		newFileFailure("c.go", processor.ErrTooManyErrors),
+	})
+
+	assert.Equal(t, 3, report.FailedFiles)
+	assert.True(t, report.Aborted)
+	assert.Equal(t, map[string]int{failurePermission: 2, failureNotProcessed: 1}, report.Categories)
+	assert.Equal(t, "permission_denied: 2, not_processed: 1", formatCategories(report))
+	assert.Equal(t, "open a.go: permission denied", report.Files[0].Reason)
+
+	empty := newErrorReport("scan", 3, nil)
+	assert.False(t, empty.Aborted)
+	assert.NotNil(t, empty.Files, "an empty report lists no files rather than null")
+}
+
+func TestScanHandler_ErrorReport(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 4; i++ {
+		// Documents that are not zip archives fail text extraction
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("broken%d.docx", i)), []byte("not a zip"), 0600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	reportFile := filepath.Join(t.TempDir(), "errors.json")
+
+	rootCmd := &cobra.Command{Use: "antimoji"}
+	rootCmd.PersistentFlags().String("config", "", "config file path")
+	rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+	handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+	scanCmd := handler.CreateCommand()
+	rootCmd.AddCommand(scanCmd)
+
+	err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{
+		Recursive: true, Format: "table", ErrorReport: reportFile,
+	})
+	assert.ErrorIs(t, err, ErrPartialFailure)
+
+	content, err := os.ReadFile(reportFile)
+	require.NoError(t, err)
+	var report ErrorReport
+	require.NoError(t, json.Unmarshal(content, &report))
+	assert.Equal(t, "scan", report.Operation)
+	assert.Equal(t, 5, report.TotalFiles)
+	assert.Equal(t, 4, report.FailedFiles)
+	assert.Equal(t, map[string]int{failureExtraction: 4}, report.Categories)
+	assert.False(t, report.Aborted)
+
+	err = handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Format: "table", MaxErrors: -1})
+	assert.ErrorContains(t, err, "--max-errors must be non-negative")
+}
@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/fs"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// FilterOptions holds the options for the filter command.
+type FilterOptions struct {
+	Clean           bool
+	Smudge          bool
+	Replace         string
+	IgnoreAllowlist bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+}
+
+// FilterHandler handles the filter command with dependency injection.
+type FilterHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	in     io.Reader
+	out    io.Writer
+}
+
+// NewFilterHandler creates a new filter command handler.
+func NewFilterHandler(logger logging.Logger, ui ui.UserOutput) *FilterHandler {
+	return &FilterHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithInput sets the reader the content is filtered from (defaults to stdin).
+func (h *FilterHandler) WithInput(in io.Reader) *FilterHandler {
+	h.in = in
+	return h
+}
+
+// WithOutput sets the writer the filtered content is written to (defaults to stdout).
+func (h *FilterHandler) WithOutput(out io.Writer) *FilterHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the filter cobra command.
+func (h *FilterHandler) CreateCommand() *cobra.Command {
+	opts := &FilterOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "filter (--clean | --smudge) [path]",
+		Short: "Strip emojis from stdin to stdout as a git filter",
+		Long: `Copy standard input to standard output with the emojis the profile does not
+allow removed, for use as a git clean or smudge filter.
+
+As a clean filter, emojis are stripped from the content git stores on commit
+while working tree files keep them. As a smudge filter, files are stripped on
+checkout while the repository keeps them. The optional path, %f in the git
+configuration, selects the language of the content and the profile's include
+and exclude patterns; excluded paths pass through unchanged.
+
+Content that does not look like text anywhere passes through byte for byte, and
+so does content that cannot be cleaned. Messages go to standard error only.
+
+Configure the filter with:
+  git config filter.antimoji.clean "antimoji filter --clean %f"
+  git config filter.antimoji.smudge cat
+  echo "*.md filter=antimoji" >> .gitattributes
+
+Examples:
+  antimoji filter --clean README.md < README.md
+  antimoji filter --smudge --profile ci docs/guide.md < docs/guide.md`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.Execute(cmd.Context(), args, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Clean, "clean", false, "run as a git clean filter, stripping emojis on commit")
+	cmd.Flags().BoolVar(&opts.Smudge, "smudge", false, "run as a git smudge filter, stripping emojis on checkout")
+	cmd.Flags().StringVar(&opts.Replace, "replace", "", "replacement string for emojis")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+	cmd.MarkFlagsMutuallyExclusive("clean", "smudge")
+	cmd.MarkFlagsOneRequired("clean", "smudge")
+
+	return cmd
+}
+
+// Execute filters the input to the output. Once the input is read, the output is
+// always written, unchanged if it cannot be cleaned, so that git never stores or checks
+// out damaged content.
+func (h *FilterHandler) Execute(parentCtx context.Context, args []string, opts *FilterOptions) error {
+	if opts.Clean == opts.Smudge {
+		return classify(ErrConfig, fmt.Errorf("specify exactly one of --clean or --smudge"))
+	}
+	path := ""
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "filter")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+	if path != "" {
+		ctx = ctxutil.WithFilePath(ctx, path)
+	}
+
+	in, out := h.in, h.out
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stdout
+	}
+
+	engine, err := h.policy(ctx, opts)
+	if err != nil {
+		return err
+	}
+	processing := engine.ProcessingConfig()
+
+	// Decide from the first bytes whether the content needs reading into memory at all
+	sample := make([]byte, processing.Sniff.WithDefaults().SampleSize)
+	n, err := io.ReadFull(in, sample)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return classify(ErrIO, fmt.Errorf("failed to read input: %w", err))
+	}
+	sample = sample[:n]
+
+	skip := ""
+	if path != "" && !engine.FileFilter().ShouldInclude(path).Include {
+		skip = "excluded by the profile"
+	} else if encoding, reason := fs.Sniff(sample, processing.Sniff); encoding == fs.EncodingBinary {
+		skip = "binary content (" + reason + ")"
+	}
+	if skip != "" {
+		h.logger.Debug(ctx, "Passing content through unchanged", "path", path, "reason", skip)
+		return copyThrough(out, io.MultiReader(bytes.NewReader(sample), in))
+	}
+
+	var content bytes.Buffer
+	content.Write(sample)
+	if _, err := content.ReadFrom(in); err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to read input: %w", err))
+	}
+	if int64(content.Len()) > processing.MaxFileSize {
+		h.logger.Debug(ctx, "Passing large content through unchanged", "path", path, "size", content.Len())
+		return copyThrough(out, &content)
+	}
+
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		h.ui.Warning(ctx, "Passing %s through unchanged: %v", displayFilterPath(path), err)
+		return copyThrough(out, &content)
+	}
+	modifyConfig := policyModifyConfig(engine)
+	modifyConfig.Replacement = opts.Replace
+	modifyConfig.RespectAllowlist = engine.Allowlist() != nil
+
+	filtered, result := processor.CleanBytes(path, content.Bytes(), patterns, modifyConfig, engine.Allowlist())
+	if result.Error != nil {
+		h.ui.Warning(ctx, "Passing %s through unchanged: %v", displayFilterPath(path), result.Error)
+	}
+	h.logger.Debug(ctx, "Content filtered", "path", path, "clean", opts.Clean,
+		"emojis_removed", result.EmojisRemoved, "binary_reason", result.BinaryReason)
+	return copyThrough(out, bytes.NewReader(filtered))
+}
+
+// policy resolves the profile of the filter and the policy engine applying it.
+func (h *FilterHandler) policy(ctx context.Context, opts *FilterOptions) (*policy.Engine, error) {
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return nil, classify(ErrConfig, fmt.Errorf("failed to load config: %w", configResult.Error()))
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return nil, classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error()))
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return nil, err
+	}
+	return policy.New(ctx, resolution.Profile, policy.Options{
+		Operation:       "filter",
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       policy.NoThreshold,
+	})
+}
+
+// copyThrough streams from to out unchanged.
+func copyThrough(out io.Writer, from io.Reader) error {
+	if _, err := io.Copy(out, from); err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to copy content: %w", err))
+	}
+	return nil
+}
+
+// displayFilterPath names the filtered content in messages.
+func displayFilterPath(path string) string {
+	if path == "" {
+		return "input"
+	}
+	return path
+}
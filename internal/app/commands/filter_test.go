@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runFilter filters input with the filter command and returns its output.
+func runFilter(t *testing.T, input []byte, args []string, opts *FilterOptions) ([]byte, error) {
+	var out bytes.Buffer
+	handler := NewFilterHandler(logging.NewMockLogger(), quietOutput()).
+		WithInput(bytes.NewReader(input)).
+		WithOutput(&out)
+	err := handler.Execute(context.Background(), args, opts)
+	return out.Bytes(), err
+}
+
+func TestFilterHandler(t *testing.T) {
+	t.Run("strips emojis from the input", func(t *testing.T) {
+		out, err := runFilter(t, []byte("# Launch 🚀\n\nDone ✅ :)\n"), []string{"README.md"}, &FilterOptions{Clean: true})
+		require.NoError(t, err)
+		assert.Equal(t, "# Launch \n\nDone  \n", string(out))
+	})
+
+	t.Run("smudges the same way", func(t *testing.T) {
+		out, err := runFilter(t, []byte("ok 🎉\n"), nil, &FilterOptions{Smudge: true, Replace: "!"})
+		require.NoError(t, err)
+		assert.Equal(t, "ok !\n", string(out))
+	})
+
+	t.Run("passes binary input through byte for byte", func(t *testing.T) {
+		input := append([]byte{0x89, 'P', 'N', 'G', 0, 0, 0, 0x0D}, []byte("🚀 not text")...)
+		out, err := runFilter(t, input, []string{"logo.png"}, &FilterOptions{Clean: true})
+		require.NoError(t, err)
+		assert.Equal(t, input, out)
+	})
+
+	t.Run("passes large binary input through", func(t *testing.T) {
+		input := append(bytes.Repeat([]byte{0, 1, 2, 0xFF}, 1<<16), "🚀"...)
+		out, err := runFilter(t, input, nil, &FilterOptions{Clean: true})
+		require.NoError(t, err)
+		assert.Equal(t, input, out)
+	})
+
+	t.Run("passes empty input through", func(t *testing.T) {
+		out, err := runFilter(t, nil, nil, &FilterOptions{Clean: true})
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("passes excluded paths through", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, ".antimoji.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(strings.Join([]string{
+			"version: \"0.5.0\"",
+			"profiles:",
+			"  default:",
+			"    exclude_patterns: [\"docs/*\"]",
+		}, "\n")+"\n"), 0644))
+
+		input := []byte("keep 🚀\n")
+		out, err := runFilter(t, input, []string{"docs/guide.md"}, &FilterOptions{Clean: true, ConfigFile: configPath})
+		require.NoError(t, err)
+		assert.Equal(t, input, out)
+
+		out, err = runFilter(t, input, []string{"src/main.go"}, &FilterOptions{Clean: true, ConfigFile: configPath})
+		require.NoError(t, err)
+		assert.Equal(t, "keep \n", string(out))
+	})
+
+	t.Run("requires clean or smudge", func(t *testing.T) {
+		_, err := runFilter(t, []byte("x"), nil, &FilterOptions{Clean: true, Smudge: true})
+		assert.ErrorIs(t, err, ErrConfig)
+	})
+}
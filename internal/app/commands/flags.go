@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// addAllowlistFlags adds the flags every command honouring the profile's emoji
+// allowlist takes, storing in ignore whether allowlisted emojis are treated like any
+// other. The --respect-allowlist that clean and filter once took still works but is
+// deprecated: --respect-allowlist=false sets ignore, and --respect-allowlist is the
+// default. --ignore-allowlist wins when both are given.
+func addAllowlistFlags(flags *pflag.FlagSet, ignore *bool) {
+	flags.BoolVar(ignore, "ignore-allowlist", false, "ignore the configured emoji allowlist")
+	respect := flags.VarPF(respectAllowlistValue{ignore: ignore}, "respect-allowlist", "", "respect the configured emoji allowlist")
+	respect.NoOptDefVal = "true"
+	_ = flags.MarkDeprecated("respect-allowlist", "the allowlist is respected by default; use --ignore-allowlist to ignore it")
+}
+
+// respectAllowlistValue is the deprecated --respect-allowlist, the inverse of
+// --ignore-allowlist.
+type respectAllowlistValue struct {
+	ignore *bool
+}
+
+func (v respectAllowlistValue) String() string {
+	// pflag formats the zero value when printing defaults
+	if v.ignore == nil {
+		return "true"
+	}
+	return strconv.FormatBool(!*v.ignore)
+}
+
+func (v respectAllowlistValue) Set(value string) error {
+	respect, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if !respect {
+		*v.ignore = true
+	}
+	return nil
+}
+
+func (v respectAllowlistValue) Type() string {
+	return "bool"
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddAllowlistFlags(t *testing.T) {
+	tests := []struct {
+		args   []string
+		ignore bool
+	}{
+		{nil, false},
+		{[]string{"--ignore-allowlist"}, true},
+		{[]string{"--respect-allowlist"}, false},
+		{[]string{"--respect-allowlist=false"}, true},
+		{[]string{"--ignore-allowlist", "--respect-allowlist"}, true},
+		{[]string{"--respect-allowlist=false", "--ignore-allowlist=false"}, false},
+	}
+	for _, tt := range tests {
+		var ignore bool
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		addAllowlistFlags(flags, &ignore)
+		require.NoError(t, flags.Parse(tt.args))
+		assert.Equal(t, tt.ignore, ignore, "%v", tt.args)
+	}
+
+	var ignore bool
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	addAllowlistFlags(flags, &ignore)
+	assert.NotEmpty(t, flags.Lookup("respect-allowlist").Deprecated)
+	assert.Error(t, flags.Parse([]string{"--respect-allowlist=maybe"}))
+}
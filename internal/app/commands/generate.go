@@ -2,17 +2,37 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/core/diff"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/analysis"
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
 	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/types"
 	"github.com/antimoji/antimoji/internal/ui"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // GenerateOptions holds the options for the generate command.
 type GenerateOptions struct {
 	Output       string
+	MergeInto    string
 	Type         string
 	IncludeTests bool
 	IncludeDocs  bool
@@ -21,12 +41,20 @@ type GenerateOptions struct {
 	MinUsage     int
 	Format       string
 	Profile      string
+	PerDirectory bool
+	// IgnoreAllowlist leaves the selected profile's allowlist out of the generated one
+	IgnoreAllowlist bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
 }
 
 // GenerateHandler handles the generate command with dependency injection.
 type GenerateHandler struct {
 	logger logging.Logger
 	ui     ui.UserOutput
+	out    io.Writer
 }
 
 // NewGenerateHandler creates a new generate command handler.
@@ -37,6 +65,13 @@ func NewGenerateHandler(logger logging.Logger, ui ui.UserOutput) *GenerateHandle
 	}
 }
 
+// WithOutput sets the writer used for the generated configuration and merge diffs
+// (defaults to stdout).
+func (h *GenerateHandler) WithOutput(out io.Writer) *GenerateHandler {
+	h.out = out
+	return h
+}
+
 // CreateCommand creates the generate cobra command.
 func (h *GenerateHandler) CreateCommand() *cobra.Command {
 	opts := &GenerateOptions{}
@@ -48,7 +83,9 @@ func (h *GenerateHandler) CreateCommand() *cobra.Command {
 
 This command scans your project to find all emojis currently in use and generates
 a configuration file that allows those emojis while maintaining strict linting
-for new emoji additions.
+for new emoji additions. The allowlist of the selected profile (--profile) is kept
+in the generated one, so emojis allowed by hand are not dropped; --ignore-allowlist
+generates it from the project's usage alone.
 
 Generation Types:
   ci-lint    - Strict allowlist for CI/CD linting (default)
@@ -59,22 +96,28 @@ Generation Types:
   full       - Allow all found emojis with categorization
 
 Examples:
-  antimoji generate .                           # Generate CI lint config
-  antimoji generate --type=dev .                # Generate dev-friendly config
-  antimoji generate --type=test-only .          # Allow only test emojis
-  antimoji generate --output=.antimoji.yaml .   # Save to specific file
-  antimoji generate --format=yaml --type=full . # Full analysis with YAML output
-  antimoji generate --min-usage=3 .             # Only emojis used 3+ times`,
+  antimoji generate .                             # Generate CI lint config
+  antimoji generate --type=dev .                  # Generate dev-friendly config
+  antimoji generate --type=test-only .            # Allow only test emojis
+  antimoji generate --output=.antimoji.yaml .     # Save to specific file
+  antimoji generate --merge-into .antimoji.yaml . # Update the profile in an existing config
+  antimoji generate --format=json --type=full .   # Full analysis with JSON output
+  antimoji generate --min-usage=3 .               # Only emojis used 3+ times`,
 		Args:          cobra.MinimumNArgs(0),
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
 			return h.Execute(cmd.Context(), cmd, args, opts)
 		},
 	}
 
 	// Add generate-specific flags
 	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "output file path (default: stdout)")
+	cmd.Flags().StringVar(&opts.MergeInto, "merge-into", "", "add or update the generated profile in this config file, keeping its comments and other profiles, and print the diff")
 	cmd.Flags().StringVar(&opts.Type, "type", "ci-lint", "generation type (ci-lint, dev, test-only, docs-only, minimal, full)")
 	cmd.Flags().BoolVar(&opts.IncludeTests, "include-tests", true, "include emojis from test files")
 	cmd.Flags().BoolVar(&opts.IncludeDocs, "include-docs", true, "include emojis from documentation files")
@@ -83,10 +126,81 @@ Examples:
 	cmd.Flags().IntVar(&opts.MinUsage, "min-usage", 1, "minimum usage count to include emoji in allowlist")
 	cmd.Flags().StringVar(&opts.Format, "format", "yaml", "output format (yaml, json)")
 	cmd.Flags().StringVar(&opts.Profile, "profile-name", "", "name for the generated profile (default: based on type)")
+	cmd.Flags().BoolVar(&opts.PerDirectory, "per-directory", false, "allow emojis used under one directory only in a profile of that directory, with rules routing its files")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+	cmd.MarkFlagsMutuallyExclusive("output", "merge-into")
 
 	return cmd
 }
 
+// generationType is what a --type generates besides its allowlist.
+type generationType struct {
+	description         string
+	fileIgnoreList      []string
+	directoryIgnoreList []string
+}
+
+// generationTypes are the --type values.
+var generationTypes = map[string]generationType{
+	"ci-lint": {
+		description: "CI/CD linting profile - strict but allows necessary emojis for tests and documentation",
+		fileIgnoreList: []string{
+			"**/*_test.go", "**/test/**/*", "**/testdata/**/*", "**/fixtures/**/*",
+			"README.md", "CHANGELOG.md", ".github/**/*", "scripts/**/*",
+			"vendor/**/*", "dist/**/*", "bin/**/*",
+		},
+		directoryIgnoreList: []string{".git", "vendor", "dist", "bin", "test", "tests", "testdata", "fixtures", ".github"},
+	},
+	"dev": {
+		description:         "Development profile - permissive allowlist for local development",
+		fileIgnoreList:      []string{"vendor/**/*", "dist/**/*", "bin/**/*", ".git/**/*"},
+		directoryIgnoreList: []string{".git", "vendor", "dist", "bin"},
+	},
+	"test-only": {
+		description: "Test-only profile - allows emojis found in test files only",
+		fileIgnoreList: []string{
+			"**/*_test.go", "**/test/**/*", "**/testdata/**/*", "**/fixtures/**/*",
+			"vendor/**/*", "dist/**/*", "bin/**/*",
+		},
+		directoryIgnoreList: []string{".git", "vendor", "dist", "bin", "test", "tests", "testdata", "fixtures"},
+	},
+	"docs-only": {
+		description:         "Documentation-only profile - allows emojis found in documentation files only",
+		fileIgnoreList:      []string{"README.md", "CHANGELOG.md", "**/*.md", "vendor/**/*", "dist/**/*", "bin/**/*"},
+		directoryIgnoreList: []string{".git", "vendor", "dist", "bin"},
+	},
+	"minimal": {
+		description:         "Minimal profile - allows only frequently used emojis",
+		fileIgnoreList:      []string{"vendor/**/*", "dist/**/*", "bin/**/*"},
+		directoryIgnoreList: []string{".git", "vendor", "dist", "bin"},
+	},
+	"full": {
+		description:         "Full profile - allows all found emojis with comprehensive categorization",
+		fileIgnoreList:      []string{"vendor/**/*", "dist/**/*", "bin/**/*"},
+		directoryIgnoreList: []string{".git", "vendor", "dist", "bin"},
+	},
+}
+
+// generatedKeys are the profile fields generate writes; --merge-into leaves the others
+// of the profile alone.
+var generatedKeys = []string{"emoji_allowlist", "file_ignore_list", "directory_ignore_list"}
+
+// generatedConfig is the configuration generate writes: the generated profile, and
+// with --per-directory the profiles of directories and the rules routing them.
+type generatedConfig struct {
+	Rules    []config.RoutingRule        `yaml:"rules,omitempty" json:"rules,omitempty"`
+	Profiles map[string]generatedProfile `yaml:"profiles" json:"profiles"`
+	// names are the profiles, the generated one first
+	names []string
+}
+
+// generatedProfile is a profile generate writes.
+type generatedProfile struct {
+	EmojiAllowlist      []string `yaml:"emoji_allowlist" json:"emoji_allowlist"`
+	FileIgnoreList      []string `yaml:"file_ignore_list,omitempty" json:"file_ignore_list,omitempty"`
+	DirectoryIgnoreList []string `yaml:"directory_ignore_list,omitempty" json:"directory_ignore_list,omitempty"`
+}
+
 // Execute runs the generate command logic with dependency injection.
 func (h *GenerateHandler) Execute(parentCtx context.Context, cmd *cobra.Command, args []string, opts *GenerateOptions) error {
 	// Derive from parent for cancellation/values
@@ -94,6 +208,20 @@ func (h *GenerateHandler) Execute(parentCtx context.Context, cmd *cobra.Command,
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx = ctxutil.WithOperation(ctx, "generate")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	kind, ok := generationTypes[opts.Type]
+	if !ok {
+		return classify(ErrConfig, fmt.Errorf("unsupported generation type %q; supported: ci-lint, dev, test-only, docs-only, minimal, full", opts.Type))
+	}
+	format := strings.ToLower(opts.Format)
+	if format != "yaml" && format != "json" {
+		return classify(ErrConfig, fmt.Errorf("unsupported format %q; supported: yaml, json", opts.Format))
+	}
+	if opts.MergeInto != "" && format != "yaml" {
+		return classify(ErrConfig, fmt.Errorf("--merge-into writes YAML and cannot be used with --format %s", format))
+	}
 
 	// If no paths provided, use current directory
 	if len(args) == 0 {
@@ -106,16 +234,333 @@ func (h *GenerateHandler) Execute(parentCtx context.Context, cmd *cobra.Command,
 		"type", opts.Type,
 		"paths", args)
 
-	// TODO: For now, return a placeholder message until full implementation
-	// This maintains the same error pattern as before but with dependency injection
-	h.ui.Info(ctx, "Generate command - dependency injection working!")
-	h.ui.Info(ctx, "Analysis would be performed for type: %s", opts.Type)
-	h.ui.Info(ctx, "Output format: %s", opts.Format)
+	usage, err := h.analyze(ctx, args, opts)
+	if err != nil {
+		return err
+	}
+	profileName := opts.Profile
+	if profileName == "" {
+		profileName = opts.Type
+	}
+	generated := usage.generate(profileName, kind, opts)
+	h.logger.Info(ctx, "Allowlist generated", "profile", profileName,
+		"emojis", len(generated.Profiles[profileName].EmojiAllowlist), "directory_rules", len(generated.Rules))
 
-	h.logger.Info(ctx, "Generate command executed with DI",
-		"args", args,
-		"type", opts.Type,
-		"format", opts.Format)
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	if opts.MergeInto != "" {
+		return h.merge(ctx, out, opts.MergeInto, generated)
+	}
+
+	var output bytes.Buffer
+	if format == "json" {
+		encoder := json.NewEncoder(&output)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		err = encoder.Encode(generated)
+	} else {
+		_, _ = fmt.Fprintf(&output, "# Generated by antimoji generate --type=%s\n# %s\n\n", opts.Type, kind.description)
+		encoder := yaml.NewEncoder(&output)
+		encoder.SetIndent(2)
+		err = encoder.Encode(generated)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+
+	if opts.Output != "" {
+		h.logger.Info(ctx, "Writing configuration to file", "output_file", opts.Output, "format", format)
+		if err := os.WriteFile(opts.Output, output.Bytes(), 0600); err != nil {
+			return classify(ErrIO, fmt.Errorf("failed to write %s: %w", opts.Output, err))
+		}
+		h.ui.Success(ctx, "Wrote profile %s with %d allowed emojis to %s", profileName, len(generated.Profiles[profileName].EmojiAllowlist), opts.Output)
+		return nil
+	}
+	_, err = out.Write(output.Bytes())
+	return err
+}
+
+// merge adds or updates the generated profiles and rules in the configuration file at
+// path and prints the diff of the change.
+func (h *GenerateHandler) merge(ctx context.Context, out io.Writer, path string, generated generatedConfig) error {
+	updates := make([]config.ProfileUpdate, 0, len(generated.names))
+	for _, name := range generated.names {
+		profile := generated.Profiles[name]
+		updates = append(updates, config.ProfileUpdate{Name: name, Keys: generatedKeys, Profile: config.Profile{
+			EmojiAllowlist:      profile.EmojiAllowlist,
+			FileIgnoreList:      profile.FileIgnoreList,
+			DirectoryIgnoreList: profile.DirectoryIgnoreList,
+		}})
+	}
+	before, after, err := config.UpdateProfiles(path, updates, generated.Rules)
+	if err != nil {
+		return classify(ErrConfig, fmt.Errorf("failed to merge into %s: %w", path, err))
+	}
+
+	changes := diff.Unified(path, path, string(before), string(after), diff.DefaultContext)
+	if changes == "" {
+		h.ui.Info(ctx, "Profile %s in %s is up to date", generated.names[0], path)
+		return nil
+	}
+	if _, err := io.WriteString(out, changes); err != nil {
+		return err
+	}
+	h.logger.Info(ctx, "Profiles merged", "config_file", path, "profiles", generated.names)
+	h.ui.Success(ctx, "Merged %d profiles and %d rules into %s", len(generated.names), len(generated.Rules), path)
+	return nil
+}
+
+// generateUsage is how often each emoji of a project is used, the types of files
+// (test, documentation, ...) it is used in and the files themselves.
+type generateUsage struct {
+	counts map[string]int
+	byType map[string]map[string]bool
+	files  map[string][]string
+	// kept are the emojis of the selected profile's allowlist, allowed whatever their use
+	kept []string
+}
+
+// analyze detects every emoji in the files of args under the selected profile, without
+// its allowlist.
+func (h *GenerateHandler) analyze(ctx context.Context, args []string, opts *GenerateOptions) (generateUsage, error) {
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return generateUsage{}, classify(ErrConfig, fmt.Errorf("failed to load config: %w", configResult.Error()))
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return generateUsage{}, classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error()))
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return generateUsage{}, err
+	}
+	profile := resolution.Profile
+
+	filePaths, err := filtering.DiscoverFiles(args, filtering.DiscoveryOptions{Recursive: opts.Recursive}, profile)
+	if err != nil {
+		h.logger.Error(ctx, "File discovery failed", "error", err, "paths", args)
+		return generateUsage{}, classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	patterns, err := emojidata.PatternsForProfile(ctx, detector.DefaultEmojiPatterns(), profile)
+	if err != nil {
+		return generateUsage{}, fmt.Errorf("failed to load emoji data: %w", err)
+	}
+
+	results := processor.ProcessFiles(filePaths, patterns, config.ToProcessingConfig(profile))
+	usage := analyzeGenerateUsage(results)
+	if !opts.IgnoreAllowlist {
+		usage.kept = profile.EmojiAllowlist
+	}
+	h.logger.Info(ctx, "Emoji analysis completed", "files", len(results), "unique_emojis", len(usage.counts))
+	return usage, nil
+}
+
+// analyzeGenerateUsage tallies the emojis of results. Files that failed to process are
+// ignored.
+func analyzeGenerateUsage(results []types.ProcessResult) generateUsage {
+	usage := generateUsage{counts: make(map[string]int), byType: make(map[string]map[string]bool), files: make(map[string][]string)}
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		fileType := analysis.CategorizeFile(result.FilePath)
+		path := routePath(result.FilePath)
+		for _, match := range result.DetectionResult.Emojis {
+			if usage.counts[match.Emoji] == 0 || usage.files[match.Emoji][len(usage.files[match.Emoji])-1] != path {
+				usage.files[match.Emoji] = append(usage.files[match.Emoji], path)
+			}
+			usage.counts[match.Emoji]++
+			if usage.byType[fileType] == nil {
+				usage.byType[fileType] = make(map[string]bool)
+			}
+			usage.byType[fileType][match.Emoji] = true
+		}
+	}
+	return usage
+}
+
+// allowlist returns the sorted emojis the --type of opts allows, each used at least
+// --min-usage times, and the emojis kept from the selected profile.
+func (u generateUsage) allowlist(opts *GenerateOptions) []string {
+	minUsage := opts.MinUsage
+	// Types other than dev, full and minimal only allow the emojis of some files
+	var fileTypes []string
+	restricted := true
+	switch opts.Type {
+	case "ci-lint":
+		if opts.IncludeTests {
+			fileTypes = append(fileTypes, "test")
+		}
+		if opts.IncludeDocs {
+			fileTypes = append(fileTypes, "documentation", "markdown")
+		}
+		if opts.IncludeCI {
+			fileTypes = append(fileTypes, "ci")
+		}
+	case "test-only":
+		fileTypes = []string{"test"}
+	case "docs-only":
+		fileTypes = []string{"documentation", "markdown"}
+	case "minimal":
+		// Minimal requires at least 2 uses
+		minUsage = max(minUsage, 2)
+		restricted = false
+	default:
+		restricted = false
+	}
+
+	var allowed []string
+	for emoji, count := range u.counts {
+		if count < minUsage {
+			continue
+		}
+		if !restricted || u.usedIn(emoji, fileTypes) {
+			allowed = append(allowed, emoji)
+		}
+	}
 
-	return fmt.Errorf("generate command not yet fully refactored for dependency injection - complex implementation pending")
+	if opts.Type == "minimal" {
+		// The 20 most used
+		sort.Slice(allowed, func(i, j int) bool {
+			if u.counts[allowed[i]] != u.counts[allowed[j]] {
+				return u.counts[allowed[i]] > u.counts[allowed[j]]
+			}
+			return allowed[i] < allowed[j]
+		})
+		if len(allowed) > 20 {
+			allowed = allowed[:20]
+		}
+	}
+	for _, emoji := range u.kept {
+		if !slices.Contains(allowed, emoji) {
+			allowed = append(allowed, emoji)
+		}
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// usedIn reports whether emoji is used in files of any of fileTypes.
+func (u generateUsage) usedIn(emoji string, fileTypes []string) bool {
+	for _, fileType := range fileTypes {
+		if u.byType[fileType][emoji] {
+			return true
+		}
+	}
+	return false
+}
+
+// generate returns the configuration generated for profile name of kind. With
+// --per-directory, the emojis used under one directory only are left out of the
+// profile's allowlist and allowed in a profile of their directory, name-<directory>,
+// that rules route the directory's files to.
+func (u generateUsage) generate(name string, kind generationType, opts *GenerateOptions) generatedConfig {
+	profile := func(allowlist []string) generatedProfile {
+		sort.Strings(allowlist)
+		return generatedProfile{
+			EmojiAllowlist:      allowlist,
+			FileIgnoreList:      kind.fileIgnoreList,
+			DirectoryIgnoreList: kind.directoryIgnoreList,
+		}
+	}
+	allowed := u.allowlist(opts)
+	if !opts.PerDirectory {
+		return generatedConfig{Profiles: map[string]generatedProfile{name: profile(allowed)}, names: []string{name}}
+	}
+
+	global, scoped := u.scopes(allowed)
+	generated := generatedConfig{Profiles: map[string]generatedProfile{name: profile(global)}, names: []string{name}}
+	dirs := make([]string, 0, len(scoped))
+	for dir := range scoped {
+		dirs = append(dirs, dir)
+	}
+	// Deeper directories first, since the first matching rule wins
+	sort.Slice(dirs, func(i, j int) bool {
+		if depth, other := strings.Count(dirs[i], "/"), strings.Count(dirs[j], "/"); depth != other {
+			return depth > other
+		}
+		return dirs[i] < dirs[j]
+	})
+	for _, dir := range dirs {
+		// A directory's files are routed away from the profiles of its parents, so
+		// their emojis are allowed too
+		allowlist := append([]string(nil), global...)
+		for parent, emojis := range scoped {
+			if dir == parent || strings.HasPrefix(dir, parent+"/") {
+				allowlist = append(allowlist, emojis...)
+			}
+		}
+		dirProfile := name + "-" + profileSlug(dir)
+		generated.Profiles[dirProfile] = profile(allowlist)
+		generated.names = append(generated.names, dirProfile)
+		generated.Rules = append(generated.Rules, config.RoutingRule{Paths: []string{dir + "/**"}, Profile: dirProfile})
+	}
+	return generated
+}
+
+// scopes splits allowed into the emojis used throughout the project and, by directory,
+// those whose files are all under one directory, the deepest one holding them all.
+func (u generateUsage) scopes(allowed []string) (global []string, scoped map[string][]string) {
+	scoped = make(map[string][]string)
+	for _, emoji := range allowed {
+		if dir := commonDir(u.files[emoji]); dir != "" {
+			scoped[dir] = append(scoped[dir], emoji)
+		} else {
+			global = append(global, emoji)
+		}
+	}
+	return global, scoped
+}
+
+// commonDir returns the deepest directory holding all files, "" when that is the
+// working directory or above it.
+func commonDir(files []string) string {
+	var common []string
+	for i, file := range files {
+		parts := strings.Split(path.Dir(file), "/")
+		if i == 0 {
+			common = parts
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(common) == 0 || common[0] == "." || common[0] == ".." {
+		return ""
+	}
+	return strings.Join(common, "/")
+}
+
+// profileSlug turns dir into a profile name suffix: docs/api becomes docs-api.
+func profileSlug(dir string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '-'
+	}, dir), "-")
+}
+
+// routePath returns filePath as routing rules match it: clean, slash-separated and
+// relative to the working directory.
+func routePath(filePath string) string {
+	if filepath.IsAbs(filePath) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, filePath); err == nil {
+				filePath = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(filePath))
 }
@@ -1,224 +1,199 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/antimoji/antimoji/internal/config"
 	"github.com/antimoji/antimoji/internal/observability/logging"
-	"github.com/antimoji/antimoji/internal/ui"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestNewGenerateHandler(t *testing.T) {
-	t.Run("creates handler with dependencies", func(t *testing.T) {
-		logger := logging.NewMockLogger()
-		uiOutput := ui.NewUserOutput(ui.DefaultConfig())
-
-		handler := NewGenerateHandler(logger, uiOutput)
-
-		assert.NotNil(t, handler)
-		assert.Equal(t, logger, handler.logger)
-		assert.Equal(t, uiOutput, handler.ui)
-	})
+// generateProject writes a project using ✅ in a test, 🚀 in its README and 🎉 twice in
+// its code.
+func generateProject(t *testing.T) string {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"main_test.go": "// ✅\n",
+		"README.md":    "# Launch 🚀\n",
+		"main.go":      "// 🎉 🎉\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir
 }
 
-func TestGenerateHandler_CreateCommand(t *testing.T) {
-	logger := logging.NewMockLogger()
-	uiOutput := ui.NewUserOutput(ui.DefaultConfig())
-	handler := NewGenerateHandler(logger, uiOutput)
-
-	t.Run("creates command with correct properties", func(t *testing.T) {
-		cmd := handler.CreateCommand()
-
-		assert.Equal(t, "generate", cmd.Use[:8])
-		assert.Contains(t, cmd.Short, "Generate")
-		assert.NotEmpty(t, cmd.Long)
-		assert.NotNil(t, cmd.RunE)
-	})
-
-	t.Run("has expected flags", func(t *testing.T) {
-		cmd := handler.CreateCommand()
-		flags := cmd.Flags()
-
-		assert.NotNil(t, flags.Lookup("type"))
-		assert.NotNil(t, flags.Lookup("output"))
-		assert.NotNil(t, flags.Lookup("include-tests"))
-		assert.NotNil(t, flags.Lookup("include-docs"))
-		assert.NotNil(t, flags.Lookup("include-ci"))
-		assert.NotNil(t, flags.Lookup("recursive"))
-		assert.NotNil(t, flags.Lookup("min-usage"))
-		assert.NotNil(t, flags.Lookup("format"))
-		assert.NotNil(t, flags.Lookup("profile-name"))
-	})
-
-	t.Run("flag defaults are correct", func(t *testing.T) {
-		cmd := handler.CreateCommand()
-		flags := cmd.Flags()
-
-		typeVal, _ := flags.GetString("type")
-		output, _ := flags.GetString("output")
-		includeTests, _ := flags.GetBool("include-tests")
-		includeDocs, _ := flags.GetBool("include-docs")
-		includeCI, _ := flags.GetBool("include-ci")
-		recursive, _ := flags.GetBool("recursive")
-		minUsage, _ := flags.GetInt("min-usage")
-		format, _ := flags.GetString("format")
-		profileName, _ := flags.GetString("profile-name")
-
-		assert.Equal(t, "ci-lint", typeVal)
-		assert.Equal(t, "", output)
-		assert.True(t, includeTests)
-		assert.True(t, includeDocs)
-		assert.True(t, includeCI)
-		assert.True(t, recursive)
-		assert.Equal(t, 1, minUsage)
-		assert.Equal(t, "yaml", format)
-		assert.Equal(t, "", profileName)
-	})
+func runGenerate(t *testing.T, dir string, opts GenerateOptions) (string, error) {
+	opts.Recursive, opts.IncludeTests, opts.IncludeDocs, opts.IncludeCI, opts.MinUsage = true, true, true, true, 1
+	if opts.Type == "" {
+		opts.Type = "ci-lint"
+	}
+	if opts.Format == "" {
+		opts.Format = "yaml"
+	}
+	var out bytes.Buffer
+	err := NewGenerateHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out).
+		Execute(context.Background(), nil, []string{dir}, &opts)
+	return out.String(), err
 }
 
-func TestGenerateHandler_Execute(t *testing.T) {
-	logger := logging.NewMockLogger()
-	uiOutput := ui.NewUserOutput(ui.DefaultConfig())
-	handler := NewGenerateHandler(logger, uiOutput)
-
-	t.Run("executes with placeholder implementation", func(t *testing.T) {
-		opts := &GenerateOptions{
-			Type:         "allowlist",
-			Output:       "output.yaml",
-			IncludeTests: true,
-			IncludeDocs:  true,
-			IncludeCI:    true,
-			Recursive:    true,
-			MinUsage:     1,
-			Format:       "yaml",
-			Profile:      "custom",
-		}
-
-		err := handler.Execute(context.Background(), nil, []string{"."}, opts)
-
-		// Should return placeholder error
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "not yet fully refactored")
-
-		// Check logging occurred
-		logs := logger.GetLogs()
-		assert.True(t, len(logs) > 0)
-
-		foundStart := false
-		foundEnd := false
-		for _, log := range logs {
-			if log.Message == "Starting emoji analysis for allowlist generation" {
-				foundStart = true
-			}
-			if log.Message == "Generate command executed with DI" {
-				foundEnd = true
-			}
-		}
-		assert.True(t, foundStart)
-		assert.True(t, foundEnd)
+func TestGenerateHandler(t *testing.T) {
+	dir := generateProject(t)
+
+	for _, tt := range []struct {
+		typ  string
+		want []string
+	}{
+		{"ci-lint", []string{"✅", "🚀"}},
+		{"full", []string{"✅", "🎉", "🚀"}},
+		{"docs-only", []string{"🚀"}},
+		{"minimal", []string{"🎉"}},
+	} {
+		t.Run(tt.typ, func(t *testing.T) {
+			out, err := runGenerate(t, dir, GenerateOptions{Type: tt.typ})
+			require.NoError(t, err)
+			assert.Contains(t, out, "# Generated by antimoji generate --type="+tt.typ)
+
+			path := filepath.Join(t.TempDir(), "generated.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(out), 0600))
+			cfg := config.LoadConfigStrict(path).Unwrap()
+			assert.Equal(t, tt.want, cfg.Profiles[tt.typ].EmojiAllowlist)
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out, err := runGenerate(t, dir, GenerateOptions{Format: "json", Profile: "team"})
+		require.NoError(t, err)
+		assert.Contains(t, out, `"team": {`)
+		assert.Contains(t, out, `"emoji_allowlist": [`)
 	})
 
-	t.Run("uses current directory when no args", func(t *testing.T) {
-		opts := &GenerateOptions{
-			Type:   "allowlist",
-			Format: "json",
+	t.Run("keeps the selected profile's allowlist", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".antimoji.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("profiles:\n  default:\n    emoji_allowlist: [\"✨\"]\n"), 0600))
+		generated := func(ignore bool) []string {
+			out, err := runGenerate(t, dir, GenerateOptions{Type: "docs-only", ConfigFile: configPath, IgnoreAllowlist: ignore})
+			require.NoError(t, err)
+			path := filepath.Join(t.TempDir(), "generated.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(out), 0600))
+			return config.LoadConfigStrict(path).Unwrap().Profiles["docs-only"].EmojiAllowlist
 		}
-
-		err := handler.Execute(context.Background(), nil, []string{}, opts)
-
-		assert.Error(t, err) // Still placeholder implementation
-
-		// Check default directory logging
-		logs := logger.GetLogs()
-		found := false
-		for _, log := range logs {
-			if log.Message == "No paths provided, using current directory" {
-				found = true
-				break
-			}
-		}
-		assert.True(t, found)
+		assert.Equal(t, []string{"✨", "🚀"}, generated(false))
+		assert.Equal(t, []string{"🚀"}, generated(true))
 	})
 
-	t.Run("handles nil context", func(t *testing.T) {
-		opts := &GenerateOptions{
-			Type: "allowlist",
-		}
-
-		err := handler.Execute(context.TODO(), nil, []string{"."}, opts)
-
-		assert.Error(t, err) // Placeholder error
+	t.Run("rejects unknown types and formats", func(t *testing.T) {
+		_, err := runGenerate(t, dir, GenerateOptions{Type: "everything"})
+		assert.ErrorIs(t, err, ErrConfig)
+		_, err = runGenerate(t, dir, GenerateOptions{Format: "toml"})
+		assert.ErrorIs(t, err, ErrConfig)
+		_, err = runGenerate(t, dir, GenerateOptions{Format: "json", MergeInto: "config.yaml"})
+		assert.ErrorIs(t, err, ErrConfig)
 	})
+}
 
-	t.Run("logs correct parameters", func(t *testing.T) {
-		opts := &GenerateOptions{
-			Type:         "ci-lint",
-			Output:       "test.json",
-			IncludeTests: false,
-			IncludeDocs:  false,
-			IncludeCI:    false,
-			Recursive:    false,
-			MinUsage:     5,
-			Format:       "json",
-			Profile:      "test-profile",
-		}
-
-		err := handler.Execute(context.Background(), nil, []string{"/test/path"}, opts)
-
-		assert.Error(t, err) // Placeholder error
-
-		// Verify parameters were logged correctly
-		logs := logger.GetLogs()
-		foundCorrectType := false
-		foundCorrectFormat := false
-
-		for _, log := range logs {
-			if log.Message == "Starting emoji analysis for allowlist generation" {
-				// Check log contains correct type
-				for i := 0; i < len(log.KeysAndValues)-1; i += 2 {
-					if log.KeysAndValues[i] == "type" && log.KeysAndValues[i+1] == "ci-lint" {
-						foundCorrectType = true
-					}
-				}
-			}
-			if log.Message == "Generate command executed with DI" {
-				// Check log contains correct format
-				for i := 0; i < len(log.KeysAndValues)-1; i += 2 {
-					if log.KeysAndValues[i] == "format" && log.KeysAndValues[i+1] == "json" {
-						foundCorrectFormat = true
-					}
-				}
-			}
-		}
-
-		assert.True(t, foundCorrectType)
-		assert.True(t, foundCorrectFormat)
+func TestGenerateHandler_MergeInto(t *testing.T) {
+	dir := generateProject(t)
+	configPath := filepath.Join(t.TempDir(), ".antimoji.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`# Team configuration
+profiles:
+  default:
+    max_total: 3 # agreed in review
+  ci:
+    # reviewed allowlist
+    emoji_allowlist: ["✅"]
+    max_per_file: 2
+`), 0600))
+
+	out, err := runGenerate(t, dir, GenerateOptions{MergeInto: configPath, Profile: "ci"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "--- "+configPath)
+	assert.Contains(t, out, `+      - "🚀"`)
+
+	raw, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "# Team configuration")
+	assert.Contains(t, string(raw), "# agreed in review")
+	assert.Contains(t, string(raw), "# reviewed allowlist")
+
+	cfg := config.LoadConfigStrict(configPath).Unwrap()
+	assert.Equal(t, 3, cfg.Profiles["default"].MaxTotal)
+	assert.Equal(t, []string{"✅", "🚀"}, cfg.Profiles["ci"].EmojiAllowlist)
+	assert.Equal(t, 2, cfg.Profiles["ci"].MaxPerFile)
+	assert.Contains(t, cfg.Profiles["ci"].DirectoryIgnoreList, "testdata")
+
+	// Merging again changes nothing
+	out, err = runGenerate(t, dir, GenerateOptions{MergeInto: configPath, Profile: "ci"})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	t.Run("creates a missing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "new.yaml")
+		out, err := runGenerate(t, dir, GenerateOptions{MergeInto: path, Type: "docs-only"})
+		require.NoError(t, err)
+		assert.Contains(t, out, "+  docs-only:")
+		assert.Equal(t, []string{"🚀"}, config.LoadConfigStrict(path).Unwrap().Profiles["docs-only"].EmojiAllowlist)
 	})
 }
 
-func TestGenerateOptions_Struct(t *testing.T) {
-	t.Run("GenerateOptions has expected fields", func(t *testing.T) {
-		opts := &GenerateOptions{
-			Type:         "ci-lint",
-			Output:       "output.json",
-			IncludeTests: false,
-			IncludeDocs:  true,
-			IncludeCI:    false,
-			Recursive:    true,
-			MinUsage:     3,
-			Format:       "json",
-			Profile:      "test-profile",
-		}
-
-		assert.Equal(t, "ci-lint", opts.Type)
-		assert.Equal(t, "output.json", opts.Output)
-		assert.False(t, opts.IncludeTests)
-		assert.True(t, opts.IncludeDocs)
-		assert.False(t, opts.IncludeCI)
-		assert.True(t, opts.Recursive)
-		assert.Equal(t, 3, opts.MinUsage)
-		assert.Equal(t, "json", opts.Format)
-		assert.Equal(t, "test-profile", opts.Profile)
+func TestGenerateHandler_PerDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"README.md":          "# Launch 🚀\n",
+		"main.go":            "// 🚀\n",
+		"docs/guide.md":      "# Done 🎉\n",
+		"docs/api/ref.md":    "# Ref 🎉 🔥\n",
+		"docs/api/extra.md":  "🔥\n",
+		"scripts/release.sh": "# ✨\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+	out, err := runGenerate(t, ".", GenerateOptions{Type: "full", PerDirectory: true})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "generated.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(out), 0600))
+	cfg := config.LoadConfigStrict(path).Unwrap()
+
+	assert.Equal(t, []string{"🚀"}, cfg.Profiles["full"].EmojiAllowlist)
+	assert.Equal(t, []config.RoutingRule{
+		{Paths: []string{"docs/api/**"}, Profile: "full-docs-api"},
+		{Paths: []string{"docs/**"}, Profile: "full-docs"},
+		{Paths: []string{"scripts/**"}, Profile: "full-scripts"},
+	}, cfg.Rules)
+	assert.Equal(t, []string{"🎉", "🚀"}, cfg.Profiles["full-docs"].EmojiAllowlist)
+	assert.Equal(t, []string{"🎉", "🔥", "🚀"}, cfg.Profiles["full-docs-api"].EmojiAllowlist)
+	assert.Equal(t, []string{"✨", "🚀"}, cfg.Profiles["full-scripts"].EmojiAllowlist)
+
+	t.Run("merge", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".antimoji.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`profiles:
+  default:
+    max_total: 3
+rules:
+  - paths: ["vendor/**"]
+    profile: default
+  - paths: ["old/**"]
+    profile: full-docs
+`), 0600))
+		out, err := runGenerate(t, ".", GenerateOptions{Type: "full", PerDirectory: true, MergeInto: configPath})
+		require.NoError(t, err)
+		assert.Contains(t, out, "+    profile: full-docs-api")
+
+		cfg := config.LoadConfigStrict(configPath).Unwrap()
+		assert.Equal(t, 3, cfg.Profiles["default"].MaxTotal)
+		assert.Equal(t, []string{"🎉", "🚀"}, cfg.Profiles["full-docs"].EmojiAllowlist)
+		require.Len(t, cfg.Rules, 4)
+		assert.Equal(t, "full-docs-api", cfg.Rules[0].Profile)
+		assert.Equal(t, []string{"vendor/**"}, cfg.Rules[3].Paths)
 	})
 }
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/git"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// CommitMsgOptions holds the options for the hook commit-msg command.
+type CommitMsgOptions struct {
+	Threshold int
+	// ThresholdSet is set when --threshold was given rather than defaulted
+	ThresholdSet    bool
+	IgnoreAllowlist bool
+	CheckBranch     bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+}
+
+// HookHandler handles git hook commands with dependency injection.
+type HookHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	repo   *git.Repository
+}
+
+// NewHookHandler creates a new hook command handler.
+func NewHookHandler(logger logging.Logger, ui ui.UserOutput) *HookHandler {
+	return &HookHandler{
+		logger: logger,
+		ui:     ui,
+		repo:   git.NewRepository(""),
+	}
+}
+
+// WithRepository sets the repository used to resolve the current branch.
+func (h *HookHandler) WithRepository(repo *git.Repository) *HookHandler {
+	h.repo = repo
+	return h
+}
+
+// CreateCommand creates the hook cobra command and its subcommands.
+func (h *HookHandler) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Run antimoji as a git hook",
+		Long: `Run antimoji from git hooks to apply the emoji policy to commit metadata.
+
+Examples:
+  antimoji hook commit-msg .git/COMMIT_EDITMSG   # Check a commit message`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.AddCommand(h.createCommitMsgCommand())
+
+	return cmd
+}
+
+// createCommitMsgCommand creates the hook commit-msg subcommand.
+func (h *HookHandler) createCommitMsgCommand() *cobra.Command {
+	opts := &CommitMsgOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "commit-msg <file>",
+		Short: "Check a commit message and branch name for emojis",
+		Long: `Check a commit message file for emojis, as a git commit-msg hook.
+
+Comment lines and the diff appended by 'git commit --verbose' are ignored, as
+git strips them from the final message. The current branch name is checked too.
+The hook fails when the number of emojis exceeds the threshold, which defaults
+to the profile's max_total; without one, any emoji fails the hook.
+
+Examples:
+  antimoji hook commit-msg .git/COMMIT_EDITMSG
+  antimoji hook commit-msg --profile=zero-tolerance "$1"`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			opts.ThresholdSet = cmd.Flags().Changed("threshold")
+			return h.ExecuteCommitMsg(cmd.Context(), args[0], opts)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.Threshold, "threshold", 0, "number of emojis tolerated (0 = none, -1 = no limit; default: the profile's max_total, or none)")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+	cmd.Flags().BoolVar(&opts.CheckBranch, "check-branch", true, "also check the current branch name")
+
+	return cmd
+}
+
+// ExecuteCommitMsg checks the commit message in messageFile against the emoji policy.
+func (h *HookHandler) ExecuteCommitMsg(parentCtx context.Context, messageFile string, opts *CommitMsgOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "hook-commit-msg")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	content, err := os.ReadFile(messageFile) // #nosec G304 - path is provided by git
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to read commit message: %w", err))
+	}
+
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return fmt.Errorf("failed to load config: %w", configResult.Error())
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error())
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return err
+	}
+	profile := resolution.Profile
+
+	threshold := policy.ResolveThreshold(opts.Threshold, opts.ThresholdSet, config.TotalLimit(profile), 0)
+	if opts.ThresholdSet {
+		if err := lockedThresholdError(resolution, threshold); err != nil {
+			return err
+		}
+	}
+	engine, err := policy.New(ctx, profile, policy.Options{
+		Operation:       "hook-commit-msg",
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       threshold,
+	})
+	if err != nil {
+		return err
+	}
+	emojiAllowlist := engine.Allowlist()
+
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return err
+	}
+	processingConfig := engine.ProcessingConfig()
+
+	messageMatches := detectInText(git.CleanMessage(string(content)), patterns, processingConfig, emojiAllowlist)
+
+	var branch string
+	var branchMatches []types.EmojiMatch
+	if opts.CheckBranch {
+		branch, err = h.repo.CurrentBranch(ctx)
+		if err != nil {
+			// Hooks may run outside a work tree (e.g. in tests); the message is still checked
+			h.logger.Debug(ctx, "Could not determine current branch", "error", err)
+		}
+		branchMatches = detectInText(branch, patterns, processingConfig, emojiAllowlist)
+	}
+
+	// Warnings are listed when the hook fails but do not count against the threshold
+	total := 0
+	for _, matches := range [][]types.EmojiMatch{messageMatches, branchMatches} {
+		for _, match := range matches {
+			if engine.Severity(match) != types.SeverityWarn {
+				total++
+			}
+		}
+	}
+	h.logger.Info(ctx, "Commit message checked",
+		"message_emojis", len(messageMatches),
+		"branch_emojis", len(branchMatches),
+		"threshold", threshold)
+
+	thresholdErr := engine.Evaluate(total)
+	if thresholdErr == nil {
+		return nil
+	}
+
+	for _, match := range messageMatches {
+		h.ui.Error(ctx, "Commit message %d:%d: %s", match.Line, match.Column, match.Display())
+	}
+	for _, match := range branchMatches {
+		h.ui.Error(ctx, "Branch name %q: %s", branch, match.Display())
+	}
+	h.ui.Error(ctx, "Emoji threshold exceeded: found %d emojis, threshold is %d", total, threshold)
+
+	return fmt.Errorf("commit metadata: %w", thresholdErr)
+}
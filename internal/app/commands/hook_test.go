@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/git"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookHandler_CreateCommand(t *testing.T) {
+	handler := NewHookHandler(logging.NewMockLogger(), ui.NewUserOutput(ui.DefaultConfig()))
+	cmd := handler.CreateCommand()
+
+	assert.Equal(t, "hook", cmd.Use)
+	commitMsg, _, err := cmd.Find([]string{"commit-msg"})
+	require.NoError(t, err)
+	assert.NotNil(t, commitMsg.Flags().Lookup("threshold"))
+	assert.NotNil(t, commitMsg.Flags().Lookup("check-branch"))
+}
+
+func TestHookHandler_ExecuteCommitMsg(t *testing.T) {
+	tempDir := t.TempDir()
+
+	newHandler := func() *HookHandler {
+		// A directory outside any repository, so the branch check is skipped
+		return NewHookHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputSilent, Writer: io.Discard, ErrorWriter: io.Discard})).
+			WithRepository(git.NewRepository(t.TempDir()))
+	}
+	writeMessage := func(t *testing.T, message string) string {
+		path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+		require.NoError(t, os.WriteFile(path, []byte(message), 0644))
+		return path
+	}
+
+	t.Run("accepts message without emojis", func(t *testing.T) {
+		path := writeMessage(t, "Fix parser\n\n# Comment with 🎉 is stripped by git\n")
+
+		err := newHandler().ExecuteCommitMsg(context.Background(), path, &CommitMsgOptions{CheckBranch: true, ProfileName: "default"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects message with emojis", func(t *testing.T) {
+		path := writeMessage(t, "Ship it 🚀\n")
+
+		err := newHandler().ExecuteCommitMsg(context.Background(), path, &CommitMsgOptions{CheckBranch: true, ProfileName: "default"})
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+	})
+
+	t.Run("respects explicit threshold", func(t *testing.T) {
+		path := writeMessage(t, "Ship it 🚀\n")
+
+		err := newHandler().ExecuteCommitMsg(context.Background(), path, &CommitMsgOptions{Threshold: 1, ThresholdSet: true, ProfileName: "default"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("respects configured allowlist", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "config.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("profiles:\n  default:\n    unicode_emojis: true\n    emoji_allowlist: [\"✅\"]\n"), 0644))
+		path := writeMessage(t, "Tests pass ✅\n")
+
+		err := newHandler().ExecuteCommitMsg(context.Background(), path, &CommitMsgOptions{ConfigFile: configPath, ProfileName: "default"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("fails for missing file", func(t *testing.T) {
+		err := newHandler().ExecuteCommitMsg(context.Background(), filepath.Join(tempDir, "missing"), &CommitMsgOptions{ProfileName: "default"})
+		assert.Error(t, err)
+	})
+}
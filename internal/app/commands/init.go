@@ -0,0 +1,563 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/analysis"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Policies offered by init.
+const (
+	initZeroTolerance = "zero-tolerance"
+	initAllowList     = "allow-list"
+	initPermissive    = "permissive"
+)
+
+// initProfileName is the profile init writes, the one commands use by default.
+const initProfileName = "default"
+
+// Exclude patterns written by init. The base patterns are always excluded, as in the
+// default profile; the others exclude documentation and tests when emojis are allowed
+// there.
+var (
+	initBaseExcludes = []string{"vendor/**", "node_modules/**", ".git/**"}
+	initDocExcludes  = []string{"*.md", "*.mdx", "*.rst", "*.txt", "**/docs/**"}
+	initTestExcludes = []string{
+		"*_test.go", "*.test.*", "*.spec.*", "test_*.py",
+		"**/test/**", "**/tests/**", "**/testdata/**", "**/__tests__/**",
+	}
+)
+
+// InitOptions holds the options for the init command.
+type InitOptions struct {
+	Yes   bool
+	Force bool
+	// Hooks is pre-commit or none; empty asks
+	Hooks string
+}
+
+// InitHandler handles the init command with dependency injection.
+type InitHandler struct {
+	logger   logging.Logger
+	ui       ui.UserOutput
+	prompter *ui.Prompter
+	out      io.Writer
+}
+
+// NewInitHandler creates a new init command handler.
+func NewInitHandler(logger logging.Logger, ui ui.UserOutput) *InitHandler {
+	return &InitHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithPrompter sets the prompter asking the questions (defaults to stdin/stdout).
+func (h *InitHandler) WithPrompter(prompter *ui.Prompter) *InitHandler {
+	h.prompter = prompter
+	return h
+}
+
+// WithOutput sets the writer used for the survey and preview (defaults to stdout).
+func (h *InitHandler) WithOutput(out io.Writer) *InitHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the init cobra command.
+func (h *InitHandler) CreateCommand() *cobra.Command {
+	opts := &InitOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "init [path]",
+		Short: "Set up antimoji for a repository step by step",
+		Long: `Scan a repository, show how it uses emojis today and ask how strict the
+policy should be, then write .antimoji.yaml and optionally pre-commit hooks.
+
+The questions cover the policy (zero-tolerance, allow-list or permissive),
+whether the emojis already in use stay allowed and whether documentation and
+tests may use emojis. Before anything is written, init shows the configuration
+and what antimoji scan would report with it.
+
+Examples:
+  antimoji init                      # Answer the questions for the current directory
+  antimoji init --yes                # Accept the suggested answers
+  antimoji init --hooks=pre-commit   # Also add the pre-commit hooks`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			return h.Execute(cmd.Context(), dir, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "accept the suggested answers without asking")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "overwrite an existing configuration file")
+	cmd.Flags().StringVar(&opts.Hooks, "hooks", "", "git hooks to add (pre-commit, none; default: ask)")
+
+	return cmd
+}
+
+// initAnswers are the choices made in the init wizard.
+type initAnswers struct {
+	Policy     string
+	Allowlist  []string
+	AllowDocs  bool
+	AllowTests bool
+	PreCommit  bool
+}
+
+// Execute runs the init command logic with dependency injection.
+func (h *InitHandler) Execute(parentCtx context.Context, dir string, opts *InitOptions) error {
+	switch opts.Hooks {
+	case "", "pre-commit", "none":
+		// ok
+	default:
+		return classify(ErrConfig, fmt.Errorf("unsupported hooks %q; supported: pre-commit, none", opts.Hooks))
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "init")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	configPath := filepath.Join(dir, defaultConfigFile)
+	if _, err := os.Stat(configPath); err == nil && !opts.Force {
+		return classify(ErrConfig, fmt.Errorf("%s already exists (use --force to replace it, or 'antimoji config set' to change it)", configPath))
+	}
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	prompter := h.prompter
+	if prompter == nil {
+		prompter = ui.NewPrompter(os.Stdin, out)
+	}
+
+	h.logger.Info(ctx, "Starting init", "dir", dir)
+	report, err := surveyUsage(ctx, dir)
+	if err != nil {
+		return err
+	}
+	writeInitSurvey(out, report)
+
+	answers, err := askInitQuestions(prompter, report, opts)
+	if err != nil {
+		return fmt.Errorf("init cancelled: %w", err)
+	}
+
+	content, err := renderInitConfig(answers)
+	if err != nil {
+		return err
+	}
+	preview, err := previewInitConfig(ctx, dir, content)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "\n%s:\n\n%s\n", configPath, content)
+	writeInitPreview(out, preview)
+
+	if !opts.Yes {
+		choice, err := prompter.Choose("Write the configuration?", []string{"y", "n"})
+		if err != nil {
+			return fmt.Errorf("init cancelled: %w", err)
+		}
+		if choice != "y" {
+			_, _ = fmt.Fprintf(out, "Nothing written.\n")
+			return nil
+		}
+	}
+
+	if err := os.WriteFile(configPath, content, 0644); err != nil { // #nosec G306 - configuration file, not secret
+		return classify(ErrIO, fmt.Errorf("failed to write %s: %w", configPath, err))
+	}
+	h.logger.Info(ctx, "Configuration written", "path", configPath, "policy", answers.Policy)
+	_, _ = fmt.Fprintf(out, "Wrote %s\n", configPath)
+
+	if answers.PreCommit {
+		added, err := addInitPreCommitHooks(filepath.Join(dir, preCommitConfigFile), answers.Policy)
+		if err != nil {
+			return classify(ErrIO, err)
+		}
+		if added {
+			_, _ = fmt.Fprintf(out, "Added the antimoji hooks to %s\n", preCommitConfigFile)
+		} else {
+			_, _ = fmt.Fprintf(out, "%s already has antimoji hooks; left unchanged\n", preCommitConfigFile)
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "\nNext steps:\n")
+	if preview.violations > 0 && answers.Policy != initPermissive {
+		_, _ = fmt.Fprintf(out, "  antimoji clean --config %s --in-place .   # remove the reported emojis\n", defaultConfigFile)
+	}
+	_, _ = fmt.Fprintf(out, "  antimoji scan --config %s .\n", defaultConfigFile)
+	if answers.PreCommit {
+		_, _ = fmt.Fprintf(out, "  pre-commit install\n")
+	}
+	return nil
+}
+
+// surveyUsage reports how the files under dir use emojis, checked with the default
+// profile.
+func surveyUsage(ctx context.Context, dir string) (analysis.UsageReport, error) {
+	profile := config.DefaultConfig().Profiles[initProfileName]
+	engine, err := policy.New(ctx, profile, policy.Options{Operation: "init", Recursive: true, Threshold: policy.NoThreshold})
+	if err != nil {
+		return analysis.UsageReport{}, err
+	}
+	discovery, err := engine.SelectFiles([]string{dir})
+	if err != nil {
+		return analysis.UsageReport{}, classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return analysis.UsageReport{}, err
+	}
+	results := processor.ProcessFiles(discovery.Files, patterns, engine.ProcessingConfig())
+	return analysis.AnalyzeUsage(results, analysis.UsageOptions{}), nil
+}
+
+// writeInitSurvey prints the current emoji usage.
+func writeInitSurvey(out io.Writer, report analysis.UsageReport) {
+	_, _ = fmt.Fprintf(out, "Scanned %d files: %d emojis (%d unique) in %d files\n",
+		report.TotalFiles, report.TotalEmojis, report.UniqueEmojis, report.FilesWithEmojis)
+	if report.TotalEmojis == 0 {
+		return
+	}
+
+	top := report.Top(5)
+	emojis := make([]string, 0, len(top.Emojis))
+	for _, emoji := range top.Emojis {
+		emojis = append(emojis, fmt.Sprintf("%s %d", emoji.Emoji, emoji.Count))
+	}
+	_, _ = fmt.Fprintf(out, "  Most used:    %s\n", strings.Join(emojis, ", "))
+	fileTypes := make([]string, 0, len(top.FileTypes))
+	for _, group := range top.FileTypes {
+		fileTypes = append(fileTypes, fmt.Sprintf("%s %d", group.Name, group.Emojis))
+	}
+	_, _ = fmt.Fprintf(out, "  By file type: %s\n\n", strings.Join(fileTypes, ", "))
+}
+
+// askInitQuestions asks for the policy. With --yes the suggested answers are taken:
+// zero tolerance for a repository without emojis, otherwise an allow-list of the
+// emojis in use, with documentation and tests free to use emojis.
+func askInitQuestions(prompter *ui.Prompter, report analysis.UsageReport, opts *InitOptions) (initAnswers, error) {
+	answers := initAnswers{Policy: initZeroTolerance, AllowDocs: true, AllowTests: true, PreCommit: opts.Hooks == "pre-commit"}
+	if report.TotalEmojis > 0 {
+		answers.Policy = initAllowList
+		answers.Allowlist = usedEmojis(report)
+	}
+	if opts.Yes {
+		return answers, nil
+	}
+
+	policies := []string{"z", "a", "p"}
+	if answers.Policy == initAllowList {
+		policies = []string{"a", "z", "p"}
+	}
+	choice, err := prompter.Choose("Policy: zero-tolerance (z), allow-list (a) or permissive (p)?", policies)
+	if err != nil {
+		return answers, err
+	}
+	answers.Policy = map[string]string{"z": initZeroTolerance, "a": initAllowList, "p": initPermissive}[choice]
+
+	answers.Allowlist = nil
+	if answers.Policy == initAllowList {
+		if report.UniqueEmojis > 0 {
+			keep, err := prompter.Choose(fmt.Sprintf("Allow the %d emojis already in use?", report.UniqueEmojis), []string{"y", "n"})
+			if err != nil {
+				return answers, err
+			}
+			if keep == "y" {
+				answers.Allowlist = usedEmojis(report)
+			}
+		}
+		if answers.Allowlist == nil {
+			list, err := prompter.Ask("Emojis to allow, separated by commas: ")
+			if err != nil {
+				return answers, err
+			}
+			answers.Allowlist = splitList(list)
+		}
+	}
+
+	if answers.Policy != initPermissive {
+		docs, err := prompter.Choose("Allow emojis in documentation?", []string{"y", "n"})
+		if err != nil {
+			return answers, err
+		}
+		answers.AllowDocs = docs == "y"
+		tests, err := prompter.Choose("Allow emojis in tests?", []string{"y", "n"})
+		if err != nil {
+			return answers, err
+		}
+		answers.AllowTests = tests == "y"
+	}
+
+	if opts.Hooks == "" {
+		hooks, err := prompter.Choose("Add pre-commit hooks?", []string{"n", "y"})
+		if err != nil {
+			return answers, err
+		}
+		answers.PreCommit = hooks == "y"
+	}
+	return answers, nil
+}
+
+// usedEmojis returns the emojis of report, most used first.
+func usedEmojis(report analysis.UsageReport) []string {
+	emojis := make([]string, 0, len(report.Emojis))
+	for _, emoji := range report.Emojis {
+		emojis = append(emojis, emoji.Emoji)
+	}
+	return emojis
+}
+
+// splitList splits a comma-separated answer, dropping empty items.
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// initProfile is the profile written by init. Only a few fields, such as the size
+// limits, take their defaults when omitted, so detection is written out.
+type initProfile struct {
+	Recursive           bool           `yaml:"recursive"`
+	UnicodeEmojis       bool           `yaml:"unicode_emojis"`
+	TextEmoticons       bool           `yaml:"text_emoticons"`
+	EmojiAllowlist      []string       `yaml:"emoji_allowlist,omitempty"`
+	ExcludePatterns     []string       `yaml:"exclude_patterns"`
+	DirectoryThresholds map[string]int `yaml:"directory_thresholds,omitempty"`
+}
+
+// renderInitConfig returns the configuration file for answers. Except in permissive
+// mode, a directory threshold of zero for "." makes antimoji scan fail on any emoji
+// outside the allowlist.
+func renderInitConfig(answers initAnswers) ([]byte, error) {
+	profile := initProfile{
+		Recursive:       true,
+		UnicodeEmojis:   true,
+		TextEmoticons:   true,
+		ExcludePatterns: append([]string(nil), initBaseExcludes...),
+	}
+	if answers.Policy == initAllowList {
+		profile.EmojiAllowlist = answers.Allowlist
+	}
+	if answers.Policy != initPermissive {
+		profile.DirectoryThresholds = map[string]int{".": 0}
+		if answers.AllowDocs {
+			profile.ExcludePatterns = append(profile.ExcludePatterns, initDocExcludes...)
+		}
+		if answers.AllowTests {
+			profile.ExcludePatterns = append(profile.ExcludePatterns, initTestExcludes...)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by antimoji init (%s policy).\n# 'antimoji config show' lists every field with its value.\n", answers.Policy)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	document := struct {
+		Version  int                    `yaml:"version"`
+		Profiles map[string]initProfile `yaml:"profiles"`
+	}{Version: config.SchemaVersion, Profiles: map[string]initProfile{initProfileName: profile}}
+	if err := encoder.Encode(document); err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// initPreview is what antimoji scan would report with the generated configuration.
+type initPreview struct {
+	violations int
+	// files lists the files with violations, in scan order
+	files []types.ProcessResult
+	// failing reports whether the scan would fail
+	failing bool
+}
+
+// previewInitConfig applies the configuration content to the files under dir the way
+// antimoji scan would.
+func previewInitConfig(ctx context.Context, dir string, content []byte) (initPreview, error) {
+	configResult := config.ParseConfig(content)
+	if configResult.IsErr() {
+		return initPreview{}, fmt.Errorf("generated configuration is invalid: %w", configResult.Error())
+	}
+	profileResult := config.GetProfile(configResult.Unwrap(), initProfileName)
+	if profileResult.IsErr() {
+		return initPreview{}, profileResult.Error()
+	}
+
+	engine, err := policy.New(ctx, profileResult.Unwrap(), policy.Options{Operation: "init", Recursive: true, Threshold: policy.NoThreshold})
+	if err != nil {
+		return initPreview{}, err
+	}
+	discovery, err := engine.SelectFiles([]string{dir})
+	if err != nil {
+		return initPreview{}, classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return initPreview{}, err
+	}
+	results := engine.Apply(processor.ProcessFiles(discovery.Files, patterns, engine.ProcessingConfig()))
+
+	var preview initPreview
+	for _, result := range results {
+		if result.Error == nil && result.DetectionResult.TotalCount > 0 {
+			preview.violations += result.DetectionResult.TotalCount
+			preview.files = append(preview.files, result)
+		}
+	}
+	preview.failing = len(engine.Budgets(results)) > 0
+	return preview, nil
+}
+
+// writeInitPreview prints what antimoji scan would report, listing the first files.
+func writeInitPreview(out io.Writer, preview initPreview) {
+	if preview.violations == 0 {
+		_, _ = fmt.Fprintf(out, "With this configuration antimoji scan reports no emojis.\n")
+		return
+	}
+
+	outcome := "and passes"
+	if preview.failing {
+		outcome = "and fails"
+	}
+	_, _ = fmt.Fprintf(out, "With this configuration antimoji scan reports %d emojis in %d files %s:\n",
+		preview.violations, len(preview.files), outcome)
+	const shown = 5
+	for i, result := range preview.files {
+		if i == shown {
+			_, _ = fmt.Fprintf(out, "  ... and %d more files\n", len(preview.files)-shown)
+			break
+		}
+		_, _ = fmt.Fprintf(out, "  %s: %d\n", result.FilePath, result.DetectionResult.TotalCount)
+	}
+}
+
+// initHook is a pre-commit hook added by init.
+type initHook struct {
+	ID       string   `yaml:"id"`
+	Name     string   `yaml:"name"`
+	Entry    string   `yaml:"entry"`
+	Language string   `yaml:"language"`
+	Types    []string `yaml:"types"`
+}
+
+// initHookRepo is the local repository entry holding the hooks added by init.
+type initHookRepo struct {
+	Repo  string     `yaml:"repo"`
+	Hooks []initHook `yaml:"hooks"`
+}
+
+// addInitPreCommitHooks adds local antimoji hooks to the pre-commit configuration at
+// path, creating it when needed: clean the committed files, then check them, or only
+// check under the permissive policy. A configuration that already runs antimoji is
+// left unchanged, and false is returned.
+func addInitPreCommitHooks(path, policyName string) (bool, error) {
+	var doc yaml.Node
+	data, err := os.ReadFile(path) // #nosec G304 - path is the pre-commit configuration of the target directory
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return false, fmt.Errorf("failed to update %s: not a mapping", path)
+	}
+
+	var repos *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "repos" {
+			repos = root.Content[i+1]
+		}
+	}
+	if repos == nil {
+		repos = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "repos"}, repos)
+	}
+	if repos.Kind != yaml.SequenceNode {
+		return false, fmt.Errorf("failed to update %s: repos is not a list", path)
+	}
+
+	var existing preCommitConfig
+	if err := doc.Decode(&existing); err == nil {
+		for _, repo := range existing.Repos {
+			for _, hook := range repo.Hooks {
+				if strings.HasPrefix(hook.ID, "antimoji") {
+					return false, nil
+				}
+			}
+		}
+	}
+
+	repo := initHookRepo{Repo: "local"}
+	if policyName != initPermissive {
+		repo.Hooks = append(repo.Hooks, initHook{
+			ID:       "antimoji-clean",
+			Name:     "Remove emojis",
+			Entry:    "antimoji clean --config=" + defaultConfigFile + " --in-place",
+			Language: "system",
+			Types:    []string{"text"},
+		})
+	}
+	repo.Hooks = append(repo.Hooks, initHook{
+		ID:       "antimoji-verify",
+		Name:     "Check emoji policy",
+		Entry:    "antimoji scan --config=" + defaultConfigFile,
+		Language: "system",
+		Types:    []string{"text"},
+	})
+	var node yaml.Node
+	if err := node.Encode(repo); err != nil {
+		return false, fmt.Errorf("failed to encode hooks: %w", err)
+	}
+	repos.Content = append(repos.Content, &node)
+
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return false, fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(out.String()), 0644); err != nil { // #nosec G306 - configuration file, not secret
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupInitRepo creates a repository with emojis in source, test and documentation files.
+func setupInitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":      "package main // ship it \U0001F680\n",
+		"main_test.go": "package main // ✅\n",
+		"README.md":    "# Project \U0001F389\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestInitHandler_Execute(t *testing.T) {
+	run := func(dir, input string, opts *InitOptions) (string, error) {
+		var out bytes.Buffer
+		h := NewInitHandler(logging.NewMockLogger(), quietOutput()).
+			WithOutput(&out).
+			WithPrompter(ui.NewPrompter(strings.NewReader(input), &out))
+		err := h.Execute(context.Background(), dir, opts)
+		return out.String(), err
+	}
+	loadProfile := func(t *testing.T, dir string) config.Profile {
+		t.Helper()
+		result := config.LoadConfigStrict(filepath.Join(dir, defaultConfigFile))
+		require.NoError(t, result.Error())
+		return result.Unwrap().Profiles["default"]
+	}
+
+	t.Run("writes the answered configuration and hooks", func(t *testing.T) {
+		dir := setupInitRepo(t)
+		// zero tolerance, documentation allowed, tests not, hooks, write
+		out, err := run(dir, "z\ny\nn\ny\ny\n", &InitOptions{})
+		require.NoError(t, err)
+
+		assert.Contains(t, out, "Scanned 3 files: 3 emojis (3 unique) in 3 files")
+		assert.Contains(t, out, "reports 2 emojis in 2 files and fails")
+		assert.Contains(t, out, "pre-commit install")
+
+		profile := loadProfile(t, dir)
+		assert.Empty(t, profile.EmojiAllowlist)
+		assert.Equal(t, map[string]int{".": 0}, profile.DirectoryThresholds)
+		assert.Contains(t, profile.ExcludePatterns, "*.md")
+		assert.NotContains(t, profile.ExcludePatterns, "*_test.go")
+		assert.True(t, profile.Recursive)
+		assert.True(t, profile.UnicodeEmojis)
+
+		hooks, err := os.ReadFile(filepath.Join(dir, preCommitConfigFile))
+		require.NoError(t, err)
+		assert.Contains(t, string(hooks), "antimoji clean --config=.antimoji.yaml --in-place")
+		assert.Contains(t, string(hooks), "antimoji scan --config=.antimoji.yaml")
+	})
+
+	t.Run("yes accepts the suggested allow-list", func(t *testing.T) {
+		dir := setupInitRepo(t)
+		out, err := run(dir, "", &InitOptions{Yes: true})
+		require.NoError(t, err)
+
+		assert.Contains(t, out, "reports no emojis")
+		profile := loadProfile(t, dir)
+		assert.ElementsMatch(t, []string{"\U0001F680", "✅", "\U0001F389"}, profile.EmojiAllowlist)
+		assert.NoFileExists(t, filepath.Join(dir, preCommitConfigFile))
+	})
+
+	t.Run("permissive only reports", func(t *testing.T) {
+		dir := setupInitRepo(t)
+		out, err := run(dir, "p\ny\n", &InitOptions{Hooks: "none"})
+		require.NoError(t, err)
+
+		assert.Contains(t, out, "reports 3 emojis in 3 files and passes")
+		assert.Empty(t, loadProfile(t, dir).DirectoryThresholds)
+	})
+
+	t.Run("declining writes nothing", func(t *testing.T) {
+		dir := setupInitRepo(t)
+		out, err := run(dir, "a\nn\n✅\ny\ny\nn\nn\n", &InitOptions{})
+		require.NoError(t, err)
+
+		assert.Contains(t, out, "emoji_allowlist:\n      - ✅\n")
+		assert.Contains(t, out, "Nothing written.")
+		assert.NoFileExists(t, filepath.Join(dir, defaultConfigFile))
+	})
+
+	t.Run("closed input cancels", func(t *testing.T) {
+		dir := setupInitRepo(t)
+		_, err := run(dir, "z\n", &InitOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "init cancelled")
+		assert.NoFileExists(t, filepath.Join(dir, defaultConfigFile))
+	})
+
+	t.Run("keeps an existing configuration without force", func(t *testing.T) {
+		dir := setupInitRepo(t)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, defaultConfigFile), []byte("profiles: {}\n"), 0600))
+
+		_, err := run(dir, "", &InitOptions{Yes: true})
+		require.ErrorIs(t, err, ErrConfig)
+
+		_, err = run(dir, "", &InitOptions{Yes: true, Force: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects unknown hooks", func(t *testing.T) {
+		_, err := run(t.TempDir(), "", &InitOptions{Hooks: "husky"})
+		require.ErrorIs(t, err, ErrConfig)
+	})
+}
+
+func TestAddInitPreCommitHooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), preCommitConfigFile)
+	existing := "repos:\n  - repo: https://github.com/pre-commit/pre-commit-hooks\n    rev: v4.6.0\n    hooks:\n      - id: trailing-whitespace\n"
+	require.NoError(t, os.WriteFile(path, []byte(existing), 0600))
+
+	added, err := addInitPreCommitHooks(path, initPermissive)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "trailing-whitespace")
+	assert.Contains(t, string(content), "antimoji-verify")
+	assert.NotContains(t, string(content), "antimoji-clean", "permissive policy only checks")
+
+	// A configuration already running antimoji is left alone
+	added, err = addInitPreCommitHooks(path, initZeroTolerance)
+	require.NoError(t, err)
+	assert.False(t, added)
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, unchanged)
+}
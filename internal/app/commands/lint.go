@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/antimoji/antimoji/internal/config"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// DefaultLintFormat is the finding format of the lint command, understood by the
+// errorformat parsers of most editors.
+const DefaultLintFormat = "{{.Path}}:{{.Line}}:{{.Column}}: {{.Message}} [{{.Rule}}]"
+
+// LintOptions holds the options for the lint command.
+type LintOptions struct {
+	Recursive       bool
+	IncludePattern  string
+	ExcludePattern  string
+	Format          string
+	IgnoreAllowlist bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+}
+
+// LintFinding is a violation as exposed to --format templates.
+type LintFinding struct {
+	Path     string
+	Line     int
+	Column   int
+	Emoji    string
+	Category types.EmojiCategory
+	// Rule names what was violated: the detection category of the emoji
+	Rule string
+	// Severity is types.SeverityError, or types.SeverityWarn for findings that do not
+	// fail the run
+	Severity string
+	Message  string
+}
+
+// LintHandler handles the lint command with dependency injection.
+type LintHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewLintHandler creates a new lint command handler.
+func NewLintHandler(logger logging.Logger, ui ui.UserOutput) *LintHandler {
+	return &LintHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the findings (defaults to stdout).
+func (h *LintHandler) WithOutput(out io.Writer) *LintHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the lint cobra command.
+func (h *LintHandler) CreateCommand() *cobra.Command {
+	opts := &LintOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "lint [flags] [path...]",
+		Short: "Report emojis one finding per line, like a classic linter",
+		Long: `Report the emojis the policy does not allow, one finding per line, without
+modifying anything.
+
+Each finding is printed as path:line:col: message [rule], which editors parse with
+their usual errorformat. --format replaces the line with a Go template over the
+fields .Path, .Line, .Column, .Emoji, .Category, .Rule, .Severity and .Message.
+Only findings of severity error fail the run.
+
+Exits 1 when findings are reported, 2 on configuration errors, 3 when no file
+could be read and 4 when some files could not be read.
+
+Examples:
+  antimoji lint .                                  # path:line:col: message [rule]
+  antimoji lint --include "*.go" src/
+  antimoji lint --format '{{.Path}}({{.Line}}): {{.Emoji}}' .`,
+		Args:          cobra.MinimumNArgs(0),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.Execute(cmd.Context(), args, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.Recursive, "recursive", "r", true, "lint directories recursively")
+	cmd.Flags().StringVar(&opts.IncludePattern, "include", "", "include files matching pattern")
+	cmd.Flags().StringVar(&opts.ExcludePattern, "exclude", "", "exclude files matching pattern")
+	cmd.Flags().StringVar(&opts.Format, "format", DefaultLintFormat, "Go template for each finding")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+
+	return cmd
+}
+
+// Execute runs the lint command logic with dependency injection.
+func (h *LintHandler) Execute(parentCtx context.Context, args []string, opts *LintOptions) error {
+	// Unknown fields only fail when the template runs, so try it on an empty finding
+	format, err := template.New("finding").Parse(opts.Format + "\n")
+	if err == nil {
+		err = format.Execute(io.Discard, LintFinding{})
+	}
+	if err != nil {
+		return classify(ErrConfig, fmt.Errorf("invalid --format: %w", err))
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "lint")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	h.logger.Info(ctx, "Starting lint operation", "paths", args, "options", opts)
+
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return fmt.Errorf("failed to load config: %w", configResult.Error())
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error())
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return err
+	}
+
+	// Every finding fails lint, so no threshold applies
+	policyOpts := policy.Options{
+		Operation:       "lint",
+		Recursive:       opts.Recursive,
+		IncludePattern:  opts.IncludePattern,
+		ExcludePattern:  opts.ExcludePattern,
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       policy.NoThreshold,
+		Rules:           resolution.Policy.Rules(),
+	}
+	engine, err := policy.New(ctx, resolution.Profile, policyOpts)
+	if err != nil {
+		return err
+	}
+	if err := routeRules(ctx, engine, cfg, opts.ConfigFile != "", opts.ProfileName, policyOpts, false, os.Environ()); err != nil {
+		return err
+	}
+
+	discovery, err := engine.SelectFiles(args)
+	if err != nil {
+		h.logger.Error(ctx, "File discovery failed", "error", err, "paths", args)
+		return classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	results, err := detectRouted(ctx, engine, discovery.Files)
+	if err != nil {
+		return err
+	}
+	findings := lintFindings(engine, results)
+	h.logger.Info(ctx, "Lint completed", "files", len(results), "findings", len(findings))
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	for _, finding := range findings {
+		if err := format.Execute(out, finding); err != nil {
+			return classify(ErrIO, fmt.Errorf("failed to write finding: %w", err))
+		}
+	}
+
+	var violation error
+	if errorsFound := countLintErrors(findings); errorsFound > 0 {
+		violation = classify(ErrViolations, fmt.Errorf("%d emojis found", errorsFound))
+	}
+	if failed := countFileFailures(results); failed > 0 {
+		for _, result := range results {
+			if result.Error != nil {
+				h.ui.Error(ctx, "%s: %v", result.FilePath, result.Error)
+			}
+		}
+		return fileFailureError(failed, len(results), violation)
+	}
+	return violation
+}
+
+// lintFindings returns the violations in results in file and position order, each under
+// the profile its file is routed to.
+func lintFindings(engine *policy.Engine, results []types.ProcessResult) []LintFinding {
+	var findings []LintFinding
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		routed := engine.For(result.FilePath)
+		for _, match := range routed.FileViolations(result.FilePath, result.DetectionResult.Emojis) {
+			severity := routed.Severity(match)
+			message := fmt.Sprintf("emoji %s is not allowed", match.Display())
+			if severity == types.SeverityWarn {
+				message = fmt.Sprintf("emoji %s is discouraged", match.Display())
+			}
+			findings = append(findings, LintFinding{
+				Path:     result.FilePath,
+				Line:     match.Line,
+				Column:   match.Column,
+				Emoji:    match.Emoji,
+				Category: match.Category,
+				Rule:     string(match.Category),
+				Severity: severity,
+				Message:  message,
+			})
+		}
+	}
+	return findings
+}
+
+// countLintErrors counts the findings of severity error.
+func countLintErrors(findings []LintFinding) int {
+	errorsFound := 0
+	for _, finding := range findings {
+		if finding.Severity != types.SeverityWarn {
+			errorsFound++
+		}
+	}
+	return errorsFound
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintHandler_Execute(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(file, []byte("package main\n\n// ship it 🚀\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "clean.go"), []byte("package main\n"), 0600))
+
+	run := func(t *testing.T, path string, opts *LintOptions) (string, error) {
+		var out bytes.Buffer
+		handler := NewLintHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+		err := handler.Execute(context.Background(), []string{path}, opts)
+		return out.String(), err
+	}
+
+	t.Run("default format", func(t *testing.T) {
+		out, err := run(t, dir, &LintOptions{Recursive: true, Format: DefaultLintFormat})
+		assert.ErrorIs(t, err, ErrViolations)
+		assert.Equal(t, file+":3:12: emoji 🚀 is not allowed [unicode]\n", out)
+	})
+
+	t.Run("custom format", func(t *testing.T) {
+		out, err := run(t, dir, &LintOptions{Recursive: true, Format: "{{.Line}} {{.Emoji}} {{.Category}}"})
+		assert.ErrorIs(t, err, ErrViolations)
+		assert.Equal(t, "3 🚀 unicode\n", out)
+	})
+
+	t.Run("warnings do not fail", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("profiles:\n  default:\n    severity:\n      unicode: warn\n"), 0600))
+		out, err := run(t, dir, &LintOptions{Recursive: true, Format: "{{.Severity}} " + DefaultLintFormat, ConfigFile: configFile})
+		assert.NoError(t, err)
+		assert.Equal(t, "warn "+file+":3:12: emoji 🚀 is discouraged [unicode]\n", out)
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		out, err := run(t, filepath.Join(dir, "clean.go"), &LintOptions{Format: DefaultLintFormat})
+		assert.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		for _, format := range []string{"{{.Path", "{{.Level}}"} {
+			_, err := run(t, dir, &LintOptions{Recursive: true, Format: format})
+			assert.ErrorIs(t, err, ErrConfig, format)
+		}
+	})
+
+	t.Run("files are not modified", func(t *testing.T) {
+		_, _ = run(t, dir, &LintOptions{Recursive: true, Format: DefaultLintFormat})
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "🚀")
+	})
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+)
+
+// NameFinding is a file or directory whose name contains emojis.
+type NameFinding struct {
+	Path   string
+	IsDir  bool
+	Emojis []types.EmojiMatch
+}
+
+// namedPaths returns the discovered files together with the directories containing
+// them, sorted and without duplicates. Directories are collected up to, but not
+// including, the scanned roots, since the user named those explicitly. For file
+// arguments only the file itself is checked.
+func namedPaths(roots, filePaths []string) []string {
+	rootSet := make(map[string]bool, len(roots))
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if info, err := os.Stat(root); err == nil && !info.IsDir() {
+			root = filepath.Dir(root)
+		}
+		rootSet[root] = true
+	}
+
+	seen := make(map[string]bool, len(filePaths))
+	paths := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		path := filepath.Clean(filePath)
+		for !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+
+			parent := filepath.Dir(path)
+			if parent == path || parent == "." || rootSet[parent] {
+				break
+			}
+			path = parent
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// findEmojiNames reports the paths whose base name contains non-allowlisted emojis.
+func findEmojiNames(paths []string, patterns types.EmojiPatterns, processingConfig types.ProcessingConfig,
+	emojiAllowlist *allowlist.Allowlist) []NameFinding {
+
+	var findings []NameFinding
+	for _, path := range paths {
+		matches := detectInText(filepath.Base(path), patterns, processingConfig, emojiAllowlist)
+		if len(matches) == 0 {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		findings = append(findings, NameFinding{
+			Path:   path,
+			IsDir:  err == nil && info.IsDir(),
+			Emojis: matches,
+		})
+	}
+	return findings
+}
+
+// countNameEmojis counts the emojis across all name findings.
+func countNameEmojis(findings []NameFinding) int {
+	total := 0
+	for _, finding := range findings {
+		total += len(finding.Emojis)
+	}
+	return total
+}
+
+// displayNameFindings prints one line per file or directory name containing emojis.
+func displayNameFindings(ctx context.Context, out ui.UserOutput, findings []NameFinding) {
+	out.Result(ctx, "Found %d emojis in %d file and directory names", countNameEmojis(findings), len(findings))
+	for _, finding := range findings {
+		kind := "file"
+		if finding.IsDir {
+			kind = "directory"
+		}
+		emojis := ""
+		for _, match := range finding.Emojis {
+			emojis += match.Emoji
+		}
+		out.Info(ctx, "%s (%s name): %s", finding.Path, kind, emojis)
+	}
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEmojiNames(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "pkg🚀"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg🚀", "main🎉.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plain.go"), []byte("package main\n"), 0600))
+	return dir
+}
+
+func quietOutput() ui.UserOutput {
+	return ui.NewUserOutput(&ui.Config{Level: ui.OutputSilent, Writer: io.Discard, ErrorWriter: io.Discard})
+}
+
+func TestNamedPaths(t *testing.T) {
+	dir := setupEmojiNames(t)
+	files := []string{filepath.Join(dir, "pkg🚀", "main🎉.go"), filepath.Join(dir, "plain.go")}
+
+	paths := namedPaths([]string{dir}, files)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "pkg🚀"),
+		filepath.Join(dir, "pkg🚀", "main🎉.go"),
+		filepath.Join(dir, "plain.go"),
+	}, paths)
+
+	t.Run("file arguments do not include their parents", func(t *testing.T) {
+		paths := namedPaths(files[:1], files[:1])
+		assert.Equal(t, files[:1], paths)
+	})
+}
+
+func TestScanHandler_IncludeNames(t *testing.T) {
+	dir := setupEmojiNames(t)
+
+	rootCmd := &cobra.Command{Use: "antimoji"}
+	rootCmd.PersistentFlags().String("config", "", "config file path")
+	rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+	handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+	scanCmd := handler.CreateCommand()
+	rootCmd.AddCommand(scanCmd)
+
+	t.Run("names count towards the threshold", func(t *testing.T) {
+		opts := &ScanOptions{Recursive: true, Format: "table", IncludeNames: true, Threshold: 1}
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, opts)
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+	})
+
+	t.Run("names are ignored by default", func(t *testing.T) {
+		opts := &ScanOptions{Recursive: true, Format: "table", Threshold: 1}
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, opts)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects history modes", func(t *testing.T) {
+		opts := &ScanOptions{Format: "table", IncludeNames: true, RevRange: "HEAD~1..HEAD"}
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, opts)
+		assert.Error(t, err)
+	})
+}
+
+func TestCleanHandler_Rename(t *testing.T) {
+	t.Run("dry run reports without renaming", func(t *testing.T) {
+		dir := setupEmojiNames(t)
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+
+		err := handler.Execute(context.Background(), []string{dir}, &CleanOptions{Recursive: true, DryRun: true, Rename: true})
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "pkg🚀", "main🎉.go"))
+	})
+
+	t.Run("renames files and directories in place", func(t *testing.T) {
+		dir := setupEmojiNames(t)
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+
+		err := handler.Execute(context.Background(), []string{dir}, &CleanOptions{Recursive: true, InPlace: true, Rename: true})
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "pkg", "main.go"))
+		assert.FileExists(t, filepath.Join(dir, "plain.go"))
+	})
+
+	t.Run("interactive mode asks before renaming", func(t *testing.T) {
+		dir := setupEmojiNames(t)
+		prompter := ui.NewPrompter(strings.NewReader("y\nn\n"), io.Discard)
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput()).WithPrompter(prompter)
+
+		err := handler.Execute(context.Background(), []string{dir}, &CleanOptions{Recursive: true, InPlace: true, Interactive: true, Rename: true})
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "pkg🚀", "main.go"))
+	})
+
+	t.Run("rejects rename with diff", func(t *testing.T) {
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+		err := handler.Execute(context.Background(), []string{t.TempDir()}, &CleanOptions{Diff: true, Rename: true})
+		assert.Error(t, err)
+	})
+}
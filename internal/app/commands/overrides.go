@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+)
+
+// setFlag is the root persistent flag carrying key=value profile overrides.
+const setFlag = "set"
+
+// strictConfigFlag is the root persistent flag that rejects unknown configuration keys.
+const strictConfigFlag = "strict-config"
+
+// loadConfigFile loads configPath, rejecting unknown keys when strict is set. Its
+// errors are configuration errors.
+func loadConfigFile(configPath string, strict bool) types.Result[config.Config] {
+	result := config.LoadConfig(configPath)
+	if strict {
+		result = config.LoadConfigStrict(configPath)
+	}
+	if result.IsErr() {
+		return types.Err[config.Config](classify(ErrConfig, result.Error()))
+	}
+	return result
+}
+
+// configLoader returns a config.Loader that loads files like loadConfigFile, for
+// config.Manager.
+func configLoader(strict bool) config.Loader {
+	return func(path string) (config.Config, error) {
+		result := loadConfigFile(path, strict)
+		if result.IsErr() {
+			return config.Config{}, result.Error()
+		}
+		return result.Unwrap(), nil
+	}
+}
+
+// showConfigWarnings passes notices from loading a configuration file, such as an
+// available schema migration, on to the user.
+func showConfigWarnings(ctx context.Context, out ui.UserOutput, cfg config.Config) {
+	for _, warning := range cfg.Warnings {
+		out.Warning(ctx, "%s", warning)
+	}
+}
+
+// resolveProfile layers ANTIMOJI_* environment variables and --set overrides on top of
+// a profile loaded from the configuration file (or the defaults when fromFile is false).
+func resolveProfile(profile config.Profile, fromFile bool, sets []string) (config.Resolution, error) {
+	return resolveProfileEnv(profile, fromFile, sets, os.Environ())
+}
+
+// resolveProfileEnv is resolveProfile with the environment variables of environ.
+func resolveProfileEnv(profile config.Profile, fromFile bool, sets, environ []string) (config.Resolution, error) {
+	flagOverrides, err := config.ParseSetFlags(sets)
+	if err != nil {
+		return config.Resolution{}, classify(ErrConfig, err)
+	}
+
+	base := config.SourceDefault
+	if fromFile {
+		base = config.SourceFile
+	}
+
+	resolved := config.Resolve(profile, base, config.EnvOverrides(environ), flagOverrides)
+	if resolved.IsErr() {
+		return config.Resolution{}, classify(ErrConfig, fmt.Errorf("invalid configuration override: %w", resolved.Error()))
+	}
+	return enforceOrgPolicy(resolved.Unwrap(), environ)
+}
+
+// enforceOrgPolicy applies the organization policy (ANTIMOJI_POLICY in environ or the
+// system-wide policy file) to a resolved profile. Conflicts are configuration errors.
+func enforceOrgPolicy(resolution config.Resolution, environ []string) (config.Resolution, error) {
+	orgPolicy, err := config.LoadOrgPolicy(environ)
+	if err != nil {
+		return config.Resolution{}, classify(ErrConfig, err)
+	}
+	enforced, err := orgPolicy.Enforce(resolution)
+	if err != nil {
+		return config.Resolution{}, classify(ErrConfig, err)
+	}
+	return enforced, nil
+}
+
+// profileThreshold returns the limit on violations across a run that the resolved
+// profile sets for when --threshold is not given: max_total, or the deprecated
+// max_emoji_threshold when the environment or --set overrides it. 0 means no limit.
+func profileThreshold(resolution config.Resolution) int {
+	if resolution.Profile.MaxTotal > 0 {
+		return resolution.Profile.MaxTotal
+	}
+	if resolution.Sources["max_emoji_threshold"] >= config.SourceEnv {
+		return resolution.Profile.MaxEmojiThreshold
+	}
+	return 0
+}
+
+// lockedThresholdError rejects a --threshold that loosens a max_total or
+// max_emoji_threshold locked by the organization policy. A negative threshold means no
+// limit.
+func lockedThresholdError(resolution config.Resolution, threshold int) error {
+	for _, locked := range []struct {
+		key   string
+		limit int
+	}{
+		{"max_total", resolution.Profile.MaxTotal},
+		{"max_emoji_threshold", resolution.Profile.MaxEmojiThreshold},
+	} {
+		if !resolution.Policy.Locked(locked.key) || locked.limit <= 0 || (threshold >= 0 && threshold <= locked.limit) {
+			continue
+		}
+		return classify(ErrConfig, fmt.Errorf("--threshold %d conflicts with organization policy %s: %s is locked to at most %d",
+			threshold, resolution.Policy.Path, locked.key, locked.limit))
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanHandler_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// 🚀 🎉\n"), 0600))
+
+	newScan := func(t *testing.T, args ...string) (*ScanHandler, *cobra.Command) {
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", "", "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		rootCmd.PersistentFlags().StringArray(setFlag, nil, "override a profile field")
+		rootCmd.PersistentFlags().Bool(strictConfigFlag, false, "fail on unknown config keys")
+		handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		require.NoError(t, rootCmd.PersistentFlags().Parse(args))
+		return handler, scanCmd
+	}
+
+	t.Run("environment sets the threshold", func(t *testing.T) {
+		t.Setenv("ANTIMOJI_THRESHOLD", "1")
+		handler, scanCmd := newScan(t)
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table"})
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+	})
+
+	t.Run("--set beats the environment", func(t *testing.T) {
+		t.Setenv("ANTIMOJI_THRESHOLD", "1")
+		handler, scanCmd := newScan(t, "--set", "threshold=5")
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("--threshold beats overrides", func(t *testing.T) {
+		t.Setenv("ANTIMOJI_THRESHOLD", "1")
+		handler, scanCmd := newScan(t)
+		require.NoError(t, scanCmd.Flags().Set("threshold", "5"))
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table", Threshold: 5})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid environment values are reported", func(t *testing.T) {
+		t.Setenv("ANTIMOJI_MAX_WORKERS", "lots")
+		handler, scanCmd := newScan(t)
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table"})
+		assert.ErrorContains(t, err, "ANTIMOJI_MAX_WORKERS")
+	})
+
+	t.Run("--strict-config rejects unknown keys", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("profiles:\n  default:\n    unicode_emojis: true\n    recursve: true\n"), 0600))
+
+		handler, scanCmd := newScan(t, "--config", configPath)
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table"})
+		assert.NoError(t, err)
+
+		handler, scanCmd = newScan(t, "--config", configPath, "--strict-config")
+		err = handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table"})
+		assert.ErrorContains(t, err, "profiles.default.recursve")
+	})
+}
+
+func TestScanHandler_OrgPolicy(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// 🚀\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("🚀 🎉\n"), 0600))
+
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte("locked:\n  max_emoji_threshold: 5\nrules:\n  - paths: ['*.go']\n    max_emojis: 0\n"), 0600))
+
+	scan := func(t *testing.T, environ []string, args ...string) error {
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", "", "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		rootCmd.PersistentFlags().StringArray(setFlag, nil, "override a profile field")
+		rootCmd.PersistentFlags().Bool(strictConfigFlag, false, "fail on unknown config keys")
+		handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+		handler.environ = environ
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		require.NoError(t, scanCmd.ParseFlags(args))
+
+		threshold, _ := scanCmd.Flags().GetInt("threshold")
+		return handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table", Threshold: threshold})
+	}
+	withPolicy := []string{config.EnvPolicy + "=" + policyPath}
+
+	t.Run("rules apply whatever the profile says", func(t *testing.T) {
+		assert.NoError(t, scan(t, nil))
+
+		err := scan(t, withPolicy)
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+		assert.ErrorContains(t, err, "policy: *.go has 1 emojis (limit 0)")
+	})
+
+	t.Run("overrides cannot loosen locked fields", func(t *testing.T) {
+		err := scan(t, append(withPolicy, "ANTIMOJI_MAX_EMOJI_THRESHOLD=50"))
+		assert.ErrorIs(t, err, ErrConfig)
+		assert.ErrorContains(t, err, "max_emoji_threshold is locked to at most 5, but ANTIMOJI_MAX_EMOJI_THRESHOLD sets 50")
+
+		err = scan(t, withPolicy, "--threshold", "50")
+		assert.ErrorIs(t, err, ErrConfig)
+		assert.ErrorContains(t, err, "--threshold 50 conflicts with organization policy")
+	})
+}
+
+func TestCleanHandler_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(target, []byte("// 🚀\n"), 0600))
+
+	handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+
+	err := handler.Execute(context.Background(), []string{dir}, &CleanOptions{
+		Recursive: true, InPlace: true, Overrides: []string{"replacement_map=🚀=[launch]"},
+	})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "// [launch]\n", string(content))
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		err := handler.Execute(context.Background(), []string{dir}, &CleanOptions{
+			Recursive: true, InPlace: true, Overrides: []string{"no_such_field=1"},
+		})
+		assert.ErrorContains(t, err, "unknown profile field")
+	})
+}
+
+func TestThreshold_Commands(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// 🚀 🎉\n"), 0600))
+
+	flag := func(threshold int) *int { return &threshold }
+	tests := []struct {
+		name      string
+		maxTotal  int
+		threshold *int
+		// scanFails, checkFails and serveFails are whether two emojis fail each
+		// command; serve takes no --threshold
+		scanFails, checkFails, serveFails bool
+	}{
+		{name: "0 tolerates none", threshold: flag(0), scanFails: true, checkFails: true},
+		{name: "-1 is no limit", threshold: flag(-1)},
+		{name: "within the threshold", threshold: flag(2)},
+		{name: "over the threshold", threshold: flag(1), scanFails: true, checkFails: true},
+		{name: "--threshold overrides max_total", maxTotal: 1, threshold: flag(-1)},
+		{name: "max_total applies without --threshold", maxTotal: 1, scanFails: true, checkFails: true, serveFails: true},
+		{name: "within max_total", maxTotal: 2},
+		{name: "commands' own defaults without either", checkFails: true, serveFails: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "antimoji.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte(fmt.Sprintf(
+				"profiles:\n  default:\n    unicode_emojis: true\n    max_total: %d\n", tt.maxTotal)), 0600))
+
+			rootCmd := &cobra.Command{Use: "antimoji"}
+			rootCmd.PersistentFlags().String("config", configFile, "config file path")
+			rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+			scan := NewScanHandler(logging.NewMockLogger(), quietOutput())
+			scanCmd := scan.CreateCommand()
+			rootCmd.AddCommand(scanCmd)
+			scanOpts := &ScanOptions{Recursive: true, Format: "table"}
+			checkOpts := &CheckOptions{Recursive: true, ConfigFile: configFile}
+			if tt.threshold != nil {
+				require.NoError(t, scanCmd.Flags().Set("threshold", strconv.Itoa(*tt.threshold)))
+				scanOpts.Threshold = *tt.threshold
+				checkOpts.Threshold, checkOpts.ThresholdSet = *tt.threshold, true
+			}
+
+			err := scan.Execute(context.Background(), scanCmd, []string{dir}, scanOpts)
+			assert.Equal(t, tt.scanFails, errors.Is(err, ErrEmojiThresholdExceeded), "scan: %v", err)
+
+			err = NewCheckHandler(logging.NewMockLogger(), quietOutput()).WithOutput(io.Discard).
+				Execute(context.Background(), []string{dir}, checkOpts)
+			assert.Equal(t, tt.checkFails, errors.Is(err, ErrEmojiThresholdExceeded), "check: %v", err)
+
+			if tt.threshold == nil {
+				var response ScanResponse
+				postJSON(t, newServeAPI(t, &ServeOptions{ConfigFile: configFile}), "/scan",
+					`{"path": "main.go", "content": "// 🚀 🎉"}`, &response)
+				assert.Equal(t, tt.serveFails, !response.Passed, "serve")
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// stderrIsTerminal reports whether stderr, where progress is drawn, is a terminal.
+// Tests replace it.
+var stderrIsTerminal = func() bool {
+	fd := os.Stderr.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// progressAllowed reports whether cmd may draw progress when its profile shows progress:
+// only on a terminal, and not in quiet mode or while JSON logs go to stderr.
+func progressAllowed(cmd *cobra.Command, out ui.UserOutput) bool {
+	if !out.IsLevelEnabled(ui.OutputNormal) {
+		return false
+	}
+	if cmd != nil {
+		flags := cmd.Root().PersistentFlags()
+		if quiet, _ := flags.GetBool("quiet"); quiet {
+			return false
+		}
+		logLevel, _ := flags.GetString("log-level")
+		logFormat, _ := flags.GetString("log-format")
+		if logLevel != "" && logLevel != "silent" && logFormat == "json" {
+			return false
+		}
+	}
+	return stderrIsTerminal()
+}
+
+// newProgress returns the reporter for a batch of total files, or nil when disabled.
+func newProgress(enabled bool, total int) *ui.ProgressReporter {
+	if !enabled {
+		return nil
+	}
+	return ui.NewProgressReporter(os.Stderr, total)
+}
+
+// progressFunc returns the processing hook feeding reporter, nil when reporter is nil.
+func progressFunc(reporter *ui.ProgressReporter) processor.ProgressFunc {
+	if reporter == nil {
+		return nil
+	}
+	return reporter.FileDone
+}
+
+// finishProgress clears the line of reporter, if any.
+func finishProgress(reporter *ui.ProgressReporter) {
+	if reporter != nil {
+		reporter.Finish()
+	}
+}
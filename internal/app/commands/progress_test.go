@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressAllowed(t *testing.T) {
+	original := stderrIsTerminal
+	t.Cleanup(func() { stderrIsTerminal = original })
+
+	newCmd := func(args ...string) *cobra.Command {
+		root := &cobra.Command{Use: "antimoji"}
+		root.PersistentFlags().BoolP("quiet", "q", false, "")
+		root.PersistentFlags().String("log-level", "silent", "")
+		root.PersistentFlags().String("log-format", "json", "")
+		assert.NoError(t, root.PersistentFlags().Parse(args))
+		return root
+	}
+
+	tests := []struct {
+		name     string
+		terminal bool
+		args     []string
+		level    ui.OutputLevel
+		expected bool
+	}{
+		{"terminal", true, nil, ui.OutputNormal, true},
+		{"not a terminal", false, nil, ui.OutputNormal, false},
+		{"quiet", true, []string{"--quiet"}, ui.OutputNormal, false},
+		{"silent output", true, nil, ui.OutputSilent, false},
+		{"json logs", true, []string{"--log-level=info"}, ui.OutputNormal, false},
+		{"text logs", true, []string{"--log-level=info", "--log-format=text"}, ui.OutputNormal, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderrIsTerminal = func() bool { return tt.terminal }
+			out := ui.NewUserOutput(&ui.Config{Level: tt.level})
+			assert.Equal(t, tt.expected, progressAllowed(newCmd(tt.args...), out))
+		})
+	}
+
+	assert.Nil(t, newProgress(false, 10))
+	assert.Nil(t, progressFunc(nil))
+	finishProgress(nil)
+}
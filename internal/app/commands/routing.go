@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// routeRules routes the files the rules of cfg match to the engines of their profiles,
+// resolved like engine's profile, the selected one named profileName, with the
+// variables of environ. The routed engines take opts; their threshold is the profile's
+// max_total unless thresholdSet, when --threshold applies to them too.
+func routeRules(ctx context.Context, engine *policy.Engine, cfg config.Config, fromFile bool, profileName string,
+	opts policy.Options, thresholdSet bool, environ []string) error {
+
+	if profileName == "" {
+		profileName = "default"
+	}
+	err := engine.RouteRules(ctx, profileName, cfg.Rules, func(name string) (config.Profile, policy.Options, error) {
+		profileResult := config.GetProfile(cfg, name)
+		if profileResult.IsErr() {
+			return config.Profile{}, opts, fmt.Errorf("rules: %w", profileResult.Error())
+		}
+		resolution, err := resolveProfileEnv(profileResult.Unwrap(), fromFile, nil, environ)
+		if err != nil {
+			return config.Profile{}, opts, err
+		}
+		routedOpts := opts
+		routedOpts.Rules = resolution.Policy.Rules()
+		if !thresholdSet {
+			routedOpts.Threshold = policy.ResolveThreshold(0, false, profileThreshold(resolution), policy.NoThreshold)
+		} else if err := lockedThresholdError(resolution, opts.Threshold); err != nil {
+			return config.Profile{}, opts, err
+		}
+		return resolution.Profile, routedOpts, nil
+	})
+	if err != nil {
+		return classify(ErrConfig, err)
+	}
+	return nil
+}
+
+// detectRouted detects the emojis in files with the patterns and detection settings of
+// the profile each file is routed to, and returns the results in file order.
+func detectRouted(ctx context.Context, engine *policy.Engine, files []string) ([]types.ProcessResult, error) {
+	router := engine.Router()
+	var results []types.ProcessResult
+	for _, group := range router.Group(files) {
+		patterns, err := group.Engine.Patterns(ctx)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, processor.ProcessFiles(group.Files, patterns, group.Engine.ProcessingConfig())...)
+	}
+	if router.Routes() {
+		results = inFileOrder(results, files)
+	}
+	return results, nil
+}
+
+// evaluateRouted checks the violations in results against the threshold and budgets of
+// the profile each file is routed to, counting only error-level findings, and joins
+// what is exceeded.
+func evaluateRouted(engine *policy.Engine, results []types.ProcessResult) error {
+	router := engine.Router()
+	var violations []error
+	for _, group := range router.GroupResults(engine.Apply(results)) {
+		errorsFound, _ := policy.CountSeverities(group.Results)
+		violation := group.Engine.Evaluate(errorsFound)
+		if violation == nil {
+			violation = policy.BudgetError(group.Engine.Budgets(group.Results))
+		}
+		if violation != nil && router.Routes() {
+			violation = fmt.Errorf("profile %s: %w", group.Profile, violation)
+		}
+		if violation != nil {
+			violations = append(violations, violation)
+		}
+	}
+	return errors.Join(violations...)
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutingRulesApplyToEveryCommand(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+	require.NoError(t, os.MkdirAll("docs", 0755))
+	guide := filepath.Join("docs", "a.md")
+	require.NoError(t, os.WriteFile(guide, []byte("# Release 🎉\n"), 0600))
+	require.NoError(t, os.WriteFile("main.go", []byte("package main\n"), 0600))
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`rules:
+  - paths: ["docs/**"]
+    profile: permissive
+profiles:
+  default:
+    unicode_emojis: true
+  permissive:
+    unicode_emojis: true
+    emoji_allowlist: ["🎉"]
+`), 0600))
+
+	rootCmd := &cobra.Command{Use: "antimoji"}
+	rootCmd.PersistentFlags().String("config", configFile, "config file path")
+	rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+	scanHandler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+	scanCmd := scanHandler.CreateCommand()
+	rootCmd.AddCommand(scanCmd)
+	assert.NoError(t, scanHandler.Execute(context.Background(), scanCmd, []string{"."}, &ScanOptions{Recursive: true, Format: "table"}))
+
+	var lintOut bytes.Buffer
+	err = NewLintHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&lintOut).
+		Execute(context.Background(), []string{"."}, &LintOptions{Recursive: true, Format: DefaultLintFormat, ConfigFile: configFile})
+	assert.NoError(t, err)
+	assert.Empty(t, lintOut.String())
+
+	err = NewCheckHandler(logging.NewMockLogger(), quietOutput()).WithOutput(io.Discard).
+		Execute(context.Background(), []string{"."}, &CheckOptions{Recursive: true, ConfigFile: configFile})
+	assert.NoError(t, err)
+
+	clean := func(opts CleanOptions) error {
+		opts.Recursive, opts.ConfigFile = true, configFile
+		return NewCleanHandler(logging.NewMockLogger(), quietOutput()).WithOutput(io.Discard).
+			Execute(context.Background(), []string{"."}, &opts)
+	}
+	assert.NoError(t, clean(CleanOptions{Check: true}))
+	require.NoError(t, clean(CleanOptions{InPlace: true}))
+	content, err := os.ReadFile(guide)
+	require.NoError(t, err)
+	assert.Equal(t, "# Release 🎉\n", string(content))
+
+	// Files outside the rules keep the selected profile
+	require.NoError(t, os.WriteFile("main.go", []byte("// 🎉\n"), 0600))
+	lintOut.Reset()
+	err = NewLintHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&lintOut).
+		Execute(context.Background(), []string{"."}, &LintOptions{Recursive: true, Format: DefaultLintFormat, ConfigFile: configFile})
+	assert.ErrorIs(t, err, ErrViolations)
+	assert.Equal(t, "main.go:1:4: emoji 🎉 is not allowed [unicode]\n", lintOut.String())
+}
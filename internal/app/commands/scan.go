@@ -5,19 +5,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/antimoji/antimoji/internal/config"
-	"github.com/antimoji/antimoji/internal/core/allowlist"
-	"github.com/antimoji/antimoji/internal/core/detector"
 	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/analysis"
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/infra/daemon"
 	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/infra/git"
+	"github.com/antimoji/antimoji/internal/infra/history"
+	"github.com/antimoji/antimoji/internal/infra/report"
 	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
 	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/antimoji/antimoji/internal/observability/tracing"
+	"github.com/antimoji/antimoji/internal/policy"
 	"github.com/antimoji/antimoji/internal/types"
 	"github.com/antimoji/antimoji/internal/ui"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ScanOptions holds the options for the scan command.
@@ -32,15 +43,63 @@ type ScanOptions struct {
 	Stats           bool
 	Benchmark       bool
 	Workers         int
+	RevRange        string
+	CommitMessages  string
+	Cache           bool
+	CacheDir        string
+	IncludeNames    bool
+	Output          string
+	ReportFile      string
+	ReportSourceURL string
+	Top             int
+	Record          bool
+	HistoryFile     string
+	Verbose         bool
+	ViaDaemon       bool
+	DaemonSocket    string
+	TUI             bool
+	MaxErrors       int
+	ErrorReport     string
+	GroupBy         string
+	Workspace       string
+	Image           string
+	SummaryFile     string
+	ExpectSummary   string
+	Baseline        string
+	NoNotify        bool
+	ChangedSince    string
 }
 
+// defaultReportFile is the report written by --output html when --report-file is not given.
+const defaultReportFile = "antimoji-report.html"
+
+// defaultCodeClimateFile is the report written by --output codeclimate when --report-file
+// is not given; GitLab's code quality examples use this name.
+const defaultCodeClimateFile = "gl-code-quality-report.json"
+
+// defaultFindingsFile is the report written by --output findings-json when --report-file
+// is not given.
+const defaultFindingsFile = "antimoji-findings.json"
+
+// defaultSARIFFile is the report written by --output sarif when --report-file is not
+// given.
+const defaultSARIFFile = "antimoji.sarif"
+
 // ErrEmojiThresholdExceeded indicates the total emoji count exceeded the provided threshold.
-var ErrEmojiThresholdExceeded = errors.New("emoji threshold exceeded")
+var ErrEmojiThresholdExceeded = policy.ErrThresholdExceeded
 
 // ScanHandler handles the scan command with dependency injection.
 type ScanHandler struct {
-	logger logging.Logger
-	ui     ui.UserOutput
+	logger  logging.Logger
+	ui      ui.UserOutput
+	metrics *metrics.Metrics
+	out     io.Writer
+	errOut  io.Writer
+
+	// warm is set when scans run inside the daemon
+	warm *warmState
+	// environ is the environment of the scan, os.Environ() when nil
+	environ []string
 }
 
 // NewScanHandler creates a new scan command handler.
@@ -51,6 +110,20 @@ func NewScanHandler(logger logging.Logger, ui ui.UserOutput) *ScanHandler {
 	}
 }
 
+// WithMetrics sets the metrics that scans are recorded in (defaults to none).
+func (h *ScanHandler) WithMetrics(m *metrics.Metrics) *ScanHandler {
+	h.metrics = m
+	return h
+}
+
+// WithOutput sets the writers that output relayed from the daemon goes to (defaults
+// to stdout and stderr).
+func (h *ScanHandler) WithOutput(out, errOut io.Writer) *ScanHandler {
+	h.out = out
+	h.errOut = errOut
+	return h
+}
+
 // CreateCommand creates the scan cobra command.
 func (h *ScanHandler) CreateCommand() *cobra.Command {
 	opts := &ScanOptions{}
@@ -70,11 +143,30 @@ Examples:
   antimoji scan --recursive src/     # Scan directory recursively
   antimoji scan --format table .    # Output results as a table
   antimoji scan --count-only .       # Show only emoji counts
-  antimoji scan --stats .            # Include performance statistics`,
+  antimoji scan --stats .            # Include performance statistics
+  antimoji scan --rev-range v1.0..HEAD  # Report emojis introduced by each commit
+  antimoji scan --commit-messages main..HEAD  # Scan commit messages in a range
+  antimoji scan --cache .            # Skip files unchanged since the last cached scan
+  antimoji scan --changed-since 24h .   # Only the files changed in the last day
+  antimoji scan --changed-since main .  # Only the files that differ from main
+  antimoji scan --include-names .    # Also report emojis in file and directory names
+  antimoji scan --output=html --report-file report.html .  # Write a shareable HTML report
+  antimoji scan --output=codeclimate .                     # GitLab code quality report
+  antimoji scan --output=findings-json .                   # Findings with stable fingerprints for tracking tools
+  antimoji scan --top 20 .           # Rank the files and packages with the most emojis per KLOC
+  antimoji scan --record .           # Append a summary to .antimoji/history.jsonl for 'antimoji trend'
+  antimoji scan --via-daemon .       # Scan through a running 'antimoji daemon'
+  antimoji scan --tui .              # Browse, clean and allow the findings interactively
+  antimoji scan --max-errors 50 --error-report errors.json .  # Stop early on broken trees, listing the failures
+  antimoji scan --workspace workspace.yaml  # Scan several roots, each with its own config and profile
+  antimoji scan --expect-summary .antimoji-summary.json .  # Check what a clean --summary-file run left behind
+  antimoji scan --baseline antimoji-findings.json .  # Limit the findings an earlier findings-json report lacks to max_new
+  antimoji scan --image ghcr.io/org/app:tag  # Scan the text files in the layers of a container image`,
 		Args:          cobra.MinimumNArgs(0),
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Verbose, _ = cmd.Root().PersistentFlags().GetBool("verbose")
 			return h.Execute(cmd.Context(), cmd, args, opts)
 		},
 	}
@@ -85,19 +177,59 @@ Examples:
 	cmd.Flags().StringVar(&opts.ExcludePattern, "exclude", "", "exclude file patterns (glob)")
 	cmd.Flags().StringVar(&opts.Format, "format", "table", "output format (table, json, csv)")
 	cmd.Flags().BoolVar(&opts.CountOnly, "count-only", false, "show only emoji counts")
-	cmd.Flags().IntVar(&opts.Threshold, "threshold", 0, "maximum allowed emoji count (for linting)")
-	cmd.Flags().BoolVar(&opts.IgnoreAllowlist, "ignore-allowlist", false, "ignore configured emoji allowlist")
+	cmd.Flags().IntVar(&opts.Threshold, "threshold", 0, "number of emojis tolerated (0 = none, -1 = no limit; default: the profile's max_total, or no limit)")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
 	cmd.Flags().BoolVar(&opts.Stats, "stats", false, "show performance statistics")
 	cmd.Flags().BoolVar(&opts.Benchmark, "benchmark", false, "run in benchmark mode with detailed metrics")
 	cmd.Flags().IntVar(&opts.Workers, "workers", 0, "number of concurrent workers (0 = auto-detect)")
+	cmd.Flags().StringVar(&opts.RevRange, "rev-range", "", "scan lines added by commits in a git revision range (e.g. v1.0..HEAD)")
+	cmd.Flags().StringVar(&opts.CommitMessages, "commit-messages", "", "scan commit messages in a git revision range (e.g. main..HEAD)")
+	cmd.Flags().StringVar(&opts.ChangedSince, "changed-since", "", "only scan files changed since a duration ago (24h, 7d), a time (2024-05-01) or a commit")
+	cmd.Flags().BoolVar(&opts.Cache, "cache", false, "reuse cached results for files whose content is unchanged")
+	cmd.Flags().StringVar(&opts.CacheDir, "cache-dir", "", "cache directory (default $ANTIMOJI_CACHE_DIR or the user cache directory)")
+	cmd.Flags().BoolVar(&opts.IncludeNames, "include-names", false, "also check file and directory names for emojis")
+	cmd.Flags().StringVar(&opts.Output, "output", "", "also write a report in this format (html, codeclimate, findings-json, sarif)")
+	cmd.Flags().StringVar(&opts.ReportFile, "report-file", "", "path of the --output report (default "+defaultReportFile+", "+defaultCodeClimateFile+", "+defaultFindingsFile+" or "+defaultSARIFFile+")")
+	cmd.Flags().StringVar(&opts.ReportSourceURL, "report-source-url", "", "URL prefix for source links in the report (e.g. https://github.com/org/repo/blob/main/)")
+	cmd.Flags().IntVar(&opts.Top, "top", 0, "show the N files and packages with the most emojis per thousand lines")
+	cmd.Flags().BoolVar(&opts.Record, "record", false, "append a summary of the scan to the history file for 'antimoji trend'")
+	cmd.Flags().StringVar(&opts.HistoryFile, "history-file", history.DefaultPath, "history file written by --record")
+	cmd.Flags().BoolVar(&opts.ViaDaemon, "via-daemon", false, "run the scan in a running 'antimoji daemon', scanning in-process when none is running")
+	cmd.Flags().StringVar(&opts.DaemonSocket, "daemon-socket", "", "socket of the daemon for --via-daemon (default $"+daemon.EnvSocket+" or one derived from the working directory)")
+	cmd.Flags().BoolVar(&opts.TUI, "tui", false, "browse the findings in an interactive terminal UI to clean, allow or edit them")
+	cmd.Flags().IntVar(&opts.MaxErrors, "max-errors", 0, "stop after this many files could not be processed (0 = never)")
+	cmd.Flags().StringVar(&opts.ErrorReport, "error-report", "", "write the files that could not be processed, with the reasons, to this JSON file")
+	cmd.Flags().StringVar(&opts.GroupBy, "group-by", "", "also total the findings per group (owner: the owners in CODEOWNERS)")
+	cmd.Flags().StringVar(&opts.Image, "image", "", "scan the files in a container image: a docker save or OCI tarball, or a reference pulled with docker")
+	cmd.Flags().StringVar(&opts.SummaryFile, "summary-file", "", "write a JSON summary of the run (files scanned, violations remaining, duration) to this file")
+	cmd.Flags().StringVar(&opts.ExpectSummary, "expect-summary", "", "fail unless the files scanned and violations remaining match this summary file, e.g. one written by clean")
+	cmd.Flags().StringVar(&opts.Baseline, "baseline", "", "findings-json report of an earlier scan; the profile's max_new limits the violations it does not list")
+	cmd.Flags().BoolVar(&opts.NoNotify, "no-notify", false, "do not send the notifications the profile lists")
+	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "scan the roots listed in this workspace file, each with its own config and profile")
 
 	return cmd
 }
 
 // Execute runs the scan command logic with dependency injection.
-func (h *ScanHandler) Execute(parentCtx context.Context, cmd *cobra.Command, args []string, opts *ScanOptions) error {
+func (h *ScanHandler) Execute(parentCtx context.Context, cmd *cobra.Command, args []string, opts *ScanOptions) (err error) {
 	startTime := time.Now()
 
+	if opts.Image != "" {
+		if err := imageConflictError(opts, args); err != nil {
+			return err
+		}
+	}
+	if opts.Workspace != "" {
+		if err := workspaceConflictError(opts, args); err != nil {
+			return err
+		}
+	}
+	if opts.ViaDaemon && !opts.TUI {
+		if handled, err := h.executeViaDaemon(parentCtx, cmd, args, opts); handled {
+			return err
+		}
+	}
+
 	// Validate output format (table-only for now)
 	switch strings.ToLower(opts.Format) {
 	case "table":
@@ -106,6 +238,58 @@ func (h *ScanHandler) Execute(parentCtx context.Context, cmd *cobra.Command, arg
 		return fmt.Errorf("unsupported format %q; supported: table", opts.Format)
 	}
 
+	if opts.RevRange != "" && opts.CommitMessages != "" {
+		return fmt.Errorf("--rev-range and --commit-messages cannot be used together")
+	}
+	if opts.ChangedSince != "" && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--changed-since cannot be used with --rev-range or --commit-messages")
+	}
+	if opts.IncludeNames && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--include-names cannot be used with --rev-range or --commit-messages")
+	}
+	switch strings.ToLower(opts.Output) {
+	case "", "html", "codeclimate", "findings-json", "sarif":
+		// ok
+	default:
+		return fmt.Errorf("unsupported output %q; supported: html, codeclimate, findings-json, sarif", opts.Output)
+	}
+	if opts.Output != "" && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--output cannot be used with --rev-range or --commit-messages")
+	}
+	if opts.Record && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--record cannot be used with --rev-range or --commit-messages")
+	}
+	if opts.TUI && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--tui cannot be used with --rev-range or --commit-messages")
+	}
+	if opts.MaxErrors < 0 {
+		return fmt.Errorf("--max-errors must be non-negative")
+	}
+	if (opts.MaxErrors > 0 || opts.ErrorReport != "") && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--max-errors and --error-report cannot be used with --rev-range or --commit-messages")
+	}
+	if (opts.SummaryFile != "" || opts.ExpectSummary != "") && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--summary-file and --expect-summary cannot be used with --rev-range or --commit-messages")
+	}
+	switch strings.ToLower(opts.GroupBy) {
+	case "", groupByOwner:
+		// ok
+	default:
+		return fmt.Errorf("unsupported --group-by %q; supported: %s", opts.GroupBy, groupByOwner)
+	}
+	if opts.GroupBy != "" && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--group-by cannot be used with --rev-range or --commit-messages")
+	}
+	if opts.Top < 0 {
+		return fmt.Errorf("--top must be non-negative")
+	}
+	if opts.Top > 0 && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--top cannot be used with --rev-range or --commit-messages")
+	}
+	if opts.Baseline != "" && (opts.RevRange != "" || opts.CommitMessages != "") {
+		return fmt.Errorf("--baseline cannot be used with --rev-range or --commit-messages")
+	}
+
 	// Derive from parent for cancellation/values, enhance with component context
 	ctx := parentCtx
 	if ctx == nil {
@@ -114,28 +298,37 @@ func (h *ScanHandler) Execute(parentCtx context.Context, cmd *cobra.Command, arg
 	ctx = ctxutil.WithOperation(ctx, "scan")
 	ctx = ctxutil.WithComponent(ctx, "cli")
 
+	if opts.Workspace != "" {
+		return h.scanWorkspace(ctx, cmd, opts, startTime)
+	}
+
 	// If no paths provided, use current directory
 	if len(args) == 0 {
 		args = []string{"."}
 		h.logger.Debug(ctx, "No paths provided, using current directory")
 	}
 
+	ctx, span := tracing.Start(ctx, "scan", attribute.StringSlice("antimoji.paths", args))
+	defer func() { tracing.End(span, err) }()
+
 	h.logger.Info(ctx, "Starting scan operation", "paths", args, "options", opts)
 
 	// Get config and profile from persistent flags
 	configFile, _ := cmd.Root().PersistentFlags().GetString("config")
 	profileName, _ := cmd.Root().PersistentFlags().GetString("profile")
+	strictConfig, _ := cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
 
 	// Load configuration
 	cfg := config.DefaultConfig()
 	if configFile != "" {
 		h.logger.Debug(ctx, "Loading configuration file", "config_file", configFile)
-		configResult := config.LoadConfig(configFile)
+		configResult := h.loadConfig(configFile, profileName, strictConfig)
 		if configResult.IsErr() {
 			h.logger.Error(ctx, "Failed to load configuration", "config_file", configFile, "error", configResult.Error())
 			return fmt.Errorf("failed to load config: %w", configResult.Error())
 		}
 		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
 		h.logger.Debug(ctx, "Configuration loaded successfully")
 	}
 
@@ -144,40 +337,90 @@ func (h *ScanHandler) Execute(parentCtx context.Context, cmd *cobra.Command, arg
 	if profileResult.IsErr() {
 		return fmt.Errorf("failed to get profile '%s': %w", profileName, profileResult.Error())
 	}
-	profile := profileResult.Unwrap()
+	// Apply ANTIMOJI_* environment and --set overrides
+	sets, _ := cmd.Root().PersistentFlags().GetStringArray(setFlag)
+	resolution, err := resolveProfileEnv(profileResult.Unwrap(), configFile != "", sets, h.env())
+	if err != nil {
+		return err
+	}
+	profile := resolution.Profile
 
 	h.logger.Debug(ctx, "Profile loaded successfully", "profile_name", profileName)
 
-	// Create allowlist for processing
-	allowlistOpts := allowlist.ProcessingOptions{
-		IgnoreAllowlist:  opts.IgnoreAllowlist,
-		RespectAllowlist: !opts.IgnoreAllowlist,
-		Operation:        "scan",
+	var baseline map[string]bool
+	if opts.Baseline != "" {
+		findings, err := report.ReadFindingsFile(opts.Baseline)
+		if err != nil {
+			return classify(ErrIO, fmt.Errorf("failed to read baseline: %w", err))
+		}
+		baseline = findings.Fingerprints()
+		h.logger.Debug(ctx, "Baseline loaded", "baseline", opts.Baseline, "findings", len(baseline))
 	}
 
-	emojiAllowlist, err := allowlist.CreateAllowlistForProcessing(ctx, profile, allowlistOpts)
+	// The policy engine decides which files are checked and what counts as a violation;
+	// the profile's max_total applies when --threshold is not given
+	threshold := policy.ResolveThreshold(opts.Threshold, thresholdGiven(cmd, opts), profileThreshold(resolution), policy.NoThreshold)
+	if thresholdGiven(cmd, opts) {
+		if err := lockedThresholdError(resolution, threshold); err != nil {
+			return err
+		}
+	}
+	policyOpts := policy.Options{
+		Operation:       "scan",
+		Recursive:       opts.Recursive,
+		IncludePattern:  opts.IncludePattern,
+		ExcludePattern:  opts.ExcludePattern,
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       threshold,
+		Baseline:        baseline,
+		Rules:           resolution.Policy.Rules(),
+	}
+	engine, err := policy.New(ctx, profile, policyOpts)
 	if err != nil {
-		h.logger.Error(ctx, "Failed to create allowlist", "error", err)
-		return fmt.Errorf("failed to create allowlist: %w", err)
+		h.logger.Error(ctx, "Failed to create policy", "error", err)
+		return err
+	}
+	h.logger.Debug(ctx, "Policy created", "should_use_allowlist", engine.Allowlist() != nil)
+
+	// The config's rules route files to other profiles
+	if err := routeRules(ctx, engine, cfg, configFile != "", profileName, policyOpts, thresholdGiven(cmd, opts), h.env()); err != nil {
+		return err
 	}
+	router := engine.Router()
 
-	shouldUseAllowlist := emojiAllowlist != nil
-	h.logger.Debug(ctx, "Allowlist created", "should_use_allowlist", shouldUseAllowlist)
+	// Scan git history instead of the working tree when a revision range is given
+	if opts.RevRange != "" {
+		return h.scanRevRange(ctx, args, opts, engine)
+	}
+	if opts.CommitMessages != "" {
+		return h.scanCommitMessages(ctx, opts, engine)
+	}
+	if opts.Image != "" {
+		return h.scanImage(ctx, opts, engine)
+	}
 
 	// Start file discovery
 	h.logger.Debug(ctx, "Starting file discovery", "paths", args, "recursive", opts.Recursive)
 
-	// Discover files
-	discoveryOptions := filtering.DiscoveryOptions{
-		Recursive:      opts.Recursive,
-		IncludePattern: opts.IncludePattern,
-		ExcludePattern: opts.ExcludePattern,
-	}
-
-	filePaths, err := filtering.DiscoverFiles(args, discoveryOptions, profile)
+	_, discoverySpan := tracing.Start(ctx, "discovery")
+	discovery, err := engine.SelectFiles(args)
+	filePaths := discovery.Files
+	discoverySpan.SetAttributes(attribute.Int("antimoji.files", len(filePaths)))
+	tracing.End(discoverySpan, err)
 	if err != nil {
 		h.logger.Error(ctx, "File discovery failed", "error", err, "paths", args)
-		return fmt.Errorf("file discovery failed: %w", err)
+		return classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	reportSkippedSymlinks(ctx, h.ui, discovery.Symlinks)
+
+	if opts.ChangedSince != "" && len(filePaths) > 0 {
+		if filePaths, err = h.changedFiles(ctx, filePaths, opts); err != nil {
+			return err
+		}
+		if len(filePaths) == 0 {
+			h.ui.Info(ctx, "No files changed since %s", opts.ChangedSince)
+			return nil
+		}
 	}
 
 	if len(filePaths) == 0 {
@@ -188,82 +431,303 @@ func (h *ScanHandler) Execute(parentCtx context.Context, cmd *cobra.Command, arg
 	h.logger.Info(ctx, "File discovery completed", "files_found", len(filePaths), "paths", args)
 
 	// Create processing configuration
-	processingConfig := config.ToProcessingConfig(profile)
+	processingConfig := engine.ProcessingConfig()
 	h.logger.Debug(ctx, "Processing configuration created", "config", processingConfig)
 
 	// Create emoji patterns
-	patterns := detector.DefaultEmojiPatterns()
+	patterns, err := h.patterns(ctx, engine)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to load supplemental emoji data", "error", err)
+		return err
+	}
 	h.logger.Debug(ctx, "Emoji patterns created", "unicode_ranges", len(patterns.UnicodeRanges))
 
 	// Process files
 	h.logger.Info(ctx, "Starting file processing", "total_files", len(filePaths))
-	results := processor.ProcessFiles(filePaths, patterns, processingConfig)
+	_, detectionSpan := tracing.Start(ctx, "detection", attribute.Int("antimoji.files", len(filePaths)))
+	// The daemon has no terminal of its own to draw progress on
+	progress := newProgress(h.warm == nil && profile.ShowProgress && progressAllowed(cmd, h.ui), len(filePaths))
+	var results []types.ProcessResult
+	var usage cacheUsage
+	for _, group := range router.Group(filePaths) {
+		groupPatterns, groupConfig := patterns, processingConfig
+		if group.Engine != engine {
+			if groupPatterns, err = h.patterns(ctx, group.Engine); err != nil {
+				finishProgress(progress)
+				return err
+			}
+			groupConfig = group.Engine.ProcessingConfig()
+			h.logger.Debug(ctx, "Files routed to profile", "profile", group.Profile, "files", len(group.Files))
+		}
+		groupResults, groupUsage := h.detect(ctx, group.Files, groupPatterns, groupConfig, progress, opts)
+		results = append(results, groupResults...)
+		usage.add(groupUsage)
+	}
+	finishProgress(progress)
+	if router.Routes() {
+		results = inFileOrder(results, filePaths)
+	}
+	traceDetection(detectionSpan, results)
+	detectionSpan.End()
 	h.logger.Info(ctx, "File processing completed", "total_results", len(results))
+	h.metrics.ObserveResults(results)
+	if opts.Verbose {
+		for _, result := range results {
+			reportBinaryFile(ctx, h.ui, result.FilePath, result.BinaryReason)
+			reportSuppressedRegions(ctx, h.ui, result.FilePath, result.DetectionResult.SuppressedRegions)
+		}
+	}
+
+	if usage.used {
+		h.metrics.ObserveCache(usage.hits, usage.misses)
+		h.logger.Info(ctx, "Result cache used", "hits", usage.hits, "misses", usage.misses)
+		if opts.Stats {
+			h.ui.Info(ctx, "Cache hits: %d, misses: %d", usage.hits, usage.misses)
+		}
+	}
 
-	// Filter results through allowlist if configured
-	if shouldUseAllowlist {
+	// Reduce detections to policy violations, leaving out allowlisted and exempted
+	// findings and marking warnings, under the profile each file is routed to
+	if engine.Applies() {
 		h.logger.Debug(ctx, "Applying allowlist filtering to results")
-		results = h.filterResultsThroughAllowlist(ctx, results, emojiAllowlist)
+		_, allowlistSpan := tracing.Start(ctx, "allowlist")
+		results = engine.Apply(results)
+		allowlistSpan.End()
 		h.logger.Debug(ctx, "Allowlist filtering completed")
 	}
 
+	// Browse the violations instead of printing them
+	if opts.TUI {
+		return h.browseResults(ctx, results, patterns, engine, configFile, profileName)
+	}
+
 	// Display results
 	if err := h.displayResults(ctx, results, opts, time.Since(startTime)); err != nil {
 		h.logger.Error(ctx, "Failed to display results", "error", err)
-		return fmt.Errorf("failed to display results: %w", err)
+		return classify(ErrIO, fmt.Errorf("failed to display results: %w", err))
+	}
+
+	// Summarize the failures and list them for --error-report
+	if err := reportFailures(ctx, h.ui, newErrorReport("scan", len(results), scanFailures(results)), opts.MaxErrors, opts.ErrorReport); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(opts.GroupBy, groupByOwner) {
+		groups, err := engine.GroupByOwner(results)
+		if err != nil {
+			return fmt.Errorf("--group-by owner: %w", err)
+		}
+		displayOwnerGroups(ctx, h.ui, groups)
+	}
+
+	if opts.Top > 0 {
+		displayHotSpots(ctx, h.ui, analysis.AnalyzeDensity(results).Top(opts.Top))
+	}
+
+	// Check file and directory names
+	var nameFindings []NameFinding
+	if opts.IncludeNames {
+		nameFindings = findEmojiNames(namedPaths(args, filePaths), patterns, processingConfig, engine.Allowlist())
+		h.logger.Info(ctx, "Name check completed", "names_with_emojis", len(nameFindings))
+		displayNameFindings(ctx, h.ui, nameFindings)
+	}
+
+	// Write the report before the threshold check so failing CI runs still produce it
+	if opts.Output != "" {
+		if err := h.writeReport(ctx, results, opts); err != nil {
+			return err
+		}
+	}
+
+	// Record the scan before the threshold check so failing runs are tracked too
+	if opts.Record {
+		if err := h.recordHistory(ctx, results, opts.HistoryFile); err != nil {
+			return err
+		}
 	}
 
 	// Check threshold for linting
-	if opts.Threshold > 0 {
-		totalEmojis := h.countTotalEmojis(results)
-		if totalEmojis > opts.Threshold {
-			h.logger.Error(ctx, "Emoji threshold exceeded",
-				"threshold", opts.Threshold,
-				"found", totalEmojis)
-			h.ui.Error(ctx, "Emoji threshold exceeded: found %d emojis, threshold is %d", totalEmojis, opts.Threshold)
-			return fmt.Errorf("%w: found %d emojis (threshold %d)", ErrEmojiThresholdExceeded, totalEmojis, opts.Threshold)
+	totalEmojis := h.countTotalEmojis(results) + countNameEmojis(nameFindings)
+
+	// Write and check the summary before the threshold check, which a mismatch explains
+	summary := scanSummary(results, totalEmojis, time.Since(startTime))
+	if err := reportSummary(ctx, h.ui, summary, opts.SummaryFile); err != nil {
+		return err
+	}
+	var mismatch error
+	if opts.ExpectSummary != "" {
+		mismatch = checkSummary(ctx, h.ui, summary, opts.ExpectSummary)
+		if errors.Is(mismatch, ErrIO) {
+			return mismatch
 		}
 	}
+	// Each group of files is held to the threshold and budgets of its profile
+	var violation error
+	if router.Routes() {
+		violation = h.evaluateRoutes(ctx, router, results, countNameEmojis(nameFindings))
+	} else {
+		violation = h.evaluate(ctx, engine, "", results, totalEmojis)
+	}
+	if violation == nil {
+		violation = mismatch
+	}
+
+	// Notify the profile's sinks of the outcome
+	if len(profile.Notify) > 0 && !opts.NoNotify {
+		passed := violation == nil && countFileFailures(results) == 0
+		h.notify(ctx, profile.Notify, notifySummary(results, profileName, args, passed, time.Since(startTime)))
+	}
+
+	// Files that could not be read make the result incomplete
+	if failed := countFileFailures(results); failed > 0 {
+		h.logger.Error(ctx, "Some files could not be processed", "failed", failed, "total", len(results))
+		return fileFailureError(failed, len(results), violation)
+	}
+	if violation != nil {
+		return violation
+	}
 
 	h.logger.Info(ctx, "Scan operation completed successfully")
 	return nil
 }
 
-// filterResultsThroughAllowlist filters detection results through the allowlist.
-func (h *ScanHandler) filterResultsThroughAllowlist(ctx context.Context, results []types.ProcessResult, allowlist *allowlist.Allowlist) []types.ProcessResult {
-	filtered := make([]types.ProcessResult, 0, len(results))
+// thresholdGiven reports whether --threshold was given. Options built in code count as
+// giving one when they set a threshold other than 0.
+func thresholdGiven(cmd *cobra.Command, opts *ScanOptions) bool {
+	return cmd.Flags().Changed("threshold") || opts.Threshold != 0
+}
+
+// slowFileThreshold is the detection time above which a file is recorded on the trace.
+const slowFileThreshold = 100 * time.Millisecond
 
+// traceDetection records detection totals on span, with an event for each slow file.
+func traceDetection(span trace.Span, results []types.ProcessResult) {
+	if !span.IsRecording() {
+		return
+	}
+
+	emojis, failed := 0, 0
 	for _, result := range results {
 		if result.Error != nil {
-			// Keep error results as-is
-			filtered = append(filtered, result)
+			failed++
 			continue
 		}
+		emojis += result.DetectionResult.TotalCount
+		if result.DetectionResult.Duration >= slowFileThreshold {
+			span.AddEvent("slow file", trace.WithAttributes(
+				attribute.String("antimoji.file", result.FilePath),
+				attribute.Int64("antimoji.duration_ms", result.DetectionResult.Duration.Milliseconds()),
+				attribute.Int64("antimoji.bytes", result.DetectionResult.ProcessedBytes),
+			))
+		}
+	}
+	span.SetAttributes(attribute.Int("antimoji.emojis", emojis), attribute.Int("antimoji.errors", failed))
+}
 
-		// Filter detections through allowlist
-		filteredEmojis := make([]types.EmojiMatch, 0)
-		for _, emoji := range result.DetectionResult.Emojis {
-			if !allowlist.IsAllowed(emoji.Emoji) {
-				filteredEmojis = append(filteredEmojis, emoji)
-			}
+// writeReport writes the --output report for results.
+func (h *ScanHandler) writeReport(ctx context.Context, results []types.ProcessResult, opts *ScanOptions) error {
+	format := strings.ToLower(opts.Output)
+	path := opts.ReportFile
+	if path == "" {
+		switch format {
+		case "codeclimate":
+			path = defaultCodeClimateFile
+		case "findings-json":
+			path = defaultFindingsFile
+		case "sarif":
+			path = defaultSARIFFile
+		default:
+			path = defaultReportFile
 		}
+	}
+
+	var err error
+	switch format {
+	case "codeclimate":
+		err = report.WriteCodeClimateFile(path, results)
+	case "findings-json":
+		err = report.WriteFindingsFile(path, results, time.Now().UTC())
+	case "sarif":
+		err = report.WriteSARIFFile(path, results)
+	default:
+		err = report.WriteHTMLFile(path, report.Build(results, report.Options{
+			SourceURL: opts.ReportSourceURL,
+			ReportDir: filepath.Dir(path),
+		}))
+	}
+	if err != nil {
+		h.logger.Error(ctx, "Failed to write report", "report_file", path, "error", err)
+		return classify(ErrIO, fmt.Errorf("failed to write report: %w", err))
+	}
+
+	h.logger.Info(ctx, "Report written", "report_file", path, "format", opts.Output)
+	switch format {
+	case "codeclimate":
+		h.ui.Success(ctx, "Code Climate report written to %s", path)
+	case "findings-json":
+		h.ui.Success(ctx, "Findings report written to %s", path)
+	case "sarif":
+		h.ui.Success(ctx, "SARIF report written to %s", path)
+	default:
+		h.ui.Success(ctx, "HTML report written to %s", path)
+	}
+	return nil
+}
+
+// recordHistory appends a summary of results, taken at the checked-out commit, to the
+// history file at path.
+func (h *ScanHandler) recordHistory(ctx context.Context, results []types.ProcessResult, path string) error {
+	if path == "" {
+		path = history.DefaultPath
+	}
+	commit, err := git.NewRepository("").Head(ctx)
+	if err != nil {
+		h.logger.Debug(ctx, "Recording scan without a commit", "error", err)
+		commit = ""
+	}
 
-		// Update result with filtered detections
-		newResult := result
-		newResult.DetectionResult.Emojis = filteredEmojis
-		newResult.DetectionResult.TotalCount = len(filteredEmojis)
+	snapshot := history.NewSnapshot(results, time.Now(), commit)
+	if err := history.Append(path, snapshot); err != nil {
+		h.logger.Error(ctx, "Failed to record scan history", "history_file", path, "error", err)
+		return classify(ErrIO, fmt.Errorf("failed to record scan history: %w", err))
+	}
 
-		// Recompute unique count
-		uniq := make(map[string]struct{}, len(filteredEmojis))
-		for _, e := range filteredEmojis {
-			uniq[e.Emoji] = struct{}{}
+	h.logger.Info(ctx, "Scan recorded", "history_file", path, "emojis", snapshot.Emojis, "commit", commit)
+	h.ui.Success(ctx, "Recorded %d emojis in %d files to %s", snapshot.Emojis, snapshot.FilesWithEmojis, path)
+	return nil
+}
+
+// openCache opens the result cache for the current detection configuration. Failures are
+// reported as warnings and disable caching, since the cache is only an optimisation.
+func (h *ScanHandler) openCache(ctx context.Context, dir string, patterns types.EmojiPatterns, processingConfig types.ProcessingConfig) *cache.Cache {
+	if dir == "" {
+		defaultDir, err := cache.DefaultDir()
+		if err != nil {
+			h.ui.Warning(ctx, "Result cache disabled: %v", err)
+			return nil
+		}
+		dir = defaultDir
+	}
+
+	fingerprint := cache.Fingerprint(patterns, processingConfig)
+	if h.warm != nil {
+		if c, ok := h.warm.caches[dir+"\x00"+fingerprint]; ok {
+			return c
 		}
-		newResult.DetectionResult.UniqueCount = len(uniq)
+	}
 
-		filtered = append(filtered, newResult)
+	cacheResult := cache.Open(dir, fingerprint)
+	if cacheResult.IsErr() {
+		h.logger.Warn(ctx, "Failed to open result cache", "cache_dir", dir, "error", cacheResult.Error())
+		h.ui.Warning(ctx, "Result cache disabled: %v", cacheResult.Error())
+		return nil
 	}
 
-	return filtered
+	h.logger.Debug(ctx, "Result cache opened", "cache_dir", dir)
+	if h.warm != nil {
+		h.warm.caches[dir+"\x00"+fingerprint] = cacheResult.Unwrap()
+	}
+	return cacheResult.Unwrap()
 }
 
 // displayResults displays the scan results based on the output options.
@@ -273,6 +737,7 @@ func (h *ScanHandler) displayResults(ctx context.Context, results []types.Proces
 	// Count totals
 	totalFiles := len(results)
 	totalEmojis := h.countTotalEmojis(results)
+	_, warnings := policy.CountSeverities(results)
 	filesWithEmojis := 0
 	errorCount := 0
 
@@ -291,16 +756,31 @@ func (h *ScanHandler) displayResults(ctx context.Context, results []types.Proces
 	// Display summary
 	if opts.CountOnly {
 		h.ui.Result(ctx, "Total emojis found: %d", totalEmojis)
+		if warnings > 0 {
+			h.ui.Result(ctx, "Warnings: %d", warnings)
+		}
 	} else {
 		h.ui.Result(ctx, "Scanned %d files, found %d emojis in %d files (%d errors)",
 			totalFiles, totalEmojis, filesWithEmojis, errorCount)
+		if warnings > 0 {
+			h.ui.Result(ctx, "Severity: %d emojis fail the policy, %d are warnings", totalEmojis-warnings, warnings)
+		}
 
 		// Show detailed results if not count-only
 		for _, result := range results {
+			if errors.Is(result.Error, processor.ErrTooManyErrors) {
+				// Counted in the error summary instead of listed
+				continue
+			}
 			if result.Error != nil {
 				h.ui.Error(ctx, "Error processing %s: %v", result.FilePath, result.Error)
 			} else if result.DetectionResult.TotalCount > 0 {
-				h.ui.Info(ctx, "%s: %d emojis found", result.FilePath, result.DetectionResult.TotalCount)
+				_, fileWarnings := policy.CountSeverities([]types.ProcessResult{result})
+				if fileWarnings > 0 {
+					h.ui.Info(ctx, "%s: %d emojis found (%d warnings)", result.FilePath, result.DetectionResult.TotalCount, fileWarnings)
+				} else {
+					h.ui.Info(ctx, "%s: %d emojis found", result.FilePath, result.DetectionResult.TotalCount)
+				}
 			}
 		}
 	}
@@ -319,6 +799,39 @@ func (h *ScanHandler) displayResults(ctx context.Context, results []types.Proces
 	return nil
 }
 
+// groupByOwner groups the findings of --group-by by CODEOWNERS owner.
+const groupByOwner = "owner"
+
+// displayOwnerGroups shows the findings totalled per owner.
+func displayOwnerGroups(ctx context.Context, output ui.UserOutput, groups []policy.OwnerGroup) {
+	if len(groups) == 0 {
+		return
+	}
+	output.Result(ctx, "Findings by owner:")
+	for _, group := range groups {
+		output.Result(ctx, "  %6d  %s (%d files)", group.Emojis, group.Owner, group.Files)
+	}
+}
+
+// displayHotSpots shows the files and packages ranked by emoji density.
+func displayHotSpots(ctx context.Context, output ui.UserOutput, report analysis.DensityReport) {
+	for _, ranking := range []struct {
+		title   string
+		entries []analysis.Density
+	}{
+		{"files", report.Files},
+		{"packages", report.Packages},
+	} {
+		if len(ranking.entries) == 0 {
+			continue
+		}
+		output.Result(ctx, "Top %d %s by emojis per KLOC:", len(ranking.entries), ranking.title)
+		for _, entry := range ranking.entries {
+			output.Result(ctx, "  %8.1f  %s (%d emojis in %d lines)", entry.PerKLOC, entry.Name, entry.Emojis, entry.Lines)
+		}
+	}
+}
+
 // countTotalEmojis counts the total number of emojis across all results.
 func (h *ScanHandler) countTotalEmojis(results []types.ProcessResult) int {
 	total := 0
@@ -329,3 +842,46 @@ func (h *ScanHandler) countTotalEmojis(results []types.ProcessResult) int {
 	}
 	return total
 }
+
+// reportBinaryFile tells the user a file was skipped as binary and why, so the
+// binary_* profile settings can be tuned. It does nothing for text files.
+func reportBinaryFile(ctx context.Context, output ui.UserOutput, path, reason string) {
+	if reason != "" {
+		output.Info(ctx, "Skipped binary file %s: %s", path, reason)
+	}
+}
+
+// reportSuppressedRegions notes the antimoji:off regions of a file in verbose output.
+func reportSuppressedRegions(ctx context.Context, output ui.UserOutput, path string, regions []types.SuppressedRegion) {
+	for _, region := range regions {
+		output.Info(ctx, "Suppressed region in %s: lines %d-%d (%d emojis)", path, region.StartLine, region.EndLine, region.Suppressed)
+	}
+}
+
+// reportSkippedSymlinks warns about the symlinks discovery did not follow.
+func reportSkippedSymlinks(ctx context.Context, output ui.UserOutput, symlinks []filtering.SkippedSymlink) {
+	for _, link := range symlinks {
+		output.Warning(ctx, "Skipped symlink %s -> %s: %s", link.Path, link.Target, link.Reason)
+	}
+}
+
+// scanOption is a scan option as named on the command line, and whether it was given.
+type scanOption struct {
+	name string
+	set  bool
+}
+
+// conflictError reports the options given with mode, a scan mode such as --workspace
+// that does not support them.
+func conflictError(mode string, options []scanOption) error {
+	var conflicts []string
+	for _, option := range options {
+		if option.set {
+			conflicts = append(conflicts, option.name)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s cannot be used with %s", mode, strings.Join(conflicts, ", "))
+}
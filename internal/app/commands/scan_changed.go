@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/git"
+)
+
+// changedSince is what --changed-since limits a scan to: the files changed since
+// cutoff, or since commit rev when rev is not "".
+type changedSince struct {
+	cutoff time.Time
+	rev    string
+}
+
+// parseChangedSince parses --changed-since: a duration before now such as 24h, 90m
+// or 7d, a time such as 2024-05-01 or 2024-05-01T10:00:00Z, or else a commit.
+func parseChangedSince(value string, now time.Time) (changedSince, error) {
+	if value == "" {
+		return changedSince{}, fmt.Errorf("--changed-since cannot be empty")
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			if n < 0 {
+				return changedSince{}, fmt.Errorf("--changed-since must not be negative")
+			}
+			return changedSince{cutoff: now.AddDate(0, 0, -n)}, nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return changedSince{}, fmt.Errorf("--changed-since must not be negative")
+		}
+		return changedSince{cutoff: now.Add(-d)}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return changedSince{cutoff: t}, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return changedSince{cutoff: t}, nil
+	}
+	return changedSince{rev: value}, nil
+}
+
+// String returns what the files changed since, for messages.
+func (c changedSince) String() string {
+	if c.rev != "" {
+		return c.rev
+	}
+	return c.cutoff.Format(time.RFC3339)
+}
+
+// changedFiles returns the files of filePaths changed since the --changed-since of
+// opts, in order. Since a commit, they are the files that differ from it in the
+// working tree and the untracked ones. Since a time, they are the files committed
+// since then and the uncommitted ones modified since then; outside a git repository,
+// the files modified since then.
+func (h *ScanHandler) changedFiles(ctx context.Context, filePaths []string, opts *ScanOptions) ([]string, error) {
+	since, err := parseChangedSince(opts.ChangedSince, time.Now())
+	if err != nil {
+		return nil, classify(ErrConfig, err)
+	}
+	repo := git.NewRepository("")
+
+	var changed map[string]bool
+	if since.rev != "" {
+		files, err := repo.ChangedFiles(ctx, since.rev)
+		if err != nil {
+			return nil, classify(ErrConfig, fmt.Errorf("--changed-since: %w", err))
+		}
+		changed = pathSet(files)
+	} else if _, err := repo.TopLevel(ctx); err == nil {
+		committed, err := repo.CommittedSince(ctx, since.cutoff)
+		if err != nil {
+			return nil, classify(ErrIO, fmt.Errorf("--changed-since: %w", err))
+		}
+		uncommitted, err := repo.UncommittedFiles(ctx)
+		if err != nil {
+			return nil, classify(ErrIO, fmt.Errorf("--changed-since: %w", err))
+		}
+		changed = pathSet(committed)
+		for _, file := range uncommitted {
+			if modifiedSince(file, since.cutoff) {
+				changed[file] = true
+			}
+		}
+	} else {
+		h.logger.Debug(ctx, "Not in a git repository, comparing modification times", "error", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, classify(ErrIO, fmt.Errorf("failed to get working directory: %w", err))
+	}
+	// git reports paths with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(wd); err == nil {
+		wd = resolved
+	}
+
+	selected := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if changed == nil {
+			if modifiedSince(filePath, since.cutoff) {
+				selected = append(selected, filePath)
+			}
+			continue
+		}
+		abs := filePath
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(wd, abs)
+		}
+		if changed[filepath.Clean(abs)] {
+			selected = append(selected, filePath)
+		}
+	}
+	h.logger.Info(ctx, "Files limited to changes", "since", since.String(), "files", len(selected), "discovered", len(filePaths))
+	return selected, nil
+}
+
+// pathSet returns the set of paths.
+func pathSet(paths []string) map[string]bool {
+	set := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		set[path] = true
+	}
+	return set
+}
+
+// modifiedSince reports whether the file at path was modified at or after cutoff.
+func modifiedSince(path string, cutoff time.Time) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.ModTime().Before(cutoff)
+}
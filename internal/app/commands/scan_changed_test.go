@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChangedSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		value string
+		want  changedSince
+	}{
+		{"24h", changedSince{cutoff: now.Add(-24 * time.Hour)}},
+		{"90m", changedSince{cutoff: now.Add(-90 * time.Minute)}},
+		{"7d", changedSince{cutoff: now.AddDate(0, 0, -7)}},
+		{"2024-05-01T10:00:00Z", changedSince{cutoff: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}},
+		{"2024-05-01", changedSince{cutoff: time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)}},
+		{"main", changedSince{rev: "main"}},
+		{"HEAD~3", changedSince{rev: "HEAD~3"}},
+	} {
+		got, err := parseChangedSince(tt.value, now)
+		require.NoError(t, err, tt.value)
+		assert.True(t, tt.want.cutoff.Equal(got.cutoff), tt.value)
+		assert.Equal(t, tt.want.rev, got.rev, tt.value)
+	}
+
+	for _, value := range []string{"", "-1h", "-2d"} {
+		_, err := parseChangedSince(value, now)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestScanHandler_ChangedSince(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+	old := time.Now().Add(-48 * time.Hour)
+	write := func(name, content string, modified time.Time) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), modified, modified))
+	}
+	write("old.go", "// 🎉\n", old)
+	write("new.go", "// 🚀\n", time.Now())
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+	scan := func(t *testing.T, since string) (string, error) {
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", "", "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+
+		err := handler.Execute(context.Background(), scanCmd, []string{"."}, &ScanOptions{Recursive: true, Format: "table", ChangedSince: since})
+		return out.String(), err
+	}
+
+	t.Run("modification times outside git", func(t *testing.T) {
+		out, err := scan(t, "24h")
+		require.NoError(t, err)
+		assert.Contains(t, out, "new.go")
+		assert.NotContains(t, out, "old.go")
+
+		out, err = scan(t, "1h")
+		require.NoError(t, err)
+		assert.NotContains(t, out, "old.go")
+
+		_, err = scan(t, "main")
+		assert.ErrorIs(t, err, ErrConfig)
+	})
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	gitCmd := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(append(os.Environ(), env...),
+			"GIT_AUTHOR_NAME=Tester", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=Tester", "GIT_COMMITTER_EMAIL=t@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	gitCmd(nil, "init", "-q")
+	gitCmd(nil, "add", ".")
+	gitCmd([]string{"GIT_AUTHOR_DATE=" + old.Format(time.RFC3339), "GIT_COMMITTER_DATE=" + old.Format(time.RFC3339)},
+		"commit", "-q", "-m", "initial")
+	gitCmd(nil, "tag", "v1.0")
+	write("feature.go", "// ✨\n", old)
+	gitCmd(nil, "add", "feature.go")
+	gitCmd(nil, "commit", "-q", "-m", "feature")
+	write("draft.go", "// 🔥\n", time.Now())
+
+	t.Run("since a commit", func(t *testing.T) {
+		out, err := scan(t, "v1.0")
+		require.NoError(t, err)
+		assert.Contains(t, out, "feature.go")
+		assert.Contains(t, out, "draft.go")
+		assert.NotContains(t, out, "new.go")
+		assert.NotContains(t, out, "old.go")
+
+		_, err = scan(t, "nope")
+		assert.ErrorIs(t, err, ErrConfig)
+	})
+
+	t.Run("since a time in git", func(t *testing.T) {
+		// feature.go was committed just now though modified long ago, and new.go was
+		// modified just now but committed long ago
+		out, err := scan(t, "1h")
+		require.NoError(t, err)
+		assert.Contains(t, out, "feature.go")
+		assert.Contains(t, out, "draft.go")
+		assert.NotContains(t, out, "new.go")
+		assert.NotContains(t, out, "old.go")
+	})
+}
@@ -4,94 +4,11 @@ import (
 	"context"
 	"testing"
 
-	"github.com/antimoji/antimoji/internal/core/allowlist"
 	"github.com/antimoji/antimoji/internal/observability/logging"
-	"github.com/antimoji/antimoji/internal/types"
 	"github.com/antimoji/antimoji/internal/ui"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 )
 
-func TestScanHandler_filterResultsThroughAllowlist(t *testing.T) {
-	logger := logging.NewMockLogger()
-	uiOutput := ui.NewUserOutput(ui.DefaultConfig())
-	handler := NewScanHandler(logger, uiOutput)
-
-	t.Run("filters results through allowlist", func(t *testing.T) {
-		results := []types.ProcessResult{
-			{
-				FilePath: "test1.go",
-				DetectionResult: types.DetectionResult{
-					TotalCount:  5,
-					UniqueCount: 3,
-					Emojis: []types.EmojiMatch{
-						{Emoji: "✅", Start: 0, End: 3, Line: 1, Column: 1, Category: types.CategoryUnicode},
-						{Emoji: "❌", Start: 10, End: 13, Line: 1, Column: 11, Category: types.CategoryUnicode},
-						{Emoji: "🚀", Start: 20, End: 24, Line: 2, Column: 1, Category: types.CategoryUnicode},
-					},
-					// Note: TotalCount and UniqueCount should match the emoji list
-				},
-			},
-			{
-				FilePath: "test2.go",
-				DetectionResult: types.DetectionResult{
-					TotalCount:  2,
-					UniqueCount: 1,
-					Emojis: []types.EmojiMatch{
-						{Emoji: "✅", Start: 0, End: 3, Line: 1, Column: 1, Category: types.CategoryUnicode},
-					},
-				},
-			},
-		}
-
-		// Create mock allowlist using the real allowlist type
-		allowlistResult := allowlist.NewAllowlist([]string{"✅"})
-		require.True(t, allowlistResult.IsOk())
-		mockAllowlist := allowlistResult.Unwrap()
-
-		filtered := handler.filterResultsThroughAllowlist(context.Background(), results, mockAllowlist)
-
-		assert.NotNil(t, filtered)
-		// The exact behavior depends on implementation
-		// We're mainly testing that the function exists and doesn't panic
-	})
-
-	t.Run("handles empty results", func(t *testing.T) {
-		results := []types.ProcessResult{}
-		allowlistResult := allowlist.NewAllowlist([]string{"✅"})
-		require.True(t, allowlistResult.IsOk())
-		mockAllowlist := allowlistResult.Unwrap()
-
-		filtered := handler.filterResultsThroughAllowlist(context.Background(), results, mockAllowlist)
-
-		assert.NotNil(t, filtered)
-	})
-
-	// Note: nil allowlist test skipped - the function expects a valid allowlist
-
-	t.Run("handles empty allowlist", func(t *testing.T) {
-		results := []types.ProcessResult{
-			{
-				FilePath: "test.go",
-				DetectionResult: types.DetectionResult{
-					TotalCount: 1,
-					Emojis: []types.EmojiMatch{
-						{Emoji: "🚀", Start: 0, End: 4, Line: 1, Column: 1, Category: types.CategoryUnicode},
-					},
-				},
-			},
-		}
-
-		allowlistResult := allowlist.NewAllowlist([]string{})
-		require.True(t, allowlistResult.IsOk())
-		mockAllowlist := allowlistResult.Unwrap()
-
-		filtered := handler.filterResultsThroughAllowlist(context.Background(), results, mockAllowlist)
-
-		assert.NotNil(t, filtered)
-	})
-}
-
 func TestScanHandler_Execute_EdgeCases(t *testing.T) {
 	logger := logging.NewMockLogger()
 	uiOutput := ui.NewUserOutput(ui.DefaultConfig())
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/infra/git"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// HistoryFinding is an emoji introduced by a commit within a scanned revision range.
+type HistoryFinding struct {
+	Commit git.Commit       `json:"commit"`
+	Path   string           `json:"path"`
+	Match  types.EmojiMatch `json:"match"`
+}
+
+// scanRevRange scans the lines added by each commit in opts.RevRange and reports which
+// commit introduced each emoji. Blobs are read from git directly, so no checkout is needed.
+func (h *ScanHandler) scanRevRange(ctx context.Context, args []string, opts *ScanOptions, engine *policy.Engine) error {
+	h.logger.Info(ctx, "Starting history scan", "rev_range", opts.RevRange, "paths", args)
+
+	addedLines, err := git.NewRepository("").AddedLines(ctx, opts.RevRange, args)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to read git history", "rev_range", opts.RevRange, "error", err)
+		return classify(ErrIO, fmt.Errorf("failed to read git history for %s: %w", opts.RevRange, err))
+	}
+
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to load supplemental emoji data", "error", err)
+		return err
+	}
+
+	findings, commits := findIntroducedEmojis(addedLines, patterns, engine.ProcessingConfig(), engine.FileFilter(), engine.Allowlist())
+	h.logger.Info(ctx, "History scan completed", "commits", commits, "findings", len(findings))
+
+	h.displayHistoryFindings(ctx, findings, commits, opts)
+
+	if err := engine.Evaluate(len(findings)); err != nil {
+		h.ui.Error(ctx, "Emoji threshold exceeded: found %d emojis, threshold is %d", len(findings), engine.Threshold())
+		return err
+	}
+
+	return nil
+}
+
+// findIntroducedEmojis detects emojis in added lines, skipping filtered paths and allowed
+// emojis. It returns the findings and the number of commits that touched included files.
+func findIntroducedEmojis(lines []git.AddedLine, patterns types.EmojiPatterns, processingConfig types.ProcessingConfig,
+	engine *filtering.FileFilterEngine, emojiAllowlist *allowlist.Allowlist) ([]HistoryFinding, int) {
+	var findings []HistoryFinding
+	commits := make(map[string]struct{})
+	included := make(map[string]bool)
+
+	for _, line := range lines {
+		include, seen := included[line.Path]
+		if !seen {
+			include = engine.ShouldInclude(line.Path).Include
+			included[line.Path] = include
+		}
+		if !include {
+			continue
+		}
+		commits[line.Commit.Hash] = struct{}{}
+
+		for _, match := range detectInText(line.Text, patterns, processingConfig, emojiAllowlist) {
+			match.Line = line.Line
+			findings = append(findings, HistoryFinding{Commit: line.Commit, Path: line.Path, Match: match})
+		}
+	}
+
+	return findings, len(commits)
+}
+
+// displayHistoryFindings displays the emojis introduced across a revision range.
+func (h *ScanHandler) displayHistoryFindings(ctx context.Context, findings []HistoryFinding, commits int, opts *ScanOptions) {
+	if opts.CountOnly {
+		h.ui.Result(ctx, "Total emojis found: %d", len(findings))
+		return
+	}
+
+	introducing := make(map[string]struct{})
+	for _, finding := range findings {
+		introducing[finding.Commit.Hash] = struct{}{}
+	}
+
+	h.ui.Result(ctx, "Scanned %d commits in %s, found %d emojis introduced by %d commits",
+		commits, opts.RevRange, len(findings), len(introducing))
+
+	for _, finding := range findings {
+		h.ui.Info(ctx, "%s %s:%d:%d %s (%s: %s)",
+			finding.Commit.ShortHash(), finding.Path, finding.Match.Line, finding.Match.Column,
+			finding.Match.Emoji, finding.Commit.Author, finding.Commit.Subject)
+	}
+}
+
+// MessageFinding is an emoji found in a commit message.
+type MessageFinding struct {
+	Commit git.Commit       `json:"commit"`
+	Match  types.EmojiMatch `json:"match"`
+}
+
+// scanCommitMessages scans the messages of the commits in opts.CommitMessages.
+func (h *ScanHandler) scanCommitMessages(ctx context.Context, opts *ScanOptions, engine *policy.Engine) error {
+	h.logger.Info(ctx, "Starting commit message scan", "rev_range", opts.CommitMessages)
+
+	messages, err := git.NewRepository("").CommitMessages(ctx, opts.CommitMessages)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to read commit messages", "rev_range", opts.CommitMessages, "error", err)
+		return classify(ErrIO, fmt.Errorf("failed to read commit messages for %s: %w", opts.CommitMessages, err))
+	}
+
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to load supplemental emoji data", "error", err)
+		return err
+	}
+	processingConfig := engine.ProcessingConfig()
+
+	var findings []MessageFinding
+	flagged := 0
+	for _, message := range messages {
+		matches := detectInText(message.Message, patterns, processingConfig, engine.Allowlist())
+		if len(matches) > 0 {
+			flagged++
+		}
+		for _, match := range matches {
+			findings = append(findings, MessageFinding{Commit: message.Commit, Match: match})
+		}
+	}
+	h.logger.Info(ctx, "Commit message scan completed", "commits", len(messages), "findings", len(findings))
+
+	if opts.CountOnly {
+		h.ui.Result(ctx, "Total emojis found: %d", len(findings))
+	} else {
+		h.ui.Result(ctx, "Scanned %d commit messages in %s, found %d emojis in %d messages",
+			len(messages), opts.CommitMessages, len(findings), flagged)
+		for _, finding := range findings {
+			h.ui.Info(ctx, "%s %d:%d %s (%s: %s)",
+				finding.Commit.ShortHash(), finding.Match.Line, finding.Match.Column,
+				finding.Match.Emoji, finding.Commit.Author, finding.Commit.Subject)
+		}
+	}
+
+	if err := engine.Evaluate(len(findings)); err != nil {
+		h.ui.Error(ctx, "Emoji threshold exceeded: found %d emojis, threshold is %d", len(findings), engine.Threshold())
+		return err
+	}
+
+	return nil
+}
+
+// detectInText detects emojis in text that does not live in the working tree, such as
+// added lines or commit metadata, dropping allowed emojis.
+func detectInText(text string, patterns types.EmojiPatterns, processingConfig types.ProcessingConfig, emojiAllowlist *allowlist.Allowlist) []types.EmojiMatch {
+	detection := processor.DetectContent([]byte(text), patterns, processingConfig)
+	if detection.IsErr() {
+		return nil
+	}
+
+	matches := make([]types.EmojiMatch, 0, len(detection.Unwrap().Emojis))
+	for _, match := range detection.Unwrap().Emojis {
+		if emojiAllowlist != nil && emojiAllowlist.IsAllowed(match.Emoji) {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/infra/git"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindIntroducedEmojis(t *testing.T) {
+	first := git.Commit{Hash: "1111111111", Subject: "add docs"}
+	second := git.Commit{Hash: "2222222222", Subject: "add code"}
+	lines := []git.AddedLine{
+		{Commit: first, Path: "README.md", Line: 4, Text: "Launch 🚀 and ✅"},
+		{Commit: second, Path: "main.go", Line: 10, Text: "// plain comment"},
+		{Commit: second, Path: "vendor/lib.go", Line: 1, Text: "// vendored 🎉"},
+	}
+
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.DirectoryIgnoreList = []string{"vendor"}
+	engine := filtering.NewFileFilterEngine(profile)
+	processingConfig := config.ToProcessingConfig(profile)
+	emojiAllowlist := allowlist.NewAllowlist([]string{"✅"}).Unwrap()
+
+	findings, commits := findIntroducedEmojis(lines, detector.DefaultEmojiPatterns(), processingConfig, engine, emojiAllowlist)
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, "🚀", findings[0].Match.Emoji)
+	assert.Equal(t, "README.md", findings[0].Path)
+	assert.Equal(t, 4, findings[0].Match.Line)
+	assert.Equal(t, 8, findings[0].Match.Column)
+	assert.Equal(t, first, findings[0].Commit)
+	assert.Equal(t, 2, commits)
+}
+
+func TestScanHandler_HistoryOptionsConflict(t *testing.T) {
+	handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(ui.DefaultConfig()))
+
+	err := handler.Execute(context.Background(), handler.CreateCommand(), nil,
+		&ScanOptions{Format: "table", RevRange: "v1.0..HEAD", CommitMessages: "v1.0..HEAD"})
+	assert.ErrorContains(t, err, "cannot be used together")
+}
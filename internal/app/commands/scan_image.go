@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/infra/containerimage"
+	"github.com/antimoji/antimoji/internal/infra/fs"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// ImageFinding is an emoji in a file added by a layer of a container image.
+type ImageFinding struct {
+	Layer  int              `json:"layer"`
+	Digest string           `json:"digest"`
+	Path   string           `json:"path"`
+	Match  types.EmojiMatch `json:"match"`
+}
+
+// scanImage scans the text files in the layers of the container image opts.Image.
+func (h *ScanHandler) scanImage(ctx context.Context, opts *ScanOptions, engine *policy.Engine) error {
+	h.logger.Info(ctx, "Starting image scan", "image", opts.Image)
+
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to load supplemental emoji data", "error", err)
+		return err
+	}
+	processingConfig := engine.ProcessingConfig()
+	filter := engine.FileFilter()
+
+	var findings []ImageFinding
+	scanned := 0
+	layers, err := containerimage.Walk(ctx, opts.Image, containerimage.Options{MaxFileSize: processingConfig.MaxFileSize},
+		func(file containerimage.File) error {
+			if !filter.ShouldInclude(strings.TrimPrefix(file.Path, "/")).Include {
+				return nil
+			}
+			if file.Content == nil {
+				h.logger.Debug(ctx, "Skipping large image file", "path", file.Path, "size", file.Size)
+				return nil
+			}
+			if encoding, _ := fs.Sniff(file.Content, processingConfig.Sniff); encoding == fs.EncodingBinary {
+				return nil
+			}
+			decoded, err := fs.Decode(file.Content, processingConfig.Sniff)
+			if err != nil {
+				return nil
+			}
+
+			scanned++
+			for _, match := range detectInText(string(decoded.Text), patterns, processingConfig, engine.Allowlist()) {
+				findings = append(findings, ImageFinding{Layer: file.Layer, Digest: file.Digest, Path: file.Path, Match: match})
+			}
+			return nil
+		})
+	if err != nil {
+		h.logger.Error(ctx, "Failed to read image", "image", opts.Image, "error", err)
+		return classify(ErrIO, fmt.Errorf("failed to read image %s: %w", opts.Image, err))
+	}
+	h.logger.Info(ctx, "Image scan completed", "layers", len(layers), "files", scanned, "findings", len(findings))
+
+	if opts.CountOnly {
+		h.ui.Result(ctx, "Total emojis found: %d", len(findings))
+	} else {
+		h.ui.Result(ctx, "Scanned %d text files in %d layers of %s, found %d emojis",
+			scanned, len(layers), opts.Image, len(findings))
+		for _, finding := range findings {
+			h.ui.Info(ctx, "layer %d (%s) %s:%d:%d %s", finding.Layer, shortDigest(finding.Digest),
+				finding.Path, finding.Match.Line, finding.Match.Column, finding.Match.Emoji)
+		}
+	}
+
+	if err := engine.Evaluate(len(findings)); err != nil {
+		h.ui.Error(ctx, "Emoji threshold exceeded: found %d emojis, threshold is %d", len(findings), engine.Threshold())
+		return err
+	}
+	return nil
+}
+
+// shortDigest abbreviates a layer digest such as sha256:4f5e... to 12 hex digits.
+func shortDigest(digest string) string {
+	algorithm, hex, found := strings.Cut(digest, ":")
+	if !found || len(hex) <= 12 {
+		return digest
+	}
+	return algorithm + ":" + hex[:12]
+}
+
+// imageConflictError reports the options given with --image that it does not support.
+func imageConflictError(opts *ScanOptions, args []string) error {
+	return conflictError("--image", []scanOption{
+		{"path arguments", len(args) > 0},
+		{"--rev-range", opts.RevRange != ""},
+		{"--commit-messages", opts.CommitMessages != ""},
+		{"--workspace", opts.Workspace != ""},
+		{"--include-names", opts.IncludeNames},
+		{"--cache", opts.Cache},
+		{"--output", opts.Output != ""},
+		{"--record", opts.Record},
+		{"--tui", opts.TUI},
+		{"--via-daemon", opts.ViaDaemon},
+		{"--group-by", opts.GroupBy != ""},
+		{"--top", opts.Top > 0},
+		{"--max-errors", opts.MaxErrors > 0},
+		{"--error-report", opts.ErrorReport != ""},
+		{"--summary-file", opts.SummaryFile != ""},
+		{"--expect-summary", opts.ExpectSummary != ""},
+		{"--baseline", opts.Baseline != ""},
+		{"--changed-since", opts.ChangedSince != ""},
+	})
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// imageTarball writes a docker save tarball whose single layer holds files.
+func imageTarball(t *testing.T, files map[string]string) string {
+	tarball := func(entries map[string][]byte) []byte {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for name, content := range entries {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+			_, err := tw.Write(content)
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		return buf.Bytes()
+	}
+
+	layer := make(map[string][]byte, len(files))
+	for name, content := range files {
+		layer[name] = []byte(content)
+	}
+	path := filepath.Join(t.TempDir(), "image.tar")
+	require.NoError(t, os.WriteFile(path, tarball(map[string][]byte{
+		"blobs/sha256/0123456789abcdef0123": tarball(layer),
+		"manifest.json":                     []byte(`[{"Layers":["blobs/sha256/0123456789abcdef0123"]}]`),
+	}), 0600))
+	return path
+}
+
+func TestScanHandler_Image(t *testing.T) {
+	image := imageTarball(t, map[string]string{
+		"etc/app/config.yaml": "name: app\nbanner: ready 🚀\n",
+		"usr/bin/tool":        "\x00\x01\x02 🚀",
+	})
+
+	scan := func(t *testing.T, args []string, opts *ScanOptions) (string, error) {
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", "", "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputVerbose, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		err := handler.Execute(context.Background(), scanCmd, args, opts)
+		return out.String(), err
+	}
+
+	t.Run("reports layer and path", func(t *testing.T) {
+		out, err := scan(t, nil, &ScanOptions{Format: "table", Image: image})
+		require.NoError(t, err)
+		assert.Contains(t, out, "Scanned 1 text files in 1 layers of "+image+", found 1 emojis")
+		assert.Contains(t, out, "layer 1 (sha256:0123456789ab) /etc/app/config.yaml:2:15 🚀")
+	})
+
+	t.Run("unreadable image", func(t *testing.T) {
+		_, err := scan(t, nil, &ScanOptions{Format: "table", Image: imageTarball(t, nil) + ".missing/image.tar"})
+		assert.ErrorIs(t, Classify(err), ErrIO)
+	})
+
+	t.Run("conflicting options", func(t *testing.T) {
+		_, err := scan(t, []string{"."}, &ScanOptions{Format: "table", Image: image, Record: true})
+		assert.EqualError(t, err, "--image cannot be used with path arguments, --record")
+	})
+}
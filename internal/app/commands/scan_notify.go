@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/analysis"
+	"github.com/antimoji/antimoji/internal/infra/httpclient"
+	"github.com/antimoji/antimoji/internal/infra/notify"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// notifyTop is the number of files and emojis a notification lists.
+const notifyTop = 5
+
+// notifyTimeout bounds the delivery of each notification.
+const notifyTimeout = 10 * time.Second
+
+// notify sends summary to each of notifications that wants its outcome. Notifications
+// that cannot be sent are warned about and never fail the scan.
+func (h *ScanHandler) notify(ctx context.Context, notifications []config.Notification, summary notify.Summary) {
+	var client *http.Client
+	for _, notification := range notifications {
+		if !notification.Wants(summary.Passed) {
+			continue
+		}
+		getenv := func(name string) string { return lookupEnv(h.env(), name) }
+		webhook := strings.TrimSpace(os.Expand(notification.Webhook, getenv))
+		if webhook == "" {
+			h.ui.Warning(ctx, "Skipped the %s notification: its webhook %s is empty", notification.Type, notification.Webhook)
+			continue
+		}
+		sent := summary
+		sent.ReportURL = os.Expand(notification.ReportURL, getenv)
+
+		if client == nil {
+			var err error
+			if client, err = httpclient.New(httpclient.CAFile(""), notifyTimeout); err != nil {
+				h.ui.Warning(ctx, "Failed to send notifications: %v", err)
+				return
+			}
+		}
+		if err := notify.Send(ctx, client, notify.Sink{Type: notification.Type, URL: webhook}, sent); err != nil {
+			h.logger.Warn(ctx, "Failed to send notification", "type", notification.Type, "error", err)
+			h.ui.Warning(ctx, "Failed to send the %s notification: %v", notification.Type, err)
+			continue
+		}
+		h.logger.Info(ctx, "Notification sent", "type", notification.Type, "passed", summary.Passed)
+	}
+}
+
+// notifySummary returns the summary of the scan of args with profileName over
+// results, listing the files and emojis with the most findings.
+func notifySummary(results []types.ProcessResult, profileName string, args []string, passed bool, duration time.Duration) notify.Summary {
+	violations, warnings := policy.CountSeverities(results)
+	summary := notify.Summary{
+		Operation:    "scan",
+		Profile:      profileName,
+		Target:       strings.Join(args, " "),
+		Passed:       passed,
+		FilesScanned: len(results),
+		FilesFailed:  countFileFailures(results),
+		Violations:   violations,
+		Warnings:     warnings,
+		DurationMS:   duration.Milliseconds(),
+	}
+
+	for _, result := range results {
+		if result.Error == nil && result.DetectionResult.TotalCount > 0 {
+			summary.TopFiles = append(summary.TopFiles, notify.Offender{Name: result.FilePath, Count: result.DetectionResult.TotalCount})
+		}
+	}
+	sort.SliceStable(summary.TopFiles, func(i, j int) bool {
+		return summary.TopFiles[i].Count > summary.TopFiles[j].Count
+	})
+	if len(summary.TopFiles) > notifyTop {
+		summary.TopFiles = summary.TopFiles[:notifyTop]
+	}
+	for _, emoji := range analysis.AnalyzeUsage(results, analysis.UsageOptions{}).Top(notifyTop).Emojis {
+		summary.TopEmojis = append(summary.TopEmojis, notify.Offender{Name: emoji.Emoji, Count: emoji.Count})
+	}
+	return summary
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/notify"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanHandler_Notify(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string][]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		received[r.URL.Path] = append(received[r.URL.Path], payload)
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTIFY_URL", server.URL)
+	t.Setenv("RUN_URL", "https://ci.example.com/runs/1")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// 🚀 🚀 ✅\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# 🚀\n"), 0600))
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`profiles:
+  default:
+    unicode_emojis: true
+    max_per_file: 1
+    notify:
+      - type: slack
+        webhook: ${NOTIFY_URL}/slack
+        report_url: ${RUN_URL}
+      - type: webhook
+        webhook: ${NOTIFY_URL}/hook
+        on: always
+      - type: webhook
+        webhook: ${NOTIFY_URL}/broken
+      - type: webhook
+        webhook: ${NOTIFY_URL}/passed
+        on: success
+`), 0600))
+
+	scan := func(t *testing.T, opts *ScanOptions) (string, error) {
+		mu.Lock()
+		received = map[string][]map[string]any{}
+		mu.Unlock()
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", configFile, "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		opts.Recursive, opts.Format = true, "table"
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, opts)
+		return out.String(), err
+	}
+
+	t.Run("sends the summary of a failing scan", func(t *testing.T) {
+		out, err := scan(t, &ScanOptions{})
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded, "notifications never change the outcome")
+		assert.Contains(t, out, "Failed to send the webhook notification")
+
+		require.Len(t, received["/slack"], 1)
+		text := received["/slack"][0]["text"].(string)
+		assert.Contains(t, text, "*antimoji scan failed*")
+		assert.Contains(t, text, "4 violations and 0 warnings in 2 files")
+		assert.Contains(t, text, "Top files: `"+filepath.Join(dir, "main.go")+"` (3), `"+filepath.Join(dir, "README.md")+"` (1)")
+		assert.Contains(t, text, "Top emojis: 🚀 (3), ✅ (1)")
+		assert.Contains(t, text, "<https://ci.example.com/runs/1|Full report>")
+
+		require.Len(t, received["/hook"], 1)
+		hook := received["/hook"][0]
+		assert.Equal(t, false, hook["passed"])
+		assert.Equal(t, "default", hook["profile"])
+		assert.Equal(t, float64(4), hook["violations"])
+		assert.Len(t, received["/broken"], 1)
+		assert.Empty(t, received["/passed"])
+	})
+
+	t.Run("no-notify", func(t *testing.T) {
+		_, err := scan(t, &ScanOptions{NoNotify: true})
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+		assert.Empty(t, received)
+	})
+}
+
+func TestNotifySummary(t *testing.T) {
+	var results []types.ProcessResult
+	for i, count := range []int{1, 6, 2, 3, 4, 5} {
+		results = append(results, types.ProcessResult{
+			FilePath:        string(rune('a'+i)) + ".go",
+			DetectionResult: types.DetectionResult{TotalCount: count},
+		})
+	}
+	summary := notifySummary(results, "ci", []string{"src", "docs"}, false, 0)
+	assert.Equal(t, "src docs", summary.Target)
+	assert.Equal(t, 6, summary.FilesScanned)
+	assert.Equal(t, []notify.Offender{
+		{Name: "b.go", Count: 6}, {Name: "f.go", Count: 5}, {Name: "e.go", Count: 4}, {Name: "d.go", Count: 3}, {Name: "c.go", Count: 2},
+	}, summary.TopFiles)
+}
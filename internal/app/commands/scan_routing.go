@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+)
+
+// cacheUsage is the result cache hits and misses of a scan.
+type cacheUsage struct {
+	used         bool
+	hits, misses int
+}
+
+// add counts the hits and misses of other.
+func (u *cacheUsage) add(other cacheUsage) {
+	u.used = u.used || other.used
+	u.hits += other.hits
+	u.misses += other.misses
+}
+
+// detect processes files with patterns and processingConfig, using the result cache
+// when enabled, and returns the results with the cache hits and misses. --max-errors
+// counts the failures of each call.
+func (h *ScanHandler) detect(ctx context.Context, files []string, patterns types.EmojiPatterns, processingConfig types.ProcessingConfig, progress *ui.ProgressReporter, opts *ScanOptions) ([]types.ProcessResult, cacheUsage) {
+	// Open the result cache; a nil interface disables caching. The daemon keeps
+	// results in memory even without --cache.
+	var detectionCache processor.DetectionCache
+	var resultCache *cache.Cache
+	if opts.Cache {
+		resultCache = h.openCache(ctx, opts.CacheDir, patterns, processingConfig)
+	} else if h.warm != nil {
+		resultCache = h.warm.memoryCache(cache.Fingerprint(patterns, processingConfig))
+	}
+	var baseHits, baseMisses int
+	if resultCache != nil {
+		detectionCache = resultCache
+		// A daemon's cache counts earlier scans too
+		baseHits, baseMisses = resultCache.Stats()
+	}
+
+	results := processor.ProcessBatch(files, patterns, processingConfig, processor.BatchOptions{
+		Cache:     detectionCache,
+		Progress:  progressFunc(progress),
+		MaxErrors: opts.MaxErrors,
+	})
+
+	if resultCache == nil {
+		return results, cacheUsage{}
+	}
+	hits, misses := resultCache.Stats()
+	if err := resultCache.Save(); err != nil {
+		h.logger.Warn(ctx, "Failed to save result cache", "error", err)
+		h.ui.Warning(ctx, "Failed to save result cache: %v", err)
+	}
+	return results, cacheUsage{used: true, hits: hits - baseHits, misses: misses - baseMisses}
+}
+
+// inFileOrder sorts results in the order of their files in filePaths.
+func inFileOrder(results []types.ProcessResult, filePaths []string) []types.ProcessResult {
+	position := make(map[string]int, len(filePaths))
+	for i, filePath := range filePaths {
+		position[filePath] = i
+	}
+	sorted := make([]types.ProcessResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return position[sorted[i].FilePath] < position[sorted[j].FilePath]
+	})
+	return sorted
+}
+
+// evaluate checks results against the threshold and budgets of engine, reporting what
+// is exceeded, and returns the violation, if any. total counts the emojis found,
+// warnings included; label names the profile in messages when not "".
+func (h *ScanHandler) evaluate(ctx context.Context, engine *policy.Engine, label string, results []types.ProcessResult, total int) error {
+	scope := ""
+	if label != "" {
+		scope = " under profile " + label
+	}
+
+	// Only error-level findings count against the threshold
+	_, warnings := policy.CountSeverities(results)
+	thresholdErr := engine.Evaluate(total - warnings)
+	if thresholdErr != nil {
+		h.logger.Error(ctx, "Emoji threshold exceeded",
+			"profile", label,
+			"threshold", engine.Threshold(),
+			"found", total-warnings,
+			"warnings", warnings)
+		h.ui.Error(ctx, "Emoji limit exceeded%s: %s", scope, engine.TotalViolation(total-warnings))
+	}
+
+	// Check the new, per-file, per-directory and per-emoji budgets
+	exceeded := engine.Budgets(results)
+	for _, budget := range exceeded {
+		h.logger.Error(ctx, "Emoji budget exceeded",
+			"profile", label,
+			"budget", budget.Budget,
+			"scope", budget.Scope,
+			"found", budget.Found,
+			"limit", budget.Limit)
+		h.ui.Error(ctx, "Emoji budget exceeded%s: %s", scope, budget)
+	}
+	if thresholdErr != nil {
+		return thresholdErr
+	}
+	return policy.BudgetError(exceeded)
+}
+
+// evaluateRoutes checks each group of results against the profile its files are routed
+// to and joins the violations. The nameEmojis found in file and directory names count
+// against the selected profile, whose group comes first.
+func (h *ScanHandler) evaluateRoutes(ctx context.Context, router *policy.Router, results []types.ProcessResult, nameEmojis int) error {
+	var violations []error
+	for i, group := range router.GroupResults(results) {
+		total := h.countTotalEmojis(group.Results)
+		if i == 0 {
+			total += nameEmojis
+		}
+		if err := h.evaluate(ctx, group.Engine, group.Profile, group.Results, total); err != nil {
+			violations = append(violations, fmt.Errorf("profile %s: %w", group.Profile, err))
+		}
+	}
+	return errors.Join(violations...)
+}
@@ -1,12 +1,18 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/infra/history"
+	"github.com/antimoji/antimoji/internal/infra/report"
 	"github.com/antimoji/antimoji/internal/observability/logging"
 	"github.com/antimoji/antimoji/internal/types"
 	"github.com/antimoji/antimoji/internal/ui"
@@ -52,6 +58,7 @@ func TestScanHandler_CreateCommand(t *testing.T) {
 		assert.NotNil(t, flags.Lookup("ignore-allowlist"))
 		assert.NotNil(t, flags.Lookup("stats"))
 		assert.NotNil(t, flags.Lookup("workers"))
+		assert.NotNil(t, flags.Lookup("rev-range"))
 	})
 }
 
@@ -224,3 +231,503 @@ func TestScanHandler_displayResults_Additional(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestScanHandler_HTMLReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\n// 🚀 launch 🚀\n"), 0600))
+
+	newScan := func() (*ScanHandler, *cobra.Command) {
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", "", "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		return handler, scanCmd
+	}
+
+	t.Run("written even when the threshold fails", func(t *testing.T) {
+		reportFile := filepath.Join(t.TempDir(), "report.html")
+		handler, scanCmd := newScan()
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{
+			Recursive: true, Format: "table", Threshold: 1, Output: "html", ReportFile: reportFile,
+			ReportSourceURL: "https://example.com/blob/main/",
+		})
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+
+		content, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "main.go")
+		assert.Contains(t, string(content), "2 emojis")
+		assert.Contains(t, string(content), "#L3")
+	})
+
+	t.Run("codeclimate", func(t *testing.T) {
+		reportFile := filepath.Join(t.TempDir(), "gl-code-quality-report.json")
+		handler, scanCmd := newScan()
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{
+			Recursive: true, Format: "table", Output: "codeclimate", ReportFile: reportFile,
+		})
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		var issues []report.CodeClimateIssue
+		require.NoError(t, json.Unmarshal(content, &issues))
+		require.Len(t, issues, 2)
+		assert.Equal(t, 3, issues[0].Location.Lines.Begin)
+		assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+	})
+
+	t.Run("findings-json", func(t *testing.T) {
+		reportFile := filepath.Join(t.TempDir(), "findings.json")
+		handler, scanCmd := newScan()
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{
+			Recursive: true, Format: "table", Output: "findings-json", ReportFile: reportFile,
+		})
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		var findings report.FindingsReport
+		require.NoError(t, json.Unmarshal(content, &findings))
+		assert.Equal(t, report.FindingsSchemaVersion, findings.SchemaVersion)
+		require.Len(t, findings.Findings, 2)
+		assert.Equal(t, 3, findings.Findings[0].Line)
+		assert.NotEqual(t, findings.Findings[0].Fingerprint, findings.Findings[1].Fingerprint)
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		reportFile := filepath.Join(t.TempDir(), "antimoji.sarif")
+		handler, scanCmd := newScan()
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{
+			Recursive: true, Format: "table", Output: "sarif", ReportFile: reportFile,
+		})
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		var log report.SARIFLog
+		require.NoError(t, json.Unmarshal(content, &log))
+		require.Len(t, log.Runs, 1)
+		require.Len(t, log.Runs[0].Results, 2)
+		assert.Equal(t, 3, log.Runs[0].Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	})
+
+	t.Run("rejects unknown outputs", func(t *testing.T) {
+		handler, scanCmd := newScan()
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table", Output: "pdf"})
+		assert.ErrorContains(t, err, "unsupported output")
+	})
+}
+
+func TestScanHandler_Record(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\n// 🚀 launch :)\n"), 0600))
+	historyFile := filepath.Join(t.TempDir(), ".antimoji", "history.jsonl")
+
+	rootCmd := &cobra.Command{Use: "antimoji"}
+	rootCmd.PersistentFlags().String("config", "", "config file path")
+	rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+	handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+	scanCmd := handler.CreateCommand()
+	rootCmd.AddCommand(scanCmd)
+
+	err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{
+		Recursive: true, Format: "table", Threshold: 1, Record: true, HistoryFile: historyFile,
+	})
+	assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+
+	snapshots, err := history.Read(historyFile)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, 2, snapshots[0].Emojis)
+	assert.Equal(t, 1, snapshots[0].FilesWithEmojis)
+	assert.Equal(t, map[string]int{"unicode": 1, "emoticon": 1}, snapshots[0].Categories)
+	assert.False(t, snapshots[0].Time.IsZero())
+
+	err = handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{
+		Format: "table", Record: true, RevRange: "HEAD~1..HEAD",
+	})
+	assert.ErrorContains(t, err, "--record cannot be used with --rev-range")
+}
+
+func TestScanHandler_Top(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\n// 🚀 launch\n\nfunc main() {}\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "notes.md"), []byte("🎉 ✅\n"), 0600))
+
+	rootCmd := &cobra.Command{Use: "antimoji"}
+	rootCmd.PersistentFlags().String("config", "", "config file path")
+	rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+	var out bytes.Buffer
+	handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: io.Discard}))
+	scanCmd := handler.CreateCommand()
+	rootCmd.AddCommand(scanCmd)
+
+	err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table", Top: 1})
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "Top 1 files by emojis per KLOC:")
+	assert.Contains(t, out.String(), "2000.0  "+filepath.Join(dir, "docs", "notes.md")+" (2 emojis in 1 lines)")
+	assert.NotContains(t, out.String(), "200.0  "+filepath.Join(dir, "main.go"))
+	assert.Contains(t, out.String(), "Top 1 packages by emojis per KLOC:")
+
+	err = handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Format: "table", Top: -1})
+	assert.ErrorContains(t, err, "--top must be non-negative")
+}
+
+func TestScanHandler_GroupByOwner(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("*.go @org/backend\n/docs/ @org/docs\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\n// 🚀 launch\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "notes.md"), []byte("🎉 ✅\n"), 0600))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	rootCmd := &cobra.Command{Use: "antimoji"}
+	rootCmd.PersistentFlags().String("config", "", "config file path")
+	rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+	var out bytes.Buffer
+	handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: io.Discard}))
+	scanCmd := handler.CreateCommand()
+	rootCmd.AddCommand(scanCmd)
+
+	err = handler.Execute(context.Background(), scanCmd, []string{"."}, &ScanOptions{Recursive: true, Format: "table", GroupBy: "owner"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Findings by owner:")
+	assert.Contains(t, out.String(), "     2  @org/docs (1 files)")
+	assert.Contains(t, out.String(), "     1  @org/backend (1 files)")
+
+	err = handler.Execute(context.Background(), scanCmd, []string{"."}, &ScanOptions{Format: "table", GroupBy: "team"})
+	assert.ErrorContains(t, err, `unsupported --group-by "team"`)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "CODEOWNERS")))
+	err = handler.Execute(context.Background(), scanCmd, []string{"."}, &ScanOptions{Recursive: true, Format: "table", GroupBy: "owner"})
+	assert.ErrorIs(t, err, filtering.ErrNoCodeOwners)
+	assert.ErrorIs(t, Classify(err), ErrConfig)
+}
+
+func TestScanHandler_ReportsBinaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "image.dat")
+	require.NoError(t, os.WriteFile(binary, []byte{0x89, 'P', 'N', 'G', 0x00, 0x1A}, 0600))
+	fixture := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(fixture, []byte("package main\n\n// antimoji:off\n// 😀\n// antimoji:on\n"), 0600))
+
+	for _, verbose := range []bool{true, false} {
+		t.Run(fmt.Sprintf("verbose=%v", verbose), func(t *testing.T) {
+			var out bytes.Buffer
+			rootCmd := &cobra.Command{Use: "antimoji"}
+			rootCmd.PersistentFlags().String("config", "", "config file path")
+			rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+			handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+			scanCmd := handler.CreateCommand()
+			rootCmd.AddCommand(scanCmd)
+
+			err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table", Verbose: verbose})
+			require.NoError(t, err)
+
+			messages := []string{
+				fmt.Sprintf("Skipped binary file %s: contains_null_bytes", binary),
+				fmt.Sprintf("Suppressed region in %s: lines 3-5 (1 emojis)", fixture),
+			}
+			for _, message := range messages {
+				if verbose {
+					assert.Contains(t, out.String(), message)
+				} else {
+					assert.NotContains(t, out.String(), message)
+				}
+			}
+		})
+	}
+}
+
+func TestScanHandler_Budgets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// ✅ ✅ 😂\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "guide.md"), []byte("# ✅ 🚀 🚀 🚀\n"), 0600))
+
+	scan := func(t *testing.T, profile string) (string, error) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte(profile), 0600))
+
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", configFile, "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table"})
+		return out.String(), err
+	}
+
+	t.Run("within budget", func(t *testing.T) {
+		_, err := scan(t, `profiles:
+  default:
+    unicode_emojis: true
+    max_per_file: 4
+    emoji_thresholds:
+      "✅": 3
+`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("reports every exceeded budget", func(t *testing.T) {
+		out, err := scan(t, `profiles:
+  default:
+    unicode_emojis: true
+    max_per_file: 3
+    emoji_thresholds:
+      "✅": 3
+      "😂": 0
+`)
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+		assert.Contains(t, out, fmt.Sprintf("max_per_file: %s has 4 emojis (limit 3)", filepath.Join(dir, "docs", "guide.md")))
+		assert.Contains(t, out, "emoji_thresholds: 😂 found 1 times (limit 0)")
+		assert.NotContains(t, out, "emoji_thresholds: ✅")
+	})
+
+	t.Run("allowlisted emojis do not count", func(t *testing.T) {
+		_, err := scan(t, `profiles:
+  default:
+    unicode_emojis: true
+    max_per_file: 3
+    emoji_allowlist: ["✅"]
+`)
+		assert.NoError(t, err)
+	})
+}
+
+func TestScanHandler_CustomRules(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "payments"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "payments", "charge.go"), []byte("// Charge ✅\nvar status = \"🚀\"\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("var banner = \"🎉\"\n"), 0600))
+
+	scan := func(t *testing.T, expression string) (string, error) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte(fmt.Sprintf(`profiles:
+  default:
+    unicode_emojis: true
+    custom_rules:
+      - name: payments
+        expression: '%s'
+        message: payments code has no emojis outside comments
+`, expression)), 0600))
+
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", configFile, "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table"})
+		return out.String(), err
+	}
+
+	out, err := scan(t, `file.dir.endsWith("/payments") && !finding.in_comment`)
+	assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+	assert.Contains(t, out, "custom_rules: payments matched 1 emojis (limit 0): payments code has no emojis outside comments")
+
+	_, err = scan(t, `file.dir.endsWith("/payments") && finding.emoji == "🎉"`)
+	assert.NoError(t, err)
+}
+
+func TestScanHandler_Rules(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "guide.md"), []byte("# Launch 🚀 🚀\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// ok\n"), 0600))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	scan := func(t *testing.T, goFile string) (string, error) {
+		require.NoError(t, os.WriteFile("main.go", []byte(goFile), 0600))
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte(`rules:
+  - paths: ["docs/**"]
+    profile: permissive
+profiles:
+  default:
+    unicode_emojis: true
+    max_per_file: 1
+  permissive:
+    unicode_emojis: true
+    emoji_allowlist: ["🚀"]
+`), 0600))
+
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", configFile, "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+
+		err := handler.Execute(context.Background(), scanCmd, []string{"."}, &ScanOptions{Recursive: true, Format: "table"})
+		return out.String(), err
+	}
+
+	// The docs are checked under the permissive profile, which has no per-file budget
+	out, err := scan(t, "// ok\n")
+	require.NoError(t, err, out)
+
+	out, err = scan(t, "// 🚀 🚀\n")
+	require.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+	assert.Contains(t, err.Error(), "profile default")
+	assert.Contains(t, out, "Emoji budget exceeded under profile default: max_per_file: main.go")
+	assert.NotContains(t, out, "profile permissive")
+}
+
+func TestScanHandler_Severity(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("// ✅ ✅ 😂\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "guide.md"), []byte("# ✅ 🚀 🚀 🚀\n"), 0600))
+
+	scan := func(t *testing.T, profile string) (string, error) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte(profile), 0600))
+
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", configFile, "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, &ScanOptions{Recursive: true, Format: "table", Threshold: 1})
+		return out.String(), err
+	}
+
+	t.Run("warnings do not count against the threshold or budgets", func(t *testing.T) {
+		out, err := scan(t, `profiles:
+  default:
+    unicode_emojis: true
+    max_per_file: 1
+    severity:
+      unicode: warn
+      "😂": error
+`)
+		assert.NoError(t, err)
+		assert.Contains(t, out, "Severity: 1 emojis fail the policy, 6 are warnings")
+		assert.Contains(t, out, fmt.Sprintf("%s: 3 emojis found (2 warnings)", filepath.Join(dir, "main.go")))
+	})
+
+	t.Run("errors fail the scan", func(t *testing.T) {
+		out, err := scan(t, `profiles:
+  default:
+    unicode_emojis: true
+    severity:
+      "✅": warn
+`)
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+		assert.Contains(t, out, "Emoji limit exceeded: max_total: 4 emojis (limit 1)")
+	})
+}
+
+func TestScanHandler_Limits(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(main, []byte("// ✅ 🚀\n"), 0600))
+
+	scan := func(t *testing.T, profile string, opts *ScanOptions) (string, error) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte(profile), 0600))
+
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", configFile, "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+
+		opts.Recursive, opts.Format = true, "table"
+		err := handler.Execute(context.Background(), scanCmd, []string{dir}, opts)
+		return out.String(), err
+	}
+
+	t.Run("max_total applies without --threshold", func(t *testing.T) {
+		out, err := scan(t, "profiles:\n  default:\n    unicode_emojis: true\n    max_total: 1\n", &ScanOptions{})
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+		assert.ErrorContains(t, err, "max_total: 2 emojis (limit 1)")
+		assert.Contains(t, out, "Emoji limit exceeded: max_total: 2 emojis (limit 1)")
+	})
+
+	t.Run("max_new limits findings outside the baseline", func(t *testing.T) {
+		baseline := filepath.Join(t.TempDir(), "baseline.json")
+		profile := "profiles:\n  default:\n    unicode_emojis: true\n    max_new: 1\n"
+		_, err := scan(t, profile, &ScanOptions{Output: "findings-json", ReportFile: baseline})
+		require.NoError(t, err)
+
+		_, err = scan(t, profile, &ScanOptions{Baseline: baseline})
+		assert.NoError(t, err, "known findings are not new")
+
+		require.NoError(t, os.WriteFile(main, []byte("// ✅ 🚀\n// 😂\n// 🎉\n"), 0600))
+		out, err := scan(t, profile, &ScanOptions{Baseline: baseline})
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+		assert.Contains(t, out, "Emoji budget exceeded: max_new: 2 emojis not in the baseline (limit 1)")
+
+		_, err = scan(t, profile, &ScanOptions{})
+		assert.NoError(t, err, "max_new needs a baseline")
+
+		_, err = scan(t, profile, &ScanOptions{Baseline: filepath.Join(dir, "missing.json")})
+		assert.ErrorIs(t, err, ErrIO)
+	})
+}
+
+func TestScanHandler_FileFailures(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.docx")
+	require.NoError(t, os.WriteFile(broken, []byte("not a zip archive"), 0600))
+	fixture := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(fixture, []byte("// 😀 😀\n"), 0600))
+
+	scan := func(t *testing.T, args []string, threshold int) error {
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", "", "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		return handler.Execute(context.Background(), scanCmd, args, &ScanOptions{Format: "table", Threshold: threshold})
+	}
+
+	t.Run("some files failed", func(t *testing.T) {
+		err := scan(t, []string{broken, fixture}, 0)
+		require.Error(t, err)
+		assert.Equal(t, ErrPartialFailure, Classify(err))
+		assert.Contains(t, err.Error(), "1 of 2 files could not be processed")
+	})
+
+	t.Run("violations are kept", func(t *testing.T) {
+		err := scan(t, []string{broken, fixture}, 1)
+		require.Error(t, err)
+		assert.Equal(t, ErrPartialFailure, Classify(err))
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+	})
+
+	t.Run("all files failed", func(t *testing.T) {
+		err := scan(t, []string{broken}, 0)
+		require.Error(t, err)
+		assert.Equal(t, ErrIO, Classify(err))
+	})
+}
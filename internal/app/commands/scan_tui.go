@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui/tui"
+)
+
+// defaultEditor opens files from the results browser when neither $VISUAL nor $EDITOR is set.
+const defaultEditor = "vi"
+
+// browseResults shows the violations in results in the interactive results browser.
+func (h *ScanHandler) browseResults(ctx context.Context, results []types.ProcessResult, patterns types.EmojiPatterns,
+	engine *policy.Engine, configFile, profileName string) error {
+	var files []tui.File
+	for _, result := range results {
+		if result.Error == nil && len(result.DetectionResult.Emojis) > 0 {
+			files = append(files, tui.File{Path: result.FilePath, Matches: result.DetectionResult.Emojis})
+		}
+	}
+	if len(files) == 0 {
+		h.ui.Success(ctx, "No emojis found")
+		return nil
+	}
+
+	if configFile == "" {
+		configFile = defaultConfigFile
+	}
+	actions := browserActions(patterns, engine, configFile, profileName, h.env())
+
+	h.logger.Info(ctx, "Opening results browser", "files", len(files))
+	if err := tui.Run(files, actions, os.Stdin, os.Stdout); err != nil {
+		return classify(ErrIO, fmt.Errorf("results browser failed: %w", err))
+	}
+	return nil
+}
+
+// browserActions returns the operations of the results browser. Emojis allowed in the
+// browser are written to configFile and, as the policy was built before, kept by later
+// reloads and cleans of the session too.
+func browserActions(patterns types.EmojiPatterns, engine *policy.Engine, configFile, profileName string,
+	environ []string) tui.Actions {
+	allowed := make(map[string]bool)
+	violations := func(path string, matches []types.EmojiMatch) []types.EmojiMatch {
+		kept := make([]types.EmojiMatch, 0, len(matches))
+		for _, match := range engine.FileViolations(path, matches) {
+			if !allowed[match.Emoji] {
+				kept = append(kept, match)
+			}
+		}
+		return kept
+	}
+
+	return tui.Actions{
+		Load: func(path string) (string, []types.EmojiMatch, error) {
+			content, err := os.ReadFile(path) // #nosec G304 - path was selected by the scan
+			if err != nil {
+				return "", nil, err
+			}
+			result := processor.ProcessFile(path, patterns, engine.ProcessingConfig())
+			if result.IsErr() {
+				return "", nil, result.Error()
+			}
+			processed := result.Unwrap()
+			if processed.Error != nil {
+				return "", nil, processed.Error
+			}
+			return string(content), violations(path, processed.DetectionResult.Emojis), nil
+		},
+		Clean: func(path string) error {
+			modifyConfig := policyModifyConfig(engine)
+			modifyConfig.Decide = func(_, _ string, match types.EmojiMatch) processor.MatchDecision {
+				if allowed[match.Emoji] {
+					return processor.MatchDecision{Action: processor.ActionKeep}
+				}
+				return processor.MatchDecision{Action: processor.ActionRemove}
+			}
+			result := processor.ModifyFile(path, patterns, modifyConfig, engine.Allowlist())
+			if result.IsErr() {
+				return result.Error()
+			}
+			return result.Unwrap().Error
+		},
+		Allow: func(emoji string) error {
+			if _, err := config.AddToAllowlist(configFile, profileName, []string{emoji}); err != nil {
+				return err
+			}
+			allowed[emoji] = true
+			return nil
+		},
+		Edit: func(path string, line int) *exec.Cmd {
+			return editorCommand(environ, path, line)
+		},
+	}
+}
+
+// editorCommand returns the command opening path on line in $VISUAL or $EDITOR. The
+// +line argument is understood by vi, vim, nano, emacs and most other editors.
+func editorCommand(environ []string, path string, line int) *exec.Cmd {
+	editor := defaultEditor
+	for _, name := range []string{"EDITOR", "VISUAL"} {
+		if value := strings.TrimSpace(lookupEnv(environ, name)); value != "" {
+			editor = value
+		}
+	}
+	args := strings.Fields(editor)
+	args = append(args, "+"+strconv.Itoa(line), path)
+	return exec.Command(args[0], args[1:]...) // #nosec G204 - the editor is chosen by the user
+}
+
+// lookupEnv returns the value of the variable name in environ.
+func lookupEnv(environ []string, name string) string {
+	value := ""
+	for _, entry := range environ {
+		if key, v, ok := strings.Cut(entry, "="); ok && key == name {
+			value = v
+		}
+	}
+	return value
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserActions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("// ship it 🚀\n// done ✅\n"), 0600))
+	configPath := filepath.Join(dir, ".antimoji.yaml")
+
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.EmojiAllowlist = nil
+	engine, err := policy.New(context.Background(), profile, policy.Options{Operation: "scan", Threshold: policy.NoThreshold})
+	require.NoError(t, err)
+	patterns, err := engine.Patterns(context.Background())
+	require.NoError(t, err)
+
+	actions := browserActions(patterns, engine, configPath, "default", nil)
+
+	content, matches, err := actions.Load(path)
+	require.NoError(t, err)
+	assert.Contains(t, content, "ship it")
+	require.Len(t, matches, 2)
+
+	require.NoError(t, actions.Allow("✅"))
+	_, matches, err = actions.Load(path)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "🚀", matches[0].Emoji)
+
+	saved, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), "✅")
+
+	// Cleaning keeps the emojis allowed during the session
+	require.NoError(t, actions.Clean(path))
+	cleaned, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(cleaned), "🚀")
+	assert.Contains(t, string(cleaned), "✅")
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  []string
+		expected []string
+	}{
+		{"default", nil, []string{defaultEditor, "+12", "a.go"}},
+		{"editor", []string{"EDITOR=nano"}, []string{"nano", "+12", "a.go"}},
+		{"visual wins", []string{"VISUAL=code --wait", "EDITOR=nano"}, []string{"code", "--wait", "+12", "a.go"}},
+		{"empty is ignored", []string{"VISUAL=", "EDITOR=nano"}, []string{"nano", "+12", "a.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, editorCommand(tt.environ, "a.go", 12).Args)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/analysis"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// workspaceRootScan is the outcome of scanning one root of a workspace.
+type workspaceRootScan struct {
+	root    config.WorkspaceRoot
+	results []types.ProcessResult
+	// violation is the threshold or budget error of the root, if any
+	violation error
+}
+
+// scanWorkspace scans each root of the workspace file at opts.Workspace with its own
+// configuration and profile, then reports the results as one scan.
+func (h *ScanHandler) scanWorkspace(ctx context.Context, cmd *cobra.Command, opts *ScanOptions, startTime time.Time) error {
+	workspace, err := config.LoadWorkspace(opts.Workspace)
+	if err != nil {
+		return err
+	}
+	h.logger.Info(ctx, "Scanning workspace", "workspace", workspace.Path, "roots", len(workspace.Roots))
+
+	var results []types.ProcessResult
+	var scans []workspaceRootScan
+	for _, root := range workspace.Roots {
+		scan, err := h.scanWorkspaceRoot(ctx, cmd, root, opts)
+		if err != nil {
+			return fmt.Errorf("workspace root %s: %w", root.Name, err)
+		}
+		scans = append(scans, scan)
+		results = append(results, scan.results...)
+	}
+
+	if err := h.displayResults(ctx, results, opts, time.Since(startTime)); err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to display results: %w", err))
+	}
+	for _, scan := range scans {
+		h.ui.Result(ctx, "Root %s: %d emojis in %d files", scan.root.Name, h.countTotalEmojis(scan.results), len(scan.results))
+	}
+
+	if err := reportFailures(ctx, h.ui, newErrorReport("scan", len(results), scanFailures(results)), opts.MaxErrors, opts.ErrorReport); err != nil {
+		return err
+	}
+	if opts.Top > 0 {
+		displayHotSpots(ctx, h.ui, analysis.AnalyzeDensity(results).Top(opts.Top))
+	}
+	if opts.Output != "" {
+		if err := h.writeReport(ctx, results, opts); err != nil {
+			return err
+		}
+	}
+	if opts.Record {
+		if err := h.recordHistory(ctx, results, opts.HistoryFile); err != nil {
+			return err
+		}
+	}
+
+	// Each root is held to the threshold and budgets of its own profile
+	var violations []error
+	for _, scan := range scans {
+		if scan.violation != nil {
+			h.ui.Error(ctx, "Root %s: %v", scan.root.Name, scan.violation)
+			violations = append(violations, fmt.Errorf("root %s: %w", scan.root.Name, scan.violation))
+		}
+	}
+	violation := errors.Join(violations...)
+
+	if failed := countFileFailures(results); failed > 0 {
+		h.logger.Error(ctx, "Some files could not be processed", "failed", failed, "total", len(results))
+		return fileFailureError(failed, len(results), violation)
+	}
+	if violation != nil {
+		return violation
+	}
+
+	h.logger.Info(ctx, "Workspace scan completed successfully")
+	return nil
+}
+
+// scanWorkspaceRoot scans one root of a workspace. The root's configuration file is
+// used, or its .antimoji.yaml when it names none, with the root's profile or --profile.
+func (h *ScanHandler) scanWorkspaceRoot(ctx context.Context, cmd *cobra.Command, root config.WorkspaceRoot, opts *ScanOptions) (workspaceRootScan, error) {
+	scan := workspaceRootScan{root: root}
+	strictConfig, _ := cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+	sets, _ := cmd.Root().PersistentFlags().GetStringArray(setFlag)
+	profileName := root.Profile
+	if profileName == "" {
+		profileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+	}
+
+	configFile := root.Config
+	if configFile == "" {
+		if _, err := os.Stat(filepath.Join(root.Path, defaultConfigFile)); err == nil {
+			configFile = filepath.Join(root.Path, defaultConfigFile)
+		}
+	}
+	cfg := config.DefaultConfig()
+	if configFile != "" {
+		configResult := h.loadConfig(configFile, profileName, strictConfig)
+		if configResult.IsErr() {
+			return scan, fmt.Errorf("failed to load config: %w", configResult.Error())
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+
+	profileResult := config.GetProfile(cfg, profileName)
+	if profileResult.IsErr() {
+		return scan, fmt.Errorf("failed to get profile '%s': %w", profileName, profileResult.Error())
+	}
+	resolution, err := resolveProfileEnv(profileResult.Unwrap(), configFile != "", sets, h.env())
+	if err != nil {
+		return scan, err
+	}
+	profile := resolution.Profile
+
+	// As in a single scan, the profile's max_total applies without --threshold
+	threshold := policy.ResolveThreshold(opts.Threshold, thresholdGiven(cmd, opts), profileThreshold(resolution), policy.NoThreshold)
+	if thresholdGiven(cmd, opts) {
+		if err := lockedThresholdError(resolution, threshold); err != nil {
+			return scan, err
+		}
+	}
+	engine, err := policy.New(ctx, profile, policy.Options{
+		Operation:       "scan",
+		Recursive:       opts.Recursive,
+		IncludePattern:  opts.IncludePattern,
+		ExcludePattern:  opts.ExcludePattern,
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       threshold,
+		Rules:           resolution.Policy.Rules(),
+	})
+	if err != nil {
+		return scan, err
+	}
+
+	discovery, err := engine.SelectFiles([]string{root.Path})
+	if err != nil {
+		return scan, classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+	reportSkippedSymlinks(ctx, h.ui, discovery.Symlinks)
+	patterns, err := h.patterns(ctx, engine)
+	if err != nil {
+		return scan, err
+	}
+
+	h.logger.Info(ctx, "Scanning workspace root", "root", root.Name, "profile", profileName, "files", len(discovery.Files))
+	results := processor.ProcessBatch(discovery.Files, patterns, engine.ProcessingConfig(), processor.BatchOptions{
+		MaxErrors: opts.MaxErrors,
+	})
+	h.metrics.ObserveResults(results)
+	if engine.Applies() {
+		results = engine.Apply(results)
+	}
+	scan.results = results
+
+	errorsFound, _ := policy.CountSeverities(results)
+	scan.violation = engine.Evaluate(errorsFound)
+	if scan.violation == nil {
+		scan.violation = policy.BudgetError(engine.Budgets(results))
+	}
+	return scan, nil
+}
+
+// workspaceConflictError reports the options given with --workspace that it does not
+// support.
+func workspaceConflictError(opts *ScanOptions, args []string) error {
+	return conflictError("--workspace", []scanOption{
+		{"path arguments", len(args) > 0},
+		{"--rev-range", opts.RevRange != ""},
+		{"--commit-messages", opts.CommitMessages != ""},
+		{"--include-names", opts.IncludeNames},
+		{"--cache", opts.Cache},
+		{"--tui", opts.TUI},
+		{"--via-daemon", opts.ViaDaemon},
+		{"--group-by", opts.GroupBy != ""},
+		{"--summary-file", opts.SummaryFile != ""},
+		{"--expect-summary", opts.ExpectSummary != ""},
+		{"--baseline", opts.Baseline != ""},
+		{"--changed-since", opts.ChangedSince != ""},
+	})
+}
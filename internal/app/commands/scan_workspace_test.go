@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanHandler_Workspace(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	write("api/main.go", "// 🚀\n")
+	write("configs/api.yaml", `profiles:
+  strict:
+    unicode_emojis: true
+    emoji_thresholds:
+      "🚀": 0
+`)
+	write("web/index.md", "# 🎉\n")
+	write("web/.antimoji.yaml", `profiles:
+  relaxed:
+    unicode_emojis: true
+    emoji_allowlist: ["🎉"]
+`)
+	write("workspace.yaml", `roots:
+  - name: api
+    path: api
+    config: configs/api.yaml
+    profile: strict
+  - path: web
+    profile: relaxed
+`)
+
+	scan := func(t *testing.T, args []string, opts *ScanOptions) (string, error) {
+		var out bytes.Buffer
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", "", "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &out, ErrorWriter: &out}))
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		err := handler.Execute(context.Background(), scanCmd, args, opts)
+		return out.String(), err
+	}
+
+	t.Run("each root uses its own profile", func(t *testing.T) {
+		out, err := scan(t, nil, &ScanOptions{Recursive: true, Format: "table", Workspace: filepath.Join(dir, "workspace.yaml")})
+		assert.ErrorIs(t, err, ErrEmojiThresholdExceeded)
+		assert.ErrorContains(t, err, "root api: ")
+		assert.NotContains(t, err.Error(), "root web")
+		assert.Contains(t, out, "Scanned 3 files, found 1 emojis in 1 files")
+		assert.Contains(t, out, "Root api: 1 emojis in 1 files")
+		assert.Contains(t, out, "Root web: 0 emojis in 2 files")
+	})
+
+	t.Run("missing workspace file", func(t *testing.T) {
+		_, err := scan(t, nil, &ScanOptions{Format: "table", Workspace: filepath.Join(dir, "missing.yaml")})
+		assert.ErrorIs(t, Classify(err), ErrIO)
+	})
+
+	t.Run("conflicting options", func(t *testing.T) {
+		_, err := scan(t, []string{"."}, &ScanOptions{Format: "table", Workspace: "workspace.yaml", Cache: true})
+		assert.EqualError(t, err, "--workspace cannot be used with path arguments, --cache")
+	})
+}
@@ -0,0 +1,632 @@
+package commands
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+// DefaultMaxRequestSize limits the body of serve API requests.
+const DefaultMaxRequestSize = 10 << 20
+
+// ServeOptions holds the options for the serve command.
+type ServeOptions struct {
+	Addr            string
+	GRPCAddr        string
+	TLSCert         string
+	TLSKey          string
+	TLSClientCA     string
+	MaxRequestSize  int64
+	IgnoreAllowlist bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+}
+
+// ServeHandler handles the serve command with dependency injection.
+type ServeHandler struct {
+	logger  logging.Logger
+	ui      ui.UserOutput
+	metrics *metrics.Metrics
+}
+
+// NewServeHandler creates a new serve command handler.
+func NewServeHandler(logger logging.Logger, ui ui.UserOutput) *ServeHandler {
+	return &ServeHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithMetrics sets the metrics that served scans and cleans are recorded in (defaults
+// to none).
+func (h *ServeHandler) WithMetrics(m *metrics.Metrics) *ServeHandler {
+	h.metrics = m
+	return h
+}
+
+// CreateCommand creates the serve cobra command.
+func (h *ServeHandler) CreateCommand() *cobra.Command {
+	opts := &ServeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve scans and cleaning over an HTTP API",
+		Long: `Serve the profile's emoji policy over HTTP, so that services can check and
+clean content without bundling antimoji.
+
+Endpoints:
+  POST /scan     report the emojis of the content sent
+  POST /clean    return the content sent with its emojis removed
+  GET  /config   show the profile the server applies
+  GET  /healthz  report that the server is up
+
+Content is sent as JSON, {"path": "README.md", "content": "..."} or
+{"files": [{"path": ..., "content": ...}]}, or as multipart/form-data file
+uploads named by their file names. Paths only select the language of the
+content and the profile's include and exclude patterns; nothing is read from or
+written to disk. Responses are JSON; /scan reports whether the violations are
+within the profile's max_total, none by default.
+
+With --grpc-addr, the same scanning and cleaning is also served over gRPC (Scan,
+Clean and StreamScan of the antimoji.v1.Antimoji service in
+api/antimoji/v1/antimoji.proto). --tls-cert and --tls-key serve both APIs over
+TLS; --tls-client-ca also requires clients to present a certificate the CA
+signed (mutual TLS).
+
+A local configuration file is reloaded when it or a local file it extends
+changes; an edit that fails to load is logged and the previous policy kept.
+Stop the server with Ctrl-C.
+
+Examples:
+  antimoji serve --addr :8080
+  antimoji serve --grpc-addr :9443 --tls-cert server.pem --tls-key server-key.pem --tls-client-ca clients.pem
+  curl -s localhost:8080/scan -d '{"path": "a.md", "content": "Ship it 🚀"}'
+  curl -s localhost:8080/clean -F file=@README.md`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.Execute(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Addr, "addr", ":8080", "address to listen on")
+	cmd.Flags().StringVar(&opts.GRPCAddr, "grpc-addr", "", "also serve the gRPC API on this address (e.g. :9090)")
+	cmd.Flags().StringVar(&opts.TLSCert, "tls-cert", "", "PEM certificate to serve TLS with")
+	cmd.Flags().StringVar(&opts.TLSKey, "tls-key", "", "PEM private key of --tls-cert")
+	cmd.Flags().StringVar(&opts.TLSClientCA, "tls-client-ca", "", "PEM certificate authorities client certificates must be signed by")
+	cmd.Flags().Int64Var(&opts.MaxRequestSize, "max-request-size", DefaultMaxRequestSize, "largest request body accepted, in bytes")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+
+	return cmd
+}
+
+// Execute serves the API until ctx is cancelled or the process is interrupted.
+func (h *ServeHandler) Execute(parentCtx context.Context, opts *ServeOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = ctxutil.WithOperation(ctx, "serve")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	service, err := h.service(ctx, opts)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := serverTLS(opts)
+	if err != nil {
+		return err
+	}
+	if service.manager != nil {
+		go func() {
+			err := service.manager.Watch(ctx, func(err error) {
+				h.logger.Warn(ctx, "Config reload failed, keeping the current policy", "error", err)
+			})
+			if err != nil {
+				h.logger.Warn(ctx, "Config changes will not be reloaded", "error", err)
+			}
+		}()
+	}
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to listen on %s: %w", opts.Addr, err))
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	server := &http.Server{Handler: h.api(service, opts), ReadHeaderTimeout: 5 * time.Second}
+
+	var grpcServer *grpc.Server
+	grpcDone := make(chan error, 1)
+	if opts.GRPCAddr != "" {
+		grpcListener, err := net.Listen("tcp", opts.GRPCAddr)
+		if err != nil {
+			_ = listener.Close()
+			return classify(ErrIO, fmt.Errorf("failed to listen on %s: %w", opts.GRPCAddr, err))
+		}
+		grpcServer = h.grpc(service, opts, tlsConfig)
+		go func() {
+			grpcDone <- grpcServer.Serve(grpcListener)
+			// Either server failing stops the other
+			cancel()
+		}()
+		h.logger.Info(ctx, "gRPC server started", "addr", grpcListener.Addr().String())
+		h.ui.Info(ctx, "Antimoji gRPC API listening on %s", grpcListener.Addr())
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+		if grpcServer != nil {
+			stopped := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-shutdownCtx.Done():
+				grpcServer.Stop()
+			}
+		}
+	}()
+
+	h.logger.Info(ctx, "API server started", "addr", listener.Addr().String())
+	h.ui.Info(ctx, "Antimoji API listening on %s", listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+		return classify(ErrIO, err)
+	}
+	if grpcServer != nil {
+		if err := <-grpcDone; err != nil {
+			return classify(ErrIO, fmt.Errorf("gRPC server failed: %w", err))
+		}
+	}
+	h.logger.Info(ctx, "API server stopped", "addr", listener.Addr().String())
+	return nil
+}
+
+// API returns the HTTP handler of the serve API for the profile opts selects.
+func (h *ServeHandler) API(ctx context.Context, opts *ServeOptions) (http.Handler, error) {
+	service, err := h.service(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return h.api(service, opts), nil
+}
+
+// service resolves the profile opts selects and returns the service applying it. A
+// local configuration file is managed, so the service follows its changes once the
+// manager is watched; remote files are loaded once.
+func (h *ServeHandler) service(ctx context.Context, opts *ServeOptions) (*serveService, error) {
+	service := &serveService{profile: opts.ProfileName, metrics: h.metrics}
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		manager, err := config.NewManagerWithLoader(opts.ConfigFile, opts.ProfileName, configLoader(opts.StrictConfig))
+		if err == nil {
+			service.manager = manager
+			cfg = manager.Current().Config
+		} else {
+			// Report the error a plain load gives, or load what cannot be managed
+			configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+			if configResult.IsErr() {
+				return nil, classify(ErrConfig, fmt.Errorf("failed to load config: %w", configResult.Error()))
+			}
+			cfg = configResult.Unwrap()
+		}
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return nil, classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error()))
+	}
+	current, err := h.policy(ctx, profileResult.Unwrap(), opts)
+	if err != nil {
+		return nil, err
+	}
+	service.policy = current
+
+	if service.manager != nil {
+		service.manager.Subscribe(func(snapshot config.Snapshot) {
+			reloaded, err := h.policy(ctx, snapshot.Profile, opts)
+			if err != nil {
+				h.logger.Warn(ctx, "Config reload failed, keeping the current policy", "error", err)
+				return
+			}
+			service.swap(reloaded)
+			h.logger.Info(ctx, "Config reloaded", "config", opts.ConfigFile, "version", snapshot.Version)
+		})
+	}
+	return service, nil
+}
+
+// policy resolves profile with the overrides of opts and returns the policy applying it.
+func (h *ServeHandler) policy(ctx context.Context, profile config.Profile, opts *ServeOptions) (servePolicy, error) {
+	resolution, err := resolveProfile(profile, opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return servePolicy{}, err
+	}
+	engine, err := policy.New(ctx, resolution.Profile, policy.Options{
+		Operation:       "serve",
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       policy.ResolveThreshold(0, false, profileThreshold(resolution), 0),
+		Rules:           resolution.Policy.Rules(),
+	})
+	if err != nil {
+		return servePolicy{}, err
+	}
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return servePolicy{}, err
+	}
+	return servePolicy{engine: engine, patterns: patterns}, nil
+}
+
+// api returns the HTTP handler serving service.
+func (h *ServeHandler) api(service *serveService, opts *ServeOptions) http.Handler {
+	api := &serveAPI{
+		logger:  h.logger,
+		service: service,
+		maxSize: maxRequestSize(opts),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /scan", api.scan)
+	mux.HandleFunc("POST /clean", api.clean)
+	mux.HandleFunc("GET /config", api.config)
+	mux.HandleFunc("GET /healthz", api.healthz)
+	return api.logged(mux)
+}
+
+// maxRequestSize returns the largest request opts accept.
+func maxRequestSize(opts *ServeOptions) int64 {
+	if opts.MaxRequestSize <= 0 {
+		return DefaultMaxRequestSize
+	}
+	return opts.MaxRequestSize
+}
+
+// ServeFile is a file sent to or returned by the serve API.
+type ServeFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ServeRequest is the JSON body of /scan and /clean requests: a single file or a list
+// of files.
+type ServeRequest struct {
+	Path    string      `json:"path,omitempty"`
+	Content string      `json:"content,omitempty"`
+	Files   []ServeFile `json:"files,omitempty"`
+	// Replacement replaces each removed emoji in /clean responses
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// ScanResponse is the response of /scan.
+type ScanResponse struct {
+	Files []ScannedFile `json:"files"`
+	// Violations counts the findings of severity error
+	Violations int `json:"violations"`
+	Warnings   int `json:"warnings"`
+	// Passed reports whether the violations are within the profile's max_total
+	Passed bool `json:"passed"`
+}
+
+// ScannedFile is the findings of a file in a ScanResponse.
+type ScannedFile struct {
+	Path     string             `json:"path"`
+	Findings []types.EmojiMatch `json:"findings"`
+	// Skipped tells why the file was not checked
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// CleanResponse is the response of /clean.
+type CleanResponse struct {
+	Files         []CleanedFile `json:"files"`
+	EmojisRemoved int           `json:"emojis_removed"`
+}
+
+// CleanedFile is a file of a CleanResponse, with its content cleaned.
+type CleanedFile struct {
+	Path          string `json:"path"`
+	Content       string `json:"content"`
+	Modified      bool   `json:"modified"`
+	EmojisRemoved int    `json:"emojis_removed"`
+	Skipped       string `json:"skipped,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// servePolicy is the engine of a profile with the emoji patterns it detects.
+type servePolicy struct {
+	engine   *policy.Engine
+	patterns types.EmojiPatterns
+}
+
+// excluded tells why the profile does not check the file at path, or returns "".
+func (p servePolicy) excluded(path string) string {
+	if path != "" && !p.engine.FileFilter().ShouldInclude(path).Include {
+		return "excluded by the profile"
+	}
+	return ""
+}
+
+// serveService scans and cleans files with the policy of one profile, for both the
+// HTTP and the gRPC API.
+type serveService struct {
+	profile string
+	metrics *metrics.Metrics
+	// manager reloads the configuration file; nil when it is loaded once
+	manager *config.Manager
+
+	mu     sync.RWMutex
+	policy servePolicy
+}
+
+// current returns the policy requests are served with.
+func (s *serveService) current() servePolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.policy
+}
+
+// swap replaces the policy, for the requests that follow.
+func (s *serveService) swap(p servePolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = p
+}
+
+// scan reports the findings in files.
+func (s *serveService) scan(files []ServeFile) ScanResponse {
+	p := s.current()
+	response := ScanResponse{Files: make([]ScannedFile, 0, len(files))}
+	for _, file := range files {
+		scanned := s.detect(p, file)
+		for _, match := range scanned.Findings {
+			if match.Severity == types.SeverityWarn {
+				response.Warnings++
+			} else {
+				response.Violations++
+			}
+		}
+		response.Files = append(response.Files, scanned)
+	}
+	response.Passed = p.engine.Evaluate(response.Violations) == nil
+	return response
+}
+
+// scanFile reports the findings in file.
+func (s *serveService) scanFile(file ServeFile) ScannedFile {
+	return s.detect(s.current(), file)
+}
+
+// detect reports the findings of p in file.
+func (s *serveService) detect(p servePolicy, file ServeFile) ScannedFile {
+	scanned := ScannedFile{Path: file.Path, Findings: []types.EmojiMatch{}}
+	if skip := p.excluded(file.Path); skip != "" {
+		scanned.Skipped = skip
+		return scanned
+	}
+
+	result := processor.DetectBytes(file.Path, []byte(file.Content), p.patterns, p.engine.ProcessingConfig())
+	s.metrics.ObserveResults([]types.ProcessResult{result})
+	switch {
+	case result.Error != nil:
+		scanned.Error = result.Error.Error()
+	case result.BinaryReason != "":
+		scanned.Skipped = "binary content (" + result.BinaryReason + ")"
+	default:
+		result = p.engine.Apply([]types.ProcessResult{result})[0]
+		for _, match := range result.DetectionResult.Emojis {
+			// Debug information describes the detector, not the content
+			match.DebugInfo = nil
+			scanned.Findings = append(scanned.Findings, match)
+		}
+	}
+	return scanned
+}
+
+// clean removes the emojis of files, replacing each with replacement.
+func (s *serveService) clean(files []ServeFile, replacement string) CleanResponse {
+	p := s.current()
+	modifyConfig := policyModifyConfig(p.engine)
+	modifyConfig.Replacement = replacement
+	response := CleanResponse{Files: make([]CleanedFile, 0, len(files))}
+	processed, modified, failed := 0, 0, 0
+	for _, file := range files {
+		cleaned := CleanedFile{Path: file.Path, Content: file.Content}
+		if skip := p.excluded(file.Path); skip != "" {
+			cleaned.Skipped = skip
+			response.Files = append(response.Files, cleaned)
+			continue
+		}
+
+		content, result := processor.CleanBytes(file.Path, []byte(file.Content), p.patterns, modifyConfig, p.engine.Allowlist())
+		processed++
+		switch {
+		case result.Error != nil:
+			failed++
+			cleaned.Error = result.Error.Error()
+		case result.BinaryReason != "":
+			cleaned.Skipped = "binary content (" + result.BinaryReason + ")"
+		default:
+			cleaned.Content = string(content)
+			cleaned.Modified = result.Modified
+			if result.Modified {
+				modified++
+			}
+			cleaned.EmojisRemoved = result.EmojisRemoved
+			response.EmojisRemoved += result.EmojisRemoved
+		}
+		response.Files = append(response.Files, cleaned)
+	}
+	s.metrics.ObserveClean(processed, modified, failed, response.EmojisRemoved)
+	return response
+}
+
+// serveAPI serves the HTTP endpoints of the serve command.
+type serveAPI struct {
+	logger  logging.Logger
+	service *serveService
+	maxSize int64
+}
+
+// statusRecorder remembers the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logged logs each request served by next.
+func (a *serveAPI) logged(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		a.logger.Info(r.Context(), "API request served",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration", time.Since(startTime))
+	})
+}
+
+func (a *serveAPI) healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (a *serveAPI) config(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, struct {
+		Profile  string         `json:"profile"`
+		Settings config.Profile `json:"settings"`
+	}{a.service.profile, a.service.current().engine.Profile()})
+}
+
+func (a *serveAPI) scan(w http.ResponseWriter, r *http.Request) {
+	request, status, err := a.read(w, r)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.service.scan(request.Files))
+}
+
+func (a *serveAPI) clean(w http.ResponseWriter, r *http.Request) {
+	request, status, err := a.read(w, r)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.service.clean(request.Files, request.Replacement))
+}
+
+// read reads the files of a /scan or /clean request, from a JSON body or multipart
+// file uploads, and returns the status code of the error when it fails.
+func (a *serveAPI) read(w http.ResponseWriter, r *http.Request) (ServeRequest, int, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, a.maxSize)
+	status := func(err error) int {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return http.StatusRequestEntityTooLarge
+		}
+		return http.StatusBadRequest
+	}
+
+	var request ServeRequest
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(a.maxSize); err != nil {
+			return request, status(err), fmt.Errorf("invalid multipart request: %w", err)
+		}
+		request.Replacement = r.FormValue("replacement")
+		fields := make([]string, 0, len(r.MultipartForm.File))
+		for field := range r.MultipartForm.File {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			for _, header := range r.MultipartForm.File[field] {
+				file, err := header.Open()
+				if err != nil {
+					return request, http.StatusBadRequest, fmt.Errorf("failed to read %s: %w", header.Filename, err)
+				}
+				content, err := io.ReadAll(file)
+				_ = file.Close()
+				if err != nil {
+					return request, http.StatusBadRequest, fmt.Errorf("failed to read %s: %w", header.Filename, err)
+				}
+				request.Files = append(request.Files, ServeFile{Path: header.Filename, Content: string(content)})
+			}
+		}
+	} else {
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&request); err != nil {
+			return request, status(err), fmt.Errorf("invalid JSON request: %w", err)
+		}
+		if request.Path != "" || request.Content != "" {
+			request.Files = append([]ServeFile{{Path: request.Path, Content: request.Content}}, request.Files...)
+		}
+	}
+
+	if len(request.Files) == 0 {
+		return request, http.StatusBadRequest, fmt.Errorf("no content sent; send a path and content, files, or multipart file uploads")
+	}
+	return request, http.StatusOK, nil
+}
+
+// writeJSON writes value as the JSON response with status.
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes err as the JSON error response with status.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
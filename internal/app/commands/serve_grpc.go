@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	antimojiv1 "github.com/antimoji/antimoji/api/antimoji/v1"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// GRPC returns the gRPC server of the serve API for the profile opts selects, secured
+// with the TLS settings of opts.
+func (h *ServeHandler) GRPC(ctx context.Context, opts *ServeOptions) (*grpc.Server, error) {
+	service, err := h.service(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := serverTLS(opts)
+	if err != nil {
+		return nil, err
+	}
+	return h.grpc(service, opts, tlsConfig), nil
+}
+
+// grpc returns the gRPC server serving service, over TLS when tlsConfig is set.
+func (h *ServeHandler) grpc(service *serveService, opts *ServeOptions, tlsConfig *tls.Config) *grpc.Server {
+	api := &serveGRPC{logger: h.logger, service: service}
+	serverOptions := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(int(maxRequestSize(opts))),
+		grpc.UnaryInterceptor(api.loggedUnary),
+		grpc.StreamInterceptor(api.loggedStream),
+	}
+	if tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(serverOptions...)
+	antimojiv1.RegisterAntimojiServer(server, api)
+	return server
+}
+
+// serverTLS returns the TLS configuration of the serve API, or nil when opts set no
+// certificate. With a client CA, clients must present a certificate it signed.
+func serverTLS(opts *ServeOptions) (*tls.Config, error) {
+	if opts.TLSCert == "" && opts.TLSKey == "" {
+		if opts.TLSClientCA != "" {
+			return nil, classify(ErrConfig, fmt.Errorf("--tls-client-ca needs --tls-cert and --tls-key"))
+		}
+		return nil, nil
+	}
+	if opts.TLSCert == "" || opts.TLSKey == "" {
+		return nil, classify(ErrConfig, fmt.Errorf("--tls-cert and --tls-key must be given together"))
+	}
+	certificate, err := tls.LoadX509KeyPair(opts.TLSCert, opts.TLSKey)
+	if err != nil {
+		return nil, classify(ErrConfig, fmt.Errorf("failed to load TLS certificate: %w", err))
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if opts.TLSClientCA != "" {
+		pem, err := os.ReadFile(opts.TLSClientCA)
+		if err != nil {
+			return nil, classify(ErrConfig, fmt.Errorf("failed to read client CA: %w", err))
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return nil, classify(ErrConfig, fmt.Errorf("client CA file %s contains no PEM certificates", opts.TLSClientCA))
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// serveGRPC serves the gRPC API of the serve command.
+type serveGRPC struct {
+	antimojiv1.UnimplementedAntimojiServer
+	logger  logging.Logger
+	service *serveService
+}
+
+func (g *serveGRPC) Scan(_ context.Context, request *antimojiv1.ScanRequest) (*antimojiv1.ScanResponse, error) {
+	if len(request.GetFiles()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no files sent")
+	}
+	response := g.service.scan(serveFiles(request.GetFiles()))
+	scanned := make([]*antimojiv1.ScannedFile, 0, len(response.Files))
+	for _, file := range response.Files {
+		scanned = append(scanned, protoScannedFile(file))
+	}
+	return &antimojiv1.ScanResponse{
+		Files:      scanned,
+		Violations: int32(response.Violations),
+		Warnings:   int32(response.Warnings),
+		Passed:     response.Passed,
+	}, nil
+}
+
+func (g *serveGRPC) Clean(_ context.Context, request *antimojiv1.CleanRequest) (*antimojiv1.CleanResponse, error) {
+	if len(request.GetFiles()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no files sent")
+	}
+	response := g.service.clean(serveFiles(request.GetFiles()), request.GetReplacement())
+	cleaned := make([]*antimojiv1.CleanedFile, 0, len(response.Files))
+	for _, file := range response.Files {
+		cleaned = append(cleaned, &antimojiv1.CleanedFile{
+			Path:          file.Path,
+			Content:       []byte(file.Content),
+			Modified:      file.Modified,
+			EmojisRemoved: int32(file.EmojisRemoved),
+			Skipped:       file.Skipped,
+			Error:         file.Error,
+		})
+	}
+	return &antimojiv1.CleanResponse{
+		Files:         cleaned,
+		EmojisRemoved: int32(response.EmojisRemoved),
+	}, nil
+}
+
+func (g *serveGRPC) StreamScan(stream antimojiv1.Antimoji_StreamScanServer) error {
+	for {
+		file, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		scanned := g.service.scanFile(ServeFile{Path: file.GetPath(), Content: string(file.GetContent())})
+		if err := stream.Send(protoScannedFile(scanned)); err != nil {
+			return err
+		}
+	}
+}
+
+// loggedUnary logs each unary call served.
+func (g *serveGRPC) loggedUnary(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	startTime := time.Now()
+	response, err := handler(ctx, request)
+	g.logger.Info(ctx, "API call served",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"duration", time.Since(startTime))
+	return response, err
+}
+
+// loggedStream logs each stream served once it ends.
+func (g *serveGRPC) loggedStream(server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	startTime := time.Now()
+	err := handler(server, stream)
+	g.logger.Info(stream.Context(), "API call served",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"duration", time.Since(startTime))
+	return err
+}
+
+// serveFiles converts the files of a gRPC request.
+func serveFiles(files []*antimojiv1.File) []ServeFile {
+	converted := make([]ServeFile, 0, len(files))
+	for _, file := range files {
+		converted = append(converted, ServeFile{Path: file.GetPath(), Content: string(file.GetContent())})
+	}
+	return converted
+}
+
+// protoScannedFile converts a scanned file for a gRPC response.
+func protoScannedFile(file ScannedFile) *antimojiv1.ScannedFile {
+	findings := make([]*antimojiv1.Finding, 0, len(file.Findings))
+	for _, match := range file.Findings {
+		severity := antimojiv1.Severity_SEVERITY_ERROR
+		if match.Severity == types.SeverityWarn {
+			severity = antimojiv1.Severity_SEVERITY_WARN
+		}
+		findings = append(findings, &antimojiv1.Finding{
+			Emoji:    match.Emoji,
+			Start:    int64(match.Start),
+			End:      int64(match.End),
+			Line:     int32(match.Line),
+			Column:   int32(match.Column),
+			Category: string(match.Category),
+			Severity: severity,
+		})
+	}
+	return &antimojiv1.ScannedFile{
+		Path:     file.Path,
+		Findings: findings,
+		Skipped:  file.Skipped,
+		Error:    file.Error,
+	}
+}
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	antimojiv1 "github.com/antimoji/antimoji/api/antimoji/v1"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// newServeGRPC serves the gRPC API with opts and returns a client connected with
+// credentials.
+func newServeGRPC(t *testing.T, opts *ServeOptions, creds credentials.TransportCredentials) antimojiv1.AntimojiClient {
+	if opts.ProfileName == "" {
+		opts.ProfileName = "default"
+	}
+	server, err := NewServeHandler(logging.NewMockLogger(), quietOutput()).GRPC(context.Background(), opts)
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return antimojiv1.NewAntimojiClient(conn)
+}
+
+func TestServeHandler_GRPC(t *testing.T) {
+	client := newServeGRPC(t, &ServeOptions{}, insecure.NewCredentials())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("scan", func(t *testing.T) {
+		response, err := client.Scan(ctx, &antimojiv1.ScanRequest{Files: []*antimojiv1.File{
+			{Path: "notes.md", Content: []byte("Ship 🚀 with `code 🎉`")},
+			{Path: "logo.png", Content: []byte("\x00\x00\x00🚀")},
+		}})
+		require.NoError(t, err)
+		require.Len(t, response.Files, 2)
+		require.Len(t, response.Files[0].Findings, 1, "Markdown code is preserved")
+		finding := response.Files[0].Findings[0]
+		assert.Equal(t, "🚀", finding.Emoji)
+		assert.Equal(t, int32(1), finding.Line)
+		assert.Equal(t, int32(6), finding.Column)
+		assert.Equal(t, antimojiv1.Severity_SEVERITY_ERROR, finding.Severity)
+		assert.Contains(t, response.Files[1].Skipped, "binary")
+		assert.Equal(t, int32(1), response.Violations)
+		assert.False(t, response.Passed)
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		response, err := client.Clean(ctx, &antimojiv1.CleanRequest{
+			Files:       []*antimojiv1.File{{Path: "a.txt", Content: []byte("Done ✅ :)")}},
+			Replacement: "!",
+		})
+		require.NoError(t, err)
+		require.Len(t, response.Files, 1)
+		assert.Equal(t, "Done ! !", string(response.Files[0].Content))
+		assert.True(t, response.Files[0].Modified)
+		assert.Equal(t, int32(2), response.EmojisRemoved)
+	})
+
+	t.Run("stream scan", func(t *testing.T) {
+		stream, err := client.StreamScan(ctx)
+		require.NoError(t, err)
+		for _, file := range []*antimojiv1.File{
+			{Path: "a.go", Content: []byte("// ok")},
+			{Path: "b.go", Content: []byte("// 🎉 and 🔥")},
+		} {
+			require.NoError(t, stream.Send(file))
+			scanned, err := stream.Recv()
+			require.NoError(t, err)
+			assert.Equal(t, file.Path, scanned.Path)
+			if file.Path == "b.go" {
+				assert.Len(t, scanned.Findings, 2)
+			} else {
+				assert.Empty(t, scanned.Findings)
+			}
+		}
+		require.NoError(t, stream.CloseSend())
+		_, err = stream.Recv()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("rejects empty requests", func(t *testing.T) {
+		_, err := client.Scan(ctx, &antimojiv1.ScanRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = client.Clean(ctx, &antimojiv1.CleanRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+// writePEM writes the PEM block of type kind with der to a file in dir and returns
+// its path.
+func writePEM(t *testing.T, dir, name, kind string, der []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0600))
+	return path
+}
+
+// issue creates a certificate for template signed by parent, or self-signed when
+// parent is nil, and returns it with its key.
+func issue(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate, key
+}
+
+func TestServeHandler_GRPCMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := issue(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	serverCert, serverKey := issue(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "antimoji"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	clientCert, clientKey := issue(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "bot"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	serverKeyDER, err := x509.MarshalECPrivateKey(serverKey)
+	require.NoError(t, err)
+	opts := &ServeOptions{
+		TLSCert:     writePEM(t, dir, "server.pem", "CERTIFICATE", serverCert.Raw),
+		TLSKey:      writePEM(t, dir, "server-key.pem", "EC PRIVATE KEY", serverKeyDER),
+		TLSClientCA: writePEM(t, dir, "ca.pem", "CERTIFICATE", ca.Raw),
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	request := &antimojiv1.ScanRequest{Files: []*antimojiv1.File{{Path: "a.txt", Content: []byte("ok")}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("accepts clients with a certificate the CA signed", func(t *testing.T) {
+		client := newServeGRPC(t, opts, credentials.NewTLS(&tls.Config{
+			RootCAs: roots,
+			Certificates: []tls.Certificate{{
+				Certificate: [][]byte{clientCert.Raw},
+				PrivateKey:  clientKey,
+			}},
+		}))
+		response, err := client.Scan(ctx, request)
+		require.NoError(t, err)
+		assert.True(t, response.Passed)
+	})
+
+	t.Run("rejects clients without a certificate", func(t *testing.T) {
+		client := newServeGRPC(t, opts, credentials.NewTLS(&tls.Config{RootCAs: roots}))
+		_, err := client.Scan(ctx, request)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("rejects incomplete TLS options", func(t *testing.T) {
+		handler := NewServeHandler(logging.NewMockLogger(), quietOutput())
+		for _, invalid := range []*ServeOptions{
+			{TLSCert: opts.TLSCert},
+			{TLSClientCA: opts.TLSClientCA},
+			{TLSCert: opts.TLSCert, TLSKey: opts.TLSKey, TLSClientCA: opts.TLSKey},
+		} {
+			invalid.ProfileName = "default"
+			_, err := handler.GRPC(context.Background(), invalid)
+			assert.ErrorIs(t, err, ErrConfig)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServeAPI returns a test server for the serve API with opts.
+func newServeAPI(t *testing.T, opts *ServeOptions) *httptest.Server {
+	if opts.ProfileName == "" {
+		opts.ProfileName = "default"
+	}
+	api, err := NewServeHandler(logging.NewMockLogger(), quietOutput()).API(context.Background(), opts)
+	require.NoError(t, err)
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// postJSON posts body to the path of server and decodes the response into response.
+func postJSON(t *testing.T, server *httptest.Server, path, body string, response any) int {
+	resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	return resp.StatusCode
+}
+
+func TestServeHandler(t *testing.T) {
+	server := newServeAPI(t, &ServeOptions{})
+
+	t.Run("healthz", func(t *testing.T) {
+		var response map[string]string
+		resp, err := http.Get(server.URL + "/healthz")
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, map[string]string{"status": "ok"}, response)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		var response struct {
+			Profile  string         `json:"profile"`
+			Settings map[string]any `json:"settings"`
+		}
+		resp, err := http.Get(server.URL + "/config")
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, "default", response.Profile)
+		assert.Contains(t, response.Settings, "unicode_emojis")
+	})
+
+	t.Run("scan content", func(t *testing.T) {
+		var response ScanResponse
+		status := postJSON(t, server, "/scan", `{"path": "notes.md", "content": "Ship 🚀 with `+"`code 🎉`"+`"}`, &response)
+		assert.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Files, 1)
+		assert.Equal(t, "notes.md", response.Files[0].Path)
+		require.Len(t, response.Files[0].Findings, 1, "Markdown code is preserved")
+		assert.Equal(t, "🚀", response.Files[0].Findings[0].Emoji)
+		assert.Nil(t, response.Files[0].Findings[0].DebugInfo)
+		assert.Equal(t, 1, response.Violations)
+		assert.False(t, response.Passed)
+	})
+
+	t.Run("scan files", func(t *testing.T) {
+		var response ScanResponse
+		postJSON(t, server, "/scan", `{"files": [{"path": "a.go", "content": "// ok"}, {"path": "logo.png", "content": "\u0000\u0000\u0000🚀"}]}`, &response)
+		require.Len(t, response.Files, 2)
+		assert.Empty(t, response.Files[0].Findings)
+		assert.Contains(t, response.Files[1].Skipped, "binary")
+		assert.True(t, response.Passed)
+	})
+
+	t.Run("clean content", func(t *testing.T) {
+		var response CleanResponse
+		status := postJSON(t, server, "/clean", `{"path": "a.txt", "content": "Done ✅ :)", "replacement": "!"}`, &response)
+		assert.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Files, 1)
+		assert.Equal(t, "Done ! !", response.Files[0].Content)
+		assert.True(t, response.Files[0].Modified)
+		assert.Equal(t, 2, response.EmojisRemoved)
+	})
+
+	t.Run("clean multipart uploads", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "README.md")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("# Launch 🚀\n"))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		resp, err := http.Post(server.URL+"/clean", form.FormDataContentType(), &body)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var response CleanResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		require.Len(t, response.Files, 1)
+		assert.Equal(t, "README.md", response.Files[0].Path)
+		assert.Equal(t, "# Launch \n", response.Files[0].Content)
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		var response map[string]string
+		assert.Equal(t, http.StatusBadRequest, postJSON(t, server, "/scan", `{}`, &response))
+		assert.Contains(t, response["error"], "no content sent")
+		assert.Equal(t, http.StatusBadRequest, postJSON(t, server, "/scan", `{"contents": "x"}`, &response))
+		assert.Contains(t, response["error"], "invalid JSON request")
+
+		resp, err := http.Get(server.URL + "/scan")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+
+	t.Run("limits the request size", func(t *testing.T) {
+		small := newServeAPI(t, &ServeOptions{MaxRequestSize: 16})
+		var response map[string]string
+		status := postJSON(t, small, "/scan", `{"content": "`+strings.Repeat("a", 32)+`"}`, &response)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	})
+}
+
+func TestServeHandler_Metrics(t *testing.T) {
+	m := metrics.New()
+	api, err := NewServeHandler(logging.NewMockLogger(), quietOutput()).WithMetrics(m).
+		API(context.Background(), &ServeOptions{ProfileName: "default"})
+	require.NoError(t, err)
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	var scanned ScanResponse
+	postJSON(t, server, "/scan", `{"path": "a.md", "content": "Ship 🚀 🎉"}`, &scanned)
+	var cleaned CleanResponse
+	postJSON(t, server, "/clean", `{"path": "a.md", "content": "Ship 🚀"}`, &cleaned)
+
+	var out bytes.Buffer
+	require.NoError(t, m.Registry().WriteText(&out))
+	assert.Contains(t, out.String(), "antimoji_files_scanned_total 2\n")
+	assert.Contains(t, out.String(), "antimoji_emojis_found_total 3\n")
+	assert.Contains(t, out.String(), "antimoji_clean_operations_total 1\n")
+}
+
+func TestServeHandler_Profile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".antimoji.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`version: 3
+profiles:
+  default:
+    unicode_emojis: true
+    emoji_allowlist: ["✅"]
+    max_total: 1
+    exclude_patterns: ["vendor/*"]
+`), 0644))
+	server := newServeAPI(t, &ServeOptions{ConfigFile: configPath})
+
+	var response ScanResponse
+	postJSON(t, server, "/scan", `{"files": [
+		{"path": "a.txt", "content": "ok ✅ 🚀"},
+		{"path": "vendor/b.txt", "content": "🎉"}
+	]}`, &response)
+	require.Len(t, response.Files, 2)
+	require.Len(t, response.Files[0].Findings, 1)
+	assert.Equal(t, "🚀", response.Files[0].Findings[0].Emoji)
+	assert.Equal(t, "excluded by the profile", response.Files[1].Skipped)
+	assert.True(t, response.Passed, "within max_total")
+
+	_, err := NewServeHandler(logging.NewMockLogger(), quietOutput()).API(context.Background(), &ServeOptions{ProfileName: "missing"})
+	assert.ErrorIs(t, err, ErrConfig)
+}
+
+func TestServeHandler_Reload(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.yaml")
+	writeBase := func(content string) {
+		require.NoError(t, os.WriteFile(basePath, []byte(content), 0644))
+	}
+	writeBase("profiles:\n  default:\n    unicode_emojis: true\n    emoji_allowlist: [\"✅\"]\n")
+	configPath := filepath.Join(dir, ".antimoji.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("version: 3\nextends: base.yaml\n"), 0644))
+
+	opts := &ServeOptions{ConfigFile: configPath, ProfileName: "default"}
+	h := NewServeHandler(logging.NewMockLogger(), quietOutput())
+	service, err := h.service(context.Background(), opts)
+	require.NoError(t, err)
+	require.NotNil(t, service.manager, "a local config file is managed")
+	server := httptest.NewServer(h.api(service, opts))
+	t.Cleanup(server.Close)
+
+	findings := func() []string {
+		var response ScanResponse
+		postJSON(t, server, "/scan", `{"path": "a.txt", "content": "ok ✅ 🚀"}`, &response)
+		require.Len(t, response.Files, 1)
+		var emojis []string
+		for _, match := range response.Files[0].Findings {
+			emojis = append(emojis, match.Emoji)
+		}
+		return emojis
+	}
+	assert.Equal(t, []string{"🚀"}, findings())
+
+	// An edit to the extended file replaces the policy
+	writeBase("profiles:\n  default:\n    unicode_emojis: true\n    emoji_allowlist: [\"✅\", \"🚀\"]\n")
+	reloaded, err := service.manager.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Empty(t, findings())
+
+	// An invalid edit keeps it
+	writeBase("profiles: [unclosed\n")
+	_, err = service.manager.Reload()
+	assert.Error(t, err)
+	assert.Empty(t, findings())
+}
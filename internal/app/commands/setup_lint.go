@@ -3,16 +3,25 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/antimoji/antimoji/internal/config"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
 	"github.com/antimoji/antimoji/internal/observability/logging"
 	"github.com/antimoji/antimoji/internal/ui"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // SetupLintOptions holds the options for the setup-lint command.
 type SetupLintOptions struct {
-	Mode              string
+	Mode              string // zero-tolerance, allow-list, or permissive
 	OutputDir         string
 	PreCommitConfig   bool
 	AllowedEmojis     []string
@@ -21,22 +30,80 @@ type SetupLintOptions struct {
 	Repair            bool
 	Review            bool
 	Validate          bool
+	CommitMsgHook     bool     // add a commit-msg hook checking commit messages and branch names
+	HookManager       string   // pre-commit, husky, lefthook, or lint-staged
+	GitHubActions     bool     // shorthand for --ci=github
+	CI                []string // CI providers to generate a pipeline job for: github, gitlab, circle, azure
+	PinVersion        string   // antimoji version the generated hooks and CI jobs require
+}
+
+// lintMode is a setup-lint linting mode, named after the built-in template it uses.
+type lintMode string
+
+const (
+	zeroToleranceMode lintMode = "zero-tolerance"
+	allowListMode     lintMode = "allow-list"
+	permissiveMode    lintMode = "permissive"
+)
+
+// isValidLintMode checks if the provided mode is valid.
+func isValidLintMode(mode lintMode) bool {
+	switch mode {
+	case zeroToleranceMode, allowListMode, permissiveMode:
+		return true
+	default:
+		return false
+	}
 }
 
 // SetupLintHandler handles the setup-lint command with dependency injection.
 type SetupLintHandler struct {
-	logger logging.Logger
-	ui     ui.UserOutput
+	logger   logging.Logger
+	ui       ui.UserOutput
+	out      io.Writer
+	prompter *ui.Prompter
+	version  string
+	// lookPath locates the antimoji and hook manager binaries; tests replace it
+	lookPath func(file string) (string, error)
 }
 
 // NewSetupLintHandler creates a new setup-lint command handler.
 func NewSetupLintHandler(logger logging.Logger, ui ui.UserOutput) *SetupLintHandler {
 	return &SetupLintHandler{
-		logger: logger,
-		ui:     ui,
+		logger:   logger,
+		ui:       ui,
+		lookPath: exec.LookPath,
 	}
 }
 
+// WithOutput sets the writer used for summaries and reviews (defaults to stdout).
+func (h *SetupLintHandler) WithOutput(out io.Writer) *SetupLintHandler {
+	h.out = out
+	return h
+}
+
+// WithPrompter sets the prompter asking before existing hooks are replaced (defaults
+// to stdin and the output writer).
+func (h *SetupLintHandler) WithPrompter(prompter *ui.Prompter) *SetupLintHandler {
+	h.prompter = prompter
+	return h
+}
+
+// WithVersion sets the version of the running binary, which the generated CI jobs
+// install when it is a release.
+func (h *SetupLintHandler) WithVersion(version string) *SetupLintHandler {
+	h.version = version
+	return h
+}
+
+// output returns the writer for summaries and reviews.
+func (h *SetupLintHandler) output() io.Writer {
+	if h.out == nil {
+		return os.Stdout
+	}
+	return h.out
+}
+
 // CreateCommand creates the setup-lint cobra command.
 func (h *SetupLintHandler) CreateCommand() *cobra.Command {
 	opts := &SetupLintOptions{}
@@ -59,13 +126,25 @@ The command will:
 - Append antimoji hooks to existing .pre-commit-config.yaml (or create new)
 - Setup pre-commit hooks for automated emoji cleaning
 
+Behavior with existing configuration files:
+- Preserves existing hooks and configuration in .pre-commit-config.yaml
+- Detects existing antimoji configuration and prompts for replacement
+- Use --force to skip confirmation prompts
+- Use --repair to restore missing .antimoji.yaml and .pre-commit-config.yaml antimoji configuration
+
 Examples:
   antimoji setup-lint --mode=zero-tolerance    # Strict: no emojis allowed
   antimoji setup-lint --mode=allow-list        # Allow specific emojis only
   antimoji setup-lint --mode=permissive        # Lenient with warnings
   antimoji setup-lint --force                  # Overwrite existing configs
   antimoji setup-lint --repair                 # Repair missing configs
-  antimoji setup-lint --review                 # Review existing configuration`,
+  antimoji setup-lint --review                 # Review existing configuration
+  antimoji setup-lint --skip-precommit         # Skip pre-commit hook setup
+  antimoji setup-lint --commit-msg-hook        # Also check commit messages
+  antimoji setup-lint --hook-manager=husky     # Run the check from husky instead of pre-commit
+  antimoji setup-lint --github-actions         # Also check pull requests on GitHub Actions
+  antimoji setup-lint --ci=gitlab,azure        # Also generate GitLab CI and Azure Pipelines jobs
+  antimoji setup-lint --pin-version v1.2.3     # Hooks fail unless antimoji v1.2.3 runs them`,
 		Args:          cobra.MaximumNArgs(1),
 		SilenceUsage:  true,
 		SilenceErrors: true,
@@ -81,9 +160,14 @@ Examples:
 	cmd.Flags().StringSliceVar(&opts.AllowedEmojis, "allowed-emojis", []string{"", ""}, "emojis to allow in allow-list mode")
 	cmd.Flags().BoolVar(&opts.Force, "force", false, "overwrite existing configuration files")
 	cmd.Flags().BoolVar(&opts.SkipPreCommitHook, "skip-precommit", false, "skip pre-commit hook installation")
-	cmd.Flags().BoolVar(&opts.Repair, "repair", false, "repair missing configs")
-	cmd.Flags().BoolVar(&opts.Review, "review", false, "review existing configuration")
-	cmd.Flags().BoolVar(&opts.Validate, "validate", false, "validate existing configuration")
+	cmd.Flags().BoolVar(&opts.Repair, "repair", false, "repair missing .antimoji.yaml and .pre-commit-config.yaml antimoji configuration")
+	cmd.Flags().BoolVar(&opts.Review, "review", false, "review existing configuration and explain how it will apply")
+	cmd.Flags().BoolVar(&opts.Validate, "validate", false, "validate existing configuration and suggest improvements")
+	cmd.Flags().BoolVar(&opts.CommitMsgHook, "commit-msg-hook", false, "add a commit-msg hook that checks commit messages and branch names")
+	cmd.Flags().StringVar(&opts.HookManager, "hook-manager", "pre-commit", "tool running the git hooks (pre-commit, husky, lefthook, lint-staged)")
+	cmd.Flags().BoolVar(&opts.GitHubActions, "github-actions", false, "generate .github/workflows/antimoji.yml checking pull requests (same as --ci=github)")
+	cmd.Flags().StringSliceVar(&opts.CI, "ci", nil, "generate a pipeline job checking pull requests for these CI providers (github, gitlab, circle, azure)")
+	cmd.Flags().StringVar(&opts.PinVersion, "pin-version", "", "require this antimoji version (vX.Y.Z) in the generated hooks and CI jobs")
 
 	return cmd
 }
@@ -95,22 +179,616 @@ func (h *SetupLintHandler) Execute(parentCtx context.Context, cmd *cobra.Command
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx = ctxutil.WithOperation(ctx, "setup-lint")
+	ctx = ctxutil.WithComponent(ctx, "cli")
 
 	h.logger.Info(ctx, "Starting setup-lint operation",
 		"mode", opts.Mode,
 		"output_dir", opts.OutputDir,
 		"args", args)
 
-	// TODO: For now, return a placeholder message until full implementation
-	// This maintains the same error pattern as before but with dependency injection
-	h.ui.Info(ctx, "Setup-lint command - dependency injection working!")
-	h.ui.Info(ctx, "Would setup linting with mode: %s", opts.Mode)
-	h.ui.Info(ctx, "Output directory: %s", opts.OutputDir)
+	// Determine target directory
+	targetDir := "."
+	if len(args) > 0 {
+		targetDir = args[0]
+	}
+	if opts.OutputDir != "" && opts.OutputDir != "." {
+		targetDir = opts.OutputDir
+	}
+	if info, err := os.Stat(targetDir); err != nil || !info.IsDir() {
+		return classify(ErrConfig, fmt.Errorf("target directory does not exist: %s", targetDir))
+	}
+
+	if opts.Review {
+		return h.reviewConfiguration(ctx, targetDir)
+	}
+	if opts.Validate {
+		return h.validateConfiguration(ctx, targetDir)
+	}
 
-	h.logger.Info(ctx, "Setup-lint command executed with DI",
-		"args", args,
-		"mode", opts.Mode,
-		"output_dir", opts.OutputDir)
+	mode := lintMode(opts.Mode)
+	if !isValidLintMode(mode) {
+		return classify(ErrConfig, fmt.Errorf("invalid linting mode: %s (must be: zero-tolerance, allow-list, or permissive)", opts.Mode))
+	}
+	manager := hookManagerOf(opts)
+	if !isValidHookManager(manager) {
+		return classify(ErrConfig, fmt.Errorf("invalid hook manager: %s (must be: pre-commit, husky, lefthook, or lint-staged)", opts.HookManager))
+	}
+	if opts.PinVersion != "" {
+		pinVersion, err := normalizePinVersion(opts.PinVersion)
+		if err != nil {
+			return err
+		}
+		opts.PinVersion = pinVersion
+	}
+	providers := ciProvidersOf(opts)
+	for _, provider := range providers {
+		if !isValidCIProvider(provider) {
+			return classify(ErrConfig, fmt.Errorf("invalid CI provider: %s (must be: github, gitlab, circle, or azure)", provider))
+		}
+	}
+
+	h.ui.Info(ctx, "Setting up antimoji linting in %s (mode: %s)", targetDir, mode)
+
+	if err := h.generateAntimojiConfig(ctx, targetDir, mode, opts); err != nil {
+		return err
+	}
+
+	for _, provider := range providers {
+		if err := h.generateCIConfig(ctx, targetDir, provider, mode, opts); err != nil {
+			return err
+		}
+	}
+
+	// Other hook managers replace the pre-commit configuration and hooks
+	if manager != preCommitManager {
+		if err := h.setupHookManager(ctx, targetDir, mode, manager, opts); err != nil {
+			return classify(ErrIO, fmt.Errorf("failed to set up %s hooks: %w", manager, err))
+		}
+		if !opts.SkipPreCommitHook {
+			if err := h.installHookManager(ctx, targetDir, manager); err != nil {
+				h.ui.Warning(ctx, "Failed to install %s hooks: %v", manager, err)
+				h.ui.Info(ctx, "You can install them with: npm install")
+			}
+		}
+	}
+
+	if manager == preCommitManager && opts.PreCommitConfig {
+		if err := h.updatePreCommitConfig(ctx, targetDir, mode, opts); err != nil {
+			return err
+		}
+	}
+	if manager == preCommitManager && !opts.SkipPreCommitHook {
+		if err := h.installPreCommitHooks(ctx, targetDir, opts.CommitMsgHook); err != nil {
+			h.ui.Warning(ctx, "Failed to install pre-commit hooks: %v", err)
+			h.ui.Info(ctx, "You can install them manually with: %s", preCommitInstallCommand(opts))
+		}
+	}
+
+	if h.ui.IsLevelEnabled(ui.OutputNormal) {
+		if opts.Repair {
+			writeRepairSummary(h.output(), mode, opts)
+		} else {
+			writeSetupSummary(h.output(), mode, opts)
+		}
+	}
+
+	h.logger.Info(ctx, "Setup-lint completed", "dir", targetDir, "mode", mode)
+	return nil
+}
+
+// generateAntimojiConfig writes the configuration of mode to .antimoji.yaml in
+// targetDir. An existing file is kept in repair mode and replaced only with --force.
+func (h *SetupLintHandler) generateAntimojiConfig(ctx context.Context, targetDir string, mode lintMode, opts *SetupLintOptions) error {
+	configPath := filepath.Join(targetDir, defaultConfigFile)
+
+	_, err := os.Stat(configPath)
+	exists := err == nil
+	if exists {
+		if opts.Repair {
+			h.ui.Info(ctx, "%s already exists, skipping", defaultConfigFile)
+			return nil
+		}
+		if !opts.Force {
+			return classify(ErrConfig, fmt.Errorf("configuration file already exists: %s (use --force to overwrite)", configPath))
+		}
+	}
+
+	cfg, err := configForMode(mode, opts.AllowedEmojis, targetDir)
+	if err != nil {
+		return classify(ErrConfig, err)
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	// Stamp the schema version, so --validate can tell the configuration is current
+	data = append([]byte(fmt.Sprintf("version: %d\n", config.SchemaVersion)), data...)
+
+	if err := os.WriteFile(configPath, data, 0644); err != nil { // #nosec G306 - configuration file, not secret
+		return classify(ErrIO, fmt.Errorf("failed to write configuration file: %w", err))
+	}
+
+	if opts.Repair {
+		h.ui.Success(ctx, "Repaired missing antimoji configuration: %s", configPath)
+	} else {
+		h.ui.Success(ctx, "Generated antimoji configuration: %s", configPath)
+	}
+	return nil
+}
+
+// configForMode returns the configuration holding the profile of the built-in template
+// of mode, named after the mode. Tests and documentation are left out, since setup-lint
+// lints source code.
+func configForMode(mode lintMode, allowedEmojis []string, targetDir string) (config.Config, error) {
+	profile, err := config.GetBuiltInProfile(string(mode), config.TemplateOptions{
+		AllowedEmojis: allowedEmojis,
+		TargetDir:     targetDir,
+		IncludeTests:  false,
+		IncludeDocs:   false,
+	})
+	if err != nil {
+		return config.Config{}, fmt.Errorf("failed to generate the %s profile: %w", mode, err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Profiles[string(mode)] = profile
+	return cfg, nil
+}
+
+// checkHookArgs returns the arguments of the antimoji check command run on staged files
+// for mode. One check cleans and then verifies with the same profile, so the two can
+// never disagree; permissive mode only verifies.
+func checkHookArgs(mode lintMode) []string {
+	args := []string{"check"}
+	if mode != permissiveMode {
+		args = append(args, "--fix")
+	}
+	return append(args, "--config="+defaultConfigFile, "--profile="+string(mode), "--threshold="+modeThreshold(mode), "--quiet")
+}
+
+// modeThreshold returns the number of emojis the checks of mode tolerate.
+func modeThreshold(mode lintMode) string {
+	switch mode {
+	case allowListMode:
+		return "5"
+	case permissiveMode:
+		return "20"
+	default:
+		return "0"
+	}
+}
+
+// preCommitFile is the part of .pre-commit-config.yaml setup-lint rewrites.
+type preCommitFile struct {
+	Repos []preCommitRepo `yaml:"repos"`
+}
+
+// preCommitRepo is a repository in the pre-commit configuration.
+type preCommitRepo struct {
+	Repo  string          `yaml:"repo"`
+	Rev   string          `yaml:"rev,omitempty"`
+	Hooks []preCommitHook `yaml:"hooks"`
+}
+
+// preCommitHook is a hook in the pre-commit configuration.
+type preCommitHook struct {
+	ID            string   `yaml:"id"`
+	Name          string   `yaml:"name,omitempty"`
+	Entry         string   `yaml:"entry,omitempty"`
+	Args          []string `yaml:"args,omitempty"`
+	Description   string   `yaml:"description,omitempty"`
+	Language      string   `yaml:"language,omitempty"`
+	Files         string   `yaml:"files,omitempty"`
+	Exclude       string   `yaml:"exclude,omitempty"`
+	PassFilenames bool     `yaml:"pass_filenames,omitempty"`
+	RequireSerial bool     `yaml:"require_serial,omitempty"`
+	Stages        []string `yaml:"stages,omitempty"`
+}
+
+// antimojiHookIDs are the ids of the hooks setup-lint generates now or did before.
+var antimojiHookIDs = []string{"antimoji-clean", "antimoji-verify", "antimoji-check", "antimoji-commit-msg", "build-antimoji"}
+
+// Filters of the files the antimoji check hook is run on.
+const (
+	hookFilesPattern   = `\.(go|js|ts|jsx|tsx|py|rb|java|c|cpp|h|hpp|rs|php|swift|kt|scala)$`
+	hookExcludePattern = `(?x)^(.*_test\.go|.*/test/.*|.*/tests/.*|.*/testdata/.*|.*/fixtures/.*|.*/mocks/.*|vendor/.*|dist/.*|bin/.*|.*\.md$|docs/.*|\.antimoji\.yaml$)$`
+)
+
+// updatePreCommitConfig adds the antimoji hooks to .pre-commit-config.yaml in
+// targetDir, creating it when missing. Existing antimoji hooks are kept in repair mode
+// and replaced after confirmation, or with --force.
+func (h *SetupLintHandler) updatePreCommitConfig(ctx context.Context, targetDir string, mode lintMode, opts *SetupLintOptions) error {
+	configPath := filepath.Join(targetDir, preCommitConfigFile)
+
+	data, err := os.ReadFile(configPath) // #nosec G304 - the pre-commit configuration of the target directory
+	if errors.Is(err, os.ErrNotExist) {
+		content := h.generatePreCommitConfig(targetDir, mode, opts)
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil { // #nosec G306 - configuration file, not secret
+			return classify(ErrIO, fmt.Errorf("failed to write pre-commit configuration: %w", err))
+		}
+		h.ui.Success(ctx, "Created new pre-commit configuration: %s", configPath)
+		return nil
+	}
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to read existing pre-commit configuration: %w", err))
+	}
+
+	var file preCommitFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return classify(ErrConfig, fmt.Errorf("failed to parse existing pre-commit configuration: %w", err))
+	}
+
+	if antimojiRepoIndex(&file) >= 0 {
+		if opts.Repair {
+			h.ui.Info(ctx, "%s already has antimoji hooks, skipping", preCommitConfigFile)
+			return nil
+		}
+		if !opts.Force && !h.confirmReplace(ctx) {
+			h.ui.Info(ctx, "Skipped updating the antimoji hooks in %s", configPath)
+			return nil
+		}
+		for i := antimojiRepoIndex(&file); i >= 0; i = antimojiRepoIndex(&file) {
+			file.Repos = append(file.Repos[:i], file.Repos[i+1:]...)
+		}
+		h.ui.Info(ctx, "Removed existing antimoji hooks")
+	}
+	file.Repos = append(file.Repos, h.antimojiRepo(targetDir, mode, opts))
+
+	updated, err := yaml.Marshal(&file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal updated pre-commit configuration: %w", err)
+	}
+	if err := os.WriteFile(configPath, updated, 0644); err != nil { // #nosec G306 - configuration file, not secret
+		return classify(ErrIO, fmt.Errorf("failed to write updated pre-commit configuration: %w", err))
+	}
+
+	if opts.Repair {
+		h.ui.Success(ctx, "Repaired missing antimoji hooks in %s", configPath)
+	} else {
+		h.ui.Success(ctx, "Updated pre-commit configuration: %s", configPath)
+	}
+	return nil
+}
+
+// antimojiRepoIndex returns the index of the first local repository holding antimoji
+// hooks, or -1.
+func antimojiRepoIndex(file *preCommitFile) int {
+	for i, repo := range file.Repos {
+		if repo.Repo != "local" {
+			continue
+		}
+		for _, hook := range repo.Hooks {
+			for _, id := range antimojiHookIDs {
+				if hook.ID == id {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// confirmReplace asks whether existing antimoji hooks are replaced. Nothing is replaced
+// without an answer, as when output is quiet.
+func (h *SetupLintHandler) confirmReplace(ctx context.Context) bool {
+	if !h.ui.IsLevelEnabled(ui.OutputNormal) {
+		return false
+	}
+	prompter := h.prompter
+	if prompter == nil {
+		prompter = ui.NewPrompter(os.Stdin, h.output())
+	}
+	prompter.Printf("Existing antimoji hooks found in %s\n", preCommitConfigFile)
+	choice, err := prompter.Choose("Replace them with the new configuration?", []string{"n", "y"})
+	if err != nil {
+		h.logger.Debug(ctx, "No answer to the replacement prompt", "error", err)
+		return false
+	}
+	return choice == "y"
+}
+
+// antimojiRepo returns the local repository of the antimoji hooks for mode, with the
+// commit-msg hook when requested and hooks requiring the pinned version.
+func (h *SetupLintHandler) antimojiRepo(targetDir string, mode lintMode, opts *SetupLintOptions) preCommitRepo {
+	antimojiCmd := h.antimojiCommand(targetDir)
+	entry := pinnedCommand(antimojiCmd, opts.PinVersion)
+	var hooks []preCommitHook
+
+	if antimojiCmd == localAntimojiCommand {
+		hooks = append(hooks, preCommitHook{
+			ID:            "build-antimoji",
+			Name:          "Build Antimoji Binary",
+			Description:   "Build antimoji binary for linting hooks",
+			Entry:         "make build",
+			Language:      "system",
+			Files:         `\.(go)$`,
+			PassFilenames: false,
+			RequireSerial: true,
+			Stages:        []string{"pre-commit", "pre-push"},
+		})
+	}
+
+	name, description, requireSerial := checkHookText(mode)
+	hooks = append(hooks, preCommitHook{
+		ID:            "antimoji-check",
+		Name:          name,
+		Entry:         entry,
+		Args:          checkHookArgs(mode),
+		Description:   description,
+		Language:      "system",
+		Files:         hookFilesPattern,
+		Exclude:       hookExcludePattern,
+		PassFilenames: true,
+		RequireSerial: requireSerial,
+	})
+
+	// The commit-msg hook receives the message file, so it must not be filtered by path
+	if opts.CommitMsgHook {
+		hooks = append(hooks, preCommitHook{
+			ID:          "antimoji-commit-msg",
+			Name:        "Commit Message Emoji Check",
+			Entry:       entry,
+			Args:        commitMsgHookArgs(mode),
+			Description: "Reject emojis in commit messages and branch names",
+			Language:    "system",
+			Stages:      []string{"commit-msg"},
+		})
+	}
+
+	return preCommitRepo{Repo: "local", Hooks: hooks}
+}
+
+// checkHookText returns the name and description of the check hook of mode, and
+// whether it must run serially; permissive mode does not modify files.
+func checkHookText(mode lintMode) (name, description string, requireSerial bool) {
+	switch mode {
+	case allowListMode:
+		return "Allow-list Emoji Check", "Remove emojis not in the allowlist and verify the rest", true
+	case permissiveMode:
+		return "Permissive Emoji Check", "Permissive emoji check - warns about excessive usage", false
+	default:
+		return "Zero-Tolerance Emoji Check", "Remove all emojis from source code files and verify none remain", true
+	}
+}
+
+// commitMsgHookArgs returns the arguments of the antimoji command checking commit
+// messages for mode; the message file is appended by the hook manager.
+func commitMsgHookArgs(mode lintMode) []string {
+	return []string{"hook", "commit-msg", "--config=" + defaultConfigFile, "--profile=" + string(mode)}
+}
+
+// generatePreCommitConfig returns a new pre-commit configuration with the standard
+// hooks, the Go hooks for Go modules and the antimoji hooks for mode, including the
+// commit-msg hook when requested and requiring the pinned version.
+func (h *SetupLintHandler) generatePreCommitConfig(targetDir string, mode lintMode, opts *SetupLintOptions) string {
+	antimojiCmd := h.antimojiCommand(targetDir)
+	entry := pinnedCommand(antimojiCmd, opts.PinVersion)
+	name, description, requireSerial := checkHookText(mode)
+
+	// Build antimoji before running hooks when it is not installed
+	buildHookSection := ""
+	if antimojiCmd == localAntimojiCommand {
+		buildHookSection = `
+      # Build antimoji before running hooks
+      - id: build-antimoji
+        name: Build Antimoji Binary
+        description: Build antimoji binary for linting hooks
+        entry: make build
+        language: system
+        files: \.(go)$
+        pass_filenames: false
+        require_serial: true
+        stages: [pre-commit, pre-push]
+`
+	}
+
+	// The commit-msg hook is placed before the check hook, whose files/exclude filters
+	// end the file
+	commitMsgHookSection := ""
+	if opts.CommitMsgHook {
+		commitMsgHookSection = fmt.Sprintf(`
+      # Check commit messages and branch names
+      - id: antimoji-commit-msg
+        name: "Commit Message Emoji Check"
+        entry: %s
+        args: [%s]
+        description: Reject emojis in commit messages and branch names
+        language: system
+        stages: [commit-msg]
+`, entry, strings.Join(commitMsgHookArgs(mode), ", "))
+	}
+
+	goHooksSection := ""
+	if _, err := os.Stat(filepath.Join(targetDir, "go.mod")); err == nil {
+		goHooksSection = `  # Go-specific hooks
+  - repo: https://github.com/dnephin/pre-commit-golang
+    rev: v0.5.1
+    hooks:
+      - id: go-fmt
+      - id: go-mod-tidy
+
+`
+	}
+
+	return fmt.Sprintf(`# Pre-commit configuration
+# Generated by: antimoji setup-lint --mode=%s
+# Cleans and verifies in one antimoji check hook, so the two never disagree
+
+repos:
+  # Standard pre-commit hooks
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v6.0.0
+    hooks:
+      - id: trailing-whitespace
+        exclude: \.md$
+      - id: end-of-file-fixer
+        exclude: \.md$
+      - id: check-yaml
+        args: [--allow-multiple-documents]
+      - id: check-added-large-files
+      - id: check-merge-conflict
+
+%s
+  # Local antimoji hooks
+  - repo: local
+    hooks:%s%s
+      - id: antimoji-check
+        name: "%s"
+        entry: %s
+        args: [%s]
+        description: %s
+        language: system
+        pass_filenames: true
+        require_serial: %t
+        files: %s
+        exclude: |
+          (?x)^(
+            .*_test\.go|
+            .*/test/.*|
+            .*/tests/.*|
+            .*/testdata/.*|
+            .*/fixtures/.*|
+            .*/mocks/.*|
+            vendor/.*|
+            dist/.*|
+            bin/.*|
+            .*\.md$|
+            docs/.*|
+            \.antimoji\.yaml$
+          )$
+`, mode, goHooksSection, buildHookSection, commitMsgHookSection, name, entry, strings.Join(checkHookArgs(mode), ", "), description, requireSerial, hookFilesPattern)
+}
+
+// localAntimojiCommand runs antimoji built by the Makefile of the repository.
+const localAntimojiCommand = "bin/antimoji"
+
+// antimojiCommand returns how hooks run antimoji: the installed binary when it is on
+// PATH, the binary built by the Makefile of targetDir otherwise, or else the installed
+// binary the user still has to install.
+func (h *SetupLintHandler) antimojiCommand(targetDir string) string {
+	if _, err := h.lookPath("antimoji"); err == nil {
+		return "antimoji"
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "Makefile")); err == nil {
+		return localAntimojiCommand
+	}
+	return "antimoji"
+}
+
+// installPreCommitHooks installs the git hooks of the pre-commit configuration in
+// targetDir, including the commit-msg hook type when requested.
+func (h *SetupLintHandler) installPreCommitHooks(ctx context.Context, targetDir string, commitMsgHook bool) error {
+	if _, err := h.lookPath("pre-commit"); err != nil {
+		return fmt.Errorf("pre-commit not found in PATH")
+	}
+
+	args := []string{"install"}
+	if commitMsgHook {
+		args = append(args, "--hook-type", "pre-commit", "--hook-type", "commit-msg")
+	}
+	cmd := exec.CommandContext(ctx, "pre-commit", args...) // #nosec G204 - arguments are constant
+	cmd.Dir = targetDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to install pre-commit hooks: %w\nOutput: %s", err, output)
+	}
+
+	h.ui.Success(ctx, "Installed pre-commit hooks")
+	return nil
+}
+
+// writePolicySummary writes the policy lines of mode.
+func writePolicySummary(out io.Writer, mode lintMode, opts *SetupLintOptions) {
+	_, _ = fmt.Fprintf(out, "  • Linting mode: %s\n", mode)
+	switch mode {
+	case zeroToleranceMode:
+		_, _ = fmt.Fprintf(out, "  • Policy: Zero tolerance - NO emojis allowed in source code\n")
+		_, _ = fmt.Fprintf(out, "  • Threshold: 0 emojis\n")
+	case allowListMode:
+		_, _ = fmt.Fprintf(out, "  • Policy: Allow-list - Only specific emojis allowed\n")
+		_, _ = fmt.Fprintf(out, "  • Allowed emojis: %s\n", strings.Join(opts.AllowedEmojis, ", "))
+		_, _ = fmt.Fprintf(out, "  • Threshold: 5 emojis maximum\n")
+	case permissiveMode:
+		_, _ = fmt.Fprintf(out, "  • Policy: Permissive - Warns about excessive emoji usage\n")
+		_, _ = fmt.Fprintf(out, "  • Threshold: 20 emojis maximum\n")
+	}
+	if opts.CommitMsgHook {
+		_, _ = fmt.Fprintf(out, "  • Commit messages: checked by the commit-msg hook\n")
+	}
+	if opts.PinVersion != "" {
+		_, _ = fmt.Fprintf(out, "  • Version: hooks and CI jobs require antimoji %s\n", opts.PinVersion)
+	}
+}
+
+// writeUsageExamples writes the commands that use the configuration of mode.
+func writeUsageExamples(out io.Writer, mode lintMode) {
+	_, _ = fmt.Fprintf(out, "\nUsage Examples:\n")
+	_, _ = fmt.Fprintf(out, "  • Run manual scan: antimoji scan --config %s .\n", defaultConfigFile)
+	_, _ = fmt.Fprintf(out, "  • Run with profile: antimoji scan --config %s --profile %s .\n", defaultConfigFile, mode)
+	_, _ = fmt.Fprintf(out, "  • Clean and verify: antimoji check --fix --config %s --profile %s .\n", defaultConfigFile, mode)
+}
+
+// preCommitInstallCommand returns the command installing the hooks setup-lint
+// configured.
+func preCommitInstallCommand(opts *SetupLintOptions) string {
+	if opts.CommitMsgHook {
+		return "pre-commit install --hook-type pre-commit --hook-type commit-msg"
+	}
+	return "pre-commit install"
+}
+
+// writeSetupSummary writes what setup-lint configured and the next steps.
+func writeSetupSummary(out io.Writer, mode lintMode, opts *SetupLintOptions) {
+	_, _ = fmt.Fprintf(out, "\nAntimoji linting setup complete!\n\n")
+	_, _ = fmt.Fprintf(out, "Configuration Summary:\n")
+	writePolicySummary(out, mode, opts)
+
+	_, _ = fmt.Fprintf(out, "\nGenerated Files:\n")
+	_, _ = fmt.Fprintf(out, "  • %s - Antimoji configuration\n", defaultConfigFile)
+	for _, provider := range ciProvidersOf(opts) {
+		ci := ciTemplates[provider]
+		_, _ = fmt.Fprintf(out, "  • %s - %s\n", filepath.ToSlash(ci.path), ci.description)
+		if ci.nextStep != "" {
+			_, _ = fmt.Fprintf(out, "    %s\n", ci.nextStep)
+		}
+	}
+	if manager := hookManagerOf(opts); manager != preCommitManager {
+		writeHookManagerSummary(out, manager, opts)
+	} else {
+		if opts.PreCommitConfig {
+			_, _ = fmt.Fprintf(out, "  • %s - Pre-commit hooks configuration\n", preCommitConfigFile)
+		}
+
+		_, _ = fmt.Fprintf(out, "\nNext Steps:\n")
+		_, _ = fmt.Fprintf(out, "  1. Review generated configuration files\n")
+		_, _ = fmt.Fprintf(out, "  2. Install pre-commit: pip install pre-commit\n")
+		_, _ = fmt.Fprintf(out, "  3. Install hooks: %s\n", preCommitInstallCommand(opts))
+		_, _ = fmt.Fprintf(out, "  4. Test setup: pre-commit run --all-files\n")
+		_, _ = fmt.Fprintf(out, "  5. Commit your changes: git add . && git commit -m \"Setup antimoji linting\"\n")
+	}
+
+	writeUsageExamples(out, mode)
+}
+
+// writeRepairSummary writes what setup-lint repaired and the next steps.
+func writeRepairSummary(out io.Writer, mode lintMode, opts *SetupLintOptions) {
+	_, _ = fmt.Fprintf(out, "\nAntimoji configuration repair complete!\n\n")
+	_, _ = fmt.Fprintf(out, "Repair Summary:\n")
+	writePolicySummary(out, mode, opts)
+
+	_, _ = fmt.Fprintf(out, "\nRepaired Files:\n")
+	_, _ = fmt.Fprintf(out, "  • %s - Antimoji configuration (if missing)\n", defaultConfigFile)
+	for _, provider := range ciProvidersOf(opts) {
+		_, _ = fmt.Fprintf(out, "  • %s - %s CI configuration (if missing)\n", filepath.ToSlash(ciTemplates[provider].path), provider)
+	}
+	if opts.PreCommitConfig {
+		_, _ = fmt.Fprintf(out, "  • %s - Pre-commit antimoji hooks (if missing)\n", preCommitConfigFile)
+	}
+
+	_, _ = fmt.Fprintf(out, "\nNext Steps:\n")
+	_, _ = fmt.Fprintf(out, "  1. Review repaired configuration files\n")
+	_, _ = fmt.Fprintf(out, "  2. Install/update hooks: %s\n", preCommitInstallCommand(opts))
+	_, _ = fmt.Fprintf(out, "  3. Test repair: pre-commit run --all-files\n")
 
-	return fmt.Errorf("setup-lint command not yet fully refactored for dependency injection - complex implementation pending")
+	writeUsageExamples(out, mode)
 }
@@ -0,0 +1,326 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// ciProvider names the CI service a pipeline job is generated for.
+type ciProvider string
+
+const (
+	gitHubCI ciProvider = "github"
+	gitLabCI ciProvider = "gitlab"
+	circleCI ciProvider = "circle"
+	azureCI  ciProvider = "azure"
+)
+
+// ciProviders are the supported CI providers, in the order of the --ci help.
+var ciProviders = []ciProvider{gitHubCI, gitLabCI, circleCI, azureCI}
+
+// ciGoVersion is the Go release the generated jobs install antimoji with, the go
+// directive of antimoji's go.mod.
+const ciGoVersion = "1.23.0"
+
+// ciTemplate is the pipeline configuration generated for a CI provider.
+type ciTemplate struct {
+	// path is relative to the target directory
+	path        string
+	description string
+	// nextStep tells how to enable the generated job, when writing it is not enough
+	nextStep string
+	body     string
+}
+
+// ciTemplates holds the pipeline configuration of each CI provider. Every job installs
+// the pinned antimoji version, caches the binary and runs check with the threshold of
+// the mode, so CI enforces what the hooks do; the templates use [[ ]] delimiters to keep
+// the CI's own ${{ }} and {{ }} expressions intact.
+var ciTemplates = map[ciProvider]ciTemplate{
+	gitHubCI: {
+		path:        filepath.Join(".github", "workflows", "antimoji.yml"),
+		description: "Checks pull requests on GitHub Actions",
+		body: `# GitHub Actions workflow checking pull requests for emojis
+# Generated by: antimoji setup-lint --mode=[[.Mode]] --ci=github
+name: Antimoji
+
+on:
+  pull_request:
+
+permissions:
+  contents: read
+  security-events: write
+
+jobs:
+  antimoji:
+    name: Emoji check
+    runs-on: ubuntu-latest
+    env:
+      # The antimoji version that generated this workflow; keep it in step with local hooks
+      ANTIMOJI_VERSION: [[.Version]]
+    steps:
+      - uses: actions/checkout@v4
+
+      - name: Cache antimoji
+        id: cache-antimoji
+        uses: actions/cache@v4
+        with:
+          path: ~/go/bin/antimoji
+          key: antimoji-${{ runner.os }}-${{ env.ANTIMOJI_VERSION }}
+
+      - name: Set up Go
+        if: steps.cache-antimoji.outputs.cache-hit != 'true'
+        uses: actions/setup-go@v5
+        with:
+          go-version: "[[.GoVersion]]"
+          cache: false
+
+      - name: Install antimoji
+        if: steps.cache-antimoji.outputs.cache-hit != 'true'
+        run: go install github.com/antimoji/antimoji/cmd/antimoji@${{ env.ANTIMOJI_VERSION }}
+
+      - name: Add antimoji to PATH
+        run: echo "$HOME/go/bin" >> "$GITHUB_PATH"
+
+      - name: Scan for emojis
+        run: antimoji scan [[.Flags]] --output=sarif --report-file=antimoji.sarif .
+
+      - name: Check emoji policy
+        run: antimoji check [[.Flags]] --threshold=[[.Threshold]] .
+
+      # Code scanning is not available on every repository, so a failed upload does not fail the job
+      - name: Upload SARIF
+        if: always() && hashFiles('antimoji.sarif') != ''
+        continue-on-error: true
+        uses: github/codeql-action/upload-sarif@v3
+        with:
+          sarif_file: antimoji.sarif
+          category: antimoji
+`,
+	},
+	gitLabCI: {
+		path:        filepath.Join(".gitlab", "ci", "antimoji.yml"),
+		description: "Checks merge requests on GitLab CI",
+		nextStep:    "Include .gitlab/ci/antimoji.yml from .gitlab-ci.yml (include: - local: .gitlab/ci/antimoji.yml)",
+		body: `# GitLab CI job checking merge requests for emojis
+# Generated by: antimoji setup-lint --mode=[[.Mode]] --ci=gitlab
+# Include it from .gitlab-ci.yml:
+#   include:
+#     - local: .gitlab/ci/antimoji.yml
+antimoji:
+  stage: test
+  image: golang:[[.GoVersion]]
+  variables:
+    # The antimoji version that generated this job; keep it in step with local hooks
+    ANTIMOJI_VERSION: [[.Version]]
+    GOBIN: $CI_PROJECT_DIR/.antimoji-bin
+  cache:
+    key: antimoji-$ANTIMOJI_VERSION
+    paths:
+      - .antimoji-bin/
+  rules:
+    - if: $CI_PIPELINE_SOURCE == "merge_request_event"
+  script:
+    - test -x "$GOBIN/antimoji" || go install github.com/antimoji/antimoji/cmd/antimoji@$ANTIMOJI_VERSION
+    - $GOBIN/antimoji scan [[.Flags]] --output=codeclimate .
+    - $GOBIN/antimoji check [[.Flags]] --threshold=[[.Threshold]] .
+  artifacts:
+    when: always
+    reports:
+      codequality: gl-code-quality-report.json
+`,
+	},
+	circleCI: {
+		path:        filepath.Join(".circleci", "config.yml"),
+		description: "Checks pushed commits and pull requests on CircleCI",
+		body: `# CircleCI configuration checking pushed commits and pull requests for emojis
+# Generated by: antimoji setup-lint --mode=[[.Mode]] --ci=circle
+version: 2.1
+
+jobs:
+  antimoji:
+    docker:
+      - image: cimg/go:[[.GoVersion]]
+    environment:
+      # The antimoji version that generated this job; keep it in step with local hooks
+      ANTIMOJI_VERSION: [[.Version]]
+    steps:
+      - checkout
+      - restore_cache:
+          keys:
+            - antimoji-[[.Version]]
+      - run:
+          name: Install antimoji
+          command: test -x ~/go/bin/antimoji || go install github.com/antimoji/antimoji/cmd/antimoji@${ANTIMOJI_VERSION}
+      - save_cache:
+          key: antimoji-[[.Version]]
+          paths:
+            - ~/go/bin/antimoji
+      - run:
+          name: Check emoji policy
+          command: ~/go/bin/antimoji check [[.Flags]] --threshold=[[.Threshold]] .
+
+workflows:
+  antimoji:
+    jobs:
+      - antimoji
+`,
+	},
+	azureCI: {
+		path:        filepath.Join(".azure-pipelines", "antimoji.yml"),
+		description: "Checks pull requests on Azure Pipelines",
+		nextStep:    "Create an Azure pipeline from .azure-pipelines/antimoji.yml (Azure Repos: add it as a build validation policy)",
+		body: `# Azure Pipelines configuration checking pull requests for emojis
+# Generated by: antimoji setup-lint --mode=[[.Mode]] --ci=azure
+trigger: none
+
+pr:
+  branches:
+    include:
+      - '*'
+
+pool:
+  vmImage: ubuntu-latest
+
+variables:
+  # The antimoji version that generated this pipeline; keep it in step with local hooks
+  ANTIMOJI_VERSION: [[.Version]]
+  GOBIN: $(Pipeline.Workspace)/antimoji-bin
+
+steps:
+  - task: Cache@2
+    displayName: Cache antimoji
+    inputs:
+      key: 'antimoji | "$(Agent.OS)" | "$(ANTIMOJI_VERSION)"'
+      path: $(GOBIN)
+      cacheHitVar: ANTIMOJI_CACHED
+
+  - task: GoTool@0
+    displayName: Set up Go
+    condition: ne(variables.ANTIMOJI_CACHED, 'true')
+    inputs:
+      version: '[[.GoVersion]]'
+
+  - script: go install github.com/antimoji/antimoji/cmd/antimoji@$(ANTIMOJI_VERSION)
+    displayName: Install antimoji
+    condition: ne(variables.ANTIMOJI_CACHED, 'true')
+
+  - script: $(GOBIN)/antimoji check [[.Flags]] --threshold=[[.Threshold]] .
+    displayName: Check emoji policy
+`,
+	},
+}
+
+// ciTemplateData is the data the CI templates are executed with.
+type ciTemplateData struct {
+	Mode      lintMode
+	Version   string
+	GoVersion string
+	// Flags select the configuration and the profile of the mode
+	Flags     string
+	Threshold string
+}
+
+// releaseVersionPattern matches the versions of tagged releases, which go install can
+// fetch; development builds report "dev" or a git describe output instead.
+var releaseVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+
+// workflowVersion returns the antimoji version the CI jobs install: the version of
+// this binary when it is a release, so CI checks with the rules used locally, and
+// "latest" otherwise.
+func (h *SetupLintHandler) workflowVersion() (string, bool) {
+	version := h.version
+	if !releaseVersionPattern.MatchString(version) {
+		return "latest", false
+	}
+	return "v" + strings.TrimPrefix(version, "v"), true
+}
+
+// ciProvidersOf returns the CI providers selected by opts, without duplicates;
+// --github-actions selects GitHub.
+func ciProvidersOf(opts *SetupLintOptions) []ciProvider {
+	var providers []ciProvider
+	seen := map[ciProvider]bool{}
+	add := func(provider ciProvider) {
+		if !seen[provider] {
+			seen[provider] = true
+			providers = append(providers, provider)
+		}
+	}
+	for _, name := range opts.CI {
+		add(ciProvider(strings.ToLower(strings.TrimSpace(name))))
+	}
+	if opts.GitHubActions {
+		add(gitHubCI)
+	}
+	return providers
+}
+
+// isValidCIProvider checks if the provided CI provider is supported.
+func isValidCIProvider(provider ciProvider) bool {
+	_, ok := ciTemplates[provider]
+	return ok
+}
+
+// renderCIConfig returns the pipeline configuration of provider for mode, installing
+// version.
+func renderCIConfig(provider ciProvider, mode lintMode, version string) (string, error) {
+	tmpl, err := template.New(string(provider)).Delims("[[", "]]").Parse(ciTemplates[provider].body)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, ciTemplateData{
+		Mode:      mode,
+		Version:   version,
+		GoVersion: ciGoVersion,
+		Flags:     "--config=" + defaultConfigFile + " --profile=" + string(mode),
+		Threshold: modeThreshold(mode),
+	})
+	return buf.String(), err
+}
+
+// generateCIConfig creates the pipeline configuration of provider checking for mode.
+// An existing file is kept in repair mode and replaced only with --force.
+func (h *SetupLintHandler) generateCIConfig(ctx context.Context, targetDir string, provider ciProvider, mode lintMode, opts *SetupLintOptions) error {
+	ci := ciTemplates[provider]
+	path := filepath.Join(targetDir, ci.path)
+
+	if _, err := os.Stat(path); err == nil {
+		if opts.Repair {
+			h.ui.Info(ctx, "%s already exists, skipping", filepath.ToSlash(ci.path))
+			return nil
+		}
+		if !opts.Force {
+			return classify(ErrConfig, fmt.Errorf("CI configuration already exists: %s (use --force to overwrite)", path))
+		}
+	}
+
+	version, pinned := h.workflowVersion()
+	if opts.PinVersion != "" {
+		version, pinned = opts.PinVersion, true
+	}
+	content, err := renderCIConfig(provider, mode, version)
+	if err != nil {
+		return fmt.Errorf("failed to render %s configuration: %w", provider, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to create CI configuration directory: %w", err))
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil { // #nosec G306 - CI configuration is committed to the repository
+		return classify(ErrIO, fmt.Errorf("failed to write CI configuration: %w", err))
+	}
+
+	h.ui.Success(ctx, "Generated %s CI configuration: %s", provider, path)
+	if !pinned {
+		h.ui.Warning(ctx, "This antimoji build (%s) is not a release; the job installs the latest release. Set ANTIMOJI_VERSION in %s to the version you use locally.",
+			h.version, filepath.ToSlash(ci.path))
+	}
+	return nil
+}
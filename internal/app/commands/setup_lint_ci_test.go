@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetupLintHandler_WorkflowVersion(t *testing.T) {
+	for version, want := range map[string]string{"0.9.16": "v0.9.16", "v1.2.3": "v1.2.3", "dev": "latest", "v1.2.3-4-gabc-dirty": "latest"} {
+		got, pinned := newTestSetupLintHandler(io.Discard).WithVersion(version).workflowVersion()
+		assert.Equal(t, want, got, version)
+		assert.Equal(t, want != "latest", pinned, version)
+	}
+}
+
+func TestCIProvidersOf(t *testing.T) {
+	assert.Empty(t, ciProvidersOf(&SetupLintOptions{}))
+	assert.Equal(t, []ciProvider{gitLabCI, gitHubCI}, ciProvidersOf(&SetupLintOptions{CI: []string{"GitLab", "github"}, GitHubActions: true}))
+	assert.False(t, isValidCIProvider("jenkins"))
+}
+
+func TestRenderCIConfig(t *testing.T) {
+	for provider := range ciTemplates {
+		t.Run(string(provider), func(t *testing.T) {
+			content, err := renderCIConfig(provider, allowListMode, "v0.9.16")
+			require.NoError(t, err)
+
+			var parsed map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(content), &parsed), "the configuration is valid YAML")
+			assert.Contains(t, content, "v0.9.16", "the version is pinned")
+			assert.Contains(t, content, "go install github.com/antimoji/antimoji/cmd/antimoji@")
+			assert.Contains(t, content, "check --config=.antimoji.yaml --profile=allow-list --threshold=5 .")
+			assert.NotContains(t, content, "[[")
+		})
+	}
+}
+
+func TestSetupLintHandler_GitHubActions(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	opts := &SetupLintOptions{Mode: string(allowListMode), OutputDir: dir, GitHubActions: true, SkipPreCommitHook: true}
+	require.NoError(t, newTestSetupLintHandler(io.Discard).WithVersion("0.9.16").Execute(ctx, nil, nil, opts))
+
+	path := filepath.Join(dir, ".github", "workflows", "antimoji.yml")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var workflow struct {
+		On   map[string]interface{} `yaml:"on"`
+		Jobs map[string]struct {
+			Env   map[string]string `yaml:"env"`
+			Steps []struct {
+				Name string `yaml:"name"`
+				Uses string `yaml:"uses"`
+				Run  string `yaml:"run"`
+			} `yaml:"steps"`
+		} `yaml:"jobs"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &workflow))
+	assert.Contains(t, workflow.On, "pull_request")
+
+	job := workflow.Jobs["antimoji"]
+	assert.Equal(t, "v0.9.16", job.Env["ANTIMOJI_VERSION"], "the version is pinned to this binary")
+	runs := map[string]string{}
+	for _, step := range job.Steps {
+		runs[step.Name] = step.Run + step.Uses
+	}
+	assert.Equal(t, "actions/cache@v4", runs["Cache antimoji"])
+	assert.Contains(t, runs["Install antimoji"], "go install github.com/antimoji/antimoji/cmd/antimoji@${{ env.ANTIMOJI_VERSION }}")
+	assert.Contains(t, runs["Scan for emojis"], "scan --config=.antimoji.yaml --profile=allow-list --output=sarif --report-file=antimoji.sarif")
+	assert.Contains(t, runs["Check emoji policy"], "check --config=.antimoji.yaml --profile=allow-list --threshold=5")
+	assert.Equal(t, "github/codeql-action/upload-sarif@v3", runs["Upload SARIF"])
+
+	// An existing workflow is kept unless forced, and repair skips it
+	handler := newTestSetupLintHandler(io.Discard)
+	err = handler.generateCIConfig(ctx, dir, gitHubCI, zeroToleranceMode, &SetupLintOptions{})
+	assert.ErrorIs(t, err, ErrConfig)
+	assert.ErrorContains(t, err, "use --force to overwrite")
+	require.NoError(t, handler.generateCIConfig(ctx, dir, gitHubCI, zeroToleranceMode, &SetupLintOptions{Repair: true}))
+	require.NoError(t, handler.generateCIConfig(ctx, dir, gitHubCI, zeroToleranceMode, &SetupLintOptions{Force: true}))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "--profile=zero-tolerance --threshold=0")
+	assert.Contains(t, string(data), "ANTIMOJI_VERSION: latest", "development builds install the latest release")
+
+	opts = &SetupLintOptions{Mode: string(zeroToleranceMode), OutputDir: t.TempDir(), CI: []string{"jenkins"}}
+	assert.ErrorContains(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, nil, opts), "invalid CI provider: jenkins")
+}
+
+func TestSetupLintHandler_CI(t *testing.T) {
+	dir := t.TempDir()
+	opts := &SetupLintOptions{Mode: string(zeroToleranceMode), OutputDir: dir, CI: []string{"gitlab", "circle", "azure"}, SkipPreCommitHook: true}
+	require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(context.Background(), nil, nil, opts))
+	for _, path := range []string{".gitlab/ci/antimoji.yml", ".circleci/config.yml", ".azure-pipelines/antimoji.yml"} {
+		assert.FileExists(t, filepath.Join(dir, path))
+	}
+	assert.NoFileExists(t, filepath.Join(dir, ".github", "workflows", "antimoji.yml"))
+}
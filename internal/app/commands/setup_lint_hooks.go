@@ -0,0 +1,592 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// hookManager names the tool that runs antimoji from git hooks.
+type hookManager string
+
+const (
+	preCommitManager  hookManager = "pre-commit"
+	huskyManager      hookManager = "husky"
+	lefthookManager   hookManager = "lefthook"
+	lintStagedManager hookManager = "lint-staged"
+)
+
+// isValidHookManager checks if the provided hook manager is supported.
+func isValidHookManager(manager hookManager) bool {
+	switch manager {
+	case preCommitManager, huskyManager, lefthookManager, lintStagedManager:
+		return true
+	default:
+		return false
+	}
+}
+
+// hookManagerOf returns the hook manager selected by opts, pre-commit when unset.
+func hookManagerOf(opts *SetupLintOptions) hookManager {
+	if opts.HookManager == "" {
+		return preCommitManager
+	}
+	return hookManager(opts.HookManager)
+}
+
+// Versions of the npm packages added to devDependencies when missing.
+const (
+	huskyVersion      = "^9.1.7"
+	lintStagedVersion = "^15.2.10"
+	lefthookVersion   = "^1.8.4"
+)
+
+// hookExtensions are the extensions of the staged files the generated hooks pass to
+// antimoji, matching the files filter of the pre-commit hooks. antimoji applies the
+// profile's exclude patterns to the files it is given, so test files and vendored code
+// stay excluded.
+var hookExtensions = []string{"go", "js", "ts", "jsx", "tsx", "py", "rb", "java", "c", "cpp", "h", "hpp", "rs", "php", "swift", "kt", "scala"}
+
+// Markers around the antimoji section of husky hook scripts, so that running
+// setup-lint again replaces the section and keeps the rest of the script.
+const (
+	hookBlockStart = "# >>> antimoji (generated by antimoji setup-lint)"
+	hookBlockEnd   = "# <<< antimoji"
+)
+
+// hookStep is one antimoji command run on staged files.
+type hookStep struct {
+	name string
+	args []string
+	// fixes marks steps that modify files, which must be staged again
+	fixes bool
+}
+
+// legacyHookSteps are the names of the clean and verify steps that earlier versions
+// generated instead of one check step; running setup-lint again removes them.
+var legacyHookSteps = []string{"antimoji-clean", "antimoji-verify"}
+
+// hookSteps returns the steps run on staged files for mode: one check that cleans and
+// then verifies with the same profile. Permissive mode only verifies.
+func hookSteps(mode lintMode) []hookStep {
+	return []hookStep{{name: "antimoji-check", args: checkHookArgs(mode), fixes: mode != permissiveMode}}
+}
+
+// commitMsgCommand returns the command of the commit-msg hook; the message file is
+// appended by the hook manager.
+func commitMsgCommand(antimojiCmd string, mode lintMode) string {
+	return antimojiCmd + " " + strings.Join(commitMsgHookArgs(mode), " ")
+}
+
+// setupHookManager writes the configuration of a hook manager other than pre-commit,
+// and the npm scripts of package.json.
+func (h *SetupLintHandler) setupHookManager(ctx context.Context, targetDir string, mode lintMode, manager hookManager, opts *SetupLintOptions) error {
+	antimojiCmd := pinnedCommand(h.antimojiCommand(targetDir), opts.PinVersion)
+	devDependencies := map[string]string{}
+
+	switch manager {
+	case huskyManager:
+		if err := h.writeHookBlock(ctx, filepath.Join(targetDir, ".husky", "pre-commit"), huskyPreCommitBlock(antimojiCmd, mode)); err != nil {
+			return err
+		}
+		devDependencies["husky"] = huskyVersion
+
+	case lintStagedManager:
+		if err := h.writeLintStagedConfig(ctx, targetDir, antimojiCmd, mode); err != nil {
+			return err
+		}
+		// lint-staged runs from husky, and stages the files its commands fix itself
+		if err := h.writeHookBlock(ctx, filepath.Join(targetDir, ".husky", "pre-commit"), "npx lint-staged || exit 1"); err != nil {
+			return err
+		}
+		devDependencies["husky"] = huskyVersion
+		devDependencies["lint-staged"] = lintStagedVersion
+
+	case lefthookManager:
+		if err := h.writeLefthookConfig(ctx, targetDir, antimojiCmd, mode, opts.CommitMsgHook); err != nil {
+			return err
+		}
+		devDependencies["lefthook"] = lefthookVersion
+	}
+
+	if opts.CommitMsgHook && manager != lefthookManager {
+		block := commitMsgCommand(antimojiCmd, mode) + ` "$1" || exit 1`
+		if err := h.writeHookBlock(ctx, filepath.Join(targetDir, ".husky", "commit-msg"), block); err != nil {
+			return err
+		}
+	}
+
+	// lefthook does not need Node, so only JavaScript projects get the npm scripts
+	packagePath := filepath.Join(targetDir, "package.json")
+	if _, err := os.Stat(packagePath); manager == lefthookManager && errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return h.updatePackageJSON(ctx, packagePath, antimojiCmd, mode, manager, devDependencies)
+}
+
+// huskyPreCommitBlock returns the pre-commit hook section that cleans the staged source
+// files, stages them again and verifies them.
+func huskyPreCommitBlock(antimojiCmd string, mode lintMode) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "files=$(git diff --cached --name-only --diff-filter=ACMR | grep -E '\\.(%s)$' || true)\n", strings.Join(hookExtensions, "|"))
+	b.WriteString("if [ -n \"$files\" ]; then\n")
+	for _, step := range hookSteps(mode) {
+		fmt.Fprintf(&b, "  printf '%%s\\n' \"$files\" | tr '\\n' '\\0' | xargs -0 %s %s || exit 1\n", antimojiCmd, strings.Join(step.args, " "))
+		if step.fixes {
+			b.WriteString("  printf '%s\\n' \"$files\" | tr '\\n' '\\0' | xargs -0 git add -- || exit 1\n")
+		}
+	}
+	b.WriteString("fi")
+	return b.String()
+}
+
+// writeHookBlock writes block to the hook script at path between the antimoji markers,
+// replacing the section of an earlier run and keeping the rest of the script.
+func (h *SetupLintHandler) writeHookBlock(ctx context.Context, path, block string) error {
+	section := hookBlockStart + "\n" + block + "\n" + hookBlockEnd + "\n"
+
+	existing, err := os.ReadFile(path) // #nosec G304 - path is a hook script below the target directory
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	content := string(existing)
+	start := strings.Index(content, hookBlockStart)
+	end := strings.Index(content, hookBlockEnd)
+	switch {
+	case start != -1 && end > start:
+		end += len(hookBlockEnd)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+		content = content[:start] + section + content[end:]
+	case content == "":
+		content = section
+	default:
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\n" + section
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	// Hook scripts must be executable
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil { // #nosec G306 - git hooks must be executable
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0755); err != nil { // #nosec G302 - git hooks must be executable
+		return fmt.Errorf("failed to make %s executable: %w", path, err)
+	}
+	h.ui.Success(ctx, "Updated hook script: %s", path)
+	return nil
+}
+
+// lintStagedGlob is the lint-staged glob of the files passed to antimoji.
+func lintStagedGlob() string {
+	return "*.{" + strings.Join(hookExtensions, ",") + "}"
+}
+
+// writeLintStagedConfig sets the antimoji commands in the lint-staged configuration:
+// the lint-staged key of package.json when it has one, .lintstagedrc.json otherwise.
+func (h *SetupLintHandler) writeLintStagedConfig(ctx context.Context, targetDir, antimojiCmd string, mode lintMode) error {
+	commands := make([]string, 0, 2)
+	for _, step := range hookSteps(mode) {
+		commands = append(commands, antimojiCmd+" "+strings.Join(step.args, " "))
+	}
+
+	packagePath := filepath.Join(targetDir, "package.json")
+	if pkg, err := readJSONObject(packagePath); err == nil {
+		if raw, ok := pkg.get("lint-staged"); ok {
+			config, err := parseJSONObject(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse lint-staged configuration in %s: %w", packagePath, err)
+			}
+			if err := config.set(lintStagedGlob(), commands); err != nil {
+				return err
+			}
+			if err := pkg.set("lint-staged", config); err != nil {
+				return err
+			}
+			return writeJSONObject(packagePath, pkg)
+		}
+	}
+
+	configPath := filepath.Join(targetDir, ".lintstagedrc.json")
+	config, err := readJSONObject(configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := config.set(lintStagedGlob(), commands); err != nil {
+		return err
+	}
+	return writeJSONObject(configPath, config)
+}
+
+// lefthookCommand is a command of lefthook.yml.
+type lefthookCommand struct {
+	Priority   int    `yaml:"priority,omitempty"`
+	Glob       string `yaml:"glob,omitempty"`
+	Run        string `yaml:"run"`
+	StageFixed bool   `yaml:"stage_fixed,omitempty"`
+}
+
+// writeLefthookConfig sets the antimoji commands in lefthook.yml, keeping the other hooks
+// and commands of an existing file.
+func (h *SetupLintHandler) writeLefthookConfig(ctx context.Context, targetDir, antimojiCmd string, mode lintMode, commitMsgHook bool) error {
+	configPath := filepath.Join(targetDir, "lefthook.yml")
+	var doc yaml.Node
+	data, err := os.ReadFile(configPath) // #nosec G304 - path is below the target directory
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", configPath, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to update %s: not a mapping", configPath)
+	}
+
+	// Commands of one hook run in parallel unless piped, and check modifies files
+	preCommit := yamlMapping(root, "pre-commit")
+	if err := setYAMLValue(preCommit, "piped", true); err != nil {
+		return err
+	}
+	commands := yamlMapping(preCommit, "commands")
+	for _, name := range legacyHookSteps {
+		deleteYAMLKey(commands, name)
+	}
+	for i, step := range hookSteps(mode) {
+		command := lefthookCommand{
+			Priority:   i + 1,
+			Glob:       lintStagedGlob(),
+			Run:        antimojiCmd + " " + strings.Join(step.args, " ") + " {staged_files}",
+			StageFixed: step.fixes,
+		}
+		if err := setYAMLValue(commands, step.name, command); err != nil {
+			return err
+		}
+	}
+	if commitMsgHook {
+		command := lefthookCommand{Run: commitMsgCommand(antimojiCmd, mode) + " {1}"}
+		if err := setYAMLValue(yamlMapping(yamlMapping(root, "commit-msg"), "commands"), "antimoji-commit-msg", command); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", configPath, err)
+	}
+	if err := os.WriteFile(configPath, buf.Bytes(), 0644); err != nil { // #nosec G306 - configuration file, not secret
+		return fmt.Errorf("failed to write %s: %w", configPath, err)
+	}
+	h.ui.Success(ctx, "Updated lefthook configuration: %s", configPath)
+	return nil
+}
+
+// yamlMapping returns the mapping under key in mapping, adding an empty one when the key
+// is missing or null.
+func yamlMapping(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			if value.Kind != yaml.MappingNode {
+				*value = yaml.Node{Kind: yaml.MappingNode}
+			}
+			return value
+		}
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
+
+// deleteYAMLKey removes key and its value from mapping.
+func deleteYAMLKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// setYAMLValue sets key in mapping to value, replacing an existing entry in place.
+func setYAMLValue(mapping *yaml.Node, key string, value interface{}) error {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = &node
+			return nil
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &node)
+	return nil
+}
+
+// updatePackageJSON adds the antimoji npm scripts, the husky prepare script for the
+// husky-based managers and the missing devDependencies to package.json, creating it
+// when needed.
+func (h *SetupLintHandler) updatePackageJSON(ctx context.Context, packagePath, antimojiCmd string, mode lintMode, manager hookManager, devDependencies map[string]string) error {
+	pkg, err := readJSONObject(packagePath)
+	if errors.Is(err, os.ErrNotExist) {
+		pkg = jsonObject{{Key: "private", Value: json.RawMessage("true")}}
+	} else if err != nil {
+		return err
+	}
+
+	scripts, err := pkg.object("scripts")
+	if err != nil {
+		return fmt.Errorf("failed to parse scripts in %s: %w", packagePath, err)
+	}
+	for _, name := range legacyHookSteps {
+		scripts.delete("antimoji:" + strings.TrimPrefix(name, "antimoji-"))
+	}
+	for _, step := range hookSteps(mode) {
+		args := step.args[:len(step.args)-1] // without --quiet
+		name := "antimoji:" + strings.TrimPrefix(step.name, "antimoji-")
+		if err := scripts.set(name, antimojiCmd+" "+strings.Join(args, " ")+" ."); err != nil {
+			return err
+		}
+	}
+	if manager == huskyManager || manager == lintStagedManager {
+		prepare := "husky"
+		if raw, ok := scripts.get("prepare"); ok {
+			var existing string
+			if err := json.Unmarshal(raw, &existing); err == nil && existing != "" {
+				prepare = existing
+				if !strings.Contains(existing, "husky") {
+					prepare = existing + " && husky"
+				}
+			}
+		}
+		if err := scripts.set("prepare", prepare); err != nil {
+			return err
+		}
+	}
+	if err := pkg.set("scripts", scripts); err != nil {
+		return err
+	}
+
+	dependencies, err := pkg.object("devDependencies")
+	if err != nil {
+		return fmt.Errorf("failed to parse devDependencies in %s: %w", packagePath, err)
+	}
+	for _, name := range []string{"husky", "lint-staged", "lefthook"} {
+		version, ok := devDependencies[name]
+		if _, present := dependencies.get(name); !ok || present {
+			continue
+		}
+		if err := dependencies.set(name, version); err != nil {
+			return err
+		}
+	}
+	if err := pkg.set("devDependencies", dependencies); err != nil {
+		return err
+	}
+
+	if err := writeJSONObject(packagePath, pkg); err != nil {
+		return err
+	}
+	h.ui.Success(ctx, "Updated npm scripts: %s", packagePath)
+	return nil
+}
+
+// installHookManager installs the git hooks of managers that are not installed by
+// npm install, which runs the prepare script of the husky-based ones.
+func (h *SetupLintHandler) installHookManager(ctx context.Context, targetDir string, manager hookManager) error {
+	if manager != lefthookManager {
+		return nil
+	}
+	if _, err := h.lookPath("lefthook"); err != nil {
+		return fmt.Errorf("lefthook not found in PATH")
+	}
+	cmd := exec.CommandContext(ctx, "lefthook", "install") // #nosec G204 - arguments are constant
+	cmd.Dir = targetDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to install lefthook hooks: %w\nOutput: %s", err, output)
+	}
+	h.ui.Success(ctx, "Installed lefthook hooks")
+	return nil
+}
+
+// writeHookManagerSummary writes the generated files and next steps of a hook manager
+// other than pre-commit.
+func writeHookManagerSummary(out io.Writer, manager hookManager, opts *SetupLintOptions) {
+	switch manager {
+	case huskyManager:
+		_, _ = fmt.Fprintf(out, "  • .husky/pre-commit - Cleans and verifies staged files\n")
+	case lintStagedManager:
+		_, _ = fmt.Fprintf(out, "  • .lintstagedrc.json (or package.json lint-staged) - Cleans and verifies staged files\n")
+		_, _ = fmt.Fprintf(out, "  • .husky/pre-commit - Runs lint-staged\n")
+	case lefthookManager:
+		_, _ = fmt.Fprintf(out, "  • lefthook.yml - Cleans and verifies staged files\n")
+	}
+	if opts.CommitMsgHook && manager != lefthookManager {
+		_, _ = fmt.Fprintf(out, "  • .husky/commit-msg - Checks commit messages\n")
+	}
+	_, _ = fmt.Fprintf(out, "  • package.json - antimoji scripts and hook dependencies\n")
+
+	_, _ = fmt.Fprintf(out, "\nNext Steps:\n")
+	_, _ = fmt.Fprintf(out, "  1. Review generated configuration files\n")
+	if manager == lefthookManager {
+		_, _ = fmt.Fprintf(out, "  2. Install hooks: npm install (or lefthook install)\n")
+	} else {
+		_, _ = fmt.Fprintf(out, "  2. Install hooks: npm install\n")
+	}
+	steps := hookSteps(lintMode(opts.Mode))
+	_, _ = fmt.Fprintf(out, "  3. Test setup: npm run antimoji:%s\n", strings.TrimPrefix(steps[len(steps)-1].name, "antimoji-"))
+	_, _ = fmt.Fprintf(out, "  4. Commit your changes: git add . && git commit -m \"Setup antimoji linting\"\n")
+}
+
+// jsonObject is a JSON object that keeps the order of its members, so that files such
+// as package.json are rewritten with only the antimoji entries changed.
+type jsonObject []jsonMember
+
+// jsonMember is a member of a jsonObject.
+type jsonMember struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// parseJSONObject parses data, which must hold a JSON object.
+func parseJSONObject(data []byte) (jsonObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, errors.New("not a JSON object")
+	}
+	var object jsonObject
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		object = append(object, jsonMember{Key: key, Value: value})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// readJSONObject reads the JSON object in the file at path.
+func readJSONObject(path string) (jsonObject, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is below the target directory
+	if err != nil {
+		return nil, err
+	}
+	object, err := parseJSONObject(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return object, nil
+}
+
+// writeJSONObject writes object to the file at path, indented with two spaces.
+func writeJSONObject(path string, object jsonObject) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(object); err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil { // #nosec G306 - configuration file, not secret
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// get returns the value of key.
+func (o jsonObject) get(key string) (json.RawMessage, bool) {
+	for _, member := range o {
+		if member.Key == key {
+			return member.Value, true
+		}
+	}
+	return nil, false
+}
+
+// object returns the object under key, or an empty one when the key is missing.
+func (o jsonObject) object(key string) (jsonObject, error) {
+	raw, ok := o.get(key)
+	if !ok {
+		return jsonObject{}, nil
+	}
+	return parseJSONObject(raw)
+}
+
+// set sets key to value, replacing an existing member in place or appending a new one.
+func (o *jsonObject) set(key string, value interface{}) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	raw := json.RawMessage(bytes.TrimSpace(buf.Bytes()))
+	for i := range *o {
+		if (*o)[i].Key == key {
+			(*o)[i].Value = raw
+			return nil
+		}
+	}
+	*o = append(*o, jsonMember{Key: key, Value: raw})
+	return nil
+}
+
+// delete removes key.
+func (o *jsonObject) delete(key string) {
+	for i := range *o {
+		if (*o)[i].Key == key {
+			*o = append((*o)[:i], (*o)[i+1:]...)
+			return
+		}
+	}
+}
+
+// MarshalJSON encodes the object with its members in order.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(member.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
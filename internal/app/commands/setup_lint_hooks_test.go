@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetupHookManager_Husky(t *testing.T) {
+	dir := t.TempDir()
+	opts := &SetupLintOptions{Mode: string(zeroToleranceMode), CommitMsgHook: true}
+
+	require.NoError(t, newTestSetupLintHandler(io.Discard).setupHookManager(context.Background(), dir, zeroToleranceMode, huskyManager, opts))
+
+	hook, err := os.ReadFile(filepath.Join(dir, ".husky", "pre-commit"))
+	require.NoError(t, err)
+	assert.Contains(t, string(hook), "git diff --cached --name-only --diff-filter=ACMR")
+	assert.Contains(t, string(hook), "check --fix --config=.antimoji.yaml --profile=zero-tolerance --threshold=0")
+	assert.Contains(t, string(hook), "xargs -0 git add --")
+	assert.Less(t, strings.Index(string(hook), " check "), strings.Index(string(hook), "git add"), "fixed files are staged again")
+
+	info, err := os.Stat(filepath.Join(dir, ".husky", "pre-commit"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0100, "hook is executable")
+
+	commitMsg, err := os.ReadFile(filepath.Join(dir, ".husky", "commit-msg"))
+	require.NoError(t, err)
+	assert.Contains(t, string(commitMsg), `hook commit-msg --config=.antimoji.yaml --profile=zero-tolerance "$1"`)
+
+	var pkg struct {
+		Scripts         map[string]string `json:"scripts"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &pkg))
+	assert.Equal(t, "husky", pkg.Scripts["prepare"])
+	assert.Contains(t, pkg.Scripts["antimoji:check"], "check --fix")
+	assert.Contains(t, pkg.Scripts["antimoji:check"], "--threshold=0 .")
+	assert.Equal(t, huskyVersion, pkg.DevDependencies["husky"])
+}
+
+func TestSetupHookManager_HuskyKeepsExistingHook(t *testing.T) {
+	dir := t.TempDir()
+	hookPath := filepath.Join(dir, ".husky", "pre-commit")
+	require.NoError(t, os.MkdirAll(filepath.Dir(hookPath), 0755))
+	require.NoError(t, os.WriteFile(hookPath, []byte("npm test\n"), 0755))
+	opts := &SetupLintOptions{Mode: string(zeroToleranceMode)}
+
+	// Running twice replaces the antimoji section instead of adding another
+	require.NoError(t, newTestSetupLintHandler(io.Discard).setupHookManager(context.Background(), dir, zeroToleranceMode, huskyManager, opts))
+	require.NoError(t, newTestSetupLintHandler(io.Discard).setupHookManager(context.Background(), dir, permissiveMode, huskyManager, opts))
+
+	hook, err := os.ReadFile(hookPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(hook), "npm test\n"))
+	assert.Equal(t, 1, strings.Count(string(hook), hookBlockStart))
+	assert.Contains(t, string(hook), "--profile=permissive --threshold=20")
+	assert.NotContains(t, string(hook), "--fix")
+}
+
+func TestSetupHookManager_LintStaged(t *testing.T) {
+	t.Run("writes .lintstagedrc.json", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, newTestSetupLintHandler(io.Discard).setupHookManager(context.Background(), dir, allowListMode, lintStagedManager, &SetupLintOptions{Mode: string(allowListMode)}))
+
+		var config map[string][]string
+		data, err := os.ReadFile(filepath.Join(dir, ".lintstagedrc.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &config))
+		commands := config[lintStagedGlob()]
+		require.Len(t, commands, 1)
+		assert.Contains(t, commands[0], "check --fix --config=.antimoji.yaml --profile=allow-list --threshold=5")
+
+		hook, err := os.ReadFile(filepath.Join(dir, ".husky", "pre-commit"))
+		require.NoError(t, err)
+		assert.Contains(t, string(hook), "npx lint-staged")
+	})
+
+	t.Run("updates package.json in place", func(t *testing.T) {
+		dir := t.TempDir()
+		original := `{
+  "name": "app",
+  "version": "1.0.0",
+  "scripts": {
+    "test": "jest",
+    "prepare": "npm run build"
+  },
+  "lint-staged": {
+    "*.css": "stylelint --fix"
+  }
+}
+`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(original), 0644))
+		require.NoError(t, newTestSetupLintHandler(io.Discard).setupHookManager(context.Background(), dir, zeroToleranceMode, lintStagedManager, &SetupLintOptions{Mode: string(zeroToleranceMode)}))
+
+		_, err := os.Stat(filepath.Join(dir, ".lintstagedrc.json"))
+		assert.True(t, os.IsNotExist(err), "configuration goes to the existing lint-staged key")
+
+		data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+		require.NoError(t, err)
+		content := string(data)
+		assert.Less(t, strings.Index(content, `"name"`), strings.Index(content, `"version"`), "key order is kept")
+		assert.Contains(t, content, `"*.css": "stylelint --fix"`)
+		assert.Contains(t, content, `"prepare": "npm run build && husky"`)
+		assert.Contains(t, content, `"lint-staged": "`+lintStagedVersion+`"`)
+
+		var pkg struct {
+			LintStaged map[string]interface{} `json:"lint-staged"`
+		}
+		require.NoError(t, json.Unmarshal(data, &pkg))
+		assert.Contains(t, pkg.LintStaged, lintStagedGlob())
+	})
+}
+
+func TestSetupHookManager_Lefthook(t *testing.T) {
+	dir := t.TempDir()
+	existing := "pre-push:\n  commands:\n    test:\n      run: go test ./...\n" +
+		"pre-commit:\n  commands:\n    antimoji-clean:\n      run: antimoji clean --in-place {staged_files}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lefthook.yml"), []byte(existing), 0644))
+
+	require.NoError(t, newTestSetupLintHandler(io.Discard).setupHookManager(context.Background(), dir, zeroToleranceMode, lefthookManager, &SetupLintOptions{Mode: string(zeroToleranceMode), CommitMsgHook: true}))
+
+	var config struct {
+		PreCommit struct {
+			Piped    bool                       `yaml:"piped"`
+			Commands map[string]lefthookCommand `yaml:"commands"`
+		} `yaml:"pre-commit"`
+		PrePush struct {
+			Commands map[string]lefthookCommand `yaml:"commands"`
+		} `yaml:"pre-push"`
+		CommitMsg struct {
+			Commands map[string]lefthookCommand `yaml:"commands"`
+		} `yaml:"commit-msg"`
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "lefthook.yml"))
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &config))
+
+	assert.True(t, config.PreCommit.Piped)
+	check := config.PreCommit.Commands["antimoji-check"]
+	assert.Equal(t, 1, check.Priority)
+	assert.True(t, check.StageFixed)
+	assert.Contains(t, check.Run, "check --fix")
+	assert.Contains(t, check.Run, "--threshold=0 --quiet {staged_files}")
+	assert.NotContains(t, config.PreCommit.Commands, "antimoji-clean", "commands of the old clean and verify workflow are removed")
+	assert.Contains(t, config.CommitMsg.Commands["antimoji-commit-msg"].Run, "hook commit-msg")
+	assert.Equal(t, "go test ./...", config.PrePush.Commands["test"].Run)
+
+	// Projects without package.json do not get one for lefthook
+	_, err = os.Stat(filepath.Join(dir, "package.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSetupLintHandler_HookManager(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects unknown hook managers", func(t *testing.T) {
+		opts := &SetupLintOptions{Mode: string(zeroToleranceMode), OutputDir: t.TempDir(), HookManager: "yarn"}
+		err := newTestSetupLintHandler(io.Discard).Execute(ctx, nil, nil, opts)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrConfig)
+		assert.Contains(t, err.Error(), "invalid hook manager")
+	})
+
+	t.Run("skips the pre-commit configuration", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := &SetupLintOptions{Mode: string(zeroToleranceMode), OutputDir: dir, PreCommitConfig: true, HookManager: string(huskyManager), SkipPreCommitHook: true}
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, nil, opts))
+
+		assert.FileExists(t, filepath.Join(dir, ".antimoji.yaml"))
+		assert.FileExists(t, filepath.Join(dir, ".husky", "pre-commit"))
+		assert.NoFileExists(t, filepath.Join(dir, ".pre-commit-config.yaml"))
+	})
+
+	t.Run("runs from the command line", func(t *testing.T) {
+		dir := t.TempDir()
+		cmd := newTestSetupLintHandler(io.Discard).CreateCommand()
+		cmd.SetArgs([]string{"--hook-manager=lefthook", "--skip-precommit", dir})
+		require.NoError(t, cmd.Execute())
+
+		data, err := os.ReadFile(filepath.Join(dir, "lefthook.yml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "antimoji-check")
+	})
+}
@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/analysis"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v3"
+)
+
+// setupReview is what setup-lint --review reports about the configuration of a
+// directory.
+type setupReview struct {
+	Mode            string
+	Policy          string
+	Threshold       string
+	AllowedEmojis   []string
+	FileCount       int
+	CurrentEmojis   int
+	PreCommitStatus string
+}
+
+// reviewProfilePriority orders the profiles --review explains when a configuration
+// has several.
+var reviewProfilePriority = []string{"zero-tolerance", "ci-lint", "allow-list", "permissive", "default"}
+
+// reviewConfiguration explains how the antimoji configuration of targetDir applies.
+func (h *SetupLintHandler) reviewConfiguration(ctx context.Context, targetDir string) error {
+	out := h.output()
+	_, _ = fmt.Fprintf(out, "Antimoji Configuration Review\n")
+	_, _ = fmt.Fprintf(out, "=============================\n\n")
+
+	review, err := analyzeSetup(targetDir)
+	if err != nil {
+		return classify(ErrConfig, fmt.Errorf("failed to analyze configuration: %w", err))
+	}
+	h.logger.Debug(ctx, "Configuration reviewed", "dir", targetDir, "mode", review.Mode)
+
+	if err := reviewTemplate.Execute(out, review); err != nil {
+		return fmt.Errorf("failed to write the review: %w", err)
+	}
+	return nil
+}
+
+// analyzeSetup analyzes the antimoji configuration and pre-commit hooks of targetDir.
+func analyzeSetup(targetDir string) (*setupReview, error) {
+	review := &setupReview{}
+
+	configPath := filepath.Join(targetDir, defaultConfigFile)
+	if _, err := os.Stat(configPath); err == nil {
+		if err := analyzeSetupConfig(configPath, targetDir, review); err != nil {
+			return nil, err
+		}
+	} else {
+		review.Mode = "not configured"
+		review.Policy = "No antimoji configuration found"
+	}
+
+	review.PreCommitStatus = preCommitStatus(filepath.Join(targetDir, preCommitConfigFile))
+	return review, nil
+}
+
+// analyzeSetupConfig fills review from the main profile of the configuration at
+// configPath and its impact on targetDir.
+func analyzeSetupConfig(configPath, targetDir string, review *setupReview) error {
+	result := config.LoadConfig(configPath)
+	if result.IsErr() {
+		return result.Error()
+	}
+	cfg := result.Unwrap()
+
+	name := ""
+	for _, candidate := range reviewProfilePriority {
+		if _, ok := cfg.Profiles[candidate]; ok {
+			name = candidate
+			break
+		}
+	}
+	if name == "" {
+		names := make([]string, 0, len(cfg.Profiles))
+		for candidate := range cfg.Profiles {
+			names = append(names, candidate)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no profiles found in configuration")
+		}
+		sort.Strings(names)
+		name = names[0]
+	}
+
+	analyzed := analysis.NewConfigAnalyzer(cfg.Profiles[name], targetDir).AnalyzeConfiguration()
+	review.Mode = name
+	review.Policy = analyzed.PolicyAnalysis.Description
+	review.Threshold = fmt.Sprintf("%d emojis maximum", analyzed.PolicyAnalysis.Threshold)
+	review.AllowedEmojis = analyzed.PolicyAnalysis.AllowedEmojis
+	review.FileCount = analyzed.ImpactAnalysis.FilesToScan
+	review.CurrentEmojis = analyzed.ImpactAnalysis.CurrentEmojis
+	if len(cfg.Profiles) > 1 {
+		review.Policy += fmt.Sprintf(" (%d total profiles available)", len(cfg.Profiles))
+	}
+	return nil
+}
+
+// preCommitStatus describes whether the pre-commit configuration at path runs
+// antimoji.
+func preCommitStatus(path string) string {
+	data, err := os.ReadFile(path) // #nosec G304 - the pre-commit configuration of the target directory
+	if os.IsNotExist(err) {
+		return "Not configured"
+	}
+	if err != nil {
+		return "Error reading file"
+	}
+	var file preCommitFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return "Error parsing YAML"
+	}
+	if antimojiRepoIndex(&file) >= 0 {
+		return "Configured with antimoji hooks"
+	}
+	return "No antimoji hooks found"
+}
+
+// reviewTemplate renders a setupReview.
+var reviewTemplate = template.Must(template.New("review").Funcs(template.FuncMap{
+	"humanizeInt": func(n int) string {
+		return humanize.Comma(int64(n))
+	},
+	"pluralize": func(count int, singular, plural string) string {
+		if count == 1 {
+			return strconv.Itoa(count) + " " + singular
+		}
+		return strconv.Itoa(count) + " " + plural
+	},
+	"explainBehavior": explainBehavior,
+}).Parse(`Configuration Summary:
+  Mode: {{.Mode}}{{if eq .Mode "zero-tolerance"}} (strictest){{end}}
+  Policy: {{.Policy}}
+  {{- if .Threshold}}
+  Threshold: {{.Threshold}}
+  {{- end}}
+  {{- if .AllowedEmojis}}
+  Allowed emojis: {{len .AllowedEmojis}} configured
+  {{- end}}
+
+Scope Analysis:
+  Files to scan: {{humanizeInt .FileCount}} files
+  Current emojis found: {{pluralize .CurrentEmojis "emoji" "emojis"}}
+
+Configuration Status:
+  .antimoji.yaml: {{if ne .Mode "not configured"}}Present{{else}}Missing{{end}}
+  Pre-commit hooks: {{.PreCommitStatus}}
+
+{{if ne .Mode "not configured"}}Behavior Explanation:
+{{explainBehavior .Mode .CurrentEmojis .PreCommitStatus}}{{else}}
+Setup Required:
+  Run 'antimoji setup-lint --mode=<mode>' to configure antimoji linting.
+  Available modes: zero-tolerance, allow-list, permissive
+{{end}}
+
+Usage Examples:
+  Review configuration: antimoji setup-lint --review
+  Manual scan: antimoji scan .
+  Clean emojis: antimoji clean --in-place .
+`))
+
+// explainBehavior explains what happens on commit, in CI and on manual runs with the
+// profile mode, given the emojis found and the pre-commit status.
+func explainBehavior(mode string, emojiCount int, status string) string {
+	hasPreCommit := strings.Contains(status, "Configured with antimoji hooks")
+	onCommit := "  On commit: No pre-commit hooks configured - manual intervention required"
+
+	var commit, ci, manual string
+	switch mode {
+	case "zero-tolerance":
+		commit = "Will prevent any emojis from being added"
+		manual = "Strict scanning maintains emoji-free codebase"
+		if emojiCount > 0 {
+			commit = fmt.Sprintf("Will automatically remove all %d emojis, then verify none remain", emojiCount)
+			manual = "Strict scanning and cleaning available"
+		}
+		ci = "Will fail build if any emojis are detected"
+		if !hasPreCommit {
+			manual = fmt.Sprintf("Run 'antimoji clean --in-place .' to remove %d emojis", emojiCount)
+		}
+	case "ci-lint":
+		commit = "Will enforce allowlist rules"
+		if emojiCount > 0 {
+			commit = fmt.Sprintf("Will remove non-allowed emojis from %d total found", emojiCount)
+		}
+		ci = "Will fail build if non-allowed emojis detected"
+		manual = "CI-focused linting with allowlist"
+		if !hasPreCommit {
+			manual = fmt.Sprintf("Run 'antimoji clean --in-place .' to remove non-allowed emojis from %d found", emojiCount)
+		}
+	case "allow-list":
+		commit = "Will remove non-allowed emojis and enforce limits"
+		ci = "Will fail build if non-allowed or excessive emojis found"
+		manual = "Selective emoji management"
+		if !hasPreCommit {
+			manual = "Run 'antimoji clean --in-place .' to manage emojis manually"
+		}
+	case "permissive":
+		commit = "Will warn about excessive emoji usage"
+		ci = "Will warn but not fail builds"
+		manual = "Lenient emoji monitoring"
+		if !hasPreCommit {
+			manual = "Run 'antimoji scan .' to monitor emoji usage"
+		}
+	default:
+		commit = "Custom behavior based on profile settings"
+		ci = "Custom rules apply"
+		if emojiCount > 0 || !hasPreCommit {
+			ci = fmt.Sprintf("Custom rules apply to %d emojis found", emojiCount)
+		}
+		manual = "Profile-specific emoji management"
+		if !hasPreCommit {
+			manual = "Use 'antimoji scan/clean' commands manually"
+		}
+	}
+	if hasPreCommit {
+		onCommit = "  On commit: " + commit
+	}
+	return fmt.Sprintf("%s\n  On CI: %s\n  Manual usage: %s", onCommit, ci, manual)
+}
+
+// validateConfiguration validates the antimoji configuration of targetDir and suggests
+// improvements.
+func (h *SetupLintHandler) validateConfiguration(ctx context.Context, targetDir string) error {
+	out := h.output()
+	_, _ = fmt.Fprintf(out, "Antimoji Configuration Validation\n")
+	_, _ = fmt.Fprintf(out, "==================================\n\n")
+
+	configPath := filepath.Join(targetDir, defaultConfigFile)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		_, _ = fmt.Fprintf(out, "No antimoji configuration found at %s\n", configPath)
+		_, _ = fmt.Fprintf(out, "Run 'antimoji setup-lint --mode=<mode>' to create configuration.\n")
+		return nil
+	}
+
+	result := config.ValidateConfigFile(configPath)
+	h.logger.Debug(ctx, "Configuration validated", "path", configPath, "issues", len(result.Issues))
+
+	_, _ = fmt.Fprintf(out, "Configuration File: %s\n", configPath)
+	_, _ = fmt.Fprintf(out, "Status: %s\n\n", result.Summary.String())
+
+	if len(result.Issues) > 0 {
+		_, _ = fmt.Fprintf(out, "Issues Found:\n")
+		for _, issue := range result.Issues {
+			_, _ = fmt.Fprintf(out, "  %s\n\n", issue.String())
+		}
+	} else {
+		_, _ = fmt.Fprintf(out, "No issues found. Configuration is valid!\n")
+	}
+
+	if result.Summary.Infos > 0 {
+		_, _ = fmt.Fprintf(out, "Suggestions for improvement:\n")
+		for _, issue := range result.Issues {
+			if issue.Level == config.ValidationLevelInfo {
+				_, _ = fmt.Fprintf(out, "  - %s\n", issue.Message)
+			}
+		}
+	}
+
+	// Report version drift between the binary, the configuration and the hooks
+	if content, err := os.ReadFile(configPath); err == nil { // #nosec G304 - configuration in the target directory
+		_, _ = fmt.Fprintf(out, "\n")
+		writeVersionCheck(out, targetDir, content, h.version)
+	}
+	return nil
+}
@@ -1,12 +1,23 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/antimoji/antimoji/internal/config"
 	"github.com/antimoji/antimoji/internal/observability/logging"
 	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewSetupLintHandler(t *testing.T) {
@@ -49,6 +60,7 @@ func TestSetupLintHandler_CreateCommand(t *testing.T) {
 		assert.NotNil(t, flags.Lookup("repair"))
 		assert.NotNil(t, flags.Lookup("review"))
 		assert.NotNil(t, flags.Lookup("validate"))
+		assert.NotNil(t, flags.Lookup("hook-manager"))
 	})
 
 	t.Run("flag defaults are correct", func(t *testing.T) {
@@ -64,6 +76,7 @@ func TestSetupLintHandler_CreateCommand(t *testing.T) {
 		repair, _ := flags.GetBool("repair")
 		review, _ := flags.GetBool("review")
 		validate, _ := flags.GetBool("validate")
+		hookManager, _ := flags.GetString("hook-manager")
 
 		assert.Equal(t, "zero-tolerance", mode)
 		assert.Equal(t, ".", outputDir)
@@ -75,140 +88,199 @@ func TestSetupLintHandler_CreateCommand(t *testing.T) {
 		assert.False(t, repair)
 		assert.False(t, review)
 		assert.False(t, validate)
+		assert.Equal(t, "pre-commit", hookManager)
 	})
 }
 
-func TestSetupLintHandler_Execute(t *testing.T) {
-	logger := logging.NewMockLogger()
-	uiOutput := ui.NewUserOutput(ui.DefaultConfig())
-	handler := NewSetupLintHandler(logger, uiOutput)
-
-	t.Run("executes with placeholder implementation", func(t *testing.T) {
-		opts := &SetupLintOptions{
-			Mode:              "zero-tolerance",
-			OutputDir:         ".",
-			PreCommitConfig:   true,
-			AllowedEmojis:     []string{"✅", "❌"},
-			Force:             false,
-			SkipPreCommitHook: false,
-			Repair:            false,
-			Review:            false,
-			Validate:          false,
-		}
-
-		err := handler.Execute(context.Background(), nil, []string{"."}, opts)
-
-		// Should return placeholder error
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "not yet fully refactored")
+// newTestSetupLintHandler returns a quiet handler that finds no binaries on PATH and
+// writes its output to out.
+func newTestSetupLintHandler(out io.Writer) *SetupLintHandler {
+	handler := NewSetupLintHandler(logging.NewMockLogger(), quietOutput()).WithOutput(out)
+	handler.lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+	return handler
+}
 
-		// Check logging occurred
-		logs := logger.GetLogs()
-		assert.True(t, len(logs) > 0)
+// readPreCommitFile parses the pre-commit configuration in dir.
+func readPreCommitFile(t *testing.T, dir string) preCommitFile {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, preCommitConfigFile))
+	require.NoError(t, err)
+	var file preCommitFile
+	require.NoError(t, yaml.Unmarshal(data, &file))
+	return file
+}
 
-		foundStart := false
-		foundEnd := false
-		for _, log := range logs {
-			if log.Message == "Starting setup-lint operation" {
-				foundStart = true
-			}
-			if log.Message == "Setup-lint command executed with DI" {
-				foundEnd = true
-			}
+// antimojiHookOf returns the hook with id in the antimoji repository of file.
+func antimojiHookOf(t *testing.T, file preCommitFile, id string) preCommitHook {
+	t.Helper()
+	i := antimojiRepoIndex(&file)
+	require.GreaterOrEqual(t, i, 0, "no antimoji repository")
+	for _, hook := range file.Repos[i].Hooks {
+		if hook.ID == id {
+			return hook
 		}
-		assert.True(t, foundStart)
-		assert.True(t, foundEnd)
+	}
+	t.Fatalf("no %s hook", id)
+	return preCommitHook{}
+}
+
+func TestSetupLintHandler_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("generates the configuration and the check hook", func(t *testing.T) {
+		dir := t.TempDir()
+		var out bytes.Buffer
+		opts := &SetupLintOptions{Mode: "zero-tolerance", OutputDir: ".", PreCommitConfig: true, SkipPreCommitHook: true}
+
+		require.NoError(t, newTestSetupLintHandler(&out).Execute(ctx, nil, []string{dir}, opts))
+
+		data, err := os.ReadFile(filepath.Join(dir, defaultConfigFile))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(data), fmt.Sprintf("version: %d\n", config.SchemaVersion)))
+		cfg := config.LoadConfig(filepath.Join(dir, defaultConfigFile))
+		require.True(t, cfg.IsOk())
+		assert.Contains(t, cfg.Unwrap().Profiles, "zero-tolerance")
+
+		hook := antimojiHookOf(t, readPreCommitFile(t, dir), "antimoji-check")
+		assert.Equal(t, "antimoji", hook.Entry)
+		assert.Equal(t, []string{"check", "--fix", "--config=.antimoji.yaml", "--profile=zero-tolerance", "--threshold=0", "--quiet"}, hook.Args)
+		assert.Equal(t, hookFilesPattern, hook.Files)
+		assert.True(t, hook.PassFilenames)
 	})
 
-	t.Run("handles nil context", func(t *testing.T) {
-		opts := &SetupLintOptions{
-			Mode:      "allow-list",
-			OutputDir: "/tmp/test",
-		}
+	t.Run("permissive mode only verifies", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := &SetupLintOptions{Mode: "permissive", PreCommitConfig: true, SkipPreCommitHook: true}
 
-		err := handler.Execute(context.TODO(), nil, []string{"test-path"}, opts)
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
 
-		assert.Error(t, err) // Placeholder error
+		hook := antimojiHookOf(t, readPreCommitFile(t, dir), "antimoji-check")
+		assert.NotContains(t, hook.Args, "--fix")
+		assert.Contains(t, hook.Args, "--threshold=20")
+		assert.False(t, hook.RequireSerial)
 	})
 
-	t.Run("logs all operation parameters", func(t *testing.T) {
-		opts := &SetupLintOptions{
-			Mode:              "allow-list",
-			OutputDir:         "/custom/path",
-			PreCommitConfig:   false,
-			AllowedEmojis:     []string{"🚀", "✨"},
-			Force:             true,
-			SkipPreCommitHook: true,
-			Repair:            true,
-			Review:            true,
-			Validate:          true,
+	t.Run("adds the commit-msg hook", func(t *testing.T) {
+		for _, existing := range []string{"", "repos: []\n"} {
+			dir := t.TempDir()
+			if existing != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, preCommitConfigFile), []byte(existing), 0644))
+			}
+			var out bytes.Buffer
+			handler := newTestSetupLintHandler(&out)
+			handler.ui = ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: io.Discard, ErrorWriter: io.Discard})
+			opts := &SetupLintOptions{Mode: "allow-list", PreCommitConfig: true, SkipPreCommitHook: true, CommitMsgHook: true}
+
+			require.NoError(t, handler.Execute(ctx, nil, []string{dir}, opts))
+
+			hook := antimojiHookOf(t, readPreCommitFile(t, dir), "antimoji-commit-msg")
+			assert.Equal(t, []string{"hook", "commit-msg", "--config=.antimoji.yaml", "--profile=allow-list"}, hook.Args)
+			assert.Equal(t, []string{"commit-msg"}, hook.Stages)
+			assert.Empty(t, hook.Files)
+			assert.Contains(t, out.String(), "pre-commit install --hook-type pre-commit --hook-type commit-msg")
 		}
+	})
 
-		err := handler.Execute(context.Background(), nil, []string{"/test/path"}, opts)
-
-		assert.Error(t, err) // Placeholder error
-
-		// Verify parameters were logged correctly
-		logs := logger.GetLogs()
-		foundCorrectMode := false
-		foundCorrectOutputDir := false
+	t.Run("builds antimoji with the Makefile when it is not installed", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Makefile"), []byte("build:\n"), 0644))
+		opts := &SetupLintOptions{Mode: "zero-tolerance", PreCommitConfig: true, SkipPreCommitHook: true}
 
-		for _, log := range logs {
-			if log.Message == "Starting setup-lint operation" {
-				// Check log contains correct parameters
-				for i := 0; i < len(log.KeysAndValues)-1; i += 2 {
-					if log.KeysAndValues[i] == "mode" && log.KeysAndValues[i+1] == "allow-list" {
-						foundCorrectMode = true
-					}
-					if log.KeysAndValues[i] == "output_dir" && log.KeysAndValues[i+1] == "/custom/path" {
-						foundCorrectOutputDir = true
-					}
-				}
-			}
-		}
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
 
-		assert.True(t, foundCorrectMode)
-		assert.True(t, foundCorrectOutputDir)
+		file := readPreCommitFile(t, dir)
+		assert.Equal(t, "make build", antimojiHookOf(t, file, "build-antimoji").Entry)
+		assert.Equal(t, localAntimojiCommand, antimojiHookOf(t, file, "antimoji-check").Entry)
 	})
 
-	t.Run("handles different modes", func(t *testing.T) {
-		modes := []string{"zero-tolerance", "allow-list", "permissive"}
+	t.Run("replaces the antimoji hooks of an existing configuration", func(t *testing.T) {
+		dir := t.TempDir()
+		existing := `repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v6.0.0
+    hooks:
+      - id: trailing-whitespace
+  - repo: local
+    hooks:
+      - id: antimoji-clean
+        entry: antimoji clean --in-place
+      - id: antimoji-verify
+        entry: antimoji scan
+`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, preCommitConfigFile), []byte(existing), 0644))
+		opts := &SetupLintOptions{Mode: "allow-list", PreCommitConfig: true, SkipPreCommitHook: true, Force: true}
+
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
+
+		file := readPreCommitFile(t, dir)
+		require.Len(t, file.Repos, 2)
+		assert.Equal(t, "https://github.com/pre-commit/pre-commit-hooks", file.Repos[0].Repo)
+		require.Len(t, file.Repos[1].Hooks, 1)
+		assert.Equal(t, "antimoji-check", file.Repos[1].Hooks[0].ID)
+		assert.Contains(t, file.Repos[1].Hooks[0].Args, "--threshold=5")
+	})
 
-		for _, mode := range modes {
-			t.Run("mode_"+mode, func(t *testing.T) {
-				opts := &SetupLintOptions{
-					Mode:      mode,
-					OutputDir: ".",
-				}
+	t.Run("asks before replacing the antimoji hooks", func(t *testing.T) {
+		dir := t.TempDir()
+		existing := "repos:\n  - repo: local\n    hooks:\n      - id: antimoji-verify\n        entry: antimoji scan\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, preCommitConfigFile), []byte(existing), 0644))
+		var out bytes.Buffer
+		handler := newTestSetupLintHandler(&out)
+		handler.ui = ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: io.Discard, ErrorWriter: io.Discard})
+		handler.WithPrompter(ui.NewPrompter(strings.NewReader("n\n"), &out))
+		opts := &SetupLintOptions{Mode: "zero-tolerance", PreCommitConfig: true, SkipPreCommitHook: true}
+
+		require.NoError(t, handler.Execute(ctx, nil, []string{dir}, opts))
+
+		assert.Contains(t, out.String(), "Replace them with the new configuration?")
+		data, err := os.ReadFile(filepath.Join(dir, preCommitConfigFile))
+		require.NoError(t, err)
+		assert.Equal(t, existing, string(data))
+	})
 
-				err := handler.Execute(context.Background(), nil, []string{"."}, opts)
+	t.Run("keeps an existing configuration", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, defaultConfigFile)
+		require.NoError(t, os.WriteFile(path, []byte("profiles: {}\n"), 0644))
+		opts := &SetupLintOptions{Mode: "zero-tolerance", SkipPreCommitHook: true}
+
+		err := newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrConfig)
+		assert.Contains(t, err.Error(), "use --force to overwrite")
+
+		opts.Repair = true
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "profiles: {}\n", string(data))
+	})
 
-				assert.Error(t, err) // Still placeholder
-				assert.Contains(t, err.Error(), "not yet fully refactored")
-			})
-		}
+	t.Run("rejects an invalid mode", func(t *testing.T) {
+		err := newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{t.TempDir()}, &SetupLintOptions{Mode: "lenient"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid linting mode")
 	})
 
-	t.Run("handles all boolean flags", func(t *testing.T) {
-		opts := &SetupLintOptions{
-			Mode:              "zero-tolerance",
-			OutputDir:         ".",
-			PreCommitConfig:   true,
-			AllowedEmojis:     []string{},
-			Force:             true,
-			SkipPreCommitHook: true,
-			Repair:            true,
-			Review:            true,
-			Validate:          true,
-		}
+	t.Run("rejects a missing directory", func(t *testing.T) {
+		err := newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{filepath.Join(t.TempDir(), "missing")}, &SetupLintOptions{Mode: "zero-tolerance"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "target directory does not exist")
+	})
 
-		err := handler.Execute(context.Background(), nil, []string{"."}, opts)
+	t.Run("reviews and validates the generated setup", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := &SetupLintOptions{Mode: "zero-tolerance", PreCommitConfig: true, SkipPreCommitHook: true}
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
 
-		assert.Error(t, err) // Placeholder error
+		var out bytes.Buffer
+		require.NoError(t, newTestSetupLintHandler(&out).Execute(ctx, nil, []string{dir}, &SetupLintOptions{Review: true}))
+		assert.Contains(t, out.String(), "Mode: zero-tolerance (strictest)")
+		assert.Contains(t, out.String(), "Pre-commit hooks: Configured with antimoji hooks")
 
-		// Just verify it doesn't panic with all flags set to true
-		logs := logger.GetLogs()
-		assert.True(t, len(logs) > 0)
+		out.Reset()
+		require.NoError(t, newTestSetupLintHandler(&out).Execute(ctx, nil, []string{dir}, &SetupLintOptions{Validate: true}))
+		assert.Contains(t, out.String(), "Configuration File: "+filepath.Join(dir, defaultConfigFile))
 	})
 }
 
@@ -237,3 +309,253 @@ func TestSetupLintOptions_Struct(t *testing.T) {
 		assert.False(t, opts.Validate)
 	})
 }
+
+// The tests below are regression tests for bugs the generated configuration once had:
+// over-indented entries pre-commit could not parse, antimoji flags that did not exist,
+// check-yaml rejecting multi-document files, Go hooks outside Go modules and build
+// hooks when antimoji is installed.
+
+// setupLintModes are the linting modes setup-lint generates configurations for.
+var setupLintModes = []lintMode{zeroToleranceMode, allowListMode, permissiveMode}
+
+// hooksByID returns the hooks of file by id.
+func hooksByID(file preCommitFile) map[string]preCommitHook {
+	hooks := make(map[string]preCommitHook)
+	for _, repo := range file.Repos {
+		for _, hook := range repo.Hooks {
+			hooks[hook.ID] = hook
+		}
+	}
+	return hooks
+}
+
+// newHookRoot returns a root command with the commands and global flags the generated
+// hooks run antimoji with.
+func newHookRoot(out io.Writer) *cobra.Command {
+	root := &cobra.Command{Use: "antimoji", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", "", "config file path")
+	root.PersistentFlags().String("profile", "default", "configuration profile")
+	root.PersistentFlags().BoolP("quiet", "q", false, "quiet mode")
+	root.PersistentFlags().Bool("dry-run", false, "show what would be changed")
+	root.PersistentFlags().StringArray(setFlag, nil, "override a profile field")
+	root.PersistentFlags().Bool(strictConfigFlag, false, "fail on unknown config keys")
+	root.PersistentFlags().String(strings.TrimPrefix(requireVersionFlag, "--"), "", "required antimoji release")
+	root.AddCommand(NewCheckHandler(logging.NewMockLogger(), quietOutput()).WithOutput(out).CreateCommand())
+	root.AddCommand(NewHookHandler(logging.NewMockLogger(), quietOutput()).CreateCommand())
+	return root
+}
+
+// hookCommandLine returns the antimoji arguments hook runs with, without the binary.
+func hookCommandLine(hook preCommitHook) []string {
+	return append(strings.Fields(hook.Entry)[1:], hook.Args...)
+}
+
+func TestSetupLintHandler_GeneratedPreCommitConfig(t *testing.T) {
+	for _, mode := range setupLintModes {
+		for _, goModule := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s go.mod=%t", mode, goModule), func(t *testing.T) {
+				dir := t.TempDir()
+				if goModule {
+					require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module test\n\ngo 1.21\n"), 0644))
+				}
+				content := newTestSetupLintHandler(io.Discard).generatePreCommitConfig(dir, mode, &SetupLintOptions{CommitMsgHook: true})
+
+				var file preCommitFile
+				require.NoError(t, yaml.Unmarshal([]byte(content), &file), "generated YAML must parse")
+				for i, line := range strings.Split(content, "\n") {
+					if strings.HasPrefix(strings.TrimSpace(line), "entry:") {
+						assert.Equal(t, 8, len(line)-len(strings.TrimLeft(line, " ")), "line %d: %q", i+1, line)
+					}
+				}
+
+				hooks := hooksByID(file)
+				assert.Equal(t, []string{"--allow-multiple-documents"}, hooks["check-yaml"].Args)
+				assert.Contains(t, hooks, "antimoji-check")
+				assert.Contains(t, hooks, "antimoji-commit-msg")
+				assert.NotContains(t, hooks, "build-antimoji", "antimoji is run from PATH")
+				_, goFmt := hooks["go-fmt"]
+				_, goModTidy := hooks["go-mod-tidy"]
+				assert.Equal(t, goModule, goFmt)
+				assert.Equal(t, goModule, goModTidy)
+			})
+		}
+	}
+}
+
+func TestSetupLintHandler_GeneratedHookFlags(t *testing.T) {
+	for _, mode := range setupLintModes {
+		for _, pin := range []string{"", "v1.2.3"} {
+			t.Run(fmt.Sprintf("%s pin=%q", mode, pin), func(t *testing.T) {
+				content := newTestSetupLintHandler(io.Discard).generatePreCommitConfig(t.TempDir(), mode,
+					&SetupLintOptions{CommitMsgHook: true, PinVersion: pin})
+				var file preCommitFile
+				require.NoError(t, yaml.Unmarshal([]byte(content), &file))
+
+				hooks := hooksByID(file)
+				for _, id := range []string{"antimoji-check", "antimoji-commit-msg"} {
+					args := hookCommandLine(hooks[id])
+					cmd, rest, err := newHookRoot(io.Discard).Find(args)
+					require.NoError(t, err, id)
+					assert.NoError(t, cmd.ParseFlags(rest), "%s runs antimoji with %v", id, args)
+				}
+			})
+		}
+	}
+}
+
+func TestSetupLintHandler_AntimojiCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed bool
+		makefile  bool
+		command   string
+	}{
+		{name: "installed", installed: true, makefile: true, command: "antimoji"},
+		{name: "built with the Makefile", makefile: true, command: localAntimojiCommand},
+		{name: "neither installed nor buildable", command: "antimoji"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.makefile {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "Makefile"), []byte("build:\n"), 0644))
+			}
+			handler := newTestSetupLintHandler(io.Discard)
+			if tt.installed {
+				handler.lookPath = func(file string) (string, error) { return "/usr/local/bin/" + file, nil }
+			}
+
+			assert.Equal(t, tt.command, handler.antimojiCommand(dir))
+
+			var file preCommitFile
+			require.NoError(t, yaml.Unmarshal([]byte(handler.generatePreCommitConfig(dir, zeroToleranceMode, &SetupLintOptions{})), &file))
+			hooks := hooksByID(file)
+			assert.Equal(t, tt.command, hooks["antimoji-check"].Entry)
+			_, build := hooks["build-antimoji"]
+			assert.Equal(t, tt.command == localAntimojiCommand, build, "antimoji is built only when it is not installed")
+		})
+	}
+}
+
+func TestSetupLintHandler_ModeProfiles(t *testing.T) {
+	ctx := context.Background()
+	for _, mode := range setupLintModes {
+		t.Run(string(mode), func(t *testing.T) {
+			dir := t.TempDir()
+			opts := &SetupLintOptions{Mode: string(mode), SkipPreCommitHook: true}
+			if mode == allowListMode {
+				opts.AllowedEmojis = []string{"✅", "❌"}
+			}
+			require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
+
+			cfg := config.LoadConfig(filepath.Join(dir, defaultConfigFile))
+			require.True(t, cfg.IsOk())
+			require.Contains(t, cfg.Unwrap().Profiles, string(mode))
+			profile := cfg.Unwrap().Profiles[string(mode)]
+			assert.True(t, profile.UnicodeEmojis)
+			assert.True(t, profile.TextEmoticons)
+
+			switch mode {
+			case zeroToleranceMode:
+				assert.Empty(t, profile.EmojiAllowlist)
+				assert.True(t, profile.FailOnFound)
+				assert.Equal(t, 0, profile.MaxTotal)
+				assert.Equal(t, 1, profile.ExitCodeOnFound)
+			case allowListMode:
+				assert.Equal(t, []string{"✅", "❌"}, profile.EmojiAllowlist)
+				assert.True(t, profile.FailOnFound)
+				assert.Equal(t, 5, profile.MaxTotal)
+			case permissiveMode:
+				assert.GreaterOrEqual(t, len(profile.EmojiAllowlist), 10)
+				assert.False(t, profile.FailOnFound)
+				assert.Equal(t, 20, profile.MaxTotal)
+				assert.Equal(t, 0, profile.ExitCodeOnFound)
+			}
+		})
+	}
+}
+
+func TestSetupLintHandler_Repair(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("adds what is missing", func(t *testing.T) {
+		dir := t.TempDir()
+		existing := "repos:\n  - repo: https://github.com/pre-commit/pre-commit-hooks\n    rev: v4.4.0\n    hooks:\n      - id: trailing-whitespace\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, preCommitConfigFile), []byte(existing), 0644))
+		opts := &SetupLintOptions{Mode: "zero-tolerance", Repair: true, PreCommitConfig: true, SkipPreCommitHook: true}
+
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
+
+		assert.FileExists(t, filepath.Join(dir, defaultConfigFile))
+		hooks := hooksByID(readPreCommitFile(t, dir))
+		assert.Contains(t, hooks, "trailing-whitespace")
+		assert.Contains(t, hooks, "antimoji-check")
+	})
+
+	t.Run("keeps an existing setup", func(t *testing.T) {
+		dir := t.TempDir()
+		existing := "repos:\n  - repo: local\n    hooks:\n      - id: antimoji-clean\n        entry: antimoji\n        language: system\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, preCommitConfigFile), []byte(existing), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, defaultConfigFile), []byte("profiles:\n  default: {}\n"), 0644))
+		opts := &SetupLintOptions{Mode: "zero-tolerance", Repair: true, PreCommitConfig: true, SkipPreCommitHook: true}
+
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
+
+		data, err := os.ReadFile(filepath.Join(dir, preCommitConfigFile))
+		require.NoError(t, err)
+		assert.Equal(t, existing, string(data))
+		data, err = os.ReadFile(filepath.Join(dir, defaultConfigFile))
+		require.NoError(t, err)
+		assert.Equal(t, "profiles:\n  default: {}\n", string(data))
+	})
+
+	t.Run("does not duplicate hooks when run again", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := &SetupLintOptions{Mode: "allow-list", PreCommitConfig: true, SkipPreCommitHook: true, CommitMsgHook: true, Force: true}
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, []string{dir}, opts))
+
+		data, err := os.ReadFile(filepath.Join(dir, preCommitConfigFile))
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(data), "id: antimoji-check"))
+		assert.Equal(t, 1, strings.Count(string(data), "id: antimoji-commit-msg"))
+	})
+}
+
+func TestSetupLintHandler_ReviewWithoutConfiguration(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, newTestSetupLintHandler(&out).Execute(context.Background(), nil, []string{t.TempDir()}, &SetupLintOptions{Review: true}))
+	assert.Contains(t, out.String(), "not configured")
+	assert.Contains(t, out.String(), "Not configured")
+}
+
+// TestSetupLintHandler_EndToEnd sets up a Go module with multi-document YAML and runs
+// the generated check hook, as pre-commit would, on a staged file.
+func TestSetupLintHandler_EndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module test\n\ngo 1.21\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "k8s.yaml"), []byte("---\nkind: ConfigMap\n---\nkind: Service\n"), 0644))
+	source := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(source, []byte("package main\n\n// ship it 🚀\n"), 0644))
+	opts := &SetupLintOptions{Mode: "zero-tolerance", PreCommitConfig: true, SkipPreCommitHook: true}
+	require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(context.Background(), nil, []string{dir}, opts))
+
+	file := readPreCommitFile(t, dir)
+	hooks := hooksByID(file)
+	assert.Equal(t, []string{"--allow-multiple-documents"}, hooks["check-yaml"].Args)
+	assert.Contains(t, hooks, "go-fmt")
+
+	// Hooks run in the repository root with the staged files appended
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	root := newHookRoot(io.Discard)
+	root.SetArgs(append(hookCommandLine(hooks["antimoji-check"]), "main.go"))
+	require.NoError(t, root.Execute())
+
+	data, err := os.ReadFile(source)
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\n// ship it \n", string(data))
+}
@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/config"
+)
+
+// requireVersionFlag is the global flag generated hooks pass to antimoji with
+// --pin-version; antimoji fails when it is not the version required.
+const requireVersionFlag = "--require-version"
+
+// normalizePinVersion returns version as vX.Y.Z, or an error when it is not the version
+// of a release.
+func normalizePinVersion(version string) (string, error) {
+	if !releaseVersionPattern.MatchString(version) {
+		return "", classify(ErrConfig, fmt.Errorf("invalid --pin-version: %s (must be a release version such as v1.2.3)", version))
+	}
+	return "v" + strings.TrimPrefix(version, "v"), nil
+}
+
+// pinnedCommand returns the command hooks run antimoji with, requiring pinVersion when
+// one is given.
+func pinnedCommand(antimojiCmd, pinVersion string) string {
+	if pinVersion == "" {
+		return antimojiCmd
+	}
+	return fmt.Sprintf("%s %s=%s", antimojiCmd, requireVersionFlag, pinVersion)
+}
+
+// hookDefinitionFiles are the files, relative to the target directory, in which
+// setup-lint writes hooks and CI jobs that run antimoji.
+func hookDefinitionFiles() []string {
+	files := []string{
+		".pre-commit-config.yaml",
+		filepath.Join(".husky", "pre-commit"),
+		filepath.Join(".husky", "commit-msg"),
+		"lefthook.yml",
+		".lintstagedrc.json",
+		"package.json",
+	}
+	for _, provider := range ciProviders {
+		files = append(files, ciTemplates[provider].path)
+	}
+	return files
+}
+
+// versionPinPattern matches the antimoji version a hook requires or a CI job installs.
+var versionPinPattern = regexp.MustCompile(`(?:` + requireVersionFlag + `[= ]|ANTIMOJI_VERSION:\s*)(v?\d+\.\d+\.\d+)\b`)
+
+// versionPin is an antimoji version pinned by a hook definition file.
+type versionPin struct {
+	file    string
+	version string
+}
+
+// findVersionPins returns the versions pinned by the hook definition files in
+// targetDir, and the files that run antimoji without pinning it.
+func findVersionPins(targetDir string) (pins []versionPin, unpinned []string) {
+	for _, file := range hookDefinitionFiles() {
+		data, err := os.ReadFile(filepath.Join(targetDir, file)) // #nosec G304 - files generated by setup-lint
+		if err != nil || !strings.Contains(string(data), "antimoji") {
+			continue
+		}
+		matches := versionPinPattern.FindAllStringSubmatch(string(data), -1)
+		if len(matches) == 0 {
+			unpinned = append(unpinned, filepath.ToSlash(file))
+			continue
+		}
+		seen := map[string]bool{}
+		for _, match := range matches {
+			version := "v" + strings.TrimPrefix(match[1], "v")
+			if !seen[version] {
+				seen[version] = true
+				pins = append(pins, versionPin{file: filepath.ToSlash(file), version: version})
+			}
+		}
+	}
+	return pins, unpinned
+}
+
+// versionDrift describes how the versions of binary, the configuration schema in
+// configContent and the hook definitions in targetDir disagree. No drift yields no
+// lines; notes are reported when nothing is pinned.
+func versionDrift(targetDir string, configContent []byte, binary string) (drift, notes []string) {
+	if schema, err := config.DetectSchemaVersion(configContent); err == nil {
+		switch {
+		case schema < config.SchemaVersion:
+			drift = append(drift, fmt.Sprintf("configuration schema %d is older than schema %d of this antimoji; run 'antimoji config migrate'", schema, config.SchemaVersion))
+		case schema > config.SchemaVersion:
+			drift = append(drift, fmt.Sprintf("configuration schema %d is newer than this antimoji supports (%d); upgrade antimoji", schema, config.SchemaVersion))
+		}
+	}
+
+	pins, unpinned := findVersionPins(targetDir)
+	files := map[string][]string{}
+	for _, pin := range pins {
+		files[pin.version] = append(files[pin.version], pin.file)
+	}
+	versions := make([]string, 0, len(files))
+	for version := range files {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	if len(versions) > 1 {
+		described := make([]string, len(versions))
+		for i, version := range versions {
+			described[i] = fmt.Sprintf("%s (%s)", version, strings.Join(files[version], ", "))
+		}
+		drift = append(drift, "hook definitions pin different versions: "+strings.Join(described, ", "))
+	}
+	current, released := "vX.Y.Z", releaseVersionPattern.MatchString(binary)
+	if released {
+		current = "v" + strings.TrimPrefix(binary, "v")
+	}
+	for _, version := range versions {
+		if released && version != current {
+			drift = append(drift, fmt.Sprintf("%s pins %s but this antimoji is %s", strings.Join(files[version], ", "), version, binary))
+		}
+	}
+	if len(unpinned) > 0 {
+		notes = append(notes, fmt.Sprintf("%s run whichever antimoji is on PATH; pin it with 'antimoji setup-lint --force --pin-version %s'",
+			strings.Join(unpinned, ", "), current))
+	}
+	return drift, notes
+}
+
+// writeVersionCheck writes the version drift between binary, the running antimoji,
+// and the setup in targetDir.
+func writeVersionCheck(out io.Writer, targetDir string, configContent []byte, binary string) {
+	drift, notes := versionDrift(targetDir, configContent, binary)
+
+	_, _ = fmt.Fprintf(out, "Version Check:\n")
+	_, _ = fmt.Fprintf(out, "  antimoji binary: %s (configuration schema %d)\n", binary, config.SchemaVersion)
+	if len(drift) == 0 {
+		_, _ = fmt.Fprintf(out, "  No version drift found.\n")
+	}
+	for _, line := range drift {
+		_, _ = fmt.Fprintf(out, "  Drift: %s\n", line)
+	}
+	for _, line := range notes {
+		_, _ = fmt.Fprintf(out, "  Note: %s\n", line)
+	}
+	_, _ = fmt.Fprintf(out, "\n")
+}
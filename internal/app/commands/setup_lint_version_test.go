@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePinVersion(t *testing.T) {
+	version, err := normalizePinVersion("1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", version)
+
+	_, err = normalizePinVersion("latest")
+	assert.ErrorContains(t, err, "invalid --pin-version")
+}
+
+func TestSetupLintHandler_PinVersion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pre-commit", func(t *testing.T) {
+		handler := newTestSetupLintHandler(io.Discard)
+		opts := &SetupLintOptions{CommitMsgHook: true, PinVersion: "v1.2.3"}
+		config := handler.generatePreCommitConfig(t.TempDir(), zeroToleranceMode, opts)
+		assert.Contains(t, config, "entry: antimoji --require-version=v1.2.3\n        args: [check, --fix")
+		assert.Contains(t, config, "entry: antimoji --require-version=v1.2.3\n        args: [hook, commit-msg")
+
+		repo := handler.antimojiRepo(t.TempDir(), zeroToleranceMode, &SetupLintOptions{PinVersion: "v1.2.3"})
+		assert.Equal(t, "antimoji --require-version=v1.2.3", repo.Hooks[len(repo.Hooks)-1].Entry)
+	})
+
+	t.Run("hook managers and CI", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := &SetupLintOptions{Mode: string(zeroToleranceMode), OutputDir: dir, HookManager: string(huskyManager),
+			GitHubActions: true, PinVersion: "1.2.3", SkipPreCommitHook: true}
+		require.NoError(t, newTestSetupLintHandler(io.Discard).Execute(ctx, nil, nil, opts))
+
+		hook, err := os.ReadFile(filepath.Join(dir, ".husky", "pre-commit"))
+		require.NoError(t, err)
+		assert.Contains(t, string(hook), "--require-version=v1.2.3 check --fix")
+		job, err := os.ReadFile(filepath.Join(dir, ".github", "workflows", "antimoji.yml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(job), "ANTIMOJI_VERSION: v1.2.3")
+
+		pins, unpinned := findVersionPins(dir)
+		assert.Empty(t, unpinned)
+		assert.ElementsMatch(t, []versionPin{
+			{file: ".husky/pre-commit", version: "v1.2.3"},
+			{file: "package.json", version: "v1.2.3"},
+			{file: ".github/workflows/antimoji.yml", version: "v1.2.3"},
+		}, pins)
+
+		var out bytes.Buffer
+		require.NoError(t, newTestSetupLintHandler(&out).WithVersion("1.2.4").Execute(ctx, nil, []string{dir}, &SetupLintOptions{Validate: true}))
+		assert.Contains(t, out.String(), "Drift: .husky/pre-commit, package.json, .github/workflows/antimoji.yml pins v1.2.3 but this antimoji is 1.2.4")
+	})
+
+	t.Run("rejects versions that are not releases", func(t *testing.T) {
+		opts := &SetupLintOptions{Mode: string(zeroToleranceMode), OutputDir: t.TempDir(), PinVersion: "main"}
+		err := newTestSetupLintHandler(io.Discard).Execute(ctx, nil, nil, opts)
+		assert.ErrorIs(t, err, ErrConfig)
+		assert.ErrorContains(t, err, "invalid --pin-version: main")
+	})
+}
+
+func TestVersionDrift(t *testing.T) {
+	write := func(t *testing.T, dir, file, content string) {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	current := []byte("version: 3\nprofiles: {}\n")
+
+	t.Run("no drift", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, ".pre-commit-config.yaml", "entry: antimoji --require-version=v0.9.16\n")
+		drift, notes := versionDrift(dir, current, "0.9.16")
+		assert.Empty(t, drift)
+		assert.Empty(t, notes)
+	})
+
+	t.Run("binary, schema and hooks disagree", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, ".pre-commit-config.yaml", "entry: antimoji --require-version=v0.9.15\n")
+		write(t, dir, ".github/workflows/antimoji.yml", "run: antimoji check\nenv:\n  ANTIMOJI_VERSION: v0.9.16\n")
+		drift, _ := versionDrift(dir, []byte("profiles: {}\n"), "0.9.16")
+		assert.Equal(t, []string{
+			"configuration schema 1 is older than schema 3 of this antimoji; run 'antimoji config migrate'",
+			"hook definitions pin different versions: v0.9.15 (.pre-commit-config.yaml), v0.9.16 (.github/workflows/antimoji.yml)",
+			".pre-commit-config.yaml pins v0.9.15 but this antimoji is 0.9.16",
+		}, drift)
+	})
+
+	t.Run("unpinned hooks", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "lefthook.yml", "pre-commit:\n  commands:\n    antimoji-check:\n      run: antimoji check\n")
+		drift, notes := versionDrift(dir, current, "dev")
+		assert.Empty(t, drift)
+		assert.Equal(t, []string{"lefthook.yml run whichever antimoji is on PATH; pin it with 'antimoji setup-lint --force --pin-version vX.Y.Z'"}, notes)
+	})
+}
@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/analysis"
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// StatsOptions holds the options for the stats command.
+type StatsOptions struct {
+	Recursive    bool
+	Top          int
+	Depth        int
+	Format       string
+	ConfigFile   string
+	ProfileName  string
+	Overrides    []string
+	StrictConfig bool
+}
+
+// StatsHandler handles the stats command with dependency injection.
+type StatsHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewStatsHandler creates a new stats command handler.
+func NewStatsHandler(logger logging.Logger, ui ui.UserOutput) *StatsHandler {
+	return &StatsHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the statistics (defaults to stdout).
+func (h *StatsHandler) WithOutput(out io.Writer) *StatsHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the stats cobra command.
+func (h *StatsHandler) CreateCommand() *cobra.Command {
+	opts := &StatsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "stats [flags] [path...]",
+		Short: "Report emoji usage statistics",
+		Long: `Report how emojis are used across a project without modifying anything.
+
+Shows the most used emojis, usage per directory, and counts by file type
+(source, test, documentation, ...) and detection category. The allowlist is not
+applied, so allowed emojis are counted too.
+
+Examples:
+  antimoji stats .                       # Usage tables for the current directory
+  antimoji stats --top 5 --depth 2 .     # Top 5 of each, directories two levels deep
+  antimoji stats --format json . > usage.json
+  antimoji stats --format csv .          # One row per emoji, directory, file type and category`,
+		Args:          cobra.MinimumNArgs(0),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.Execute(cmd.Context(), args, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.Recursive, "recursive", "r", true, "scan directories recursively")
+	cmd.Flags().IntVar(&opts.Top, "top", 10, "number of entries to show per table (0 = all)")
+	cmd.Flags().IntVar(&opts.Depth, "depth", 0, "group directories by their first N path elements (0 = full path)")
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "output format (table, json, csv)")
+
+	return cmd
+}
+
+// Execute runs the stats command logic with dependency injection.
+func (h *StatsHandler) Execute(parentCtx context.Context, args []string, opts *StatsOptions) error {
+	format := strings.ToLower(opts.Format)
+	switch format {
+	case "table", "json", "csv":
+		// ok
+	default:
+		return fmt.Errorf("unsupported format %q; supported: table, json, csv", opts.Format)
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "stats")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	h.logger.Info(ctx, "Starting stats operation", "paths", args, "options", opts)
+
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return fmt.Errorf("failed to load config: %w", configResult.Error())
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error())
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return err
+	}
+	profile := resolution.Profile
+
+	filePaths, err := filtering.DiscoverFiles(args, filtering.DiscoveryOptions{Recursive: opts.Recursive}, profile)
+	if err != nil {
+		h.logger.Error(ctx, "File discovery failed", "error", err, "paths", args)
+		return classify(ErrIO, fmt.Errorf("file discovery failed: %w", err))
+	}
+
+	patterns, err := emojidata.PatternsForProfile(ctx, detector.DefaultEmojiPatterns(), profile)
+	if err != nil {
+		return fmt.Errorf("failed to load emoji data: %w", err)
+	}
+
+	results := processor.ProcessFiles(filePaths, patterns, config.ToProcessingConfig(profile))
+	report := analysis.AnalyzeUsage(results, analysis.UsageOptions{DirectoryDepth: opts.Depth}).Top(opts.Top)
+	h.logger.Info(ctx, "Usage analysis completed",
+		"files", report.TotalFiles, "emojis", report.TotalEmojis, "unique", report.UniqueEmojis)
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(report)
+	case "csv":
+		return writeStatsCSV(out, report)
+	default:
+		return writeStatsTable(out, report)
+	}
+}
+
+// writeStatsTable prints the report as aligned tables.
+func writeStatsTable(out io.Writer, report analysis.UsageReport) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Scanned %d files: %d emojis (%d unique) in %d files\n",
+		report.TotalFiles, report.TotalEmojis, report.UniqueEmojis, report.FilesWithEmojis)
+	if report.TotalEmojis == 0 {
+		return tw.Flush()
+	}
+
+	_, _ = fmt.Fprintf(tw, "\nTop emojis\nEMOJI\tCATEGORY\tCOUNT\tFILES\tSHARE\n")
+	for _, emoji := range report.Emojis {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", emoji.Emoji, emoji.Category, emoji.Count, emoji.Files, share(emoji.Count, report.TotalEmojis))
+	}
+
+	for _, section := range []struct {
+		title, column string
+		groups        []analysis.GroupCount
+	}{
+		{"By directory", "DIRECTORY", report.Directories},
+		{"By file type", "FILE TYPE", report.FileTypes},
+		{"By category", "CATEGORY", report.Categories},
+	} {
+		_, _ = fmt.Fprintf(tw, "\n%s\n%s\tEMOJIS\tFILES\tSHARE\tTOP\n", section.title, section.column)
+		for _, group := range section.groups {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", group.Name, group.Emojis, group.Files, share(group.Emojis, report.TotalEmojis), group.Top)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// writeStatsCSV prints the report as CSV with one row per table entry.
+func writeStatsCSV(out io.Writer, report analysis.UsageReport) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"section", "name", "category", "emojis", "files", "top"})
+	for _, emoji := range report.Emojis {
+		_ = w.Write([]string{"emoji", emoji.Emoji, emoji.Category, strconv.Itoa(emoji.Count), strconv.Itoa(emoji.Files), ""})
+	}
+	for _, section := range []struct {
+		name   string
+		groups []analysis.GroupCount
+	}{
+		{"directory", report.Directories},
+		{"file_type", report.FileTypes},
+		{"category", report.Categories},
+	} {
+		for _, group := range section.groups {
+			_ = w.Write([]string{section.name, group.Name, "", strconv.Itoa(group.Emojis), strconv.Itoa(group.Files), group.Top})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// share formats part as a percentage of total.
+func share(part, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/analysis"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStatsDir(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("// 🚀 🚀 ✅\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "guide.md"), []byte("# 🚀\n"), 0600))
+	return dir
+}
+
+func TestStatsHandler_Execute(t *testing.T) {
+	dir := setupStatsDir(t)
+
+	run := func(t *testing.T, opts *StatsOptions) string {
+		var out bytes.Buffer
+		handler := NewStatsHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+		require.NoError(t, handler.Execute(context.Background(), []string{dir}, opts))
+		return out.String()
+	}
+
+	t.Run("table", func(t *testing.T) {
+		out := run(t, &StatsOptions{Recursive: true, Top: 10, Format: "table"})
+		assert.Contains(t, out, "4 emojis (2 unique) in 2 files")
+		assert.Contains(t, out, "Top emojis")
+		assert.Contains(t, out, "By file type")
+		assert.Contains(t, out, "75.0%")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out := run(t, &StatsOptions{Recursive: true, Top: 1, Format: "json"})
+		var report analysis.UsageReport
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		assert.Equal(t, 4, report.TotalEmojis)
+		require.Len(t, report.Emojis, 1)
+		assert.Equal(t, "🚀", report.Emojis[0].Emoji)
+		assert.Equal(t, 3, report.Emojis[0].Count)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		out := run(t, &StatsOptions{Recursive: true, Format: "csv"})
+		rows, err := csv.NewReader(bytes.NewBufferString(out)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"section", "name", "category", "emojis", "files", "top"}, rows[0])
+		assert.Contains(t, rows, []string{"emoji", "🚀", "unicode", "3", "2", ""})
+		assert.Contains(t, rows, []string{"file_type", "markdown", "", "1", "1", "🚀"})
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		handler := NewStatsHandler(logging.NewMockLogger(), quietOutput())
+		err := handler.Execute(context.Background(), []string{dir}, &StatsOptions{Format: "xml"})
+		assert.ErrorContains(t, err, "unsupported format")
+	})
+}
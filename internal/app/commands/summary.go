@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+)
+
+// ErrSummaryMismatch indicates scan --expect-summary found results that differ from the
+// summary of an earlier run.
+var ErrSummaryMismatch = errors.New("summary mismatch")
+
+// RunSummary is the document written by --summary-file. Scan and clean write the same
+// fields, so a verifying scan can check what a clean run left behind.
+type RunSummary struct {
+	Operation    string `json:"operation"`
+	DryRun       bool   `json:"dry_run"`
+	FilesScanned int    `json:"files_scanned"`
+	// FilesModified counts the files clean changed, or would change in a dry run
+	FilesModified int `json:"files_modified"`
+	FilesFailed   int `json:"files_failed"`
+	EmojisRemoved int `json:"emojis_removed"`
+	// ViolationsRemaining counts the emojis the policy still reports in the files
+	ViolationsRemaining int   `json:"violations_remaining"`
+	DurationMS          int64 `json:"duration_ms"`
+}
+
+// scanSummary returns the summary of a scan over results, which hold violations only.
+func scanSummary(results []types.ProcessResult, violations int, duration time.Duration) RunSummary {
+	return RunSummary{
+		Operation:           "scan",
+		FilesScanned:        len(results),
+		FilesFailed:         countFileFailures(results),
+		ViolationsRemaining: violations,
+		DurationMS:          duration.Milliseconds(),
+	}
+}
+
+// cleanSummary returns the summary of a clean run over results, counting the remaining
+// violations by scanning the files again as the policy of engine sees them.
+func cleanSummary(results []processor.ModifyResult, engine *policy.Engine, patterns types.EmojiPatterns,
+	dryRun bool, duration time.Duration) RunSummary {
+
+	summary := RunSummary{
+		Operation:    "clean",
+		DryRun:       dryRun,
+		FilesScanned: len(results),
+		DurationMS:   duration.Milliseconds(),
+	}
+	paths := make([]string, 0, len(results))
+	for _, result := range results {
+		switch {
+		case isFileFailure(result.Error):
+			summary.FilesFailed++
+		case result.Error == nil && result.Modified:
+			summary.FilesModified++
+			summary.EmojisRemoved += result.EmojisRemoved
+		}
+		paths = append(paths, result.FilePath)
+	}
+
+	rescanned := engine.Apply(processor.ProcessBatch(paths, patterns, engine.ProcessingConfig(), processor.BatchOptions{}))
+	for _, result := range rescanned {
+		if result.Error == nil {
+			summary.ViolationsRemaining += result.DetectionResult.TotalCount
+		}
+	}
+	return summary
+}
+
+// writeSummary writes summary as JSON to path.
+func writeSummary(path string, summary RunSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) // #nosec G306 - summaries are meant to be shared
+}
+
+// readSummary reads a summary written by --summary-file.
+func readSummary(path string) (RunSummary, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is given by the user
+	if err != nil {
+		return RunSummary{}, err
+	}
+	var summary RunSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return RunSummary{}, fmt.Errorf("%s is not a summary file: %w", path, err)
+	}
+	return summary, nil
+}
+
+// reportSummary writes summary to path when one is given.
+func reportSummary(ctx context.Context, output ui.UserOutput, summary RunSummary, path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := writeSummary(path, summary); err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to write summary file: %w", err))
+	}
+	output.Info(ctx, "Summary of the %s written to %s", summary.Operation, path)
+	return nil
+}
+
+// summaryDifferences lists the fields where actual, the summary of this run, differs
+// from expected: the files checked and the violations left. The counts of modified
+// files, removed emojis and the duration describe the earlier run only.
+func summaryDifferences(expected, actual RunSummary) []string {
+	var differences []string
+	compare := func(field string, want, got int) {
+		if want != got {
+			differences = append(differences, fmt.Sprintf("%s: %s reported %d, %s found %d",
+				field, expected.Operation, want, actual.Operation, got))
+		}
+	}
+	compare("files_scanned", expected.FilesScanned, actual.FilesScanned)
+	compare("violations_remaining", expected.ViolationsRemaining, actual.ViolationsRemaining)
+	return differences
+}
+
+// checkSummary compares actual with the summary in path, showing each difference.
+func checkSummary(ctx context.Context, output ui.UserOutput, actual RunSummary, path string) error {
+	expected, err := readSummary(path)
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to read expected summary: %w", err))
+	}
+	if expected.DryRun {
+		output.Warning(ctx, "%s describes a dry run; its files were not modified", path)
+	}
+
+	differences := summaryDifferences(expected, actual)
+	if len(differences) == 0 {
+		output.Info(ctx, "Results match the %s summary in %s", expected.Operation, path)
+		return nil
+	}
+	for _, difference := range differences {
+		output.Error(ctx, "Summary mismatch: %s", difference)
+	}
+	return classify(ErrViolations, fmt.Errorf("%w with %s: %s", ErrSummaryMismatch, path, strings.Join(differences, "; ")))
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryDifferences(t *testing.T) {
+	clean := RunSummary{Operation: "clean", FilesScanned: 3, FilesModified: 2, ViolationsRemaining: 0, DurationMS: 40}
+	scan := RunSummary{Operation: "scan", FilesScanned: 3, ViolationsRemaining: 0, DurationMS: 5}
+	assert.Empty(t, summaryDifferences(clean, scan), "modified files and durations are not compared")
+
+	scan.FilesScanned = 4
+	scan.ViolationsRemaining = 2
+	assert.Equal(t, []string{
+		"files_scanned: clean reported 3, scan found 4",
+		"violations_remaining: clean reported 0, scan found 2",
+	}, summaryDifferences(clean, scan))
+}
+
+func TestSummaryFile(t *testing.T) {
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("// Launch 🚀 🎉\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "plain.go"), []byte("// plain\n"), 0644))
+		return dir
+	}
+	read := func(t *testing.T, path string) RunSummary {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var summary RunSummary
+		require.NoError(t, json.Unmarshal(data, &summary))
+		return summary
+	}
+	clean := func(t *testing.T, dir string, opts CleanOptions) (RunSummary, string) {
+		opts.Recursive = true
+		opts.SummaryFile = filepath.Join(t.TempDir(), "clean.json")
+		require.NoError(t, NewCleanHandler(logging.NewMockLogger(), quietOutput()).Execute(context.Background(), []string{dir}, &opts))
+		return read(t, opts.SummaryFile), opts.SummaryFile
+	}
+	scan := func(t *testing.T, dir string, opts ScanOptions) error {
+		rootCmd := &cobra.Command{Use: "antimoji"}
+		rootCmd.PersistentFlags().String("config", "", "config file path")
+		rootCmd.PersistentFlags().String("profile", "default", "configuration profile")
+		handler := NewScanHandler(logging.NewMockLogger(), quietOutput())
+		scanCmd := handler.CreateCommand()
+		rootCmd.AddCommand(scanCmd)
+		opts.Recursive = true
+		opts.Format = "table"
+		return handler.Execute(context.Background(), scanCmd, []string{dir}, &opts)
+	}
+
+	t.Run("clean and scan agree after cleaning", func(t *testing.T) {
+		dir := setup(t)
+		summary, cleanFile := clean(t, dir, CleanOptions{InPlace: true})
+		assert.Equal(t, RunSummary{
+			Operation: "clean", FilesScanned: 2, FilesModified: 1, EmojisRemoved: 2, DurationMS: summary.DurationMS,
+		}, summary)
+
+		scanFile := filepath.Join(t.TempDir(), "scan.json")
+		require.NoError(t, scan(t, dir, ScanOptions{SummaryFile: scanFile, ExpectSummary: cleanFile}))
+
+		scanned := read(t, scanFile)
+		assert.Equal(t, "scan", scanned.Operation)
+		assert.Equal(t, 2, scanned.FilesScanned)
+		assert.Zero(t, scanned.ViolationsRemaining)
+	})
+
+	t.Run("dry run leaves the violations", func(t *testing.T) {
+		summary, _ := clean(t, setup(t), CleanOptions{DryRun: true})
+		assert.True(t, summary.DryRun)
+		assert.Equal(t, 1, summary.FilesModified)
+		assert.Equal(t, 2, summary.ViolationsRemaining)
+	})
+
+	t.Run("scan reports what clean left behind", func(t *testing.T) {
+		dir := setup(t)
+		// Clean keeps the excepted emoji, which scan still reports
+		summary, cleanFile := clean(t, dir, CleanOptions{InPlace: true, Except: []string{"🎉"}})
+		assert.Zero(t, summary.ViolationsRemaining)
+
+		err := scan(t, dir, ScanOptions{ExpectSummary: cleanFile})
+		assert.ErrorIs(t, err, ErrSummaryMismatch)
+		assert.ErrorIs(t, err, ErrViolations)
+		assert.ErrorContains(t, err, "violations_remaining: clean reported 0, scan found 1")
+	})
+
+	t.Run("missing expected summary is an I/O error", func(t *testing.T) {
+		err := scan(t, setup(t), ScanOptions{ExpectSummary: filepath.Join(t.TempDir(), "missing.json")})
+		assert.ErrorIs(t, err, ErrIO)
+	})
+}
@@ -0,0 +1,258 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/logtail"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/antimoji/antimoji/internal/policy"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// errFailFast ends tailing at the first finding of severity error.
+var errFailFast = errors.New("emoji found")
+
+// TailOptions holds the options for the tail command.
+type TailOptions struct {
+	Follow          bool
+	FromStart       bool
+	FailFast        bool
+	PollInterval    time.Duration
+	Format          string
+	IgnoreAllowlist bool
+	ConfigFile      string
+	ProfileName     string
+	Overrides       []string
+	StrictConfig    bool
+}
+
+// TailHandler handles the tail command with dependency injection.
+type TailHandler struct {
+	logger  logging.Logger
+	ui      ui.UserOutput
+	metrics *metrics.Metrics
+	in      io.Reader
+	out     io.Writer
+}
+
+// NewTailHandler creates a new tail command handler.
+func NewTailHandler(logger logging.Logger, ui ui.UserOutput) *TailHandler {
+	return &TailHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithMetrics sets the metrics that the emojis found are recorded in (defaults to none).
+func (h *TailHandler) WithMetrics(m *metrics.Metrics) *TailHandler {
+	h.metrics = m
+	return h
+}
+
+// WithInput sets the reader logs are read from when no file is given (defaults to
+// stdin).
+func (h *TailHandler) WithInput(in io.Reader) *TailHandler {
+	h.in = in
+	return h
+}
+
+// WithOutput sets the writer used for the findings (defaults to stdout).
+func (h *TailHandler) WithOutput(out io.Writer) *TailHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the tail cobra command.
+func (h *TailHandler) CreateCommand() *cobra.Command {
+	opts := &TailOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "tail [flags] [file]",
+		Short: "Report emojis in log lines as they are written",
+		Long: `Report the emojis the policy does not allow in the lines of a log, one
+finding per line in the format of the lint command.
+
+Without a file, or with -, lines are read from standard input until it ends, so
+journald and other log streams can be piped in; their findings are reported
+under the path -. With --follow, the file is watched for new lines until
+interrupted, like tail -f, and reopened when it is truncated or rotated; only
+lines written after starting are checked unless --from-start is given.
+
+--fail-fast exits at the first finding of severity error. Otherwise the command
+exits 1 when it ends, at the end of the input or on Ctrl-C, if any such finding
+was reported.
+
+Examples:
+  antimoji tail app.log                            # Check a whole log once
+  antimoji tail -f /var/log/app.log                # Follow new lines
+  antimoji tail -f --fail-fast app.log             # Alert on the first finding
+  journalctl -f -o cat -u app | antimoji tail      # Check journald output`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			opts.ProfileName, _ = cmd.Root().PersistentFlags().GetString("profile")
+			opts.Overrides, _ = cmd.Root().PersistentFlags().GetStringArray(setFlag)
+			opts.StrictConfig, _ = cmd.Root().PersistentFlags().GetBool(strictConfigFlag)
+			return h.Execute(cmd.Context(), args, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.Follow, "follow", "f", false, "keep reading the file as it grows")
+	cmd.Flags().BoolVar(&opts.FromStart, "from-start", false, "with --follow, check the lines already in the file too")
+	cmd.Flags().BoolVar(&opts.FailFast, "fail-fast", false, "exit non-zero at the first finding")
+	cmd.Flags().DurationVar(&opts.PollInterval, "poll-interval", logtail.DefaultPollInterval, "how often a followed file is checked for new lines")
+	cmd.Flags().StringVar(&opts.Format, "format", DefaultLintFormat, "Go template for each finding")
+	addAllowlistFlags(cmd.Flags(), &opts.IgnoreAllowlist)
+
+	return cmd
+}
+
+// Execute reports the findings in the lines of the log named by args, or of the
+// input, until it ends or ctx is cancelled.
+func (h *TailHandler) Execute(parentCtx context.Context, args []string, opts *TailOptions) error {
+	format, err := template.New("finding").Parse(opts.Format + "\n")
+	if err == nil {
+		err = format.Execute(io.Discard, LintFinding{})
+	}
+	if err != nil {
+		return classify(ErrConfig, fmt.Errorf("invalid --format: %w", err))
+	}
+	path := "-"
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if opts.Follow && path == "-" {
+		return classify(ErrConfig, fmt.Errorf("--follow needs a file; standard input is read until it ends"))
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = ctxutil.WithOperation(ctx, "tail")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	engine, err := h.policy(ctx, opts)
+	if err != nil {
+		return err
+	}
+	patterns, err := engine.Patterns(ctx)
+	if err != nil {
+		return err
+	}
+	processingConfig := engine.ProcessingConfig()
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	errorsFound, lines := 0, 0
+	check := func(line logtail.Line) error {
+		lines++
+		detection := processor.DetectContent([]byte(line.Text), patterns, processingConfig)
+		if detection.IsErr() {
+			return nil
+		}
+		h.metrics.ObserveEmojis(detection.Unwrap().TotalCount)
+		for _, match := range engine.Violations(detection.Unwrap().Emojis) {
+			severity := engine.Severity(match)
+			message := fmt.Sprintf("emoji %s is not allowed", match.Display())
+			if severity == types.SeverityWarn {
+				message = fmt.Sprintf("emoji %s is discouraged", match.Display())
+			}
+			finding := LintFinding{
+				Path:     path,
+				Line:     line.Number,
+				Column:   match.Column,
+				Emoji:    match.Emoji,
+				Category: match.Category,
+				Rule:     string(match.Category),
+				Severity: severity,
+				Message:  message,
+			}
+			if err := format.Execute(out, finding); err != nil {
+				return classify(ErrIO, fmt.Errorf("failed to write finding: %w", err))
+			}
+			if severity == types.SeverityWarn {
+				continue
+			}
+			errorsFound++
+			if opts.FailFast {
+				return errFailFast
+			}
+		}
+		return nil
+	}
+
+	h.logger.Info(ctx, "Tailing log", "path", path, "follow", opts.Follow)
+	if path == "-" {
+		in := h.in
+		if in == nil {
+			in = os.Stdin
+		}
+		err = logtail.Read(ctx, in, check)
+	} else {
+		err = logtail.File(ctx, path, logtail.Options{
+			Follow:       opts.Follow,
+			FromStart:    opts.FromStart,
+			PollInterval: opts.PollInterval,
+		}, check)
+	}
+	h.logger.Info(ctx, "Tailing ended", "path", path, "lines", lines, "findings", errorsFound)
+
+	switch {
+	case errors.Is(err, errFailFast):
+		return classify(ErrViolations, fmt.Errorf("%s: %w", path, errFailFast))
+	case errors.Is(err, ErrIO):
+		return err
+	case err != nil:
+		return classify(ErrIO, fmt.Errorf("failed to read %s: %w", path, err))
+	case errorsFound > 0:
+		return classify(ErrViolations, fmt.Errorf("%d emojis found in %s (%d lines checked)", errorsFound, path, lines))
+	}
+	return nil
+}
+
+// policy resolves the profile of the tail command and the policy engine applying it.
+func (h *TailHandler) policy(ctx context.Context, opts *TailOptions) (*policy.Engine, error) {
+	cfg := config.DefaultConfig()
+	if opts.ConfigFile != "" {
+		configResult := loadConfigFile(opts.ConfigFile, opts.StrictConfig)
+		if configResult.IsErr() {
+			return nil, classify(ErrConfig, fmt.Errorf("failed to load config: %w", configResult.Error()))
+		}
+		cfg = configResult.Unwrap()
+		showConfigWarnings(ctx, h.ui, cfg)
+	}
+	profileResult := config.GetProfile(cfg, opts.ProfileName)
+	if profileResult.IsErr() {
+		return nil, classify(ErrConfig, fmt.Errorf("failed to get profile '%s': %w", opts.ProfileName, profileResult.Error()))
+	}
+	resolution, err := resolveProfile(profileResult.Unwrap(), opts.ConfigFile != "", opts.Overrides)
+	if err != nil {
+		return nil, err
+	}
+	return policy.New(ctx, resolution.Profile, policy.Options{
+		Operation:       "tail",
+		IgnoreAllowlist: opts.IgnoreAllowlist,
+		Threshold:       policy.NoThreshold,
+	})
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailHandler(t *testing.T) {
+	tail := func(input string, args []string, opts *TailOptions) (string, error) {
+		var out bytes.Buffer
+		handler := NewTailHandler(logging.NewMockLogger(), quietOutput()).
+			WithInput(strings.NewReader(input)).
+			WithOutput(&out)
+		if opts.Format == "" {
+			opts.Format = DefaultLintFormat
+		}
+		err := handler.Execute(context.Background(), args, opts)
+		return out.String(), err
+	}
+
+	t.Run("reports findings in standard input", func(t *testing.T) {
+		out, err := tail("started\nready 🚀\nok\n", nil, &TailOptions{})
+		assert.ErrorIs(t, err, ErrViolations)
+		assert.Contains(t, err.Error(), "1 emojis found in - (3 lines checked)")
+		assert.Equal(t, "-:2:7: emoji 🚀 is not allowed [unicode]\n", out)
+	})
+
+	t.Run("passes clean logs", func(t *testing.T) {
+		out, err := tail("started\nready\n", nil, &TailOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("reads a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("one 🎉\ntwo\nthree 🔥"), 0644))
+		out, err := tail("", []string{path}, &TailOptions{Format: "{{.Line}} {{.Emoji}}"})
+		assert.ErrorIs(t, err, ErrViolations)
+		assert.Equal(t, "1 🎉\n3 🔥\n", out)
+	})
+
+	t.Run("fails fast", func(t *testing.T) {
+		out, err := tail("a 🎉\nb 🔥\n", nil, &TailOptions{FailFast: true, Format: "{{.Emoji}}"})
+		assert.ErrorIs(t, err, ErrViolations)
+		assert.Equal(t, "🎉\n", out)
+	})
+
+	t.Run("rejects invalid usage", func(t *testing.T) {
+		_, err := tail("", nil, &TailOptions{Follow: true})
+		assert.ErrorIs(t, err, ErrConfig)
+		_, err = tail("", nil, &TailOptions{Format: "{{.Nope}}"})
+		assert.ErrorIs(t, err, ErrConfig)
+		_, err = tail("", []string{filepath.Join(t.TempDir(), "missing.log")}, &TailOptions{})
+		assert.ErrorIs(t, err, ErrIO)
+	})
+
+	t.Run("follows a file until the first finding", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("old 🎉\n"), 0644))
+		var out bytes.Buffer
+		handler := NewTailHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- handler.Execute(context.Background(), []string{path}, &TailOptions{
+				Follow: true, FailFast: true, FromStart: true,
+				PollInterval: 5 * time.Millisecond, Format: "{{.Line}} {{.Emoji}}",
+			})
+		}()
+
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, ErrViolations)
+		case <-time.After(5 * time.Second):
+			t.Fatal("tail did not stop at the finding")
+		}
+		assert.Equal(t, "1 🎉\n", out.String())
+	})
+
+	t.Run("ends following with the context", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("clean\n"), 0644))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		handler := NewTailHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&bytes.Buffer{})
+		err := handler.Execute(ctx, []string{path}, &TailOptions{
+			Follow: true, PollInterval: 5 * time.Millisecond, Format: DefaultLintFormat,
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestTailHandler_Metrics(t *testing.T) {
+	m := metrics.New()
+	handler := NewTailHandler(logging.NewMockLogger(), quietOutput()).WithMetrics(m).
+		WithInput(strings.NewReader("ready 🚀\nok 🎉 ✅\n")).
+		WithOutput(&bytes.Buffer{})
+	_ = handler.Execute(context.Background(), nil, &TailOptions{Format: DefaultLintFormat})
+
+	var out bytes.Buffer
+	require.NoError(t, m.Registry().WriteText(&out))
+	assert.Contains(t, out.String(), "antimoji_emojis_found_total 3\n")
+}
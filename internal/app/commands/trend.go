@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/antimoji/antimoji/internal/infra/history"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// TrendOptions holds the options for the trend command.
+type TrendOptions struct {
+	HistoryFile string
+	Last        int
+	Format      string
+}
+
+// TrendHandler handles the trend command with dependency injection.
+type TrendHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewTrendHandler creates a new trend command handler.
+func NewTrendHandler(logger logging.Logger, ui ui.UserOutput) *TrendHandler {
+	return &TrendHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the trend (defaults to stdout).
+func (h *TrendHandler) WithOutput(out io.Writer) *TrendHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the trend cobra command.
+func (h *TrendHandler) CreateCommand() *cobra.Command {
+	opts := &TrendOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "trend [flags]",
+		Short: "Show how emoji usage changed across recorded scans",
+		Long: `Show how emoji usage changed across the scans recorded with 'antimoji scan --record'.
+
+Each recorded scan is listed with its commit, its emoji count, the change from the
+previous scan and its counts per detection category, followed by the overall change
+from the first listed scan to the last.
+
+Examples:
+  antimoji scan --record .            # Record a scan, e.g. on every merge to main
+  antimoji trend                      # Show every recorded scan
+  antimoji trend --last 10            # Show the ten most recent scans
+  antimoji trend --format json > trend.json`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return h.Execute(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.HistoryFile, "history-file", history.DefaultPath, "history file written by 'scan --record'")
+	cmd.Flags().IntVar(&opts.Last, "last", 0, "show only the N most recent scans (0 = all)")
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "output format (table, json)")
+
+	return cmd
+}
+
+// Execute runs the trend command logic with dependency injection.
+func (h *TrendHandler) Execute(parentCtx context.Context, opts *TrendOptions) error {
+	format := strings.ToLower(opts.Format)
+	switch format {
+	case "table", "json":
+		// ok
+	default:
+		return fmt.Errorf("unsupported format %q; supported: table, json", opts.Format)
+	}
+	if opts.Last < 0 {
+		return fmt.Errorf("--last must be non-negative")
+	}
+
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "trend")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	path := opts.HistoryFile
+	if path == "" {
+		path = history.DefaultPath
+	}
+	snapshots, err := history.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return classify(ErrIO, fmt.Errorf("no scan history at %s; record scans with 'antimoji scan --record'", path))
+	}
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to read scan history: %w", err))
+	}
+	if opts.Last > 0 && len(snapshots) > opts.Last {
+		snapshots = snapshots[len(snapshots)-opts.Last:]
+	}
+	h.logger.Info(ctx, "Scan history loaded", "history_file", path, "snapshots", len(snapshots))
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	trend := history.Compute(snapshots)
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(trend)
+	}
+
+	if len(snapshots) == 0 {
+		h.ui.Info(ctx, "No scans recorded in %s", path)
+		return nil
+	}
+	return writeTrendTable(out, trend, history.CategoryNames(snapshots))
+}
+
+// writeTrendTable prints the trend as a summary line and a table of recorded scans.
+func writeTrendTable(out io.Writer, trend history.Trend, categories []string) error {
+	first, last := trend.Points[0], trend.Points[len(trend.Points)-1]
+	summary := fmt.Sprintf("%d -> %d emojis over %d scans since %s (%s",
+		first.Emojis, last.Emojis, len(trend.Points), first.Time.Local().Format("2006-01-02"), signed(trend.Change))
+	if first.Emojis > 0 {
+		summary += fmt.Sprintf(", %+.1f%%", float64(trend.Change)*100/float64(first.Emojis))
+	}
+	_, _ = fmt.Fprintf(out, "%s)\n\n", summary)
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	header := []string{"DATE", "COMMIT", "EMOJIS", "CHANGE", "FILES"}
+	for _, category := range categories {
+		header = append(header, strings.ToUpper(category))
+	}
+	_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for i, point := range trend.Points {
+		commit := point.Commit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		if commit == "" {
+			commit = "-"
+		}
+		change := "-"
+		if i > 0 {
+			change = signed(point.Change)
+		}
+		row := []string{point.Time.Local().Format("2006-01-02 15:04"), commit,
+			fmt.Sprint(point.Emojis), change, fmt.Sprint(point.FilesWithEmojis)}
+		for _, category := range categories {
+			row = append(row, fmt.Sprint(point.Categories[category]))
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(trend.Points) > 1 && len(categories) > 0 {
+		changes := make([]string, 0, len(categories))
+		for _, category := range categories {
+			changes = append(changes, category+" "+signed(trend.Categories[category]))
+		}
+		_, _ = fmt.Fprintf(out, "\nChange by category: %s\n", strings.Join(changes, ", "))
+	}
+	return nil
+}
+
+// signed formats n with an explicit sign, leaving 0 unsigned.
+func signed(n int) string {
+	if n == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%+d", n)
+}
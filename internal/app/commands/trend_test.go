@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/history"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrendHandler_Execute(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+	for _, snapshot := range []history.Snapshot{
+		{Time: day(1), Commit: "0123456789abcdef", Emojis: 40, FilesWithEmojis: 10, Categories: map[string]int{"unicode": 30, "emoticon": 10}},
+		{Time: day(8), Commit: "fedcba9876543210", Emojis: 30, FilesWithEmojis: 8, Categories: map[string]int{"unicode": 25, "emoticon": 5}},
+		{Time: day(15), Emojis: 10, FilesWithEmojis: 3, Categories: map[string]int{"unicode": 10}},
+	} {
+		require.NoError(t, history.Append(historyFile, snapshot))
+	}
+
+	run := func(t *testing.T, opts *TrendOptions) (string, error) {
+		var out bytes.Buffer
+		handler := NewTrendHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&out)
+		err := handler.Execute(context.Background(), opts)
+		return out.String(), err
+	}
+
+	t.Run("table", func(t *testing.T) {
+		out, err := run(t, &TrendOptions{HistoryFile: historyFile, Format: "table"})
+		require.NoError(t, err)
+		assert.Contains(t, out, "40 -> 10 emojis over 3 scans")
+		assert.Contains(t, out, "(-30, -75.0%)")
+		assert.Contains(t, out, "EMOTICON")
+		assert.Contains(t, out, "01234567")
+		assert.Contains(t, out, "-20")
+		assert.Contains(t, out, "Change by category: emoticon -10, unicode -20")
+	})
+
+	t.Run("last", func(t *testing.T) {
+		out, err := run(t, &TrendOptions{HistoryFile: historyFile, Format: "table", Last: 2})
+		require.NoError(t, err)
+		assert.Contains(t, out, "30 -> 10 emojis over 2 scans")
+		assert.NotContains(t, out, "01234567")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := run(t, &TrendOptions{HistoryFile: historyFile, Format: "json"})
+		require.NoError(t, err)
+		var trend history.Trend
+		require.NoError(t, json.Unmarshal([]byte(out), &trend))
+		require.Len(t, trend.Points, 3)
+		assert.Equal(t, -10, trend.Points[1].Change)
+		assert.Equal(t, -30, trend.Change)
+	})
+
+	t.Run("missing history", func(t *testing.T) {
+		_, err := run(t, &TrendOptions{HistoryFile: filepath.Join(t.TempDir(), "none.jsonl"), Format: "table"})
+		assert.ErrorIs(t, err, ErrIO)
+		assert.ErrorContains(t, err, "antimoji scan --record")
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		_, err := run(t, &TrendOptions{HistoryFile: historyFile, Format: "csv"})
+		assert.ErrorContains(t, err, "unsupported format")
+	})
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/undo"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// UndoOptions holds the options for the undo command.
+type UndoOptions struct {
+	Dir   string
+	Force bool
+}
+
+// UndoHandler handles the undo command with dependency injection.
+type UndoHandler struct {
+	logger logging.Logger
+	ui     ui.UserOutput
+	out    io.Writer
+}
+
+// NewUndoHandler creates a new undo command handler.
+func NewUndoHandler(logger logging.Logger, ui ui.UserOutput) *UndoHandler {
+	return &UndoHandler{
+		logger: logger,
+		ui:     ui,
+	}
+}
+
+// WithOutput sets the writer used for the list of runs (defaults to stdout).
+func (h *UndoHandler) WithOutput(out io.Writer) *UndoHandler {
+	h.out = out
+	return h
+}
+
+// CreateCommand creates the undo cobra command.
+func (h *UndoHandler) CreateCommand() *cobra.Command {
+	opts := &UndoOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "undo [run-id]",
+		Short: "Revert every file of a clean run from its backups",
+		Long: `Revert a whole 'antimoji clean --backup' run, restoring every file it cleaned
+from its backup.
+
+Each clean run with --backup records which files it modified in
+.antimoji/undo/<run-id>.json and prints its run ID. Without a run ID, the recorded
+runs are listed.
+
+The files are only restored if all of them can be: a missing or changed backup
+restores none. Files edited since the run are not overwritten unless --force is
+given.
+
+Examples:
+  antimoji undo                          # List the recorded clean runs
+  antimoji undo 20260301-120000-4f2a     # Restore the files of a run
+  antimoji undo --force 20260301-120000-4f2a`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return h.Execute(cmd.Context(), args, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Dir, "undo-dir", undo.DefaultDir, "directory of the clean run manifests")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "restore files even if they changed since the clean run")
+
+	return cmd
+}
+
+// Execute runs the undo command logic with dependency injection.
+func (h *UndoHandler) Execute(parentCtx context.Context, args []string, opts *UndoOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "undo")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = undo.DefaultDir
+	}
+	if len(args) == 0 {
+		return h.listRuns(dir)
+	}
+
+	runID := args[0]
+	h.logger.Info(ctx, "Starting undo operation", "run_id", runID, "force", opts.Force)
+	manifest, err := undo.Undo(dir, runID, opts.Force, time.Now())
+	switch {
+	case errors.Is(err, undo.ErrRunNotFound), errors.Is(err, undo.ErrAlreadyUndone), errors.Is(err, undo.ErrModifiedSinceRun):
+		return classify(ErrConfig, err)
+	case err != nil && manifest.UndoneAt != nil:
+		return classify(ErrIO, err)
+	case err != nil:
+		h.logger.Error(ctx, "Undo failed", "run_id", runID, "error", err)
+		return classify(ErrIO, fmt.Errorf("undo failed, no file was restored: %w", err))
+	}
+
+	for _, file := range manifest.Files {
+		h.ui.Info(ctx, "Restored %s", file.Path)
+	}
+	h.logger.Info(ctx, "Undo completed", "run_id", runID, "files", len(manifest.Files))
+	h.ui.Success(ctx, "Restored %d files cleaned by run %s", len(manifest.Files), runID)
+	return nil
+}
+
+// listRuns prints the clean runs recorded in dir, most recent last.
+func (h *UndoHandler) listRuns(dir string) error {
+	manifests, err := undo.List(dir)
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to list clean runs: %w", err))
+	}
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+	if len(manifests) == 0 {
+		_, err := fmt.Fprintf(out, "No clean runs recorded in %s; 'antimoji clean --backup' records them\n", dir)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "RUN ID\tTIME\tFILES\tSTATUS")
+	for _, manifest := range manifests {
+		status := "can be undone"
+		if manifest.UndoneAt != nil {
+			status = "undone " + manifest.UndoneAt.Local().Format("2006-01-02 15:04")
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", manifest.RunID, manifest.Time.Local().Format("2006-01-02 15:04:05"), len(manifest.Files), status)
+	}
+	return tw.Flush()
+}
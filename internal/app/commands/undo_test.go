@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/undo"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndoHandler(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+	original := "// Launch 🚀 🎉\n"
+	target := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(target, []byte(original), 0644))
+	clean := func() {
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+		require.NoError(t, handler.Execute(context.Background(), []string{target}, &CleanOptions{InPlace: true, Backup: true}))
+	}
+	undoHandler := func(out *bytes.Buffer) *UndoHandler {
+		return NewUndoHandler(logging.NewMockLogger(), quietOutput()).WithOutput(out)
+	}
+
+	clean()
+	manifests, err := undo.List(undo.DefaultDir)
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	runID := manifests[0].RunID
+
+	t.Run("lists recorded runs", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, undoHandler(&out).Execute(context.Background(), nil, &UndoOptions{}))
+		assert.Contains(t, out.String(), "RUN ID")
+		assert.Contains(t, out.String(), runID)
+		assert.Contains(t, out.String(), "can be undone")
+	})
+
+	t.Run("refuses files edited since the run", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(target, []byte("// edited\n"), 0644))
+		err := undoHandler(&bytes.Buffer{}).Execute(context.Background(), []string{runID}, &UndoOptions{})
+		assert.ErrorIs(t, err, ErrConfig)
+		assert.ErrorIs(t, err, undo.ErrModifiedSinceRun)
+	})
+
+	t.Run("restores the run when forced", func(t *testing.T) {
+		require.NoError(t, undoHandler(&bytes.Buffer{}).Execute(context.Background(), []string{runID}, &UndoOptions{Force: true}))
+		content, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, original, string(content))
+	})
+
+	t.Run("refuses a run undone already", func(t *testing.T) {
+		err := undoHandler(&bytes.Buffer{}).Execute(context.Background(), []string{runID}, &UndoOptions{})
+		assert.ErrorIs(t, err, undo.ErrAlreadyUndone)
+
+		var out bytes.Buffer
+		require.NoError(t, undoHandler(&out).Execute(context.Background(), nil, &UndoOptions{}))
+		assert.Contains(t, out.String(), "undone ")
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		err := undoHandler(&bytes.Buffer{}).Execute(context.Background(), []string{"20200101-000000-0000"}, &UndoOptions{})
+		assert.ErrorIs(t, err, undo.ErrRunNotFound)
+	})
+}
+
+func TestUndoHandler_BackToBackRuns(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+	original := "// Launch 🚀\n"
+	target := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(target, []byte(original), 0644))
+	clean := func() {
+		handler := NewCleanHandler(logging.NewMockLogger(), quietOutput())
+		require.NoError(t, handler.Execute(context.Background(), []string{target}, &CleanOptions{InPlace: true, Backup: true}))
+	}
+
+	// Two runs within the same second must not share a backup
+	clean()
+	manifests, err := undo.List(undo.DefaultDir)
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	first := manifests[0]
+	require.NoError(t, os.WriteFile(target, []byte("// Land 🎉\n"), 0644))
+	clean()
+
+	manifests, err = undo.List(undo.DefaultDir)
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	backups, err := filepath.Glob(filepath.Join(dir, "main.backup.*.go"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+
+	err = NewUndoHandler(logging.NewMockLogger(), quietOutput()).WithOutput(&bytes.Buffer{}).
+		Execute(context.Background(), []string{first.RunID}, &UndoOptions{Force: true})
+	require.NoError(t, err)
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(content))
+}
@@ -0,0 +1,346 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/release"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// InstallMethod is how the running antimoji binary was installed, which decides how
+// it is upgraded.
+type InstallMethod string
+
+const (
+	// InstallMethodHomebrew is a binary installed by Homebrew, upgraded with brew.
+	InstallMethodHomebrew InstallMethod = "homebrew"
+	// InstallMethodGo is a binary built by go install, upgraded with go install.
+	InstallMethodGo InstallMethod = "go"
+	// InstallMethodManual is a release binary copied into place, which antimoji
+	// replaces itself.
+	InstallMethodManual InstallMethod = "manual"
+)
+
+// DetectInstallMethod returns how the binary at exe, with symlinks resolved, was
+// installed.
+func DetectInstallMethod(exe string) InstallMethod {
+	slashed := filepath.ToSlash(exe)
+	if strings.Contains(slashed, "/Cellar/") || strings.Contains(slashed, "/homebrew/") || strings.Contains(slashed, "/linuxbrew/") {
+		return InstallMethodHomebrew
+	}
+	dir := filepath.Dir(exe)
+	for _, goBin := range goBinDirs() {
+		if dir == filepath.Clean(goBin) {
+			return InstallMethodGo
+		}
+	}
+	return InstallMethodManual
+}
+
+// goBinDirs returns the directories go install may write binaries to.
+func goBinDirs() []string {
+	var dirs []string
+	if goBin := os.Getenv("GOBIN"); goBin != "" {
+		dirs = append(dirs, goBin)
+	}
+	goPath := os.Getenv("GOPATH")
+	if goPath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			goPath = filepath.Join(home, "go")
+		}
+	}
+	for _, path := range filepath.SplitList(goPath) {
+		dirs = append(dirs, filepath.Join(path, "bin"))
+	}
+	return dirs
+}
+
+// UpgradeOptions holds the options for the upgrade command.
+type UpgradeOptions struct {
+	// Version is the release to install instead of the latest one
+	Version string
+	// Channel overrides the upgrade.channel of the configuration file
+	Channel string
+	// ConfigFile holds the upgrade settings (default .antimoji.yaml when it exists)
+	ConfigFile string
+	// GitHubAPIURL overrides the upgrade.github_api_url of the configuration file
+	GitHubAPIURL string
+	// Check only reports whether a newer release exists
+	Check bool
+	// Insecure installs a release whose archive has no published checksum
+	Insecure bool
+}
+
+// UpgradeHandler handles the upgrade command with dependency injection.
+type UpgradeHandler struct {
+	logger  logging.Logger
+	ui      ui.UserOutput
+	out     io.Writer
+	version string
+	// client reads releases; when nil, one is built from the configuration
+	client *release.Client
+	// executable, goos and goarch describe the running binary; tests replace them
+	executable func() (string, error)
+	goos       string
+	goarch     string
+}
+
+// NewUpgradeHandler creates a new upgrade command handler.
+func NewUpgradeHandler(logger logging.Logger, ui ui.UserOutput) *UpgradeHandler {
+	return &UpgradeHandler{
+		logger:     logger,
+		ui:         ui,
+		executable: os.Executable,
+		goos:       runtime.GOOS,
+		goarch:     runtime.GOARCH,
+	}
+}
+
+// WithOutput sets the writer used for the upgrade report (defaults to stdout).
+func (h *UpgradeHandler) WithOutput(out io.Writer) *UpgradeHandler {
+	h.out = out
+	return h
+}
+
+// WithVersion sets the version of the running binary.
+func (h *UpgradeHandler) WithVersion(version string) *UpgradeHandler {
+	h.version = version
+	return h
+}
+
+// WithClient sets the client releases are read from.
+func (h *UpgradeHandler) WithClient(client *release.Client) *UpgradeHandler {
+	h.client = client
+	return h
+}
+
+// CreateCommand creates the upgrade cobra command.
+func (h *UpgradeHandler) CreateCommand() *cobra.Command {
+	opts := &UpgradeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade antimoji to the latest release",
+		Long: `Upgrade antimoji to the latest release, or to the release given with --version.
+
+The release installed comes from a channel, set with --channel or upgrade.channel
+in the configuration file: stable (the default) installs the newest release,
+prerelease also considers release candidates, and pinned stays within the
+version constraint upgrade.version (e.g. ~1.2 or ">=1.2.0, <2.0.0"), which also
+limits the other channels when set.
+
+Requests go through the proxies of HTTPS_PROXY and NO_PROXY and trust the
+certificate authorities of http.ca_file or $ANTIMOJI_CA_FILE besides the system
+ones. --github-api-url or upgrade.github_api_url reads the releases from a
+GitHub Enterprise mirror.
+
+Binaries installed by Homebrew or go install are upgraded with those tools, so
+for them the command prints what to run. A release binary that was copied into
+place is replaced directly: the archive for this platform is downloaded,
+verified against the checksums the release publishes and moved over the running
+binary. Releases without checksums are refused unless --insecure is given, which
+installs them unverified after a warning. An archive that does not match its
+published checksum is always refused. On Windows, when the binary cannot be
+replaced while it runs, the replacement happens on the next reboot.
+
+Examples:
+  antimoji upgrade                    # Install the latest release
+  antimoji upgrade --check            # Only report whether a newer release exists
+  antimoji upgrade --channel prerelease  # Install the newest release candidate
+  antimoji upgrade --version v1.2.3   # Install a specific release`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ConfigFile, _ = cmd.Root().PersistentFlags().GetString("config")
+			return h.Execute(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Version, "version", "", "release to install (e.g. v1.2.3; default the newest on the channel)")
+	cmd.Flags().StringVar(&opts.Channel, "channel", "", "release channel: stable, prerelease or pinned (default upgrade.channel or stable)")
+	cmd.Flags().BoolVar(&opts.Check, "check", false, "only report whether a newer release exists")
+	cmd.Flags().BoolVar(&opts.Insecure, "insecure", false, "install a release that publishes no checksum for its archive, without verifying it")
+	cmd.Flags().StringVar(&opts.GitHubAPIURL, "github-api-url", "", "GitHub API to read releases from (default upgrade.github_api_url or "+release.DefaultAPIURL+")")
+
+	return cmd
+}
+
+// Execute finds the release to install and upgrades the running binary to it.
+func (h *UpgradeHandler) Execute(parentCtx context.Context, opts *UpgradeOptions) error {
+	ctx := parentCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = ctxutil.WithOperation(ctx, "upgrade")
+	ctx = ctxutil.WithComponent(ctx, "cli")
+
+	out := h.out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	cfg, err := upgradeConfig(opts.ConfigFile)
+	if err != nil {
+		return err
+	}
+	client, err := h.releaseClient(cfg, opts.GitHubAPIURL)
+	if err != nil {
+		return err
+	}
+
+	var target release.Release
+	if opts.Version != "" {
+		if releaseVersion(opts.Version) == "" {
+			return classify(ErrConfig, fmt.Errorf("invalid --version %q: must be a release version such as v1.2.3", opts.Version))
+		}
+		target, err = client.ByTag(ctx, "v"+strings.TrimPrefix(opts.Version, "v"))
+	} else {
+		channel, constraint, settingsErr := upgradeSettings(cfg.Upgrade, opts.Channel)
+		if settingsErr != nil {
+			return settingsErr
+		}
+		h.logger.Info(ctx, "Selecting release", "channel", channel, "constraint", constraint.String())
+		target, err = client.Find(ctx, channel, constraint)
+	}
+	if errors.Is(err, release.ErrNotFound) {
+		return classify(ErrConfig, err)
+	}
+	if err != nil {
+		return classify(ErrIO, err)
+	}
+	h.logger.Info(ctx, "Found release", "release", target.Tag, "current", h.version)
+
+	if opts.Version == "" && release.CompareVersions(target.Tag, h.version) <= 0 {
+		_, err := fmt.Fprintf(out, "antimoji %s is up to date (latest release %s)\n", h.version, target.Tag)
+		return classify(ErrIO, err)
+	}
+	if opts.Check {
+		_, err := fmt.Fprintf(out, "antimoji %s is available (this is %s); run 'antimoji upgrade' to install it\n", target.Tag, h.version)
+		return classify(ErrIO, err)
+	}
+
+	exe, err := h.executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		return classify(ErrIO, fmt.Errorf("failed to locate the antimoji binary: %w", err))
+	}
+
+	switch DetectInstallMethod(exe) {
+	case InstallMethodHomebrew:
+		_, err := fmt.Fprintf(out, "antimoji was installed by Homebrew; upgrade it to %s with 'brew upgrade antimoji'\n", target.Tag)
+		return classify(ErrIO, err)
+	case InstallMethodGo:
+		_, err := fmt.Fprintf(out, "antimoji was installed by go install; upgrade it to %s with 'go install github.com/%s/cmd/antimoji@%s'\n",
+			target.Tag, release.Repository, target.Tag)
+		return classify(ErrIO, err)
+	}
+	return h.replace(ctx, out, client, target, exe, opts.Insecure)
+}
+
+// upgradeConfig loads the configuration file holding the upgrade settings, which is
+// .antimoji.yaml when configFile is "" and that file exists.
+func upgradeConfig(configFile string) (config.Config, error) {
+	if configFile == "" {
+		if _, err := os.Stat(defaultConfigFile); err != nil {
+			return config.Config{}, nil
+		}
+		configFile = defaultConfigFile
+	}
+	loaded := loadConfigFile(configFile, false)
+	if loaded.IsErr() {
+		return config.Config{}, loaded.Error()
+	}
+	return loaded.Unwrap(), nil
+}
+
+// releaseClient returns the client releases are read from: the one set with
+// WithClient, or one for the configured API trusting the configured CA file.
+func (h *UpgradeHandler) releaseClient(cfg config.Config, apiURL string) (*release.Client, error) {
+	if h.client != nil {
+		return h.client, nil
+	}
+	client, err := release.NewConfiguredClient(cfg.HTTP.CAFile, cfg.Upgrade.GitHubAPIURL)
+	if err != nil {
+		return nil, classify(ErrConfig, err)
+	}
+	if apiURL != "" {
+		client.WithAPIURL(apiURL)
+	}
+	return client, nil
+}
+
+// upgradeSettings returns the channel and version constraint of the upgrade: the
+// upgrade section of the configuration file, with the channel overridden by --channel.
+func upgradeSettings(settings config.UpgradeConfig, channelFlag string) (release.Channel, release.Constraint, error) {
+	if channelFlag != "" {
+		settings.Channel = channelFlag
+	}
+
+	channel, err := release.ParseChannel(settings.Channel)
+	if err != nil {
+		return "", release.Constraint{}, classify(ErrConfig, err)
+	}
+	constraint, err := release.ParseConstraint(settings.Version)
+	if err != nil {
+		return "", release.Constraint{}, classify(ErrConfig, err)
+	}
+	if channel == release.ChannelPinned && constraint.IsZero() {
+		return "", release.Constraint{}, classify(ErrConfig, fmt.Errorf("the pinned channel needs upgrade.version in the configuration file, e.g. ~1.2"))
+	}
+	return channel, constraint, nil
+}
+
+// replace replaces the binary at exe, which was installed manually, with the binary
+// of target. With insecure, a binary whose archive has no published checksum is
+// installed after a warning.
+func (h *UpgradeHandler) replace(ctx context.Context, out io.Writer, client *release.Client, target release.Release, exe string, insecure bool) error {
+	var newBinary string
+	verified := true
+	var err error
+	if insecure {
+		newBinary, verified, err = client.DownloadUnverifiedBinary(ctx, target, h.goos, h.goarch, filepath.Dir(exe))
+	} else {
+		newBinary, err = client.DownloadBinary(ctx, target, h.goos, h.goarch, filepath.Dir(exe))
+	}
+	if errors.Is(err, release.ErrUnverified) || errors.Is(err, release.ErrNoChecksum) {
+		return classify(ErrConfig, fmt.Errorf("%w; pass --insecure to install it unverified", err))
+	}
+	if errors.Is(err, release.ErrChecksumMismatch) {
+		return classify(ErrConfig, err)
+	}
+	if err != nil {
+		return classify(ErrIO, err)
+	}
+	if !verified {
+		h.logger.Warn(ctx, "Installing unverified release", "release", target.Tag)
+		h.ui.Warning(ctx, "WARNING: %s publishes no checksum for its archive; installing it UNVERIFIED because of --insecure", target.Tag)
+	}
+
+	deferred, err := release.Replace(newBinary, exe)
+	if err != nil {
+		_ = os.Remove(newBinary)
+		return classify(ErrIO, fmt.Errorf("failed to replace %s: %w", exe, err))
+	}
+	h.logger.Info(ctx, "Upgraded binary", "path", exe, "release", target.Tag, "deferred", deferred)
+
+	if deferred {
+		_, err = fmt.Fprintf(out, "antimoji %s will replace %s on the next reboot\n", target.Tag, exe)
+	} else {
+		_, err = fmt.Fprintf(out, "Upgraded antimoji %s to %s (%s)\n", h.version, target.Tag, exe)
+	}
+	return classify(ErrIO, err)
+}
@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/release"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseAPI serves release v1.3.0 for linux/amd64, with its checksums unless
+// unverified is set, and the prerelease v1.4.0-rc.1.
+func releaseAPI(t *testing.T, unverified bool) (*release.Client, string) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "antimoji", Mode: 0755, Size: 10, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("new binary"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	sum := sha256.Sum256(buf.Bytes())
+
+	var server *httptest.Server
+	releaseJSON := func() string {
+		assets := `{"name": "antimoji_1.3.0_linux_amd64.tar.gz", "browser_download_url": "` + server.URL + `/archive"}`
+		if !unverified {
+			assets += `, {"name": "antimoji_1.3.0_checksums.txt", "browser_download_url": "` + server.URL + `/checksums"}`
+		}
+		return `{"tag_name": "v1.3.0", "assets": [` + assets + `]}`
+	}
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/" + release.Repository + "/releases":
+			_, _ = w.Write([]byte(`[{"tag_name": "v1.4.0-rc.1", "prerelease": true}, ` + releaseJSON() + `]`))
+		case "/repos/" + release.Repository + "/releases/tags/v1.3.0":
+			_, _ = w.Write([]byte(releaseJSON()))
+		case "/archive":
+			_, _ = w.Write(buf.Bytes())
+		case "/checksums":
+			_, _ = w.Write([]byte(hex.EncodeToString(sum[:]) + "  antimoji_1.3.0_linux_amd64.tar.gz\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return release.NewClient().WithClient(server.Client()).WithAPIURL(server.URL), server.URL
+}
+
+func TestUpgradeHandler_Execute(t *testing.T) {
+	newHandler := func(t *testing.T, version, exe string, unverified bool) (*UpgradeHandler, *bytes.Buffer) {
+		var out bytes.Buffer
+		client, _ := releaseAPI(t, unverified)
+		h := NewUpgradeHandler(logging.NewMockLogger(), quietOutput()).
+			WithVersion(version).WithOutput(&out).WithClient(client)
+		h.executable = func() (string, error) { return exe, nil }
+		h.goos, h.goarch = "linux", "amd64"
+		return h, &out
+	}
+	installed := func(t *testing.T) string {
+		exe := filepath.Join(t.TempDir(), "antimoji")
+		require.NoError(t, os.WriteFile(exe, []byte("old binary"), 0755))
+		return exe
+	}
+
+	t.Run("replaces a manually installed binary", func(t *testing.T) {
+		exe := installed(t)
+		h, out := newHandler(t, "1.2.0", exe, false)
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{}))
+
+		content, err := os.ReadFile(exe)
+		require.NoError(t, err)
+		assert.Equal(t, "new binary", string(content))
+		assert.Contains(t, out.String(), "Upgraded antimoji 1.2.0 to v1.3.0")
+	})
+
+	t.Run("refuses releases without checksums", func(t *testing.T) {
+		exe := installed(t)
+		h, _ := newHandler(t, "1.2.0", exe, true)
+		err := h.Execute(context.Background(), &UpgradeOptions{})
+		assert.ErrorIs(t, err, release.ErrUnverified)
+		assert.ErrorIs(t, err, ErrConfig)
+		assert.ErrorContains(t, err, "--insecure")
+
+		content, err := os.ReadFile(exe)
+		require.NoError(t, err)
+		assert.Equal(t, "old binary", string(content))
+	})
+
+	t.Run("installs releases without checksums with --insecure", func(t *testing.T) {
+		exe := installed(t)
+		h, out := newHandler(t, "1.2.0", exe, true)
+		var stderr bytes.Buffer
+		h.ui = ui.NewUserOutput(&ui.Config{Level: ui.OutputNormal, Writer: &bytes.Buffer{}, ErrorWriter: &stderr})
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{Insecure: true}))
+
+		content, err := os.ReadFile(exe)
+		require.NoError(t, err)
+		assert.Equal(t, "new binary", string(content))
+		assert.Contains(t, stderr.String(), "installing it UNVERIFIED")
+		assert.Contains(t, out.String(), "Upgraded antimoji 1.2.0 to v1.3.0")
+	})
+
+	t.Run("reports up to date and available releases", func(t *testing.T) {
+		exe := installed(t)
+		h, out := newHandler(t, "1.3.0", exe, false)
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{}))
+		assert.Contains(t, out.String(), "antimoji 1.3.0 is up to date")
+
+		h, out = newHandler(t, "1.2.0", exe, false)
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{Check: true}))
+		assert.Contains(t, out.String(), "antimoji v1.3.0 is available")
+		content, err := os.ReadFile(exe)
+		require.NoError(t, err)
+		assert.Equal(t, "old binary", string(content), "--check changes nothing")
+	})
+
+	t.Run("selects releases by channel and constraint", func(t *testing.T) {
+		exe := installed(t)
+		h, out := newHandler(t, "1.2.0", exe, false)
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{Channel: "prerelease", Check: true}))
+		assert.Contains(t, out.String(), "antimoji v1.4.0-rc.1 is available")
+
+		configFile := filepath.Join(t.TempDir(), ".antimoji.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("upgrade:\n  channel: prerelease\n  version: ~1.3.0\nprofiles:\n  default: {}\n"), 0600))
+		h, out = newHandler(t, "1.2.0", exe, false)
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{ConfigFile: configFile, Check: true}))
+		assert.Contains(t, out.String(), "antimoji v1.3.0 is available")
+
+		err := h.Execute(context.Background(), &UpgradeOptions{Channel: "pinned"})
+		assert.ErrorContains(t, err, "the pinned channel needs upgrade.version")
+		assert.ErrorIs(t, err, ErrConfig)
+		assert.ErrorContains(t, h.Execute(context.Background(), &UpgradeOptions{Channel: "nightly"}), "invalid upgrade channel")
+	})
+
+	t.Run("reads releases from the configured API", func(t *testing.T) {
+		_, apiURL := releaseAPI(t, false)
+		var out bytes.Buffer
+		h := NewUpgradeHandler(logging.NewMockLogger(), quietOutput()).WithVersion("1.2.0").WithOutput(&out)
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{GitHubAPIURL: apiURL, Check: true}))
+		assert.Contains(t, out.String(), "antimoji v1.3.0 is available")
+
+		configFile := filepath.Join(t.TempDir(), ".antimoji.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("http:\n  ca_file: missing.pem\nprofiles:\n  default: {}\n"), 0600))
+		err := h.Execute(context.Background(), &UpgradeOptions{ConfigFile: configFile, GitHubAPIURL: apiURL, Check: true})
+		assert.ErrorContains(t, err, "failed to read CA file")
+		assert.ErrorIs(t, err, ErrConfig)
+	})
+
+	t.Run("installs a given release", func(t *testing.T) {
+		exe := installed(t)
+		h, _ := newHandler(t, "1.4.0", exe, false)
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{Version: "1.3.0"}))
+
+		err := h.Execute(context.Background(), &UpgradeOptions{Version: "v9.9.9"})
+		assert.ErrorIs(t, err, release.ErrNotFound)
+		assert.ErrorContains(t, h.Execute(context.Background(), &UpgradeOptions{Version: "main"}), "invalid --version")
+	})
+
+	t.Run("leaves package managers to upgrade their binaries", func(t *testing.T) {
+		exe := filepath.Join(t.TempDir(), "Cellar", "antimoji", "1.2.0", "bin", "antimoji")
+		require.NoError(t, os.MkdirAll(filepath.Dir(exe), 0755))
+		require.NoError(t, os.WriteFile(exe, []byte("old binary"), 0755))
+		h, out := newHandler(t, "1.2.0", exe, false)
+		require.NoError(t, h.Execute(context.Background(), &UpgradeOptions{}))
+		assert.Contains(t, out.String(), "brew upgrade antimoji")
+	})
+}
+
+func TestDetectInstallMethod(t *testing.T) {
+	goPath := t.TempDir()
+	t.Setenv("GOBIN", "")
+	t.Setenv("GOPATH", goPath)
+
+	assert.Equal(t, InstallMethodHomebrew, DetectInstallMethod("/usr/local/Cellar/antimoji/1.2.0/bin/antimoji"))
+	assert.Equal(t, InstallMethodHomebrew, DetectInstallMethod("/home/linuxbrew/.linuxbrew/bin/antimoji"))
+	assert.Equal(t, InstallMethodGo, DetectInstallMethod(filepath.Join(goPath, "bin", "antimoji")))
+	assert.Equal(t, InstallMethodManual, DetectInstallMethod("/usr/local/bin/antimoji"))
+}
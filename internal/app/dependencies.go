@@ -8,13 +8,15 @@ import (
 	"os"
 
 	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/observability/metrics"
 	"github.com/antimoji/antimoji/internal/ui"
 )
 
 // Dependencies holds all application dependencies.
 type Dependencies struct {
-	Logger logging.Logger
-	UI     ui.UserOutput
+	Logger  logging.Logger
+	UI      ui.UserOutput
+	Metrics *metrics.Metrics
 }
 
 // Config holds configuration for creating dependencies.
@@ -83,16 +85,18 @@ func NewDependencies(config *Config) (*Dependencies, error) {
 	userOutput := ui.NewUserOutput(uiConfig)
 
 	return &Dependencies{
-		Logger: logger,
-		UI:     userOutput,
+		Logger:  logger,
+		UI:      userOutput,
+		Metrics: metrics.New(),
 	}, nil
 }
 
 // NewTestDependencies creates dependencies suitable for testing.
 func NewTestDependencies() *Dependencies {
 	return &Dependencies{
-		Logger: logging.NewMockLogger(),
-		UI:     ui.NewUserOutput(ui.DefaultConfig()),
+		Logger:  logging.NewMockLogger(),
+		UI:      ui.NewUserOutput(ui.DefaultConfig()),
+		Metrics: metrics.New(),
 	}
 }
 
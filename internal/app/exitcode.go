@@ -0,0 +1,36 @@
+package app
+
+import (
+	"github.com/antimoji/antimoji/internal/app/commands"
+)
+
+// Exit codes of the antimoji command. Scripts can tell violations (ExitViolations)
+// from antimoji being unable to do its job (the others).
+const (
+	// ExitOK means the command succeeded and found no violations.
+	ExitOK = 0
+	// ExitViolations means emojis the policy does not allow were found.
+	ExitViolations = 1
+	// ExitConfigError means the configuration or the command line is invalid.
+	ExitConfigError = 2
+	// ExitIOError means files or other inputs and outputs could not be read or written.
+	ExitIOError = 3
+	// ExitPartialFailure means some files were processed and others failed.
+	ExitPartialFailure = 4
+)
+
+// ExitCode returns the exit code for the error a command returned.
+func ExitCode(err error) int {
+	switch commands.Classify(err) {
+	case nil:
+		return ExitOK
+	case commands.ErrViolations:
+		return ExitViolations
+	case commands.ErrIO:
+		return ExitIOError
+	case commands.ErrPartialFailure:
+		return ExitPartialFailure
+	default:
+		return ExitConfigError
+	}
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/app/commands"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"violations", fmt.Errorf("command execution failed: %w", commands.ErrEmojiThresholdExceeded), ExitViolations},
+		{"config", errors.New("unknown flag: --bogus"), ExitConfigError},
+		{"io", fmt.Errorf("wrapped: %w", commands.ErrIO), ExitIOError},
+		{"partial failure", fmt.Errorf("wrapped: %w", commands.ErrPartialFailure), ExitPartialFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
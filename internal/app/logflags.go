@@ -0,0 +1,53 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/spf13/pflag"
+)
+
+// ApplyLogFlags sets the log level and format of config from the --log-level and
+// --log-format flags in args, and from the deprecated --quiet and --verbose flags. The
+// logger is injected into the commands before cobra parses the command line, so these
+// flags are read ahead of it; all other arguments are ignored.
+func ApplyLogFlags(config *Config, args []string) error {
+	flags := pflag.NewFlagSet("logging", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	flags.Usage = func() {}
+
+	level := flags.String("log-level", string(config.LogLevel), "")
+	format := flags.String("log-format", string(config.LogFormat), "")
+	quiet := flags.BoolP("quiet", "q", false, "")
+	verbose := flags.BoolP("verbose", "v", false, "")
+	if err := flags.Parse(args); err != nil && !errors.Is(err, pflag.ErrHelp) {
+		return err
+	}
+
+	switch logging.LogLevel(*level) {
+	case logging.LevelSilent, logging.LevelDebug, logging.LevelInfo, logging.LevelWarn, logging.LevelError:
+		config.LogLevel = logging.LogLevel(*level)
+	default:
+		return fmt.Errorf("invalid --log-level %q; supported: silent, debug, info, warn, error", *level)
+	}
+	switch logging.LogFormat(*format) {
+	case logging.FormatJSON, logging.FormatText:
+		config.LogFormat = logging.LogFormat(*format)
+	default:
+		return fmt.Errorf("invalid --log-format %q; supported: json, text", *format)
+	}
+
+	// The deprecated flags apply when --log-level is not given
+	if !flags.Changed("log-level") {
+		switch {
+		case *quiet:
+			config.LogLevel = logging.LevelSilent
+		case *verbose:
+			config.LogLevel = logging.LevelInfo
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyLogFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		level  logging.LogLevel
+		format logging.LogFormat
+	}{
+		{"defaults", []string{"scan", "."}, logging.LevelSilent, logging.FormatJSON},
+		{"level and format", []string{"scan", "--log-level=debug", "--log-format", "text", "."}, logging.LevelDebug, logging.FormatText},
+		{"other flags are ignored", []string{"clean", "--in-place", "--config", "x.yaml", "--log-level", "warn"}, logging.LevelWarn, logging.FormatJSON},
+		{"verbose", []string{"scan", "-v"}, logging.LevelInfo, logging.FormatJSON},
+		{"log level wins over verbose", []string{"scan", "-v", "--log-level=error"}, logging.LevelError, logging.FormatJSON},
+		{"quiet", []string{"scan", "--quiet", "--verbose"}, logging.LevelSilent, logging.FormatJSON},
+		{"help", []string{"scan", "--help"}, logging.LevelSilent, logging.FormatJSON},
+		{"after terminator", []string{"scan", "--", "--log-level=debug"}, logging.LevelSilent, logging.FormatJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{LogLevel: logging.LevelSilent, LogFormat: logging.FormatJSON}
+			require.NoError(t, ApplyLogFlags(config, tt.args))
+			assert.Equal(t, tt.level, config.LogLevel)
+			assert.Equal(t, tt.format, config.LogFormat)
+		})
+	}
+}
+
+func TestApplyLogFlags_Invalid(t *testing.T) {
+	config := &Config{LogLevel: logging.LevelSilent, LogFormat: logging.FormatJSON}
+	assert.ErrorContains(t, ApplyLogFlags(config, []string{"--log-level=loud"}), "invalid --log-level")
+	assert.ErrorContains(t, ApplyLogFlags(config, []string{"--log-format=xml"}), "invalid --log-format")
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/infra/release"
+	"github.com/antimoji/antimoji/internal/infra/remoteconfig"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// updateCheckTimeout bounds the release lookup of the update check.
+	updateCheckTimeout = 5 * time.Second
+
+	// noticeWait is how long a finished command waits for a running update check
+	// before exiting without a notice.
+	noticeWait = 500 * time.Millisecond
+)
+
+// noUpdateCheckCommands are the commands that never check for updates: upgrade does
+// so itself and the daemon does not finish.
+var noUpdateCheckCommands = map[string]bool{"upgrade": true, "daemon": true}
+
+// startUpdateCheck looks up the newest release in the background when the update
+// check is enabled, for printUpdateNotice to report once the command finished.
+func (a *Application) startUpdateCheck(cmd *cobra.Command) {
+	if noUpdateCheckCommands[cmd.Name()] {
+		return
+	}
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		return
+	}
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, enabled := updateCheckSettings(configPath)
+	if !enabled {
+		return
+	}
+	channel, err := release.ParseChannel(cfg.Upgrade.Channel)
+	if err != nil {
+		return
+	}
+	constraint, err := release.ParseConstraint(cfg.Upgrade.Version)
+	if err != nil {
+		return
+	}
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return
+	}
+	client := a.releases
+	if client == nil {
+		if client, err = release.NewConfiguredClient(cfg.HTTP.CAFile, cfg.Upgrade.GitHubAPIURL); err != nil {
+			a.deps.Logger.Debug(a.ctx, "Update check skipped", "error", err)
+			return
+		}
+	}
+
+	checker := release.NewUpdateChecker(client, filepath.Join(dir, release.UpdateCheckFile))
+	newest := make(chan string, 1)
+	a.updateNotice = newest
+	go func() {
+		ctx, cancel := context.WithTimeout(a.ctx, updateCheckTimeout)
+		defer cancel()
+		tag, err := checker.Newest(ctx, channel, constraint)
+		if err != nil {
+			a.deps.Logger.Debug(ctx, "Update check failed", "error", err)
+		}
+		newest <- tag
+	}()
+}
+
+// printUpdateNotice prints one line when the update check found a newer release.
+func (a *Application) printUpdateNotice() {
+	if a.updateNotice == nil {
+		return
+	}
+	defer func() { a.updateNotice = nil }()
+
+	var newest string
+	select {
+	case newest = <-a.updateNotice:
+	case <-time.After(noticeWait):
+		return
+	}
+	current := a.getBuildVersion()
+	if newest == "" || release.CompareVersions(newest, current) <= 0 {
+		return
+	}
+	out := a.noticeOut
+	if out == nil {
+		out = os.Stderr
+	}
+	// stderr keeps the notice out of reports written to stdout
+	_, _ = fmt.Fprintf(out, "antimoji %s is available (this is %s); run 'antimoji upgrade' to install it\n", newest, current)
+}
+
+// updateCheckSettings returns the local configuration file and whether the update
+// check is enabled: by $ANTIMOJI_UPDATE_CHECK when it is set, otherwise by
+// upgrade.check. It is always off in offline mode.
+func updateCheckSettings(configPath string) (config.Config, bool) {
+	if offline, _ := strconv.ParseBool(os.Getenv(remoteconfig.EnvOffline)); offline {
+		return config.Config{}, false
+	}
+
+	var cfg config.Config
+	if configPath == "" {
+		configPath = ".antimoji.yaml"
+	}
+	// Remote configurations are not fetched for the check, which must stay cheap
+	if !remoteconfig.IsRemote(configPath) {
+		if loaded := config.LoadConfig(configPath); loaded.IsOk() {
+			cfg = loaded.Unwrap()
+		}
+	}
+
+	if value, ok := os.LookupEnv(release.EnvUpdateCheck); ok {
+		enabled, err := strconv.ParseBool(value)
+		return cfg, err == nil && enabled
+	}
+	return cfg, cfg.Upgrade.Check
+}
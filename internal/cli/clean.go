@@ -10,6 +10,7 @@ import (
 	"github.com/antimoji/antimoji/internal/core/allowlist"
 	"github.com/antimoji/antimoji/internal/core/detector"
 	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
 	"github.com/antimoji/antimoji/internal/infra/filtering"
 	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
 	"github.com/antimoji/antimoji/internal/observability/logging"
@@ -58,7 +59,7 @@ Examples:
 	// Add clean-specific flags
 	cmd.Flags().BoolVarP(&opts.Recursive, "recursive", "r", true, "clean directories recursively")
 	cmd.Flags().BoolVar(&opts.Backup, "backup", false, "create backup files")
-	cmd.Flags().StringVar(&opts.Replace, "replace", "", "replacement text for emojis")
+	cmd.Flags().StringVar(&opts.Replace, "replace", "", "replacement text for emojis not in the profile replacement_map")
 	cmd.Flags().BoolVarP(&opts.InPlace, "in-place", "i", false, "modify files in place")
 	cmd.Flags().BoolVar(&opts.RespectAllowlist, "respect-allowlist", true, "respect configured emoji allowlist during cleaning (deprecated, use --ignore-allowlist)")
 	cmd.Flags().BoolVar(&opts.IgnoreAllowlist, "ignore-allowlist", false, "ignore configured emoji allowlist (overrides --respect-allowlist)")
@@ -156,12 +157,16 @@ func runClean(_ *cobra.Command, args []string, opts *CleanOptions) error {
 	// Create modification configuration
 	logging.Debug(ctx, "Creating modification configuration")
 	// Use the resolved allowlist behavior (ignore-allowlist takes precedence)
+	processing := config.ToProcessingConfig(profile)
 	modifyConfig := processor.ModifyConfig{
 		Replacement:         opts.Replace,
+		ReplacementMap:      profile.ReplacementMap,
 		CreateBackup:        opts.Backup,
 		RespectAllowlist:    shouldUseAllowlist,
 		PreservePermissions: true,
 		DryRun:              dryRun,
+		Sniff:               processing.Sniff,
+		Retry:               processing.Retry,
 	}
 	logging.Debug(ctx, "Modification configuration created",
 		"dry_run", modifyConfig.DryRun,
@@ -170,7 +175,12 @@ func runClean(_ *cobra.Command, args []string, opts *CleanOptions) error {
 
 	// Create emoji patterns
 	logging.Debug(ctx, "Creating emoji patterns")
-	patterns := detector.DefaultEmojiPatterns()
+	patterns, err := emojidata.PatternsForProfile(ctx, detector.DefaultEmojiPatterns(), profile)
+	if err != nil {
+		logging.Error(ctx, "Failed to load supplemental emoji data", "error", err)
+		ui.Error(ctx, "Failed to load emoji data: %v", err)
+		return fmt.Errorf("failed to load emoji data: %w", err)
+	}
 	logging.Debug(ctx, "Emoji patterns created", "unicode_ranges", len(patterns.UnicodeRanges))
 
 	// Modify files
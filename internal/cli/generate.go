@@ -13,6 +13,7 @@ import (
 	"github.com/antimoji/antimoji/internal/config"
 	"github.com/antimoji/antimoji/internal/core/detector"
 	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/analysis"
 	"github.com/antimoji/antimoji/internal/observability/logging"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -504,56 +505,7 @@ func generateFullAllowlist(analysis *EmojiUsageAnalysis, opts *GenerateOptions)
 
 // categorizeFile categorizes a file based on its path and extension.
 func categorizeFile(filePath string) string {
-	fileName := filepath.Base(filePath)
-	ext := filepath.Ext(fileName)
-	dir := filepath.Dir(filePath)
-
-	// Normalize path separators for cross-platform compatibility
-	normalizedPath := filepath.ToSlash(filePath)
-	normalizedDir := filepath.ToSlash(dir)
-
-	// Check for test files first (most specific)
-	if strings.Contains(fileName, "_test.") || strings.Contains(fileName, "test_") ||
-		strings.Contains(normalizedDir, "/test/") || strings.Contains(normalizedDir, "/tests/") ||
-		strings.Contains(normalizedDir, "/testdata/") || strings.Contains(normalizedDir, "/fixtures/") ||
-		strings.HasSuffix(normalizedDir, "/test") || strings.HasSuffix(normalizedDir, "/tests") ||
-		strings.HasSuffix(normalizedDir, "/testdata") || strings.HasSuffix(normalizedDir, "/fixtures") ||
-		strings.Contains(normalizedPath, "/test/") || strings.Contains(normalizedPath, "/tests/") {
-		return "test"
-	}
-
-	// Check for CI files
-	if strings.Contains(normalizedDir, ".github") || strings.Contains(normalizedDir, "scripts") ||
-		(ext == ".yml" || ext == ".yaml") && (strings.Contains(normalizedDir, ".github") || strings.Contains(fileName, "ci")) {
-		return "ci"
-	}
-
-	// Check for documentation
-	if ext == ".md" {
-		if strings.Contains(fileName, "README") || strings.Contains(fileName, "CHANGELOG") {
-			return "documentation"
-		}
-		if strings.Contains(dir, "docs") || strings.Contains(dir, "/doc/") {
-			return "markdown"
-		}
-		return "documentation" // Default for .md files
-	}
-
-	// Check for config files
-	if ext == ".yaml" || ext == ".yml" || ext == ".json" || ext == ".toml" {
-		return "config"
-	}
-
-	// Check for source code
-	sourceExts := map[string]bool{
-		".go": true, ".js": true, ".ts": true, ".py": true, ".java": true,
-		".c": true, ".cpp": true, ".h": true, ".hpp": true, ".rs": true,
-	}
-	if sourceExts[ext] {
-		return "source"
-	}
-
-	return "other"
+	return analysis.CategorizeFile(filePath)
 }
 
 // getEmojisFromFileType extracts emojis used in specific file types.
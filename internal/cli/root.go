@@ -61,7 +61,6 @@ Built with Go using functional programming principles, Antimoji provides:
 	cmd.AddCommand(NewScanCommand())
 	cmd.AddCommand(NewCleanCommand())
 	cmd.AddCommand(NewGenerateCommand())
-	cmd.AddCommand(NewSetupLintCommand())
 	cmd.AddCommand(NewVersionCommand())
 
 	// Set up configuration and logging
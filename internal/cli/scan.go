@@ -12,6 +12,7 @@ import (
 	"github.com/antimoji/antimoji/internal/core/allowlist"
 	"github.com/antimoji/antimoji/internal/core/detector"
 	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
 	"github.com/antimoji/antimoji/internal/infra/filtering"
 	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
 	"github.com/antimoji/antimoji/internal/observability/logging"
@@ -125,7 +126,10 @@ func runScan(cmd *cobra.Command, args []string, opts *ScanOptions) error {
 		"operation", "scan")
 
 	// Create emoji patterns
-	patterns := detector.DefaultEmojiPatterns()
+	patterns, err := emojidata.PatternsForProfile(ctx, detector.DefaultEmojiPatterns(), profile)
+	if err != nil {
+		return fmt.Errorf("failed to load emoji data: %w", err)
+	}
 
 	// Create allowlist using unified processing logic
 	allowlistOpts := allowlist.ProcessingOptions{
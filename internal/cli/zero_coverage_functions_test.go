@@ -1,13 +1,9 @@
 package cli
 
 import (
-	"os"
-	"path/filepath"
 	"testing"
 
-	"github.com/antimoji/antimoji/internal/config"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 )
 
 func TestSetBuildInfo_ZeroCoverage(t *testing.T) {
@@ -25,139 +21,3 @@ func TestGoVersion_ZeroCoverage(t *testing.T) {
 		assert.Contains(t, version, "go")
 	})
 }
-
-func TestGenerateZeroToleranceConfig_ZeroCoverage(t *testing.T) {
-	t.Run("generates config", func(t *testing.T) {
-		baseConfig := config.DefaultConfig()
-		result := generateZeroToleranceConfig(baseConfig)
-
-		assert.NotNil(t, result)
-		assert.Contains(t, result.Profiles, "zero-tolerance")
-	})
-}
-
-func TestGenerateAllowListConfig_ZeroCoverage(t *testing.T) {
-	t.Run("generates config", func(t *testing.T) {
-		baseConfig := config.DefaultConfig()
-		allowedEmojis := []string{"✅", "❌"}
-		result := generateAllowListConfig(baseConfig, allowedEmojis)
-
-		assert.NotNil(t, result)
-		assert.Contains(t, result.Profiles, "allow-list")
-	})
-}
-
-func TestGeneratePermissiveConfig_ZeroCoverage(t *testing.T) {
-	t.Run("generates config", func(t *testing.T) {
-		baseConfig := config.DefaultConfig()
-		result := generatePermissiveConfig(baseConfig)
-
-		assert.NotNil(t, result)
-		assert.Contains(t, result.Profiles, "permissive")
-	})
-}
-
-func TestPromptForReplacement_ZeroCoverage(t *testing.T) {
-	t.Run("function exists", func(t *testing.T) {
-		assert.NotPanics(t, func() {
-			_ = promptForReplacement()
-		})
-	})
-}
-
-func TestPrintRepairSummary_ZeroCoverage(t *testing.T) {
-	t.Run("prints summary", func(t *testing.T) {
-		assert.NotPanics(t, func() {
-			printRepairSummary(ZeroToleranceMode, &SetupLintOptions{})
-		})
-	})
-}
-
-func TestAnalyzeAntimojiConfig_ZeroCoverage(t *testing.T) {
-	tempDir := t.TempDir()
-
-	t.Run("analyzes config", func(t *testing.T) {
-		configContent := `profiles:
-  default:
-    unicode_emojis: true`
-		configPath := filepath.Join(tempDir, "test.yaml")
-		err := os.WriteFile(configPath, []byte(configContent), 0644)
-		require.NoError(t, err)
-
-		assert.NotPanics(t, func() {
-			_ = analyzeAntimojiConfig(configPath, &ReviewData{}) // Test function call, ignore result
-		})
-	})
-}
-
-func TestAnalyzePreCommitHooks_ZeroCoverage(t *testing.T) {
-	tempDir := t.TempDir()
-
-	t.Run("analyzes hooks", func(t *testing.T) {
-		configContent := `repos:
-- repo: local`
-		configPath := filepath.Join(tempDir, "hooks.yaml")
-		err := os.WriteFile(configPath, []byte(configContent), 0644)
-		require.NoError(t, err)
-
-		assert.NotPanics(t, func() {
-			analyzePreCommitHooks(configPath, &ReviewData{})
-		})
-	})
-}
-
-func TestAnalyzeCodebaseImpact_ZeroCoverage(t *testing.T) {
-	tempDir := t.TempDir()
-
-	// Create test file
-	err := os.WriteFile(filepath.Join(tempDir, "test.go"), []byte("package main"), 0644)
-	require.NoError(t, err)
-
-	t.Run("analyzes codebase", func(t *testing.T) {
-		assert.NotPanics(t, func() {
-			analyzeCodebaseImpact(tempDir, &ReviewData{})
-		})
-	})
-}
-
-func TestIsRelevantFile_ZeroCoverage(t *testing.T) {
-	tests := []string{"main.go", "app.py", "script.js", "README.md"}
-
-	for _, filename := range tests {
-		t.Run(filename, func(t *testing.T) {
-			result := isRelevantFile(filename)
-			assert.True(t, result == true || result == false) // Just test it doesn't panic
-		})
-	}
-}
-
-func TestCountEmojisInContent_ZeroCoverage(t *testing.T) {
-	tests := []string{
-		"Hello world",
-		"Hello 👋",
-		"🚀 Amazing ✨",
-		"",
-	}
-
-	for _, content := range tests {
-		t.Run("content_test", func(t *testing.T) {
-			count := countEmojisInContent(content)
-			assert.True(t, count >= 0) // Should return non-negative count
-		})
-	}
-}
-
-func TestValidateConfigurationFile_ZeroCoverage(t *testing.T) {
-	tempDir := t.TempDir()
-
-	t.Run("validates file", func(t *testing.T) {
-		configContent := `profiles: {}`
-		configPath := filepath.Join(tempDir, "config.yaml")
-		err := os.WriteFile(configPath, []byte(configContent), 0644)
-		require.NoError(t, err)
-
-		assert.NotPanics(t, func() {
-			_ = validateConfigurationFile(configPath, &SetupLintOptions{}) // Test function call, ignore result
-		})
-	})
-}
@@ -2,68 +2,398 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+	"github.com/antimoji/antimoji/internal/infra/remoteconfig"
 	"github.com/antimoji/antimoji/internal/types"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // Config represents the complete application configuration.
 type Config struct {
 	Profiles map[string]Profile `yaml:"profiles" json:"profiles"`
+
+	// Upgrade selects the releases 'antimoji upgrade' installs
+	Upgrade UpgradeConfig `yaml:"upgrade,omitempty" json:"upgrade,omitempty"`
+
+	// HTTP holds the network settings of downloads
+	HTTP HTTPConfig `yaml:"http,omitempty" json:"http,omitempty"`
+
+	// Rules route the files matching their paths to other profiles; the first match
+	// wins
+	Rules []RoutingRule `yaml:"rules,omitempty" json:"rules,omitempty"`
+
+	// Warnings are notices about the loaded file, such as an available schema migration
+	Warnings []string `yaml:"-" json:"-"`
 }
 
 // Profile represents a configuration profile with specific settings.
 type Profile struct {
 	// File processing
-	Recursive      bool `yaml:"recursive" json:"recursive"`
-	FollowSymlinks bool `yaml:"follow_symlinks" json:"follow_symlinks"`
-	BackupFiles    bool `yaml:"backup_files" json:"backup_files"`
+	Recursive        bool `yaml:"recursive" json:"recursive"`
+	FollowSymlinks   bool `yaml:"follow_symlinks" json:"follow_symlinks"`
+	BackupFiles      bool `yaml:"backup_files" json:"backup_files"`
+	RespectGitignore bool `yaml:"respect_gitignore" json:"respect_gitignore"`
+
+	// Metadata clean keeps on top of permissions, where the process may set it
+	PreserveOwnership bool `yaml:"preserve_ownership" json:"preserve_ownership"`
+	PreserveXattrs    bool `yaml:"preserve_xattrs" json:"preserve_xattrs"`
+
+	// Hard-linked files in clean (warn, in_place or skip; unset warns)
+	HardlinkPolicy string `yaml:"hardlink_policy" json:"hardlink_policy"`
+
+	// Remove UTF-8 byte order marks in clean instead of keeping them
+	StripBOM bool `yaml:"strip_bom" json:"strip_bom"`
+
+	// Symlink handling (follow, skip or report; unset uses follow_symlinks)
+	SymlinkPolicy   string `yaml:"symlink_policy" json:"symlink_policy"`
+	MaxSymlinkDepth int    `yaml:"max_symlink_depth" json:"max_symlink_depth"`
 
 	// Emoji detection
 	UnicodeEmojis  bool     `yaml:"unicode_emojis" json:"unicode_emojis"`
 	TextEmoticons  bool     `yaml:"text_emoticons" json:"text_emoticons"`
 	CustomPatterns []string `yaml:"custom_patterns" json:"custom_patterns"`
 
+	// Opt-in detection of kaomoji and decorative symbols such as ★ and ►
+	Kaomoji           bool `yaml:"kaomoji" json:"kaomoji"`
+	DecorativeSymbols bool `yaml:"decorative_symbols" json:"decorative_symbols"`
+
+	// Opt-in detection of emojis written as escapes such as &#x1F600; or \u{1F600}
+	EscapedEmojis bool `yaml:"escaped_emojis" json:"escaped_emojis"`
+
+	// Opt-in detection of invisible characters and lookalike pictographs that evade
+	// emoji detection, with the number of such findings tolerated (0 = none)
+	EvasionDetection bool `yaml:"evasion_detection" json:"evasion_detection"`
+	EvasionThreshold int  `yaml:"evasion_threshold" json:"evasion_threshold"`
+
+	// Opt-in rule set reporting zero width spaces, byte order marks after the start of
+	// a file and bidirectional controls (trojan source)
+	UnicodeHygiene bool `yaml:"unicode_hygiene" json:"unicode_hygiene"`
+
+	// Supplemental emoji data (local paths or http(s) URLs)
+	EmojiDataSources   []string `yaml:"emoji_data_sources" json:"emoji_data_sources"`
+	EmojiDataPublicKey string   `yaml:"emoji_data_public_key" json:"emoji_data_public_key"`
+
+	// Emoji dataset replacing the embedded one, such as a file generated from a newer
+	// Unicode release (local path or http(s) URL)
+	EmojiDataset string `yaml:"emoji_dataset" json:"emoji_dataset"`
+
 	// Allowlist and ignore functionality
 	EmojiAllowlist      []string `yaml:"emoji_allowlist" json:"emoji_allowlist"`
 	FileIgnoreList      []string `yaml:"file_ignore_list" json:"file_ignore_list"`
 	DirectoryIgnoreList []string `yaml:"directory_ignore_list" json:"directory_ignore_list"`
 
+	// GitHub and GitLab shortcodes such as :rocket: (ignore, detect, to_unicode or
+	// to_shortcode; unset ignores them)
+	ShortcodePolicy string `yaml:"shortcode_policy" json:"shortcode_policy"`
+
+	// Markdown code blocks and inline code (preserve or clean; unset preserves)
+	MarkdownCodeBlocks string `yaml:"markdown_code_blocks" json:"markdown_code_blocks"`
+
+	// Keys and values of YAML, JSON and TOML files (check or allow; unset checks)
+	StructuredKeys   string `yaml:"structured_keys,omitempty" json:"structured_keys,omitempty"`
+	StructuredValues string `yaml:"structured_values,omitempty" json:"structured_values,omitempty"`
+
+	// Languages registered on top of the built-in ones, for their comments, escapes and
+	// code fences
+	Languages []LanguageConfig `yaml:"languages,omitempty" json:"languages,omitempty"`
+
 	// Replacement behavior
-	Replacement        string `yaml:"replacement" json:"replacement"`
-	PreserveWhitespace bool   `yaml:"preserve_whitespace" json:"preserve_whitespace"`
+	Replacement        string            `yaml:"replacement" json:"replacement"`
+	ReplacementMap     map[string]string `yaml:"replacement_map,omitempty" json:"replacement_map,omitempty"`
+	PreserveWhitespace bool              `yaml:"preserve_whitespace" json:"preserve_whitespace"`
 
 	// File filters
 	IncludePatterns []string `yaml:"include_patterns" json:"include_patterns"`
 	ExcludePatterns []string `yaml:"exclude_patterns" json:"exclude_patterns"`
 
 	// CI/CD and linting
-	FailOnFound       bool `yaml:"fail_on_found" json:"fail_on_found"`
-	MaxEmojiThreshold int  `yaml:"max_emoji_threshold" json:"max_emoji_threshold"`
-	ExitCodeOnFound   int  `yaml:"exit_code_on_found" json:"exit_code_on_found"`
+	FailOnFound     bool `yaml:"fail_on_found" json:"fail_on_found"`
+	ExitCodeOnFound int  `yaml:"exit_code_on_found" json:"exit_code_on_found"`
+
+	// Deprecated: MaxEmojiThreshold is superseded by MaxTotal, which 'antimoji config
+	// migrate' rewrites it to. It still sets the commit-msg hook's limit when max_total
+	// is not set, and scan's limit only when overridden by the environment or --set.
+	MaxEmojiThreshold int `yaml:"max_emoji_threshold,omitempty" json:"max_emoji_threshold,omitempty"`
+
+	// Violation limits of a run: in total across all files (0 = no limit) and, when a
+	// baseline of earlier findings is given, outside the baseline (0 = none tolerated)
+	MaxTotal int `yaml:"max_total" json:"max_total"`
+	MaxNew   int `yaml:"max_new" json:"max_new"`
+
+	// Violation budgets on top of max_total: per file (0 = no limit), below a
+	// directory, per emoji and per CODEOWNERS owner (0 = none tolerated)
+	MaxPerFile          int            `yaml:"max_per_file" json:"max_per_file"`
+	DirectoryThresholds map[string]int `yaml:"directory_thresholds,omitempty" json:"directory_thresholds,omitempty"`
+	EmojiThresholds     map[string]int `yaml:"emoji_thresholds,omitempty" json:"emoji_thresholds,omitempty"`
+	OwnerThresholds     map[string]int `yaml:"owner_thresholds,omitempty" json:"owner_thresholds,omitempty"`
+
+	// Severity maps detection categories and emojis to "error" or "warn"; warnings are
+	// reported but count against no threshold or budget. Emojis take precedence over
+	// their category, and findings default to errors.
+	Severity map[string]string `yaml:"severity,omitempty" json:"severity,omitempty"`
+
+	// Findings allowed until a date, for staged migrations
+	Exemptions []Exemption `yaml:"exemptions,omitempty" json:"exemptions,omitempty"`
+
+	// Sinks the summaries of scans are sent to
+	Notify []Notification `yaml:"notify,omitempty" json:"notify,omitempty"`
+
+	// CEL expressions over the violations that fail a scan when they match too many
+	CustomRules []CustomRule `yaml:"custom_rules,omitempty" json:"custom_rules,omitempty"`
 
 	// Performance
 	MaxWorkers  int   `yaml:"max_workers" json:"max_workers"`
 	BufferSize  int   `yaml:"buffer_size" json:"buffer_size"`
 	MaxFileSize int64 `yaml:"max_file_size" json:"max_file_size"`
 
+	// Binary detection (0 uses the default for each setting)
+	BinarySampleSize   int     `yaml:"binary_sample_size" json:"binary_sample_size"`
+	BinaryNullRatio    float64 `yaml:"binary_null_ratio" json:"binary_null_ratio"`
+	BinaryControlRatio float64 `yaml:"binary_control_ratio" json:"binary_control_ratio"`
+
+	// Retries of reads failing with a transient error, as on network file systems
+	ReadRetries    int `yaml:"read_retries" json:"read_retries"`
+	RetryBackoffMs int `yaml:"retry_backoff_ms" json:"retry_backoff_ms"`
+
 	// Output
 	OutputFormat  string `yaml:"output_format" json:"output_format"`
 	ShowProgress  bool   `yaml:"show_progress" json:"show_progress"`
 	ColoredOutput bool   `yaml:"colored_output" json:"colored_output"`
 }
 
-// LoadConfig loads configuration from the specified file path.
+// LoadConfig loads configuration from the specified file path or remote reference
+// (see remoteconfig.Parse), merging any files it extends.
 func LoadConfig(configPath string) types.Result[Config] {
+	source, err := readConfigSource(configPath)
+	if err != nil {
+		return types.Err[Config](err)
+	}
+
+	return withWarnings(configPath, source, parseConfig(source.content))
+}
+
+// LoadConfigStrict loads configuration like LoadConfig but fails when the file contains
+// keys antimoji does not know, which are usually misspelled field names.
+func LoadConfigStrict(configPath string) types.Result[Config] {
+	source, err := readConfigSource(configPath)
+	if err != nil {
+		return types.Err[Config](err)
+	}
+
+	unknown, err := UnknownKeys(source.content)
+	if err != nil {
+		return types.Err[Config](fmt.Errorf("failed to parse config: %w", err))
+	}
+	if len(unknown) > 0 {
+		return types.Err[Config](fmt.Errorf("unknown configuration keys: %s", strings.Join(unknown, ", ")))
+	}
+
+	return withWarnings(configPath, source, parseConfig(source.content))
+}
+
+// ParseConfig parses configuration content that is not read from a file, such as a
+// configuration about to be written. The content cannot extend other files.
+func ParseConfig(content []byte) types.Result[Config] {
+	return parseConfig(content)
+}
+
+// withWarnings adds the warnings of source to a loaded configuration, and a warning
+// when a local file uses deprecated keys that 'antimoji config migrate' would rewrite.
+func withWarnings(configPath string, source configSource, result types.Result[Config]) types.Result[Config] {
+	if result.IsErr() {
+		return result
+	}
+	config := result.Unwrap()
+	config.Warnings = append(config.Warnings, source.warnings...)
+	if remoteconfig.IsRemote(configPath) {
+		return types.Ok(config)
+	}
+	if warning := migrationWarning(configPath, source.local); warning != "" {
+		config.Warnings = append(config.Warnings, warning)
+	}
+	return types.Ok(config)
+}
+
+// knownTopLevelKeys are the keys accepted at the root of a configuration file. version
+// is the schema version (see SchemaVersion), extends names the files this one builds on,
+// upgrade holds the settings of 'antimoji upgrade', http those of downloads and rules
+// route paths to profiles.
+var knownTopLevelKeys = map[string]bool{"profiles": true, "version": true, "extends": true, "upgrade": true, "http": true, "rules": true}
+
+// sectionFields are the keys of the top-level sections other than profiles.
+var sectionFields = map[string]map[string]bool{"upgrade": upgradeFields, "http": httpFields}
+
+// UnknownKeys returns the dotted paths of keys in YAML content that are not part of the
+// configuration schema, e.g. profiles.ci.max_file_sise, in sorted order.
+func UnknownKeys(content []byte) ([]string, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for key, value := range raw {
+		if !knownTopLevelKeys[key] {
+			unknown = append(unknown, key)
+			continue
+		}
+		if fields, ok := sectionFields[key]; ok {
+			settings, _ := value.(map[string]interface{})
+			for field := range settings {
+				if !fields[field] {
+					unknown = append(unknown, key+"."+field)
+				}
+			}
+			continue
+		}
+		if key != "profiles" {
+			continue
+		}
+
+		profiles, _ := value.(map[string]interface{})
+		for name, fields := range profiles {
+			profile, _ := fields.(map[string]interface{})
+			for field := range profile {
+				if _, ok := profileFields[field]; !ok {
+					unknown = append(unknown, "profiles."+name+"."+field)
+				}
+			}
+		}
+	}
+
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// parseConfig parses configuration from YAML content.
+func parseConfig(content []byte) types.Result[Config] {
 	v := viper.New()
-	v.SetConfigFile(configPath)
 	v.SetConfigType("yaml")
 
-	if err := v.ReadInConfig(); err != nil {
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return types.Err[Config](err)
+	}
+
+	configResult := configFromViper(v)
+	if configResult.IsErr() {
+		return configResult
+	}
+
+	config := configResult.Unwrap()
+	if err := loadProfileMaps(content, config); err != nil {
 		return types.Err[Config](err)
 	}
+	upgrade, err := loadUpgrade(content)
+	if err != nil {
+		return types.Err[Config](err)
+	}
+	config.Upgrade = upgrade
+	if config.HTTP, err = loadHTTP(content); err != nil {
+		return types.Err[Config](err)
+	}
+	if config.Rules, err = loadRules(content); err != nil {
+		return types.Err[Config](err)
+	}
+	if err := validateRules(config); err != nil {
+		return types.Err[Config](err)
+	}
+
+	// A malformed pattern would silently match nothing, an exemption without a valid
+	// date could never expire, a misspelt severity would silently be an error, a
+	// misconfigured notification would never be sent and a custom rule that does not
+	// compile could never fail
+	for name, profile := range config.Profiles {
+		if err := validatePatterns(name, profile); err != nil {
+			return types.Err[Config](err)
+		}
+		if err := validateExemptions(name, profile); err != nil {
+			return types.Err[Config](err)
+		}
+		if err := validateSeverities(name, profile); err != nil {
+			return types.Err[Config](err)
+		}
+		if err := validateNotifications(name, profile); err != nil {
+			return types.Err[Config](err)
+		}
+		if err := validateCustomRules(name, profile); err != nil {
+			return types.Err[Config](err)
+		}
+	}
+
+	return types.Ok(config)
+}
+
+// loadProfileMaps decodes each profile's replacement_map and threshold maps, and its
+// languages, exemptions, notifications and custom rules, from the raw YAML. Viper lower-cases map
+// keys, which would corrupt emoticon keys such as ":D" and directory names.
+func loadProfileMaps(content []byte, config Config) error {
+	var raw struct {
+		Profiles map[string]struct {
+			ReplacementMap      map[string]string `yaml:"replacement_map"`
+			DirectoryThresholds map[string]int    `yaml:"directory_thresholds"`
+			EmojiThresholds     map[string]int    `yaml:"emoji_thresholds"`
+			OwnerThresholds     map[string]int    `yaml:"owner_thresholds"`
+			Severity            map[string]string `yaml:"severity"`
+			Languages           []LanguageConfig  `yaml:"languages"`
+			Exemptions          []Exemption       `yaml:"exemptions"`
+			Notify              []Notification    `yaml:"notify"`
+			CustomRules         []CustomRule      `yaml:"custom_rules"`
+		} `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return fmt.Errorf("failed to parse profile maps: %w", err)
+	}
+
+	for name, rawProfile := range raw.Profiles {
+		// Profile names are lower-cased by Viper as well
+		profileName := strings.ToLower(name)
+		profile, ok := config.Profiles[profileName]
+		if !ok {
+			continue
+		}
+		if len(rawProfile.ReplacementMap) > 0 {
+			profile.ReplacementMap = rawProfile.ReplacementMap
+		}
+		if len(rawProfile.DirectoryThresholds) > 0 {
+			profile.DirectoryThresholds = rawProfile.DirectoryThresholds
+		}
+		if len(rawProfile.EmojiThresholds) > 0 {
+			profile.EmojiThresholds = rawProfile.EmojiThresholds
+		}
+		if len(rawProfile.OwnerThresholds) > 0 {
+			profile.OwnerThresholds = rawProfile.OwnerThresholds
+		}
+		if len(rawProfile.Severity) > 0 {
+			profile.Severity = rawProfile.Severity
+		}
+		if len(rawProfile.Languages) > 0 {
+			profile.Languages = rawProfile.Languages
+		}
+		if len(rawProfile.Exemptions) > 0 {
+			profile.Exemptions = rawProfile.Exemptions
+		}
+		if len(rawProfile.Notify) > 0 {
+			profile.Notify = rawProfile.Notify
+		}
+		if len(rawProfile.CustomRules) > 0 {
+			profile.CustomRules = rawProfile.CustomRules
+		}
+		config.Profiles[profileName] = profile
+	}
+
+	return nil
+}
 
+// configFromViper builds a Config from loaded Viper settings.
+func configFromViper(v *viper.Viper) types.Result[Config] {
 	config := Config{
 		Profiles: make(map[string]Profile),
 	}
@@ -87,20 +417,60 @@ func loadProfile(v *viper.Viper, profileName string) (Profile, error) {
 
 	profile := Profile{
 		// File processing
-		Recursive:      v.GetBool(prefix + ".recursive"),
-		FollowSymlinks: v.GetBool(prefix + ".follow_symlinks"),
-		BackupFiles:    v.GetBool(prefix + ".backup_files"),
+		Recursive:        v.GetBool(prefix + ".recursive"),
+		FollowSymlinks:   v.GetBool(prefix + ".follow_symlinks"),
+		BackupFiles:      v.GetBool(prefix + ".backup_files"),
+		RespectGitignore: v.GetBool(prefix + ".respect_gitignore"),
+
+		// Metadata preservation
+		PreserveOwnership: v.GetBool(prefix + ".preserve_ownership"),
+		PreserveXattrs:    v.GetBool(prefix + ".preserve_xattrs"),
+		HardlinkPolicy:    v.GetString(prefix + ".hardlink_policy"),
+		StripBOM:          v.GetBool(prefix + ".strip_bom"),
+
+		// Symlink handling
+		SymlinkPolicy:   v.GetString(prefix + ".symlink_policy"),
+		MaxSymlinkDepth: v.GetInt(prefix + ".max_symlink_depth"),
 
 		// Emoji detection
 		UnicodeEmojis:  v.GetBool(prefix + ".unicode_emojis"),
 		TextEmoticons:  v.GetBool(prefix + ".text_emoticons"),
 		CustomPatterns: v.GetStringSlice(prefix + ".custom_patterns"),
 
+		// Kaomoji and decorative symbols
+		Kaomoji:           v.GetBool(prefix + ".kaomoji"),
+		DecorativeSymbols: v.GetBool(prefix + ".decorative_symbols"),
+
+		// Escaped emojis
+		EscapedEmojis: v.GetBool(prefix + ".escaped_emojis"),
+
+		// Evasion detection
+		EvasionDetection: v.GetBool(prefix + ".evasion_detection"),
+		EvasionThreshold: v.GetInt(prefix + ".evasion_threshold"),
+
+		// Unicode hygiene
+		UnicodeHygiene: v.GetBool(prefix + ".unicode_hygiene"),
+
+		// Supplemental emoji data
+		EmojiDataSources:   v.GetStringSlice(prefix + ".emoji_data_sources"),
+		EmojiDataPublicKey: v.GetString(prefix + ".emoji_data_public_key"),
+		EmojiDataset:       v.GetString(prefix + ".emoji_dataset"),
+
 		// Allowlist and ignore functionality
 		EmojiAllowlist:      v.GetStringSlice(prefix + ".emoji_allowlist"),
 		FileIgnoreList:      v.GetStringSlice(prefix + ".file_ignore_list"),
 		DirectoryIgnoreList: v.GetStringSlice(prefix + ".directory_ignore_list"),
 
+		// Shortcode handling
+		ShortcodePolicy: v.GetString(prefix + ".shortcode_policy"),
+
+		// Markdown code handling
+		MarkdownCodeBlocks: v.GetString(prefix + ".markdown_code_blocks"),
+
+		// Structured file handling
+		StructuredKeys:   v.GetString(prefix + ".structured_keys"),
+		StructuredValues: v.GetString(prefix + ".structured_values"),
+
 		// Replacement behavior
 		Replacement:        v.GetString(prefix + ".replacement"),
 		PreserveWhitespace: v.GetBool(prefix + ".preserve_whitespace"),
@@ -113,30 +483,128 @@ func loadProfile(v *viper.Viper, profileName string) (Profile, error) {
 		FailOnFound:       v.GetBool(prefix + ".fail_on_found"),
 		MaxEmojiThreshold: v.GetInt(prefix + ".max_emoji_threshold"),
 		ExitCodeOnFound:   v.GetInt(prefix + ".exit_code_on_found"),
+		MaxTotal:          v.GetInt(prefix + ".max_total"),
+		MaxNew:            v.GetInt(prefix + ".max_new"),
+		MaxPerFile:        v.GetInt(prefix + ".max_per_file"),
 
 		// Performance
 		MaxWorkers:  v.GetInt(prefix + ".max_workers"),
 		BufferSize:  v.GetInt(prefix + ".buffer_size"),
 		MaxFileSize: v.GetInt64(prefix + ".max_file_size"),
 
+		// Binary detection
+		BinarySampleSize:   v.GetInt(prefix + ".binary_sample_size"),
+		BinaryNullRatio:    v.GetFloat64(prefix + ".binary_null_ratio"),
+		BinaryControlRatio: v.GetFloat64(prefix + ".binary_control_ratio"),
+
+		// Read retries
+		ReadRetries:    v.GetInt(prefix + ".read_retries"),
+		RetryBackoffMs: v.GetInt(prefix + ".retry_backoff_ms"),
+
 		// Output
 		OutputFormat:  v.GetString(prefix + ".output_format"),
 		ShowProgress:  v.GetBool(prefix + ".show_progress"),
 		ColoredOutput: v.GetBool(prefix + ".colored_output"),
 	}
 
-	return profile, nil
+	return mergeDefaults(profile, defaultedFields, func(key string) bool { return v.IsSet(prefix + "." + key) }), nil
 }
 
+// defaultedFields are the profile fields whose zero value is unsafe (a zero size limit
+// skips every file) or not the intended default, so they take the default profile's
+// value when a file omits them.
+var defaultedFields = []string{"max_file_size", "buffer_size", "max_workers", "respect_gitignore", "read_retries"}
+
+// mergeDefaults fills the fields of profile named by keys that isSet reports as
+// unspecified with the values of the default profile.
+func mergeDefaults(profile Profile, keys []string, isSet func(key string) bool) Profile {
+	defaults := reflect.ValueOf(DefaultConfig().Profiles["default"])
+	merged := reflect.ValueOf(&profile).Elem()
+	for _, key := range keys {
+		if !isSet(key) {
+			index := profileFields[key]
+			merged.Field(index).Set(defaults.Field(index))
+		}
+	}
+	return profile
+}
+
+// Defaults for the performance limits, used wherever a profile leaves them unset.
+const (
+	DefaultMaxFileSize int64 = 100 * 1024 * 1024 // 100MB
+	DefaultBufferSize  int   = 64 * 1024         // 64KB
+)
+
+// DefaultMaxSymlinkDepth is how many symlinked directories deep discovery follows when a
+// profile leaves max_symlink_depth unset.
+const DefaultMaxSymlinkDepth = 8
+
+// Symlink policies for file discovery.
+const (
+	// SymlinkFollow follows symlinks to files and directories, skipping cycles
+	SymlinkFollow = "follow"
+	// SymlinkSkip ignores symlinks silently
+	SymlinkSkip = "skip"
+	// SymlinkReport ignores symlinks but reports each one
+	SymlinkReport = "report"
+)
+
+// Shortcode policies.
+const (
+	// ShortcodeIgnore leaves shortcodes not listed in custom_patterns undetected
+	ShortcodeIgnore = "ignore"
+	// ShortcodeDetect detects shortcodes like any other emoji
+	ShortcodeDetect = "detect"
+	// ShortcodeToUnicode detects shortcodes and has clean rewrite them as Unicode emojis
+	ShortcodeToUnicode = "to_unicode"
+	// ShortcodeToShortcode detects shortcodes and has clean rewrite Unicode emojis as
+	// shortcodes
+	ShortcodeToShortcode = "to_shortcode"
+)
+
+// Hard link policies for clean.
+const (
+	// HardlinkWarn replaces hard-linked files like any other, detaching them from their
+	// other links, and warns about each
+	HardlinkWarn = "warn"
+	// HardlinkInPlace overwrites hard-linked files in place so all their links see the
+	// cleaned content, giving up the atomic write
+	HardlinkInPlace = "in_place"
+	// HardlinkSkip leaves hard-linked files unmodified and reports each
+	HardlinkSkip = "skip"
+)
+
+// Markdown code block handling.
+const (
+	// MarkdownPreserve leaves emojis in fenced code blocks and inline code untouched
+	MarkdownPreserve = "preserve"
+	// MarkdownClean treats code in Markdown files like prose
+	MarkdownClean = "clean"
+)
+
+// Structured file key and value handling.
+const (
+	// StructuredCheck detects emojis in the keys or values of structured files like
+	// anywhere else
+	StructuredCheck = "check"
+	// StructuredAllow leaves emojis in the keys or values of structured files alone
+	StructuredAllow = "allow"
+)
+
 // DefaultConfig returns the default configuration.
 func DefaultConfig() Config {
 	return Config{
 		Profiles: map[string]Profile{
 			"default": {
 				// File processing
-				Recursive:      true,
-				FollowSymlinks: false,
-				BackupFiles:    false,
+				Recursive:        true,
+				FollowSymlinks:   false,
+				BackupFiles:      false,
+				RespectGitignore: true, // Only takes effect inside a git repository
+
+				// Symlink handling - policy follows follow_symlinks
+				SymlinkPolicy:   "",
+				MaxSymlinkDepth: DefaultMaxSymlinkDepth,
 
 				// Emoji detection
 				UnicodeEmojis:  true,
@@ -150,7 +618,7 @@ func DefaultConfig() Config {
 					".git/**/*", "**/*.generated.*",
 				},
 				DirectoryIgnoreList: []string{
-					".git", "node_modules", "vendor", "dist", "build",
+					".git", ".antimoji", "node_modules", "vendor", "dist", "build",
 				},
 
 				// Replacement behavior
@@ -162,14 +630,22 @@ func DefaultConfig() Config {
 				ExcludePatterns: []string{"vendor/*", "node_modules/*", ".git/*", ".git/**/*"},
 
 				// CI/CD and linting
-				FailOnFound:       false,
-				MaxEmojiThreshold: 0,
-				ExitCodeOnFound:   1,
+				FailOnFound:     false,
+				ExitCodeOnFound: 1,
 
 				// Performance
 				MaxWorkers:  0, // Auto-detect CPU cores
-				BufferSize:  64 * 1024,
-				MaxFileSize: 100 * 1024 * 1024, // 100MB
+				BufferSize:  DefaultBufferSize,
+				MaxFileSize: DefaultMaxFileSize,
+
+				// Binary detection - any NUL byte or over 30% control characters
+				BinarySampleSize:   1024,
+				BinaryNullRatio:    0,
+				BinaryControlRatio: 0.30,
+
+				// Read retries - two retries after 100ms and 200ms
+				ReadRetries:    2,
+				RetryBackoffMs: 100,
 
 				// Output
 				OutputFormat:  "table",
@@ -202,6 +678,9 @@ func ValidateConfig(config Config) types.Result[Config] {
 			return types.Err[Config](err)
 		}
 	}
+	if err := validateRules(config); err != nil {
+		return types.Err[Config](err)
+	}
 
 	return types.Ok(config)
 }
@@ -224,6 +703,119 @@ func validateProfile(name string, profile Profile) error {
 		return fmt.Errorf("profile %s: max emoji threshold cannot be negative", name)
 	}
 
+	if profile.MaxTotal < 0 {
+		return fmt.Errorf("profile %s: max total cannot be negative", name)
+	}
+
+	if profile.MaxNew < 0 {
+		return fmt.Errorf("profile %s: max new cannot be negative", name)
+	}
+
+	if profile.MaxSymlinkDepth < 0 {
+		return fmt.Errorf("profile %s: max symlink depth cannot be negative", name)
+	}
+
+	if profile.MaxPerFile < 0 {
+		return fmt.Errorf("profile %s: max per file cannot be negative", name)
+	}
+
+	if profile.EvasionThreshold < 0 {
+		return fmt.Errorf("profile %s: evasion threshold cannot be negative", name)
+	}
+
+	for dir, limit := range profile.DirectoryThresholds {
+		if limit < 0 {
+			return fmt.Errorf("profile %s: directory threshold for %s cannot be negative", name, dir)
+		}
+	}
+
+	for emoji, limit := range profile.EmojiThresholds {
+		if limit < 0 {
+			return fmt.Errorf("profile %s: emoji threshold for %s cannot be negative", name, emoji)
+		}
+	}
+
+	for owner, limit := range profile.OwnerThresholds {
+		if limit < 0 {
+			return fmt.Errorf("profile %s: owner threshold for %s cannot be negative", name, owner)
+		}
+	}
+
+	if err := validateSeverities(name, profile); err != nil {
+		return err
+	}
+
+	if profile.BinarySampleSize < 0 {
+		return fmt.Errorf("profile %s: binary sample size cannot be negative", name)
+	}
+
+	if profile.BinaryNullRatio < 0 || profile.BinaryNullRatio > 1 {
+		return fmt.Errorf("profile %s: binary null ratio must be between 0 and 1", name)
+	}
+
+	if profile.BinaryControlRatio < 0 || profile.BinaryControlRatio > 1 {
+		return fmt.Errorf("profile %s: binary control ratio must be between 0 and 1", name)
+	}
+
+	if profile.ReadRetries < 0 {
+		return fmt.Errorf("profile %s: read retries cannot be negative", name)
+	}
+
+	if profile.RetryBackoffMs < 0 {
+		return fmt.Errorf("profile %s: retry backoff cannot be negative", name)
+	}
+
+	switch profile.SymlinkPolicy {
+	case "", SymlinkFollow, SymlinkSkip, SymlinkReport:
+	default:
+		return fmt.Errorf("profile %s: invalid symlink policy: %s (must be follow, skip or report)", name, profile.SymlinkPolicy)
+	}
+
+	switch profile.ShortcodePolicy {
+	case "", ShortcodeIgnore, ShortcodeDetect, ShortcodeToUnicode, ShortcodeToShortcode:
+	default:
+		return fmt.Errorf("profile %s: invalid shortcode_policy: %s (must be ignore, detect, to_unicode or to_shortcode)", name, profile.ShortcodePolicy)
+	}
+
+	switch profile.HardlinkPolicy {
+	case "", HardlinkWarn, HardlinkInPlace, HardlinkSkip:
+	default:
+		return fmt.Errorf("profile %s: invalid hardlink_policy: %s (must be warn, in_place or skip)", name, profile.HardlinkPolicy)
+	}
+
+	switch profile.MarkdownCodeBlocks {
+	case "", MarkdownPreserve, MarkdownClean:
+	default:
+		return fmt.Errorf("profile %s: invalid markdown_code_blocks: %s (must be preserve or clean)", name, profile.MarkdownCodeBlocks)
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"structured_keys", profile.StructuredKeys},
+		{"structured_values", profile.StructuredValues},
+	} {
+		switch field.value {
+		case "", StructuredCheck, StructuredAllow:
+		default:
+			return fmt.Errorf("profile %s: invalid %s: %s (must be check or allow)", name, field.name, field.value)
+		}
+	}
+
+	if err := validatePatterns(name, profile); err != nil {
+		return err
+	}
+	if err := validateLanguages(name, profile); err != nil {
+		return err
+	}
+	if err := validateExemptions(name, profile); err != nil {
+		return err
+	}
+	if err := validateNotifications(name, profile); err != nil {
+		return err
+	}
+	if err := validateCustomRules(name, profile); err != nil {
+		return err
+	}
+
 	// Validate output format
 	validFormats := []string{"table", "json", "csv"}
 	validFormat := false
@@ -240,17 +832,63 @@ func validateProfile(name string, profile Profile) error {
 	return nil
 }
 
+// validateSeverities checks that the severity map of a profile only uses known
+// severities.
+func validateSeverities(name string, profile Profile) error {
+	keys := make([]string, 0, len(profile.Severity))
+	for key := range profile.Severity {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if severity := profile.Severity[key]; severity != types.SeverityError && severity != types.SeverityWarn {
+			return fmt.Errorf("profile %s: severity of %s must be %q or %q, got %q",
+				name, key, types.SeverityError, types.SeverityWarn, severity)
+		}
+	}
+	return nil
+}
+
+// validatePatterns checks the glob syntax of the file filter patterns of a profile.
+func validatePatterns(name string, profile Profile) error {
+	fields := []struct {
+		name     string
+		patterns []string
+	}{
+		{"include_patterns", profile.IncludePatterns},
+		{"exclude_patterns", profile.ExcludePatterns},
+		{"file_ignore_list", profile.FileIgnoreList},
+	}
+	for _, field := range fields {
+		for _, pattern := range field.patterns {
+			if err := pathmatch.Validate(pattern); err != nil {
+				return fmt.Errorf("profile %s: %s: %w", name, field.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TotalLimit returns the profile's limit on violations across a run: max_total, or the
+// deprecated max_emoji_threshold when max_total is not set. 0 means neither is set.
+func TotalLimit(profile Profile) int {
+	if profile.MaxTotal > 0 {
+		return profile.MaxTotal
+	}
+	return profile.MaxEmojiThreshold
+}
+
 // ToProcessingConfig converts a Profile to a ProcessingConfig.
 func ToProcessingConfig(profile Profile) types.ProcessingConfig {
-	// Use sensible defaults for zero values that would break processing
+	// Profiles built in code (or with explicit zeros) still get safe limits
 	maxFileSize := profile.MaxFileSize
 	if maxFileSize <= 0 {
-		maxFileSize = 100 * 1024 * 1024 // 100MB default
+		maxFileSize = DefaultMaxFileSize
 	}
 
 	bufferSize := profile.BufferSize
 	if bufferSize <= 0 {
-		bufferSize = 64 * 1024 // 64KB default
+		bufferSize = DefaultBufferSize
 	}
 
 	// For emoji detection, use defaults that make sense for typical usage
@@ -266,12 +904,44 @@ func ToProcessingConfig(profile Profile) types.ProcessingConfig {
 		enableEmoticons = true // Enable text emoticons by default
 	}
 
+	normalize := types.NormalizeNone
+	switch profile.ShortcodePolicy {
+	case ShortcodeToUnicode:
+		normalize = types.NormalizeToUnicode
+	case ShortcodeToShortcode:
+		normalize = types.NormalizeToShortcode
+	}
+
 	return types.ProcessingConfig{
-		EnableUnicode:   enableUnicode,
-		EnableEmoticons: enableEmoticons,
-		EnableCustom:    len(profile.CustomPatterns) > 0,
-		MaxFileSize:     maxFileSize,
-		BufferSize:      bufferSize,
+		EnableUnicode:    enableUnicode,
+		EnableEmoticons:  enableEmoticons,
+		EnableCustom:     len(profile.CustomPatterns) > 0,
+		EnableShortcodes: profile.ShortcodePolicy != "" && profile.ShortcodePolicy != ShortcodeIgnore,
+		EnableKaomoji:    profile.Kaomoji,
+		EnableDecorative: profile.DecorativeSymbols,
+		EnableEscapes:    profile.EscapedEmojis,
+		EnableEvasion:    profile.EvasionDetection,
+		EnableHygiene:    profile.UnicodeHygiene,
+		MaxFileSize:      maxFileSize,
+		BufferSize:       bufferSize,
+
+		NormalizeShortcodes:  normalize,
+		PreserveMarkdownCode: profile.MarkdownCodeBlocks != MarkdownClean,
+		Structured: types.StructuredPolicy{
+			AllowKeys:   profile.StructuredKeys == StructuredAllow,
+			AllowValues: profile.StructuredValues == StructuredAllow,
+		},
+		Languages:    languageLookup(profile),
+		MarkComments: len(profile.CustomRules) > 0,
+		Sniff: types.SniffConfig{
+			SampleSize:      profile.BinarySampleSize,
+			MaxNullRatio:    profile.BinaryNullRatio,
+			MaxControlRatio: profile.BinaryControlRatio,
+		}.WithDefaults(),
+		Retry: types.RetryConfig{
+			Attempts: profile.ReadRetries,
+			Backoff:  time.Duration(profile.RetryBackoffMs) * time.Millisecond,
+		}.WithDefaults(),
 	}
 }
 
@@ -291,6 +961,26 @@ func MergeProfiles(base, override Profile) Profile {
 	if len(override.EmojiAllowlist) > 0 {
 		result.EmojiAllowlist = override.EmojiAllowlist
 	}
+	if len(override.ReplacementMap) > 0 {
+		merged := make(map[string]string, len(base.ReplacementMap)+len(override.ReplacementMap))
+		for emoji, replacement := range base.ReplacementMap {
+			merged[emoji] = replacement
+		}
+		for emoji, replacement := range override.ReplacementMap {
+			merged[emoji] = replacement
+		}
+		result.ReplacementMap = merged
+	}
+	if len(override.Severity) > 0 {
+		merged := make(map[string]string, len(base.Severity)+len(override.Severity))
+		for key, severity := range base.Severity {
+			merged[key] = severity
+		}
+		for key, severity := range override.Severity {
+			merged[key] = severity
+		}
+		result.Severity = merged
+	}
 	if override.MaxFileSize > 0 {
 		result.MaxFileSize = override.MaxFileSize
 	}
@@ -6,8 +6,12 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+	"github.com/antimoji/antimoji/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -76,6 +80,80 @@ profiles:
 		assert.True(t, result.IsErr())
 	})
 
+	t.Run("loads replacement map with case-sensitive keys", func(t *testing.T) {
+		configContent := `
+profiles:
+  CI:
+    unicode_emojis: true
+    replacement_map:
+      "✅": "[ok]"
+      "🚀": "(launch)"
+      ":D": "[grin]"
+`
+		configPath := filepath.Join(tmpDir, "replacements.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+		result := LoadConfig(configPath)
+		require.True(t, result.IsOk(), "%v", result.Error())
+
+		profile := result.Unwrap().Profiles["ci"]
+		assert.Equal(t, map[string]string{"✅": "[ok]", "🚀": "(launch)", ":D": "[grin]"}, profile.ReplacementMap)
+	})
+
+	t.Run("loads threshold maps with case-sensitive keys", func(t *testing.T) {
+		configContent := `
+profiles:
+  ci:
+    max_per_file: 2
+    evasion_detection: true
+    evasion_threshold: 1
+    directory_thresholds:
+      Docs: 10
+      src/internal: 0
+    emoji_thresholds:
+      "✅": 3
+      ":D": 0
+`
+		configPath := filepath.Join(tmpDir, "thresholds.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+		result := LoadConfig(configPath)
+		require.True(t, result.IsOk(), "%v", result.Error())
+
+		profile := result.Unwrap().Profiles["ci"]
+		assert.Equal(t, 2, profile.MaxPerFile)
+		assert.True(t, profile.EvasionDetection)
+		assert.Equal(t, 1, profile.EvasionThreshold)
+		assert.Equal(t, map[string]int{"Docs": 10, "src/internal": 0}, profile.DirectoryThresholds)
+		assert.Equal(t, map[string]int{"✅": 3, ":D": 0}, profile.EmojiThresholds)
+	})
+
+	t.Run("loads severities with case-sensitive keys", func(t *testing.T) {
+		configContent := `
+profiles:
+  ci:
+    severity:
+      emoticon: warn
+      ":D": error
+      "✅": warn
+`
+		configPath := filepath.Join(tmpDir, "severity.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+		result := LoadConfig(configPath)
+		require.True(t, result.IsOk(), "%v", result.Error())
+		assert.Equal(t, map[string]string{"emoticon": "warn", ":D": "error", "✅": "warn"}, result.Unwrap().Profiles["ci"].Severity)
+	})
+
+	t.Run("rejects unknown severities", func(t *testing.T) {
+		configPath := filepath.Join(tmpDir, "bad-severity.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("profiles:\n  ci:\n    severity:\n      unicode: info\n"), 0644))
+
+		result := LoadConfig(configPath)
+		require.True(t, result.IsErr())
+		assert.Contains(t, result.Error().Error(), `severity of unicode must be "error" or "warn", got "info"`)
+	})
+
 	t.Run("loads empty config file", func(t *testing.T) {
 		configPath := filepath.Join(tmpDir, "empty.yaml")
 		err := os.WriteFile(configPath, []byte{}, 0644)
@@ -90,6 +168,74 @@ profiles:
 	})
 }
 
+func TestLoadConfig_OmittedLimits(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "minimal.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`profiles:
+  minimal:
+    fail_on_found: true
+  explicit:
+    max_file_size: 2048
+    buffer_size: 0
+    respect_gitignore: false
+`), 0644))
+
+	config := LoadConfig(configPath).Unwrap()
+
+	minimal := config.Profiles["minimal"]
+	assert.Equal(t, DefaultMaxFileSize, minimal.MaxFileSize)
+	assert.Equal(t, DefaultBufferSize, minimal.BufferSize)
+	assert.Equal(t, 0, minimal.MaxWorkers)
+	assert.True(t, minimal.RespectGitignore)
+
+	// Explicit values are kept; ToProcessingConfig still guards explicit zeros
+	explicit := config.Profiles["explicit"]
+	assert.Equal(t, int64(2048), explicit.MaxFileSize)
+	assert.Equal(t, 0, explicit.BufferSize)
+	assert.False(t, explicit.RespectGitignore)
+	assert.Equal(t, DefaultBufferSize, ToProcessingConfig(explicit).BufferSize)
+}
+
+func TestLoadConfigStrict(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	valid := filepath.Join(tmpDir, "valid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte("version: \"0.5.0\"\nprofiles:\n  ci:\n    max_workers: 2\n"), 0644))
+	assert.True(t, LoadConfigStrict(valid).IsOk())
+
+	typo := filepath.Join(tmpDir, "typo.yaml")
+	require.NoError(t, os.WriteFile(typo, []byte("profile:\n  ci: {}\nprofiles:\n  ci:\n    max_file_sise: 10\n"), 0644))
+	assert.True(t, LoadConfig(typo).IsOk())
+
+	result := LoadConfigStrict(typo)
+	require.True(t, result.IsErr())
+	assert.EqualError(t, result.Error(), "unknown configuration keys: profile, profiles.ci.max_file_sise")
+}
+
+func TestLoadConfig_InvalidPattern(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`profiles:
+  ci:
+    exclude_patterns: ["vendor/**", "src/[a-"]
+`), 0644))
+
+	result := LoadConfig(configPath)
+	require.True(t, result.IsErr())
+	assert.ErrorIs(t, result.Error(), pathmatch.ErrBadPattern)
+	assert.Contains(t, result.Error().Error(), "profile ci: exclude_patterns")
+}
+
+func TestParseConfig(t *testing.T) {
+	result := ParseConfig([]byte("profiles:\n  ci:\n    emoji_allowlist: [\"✅\"]\n"))
+	require.True(t, result.IsOk())
+	profile := result.Unwrap().Profiles["ci"]
+	assert.Equal(t, []string{"✅"}, profile.EmojiAllowlist)
+	assert.Equal(t, DefaultMaxFileSize, profile.MaxFileSize)
+
+	result = ParseConfig([]byte("profiles:\n  ci:\n    exclude_patterns: [\"[a-\"]\n"))
+	require.True(t, result.IsErr())
+	assert.ErrorIs(t, result.Error(), pathmatch.ErrBadPattern)
+}
+
 func TestDefaultConfig(t *testing.T) {
 	t.Run("returns sensible defaults", func(t *testing.T) {
 		config := DefaultConfig()
@@ -185,6 +331,25 @@ func TestValidateConfig(t *testing.T) {
 }
 
 func TestToProcessingConfig(t *testing.T) {
+	t.Run("maps shortcode policies", func(t *testing.T) {
+		tests := []struct {
+			policy    string
+			enabled   bool
+			normalize types.ShortcodeNormalization
+		}{
+			{"", false, types.NormalizeNone},
+			{ShortcodeIgnore, false, types.NormalizeNone},
+			{ShortcodeDetect, true, types.NormalizeNone},
+			{ShortcodeToUnicode, true, types.NormalizeToUnicode},
+			{ShortcodeToShortcode, true, types.NormalizeToShortcode},
+		}
+		for _, tt := range tests {
+			processingConfig := ToProcessingConfig(Profile{ShortcodePolicy: tt.policy})
+			assert.Equal(t, tt.enabled, processingConfig.EnableShortcodes, tt.policy)
+			assert.Equal(t, tt.normalize, processingConfig.NormalizeShortcodes, tt.policy)
+		}
+	})
+
 	t.Run("converts profile to processing config", func(t *testing.T) {
 		profile := Profile{
 			UnicodeEmojis:  true,
@@ -216,6 +381,43 @@ func TestToProcessingConfig(t *testing.T) {
 		assert.True(t, processingConfig.EnableEmoticons)
 		assert.False(t, processingConfig.EnableCustom) // Should be false for empty patterns
 	})
+
+	t.Run("enables kaomoji and decorative symbols only when opted in", func(t *testing.T) {
+		processingConfig := ToProcessingConfig(Profile{UnicodeEmojis: true})
+		assert.False(t, processingConfig.EnableKaomoji)
+		assert.False(t, processingConfig.EnableDecorative)
+
+		processingConfig = ToProcessingConfig(Profile{UnicodeEmojis: true, Kaomoji: true, DecorativeSymbols: true})
+		assert.True(t, processingConfig.EnableKaomoji)
+		assert.True(t, processingConfig.EnableDecorative)
+	})
+
+	t.Run("enables escaped emojis only when opted in", func(t *testing.T) {
+		assert.False(t, ToProcessingConfig(Profile{UnicodeEmojis: true}).EnableEscapes)
+		assert.True(t, ToProcessingConfig(Profile{UnicodeEmojis: true, EscapedEmojis: true}).EnableEscapes)
+	})
+
+	t.Run("enables evasion detection only when opted in", func(t *testing.T) {
+		assert.False(t, ToProcessingConfig(Profile{UnicodeEmojis: true}).EnableEvasion)
+		assert.True(t, ToProcessingConfig(Profile{UnicodeEmojis: true, EvasionDetection: true}).EnableEvasion)
+	})
+
+	t.Run("enables unicode hygiene only when opted in", func(t *testing.T) {
+		assert.False(t, ToProcessingConfig(Profile{UnicodeEmojis: true}).EnableHygiene)
+		assert.True(t, ToProcessingConfig(Profile{UnicodeEmojis: true, UnicodeHygiene: true}).EnableHygiene)
+	})
+
+	t.Run("maps read retries", func(t *testing.T) {
+		retry := ToProcessingConfig(Profile{ReadRetries: 4, RetryBackoffMs: 250}).Retry
+		assert.Equal(t, types.RetryConfig{Attempts: 4, Backoff: 250 * time.Millisecond}, retry)
+
+		retry = ToProcessingConfig(Profile{}).Retry
+		assert.Equal(t, 0, retry.Attempts)
+		assert.Equal(t, types.DefaultRetryBackoff, retry.Backoff)
+
+		assert.Error(t, validateProfile("default", Profile{ReadRetries: -1}))
+		assert.Error(t, validateProfile("default", Profile{RetryBackoffMs: -1}))
+	})
 }
 
 func TestMergeProfiles(t *testing.T) {
@@ -241,6 +443,15 @@ func TestMergeProfiles(t *testing.T) {
 		assert.Equal(t, "json", merged.OutputFormat) // Overridden
 	})
 
+	t.Run("merges replacement maps with override precedence", func(t *testing.T) {
+		base := Profile{ReplacementMap: map[string]string{"✅": "[ok]", "🚀": "(launch)"}}
+		override := Profile{ReplacementMap: map[string]string{"🚀": "[ship]"}}
+
+		merged := MergeProfiles(base, override)
+		assert.Equal(t, map[string]string{"✅": "[ok]", "🚀": "[ship]"}, merged.ReplacementMap)
+		assert.Equal(t, "(launch)", base.ReplacementMap["🚀"], "base is not modified")
+	})
+
 	t.Run("handles empty override", func(t *testing.T) {
 		base := Profile{
 			Recursive:     true,
@@ -259,6 +470,73 @@ func TestMergeProfiles(t *testing.T) {
 }
 
 func TestValidateProfile(t *testing.T) {
+	t.Run("validates symlink policy", func(t *testing.T) {
+		config := DefaultConfig()
+		profile := config.Profiles["default"]
+		profile.SymlinkPolicy = "always"
+		config.Profiles["default"] = profile
+
+		result := ValidateConfig(config)
+		assert.True(t, result.IsErr())
+		assert.Contains(t, result.Error().Error(), "invalid symlink policy")
+
+		profile.SymlinkPolicy = SymlinkReport
+		profile.MaxSymlinkDepth = -1
+		config.Profiles["default"] = profile
+		assert.Contains(t, ValidateConfig(config).Error().Error(), "max symlink depth")
+	})
+
+	t.Run("validates shortcode policy", func(t *testing.T) {
+		config := DefaultConfig()
+		profile := config.Profiles["default"]
+		profile.ShortcodePolicy = "rewrite"
+		config.Profiles["default"] = profile
+		assert.Contains(t, ValidateConfig(config).Error().Error(), "invalid shortcode_policy")
+	})
+
+	t.Run("validates hardlink policy", func(t *testing.T) {
+		config := DefaultConfig()
+		profile := config.Profiles["default"]
+		profile.HardlinkPolicy = HardlinkInPlace
+		config.Profiles["default"] = profile
+		assert.True(t, ValidateConfig(config).IsOk())
+
+		profile.HardlinkPolicy = "copy"
+		config.Profiles["default"] = profile
+		assert.Contains(t, ValidateConfig(config).Error().Error(), "invalid hardlink_policy")
+	})
+
+	t.Run("validates markdown code blocks", func(t *testing.T) {
+		config := DefaultConfig()
+		profile := config.Profiles["default"]
+		assert.True(t, ToProcessingConfig(profile).PreserveMarkdownCode, "unset preserves code")
+
+		profile.MarkdownCodeBlocks = MarkdownClean
+		config.Profiles["default"] = profile
+		assert.True(t, ValidateConfig(config).IsOk())
+		assert.False(t, ToProcessingConfig(profile).PreserveMarkdownCode)
+
+		profile.MarkdownCodeBlocks = "strip"
+		config.Profiles["default"] = profile
+		assert.Contains(t, ValidateConfig(config).Error().Error(), "invalid markdown_code_blocks")
+	})
+
+	t.Run("validates structured keys and values", func(t *testing.T) {
+		config := DefaultConfig()
+		profile := config.Profiles["default"]
+		assert.True(t, ToProcessingConfig(profile).Structured.IsZero(), "unset checks keys and values")
+
+		profile.StructuredValues = StructuredAllow
+		profile.StructuredKeys = StructuredCheck
+		config.Profiles["default"] = profile
+		assert.True(t, ValidateConfig(config).IsOk())
+		assert.Equal(t, types.StructuredPolicy{AllowValues: true}, ToProcessingConfig(profile).Structured)
+
+		profile.StructuredKeys = "ignore"
+		config.Profiles["default"] = profile
+		assert.Contains(t, ValidateConfig(config).Error().Error(), "invalid structured_keys")
+	})
+
 	t.Run("validates output format", func(t *testing.T) {
 		config := DefaultConfig()
 		profile := config.Profiles["default"]
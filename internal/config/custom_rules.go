@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/core/rules"
+)
+
+// CustomRule is a CEL expression over each violation and the file holding it. A scan
+// fails when the rule matches more violations than Max, e.g. for
+//
+//	"@acme/payments" in file.owners && !finding.in_comment
+//
+// which keeps the payments team's code free of emojis outside comments.
+type CustomRule struct {
+	// Name identifies the rule in failures
+	Name string `yaml:"name" json:"name"`
+
+	// Expression is the CEL expression, evaluating to a bool with the variables finding
+	// and file
+	Expression string `yaml:"expression" json:"expression"`
+
+	// Max is the number of violations the rule tolerates across a run
+	Max int `yaml:"max,omitempty" json:"max,omitempty"`
+
+	// Message explains the rule when it fails
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// validateCustomRule checks a custom rule listed by a profile, compiling its
+// expression.
+func validateCustomRule(rule CustomRule) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("custom rule without a name")
+	}
+	if rule.Max < 0 {
+		return fmt.Errorf("custom rule %s: max cannot be negative", rule.Name)
+	}
+	if strings.TrimSpace(rule.Expression) == "" {
+		return fmt.Errorf("custom rule %s without an expression", rule.Name)
+	}
+	if _, err := rules.Compile(rule.Expression); err != nil {
+		return fmt.Errorf("custom rule %s: %w", rule.Name, err)
+	}
+	return nil
+}
+
+// validateCustomRules checks the custom rules listed by a profile, whose names must
+// be unique.
+func validateCustomRules(name string, profile Profile) error {
+	seen := make(map[string]bool, len(profile.CustomRules))
+	for _, rule := range profile.CustomRules {
+		if err := validateCustomRule(rule); err != nil {
+			return fmt.Errorf("profile %s: custom_rules: %w", name, err)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("profile %s: custom_rules: %s is listed twice", name, rule.Name)
+		}
+		seen[rule.Name] = true
+	}
+	return nil
+}
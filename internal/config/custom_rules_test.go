@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileCustomRules(t *testing.T) {
+	content := `profiles:
+  ci:
+    custom_rules:
+      - name: payments
+        expression: '"@acme/payments" in file.owners && !finding.in_comment'
+        message: Payments code ships without emojis
+      - name: docs
+        expression: file.ext == ".md"
+        max: 10
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	config := LoadConfigStrict(configPath).Unwrap()
+	require.True(t, ValidateConfig(config).IsOk())
+	assert.Equal(t, []CustomRule{
+		{Name: "payments", Expression: `"@acme/payments" in file.owners && !finding.in_comment`, Message: "Payments code ships without emojis"},
+		{Name: "docs", Expression: `file.ext == ".md"`, Max: 10},
+	}, config.Profiles["ci"].CustomRules)
+	assert.True(t, ToProcessingConfig(config.Profiles["ci"]).MarkComments)
+}
+
+func TestValidateCustomRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []CustomRule
+		err   string
+	}{
+		{"valid", []CustomRule{{Name: "a", Expression: "finding.line > 10"}}, ""},
+		{"no name", []CustomRule{{Expression: "true"}}, "custom rule without a name"},
+		{"no expression", []CustomRule{{Name: "a"}}, "custom rule a without an expression"},
+		{"negative max", []CustomRule{{Name: "a", Expression: "true", Max: -1}}, "max cannot be negative"},
+		{"unknown field", []CustomRule{{Name: "a", Expression: "file.owner == 'x'"}}, "undefined field 'owner'"},
+		{"not a bool", []CustomRule{{Name: "a", Expression: "file.path"}}, "not a bool"},
+		{"duplicate", []CustomRule{{Name: "a", Expression: "true"}, {Name: "a", Expression: "false"}}, "a is listed twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCustomRules("ci", Profile{CustomRules: tt.rules})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+			assert.ErrorContains(t, err, "profile ci: custom_rules")
+		})
+	}
+}
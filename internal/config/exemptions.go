@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+)
+
+// ExemptionDateLayout is the layout of the expires date of an exemption.
+const ExemptionDateLayout = "2006-01-02"
+
+// Exemption allows the findings in the files matching Path, or only those of one emoji
+// or on one line of them, until the end of the day it expires. Staged migrations list
+// what may stay for now, with a reason for the audit trail.
+type Exemption struct {
+	// Path is a pattern such as docs/legacy/** matched against the whole path, or such
+	// as *.md matched against the file name
+	Path string `yaml:"path" json:"path"`
+
+	// Emoji and Line narrow the exemption to one emoji or one line (1-based)
+	Emoji string `yaml:"emoji,omitempty" json:"emoji,omitempty"`
+	Line  int    `yaml:"line,omitempty" json:"line,omitempty"`
+
+	// Expires is the last day the exemption applies, as YYYY-MM-DD
+	Expires string `yaml:"expires" json:"expires"`
+	Reason  string `yaml:"reason" json:"reason"`
+}
+
+// ExpiresAt returns the moment the exemption stops applying: the start of the day
+// after Expires, in local time.
+func (x Exemption) ExpiresAt() (time.Time, error) {
+	day, err := time.ParseInLocation(ExemptionDateLayout, x.Expires, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expires %q is not a date (YYYY-MM-DD)", x.Expires)
+	}
+	return day.AddDate(0, 0, 1), nil
+}
+
+// String describes the exemption, e.g. "docs/** (🚀, line 3)".
+func (x Exemption) String() string {
+	var scope []string
+	if x.Emoji != "" {
+		scope = append(scope, x.Emoji)
+	}
+	if x.Line > 0 {
+		scope = append(scope, fmt.Sprintf("line %d", x.Line))
+	}
+	if len(scope) == 0 {
+		return x.Path
+	}
+	return fmt.Sprintf("%s (%s)", x.Path, strings.Join(scope, ", "))
+}
+
+// validateExemption checks an exemption listed by a profile.
+func validateExemption(exemption Exemption) error {
+	if strings.TrimSpace(exemption.Path) == "" {
+		return fmt.Errorf("exemption without a path")
+	}
+	if err := pathmatch.Validate(exemption.Path); err != nil {
+		return fmt.Errorf("exemption %s: %w", exemption.Path, err)
+	}
+	if exemption.Line < 0 {
+		return fmt.Errorf("exemption %s: line cannot be negative", exemption.Path)
+	}
+	if _, err := exemption.ExpiresAt(); err != nil {
+		return fmt.Errorf("exemption %s: %w", exemption.Path, err)
+	}
+	if strings.TrimSpace(exemption.Reason) == "" {
+		return fmt.Errorf("exemption %s: give a reason", exemption.Path)
+	}
+	return nil
+}
+
+// validateExemptions checks the exemptions listed by a profile.
+func validateExemptions(name string, profile Profile) error {
+	for _, exemption := range profile.Exemptions {
+		if err := validateExemption(exemption); err != nil {
+			return fmt.Errorf("profile %s: exemptions: %w", name, err)
+		}
+	}
+	return nil
+}
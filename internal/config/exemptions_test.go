@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileExemptions(t *testing.T) {
+	content := `profiles:
+  default:
+    exemptions:
+      - path: "docs/legacy/**"
+        expires: 2026-06-30
+        reason: Docs move to the new site in Q2
+      - path: cmd/main.go
+        emoji: "🚀"
+        line: 12
+        expires: "2026-12-31"
+        reason: Release banner, see TICKET-42
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	config := LoadConfigStrict(configPath).Unwrap()
+	require.True(t, ValidateConfig(config).IsOk())
+	assert.Equal(t, []Exemption{
+		{Path: "docs/legacy/**", Expires: "2026-06-30", Reason: "Docs move to the new site in Q2"},
+		{Path: "cmd/main.go", Emoji: "🚀", Line: 12, Expires: "2026-12-31", Reason: "Release banner, see TICKET-42"},
+	}, config.Profiles["default"].Exemptions)
+
+	expiresAt, err := config.Profiles["default"].Exemptions[0].ExpiresAt()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.Local), expiresAt)
+}
+
+func TestValidateExemptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		exemption Exemption
+		wantErr   string
+	}{
+		{"valid", Exemption{Path: "*.md", Expires: "2026-01-31", Reason: "migration"}, ""},
+		{"missing path", Exemption{Expires: "2026-01-31", Reason: "migration"}, "exemption without a path"},
+		{"bad pattern", Exemption{Path: "[docs", Expires: "2026-01-31", Reason: "migration"}, "invalid glob pattern"},
+		{"missing date", Exemption{Path: "docs/**", Reason: "migration"}, `expires "" is not a date (YYYY-MM-DD)`},
+		{"bad date", Exemption{Path: "docs/**", Expires: "31/01/2026", Reason: "migration"}, `expires "31/01/2026" is not a date`},
+		{"missing reason", Exemption{Path: "docs/**", Expires: "2026-01-31"}, "exemption docs/**: give a reason"},
+		{"negative line", Exemption{Path: "docs/**", Line: -1, Expires: "2026-01-31", Reason: "migration"}, "line cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			profile := config.Profiles["default"]
+			profile.Exemptions = []Exemption{tt.exemption}
+			config.Profiles["default"] = profile
+
+			result := ValidateConfig(config)
+			if tt.wantErr == "" {
+				assert.True(t, result.IsOk())
+				return
+			}
+			require.True(t, result.IsErr())
+			assert.Contains(t, result.Error().Error(), tt.wantErr)
+		})
+	}
+}
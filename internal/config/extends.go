@@ -0,0 +1,279 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/infra/httpclient"
+	"github.com/antimoji/antimoji/internal/infra/remoteconfig"
+	"gopkg.in/yaml.v3"
+)
+
+// maxExtendsDepth bounds how many configuration files may extend each other in a chain.
+const maxExtendsDepth = 8
+
+// newFetcher returns the fetcher for remote configuration files, caching them below the
+// result cache directory and trusting the certificates of caFile. Tests replace it.
+var newFetcher = func(caFile string) (*remoteconfig.Fetcher, error) {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	client, err := httpclient.New(caFile, 0)
+	if err != nil {
+		return nil, err
+	}
+	offline, _ := strconv.ParseBool(os.Getenv(remoteconfig.EnvOffline))
+	// Downloads are bounded by the fetcher
+	return remoteconfig.New(filepath.Join(dir, "config"), offline).WithClient(client), nil
+}
+
+// configSource is a configuration file with its extends chain resolved.
+type configSource struct {
+	// content is the merged configuration
+	content []byte
+	// local is the file's own content
+	local []byte
+	// warnings report cached copies used in place of failed downloads
+	warnings []string
+}
+
+// readConfigSource reads a local or remote configuration file and merges the files it
+// extends beneath it.
+func readConfigSource(path string) (configSource, error) {
+	r := &extendsResolver{active: make(map[string]bool), caFile: httpclient.CAFile("")}
+	content, err := r.read(path)
+	if err != nil {
+		return configSource{}, err
+	}
+	// The files a local configuration extends are fetched with its CA file
+	if !remoteconfig.IsRemote(path) {
+		if settings, err := loadHTTP(content); err == nil {
+			r.caFile = httpclient.CAFile(settings.CAFile)
+		}
+	}
+
+	merged, err := r.resolve(path, content, 0)
+	if err != nil {
+		return configSource{}, err
+	}
+	return configSource{content: merged, local: content, warnings: r.warnings}, nil
+}
+
+// localSources returns the local files the configuration at path is read from: path and
+// the local files of its extends chain. Remote files are left out, as are the files
+// below one that cannot be read or parsed.
+func localSources(path string) []string {
+	var sources []string
+	seen := make(map[string]bool)
+	var walk func(location string, depth int)
+	walk = func(location string, depth int) {
+		key := sourceKey(location)
+		if seen[key] || depth > maxExtendsDepth {
+			return
+		}
+		seen[key] = true
+		sources = append(sources, location)
+
+		content, err := os.ReadFile(location) // #nosec G304 - config path is user-provided by design
+		if err != nil {
+			return
+		}
+		refs, err := extendsRefs(content)
+		if err != nil {
+			return
+		}
+		for _, ref := range refs {
+			if next, err := extendsLocation(location, ref); err == nil && !remoteconfig.IsRemote(next) {
+				walk(next, depth+1)
+			}
+		}
+	}
+	walk(path, 0)
+	return sources
+}
+
+// extendsResolver follows extends references, detecting cycles.
+type extendsResolver struct {
+	fetcher  *remoteconfig.Fetcher
+	caFile   string
+	active   map[string]bool
+	warnings []string
+}
+
+// read returns the content of a local path or remote reference.
+func (r *extendsResolver) read(ref string) ([]byte, error) {
+	if !remoteconfig.IsRemote(ref) {
+		return os.ReadFile(ref) // #nosec G304 - config path is user-provided by design
+	}
+
+	if r.fetcher == nil {
+		fetcher, err := newFetcher(r.caFile)
+		if err != nil {
+			return nil, err
+		}
+		r.fetcher = fetcher
+	}
+	result, err := r.fetcher.Fetch(context.Background(), ref)
+	if err != nil {
+		return nil, err
+	}
+	if result.Stale != nil {
+		r.warnings = append(r.warnings, fmt.Sprintf("using cached copy of %s: %v", ref, result.Stale))
+	}
+	return result.Content, nil
+}
+
+// resolve returns content with the files named by its extends key merged beneath it.
+// from is where content was read, against which relative local paths are resolved.
+func (r *extendsResolver) resolve(from string, content []byte, depth int) ([]byte, error) {
+	refs, err := extendsRefs(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", from, err)
+	}
+	if len(refs) == 0 {
+		return content, nil
+	}
+	if depth >= maxExtendsDepth {
+		return nil, fmt.Errorf("%s: extends chain is deeper than %d files", from, maxExtendsDepth)
+	}
+
+	key := sourceKey(from)
+	r.active[key] = true
+	defer delete(r.active, key)
+
+	merged := map[string]interface{}{}
+	for _, ref := range refs {
+		location, err := extendsLocation(from, ref)
+		if err != nil {
+			return nil, err
+		}
+		if r.active[sourceKey(location)] {
+			return nil, fmt.Errorf("%s: extends cycle through %s", from, ref)
+		}
+
+		base, err := r.read(location)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to load extended config %s: %w", from, ref, err)
+		}
+		base, err = r.resolve(location, base, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if merged, err = mergeConfigContent(merged, base); err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+	}
+
+	merged, err = mergeConfigContent(merged, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", from, err)
+	}
+	delete(merged, "extends")
+
+	return yaml.Marshal(merged)
+}
+
+// extendsRefs returns the references of the extends key, a string or a list of strings.
+func extendsRefs(content []byte) ([]string, error) {
+	var raw struct {
+		Extends interface{} `yaml:"extends"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	switch extends := raw.Extends.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{extends}, nil
+	case []interface{}:
+		refs := make([]string, 0, len(extends))
+		for _, item := range extends {
+			ref, ok := item.(string)
+			if !ok || ref == "" {
+				return nil, fmt.Errorf("extends must be a string or a list of strings")
+			}
+			refs = append(refs, ref)
+		}
+		return refs, nil
+	default:
+		return nil, fmt.Errorf("extends must be a string or a list of strings")
+	}
+}
+
+// extendsLocation resolves ref relative to the file that names it. Remote files may
+// only extend other remote files.
+func extendsLocation(from, ref string) (string, error) {
+	if remoteconfig.IsRemote(ref) || filepath.IsAbs(ref) {
+		return ref, nil
+	}
+	if remoteconfig.IsRemote(from) {
+		return "", fmt.Errorf("%s: relative extends %q is not allowed in a remote config", from, ref)
+	}
+	return filepath.Join(filepath.Dir(from), ref), nil
+}
+
+// sourceKey identifies a configuration file for cycle detection.
+func sourceKey(location string) string {
+	if remoteconfig.IsRemote(location) {
+		ref, _, _ := strings.Cut(location, "#")
+		return ref
+	}
+	if abs, err := filepath.Abs(location); err == nil {
+		return abs
+	}
+	return location
+}
+
+// mergeConfigContent decodes content and merges it over base: top-level keys replace
+// base keys, and each profile's fields replace the same fields of the base profile, so
+// a file only needs to list the settings it changes.
+func mergeConfigContent(base map[string]interface{}, content []byte) (map[string]interface{}, error) {
+	var overlay map[string]interface{}
+	if err := yaml.Unmarshal(content, &overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	for key, value := range overlay {
+		if key != "profiles" {
+			base[key] = value
+			continue
+		}
+
+		profiles, ok := value.(map[string]interface{})
+		if !ok {
+			base[key] = value
+			continue
+		}
+		merged, _ := base["profiles"].(map[string]interface{})
+		if merged == nil {
+			merged = make(map[string]interface{}, len(profiles))
+		}
+		for name, fields := range profiles {
+			overlayFields, ok := fields.(map[string]interface{})
+			baseFields, baseOK := merged[name].(map[string]interface{})
+			if !ok || !baseOK {
+				merged[name] = fields
+				continue
+			}
+			combined := make(map[string]interface{}, len(baseFields)+len(overlayFields))
+			for field, v := range baseFields {
+				combined[field] = v
+			}
+			for field, v := range overlayFields {
+				combined[field] = v
+			}
+			merged[name] = combined
+		}
+		base["profiles"] = merged
+	}
+
+	return base, nil
+}
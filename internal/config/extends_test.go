@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/cache"
+	"github.com/antimoji/antimoji/internal/infra/httpclient"
+	"github.com/antimoji/antimoji/internal/infra/remoteconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_Extends(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(`profiles:
+  default:
+    max_emoji_threshold: 0
+    emoji_allowlist: ["✅"]
+  ci:
+    fail_on_found: true
+`), 0600))
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: 2
+extends: base.yaml
+profiles:
+  default:
+    max_emoji_threshold: 3
+`), 0600))
+
+	cfg := LoadConfig(path).Unwrap()
+	assert.Equal(t, 3, cfg.Profiles["default"].MaxEmojiThreshold, "the extending file wins")
+	assert.Equal(t, []string{"✅"}, cfg.Profiles["default"].EmojiAllowlist, "unset fields come from the base")
+	assert.True(t, cfg.Profiles["ci"].FailOnFound, "base profiles are inherited")
+
+	assert.True(t, LoadConfigStrict(path).IsOk(), "extends is a known key")
+
+	resolution := LoadResolution(path, "default").Unwrap()
+	assert.Equal(t, SourceFile, resolution.Sources["emoji_allowlist"])
+}
+
+func TestLoadConfig_ExtendsErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	write("a.yaml", "extends: b.yaml\n")
+	cycle := write("b.yaml", "extends: [a.yaml]\n")
+	assert.ErrorContains(t, LoadConfig(cycle).Error(), "extends cycle")
+
+	missing := write("missing.yaml", "extends: nowhere.yaml\n")
+	assert.ErrorContains(t, LoadConfig(missing).Error(), "failed to load extended config nowhere.yaml")
+
+	invalid := write("invalid.yaml", "extends: {a: b}\n")
+	assert.ErrorContains(t, LoadConfig(invalid).Error(), "extends must be a string or a list of strings")
+}
+
+func TestLoadConfig_Remote(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("profiles:\n  default:\n    max_emoji_threshold: 0\n    emoji_allowlist: [\"✅\"]\n"))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	offline := false
+	original := newFetcher
+	newFetcher = func(string) (*remoteconfig.Fetcher, error) {
+		return remoteconfig.New(cacheDir, offline).WithClient(server.Client()), nil
+	}
+	defer func() { newFetcher = original }()
+
+	ref := server.URL + "/antimoji.yaml"
+	cfg := LoadConfig(ref).Unwrap()
+	assert.Equal(t, []string{"✅"}, cfg.Profiles["default"].EmojiAllowlist)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("version: 2\nextends: "+ref+"\nprofiles:\n  default:\n    max_emoji_threshold: 2\n"), 0600))
+	cfg = LoadConfig(path).Unwrap()
+	assert.Equal(t, 2, cfg.Profiles["default"].MaxEmojiThreshold)
+	assert.Equal(t, []string{"✅"}, cfg.Profiles["default"].EmojiAllowlist)
+
+	offline = true
+	server.Close()
+	assert.True(t, LoadConfig(path).IsOk(), "offline resolution uses the cached copy")
+	assert.ErrorIs(t, LoadConfig(server.URL+"/other.yaml").Error(), remoteconfig.ErrNotCached)
+}
+
+func TestLoadConfig_RemoteCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("profiles:\n  default:\n    emoji_allowlist: [\"✅\"]\n"))
+	}))
+	defer server.Close()
+	t.Setenv(cache.EnvDir, t.TempDir())
+	t.Setenv(httpclient.EnvCAFile, "")
+	t.Setenv(remoteconfig.EnvOffline, "")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("extends: "+server.URL+"/base.yaml\nprofiles:\n  default: {}\n"), 0600))
+	result := LoadConfig(path)
+	require.True(t, result.IsErr(), "the test server's certificate is not trusted by default")
+
+	caFile := filepath.Join(dir, "ca.pem")
+	cert := server.TLS.Certificates[0].Certificate[0]
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600))
+	require.NoError(t, os.WriteFile(path, []byte("extends: "+server.URL+"/base.yaml\nhttp:\n  ca_file: "+caFile+"\nprofiles:\n  default: {}\n"), 0600))
+	result = LoadConfig(path)
+	require.True(t, result.IsOk(), "%v", result.Error())
+	cfg := result.Unwrap()
+	assert.Equal(t, []string{"✅"}, cfg.Profiles["default"].EmojiAllowlist)
+	assert.Equal(t, caFile, cfg.HTTP.CAFile)
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HTTPConfig holds the network settings of downloads: remote configuration files
+// and releases for 'antimoji upgrade'. Proxies come from HTTPS_PROXY and NO_PROXY.
+type HTTPConfig struct {
+	// CAFile is a PEM file of certificate authorities trusted besides the system
+	// ones; $ANTIMOJI_CA_FILE takes precedence
+	CAFile string `yaml:"ca_file,omitempty" json:"ca_file,omitempty"`
+}
+
+// httpFields are the keys of the http section.
+var httpFields = map[string]bool{"ca_file": true}
+
+// loadHTTP decodes the http section of the raw YAML.
+func loadHTTP(content []byte) (HTTPConfig, error) {
+	var raw struct {
+		HTTP HTTPConfig `yaml:"http"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return HTTPConfig{}, fmt.Errorf("failed to parse http settings: %w", err)
+	}
+	return raw.HTTP, nil
+}
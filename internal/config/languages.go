@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/lang"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// LanguageConfig registers a language for files the built-in languages do not know, or
+// replaces the built-in language of the same name (see lang.Registry.Register).
+type LanguageConfig struct {
+	Name string `yaml:"name" json:"name"`
+
+	// Files of the language: by extension (with the leading dot), whole file name or
+	// shebang interpreter
+	Extensions   []string `yaml:"extensions,omitempty" json:"extensions,omitempty"`
+	Filenames    []string `yaml:"filenames,omitempty" json:"filenames,omitempty"`
+	Interpreters []string `yaml:"interpreters,omitempty" json:"interpreters,omitempty"`
+
+	// Comments, in which antimoji:off and antimoji:on markers count, and string quotes
+	LineComments     []string             `yaml:"line_comments,omitempty" json:"line_comments,omitempty"`
+	BlockComments    []BlockCommentConfig `yaml:"block_comments,omitempty" json:"block_comments,omitempty"`
+	StringDelimiters []string             `yaml:"string_delimiters,omitempty" json:"string_delimiters,omitempty"`
+
+	// Escapes decoded when escaped_emojis is on: html_entity, utf16, unicode_braces or
+	// unicode_long
+	Escapes []string `yaml:"escapes,omitempty" json:"escapes,omitempty"`
+
+	// CodeFences treats the files like Markdown for markdown_code_blocks
+	CodeFences bool `yaml:"code_fences,omitempty" json:"code_fences,omitempty"`
+}
+
+// BlockCommentConfig holds the delimiters of a block comment.
+type BlockCommentConfig struct {
+	Start string `yaml:"start" json:"start"`
+	End   string `yaml:"end" json:"end"`
+}
+
+// Language converts the configuration to a language descriptor. Unknown escape names
+// are ignored; validateLanguages reports them.
+func (c LanguageConfig) Language() types.Language {
+	escapes, _ := lang.ParseEscapes(c.Escapes)
+	language := types.Language{
+		Name:             c.Name,
+		Extensions:       c.Extensions,
+		Filenames:        c.Filenames,
+		Interpreters:     c.Interpreters,
+		Comments:         types.CommentSyntax{Line: c.LineComments},
+		StringDelimiters: c.StringDelimiters,
+		Escapes:          escapes,
+		CodeFences:       c.CodeFences,
+	}
+	for _, block := range c.BlockComments {
+		language.Comments.Block = append(language.Comments.Block, types.BlockComment{Start: block.Start, End: block.End})
+	}
+	return language
+}
+
+// validateLanguage checks a language registered by a profile.
+func validateLanguage(language LanguageConfig) error {
+	if strings.TrimSpace(language.Name) == "" {
+		return fmt.Errorf("language without a name")
+	}
+	if len(language.Extensions)+len(language.Filenames)+len(language.Interpreters) == 0 {
+		return fmt.Errorf("language %s: set extensions, filenames or interpreters", language.Name)
+	}
+	for _, extension := range language.Extensions {
+		if !strings.HasPrefix(extension, ".") || len(extension) < 2 {
+			return fmt.Errorf("language %s: extension %q must start with a dot", language.Name, extension)
+		}
+	}
+	for _, block := range language.BlockComments {
+		if block.Start == "" || block.End == "" {
+			return fmt.Errorf("language %s: block comments need a start and an end", language.Name)
+		}
+	}
+	if _, err := lang.ParseEscapes(language.Escapes); err != nil {
+		return fmt.Errorf("language %s: %w", language.Name, err)
+	}
+	return nil
+}
+
+// validateLanguages checks the languages registered by a profile.
+func validateLanguages(name string, profile Profile) error {
+	seen := make(map[string]bool, len(profile.Languages))
+	for _, language := range profile.Languages {
+		if err := validateLanguage(language); err != nil {
+			return fmt.Errorf("profile %s: languages: %w", name, err)
+		}
+		if seen[language.Name] {
+			return fmt.Errorf("profile %s: languages: language %s is registered twice", name, language.Name)
+		}
+		seen[language.Name] = true
+	}
+	return nil
+}
+
+// languageLookup returns the registry of the built-in languages and those registered by
+// profile, or nil for the built-in languages alone.
+func languageLookup(profile Profile) types.LanguageLookup {
+	if len(profile.Languages) == 0 {
+		return nil
+	}
+	languages := make([]types.Language, 0, len(profile.Languages))
+	for _, language := range profile.Languages {
+		languages = append(languages, language.Language())
+	}
+	return lang.Default().With(languages...)
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileLanguages(t *testing.T) {
+	content := `profiles:
+  default:
+    unicode_emojis: true
+    languages:
+      - name: hcl
+        extensions: [".tf", ".hcl"]
+        line_comments: ["#", "//"]
+        block_comments:
+          - {start: "/*", end: "*/"}
+        string_delimiters: ['"']
+        escapes: [utf16, unicode_long]
+      - name: asciidoc
+        extensions: [".adoc"]
+        code_fences: true
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	config := LoadConfigStrict(configPath).Unwrap()
+	profile := config.Profiles["default"]
+	require.Len(t, profile.Languages, 2)
+	require.True(t, ValidateConfig(config).IsOk())
+
+	languages := ToProcessingConfig(profile).Languages
+	require.NotNil(t, languages)
+	hcl, ok := languages.Lookup("infra/main.tf", nil)
+	require.True(t, ok)
+	assert.Equal(t, types.Language{
+		Name:       "hcl",
+		Extensions: []string{".tf", ".hcl"},
+		Comments: types.CommentSyntax{
+			Line:  []string{"#", "//"},
+			Block: []types.BlockComment{{Start: "/*", End: "*/"}},
+		},
+		StringDelimiters: []string{`"`},
+		Escapes:          types.EscapeUTF16 | types.EscapeUnicodeLong,
+	}, hcl)
+
+	asciidoc, _ := languages.Lookup("guide.adoc", nil)
+	assert.True(t, asciidoc.CodeFences)
+
+	// Built-in languages are still known
+	golang, ok := languages.Lookup("main.go", nil)
+	require.True(t, ok)
+	assert.Equal(t, "go", golang.Name)
+
+	assert.Nil(t, ToProcessingConfig(DefaultConfig().Profiles["default"]).Languages, "no languages uses the built-in ones")
+}
+
+func TestValidateLanguages(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages []LanguageConfig
+		wantErr   string
+	}{
+		{"valid", []LanguageConfig{{Name: "hcl", Filenames: []string{"Terrafile"}}}, ""},
+		{"missing name", []LanguageConfig{{Extensions: []string{".tf"}}}, "language without a name"},
+		{"no files", []LanguageConfig{{Name: "hcl"}}, "language hcl: set extensions, filenames or interpreters"},
+		{"extension without dot", []LanguageConfig{{Name: "hcl", Extensions: []string{"tf"}}}, `extension "tf" must start with a dot`},
+		{"incomplete block comment", []LanguageConfig{{Name: "hcl", Extensions: []string{".tf"}, BlockComments: []BlockCommentConfig{{Start: "/*"}}}},
+			"block comments need a start and an end"},
+		{"unknown escape", []LanguageConfig{{Name: "hcl", Extensions: []string{".tf"}, Escapes: []string{"octal"}}}, `unknown escape syntax "octal"`},
+		{"duplicate", []LanguageConfig{{Name: "hcl", Extensions: []string{".tf"}}, {Name: "hcl", Extensions: []string{".hcl"}}},
+			"language hcl is registered twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			profile := config.Profiles["default"]
+			profile.Languages = tt.languages
+			config.Profiles["default"] = profile
+
+			result := ValidateConfig(config)
+			if tt.wantErr == "" {
+				assert.True(t, result.IsOk())
+				return
+			}
+			require.True(t, result.IsErr())
+			assert.Contains(t, result.Error().Error(), tt.wantErr)
+		})
+	}
+}
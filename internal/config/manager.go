@@ -0,0 +1,362 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/remoteconfig"
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce groups the burst of events editors produce when saving a file.
+const reloadDebounce = 100 * time.Millisecond
+
+// Snapshot is an immutable view of the active configuration. Subscribers receive a new
+// snapshot whenever the configuration file or the active profile changes; snapshots
+// must not be modified.
+type Snapshot struct {
+	Config      Config
+	ProfileName string
+	Profile     Profile
+	// Version increases with every change, so consumers can discard stale work.
+	Version uint64
+}
+
+// Loader loads a configuration file for a Manager.
+type Loader func(path string) (Config, error)
+
+// Manager owns the configuration of a long-running process. It reloads the
+// configuration when the file or a local file of its extends chain changes, supports switching the active profile, and
+// notifies subscribers so dependent subsystems can re-resolve patterns and allowlists.
+// An invalid edit never replaces a working configuration.
+type Manager struct {
+	path   string
+	loader Loader
+
+	mu       sync.RWMutex
+	snapshot Snapshot
+	// sources holds the state of the local files the configuration was loaded from
+	sources map[string]fileStamp
+
+	subMu       sync.Mutex
+	subscribers map[int]func(Snapshot)
+	nextID      int
+
+	// notifyMu serialises notifications so subscribers observe versions in order
+	notifyMu sync.Mutex
+}
+
+// fileStamp is the state of a configuration file when it was last loaded. A file that
+// did not exist has the zero stamp.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewManager loads the configuration at path and activates profileName. An empty path
+// uses the built-in default configuration, which is never reloaded.
+func NewManager(path, profileName string) (*Manager, error) {
+	return NewManagerWithLoader(path, profileName, loadValidated)
+}
+
+// NewManagerWithLoader is NewManager with the configuration file loaded by loader,
+// for callers that need the loading rules of a command, such as strict mode. Reloads
+// only call loader when the file at path or a local file it extends changed. Remote
+// configuration files cannot be watched and are rejected.
+func NewManagerWithLoader(path, profileName string, loader Loader) (*Manager, error) {
+	if profileName == "" {
+		profileName = "default"
+	}
+	if remoteconfig.IsRemote(path) {
+		return nil, fmt.Errorf("remote config %s cannot be reloaded", path)
+	}
+
+	m := &Manager{
+		path:        path,
+		loader:      loader,
+		subscribers: make(map[int]func(Snapshot)),
+	}
+
+	cfg := DefaultConfig()
+	if path != "" {
+		m.path = filepath.Clean(path)
+		sources := stampSources(m.path)
+		loaded, err := loader(m.path)
+		if err != nil {
+			return nil, err
+		}
+		cfg, m.sources = loaded, sources
+	}
+
+	profileResult := GetProfile(cfg, profileName)
+	if profileResult.IsErr() {
+		return nil, profileResult.Error()
+	}
+
+	m.snapshot = Snapshot{Config: cfg, ProfileName: profileName, Profile: profileResult.Unwrap(), Version: 1}
+	return m, nil
+}
+
+// Current returns the active configuration snapshot.
+func (m *Manager) Current() Snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.snapshot
+}
+
+// Subscribe registers fn to be called with each new snapshot. Calls are made
+// sequentially, in subscription order; fn must not call Reload or SwitchProfile.
+// The returned function unsubscribes.
+func (m *Manager) Subscribe(fn func(Snapshot)) func() {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+
+	id := m.nextID
+	m.nextID++
+	m.subscribers[id] = fn
+
+	return func() {
+		m.subMu.Lock()
+		defer m.subMu.Unlock()
+		delete(m.subscribers, id)
+	}
+}
+
+// SwitchProfile activates another profile of the current configuration.
+func (m *Manager) SwitchProfile(profileName string) error {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+
+	m.mu.Lock()
+	profileResult := GetProfile(m.snapshot.Config, profileName)
+	if profileResult.IsErr() {
+		m.mu.Unlock()
+		return profileResult.Error()
+	}
+	m.snapshot = Snapshot{
+		Config:      m.snapshot.Config,
+		ProfileName: profileName,
+		Profile:     profileResult.Unwrap(),
+		Version:     m.snapshot.Version + 1,
+	}
+	snapshot := m.snapshot
+	m.mu.Unlock()
+
+	m.notify(snapshot)
+	return nil
+}
+
+// Reload re-reads the configuration when the file or a local file it extends changed,
+// and reports whether it was reloaded. If the new
+// configuration is invalid or no longer contains the active profile, the current
+// configuration is kept and an error is returned.
+func (m *Manager) Reload() (bool, error) {
+	if m.path == "" {
+		return false, nil
+	}
+
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+
+	m.mu.RLock()
+	changed := m.sourcesChanged()
+	m.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	// Stamped before loading, so an edit made during the load is seen by the next Reload
+	sources := stampSources(m.path)
+	cfg, err := m.loader(m.path)
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+
+	profileResult := GetProfile(cfg, m.snapshot.ProfileName)
+	if profileResult.IsErr() {
+		m.mu.Unlock()
+		return false, fmt.Errorf("reloaded config: %w", profileResult.Error())
+	}
+
+	m.sources = sources
+	m.snapshot = Snapshot{
+		Config:      cfg,
+		ProfileName: m.snapshot.ProfileName,
+		Profile:     profileResult.Unwrap(),
+		Version:     m.snapshot.Version + 1,
+	}
+	snapshot := m.snapshot
+	m.mu.Unlock()
+
+	m.notify(snapshot)
+	return true, nil
+}
+
+// Watch reloads the configuration whenever the file or a local file it extends changes
+// until ctx is cancelled. The parent directories are watched so that editors which save
+// by renaming are handled, and files added to the extends chain are watched once it is
+// reloaded. Reload errors are passed to onError, which may be nil.
+func (m *Manager) Watch(ctx context.Context, onError func(error)) error {
+	if m.path == "" {
+		<-ctx.Done()
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	watched := make(map[string]bool)
+	watch := func() error {
+		for _, dir := range m.sourceDirs() {
+			if watched[dir] {
+				continue
+			}
+			if err := watcher.Add(dir); err != nil {
+				return fmt.Errorf("failed to watch config directory: %w", err)
+			}
+			watched[dir] = true
+		}
+		return nil
+	}
+	if err := watch(); err != nil {
+		return err
+	}
+
+	report := func(err error) {
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if m.isSource(event.Name) && event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				timer.Reset(reloadDebounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			report(err)
+
+		case <-timer.C:
+			if _, err := os.Stat(m.path); err != nil {
+				// Mid-save; a Create event follows when the file is back
+				continue
+			}
+			reloaded, err := m.Reload()
+			report(err)
+			if reloaded {
+				report(watch())
+			}
+		}
+	}
+}
+
+// sourcesChanged reports whether a source file differs from when it was loaded, or the
+// file at path now extends other files. The caller must hold mu.
+func (m *Manager) sourcesChanged() bool {
+	current := stampSources(m.path)
+	if len(current) != len(m.sources) {
+		return true
+	}
+	for source, stamp := range current {
+		loaded, ok := m.sources[source]
+		if !ok || !stamp.modTime.Equal(loaded.modTime) || stamp.size != loaded.size {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceDirs returns the directories holding the source files.
+func (m *Manager) sourceDirs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for source := range m.sources {
+		dir := filepath.Dir(source)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// isSource reports whether name is one of the source files.
+func (m *Manager) isSource(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.sources[sourceKey(name)]
+	return ok
+}
+
+// stampSources records the state of path and the local files of its extends chain,
+// keyed by absolute path.
+func stampSources(path string) map[string]fileStamp {
+	sources := make(map[string]fileStamp)
+	for _, source := range localSources(path) {
+		var stamp fileStamp
+		if info, err := os.Stat(source); err == nil {
+			stamp = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+		sources[sourceKey(source)] = stamp
+	}
+	return sources
+}
+
+// loadValidated loads and validates the configuration file at path with the files it
+// extends.
+func loadValidated(path string) (Config, error) {
+	configResult := LoadConfig(path)
+	if configResult.IsErr() {
+		return Config{}, fmt.Errorf("failed to load config: %w", configResult.Error())
+	}
+
+	validated := ValidateConfig(configResult.Unwrap())
+	if validated.IsErr() {
+		return Config{}, fmt.Errorf("invalid config: %w", validated.Error())
+	}
+
+	return validated.Unwrap(), nil
+}
+
+// notify calls every subscriber with snapshot.
+func (m *Manager) notify(snapshot Snapshot) {
+	m.subMu.Lock()
+	subscribers := make([]func(Snapshot), 0, len(m.subscribers))
+	for id := 0; id < m.nextID; id++ {
+		if fn, ok := m.subscribers[id]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	m.subMu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(snapshot)
+	}
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const managerTestConfig = `profiles:
+  default:
+    unicode_emojis: true
+    emoji_allowlist: ["✅"]
+  strict:
+    unicode_emojis: true
+    max_emoji_threshold: 0
+`
+
+func writeManagerConfig(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestNewManager(t *testing.T) {
+	t.Run("uses defaults without a file", func(t *testing.T) {
+		m, err := NewManager("", "")
+		require.NoError(t, err)
+
+		snapshot := m.Current()
+		assert.Equal(t, "default", snapshot.ProfileName)
+		assert.Equal(t, uint64(1), snapshot.Version)
+
+		changed, err := m.Reload()
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("loads file and profile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".antimoji.yaml")
+		writeManagerConfig(t, path, managerTestConfig)
+
+		m, err := NewManager(path, "default")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"✅"}, m.Current().Profile.EmojiAllowlist)
+	})
+
+	t.Run("rejects unknown profile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".antimoji.yaml")
+		writeManagerConfig(t, path, managerTestConfig)
+
+		_, err := NewManager(path, "missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects remote config", func(t *testing.T) {
+		_, err := NewManager("https://example.com/antimoji.yaml", "default")
+		assert.ErrorContains(t, err, "cannot be reloaded")
+	})
+}
+
+func TestNewManagerWithLoader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".antimoji.yaml")
+	writeManagerConfig(t, path, managerTestConfig)
+
+	loads := 0
+	m, err := NewManagerWithLoader(path, "default", func(path string) (Config, error) {
+		loads++
+		return LoadConfig(path).Unwrap(), nil
+	})
+	require.NoError(t, err)
+
+	changed, err := m.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, 1, loads, "an unchanged file is not loaded again")
+
+	writeManagerConfig(t, path, managerTestConfig+"    max_per_file: 1\n")
+	changed, err = m.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 2, loads)
+}
+
+func TestManager_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".antimoji.yaml")
+	writeManagerConfig(t, path, managerTestConfig)
+
+	m, err := NewManager(path, "default")
+	require.NoError(t, err)
+
+	var received []Snapshot
+	unsubscribe := m.Subscribe(func(s Snapshot) { received = append(received, s) })
+
+	t.Run("unchanged file does not notify", func(t *testing.T) {
+		changed, err := m.Reload()
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Empty(t, received)
+	})
+
+	t.Run("changed file notifies subscribers", func(t *testing.T) {
+		writeManagerConfig(t, path, `profiles:
+  default:
+    unicode_emojis: true
+    emoji_allowlist: ["✅", "🚀"]
+`)
+		changed, err := m.Reload()
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		require.Len(t, received, 1)
+		assert.Equal(t, []string{"✅", "🚀"}, received[0].Profile.EmojiAllowlist)
+		assert.Equal(t, uint64(2), received[0].Version)
+		assert.Equal(t, received[0], m.Current())
+	})
+
+	t.Run("invalid file keeps current configuration", func(t *testing.T) {
+		writeManagerConfig(t, path, "profiles:\n  default:\n    max_workers: -1\n")
+		_, err := m.Reload()
+		assert.Error(t, err)
+
+		writeManagerConfig(t, path, "profiles: [unclosed\n")
+		_, err = m.Reload()
+		assert.Error(t, err)
+
+		assert.Equal(t, []string{"✅", "🚀"}, m.Current().Profile.EmojiAllowlist)
+		assert.Len(t, received, 1)
+	})
+
+	t.Run("removing the active profile keeps current configuration", func(t *testing.T) {
+		writeManagerConfig(t, path, "profiles:\n  other:\n    unicode_emojis: true\n")
+		_, err := m.Reload()
+		assert.Error(t, err)
+		assert.Equal(t, "default", m.Current().ProfileName)
+	})
+
+	t.Run("unsubscribed functions are not called", func(t *testing.T) {
+		unsubscribe()
+		writeManagerConfig(t, path, managerTestConfig)
+		changed, err := m.Reload()
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Len(t, received, 1)
+	})
+}
+
+func TestManager_ReloadExtends(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	writeManagerConfig(t, base, managerTestConfig)
+	path := filepath.Join(dir, ".antimoji.yaml")
+	writeManagerConfig(t, path, "extends: base.yaml\n")
+
+	m, err := NewManager(path, "default")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"✅"}, m.Current().Profile.EmojiAllowlist)
+
+	changed, err := m.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	writeManagerConfig(t, base, `profiles:
+  default:
+    unicode_emojis: true
+    emoji_allowlist: ["✅", "🚀"]
+`)
+	changed, err = m.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed, "an edit to an extended file is reloaded")
+	assert.Equal(t, []string{"✅", "🚀"}, m.Current().Profile.EmojiAllowlist)
+
+	// A file added to the chain is tracked once the chain is reloaded
+	other := filepath.Join(dir, "other.yaml")
+	writeManagerConfig(t, other, "profiles:\n  default:\n    max_per_file: 1\n")
+	writeManagerConfig(t, path, "extends: [base.yaml, other.yaml]\n")
+	changed, err = m.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 1, m.Current().Profile.MaxPerFile)
+
+	writeManagerConfig(t, other, "profiles:\n  default:\n    max_per_file: 12\n")
+	changed, err = m.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 12, m.Current().Profile.MaxPerFile)
+}
+
+func TestManager_SwitchProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".antimoji.yaml")
+	writeManagerConfig(t, path, managerTestConfig)
+
+	m, err := NewManager(path, "default")
+	require.NoError(t, err)
+
+	var received []Snapshot
+	m.Subscribe(func(s Snapshot) { received = append(received, s) })
+
+	require.NoError(t, m.SwitchProfile("strict"))
+	assert.Equal(t, "strict", m.Current().ProfileName)
+	assert.Empty(t, m.Current().Profile.EmojiAllowlist)
+	require.Len(t, received, 1)
+	assert.Equal(t, "strict", received[0].ProfileName)
+
+	assert.Error(t, m.SwitchProfile("missing"))
+	assert.Equal(t, "strict", m.Current().ProfileName)
+	assert.Len(t, received, 1)
+}
+
+func TestManager_Watch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".antimoji.yaml")
+	writeManagerConfig(t, path, managerTestConfig)
+
+	m, err := NewManager(path, "default")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var allowlists [][]string
+	m.Subscribe(func(s Snapshot) {
+		mu.Lock()
+		defer mu.Unlock()
+		allowlists = append(allowlists, s.Profile.EmojiAllowlist)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Watch(ctx, nil) }()
+	defer func() {
+		cancel()
+		assert.NoError(t, <-done)
+	}()
+
+	// Save by renaming a temporary file over the config, as many editors do
+	updated := `profiles:
+  default:
+    unicode_emojis: true
+    emoji_allowlist: ["🎉"]
+`
+	assert.Eventually(t, func() bool {
+		tmp := filepath.Join(dir, ".antimoji.yaml.tmp")
+		writeManagerConfig(t, tmp, updated)
+		require.NoError(t, os.Rename(tmp, path))
+
+		mu.Lock()
+		defer mu.Unlock()
+		return len(allowlists) > 0
+	}, 5*time.Second, 250*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"🎉"}, allowlists[0])
+}
+
+func TestManager_WatchExtends(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared")
+	require.NoError(t, os.Mkdir(shared, 0755))
+	base := filepath.Join(shared, "base.yaml")
+	writeManagerConfig(t, base, managerTestConfig)
+	path := filepath.Join(dir, ".antimoji.yaml")
+	writeManagerConfig(t, path, "extends: shared/base.yaml\n")
+
+	m, err := NewManager(path, "default")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Watch(ctx, nil) }()
+	defer func() {
+		cancel()
+		assert.NoError(t, <-done)
+	}()
+
+	// The base file lives in another directory than the config that extends it
+	assert.Eventually(t, func() bool {
+		writeManagerConfig(t, base, `profiles:
+  default:
+    unicode_emojis: true
+    emoji_allowlist: ["🎉"]
+`)
+		allowlist := m.Current().Profile.EmojiAllowlist
+		return len(allowlist) == 1 && allowlist[0] == "🎉"
+	}, 5*time.Second, 250*time.Millisecond)
+}
@@ -0,0 +1,301 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the configuration schema written by this version of antimoji. Files
+// without an integer version key (older files carry a release number such as "0.5.0")
+// use schema 1.
+const SchemaVersion = 3
+
+// Change is one transformation made while migrating a configuration file.
+type Change struct {
+	Path        string
+	Description string
+}
+
+// String renders the change as a changelog line.
+func (c Change) String() string {
+	return c.Path + ": " + c.Description
+}
+
+// MigrationResult describes the upgrade of a configuration file to SchemaVersion.
+type MigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	Changes     []Change
+	Content     []byte
+}
+
+// NeedsMigration reports whether the file is older than SchemaVersion.
+func (r MigrationResult) NeedsMigration() bool {
+	return r.FromVersion < r.ToVersion
+}
+
+// Deprecated returns the changes that rewrite deprecated keys, leaving out the
+// version stamp.
+func (r MigrationResult) Deprecated() []Change {
+	var changes []Change
+	for _, change := range r.Changes {
+		if change.Path != "version" {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// migration upgrades the document root of a file using schema from to schema from+1.
+type migration struct {
+	from  int
+	apply func(root *yaml.Node) []Change
+}
+
+// migrations lists the schema upgrades in order.
+var migrations = []migration{
+	{from: 1, apply: migrateSymlinkPolicy},
+	{from: 2, apply: migrateThreshold},
+}
+
+// DetectSchemaVersion returns the schema version of YAML configuration content.
+func DetectSchemaVersion(content []byte) (int, error) {
+	var raw struct {
+		Version interface{} `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return 0, err
+	}
+	if version, ok := raw.Version.(int); ok && version > 0 {
+		return version, nil
+	}
+	return 1, nil
+}
+
+// Migrate upgrades YAML configuration content to SchemaVersion, preserving comments and
+// key order. The result lists every change in the order applied; Content is the input
+// unchanged when the file is already current.
+func Migrate(content []byte) (MigrationResult, error) {
+	version, err := DetectSchemaVersion(content)
+	if err != nil {
+		return MigrationResult{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	result := MigrationResult{FromVersion: version, ToVersion: SchemaVersion, Content: content}
+	if version > SchemaVersion {
+		return result, fmt.Errorf("configuration schema %d is newer than this antimoji supports (%d)", version, SchemaVersion)
+	}
+	if version == SchemaVersion {
+		return result, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return result, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return result, fmt.Errorf("configuration root is not a mapping")
+	}
+	root := doc.Content[0]
+
+	for _, m := range migrations {
+		if m.from >= version {
+			result.Changes = append(result.Changes, m.apply(root)...)
+		}
+	}
+	result.Changes = append(result.Changes, stampVersion(root))
+
+	migrated, err := encodeYAMLDocumentIndent(&doc, detectIndent(content))
+	if err != nil {
+		return result, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if configResult := parseConfig(migrated); configResult.IsErr() {
+		return result, fmt.Errorf("migrated configuration is invalid: %w", configResult.Error())
+	}
+	result.Content = migrated
+
+	return result, nil
+}
+
+// MigrateFile upgrades the configuration file at path to SchemaVersion. Nothing is
+// written when dryRun is set or the file is already current.
+func MigrateFile(path string, dryRun bool) (MigrationResult, error) {
+	content, err := os.ReadFile(path) // #nosec G304 - config path is user-provided by design
+	if err != nil {
+		return MigrationResult{}, err
+	}
+
+	result, err := Migrate(content)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", path, err)
+	}
+	if dryRun || !result.NeedsMigration() {
+		return result, nil
+	}
+
+	perm := os.FileMode(0644)
+	if stat, err := os.Stat(path); err == nil {
+		perm = stat.Mode().Perm()
+	}
+	return result, os.WriteFile(path, result.Content, perm)
+}
+
+// migrationWarning describes the deprecated keys of an outdated configuration file, or
+// returns "" when there is nothing to migrate. Files that only lack the version stamp
+// load exactly as before and are not reported.
+func migrationWarning(path string, content []byte) string {
+	result, err := Migrate(content)
+	if err != nil || len(result.Deprecated()) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s uses configuration schema %d with deprecated keys (%d to rewrite); run 'antimoji config migrate --config %s' to upgrade to schema %d",
+		path, result.FromVersion, len(result.Deprecated()), path, result.ToVersion)
+}
+
+// detectIndent returns the indentation of the first indented line of content, so that
+// migrated files keep their layout. It defaults to two spaces.
+func detectIndent(content []byte) int {
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if indent := len(line) - len(trimmed); indent > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return indent
+		}
+	}
+	return 2
+}
+
+// stampVersion sets the root version key to SchemaVersion, adding it first when absent.
+func stampVersion(root *yaml.Node) Change {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(SchemaVersion)}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "version" {
+			old := root.Content[i+1].Value
+			setMappingValue(root, "version", value)
+			return Change{Path: "version", Description: fmt.Sprintf("%q replaced by schema version %d", old, SchemaVersion)}
+		}
+	}
+
+	root.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"},
+		value,
+	}, root.Content...)
+	// Keep a leading file comment above the new key
+	if len(root.Content) > 2 {
+		root.Content[0].HeadComment, root.Content[2].HeadComment = root.Content[2].HeadComment, ""
+	}
+	return Change{Path: "version", Description: fmt.Sprintf("added schema version %d", SchemaVersion)}
+}
+
+// migrateSymlinkPolicy (schema 1 to 2) replaces follow_symlinks, which symlink_policy
+// supersedes, keeping how each profile treats symlinks.
+func migrateSymlinkPolicy(root *yaml.Node) []Change {
+	profiles := mappingValue(root, "profiles")
+	if profiles == nil || profiles.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var changes []Change
+	for i := 0; i+1 < len(profiles.Content); i += 2 {
+		name, profile := profiles.Content[i].Value, profiles.Content[i+1]
+		if profile.Kind != yaml.MappingNode {
+			continue
+		}
+
+		index := mappingIndex(profile, "follow_symlinks")
+		if index < 0 {
+			continue
+		}
+		path := "profiles." + name + ".follow_symlinks"
+		follow, _ := strconv.ParseBool(profile.Content[index+1].Value)
+
+		switch {
+		case mappingIndex(profile, "symlink_policy") >= 0:
+			removeMappingKey(profile, index)
+			changes = append(changes, Change{Path: path, Description: "removed; symlink_policy takes precedence"})
+		case follow:
+			// Rename in place so comments and position are kept
+			profile.Content[index].Value = "symlink_policy"
+			old := profile.Content[index+1]
+			profile.Content[index+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: SymlinkFollow,
+				LineComment: old.LineComment, FootComment: old.FootComment}
+			changes = append(changes, Change{Path: path, Description: "replaced by symlink_policy: " + SymlinkFollow})
+		default:
+			removeMappingKey(profile, index)
+			changes = append(changes, Change{Path: path, Description: "removed; false is the default"})
+		}
+	}
+
+	return changes
+}
+
+// migrateThreshold (schema 2 to 3) replaces max_emoji_threshold, whose meaning depended
+// on the command, with max_total. scan enforces max_total from the file, where it only
+// used max_emoji_threshold when overridden by the environment or --set.
+func migrateThreshold(root *yaml.Node) []Change {
+	profiles := mappingValue(root, "profiles")
+	if profiles == nil || profiles.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var changes []Change
+	for i := 0; i+1 < len(profiles.Content); i += 2 {
+		name, profile := profiles.Content[i].Value, profiles.Content[i+1]
+		if profile.Kind != yaml.MappingNode {
+			continue
+		}
+
+		index := mappingIndex(profile, "max_emoji_threshold")
+		if index < 0 {
+			continue
+		}
+		path := "profiles." + name + ".max_emoji_threshold"
+		if mappingIndex(profile, "max_total") >= 0 {
+			removeMappingKey(profile, index)
+			changes = append(changes, Change{Path: path, Description: "removed; max_total takes precedence"})
+			continue
+		}
+		// Rename in place so comments and position are kept
+		profile.Content[index].Value = "max_total"
+		changes = append(changes, Change{Path: path, Description: "replaced by max_total, which scan enforces without --threshold"})
+	}
+
+	return changes
+}
+
+// mappingValue returns the value node for key in a mapping, or nil when absent.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if index := mappingIndex(mapping, key); index >= 0 {
+		return mapping.Content[index+1]
+	}
+	return nil
+}
+
+// mappingIndex returns the index of key's key node in a mapping, or -1 when absent.
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeMappingKey deletes the pair at index from a mapping, moving a comment above the
+// removed key to the key that follows it.
+func removeMappingKey(mapping *yaml.Node, index int) {
+	comment := mapping.Content[index].HeadComment
+	mapping.Content = append(mapping.Content[:index], mapping.Content[index+2:]...)
+	if comment != "" && index < len(mapping.Content) {
+		next := mapping.Content[index]
+		if next.HeadComment != "" {
+			comment += "\n" + next.HeadComment
+		}
+		next.HeadComment = comment
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacyConfigYAML = `# team config
+version: "0.5.0"
+profiles:
+  default:
+    # follow vendored links
+    follow_symlinks: true # legacy
+    max_emoji_threshold: 2
+  ci:
+    follow_symlinks: false
+    # keep this
+    recursive: true
+  strict:
+    follow_symlinks: true
+    symlink_policy: report
+`
+
+func TestDetectSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"release number", `version: "0.5.0"`, 1},
+		{"missing", "profiles: {}", 1},
+		{"empty", "", 1},
+		{"schema", "version: 2", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := DetectSchemaVersion([]byte(tt.content))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, version)
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("rewrites deprecated keys and keeps comments", func(t *testing.T) {
+		result, err := Migrate([]byte(legacyConfigYAML))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, result.FromVersion)
+		assert.Equal(t, SchemaVersion, result.ToVersion)
+		assert.Equal(t, []Change{
+			{Path: "profiles.default.follow_symlinks", Description: "replaced by symlink_policy: follow"},
+			{Path: "profiles.ci.follow_symlinks", Description: "removed; false is the default"},
+			{Path: "profiles.strict.follow_symlinks", Description: "removed; symlink_policy takes precedence"},
+			{Path: "profiles.default.max_emoji_threshold", Description: "replaced by max_total, which scan enforces without --threshold"},
+			{Path: "version", Description: `"0.5.0" replaced by schema version 3`},
+		}, result.Changes)
+		assert.Len(t, result.Deprecated(), 4)
+
+		content := string(result.Content)
+		assert.Contains(t, content, "# team config\nversion: 3\n")
+		assert.Contains(t, content, "# follow vendored links\n    symlink_policy: follow # legacy\n")
+		assert.Contains(t, content, "# keep this\n    recursive: true")
+		assert.NotContains(t, content, "follow_symlinks")
+		assert.NotContains(t, content, "max_emoji_threshold")
+
+		cfg := parseConfig(result.Content).Unwrap()
+		assert.Equal(t, SymlinkFollow, cfg.Profiles["default"].SymlinkPolicy)
+		assert.Equal(t, SymlinkReport, cfg.Profiles["strict"].SymlinkPolicy)
+		assert.Equal(t, 2, cfg.Profiles["default"].MaxTotal)
+	})
+
+	t.Run("stamps a missing version", func(t *testing.T) {
+		result, err := Migrate([]byte("# header\nprofiles:\n  default:\n    recursive: true\n"))
+		require.NoError(t, err)
+		assert.Empty(t, result.Deprecated())
+		assert.Equal(t, "# header\nversion: 3\nprofiles:\n  default:\n    recursive: true\n", string(result.Content))
+	})
+
+	t.Run("keeps the file's indentation", func(t *testing.T) {
+		result, err := Migrate([]byte("profiles:\n    default:\n        follow_symlinks: false\n        recursive: true\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "version: 3\nprofiles:\n    default:\n        recursive: true\n", string(result.Content))
+	})
+
+	t.Run("current file is unchanged", func(t *testing.T) {
+		content := []byte("version: 3\nprofiles:\n  default:\n    follow_symlinks: true\n")
+		result, err := Migrate(content)
+		require.NoError(t, err)
+		assert.False(t, result.NeedsMigration())
+		assert.Empty(t, result.Changes)
+		assert.Equal(t, content, result.Content)
+	})
+
+	t.Run("max_total takes precedence over max_emoji_threshold", func(t *testing.T) {
+		result, err := Migrate([]byte("version: 2\nprofiles:\n  default:\n    max_emoji_threshold: 5\n    max_total: 3\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Path: "profiles.default.max_emoji_threshold", Description: "removed; max_total takes precedence"},
+			{Path: "version", Description: `"2" replaced by schema version 3`},
+		}, result.Changes)
+		assert.Equal(t, "version: 3\nprofiles:\n  default:\n    max_total: 3\n", string(result.Content))
+	})
+
+	t.Run("newer schema is rejected", func(t *testing.T) {
+		_, err := Migrate([]byte("version: 4\n"))
+		assert.ErrorContains(t, err, "newer than this antimoji supports")
+	})
+}
+
+func TestMigrateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(legacyConfigYAML), 0600))
+
+	_, err := MigrateFile(path, true)
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacyConfigYAML, string(content), "dry run must not write")
+
+	_, err = MigrateFile(path, false)
+	require.NoError(t, err)
+	stat, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	result, err := MigrateFile(path, false)
+	require.NoError(t, err)
+	assert.False(t, result.NeedsMigration())
+}
+
+func TestLoadConfig_MigrationWarning(t *testing.T) {
+	dir := t.TempDir()
+
+	legacy := filepath.Join(dir, "legacy.yaml")
+	require.NoError(t, os.WriteFile(legacy, []byte(legacyConfigYAML), 0600))
+	cfg := LoadConfig(legacy).Unwrap()
+	require.Len(t, cfg.Warnings, 1)
+	assert.Contains(t, cfg.Warnings[0], "antimoji config migrate")
+
+	unversioned := filepath.Join(dir, "unversioned.yaml")
+	require.NoError(t, os.WriteFile(unversioned, []byte("profiles:\n  default:\n    recursive: true\n"), 0600))
+	assert.Empty(t, LoadConfig(unversioned).Unwrap().Warnings)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Notification types.
+const (
+	NotifySlack   = "slack"
+	NotifyWebhook = "webhook"
+)
+
+// When a notification is sent.
+const (
+	NotifyOnFailure = "failure"
+	NotifyOnSuccess = "success"
+	NotifyOnAlways  = "always"
+)
+
+// Notification sends the summary of a scan to a Slack incoming webhook or to any URL
+// accepting JSON, such as the scan of a nightly CI job.
+type Notification struct {
+	// Type is slack or webhook
+	Type string `yaml:"type" json:"type"`
+
+	// Webhook is the URL the summary is posted to. ${VAR} references are expanded from
+	// the environment, so that secret URLs stay out of the file.
+	Webhook string `yaml:"webhook" json:"webhook"`
+
+	// On is failure, success or always; unset notifies of failures
+	On string `yaml:"on,omitempty" json:"on,omitempty"`
+
+	// ReportURL links the summary to the full report, such as a CI artifact; ${VAR}
+	// references are expanded too
+	ReportURL string `yaml:"report_url,omitempty" json:"report_url,omitempty"`
+}
+
+// Wants reports whether the notification is sent for a run that passed or failed.
+func (n Notification) Wants(passed bool) bool {
+	switch n.On {
+	case NotifyOnAlways:
+		return true
+	case NotifyOnSuccess:
+		return passed
+	default:
+		return !passed
+	}
+}
+
+// validateNotification checks a notification listed by a profile.
+func validateNotification(notification Notification) error {
+	switch notification.Type {
+	case NotifySlack, NotifyWebhook:
+	default:
+		return fmt.Errorf("invalid type %q (must be slack or webhook)", notification.Type)
+	}
+	switch notification.On {
+	case "", NotifyOnFailure, NotifyOnSuccess, NotifyOnAlways:
+	default:
+		return fmt.Errorf("%s notification: invalid on %q (must be failure, success or always)", notification.Type, notification.On)
+	}
+	webhook := strings.TrimSpace(notification.Webhook)
+	if webhook == "" {
+		return fmt.Errorf("%s notification without a webhook", notification.Type)
+	}
+	// URLs built from the environment are only known when the notification is sent
+	if !strings.Contains(webhook, "$") {
+		parsed, err := url.Parse(webhook)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("%s notification: webhook %q is not an http(s) URL", notification.Type, webhook)
+		}
+	}
+	return nil
+}
+
+// validateNotifications checks the notifications listed by a profile.
+func validateNotifications(name string, profile Profile) error {
+	for _, notification := range profile.Notify {
+		if err := validateNotification(notification); err != nil {
+			return fmt.Errorf("profile %s: notify: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileNotify(t *testing.T) {
+	content := `profiles:
+  ci:
+    notify:
+      - type: slack
+        webhook: ${SLACK_WEBHOOK_URL}
+        report_url: ${CI_JOB_URL}/artifacts
+      - type: webhook
+        webhook: https://hooks.example.com/antimoji
+        on: always
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	config := LoadConfigStrict(configPath).Unwrap()
+	require.True(t, ValidateConfig(config).IsOk())
+	assert.Equal(t, []Notification{
+		{Type: NotifySlack, Webhook: "${SLACK_WEBHOOK_URL}", ReportURL: "${CI_JOB_URL}/artifacts"},
+		{Type: NotifyWebhook, Webhook: "https://hooks.example.com/antimoji", On: NotifyOnAlways},
+	}, config.Profiles["ci"].Notify)
+}
+
+func TestNotification_Wants(t *testing.T) {
+	for on, want := range map[string][2]bool{
+		"":              {false, true},
+		NotifyOnFailure: {false, true},
+		NotifyOnSuccess: {true, false},
+		NotifyOnAlways:  {true, true},
+	} {
+		notification := Notification{On: on}
+		assert.Equal(t, want[0], notification.Wants(true), "on %q, passed", on)
+		assert.Equal(t, want[1], notification.Wants(false), "on %q, failed", on)
+	}
+}
+
+func TestValidateNotifications(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification Notification
+		wantErr      string
+	}{
+		{"valid", Notification{Type: NotifySlack, Webhook: "https://hooks.slack.com/services/T/B/X"}, ""},
+		{"from the environment", Notification{Type: NotifyWebhook, Webhook: "$HOOK", On: NotifyOnSuccess}, ""},
+		{"unknown type", Notification{Type: "email", Webhook: "https://example.com"}, `invalid type "email"`},
+		{"unknown on", Notification{Type: NotifySlack, Webhook: "https://example.com", On: "never"}, `invalid on "never"`},
+		{"missing webhook", Notification{Type: NotifySlack}, "slack notification without a webhook"},
+		{"not a URL", Notification{Type: NotifyWebhook, Webhook: "hooks.example.com"}, "is not an http(s) URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			profile := config.Profiles["default"]
+			profile.Notify = []Notification{tt.notification}
+			config.Profiles["default"] = profile
+
+			result := ValidateConfig(config)
+			if tt.wantErr == "" {
+				assert.True(t, result.IsOk())
+				return
+			}
+			require.True(t, result.IsErr())
+			assert.Contains(t, result.Error().Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPolicy points at the organization policy file, overriding DefaultPolicyPath.
+const EnvPolicy = "ANTIMOJI_POLICY"
+
+// DefaultPolicyPath returns the system-wide policy file: /etc/antimoji/policy.yaml, or
+// %ProgramData%\antimoji\policy.yaml on Windows.
+func DefaultPolicyPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "antimoji", "policy.yaml")
+	}
+	return "/etc/antimoji/policy.yaml"
+}
+
+// OrgPolicy is an organization-wide policy whose settings repository configuration
+// cannot weaken. Locked profile fields may only be tightened (see lockKinds), and rules
+// set violation budgets for paths whatever the local profile says.
+type OrgPolicy struct {
+	// Path is where the policy was loaded from
+	Path string
+
+	locked Profile
+	keys   []string
+	rules  []PolicyRule
+}
+
+// PolicyRule limits the violations tolerated across the files matching Paths: patterns
+// such as internal/**/*.go matched against the whole path, or such as *.go matched
+// against the file name.
+type PolicyRule struct {
+	Paths     []string `yaml:"paths"`
+	MaxEmojis int      `yaml:"max_emojis"`
+}
+
+// lockKind says how repository configuration may tighten a locked field.
+type lockKind int
+
+const (
+	// lockExact requires the locked value
+	lockExact lockKind = iota
+	// lockAtMost allows a lower, non-zero limit (0 means no limit)
+	lockAtMost
+	// lockEnabled keeps a locked true value enabled
+	lockEnabled
+	// lockSubset allows a subset of the locked list
+	lockSubset
+)
+
+// lockKinds lists the fields with a known stricter direction; other locked fields must
+// match exactly.
+var lockKinds = map[string]lockKind{
+	"max_emoji_threshold":   lockAtMost,
+	"max_total":             lockAtMost,
+	"max_new":               lockAtMost,
+	"max_per_file":          lockAtMost,
+	"unicode_emojis":        lockEnabled,
+	"text_emoticons":        lockEnabled,
+	"kaomoji":               lockEnabled,
+	"decorative_symbols":    lockEnabled,
+	"escaped_emojis":        lockEnabled,
+	"evasion_detection":     lockEnabled,
+	"unicode_hygiene":       lockEnabled,
+	"fail_on_found":         lockEnabled,
+	"emoji_allowlist":       lockSubset,
+	"file_ignore_list":      lockSubset,
+	"directory_ignore_list": lockSubset,
+	"exclude_patterns":      lockSubset,
+}
+
+// LoadOrgPolicy loads the policy named by ANTIMOJI_POLICY in environ (os.Environ format),
+// or the file at DefaultPolicyPath. It returns nil when no policy applies; a policy named
+// by ANTIMOJI_POLICY must exist.
+func LoadOrgPolicy(environ []string) (*OrgPolicy, error) {
+	path, explicit := "", false
+	for _, entry := range environ {
+		if value, ok := strings.CutPrefix(entry, EnvPolicy+"="); ok && value != "" {
+			path, explicit = value, true
+		}
+	}
+	if !explicit {
+		path = DefaultPolicyPath()
+	}
+
+	source, err := readConfigSource(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load organization policy: %w", err)
+	}
+
+	orgPolicy, err := parseOrgPolicy(source.content)
+	if err != nil {
+		return nil, fmt.Errorf("organization policy %s: %w", path, err)
+	}
+	orgPolicy.Path = path
+	return orgPolicy, nil
+}
+
+// parseOrgPolicy parses policy content with a locked profile and rules.
+func parseOrgPolicy(content []byte) (*OrgPolicy, error) {
+	var raw struct {
+		Version interface{}            `yaml:"version"`
+		Locked  map[string]interface{} `yaml:"locked"`
+		Rules   []PolicyRule           `yaml:"rules"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	orgPolicy := &OrgPolicy{rules: raw.Rules}
+	for key := range raw.Locked {
+		canonical, known := canonicalKey(key)
+		if !known {
+			return nil, fmt.Errorf("locked: unknown profile field %q", key)
+		}
+		orgPolicy.keys = append(orgPolicy.keys, canonical)
+	}
+	sort.Slice(orgPolicy.keys, func(i, j int) bool {
+		return profileFields[orgPolicy.keys[i]] < profileFields[orgPolicy.keys[j]]
+	})
+
+	// Parse the locked fields like a profile so values mean the same as in a config file
+	wrapped, err := yaml.Marshal(map[string]interface{}{"profiles": map[string]interface{}{"locked": raw.Locked}})
+	if err != nil {
+		return nil, err
+	}
+	configResult := parseConfig(wrapped)
+	if configResult.IsErr() {
+		return nil, fmt.Errorf("locked: %w", configResult.Error())
+	}
+	orgPolicy.locked = configResult.Unwrap().Profiles["locked"]
+
+	for i, rule := range orgPolicy.rules {
+		if len(rule.Paths) == 0 {
+			return nil, fmt.Errorf("rules[%d]: paths is required", i)
+		}
+		if rule.MaxEmojis < 0 {
+			return nil, fmt.Errorf("rules[%d]: max_emojis must be non-negative", i)
+		}
+		for _, pattern := range rule.Paths {
+			if err := pathmatch.Validate(pattern); err != nil {
+				return nil, fmt.Errorf("rules[%d]: %w", i, err)
+			}
+		}
+	}
+
+	return orgPolicy, nil
+}
+
+// Rules returns the path rules of the policy; a nil policy has none.
+func (p *OrgPolicy) Rules() []PolicyRule {
+	if p == nil {
+		return nil
+	}
+	return p.rules
+}
+
+// Locked reports whether the policy locks the profile field named key.
+func (p *OrgPolicy) Locked(key string) bool {
+	if p == nil {
+		return false
+	}
+	for _, locked := range p.keys {
+		if locked == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Enforce applies the policy to a resolved profile. Locked fields left at their defaults
+// take the locked value, stricter values are kept, and weaker values set by the config
+// file, environment or --set are reported together in one error. Commands attribute every
+// field of a loaded profile to the file, so file values equal to the defaults count as
+// left at their defaults. Enforced fields are
+// attributed to SourcePolicy. A nil policy returns resolution unchanged.
+func (p *OrgPolicy) Enforce(resolution Resolution) (Resolution, error) {
+	if p == nil {
+		return resolution, nil
+	}
+
+	sources := make(map[string]Source, len(resolution.Sources))
+	for key, source := range resolution.Sources {
+		sources[key] = source
+	}
+	resolution.Sources = sources
+	resolution.Policy = p
+
+	var conflicts []string
+	for _, key := range p.keys {
+		locked, _ := FieldValue(p.locked, key)
+		local, _ := FieldValue(resolution.Profile, key)
+		if !satisfiesLock(lockKinds[key], local, locked) {
+			if source := resolution.Sources[key]; source > SourceFile || (source == SourceFile && !isDefaultValue(key, local)) {
+				conflicts = append(conflicts, describeConflict(key, local, locked, resolution.Sources[key]))
+				continue
+			}
+			reflect.ValueOf(&resolution.Profile).Elem().Field(profileFields[key]).Set(reflect.ValueOf(locked))
+		}
+		resolution.Sources[key] = SourcePolicy
+	}
+
+	if len(conflicts) > 0 {
+		return resolution, fmt.Errorf("configuration conflicts with organization policy %s: %s", p.Path, strings.Join(conflicts, "; "))
+	}
+	return resolution, nil
+}
+
+// satisfiesLock reports whether local is at least as strict as locked.
+func satisfiesLock(kind lockKind, local, locked interface{}) bool {
+	switch kind {
+	case lockAtMost:
+		limit, value := locked.(int), local.(int)
+		return limit == 0 || (value > 0 && value <= limit)
+	case lockEnabled:
+		return !locked.(bool) || local.(bool)
+	case lockSubset:
+		return len(addedItems(local.([]string), locked.([]string))) == 0
+	default:
+		lv, kv := reflect.ValueOf(local), reflect.ValueOf(locked)
+		if (lv.Kind() == reflect.Slice || lv.Kind() == reflect.Map) && lv.Len() == 0 && kv.Len() == 0 {
+			return true
+		}
+		return reflect.DeepEqual(local, locked)
+	}
+}
+
+// isDefaultValue reports whether value is empty or the built-in default of the field
+// named key, i.e. what a profile that does not mention the field holds.
+func isDefaultValue(key string, value interface{}) bool {
+	v := reflect.ValueOf(value)
+	if v.IsZero() || ((v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0) {
+		return true
+	}
+	defaultValue, _ := FieldValue(DefaultConfig().Profiles["default"], key)
+	return reflect.DeepEqual(value, defaultValue)
+}
+
+// describeConflict explains why a local value weakens a locked field.
+func describeConflict(key string, local, locked interface{}, source Source) string {
+	origin := map[Source]string{
+		SourceFile: "the config file",
+		SourceEnv:  EnvPrefix + strings.ToUpper(key),
+		SourceFlag: "--set",
+	}[source]
+
+	switch lockKinds[key] {
+	case lockAtMost:
+		value := fmt.Sprint(local)
+		if local.(int) == 0 {
+			value = "no limit (0)"
+		}
+		return fmt.Sprintf("%s is locked to at most %v, but %s sets %s", key, locked, origin, value)
+	case lockEnabled:
+		return fmt.Sprintf("%s is locked to true, but %s sets false", key, origin)
+	case lockSubset:
+		return fmt.Sprintf("%s may only contain %q, but %s adds %q", key, locked, origin, addedItems(local.([]string), locked.([]string)))
+	default:
+		return fmt.Sprintf("%s is locked to %v, but %s sets %v", key, locked, origin, local)
+	}
+}
+
+// addedItems returns the items of local that are not in allowed.
+func addedItems(local, allowed []string) []string {
+	permitted := make(map[string]bool, len(allowed))
+	for _, item := range allowed {
+		permitted[item] = true
+	}
+	var added []string
+	for _, item := range local {
+		if !permitted[item] {
+			added = append(added, item)
+		}
+	}
+	return added
+}
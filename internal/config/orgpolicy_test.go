@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrgPolicyYAML = `locked:
+  max_per_file: 3
+  unicode_emojis: true
+  emoji_allowlist: ["✅", "⚠️"]
+  symlink_policy: skip
+rules:
+  - paths: ["*.go"]
+    max_emojis: 0
+`
+
+func writeOrgPolicy(t *testing.T, content string) []string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return []string{EnvPolicy + "=" + path}
+}
+
+func TestLoadOrgPolicy(t *testing.T) {
+	t.Run("parses locked fields and rules", func(t *testing.T) {
+		orgPolicy, err := LoadOrgPolicy(writeOrgPolicy(t, testOrgPolicyYAML))
+		require.NoError(t, err)
+		require.NotNil(t, orgPolicy)
+
+		assert.True(t, orgPolicy.Locked("max_per_file"))
+		assert.False(t, orgPolicy.Locked("max_workers"))
+		assert.Equal(t, []PolicyRule{{Paths: []string{"*.go"}, MaxEmojis: 0}}, orgPolicy.Rules())
+	})
+
+	t.Run("missing ANTIMOJI_POLICY file is an error", func(t *testing.T) {
+		_, err := LoadOrgPolicy([]string{EnvPolicy + "=" + filepath.Join(t.TempDir(), "none.yaml")})
+		assert.ErrorContains(t, err, "failed to load organization policy")
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		for content, want := range map[string]string{
+			"locked:\n  max_per_fiel: 3\n":                      `unknown profile field "max_per_fiel"`,
+			"rules:\n  - max_emojis: 0\n":                       "rules[0]: paths is required",
+			"rules:\n  - paths: ['[']\n":                        "rules[0]",
+			"rules:\n  - paths: ['*.go']\n    max_emojis: -1\n": "max_emojis must be non-negative",
+			"lockd:\n  max_per_file: 3\n":                       "field lockd not found",
+		} {
+			_, err := LoadOrgPolicy(writeOrgPolicy(t, content))
+			assert.ErrorContains(t, err, want, content)
+		}
+	})
+
+	t.Run("nil policy changes nothing", func(t *testing.T) {
+		var orgPolicy *OrgPolicy
+		resolution := Resolve(DefaultConfig().Profiles["default"], SourceDefault).Unwrap()
+		enforced, err := orgPolicy.Enforce(resolution)
+		require.NoError(t, err)
+		assert.Equal(t, resolution, enforced)
+		assert.Empty(t, orgPolicy.Rules())
+	})
+}
+
+func TestOrgPolicy_Enforce(t *testing.T) {
+	orgPolicy, err := LoadOrgPolicy(writeOrgPolicy(t, testOrgPolicyYAML))
+	require.NoError(t, err)
+	profile := DefaultConfig().Profiles["default"]
+
+	t.Run("defaults take the locked values", func(t *testing.T) {
+		enforced, err := orgPolicy.Enforce(Resolve(profile, SourceFile).Unwrap())
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, enforced.Profile.MaxPerFile)
+		assert.Equal(t, SymlinkSkip, enforced.Profile.SymlinkPolicy)
+		assert.Empty(t, enforced.Profile.EmojiAllowlist, "an empty allowlist is already stricter")
+		assert.Equal(t, SourcePolicy, enforced.Sources["max_per_file"])
+		assert.Equal(t, SourceFile, enforced.Sources["max_workers"])
+		assert.Same(t, orgPolicy, enforced.Policy)
+	})
+
+	t.Run("stricter values are kept", func(t *testing.T) {
+		strict := profile
+		strict.MaxPerFile = 1
+		strict.EmojiAllowlist = []string{"✅"}
+		strict.SymlinkPolicy = SymlinkSkip
+
+		enforced, err := orgPolicy.Enforce(Resolve(strict, SourceFile).Unwrap())
+		require.NoError(t, err)
+		assert.Equal(t, 1, enforced.Profile.MaxPerFile)
+		assert.Equal(t, []string{"✅"}, enforced.Profile.EmojiAllowlist)
+	})
+
+	t.Run("weaker values are conflicts", func(t *testing.T) {
+		weak := profile
+		weak.MaxPerFile = 10
+		weak.EmojiAllowlist = []string{"✅", "🚀"}
+		weak.SymlinkPolicy = SymlinkFollow
+		flags, err := ParseSetFlags([]string{"unicode_emojis=false"})
+		require.NoError(t, err)
+		env := EnvOverrides([]string{"ANTIMOJI_MAX_PER_FILE=0"})
+
+		_, err = orgPolicy.Enforce(Resolve(weak, SourceFile, env, flags).Unwrap())
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "configuration conflicts with organization policy")
+		assert.ErrorContains(t, err, "unicode_emojis is locked to true, but --set sets false")
+		assert.ErrorContains(t, err, `symlink_policy is locked to skip, but the config file sets follow`)
+		assert.ErrorContains(t, err, `emoji_allowlist may only contain ["✅" "⚠️"], but the config file adds ["🚀"]`)
+		assert.ErrorContains(t, err, "max_per_file is locked to at most 3, but ANTIMOJI_MAX_PER_FILE sets no limit (0)")
+	})
+}
@@ -0,0 +1,385 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of environment variables that override profile fields,
+// e.g. ANTIMOJI_MAX_FILE_SIZE for max_file_size.
+const EnvPrefix = "ANTIMOJI_"
+
+// Source identifies the configuration layer a profile value came from. Layers are
+// applied in increasing order, so later sources take precedence.
+type Source int
+
+const (
+	// SourceDefault is the built-in default configuration
+	SourceDefault Source = iota
+	// SourceFile is the configuration file
+	SourceFile
+	// SourceEnv is an ANTIMOJI_* environment variable
+	SourceEnv
+	// SourceFlag is a command-line flag
+	SourceFlag
+	// SourcePolicy is a field locked by the organization policy (see OrgPolicy)
+	SourcePolicy
+)
+
+// String returns the name of the source.
+func (s Source) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	case SourcePolicy:
+		return "policy"
+	default:
+		return fmt.Sprintf("source(%d)", int(s))
+	}
+}
+
+// Override sets a single profile field from a layer above the configuration file.
+type Override struct {
+	// Key is the YAML name of the profile field, e.g. max_file_size
+	Key    string
+	Value  string
+	Source Source
+	// Origin describes where the override was given, for error messages
+	Origin string
+}
+
+// Resolution is a profile with all override layers applied.
+type Resolution struct {
+	Profile Profile
+	// Sources records the layer that supplied each field, keyed by YAML name
+	Sources map[string]Source
+	// Policy is the organization policy enforced on the profile, if any
+	Policy *OrgPolicy
+}
+
+// fieldAliases maps shorthand keys to profile fields.
+var fieldAliases = map[string]string{
+	"threshold": "max_total",
+}
+
+// profileFieldOrder lists the YAML names of Profile fields in declaration order, and
+// profileFields maps them to struct field indexes.
+var profileFieldOrder, profileFields = func() ([]string, map[string]int) {
+	var order []string
+	fields := make(map[string]int)
+	t := reflect.TypeOf(Profile{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			order = append(order, name)
+			fields[name] = i
+		}
+	}
+	return order, fields
+}()
+
+// ProfileFields returns the keys of all profile fields in declaration order.
+func ProfileFields() []string {
+	return append([]string(nil), profileFieldOrder...)
+}
+
+// FieldValue returns the value of the profile field named key (or one of its aliases).
+func FieldValue(profile Profile, key string) (interface{}, bool) {
+	key, ok := canonicalKey(key)
+	if !ok {
+		return nil, false
+	}
+	return reflect.ValueOf(profile).Field(profileFields[key]).Interface(), true
+}
+
+// EnvOverrides collects overrides from environment variables in os.Environ format.
+// Variables that do not name a profile field are ignored, since other settings such as
+// ANTIMOJI_CACHE_DIR share the prefix.
+func EnvOverrides(environ []string) []Override {
+	var overrides []Override
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+
+		key, known := canonicalKey(strings.ToLower(strings.TrimPrefix(name, EnvPrefix)))
+		if !known {
+			continue
+		}
+		overrides = append(overrides, Override{Key: key, Value: value, Source: SourceEnv, Origin: name})
+	}
+
+	// os.Environ order is unspecified; keep results stable
+	sort.SliceStable(overrides, func(i, j int) bool { return overrides[i].Origin < overrides[j].Origin })
+	return overrides
+}
+
+// ParseSetFlags parses key=value pairs given with --set. Unlike environment variables,
+// unknown keys are rejected because they can only be typos.
+func ParseSetFlags(values []string) ([]Override, error) {
+	overrides := make([]Override, 0, len(values))
+	for _, entry := range values {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q: expected key=value", entry)
+		}
+
+		canonical, known := canonicalKey(strings.TrimSpace(key))
+		if !known {
+			return nil, fmt.Errorf("invalid --set %q: unknown profile field %q", entry, key)
+		}
+		overrides = append(overrides, Override{Key: canonical, Value: value, Source: SourceFlag, Origin: "--set " + entry})
+	}
+	return overrides, nil
+}
+
+// Resolve applies override layers to a profile loaded from base. Overrides are applied
+// in precedence order (defaults < file < env < flags); within a layer, later overrides
+// win. The resolved profile is validated like a profile from a configuration file.
+func Resolve(profile Profile, base Source, overrides ...[]Override) types.Result[Resolution] {
+	resolution := Resolution{
+		Profile: cloneProfile(profile),
+		Sources: make(map[string]Source, len(profileFields)),
+	}
+	for key := range profileFields {
+		resolution.Sources[key] = base
+	}
+
+	var ordered []Override
+	for _, layer := range overrides {
+		ordered = append(ordered, layer...)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Source < ordered[j].Source })
+
+	for _, override := range ordered {
+		if err := setField(&resolution.Profile, override.Key, override.Value); err != nil {
+			return types.Err[Resolution](fmt.Errorf("%s: %w", override.Origin, err))
+		}
+		resolution.Sources[override.Key] = override.Source
+	}
+
+	if len(ordered) > 0 {
+		if err := validateProfile("resolved", resolution.Profile); err != nil {
+			return types.Err[Resolution](err)
+		}
+	}
+
+	return types.Ok(resolution)
+}
+
+// canonicalKey resolves aliases and reports whether key names a profile field.
+func canonicalKey(key string) (string, bool) {
+	if alias, ok := fieldAliases[key]; ok {
+		key = alias
+	}
+	_, ok := profileFields[key]
+	return key, ok
+}
+
+// setField parses value according to the type of the profile field named key.
+// Lists are comma separated and maps are comma separated key=value pairs; an empty
+// value clears them. Sizes accept units such as "10MB".
+func setField(profile *Profile, key, value string) error {
+	index, ok := profileFields[key]
+	if !ok {
+		return fmt.Errorf("unknown profile field %q", key)
+	}
+	return setValue(reflect.ValueOf(profile).Elem().Field(index), key, value)
+}
+
+// setValue parses value into field, a profile field or map entry named by key.
+func setValue(field reflect.Value, key, value string) error {
+	switch field.Kind() {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q", key, value)
+		}
+		field.SetBool(parsed)
+
+	case reflect.Int, reflect.Int64:
+		value = strings.TrimSpace(value)
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil && strings.HasSuffix(key, "_size") {
+			var size uint64
+			size, err = humanize.ParseBytes(value)
+			parsed = int64(size) // #nosec G115 - sizes beyond int64 wrap negative and are rejected below
+		}
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", key, value)
+		}
+		if field.OverflowInt(parsed) || (parsed < 0 && strings.HasSuffix(key, "_size")) {
+			return fmt.Errorf("%s: value %q out of range", key, value)
+		}
+		field.SetInt(parsed)
+
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", key, value)
+		}
+		field.SetFloat(parsed)
+
+	case reflect.String:
+		// Kept verbatim: whitespace can be meaningful, e.g. in replacement
+		field.SetString(value)
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%s: cannot be overridden", key)
+		}
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+
+	case reflect.Map:
+		entries := reflect.MakeMap(field.Type())
+		for _, pair := range strings.Split(value, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("%s: invalid entry %q, expected key=value", key, pair)
+			}
+			entry := reflect.New(field.Type().Elem()).Elem()
+			if err := setValue(entry, key, strings.TrimSpace(v)); err != nil {
+				return err
+			}
+			entries.SetMapIndex(reflect.ValueOf(strings.TrimSpace(k)), entry)
+		}
+		field.Set(entries)
+
+	default:
+		return fmt.Errorf("%s: cannot be overridden", key)
+	}
+
+	return nil
+}
+
+// cloneProfile copies a profile so overrides never modify slices or maps shared with
+// the loaded configuration.
+func cloneProfile(profile Profile) Profile {
+	clone := profile
+	v := reflect.ValueOf(&clone).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Slice:
+			if !field.IsNil() {
+				copied := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+				reflect.Copy(copied, field)
+				field.Set(copied)
+			}
+		case reflect.Map:
+			if !field.IsNil() {
+				copied := reflect.MakeMapWithSize(field.Type(), field.Len())
+				iter := field.MapRange()
+				for iter.Next() {
+					copied.SetMapIndex(iter.Key(), iter.Value())
+				}
+				field.Set(copied)
+			}
+		}
+	}
+	return clone
+}
+
+// LoadResolution loads profileName from configPath, or from the built-in defaults when
+// configPath is empty, and applies the override layers. Fields the configuration file
+// does not mention take the default profile's values and are attributed to the
+// defaults.
+func LoadResolution(configPath, profileName string, overrides ...[]Override) types.Result[Resolution] {
+	if profileName == "" {
+		profileName = "default"
+	}
+
+	cfg := DefaultConfig()
+	base := SourceDefault
+	var fileKeys map[string]bool
+	if configPath != "" {
+		source, err := readConfigSource(configPath)
+		if err != nil {
+			return types.Err[Resolution](fmt.Errorf("failed to read config: %w", err))
+		}
+		content := source.content
+
+		configResult := parseConfig(content)
+		if configResult.IsErr() {
+			return types.Err[Resolution](fmt.Errorf("failed to load config: %w", configResult.Error()))
+		}
+		cfg = configResult.Unwrap()
+		base = SourceFile
+
+		fileKeys, err = profileKeys(content, profileName)
+		if err != nil {
+			return types.Err[Resolution](err)
+		}
+	}
+
+	profileResult := GetProfile(cfg, profileName)
+	if profileResult.IsErr() {
+		return types.Err[Resolution](profileResult.Error())
+	}
+
+	profile := profileResult.Unwrap()
+	if base == SourceFile {
+		profile = mergeDefaults(profile, profileFieldOrder, func(key string) bool { return fileKeys[key] })
+	}
+
+	resolved := Resolve(profile, base, overrides...)
+	if resolved.IsErr() {
+		return resolved
+	}
+
+	resolution := resolved.Unwrap()
+	if base == SourceFile {
+		for key, source := range resolution.Sources {
+			if source == SourceFile && !fileKeys[key] {
+				resolution.Sources[key] = SourceDefault
+			}
+		}
+	}
+
+	return types.Ok(resolution)
+}
+
+// profileKeys returns the field keys set for a profile in raw YAML content. Profile
+// names are matched case-insensitively, as Viper lower-cases them.
+func profileKeys(content []byte, profileName string) (map[string]bool, error) {
+	var raw struct {
+		Profiles map[string]map[string]interface{} `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	keys := make(map[string]bool)
+	for name, fields := range raw.Profiles {
+		if !strings.EqualFold(name, profileName) {
+			continue
+		}
+		for key := range fields {
+			keys[key] = true
+		}
+	}
+	return keys, nil
+}
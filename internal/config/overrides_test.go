@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	overrides := EnvOverrides([]string{
+		"PATH=/usr/bin",
+		"ANTIMOJI_MAX_FILE_SIZE=10MB",
+		"ANTIMOJI_THRESHOLD=3",
+		"ANTIMOJI_CACHE_DIR=/tmp/cache", // not a profile field
+		"ANTIMOJI_EMOJI_ALLOWLIST=✅,❌",
+	})
+
+	assert.Equal(t, []Override{
+		{Key: "emoji_allowlist", Value: "✅,❌", Source: SourceEnv, Origin: "ANTIMOJI_EMOJI_ALLOWLIST"},
+		{Key: "max_file_size", Value: "10MB", Source: SourceEnv, Origin: "ANTIMOJI_MAX_FILE_SIZE"},
+		{Key: "max_total", Value: "3", Source: SourceEnv, Origin: "ANTIMOJI_THRESHOLD"},
+	}, overrides)
+}
+
+func TestParseSetFlags(t *testing.T) {
+	overrides, err := ParseSetFlags([]string{"recursive=false", "threshold=2"})
+	require.NoError(t, err)
+	assert.Equal(t, "recursive", overrides[0].Key)
+	assert.Equal(t, "max_total", overrides[1].Key)
+	assert.Equal(t, SourceFlag, overrides[1].Source)
+
+	_, err = ParseSetFlags([]string{"recursive"})
+	assert.ErrorContains(t, err, "expected key=value")
+
+	_, err = ParseSetFlags([]string{"recursiv=true"})
+	assert.ErrorContains(t, err, "unknown profile field")
+}
+
+func TestResolve(t *testing.T) {
+	base := DefaultConfig().Profiles["default"]
+
+	t.Run("flags beat env beat file", func(t *testing.T) {
+		env := []Override{
+			{Key: "max_emoji_threshold", Value: "3", Source: SourceEnv, Origin: "ANTIMOJI_THRESHOLD"},
+			{Key: "max_file_size", Value: "10MB", Source: SourceEnv, Origin: "ANTIMOJI_MAX_FILE_SIZE"},
+		}
+		flags := []Override{{Key: "max_emoji_threshold", Value: "7", Source: SourceFlag, Origin: "--set threshold=7"}}
+
+		// Layers are ordered by source, not by argument order
+		resolution := Resolve(base, SourceFile, flags, env).Unwrap()
+
+		assert.Equal(t, 7, resolution.Profile.MaxEmojiThreshold)
+		assert.Equal(t, int64(10_000_000), resolution.Profile.MaxFileSize)
+		assert.Equal(t, SourceFlag, resolution.Sources["max_emoji_threshold"])
+		assert.Equal(t, SourceEnv, resolution.Sources["max_file_size"])
+		assert.Equal(t, SourceFile, resolution.Sources["recursive"])
+	})
+
+	t.Run("parses every field kind", func(t *testing.T) {
+		overrides, err := ParseSetFlags([]string{
+			"recursive=false",
+			"max_workers=4",
+			"output_format=json",
+			"replacement= [x] ",
+			"emoji_allowlist=✅, ❌,",
+			"replacement_map=🚀=[launch],🐛=[bug]",
+			"binary_control_ratio=0.5",
+			"binary_sample_size=4KB",
+			"emoji_thresholds=✅=3,😂=0",
+		})
+		require.NoError(t, err)
+
+		profile := Resolve(base, SourceDefault, overrides).Unwrap().Profile
+		assert.False(t, profile.Recursive)
+		assert.Equal(t, 4, profile.MaxWorkers)
+		assert.Equal(t, "json", profile.OutputFormat)
+		assert.Equal(t, " [x] ", profile.Replacement)
+		assert.Equal(t, []string{"✅", "❌"}, profile.EmojiAllowlist)
+		assert.Equal(t, map[string]string{"🚀": "[launch]", "🐛": "[bug]"}, profile.ReplacementMap)
+		assert.InDelta(t, 0.5, profile.BinaryControlRatio, 1e-9)
+		assert.Equal(t, 4000, profile.BinarySampleSize)
+		assert.Equal(t, map[string]int{"✅": 3, "😂": 0}, profile.EmojiThresholds)
+	})
+
+	t.Run("does not modify the base profile", func(t *testing.T) {
+		original := append([]string(nil), base.FileIgnoreList...)
+		overrides, err := ParseSetFlags([]string{"file_ignore_list=*.tmp"})
+		require.NoError(t, err)
+
+		resolution := Resolve(base, SourceDefault, overrides).Unwrap()
+		assert.Equal(t, []string{"*.tmp"}, resolution.Profile.FileIgnoreList)
+		assert.Equal(t, original, base.FileIgnoreList)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		for _, set := range []string{"recursive=maybe", "max_workers=many", "max_file_size=-1", "max_workers=-1", "output_format=xml", "binary_null_ratio=1.5", "binary_control_ratio=high", "max_per_file=-1", "emoji_thresholds=✅=many", "directory_thresholds=docs=-1"} {
+			overrides, err := ParseSetFlags([]string{set})
+			require.NoError(t, err)
+			assert.True(t, Resolve(base, SourceDefault, overrides).IsErr(), set)
+		}
+	})
+}
+
+func TestLoadResolution(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`profiles:
+  CI:
+    recursive: false
+    max_emoji_threshold: 2
+`), 0600))
+
+	env := []Override{{Key: "max_emoji_threshold", Value: "4", Source: SourceEnv, Origin: "ANTIMOJI_THRESHOLD"}}
+	resolution := LoadResolution(path, "ci", env).Unwrap()
+
+	assert.False(t, resolution.Profile.Recursive)
+	assert.Equal(t, 4, resolution.Profile.MaxEmojiThreshold)
+	assert.Equal(t, SourceFile, resolution.Sources["recursive"])
+	assert.Equal(t, SourceEnv, resolution.Sources["max_emoji_threshold"])
+	assert.Equal(t, SourceDefault, resolution.Sources["buffer_size"])
+
+	// Fields the file leaves out show the default profile's values, not zero values
+	defaults := DefaultConfig().Profiles["default"]
+	assert.Equal(t, SourceDefault, resolution.Sources["unicode_emojis"])
+	assert.Equal(t, defaults.UnicodeEmojis, resolution.Profile.UnicodeEmojis)
+	assert.Equal(t, defaults.SymlinkPolicy, resolution.Profile.SymlinkPolicy)
+
+	t.Run("defaults without a config file", func(t *testing.T) {
+		resolution := LoadResolution("", "", nil).Unwrap()
+		assert.Equal(t, SourceDefault, resolution.Sources["recursive"])
+		assert.True(t, resolution.Profile.Recursive)
+	})
+
+	t.Run("missing profile", func(t *testing.T) {
+		assert.True(t, LoadResolution(path, "nope").IsErr())
+	})
+}
@@ -0,0 +1,525 @@
+// Package config provides in-place editing of configuration files that preserves comments and layout.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AddToAllowlist appends emojis to a profile's emoji_allowlist in the given configuration file.
+// The file is created when missing, and comments and key order are preserved otherwise.
+// It returns the emojis that were actually added (entries already present are skipped).
+func AddToAllowlist(configPath, profileName string, emojis []string) ([]string, error) {
+	if profileName == "" {
+		profileName = "default"
+	}
+
+	doc, perm, err := readYAMLDocument(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := profileNode(doc, profileName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	allowlist := mappingChild(profile, "emoji_allowlist", yaml.SequenceNode)
+	if allowlist.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s: profiles.%s.emoji_allowlist is not a list", configPath, profileName)
+	}
+
+	// New entries are written like the existing ones, in the list's own style
+	style := yaml.DoubleQuotedStyle
+	existing := make(map[string]bool, len(allowlist.Content))
+	for _, item := range allowlist.Content {
+		existing[item.Value] = true
+		style = item.Style
+	}
+
+	var added []string
+	for _, emoji := range emojis {
+		if emoji == "" || existing[emoji] {
+			continue
+		}
+		existing[emoji] = true
+		allowlist.Content = append(allowlist.Content, &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Tag:   "!!str",
+			Value: emoji,
+			Style: style,
+		})
+		added = append(added, emoji)
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if err := writeYAMLDocument(configPath, doc, perm); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// RemoveFromAllowlist removes emojis from a profile's emoji_allowlist in the given
+// configuration file, preserving comments and key order. It returns the emojis that
+// were actually removed; entries the profile only inherits are left alone.
+func RemoveFromAllowlist(configPath, profileName string, emojis []string) ([]string, error) {
+	if profileName == "" {
+		profileName = "default"
+	}
+
+	doc, perm, err := readYAMLDocument(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := profileNode(doc, profileName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+	if !hasMappingKey(profile, "emoji_allowlist") {
+		return nil, nil
+	}
+
+	allowlist := mappingChild(profile, "emoji_allowlist", yaml.SequenceNode)
+	if allowlist.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s: profiles.%s.emoji_allowlist is not a list", configPath, profileName)
+	}
+
+	remove := make(map[string]bool, len(emojis))
+	for _, emoji := range emojis {
+		remove[emoji] = true
+	}
+
+	var removed []string
+	kept := allowlist.Content[:0]
+	for _, item := range allowlist.Content {
+		if remove[item.Value] {
+			removed = append(removed, item.Value)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	allowlist.Content = kept
+
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	if err := writeYAMLDocument(configPath, doc, perm); err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// SetValue sets a profile field in the given configuration file. path has the form
+// profiles.<name>.<field>, e.g. profiles.ci.max_total, and value is parsed
+// like a --set override. The file is created when missing, comments and key order
+// are preserved, and nothing is written unless the result is a valid configuration.
+func SetValue(configPath, path, value string) error {
+	profileName, key, err := ParseFieldPath(path)
+	if err != nil {
+		return err
+	}
+
+	var parsed Profile
+	if err := setField(&parsed, key, value); err != nil {
+		return err
+	}
+	fieldValue, _ := FieldValue(parsed, key)
+
+	doc, perm, err := readYAMLDocument(configPath)
+	if err != nil {
+		return err
+	}
+
+	profile, err := profileNode(doc, profileName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", configPath, err)
+	}
+	setMappingValue(profile, key, valueNode(reflect.ValueOf(fieldValue)))
+
+	content, err := encodeYAMLDocument(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", configPath, err)
+	}
+
+	configResult := parseConfig(content)
+	if configResult.IsErr() {
+		return fmt.Errorf("resulting configuration is invalid: %w", configResult.Error())
+	}
+	if validated := ValidateConfig(configResult.Unwrap()); validated.IsErr() {
+		return fmt.Errorf("resulting configuration is invalid: %w", validated.Error())
+	}
+
+	return os.WriteFile(configPath, content, perm)
+}
+
+// ProfileUpdate is a profile UpdateProfiles writes: the fields Keys of Profile, under
+// profiles.<Name>.
+type ProfileUpdate struct {
+	Name    string
+	Profile Profile
+	Keys    []string
+}
+
+// UpdateProfiles writes updates to the given configuration file, adding the profiles
+// that are missing, and puts rules first among its routing rules, replacing the
+// rules that route to an updated profile. The file is created when missing, comments,
+// key order and the other profiles and rules are preserved, and nothing is written
+// unless the result is a valid configuration. It returns the content of the file
+// before and after, which are equal when nothing changed.
+func UpdateProfiles(configPath string, updates []ProfileUpdate, rules []RoutingRule) (before, after []byte, err error) {
+	before, err = os.ReadFile(configPath) // #nosec G304 - path comes from user configuration
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	doc, perm, err := readYAMLDocument(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated := make(map[string]bool, len(updates))
+	for _, update := range updates {
+		mapping, err := profileNode(doc, update.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", configPath, err)
+		}
+		for _, key := range update.Keys {
+			value, ok := FieldValue(update.Profile, key)
+			if !ok {
+				return nil, nil, fmt.Errorf("unknown profile field %q", key)
+			}
+			setMappingValue(mapping, key, valueNode(reflect.ValueOf(value)))
+		}
+		updated[update.Name] = true
+	}
+	if err := mergeRules(doc, rules, updated); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	after, err = encodeYAMLDocument(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode %s: %w", configPath, err)
+	}
+	configResult := parseConfig(after)
+	if configResult.IsErr() {
+		return nil, nil, fmt.Errorf("resulting configuration is invalid: %w", configResult.Error())
+	}
+	if validated := ValidateConfig(configResult.Unwrap()); validated.IsErr() {
+		return nil, nil, fmt.Errorf("resulting configuration is invalid: %w", validated.Error())
+	}
+	if bytes.Equal(before, after) {
+		return before, after, nil
+	}
+	return before, after, os.WriteFile(configPath, after, perm)
+}
+
+// mergeRules puts rules before the routing rules of doc that do not route to an
+// updated profile, dropping the others. A document without rules gets none when rules
+// is empty.
+func mergeRules(doc *yaml.Node, rules []RoutingRule, updated map[string]bool) error {
+	root := doc.Content[0]
+	if len(rules) == 0 && !hasMappingKey(root, "rules") {
+		return nil
+	}
+	sequence := mappingChild(root, "rules", yaml.SequenceNode)
+	if sequence.Kind != yaml.SequenceNode {
+		return fmt.Errorf("rules is not a list")
+	}
+
+	content := make([]*yaml.Node, 0, len(rules)+len(sequence.Content))
+	for _, rule := range rules {
+		paths := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, path := range rule.Paths {
+			paths.Content = append(paths.Content, stringNode(path))
+		}
+		content = append(content, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "paths"}, paths,
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "profile"}, {Kind: yaml.ScalarNode, Tag: "!!str", Value: rule.Profile},
+		}})
+	}
+	for _, existing := range sequence.Content {
+		var rule RoutingRule
+		if existing.Decode(&rule) == nil && updated[rule.Profile] {
+			continue
+		}
+		content = append(content, existing)
+	}
+	sequence.Content = content
+	return nil
+}
+
+// hasMappingKey reports whether mapping has key.
+func hasMappingKey(mapping *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFieldPath splits a path of the form profiles.<name>.<field> into the profile
+// name and the canonical field key.
+func ParseFieldPath(path string) (profileName, key string, err error) {
+	rest, ok := strings.CutPrefix(path, "profiles.")
+	dot := strings.LastIndex(rest, ".")
+	if !ok || dot <= 0 || dot == len(rest)-1 {
+		return "", "", fmt.Errorf("invalid path %q: expected profiles.<name>.<field>", path)
+	}
+
+	key, known := canonicalKey(rest[dot+1:])
+	if !known {
+		return "", "", fmt.Errorf("invalid path %q: unknown profile field %q", path, rest[dot+1:])
+	}
+	return rest[:dot], key, nil
+}
+
+// setMappingValue replaces the value for key in a mapping, keeping any comment on the
+// old value, or appends the key when absent.
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			old := mapping.Content[i+1]
+			value.LineComment = old.LineComment
+			value.FootComment = old.FootComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value)
+}
+
+// valueNode encodes a profile field value as a YAML node.
+func valueNode(v reflect.Value) *yaml.Node {
+	switch v.Kind() {
+	case reflect.Bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v.Bool())}
+	case reflect.Int, reflect.Int64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(v.Int(), 10)}
+	case reflect.Float64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: strconv.FormatFloat(v.Float(), 'g', -1, 64)}
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if v.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for i := 0; i < v.Len(); i++ {
+			node.Content = append(node.Content, stringNode(v.Index(i).String()))
+		}
+		return node
+	case reflect.Map:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if v.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			node.Content = append(node.Content, stringNode(k), valueNode(v.MapIndex(reflect.ValueOf(k))))
+		}
+		return node
+	default:
+		return stringNode(v.String())
+	}
+}
+
+// stringNode returns a double-quoted string scalar, the style used for emojis.
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: yaml.DoubleQuotedStyle}
+}
+
+// readYAMLDocument reads a YAML file into a document node, returning a new document stamped
+// with the current schema version when missing.
+func readYAMLDocument(path string) (*yaml.Node, os.FileMode, error) {
+	perm := os.FileMode(0644)
+
+	content, err := os.ReadFile(path) // #nosec G304 - path comes from user configuration
+	if os.IsNotExist(err) {
+		return newYAMLDocument(), perm, nil
+	}
+	if err != nil {
+		return nil, perm, err
+	}
+
+	if stat, err := os.Stat(path); err == nil {
+		perm = stat.Mode().Perm()
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, perm, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		return newYAMLDocument(), perm, nil
+	}
+
+	return &doc, perm, nil
+}
+
+// newYAMLDocument returns the document for a new configuration file.
+func newYAMLDocument() *yaml.Node {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	stampVersion(root)
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+}
+
+// writeYAMLDocument encodes a document node back to disk.
+func writeYAMLDocument(path string, doc *yaml.Node, perm os.FileMode) error {
+	content, err := encodeYAMLDocument(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+
+	return os.WriteFile(path, content, perm)
+}
+
+// encodeYAMLDocument encodes a document node with the repository's two-space indent.
+func encodeYAMLDocument(doc *yaml.Node) ([]byte, error) {
+	return encodeYAMLDocumentIndent(doc, 2)
+}
+
+// encodeYAMLDocumentIndent encodes a document node with the given indent.
+func encodeYAMLDocumentIndent(doc *yaml.Node, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return literalAstral(buf.Bytes()), nil
+}
+
+// literalAstral writes the \UXXXXXXXX escapes of double-quoted scalars as the characters
+// they stand for. The YAML encoder escapes every character outside the Basic
+// Multilingual Plane, which turns most emojis into unreadable escapes, although YAML
+// allows them in double-quoted scalars as they are.
+func literalAstral(content []byte) []byte {
+	if !bytes.Contains(content, []byte(`\U`)) {
+		return content
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(content))
+	inDouble, inSingle, inComment := false, false, false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\n':
+			inComment = false
+		case inComment:
+		case inDouble && c == '\\':
+			if r, ok := astralEscape(content[i:]); ok {
+				out.WriteRune(r)
+				i += len(`\U`) + 7
+				continue
+			}
+			// Copy the escaped character too, so that \\ and \" are not read again
+			if i+1 < len(content) {
+				out.WriteByte(c)
+				i++
+				c = content[i]
+			}
+		case inDouble:
+			inDouble = c != '"'
+		case inSingle:
+			// A doubled quote is an escaped one and toggles twice
+			inSingle = c != '\''
+		case c == '#' && (i == 0 || content[i-1] == ' ' || content[i-1] == '\t'):
+			inComment = true
+		case c == '"' || c == '\'':
+			if startsScalar(content[:i]) {
+				inDouble, inSingle = c == '"', c == '\''
+			}
+		}
+		out.WriteByte(c)
+	}
+	return out.Bytes()
+}
+
+// astralEscape decodes the \UXXXXXXXX escape content starts with when it stands for a
+// character outside the Basic Multilingual Plane.
+func astralEscape(content []byte) (rune, bool) {
+	const length = len(`\U`) + 8
+	if len(content) < length || content[1] != 'U' {
+		return 0, false
+	}
+	code, err := strconv.ParseUint(string(content[2:length]), 16, 32)
+	if err != nil || code < 0x10000 || code > unicode.MaxRune {
+		return 0, false
+	}
+	return rune(code), true
+}
+
+// startsScalar reports whether a quote following before starts a quoted scalar: it is
+// the first character of a line or follows an indicator such as ": " or "- ".
+func startsScalar(before []byte) bool {
+	trimmed := bytes.TrimRight(before, " \t")
+	if len(trimmed) == 0 {
+		return true
+	}
+	return strings.IndexByte(":-[{,?\n", trimmed[len(trimmed)-1]) >= 0
+}
+
+// profileNode returns the mapping node for profiles.<name>, creating it when needed.
+func profileNode(doc *yaml.Node, name string) (*yaml.Node, error) {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("configuration root is not a mapping")
+	}
+
+	profiles := mappingChild(doc.Content[0], "profiles", yaml.MappingNode)
+	if profiles.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("profiles is not a mapping")
+	}
+
+	profile := mappingChild(profiles, name, yaml.MappingNode)
+	if profile.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("profiles.%s is not a mapping", name)
+	}
+
+	return profile, nil
+}
+
+// mappingChild returns the value node for key in a mapping, appending an empty node of kind when absent.
+func mappingChild(mapping *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			// Treat explicit nulls (e.g. "emoji_allowlist:") as empty containers
+			if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+				value.Kind = kind
+				value.Tag = ""
+				value.Value = ""
+			}
+			return value
+		}
+	}
+
+	value := &yaml.Node{Kind: kind}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value)
+	return value
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddToAllowlist(t *testing.T) {
+	t.Run("creates missing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+
+		added, err := AddToAllowlist(path, "ci", []string{"✅", "🚀"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"✅", "🚀"}, added)
+
+		cfg := LoadConfig(path).Unwrap()
+		assert.Equal(t, []string{"✅", "🚀"}, cfg.Profiles["ci"].EmojiAllowlist)
+	})
+
+	t.Run("preserves comments and skips duplicates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		original := `# team config
+profiles:
+  default:
+    # keep these
+    emoji_allowlist:
+      - "✅"
+    max_emoji_threshold: 3
+`
+		require.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+		added, err := AddToAllowlist(path, "default", []string{"✅", "🚀"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"🚀"}, added)
+
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), "# team config")
+		assert.Contains(t, string(raw), "# keep these")
+
+		stat, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+		cfg := LoadConfig(path).Unwrap()
+		assert.Equal(t, []string{"✅", "🚀"}, cfg.Profiles["default"].EmojiAllowlist)
+		assert.Equal(t, 3, cfg.Profiles["default"].MaxEmojiThreshold)
+	})
+
+	t.Run("handles empty allowlist key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("profiles:\n  default:\n    emoji_allowlist:\n"), 0644))
+
+		_, err := AddToAllowlist(path, "default", []string{"🎉"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"🎉"}, LoadConfig(path).Unwrap().Profiles["default"].EmojiAllowlist)
+	})
+
+	t.Run("rejects non-list allowlist", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("profiles:\n  default:\n    emoji_allowlist: nope\n"), 0644))
+
+		_, err := AddToAllowlist(path, "default", []string{"🎉"})
+		assert.Error(t, err)
+	})
+}
+
+func TestAllowlistRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := `# team config
+profiles:
+  default:
+    emoji_allowlist: ["✅", "🎉"] # team emojis
+    max_total: 2
+`
+	require.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+	// The list keeps its flow style and comment, and emojis are written as they are
+	_, err := AddToAllowlist(path, "default", []string{"🚀"})
+	require.NoError(t, err)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# team config
+profiles:
+  default:
+    emoji_allowlist: ["✅", "🎉", "🚀"] # team emojis
+    max_total: 2
+`, string(raw))
+
+	_, err = RemoveFromAllowlist(path, "default", []string{"🚀"})
+	require.NoError(t, err)
+	raw, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(raw))
+}
+
+func TestLiteralAstral(t *testing.T) {
+	for input, want := range map[string]string{
+		`a: "\U0001F680 go"` + "\n":    `a: "🚀 go"` + "\n",
+		`- "\\U0001F680"` + "\n":       `- "\\U0001F680"` + "\n",
+		`a: b\U0001F680` + "\n":        `a: b\U0001F680` + "\n",
+		`a: 'x' # "\U0001F680"` + "\n": `a: 'x' # "\U0001F680"` + "\n",
+		`a: "\u00e9\U0001F389"` + "\n": `a: "\u00e9🎉"` + "\n",
+	} {
+		assert.Equal(t, want, string(literalAstral([]byte(input))), input)
+	}
+}
+
+func TestRemoveFromAllowlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`# team config
+profiles:
+  default:
+    # keep these
+    emoji_allowlist: ["✅", "🚀", "🎉"]
+  ci:
+    max_total: 1
+`), 0600))
+
+	removed, err := RemoveFromAllowlist(path, "default", []string{"🚀", "🎉", "🔥"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"🚀", "🎉"}, removed)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "# keep these")
+	assert.Equal(t, []string{"✅"}, LoadConfig(path).Unwrap().Profiles["default"].EmojiAllowlist)
+
+	// A profile without its own allowlist is left alone
+	removed, err = RemoveFromAllowlist(path, "ci", []string{"✅"})
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, raw, after)
+}
+
+func TestSetValue(t *testing.T) {
+	original := `# team config
+profiles:
+  ci:
+    # CI tolerance
+    max_emoji_threshold: 0 # zero tolerance
+    recursive: true
+`
+
+	t.Run("updates a field and preserves comments", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+		require.NoError(t, SetValue(path, "profiles.ci.max_emoji_threshold", "3"))
+		require.NoError(t, SetValue(path, "profiles.ci.emoji_allowlist", "✅,❌"))
+
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), "# team config")
+		assert.Contains(t, string(raw), "# CI tolerance")
+		assert.Contains(t, string(raw), "max_emoji_threshold: 3 # zero tolerance")
+
+		profile := LoadConfig(path).Unwrap().Profiles["ci"]
+		assert.Equal(t, 3, profile.MaxEmojiThreshold)
+		assert.True(t, profile.Recursive)
+		assert.Equal(t, []string{"✅", "❌"}, profile.EmojiAllowlist)
+	})
+
+	t.Run("parses sizes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, SetValue(path, "profiles.default.max_file_size", "1MB"))
+		assert.Equal(t, int64(1_000_000), LoadConfig(path).Unwrap().Profiles["default"].MaxFileSize)
+	})
+
+	t.Run("writes maps of counts", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, SetValue(path, "profiles.default.emoji_thresholds", "✅=3,😂=0"))
+
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), `"✅": 3`)
+		assert.Equal(t, map[string]int{"✅": 3, "😂": 0}, LoadConfig(path).Unwrap().Profiles["default"].EmojiThresholds)
+	})
+
+	t.Run("rejects invalid input without writing", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+		assert.Error(t, SetValue(path, "profiles.ci.max_emoji_threshold", "many"))
+		assert.Error(t, SetValue(path, "profiles.ci.max_emoji_threshold", "-1"))
+		assert.Error(t, SetValue(path, "profiles.ci.no_such_field", "1"))
+		assert.Error(t, SetValue(path, "ci.recursive", "true"))
+
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, original, string(raw))
+	})
+}
+
+func TestParseFieldPath(t *testing.T) {
+	profileName, key, err := ParseFieldPath("profiles.ci.threshold")
+	require.NoError(t, err)
+	assert.Equal(t, "ci", profileName)
+	assert.Equal(t, "max_total", key)
+
+	for _, path := range []string{"", "profiles", "profiles.ci", "profiles..recursive", "profiles.ci.", "other.ci.recursive"} {
+		_, _, err := ParseFieldPath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestUpdateProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("# team config\nprofiles:\n  default:\n    max_total: 3\n"), 0600))
+
+	before, after, err := UpdateProfiles(path, []ProfileUpdate{
+		{Name: "ci", Profile: Profile{EmojiAllowlist: []string{"✅"}, MaxTotal: 9}, Keys: []string{"emoji_allowlist"}},
+	}, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+	assert.Equal(t, after, mustRead(t, path))
+	assert.Contains(t, string(after), "# team config")
+
+	cfg := LoadConfig(path).Unwrap()
+	assert.Equal(t, 3, cfg.Profiles["default"].MaxTotal)
+	assert.Equal(t, []string{"✅"}, cfg.Profiles["ci"].EmojiAllowlist)
+	assert.Zero(t, cfg.Profiles["ci"].MaxTotal, "only the listed fields are merged")
+
+	assert.NotContains(t, string(after), "rules:")
+
+	_, _, err = UpdateProfiles(path, []ProfileUpdate{{Name: "ci", Keys: []string{"no_such_field"}}}, nil)
+	assert.ErrorContains(t, err, "unknown profile field")
+	_, _, err = UpdateProfiles(path, []ProfileUpdate{{Name: "ci", Profile: Profile{FileIgnoreList: []string{"["}}, Keys: []string{"file_ignore_list"}}}, nil)
+	assert.ErrorContains(t, err, "resulting configuration is invalid")
+	assert.Equal(t, after, mustRead(t, path), "invalid results are not written")
+}
+
+func TestUpdateProfiles_Rules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`rules:
+  - paths: ["vendor/**"]
+    profile: default
+  - paths: ["old/**"]
+    profile: ci-old
+profiles:
+  default: {}
+  ci-old: {}
+`), 0600))
+
+	docs := ProfileUpdate{Name: "ci-docs", Profile: Profile{EmojiAllowlist: []string{"✅"}}, Keys: []string{"emoji_allowlist"}}
+	old := ProfileUpdate{Name: "ci-old", Keys: []string{"emoji_allowlist"}}
+	_, _, err := UpdateProfiles(path, []ProfileUpdate{docs, old}, []RoutingRule{{Paths: []string{"docs/**"}, Profile: "ci-docs"}})
+	require.NoError(t, err)
+
+	cfg := LoadConfig(path).Unwrap()
+	assert.Equal(t, []RoutingRule{
+		{Paths: []string{"docs/**"}, Profile: "ci-docs"},
+		{Paths: []string{"vendor/**"}, Profile: "default"},
+	}, cfg.Rules, "rules to updated profiles are replaced")
+
+	_, _, err = UpdateProfiles(path, nil, []RoutingRule{{Paths: []string{"x/**"}, Profile: "missing"}})
+	assert.ErrorContains(t, err, "profile not found: missing")
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return raw
+}
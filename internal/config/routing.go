@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+	"gopkg.in/yaml.v3"
+)
+
+// RoutingRule checks the files matching Paths under Profile instead of the profile a
+// command selects, so that one run holds docs and code to different profiles. Paths
+// are patterns such as docs/** matched against the whole path, or such as *.go
+// matched against the file name.
+type RoutingRule struct {
+	Paths   []string `yaml:"paths" json:"paths"`
+	Profile string   `yaml:"profile" json:"profile"`
+}
+
+// loadRules decodes the rules section of the raw YAML.
+func loadRules(content []byte) ([]RoutingRule, error) {
+	var raw struct {
+		Rules []RoutingRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	return raw.Rules, nil
+}
+
+// validateRules checks that each routing rule of config has valid path patterns and
+// routes to one of its profiles.
+func validateRules(config Config) error {
+	for i, rule := range config.Rules {
+		if len(rule.Paths) == 0 {
+			return fmt.Errorf("rules[%d]: no paths", i)
+		}
+		for _, pattern := range rule.Paths {
+			if err := pathmatch.Validate(pattern); err != nil {
+				return fmt.Errorf("rules[%d]: %w", i, err)
+			}
+		}
+		if rule.Profile == "" {
+			return fmt.Errorf("rules[%d]: no profile", i)
+		}
+		if _, ok := config.Profiles[rule.Profile]; !ok {
+			return fmt.Errorf("rules[%d]: profile not found: %s", i, rule.Profile)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_Rules(t *testing.T) {
+	content := `rules:
+  - paths: ["docs/**"]
+    profile: permissive
+  - paths: ["**/*.go", "cmd/**"]
+    profile: zero
+profiles:
+  permissive:
+    emoji_allowlist: ["🚀"]
+  zero:
+    max_total: 0
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	config := LoadConfigStrict(configPath).Unwrap()
+	assert.Equal(t, []RoutingRule{
+		{Paths: []string{"docs/**"}, Profile: "permissive"},
+		{Paths: []string{"**/*.go", "cmd/**"}, Profile: "zero"},
+	}, config.Rules)
+	assert.True(t, ValidateConfig(config).IsOk())
+}
+
+func TestValidateRules(t *testing.T) {
+	profiles := map[string]Profile{"docs": {}}
+	tests := []struct {
+		name  string
+		rules []RoutingRule
+		err   string
+	}{
+		{"valid", []RoutingRule{{Paths: []string{"docs/**"}, Profile: "docs"}}, ""},
+		{"no paths", []RoutingRule{{Profile: "docs"}}, "rules[0]: no paths"},
+		{"invalid pattern", []RoutingRule{{Paths: []string{"docs/["}, Profile: "docs"}}, "rules[0]"},
+		{"no profile", []RoutingRule{{Paths: []string{"*.md"}}}, "rules[0]: no profile"},
+		{"unknown profile", []RoutingRule{
+			{Paths: []string{"*.md"}, Profile: "docs"},
+			{Paths: []string{"*.go"}, Profile: "zero"},
+		}, "rules[1]: profile not found: zero"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRules(Config{Profiles: profiles, Rules: tt.rules})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -81,10 +81,10 @@ func (tr *TemplateRegistry) registerBuiltInTemplates() {
 			CustomPatterns: []string{"", "", "", "", "", "", "", ":x:"},
 
 			// Zero tolerance policy
-			EmojiAllowlist:    []string{}, // No emojis allowed
-			MaxEmojiThreshold: 0,
-			FailOnFound:       true,
-			ExitCodeOnFound:   1,
+			EmojiAllowlist:  []string{}, // No emojis allowed
+			MaxTotal:        0,
+			FailOnFound:     true,
+			ExitCodeOnFound: 1,
 
 			// File filtering - focus on source code
 			IncludePatterns: []string{
@@ -103,7 +103,7 @@ func (tr *TemplateRegistry) registerBuiltInTemplates() {
 				"README.md", "CHANGELOG.md", "*.md", "docs/**/*",
 			},
 			DirectoryIgnoreList: []string{
-				".git", "node_modules", "vendor", "dist", "build", "docs",
+				".git", ".antimoji", "node_modules", "vendor", "dist", "build", "docs",
 			},
 
 			// Performance
@@ -131,10 +131,10 @@ func (tr *TemplateRegistry) registerBuiltInTemplates() {
 			CustomPatterns: []string{"", "", "", "", "", "", "", ":x:"},
 
 			// Allow-list policy (will be customized)
-			EmojiAllowlist:    []string{"", ""}, // Default allowlist
-			MaxEmojiThreshold: 5,
-			FailOnFound:       true,
-			ExitCodeOnFound:   1,
+			EmojiAllowlist:  []string{"", ""}, // Default allowlist
+			MaxTotal:        5,
+			FailOnFound:     true,
+			ExitCodeOnFound: 1,
 
 			// File filtering - same as zero tolerance
 			IncludePatterns: []string{
@@ -153,7 +153,7 @@ func (tr *TemplateRegistry) registerBuiltInTemplates() {
 				"README.md", "CHANGELOG.md", "*.md", "docs/**/*",
 			},
 			DirectoryIgnoreList: []string{
-				".git", "node_modules", "vendor", "dist", "build", "docs",
+				".git", ".antimoji", "node_modules", "vendor", "dist", "build", "docs",
 			},
 
 			// Performance
@@ -174,7 +174,7 @@ func (tr *TemplateRegistry) registerBuiltInTemplates() {
 
 			// Apply custom threshold
 			if options.Threshold > 0 {
-				profile.MaxEmojiThreshold = options.Threshold
+				profile.MaxTotal = options.Threshold
 			}
 
 			return profile
@@ -198,9 +198,9 @@ func (tr *TemplateRegistry) registerBuiltInTemplates() {
 				"", "", "", "", "", "", "", "", "", "",
 				"", "", "", "", "", "", "", "", "", "",
 			},
-			MaxEmojiThreshold: 20,
-			FailOnFound:       false, // Don't fail, just warn
-			ExitCodeOnFound:   0,     // Don't exit with error
+			MaxTotal:        20,
+			FailOnFound:     false, // Don't fail, just warn
+			ExitCodeOnFound: 0,     // Don't exit with error
 
 			// More lenient file filtering
 			IncludePatterns: []string{
@@ -216,7 +216,7 @@ func (tr *TemplateRegistry) registerBuiltInTemplates() {
 				".git/**/*", "**/*.generated.*", "**/*.pb.go", "**/wire_gen.go",
 			},
 			DirectoryIgnoreList: []string{
-				".git", "node_modules", "vendor", "dist", "build",
+				".git", ".antimoji", "node_modules", "vendor", "dist", "build",
 			},
 
 			// Performance
@@ -37,7 +37,7 @@ func TestTemplateRegistry_ApplyTemplate(t *testing.T) {
 		assert.True(t, profile.Recursive)
 		assert.True(t, profile.UnicodeEmojis)
 		assert.True(t, profile.TextEmoticons)
-		assert.Equal(t, 0, profile.MaxEmojiThreshold) // Zero tolerance ignores threshold
+		assert.Equal(t, 0, profile.MaxTotal) // Zero tolerance ignores threshold
 		assert.True(t, profile.FailOnFound)
 		assert.Equal(t, 1, profile.ExitCodeOnFound)
 		assert.Empty(t, profile.EmojiAllowlist) // Zero tolerance has empty allowlist
@@ -55,7 +55,7 @@ func TestTemplateRegistry_ApplyTemplate(t *testing.T) {
 		assert.True(t, profile.Recursive)
 		assert.True(t, profile.UnicodeEmojis)
 		assert.True(t, profile.TextEmoticons)
-		assert.Equal(t, 10, profile.MaxEmojiThreshold) // Custom threshold applied
+		assert.Equal(t, 10, profile.MaxTotal) // Custom threshold applied
 		assert.True(t, profile.FailOnFound)
 		assert.Equal(t, 1, profile.ExitCodeOnFound)
 		assert.Equal(t, []string{"🚀", "✨", "🎉"}, profile.EmojiAllowlist) // Custom allowlist applied
@@ -67,7 +67,7 @@ func TestTemplateRegistry_ApplyTemplate(t *testing.T) {
 		profile, err := registry.ApplyTemplate("allow-list", options)
 
 		assert.NoError(t, err)
-		assert.Equal(t, 5, profile.MaxEmojiThreshold) // Default threshold
+		assert.Equal(t, 5, profile.MaxTotal) // Default threshold
 		// The template uses empty emoji strings that render as actual emojis
 		assert.Equal(t, 2, len(profile.EmojiAllowlist)) // Default allowlist has 2 items
 	})
@@ -81,7 +81,7 @@ func TestTemplateRegistry_ApplyTemplate(t *testing.T) {
 		assert.True(t, profile.Recursive)
 		assert.True(t, profile.UnicodeEmojis)
 		assert.True(t, profile.TextEmoticons)
-		assert.Equal(t, 20, profile.MaxEmojiThreshold)
+		assert.Equal(t, 20, profile.MaxTotal)
 		assert.False(t, profile.FailOnFound) // Permissive doesn't fail
 		assert.Equal(t, 0, profile.ExitCodeOnFound)
 		assert.True(t, len(profile.EmojiAllowlist) > 0) // Has generous allowlist
@@ -123,7 +123,7 @@ func TestGetBuiltInProfile(t *testing.T) {
 		profile, err := GetBuiltInProfile("zero-tolerance", options)
 
 		assert.NoError(t, err)
-		assert.Equal(t, 0, profile.MaxEmojiThreshold)
+		assert.Equal(t, 0, profile.MaxTotal)
 		assert.True(t, profile.FailOnFound)
 		assert.Empty(t, profile.EmojiAllowlist)
 	})
@@ -137,7 +137,7 @@ func TestGetBuiltInProfile(t *testing.T) {
 		profile, err := GetBuiltInProfile("allow-list", options)
 
 		assert.NoError(t, err)
-		assert.Equal(t, 3, profile.MaxEmojiThreshold)
+		assert.Equal(t, 3, profile.MaxTotal)
 		assert.Equal(t, []string{"📝", "🔧"}, profile.EmojiAllowlist)
 	})
 
@@ -159,7 +159,7 @@ func TestTemplateConfigurationConsistency(t *testing.T) {
 		require.NoError(t, err)
 
 		// Zero tolerance should be strict
-		assert.Equal(t, 0, profile.MaxEmojiThreshold)
+		assert.Equal(t, 0, profile.MaxTotal)
 		assert.True(t, profile.FailOnFound)
 		assert.Equal(t, 1, profile.ExitCodeOnFound)
 		assert.Empty(t, profile.EmojiAllowlist)
@@ -181,7 +181,7 @@ func TestTemplateConfigurationConsistency(t *testing.T) {
 		require.NoError(t, err)
 
 		// Allow-list should be moderate
-		assert.True(t, profile.MaxEmojiThreshold > 0)
+		assert.True(t, profile.MaxTotal > 0)
 		assert.True(t, profile.FailOnFound)
 		assert.Equal(t, 1, profile.ExitCodeOnFound)
 		assert.True(t, len(profile.EmojiAllowlist) > 0)
@@ -198,7 +198,7 @@ func TestTemplateConfigurationConsistency(t *testing.T) {
 		require.NoError(t, err)
 
 		// Permissive should be lenient
-		assert.True(t, profile.MaxEmojiThreshold > 10)
+		assert.True(t, profile.MaxTotal > 10)
 		assert.False(t, profile.FailOnFound) // Key difference
 		assert.Equal(t, 0, profile.ExitCodeOnFound)
 		assert.True(t, len(profile.EmojiAllowlist) > 10) // Generous allowlist
@@ -225,7 +225,7 @@ func TestTemplateCustomization(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Equal(t, []string{"🎯", "🔥", "💡"}, profile.EmojiAllowlist)
-		assert.Equal(t, 5, profile.MaxEmojiThreshold) // Default threshold
+		assert.Equal(t, 5, profile.MaxTotal) // Default threshold
 	})
 
 	t.Run("allow-list customizer with threshold only", func(t *testing.T) {
@@ -236,7 +236,7 @@ func TestTemplateCustomization(t *testing.T) {
 		profile, err := registry.ApplyTemplate("allow-list", options)
 		require.NoError(t, err)
 
-		assert.Equal(t, 15, profile.MaxEmojiThreshold)
+		assert.Equal(t, 15, profile.MaxTotal)
 		// The template uses empty emoji strings that render as actual emojis
 		assert.Equal(t, 2, len(profile.EmojiAllowlist)) // Default allowlist has 2 items
 	})
@@ -251,7 +251,7 @@ func TestTemplateCustomization(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Equal(t, []string{"🚀"}, profile.EmojiAllowlist)
-		assert.Equal(t, 1, profile.MaxEmojiThreshold)
+		assert.Equal(t, 1, profile.MaxTotal)
 	})
 
 	t.Run("zero-tolerance ignores customization", func(t *testing.T) {
@@ -265,7 +265,7 @@ func TestTemplateCustomization(t *testing.T) {
 		require.NoError(t, err)
 
 		// Should maintain zero-tolerance settings regardless of options
-		assert.Equal(t, 0, profile.MaxEmojiThreshold)
+		assert.Equal(t, 0, profile.MaxTotal)
 		assert.Empty(t, profile.EmojiAllowlist)
 		assert.True(t, profile.FailOnFound)
 	})
@@ -281,7 +281,7 @@ func TestTemplateCustomization(t *testing.T) {
 		require.NoError(t, err)
 
 		// Should maintain permissive settings
-		assert.Equal(t, 20, profile.MaxEmojiThreshold)
+		assert.Equal(t, 20, profile.MaxTotal)
 		assert.True(t, len(profile.EmojiAllowlist) > 10) // Original generous allowlist
 		assert.False(t, profile.FailOnFound)
 	})
@@ -314,7 +314,7 @@ func TestConfigTemplate(t *testing.T) {
 				Recursive: true,
 			},
 			Customizer: func(profile Profile, options TemplateOptions) Profile {
-				profile.MaxEmojiThreshold = options.Threshold
+				profile.MaxTotal = options.Threshold
 				return profile
 			},
 		}
@@ -327,7 +327,7 @@ func TestConfigTemplate(t *testing.T) {
 		// Test customizer function
 		options := TemplateOptions{Threshold: 10}
 		customized := template.Customizer(template.BaseProfile, options)
-		assert.Equal(t, 10, customized.MaxEmojiThreshold)
+		assert.Equal(t, 10, customized.MaxTotal)
 	})
 }
 
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/antimoji/antimoji/internal/infra/release"
+	"gopkg.in/yaml.v3"
+)
+
+// UpgradeConfig selects the releases 'antimoji upgrade' installs, so that a team can
+// move to release candidates or stay within a version range together.
+type UpgradeConfig struct {
+	// Channel is stable (the default), prerelease or pinned
+	Channel string `yaml:"channel,omitempty" json:"channel,omitempty"`
+	// Version is a constraint on the versions installed, such as ~1.2; the pinned
+	// channel requires one
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// Check looks for a newer release once a day and notes it after a command
+	Check bool `yaml:"check,omitempty" json:"check,omitempty"`
+	// GitHubAPIURL is the API releases are read from, for GitHub Enterprise mirrors
+	GitHubAPIURL string `yaml:"github_api_url,omitempty" json:"github_api_url,omitempty"`
+}
+
+// upgradeFields are the keys of the upgrade section.
+var upgradeFields = map[string]bool{"channel": true, "version": true, "check": true, "github_api_url": true}
+
+// loadUpgrade decodes and validates the upgrade section of the raw YAML.
+func loadUpgrade(content []byte) (UpgradeConfig, error) {
+	var raw struct {
+		Upgrade UpgradeConfig `yaml:"upgrade"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return UpgradeConfig{}, fmt.Errorf("failed to parse upgrade settings: %w", err)
+	}
+
+	channel, err := release.ParseChannel(raw.Upgrade.Channel)
+	if err != nil {
+		return UpgradeConfig{}, fmt.Errorf("upgrade.channel: %w", err)
+	}
+	constraint, err := release.ParseConstraint(raw.Upgrade.Version)
+	if err != nil {
+		return UpgradeConfig{}, fmt.Errorf("upgrade.version: %w", err)
+	}
+	if channel == release.ChannelPinned && constraint.IsZero() {
+		return UpgradeConfig{}, fmt.Errorf("upgrade.version: the pinned channel needs a version constraint such as ~1.2")
+	}
+	if apiURL := raw.Upgrade.GitHubAPIURL; apiURL != "" {
+		if u, err := url.Parse(apiURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return UpgradeConfig{}, fmt.Errorf("upgrade.github_api_url: %q is not an http(s) URL", apiURL)
+		}
+	}
+	return raw.Upgrade, nil
+}
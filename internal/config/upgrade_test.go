@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeConfig(t *testing.T) {
+	result := ParseConfig([]byte("upgrade:\n  channel: prerelease\n  version: ^1.2\n  check: true\nprofiles:\n  default: {}\n"))
+	require.True(t, result.IsOk(), "%v", result.Error())
+	assert.Equal(t, UpgradeConfig{Channel: "prerelease", Version: "^1.2", Check: true}, result.Unwrap().Upgrade)
+
+	for content, message := range map[string]string{
+		"upgrade:\n  channel: nightly\n":      "upgrade.channel: invalid upgrade channel",
+		"upgrade:\n  version: latest\n":       "upgrade.version: invalid version constraint",
+		"upgrade:\n  channel: pinned\n":       "the pinned channel needs a version constraint",
+		"upgrade:\n  channel: [stable, rc]\n": "failed to parse upgrade settings",
+	} {
+		result := ParseConfig([]byte(content))
+		require.True(t, result.IsErr(), content)
+		assert.ErrorContains(t, result.Error(), message)
+	}
+
+	unknown, err := UnknownKeys([]byte("upgrade:\n  channel: stable\n  chanel: stable\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"upgrade.chanel"}, unknown)
+}
@@ -3,8 +3,11 @@ package config
 
 import (
 	"fmt"
-	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+	"github.com/antimoji/antimoji/internal/types"
 )
 
 // ValidationLevel defines the severity of validation issues.
@@ -70,6 +73,12 @@ func (cv *ConfigValidator) ValidateConfig(config Config) ValidationResult {
 		cv.validateProfile(name, profile)
 	}
 
+	if err := validateRules(config); err != nil {
+		cv.addError("rules", config.Rules, err.Error(),
+			"give each rule path patterns and the name of a profile in this file",
+			"rules: [{paths: [\"docs/**\"], profile: \"permissive\"}]")
+	}
+
 	// Validate cross-profile consistency
 	cv.validateCrossProfileConsistency(config)
 
@@ -87,6 +96,15 @@ func (cv *ConfigValidator) validateProfile(name string, profile Profile) {
 	// Validate emoji policy consistency
 	cv.validateEmojiPolicyConsistency(fieldPrefix, profile)
 
+	// Validate replacement mapping
+	cv.validateReplacementMap(fieldPrefix, profile)
+
+	// Validate violation budgets
+	cv.validateBudgets(fieldPrefix, profile)
+
+	// Validate finding severities
+	cv.validateSeverity(fieldPrefix, profile)
+
 	// Validate file filtering logic
 	cv.validateFileFilteringLogic(fieldPrefix, profile)
 
@@ -103,27 +121,36 @@ func (cv *ConfigValidator) validateProfile(name string, profile Profile) {
 // validateEmojiPolicyConsistency validates emoji policy for logical consistency.
 func (cv *ConfigValidator) validateEmojiPolicyConsistency(fieldPrefix string, profile Profile) {
 	// Check zero tolerance consistency
-	if profile.MaxEmojiThreshold == 0 && len(profile.EmojiAllowlist) > 0 {
+	limit := TotalLimit(profile)
+	if limit == 0 && len(profile.EmojiAllowlist) > 0 {
 		cv.addWarning(fieldPrefix+".emoji_allowlist", profile.EmojiAllowlist,
 			"zero threshold with non-empty allowlist is contradictory",
 			"either increase threshold or remove allowlist",
-			"max_emoji_threshold: 5  # or emoji_allowlist: []")
+			"max_total: 5  # or emoji_allowlist: []")
 	}
 
 	// Check allowlist without threshold
-	if len(profile.EmojiAllowlist) > 0 && profile.MaxEmojiThreshold == 0 {
-		cv.addWarning(fieldPrefix+".max_emoji_threshold", profile.MaxEmojiThreshold,
+	if len(profile.EmojiAllowlist) > 0 && limit == 0 {
+		cv.addWarning(fieldPrefix+".max_total", profile.MaxTotal,
 			"allowlist specified but threshold is zero",
 			"set threshold to match allowlist size",
-			fmt.Sprintf("max_emoji_threshold: %d", len(profile.EmojiAllowlist)))
+			fmt.Sprintf("max_total: %d", len(profile.EmojiAllowlist)))
 	}
 
 	// Check unrealistic thresholds
-	if profile.MaxEmojiThreshold > 100 {
-		cv.addWarning(fieldPrefix+".max_emoji_threshold", profile.MaxEmojiThreshold,
+	if limit > 100 {
+		cv.addWarning(fieldPrefix+".max_total", limit,
 			"very high emoji threshold may not be effective",
 			"consider a lower threshold for better emoji control",
-			"max_emoji_threshold: 20")
+			"max_total: 20")
+	}
+
+	// Check the deprecated threshold
+	if profile.MaxEmojiThreshold > 0 {
+		cv.addWarning(fieldPrefix+".max_emoji_threshold", profile.MaxEmojiThreshold,
+			"max_emoji_threshold is deprecated",
+			"use max_total for the limit across a run; 'antimoji config migrate' rewrites it",
+			fmt.Sprintf("max_total: %d", profile.MaxEmojiThreshold))
 	}
 
 	// Check detection method consistency
@@ -143,6 +170,137 @@ func (cv *ConfigValidator) validateEmojiPolicyConsistency(fieldPrefix string, pr
 	}
 }
 
+// validateBudgets validates the total, new, per-file, per-directory, per-owner,
+// per-emoji and evasion thresholds.
+func (cv *ConfigValidator) validateBudgets(fieldPrefix string, profile Profile) {
+	if profile.MaxTotal < 0 {
+		cv.addError(fieldPrefix+".max_total", profile.MaxTotal,
+			"max total cannot be negative",
+			"use 0 for no total limit or a positive count",
+			"max_total: 10")
+	}
+
+	if profile.MaxNew < 0 {
+		cv.addError(fieldPrefix+".max_new", profile.MaxNew,
+			"max new cannot be negative",
+			"use 0 to tolerate no findings outside the baseline",
+			"max_new: 0")
+	}
+
+	if profile.MaxPerFile < 0 {
+		cv.addError(fieldPrefix+".max_per_file", profile.MaxPerFile,
+			"max per file cannot be negative",
+			"use 0 for no per-file limit or a positive count",
+			"max_per_file: 3")
+	}
+
+	if profile.EvasionThreshold < 0 {
+		cv.addError(fieldPrefix+".evasion_threshold", profile.EvasionThreshold,
+			"evasion threshold cannot be negative",
+			"use 0 to tolerate no evasion findings or a positive count",
+			"evasion_threshold: 0")
+	}
+
+	dirs := make([]string, 0, len(profile.DirectoryThresholds))
+	for dir := range profile.DirectoryThresholds {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if profile.DirectoryThresholds[dir] < 0 {
+			cv.addError(fieldPrefix+".directory_thresholds", dir,
+				"directory threshold cannot be negative",
+				"use 0 to tolerate no emojis below the directory",
+				"directory_thresholds:\n  docs: 10")
+		}
+	}
+
+	owners := make([]string, 0, len(profile.OwnerThresholds))
+	for owner := range profile.OwnerThresholds {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	for _, owner := range owners {
+		if profile.OwnerThresholds[owner] < 0 {
+			cv.addError(fieldPrefix+".owner_thresholds", owner,
+				"owner threshold cannot be negative",
+				"use 0 to tolerate no emojis in the files the owner is responsible for",
+				"owner_thresholds:\n  \"@org/docs\": 10")
+		}
+	}
+
+	allowed := make(map[string]bool, len(profile.EmojiAllowlist))
+	for _, emoji := range profile.EmojiAllowlist {
+		allowed[emoji] = true
+	}
+	emojis := make([]string, 0, len(profile.EmojiThresholds))
+	for emoji := range profile.EmojiThresholds {
+		emojis = append(emojis, emoji)
+	}
+	sort.Strings(emojis)
+	for _, emoji := range emojis {
+		if profile.EmojiThresholds[emoji] < 0 {
+			cv.addError(fieldPrefix+".emoji_thresholds", emoji,
+				"emoji threshold cannot be negative",
+				"use 0 to tolerate none of the emoji",
+				"emoji_thresholds:\n  \"✅\": 3")
+		}
+		if allowed[emoji] {
+			cv.addWarning(fieldPrefix+".emoji_thresholds", emoji,
+				"emoji is both allowlisted and budgeted; allowlisted emojis never count against a threshold",
+				"remove the emoji from either emoji_allowlist or emoji_thresholds",
+				"")
+		}
+	}
+}
+
+// validateSeverity validates the severity of categories and emojis.
+func (cv *ConfigValidator) validateSeverity(fieldPrefix string, profile Profile) {
+	keys := make([]string, 0, len(profile.Severity))
+	for key := range profile.Severity {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if severity := profile.Severity[key]; severity != types.SeverityError && severity != types.SeverityWarn {
+			cv.addError(fieldPrefix+".severity", key,
+				fmt.Sprintf("severity must be %q or %q, got %q", types.SeverityError, types.SeverityWarn, severity),
+				"use warn for findings that should be reported without failing the run",
+				"severity:\n  emoticon: warn\n  \"✅\": warn")
+		}
+	}
+}
+
+// validateReplacementMap validates per-emoji replacements.
+func (cv *ConfigValidator) validateReplacementMap(fieldPrefix string, profile Profile) {
+	allowed := make(map[string]bool, len(profile.EmojiAllowlist))
+	for _, emoji := range profile.EmojiAllowlist {
+		allowed[emoji] = true
+	}
+
+	emojis := make([]string, 0, len(profile.ReplacementMap))
+	for emoji := range profile.ReplacementMap {
+		emojis = append(emojis, emoji)
+	}
+	sort.Strings(emojis)
+
+	for _, emoji := range emojis {
+		if emoji == "" {
+			cv.addError(fieldPrefix+".replacement_map", profile.ReplacementMap,
+				"replacement map contains an empty emoji key",
+				"remove the empty entry",
+				"replacement_map:\n  \"✅\": \"[ok]\"")
+			continue
+		}
+		if allowed[emoji] {
+			cv.addWarning(fieldPrefix+".replacement_map", emoji,
+				"emoji is both allowlisted and mapped; allowlisted emojis are never replaced",
+				"remove the emoji from either emoji_allowlist or replacement_map",
+				"")
+		}
+	}
+}
+
 // validateFileFilteringLogic validates file filtering for effectiveness.
 func (cv *ConfigValidator) validateFileFilteringLogic(fieldPrefix string, profile Profile) {
 	// Check for overly restrictive includes
@@ -175,11 +333,25 @@ func (cv *ConfigValidator) validateFileFilteringLogic(fieldPrefix string, profil
 				"exclude_patterns: [\"*.min.js\", \"vendor/*\"]")
 		}
 
-		if _, err := filepath.Match(pattern, "test"); err != nil {
-			cv.addError(fmt.Sprintf("%s.exclude_patterns[%d]", fieldPrefix, i), pattern,
-				fmt.Sprintf("invalid pattern syntax: %s", err),
-				"use valid glob pattern syntax",
-				"exclude_patterns: [\"*.min.js\", \"*_test.go\"]")
+	}
+
+	// Check glob syntax; ** matches any number of directories
+	patternFields := []struct {
+		name     string
+		patterns []string
+	}{
+		{"include_patterns", profile.IncludePatterns},
+		{"exclude_patterns", profile.ExcludePatterns},
+		{"file_ignore_list", profile.FileIgnoreList},
+	}
+	for _, field := range patternFields {
+		for i, pattern := range field.patterns {
+			if err := pathmatch.Validate(pattern); err != nil {
+				cv.addError(fmt.Sprintf("%s.%s[%d]", fieldPrefix, field.name, i), pattern,
+					fmt.Sprintf("invalid pattern syntax: %s", err),
+					"use valid glob pattern syntax",
+					field.name+": [\"*.min.js\", \"vendor/**\"]")
+			}
 		}
 	}
 
@@ -205,6 +377,100 @@ func (cv *ConfigValidator) validateFileFilteringLogic(fieldPrefix string, profil
 			"add common directories to ignore list",
 			fmt.Sprintf("directory_ignore_list: [\"%s\"]", strings.Join(missingCommonDirs, "\", \"")))
 	}
+
+	// Check symlink handling
+	switch profile.SymlinkPolicy {
+	case "", SymlinkFollow, SymlinkSkip, SymlinkReport:
+	default:
+		cv.addError(fieldPrefix+".symlink_policy", profile.SymlinkPolicy,
+			fmt.Sprintf("invalid symlink policy: %s", profile.SymlinkPolicy),
+			"use one of: follow, skip, report",
+			"symlink_policy: \"report\"")
+	}
+
+	switch profile.ShortcodePolicy {
+	case "", ShortcodeIgnore, ShortcodeDetect, ShortcodeToUnicode, ShortcodeToShortcode:
+	default:
+		cv.addError(fieldPrefix+".shortcode_policy", profile.ShortcodePolicy,
+			fmt.Sprintf("invalid shortcode_policy: %s", profile.ShortcodePolicy),
+			"use one of: ignore, detect, to_unicode, to_shortcode",
+			"shortcode_policy: \"detect\"")
+	}
+
+	switch profile.HardlinkPolicy {
+	case "", HardlinkWarn, HardlinkInPlace, HardlinkSkip:
+	default:
+		cv.addError(fieldPrefix+".hardlink_policy", profile.HardlinkPolicy,
+			fmt.Sprintf("invalid hardlink_policy: %s", profile.HardlinkPolicy),
+			"use one of: warn, in_place, skip",
+			"hardlink_policy: \"in_place\"")
+	}
+
+	switch profile.MarkdownCodeBlocks {
+	case "", MarkdownPreserve, MarkdownClean:
+	default:
+		cv.addError(fieldPrefix+".markdown_code_blocks", profile.MarkdownCodeBlocks,
+			fmt.Sprintf("invalid markdown_code_blocks: %s", profile.MarkdownCodeBlocks),
+			"use one of: preserve, clean",
+			"markdown_code_blocks: \"preserve\"")
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"structured_keys", profile.StructuredKeys},
+		{"structured_values", profile.StructuredValues},
+	} {
+		switch field.value {
+		case "", StructuredCheck, StructuredAllow:
+		default:
+			cv.addError(fieldPrefix+"."+field.name, field.value,
+				fmt.Sprintf("invalid %s: %s", field.name, field.value),
+				"use one of: check, allow",
+				field.name+": \"allow\"")
+		}
+	}
+
+	for i, language := range profile.Languages {
+		if err := validateLanguage(language); err != nil {
+			cv.addError(fmt.Sprintf("%s.languages[%d]", fieldPrefix, i), language.Name,
+				err.Error(),
+				"give each language a name, the files it covers and known escapes",
+				"languages: [{name: \"hcl\", extensions: [\".tf\"], line_comments: [\"#\"]}]")
+		}
+	}
+
+	for i, exemption := range profile.Exemptions {
+		if err := validateExemption(exemption); err != nil {
+			cv.addError(fmt.Sprintf("%s.exemptions[%d]", fieldPrefix, i), exemption.Path,
+				err.Error(),
+				"give each exemption a path pattern, an expiry date and a reason",
+				"exemptions: [{path: \"docs/legacy/**\", expires: \"2026-12-31\", reason: \"migration\"}]")
+		}
+	}
+
+	for i, notification := range profile.Notify {
+		if err := validateNotification(notification); err != nil {
+			cv.addError(fmt.Sprintf("%s.notify[%d]", fieldPrefix, i), notification.Webhook,
+				err.Error(),
+				"give each notification a type, slack or webhook, and a webhook URL",
+				"notify: [{type: \"slack\", webhook: \"${SLACK_WEBHOOK_URL}\", on: \"failure\"}]")
+		}
+	}
+
+	for i, rule := range profile.CustomRules {
+		if err := validateCustomRule(rule); err != nil {
+			cv.addError(fmt.Sprintf("%s.custom_rules[%d]", fieldPrefix, i), rule.Expression,
+				err.Error(),
+				"give each rule a name and a CEL expression over finding and file that evaluates to a bool",
+				"custom_rules: [{name: \"payments\", expression: \"'@acme/payments' in file.owners && !finding.in_comment\"}]")
+		}
+	}
+
+	if profile.FollowSymlinks && profile.SymlinkPolicy != "" && profile.SymlinkPolicy != SymlinkFollow {
+		cv.addWarning(fieldPrefix+".follow_symlinks", profile.FollowSymlinks,
+			fmt.Sprintf("follow_symlinks is overridden by symlink_policy: %s", profile.SymlinkPolicy),
+			"remove follow_symlinks or set symlink_policy to follow",
+			"symlink_policy: \"follow\"")
+	}
 }
 
 // validatePerformanceSettings validates performance configuration.
@@ -232,6 +498,23 @@ func (cv *ConfigValidator) validatePerformanceSettings(fieldPrefix string, profi
 			"max_file_size: 1048576  # 1MB")
 	}
 
+	// Negative limits are rejected when the profile loads
+	for _, limit := range []struct {
+		key   string
+		value int64
+	}{
+		{"buffer_size", int64(profile.BufferSize)},
+		{"max_file_size", profile.MaxFileSize},
+		{"max_workers", int64(profile.MaxWorkers)},
+	} {
+		if limit.value < 0 {
+			cv.addError(fieldPrefix+"."+limit.key, limit.value,
+				fmt.Sprintf("%s cannot be negative", limit.key),
+				"use 0 for the default or a positive value",
+				limit.key+": 0")
+		}
+	}
+
 	// Check worker configuration
 	if profile.MaxWorkers > 32 {
 		cv.addWarning(fieldPrefix+".max_workers", profile.MaxWorkers,
@@ -239,6 +522,43 @@ func (cv *ConfigValidator) validatePerformanceSettings(fieldPrefix string, profi
 			"consider using auto-detection or lower number",
 			"max_workers: 0  # auto-detect")
 	}
+
+	// Check binary detection thresholds
+	if profile.BinarySampleSize < 0 {
+		cv.addError(fieldPrefix+".binary_sample_size", profile.BinarySampleSize,
+			"binary sample size cannot be negative",
+			"use 0 for the default sample or a positive size",
+			"binary_sample_size: 1024")
+	}
+
+	for _, ratio := range []struct {
+		key   string
+		value float64
+	}{
+		{"binary_null_ratio", profile.BinaryNullRatio},
+		{"binary_control_ratio", profile.BinaryControlRatio},
+	} {
+		if ratio.value < 0 || ratio.value > 1 {
+			cv.addError(fieldPrefix+"."+ratio.key, ratio.value,
+				fmt.Sprintf("%s must be between 0 and 1", ratio.key),
+				"use a fraction of the sampled bytes",
+				ratio.key+": 0.3")
+		}
+	}
+
+	// Check read retries
+	if profile.ReadRetries < 0 {
+		cv.addError(fieldPrefix+".read_retries", profile.ReadRetries,
+			"read retries cannot be negative",
+			"use 0 to read each file once",
+			"read_retries: 2")
+	}
+	if profile.RetryBackoffMs < 0 {
+		cv.addError(fieldPrefix+".retry_backoff_ms", profile.RetryBackoffMs,
+			"retry backoff cannot be negative",
+			"use 0 for the default backoff of 100ms",
+			"retry_backoff_ms: 100")
+	}
 }
 
 // validateOutputSettings validates output configuration.
@@ -296,7 +616,7 @@ func (cv *ConfigValidator) validateCrossProfileConsistency(config Config) {
 	profileConfigs := make(map[string][]string)
 
 	for name, profile := range config.Profiles {
-		key := fmt.Sprintf("%d-%d-%t", profile.MaxEmojiThreshold, len(profile.EmojiAllowlist), profile.FailOnFound)
+		key := fmt.Sprintf("%d-%d-%t", TotalLimit(profile), len(profile.EmojiAllowlist), profile.FailOnFound)
 		profileConfigs[key] = append(profileConfigs[key], name)
 	}
 
@@ -443,10 +763,20 @@ func (vr ValidationResult) String() string {
 	return result
 }
 
+// validatedDefaults are the fields ValidateConfigFile fills from the default profile
+// when a file omits them.
+var validatedDefaults = append([]string{"unicode_emojis", "text_emoticons"}, defaultedFields...)
+
 // ValidateConfigFile validates a configuration file and returns detailed results.
+// Unknown keys, which are otherwise ignored, are reported as warnings.
 func ValidateConfigFile(configPath string) ValidationResult {
 	// Load configuration
-	configResult := LoadConfig(configPath)
+	source, err := readConfigSource(configPath)
+	content := source.content
+	configResult := types.Err[Config](err)
+	if err == nil {
+		configResult = parseConfig(content)
+	}
 	if configResult.IsErr() {
 		return ValidationResult{
 			IsValid: false,
@@ -463,9 +793,34 @@ func ValidateConfigFile(configPath string) ValidationResult {
 		}
 	}
 
-	// Validate configuration
+	// Validate the profiles as they load: the defaulted fields and detection
+	// switches a file omits take the default profile's values, so a minimal profile
+	// is not reported as disabling detection
+	cfg := configResult.Unwrap()
+	for name, profile := range cfg.Profiles {
+		fileKeys, err := profileKeys(content, name)
+		if err != nil {
+			break
+		}
+		cfg.Profiles[name] = mergeDefaults(profile, validatedDefaults, func(key string) bool { return fileKeys[key] })
+	}
+
 	validator := NewConfigValidator()
-	return validator.ValidateConfig(configResult.Unwrap())
+	result := validator.ValidateConfig(cfg)
+
+	unknown, err := UnknownKeys(content)
+	if err != nil || len(unknown) == 0 {
+		return result
+	}
+	for _, key := range unknown {
+		validator.addWarning(key, nil, "unknown configuration key is ignored",
+			"check the spelling against 'antimoji config show'; --strict-config rejects unknown keys", "")
+	}
+	return ValidationResult{
+		IsValid: validator.countErrors() == 0,
+		Issues:  validator.issues,
+		Summary: validator.generateSummary(),
+	}
 }
 
 // SuggestImprovements analyzes a profile and suggests improvements.
@@ -477,7 +832,7 @@ func SuggestImprovements(profile Profile) []ValidationIssue {
 		validator.addInfo("emoji_allowlist", len(profile.EmojiAllowlist),
 			"large allowlist may be hard to maintain",
 			"consider reducing allowlist or using permissive mode",
-			"max_emoji_threshold: 20\nfail_on_found: false")
+			"max_total: 20\nfail_on_found: false")
 	}
 
 	if len(profile.IncludePatterns) > 10 {
@@ -164,9 +164,9 @@ func TestConfigValidator_validateEmojiPolicyConsistency(t *testing.T) {
 		{
 			name: "consistent zero tolerance",
 			profile: Profile{
-				MaxEmojiThreshold: 0,
-				EmojiAllowlist:    []string{},
-				UnicodeEmojis:     true,
+				MaxTotal:       0,
+				EmojiAllowlist: []string{},
+				UnicodeEmojis:  true,
 			},
 			expectWarning: false,
 			expectError:   false,
@@ -174,9 +174,9 @@ func TestConfigValidator_validateEmojiPolicyConsistency(t *testing.T) {
 		{
 			name: "consistent allow list",
 			profile: Profile{
-				MaxEmojiThreshold: 5,
-				EmojiAllowlist:    []string{"✅", "❌"},
-				UnicodeEmojis:     true,
+				MaxTotal:       5,
+				EmojiAllowlist: []string{"✅", "❌"},
+				UnicodeEmojis:  true,
 			},
 			expectWarning: false,
 			expectError:   false,
@@ -184,9 +184,9 @@ func TestConfigValidator_validateEmojiPolicyConsistency(t *testing.T) {
 		{
 			name: "contradictory zero threshold with allowlist",
 			profile: Profile{
-				MaxEmojiThreshold: 0,
-				EmojiAllowlist:    []string{"✅"},
-				UnicodeEmojis:     true,
+				MaxTotal:       0,
+				EmojiAllowlist: []string{"✅"},
+				UnicodeEmojis:  true,
 			},
 			expectWarning: true,
 			expectError:   false,
@@ -194,23 +194,33 @@ func TestConfigValidator_validateEmojiPolicyConsistency(t *testing.T) {
 		{
 			name: "no detection methods",
 			profile: Profile{
-				MaxEmojiThreshold: 5,
-				UnicodeEmojis:     false,
-				TextEmoticons:     false,
-				CustomPatterns:    []string{},
+				MaxTotal:       5,
+				UnicodeEmojis:  false,
+				TextEmoticons:  false,
+				CustomPatterns: []string{},
 			},
 			expectWarning: false,
 			expectError:   true,
 		},
 		{
-			name: "unrealistic threshold",
+			name: "deprecated threshold",
 			profile: Profile{
-				MaxEmojiThreshold: 200,
+				MaxEmojiThreshold: 5,
+				EmojiAllowlist:    []string{"✅", "❌"},
 				UnicodeEmojis:     true,
 			},
 			expectWarning: true,
 			expectError:   false,
 		},
+		{
+			name: "unrealistic threshold",
+			profile: Profile{
+				MaxTotal:      200,
+				UnicodeEmojis: true,
+			},
+			expectWarning: true,
+			expectError:   false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -445,6 +455,46 @@ func TestValidateConfigFile(t *testing.T) {
 		assert.False(t, result.HasErrors()) // Should be valid or have only warnings
 	})
 
+	t.Run("validates minimal profiles with default values", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "minimal.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("profiles:\n  ci:\n    max_total: 3\n"), 0644))
+
+		result := ValidateConfigFile(configPath)
+		assert.True(t, result.IsValid, result.String())
+	})
+
+	t.Run("reports detection disabled explicitly", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "disabled.yaml")
+		require.NoError(t, os.WriteFile(configPath,
+			[]byte("profiles:\n  ci:\n    unicode_emojis: false\n    text_emoticons: false\n"), 0644))
+
+		result := ValidateConfigFile(configPath)
+		assert.Equal(t, []string{"profiles.ci.emoji_detection"}, errorFields(result))
+	})
+
+	t.Run("reports negative limits", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "negative.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("profiles:\n  default:\n    max_workers: -1\n"), 0644))
+
+		result := ValidateConfigFile(configPath)
+		assert.Equal(t, []string{"profiles.default.max_workers"}, errorFields(result))
+	})
+
+	t.Run("warns about unknown keys", func(t *testing.T) {
+		configContent := `profiles:
+  default:
+    unicode_emojis: true
+    recursve: true`
+
+		configPath := filepath.Join(tempDir, "typo.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+		result := ValidateConfigFile(configPath)
+		assert.False(t, result.HasErrors())
+		require.Equal(t, 1, result.Summary.Warnings)
+		assert.Equal(t, "profiles.default.recursve", result.Issues[len(result.Issues)-1].Field)
+	})
+
 	t.Run("handles nonexistent config file", func(t *testing.T) {
 		configPath := filepath.Join(tempDir, "nonexistent.yaml")
 
@@ -474,6 +524,17 @@ func TestValidateConfigFile(t *testing.T) {
 	})
 }
 
+// errorFields returns the fields of the error-level issues in result.
+func errorFields(result ValidationResult) []string {
+	var fields []string
+	for _, issue := range result.Issues {
+		if issue.Level == ValidationLevelError {
+			fields = append(fields, issue.Field)
+		}
+	}
+	return fields
+}
+
 func TestSuggestImprovements(t *testing.T) {
 	t.Run("suggests improvements for large allowlist", func(t *testing.T) {
 		profile := Profile{
@@ -603,3 +664,48 @@ func TestConfigValidator_validateCrossProfileConsistency(t *testing.T) {
 		assert.True(t, hasDuplicateInfo)
 	})
 }
+
+func TestConfigValidator_validateReplacementMap(t *testing.T) {
+	t.Run("flags empty keys and allowlisted emojis", func(t *testing.T) {
+		validator := NewConfigValidator()
+		validator.validateReplacementMap("test", Profile{
+			EmojiAllowlist: []string{"✅"},
+			ReplacementMap: map[string]string{"": "x", "✅": "[ok]", "🚀": "(launch)"},
+		})
+
+		require.Len(t, validator.issues, 2)
+		assert.Equal(t, ValidationLevelError, validator.issues[0].Level)
+		assert.Equal(t, ValidationLevelWarning, validator.issues[1].Level)
+		assert.Equal(t, "✅", validator.issues[1].Value)
+	})
+
+	t.Run("accepts valid map", func(t *testing.T) {
+		validator := NewConfigValidator()
+		validator.validateReplacementMap("test", Profile{ReplacementMap: map[string]string{"🚀": "(launch)"}})
+		assert.Empty(t, validator.issues)
+	})
+}
+
+func TestConfigValidator_validateBudgets(t *testing.T) {
+	t.Run("flags negative limits and allowlisted emojis", func(t *testing.T) {
+		validator := NewConfigValidator()
+		validator.validateBudgets("test", Profile{
+			MaxPerFile:          -1,
+			DirectoryThresholds: map[string]int{"docs": -2},
+			EmojiAllowlist:      []string{"✅"},
+			EmojiThresholds:     map[string]int{"✅": 3, "😂": 0},
+		})
+
+		require.Len(t, validator.issues, 3)
+		assert.Equal(t, "test.max_per_file", validator.issues[0].Field)
+		assert.Equal(t, "test.directory_thresholds", validator.issues[1].Field)
+		assert.Equal(t, ValidationLevelWarning, validator.issues[2].Level)
+		assert.Equal(t, "✅", validator.issues[2].Value)
+	})
+
+	t.Run("accepts zero limits", func(t *testing.T) {
+		validator := NewConfigValidator()
+		validator.validateBudgets("test", Profile{EmojiThresholds: map[string]int{"😂": 0}})
+		assert.Empty(t, validator.issues)
+	})
+}
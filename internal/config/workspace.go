@@ -0,0 +1,81 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace lists the roots scanned together by scan --workspace, such as the
+// checkouts of a meta-repository, each with its own configuration and profile.
+type Workspace struct {
+	// Path is where the workspace was loaded from
+	Path  string          `yaml:"-"`
+	Roots []WorkspaceRoot `yaml:"roots"`
+}
+
+// WorkspaceRoot is one root of a workspace. Paths in the workspace file are relative
+// to the file; LoadWorkspace resolves them.
+type WorkspaceRoot struct {
+	// Name labels the root in output; it defaults to Path
+	Name string `yaml:"name,omitempty"`
+	Path string `yaml:"path"`
+	// Config is the configuration file of the root. Without one, the root's own
+	// .antimoji.yaml is used when it exists.
+	Config  string `yaml:"config,omitempty"`
+	Profile string `yaml:"profile,omitempty"`
+}
+
+// LoadWorkspace reads and validates the workspace file at path.
+func LoadWorkspace(path string) (*Workspace, error) {
+	content, err := os.ReadFile(path) // #nosec G304 - workspace file given by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace: %w", err)
+	}
+
+	workspace := &Workspace{Path: path}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(workspace); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("workspace %s: %w", path, err)
+	}
+	if len(workspace.Roots) == 0 {
+		return nil, fmt.Errorf("workspace %s: no roots listed", path)
+	}
+
+	dir := filepath.Dir(path)
+	names := make(map[string]bool, len(workspace.Roots))
+	for i := range workspace.Roots {
+		root := &workspace.Roots[i]
+		if strings.TrimSpace(root.Path) == "" {
+			return nil, fmt.Errorf("workspace %s: roots[%d] has no path", path, i)
+		}
+		if root.Name == "" {
+			root.Name = root.Path
+		}
+		if names[root.Name] {
+			return nil, fmt.Errorf("workspace %s: root %s is listed twice", path, root.Name)
+		}
+		names[root.Name] = true
+
+		root.Path = resolveWorkspacePath(dir, root.Path)
+		if root.Config != "" {
+			root.Config = resolveWorkspacePath(dir, root.Config)
+		}
+	}
+	return workspace, nil
+}
+
+// resolveWorkspacePath returns path relative to the directory of the workspace file.
+func resolveWorkspacePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	load := func(t *testing.T, content string) (*Workspace, error) {
+		path := filepath.Join(dir, "workspace.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return LoadWorkspace(path)
+	}
+
+	t.Run("resolves paths against the workspace file", func(t *testing.T) {
+		workspace, err := load(t, `roots:
+  - path: services/api
+    config: configs/api.yaml
+    profile: strict
+  - name: tools
+    path: /opt/tools/
+`)
+		require.NoError(t, err)
+		assert.Equal(t, []WorkspaceRoot{
+			{Name: "services/api", Path: filepath.Join(dir, "services", "api"), Config: filepath.Join(dir, "configs", "api.yaml"), Profile: "strict"},
+			{Name: "tools", Path: "/opt/tools"},
+		}, workspace.Roots)
+	})
+
+	for name, content := range map[string]string{
+		"no roots":          "roots: []\n",
+		"root without path": "roots:\n  - name: api\n",
+		"duplicate root":    "roots:\n  - path: api\n  - path: web\n    name: api\n",
+		"unknown field":     "roots:\n  - path: api\n    profiles: [strict]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := load(t, content)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/antimoji/antimoji/internal/core/shortcode"
 	"github.com/antimoji/antimoji/internal/types"
 )
 
@@ -12,6 +13,7 @@ import (
 type Allowlist struct {
 	patterns         map[string]bool // Normalized patterns for fast lookup
 	originalPatterns []string        // Original patterns for reference
+	targets          map[string]bool // When set, every emoji outside it is allowed
 }
 
 // NewAllowlist creates a new allowlist from the given patterns.
@@ -41,8 +43,21 @@ func (a *Allowlist) IsAllowed(emoji string) bool {
 		return false
 	}
 
-	normalized := normalizeEmoji(emoji)
-	return a.patterns[normalized]
+	return contains(a.patterns, emoji) || (a.targets != nil && !contains(a.targets, emoji))
+}
+
+// contains reports whether set holds the normalized emoji or another way of writing it,
+// so that a shortcode such as :rocket: and its emoji match each other.
+func contains(set map[string]bool, emoji string) bool {
+	if set[normalizeEmoji(emoji)] {
+		return true
+	}
+	for _, equivalent := range shortcode.Equivalents(emoji) {
+		if set[normalizeEmoji(equivalent)] {
+			return true
+		}
+	}
+	return false
 }
 
 // GetPatterns returns a copy of the original patterns used to create this allowlist.
@@ -72,6 +87,7 @@ func ApplyAllowlist(detectionResult types.DetectionResult, allowlist *Allowlist)
 	// Create new result with filtered emojis
 	filtered := types.DetectionResult{
 		ProcessedBytes: detectionResult.ProcessedBytes,
+		Lines:          detectionResult.Lines,
 		Duration:       detectionResult.Duration,
 		Success:        detectionResult.Success,
 	}
@@ -153,9 +169,35 @@ func CreateDefaultAllowlist() *Allowlist {
 	return NewAllowlist(patterns).Unwrap()
 }
 
-// IsEmpty returns true if the allowlist contains no patterns.
+// IsEmpty returns true if the allowlist contains no patterns and allows every emoji
+// outside no target set.
 func (a *Allowlist) IsEmpty() bool {
-	return len(a.patterns) == 0
+	return len(a.patterns) == 0 && a.targets == nil
+}
+
+// Restrict returns an allowlist that also allows the emojis in except and, when only is
+// not empty, every emoji not in only, so that only the remaining emojis are treated as
+// violations. base may be nil. Emojis allowed by base stay allowed.
+func Restrict(base *Allowlist, only, except []string) *Allowlist {
+	patterns := append([]string{}, except...)
+	if base != nil {
+		patterns = append(base.GetPatterns(), patterns...)
+	}
+	restricted := NewAllowlist(patterns).Unwrap()
+	if base != nil && base.targets != nil {
+		restricted.targets = base.targets
+	}
+	if len(only) > 0 {
+		targets := make(map[string]bool, len(only))
+		for _, emoji := range only {
+			normalized := normalizeEmoji(emoji)
+			if restricted.targets == nil || restricted.targets[normalized] {
+				targets[normalized] = true
+			}
+		}
+		restricted.targets = targets
+	}
+	return restricted
 }
 
 // Merge combines two allowlists into a new allowlist.
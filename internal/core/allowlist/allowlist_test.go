@@ -351,6 +351,53 @@ func TestMerge(t *testing.T) {
 	})
 }
 
+func TestRestrict(t *testing.T) {
+	t.Run("only allows everything else", func(t *testing.T) {
+		restricted := Restrict(nil, []string{"🚀", "🔥"}, nil)
+		assert.False(t, restricted.IsAllowed("🚀"))
+		assert.False(t, restricted.IsAllowed("🔥"))
+		assert.True(t, restricted.IsAllowed("✅"))
+		assert.False(t, restricted.IsEmpty())
+	})
+
+	t.Run("except allows the listed emojis", func(t *testing.T) {
+		restricted := Restrict(nil, nil, []string{"✅"})
+		assert.True(t, restricted.IsAllowed("✅"))
+		assert.False(t, restricted.IsAllowed("🚀"))
+	})
+
+	t.Run("base allowlist is kept", func(t *testing.T) {
+		base := NewAllowlist([]string{"🚀"}).Unwrap()
+		restricted := Restrict(base, []string{"🚀", "🔥"}, []string{"✅"})
+		assert.True(t, restricted.IsAllowed("🚀"))
+		assert.False(t, restricted.IsAllowed("🔥"))
+		assert.True(t, restricted.IsAllowed("✅"))
+		assert.True(t, restricted.IsAllowed("🎉"))
+		assert.False(t, base.IsAllowed("🎉"))
+	})
+
+	t.Run("matches variation selectors", func(t *testing.T) {
+		restricted := Restrict(nil, []string{"⚠️"}, nil)
+		assert.False(t, restricted.IsAllowed("⚠"))
+	})
+
+	t.Run("matches shortcodes", func(t *testing.T) {
+		restricted := Restrict(nil, []string{":rocket:"}, nil)
+		assert.False(t, restricted.IsAllowed("🚀"))
+		assert.True(t, restricted.IsAllowed(":tada:"))
+	})
+}
+
+func TestAllowlist_Shortcodes(t *testing.T) {
+	allowlist := NewAllowlist([]string{"✅", ":thumbsup:"}).Unwrap()
+
+	assert.True(t, allowlist.IsAllowed(":white_check_mark:"))
+	assert.True(t, allowlist.IsAllowed("👍"))
+	assert.True(t, allowlist.IsAllowed(":+1:"), "aliases of an allowed shortcode are allowed")
+	assert.False(t, allowlist.IsAllowed(":rocket:"))
+	assert.False(t, allowlist.IsAllowed(":thumbs_up:"), "custom patterns are not shortcodes")
+}
+
 func TestNormalizeEmoji(t *testing.T) {
 	t.Run("removes variation selectors", func(t *testing.T) {
 		// These test cases would be internal if normalizeEmoji was exported
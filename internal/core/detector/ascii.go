@@ -0,0 +1,92 @@
+package detector
+
+import (
+	"encoding/binary"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// highBits has the high bit of each byte of a word set; a word of ASCII bytes has none.
+const highBits = 0x8080808080808080
+
+// isASCII reports whether content holds only ASCII bytes, checking eight bytes at a
+// time.
+func isASCII(content []byte) bool {
+	i := 0
+	for ; i+8 <= len(content); i += 8 {
+		if binary.LittleEndian.Uint64(content[i:])&highBits != 0 {
+			return false
+		}
+	}
+	for ; i < len(content); i++ {
+		if content[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// rangesCoverASCII reports whether a range of any of the sets contains an ASCII code
+// point. The built-in ranges do not, but configured ones could.
+func rangesCoverASCII(sets ...[]types.UnicodeRange) bool {
+	for _, ranges := range sets {
+		for _, urange := range ranges {
+			if urange.Start < utf8.RuneSelf {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// plainASCII reports whether runes[i] is ASCII that cannot start a Unicode match, so
+// the range lookups can be skipped: no range covers ASCII, and it is not followed by
+// the non-ASCII code points that make a digit, '#' or '*' a keycap.
+func plainASCII(runes []rune, i int, coversASCII bool) bool {
+	return !coversASCII && runes[i] < utf8.RuneSelf && (i+1 == len(runes) || runes[i+1] < utf8.RuneSelf)
+}
+
+// lineIndex finds the line and column of byte offsets in content, so that positioning
+// each match does not scan content from its start.
+type lineIndex struct {
+	content string
+	// starts holds the byte offset of each line, built on first use
+	starts []int
+	// The last position looked up, from which columns further along its line are
+	// counted, as matches are mostly looked up in order
+	lastPos, lastLine, lastColumn int
+}
+
+// newLineIndex returns the line index of content.
+func newLineIndex(content string) *lineIndex {
+	return &lineIndex{content: content}
+}
+
+// position returns the line and the column in runes of bytePos, both counted from 1.
+func (x *lineIndex) position(bytePos int) (line, column int) {
+	if x.starts == nil {
+		x.starts = []int{0}
+		for i := 0; ; {
+			newline := strings.IndexByte(x.content[i:], '\n')
+			if newline == -1 {
+				break
+			}
+			i += newline + 1
+			x.starts = append(x.starts, i)
+		}
+	}
+	bytePos = min(bytePos, len(x.content))
+
+	// The line is the number of lines starting at or before bytePos
+	line = sort.SearchInts(x.starts, bytePos+1)
+	from, column := x.starts[line-1], 1
+	if line == x.lastLine && x.lastPos <= bytePos {
+		from, column = x.lastPos, x.lastColumn
+	}
+	column += utf8.RuneCountInString(x.content[from:bytePos])
+	x.lastPos, x.lastLine, x.lastColumn = bytePos, line, column
+	return line, column
+}
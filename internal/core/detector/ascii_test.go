@@ -0,0 +1,130 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsASCII(t *testing.T) {
+	assert.True(t, isASCII(nil))
+	assert.True(t, isASCII([]byte("package main // plain text that spans several words")))
+	for _, content := range []string{"é", "eight by" + "é", strings.Repeat("a", 17) + "😀", "😀" + strings.Repeat("a", 20)} {
+		assert.False(t, isASCII([]byte(content)), content)
+	}
+}
+
+func TestPlainASCII(t *testing.T) {
+	runes := []rune("a1\ufe0f\u20e3#")
+	assert.True(t, plainASCII(runes, 0, false))
+	assert.False(t, plainASCII(runes, 1, false), "keycap base")
+	assert.False(t, plainASCII(runes, 2, false), "not ASCII")
+	assert.True(t, plainASCII(runes, 4, false), "last rune")
+	assert.False(t, plainASCII(runes, 0, true), "ranges cover ASCII")
+}
+
+// naivePosition is the line and rune column of bytePos, scanning content from its start.
+func naivePosition(content string, bytePos int) (line, column int) {
+	line, column = 1, 1
+	for i, r := range content {
+		if i >= bytePos {
+			break
+		}
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+func TestLineIndex(t *testing.T) {
+	for _, content := range []string{"", "one line", "a\nb\n", "\n\n😀 x\nnaïve :)\n"} {
+		positions := newLineIndex(content)
+		check := func(pos int) {
+			line, column := positions.position(pos)
+			wantLine, wantColumn := naivePosition(content, pos)
+			assert.Equal(t, []int{wantLine, wantColumn}, []int{line, column}, "%q at %d", content, pos)
+		}
+		for pos := range content {
+			check(pos)
+		}
+		check(len(content))
+		// Looking back after looking further along the line
+		for pos := range content {
+			check(pos)
+		}
+	}
+}
+
+func TestDetectEmojis_ASCIIContent(t *testing.T) {
+	t.Run("emoticons are still found", func(t *testing.T) {
+		detection := DetectEmojis([]byte("ok\nfine :) done"), DefaultEmojiPatterns()).Unwrap()
+		require.Len(t, detection.Emojis, 1)
+		assert.Equal(t, ":)", detection.Emojis[0].Emoji)
+		assert.Equal(t, 2, detection.Emojis[0].Line)
+		assert.Equal(t, 6, detection.Emojis[0].Column)
+	})
+
+	t.Run("configured ranges covering ASCII are scanned", func(t *testing.T) {
+		patterns := types.EmojiPatterns{UnicodeRanges: []types.UnicodeRange{{Start: '@', End: '@', Name: "At"}}}
+		detection := DetectEmojis([]byte("a\nuser@example.com"), patterns).Unwrap()
+		require.Len(t, detection.Emojis, 1)
+		assert.Equal(t, types.EmojiMatch{Emoji: "@", Start: 6, End: 7, Line: 2, Column: 5, Category: types.CategoryUnicode},
+			types.EmojiMatch{Emoji: detection.Emojis[0].Emoji, Start: detection.Emojis[0].Start, End: detection.Emojis[0].End,
+				Line: detection.Emojis[0].Line, Column: detection.Emojis[0].Column, Category: detection.Emojis[0].Category})
+	})
+
+	t.Run("keycaps after ASCII text", func(t *testing.T) {
+		detection := DetectEmojis([]byte("step 1\ufe0f\u20e3 and 2"), DefaultEmojiPatterns()).Unwrap()
+		require.Len(t, detection.Emojis, 1)
+		assert.Equal(t, "1\ufe0f\u20e3", detection.Emojis[0].Emoji)
+		assert.Equal(t, 6, detection.Emojis[0].Column)
+	})
+}
+
+// emojiFreeSource returns about size bytes of Go source without emojis.
+func emojiFreeSource(size int) []byte {
+	const source = `func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if err := s.auth(r); err != nil { // reject unauthenticated requests
+		http.Error(w, fmt.Sprintf("unauthorized: %v", err), http.StatusUnauthorized)
+		return
+	}
+	items := map[string]int{"a": 1, "b": 2}
+	_ = json.NewEncoder(w).Encode(items)
+}
+`
+	return []byte(strings.Repeat(source, size/len(source)+1))
+}
+
+func BenchmarkDetectEmojis_EmojiFreeSource(b *testing.B) {
+	fullScan := DefaultEmojiPatterns()
+	// A range covering an ASCII code point absent from the content disables skipping the
+	// Unicode range lookups for ASCII
+	fullScan.UnicodeRanges = append(fullScan.UnicodeRanges, types.UnicodeRange{Start: 0x01, End: 0x01, Name: "Unused"})
+
+	for _, size := range []int{64 * 1024, 1024 * 1024} {
+		content := emojiFreeSource(size)
+		for _, bench := range []struct {
+			name     string
+			patterns types.EmojiPatterns
+		}{
+			{"ascii_fast_path", DefaultEmojiPatterns()},
+			{"full_scan", fullScan},
+		} {
+			b.Run(fmt.Sprintf("%s/%dKB", bench.name, size/1024), func(b *testing.B) {
+				b.SetBytes(int64(len(content)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = DetectEmojis(content, bench.patterns).Unwrap()
+				}
+			})
+		}
+	}
+}
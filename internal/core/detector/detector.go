@@ -2,28 +2,47 @@
 package detector
 
 import (
+	"bytes"
 	"fmt"
 	"regexp"
-	"sort"
+	"slices"
+	"strings"
 	"time"
 	"unicode/utf8"
+	"unsafe"
 
+	"github.com/antimoji/antimoji/internal/core/shortcode"
 	"github.com/antimoji/antimoji/internal/types"
 )
 
 // DetectEmojis detects emojis in the given content using the provided patterns.
 // This is a pure function that does not modify the input content.
 func DetectEmojis(content []byte, patterns types.EmojiPatterns) types.Result[types.DetectionResult] {
+	var result types.DetectionResult
+	DetectEmojisInto(content, patterns, &result, DetectOptions{})
+	return types.Ok(result)
+}
+
+// DetectEmojisInto detects emojis in content like DetectEmojis, storing the result in
+// result and reusing the memory of its matches. With a result from AcquireResult,
+// repeated detections allocate little.
+func DetectEmojisInto(content []byte, patterns types.EmojiPatterns, into *types.DetectionResult, opts DetectOptions) {
+	into.Reset()
 	if content == nil {
-		return types.Ok(types.DetectionResult{Success: true})
+		*into = types.DetectionResult{Emojis: into.Emojis, Success: true}
+		return
 	}
 
 	startTime := time.Now()
 	result := types.DetectionResult{
+		Emojis:      into.Emojis,
 		ContentSize: len(content),
 		StartTime:   startTime,
 	}
 	contentStr := string(content)
+	if opts.ZeroCopy {
+		contentStr = unsafe.String(unsafe.SliceData(content), len(content)) // #nosec G103 - documented on DetectOptions
+	}
 
 	// Track line and column positions
 	line := 1
@@ -31,30 +50,73 @@ func DetectEmojis(content []byte, patterns types.EmojiPatterns) types.Result[typ
 	bytePos := 0
 	patternsApplied := 0
 
-	// Convert content to runes for proper Unicode handling
-	runes := []rune(contentStr)
+	// Convert content to runes for proper Unicode handling; ASCII content has no
+	// Unicode matches unless configured ranges cover ASCII, so it is not scanned
+	ascii := isASCII(content)
+	coversASCII := rangesCoverASCII(patterns.DecorativeRanges, patterns.UnicodeRanges,
+		patterns.HygieneRanges, patterns.EvasionRanges)
+	var runes []rune
+	if coversASCII || !ascii {
+		buffer := acquireRunes()
+		defer releaseRunes(buffer)
+		for _, r := range contentStr {
+			*buffer = append(*buffer, r)
+		}
+		runes = *buffer
+	}
 
 	for i := 0; i < len(runes); i++ {
 		r := runes[i]
 		runeStart := bytePos
 		runeWidth := utf8.RuneLen(r)
 
-		// Check for Unicode emojis
-		if isUnicodeEmoji(r, patterns.UnicodeRanges) {
+		// ASCII that cannot start a match skips the range lookups
+		if plainASCII(runes, i, coversASCII) {
+			bytePos++
+			if r == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+			continue
+		}
+
+		// Decorative symbols take precedence over the Unicode ranges they overlap,
+		// unless the emoji variation selector asks for emoji presentation
+		if decorativeEnd := decorativeSymbolEnd(runes, i, patterns.DecorativeRanges); decorativeEnd > i {
 			patternsApplied++
-			// Handle multi-rune emojis (like skin tone modifiers)
-			emojiEnd := i + 1
-			emojiWidth := runeWidth
-
-			// Check for skin tone modifiers and ZWJ sequences
-			for emojiEnd < len(runes) && isEmojiModifier(runes[emojiEnd]) {
-				emojiWidth += utf8.RuneLen(runes[emojiEnd])
-				emojiEnd++
+			symbolWidth := 0
+			for _, sr := range runes[i:decorativeEnd] {
+				symbolWidth += utf8.RuneLen(sr)
+			}
+
+			result.AddEmoji(types.EmojiMatch{
+				Emoji:    matchText(contentStr, runes[i:decorativeEnd], runeStart, symbolWidth, opts),
+				Start:    runeStart,
+				End:      runeStart + symbolWidth,
+				Line:     line,
+				Column:   column,
+				Category: types.CategoryDecorative,
+			})
+
+			i = decorativeEnd - 1
+			bytePos += symbolWidth
+			column++
+			continue
+		}
+
+		// Check for Unicode emojis; a whole sequence (ZWJ, skin tone, keycap, flag)
+		// is reported as a single match
+		if emojiEnd := emojiSequenceEnd(runes, i, patterns.UnicodeRanges); emojiEnd > i {
+			patternsApplied++
+			emojiWidth := 0
+			for _, sr := range runes[i:emojiEnd] {
+				emojiWidth += utf8.RuneLen(sr)
 			}
 
-			emoji := string(runes[i:emojiEnd])
 			match := types.EmojiMatch{
-				Emoji:    emoji,
+				Emoji:    matchText(contentStr, runes[i:emojiEnd], runeStart, emojiWidth, opts),
 				Start:    runeStart,
 				End:      runeStart + emojiWidth,
 				Line:     line,
@@ -63,14 +125,56 @@ func DetectEmojis(content []byte, patterns types.EmojiPatterns) types.Result[typ
 			}
 
 			// Store debug information about the Unicode characters detected
-			match.DebugInfo = createEmojiDebugInfo(runes[i:emojiEnd], patterns.UnicodeRanges)
+			if !opts.ZeroCopy {
+				match.DebugInfo = createEmojiDebugInfo(runes[i:emojiEnd], patterns.UnicodeRanges)
+			}
 
 			result.AddEmoji(match)
 
-			// Skip processed runes
+			// Skip processed runes; a sequence occupies a single column
 			i = emojiEnd - 1
 			bytePos += emojiWidth
-			column += emojiEnd - i
+			column++
+		} else if isHygieneFinding(runes, i, patterns.HygieneRanges) {
+			patternsApplied++
+			match := types.EmojiMatch{
+				Emoji:    matchText(contentStr, runes[i:i+1], runeStart, runeWidth, opts),
+				Start:    runeStart,
+				End:      runeStart + runeWidth,
+				Line:     line,
+				Column:   column,
+				Category: types.CategoryHygiene,
+			}
+			if !opts.ZeroCopy {
+				match.DebugInfo = createEmojiDebugInfo(runes[i:i+1], patterns.HygieneRanges)
+			}
+			result.AddEmoji(match)
+
+			bytePos += runeWidth
+			column++
+		} else if evasionEnd := evasionEnd(runes, i, patterns.EvasionRanges); evasionEnd > i {
+			patternsApplied++
+			evasionWidth := 0
+			for _, sr := range runes[i:evasionEnd] {
+				evasionWidth += utf8.RuneLen(sr)
+			}
+
+			match := types.EmojiMatch{
+				Emoji:    matchText(contentStr, runes[i:evasionEnd], runeStart, evasionWidth, opts),
+				Start:    runeStart,
+				End:      runeStart + evasionWidth,
+				Line:     line,
+				Column:   column,
+				Category: types.CategoryEvasion,
+			}
+			if !opts.ZeroCopy {
+				match.DebugInfo = createEmojiDebugInfo(runes[i:evasionEnd], patterns.EvasionRanges)
+			}
+			result.AddEmoji(match)
+
+			i = evasionEnd - 1
+			bytePos += evasionWidth
+			column++
 		} else {
 			bytePos += runeWidth
 			if r == '\n' {
@@ -82,29 +186,64 @@ func DetectEmojis(content []byte, patterns types.EmojiPatterns) types.Result[typ
 		}
 	}
 
+	positions := newLineIndex(contentStr)
+
 	// Detect text emoticons
-	result, emoticonPatternsApplied := detectEmoticons(contentStr, patterns.EmoticonPatterns, result)
+	result, emoticonPatternsApplied := detectEmoticons(contentStr, positions, patterns.EmoticonPatterns, result)
 	patternsApplied += emoticonPatternsApplied
 
 	// Detect custom patterns
-	result, customPatternsApplied := detectCustomPatterns(contentStr, patterns.CustomPatterns, result)
+	result, customPatternsApplied := detectCustomPatterns(contentStr, positions, patterns.CustomPatterns, result)
 	patternsApplied += customPatternsApplied
 
-	// Sort emojis by position to ensure consistent ordering
-	sort.Slice(result.Emojis, func(i, j int) bool {
-		return result.Emojis[i].Start < result.Emojis[j].Start
+	// Detect shortcodes
+	result, shortcodePatternsApplied := detectShortcodes(contentStr, positions, patterns.Shortcodes, patterns.CustomPatterns, result)
+	patternsApplied += shortcodePatternsApplied
+
+	// Detect escaped emojis
+	result, escapePatternsApplied := detectEscapes(contentStr, positions, patterns.Escapes, patterns.UnicodeRanges, result)
+	patternsApplied += escapePatternsApplied
+
+	// Detect kaomoji
+	result, kaomojiPatternsApplied := detectKaomoji(contentStr, positions, ascii, patterns.KaomojiPatterns, result)
+	patternsApplied += kaomojiPatternsApplied
+
+	// Sort emojis by position to ensure consistent ordering; of two matches starting
+	// at the same position the longer one comes first, so a kaomoji wins over the
+	// symbols inside it
+	slices.SortStableFunc(result.Emojis, func(a, b types.EmojiMatch) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return b.End - a.End
 	})
 
 	// Remove overlapping detections (keep the first one found)
 	result.Emojis = removeOverlaps(result.Emojis)
+
+	// Drop the emojis inside antimoji:off / antimoji:on regions
+	result.Emojis, result.SuppressedRegions = suppressRegions(contentStr, positions, result.Emojis, patterns.Comments, patterns.StringDelimiters)
+	if patterns.MarkComments && !patterns.Comments.IsZero() {
+		markComments(contentStr, result.Emojis, patterns.Comments, patterns.StringDelimiters)
+	}
 	result.TotalCount = len(result.Emojis)
 
 	result.ProcessedBytes = int64(len(content))
+	result.Lines = countLines(content)
 	result.PatternsApplied = patternsApplied
 	result.Duration = time.Since(startTime)
 	result.Finalize()
+	*into = result
+}
 
-	return types.Ok(result)
+// countLines returns the number of lines in content, counting a final line without a
+// trailing newline.
+func countLines(content []byte) int {
+	lines := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		lines++
+	}
+	return lines
 }
 
 // DefaultEmojiPatterns returns default patterns for emoji detection.
@@ -138,9 +277,84 @@ func DefaultEmojiPatterns() types.EmojiPatterns {
 			`:star:`, `:check:`, `:cross:`, `:warning:`,
 			`:fire:`, `:rocket:`, `:tada:`, `:sparkles:`, `:zap:`,
 		},
+		Shortcodes: shortcode.Table(),
+		KaomojiPatterns: []string{
+			`(^_^)`, `(^o^)`, `(^.^)`, `(^_^;)`, `\(^o^)/`, `(T_T)`, `(;_;)`, `(>_<)`,
+			`(-_-)`, `(o_O)`, `(O_o)`, `(*_*)`, `(x_x)`, `m(_ _)m`, `^_^`, `>_<`,
+			`¯\_(ツ)_/¯`, `(ಠ_ಠ)`, `( ͡° ͜ʖ ͡°)`, `ʕ•ᴥ•ʔ`,
+		},
+		DecorativeRanges: []types.UnicodeRange{
+			// Geometric Shapes, except the emoji-presentation squares U+25FD and U+25FE
+			{Start: 0x25A0, End: 0x25FC, Name: "Geometric Shapes"},
+			// Stars
+			{Start: 0x2605, End: 0x2606, Name: "Stars"},
+			// Card suits
+			{Start: 0x2660, End: 0x2667, Name: "Card Suits"},
+			// Musical symbols
+			{Start: 0x2669, End: 0x266F, Name: "Musical Symbols"},
+			// Check marks and crosses
+			{Start: 0x2713, End: 0x2718, Name: "Check Marks"},
+			// Dingbat stars, asterisks and florettes
+			{Start: 0x2720, End: 0x2727, Name: "Dingbat Stars"},
+			{Start: 0x2729, End: 0x274B, Name: "Dingbat Asterisks"},
+			// Hearts
+			{Start: 0x2764, End: 0x2767, Name: "Hearts"},
+			// Dingbat arrows
+			{Start: 0x2798, End: 0x27AF, Name: "Dingbat Arrows"},
+			{Start: 0x27B1, End: 0x27BE, Name: "Dingbat Arrows"},
+		},
+		HygieneRanges: []types.UnicodeRange{
+			{Start: 0x200B, End: 0x200B, Name: "Zero Width Space"},
+			{Start: 0x2060, End: 0x2060, Name: "Word Joiner"},
+			{Start: 0xFEFF, End: 0xFEFF, Name: "Byte Order Mark"},
+			// Embeddings, overrides and isolates, which reorder source code as displayed
+			{Start: 0x202A, End: 0x202E, Name: "Bidirectional Control"},
+			{Start: 0x2066, End: 0x2069, Name: "Bidirectional Control"},
+		},
+		EvasionRanges: []types.UnicodeRange{
+			// Invisible characters: zero width space, non-joiner and joiner
+			{Start: 0x200B, End: 0x200D, Name: "Zero Width Characters"},
+			// Bidirectional embeddings, overrides and isolates
+			{Start: 0x202A, End: 0x202E, Name: "Bidirectional Controls"},
+			{Start: 0x2066, End: 0x2069, Name: "Bidirectional Controls"},
+			// Word joiner and invisible operators
+			{Start: 0x2060, End: 0x2064, Name: "Invisible Operators"},
+			{Start: 0x180E, End: 0x180E, Name: "Mongolian Vowel Separator"},
+			{Start: 0xFEFF, End: 0xFEFF, Name: "Zero Width No-Break Space"},
+			{Start: 0xFE00, End: 0xFE0F, Name: "Variation Selectors"},
+			{Start: 0xE0000, End: 0xE007F, Name: "Tags"},
+			{Start: 0xE0100, End: 0xE01EF, Name: "Variation Selectors Supplement"},
+			// Pictographs outside the emoji ranges
+			{Start: 0x1F000, End: 0x1F0FF, Name: "Game Symbols"},
+			{Start: 0x1F100, End: 0x1F1FF, Name: "Enclosed Alphanumerics"},
+			{Start: 0x1F200, End: 0x1F2FF, Name: "Enclosed Ideographs"},
+			{Start: 0x1F650, End: 0x1F67F, Name: "Ornamental Dingbats"},
+			{Start: 0x1F700, End: 0x1F7FF, Name: "Alchemical and Geometric Shapes"},
+			{Start: 0x1F800, End: 0x1F8FF, Name: "Supplemental Arrows"},
+			{Start: 0x1FA00, End: 0x1FA6F, Name: "Chess Symbols"},
+			{Start: 0x1FB00, End: 0x1FBFF, Name: "Legacy Computing"},
+		},
 	}
 }
 
+// decorativeSymbolEnd returns the end of the decorative symbol starting at runes[i],
+// including a following text variation selector, or i when there is none. A symbol
+// followed by the emoji variation selector is left to Unicode emoji detection.
+func decorativeSymbolEnd(runes []rune, i int, ranges []types.UnicodeRange) int {
+	if !isUnicodeEmoji(runes[i], ranges) {
+		return i
+	}
+	if i+1 < len(runes) {
+		switch runes[i+1] {
+		case 0xFE0F:
+			return i
+		case 0xFE0E:
+			return i + 2
+		}
+	}
+	return i + 1
+}
+
 // isUnicodeEmoji checks if a rune is a Unicode emoji.
 func isUnicodeEmoji(r rune, ranges []types.UnicodeRange) bool {
 	for _, urange := range ranges {
@@ -151,25 +365,8 @@ func isUnicodeEmoji(r rune, ranges []types.UnicodeRange) bool {
 	return false
 }
 
-// isEmojiModifier checks if a rune is an emoji modifier (like skin tone).
-func isEmojiModifier(r rune) bool {
-	// Skin tone modifiers
-	if r >= 0x1F3FB && r <= 0x1F3FF {
-		return true
-	}
-	// Zero Width Joiner
-	if r == 0x200D {
-		return true
-	}
-	// Variation Selector-16 (emoji presentation)
-	if r == 0xFE0F {
-		return true
-	}
-	return false
-}
-
 // detectEmoticons detects text-based emoticons in content.
-func detectEmoticons(content string, patterns []string, result types.DetectionResult) (types.DetectionResult, int) {
+func detectEmoticons(content string, positions *lineIndex, patterns []string, result types.DetectionResult) (types.DetectionResult, int) {
 	for _, pattern := range patterns {
 		// Skip empty patterns to avoid zero-length matches causing infinite loops
 		if len(pattern) == 0 {
@@ -183,7 +380,7 @@ func detectEmoticons(content string, patterns []string, result types.DetectionRe
 				break
 			}
 
-			line, column := calculatePosition(content, index)
+			line, column := positions.position(index)
 			match := types.EmojiMatch{
 				Emoji:    pattern,
 				Start:    index,
@@ -200,7 +397,7 @@ func detectEmoticons(content string, patterns []string, result types.DetectionRe
 }
 
 // detectCustomPatterns detects custom emoji patterns in content.
-func detectCustomPatterns(content string, patterns []string, result types.DetectionResult) (types.DetectionResult, int) {
+func detectCustomPatterns(content string, positions *lineIndex, patterns []string, result types.DetectionResult) (types.DetectionResult, int) {
 	for _, pattern := range patterns {
 		// Skip empty patterns to avoid pathological regex behavior
 		if len(pattern) == 0 {
@@ -212,7 +409,7 @@ func detectCustomPatterns(content string, patterns []string, result types.Detect
 
 		for _, match := range matches {
 			start, end := match[0], match[1]
-			line, column := calculatePosition(content, start)
+			line, column := positions.position(start)
 
 			emojiMatch := types.EmojiMatch{
 				Emoji:    pattern,
@@ -228,49 +425,277 @@ func detectCustomPatterns(content string, patterns []string, result types.Detect
 	return result, len(patterns)
 }
 
+// kaomojiFaceRegex matches kaomoji built from characters typical of faces, such as
+// (╯°□°）╯︵ ┻━┻ or (◕‿◕): a bracketed face holding at least one of them, with
+// optional arms. Faces containing digits, Latin letters other than o and O, or
+// Japanese text are left alone, so that ordinary parenthesized prose does not match.
+var kaomojiFaceRegex = func() *regexp.Regexp {
+	const (
+		faceChars     = `ツ°□益ಠ◕‿ω´｀･・∀▽≧≦ᴥ˘ᴗ︶￣＾ʖ͜͡ಥ╥﹏ºᵔ◡⊙◉ʘ∇Д皿`
+		interior      = `(?:[^\s()（）ʕʔ༼༽0-9A-NP-Za-np-z\p{Hiragana}\p{Katakana}\p{Han}]|[ツノ益皿]| )`
+		leftArm       = `(?:¯\\_|[ノﾉ╯ヽ٩づっԅ┐┌ヾ＼\\])?`
+		rightArm      = `(?:_/¯|[ノﾉ╯۶づっ┘ヾゞ／/])?`
+		tableFlip     = `(?: ?[︵彡] ?┻━┻)?`
+		faceOpenClose = `[(（ʕ༼]%s{0,8}[%s]%s{0,8}[)）ʔ༽]`
+	)
+	face := fmt.Sprintf(faceOpenClose, interior, faceChars, interior)
+	return regexp.MustCompile(leftArm + face + rightArm + tableFlip)
+}()
+
+// detectKaomoji detects kaomoji in content: the given literal patterns and, when there
+// are any, faces matched by kaomojiFaceRegex. Faces hold a non-ASCII face character, so
+// the regex is skipped for ASCII content.
+func detectKaomoji(content string, positions *lineIndex, ascii bool, patterns []string, result types.DetectionResult) (types.DetectionResult, int) {
+	if len(patterns) == 0 {
+		return result, 0
+	}
+
+	for _, pattern := range patterns {
+		if len(pattern) == 0 {
+			continue
+		}
+		for start := 0; ; {
+			index := findEmoticonAt(content, pattern, start)
+			if index == -1 {
+				break
+			}
+			result.AddEmoji(kaomojiMatch(content, positions, index, index+len(pattern)))
+			start = index + len(pattern)
+		}
+	}
+
+	if ascii {
+		return result, len(patterns) + 1
+	}
+	for _, match := range kaomojiFaceRegex.FindAllStringIndex(content, -1) {
+		result.AddEmoji(kaomojiMatch(content, positions, match[0], match[1]))
+	}
+	return result, len(patterns) + 1
+}
+
+// kaomojiMatch returns the kaomoji match for content[start:end].
+func kaomojiMatch(content string, positions *lineIndex, start, end int) types.EmojiMatch {
+	line, column := positions.position(start)
+	return types.EmojiMatch{
+		Emoji:    content[start:end],
+		Start:    start,
+		End:      end,
+		Line:     line,
+		Column:   column,
+		Category: types.CategoryKaomoji,
+	}
+}
+
 // findEmoticonAt finds an emoticon pattern at or after the given start position.
 func findEmoticonAt(content, pattern string, start int) int {
 	if start >= len(content) {
 		return -1
 	}
 
-	for i := start; i <= len(content)-len(pattern); i++ {
-		if content[i:i+len(pattern)] == pattern {
-			// Check if it's not part of a larger word (basic boundary check)
-			if i > 0 && isAlphanumeric(rune(content[i-1])) {
+	for i := start; ; i++ {
+		index := strings.Index(content[i:], pattern)
+		if index == -1 {
+			return -1
+		}
+		i += index
+		// Check if it's not part of a larger word (basic boundary check)
+		if i > 0 && isAlphanumeric(rune(content[i-1])) {
+			continue
+		}
+		if i+len(pattern) < len(content) && isAlphanumeric(rune(content[i+len(pattern)])) {
+			continue
+		}
+		return i
+	}
+}
+
+// isAlphanumeric checks if a rune is alphanumeric.
+func isAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// Markers that disable detection from the line holding SuppressOffMarker through the
+// line holding the next SuppressOnMarker, or through the end of the content without one.
+// In languages with comments, markers only count inside comments.
+const (
+	SuppressOffMarker = "antimoji:off"
+	SuppressOnMarker  = "antimoji:on"
+)
+
+// suppressRegions removes the emojis inside suppressed regions of content from emojis,
+// which are sorted by position, and returns the remaining emojis and the regions.
+func suppressRegions(content string, positions *lineIndex, emojis []types.EmojiMatch, comments types.CommentSyntax, quotes []string) ([]types.EmojiMatch, []types.SuppressedRegion) {
+	if !strings.Contains(content, SuppressOffMarker) {
+		return emojis, nil
+	}
+
+	type span struct{ start, end int }
+	var spans []span
+	var regions []types.SuppressedRegion
+	for pos := 0; ; {
+		off := findMarker(content, pos, SuppressOffMarker, comments, quotes)
+		if off == -1 {
+			break
+		}
+		start := strings.LastIndexByte(content[:off], '\n') + 1
+
+		end := len(content)
+		if on := findMarker(content, off+len(SuppressOffMarker), SuppressOnMarker, comments, quotes); on != -1 {
+			end = on + len(SuppressOnMarker)
+			if newline := strings.IndexByte(content[end:], '\n'); newline != -1 {
+				end += newline
+			} else {
+				end = len(content)
+			}
+		}
+
+		startLine, _ := positions.position(start)
+		endLine := startLine + strings.Count(content[start:end], "\n")
+		spans = append(spans, span{start, end})
+		regions = append(regions, types.SuppressedRegion{StartLine: startLine, EndLine: endLine})
+		pos = end
+	}
+
+	kept := make([]types.EmojiMatch, 0, len(emojis))
+	region := 0
+	for _, emoji := range emojis {
+		for region < len(spans) && spans[region].end <= emoji.Start {
+			region++
+		}
+		if region < len(spans) && emoji.Start >= spans[region].start {
+			regions[region].Suppressed++
+			continue
+		}
+		kept = append(kept, emoji)
+	}
+	return kept, regions
+}
+
+// findMarker returns the position of the first marker in content from pos on that is
+// inside a comment, or of the first marker at all when comments is empty; -1 if none.
+func findMarker(content string, pos int, marker string, comments types.CommentSyntax, quotes []string) int {
+	for {
+		found := strings.Index(content[pos:], marker)
+		if found == -1 {
+			return -1
+		}
+		found += pos
+		if comments.IsZero() || inComment(content, found, comments, quotes) {
+			return found
+		}
+		pos = found + len(marker)
+	}
+}
+
+// inComment reports whether pos in content is inside a comment: after an unclosed block
+// comment opener, or after a line comment marker that is not inside a string literal on
+// the same line. Block comment openers inside strings are not recognised.
+func inComment(content string, pos int, comments types.CommentSyntax, quotes []string) bool {
+	for _, block := range comments.Block {
+		open := strings.LastIndex(content[:pos], block.Start)
+		if open != -1 && !strings.Contains(content[open+len(block.Start):pos], block.End) {
+			return true
+		}
+	}
+
+	line := content[strings.LastIndexByte(content[:pos], '\n')+1 : pos]
+	for i := 0; i < len(line); {
+		if hasAnyPrefix(line[i:], comments.Line) != "" {
+			return true
+		}
+		quote := hasAnyPrefix(line[i:], quotes)
+		if quote == "" {
+			i++
+			continue
+		}
+		// Skip the string literal, minding escaped quotes
+		i += len(quote)
+		for i < len(line) && !strings.HasPrefix(line[i:], quote) {
+			if line[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		i += len(quote)
+	}
+	return false
+}
+
+// markComments sets InComment on the emojis inside comments of content, which are
+// sorted by position, in one pass over content. Like inComment, it takes string
+// literals to end with their line.
+func markComments(content string, emojis []types.EmojiMatch, comments types.CommentSyntax, quotes []string) {
+	var blockEnd, quote string
+	lineComment := false
+	next := 0
+	for i := 0; i < len(content) && next < len(emojis); {
+		for next < len(emojis) && emojis[next].Start <= i {
+			emojis[next].InComment = lineComment || blockEnd != ""
+			next++
+		}
+		switch {
+		case content[i] == '\n':
+			lineComment, quote = false, ""
+			i++
+		case lineComment:
+			i++
+		case blockEnd != "":
+			if strings.HasPrefix(content[i:], blockEnd) {
+				i += len(blockEnd)
+				blockEnd = ""
+				continue
+			}
+			i++
+		case quote != "":
+			switch {
+			case content[i] == '\\':
+				i += 2
+			case strings.HasPrefix(content[i:], quote):
+				i += len(quote)
+				quote = ""
+			default:
+				i++
+			}
+		default:
+			if hasAnyPrefix(content[i:], comments.Line) != "" {
+				lineComment = true
 				continue
 			}
-			if i+len(pattern) < len(content) && isAlphanumeric(rune(content[i+len(pattern)])) {
+			if block, ok := blockOpener(content[i:], comments.Block); ok {
+				blockEnd = block.End
+				i += len(block.Start)
 				continue
 			}
-			return i
+			if quote = hasAnyPrefix(content[i:], quotes); quote != "" {
+				i += len(quote)
+				continue
+			}
+			i++
 		}
 	}
-	return -1
+	for ; next < len(emojis); next++ {
+		emojis[next].InComment = lineComment || blockEnd != ""
+	}
 }
 
-// calculatePosition calculates line and column from byte position.
-func calculatePosition(content string, bytePos int) (line, column int) {
-	line = 1
-	column = 1
-
-	for i, r := range content {
-		if i >= bytePos {
-			break
-		}
-		if r == '\n' {
-			line++
-			column = 1
-		} else {
-			column++
+// blockOpener returns the block comment that s starts with.
+func blockOpener(s string, blocks []types.BlockComment) (types.BlockComment, bool) {
+	for _, block := range blocks {
+		if block.Start != "" && strings.HasPrefix(s, block.Start) {
+			return block, true
 		}
 	}
-	return line, column
+	return types.BlockComment{}, false
 }
 
-// isAlphanumeric checks if a rune is alphanumeric.
-func isAlphanumeric(r rune) bool {
-	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+// hasAnyPrefix returns the first of prefixes that s starts with, or "".
+func hasAnyPrefix(s string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(s, prefix) {
+			return prefix
+		}
+	}
+	return ""
 }
 
 // removeOverlaps removes overlapping emoji matches, keeping the first one.
@@ -279,8 +704,8 @@ func removeOverlaps(emojis []types.EmojiMatch) []types.EmojiMatch {
 		return emojis
 	}
 
-	result := make([]types.EmojiMatch, 0, len(emojis))
-	result = append(result, emojis[0])
+	// Compact in place: the kept matches never overtake the ones being read
+	result := emojis[:1]
 
 	for i := 1; i < len(emojis); i++ {
 		current := emojis[i]
@@ -324,17 +749,8 @@ func createEmojiDebugInfo(runes []rune, ranges []types.UnicodeRange) map[string]
 	// Add information about modifiers if present
 	if len(runes) > 1 {
 		var modifierTypes []string
-		for i := 1; i < len(runes); i++ {
-			r := runes[i]
-			if r >= 0x1F3FB && r <= 0x1F3FF {
-				modifierTypes = append(modifierTypes, "skin_tone")
-			} else if r == 0x200D {
-				modifierTypes = append(modifierTypes, "zwj")
-			} else if r == 0xFE0F {
-				modifierTypes = append(modifierTypes, "variation_selector")
-			} else {
-				modifierTypes = append(modifierTypes, "other")
-			}
+		for _, r := range runes[1:] {
+			modifierTypes = append(modifierTypes, sequenceComponent(r))
 		}
 		debugInfo["modifiers"] = modifierTypes
 	}
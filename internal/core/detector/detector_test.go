@@ -2,10 +2,12 @@ package detector
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/antimoji/antimoji/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDetectEmojis(t *testing.T) {
@@ -259,6 +261,268 @@ func TestDetectEmojis_Properties(t *testing.T) {
 	})
 }
 
+func TestDetectEmojis_Kaomoji(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"shrug", `meh ¯\_(ツ)_/¯ ok`, []string{`¯\_(ツ)_/¯`}},
+		{"table flip keeps the symbols inside", "(╯°□°）╯︵ ┻━┻", []string{"(╯°□°）╯︵ ┻━┻"}},
+		{"flip with arms", "(ノಠ益ಠ)ノ彡┻━┻", []string{"(ノಠ益ಠ)ノ彡┻━┻"}},
+		{"lenny", "( ͡° ͜ʖ ͡°)", []string{"( ͡° ͜ʖ ͡°)"}},
+		{"bear", "ʕ•ᴥ•ʔ", []string{"ʕ•ᴥ•ʔ"}},
+		{"ascii faces", "done (^_^) and >_<", []string{"(^_^)", ">_<"}},
+		{"parenthesized prose", "f(a * 2) at (90°) or (°C)", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DetectEmojis([]byte(tt.content), patterns).Unwrap()
+
+			var found []string
+			for _, match := range result.Emojis {
+				assert.Equal(t, types.CategoryKaomoji, match.Category)
+				assert.Equal(t, match.Emoji, tt.content[match.Start:match.End])
+				found = append(found, match.Emoji)
+			}
+			assert.Equal(t, tt.want, found)
+		})
+	}
+
+	t.Run("off without kaomoji patterns", func(t *testing.T) {
+		patterns := DefaultEmojiPatterns()
+		patterns.KaomojiPatterns = nil
+
+		result := DetectEmojis([]byte(`¯\_(ツ)_/¯ (^_^)`), patterns).Unwrap()
+		assert.Empty(t, result.Emojis)
+	})
+}
+
+func TestDetectEmojis_Shortcodes(t *testing.T) {
+	patterns := types.EmojiPatterns{
+		EmoticonPatterns: []string{`:)`},
+		CustomPatterns:   []string{`:smile:`},
+		Shortcodes:       DefaultEmojiPatterns().Shortcodes,
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"shortcodes", "Ship it :rocket: :tada:", []string{":rocket:", ":tada:"}},
+		{"adjacent shortcodes", ":+1::heart:", []string{":+1:", ":heart:"}},
+		{"unknown names", ":not_an_emoji: and :rocket_ship:", nil},
+		{"glued to words", "key:rocket: and :rocket:s", nil},
+		{"after another colon", "Done :) :rocket:", []string{":rocket:"}},
+		{"scope operators", "std::x and a::b", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DetectEmojis([]byte(tt.content), patterns).Unwrap()
+
+			var found []string
+			for _, match := range result.Emojis {
+				if match.Category == types.CategoryShortcode {
+					assert.Equal(t, match.Emoji, tt.content[match.Start:match.End])
+					found = append(found, match.Emoji)
+				}
+			}
+			assert.Equal(t, tt.want, found)
+		})
+	}
+
+	t.Run("custom patterns keep their category", func(t *testing.T) {
+		result := DetectEmojis([]byte(":smile: :grin:"), patterns).Unwrap()
+		require.Len(t, result.Emojis, 2)
+		assert.Equal(t, types.CategoryCustom, result.Emojis[0].Category)
+		assert.Equal(t, types.CategoryShortcode, result.Emojis[1].Category)
+	})
+}
+
+func TestDetectEmojis_Escapes(t *testing.T) {
+	all := types.EscapeHTMLEntity | types.EscapeUTF16 | types.EscapeUnicodeBraces | types.EscapeUnicodeLong
+
+	tests := []struct {
+		name     string
+		content  string
+		syntaxes types.EscapeSyntax
+		want     []string
+		escaped  []string
+	}{
+		{"html entities", "<p>&#x1F600; &#128512; &#233; &amp;</p>", all, []string{"😀", "😀"}, []string{"&#x1F600;", "&#128512;"}},
+		{"surrogate pair", `"\uD83D\uDE80 launch"`, all, []string{"🚀"}, []string{`\uD83D\uDE80`}},
+		{"basic plane escape", `"\u2705 done \u00e9"`, all, []string{"✅"}, []string{`\u2705`}},
+		{"braced escape", `"\u{1F389}"`, all, []string{"🎉"}, []string{`\u{1F389}`}},
+		{"long escape", `"\U0001F680"`, all, []string{"🚀"}, []string{`\U0001F680`}},
+		{"escaped sequence", `"\uD83D\uDC68\u200D\uD83D\uDCBB\u2764\uFE0F"`, all,
+			[]string{"👨‍💻", "❤️"}, []string{`\uD83D\uDC68\u200D\uD83D\uDCBB`, `\u2764\uFE0F`}},
+		{"escaped backslash", `"\\uD83D\\uDE80 and \\\u2705"`, all, []string{"✅"}, []string{`\u2705`}},
+		{"lone surrogates", `"\uD83D and \uDE80"`, all, nil, nil},
+		{"syntax not enabled", `&#x1F600; \u{1F389}`, types.EscapeUTF16, nil, nil},
+		{"no syntaxes", `\uD83D\uDE80`, 0, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns := DefaultEmojiPatterns()
+			patterns.Escapes = tt.syntaxes
+			result := DetectEmojis([]byte(tt.content), patterns).Unwrap()
+
+			var found, escaped []string
+			for _, match := range result.Emojis {
+				assert.Equal(t, types.CategoryEscaped, match.Category)
+				found = append(found, match.Emoji)
+				escaped = append(escaped, tt.content[match.Start:match.End])
+			}
+			assert.Equal(t, tt.want, found)
+			assert.Equal(t, tt.escaped, escaped)
+		})
+	}
+}
+
+func TestDetectEmojis_Decorative(t *testing.T) {
+	t.Run("symbols get their own category", func(t *testing.T) {
+		result := DetectEmojis([]byte("★ ♥ ► ✓"), DefaultEmojiPatterns()).Unwrap()
+
+		assert.Len(t, result.Emojis, 4)
+		for _, match := range result.Emojis {
+			assert.Equal(t, types.CategoryDecorative, match.Category, match.Emoji)
+		}
+	})
+
+	t.Run("emoji presentation stays unicode", func(t *testing.T) {
+		result := DetectEmojis([]byte("❤\uFE0F ❤\uFE0E"), DefaultEmojiPatterns()).Unwrap()
+
+		assert.Len(t, result.Emojis, 2)
+		assert.Equal(t, "❤\uFE0F", result.Emojis[0].Emoji)
+		assert.Equal(t, types.CategoryUnicode, result.Emojis[0].Category)
+		assert.Equal(t, "❤\uFE0E", result.Emojis[1].Emoji)
+		assert.Equal(t, types.CategoryDecorative, result.Emojis[1].Category)
+	})
+
+	t.Run("off without decorative ranges", func(t *testing.T) {
+		patterns := DefaultEmojiPatterns()
+		patterns.DecorativeRanges = nil
+
+		result := DetectEmojis([]byte("★ ►"), patterns).Unwrap()
+		assert.Len(t, result.Emojis, 1, "★ is still a Unicode emoji, ► is not")
+		assert.Equal(t, types.CategoryUnicode, result.Emojis[0].Category)
+	})
+}
+
+func TestDetectEmojis_SuppressedRegions(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+
+	t.Run("skips emojis between markers", func(t *testing.T) {
+		content := "😀\n# antimoji:off\n🚀 🚀\n# antimoji:on 🎉\n✨\n"
+		result := DetectEmojis([]byte(content), patterns).Unwrap()
+
+		var found []string
+		for _, match := range result.Emojis {
+			found = append(found, match.Emoji)
+		}
+		assert.Equal(t, []string{"😀", "✨"}, found)
+		assert.Equal(t, 2, result.TotalCount)
+		assert.Equal(t, []types.SuppressedRegion{{StartLine: 2, EndLine: 4, Suppressed: 3}}, result.SuppressedRegions)
+	})
+
+	t.Run("unterminated region runs to the end", func(t *testing.T) {
+		content := "😀\n<!-- antimoji:off -->\n🚀\n🎉"
+		result := DetectEmojis([]byte(content), patterns).Unwrap()
+
+		assert.Len(t, result.Emojis, 1)
+		assert.Equal(t, []types.SuppressedRegion{{StartLine: 2, EndLine: 4, Suppressed: 2}}, result.SuppressedRegions)
+	})
+
+	t.Run("several regions", func(t *testing.T) {
+		content := "antimoji:off 😀 antimoji:on\n🚀\nantimoji:off\n🎉\nantimoji:on\n"
+		result := DetectEmojis([]byte(content), patterns).Unwrap()
+
+		assert.Len(t, result.Emojis, 1)
+		assert.Equal(t, []types.SuppressedRegion{
+			{StartLine: 1, EndLine: 1, Suppressed: 1},
+			{StartLine: 3, EndLine: 5, Suppressed: 1},
+		}, result.SuppressedRegions)
+	})
+
+	t.Run("no markers", func(t *testing.T) {
+		result := DetectEmojis([]byte("😀 antimoji:on"), patterns).Unwrap()
+		assert.Len(t, result.Emojis, 1)
+		assert.Nil(t, result.SuppressedRegions)
+	})
+
+	t.Run("markers only count in comments when the language has them", func(t *testing.T) {
+		goPatterns := patterns
+		goPatterns.Comments = types.CommentSyntax{Line: []string{"//"}, Block: []types.BlockComment{{Start: "/*", End: "*/"}}}
+		goPatterns.StringDelimiters = []string{`"`, "`"}
+
+		content := strings.Join([]string{
+			`marker := "// antimoji:off" // 😀`,
+			`url := "http://x" // antimoji:off`,
+			`a := "🚀" /* antimoji:on */`,
+			`/*`,
+			` * antimoji:off`,
+			` */`,
+			`b := "🎉" // "antimoji:on"`,
+			`c := "✨"`,
+		}, "\n")
+		result := DetectEmojis([]byte(content), goPatterns).Unwrap()
+
+		var found []string
+		for _, match := range result.Emojis {
+			found = append(found, match.Emoji)
+		}
+		assert.Equal(t, []string{"😀", "✨"}, found)
+		assert.Equal(t, []types.SuppressedRegion{
+			{StartLine: 2, EndLine: 3, Suppressed: 1},
+			{StartLine: 5, EndLine: 7, Suppressed: 1},
+		}, result.SuppressedRegions)
+	})
+}
+
+func TestDetectEmojis_MarkComments(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+	patterns.Comments = types.CommentSyntax{Line: []string{"//"}, Block: []types.BlockComment{{Start: "/*", End: "*/"}}}
+	patterns.StringDelimiters = []string{`"`}
+	content := strings.Join([]string{
+		`a := "🚀 // not a comment" // 😀`,
+		`/* 🎉`,
+		`   ✨ */ b := "\"🔥"`,
+		`c := 1 // "🌟`,
+	}, "\n")
+
+	inComment := func(patterns types.EmojiPatterns) map[string]bool {
+		marked := make(map[string]bool)
+		for _, match := range DetectEmojis([]byte(content), patterns).Unwrap().Emojis {
+			marked[match.Emoji] = match.InComment
+		}
+		return marked
+	}
+
+	assert.Equal(t, map[string]bool{"🚀": false, "😀": false, "🎉": false, "✨": false, "🔥": false, "🌟": false}, inComment(patterns),
+		"comments are only marked when asked for")
+	patterns.MarkComments = true
+	assert.Equal(t, map[string]bool{"🚀": false, "😀": true, "🎉": true, "✨": true, "🔥": false, "🌟": true}, inComment(patterns))
+}
+
+func TestDetectEmojis_Lines(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+	for content, want := range map[string]int{
+		"":               0,
+		"one":            1,
+		"one\n":          1,
+		"one\ntwo 🚀":     2,
+		"one\n\nthree\n": 3,
+	} {
+		assert.Equal(t, want, DetectEmojis([]byte(content), patterns).Unwrap().Lines, "content %q", content)
+	}
+}
+
 func TestDefaultEmojiPatterns(t *testing.T) {
 	t.Run("returns valid default patterns", func(t *testing.T) {
 		patterns := DefaultEmojiPatterns()
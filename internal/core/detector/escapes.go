@@ -0,0 +1,121 @@
+package detector
+
+import (
+	"regexp"
+	"strconv"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// escapeRegex matches one escaped code point in any supported syntax. Surrogate halves
+// are matched one at a time and paired up while decoding.
+var escapeRegex = regexp.MustCompile(`&#[xX][0-9a-fA-F]{1,6};|&#[0-9]{1,7};|\\u\{[0-9a-fA-F]{1,6}\}|\\u[0-9a-fA-F]{4}|\\U[0-9a-fA-F]{8}`)
+
+// escapedRune is a code point decoded from content[start:end].
+type escapedRune struct {
+	start, end int
+	r          rune
+}
+
+// detectEscapes detects emojis spelled with the given escape syntaxes, such as
+// "&#x1F600;" or "\u{1F600}". Adjacent escapes are decoded together, so an escaped
+// sequence such as a ZWJ sequence is a single match. Matches report the decoded emoji.
+func detectEscapes(content string, positions *lineIndex, syntaxes types.EscapeSyntax, ranges []types.UnicodeRange, result types.DetectionResult) (types.DetectionResult, int) {
+	if syntaxes == 0 || len(ranges) == 0 {
+		return result, 0
+	}
+
+	escapes := decodeEscapes(content, syntaxes)
+	for first := 0; first < len(escapes); {
+		// Decode each run of adjacent escapes as one piece of text
+		last := first + 1
+		for last < len(escapes) && escapes[last].start == escapes[last-1].end {
+			last++
+		}
+		run := escapes[first:last]
+		runes := make([]rune, len(run))
+		for i, escaped := range run {
+			runes[i] = escaped.r
+		}
+
+		for i := 0; i < len(runes); i++ {
+			end := emojiSequenceEnd(runes, i, ranges)
+			if end <= i {
+				continue
+			}
+			start := run[i].start
+			line, column := positions.position(start)
+			result.AddEmoji(types.EmojiMatch{
+				Emoji:     string(runes[i:end]),
+				Start:     start,
+				End:       run[end-1].end,
+				Line:      line,
+				Column:    column,
+				Category:  types.CategoryEscaped,
+				DebugInfo: map[string]interface{}{"escaped": content[start:run[end-1].end]},
+			})
+			i = end - 1
+		}
+		first = last
+	}
+	return result, 1
+}
+
+// decodeEscapes returns the code points escaped in content with the given syntaxes, in
+// order. UTF-16 surrogate pairs are combined into one code point; escapes whose
+// backslash is itself escaped are literal text.
+func decodeEscapes(content string, syntaxes types.EscapeSyntax) []escapedRune {
+	var escapes []escapedRune
+	for _, loc := range escapeRegex.FindAllStringIndex(content, -1) {
+		start, end := loc[0], loc[1]
+		token := content[start:end]
+
+		var syntax types.EscapeSyntax
+		var digits string
+		base := 16
+		switch {
+		case token[0] == '&' && (token[2] == 'x' || token[2] == 'X'):
+			syntax, digits = types.EscapeHTMLEntity, token[3:len(token)-1]
+		case token[0] == '&':
+			syntax, digits, base = types.EscapeHTMLEntity, token[2:len(token)-1], 10
+		case token[1] == 'U':
+			syntax, digits = types.EscapeUnicodeLong, token[2:]
+		case token[2] == '{':
+			syntax, digits = types.EscapeUnicodeBraces, token[3:len(token)-1]
+		default:
+			syntax, digits = types.EscapeUTF16, token[2:]
+		}
+		if syntaxes&syntax == 0 || (token[0] == '\\' && escapedBackslash(content, start)) {
+			continue
+		}
+
+		value, err := strconv.ParseInt(digits, base, 32)
+		if err != nil || value > 0x10FFFF {
+			continue
+		}
+		r := rune(value)
+
+		// A low surrogate completes the high surrogate escaped right before it
+		if n := len(escapes); syntax == types.EscapeUTF16 && utf16.IsSurrogate(r) && n > 0 &&
+			escapes[n-1].end == start && escapes[n-1].r >= 0xD800 && escapes[n-1].r < 0xDC00 {
+			if combined := utf16.DecodeRune(escapes[n-1].r, r); combined != unicode.ReplacementChar {
+				escapes[n-1].r, escapes[n-1].end = combined, end
+				continue
+			}
+		}
+		escapes = append(escapes, escapedRune{start: start, end: end, r: r})
+	}
+	return escapes
+}
+
+// escapedBackslash reports whether the backslash at content[pos] is preceded by an odd
+// number of backslashes, making it a literal backslash rather than an escape.
+func escapedBackslash(content string, pos int) bool {
+	backslashes := 0
+	for i := pos - 1; i >= 0 && content[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
@@ -0,0 +1,75 @@
+package detector
+
+import (
+	"unicode"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Code points with a legitimate use outside emoji sequences.
+const (
+	zeroWidthNonJoiner = 0x200C
+	byteOrderMark      = 0xFEFF
+)
+
+// evasionEnd returns the index just past the evasion finding starting at runes[i], or
+// i when there is none. A pictograph in ranges is one finding together with its
+// modifiers and variation selectors. Invisible characters in ranges are only findings
+// where nothing needs them, and a run of them is a single finding.
+func evasionEnd(runes []rune, i int, ranges []types.UnicodeRange) int {
+	if !isUnicodeEmoji(runes[i], ranges) {
+		return i
+	}
+	if !isInvisible(runes[i]) {
+		end := i + 1
+		for end < len(runes) && isEmojiExtender(runes[end]) {
+			end++
+		}
+		return end
+	}
+
+	end := i
+	for end < len(runes) && isInvisible(runes[end]) && isUnicodeEmoji(runes[end], ranges) && !isNeeded(runes, end) {
+		end++
+	}
+	return end
+}
+
+// isInvisible reports whether r has no visible form: format characters such as zero
+// width spaces, bidirectional controls and tags, and variation selectors.
+func isInvisible(r rune) bool {
+	return unicode.Is(unicode.Cf, r) || unicode.Is(unicode.Variation_Selector, r)
+}
+
+// isNeeded reports whether the invisible character runes[i] serves its purpose where
+// it stands: a byte order mark opening the text, a joiner or non-joiner shaping the
+// letters of a script such as Devanagari or Persian, or a variation selector choosing
+// the form of a symbol, digit or ideograph. Inside an emoji sequence they never get
+// here, since the sequence is detected first.
+func isNeeded(runes []rune, i int) bool {
+	r := runes[i]
+	switch {
+	case r == byteOrderMark:
+		return i == 0
+	case r == zeroWidthJoiner || r == zeroWidthNonJoiner:
+		return i > 0 && i+1 < len(runes) && isLetterOrMark(runes[i-1]) && isLetterOrMark(runes[i+1])
+	case unicode.Is(unicode.Variation_Selector, r):
+		if i == 0 {
+			return false
+		}
+		prev := runes[i-1]
+		return unicode.IsSymbol(prev) || unicode.IsDigit(prev) || unicode.IsPunct(prev) ||
+			unicode.Is(unicode.Ideographic, prev)
+	}
+	return false
+}
+
+// isHygieneFinding reports whether runes[i] is an invisible character in ranges,
+// other than a byte order mark at the start of the content.
+func isHygieneFinding(runes []rune, i int, ranges []types.UnicodeRange) bool {
+	return isUnicodeEmoji(runes[i], ranges) && (runes[i] != byteOrderMark || i > 0)
+}
+
+func isLetterOrMark(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r)
+}
@@ -0,0 +1,69 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectEmojis_Evasion(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+	// Hygiene findings take precedence over evasion ones for the characters both cover
+	patterns.HygieneRanges = nil
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"zero width space", "ship\u200bit", []string{"\u200b"}},
+		{"run of invisible characters", "a\u200b\u200c\u2060b", []string{"\u200b\u200c\u2060"}},
+		{"tag characters smuggling text", "ok\U000E0068\U000E0069", []string{"\U000E0068\U000E0069"}},
+		{"bidirectional override", "x = \u202eevil", []string{"\u202e"}},
+		{"variation selector after a letter", "ro\ufe0fcket", []string{"\ufe0f"}},
+		{"lone joiner", "\u200d", []string{"\u200d"}},
+		{"byte order mark inside the text", "a\ufeffb", []string{"\ufeff"}},
+		{"enclosed letter", "grade \U0001F170 ", []string{"\U0001F170"}},
+		{"enclosed letter with selector", "\U0001F170\ufe0f", []string{"\U0001F170\ufe0f"}},
+		{"legacy computing block", "\U0001FBC5", []string{"\U0001FBC5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := DetectEmojis([]byte(tt.content), patterns).Unwrap()
+			var found []string
+			for _, match := range detection.Emojis {
+				assert.Equal(t, types.CategoryEvasion, match.Category)
+				found = append(found, match.Emoji)
+			}
+			assert.Equal(t, tt.want, found)
+		})
+	}
+}
+
+func TestDetectEmojis_EvasionIgnoresLegitimateUses(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+
+	for _, content := range []string{
+		"\ufeffpackage main",  // byte order mark
+		"क\u094d\u200dष",      // joiner shaping Devanagari
+		"می\u200cخو",          // non-joiner in Persian
+		"↔\ufe0e and #\ufe0f", // text and emoji presentation of symbols
+		"葛\U000E0100",         // ideographic variation sequence
+		"plain ascii text",
+	} {
+		detection := DetectEmojis([]byte(content), patterns).Unwrap()
+		assert.Empty(t, detection.Emojis, "unexpected match in %q", content)
+	}
+}
+
+func TestDetectEmojis_EvasionLeavesEmojiSequences(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+
+	england := "\U0001F3F4\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F"
+	detection := DetectEmojis([]byte("👩\u200d💻 ❤\ufe0f 🇺🇸 "+england), patterns).Unwrap()
+	for _, match := range detection.Emojis {
+		assert.Equal(t, types.CategoryUnicode, match.Category, match.Emoji)
+	}
+	assert.Len(t, detection.Emojis, 4)
+}
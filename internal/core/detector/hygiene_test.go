@@ -0,0 +1,49 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectEmojis_Hygiene(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"zero width space", "ship\u200bit", []string{"\u200b"}},
+		{"each character is a finding", "a\u200b\u2060b", []string{"\u200b", "\u2060"}},
+		{"byte order mark inside the file", "a\ufeffb", []string{"\ufeff"}},
+		{"trojan source override", "if admin { /*\u202e } \u2066begin*/", []string{"\u202e", "\u2066"}},
+		{"byte order mark at the start", "\ufeffpackage main", nil},
+		{"joiner in a script", "क्\u200dष", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := DetectEmojis([]byte(tt.content), patterns).Unwrap()
+			var found []string
+			for _, match := range detection.Emojis {
+				assert.Equal(t, types.CategoryHygiene, match.Category)
+				found = append(found, match.Emoji)
+			}
+			assert.Equal(t, tt.want, found)
+		})
+	}
+}
+
+func TestDetectEmojis_HygienePositions(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+
+	detection := DetectEmojis([]byte("ab\n c\u200bd"), patterns).Unwrap()
+	if assert.Len(t, detection.Emojis, 1) {
+		match := detection.Emojis[0]
+		assert.Equal(t, 2, match.Line)
+		assert.Equal(t, 3, match.Column)
+		assert.Equal(t, 5, match.Start)
+		assert.Equal(t, 8, match.End)
+	}
+}
@@ -0,0 +1,66 @@
+package detector
+
+import (
+	"sync"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// maxPooledRunes bounds the rune buffers kept for reuse, so that one large file does
+// not pin its buffer for the rest of a scan.
+const maxPooledRunes = 1 << 20
+
+// DetectOptions configure DetectEmojisInto.
+type DetectOptions struct {
+	// ZeroCopy makes matches reference content instead of copying it: the Emoji of a
+	// match shares the memory of content, which must not be modified while the result
+	// is in use. DebugInfo is not collected.
+	ZeroCopy bool
+}
+
+var resultPool = sync.Pool{
+	New: func() any { return new(types.DetectionResult) },
+}
+
+var runePool = sync.Pool{
+	New: func() any { return new([]rune) },
+}
+
+// AcquireResult returns an empty detection result from a pool, for DetectEmojisInto.
+// Release it with ReleaseResult once its matches are no longer used.
+func AcquireResult() *types.DetectionResult {
+	result := resultPool.Get().(*types.DetectionResult)
+	result.Reset()
+	return result
+}
+
+// ReleaseResult returns result to the pool of AcquireResult. Neither result nor its
+// matches may be used afterwards.
+func ReleaseResult(result *types.DetectionResult) {
+	if result != nil {
+		resultPool.Put(result)
+	}
+}
+
+// acquireRunes returns an empty rune buffer from a pool.
+func acquireRunes() *[]rune {
+	buffer := runePool.Get().(*[]rune)
+	*buffer = (*buffer)[:0]
+	return buffer
+}
+
+// releaseRunes returns buffer to the pool unless it grew too large to keep.
+func releaseRunes(buffer *[]rune) {
+	if cap(*buffer) <= maxPooledRunes {
+		runePool.Put(buffer)
+	}
+}
+
+// matchText returns the text of the match of runes, which starts at byte start of
+// content and is width bytes long: a copy, or with ZeroCopy a substring of content.
+func matchText(content string, runes []rune, start, width int, opts DetectOptions) string {
+	if opts.ZeroCopy {
+		return content[start : start+width]
+	}
+	return string(runes)
+}
@@ -0,0 +1,117 @@
+package detector
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withoutDebugInfo returns matches without their DebugInfo, which ZeroCopy does not
+// collect.
+func withoutDebugInfo(matches []types.EmojiMatch) []types.EmojiMatch {
+	stripped := make([]types.EmojiMatch, len(matches))
+	for i, match := range matches {
+		match.DebugInfo = nil
+		stripped[i] = match
+	}
+	return stripped
+}
+
+func TestDetectEmojisInto(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+	contents := []string{
+		"",
+		"plain text",
+		"Hello 😀 world :) and 👩🏽\u200d💻 on\nthe next ❤\ufe0f line",
+		"table flip (╯°□°）╯︵ ┻━┻ and :rocket:",
+	}
+
+	result := AcquireResult()
+	defer ReleaseResult(result)
+	for _, opts := range []DetectOptions{{}, {ZeroCopy: true}} {
+		for _, content := range contents {
+			want := DetectEmojis([]byte(content), patterns).Unwrap()
+			DetectEmojisInto([]byte(content), patterns, result, opts)
+
+			assert.Equal(t, withoutDebugInfo(want.Emojis), withoutDebugInfo(result.Emojis), "%q", content)
+			assert.Equal(t, want.TotalCount, result.TotalCount)
+			assert.Equal(t, want.UniqueCount, result.UniqueCount)
+			assert.Equal(t, want.Lines, result.Lines)
+			assert.True(t, result.Success)
+		}
+	}
+
+	t.Run("nil content", func(t *testing.T) {
+		DetectEmojisInto([]byte("😀"), patterns, result, DetectOptions{})
+		DetectEmojisInto(nil, patterns, result, DetectOptions{})
+		assert.Empty(t, result.Emojis)
+		assert.Zero(t, result.TotalCount)
+		assert.True(t, result.Success)
+	})
+}
+
+func TestDetectEmojisInto_ZeroCopy(t *testing.T) {
+	content := []byte("ship it 🚀 now")
+	result := AcquireResult()
+	defer ReleaseResult(result)
+
+	DetectEmojisInto(content, DefaultEmojiPatterns(), result, DetectOptions{ZeroCopy: true})
+	require.Len(t, result.Emojis, 1)
+	match := result.Emojis[0]
+	assert.Equal(t, "🚀", match.Emoji)
+	assert.Nil(t, match.DebugInfo)
+	assert.Equal(t, unsafe.Pointer(&content[match.Start]), unsafe.Pointer(unsafe.StringData(match.Emoji)),
+		"the match references content")
+
+	DetectEmojisInto(content, DefaultEmojiPatterns(), result, DetectOptions{})
+	require.Len(t, result.Emojis, 1)
+	assert.NotEqual(t, unsafe.Pointer(&content[match.Start]), unsafe.Pointer(unsafe.StringData(result.Emojis[0].Emoji)))
+	assert.NotNil(t, result.Emojis[0].DebugInfo)
+}
+
+func TestDetectEmojisInto_ReusesMatches(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+	content := []byte("😀 😃 😄 😁 😆 😅 😂 🤣 and some more text")
+	result := AcquireResult()
+	defer ReleaseResult(result)
+	DetectEmojisInto(content, patterns, result, DetectOptions{ZeroCopy: true})
+
+	fresh := testing.AllocsPerRun(20, func() {
+		_ = DetectEmojis(content, patterns)
+	})
+	reused := testing.AllocsPerRun(20, func() {
+		DetectEmojisInto(content, patterns, result, DetectOptions{ZeroCopy: true})
+	})
+	assert.Less(t, reused, fresh)
+}
+
+func BenchmarkDetectEmojisInto(b *testing.B) {
+	patterns := DefaultEmojiPatterns()
+	content := []byte(generateLargeText(64 * 1024))
+
+	b.Run("DetectEmojis", func(b *testing.B) {
+		b.SetBytes(int64(len(content)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = DetectEmojis(content, patterns).Unwrap()
+		}
+	})
+	for _, opts := range []DetectOptions{{}, {ZeroCopy: true}} {
+		name := "pooled"
+		if opts.ZeroCopy {
+			name = "pooled_zero_copy"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				result := AcquireResult()
+				DetectEmojisInto(content, patterns, result, opts)
+				ReleaseResult(result)
+			}
+		})
+	}
+}
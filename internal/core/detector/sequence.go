@@ -0,0 +1,145 @@
+package detector
+
+import (
+	"unicode"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Code points that take part in multi-rune emoji sequences (Unicode TS #51).
+const (
+	zeroWidthJoiner   = 0x200D
+	variationSelector = 0xFE0F
+	combiningKeycap   = 0x20E3
+
+	skinToneFirst = 0x1F3FB
+	skinToneLast  = 0x1F3FF
+
+	regionalIndicatorFirst = 0x1F1E6
+	regionalIndicatorLast  = 0x1F1FF
+
+	tagFirst = 0xE0020
+	tagLast  = 0xE007F
+)
+
+// pictographicRanges approximates the Extended_Pictographic property, which decides
+// whether a rune following a zero width joiner continues the emoji. It is deliberately
+// wider than the default detection ranges so sequences such as "🐈‍⬛" stay whole.
+var pictographicRanges = []types.UnicodeRange{
+	{Start: 0x00A9, End: 0x00A9}, {Start: 0x00AE, End: 0x00AE},
+	{Start: 0x203C, End: 0x203C}, {Start: 0x2049, End: 0x2049},
+	{Start: 0x2122, End: 0x2122}, {Start: 0x2139, End: 0x2139},
+	{Start: 0x2194, End: 0x21AA}, {Start: 0x231A, End: 0x23FF},
+	{Start: 0x24C2, End: 0x24C2}, {Start: 0x25AA, End: 0x25FE},
+	{Start: 0x2600, End: 0x27BF}, {Start: 0x2934, End: 0x2935},
+	{Start: 0x2B05, End: 0x2B55}, {Start: 0x3030, End: 0x3030},
+	{Start: 0x303D, End: 0x303D}, {Start: 0x3297, End: 0x3299},
+	{Start: 0x1F000, End: 0x1FAFF},
+}
+
+// emojiSequenceEnd returns the index just past the emoji sequence starting at runes[start],
+// or start if no emoji starts there. A sequence is what users perceive as one emoji:
+// a flag (pair of regional indicators), a keycap ("1️⃣"), or a base emoji with its
+// modifiers, variation selectors and tags, optionally joined to further emojis with
+// zero width joiners ("👩🏽‍💻", "👨‍👩‍👧").
+func emojiSequenceEnd(runes []rune, start int, ranges []types.UnicodeRange) int {
+	if len(ranges) == 0 {
+		return start
+	}
+
+	if end := keycapEnd(runes, start); end > start {
+		return end
+	}
+
+	if !isUnicodeEmoji(runes[start], ranges) {
+		return start
+	}
+
+	end := emojiElementEnd(runes, start)
+	for end+1 < len(runes) && runes[end] == zeroWidthJoiner && isPictographic(runes[end+1], ranges) {
+		end = emojiElementEnd(runes, end+1)
+	}
+
+	// A trailing joiner belongs to the preceding emoji, as in grapheme segmentation
+	if end < len(runes) && runes[end] == zeroWidthJoiner {
+		end++
+	}
+
+	return end
+}
+
+// emojiElementEnd returns the index just past a single emoji element starting at
+// runes[start]: the base rune, or a regional indicator pair, followed by extenders.
+func emojiElementEnd(runes []rune, start int) int {
+	end := start + 1
+	if isRegionalIndicator(runes[start]) && end < len(runes) && isRegionalIndicator(runes[end]) {
+		end++
+	}
+
+	for end < len(runes) && isEmojiExtender(runes[end]) {
+		end++
+	}
+	return end
+}
+
+// keycapEnd returns the index just past a keycap sequence ([0-9#*] VS16? U+20E3)
+// starting at runes[start], or start if there is none.
+func keycapEnd(runes []rune, start int) int {
+	r := runes[start]
+	if !(r >= '0' && r <= '9') && r != '#' && r != '*' {
+		return start
+	}
+
+	next := start + 1
+	if next < len(runes) && runes[next] == variationSelector {
+		next++
+	}
+	if next < len(runes) && runes[next] == combiningKeycap {
+		return next + 1
+	}
+	return start
+}
+
+// isEmojiExtender reports whether r extends the preceding emoji without starting a new
+// one: skin tone modifiers, tag characters (subdivision flags) and combining marks,
+// which include variation selectors and the combining keycap.
+func isEmojiExtender(r rune) bool {
+	switch {
+	case r >= skinToneFirst && r <= skinToneLast:
+		return true
+	case r >= tagFirst && r <= tagLast:
+		return true
+	default:
+		return unicode.In(r, unicode.Mn, unicode.Me)
+	}
+}
+
+// isRegionalIndicator reports whether r is one of the letters used to build flags.
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicatorFirst && r <= regionalIndicatorLast
+}
+
+// isPictographic reports whether r can follow a zero width joiner in an emoji sequence.
+func isPictographic(r rune, ranges []types.UnicodeRange) bool {
+	return isUnicodeEmoji(r, ranges) || isUnicodeEmoji(r, pictographicRanges)
+}
+
+// sequenceComponent names the role of a non-base rune within an emoji sequence.
+func sequenceComponent(r rune) string {
+	switch {
+	case r >= skinToneFirst && r <= skinToneLast:
+		return "skin_tone"
+	case r == zeroWidthJoiner:
+		return "zwj"
+	case r == variationSelector || r == 0xFE0E:
+		return "variation_selector"
+	case r == combiningKeycap:
+		return "keycap"
+	case r >= tagFirst && r <= tagLast:
+		return "tag"
+	case isRegionalIndicator(r):
+		return "regional_indicator"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,176 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceCorpus lists multi-rune emojis that must each be detected as exactly one match.
+var sequenceCorpus = []struct {
+	name  string
+	emoji string
+}{
+	// Skin tone modifiers
+	{"thumbs up light", "👍🏻"},
+	{"thumbs up medium", "👍🏽"},
+	{"thumbs up dark", "👍🏿"},
+	{"waving hand medium dark", "👋🏾"},
+	{"person raising hand light", "🙋🏼"},
+
+	// Variation selectors
+	{"heart with VS16", "❤️"},
+	{"warning with VS16", "⚠️"},
+	{"check mark with VS16", "✔️"},
+	{"sun with VS16", "☀️"},
+	{"snowflake with text presentation", "\u2744\ufe0e"},
+
+	// ZWJ sequences
+	{"family man woman girl", "👨‍👩‍👧"},
+	{"family man woman girl boy", "👨‍👩‍👧‍👦"},
+	{"couple with heart", "👩‍❤️‍👨"},
+	{"kiss", "👩‍❤️‍💋‍👨"},
+	{"woman technologist", "👩‍💻"},
+	{"man firefighter", "👨‍🚒"},
+	{"health worker", "🧑‍⚕️"},
+	{"rainbow flag", "🏳️‍🌈"},
+	{"transgender flag", "🏳️‍⚧️"},
+	{"pirate flag", "🏴‍☠️"},
+	{"eye in speech bubble", "👁️‍🗨️"},
+	{"heart on fire", "❤️‍🔥"},
+	{"black cat", "🐈‍⬛"},
+	{"polar bear", "🐻‍❄️"},
+	{"face exhaling", "😮‍💨"},
+
+	// ZWJ sequences with skin tones
+	{"woman technologist medium", "👩🏽‍💻"},
+	{"man farmer dark", "👨🏿‍🌾"},
+	{"people holding hands mixed tones", "🧑🏻‍🤝‍🧑🏿"},
+	{"woman running light", "🏃🏻‍♀️"},
+
+	// Keycaps
+	{"keycap one", "1️⃣"},
+	{"keycap zero", "0️⃣"},
+	{"keycap hash", "#️⃣"},
+	{"keycap asterisk", "*️⃣"},
+	{"keycap without VS16", "5\u20e3"},
+
+	// Flags
+	{"flag US", "🇺🇸"},
+	{"flag GB", "🇬🇧"},
+	{"flag JP", "🇯🇵"},
+	{"flag EU", "🇪🇺"},
+	{"flag England", "🏴󠁧󠁢󠁥󠁮󠁧󠁿"},
+	{"flag Scotland", "🏴󠁧󠁢󠁳󠁣󠁴󠁿"},
+}
+
+func TestDetectEmojis_SequencesAreSingleMatches(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+
+	for _, tt := range sequenceCorpus {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "before " + tt.emoji + " after"
+			detection := DetectEmojis([]byte(content), patterns).Unwrap()
+
+			require.Len(t, detection.Emojis, 1, "expected one match for %q (%s)", tt.emoji, codepoints(tt.emoji))
+			match := detection.Emojis[0]
+			assert.Equal(t, tt.emoji, match.Emoji)
+			assert.Equal(t, len("before "), match.Start)
+			assert.Equal(t, len("before ")+len(tt.emoji), match.End)
+			assert.Equal(t, 8, match.Column)
+			assert.Equal(t, 1, detection.UniqueCount)
+		})
+	}
+}
+
+func TestDetectEmojis_AdjacentSequences(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"adjacent flags pair from the start", "🇺🇸🇬🇧🇯🇵", []string{"🇺🇸", "🇬🇧", "🇯🇵"}},
+		{"odd regional indicator stands alone", "🇺🇸🇬", []string{"🇺🇸", "🇬"}},
+		{"adjacent families", "👨‍👩‍👧👨‍👩‍👧", []string{"👨‍👩‍👧", "👨‍👩‍👧"}},
+		{"skin tones on adjacent emojis", "👍🏽👎🏿", []string{"👍🏽", "👎🏿"}},
+		{"keycaps in a row", "1️⃣2️⃣3️⃣", []string{"1️⃣", "2️⃣", "3️⃣"}},
+		{"trailing joiner stays with the emoji", "👩‍ text", []string{"👩‍"}},
+		{"joiner before text does not absorb it", "👩‍a", []string{"👩‍"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := DetectEmojis([]byte(tt.content), patterns).Unwrap()
+
+			emojis := make([]string, len(detection.Emojis))
+			for i, match := range detection.Emojis {
+				emojis[i] = match.Emoji
+			}
+			assert.Equal(t, tt.expected, emojis)
+
+			// Matches tile the emoji bytes without gaps or overlaps
+			for i := 1; i < len(detection.Emojis); i++ {
+				assert.Equal(t, detection.Emojis[i-1].End, detection.Emojis[i].Start)
+			}
+		})
+	}
+}
+
+func TestDetectEmojis_PlainTextIsNotASequence(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+	// A lone joiner is an evasion finding when evasion detection is on
+	patterns.EvasionRanges = nil
+	patterns.HygieneRanges = nil
+
+	for _, content := range []string{
+		"1 + 2 = 3",
+		"# heading",
+		"a * b",
+		"cafe\u0301",   // combining mark on a letter
+		"naïve résumé", // precomposed letters
+		"x\u200dy",     // joiner between letters
+		"\u200d",       // lone joiner
+	} {
+		detection := DetectEmojis([]byte(content), patterns).Unwrap()
+		assert.Empty(t, detection.Emojis, "unexpected match in %q", content)
+	}
+}
+
+func TestDetectEmojis_SequencesRespectAllowlist(t *testing.T) {
+	patterns := DefaultEmojiPatterns()
+	emojiAllowlist := allowlist.NewAllowlist([]string{"👨‍👩‍👧", "🇺🇸", "1️⃣"}).Unwrap()
+
+	content := "👨‍👩‍👧 🇺🇸 1️⃣ 👨 🇬🇧"
+	detection := DetectEmojis([]byte(content), patterns).Unwrap()
+
+	var blocked []string
+	for _, match := range detection.Emojis {
+		if !emojiAllowlist.IsAllowed(match.Emoji) {
+			blocked = append(blocked, match.Emoji)
+		}
+	}
+	assert.Equal(t, []string{"👨", "🇬🇧"}, blocked)
+}
+
+func TestDetectEmojis_SequenceDebugInfo(t *testing.T) {
+	detection := DetectEmojis([]byte("👩🏽‍💻"), DefaultEmojiPatterns()).Unwrap()
+	require.Len(t, detection.Emojis, 1)
+
+	debugInfo := detection.Emojis[0].DebugInfo
+	assert.Equal(t, 4, debugInfo["rune_count"])
+	assert.Equal(t, []string{"skin_tone", "zwj", "other"}, debugInfo["modifiers"])
+}
+
+func codepoints(s string) string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		parts = append(parts, fmt.Sprintf("%U", r))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,52 @@
+package detector
+
+import (
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// detectShortcodes detects the :name: aliases of the emojis in shortcodes. A shortcode
+// must not be glued to a letter or digit on either side; shortcodes that are also
+// custom patterns are left to custom pattern detection.
+func detectShortcodes(content string, positions *lineIndex, shortcodes map[string]string, custom []string, result types.DetectionResult) (types.DetectionResult, int) {
+	if len(shortcodes) == 0 {
+		return result, 0
+	}
+	customPatterns := make(map[string]bool, len(custom))
+	for _, pattern := range custom {
+		customPatterns[pattern] = true
+	}
+
+	for start := 0; ; {
+		open := strings.IndexByte(content[start:], ':')
+		if open == -1 {
+			break
+		}
+		open += start
+		end := strings.IndexByte(content[open+1:], ':')
+		if end == -1 {
+			break
+		}
+		end += open + 2
+
+		if _, ok := shortcodes[content[open+1:end-1]]; ok && !customPatterns[content[open:end]] &&
+			(open == 0 || !isAlphanumeric(rune(content[open-1]))) &&
+			(end == len(content) || !isAlphanumeric(rune(content[end]))) {
+			line, column := positions.position(open)
+			result.AddEmoji(types.EmojiMatch{
+				Emoji:    content[open:end],
+				Start:    open,
+				End:      end,
+				Line:     line,
+				Column:   column,
+				Category: types.CategoryShortcode,
+			})
+			start = end
+			continue
+		}
+		// The closing colon may open the next shortcode, as in "Done :) :rocket:"
+		start = end - 1
+	}
+	return result, 1
+}
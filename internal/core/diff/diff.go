@@ -0,0 +1,246 @@
+// Package diff provides unified diff generation for proposed file modifications.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines shown around each change.
+const DefaultContext = 3
+
+// opKind identifies a line-level edit operation.
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// lineOp is a single line-level edit.
+type lineOp struct {
+	kind    opKind
+	oldLine int // index into old lines (equal/delete)
+	newLine int // index into new lines (equal/insert)
+}
+
+// Unified returns a unified diff between oldText and newText in the format accepted by
+// `git apply` and `patch`. An empty string is returned when the texts are identical.
+func Unified(oldName, newName, oldText, newText string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+	if context < 0 {
+		context = DefaultContext
+	}
+
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+	ops := computeOps(oldLines, newLines)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	for _, hunk := range groupHunks(ops, context) {
+		writeHunk(&b, hunk, oldLines, newLines)
+	}
+
+	return b.String()
+}
+
+// splitLines splits text into lines keeping their terminators, so that a missing
+// final newline can be reported.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// computeOps computes a shortest edit script. Common leading and trailing lines are
+// matched directly so that Myers' O(ND) search only runs over the changed region.
+func computeOps(a, b []string) []lineOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]lineOp, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, lineOp{kind: opEqual, oldLine: i, newLine: i})
+	}
+	for _, op := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		op.oldLine += prefix
+		op.newLine += prefix
+		ops = append(ops, op)
+	}
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, lineOp{kind: opEqual, oldLine: len(a) - suffix + i, newLine: len(b) - suffix + i})
+	}
+
+	return ops
+}
+
+// myers computes a shortest edit script using Myers' O(ND) algorithm.
+func myers(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset, d)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack walks the Myers trace backwards to produce the edit script.
+func backtrack(trace [][]int, a, b []string, offset, depth int) []lineOp {
+	x, y := len(a), len(b)
+	var ops []lineOp
+
+	for d := depth; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, lineOp{kind: opEqual, oldLine: x, newLine: y})
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, lineOp{kind: opInsert, oldLine: x, newLine: y})
+		} else {
+			x--
+			ops = append(ops, lineOp{kind: opDelete, oldLine: x, newLine: y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, lineOp{kind: opEqual, oldLine: x, newLine: y})
+	}
+
+	// Reverse into forward order
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// groupHunks splits the edit script into hunks with the requested context.
+func groupHunks(ops []lineOp, context int) [][]lineOp {
+	var hunks [][]lineOp
+	start := -1
+	lastChange := -1
+
+	for i, op := range ops {
+		if op.kind == opEqual {
+			continue
+		}
+		if start >= 0 && i-lastChange > 2*context {
+			hunks = append(hunks, ops[start:min(lastChange+context+1, len(ops))])
+			start = -1
+		}
+		if start < 0 {
+			start = max(i-context, 0)
+		}
+		lastChange = i
+	}
+	if start >= 0 {
+		hunks = append(hunks, ops[start:min(lastChange+context+1, len(ops))])
+	}
+
+	return hunks
+}
+
+// writeHunk writes a single hunk including its header.
+func writeHunk(b *strings.Builder, hunk []lineOp, oldLines, newLines []string) {
+	oldStart, newStart := hunk[0].oldLine, hunk[0].newLine
+	oldCount, newCount := 0, 0
+	for _, op := range hunk {
+		switch op.kind {
+		case opEqual:
+			oldCount++
+			newCount++
+		case opDelete:
+			oldCount++
+		case opInsert:
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+
+	for _, op := range hunk {
+		switch op.kind {
+		case opEqual:
+			writeLine(b, ' ', oldLines[op.oldLine])
+		case opDelete:
+			writeLine(b, '-', oldLines[op.oldLine])
+		case opInsert:
+			writeLine(b, '+', newLines[op.newLine])
+		}
+	}
+}
+
+// hunkRange formats a hunk range; empty ranges point at the line before the change.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// writeLine writes one diff line, marking a missing trailing newline.
+func writeLine(b *strings.Builder, prefix byte, line string) {
+	b.WriteByte(prefix)
+	b.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		b.WriteString("\n\\ No newline at end of file\n")
+	}
+}
@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "identical texts produce no diff",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			name: "single changed line",
+			old:  "one\ntwo 🚀\nthree\n",
+			new:  "one\ntwo \nthree\n",
+			expected: "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n" +
+				" one\n-two 🚀\n+two \n three\n",
+		},
+		{
+			name: "missing final newline is marked",
+			old:  "x 😀",
+			new:  "x ",
+			expected: "--- a/f\n+++ b/f\n@@ -1 +1 @@\n" +
+				"-x 😀\n\\ No newline at end of file\n+x \n\\ No newline at end of file\n",
+		},
+		{
+			name: "distant changes use separate hunks",
+			old:  "1🚀\n2\n3\n4\n5\n6\n7\n8\n9\n10🚀\n",
+			new:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			expected: "--- a/f\n+++ b/f\n" +
+				"@@ -1,4 +1,4 @@\n-1🚀\n+1\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10🚀\n+10\n",
+		},
+		{
+			name: "inserted and deleted lines",
+			old:  "a\nb\nc\n",
+			new:  "a\nc\nd\n",
+			expected: "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n" +
+				" a\n-b\n c\n+d\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Unified("a/f", "b/f", tt.old, tt.new, DefaultContext))
+		})
+	}
+}
+
+func TestUnified_AppliesWithGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	old := strings.Repeat("line\n", 20) + "emoji 🎉 here\n" + strings.Repeat("tail\n", 5) + "end ✨"
+	updated := strings.Repeat("line\n", 20) + "emoji  here\n" + strings.Repeat("tail\n", 5) + "end "
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(old), 0644))
+
+	patch := Unified("a/file.txt", "b/file.txt", old, updated, DefaultContext)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "change.patch"), []byte(patch), 0644))
+
+	cmd := exec.Command("git", "apply", "change.patch")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	applied, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, updated, string(applied))
+}
@@ -0,0 +1,134 @@
+package processor
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+// maxDocumentPartSize bounds the uncompressed size of a single document part, so a
+// crafted archive cannot exhaust memory.
+const maxDocumentPartSize = 32 * 1024 * 1024 // 32MB
+
+// DocxExtractor returns the extractor for Office Open XML documents (.docx). It reads
+// the body, headers, footers, footnotes, endnotes and comments.
+func DocxExtractor() ContentExtractor {
+	return &xmlDocumentExtractor{
+		parts: []string{
+			"word/document.xml", "word/header*.xml", "word/footer*.xml",
+			"word/footnotes.xml", "word/endnotes.xml", "word/comments.xml",
+		},
+		text:       map[string]bool{"t": true},
+		paragraphs: map[string]bool{"p": true},
+		tabs:       map[string]bool{"tab": true},
+		breaks:     map[string]bool{"br": true, "cr": true},
+	}
+}
+
+// ODTExtractor returns the extractor for OpenDocument text documents (.odt).
+func ODTExtractor() ContentExtractor {
+	return &xmlDocumentExtractor{
+		parts:      []string{"content.xml", "styles.xml"},
+		text:       map[string]bool{"p": true, "h": true},
+		paragraphs: map[string]bool{"p": true, "h": true},
+		tabs:       map[string]bool{"tab": true},
+		breaks:     map[string]bool{"line-break": true},
+		spaces:     map[string]bool{"s": true},
+	}
+}
+
+// xmlDocumentExtractor extracts text from zipped XML document formats. Element names
+// are matched without their namespace.
+type xmlDocumentExtractor struct {
+	// parts are the archive members to read, as path.Match patterns, in output order
+	parts []string
+	// text are the elements whose character data is document text
+	text map[string]bool
+	// paragraphs end with a newline
+	paragraphs map[string]bool
+	// tabs, breaks and spaces are empty elements standing for whitespace
+	tabs, breaks, spaces map[string]bool
+}
+
+// Extract returns the text of the document, one line per paragraph.
+func (e *xmlDocumentExtractor) Extract(content []byte) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document archive: %w", err)
+	}
+
+	var out bytes.Buffer
+	found := false
+	for _, pattern := range e.parts {
+		for _, file := range archive.File {
+			if matched, _ := path.Match(pattern, file.Name); !matched {
+				continue
+			}
+			found = true
+			if err := e.extractPart(file, &out); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+			}
+		}
+	}
+	if !found {
+		return nil, errors.New("not a supported document: no text parts found")
+	}
+
+	return out.Bytes(), nil
+}
+
+// extractPart appends the text of one XML part to out.
+func (e *xmlDocumentExtractor) extractPart(file *zip.File, out *bytes.Buffer) error {
+	if file.UncompressedSize64 > maxDocumentPartSize {
+		return errors.New("document part too large")
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxDocumentPartSize))
+	inText := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			name := element.Name.Local
+			switch {
+			case e.text[name]:
+				inText++
+			case e.tabs[name]:
+				out.WriteByte('\t')
+			case e.breaks[name]:
+				out.WriteByte('\n')
+			case e.spaces[name]:
+				out.WriteByte(' ')
+			}
+		case xml.EndElement:
+			name := element.Name.Local
+			if e.text[name] && inText > 0 {
+				inText--
+			}
+			if e.paragraphs[name] {
+				out.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText > 0 {
+				out.Write(element)
+			}
+		}
+	}
+}
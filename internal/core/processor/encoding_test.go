@@ -0,0 +1,131 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// utf16LE encodes s as UTF-16LE with a byte order mark.
+func utf16LE(s string) []byte {
+	out := []byte{0xFF, 0xFE}
+	for _, unit := range utf16.Encode([]rune(s)) {
+		out = append(out, byte(unit), byte(unit>>8))
+	}
+	return out
+}
+
+func TestProcessFile_Encodings(t *testing.T) {
+	tmpDir := t.TempDir()
+	patterns := detector.DefaultEmojiPatterns()
+
+	t.Run("utf-16 offsets refer to the file", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "utf16.txt")
+		require.NoError(t, os.WriteFile(path, utf16LE("hi 🚀\n"), 0644))
+
+		result := ProcessFile(path, patterns, types.DefaultProcessingConfig()).Unwrap()
+		require.NoError(t, result.Error)
+		assert.Equal(t, "utf-16le", result.Encoding)
+		require.Equal(t, 1, result.DetectionResult.TotalCount)
+
+		match := result.DetectionResult.Emojis[0]
+		assert.Equal(t, "🚀", match.Emoji)
+		assert.Equal(t, 8, match.Start)
+		assert.Equal(t, 12, match.End)
+	})
+
+	t.Run("utf-8 byte order mark is not counted in columns", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "bom.txt")
+		require.NoError(t, os.WriteFile(path, []byte("\xef\xbb\xbf🚀 hi\n"), 0644))
+
+		result := ProcessFile(path, patterns, types.DefaultProcessingConfig()).Unwrap()
+		require.NoError(t, result.Error)
+		require.Equal(t, 1, result.DetectionResult.TotalCount)
+
+		match := result.DetectionResult.Emojis[0]
+		assert.Equal(t, 1, match.Column)
+		assert.Equal(t, 3, match.Start, "offsets still refer to the file")
+	})
+
+	t.Run("latin-1 is not binary", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "latin1.txt")
+		require.NoError(t, os.WriteFile(path, []byte("caf\xe9 :)\n"), 0644))
+
+		result := ProcessFile(path, patterns, types.DefaultProcessingConfig()).Unwrap()
+		assert.Equal(t, "iso-8859-1", result.Encoding)
+		require.Equal(t, 1, result.DetectionResult.TotalCount)
+		assert.Equal(t, 5, result.DetectionResult.Emojis[0].Start)
+	})
+}
+
+func TestModifyFile_Encodings(t *testing.T) {
+	tmpDir := t.TempDir()
+	patterns := detector.DefaultEmojiPatterns()
+
+	t.Run("utf-16 is written back as utf-16", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "utf16.txt")
+		require.NoError(t, os.WriteFile(path, utf16LE("hi 🚀 there\n"), 0644))
+
+		result := ModifyFile(path, patterns, DefaultModifyConfig(), nil).Unwrap()
+		require.NoError(t, result.Error)
+		assert.True(t, result.Modified)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, utf16LE("hi  there\n"), content)
+	})
+
+	t.Run("latin-1 rejects replacements it cannot encode", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "latin1.txt")
+		original := []byte("caf\xe9 :)\n")
+		require.NoError(t, os.WriteFile(path, original, 0644))
+
+		config := DefaultModifyConfig()
+		config.Replacement = "✓"
+		result := ModifyFile(path, patterns, config, nil).Unwrap()
+		assert.ErrorContains(t, result.Error, "failed to encode file")
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, original, content)
+	})
+
+	t.Run("utf-8 byte order mark is kept", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "bom.txt")
+		require.NoError(t, os.WriteFile(path, []byte("\xef\xbb\xbfhi 🚀\n"), 0644))
+
+		config := DefaultModifyConfig()
+		config.GenerateDiff = true
+		result := ModifyFile(path, patterns, config, nil).Unwrap()
+		require.NoError(t, result.Error)
+		assert.False(t, result.BOMStripped)
+		assert.Contains(t, result.Diff, "+\ufeffhi \n")
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("\xef\xbb\xbfhi \n"), content)
+	})
+
+	t.Run("strip bom removes it from files without emojis", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "plain-bom.txt")
+		require.NoError(t, os.WriteFile(path, []byte("\xef\xbb\xbfplain\n"), 0644))
+
+		config := DefaultModifyConfig()
+		config.StripBOM = true
+		result := ModifyFile(path, patterns, config, nil).Unwrap()
+		require.NoError(t, result.Error)
+		assert.True(t, result.Modified)
+		assert.True(t, result.BOMStripped)
+		assert.Zero(t, result.EmojisRemoved)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("plain\n"), content)
+	})
+}
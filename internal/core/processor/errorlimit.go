@@ -0,0 +1,38 @@
+package processor
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrTooManyErrors is the error of the files a batch did not process because it had
+// reached its error limit.
+var ErrTooManyErrors = errors.New("not processed: too many errors")
+
+// errorLimit counts the failed files of a batch until a maximum is reached. It is safe
+// for concurrent use; a nil limit is never reached.
+type errorLimit struct {
+	max    int64
+	failed atomic.Int64
+}
+
+// newErrorLimit returns a limit of max failed files, or nil when max is zero or less.
+func newErrorLimit(max int) *errorLimit {
+	if max <= 0 {
+		return nil
+	}
+	return &errorLimit{max: int64(max)}
+}
+
+// record counts err if it means the file failed. Files skipped for their size are not
+// failures.
+func (l *errorLimit) record(err error) {
+	if l != nil && err != nil && !errors.Is(err, ErrFileTooLarge) && !errors.Is(err, ErrTooManyErrors) {
+		l.failed.Add(1)
+	}
+}
+
+// reached reports whether no further files should be processed.
+func (l *errorLimit) reached() bool {
+	return l != nil && l.failed.Load() >= l.max
+}
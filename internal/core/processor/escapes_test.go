@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessFile_Escapes(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"status": "\u2705 done", "icon": "\uD83D\uDE80"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.json"), []byte(content), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.txt"), []byte(content), 0644))
+
+	config := types.DefaultProcessingConfig()
+	config.EnableEscapes = true
+	cache := &mapCache{entries: map[string]types.DetectionResult{}}
+	for _, run := range []string{"first", "cached"} {
+		json := ProcessFileWithCache(filepath.Join(dir, "data.json"), detector.DefaultEmojiPatterns(), config, cache).Unwrap()
+		require.NoError(t, json.Error)
+		require.Len(t, json.DetectionResult.Emojis, 2, run)
+		assert.Equal(t, "✅", json.DetectionResult.Emojis[0].Emoji)
+		assert.Equal(t, "🚀", json.DetectionResult.Emojis[1].Emoji)
+		assert.Equal(t, types.CategoryEscaped, json.DetectionResult.Emojis[1].Category)
+
+		text := ProcessFileWithCache(filepath.Join(dir, "data.txt"), detector.DefaultEmojiPatterns(), config, cache).Unwrap()
+		assert.Zero(t, text.DetectionResult.TotalCount, run)
+	}
+	assert.Equal(t, 2, cache.hits)
+
+	config.EnableEscapes = false
+	disabled := ProcessFile(filepath.Join(dir, "data.json"), detector.DefaultEmojiPatterns(), config).Unwrap()
+	assert.Zero(t, disabled.DetectionResult.TotalCount)
+}
+
+func TestModifyFile_DecodeEscapes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.html")
+	require.NoError(t, os.WriteFile(path, []byte("<p>&#x1F680; Launch &#10004; &amp; go</p>\n"), 0644))
+
+	config := DefaultModifyConfig()
+	config.DecodeEscapes = true
+	config.ReplacementMap = map[string]string{"🚀": "[launch]"}
+	result := ModifyFile(path, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+	require.NoError(t, result.Error)
+	assert.Equal(t, 2, result.EmojisRemoved)
+
+	cleaned, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>[launch] Launch  &amp; go</p>\n", string(cleaned))
+}
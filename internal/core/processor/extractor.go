@@ -0,0 +1,62 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ContentExtractor turns a document that is not plain text, such as a word processor
+// file, into the text antimoji scans. Extracted documents are scanned but never
+// cleaned, and match positions refer to the extracted text.
+type ContentExtractor interface {
+	Extract(content []byte) ([]byte, error)
+}
+
+// ExtractorFunc adapts a function to the ContentExtractor interface.
+type ExtractorFunc func(content []byte) ([]byte, error)
+
+// Extract calls f(content).
+func (f ExtractorFunc) Extract(content []byte) ([]byte, error) {
+	return f(content)
+}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = map[string]ContentExtractor{
+		".docx": DocxExtractor(),
+		".odt":  ODTExtractor(),
+	}
+)
+
+// RegisterExtractor registers extractor for files with extension ext (e.g. ".pdf"),
+// replacing any extractor already registered for it. A nil extractor removes the
+// registration so the files are read as text again.
+func RegisterExtractor(ext string, extractor ContentExtractor) {
+	ext = normalizeExtension(ext)
+
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	if extractor == nil {
+		delete(extractors, ext)
+		return
+	}
+	extractors[ext] = extractor
+}
+
+// ExtractorFor returns the extractor registered for the extension of filePath.
+func ExtractorFor(filePath string) (ContentExtractor, bool) {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	extractor, ok := extractors[normalizeExtension(filepath.Ext(filePath))]
+	return extractor, ok
+}
+
+// normalizeExtension lowercases ext and adds the leading dot if missing.
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
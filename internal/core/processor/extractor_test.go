@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildArchive zips parts into an in-memory document.
+func buildArchive(t *testing.T, parts map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range parts {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+const testDocumentXML = `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:body>
+    <w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Release 🚀</w:t></w:r></w:p>
+    <w:p><w:r><w:t xml:space="preserve">Done </w:t></w:r><w:r><w:tab/><w:t>✅</w:t></w:r></w:p>
+  </w:body>
+</w:document>`
+
+func TestDocxExtractor(t *testing.T) {
+	docx := buildArchive(t, map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml":   testDocumentXML,
+		"word/footer1.xml":    `<w:ftr xmlns:w="x"><w:p><w:r><w:t>Footer :)</w:t></w:r></w:p></w:ftr>`,
+	})
+
+	text, err := DocxExtractor().Extract(docx)
+	require.NoError(t, err)
+	assert.Equal(t, "Release 🚀\nDone \t✅\nFooter :)\n", string(text))
+
+	t.Run("rejects non-documents", func(t *testing.T) {
+		_, err := DocxExtractor().Extract([]byte("plain text"))
+		assert.ErrorContains(t, err, "failed to open document archive")
+
+		_, err = DocxExtractor().Extract(buildArchive(t, map[string]string{"other.xml": "<x/>"}))
+		assert.ErrorContains(t, err, "no text parts found")
+	})
+}
+
+func TestODTExtractor(t *testing.T) {
+	odt := buildArchive(t, map[string]string{
+		"mimetype": "application/vnd.oasis.opendocument.text",
+		"content.xml": `<office:document-content xmlns:office="o" xmlns:text="t"><office:body><office:text>
+<text:h>Notes 🎉</text:h><text:p>One<text:s/>two<text:line-break/><text:span>three</text:span></text:p>
+</office:text></office:body></office:document-content>`,
+	})
+
+	text, err := ODTExtractor().Extract(odt)
+	require.NoError(t, err)
+	assert.Equal(t, "Notes 🎉\nOne two\nthree\n", string(text))
+}
+
+func TestRegisterExtractor(t *testing.T) {
+	upper := ExtractorFunc(func(content []byte) ([]byte, error) {
+		return []byte(strings.ToUpper(string(content))), nil
+	})
+	RegisterExtractor("TXT2", upper)
+	defer RegisterExtractor(".txt2", nil)
+
+	extractor, ok := ExtractorFor("notes.TxT2")
+	require.True(t, ok)
+	text, err := extractor.Extract([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, "ABC", string(text))
+
+	_, ok = ExtractorFor("main.go")
+	assert.False(t, ok)
+}
+
+func TestProcessFile_Documents(t *testing.T) {
+	tmpDir := t.TempDir()
+	patterns := detector.DefaultEmojiPatterns()
+	config := types.DefaultProcessingConfig()
+
+	t.Run("scans docx text", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "report.docx")
+		require.NoError(t, os.WriteFile(path, buildArchive(t, map[string]string{"word/document.xml": testDocumentXML}), 0644))
+
+		result := ProcessFile(path, patterns, config).Unwrap()
+		require.NoError(t, result.Error)
+		assert.Equal(t, 2, result.DetectionResult.TotalCount)
+		assert.Equal(t, "🚀", result.DetectionResult.Emojis[0].Emoji)
+		assert.Equal(t, 1, result.DetectionResult.Emojis[0].Line)
+	})
+
+	t.Run("reports extraction errors", func(t *testing.T) {
+		RegisterExtractor(".broken", ExtractorFunc(func([]byte) ([]byte, error) {
+			return nil, errors.New("unsupported encryption")
+		}))
+		defer RegisterExtractor(".broken", nil)
+
+		path := filepath.Join(tmpDir, "secret.broken")
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0644))
+
+		result := ProcessFile(path, patterns, config).Unwrap()
+		assert.ErrorContains(t, result.Error, "failed to extract text: unsupported encryption")
+	})
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/infra/fs"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// CleanBytes removes emojis from content in memory, as ModifyFile removes them from a
+// file, and returns the cleaned content. filePath only selects the language of the
+// content and may be empty. Backups, diffs, Decide and writing are not supported.
+//
+// Content that is not text anywhere, rather than only in its first bytes, is returned
+// unchanged with BinaryReason set, as is content whose cleaned text cannot be encoded
+// back; the error of the latter is in the result. The returned slice may be content
+// itself.
+func CleanBytes(filePath string, content []byte, patterns types.EmojiPatterns, config ModifyConfig,
+	emojiAllowlist *allowlist.Allowlist) ([]byte, ModifyResult) {
+
+	result := ModifyResult{FilePath: filePath}
+	if encoding, reason := fs.Sniff(content, config.Sniff); encoding == fs.EncodingBinary {
+		result.Success = true
+		result.BinaryReason = reason
+		return content, result
+	}
+	decoded, err := fs.Decode(content, config.Sniff)
+	if err != nil {
+		result.Error = err
+		return content, result
+	}
+	if config.StripBOM {
+		decoded = decoded.StripBOM()
+	}
+	original := string(decoded.Text)
+
+	language := languageOf(config.Languages, filePath, decoded.Text)
+	patterns = withLanguage(patterns, language, config.DecodeEscapes)
+	exempt := exemptionFor(language, config.PreserveMarkdownCode, config.Structured)
+	text, normalized := normalizeShortcodes(original, patterns, config.NormalizeShortcodes, exempt)
+
+	var keep *allowlist.Allowlist
+	if config.RespectAllowlist {
+		keep = emojiAllowlist
+	}
+	cleaned, removed := removeUntilStable(text, nil, patterns, config.ReplacementFor, keep, config.violationsIn(filePath), exempt)
+	if removed == 0 && normalized == 0 && !decoded.BOMStripped() {
+		result.Success = true
+		return content, result
+	}
+
+	encoded, err := decoded.Encode([]byte(keepLineEndings(original, cleaned)))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to encode content: %w", err)
+		return content, result
+	}
+	result.Success = true
+	result.Modified = true
+	result.EmojisRemoved = removed
+	result.EmojisNormalized = normalized
+	result.BOMStripped = decoded.BOMStripped()
+	return encoded, result
+}
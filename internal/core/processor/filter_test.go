@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanBytes(t *testing.T) {
+	patterns := detector.DefaultEmojiPatterns()
+
+	t.Run("removes emojis", func(t *testing.T) {
+		cleaned, result := CleanBytes("main.go", []byte("// Launch 🚀\r\nfunc main() {}\r\n"), patterns, DefaultModifyConfig(), nil)
+		assert.Equal(t, "// Launch \r\nfunc main() {}\r\n", string(cleaned))
+		assert.True(t, result.Success)
+		assert.True(t, result.Modified)
+		assert.Equal(t, 1, result.EmojisRemoved)
+	})
+
+	t.Run("keeps allowed emojis", func(t *testing.T) {
+		emojiAllowlist := allowlist.NewAllowlist([]string{"✅"}).Unwrap()
+		cleaned, result := CleanBytes("", []byte("done ✅ 🎉"), patterns, DefaultModifyConfig(), emojiAllowlist)
+		assert.Equal(t, "done ✅ ", string(cleaned))
+		assert.Equal(t, 1, result.EmojisRemoved)
+	})
+
+	t.Run("returns clean content itself", func(t *testing.T) {
+		content := []byte("package main\n")
+		cleaned, result := CleanBytes("main.go", content, patterns, DefaultModifyConfig(), nil)
+		assert.Same(t, &content[0], &cleaned[0])
+		assert.False(t, result.Modified)
+	})
+
+	t.Run("passes binary content through", func(t *testing.T) {
+		// Text at the start, but binary further on
+		content := append([]byte("// Launch 🚀\n"), make([]byte, 4096)...)
+		content = append(content, "🎉"...)
+		cleaned, result := CleanBytes("", content, patterns, DefaultModifyConfig(), nil)
+		assert.Equal(t, content, cleaned)
+		assert.NotEmpty(t, result.BinaryReason)
+		assert.False(t, result.Modified)
+	})
+
+	t.Run("keeps the encoding", func(t *testing.T) {
+		utf16 := []byte{0xFF, 0xFE, 'h', 0, 'i', 0, 0x3D, 0xD8, 0x80, 0xDE, '\n', 0}
+		cleaned, result := CleanBytes("", utf16, patterns, DefaultModifyConfig(), nil)
+		assert.Equal(t, []byte{0xFF, 0xFE, 'h', 0, 'i', 0, '\n', 0}, cleaned)
+		assert.Equal(t, 1, result.EmojisRemoved)
+	})
+}
@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/lang"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// builtinLanguages identifies files when the configuration sets no Languages.
+var builtinLanguages = lang.Default()
+
+// languageOf returns the language of the file at filePath with content, or the zero
+// Language for files of unknown languages.
+func languageOf(languages types.LanguageLookup, filePath string, content []byte) types.Language {
+	if languages == nil {
+		languages = builtinLanguages
+	}
+	language, _ := languages.Lookup(filePath, content)
+	return language
+}
+
+// withLanguage sets the per-file patterns that depend on the language of the file.
+func withLanguage(patterns types.EmojiPatterns, language types.Language, escapes bool) types.EmojiPatterns {
+	if escapes {
+		patterns.Escapes = language.Escapes
+	}
+	patterns.Comments = language.Comments
+	patterns.StringDelimiters = language.StringDelimiters
+	return patterns
+}
+
+// languageCacheKey returns the suffix of a content hash for the parts of detection that
+// depend on the file's language, so that the same content in other files is detected anew.
+func languageCacheKey(content []byte, patterns types.EmojiPatterns, exempt exemption) string {
+	var key string
+	if exempt.key != "" {
+		// Markdown code or parts of structured files are left out
+		key += ":" + exempt.key
+	}
+	if patterns.Escapes != 0 {
+		key += fmt.Sprintf(":escapes=%d", patterns.Escapes)
+	}
+	if !patterns.Comments.IsZero() && (patterns.MarkComments || bytes.Contains(content, []byte(detector.SuppressOffMarker))) {
+		// Suppression markers only count inside comments, and matches are marked with
+		// whether they are in one
+		key += fmt.Sprintf(":comments=%q%q", patterns.Comments, patterns.StringDelimiters)
+	}
+	return key
+}
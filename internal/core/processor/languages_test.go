@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/lang"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessFile_Languages(t *testing.T) {
+	dir := t.TempDir()
+	// The marker in the string only counts where the language has no comments
+	content := "msg = \"antimoji:off\"\nstatus = \"🚀\"\n"
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	python := write("tool.py", content)
+	text := write("notes.txt", content)
+	script := write("deploy", "#!/usr/bin/env python3\n"+content)
+	terraform := write("main.tf", content)
+
+	config := types.DefaultProcessingConfig()
+	cache := &mapCache{entries: map[string]types.DetectionResult{}}
+	count := func(path string) int {
+		result := ProcessFileWithCache(path, detector.DefaultEmojiPatterns(), config, cache).Unwrap()
+		require.NoError(t, result.Error)
+		return result.DetectionResult.TotalCount
+	}
+
+	for _, run := range []string{"first", "cached"} {
+		assert.Equal(t, 1, count(python), run)
+		assert.Equal(t, 0, count(text), run)
+		assert.Equal(t, 1, count(script), run)
+		assert.Equal(t, 0, count(terraform), run)
+	}
+
+	// Languages from the configuration are known too
+	config.Languages = lang.Default().With(types.Language{
+		Name: "hcl", Extensions: []string{".tf"},
+		Comments: types.CommentSyntax{Line: []string{"#"}}, StringDelimiters: []string{`"`},
+	})
+	assert.Equal(t, 1, count(terraform))
+}
+
+func TestModifyFile_Languages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guide.adoc")
+	require.NoError(t, os.WriteFile(path, []byte("Ship 🚀 with `deploy 🚀`\n"), 0644))
+
+	config := DefaultModifyConfig()
+	config.PreserveMarkdownCode = true
+	config.Languages = lang.Default().With(types.Language{Name: "asciidoc", Extensions: []string{".adoc"}, CodeFences: true})
+	result := ModifyFile(path, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+	require.NoError(t, result.Error)
+
+	cleaned, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Ship  with `deploy 🚀`\n", string(cleaned))
+}
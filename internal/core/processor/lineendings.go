@@ -0,0 +1,66 @@
+package processor
+
+import "strings"
+
+// lineEnding is the line ending style of a text.
+type lineEnding string
+
+const (
+	// lineEndingNone is the style of a text without line breaks
+	lineEndingNone lineEnding = "none"
+	// lineEndingLF is the style of a text whose lines all end with \n
+	lineEndingLF lineEnding = "lf"
+	// lineEndingCRLF is the style of a text whose lines all end with \r\n
+	lineEndingCRLF lineEnding = "crlf"
+	// lineEndingMixed is the style of a text mixing \n and \r\n, or with lone \r
+	lineEndingMixed lineEnding = "mixed"
+)
+
+// detectLineEnding returns the line ending style of text and whether it ends with a
+// line break.
+func detectLineEnding(text string) (lineEnding, bool) {
+	crlf := strings.Count(text, "\r\n")
+	lf := strings.Count(text, "\n") - crlf
+	cr := strings.Count(text, "\r") - crlf
+	final := strings.HasSuffix(text, "\n")
+
+	switch {
+	case cr > 0 || (crlf > 0 && lf > 0):
+		return lineEndingMixed, final
+	case crlf > 0:
+		return lineEndingCRLF, final
+	case lf > 0:
+		return lineEndingLF, final
+	default:
+		return lineEndingNone, final
+	}
+}
+
+// keepLineEndings gives cleaned, the cleaned version of original, the line ending style
+// and final line break of original. Removing emojis never touches line breaks, but
+// replacements spanning lines or custom patterns matching them can: line breaks they
+// add follow the file's style, and the file keeps ending with a line break or not.
+// Mixed files keep the line breaks as cleaned, since no style can be told.
+func keepLineEndings(original, cleaned string) string {
+	style, final := detectLineEnding(original)
+	switch style {
+	case lineEndingCRLF:
+		cleaned = strings.ReplaceAll(strings.ReplaceAll(cleaned, "\r\n", "\n"), "\n", "\r\n")
+	case lineEndingLF:
+		cleaned = strings.ReplaceAll(cleaned, "\r\n", "\n")
+	}
+
+	// Removing an emoji after the last line break makes the file end with one, which
+	// is not a line break added by cleaning
+	breaks, cleanedBreaks := strings.Count(original, "\n"), strings.Count(cleaned, "\n")
+	switch {
+	case final && cleanedBreaks < breaks && !strings.HasSuffix(cleaned, "\n"):
+		if strings.HasSuffix(original, "\r\n") {
+			return cleaned + "\r\n"
+		}
+		return cleaned + "\n"
+	case !final && cleanedBreaks > breaks && strings.HasSuffix(cleaned, "\n"):
+		return strings.TrimSuffix(strings.TrimSuffix(cleaned, "\n"), "\r")
+	}
+	return cleaned
+}
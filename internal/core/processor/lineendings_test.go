@@ -0,0 +1,101 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rapid"
+)
+
+func TestDetectLineEnding(t *testing.T) {
+	tests := []struct {
+		text  string
+		style lineEnding
+		final bool
+	}{
+		{"", lineEndingNone, false},
+		{"one line", lineEndingNone, false},
+		{"a\nb\n", lineEndingLF, true},
+		{"a\r\nb", lineEndingCRLF, false},
+		{"a\r\nb\n", lineEndingMixed, true},
+		{"a\rb\n", lineEndingMixed, true},
+	}
+	for _, tt := range tests {
+		style, final := detectLineEnding(tt.text)
+		assert.Equal(t, tt.style, style, "%q", tt.text)
+		assert.Equal(t, tt.final, final, "%q", tt.text)
+	}
+}
+
+func TestModifyFile_LineEndings(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		replace  map[string]string
+		expected string
+	}{
+		{"LF", "a 🚀\nb 🎉\n", nil, "a \nb \n"},
+		{"CRLF", "a 🚀\r\nb 🎉\r\n", nil, "a \r\nb \r\n"},
+		{"CRLF without final line break", "a 🚀\r\nb 🎉", nil, "a \r\nb "},
+		{"mixed", "a 🚀\r\nb 🎉\nc ✅\r\n", nil, "a \r\nb \nc \r\n"},
+		{"emoji alone on the last line", "a\r\n🚀", nil, "a\r\n"},
+		{"line breaks of replacements follow CRLF", "a 🚀\r\nb\r\n", map[string]string{"🚀": "\n- launch"}, "a \r\n- launch\r\nb\r\n"},
+		{"line breaks of replacements follow LF", "a 🚀\nb\n", map[string]string{"🚀": "\r\n- launch"}, "a \n- launch\nb\n"},
+		{"replacement at the end adds no final line break", "a 🚀", map[string]string{"🚀": "launch\n"}, "a launch"},
+		{"mixed files keep replacements as given", "a 🚀\r\nb\n", map[string]string{"🚀": "x\ny"}, "a x\ny\r\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "notes.txt")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			config := DefaultModifyConfig()
+			config.ReplacementMap = tt.replace
+			result := ModifyFile(path, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+			require.NoError(t, result.Error)
+
+			written, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(written))
+		})
+	}
+}
+
+func TestProperty_CleanKeepsLineEndings(t *testing.T) {
+	dir := t.TempDir()
+
+	rapid.Check(t, func(t *rapid.T) {
+		content := genContent().Draw(t, "content")
+		if rapid.Bool().Draw(t, "crlf") {
+			content = strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+		}
+		path := filepath.Join(dir, "property.txt")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		config := DefaultModifyConfig()
+		config.Replacement = rapid.SampledFrom([]string{"", "\n", "\r\n", "x\ny"}).Draw(t, "replacement")
+		if result := ModifyFile(path, detector.DefaultEmojiPatterns(), config, nil).Unwrap(); result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		written, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		style, _ := detectLineEnding(content)
+		if style != lineEndingLF && style != lineEndingCRLF {
+			return
+		}
+		if writtenStyle, _ := detectLineEnding(string(written)); writtenStyle != style {
+			t.Fatalf("line endings changed from %s to %s:\ninput:   %q\nwritten: %q", style, writtenStyle, content, written)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package processor
+
+import (
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// codeSpan is the byte range [start, end) of a code region in Markdown content.
+type codeSpan struct {
+	start, end int
+}
+
+// markdownCodeSpans returns the fenced code blocks and inline code spans of Markdown
+// content, in order. An unclosed fence runs to the end of the content; an unmatched
+// backtick run is literal text.
+func markdownCodeSpans(content string) []codeSpan {
+	var spans []codeSpan
+	proseStart := 0
+	fenceStart, fence := -1, ""
+
+	for pos := 0; pos < len(content); {
+		end := strings.IndexByte(content[pos:], '\n')
+		if end == -1 {
+			end = len(content)
+		} else {
+			end += pos + 1
+		}
+		line := content[pos:end]
+
+		if fence == "" {
+			if marker := openingFence(line); marker != "" {
+				spans = append(spans, inlineCodeSpans(content, proseStart, pos)...)
+				fenceStart, fence = pos, marker
+			}
+		} else if closesFence(line, fence) {
+			spans = append(spans, codeSpan{fenceStart, end})
+			fence, proseStart = "", end
+		}
+		pos = end
+	}
+
+	if fence != "" {
+		return append(spans, codeSpan{fenceStart, len(content)})
+	}
+	return append(spans, inlineCodeSpans(content, proseStart, len(content))...)
+}
+
+// openingFence returns the fence marker (three or more backticks or tildes) opening a
+// fenced code block on line, or "" when line does not open one.
+func openingFence(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return ""
+	}
+	marker := trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+	if len(marker) < 3 {
+		return ""
+	}
+	// The info string of a backtick fence cannot contain backticks
+	if marker[0] == '`' && strings.Contains(trimmed[len(marker):], "`") {
+		return ""
+	}
+	return marker
+}
+
+// closesFence reports whether line closes a block opened with marker: the same fence
+// character at least as many times, followed only by whitespace.
+func closesFence(line, marker string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+	rest := strings.TrimLeft(trimmed, marker[:1])
+	return len(trimmed)-len(rest) >= len(marker) && strings.TrimSpace(rest) == ""
+}
+
+// inlineCodeSpans returns the code spans in content[start:end]: text between two runs of
+// the same number of backticks within one paragraph.
+func inlineCodeSpans(content string, start, end int) []codeSpan {
+	var spans []codeSpan
+	for pos := start; pos < end; {
+		open := strings.IndexByte(content[pos:end], '`')
+		if open == -1 {
+			break
+		}
+		open += pos
+		length := backtickRun(content[open:end])
+		if open > start && content[open-1] == '\\' {
+			// An escaped backtick is literal; the rest of the run may still open a span
+			pos = open + 1
+			continue
+		}
+
+		closing := findClosingRun(content, open+length, end, length)
+		if closing == -1 {
+			pos = open + length
+			continue
+		}
+		spans = append(spans, codeSpan{open, closing + length})
+		pos = closing + length
+	}
+	return spans
+}
+
+// blankLine returns the offset of the first line break in text followed by an empty
+// line, with LF or CRLF line endings, or -1.
+func blankLine(text string) int {
+	for pos := 0; ; {
+		next := strings.IndexByte(text[pos:], '\n')
+		if next == -1 {
+			return -1
+		}
+		next += pos
+		rest := strings.TrimPrefix(text[next+1:], "\r")
+		if strings.HasPrefix(rest, "\n") {
+			return next
+		}
+		pos = next + 1
+	}
+}
+
+// findClosingRun returns the start of the next run of exactly length backticks in
+// content[from:end] before a blank line, or -1.
+func findClosingRun(content string, from, end, length int) int {
+	limit := end
+	if blank := blankLine(content[from:end]); blank != -1 {
+		limit = from + blank
+	}
+	for pos := from; pos < limit; {
+		next := strings.IndexByte(content[pos:limit], '`')
+		if next == -1 {
+			return -1
+		}
+		next += pos
+		run := backtickRun(content[next:limit])
+		if run == length {
+			return next
+		}
+		pos = next + run
+	}
+	return -1
+}
+
+// backtickRun returns the number of backticks at the start of s.
+func backtickRun(s string) int {
+	return len(s) - len(strings.TrimLeft(s, "`"))
+}
+
+// markdownCode returns the code regions of Markdown content.
+func markdownCode(content string) []codeSpan {
+	if !strings.ContainsAny(content, "`~") {
+		return nil
+	}
+	return markdownCodeSpans(content)
+}
+
+// outsideSpans returns the matches that do not start inside spans, which must be in
+// order.
+func outsideSpans(spans []codeSpan, matches []types.EmojiMatch) []types.EmojiMatch {
+	if len(spans) == 0 {
+		return matches
+	}
+
+	kept := make([]types.EmojiMatch, 0, len(matches))
+	span := 0
+	for _, match := range matches {
+		for span < len(spans) && spans[span].end <= match.Start {
+			span++
+		}
+		if span < len(spans) && match.Start >= spans[span].start {
+			continue
+		}
+		kept = append(kept, match)
+	}
+	return kept
+}
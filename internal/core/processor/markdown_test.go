@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownCodeSpans(t *testing.T) {
+	code := func(content string) []string {
+		var regions []string
+		for _, span := range markdownCodeSpans(content) {
+			regions = append(regions, content[span.start:span.end])
+		}
+		return regions
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"inline code", "Run `make 🚀` now", []string{"`make 🚀`"}},
+		{"double backticks", "Use ``a ` b`` here", []string{"``a ` b``"}},
+		{"unmatched backtick is literal", "It`s 🎉\n\nnext", nil},
+		{"escaped backtick", "\\`not code` but `code`", []string{"` but `"}},
+		{"code span ends at blank line", "`open\n\nclose`", nil},
+		{"code span ends at CRLF blank line", "`open\r\n\r\nclose`", nil},
+		{"CRLF fence", "```\r\n🎉\r\n```\r\n", []string{"```\r\n🎉\r\n```\r\n"}},
+		{"backtick fence", "text\n```go\n// 🚀\n```\nafter `x`\n", []string{"```go\n// 🚀\n```\n", "`x`"}},
+		{"tilde fence with longer close", "~~~\n🎉\n~~~~\n", []string{"~~~\n🎉\n~~~~\n"}},
+		{"indented fence", "   ```\n🎉\n   ```\n", []string{"   ```\n🎉\n   ```\n"}},
+		{"four spaces is not a fence", "    ```\n🎉\n", nil},
+		{"shorter marker does not close", "````\n```\n🎉\n````\n", []string{"````\n```\n🎉\n````\n"}},
+		{"unclosed fence runs to the end", "```\n🎉\nmore", []string{"```\n🎉\nmore"}},
+		{"backtick in info string", "``` a`b\n🎉\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, code(tt.content))
+		})
+	}
+}
+
+func TestProcessFile_Markdown(t *testing.T) {
+	dir := t.TempDir()
+	content := "# Release 🚀\n\nRun `deploy 🎉` then:\n\n```sh\necho ✅\n```\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte(content), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(content), 0644))
+
+	config := types.DefaultProcessingConfig()
+	config.PreserveMarkdownCode = true
+	cache := &mapCache{entries: map[string]types.DetectionResult{}}
+	for _, run := range []string{"first", "cached"} {
+		markdown := ProcessFileWithCache(filepath.Join(dir, "notes.md"), detector.DefaultEmojiPatterns(), config, cache).Unwrap()
+		require.NoError(t, markdown.Error)
+		require.Len(t, markdown.DetectionResult.Emojis, 1, run)
+		assert.Equal(t, "🚀", markdown.DetectionResult.Emojis[0].Emoji)
+
+		text := ProcessFileWithCache(filepath.Join(dir, "notes.txt"), detector.DefaultEmojiPatterns(), config, cache).Unwrap()
+		assert.Equal(t, 3, text.DetectionResult.TotalCount, run)
+	}
+	assert.Equal(t, 2, cache.hits)
+
+	config.PreserveMarkdownCode = false
+	all := ProcessFile(filepath.Join(dir, "notes.md"), detector.DefaultEmojiPatterns(), config).Unwrap()
+	assert.Equal(t, 3, all.DetectionResult.TotalCount)
+}
+
+func TestModifyFile_Markdown(t *testing.T) {
+	content := "Ship it 🚀 with `deploy 🚀`:\n\n```\n🎉 :)\n```\ndone :)\n"
+	run := func(t *testing.T, preserve bool) string {
+		path := filepath.Join(t.TempDir(), "README.md")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		config := DefaultModifyConfig()
+		config.PreserveMarkdownCode = preserve
+		result := ModifyFile(path, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+		require.NoError(t, result.Error)
+
+		cleaned, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(cleaned)
+	}
+
+	assert.Equal(t, "Ship it  with `deploy 🚀`:\n\n```\n🎉 :)\n```\ndone \n", run(t, true))
+	assert.Equal(t, "Ship it  with `deploy `:\n\n```\n \n```\ndone \n", run(t, false))
+}
@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+)
+
+// HardLinkPolicy decides how clean writes files that have more than one hard link.
+// Writing through a temporary file gives the path a new inode, detaching it from the
+// file's other links.
+type HardLinkPolicy int
+
+const (
+	// HardLinkWarn writes hard-linked files like any other, detaching them from their
+	// other links, and reports their link count in ModifyResult.HardLinks
+	HardLinkWarn HardLinkPolicy = iota
+	// HardLinkInPlace overwrites the content of hard-linked files in place, so every
+	// link sees the cleaned content, at the cost of an atomic write
+	HardLinkInPlace
+	// HardLinkSkip leaves hard-linked files unmodified
+	HardLinkSkip
+)
+
+// PreserveConfig selects the metadata kept when a file is replaced by its cleaned
+// copy, on top of its permissions.
+type PreserveConfig struct {
+	// Ownership keeps the owner and group of the file, where the process may set them
+	Ownership bool
+	// Xattrs keeps the extended attributes of the file, which include POSIX ACLs on
+	// Linux, where the process may set them
+	Xattrs bool
+}
+
+// linkCount returns the number of hard links of the file at path, or 1 when the
+// platform does not report it.
+func linkCount(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return statLinks(info), nil
+}
+
+// copyMetadata gives the file at dst the metadata of src that preserve selects,
+// returning a warning for each piece that could not be kept.
+func copyMetadata(src, dst string, preserve PreserveConfig) []string {
+	var warnings []string
+	if preserve.Ownership {
+		if err := copyOwnership(src, dst); err != nil {
+			warnings = append(warnings, fmt.Sprintf("ownership not preserved: %v", err))
+		}
+	}
+	if preserve.Xattrs {
+		warnings = append(warnings, copyXattrs(src, dst)...)
+	}
+	return warnings
+}
+
+// writeInPlace overwrites the content of the file at path without replacing its inode,
+// keeping its hard links, ownership and extended attributes.
+func writeInPlace(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0) // #nosec G304 - filepath is validated by caller
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
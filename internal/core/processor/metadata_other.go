@@ -0,0 +1,20 @@
+//go:build !linux && !darwin
+
+package processor
+
+import "os"
+
+// statLinks returns 1: hard link counts are not reported on this platform.
+func statLinks(os.FileInfo) uint64 {
+	return 1
+}
+
+// copyOwnership does nothing: ownership is not preserved on this platform.
+func copyOwnership(string, string) error {
+	return nil
+}
+
+// copyXattrs does nothing: extended attributes are not preserved on this platform.
+func copyXattrs(string, string) []string {
+	return nil
+}
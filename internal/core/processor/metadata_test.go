@@ -0,0 +1,84 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifyFile_HardLinks(t *testing.T) {
+	setup := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "main.go")
+		require.NoError(t, os.WriteFile(path, []byte("// launch 🚀\n"), 0644))
+		link := filepath.Join(dir, "link.go")
+		if err := os.Link(path, link); err != nil {
+			t.Skipf("hard links not supported: %v", err)
+		}
+		return path, link
+	}
+	modify := func(t *testing.T, path string, policy HardLinkPolicy, atomic bool) ModifyResult {
+		config := DefaultModifyConfig()
+		config.HardLinks = policy
+		config.Atomic = atomic
+		results := ModifyFiles([]string{path}, detector.DefaultEmojiPatterns(), config, nil)
+		require.NoError(t, results[0].Error)
+		return results[0]
+	}
+	read := func(t *testing.T, path string) string {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("warn detaches the file from its links", func(t *testing.T) {
+		path, link := setup(t)
+		result := modify(t, path, HardLinkWarn, false)
+		if result.HardLinks == 0 {
+			t.Skip("hard link counts not reported on this platform")
+		}
+		assert.Equal(t, uint64(2), result.HardLinks)
+		assert.True(t, result.Modified)
+		assert.Equal(t, "// launch \n", read(t, path))
+		assert.Equal(t, "// launch 🚀\n", read(t, link))
+	})
+
+	t.Run("in place keeps the links", func(t *testing.T) {
+		for _, atomic := range []bool{false, true} {
+			path, link := setup(t)
+			result := modify(t, path, HardLinkInPlace, atomic)
+			assert.Zero(t, result.HardLinks)
+			assert.True(t, result.Modified)
+			assert.Equal(t, "// launch \n", read(t, link), "atomic: %v", atomic)
+			assert.Empty(t, entriesExcept(t, filepath.Dir(path), "main.go", "link.go"), "atomic: %v", atomic)
+		}
+	})
+
+	t.Run("skip leaves the file alone", func(t *testing.T) {
+		path, _ := setup(t)
+		result := modify(t, path, HardLinkSkip, false)
+		if result.HardLinks == 0 {
+			t.Skip("hard link counts not reported on this platform")
+		}
+		assert.False(t, result.Modified)
+		assert.Equal(t, "// launch 🚀\n", read(t, path))
+	})
+}
+
+// entriesExcept returns the names of the entries of dir other than names.
+func entriesExcept(t *testing.T, dir string, names ...string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var others []string
+	for _, entry := range entries {
+		if !slices.Contains(names, entry.Name()) {
+			others = append(others, entry.Name())
+		}
+	}
+	return others
+}
@@ -0,0 +1,56 @@
+//go:build linux || darwin
+
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestModifyFile_PreserveMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("// launch 🚀\n"), 0640))
+
+	preserve := PreserveConfig{Ownership: true}
+	if err := unix.Setxattr(path, "user.antimoji", []byte("kept"), 0); err == nil {
+		preserve.Xattrs = true
+	} else if !errors.Is(err, unix.ENOTSUP) && !errors.Is(err, unix.EPERM) {
+		require.NoError(t, err)
+	}
+	owner := os.Getuid()
+	if owner == 0 {
+		// Only root can give files away, which the cleaned copy must then keep
+		require.NoError(t, os.Chown(path, 1234, 5678))
+	}
+
+	config := DefaultModifyConfig()
+	config.Preserve = preserve
+	result := ModifyFile(path, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+	require.NoError(t, result.Error)
+	assert.True(t, result.Modified)
+	assert.Empty(t, result.MetadataWarnings)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	stat := info.Sys().(*syscall.Stat_t)
+	if owner == 0 {
+		assert.Equal(t, uint32(1234), stat.Uid)
+		assert.Equal(t, uint32(5678), stat.Gid)
+	} else {
+		assert.Equal(t, uint32(owner), stat.Uid)
+	}
+	if preserve.Xattrs {
+		value, err := getXattr(path, "user.antimoji")
+		require.NoError(t, err)
+		assert.Equal(t, "kept", string(value))
+	}
+}
@@ -2,15 +2,19 @@
 package processor
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/antimoji/antimoji/internal/core/allowlist"
 	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/core/diff"
 	"github.com/antimoji/antimoji/internal/infra/fs"
 	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
 	"github.com/antimoji/antimoji/internal/observability/logging"
@@ -22,6 +26,10 @@ type ModifyConfig struct {
 	// Replacement is the string to replace emojis with
 	Replacement string
 
+	// ReplacementMap maps individual emojis to their own replacement text.
+	// Emojis not in the map use Replacement.
+	ReplacementMap map[string]string
+
 	// CreateBackup creates a backup file before modification
 	CreateBackup bool
 
@@ -31,18 +39,132 @@ type ModifyConfig struct {
 	// PreservePermissions maintains original file permissions
 	PreservePermissions bool
 
+	// Preserve selects the metadata kept on top of permissions, such as ownership
+	Preserve PreserveConfig
+
+	// HardLinks decides how files with more than one hard link are written
+	HardLinks HardLinkPolicy
+
 	// DryRun shows what would be changed without modifying files
 	DryRun bool
+
+	// GenerateDiff records a unified diff of the proposed changes in ModifyResult.Diff
+	GenerateDiff bool
+
+	// Sniff sets how much of a file is examined and which thresholds make it binary
+	Sniff types.SniffConfig
+
+	// Retry sets how reads failing with a transient error are retried
+	Retry types.RetryConfig
+
+	// PreserveMarkdownCode leaves emojis in the fenced code blocks and inline code spans
+	// of Markdown files untouched
+	PreserveMarkdownCode bool
+
+	// Structured selects the parts of YAML, JSON and TOML files whose emojis are left
+	// untouched
+	Structured types.StructuredPolicy
+
+	// DecodeEscapes detects emojis written as escapes, such as &#x1F600;, using the
+	// escape syntaxes of the file's language
+	DecodeEscapes bool
+
+	// Languages identifies the language of each file; nil uses the built-in languages
+	Languages types.LanguageLookup
+
+	// NormalizeShortcodes rewrites shortcodes as emojis or emojis as shortcodes before
+	// emojis are removed
+	NormalizeShortcodes types.ShortcodeNormalization
+
+	// StripBOM removes the UTF-8 byte order mark of files, which counts as a
+	// modification; otherwise files keep it
+	StripBOM bool
+
+	// Violations, when set, is given the matches in the file at filePath that the
+	// allowlist does not allow and returns those to remove, so that callers can apply
+	// the rest of their policy, such as path-scoped exemptions
+	Violations func(filePath string, matches []types.EmojiMatch) []types.EmojiMatch
+
+	// Decide is consulted for every emoji that would be removed, allowing callers
+	// to keep or replace individual matches. Nil removes every match.
+	Decide MatchDecider
+
+	// MaxWorkers bounds how many files ModifyFiles modifies at once; zero or less uses
+	// one worker per CPU. Files are modified one at a time when Decide is set.
+	MaxWorkers int
+
+	// Progress is called by ModifyFiles as each file is finished. Nil reports nothing.
+	Progress ProgressFunc
+
+	// MaxErrors stops ModifyFiles once this many files failed, the files not started
+	// by then failing with ErrTooManyErrors; zero or less never stops
+	MaxErrors int
+
+	// Atomic makes ModifyFiles write no file unless every file can be written: cleaned
+	// contents are staged in temporary files and only swapped into place once all files
+	// were processed. Otherwise the batch stops at its first failure and every other
+	// file fails with ErrRolledBack.
+	Atomic bool
 }
 
+// MatchAction describes what to do with a single detected emoji.
+type MatchAction int
+
+const (
+	// ActionRemove removes the emoji using the configured replacement for that emoji
+	ActionRemove MatchAction = iota
+	// ActionKeep leaves the emoji untouched
+	ActionKeep
+	// ActionReplace replaces the emoji with MatchDecision.Replacement
+	ActionReplace
+)
+
+// MatchDecision is the outcome of a MatchDecider for one emoji.
+type MatchDecision struct {
+	Action      MatchAction
+	Replacement string
+}
+
+// MatchDecider decides how a single emoji match should be handled.
+// content is the full original file content, so deciders can show surrounding context.
+type MatchDecider func(filePath, content string, match types.EmojiMatch) MatchDecision
+
 // ModifyResult contains the result of a file modification operation.
 type ModifyResult struct {
 	FilePath      string `json:"file_path"`
 	Success       bool   `json:"success"`
 	Modified      bool   `json:"modified"`
 	EmojisRemoved int    `json:"emojis_removed"`
-	BackupPath    string `json:"backup_path,omitempty"`
-	Error         error  `json:"error,omitempty"`
+	// EmojisNormalized counts the shortcodes and emojis rewritten by NormalizeShortcodes
+	EmojisNormalized int    `json:"emojis_normalized,omitempty"`
+	BackupPath       string `json:"backup_path,omitempty"`
+	Diff             string `json:"diff,omitempty"`
+	Error            error  `json:"error,omitempty"`
+	// BinaryReason is set when the file was skipped as binary
+	BinaryReason string `json:"binary_reason,omitempty"`
+	// SuppressedRegions are the antimoji:off regions left untouched
+	SuppressedRegions []types.SuppressedRegion `json:"suppressed_regions,omitempty"`
+
+	// HardLinks is the link count of the hard-linked files that HardLinkWarn detaches
+	// from their other links or HardLinkSkip leaves unmodified
+	HardLinks uint64 `json:"hard_links,omitempty"`
+	// MetadataWarnings lists the metadata selected by Preserve that could not be kept
+	MetadataWarnings []string `json:"metadata_warnings,omitempty"`
+	// BOMStripped is set when StripBOM removed the byte order mark of the file
+	BOMStripped bool `json:"bom_stripped,omitempty"`
+
+	// staged is the temporary file holding the cleaned content in Atomic mode
+	staged string
+	// inPlace writes staged over the content of the file instead of replacing it
+	inPlace bool
+}
+
+// ReplacementFor returns the replacement text for emoji, preferring ReplacementMap.
+func (c ModifyConfig) ReplacementFor(emoji string) string {
+	if replacement, ok := c.ReplacementMap[emoji]; ok {
+		return replacement
+	}
+	return c.Replacement
 }
 
 // DefaultModifyConfig returns a default configuration for file modification.
@@ -83,15 +205,16 @@ func ModifyFile(filePath string, patterns types.EmojiPatterns, config ModifyConf
 	}
 
 	// Check if it's a text file before processing
-	if !fs.IsTextFile(filePath) {
-		logging.Debug(ctx, "Skipping binary file", "file_path", filePath)
+	if encoding, reason := fs.SniffFile(filePath, config.Sniff); encoding == fs.EncodingBinary {
+		logging.Debug(ctx, "Skipping binary file", "file_path", filePath, "reason", reason)
+		result.BinaryReason = reason
 		result.Success = true // Consider skipping a binary file as successful
 		return types.Ok(result)
 	}
 
 	// Read original file content
 	logging.Debug(ctx, "About to read file", "file_path", filePath)
-	contentResult := fs.ReadFile(filePath)
+	contentResult := fs.ReadFileWithRetry(filePath, config.Retry)
 	if contentResult.IsErr() {
 		logging.Debug(ctx, "Failed to read file", "file_path", filePath, "error", contentResult.Error())
 		result.Error = contentResult.Error()
@@ -99,14 +222,31 @@ func ModifyFile(filePath string, patterns types.EmojiPatterns, config ModifyConf
 	}
 	logging.Debug(ctx, "File read completed", "file_path", filePath)
 
-	originalContent := string(contentResult.Unwrap())
+	// Non-UTF-8 files are cleaned as UTF-8 and written back in their own encoding
+	decoded, err := fs.Decode(contentResult.Unwrap(), config.Sniff)
+	if err != nil {
+		result.Error = err
+		return types.Ok(result)
+	}
+	if config.StripBOM {
+		decoded = decoded.StripBOM()
+	}
+	originalContent := string(decoded.Text)
 	logging.Debug(ctx, "File content processed",
 		"file_path", filePath,
 		"content_size", len(originalContent))
 
+	language := languageOf(config.Languages, filePath, decoded.Text)
+	patterns = withLanguage(patterns, language, config.DecodeEscapes)
+
+	// Normalize shortcodes first, so that only violations in their normalized form are
+	// removed
+	exempt := exemptionFor(language, config.PreserveMarkdownCode, config.Structured)
+	content, normalized := normalizeShortcodes(originalContent, patterns, config.NormalizeShortcodes, exempt)
+
 	// Detect emojis in the content
 	logging.Debug(ctx, "Starting emoji detection", "file_path", filePath)
-	detectionResult := detector.DetectEmojis([]byte(originalContent), patterns)
+	detectionResult := detector.DetectEmojis([]byte(content), patterns)
 	if detectionResult.IsErr() {
 		logging.Debug(ctx, "Failed to detect emojis", "file_path", filePath, "error", detectionResult.Error())
 		result.Error = detectionResult.Error()
@@ -115,6 +255,8 @@ func ModifyFile(filePath string, patterns types.EmojiPatterns, config ModifyConf
 	logging.Debug(ctx, "Emoji detection completed", "file_path", filePath)
 
 	detection := detectionResult.Unwrap()
+	detection = exempt.apply(content, detection)
+	result.SuppressedRegions = detection.SuppressedRegions
 	logging.Debug(ctx, "Emoji detection results processed",
 		"file_path", filePath,
 		"emojis_found", detection.TotalCount)
@@ -169,6 +311,7 @@ func ModifyFile(filePath string, patterns types.EmojiPatterns, config ModifyConf
 			Emojis:         filteredEmojis,
 			TotalCount:     len(filteredEmojis),
 			ProcessedBytes: detection.ProcessedBytes,
+			Lines:          detection.Lines,
 			Duration:       detection.Duration,
 			Success:        detection.Success,
 		}
@@ -177,9 +320,59 @@ func ModifyFile(filePath string, patterns types.EmojiPatterns, config ModifyConf
 			"file_path", filePath,
 			"emojis_after_filtering", detection.TotalCount)
 	}
+	if config.Violations != nil {
+		violations := config.Violations(filePath, detection.Emojis)
+		detection = types.DetectionResult{
+			Emojis:         violations,
+			TotalCount:     len(violations),
+			ProcessedBytes: detection.ProcessedBytes,
+			Lines:          detection.Lines,
+			Duration:       detection.Duration,
+			Success:        detection.Success,
+		}
+		detection.Finalize()
+		logging.Debug(ctx, "Policy filtering completed",
+			"file_path", filePath,
+			"emojis_after_filtering", detection.TotalCount)
+	}
+
+	// Let the decider keep or replace individual matches
+	replacements := make([]string, len(detection.Emojis))
+	for i, emoji := range detection.Emojis {
+		replacements[i] = config.ReplacementFor(emoji.Emoji)
+	}
+	if config.Decide != nil && detection.TotalCount > 0 {
+		selected := make([]types.EmojiMatch, 0, len(detection.Emojis))
+		selectedReplacements := make([]string, 0, len(detection.Emojis))
+		for _, emoji := range detection.Emojis {
+			decision := config.Decide(filePath, content, emoji)
+			switch decision.Action {
+			case ActionKeep:
+				continue
+			case ActionReplace:
+				selectedReplacements = append(selectedReplacements, decision.Replacement)
+			default:
+				selectedReplacements = append(selectedReplacements, config.ReplacementFor(emoji.Emoji))
+			}
+			selected = append(selected, emoji)
+		}
+		detection = types.DetectionResult{
+			Emojis:         selected,
+			TotalCount:     len(selected),
+			ProcessedBytes: detection.ProcessedBytes,
+			Lines:          detection.Lines,
+			Duration:       detection.Duration,
+			Success:        detection.Success,
+		}
+		detection.Finalize()
+		replacements = selectedReplacements
+		logging.Debug(ctx, "Match decisions applied",
+			"file_path", filePath,
+			"emojis_after_decisions", detection.TotalCount)
+	}
 
-	// If no emojis to remove, return success without modification
-	if detection.TotalCount == 0 {
+	// If no emojis to remove or normalize, return success without modification
+	if detection.TotalCount == 0 && normalized == 0 && !decoded.BOMStripped() {
 		logging.Debug(ctx, "No emojis to remove", "file_path", filePath)
 		result.Success = true
 		return types.Ok(result)
@@ -190,6 +383,22 @@ func ModifyFile(filePath string, patterns types.EmojiPatterns, config ModifyConf
 		"emojis_to_remove", detection.TotalCount,
 		"replacement", config.Replacement)
 
+	// Replacing a hard-linked file detaches it from its other links
+	links, err := linkCount(filePath)
+	if err != nil {
+		result.Error = err
+		return types.Ok(result)
+	}
+	if links > 1 && config.HardLinks != HardLinkInPlace {
+		result.HardLinks = links
+		if config.HardLinks == HardLinkSkip {
+			logging.Debug(ctx, "Skipping hard-linked file", "file_path", filePath, "links", links)
+			result.Success = true
+			return types.Ok(result)
+		}
+	}
+	inPlace := links > 1 && config.HardLinks == HardLinkInPlace
+
 	// Create backup if requested
 	if config.CreateBackup {
 		backupResult := CreateBackup(filePath)
@@ -201,13 +410,48 @@ func ModifyFile(filePath string, patterns types.EmojiPatterns, config ModifyConf
 	}
 
 	// Remove emojis from content
-	modifiedContent := RemoveEmojis(originalContent, detection, config.Replacement)
+	modifiedContent, replaced := replaceMasked(content, detection.Emojis, replacements, nil)
+	emojisRemoved := detection.TotalCount
+
+	// Removing an emoji can join its neighbours into a new match (":😀)" becomes ":)"),
+	// so keep removing until the content is stable. Interactive decisions are final.
+	if config.Decide == nil {
+		var keep *allowlist.Allowlist
+		if config.RespectAllowlist {
+			keep = emojiAllowlist
+		}
+		var extra int
+		modifiedContent, extra = removeUntilStable(modifiedContent, replaced, patterns, config.ReplacementFor, keep, config.violationsIn(filePath), exempt)
+		emojisRemoved += extra
+	}
+	modifiedContent = keepLineEndings(originalContent, modifiedContent)
+
+	encoded, err := decoded.Encode([]byte(modifiedContent))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to encode file: %w", err)
+		return types.Ok(result)
+	}
+
+	if config.GenerateDiff {
+		label := diffLabel(filePath)
+		// The diff applies to the file, byte order mark included
+		before, after := originalContent, modifiedContent
+		if decoded.HasBOM() && decoded.Encoding == fs.EncodingUTF8 {
+			before = bomText + before
+			if !decoded.BOMStripped() {
+				after = bomText + after
+			}
+		}
+		result.Diff = diff.Unified("a/"+label, "b/"+label, before, after, diff.DefaultContext)
+	}
 
 	// In dry-run mode, don't actually modify the file
 	if config.DryRun {
 		result.Success = true
 		result.Modified = true
-		result.EmojisRemoved = detection.TotalCount
+		result.EmojisRemoved = emojisRemoved
+		result.EmojisNormalized = normalized
+		result.BOMStripped = decoded.BOMStripped()
 		return types.Ok(result)
 	}
 
@@ -219,91 +463,189 @@ func ModifyFile(filePath string, patterns types.EmojiPatterns, config ModifyConf
 		}
 	}
 
-	// Write modified content atomically
-	writeResult := AtomicWriteFile(filePath, []byte(modifiedContent), fileMode)
-	if writeResult.IsErr() {
-		result.Error = fmt.Errorf("failed to write file: %w", writeResult.Error())
-		return types.Ok(result)
+	// Files written in place keep their inode, and with it all their metadata
+	preserve := config.Preserve
+	if inPlace {
+		preserve = PreserveConfig{}
+	}
+	switch {
+	case config.Atomic:
+		// Staged until every file of the batch is processed, see commitStaged
+		staged, warnings, err := writeTemp(filePath, encoded, fileMode, preserve)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to stage file: %w", err)
+			return types.Ok(result)
+		}
+		result.staged, result.inPlace, result.MetadataWarnings = staged, inPlace, warnings
+	case inPlace:
+		if err := writeInPlace(filePath, encoded); err != nil {
+			result.Error = fmt.Errorf("failed to write file: %w", err)
+			return types.Ok(result)
+		}
+	default:
+		// Write modified content atomically
+		warnings, err := atomicWrite(filePath, encoded, fileMode, preserve)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to write file: %w", err)
+			return types.Ok(result)
+		}
+		result.MetadataWarnings = warnings
 	}
 
 	result.Success = true
 	result.Modified = true
-	result.EmojisRemoved = detection.TotalCount
+	result.EmojisRemoved = emojisRemoved
+	result.EmojisNormalized = normalized
+	result.BOMStripped = decoded.BOMStripped()
 
 	logging.Debug(ctx, "File modification completed successfully",
 		"file_path", filePath,
-		"emojis_removed", detection.TotalCount,
+		"emojis_removed", emojisRemoved,
 		"backup_created", result.BackupPath != "",
 		"dry_run", config.DryRun)
 
 	return types.Ok(result)
 }
 
-// ModifyFiles modifies multiple files to remove emojis.
+// ModifyFiles modifies multiple files to remove emojis, up to config.MaxWorkers at a
+// time. Each file is written atomically, and results are ordered by path regardless of
+// which file finishes first.
 func ModifyFiles(filePaths []string, patterns types.EmojiPatterns, config ModifyConfig,
 	emojiAllowlist *allowlist.Allowlist) []ModifyResult {
 
 	// Create context for batch processing
 	ctx := ctxutil.NewComponentContext("process_files_batch", "processor")
-	results := make([]ModifyResult, 0, len(filePaths))
+	results := make([]ModifyResult, len(filePaths))
 	totalFiles := len(filePaths)
-	processedFiles := 0
 
-	for i, filePath := range filePaths {
-		// Log progress for every file to see where it's hanging
-		logging.Debug(ctx, "Processing file",
-			"file_index", i+1,
-			"total_files", totalFiles,
-			"file_path", filePath,
-			"progress_percentage", float64(i)/float64(totalFiles)*100)
-
-		modifyResult := ModifyFile(filePath, patterns, config, emojiAllowlist)
-		if modifyResult.IsOk() {
-			result := modifyResult.Unwrap()
-			results = append(results, result)
-			processedFiles++
+	workers := config.MaxWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if config.Decide != nil {
+		// Decisions prompt the user, so files must be handled in turn
+		workers = 1
+	}
+	if workers > totalFiles {
+		workers = totalFiles
+	}
 
-			// Log completion of each file
-			logging.Debug(ctx, "File processing completed",
-				"file_path", filePath,
-				"success", result.Success,
-				"modified", result.Modified,
-				"emojis_removed", result.EmojisRemoved,
-				"file_index", i+1,
-				"total_files", totalFiles)
-		} else {
-			// This shouldn't happen with current implementation
-			errorResult := ModifyResult{
-				FilePath: filePath,
-				Success:  false,
-				Error:    modifyResult.Error(),
+	limit := newErrorLimit(config.MaxErrors)
+	if config.Atomic {
+		// A single failure rolls the batch back, so the other files need not be read
+		limit = newErrorLimit(1)
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				var size int64
+				if config.Progress != nil {
+					// The size before cleaning is the amount of content read
+					if info, err := os.Stat(filePaths[i]); err == nil {
+						size = info.Size()
+					}
+				}
+				if limit.reached() {
+					results[i] = ModifyResult{FilePath: filePaths[i], Error: ErrTooManyErrors}
+				} else {
+					results[i] = modifyFileAt(ctx, filePaths[i], i, totalFiles, patterns, config, emojiAllowlist)
+					limit.record(results[i].Error)
+				}
+				if config.Progress != nil {
+					config.Progress(filePaths[i], size)
+				}
 			}
-			results = append(results, errorResult)
-			logging.Debug(ctx, "Error processing file",
-				"file_path", filePath,
-				"error", modifyResult.Error(),
-				"file_index", i+1,
-				"total_files", totalFiles)
-		}
+		}()
+	}
+	for i := range filePaths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if config.Atomic && !config.DryRun {
+		commitStaged(ctx, results)
 	}
+	sortByPath(results, func(result ModifyResult) string { return result.FilePath })
 
-	// Final progress update
-	fmt.Printf("Processing files: 100.0%% (%d/%d) - Complete!\n", totalFiles, totalFiles)
+	summary := SummarizeModify(results)
+	logging.Debug(ctx, "Batch processing completed",
+		"total_files", totalFiles,
+		"workers", workers,
+		"modified_files", summary.FilesModified,
+		"emojis_removed", summary.EmojisRemoved,
+		"errors", summary.Errors)
 
 	return results
 }
 
-// CreateBackup creates a backup copy of the specified file.
-func CreateBackup(filePath string) types.Result[string] {
-	// Generate backup filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	dir := filepath.Dir(filePath)
-	base := filepath.Base(filePath)
-	ext := filepath.Ext(base)
-	name := strings.TrimSuffix(base, ext)
+// modifyFileAt modifies the file at index i of a batch of totalFiles.
+func modifyFileAt(ctx context.Context, filePath string, i, totalFiles int, patterns types.EmojiPatterns,
+	config ModifyConfig, emojiAllowlist *allowlist.Allowlist) ModifyResult {
+
+	ctx = ctxutil.WithFilePath(ctx, filePath)
+	logging.Debug(ctx, "Processing file",
+		"file_index", i+1,
+		"total_files", totalFiles)
+
+	modifyResult := ModifyFile(filePath, patterns, config, emojiAllowlist)
+	if modifyResult.IsErr() {
+		// This shouldn't happen with current implementation
+		logging.Debug(ctx, "Error processing file",
+			"error", modifyResult.Error(),
+			"file_index", i+1,
+			"total_files", totalFiles)
+		return ModifyResult{
+			FilePath: filePath,
+			Success:  false,
+			Error:    modifyResult.Error(),
+		}
+	}
+
+	result := modifyResult.Unwrap()
+	logging.Debug(ctx, "File processing completed",
+		"success", result.Success,
+		"modified", result.Modified,
+		"emojis_removed", result.EmojisRemoved,
+		"file_index", i+1,
+		"total_files", totalFiles)
+	return result
+}
+
+// ModifySummary aggregates the results of modifying a batch of files.
+type ModifySummary struct {
+	Files         int `json:"files"`
+	FilesModified int `json:"files_modified"`
+	EmojisRemoved int `json:"emojis_removed"`
+	// EmojisNormalized counts the shortcodes and emojis rewritten instead of removed
+	EmojisNormalized int `json:"emojis_normalized,omitempty"`
+	Errors           int `json:"errors"`
+}
 
-	backupPath := filepath.Join(dir, fmt.Sprintf("%s.backup.%s%s", name, timestamp, ext))
+// SummarizeModify totals results. Files with an error count as neither modified nor
+// contributing removed emojis.
+func SummarizeModify(results []ModifyResult) ModifySummary {
+	summary := ModifySummary{Files: len(results)}
+	for _, result := range results {
+		switch {
+		case result.Error != nil:
+			summary.Errors++
+		case result.Modified:
+			summary.FilesModified++
+			summary.EmojisRemoved += result.EmojisRemoved
+			summary.EmojisNormalized += result.EmojisNormalized
+		}
+	}
+	return summary
+}
 
+// CreateBackup creates a backup copy of the specified file, named after it with a
+// timestamp, and returns its path. An existing backup is never overwritten.
+func CreateBackup(filePath string) types.Result[string] {
 	// Read original content
 	content, err := os.ReadFile(filePath) // #nosec G304 - filepath is validated by caller
 	if err != nil {
@@ -316,17 +658,73 @@ func CreateBackup(filePath string) types.Result[string] {
 		return types.Err[string](err)
 	}
 
-	// Write backup file
-	err = os.WriteFile(backupPath, content, stat.Mode().Perm())
-	if err != nil {
-		return types.Err[string](err)
-	}
+	// Generate backup filename with timestamp. Runs within the same second get a
+	// numbered suffix, as an existing backup is never overwritten
+	timestamp := time.Now().Format("20060102-150405")
+	dir := filepath.Dir(filePath)
+	base := filepath.Base(filePath)
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+
+	for attempt := 1; attempt <= maxBackupAttempts; attempt++ {
+		suffix := timestamp
+		if attempt > 1 {
+			suffix = fmt.Sprintf("%s-%d", timestamp, attempt)
+		}
+		backupPath := filepath.Join(dir, fmt.Sprintf("%s.backup.%s%s", name, suffix, ext))
 
-	return types.Ok(backupPath)
+		file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, stat.Mode().Perm()) // #nosec G304 - derived from the validated filepath
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return types.Err[string](err)
+		}
+		_, err = file.Write(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(backupPath)
+			return types.Err[string](err)
+		}
+		return types.Ok(backupPath)
+	}
+	return types.Err[string](fmt.Errorf("no free backup name for %s after %d attempts", filePath, maxBackupAttempts))
 }
 
+// maxBackupAttempts bounds the numbered backup names tried for one file within a second.
+const maxBackupAttempts = 1000
+
 // AtomicWriteFile writes data to a file atomically by writing to a temporary file first.
 func AtomicWriteFile(filePath string, data []byte, perm os.FileMode) types.Result[struct{}] {
+	if _, err := atomicWrite(filePath, data, perm, PreserveConfig{}); err != nil {
+		return types.Err[struct{}](err)
+	}
+	return types.Ok(struct{}{})
+}
+
+// atomicWrite replaces filePath with data through a temporary file, keeping the
+// metadata preserve selects, and returns warnings for the metadata that was lost.
+func atomicWrite(filePath string, data []byte, perm os.FileMode, preserve PreserveConfig) ([]string, error) {
+	tmpPath, warnings, err := writeTemp(filePath, data, perm, preserve)
+	if err != nil {
+		return nil, err
+	}
+
+	// Atomically replace original file
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// writeTemp writes data to a temporary file next to filePath, with the permissions of
+// filePath or perm when it does not exist and the metadata of filePath that preserve
+// selects. It returns the temporary file's path and a warning for each piece of
+// metadata that could not be kept.
+func writeTemp(filePath string, data []byte, perm os.FileMode, preserve PreserveConfig) (string, []string, error) {
 	dir := filepath.Dir(filePath)
 
 	// Check if file exists and get its permissions
@@ -340,77 +738,261 @@ func AtomicWriteFile(filePath string, data []byte, perm os.FileMode) types.Resul
 	// Create temporary file in the same directory
 	tmpFile, err := os.CreateTemp(dir, ".antimoji-tmp-*")
 	if err != nil {
-		return types.Err[struct{}](err)
+		return "", nil, err
 	}
 	tmpPath := tmpFile.Name()
 
 	// Clean up on error
+	written := false
 	defer func() {
-		if tmpFile != nil {
+		if !written {
 			_ = tmpFile.Close()
 			_ = os.Remove(tmpPath)
 		}
 	}()
 
 	// Write data to temporary file
-	_, err = tmpFile.Write(data)
-	if err != nil {
-		return types.Err[struct{}](err)
+	if _, err := tmpFile.Write(data); err != nil {
+		return "", nil, err
 	}
 
 	// Sync to ensure data is written
-	err = tmpFile.Sync()
-	if err != nil {
-		return types.Err[struct{}](err)
+	if err := tmpFile.Sync(); err != nil {
+		return "", nil, err
 	}
 
 	// Close temporary file
-	err = tmpFile.Close()
-	tmpFile = nil // Mark as closed
-	if err != nil {
-		return types.Err[struct{}](err)
+	if err := tmpFile.Close(); err != nil {
+		return "", nil, err
 	}
 
-	// Set permissions on temporary file (use existing file permissions if available)
-	err = os.Chmod(tmpPath, existingMode)
-	if err != nil {
-		return types.Err[struct{}](err)
+	// Copy ownership and extended attributes before the permissions, since changing
+	// the owner can clear setuid and setgid bits
+	var warnings []string
+	if _, err := os.Stat(filePath); err == nil {
+		warnings = copyMetadata(filePath, tmpPath, preserve)
 	}
 
-	// Atomically replace original file
-	err = os.Rename(tmpPath, filePath)
-	if err != nil {
-		return types.Err[struct{}](err)
+	// Set permissions on temporary file (use existing file permissions if available)
+	if err := os.Chmod(tmpPath, existingMode); err != nil {
+		return "", nil, err
 	}
 
-	return types.Ok(struct{}{})
+	written = true
+	return tmpPath, warnings, nil
 }
 
 // RemoveEmojis removes detected emojis from content and replaces them with the specified replacement.
 // This is a pure function that does not modify external state.
 func RemoveEmojis(content string, detectionResult types.DetectionResult, replacement string) string {
-	if len(detectionResult.Emojis) == 0 {
+	replacements := make([]string, len(detectionResult.Emojis))
+	for i := range replacements {
+		replacements[i] = replacement
+	}
+	return ReplaceMatches(content, detectionResult.Emojis, replacements)
+}
+
+// bomText is the UTF-8 byte order mark as text.
+const bomText = "\uFEFF"
+
+// maxCleanPasses bounds how often removal is repeated to reach a stable result.
+const maxCleanPasses = 16
+
+// CleanContent detects and removes every emoji in content until no emoji remains,
+// so that cleaning already-cleaned content is a no-op.
+// This is a pure function that does not modify external state.
+func CleanContent(content string, patterns types.EmojiPatterns, replacement string) string {
+	cleaned, _ := removeUntilStable(content, nil, patterns, func(string) string { return replacement }, nil, nil, exemption{})
+	return cleaned
+}
+
+// violationsIn returns Violations for the file at filePath, or nil when it is not set.
+func (c ModifyConfig) violationsIn(filePath string) func([]types.EmojiMatch) []types.EmojiMatch {
+	if c.Violations == nil {
+		return nil
+	}
+	return func(matches []types.EmojiMatch) []types.EmojiMatch {
+		return c.Violations(filePath, matches)
+	}
+}
+
+// removeUntilStable repeatedly replaces non-allowlisted emojis using replacementFor until
+// detection finds none or the content stops changing. When violations is not nil, only
+// the matches it returns are replaced. Code in Markdown content is left untouched as
+// exempt selects, and so is the text replacements wrote, in this call or before it in
+// the replaced spans, so that a replacement such as ":)" is never removed in turn. It
+// returns the cleaned content and the number of removals.
+func removeUntilStable(content string, replaced []span, patterns types.EmojiPatterns, replacementFor func(emoji string) string,
+	emojiAllowlist *allowlist.Allowlist, violations func([]types.EmojiMatch) []types.EmojiMatch, exempt exemption) (string, int) {
+
+	removed := 0
+	for pass := 0; pass < maxCleanPasses; pass++ {
+		detectionResult := detector.DetectEmojis([]byte(content), patterns)
+		if detectionResult.IsErr() {
+			break
+		}
+
+		matches := detectionResult.Unwrap().Emojis
+		matches = exempt.filter(content, matches)
+		matches = outside(matches, replaced)
+		if emojiAllowlist != nil {
+			filtered := matches[:0]
+			for _, match := range matches {
+				if !emojiAllowlist.IsAllowed(match.Emoji) {
+					filtered = append(filtered, match)
+				}
+			}
+			matches = filtered
+		}
+		if violations != nil {
+			matches = violations(matches)
+		}
+		if len(matches) == 0 {
+			break
+		}
+
+		replacements := make([]string, len(matches))
+		for i, match := range matches {
+			replacements[i] = replacementFor(match.Emoji)
+		}
+		next, nextReplaced := replaceMasked(content, matches, replacements, replaced)
+		if next == content {
+			break
+		}
+		replaced = nextReplaced
+		content = next
+		removed += len(matches)
+	}
+
+	return content, removed
+}
+
+// ReplaceMatches replaces each match with the replacement at the same index.
+// This is a pure function that does not modify external state.
+func ReplaceMatches(content string, matches []types.EmojiMatch, replacements []string) string {
+	if len(matches) == 0 {
 		return content
 	}
 
-	// Sort emojis by position in reverse order to avoid position shifts
-	emojis := make([]types.EmojiMatch, len(detectionResult.Emojis))
-	copy(emojis, detectionResult.Emojis)
-	sort.Slice(emojis, func(i, j int) bool {
-		return emojis[i].Start > emojis[j].Start
+	// Pair matches with their replacements and sort in reverse order to avoid position shifts
+	type edit struct {
+		match       types.EmojiMatch
+		replacement string
+	}
+	edits := make([]edit, len(matches))
+	for i, match := range matches {
+		edits[i] = edit{match: match}
+		if i < len(replacements) {
+			edits[i].replacement = replacements[i]
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].match.Start > edits[j].match.Start
 	})
 
-	// Remove emojis from end to beginning to avoid position shifts
+	// Replace emojis from end to beginning to avoid position shifts
 	result := content
-	for _, emoji := range emojis {
+	for _, e := range edits {
+		emoji := e.match
 		if emoji.Start >= 0 && emoji.End <= len(result) && emoji.End > emoji.Start {
-			result = result[:emoji.Start] + replacement + result[emoji.End:]
+			result = result[:emoji.Start] + e.replacement + result[emoji.End:]
 		}
 	}
 
 	return result
 }
 
+// span is the byte range [start, end) of some content.
+type span struct {
+	start, end int
+}
+
+// replaceMasked replaces each match with the replacement at the same index, like
+// ReplaceMatches, and returns the spans of the result that replacements wrote: those of
+// this call and those of replaced, the spans written before, that no match overlaps.
+// replaced must be sorted; the returned spans are.
+func replaceMasked(content string, matches []types.EmojiMatch, replacements []string, replaced []span) (string, []span) {
+	type edit struct {
+		start, end  int
+		replacement string
+	}
+	edits := make([]edit, 0, len(matches))
+	for i, match := range matches {
+		if match.Start < 0 || match.End > len(content) || match.End <= match.Start {
+			continue
+		}
+		e := edit{start: match.Start, end: match.End}
+		if i < len(replacements) {
+			e.replacement = replacements[i]
+		}
+		edits = append(edits, e)
+	}
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	b.Grow(len(content))
+	written := make([]span, 0, len(replaced)+len(edits))
+	pos, delta, r := 0, 0, 0
+	// keep moves the earlier spans ending before limit by the replacements so far,
+	// dropping those an edit overlapped
+	keep := func(limit int) {
+		for ; r < len(replaced) && replaced[r].start < limit; r++ {
+			if replaced[r].start >= pos && replaced[r].end <= limit {
+				written = append(written, span{replaced[r].start + delta, replaced[r].end + delta})
+			}
+		}
+	}
+	for _, e := range edits {
+		if e.start < pos {
+			// Overlapping matches are replaced once
+			continue
+		}
+		keep(e.start)
+		b.WriteString(content[pos:e.start])
+		start := b.Len()
+		b.WriteString(e.replacement)
+		if e.replacement != "" {
+			written = append(written, span{start, b.Len()})
+		}
+		delta += len(e.replacement) - (e.end - e.start)
+		pos = e.end
+	}
+	keep(len(content) + 1)
+	b.WriteString(content[pos:])
+	return b.String(), written
+}
+
+// outside returns the matches that lie outside every span of spans, which are sorted.
+func outside(matches []types.EmojiMatch, spans []span) []types.EmojiMatch {
+	if len(spans) == 0 {
+		return matches
+	}
+	kept := matches[:0]
+	for _, match := range matches {
+		// The last span starting at or before the match is the only one that can hold it
+		i := sort.Search(len(spans), func(i int) bool { return spans[i].start > match.Start }) - 1
+		if i >= 0 && match.End <= spans[i].end {
+			continue
+		}
+		kept = append(kept, match)
+	}
+	return kept
+}
+
+// diffLabel returns the slash-separated path used in diff headers, relative to the
+// working directory when possible so that patches apply with `git apply`.
+func diffLabel(filePath string) string {
+	path := filepath.Clean(filePath)
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(path), "/")
+}
+
 // getUnicodeCodepoints returns the Unicode code points for debugging emoji detection.
 func getUnicodeCodepoints(text string) []string {
 	var codepoints []string
@@ -5,12 +5,15 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/antimoji/antimoji/internal/core/allowlist"
 	"github.com/antimoji/antimoji/internal/core/detector"
 	"github.com/antimoji/antimoji/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestModifyFile(t *testing.T) {
@@ -210,6 +213,21 @@ func TestModifyFile(t *testing.T) {
 	})
 }
 
+func TestModifyFile_SuppressedRegion(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "fixture_test.go")
+	content := "// 🚀\n// antimoji:off\nvar fixture = \"😀 :)\"\n// antimoji:on\n// 🎉\n"
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0600))
+
+	result := ModifyFile(filePath, detector.DefaultEmojiPatterns(), DefaultModifyConfig(), nil).Unwrap()
+	require.NoError(t, result.Error)
+	assert.Equal(t, 2, result.EmojisRemoved)
+	assert.Equal(t, []types.SuppressedRegion{{StartLine: 2, EndLine: 4, Suppressed: 2}}, result.SuppressedRegions)
+
+	modified, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "// \n// antimoji:off\nvar fixture = \"😀 :)\"\n// antimoji:on\n// \n", string(modified))
+}
+
 func TestCreateBackup(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -234,6 +252,24 @@ func TestCreateBackup(t *testing.T) {
 		assert.Equal(t, originalContent, string(backupContent))
 	})
 
+	t.Run("never overwrites an earlier backup", func(t *testing.T) {
+		filePath := filepath.Join(tmpDir, "twice.txt")
+		require.NoError(t, os.WriteFile(filePath, []byte("first 😀"), 0644))
+		first := CreateBackup(filePath)
+		require.True(t, first.IsOk())
+		require.NoError(t, os.WriteFile(filePath, []byte("second 🎉"), 0644))
+		second := CreateBackup(filePath)
+		require.True(t, second.IsOk())
+
+		assert.NotEqual(t, first.Unwrap(), second.Unwrap())
+		content, err := os.ReadFile(first.Unwrap())
+		require.NoError(t, err)
+		assert.Equal(t, "first 😀", string(content))
+		content, err = os.ReadFile(second.Unwrap())
+		require.NoError(t, err)
+		assert.Equal(t, "second 🎉", string(content))
+	})
+
 	t.Run("handles non-existent file", func(t *testing.T) {
 		filePath := filepath.Join(tmpDir, "nonexistent.txt")
 
@@ -389,6 +425,17 @@ func TestRemoveEmojis(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 
+	t.Run("replaces emoji sequences as a whole", func(t *testing.T) {
+		content := "Team 👨‍👩‍👧, dev 👩🏽‍💻, flag 🇺🇸, key 1️⃣"
+		expected := "Team [E], dev [E], flag [E], key [E]"
+
+		patterns := detector.DefaultEmojiPatterns()
+		detectionResult := detector.DetectEmojis([]byte(content), patterns).Unwrap()
+
+		result := RemoveEmojis(content, detectionResult, "[E]")
+		assert.Equal(t, expected, result)
+	})
+
 	t.Run("preserves non-emoji content exactly", func(t *testing.T) {
 		content := "Hello 😀 world!\nLine 2 with :) content.\n\tTabbed content."
 
@@ -404,6 +451,93 @@ func TestRemoveEmojis(t *testing.T) {
 	})
 }
 
+func TestReplaceMatches(t *testing.T) {
+	t.Run("applies per-match replacements", func(t *testing.T) {
+		content := "a 😀 b 🚀 c"
+		detectionResult := detector.DetectEmojis([]byte(content), detector.DefaultEmojiPatterns()).Unwrap()
+
+		result := ReplaceMatches(content, detectionResult.Emojis, []string{"[smile]", ""})
+		assert.Equal(t, "a [smile] b  c", result)
+	})
+}
+
+func TestCleanContent(t *testing.T) {
+	t.Run("removes emoticons formed by a removal", func(t *testing.T) {
+		// Found by TestProperty_CleanIsIdempotent
+		patterns := detector.DefaultEmojiPatterns()
+		cleaned := CleanContent(":😀)", patterns, "")
+
+		assert.Equal(t, "", cleaned)
+		assert.Equal(t, cleaned, CleanContent(cleaned, patterns, ""))
+	})
+
+	t.Run("stops when replacement is itself an emoji", func(t *testing.T) {
+		assert.Equal(t, "a 🙂 b", CleanContent("a 😀 b", detector.DefaultEmojiPatterns(), "🙂"))
+	})
+}
+
+func TestModifyFile_Decide(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "decide.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("keep 😀 remove 🚀 swap 🎉"), 0644))
+
+	config := DefaultModifyConfig()
+	config.Decide = func(_, _ string, match types.EmojiMatch) MatchDecision {
+		switch match.Emoji {
+		case "😀":
+			return MatchDecision{Action: ActionKeep}
+		case "🎉":
+			return MatchDecision{Action: ActionReplace, Replacement: "[party]"}
+		default:
+			return MatchDecision{Action: ActionRemove}
+		}
+	}
+
+	result := ModifyFile(filePath, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+	assert.True(t, result.Modified)
+	assert.Equal(t, 2, result.EmojisRemoved)
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "keep 😀 remove  swap [party]", string(content))
+}
+
+func TestModifyFile_ReplacementMap(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "mapped.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("done ✅ ship 🚀 party 🎉"), 0644))
+
+	config := DefaultModifyConfig()
+	config.Replacement = "[emoji]"
+	config.ReplacementMap = map[string]string{"✅": "[ok]", "🚀": "(launch)"}
+
+	result := ModifyFile(filePath, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+	assert.True(t, result.Modified)
+	assert.Equal(t, 3, result.EmojisRemoved)
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "done [ok] ship (launch) party [emoji]", string(content))
+}
+
+func TestModifyFile_ReplacementsAreKept(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "mapped.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("hi 😀 there, go 🚀, :🎉)"), 0644))
+
+	config := DefaultModifyConfig()
+	config.ReplacementMap = map[string]string{"😀": ":)", "🚀": "✅"}
+
+	// Replacement text is never detected again, while emojis joined into a new
+	// emoticon by a removal still are
+	result := ModifyFile(filePath, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+	assert.True(t, result.Modified)
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "hi :) there, go ✅, ", string(content))
+}
+
 func TestModifyConfig(t *testing.T) {
 	t.Run("DefaultModifyConfig returns sensible defaults", func(t *testing.T) {
 		config := DefaultModifyConfig()
@@ -495,6 +629,100 @@ func TestModifyFiles(t *testing.T) {
 	})
 }
 
+func TestModifyFiles_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	var filePaths []string
+	for i := 0; i < 40; i++ {
+		filePath := filepath.Join(dir, fmt.Sprintf("file%02d.txt", i))
+		content := fmt.Sprintf("file %d", i)
+		if i%3 == 0 {
+			content += " 😀 🚀"
+		}
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0600))
+		filePaths = append(filePaths, filePath)
+	}
+	filePaths = append(filePaths, filepath.Join(dir, "missing.txt"))
+	reversed := slices.Clone(filePaths)
+	slices.Reverse(reversed)
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			config := DefaultModifyConfig()
+			config.DryRun = true
+			config.MaxWorkers = workers
+
+			results := ModifyFiles(reversed, detector.DefaultEmojiPatterns(), config, nil)
+			require.Len(t, results, len(filePaths))
+			for i, result := range results {
+				assert.Equal(t, filePaths[i], result.FilePath, "results are ordered by path")
+			}
+			assert.Equal(t, ModifySummary{Files: 41, FilesModified: 14, EmojisRemoved: 28, Errors: 1}, SummarizeModify(results))
+		})
+	}
+
+	t.Run("writes every file", func(t *testing.T) {
+		config := DefaultModifyConfig()
+		config.MaxWorkers = 8
+
+		ModifyFiles(filePaths[:40], detector.DefaultEmojiPatterns(), config, nil)
+		for i, filePath := range filePaths[:40] {
+			content, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+			assert.NotContains(t, string(content), "😀", "file %d", i)
+		}
+	})
+}
+
+func TestModifyFiles_Progress(t *testing.T) {
+	dir := t.TempDir()
+	var filePaths []string
+	for i := 0; i < 10; i++ {
+		filePath := filepath.Join(dir, fmt.Sprintf("file%d.txt", i))
+		require.NoError(t, os.WriteFile(filePath, []byte("hi 😀"), 0600))
+		filePaths = append(filePaths, filePath)
+	}
+
+	var mu sync.Mutex
+	var reported []string
+	var bytes int64
+	config := DefaultModifyConfig()
+	config.MaxWorkers = 4
+	config.Progress = func(filePath string, size int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, filePath)
+		bytes += size
+	}
+
+	ModifyFiles(filePaths, detector.DefaultEmojiPatterns(), config, nil)
+	assert.ElementsMatch(t, filePaths, reported)
+	assert.Equal(t, int64(10*len("hi 😀")), bytes, "sizes are taken before cleaning")
+}
+
+func TestModifyFiles_MaxErrors(t *testing.T) {
+	dir := t.TempDir()
+	var filePaths []string
+	for i := 0; i < 6; i++ {
+		filePaths = append(filePaths, filepath.Join(dir, fmt.Sprintf("missing%d.txt", i)))
+	}
+
+	config := DefaultModifyConfig()
+	config.DryRun = true
+	config.MaxWorkers = 1
+	config.MaxErrors = 2
+
+	results := ModifyFiles(filePaths, detector.DefaultEmojiPatterns(), config, nil)
+	require.Len(t, results, 6)
+	for i, result := range results {
+		if i < 2 {
+			assert.NotErrorIs(t, result.Error, ErrTooManyErrors)
+		} else {
+			assert.ErrorIs(t, result.Error, ErrTooManyErrors)
+			assert.Equal(t, filePaths[i], result.FilePath)
+		}
+	}
+}
+
 // Benchmark tests for performance
 func BenchmarkModifyFile(b *testing.B) {
 	tmpDir := b.TempDir()
@@ -578,3 +806,23 @@ func ExampleModifyFile() {
 	}
 	// Output: Removed 2 emojis, modified: true
 }
+
+func TestReplaceMasked(t *testing.T) {
+	content := "ab😀cd🚀ef"
+	matches := []types.EmojiMatch{{Emoji: "😀", Start: 2, End: 6}, {Emoji: "🚀", Start: 8, End: 12}}
+
+	replaced, spans := replaceMasked(content, matches, []string{":)", ""}, nil)
+	assert.Equal(t, "ab:)cdef", replaced)
+	assert.Equal(t, []span{{2, 4}}, spans)
+
+	// Earlier spans move with the edits before them and are dropped when overlapped
+	next, spans := replaceMasked("xx:)yy😀", []types.EmojiMatch{{Emoji: "x", Start: 0, End: 1}, {Emoji: "😀", Start: 6, End: 10}},
+		[]string{"", "[e]"}, []span{{2, 4}})
+	assert.Equal(t, "x:)yy[e]", next)
+	assert.Equal(t, []span{{1, 3}, {5, 8}}, spans)
+
+	_, spans = replaceMasked("a:)b", []types.EmojiMatch{{Emoji: ":)", Start: 1, End: 3}}, []string{""}, []span{{1, 3}})
+	assert.Empty(t, spans)
+
+	assert.Len(t, outside(matches, []span{{2, 6}}), 1)
+}
@@ -2,13 +2,20 @@
 package processor
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/antimoji/antimoji/internal/core/detector"
 	"github.com/antimoji/antimoji/internal/infra/concurrency"
 	"github.com/antimoji/antimoji/internal/infra/fs"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
 	"github.com/antimoji/antimoji/internal/types"
 )
 
@@ -17,9 +24,57 @@ type ProcessingPipeline struct {
 	Config types.ProcessingConfig
 }
 
+// DetectionCache stores detection results keyed by content hash so that unchanged
+// files can skip detection. Implementations must be safe for concurrent use.
+type DetectionCache interface {
+	Get(contentHash string) (types.DetectionResult, bool)
+	Put(contentHash string, result types.DetectionResult)
+}
+
+// ProgressFunc is called as each file of a batch is finished, with its path and size in
+// bytes. Files are processed concurrently, so it must be safe for concurrent use.
+type ProgressFunc func(filePath string, size int64)
+
+// ErrFileTooLarge is the error of files skipped for exceeding the maximum file size.
+var ErrFileTooLarge = errors.New("file too large")
+
+// ErrExtractText is the error of rich documents whose text could not be extracted.
+var ErrExtractText = errors.New("failed to extract text")
+
 // ProcessFile processes a single file for emoji detection.
 // This is a pure function that does not modify files (scan mode only for now).
 func ProcessFile(filePath string, patterns types.EmojiPatterns, config types.ProcessingConfig) types.Result[types.ProcessResult] {
+	return ProcessFileWithCache(filePath, patterns, config, nil)
+}
+
+// ProcessFileWithCache processes a single file, reusing the cached detection result when
+// the file content is unchanged. A nil cache disables caching.
+func ProcessFileWithCache(filePath string, patterns types.EmojiPatterns, config types.ProcessingConfig, cache DetectionCache) types.Result[types.ProcessResult] {
+	ctx := ctxutil.WithFilePath(ctxutil.NewComponentContext("detect_file", "processor"), filePath)
+	logging.Debug(ctx, "Starting emoji detection")
+
+	result := processFile(filePath, patterns, config, cache)
+	if result.IsErr() {
+		logging.Debug(ctx, "Emoji detection failed", "error", result.Error())
+		return result
+	}
+	processed := result.Unwrap()
+	switch {
+	case processed.Error != nil:
+		logging.Debug(ctx, "Emoji detection failed", "error", processed.Error)
+	case processed.BinaryReason != "":
+		logging.Debug(ctx, "Skipping binary file", "reason", processed.BinaryReason)
+	default:
+		logging.Debug(ctx, "Emoji detection completed",
+			"emojis", processed.DetectionResult.TotalCount,
+			"bytes", processed.DetectionResult.ProcessedBytes,
+			"duration_ms", processed.DetectionResult.Duration.Milliseconds())
+	}
+	return result
+}
+
+// processFile detects the emojis of a single file for ProcessFileWithCache.
+func processFile(filePath string, patterns types.EmojiPatterns, config types.ProcessingConfig, cache DetectionCache) types.Result[types.ProcessResult] {
 	startTime := time.Now()
 
 	// Initialize result
@@ -39,23 +94,29 @@ func ProcessFile(filePath string, patterns types.EmojiPatterns, config types.Pro
 
 	// Check file size limit
 	if fileInfo.Size > config.MaxFileSize {
-		result.Error = errors.New("file too large")
+		result.Error = ErrFileTooLarge
 		return types.Ok(result)
 	}
 
+	// Rich documents are read through their registered extractor instead
+	extractor, isDocument := ExtractorFor(filePath)
+
 	// Check if it's a text file
-	if !fs.IsTextFile(filePath) {
-		// Skip binary files
-		result.DetectionResult = types.DetectionResult{
-			ProcessedBytes: fileInfo.Size,
-			Duration:       time.Since(startTime),
-			Success:        false,
+	if !isDocument {
+		if encoding, reason := fs.SniffFile(filePath, config.Sniff); encoding == fs.EncodingBinary {
+			// Skip binary files
+			result.BinaryReason = reason
+			result.DetectionResult = types.DetectionResult{
+				ProcessedBytes: fileInfo.Size,
+				Duration:       time.Since(startTime),
+				Success:        false,
+			}
+			return types.Ok(result)
 		}
-		return types.Ok(result)
 	}
 
 	// Read file content
-	contentResult := fs.ReadFile(filePath)
+	contentResult := fs.ReadFileWithRetry(filePath, config.Retry)
 	if contentResult.IsErr() {
 		result.Error = contentResult.Error()
 		return types.Ok(result)
@@ -63,77 +124,203 @@ func ProcessFile(filePath string, patterns types.EmojiPatterns, config types.Pro
 
 	content := contentResult.Unwrap()
 
-	// Filter patterns based on configuration
-	filteredPatterns := filterPatterns(patterns, config)
+	// Non-UTF-8 text is detected as UTF-8 and the matches mapped back to file offsets
+	decoded := fs.DecodedContent{Text: content, Encoding: fs.EncodingUTF8}
+	if !isDocument {
+		if text, err := fs.Decode(content, config.Sniff); err == nil {
+			decoded = text
+		}
+		if decoded.Encoding != fs.EncodingUTF8 {
+			result.Encoding = string(decoded.Encoding)
+		}
+	}
+
+	language := languageOf(config.Languages, filePath, decoded.Text)
+	exempt := exemptionFor(language, config.PreserveMarkdownCode, config.Structured)
+	filteredPatterns := withLanguage(FilterPatterns(patterns, config), language, config.EnableEscapes)
+
+	// Reuse the previous result for unchanged content
+	var contentHash string
+	if cache != nil {
+		sum := sha256.Sum256(content)
+		contentHash = hex.EncodeToString(sum[:]) + languageCacheKey(content, filteredPatterns, exempt)
+		if cached, ok := cache.Get(contentHash); ok {
+			cached.Duration = time.Since(startTime)
+			result.DetectionResult = cached
+			return types.Ok(result)
+		}
+	}
 
-	// Detect emojis
-	detectionResult := detector.DetectEmojis(content, filteredPatterns)
-	if detectionResult.IsErr() {
-		result.Error = detectionResult.Error()
-		return types.Ok(result)
+	text := decoded.Text
+	if isDocument {
+		extracted, err := extractor.Extract(content)
+		if err != nil {
+			result.Error = fmt.Errorf("%w: %w", ErrExtractText, err)
+			return types.Ok(result)
+		}
+		text = extracted
 	}
 
-	detection := detectionResult.Unwrap()
+	detection, err := detectText(text, decoded, filteredPatterns, exempt)
+	if err != nil {
+		result.Error = err
+		return types.Ok(result)
+	}
 	detection.Duration = time.Since(startTime)
 	result.DetectionResult = detection
 
+	if cache != nil {
+		cache.Put(contentHash, detection)
+	}
+
 	return types.Ok(result)
 }
 
+// DetectBytes detects the emojis of content in memory as ProcessFile detects those of
+// a file. filePath only selects the language of the content and may be empty; rich
+// documents are detected as they are, without extracting their text. Content that
+// looks binary has BinaryReason set.
+func DetectBytes(filePath string, content []byte, patterns types.EmojiPatterns, config types.ProcessingConfig) types.ProcessResult {
+	startTime := time.Now()
+	result := types.ProcessResult{FilePath: filePath}
+	if config.MaxFileSize > 0 && int64(len(content)) > config.MaxFileSize {
+		result.Error = ErrFileTooLarge
+		return result
+	}
+	if encoding, reason := fs.Sniff(content, config.Sniff); encoding == fs.EncodingBinary {
+		result.BinaryReason = reason
+		result.DetectionResult = types.DetectionResult{ProcessedBytes: int64(len(content))}
+		return result
+	}
+
+	decoded := fs.DecodedContent{Text: content, Encoding: fs.EncodingUTF8}
+	if text, err := fs.Decode(content, config.Sniff); err == nil {
+		decoded = text
+	}
+	if decoded.Encoding != fs.EncodingUTF8 {
+		result.Encoding = string(decoded.Encoding)
+	}
+
+	language := languageOf(config.Languages, filePath, decoded.Text)
+	exempt := exemptionFor(language, config.PreserveMarkdownCode, config.Structured)
+	filteredPatterns := withLanguage(FilterPatterns(patterns, config), language, config.EnableEscapes)
+	detection, err := detectText(decoded.Text, decoded, filteredPatterns, exempt)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	detection.Duration = time.Since(startTime)
+	result.DetectionResult = detection
+	return result
+}
+
+// detectText detects the emojis of text, the decoded or extracted text of content,
+// leaving out those exempt leaves alone, with offsets into the original content.
+func detectText(text []byte, content fs.DecodedContent, patterns types.EmojiPatterns, exempt exemption) (types.DetectionResult, error) {
+	detectionResult := detector.DetectEmojis(text, patterns)
+	if detectionResult.IsErr() {
+		return types.DetectionResult{}, detectionResult.Error()
+	}
+
+	detection := exempt.apply(string(text), detectionResult.Unwrap())
+	for i := range detection.Emojis {
+		detection.Emojis[i].Start = content.OriginalOffset(detection.Emojis[i].Start)
+		detection.Emojis[i].End = content.OriginalOffset(detection.Emojis[i].End)
+	}
+	return detection, nil
+}
+
+// DetectContent detects emojis in in-memory content using the patterns enabled by config.
+// It is used for content that does not live on disk, such as blobs from git history.
+func DetectContent(content []byte, patterns types.EmojiPatterns, config types.ProcessingConfig) types.Result[types.DetectionResult] {
+	return detector.DetectEmojis(content, FilterPatterns(patterns, config))
+}
+
 // ProcessFiles processes multiple files and returns results for all files.
 // Uses concurrent processing for improved performance with multiple files.
 func ProcessFiles(filePaths []string, patterns types.EmojiPatterns, config types.ProcessingConfig) []types.ProcessResult {
-	// Use concurrent processing for multiple files
-	if len(filePaths) > 1 {
-		return ProcessFilesConcurrently(filePaths, patterns, config, 0) // Auto-detect workers
-	}
+	return ProcessFilesWithCache(filePaths, patterns, config, nil)
+}
 
-	// Single file - use direct processing
-	results := make([]types.ProcessResult, 0, len(filePaths))
+// ProcessFilesWithCache processes multiple files, skipping detection for files whose
+// content has a cached result. A nil cache disables caching.
+func ProcessFilesWithCache(filePaths []string, patterns types.EmojiPatterns, config types.ProcessingConfig, cache DetectionCache) []types.ProcessResult {
+	return ProcessBatch(filePaths, patterns, config, BatchOptions{Cache: cache})
+}
 
-	for _, filePath := range filePaths {
-		processResult := ProcessFile(filePath, patterns, config)
-		if processResult.IsOk() {
-			results = append(results, processResult.Unwrap())
+// BatchOptions are the optional behaviours of ProcessBatch.
+type BatchOptions struct {
+	// Cache holds detection results by content; nil disables caching
+	Cache DetectionCache
+	// Progress is called as each file is finished; nil reports nothing
+	Progress ProgressFunc
+	// MaxErrors stops the batch once this many files failed, the files not started by
+	// then failing with ErrTooManyErrors; zero or less never stops
+	MaxErrors int
+}
+
+// ProcessBatch processes multiple files like ProcessFilesWithCache with the options of
+// opts.
+func ProcessBatch(filePaths []string, patterns types.EmojiPatterns, config types.ProcessingConfig, opts BatchOptions) []types.ProcessResult {
+	limit := newErrorLimit(opts.MaxErrors)
+	process := func(filePath string) types.Result[types.ProcessResult] {
+		var result types.Result[types.ProcessResult]
+		if limit.reached() {
+			result = types.Ok(types.ProcessResult{FilePath: filePath, Error: ErrTooManyErrors})
 		} else {
-			// This shouldn't happen with current implementation, but handle it
-			errorResult := types.ProcessResult{
-				FilePath: filePath,
-				Error:    processResult.Error(),
-				Modified: false,
+			result = ProcessFileWithCache(filePath, patterns, config, opts.Cache)
+			if result.IsErr() {
+				limit.record(result.Error())
+			} else {
+				limit.record(result.Unwrap().Error)
 			}
-			results = append(results, errorResult)
 		}
+		if opts.Progress != nil {
+			var size int64
+			if result.IsOk() {
+				size = result.Unwrap().DetectionResult.ProcessedBytes
+			}
+			opts.Progress(filePath, size)
+		}
+		return result
 	}
 
-	return results
+	// Use concurrent processing for multiple files
+	if len(filePaths) > 1 {
+		return processFilesConcurrently(filePaths, 0, process) // Auto-detect workers
+	}
+
+	// Single file - use direct processing
+	return processFilesSequentially(filePaths, process)
 }
 
 // ProcessFilesConcurrently processes multiple files using worker pool for better performance.
 func ProcessFilesConcurrently(filePaths []string, patterns types.EmojiPatterns, config types.ProcessingConfig, workerCount int) []types.ProcessResult {
+	return processFilesConcurrently(filePaths, workerCount, func(filePath string) types.Result[types.ProcessResult] {
+		return ProcessFile(filePath, patterns, config)
+	})
+}
+
+// processFilesConcurrently processes files with process using a worker pool.
+func processFilesConcurrently(filePaths []string, workerCount int, process func(string) types.Result[types.ProcessResult]) []types.ProcessResult {
 	if workerCount <= 0 {
 		workerCount = runtime.NumCPU()
 	}
 
 	// For small numbers of files, sequential might be faster due to overhead
 	if len(filePaths) < workerCount {
-		return processFilesSequentially(filePaths, patterns, config)
+		return processFilesSequentially(filePaths, process)
 	}
 
-	// Create processor function for concurrent execution
-	processor := func(filePath string) types.Result[types.ProcessResult] {
-		return ProcessFile(filePath, patterns, config)
-	}
-
-	return concurrency.ProcessFiles(filePaths, workerCount, processor)
+	return sortByPath(concurrency.ProcessFiles(filePaths, workerCount, process), processResultPath)
 }
 
 // processFilesSequentially processes files one by one (used as fallback).
-func processFilesSequentially(filePaths []string, patterns types.EmojiPatterns, config types.ProcessingConfig) []types.ProcessResult {
+func processFilesSequentially(filePaths []string, process func(string) types.Result[types.ProcessResult]) []types.ProcessResult {
 	results := make([]types.ProcessResult, 0, len(filePaths))
 
 	for _, filePath := range filePaths {
-		processResult := ProcessFile(filePath, patterns, config)
+		processResult := process(filePath)
 		if processResult.IsOk() {
 			results = append(results, processResult.Unwrap())
 		} else {
@@ -146,9 +333,22 @@ func processFilesSequentially(filePaths []string, patterns types.EmojiPatterns,
 		}
 	}
 
+	return sortByPath(results, processResultPath)
+}
+
+// sortByPath orders results by file path, so that output is the same whichever worker
+// finishes first. Results for the same path keep their order.
+func sortByPath[R any](results []R, path func(R) string) []R {
+	slices.SortStableFunc(results, func(a, b R) int {
+		return strings.Compare(path(a), path(b))
+	})
 	return results
 }
 
+func processResultPath(result types.ProcessResult) string {
+	return result.FilePath
+}
+
 // CreateProcessingPipeline creates a new processing pipeline with the given configuration.
 func CreateProcessingPipeline(config types.ProcessingConfig) *ProcessingPipeline {
 	return &ProcessingPipeline{
@@ -161,8 +361,8 @@ func (p *ProcessingPipeline) Process(filePaths []string, patterns types.EmojiPat
 	return ProcessFiles(filePaths, patterns, p.Config)
 }
 
-// filterPatterns filters emoji patterns based on processing configuration.
-func filterPatterns(patterns types.EmojiPatterns, config types.ProcessingConfig) types.EmojiPatterns {
+// FilterPatterns filters emoji patterns based on processing configuration.
+func FilterPatterns(patterns types.EmojiPatterns, config types.ProcessingConfig) types.EmojiPatterns {
 	filtered := types.EmojiPatterns{}
 
 	if config.EnableUnicode {
@@ -177,5 +377,26 @@ func filterPatterns(patterns types.EmojiPatterns, config types.ProcessingConfig)
 		filtered.CustomPatterns = patterns.CustomPatterns
 	}
 
+	if config.EnableShortcodes {
+		filtered.Shortcodes = patterns.Shortcodes
+	}
+
+	if config.EnableKaomoji {
+		filtered.KaomojiPatterns = patterns.KaomojiPatterns
+	}
+
+	if config.EnableDecorative {
+		filtered.DecorativeRanges = patterns.DecorativeRanges
+	}
+
+	if config.EnableEvasion {
+		filtered.EvasionRanges = patterns.EvasionRanges
+	}
+
+	if config.EnableHygiene {
+		filtered.HygieneRanges = patterns.HygieneRanges
+	}
+
+	filtered.MarkComments = config.MarkComments
 	return filtered
 }
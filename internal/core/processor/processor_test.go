@@ -1,9 +1,14 @@
 package processor
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/antimoji/antimoji/internal/core/detector"
@@ -219,6 +224,159 @@ func TestProcessFiles(t *testing.T) {
 	})
 }
 
+// mapCache is an in-memory DetectionCache for tests.
+type mapCache struct {
+	mu      sync.Mutex
+	entries map[string]types.DetectionResult
+	hits    int
+}
+
+func (c *mapCache) Get(hash string) (types.DetectionResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.entries[hash]
+	if ok {
+		c.hits++
+	}
+	return result, ok
+}
+
+func (c *mapCache) Put(hash string, result types.DetectionResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[hash] = result
+}
+
+func TestProcessFilesWithCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	unchanged := filepath.Join(tmpDir, "unchanged.txt")
+	changed := filepath.Join(tmpDir, "changed.txt")
+	assert.NoError(t, os.WriteFile(unchanged, []byte("Hello 😀"), 0644))
+	assert.NoError(t, os.WriteFile(changed, []byte("No emojis"), 0644))
+
+	patterns := detector.DefaultEmojiPatterns()
+	config := types.DefaultProcessingConfig()
+	cache := &mapCache{entries: make(map[string]types.DetectionResult)}
+
+	first := ProcessFilesWithCache([]string{unchanged, changed}, patterns, config, cache)
+	assert.Len(t, first, 2)
+	assert.Len(t, cache.entries, 2)
+	assert.Equal(t, 0, cache.hits)
+
+	assert.NoError(t, os.WriteFile(changed, []byte("Now 🚀🎉"), 0644))
+
+	second := ProcessFilesWithCache([]string{unchanged, changed}, patterns, config, cache)
+	assert.Equal(t, 1, cache.hits, "only the unchanged file is served from the cache")
+
+	counts := map[string]int{}
+	for _, result := range second {
+		counts[result.FilePath] = result.DetectionResult.TotalCount
+	}
+	assert.Equal(t, 1, counts[unchanged])
+	assert.Equal(t, 2, counts[changed])
+}
+
+func TestProcessBatch_Progress(t *testing.T) {
+	tmpDir := t.TempDir()
+	var filePaths []string
+	for i := 0; i < 3*runtime.NumCPU(); i++ {
+		filePath := filepath.Join(tmpDir, fmt.Sprintf("file%02d.txt", i))
+		assert.NoError(t, os.WriteFile(filePath, []byte("Hello 😀"), 0644))
+		filePaths = append(filePaths, filePath)
+	}
+
+	var mu sync.Mutex
+	sizes := map[string]int64{}
+	progress := func(filePath string, size int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes[filePath] = size
+	}
+
+	for _, paths := range [][]string{filePaths[:1], filePaths} {
+		results := ProcessBatch(paths, detector.DefaultEmojiPatterns(), types.DefaultProcessingConfig(), BatchOptions{Progress: progress})
+		assert.Len(t, results, len(paths))
+	}
+	assert.Len(t, sizes, len(filePaths), "every file is reported")
+	for filePath, size := range sizes {
+		assert.Equal(t, int64(len("Hello 😀")), size, filePath)
+	}
+}
+
+func TestProcessBatch_OrderedByPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	var filePaths []string
+	for i := 0; i < 4*runtime.NumCPU()+1; i++ {
+		filePath := filepath.Join(tmpDir, fmt.Sprintf("file%02d.txt", i))
+		assert.NoError(t, os.WriteFile(filePath, []byte(strings.Repeat("Hello 😀\n", i*100)), 0644))
+		filePaths = append(filePaths, filePath)
+	}
+	reversed := slices.Clone(filePaths)
+	slices.Reverse(reversed)
+
+	for _, paths := range [][]string{filePaths, reversed, reversed[:2]} {
+		results := ProcessBatch(paths, detector.DefaultEmojiPatterns(), types.DefaultProcessingConfig(), BatchOptions{})
+		var got []string
+		for _, result := range results {
+			got = append(got, result.FilePath)
+		}
+		want := slices.Clone(paths)
+		slices.Sort(want)
+		assert.Equal(t, want, got)
+	}
+
+	results := ProcessFilesConcurrently(reversed, detector.DefaultEmojiPatterns(), types.DefaultProcessingConfig(), 4)
+	assert.Equal(t, filePaths[0], results[0].FilePath)
+	assert.Equal(t, filePaths[len(filePaths)-1], results[len(results)-1].FilePath)
+}
+
+func TestProcessBatch_MaxErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	var filePaths []string
+	for i := 0; i < 5; i++ {
+		filePaths = append(filePaths, filepath.Join(tmpDir, fmt.Sprintf("missing%d.txt", i)))
+	}
+	present := filepath.Join(tmpDir, "present.txt")
+	assert.NoError(t, os.WriteFile(present, []byte("Hello 😀"), 0644))
+	filePaths = append(filePaths, present)
+
+	// One file at a time makes the stopping point deterministic
+	limit := newErrorLimit(2)
+	results := processFilesSequentially(filePaths, func(filePath string) types.Result[types.ProcessResult] {
+		if limit.reached() {
+			return types.Ok(types.ProcessResult{FilePath: filePath, Error: ErrTooManyErrors})
+		}
+		result := ProcessFile(filePath, detector.DefaultEmojiPatterns(), types.DefaultProcessingConfig())
+		limit.record(result.Unwrap().Error)
+		return result
+	})
+	assert.Error(t, results[1].Error)
+	assert.NotErrorIs(t, results[1].Error, ErrTooManyErrors)
+	for _, result := range results[2:] {
+		assert.ErrorIs(t, result.Error, ErrTooManyErrors, result.FilePath)
+	}
+
+	results = ProcessBatch(filePaths, detector.DefaultEmojiPatterns(), types.DefaultProcessingConfig(), BatchOptions{MaxErrors: 100})
+	for _, result := range results {
+		assert.NotErrorIs(t, result.Error, ErrTooManyErrors)
+	}
+}
+
+func TestErrorLimit(t *testing.T) {
+	var unlimited *errorLimit
+	unlimited.record(errors.New("failed"))
+	assert.False(t, unlimited.reached())
+	assert.Nil(t, newErrorLimit(0))
+
+	limit := newErrorLimit(2)
+	limit.record(nil)
+	limit.record(ErrFileTooLarge)
+	limit.record(errors.New("failed"))
+	assert.False(t, limit.reached(), "skipped files are not failures")
+	limit.record(errors.New("failed"))
+	assert.True(t, limit.reached())
+}
+
 func TestCreateProcessingPipeline(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -466,7 +624,9 @@ func BenchmarkProcessFilesConcurrent_vs_Sequential(b *testing.B) {
 		b.ReportAllocs()
 
 		for i := 0; i < b.N; i++ {
-			results := processFilesSequentially(filePaths, patterns, config)
+			results := processFilesSequentially(filePaths, func(filePath string) types.Result[types.ProcessResult] {
+				return ProcessFile(filePath, patterns, config)
+			})
 			if len(results) != len(filePaths) {
 				b.Fatal("unexpected number of results")
 			}
@@ -540,3 +700,23 @@ func ExampleProcessFile() {
 	}
 	// Output: Found 2 emojis
 }
+
+func TestDetectBytes(t *testing.T) {
+	config := types.DefaultProcessingConfig()
+	config.PreserveMarkdownCode = true
+
+	result := DetectBytes("notes.md", []byte("Ship 🚀 with `code 🎉`"), detector.DefaultEmojiPatterns(), config)
+	assert.NoError(t, result.Error)
+	if assert.Len(t, result.DetectionResult.Emojis, 1) {
+		assert.Equal(t, "🚀", result.DetectionResult.Emojis[0].Emoji)
+	}
+
+	plain := DetectBytes("", []byte("Ship 🚀 with `code 🎉`"), detector.DefaultEmojiPatterns(), config)
+	assert.Equal(t, 2, plain.DetectionResult.TotalCount)
+
+	binary := DetectBytes("logo.png", []byte{0, 0, 0, 0xF0, 0x9F, 0x9A, 0x80}, detector.DefaultEmojiPatterns(), config)
+	assert.NotEmpty(t, binary.BinaryReason)
+
+	config.MaxFileSize = 4
+	assert.ErrorIs(t, DetectBytes("a.txt", []byte("too long"), detector.DefaultEmojiPatterns(), config).Error, ErrFileTooLarge)
+}
@@ -0,0 +1,114 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"pgregory.net/rapid"
+)
+
+// fragments are the building blocks for generated inputs. They deliberately mix
+// plain text, multi-byte non-emoji text, emojis, modifiers and emoticon pieces so
+// that removals can bring new candidate sequences next to each other.
+var fragments = []string{
+	"a", "Z", "0", " ", "\n", "\t", "\r\n", "-", "_",
+	":", ";", "=", ">", ")", "(", "D", "P", "o", "O",
+	"é", "中", "ß", "€", "→",
+	"😀", "🚀", "🎉", "👍", "✨", "✅", "❌", "⚠", "🇺", "🇸",
+	"\U0001F3FB", "‍", "️",
+	":)", ":-(", ";)", ":rocket:", ":fire:", ":smile:",
+}
+
+// genContent draws arbitrary text built from fragments.
+func genContent() *rapid.Generator[string] {
+	return rapid.Custom(func(t *rapid.T) string {
+		parts := rapid.SliceOfN(rapid.SampledFrom(fragments), 0, 40).Draw(t, "parts")
+		return strings.Join(parts, "")
+	})
+}
+
+// cleanContent runs the same detect-and-remove pipeline as ModifyFile.
+func cleanContent(content string) string {
+	return CleanContent(content, detector.DefaultEmojiPatterns(), "")
+}
+
+func TestProperty_CleanIsIdempotent(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		content := genContent().Draw(t, "content")
+
+		once := cleanContent(content)
+		twice := cleanContent(once)
+		if once != twice {
+			t.Fatalf("clean is not idempotent:\ninput: %q\nonce:  %q\ntwice: %q", content, once, twice)
+		}
+	})
+}
+
+func TestProperty_CleanPreservesUTF8(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		content := genContent().Draw(t, "content")
+
+		cleaned := cleanContent(content)
+		if !utf8.ValidString(cleaned) {
+			t.Fatalf("clean produced invalid UTF-8:\ninput:  %q\noutput: %q", content, cleaned)
+		}
+	})
+}
+
+func TestProperty_RemoveOnlyTouchesMatches(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		content := genContent().Draw(t, "content")
+		replacement := rapid.SampledFrom([]string{"", "[X]", " "}).Draw(t, "replacement")
+
+		detection := detector.DetectEmojis([]byte(content), detector.DefaultEmojiPatterns()).Unwrap()
+		cleaned := RemoveEmojis(content, detection, replacement)
+
+		// Rebuild the expected output from the unmatched segments of the input
+		var expected strings.Builder
+		last := 0
+		for _, match := range detection.Emojis {
+			if match.Start < last {
+				t.Fatalf("overlapping matches in %q: %+v", content, detection.Emojis)
+			}
+			expected.WriteString(content[last:match.Start])
+			expected.WriteString(replacement)
+			last = match.End
+		}
+		expected.WriteString(content[last:])
+
+		if cleaned != expected.String() {
+			t.Fatalf("bytes outside matches were altered:\ninput:    %q\nexpected: %q\ngot:      %q", content, expected.String(), cleaned)
+		}
+	})
+}
+
+func TestProperty_ModifyFileMatchesCleanContent(t *testing.T) {
+	dir := t.TempDir()
+
+	rapid.Check(t, func(t *rapid.T) {
+		content := genContent().Draw(t, "content")
+		path := filepath.Join(dir, "property.txt")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		config := DefaultModifyConfig()
+		config.RespectAllowlist = false
+		result := ModifyFile(path, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+
+		written, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(written) != cleanContent(content) {
+			t.Fatalf("file clean differs from CleanContent:\ninput: %q\nfile:  %q\npure:  %q", content, written, cleanContent(content))
+		}
+	})
+}
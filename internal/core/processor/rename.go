@@ -0,0 +1,128 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// maxRenameSuffix bounds the search for a free name when the cleaned name is taken.
+const maxRenameSuffix = 1000
+
+// ErrNoNameLeft indicates that a name consists only of emojis, so stripping them
+// would leave nothing usable.
+var ErrNoNameLeft = errors.New("no name left after removing emojis")
+
+// RenameResult describes the rename of a single file or directory.
+type RenameResult struct {
+	From          string `json:"from"`
+	To            string `json:"to,omitempty"`
+	EmojisRemoved int    `json:"emojis_removed"`
+	Renamed       bool   `json:"renamed"`
+	Error         error  `json:"error,omitempty"`
+}
+
+// CleanName strips non-allowlisted emojis from a single path component and returns
+// the cleaned name and the number of emojis removed. Surrounding whitespace left
+// behind by the removal is trimmed.
+func CleanName(name string, patterns types.EmojiPatterns, emojiAllowlist *allowlist.Allowlist) (string, int) {
+	cleaned, removed := removeUntilStable(name, nil, patterns, func(string) string { return "" }, emojiAllowlist, nil, exemption{})
+	if removed == 0 {
+		return name, 0
+	}
+	return strings.TrimSpace(cleaned), removed
+}
+
+// RenamePaths strips emojis from the base names of the given files and directories.
+// Paths are renamed deepest first, so renaming a directory never invalidates a path
+// below it; To is therefore reported relative to the original parent directory.
+// When the cleaned name is already taken a numeric suffix is added ("notes-1.md").
+// confirm, if not nil, is asked before each rename. In dry-run mode nothing is
+// renamed but collisions are still resolved as if it were.
+func RenamePaths(paths []string, patterns types.EmojiPatterns, emojiAllowlist *allowlist.Allowlist,
+	dryRun bool, confirm func(from, to string) bool) []RenameResult {
+
+	ordered := append([]string(nil), paths...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return pathDepth(ordered[i]) > pathDepth(ordered[j])
+	})
+
+	claimed := make(map[string]bool)
+	results := make([]RenameResult, 0, len(ordered))
+	for _, path := range ordered {
+		dir, name := filepath.Split(path)
+		cleaned, removed := CleanName(name, patterns, emojiAllowlist)
+		if removed == 0 {
+			continue
+		}
+
+		result := RenameResult{From: path, EmojisRemoved: removed}
+		if cleaned == "" || cleaned == "." || cleaned == ".." ||
+			(strings.HasPrefix(cleaned, ".") && !strings.HasPrefix(name, ".")) {
+			result.Error = fmt.Errorf("%s: %w", path, ErrNoNameLeft)
+			results = append(results, result)
+			continue
+		}
+
+		target, err := freeName(dir, cleaned, claimed)
+		if err != nil {
+			result.Error = err
+			results = append(results, result)
+			continue
+		}
+		result.To = target
+
+		if confirm != nil && !confirm(path, target) {
+			results = append(results, result)
+			continue
+		}
+
+		if !dryRun {
+			if err := os.Rename(path, target); err != nil {
+				result.Error = fmt.Errorf("failed to rename %s: %w", path, err)
+				results = append(results, result)
+				continue
+			}
+		}
+
+		claimed[target] = true
+		result.Renamed = true
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// freeName returns a path in dir for name that neither exists nor has been claimed by
+// an earlier rename in the same run.
+func freeName(dir, name string, claimed map[string]bool) (string, error) {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if stem == "" {
+		// Dotfiles such as ".env" have no extension to preserve
+		stem, ext = name, ""
+	}
+
+	candidate := filepath.Join(dir, name)
+	for i := 1; i <= maxRenameSuffix; i++ {
+		if !claimed[candidate] {
+			if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+				return candidate, nil
+			}
+		}
+		candidate = filepath.Join(dir, fmt.Sprintf("%s-%d%s", stem, i, ext))
+	}
+
+	return "", fmt.Errorf("no free name for %s in %s", name, dir)
+}
+
+// pathDepth returns the number of path components in path.
+func pathDepth(path string) int {
+	return strings.Count(filepath.Clean(path), string(filepath.Separator))
+}
@@ -0,0 +1,112 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanName(t *testing.T) {
+	patterns := detector.DefaultEmojiPatterns()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		removed  int
+	}{
+		{"no emoji", "main.go", "main.go", 0},
+		{"leading emoji", "🚀 launch.md", "launch.md", 1},
+		{"embedded emoji", "notes🎉.txt", "notes.txt", 1},
+		{"only emoji", "🎉", "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaned, removed := CleanName(tt.input, patterns, nil)
+			assert.Equal(t, tt.expected, cleaned)
+			assert.Equal(t, tt.removed, removed)
+		})
+	}
+
+	t.Run("allowlisted emoji is kept", func(t *testing.T) {
+		emojiAllowlist := allowlist.NewAllowlist([]string{"✅"}).Unwrap()
+		cleaned, removed := CleanName("✅done🚀.md", patterns, emojiAllowlist)
+		assert.Equal(t, "✅done.md", cleaned)
+		assert.Equal(t, 1, removed)
+	})
+}
+
+func TestRenamePaths(t *testing.T) {
+	patterns := detector.DefaultEmojiPatterns()
+
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "docs🚀"), 0750))
+		for _, name := range []string{"docs🚀/a🎉.md", "docs🚀/a.md", "docs🚀/b🎉.md", "docs🚀/b🐛.md", "🎉.md"} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("x"), 0600))
+		}
+		return dir
+	}
+	paths := func(dir string) []string {
+		return []string{
+			filepath.Join(dir, "docs🚀"),
+			filepath.Join(dir, "docs🚀", "a🎉.md"),
+			filepath.Join(dir, "docs🚀", "a.md"),
+			filepath.Join(dir, "docs🚀", "b🎉.md"),
+			filepath.Join(dir, "docs🚀", "b🐛.md"),
+			filepath.Join(dir, "🎉.md"),
+		}
+	}
+
+	t.Run("renames deepest first with collision handling", func(t *testing.T) {
+		dir := setup(t)
+
+		results := RenamePaths(paths(dir), patterns, nil, false, nil)
+		require.Len(t, results, 5)
+
+		assert.FileExists(t, filepath.Join(dir, "docs", "a.md"))
+		assert.FileExists(t, filepath.Join(dir, "docs", "a-1.md"))
+		assert.FileExists(t, filepath.Join(dir, "docs", "b.md"))
+		assert.FileExists(t, filepath.Join(dir, "docs", "b-1.md"))
+		assert.NoDirExists(t, filepath.Join(dir, "docs🚀"))
+
+		// A name made only of emojis is reported and left alone
+		last := results[len(results)-1]
+		assert.Equal(t, filepath.Join(dir, "🎉.md"), last.From)
+		assert.ErrorIs(t, last.Error, ErrNoNameLeft)
+		assert.FileExists(t, filepath.Join(dir, "🎉.md"))
+	})
+
+	t.Run("dry run leaves the tree untouched", func(t *testing.T) {
+		dir := setup(t)
+
+		results := RenamePaths(paths(dir), patterns, nil, true, nil)
+		require.Len(t, results, 5)
+		assert.True(t, results[0].Renamed)
+		assert.Equal(t, filepath.Join(dir, "docs🚀", "a-1.md"), results[0].To)
+		assert.DirExists(t, filepath.Join(dir, "docs🚀"))
+		assert.NoDirExists(t, filepath.Join(dir, "docs"))
+	})
+
+	t.Run("declined renames are skipped", func(t *testing.T) {
+		dir := setup(t)
+
+		results := RenamePaths(paths(dir), patterns, nil, false, func(from, to string) bool {
+			return filepath.Base(from) != "docs🚀"
+		})
+		for _, result := range results {
+			if result.From == filepath.Join(dir, "docs🚀") {
+				assert.False(t, result.Renamed)
+				assert.NoError(t, result.Error)
+			}
+		}
+		assert.DirExists(t, filepath.Join(dir, "docs🚀"))
+		assert.FileExists(t, filepath.Join(dir, "docs🚀", "b-1.md"))
+	})
+}
@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/core/shortcode"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// normalizeShortcodes rewrites the shortcodes of content as the emojis they stand for,
+// or its Unicode emojis as their canonical shortcode, as mode asks. The regions exempt
+// selects, such as code in Markdown content, are left untouched. It returns the
+// rewritten content and the number of rewrites.
+func normalizeShortcodes(content string, patterns types.EmojiPatterns, mode types.ShortcodeNormalization, exempt exemption) (string, int) {
+	if mode == types.NormalizeNone {
+		return content, 0
+	}
+	detectionResult := detector.DetectEmojis([]byte(content), patterns)
+	if detectionResult.IsErr() {
+		return content, 0
+	}
+
+	matches := detectionResult.Unwrap().Emojis
+	matches = exempt.filter(content, matches)
+
+	selected := make([]types.EmojiMatch, 0, len(matches))
+	replacements := make([]string, 0, len(matches))
+	for _, match := range matches {
+		var replacement string
+		switch {
+		case mode == types.NormalizeToUnicode && isShortcode(match.Emoji):
+			// Shortcodes listed as custom patterns are rewritten too
+			replacement = patterns.Shortcodes[match.Emoji[1:len(match.Emoji)-1]]
+		case mode == types.NormalizeToShortcode && match.Category == types.CategoryUnicode:
+			replacement, _ = shortcode.For(match.Emoji)
+		}
+		if replacement != "" {
+			selected = append(selected, match)
+			replacements = append(replacements, replacement)
+		}
+	}
+	if len(selected) == 0 {
+		return content, 0
+	}
+	return ReplaceMatches(content, selected, replacements), len(selected)
+}
+
+// isShortcode reports whether text has the :name: form of a shortcode.
+func isShortcode(text string) bool {
+	return len(text) > 2 && strings.HasPrefix(text, ":") && strings.HasSuffix(text, ":")
+}
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifyFile_NormalizeShortcodes(t *testing.T) {
+	config := types.DefaultProcessingConfig()
+	config.EnableShortcodes = true
+	patterns := FilterPatterns(detector.DefaultEmojiPatterns(), config)
+
+	run := func(t *testing.T, content string, mode types.ShortcodeNormalization, allowed []string) (string, ModifyResult) {
+		path := filepath.Join(t.TempDir(), "README.md")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		modify := DefaultModifyConfig()
+		modify.NormalizeShortcodes = mode
+		modify.PreserveMarkdownCode = true
+		result := ModifyFile(path, patterns, modify, allowlist.NewAllowlist(allowed).Unwrap()).Unwrap()
+		require.NoError(t, result.Error)
+
+		cleaned, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(cleaned), result
+	}
+
+	t.Run("to unicode", func(t *testing.T) {
+		cleaned, result := run(t, "Ship :rocket: :tada: and `:zap:`\n", types.NormalizeToUnicode, []string{"🚀"})
+		assert.Equal(t, "Ship 🚀  and `:zap:`\n", cleaned)
+		assert.Equal(t, 2, result.EmojisNormalized)
+		assert.Equal(t, 1, result.EmojisRemoved)
+	})
+
+	t.Run("to shortcode", func(t *testing.T) {
+		cleaned, result := run(t, "Ship 🚀 ✅ 🎉 🧑‍💻\n", types.NormalizeToShortcode, []string{"🚀", "✅"})
+		assert.Equal(t, "Ship :rocket: :white_check_mark:  \n", cleaned)
+		assert.Equal(t, 3, result.EmojisNormalized)
+		assert.Equal(t, 2, result.EmojisRemoved)
+	})
+
+	t.Run("normalizing alone modifies the file", func(t *testing.T) {
+		cleaned, result := run(t, "Ship :rocket:\n", types.NormalizeToUnicode, []string{":rocket:"})
+		assert.Equal(t, "Ship 🚀\n", cleaned)
+		assert.True(t, result.Modified)
+		assert.Equal(t, 0, result.EmojisRemoved)
+	})
+
+	t.Run("without normalization shortcodes are removed", func(t *testing.T) {
+		cleaned, result := run(t, "Ship :rocket: 🚀\n", types.NormalizeNone, nil)
+		assert.Equal(t, "Ship  \n", cleaned)
+		assert.Equal(t, 0, result.EmojisNormalized)
+	})
+}
@@ -0,0 +1,343 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+)
+
+// exemption leaves the emojis in some regions of a file's content alone, such as the
+// code of Markdown files or the values of YAML files. The zero exemption leaves none.
+type exemption struct {
+	// key distinguishes detection results with the exemption in the cache
+	key string
+	// spans returns the exempt regions of content in order
+	spans func(content string) []codeSpan
+}
+
+// exemptionFor returns the exemption of files in language: Markdown code when
+// preserveMarkdown is set, and the keys or values of structured files as structured
+// allows.
+func exemptionFor(language types.Language, preserveMarkdown bool, structured types.StructuredPolicy) exemption {
+	if preserveMarkdown && language.CodeFences {
+		return exemption{key: "markdown", spans: markdownCode}
+	}
+	if structured.IsZero() {
+		return exemption{}
+	}
+	var parse func(content string) (keys, values []codeSpan, ok bool)
+	switch language.Name {
+	case "json":
+		parse = jsonSpans
+	case "yaml":
+		parse = yamlSpans
+	case "toml":
+		parse = tomlSpans
+	default:
+		return exemption{}
+	}
+
+	key := "structured"
+	if structured.AllowKeys {
+		key += "+keys"
+	}
+	if structured.AllowValues {
+		key += "+values"
+	}
+	return exemption{key: key, spans: func(content string) []codeSpan {
+		keys, values, ok := parse(content)
+		if !ok {
+			// Content that does not parse is checked as plain text
+			return nil
+		}
+		var spans []codeSpan
+		if structured.AllowKeys {
+			spans = append(spans, keys...)
+		}
+		if structured.AllowValues {
+			spans = append(spans, values...)
+		}
+		sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+		return spans
+	}}
+}
+
+// filter returns the matches outside the exempt regions of content. Match offsets must
+// be byte offsets into content.
+func (e exemption) filter(content string, matches []types.EmojiMatch) []types.EmojiMatch {
+	if e.spans == nil || len(matches) == 0 {
+		return matches
+	}
+	return outsideSpans(e.spans(content), matches)
+}
+
+// apply drops the matches inside the exempt regions of content, updating the counts of
+// detection.
+func (e exemption) apply(content string, detection types.DetectionResult) types.DetectionResult {
+	if e.spans == nil {
+		return detection
+	}
+	detection.Emojis = e.filter(content, detection.Emojis)
+	detection.TotalCount = len(detection.Emojis)
+	detection.Finalize()
+	return detection
+}
+
+// jsonSpans returns the object keys and string values of JSON content, quotes
+// included, or false when content is not valid JSON.
+func jsonSpans(content string) (keys, values []codeSpan, ok bool) {
+	if !json.Valid([]byte(content)) {
+		return nil, nil, false
+	}
+	for pos := 0; pos < len(content); {
+		if content[pos] != '"' {
+			pos++
+			continue
+		}
+		end := pos + 1
+		for content[end] != '"' {
+			if content[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		end++
+
+		// In valid JSON, only keys are followed by a colon
+		next := end
+		for next < len(content) && strings.IndexByte(" \t\r\n", content[next]) != -1 {
+			next++
+		}
+		if next < len(content) && content[next] == ':' {
+			keys = append(keys, codeSpan{pos, end})
+		} else {
+			values = append(values, codeSpan{pos, end})
+		}
+		pos = end
+	}
+	return keys, values, true
+}
+
+// tomlSpans returns the keys, table names and string values of TOML content, quotes
+// included, or false when content is not valid TOML.
+func tomlSpans(content string) (keys, values []codeSpan, ok bool) {
+	var parser unstable.Parser
+	parser.Reset([]byte(content))
+
+	var walk func(node *unstable.Node)
+	walk = func(node *unstable.Node) {
+		switch node.Kind {
+		case unstable.Key:
+			keys = append(keys, rawSpan(node.Raw))
+		case unstable.String:
+			values = append(values, rawSpan(node.Raw))
+		}
+		for children := node.Children(); children.Next(); {
+			walk(children.Node())
+		}
+	}
+	for parser.NextExpression() {
+		walk(parser.Expression())
+	}
+	if parser.Error() != nil {
+		return nil, nil, false
+	}
+	// The value of a key-value pair comes before its key
+	sort.Slice(keys, func(i, j int) bool { return keys[i].start < keys[j].start })
+	return keys, values, true
+}
+
+// rawSpan returns the region of a TOML node.
+func rawSpan(raw unstable.Range) codeSpan {
+	return codeSpan{int(raw.Offset), int(raw.Offset + raw.Length)}
+}
+
+// yamlSpans returns the scalar mapping keys and the other scalars of the documents of
+// YAML content, or false when content is not valid YAML. Block scalars span their
+// content lines only, so the comments of their headers stay checked.
+func yamlSpans(content string) (keys, values []codeSpan, ok bool) {
+	var lines []int
+	for pos := 0; pos != -1; {
+		lines = append(lines, pos)
+		next := strings.IndexByte(content[pos:], '\n')
+		if next == -1 {
+			break
+		}
+		pos += next + 1
+	}
+	offset := func(node *yaml.Node) int {
+		if node.Line < 1 || node.Line > len(lines) {
+			return -1
+		}
+		pos := lines[node.Line-1]
+		for column := 1; column < node.Column && pos < len(content); column++ {
+			_, size := utf8.DecodeRuneInString(content[pos:])
+			pos += size
+		}
+		return pos
+	}
+
+	var walk func(node *yaml.Node, key, flow bool)
+	walk = func(node *yaml.Node, key, flow bool) {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			if start := offset(node); start != -1 {
+				span := yamlScalar(content, start, node.Value, flow)
+				if key {
+					keys = append(keys, span)
+				} else {
+					values = append(values, span)
+				}
+			}
+		case yaml.MappingNode:
+			flow = flow || node.Style&yaml.FlowStyle != 0
+			for i, child := range node.Content {
+				walk(child, i%2 == 0, flow)
+			}
+		default:
+			flow = flow || node.Style&yaml.FlowStyle != 0
+			for _, child := range node.Content {
+				walk(child, false, flow)
+			}
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(content)))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, false
+		}
+		walk(&document, false, false)
+	}
+	return keys, values, true
+}
+
+// yamlScalar returns the region of the scalar with value at start in YAML content,
+// past its anchor and tag. Plain scalars end before a comment, a mapping colon, or in
+// flow collections, an entry separator.
+func yamlScalar(content string, start int, value string, flow bool) codeSpan {
+	// Node positions include the anchor and tag
+	for start < len(content) && (content[start] == '&' || content[start] == '!') {
+		for start < len(content) && strings.IndexByte(" \t\r\n", content[start]) == -1 {
+			start++
+		}
+		for start < len(content) && strings.IndexByte(" \t\r\n", content[start]) != -1 {
+			start++
+		}
+	}
+	if start >= len(content) {
+		return codeSpan{start, start}
+	}
+
+	switch content[start] {
+	case '"':
+		end := start + 1
+		for end < len(content) && content[end] != '"' {
+			if content[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		return codeSpan{start, min(end+1, len(content))}
+	case '\'':
+		end := start + 1
+		for end < len(content) {
+			if content[end] == '\'' {
+				if end+1 < len(content) && content[end+1] == '\'' {
+					end += 2
+					continue
+				}
+				break
+			}
+			end++
+		}
+		return codeSpan{start, min(end+1, len(content))}
+	case '|', '>':
+		return yamlBlock(content, start)
+	}
+
+	// Plain scalars may continue on the following lines, folded into value
+	span := codeSpan{start, start}
+	consumed := 0
+	for pos := start; pos < len(content); {
+		lineEnd := strings.IndexByte(content[pos:], '\n')
+		if lineEnd == -1 {
+			lineEnd = len(content)
+		} else {
+			lineEnd += pos
+		}
+		end := plainEnd(content[:lineEnd], pos, flow)
+		if trimmed := strings.TrimSpace(content[pos:end]); trimmed != "" {
+			span.end = pos + strings.Index(content[pos:end], trimmed) + len(trimmed)
+			consumed += len(trimmed) + 1
+		}
+		if consumed >= len(value) || end < lineEnd || lineEnd == len(content) {
+			break
+		}
+		pos = lineEnd + 1
+	}
+	return span
+}
+
+// plainEnd returns the end of the plain scalar text from pos in line.
+func plainEnd(line string, pos int, flow bool) int {
+	for i := pos; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '#' && i > pos && (line[i-1] == ' ' || line[i-1] == '\t'):
+			return i
+		case c == ':' && (i+1 == len(line) || strings.IndexByte(" \t\r", line[i+1]) != -1 ||
+			flow && strings.IndexByte(",[]{}", line[i+1]) != -1):
+			return i
+		case flow && strings.IndexByte(",[]{}", c) != -1:
+			return i
+		}
+	}
+	return len(line)
+}
+
+// yamlBlock returns the content lines of the literal or folded block scalar whose
+// header starts at start: the lines after the header indented at least as much as the
+// first non-blank one, without trailing blank lines.
+func yamlBlock(content string, start int) codeSpan {
+	header := strings.IndexByte(content[start:], '\n')
+	if header == -1 {
+		return codeSpan{len(content), len(content)}
+	}
+	first := start + header + 1
+	span := codeSpan{first, first}
+	indent := -1
+	for pos := first; pos < len(content); {
+		lineEnd := strings.IndexByte(content[pos:], '\n')
+		if lineEnd == -1 {
+			lineEnd = len(content)
+		} else {
+			lineEnd += pos
+		}
+		line := content[pos:lineEnd]
+		text := strings.TrimLeft(line, " ")
+		if strings.TrimSpace(text) != "" {
+			if indent == -1 {
+				indent = len(line) - len(text)
+			}
+			if len(line)-len(text) < indent {
+				break
+			}
+			span.end = pos + len(strings.TrimRight(line, " \t\r"))
+		}
+		pos = lineEnd + 1
+	}
+	return span
+}
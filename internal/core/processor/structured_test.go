@@ -0,0 +1,138 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredSpans(t *testing.T) {
+	regions := func(content string, spans []codeSpan) []string {
+		var text []string
+		for _, span := range spans {
+			text = append(text, content[span.start:span.end])
+		}
+		return text
+	}
+
+	tests := []struct {
+		name    string
+		parse   func(string) ([]codeSpan, []codeSpan, bool)
+		content string
+		keys    []string
+		values  []string
+	}{
+		{"json", jsonSpans, `{"a 🚀": "b \"🎉\"", "n": [1, "x"], "o": {"k" : true}}`,
+			[]string{`"a 🚀"`, `"n"`, `"o"`, `"k"`}, []string{`"b \"🎉\""`, `"x"`}},
+		{"yaml plain", yamlSpans, "é: value # note 🎉\nkey 🚀: two words\n",
+			[]string{"é", "key 🚀"}, []string{"value", "two words"}},
+		{"yaml quoted", yamlSpans, "\"a: b\": 'it''s 🎉'\nc: \"x \\\" y\"\n",
+			[]string{`"a: b"`, "c"}, []string{`'it''s 🎉'`, `"x \" y"`}},
+		{"yaml anchor and tag", yamlSpans, "a: &ref !!str 🎉\nb: *ref\n",
+			[]string{"a", "b"}, []string{"🎉"}},
+		{"yaml block", yamlSpans, "a: | # header 🚀\n  one 🎉\n\n  two\nb: c\n",
+			[]string{"a", "b"}, []string{"  one 🎉\n\n  two", "c"}},
+		{"invalid yaml", yamlSpans, "a: [x, {y: z}]\n- ", nil, nil},
+		{"yaml flow collections", yamlSpans, "a: [x 🎉, {y: z}]\n",
+			[]string{"a", "y"}, []string{"x 🎉", "z"}},
+		{"yaml multiline plain", yamlSpans, "a: one\n  two\nb: c\n",
+			[]string{"a", "b"}, []string{"one\n  two", "c"}},
+		{"yaml documents", yamlSpans, "a: 🎉\n---\nb: c\n",
+			[]string{"a", "b"}, []string{"🎉", "c"}},
+		{"toml", tomlSpans, "# 🎉\ntitle = \"🚀\"\n[\"server 🎉\".db]\nports = [1, 'x']\npoint = { \"k\" = \"v\" }\n",
+			[]string{"title", `"server 🎉"`, "db", "ports", "point", `"k"`}, []string{`"🚀"`, "'x'", `"v"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, values, ok := tt.parse(tt.content)
+			if tt.keys == nil && tt.values == nil {
+				assert.False(t, ok, "invalid content does not parse")
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.keys, regions(tt.content, keys))
+			assert.Equal(t, tt.values, regions(tt.content, values))
+		})
+	}
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, _, ok := jsonSpans(`{"a": }`)
+		assert.False(t, ok)
+	})
+	t.Run("invalid toml", func(t *testing.T) {
+		_, _, ok := tomlSpans("a = \n")
+		assert.False(t, ok)
+	})
+}
+
+func TestProcessFile_Structured(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.yaml": "# comment 🔥\ngreeting: Hello 👋\nrocket🚀: true\n",
+		"config.json": `{"greeting": "Hello 👋", "rocket🚀": true}`,
+		"config.toml": "# comment 🔥\ngreeting = \"Hello 👋\"\n\"rocket🚀\" = true\n",
+		"broken.yaml": "greeting: [Hello 👋\nrocket🚀: true\n",
+		"notes.txt":   "greeting: Hello 👋\nrocket🚀: true\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	detect := func(name string, structured types.StructuredPolicy) []string {
+		config := types.DefaultProcessingConfig()
+		config.Structured = structured
+		result := ProcessFile(filepath.Join(dir, name), detector.DefaultEmojiPatterns(), config).Unwrap()
+		require.NoError(t, result.Error)
+		var emojis []string
+		for _, match := range result.DetectionResult.Emojis {
+			emojis = append(emojis, match.Emoji)
+		}
+		return emojis
+	}
+
+	values := types.StructuredPolicy{AllowValues: true}
+	assert.Equal(t, []string{"🔥", "🚀"}, detect("config.yaml", values))
+	assert.Equal(t, []string{"🚀"}, detect("config.json", values))
+	assert.Equal(t, []string{"🔥", "🚀"}, detect("config.toml", values))
+	assert.Equal(t, []string{"🔥", "👋"}, detect("config.yaml", types.StructuredPolicy{AllowKeys: true}))
+	assert.Equal(t, []string{"👋", "🚀"}, detect("broken.yaml", values), "unparsable files are plain text")
+	assert.Equal(t, []string{"👋", "🚀"}, detect("notes.txt", values), "other files are plain text")
+	assert.Equal(t, []string{"🔥", "👋", "🚀"}, detect("config.yaml", types.StructuredPolicy{}))
+
+	t.Run("cache keeps policies apart", func(t *testing.T) {
+		cache := &mapCache{entries: map[string]types.DetectionResult{}}
+		config := types.DefaultProcessingConfig()
+		path := filepath.Join(dir, "config.yaml")
+		all := ProcessFileWithCache(path, detector.DefaultEmojiPatterns(), config, cache).Unwrap()
+		config.Structured = values
+		allowed := ProcessFileWithCache(path, detector.DefaultEmojiPatterns(), config, cache).Unwrap()
+		assert.Equal(t, 3, all.DetectionResult.TotalCount)
+		assert.Equal(t, 2, allowed.DetectionResult.TotalCount)
+		assert.Zero(t, cache.hits)
+	})
+}
+
+func TestModifyFile_Structured(t *testing.T) {
+	content := "greeting: Hello 👋 # wave 🔥\nrocket🚀: true\n"
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config := DefaultModifyConfig()
+	config.Structured = types.StructuredPolicy{AllowValues: true}
+	result := ModifyFile(path, detector.DefaultEmojiPatterns(), config, nil).Unwrap()
+	require.NoError(t, result.Error)
+	assert.Equal(t, 2, result.EmojisRemoved)
+
+	cleaned, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "greeting: Hello 👋 # wave \nrocket: true\n", string(cleaned))
+
+	filtered, filterResult := CleanBytes("config.yml", []byte(content), detector.DefaultEmojiPatterns(), config, nil)
+	require.NoError(t, filterResult.Error)
+	assert.Equal(t, string(cleaned), string(filtered))
+}
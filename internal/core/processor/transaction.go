@@ -0,0 +1,135 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/antimoji/antimoji/internal/observability/logging"
+)
+
+// ErrRolledBack is the error of the files an Atomic batch did not write because another
+// file failed.
+var ErrRolledBack = errors.New("not written: the atomic clean was rolled back")
+
+// commitStaged swaps the staged contents of an Atomic batch into place once every file
+// was processed, or removes them when a file failed. If a swap fails, the files
+// swapped already get their original content back, so no file of the batch changes.
+func commitStaged(ctx context.Context, results []ModifyResult) {
+	for _, result := range results {
+		if result.Error != nil && !errors.Is(result.Error, ErrFileTooLarge) {
+			logging.Debug(ctx, "Atomic batch rolled back", "failed_file", result.FilePath, "error", result.Error)
+			rollBack(results)
+			return
+		}
+	}
+
+	// The original of each swapped file is kept until the whole batch is swapped
+	var swapped []swap
+	for i := range results {
+		if results[i].staged == "" {
+			continue
+		}
+		done, err := swapStaged(results[i].FilePath, results[i].staged, results[i].inPlace)
+		if err != nil {
+			logging.Debug(ctx, "Atomic batch rolled back", "failed_file", results[i].FilePath, "error", err)
+			for _, s := range swapped {
+				s.undo()
+			}
+			rollBack(results)
+			results[i].Error = fmt.Errorf("failed to write file: %w", err)
+			return
+		}
+		swapped = append(swapped, done)
+		results[i].staged = ""
+	}
+	for _, s := range swapped {
+		_ = os.Remove(s.hold)
+	}
+}
+
+// swap is a staged file swapped into place, with the original content held aside.
+type swap struct {
+	path    string
+	hold    string
+	inPlace bool
+}
+
+// undo puts the original content back in place.
+func (s swap) undo() {
+	if !s.inPlace {
+		_ = os.Rename(s.hold, s.path)
+		return
+	}
+	if content, err := os.ReadFile(s.hold); err == nil {
+		_ = writeInPlace(s.path, content)
+	}
+	_ = os.Remove(s.hold)
+}
+
+// swapStaged puts the staged content in place of the file at path, holding the
+// original aside. The staged file replaces the file, unless inPlace, where its content
+// overwrites the file's own so hard links keep sharing it.
+func swapStaged(path, staged string, inPlace bool) (swap, error) {
+	hold, err := os.CreateTemp(filepath.Dir(path), ".antimoji-orig-*")
+	if err != nil {
+		return swap{}, err
+	}
+	s := swap{path: path, hold: hold.Name(), inPlace: inPlace}
+	_ = hold.Close()
+
+	if inPlace {
+		original, err := os.ReadFile(path) // #nosec G304 - filepath is validated by caller
+		if err == nil {
+			err = os.WriteFile(s.hold, original, 0600)
+		}
+		var content []byte
+		if err == nil {
+			content, err = os.ReadFile(staged) // #nosec G304 - staged by writeTemp
+		}
+		if err != nil {
+			_ = os.Remove(s.hold)
+			return swap{}, err
+		}
+		if err := writeInPlace(path, content); err != nil {
+			_ = writeInPlace(path, original)
+			_ = os.Remove(s.hold)
+			return swap{}, err
+		}
+		_ = os.Remove(staged)
+		return s, nil
+	}
+
+	if err := os.Rename(path, s.hold); err != nil {
+		_ = os.Remove(s.hold)
+		return swap{}, err
+	}
+	if err := os.Rename(staged, path); err != nil {
+		s.undo()
+		return swap{}, err
+	}
+	return s, nil
+}
+
+// rollBack discards the staged contents and backups of results. The files that would
+// have been modified, and those not processed, fail with ErrRolledBack.
+func rollBack(results []ModifyResult) {
+	for i := range results {
+		result := &results[i]
+		if result.staged != "" {
+			_ = os.Remove(result.staged)
+			result.staged = ""
+		}
+		if result.BackupPath != "" {
+			_ = os.Remove(result.BackupPath)
+			result.BackupPath = ""
+		}
+		if (result.Error == nil && result.Modified) || errors.Is(result.Error, ErrTooManyErrors) {
+			result.Error = ErrRolledBack
+			result.Success = false
+			result.Modified = false
+		}
+	}
+}
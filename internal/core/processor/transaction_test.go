@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifyFiles_Atomic(t *testing.T) {
+	setup := func(t *testing.T) (string, []string) {
+		dir := t.TempDir()
+		var paths []string
+		for _, name := range []string{"a.go", "b.go", "plain.go"} {
+			content := "// launch 🚀\n"
+			if name == "plain.go" {
+				content = "// plain\n"
+			}
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			paths = append(paths, path)
+		}
+		return dir, paths
+	}
+	entries := func(t *testing.T, dir string) []string {
+		found, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		names := make([]string, len(found))
+		for i, entry := range found {
+			names[i] = entry.Name()
+		}
+		return names
+	}
+	config := DefaultModifyConfig()
+	config.Atomic = true
+
+	t.Run("writes every file once all succeeded", func(t *testing.T) {
+		dir, paths := setup(t)
+		results := ModifyFiles(paths, detector.DefaultEmojiPatterns(), config, nil)
+
+		for _, result := range results {
+			require.NoError(t, result.Error)
+			assert.Empty(t, result.staged)
+		}
+		content, err := os.ReadFile(paths[1])
+		require.NoError(t, err)
+		assert.Equal(t, "// launch \n", string(content))
+		assert.ElementsMatch(t, []string{"a.go", "b.go", "plain.go"}, entries(t, dir))
+	})
+
+	t.Run("writes nothing when a file fails", func(t *testing.T) {
+		dir, paths := setup(t)
+		backups := config
+		backups.CreateBackup = true
+		missing := filepath.Join(dir, "missing.go")
+		results := ModifyFiles(append(paths, missing), detector.DefaultEmojiPatterns(), backups, nil)
+
+		// Results are ordered by path: a.go, b.go, missing.go, plain.go
+		assert.ErrorIs(t, results[0].Error, ErrRolledBack)
+		assert.ErrorIs(t, results[1].Error, ErrRolledBack)
+		assert.False(t, results[0].Modified)
+		assert.Error(t, results[2].Error)
+		assert.NotErrorIs(t, results[2].Error, ErrRolledBack)
+		assert.NoError(t, results[3].Error, "files without emojis had nothing to write")
+
+		for _, path := range paths[:2] {
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "// launch 🚀\n", string(content))
+		}
+		// No staged file or backup is left behind
+		assert.ElementsMatch(t, []string{"a.go", "b.go", "plain.go"}, entries(t, dir))
+	})
+
+	t.Run("dry run stages nothing", func(t *testing.T) {
+		dir, paths := setup(t)
+		dryRun := config
+		dryRun.DryRun = true
+		results := ModifyFiles(paths, detector.DefaultEmojiPatterns(), dryRun, nil)
+
+		assert.True(t, results[0].Modified)
+		assert.ElementsMatch(t, []string{"a.go", "b.go", "plain.go"}, entries(t, dir))
+	})
+}
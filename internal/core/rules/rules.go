@@ -0,0 +1,89 @@
+// Package rules evaluates the custom rules of profiles: CEL expressions over each
+// finding and the file holding it, for pass/fail logic the built-in budgets do not
+// express, such as "no emojis outside comments in the payments team's files".
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+// Finding is a violation as a rule sees it, the variable finding.
+type Finding struct {
+	Emoji    string `cel:"emoji"`
+	Category string `cel:"category"`
+	// Severity is error or warn
+	Severity string `cel:"severity"`
+	Line     int    `cel:"line"`
+	Column   int    `cel:"column"`
+	// InComment is set for findings inside a comment of the file's language
+	InComment bool `cel:"in_comment"`
+}
+
+// File is the file holding a finding, the variable file.
+type File struct {
+	// Path is slash-separated and relative to the working directory when inside it
+	Path string `cel:"path"`
+	// Name is the last element of Path, Dir the others and Ext the extension of Name
+	Name string `cel:"name"`
+	Dir  string `cel:"dir"`
+	Ext  string `cel:"ext"`
+	// Language is the language of the file, e.g. go, or "" when unknown
+	Language string `cel:"language"`
+	// Owners are the CODEOWNERS owners of the file
+	Owners []string `cel:"owners"`
+	// Findings is the number of violations in the file
+	Findings int `cel:"findings"`
+}
+
+// env declares the variables rules are evaluated with.
+var env = func() *cel.Env {
+	env, err := cel.NewEnv(
+		ext.NativeTypes(reflect.TypeOf(&Finding{}), reflect.TypeOf(&File{}), ext.ParseStructTags(true)),
+		cel.Variable("finding", cel.ObjectType("rules.Finding")),
+		cel.Variable("file", cel.ObjectType("rules.File")),
+		ext.Strings(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return env
+}()
+
+// Rule is a compiled rule expression.
+type Rule struct {
+	program cel.Program
+}
+
+// Compile compiles expression, which must evaluate to a bool, into a rule.
+func Compile(expression string) (*Rule, error) {
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression is a %s, not a bool", ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &Rule{program: program}, nil
+}
+
+// Matches reports whether the rule matches finding in file.
+func (r *Rule) Matches(finding Finding, file File) (bool, error) {
+	value, _, err := r.program.Eval(map[string]any{"finding": finding, "file": file})
+	if err != nil {
+		return false, err
+	}
+	matched, ok := value.Value().(bool)
+	if !ok {
+		return false, errors.New("expression did not evaluate to a bool")
+	}
+	return matched, nil
+}
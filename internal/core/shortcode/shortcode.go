@@ -0,0 +1,538 @@
+// Package shortcode provides the bundled table of GitHub and GitLab emoji shortcodes,
+// such as :rocket: and :tada:, and the emojis they stand for.
+package shortcode
+
+import (
+	"slices"
+	"strings"
+)
+
+// table lists the GitHub and GitLab emoji shortcodes (without colons) and the
+// emojis they render as. An emoji's first name is its canonical shortcode; later
+// names are aliases.
+var table = []struct {
+	name  string
+	emoji string
+}{
+	// Smileys
+	{"grinning", "😀"}, {"smiley", "😃"}, {"smile", "😄"}, {"grin", "😁"},
+	{"laughing", "😆"}, {"satisfied", "😆"}, {"sweat_smile", "😅"},
+	{"rofl", "🤣"}, {"joy", "😂"}, {"slightly_smiling_face", "🙂"},
+	{"upside_down_face", "🙃"}, {"wink", "😉"}, {"blush", "😊"},
+	{"innocent", "😇"}, {"smiling_face_with_three_hearts", "🥰"},
+	{"heart_eyes", "😍"}, {"star_struck", "🤩"}, {"kissing_heart", "😘"},
+	{"kissing", "😗"}, {"relaxed", "☺️"}, {"kissing_closed_eyes", "😚"},
+	{"kissing_smiling_eyes", "😙"}, {"yum", "😋"}, {"stuck_out_tongue", "😛"},
+	{"stuck_out_tongue_winking_eye", "😜"}, {"zany_face", "🤪"},
+	{"stuck_out_tongue_closed_eyes", "😝"}, {"money_mouth_face", "🤑"},
+	{"hugs", "🤗"}, {"hand_over_mouth", "🤭"}, {"shushing_face", "🤫"},
+	{"thinking", "🤔"}, {"zipper_mouth_face", "🤐"}, {"raised_eyebrow", "🤨"},
+	{"neutral_face", "😐"}, {"expressionless", "😑"}, {"no_mouth", "😶"},
+	{"smirk", "😏"}, {"unamused", "😒"}, {"roll_eyes", "🙄"},
+	{"grimacing", "😬"}, {"lying_face", "🤥"}, {"relieved", "😌"},
+	{"pensive", "😔"}, {"sleepy", "😪"}, {"drooling_face", "🤤"},
+	{"sleeping", "😴"}, {"mask", "😷"}, {"face_with_thermometer", "🤒"},
+	{"face_with_head_bandage", "🤕"}, {"nauseated_face", "🤢"},
+	{"vomiting_face", "🤮"}, {"sneezing_face", "🤧"}, {"hot_face", "🥵"},
+	{"cold_face", "🥶"}, {"woozy_face", "🥴"}, {"dizzy_face", "😵"},
+	{"exploding_head", "🤯"}, {"cowboy_hat_face", "🤠"},
+	{"partying_face", "🥳"}, {"sunglasses", "😎"}, {"nerd_face", "🤓"},
+	{"monocle_face", "🧐"}, {"confused", "😕"}, {"worried", "😟"},
+	{"slightly_frowning_face", "🙁"}, {"frowning_face", "☹️"},
+	{"open_mouth", "😮"}, {"hushed", "😯"}, {"astonished", "😲"},
+	{"flushed", "😳"}, {"pleading_face", "🥺"}, {"frowning", "😦"},
+	{"anguished", "😧"}, {"fearful", "😨"}, {"cold_sweat", "😰"},
+	{"disappointed_relieved", "😥"}, {"cry", "😢"}, {"sob", "😭"},
+	{"scream", "😱"}, {"confounded", "😖"}, {"persevere", "😣"},
+	{"disappointed", "😞"}, {"sweat", "😓"}, {"weary", "😩"},
+	{"tired_face", "😫"}, {"yawning_face", "🥱"}, {"triumph", "😤"},
+	{"rage", "😡"}, {"pout", "😡"}, {"angry", "😠"}, {"cursing_face", "🤬"},
+	{"smiling_imp", "😈"}, {"imp", "👿"}, {"skull", "💀"},
+	{"skull_and_crossbones", "☠️"}, {"hankey", "💩"}, {"poop", "💩"},
+	{"shit", "💩"}, {"clown_face", "🤡"}, {"japanese_ogre", "👹"},
+	{"japanese_goblin", "👺"}, {"ghost", "👻"}, {"alien", "👽"},
+	{"space_invader", "👾"}, {"robot", "🤖"}, {"smiley_cat", "😺"},
+	{"smile_cat", "😸"}, {"joy_cat", "😹"}, {"heart_eyes_cat", "😻"},
+	{"smirk_cat", "😼"}, {"kissing_cat", "😽"}, {"scream_cat", "🙀"},
+	{"crying_cat_face", "😿"}, {"pouting_cat", "😾"},
+	{"see_no_evil", "🙈"}, {"hear_no_evil", "🙉"}, {"speak_no_evil", "🙊"},
+
+	// Hearts and marks
+	{"kiss", "💋"}, {"love_letter", "💌"}, {"cupid", "💘"},
+	{"gift_heart", "💝"}, {"sparkling_heart", "💖"}, {"heartpulse", "💗"},
+	{"heartbeat", "💓"}, {"revolving_hearts", "💞"}, {"two_hearts", "💕"},
+	{"heart_decoration", "💟"}, {"heavy_heart_exclamation", "❣️"},
+	{"broken_heart", "💔"}, {"heart", "❤️"}, {"orange_heart", "🧡"},
+	{"yellow_heart", "💛"}, {"green_heart", "💚"}, {"blue_heart", "💙"},
+	{"purple_heart", "💜"}, {"brown_heart", "🤎"}, {"black_heart", "🖤"},
+	{"white_heart", "🤍"}, {"100", "💯"}, {"anger", "💢"}, {"boom", "💥"},
+	{"collision", "💥"}, {"dizzy", "💫"}, {"sweat_drops", "💦"},
+	{"dash", "💨"}, {"hole", "🕳️"}, {"bomb", "💣"},
+	{"speech_balloon", "💬"}, {"left_speech_bubble", "🗨️"},
+	{"right_anger_bubble", "🗯️"}, {"thought_balloon", "💭"}, {"zzz", "💤"},
+
+	// Hands and people
+	{"wave", "👋"}, {"raised_back_of_hand", "🤚"},
+	{"raised_hand_with_fingers_splayed", "🖐️"}, {"raised_hand", "✋"},
+	{"hand", "✋"}, {"vulcan_salute", "🖖"}, {"ok_hand", "👌"},
+	{"pinching_hand", "🤏"}, {"v", "✌️"}, {"crossed_fingers", "🤞"},
+	{"love_you_gesture", "🤟"}, {"metal", "🤘"}, {"call_me_hand", "🤙"},
+	{"point_left", "👈"}, {"point_right", "👉"}, {"point_up_2", "👆"},
+	{"middle_finger", "🖕"}, {"fu", "🖕"}, {"point_down", "👇"},
+	{"point_up", "☝️"}, {"+1", "👍"}, {"thumbsup", "👍"}, {"-1", "👎"},
+	{"thumbsdown", "👎"}, {"fist_raised", "✊"}, {"fist", "✊"},
+	{"fist_oncoming", "👊"}, {"facepunch", "👊"}, {"punch", "👊"},
+	{"fist_left", "🤛"}, {"fist_right", "🤜"}, {"clap", "👏"},
+	{"raised_hands", "🙌"}, {"open_hands", "👐"}, {"palms_up_together", "🤲"},
+	{"handshake", "🤝"}, {"pray", "🙏"}, {"writing_hand", "✍️"},
+	{"nail_care", "💅"}, {"selfie", "🤳"}, {"muscle", "💪"},
+	{"brain", "🧠"}, {"eyes", "👀"}, {"eye", "👁️"}, {"tongue", "👅"},
+	{"lips", "👄"}, {"baby", "👶"}, {"child", "🧒"}, {"boy", "👦"},
+	{"girl", "👧"}, {"adult", "🧑"}, {"man", "👨"}, {"woman", "👩"},
+	{"older_adult", "🧓"}, {"older_man", "👴"}, {"older_woman", "👵"},
+	{"person_frowning", "🙍"}, {"pouting_face", "🙎"},
+	{"no_good", "🙅"}, {"ok_person", "🙆"}, {"tipping_hand_person", "💁"},
+	{"raising_hand", "🙋"}, {"bow", "🙇"}, {"facepalm", "🤦"},
+	{"shrug", "🤷"}, {"cop", "👮"}, {"detective", "🕵️"}, {"guard", "💂"},
+	{"construction_worker", "👷"}, {"prince", "🤴"}, {"princess", "👸"},
+	{"angel", "👼"}, {"santa", "🎅"}, {"superhero", "🦸"}, {"supervillain", "🦹"},
+	{"mage", "🧙"}, {"fairy", "🧚"}, {"vampire", "🧛"}, {"zombie", "🧟"},
+	{"walking", "🚶"}, {"runner", "🏃"}, {"running", "🏃"}, {"dancer", "💃"},
+	{"man_dancing", "🕺"}, {"dancers", "👯"}, {"couple", "👫"},
+	{"family", "👪"}, {"bust_in_silhouette", "👤"},
+	{"busts_in_silhouette", "👥"}, {"footprints", "👣"},
+
+	// Animals and nature
+	{"monkey_face", "🐵"}, {"monkey", "🐒"}, {"dog", "🐶"}, {"dog2", "🐕"},
+	{"poodle", "🐩"}, {"wolf", "🐺"}, {"fox_face", "🦊"}, {"cat", "🐱"},
+	{"cat2", "🐈"}, {"lion", "🦁"}, {"tiger", "🐯"}, {"horse", "🐴"},
+	{"racehorse", "🐎"}, {"unicorn", "🦄"}, {"zebra", "🦓"}, {"cow", "🐮"},
+	{"ox", "🐂"}, {"pig", "🐷"}, {"pig_nose", "🐽"}, {"sheep", "🐑"},
+	{"goat", "🐐"}, {"camel", "🐫"}, {"giraffe", "🦒"}, {"elephant", "🐘"},
+	{"mouse", "🐭"}, {"rat", "🐀"}, {"hamster", "🐹"}, {"rabbit", "🐰"},
+	{"hedgehog", "🦔"}, {"bat", "🦇"}, {"bear", "🐻"}, {"koala", "🐨"},
+	{"panda_face", "🐼"}, {"feet", "🐾"}, {"paw_prints", "🐾"},
+	{"turkey", "🦃"}, {"chicken", "🐔"}, {"rooster", "🐓"},
+	{"hatching_chick", "🐣"}, {"baby_chick", "🐤"}, {"bird", "🐦"},
+	{"penguin", "🐧"}, {"dove", "🕊️"}, {"eagle", "🦅"}, {"duck", "🦆"},
+	{"owl", "🦉"}, {"parrot", "🦜"}, {"frog", "🐸"}, {"crocodile", "🐊"},
+	{"turtle", "🐢"}, {"lizard", "🦎"}, {"snake", "🐍"}, {"dragon_face", "🐲"},
+	{"dragon", "🐉"}, {"sauropod", "🦕"}, {"t-rex", "🦖"}, {"whale", "🐳"},
+	{"whale2", "🐋"}, {"dolphin", "🐬"}, {"fish", "🐟"},
+	{"tropical_fish", "🐠"}, {"blowfish", "🐡"}, {"shark", "🦈"},
+	{"octopus", "🐙"}, {"shell", "🐚"}, {"snail", "🐌"}, {"butterfly", "🦋"},
+	{"bug", "🐛"}, {"ant", "🐜"}, {"bee", "🐝"}, {"honeybee", "🐝"},
+	{"lady_beetle", "🐞"}, {"cricket", "🦗"},
+	{"spider", "🕷️"}, {"spider_web", "🕸️"}, {"scorpion", "🦂"},
+	{"mosquito", "🦟"}, {"microbe", "🦠"}, {"bouquet", "💐"},
+	{"cherry_blossom", "🌸"}, {"rosette", "🏵️"}, {"rose", "🌹"},
+	{"wilted_flower", "🥀"}, {"hibiscus", "🌺"}, {"sunflower", "🌻"},
+	{"blossom", "🌼"}, {"tulip", "🌷"}, {"seedling", "🌱"},
+	{"evergreen_tree", "🌲"}, {"deciduous_tree", "🌳"}, {"palm_tree", "🌴"},
+	{"cactus", "🌵"}, {"ear_of_rice", "🌾"}, {"herb", "🌿"},
+	{"shamrock", "☘️"}, {"four_leaf_clover", "🍀"}, {"maple_leaf", "🍁"},
+	{"fallen_leaf", "🍂"}, {"leaves", "🍃"}, {"mushroom", "🍄"},
+
+	// Food and drink
+	{"grapes", "🍇"}, {"melon", "🍈"}, {"watermelon", "🍉"},
+	{"tangerine", "🍊"}, {"lemon", "🍋"}, {"banana", "🍌"},
+	{"pineapple", "🍍"}, {"mango", "🥭"}, {"apple", "🍎"},
+	{"green_apple", "🍏"}, {"pear", "🍐"}, {"peach", "🍑"},
+	{"cherries", "🍒"}, {"strawberry", "🍓"}, {"kiwi_fruit", "🥝"},
+	{"tomato", "🍅"}, {"coconut", "🥥"}, {"avocado", "🥑"},
+	{"eggplant", "🍆"}, {"potato", "🥔"}, {"carrot", "🥕"}, {"corn", "🌽"},
+	{"hot_pepper", "🌶️"}, {"cucumber", "🥒"}, {"broccoli", "🥦"},
+	{"garlic", "🧄"}, {"onion", "🧅"}, {"peanuts", "🥜"}, {"bread", "🍞"},
+	{"croissant", "🥐"}, {"baguette_bread", "🥖"}, {"pretzel", "🥨"},
+	{"bagel", "🥯"}, {"pancakes", "🥞"}, {"cheese", "🧀"},
+	{"meat_on_bone", "🍖"}, {"poultry_leg", "🍗"}, {"bacon", "🥓"},
+	{"hamburger", "🍔"}, {"fries", "🍟"}, {"pizza", "🍕"}, {"hotdog", "🌭"},
+	{"sandwich", "🥪"}, {"taco", "🌮"}, {"burrito", "🌯"}, {"egg", "🥚"},
+	{"fried_egg", "🍳"}, {"popcorn", "🍿"}, {"salt", "🧂"}, {"bento", "🍱"},
+	{"rice", "🍚"}, {"curry", "🍛"}, {"ramen", "🍜"}, {"spaghetti", "🍝"},
+	{"sushi", "🍣"}, {"dumpling", "🥟"}, {"icecream", "🍦"},
+	{"ice_cream", "🍨"}, {"doughnut", "🍩"}, {"cookie", "🍪"},
+	{"birthday", "🎂"}, {"cake", "🍰"}, {"cupcake", "🧁"}, {"pie", "🥧"},
+	{"chocolate_bar", "🍫"}, {"candy", "🍬"}, {"lollipop", "🍭"},
+	{"honey_pot", "🍯"}, {"baby_bottle", "🍼"}, {"milk_glass", "🥛"},
+	{"coffee", "☕"}, {"tea", "🍵"}, {"sake", "🍶"}, {"champagne", "🍾"},
+	{"wine_glass", "🍷"}, {"cocktail", "🍸"}, {"tropical_drink", "🍹"},
+	{"beer", "🍺"}, {"beers", "🍻"}, {"clinking_glasses", "🥂"},
+	{"tumbler_glass", "🥃"}, {"cup_with_straw", "🥤"},
+	{"chopsticks", "🥢"}, {"fork_and_knife", "🍴"}, {"spoon", "🥄"},
+
+	// Travel and places
+	{"earth_africa", "🌍"}, {"earth_americas", "🌎"}, {"earth_asia", "🌏"},
+	{"globe_with_meridians", "🌐"}, {"world_map", "🗺️"}, {"compass", "🧭"},
+	{"mountain_snow", "🏔️"}, {"mountain", "⛰️"}, {"volcano", "🌋"},
+	{"camping", "🏕️"}, {"beach_umbrella", "🏖️"}, {"desert", "🏜️"},
+	{"desert_island", "🏝️"}, {"stadium", "🏟️"}, {"classical_building", "🏛️"},
+	{"building_construction", "🏗️"}, {"bricks", "🧱"}, {"house", "🏠"},
+	{"house_with_garden", "🏡"}, {"office", "🏢"}, {"post_office", "🏣"},
+	{"hospital", "🏥"}, {"bank", "🏦"}, {"hotel", "🏨"}, {"school", "🏫"},
+	{"factory", "🏭"}, {"castle", "🏰"}, {"wedding", "💒"},
+	{"tokyo_tower", "🗼"}, {"statue_of_liberty", "🗽"}, {"church", "⛪"},
+	{"fountain", "⛲"}, {"tent", "⛺"}, {"foggy", "🌁"},
+	{"night_with_stars", "🌃"}, {"cityscape", "🏙️"}, {"sunrise", "🌅"},
+	{"bridge_at_night", "🌉"}, {"ferris_wheel", "🎡"},
+	{"roller_coaster", "🎢"}, {"circus_tent", "🎪"},
+	{"steam_locomotive", "🚂"}, {"railway_car", "🚃"},
+	{"bullettrain_side", "🚄"}, {"train2", "🚆"}, {"metro", "🚇"},
+	{"station", "🚉"}, {"tram", "🚊"}, {"monorail", "🚝"}, {"bus", "🚌"},
+	{"ambulance", "🚑"}, {"fire_engine", "🚒"}, {"police_car", "🚓"},
+	{"taxi", "🚕"}, {"car", "🚗"}, {"red_car", "🚗"}, {"blue_car", "🚙"},
+	{"truck", "🚚"}, {"articulated_lorry", "🚛"}, {"tractor", "🚜"},
+	{"racing_car", "🏎️"}, {"motorcycle", "🏍️"}, {"bike", "🚲"},
+	{"kick_scooter", "🛴"}, {"busstop", "🚏"}, {"fuelpump", "⛽"},
+	{"rotating_light", "🚨"}, {"traffic_light", "🚥"},
+	{"vertical_traffic_light", "🚦"}, {"stop_sign", "🛑"},
+	{"construction", "🚧"}, {"anchor", "⚓"}, {"boat", "⛵"},
+	{"sailboat", "⛵"}, {"canoe", "🛶"}, {"speedboat", "🚤"},
+	{"ship", "🚢"}, {"airplane", "✈️"}, {"flight_departure", "🛫"},
+	{"flight_arrival", "🛬"}, {"parachute", "🪂"}, {"seat", "💺"},
+	{"helicopter", "🚁"}, {"artificial_satellite", "🛰️"}, {"rocket", "🚀"},
+	{"flying_saucer", "🛸"}, {"hourglass", "⌛"},
+	{"hourglass_flowing_sand", "⏳"}, {"watch", "⌚"}, {"alarm_clock", "⏰"},
+	{"stopwatch", "⏱️"}, {"timer_clock", "⏲️"}, {"clock12", "🕛"},
+	{"new_moon", "🌑"}, {"full_moon", "🌕"}, {"crescent_moon", "🌙"},
+	{"thermometer", "🌡️"}, {"sunny", "☀️"}, {"star", "⭐"},
+	{"star2", "🌟"}, {"stars", "🌠"}, {"milky_way", "🌌"}, {"cloud", "☁️"},
+	{"partly_sunny", "⛅"}, {"cloud_with_lightning_and_rain", "⛈️"},
+	{"cloud_with_rain", "🌧️"}, {"cloud_with_snow", "🌨️"},
+	{"tornado", "🌪️"}, {"fog", "🌫️"}, {"cyclone", "🌀"},
+	{"rainbow", "🌈"}, {"closed_umbrella", "🌂"}, {"umbrella", "☔"},
+	{"zap", "⚡"}, {"snowflake", "❄️"}, {"snowman", "⛄"}, {"comet", "☄️"},
+	{"fire", "🔥"}, {"droplet", "💧"}, {"ocean", "🌊"},
+
+	// Activities
+	{"jack_o_lantern", "🎃"}, {"christmas_tree", "🎄"}, {"fireworks", "🎆"},
+	{"sparkler", "🎇"}, {"firecracker", "🧨"}, {"sparkles", "✨"},
+	{"balloon", "🎈"}, {"tada", "🎉"}, {"confetti_ball", "🎊"},
+	{"tanabata_tree", "🎋"}, {"bamboo", "🎍"}, {"dolls", "🎎"},
+	{"flags", "🎏"}, {"wind_chime", "🎐"}, {"rice_scene", "🎑"},
+	{"ribbon", "🎀"}, {"gift", "🎁"}, {"reminder_ribbon", "🎗️"},
+	{"tickets", "🎟️"}, {"ticket", "🎫"}, {"medal_military", "🎖️"},
+	{"trophy", "🏆"}, {"medal_sports", "🏅"}, {"1st_place_medal", "🥇"},
+	{"2nd_place_medal", "🥈"}, {"3rd_place_medal", "🥉"},
+	{"soccer", "⚽"}, {"baseball", "⚾"}, {"softball", "🥎"},
+	{"basketball", "🏀"}, {"volleyball", "🏐"}, {"football", "🏈"},
+	{"rugby_football", "🏉"}, {"tennis", "🎾"}, {"bowling", "🎳"},
+	{"ping_pong", "🏓"}, {"badminton", "🏸"}, {"boxing_glove", "🥊"},
+	{"golf", "⛳"}, {"ice_skate", "⛸️"}, {"fishing_pole_and_fish", "🎣"},
+	{"ski", "🎿"}, {"dart", "🎯"}, {"yo_yo", "🪀"}, {"kite", "🪁"},
+	{"8ball", "🎱"}, {"crystal_ball", "🔮"}, {"video_game", "🎮"},
+	{"joystick", "🕹️"}, {"slot_machine", "🎰"}, {"game_die", "🎲"},
+	{"jigsaw", "🧩"}, {"teddy_bear", "🧸"}, {"spades", "♠️"},
+	{"hearts", "♥️"}, {"diamonds", "♦️"}, {"clubs", "♣️"},
+	{"chess_pawn", "♟️"}, {"black_joker", "🃏"}, {"mahjong", "🀄"},
+	{"performing_arts", "🎭"}, {"framed_picture", "🖼️"}, {"art", "🎨"},
+	{"thread", "🧵"}, {"yarn", "🧶"},
+
+	// Objects
+	{"eyeglasses", "👓"}, {"dark_sunglasses", "🕶️"}, {"goggles", "🥽"},
+	{"lab_coat", "🥼"}, {"necktie", "👔"}, {"shirt", "👕"},
+	{"tshirt", "👕"}, {"jeans", "👖"}, {"dress", "👗"}, {"bikini", "👙"},
+	{"purse", "👛"}, {"handbag", "👜"}, {"school_satchel", "🎒"},
+	{"mans_shoe", "👞"}, {"athletic_shoe", "👟"}, {"high_heel", "👠"},
+	{"boot", "👢"}, {"crown", "👑"}, {"womans_hat", "👒"}, {"tophat", "🎩"},
+	{"mortar_board", "🎓"}, {"rescue_worker_helmet", "⛑️"},
+	{"lipstick", "💄"}, {"ring", "💍"}, {"gem", "💎"}, {"mute", "🔇"},
+	{"speaker", "🔈"}, {"sound", "🔉"}, {"loud_sound", "🔊"},
+	{"loudspeaker", "📢"}, {"mega", "📣"}, {"postal_horn", "📯"},
+	{"bell", "🔔"}, {"no_bell", "🔕"}, {"musical_score", "🎼"},
+	{"musical_note", "🎵"}, {"notes", "🎶"}, {"studio_microphone", "🎙️"},
+	{"microphone", "🎤"}, {"headphones", "🎧"}, {"radio", "📻"},
+	{"saxophone", "🎷"}, {"guitar", "🎸"}, {"musical_keyboard", "🎹"},
+	{"trumpet", "🎺"}, {"violin", "🎻"}, {"drum", "🥁"},
+	{"iphone", "📱"}, {"calling", "📲"}, {"phone", "☎️"},
+	{"telephone", "☎️"}, {"telephone_receiver", "📞"}, {"pager", "📟"},
+	{"fax", "📠"}, {"battery", "🔋"}, {"electric_plug", "🔌"},
+	{"computer", "💻"}, {"desktop_computer", "🖥️"}, {"printer", "🖨️"},
+	{"keyboard", "⌨️"}, {"computer_mouse", "🖱️"}, {"trackball", "🖲️"},
+	{"minidisc", "💽"}, {"floppy_disk", "💾"}, {"cd", "💿"}, {"dvd", "📀"},
+	{"abacus", "🧮"}, {"movie_camera", "🎥"}, {"film_strip", "🎞️"},
+	{"film_projector", "📽️"}, {"clapper", "🎬"}, {"tv", "📺"},
+	{"camera", "📷"}, {"camera_flash", "📸"}, {"video_camera", "📹"},
+	{"vhs", "📼"}, {"mag", "🔍"}, {"mag_right", "🔎"}, {"candle", "🕯️"},
+	{"bulb", "💡"}, {"flashlight", "🔦"}, {"izakaya_lantern", "🏮"},
+	{"lantern", "🏮"}, {"notebook_with_decorative_cover", "📔"},
+	{"closed_book", "📕"}, {"book", "📖"}, {"open_book", "📖"},
+	{"green_book", "📗"}, {"blue_book", "📘"}, {"orange_book", "📙"},
+	{"books", "📚"}, {"notebook", "📓"}, {"ledger", "📒"},
+	{"page_with_curl", "📃"}, {"scroll", "📜"}, {"page_facing_up", "📄"},
+	{"newspaper", "📰"}, {"newspaper_roll", "🗞️"}, {"bookmark_tabs", "📑"},
+	{"bookmark", "🔖"}, {"label", "🏷️"}, {"moneybag", "💰"}, {"yen", "💴"},
+	{"dollar", "💵"}, {"euro", "💶"}, {"pound", "💷"},
+	{"money_with_wings", "💸"}, {"credit_card", "💳"}, {"receipt", "🧾"},
+	{"chart", "💹"}, {"email", "📧"}, {"envelope", "✉️"},
+	{"incoming_envelope", "📨"}, {"envelope_with_arrow", "📩"},
+	{"outbox_tray", "📤"}, {"inbox_tray", "📥"}, {"package", "📦"},
+	{"mailbox", "📫"}, {"mailbox_closed", "📪"},
+	{"mailbox_with_mail", "📬"}, {"mailbox_with_no_mail", "📭"},
+	{"postbox", "📮"}, {"ballot_box", "🗳️"}, {"pencil2", "✏️"},
+	{"black_nib", "✒️"}, {"fountain_pen", "🖋️"}, {"pen", "🖊️"},
+	{"paintbrush", "🖌️"}, {"crayon", "🖍️"}, {"memo", "📝"},
+	{"pencil", "📝"}, {"briefcase", "💼"}, {"file_folder", "📁"},
+	{"open_file_folder", "📂"}, {"card_index_dividers", "🗂️"},
+	{"date", "📅"}, {"calendar", "📆"}, {"spiral_notepad", "🗒️"},
+	{"spiral_calendar", "🗓️"}, {"card_index", "📇"},
+	{"chart_with_upwards_trend", "📈"}, {"chart_with_downwards_trend", "📉"},
+	{"bar_chart", "📊"}, {"clipboard", "📋"}, {"pushpin", "📌"},
+	{"round_pushpin", "📍"}, {"paperclip", "📎"}, {"paperclips", "🖇️"},
+	{"straight_ruler", "📏"}, {"triangular_ruler", "📐"},
+	{"scissors", "✂️"}, {"card_file_box", "🗃️"}, {"file_cabinet", "🗄️"},
+	{"wastebasket", "🗑️"}, {"lock", "🔒"}, {"unlock", "🔓"},
+	{"lock_with_ink_pen", "🔏"}, {"closed_lock_with_key", "🔐"},
+	{"key", "🔑"}, {"old_key", "🗝️"}, {"hammer", "🔨"}, {"axe", "🪓"},
+	{"pick", "⛏️"}, {"hammer_and_pick", "⚒️"},
+	{"hammer_and_wrench", "🛠️"}, {"dagger", "🗡️"},
+	{"crossed_swords", "⚔️"}, {"gun", "🔫"}, {"bow_and_arrow", "🏹"},
+	{"shield", "🛡️"}, {"wrench", "🔧"}, {"nut_and_bolt", "🔩"},
+	{"gear", "⚙️"}, {"clamp", "🗜️"}, {"balance_scale", "⚖️"},
+	{"link", "🔗"}, {"chains", "⛓️"}, {"toolbox", "🧰"}, {"magnet", "🧲"},
+	{"alembic", "⚗️"}, {"test_tube", "🧪"}, {"petri_dish", "🧫"},
+	{"dna", "🧬"}, {"microscope", "🔬"}, {"telescope", "🔭"},
+	{"satellite", "📡"}, {"syringe", "💉"}, {"drop_of_blood", "🩸"},
+	{"pill", "💊"}, {"adhesive_bandage", "🩹"}, {"stethoscope", "🩺"},
+	{"door", "🚪"}, {"bed", "🛏️"}, {"couch_and_lamp", "🛋️"},
+	{"chair", "🪑"}, {"toilet", "🚽"}, {"shower", "🚿"}, {"bathtub", "🛁"},
+	{"broom", "🧹"}, {"basket", "🧺"}, {"roll_of_paper", "🧻"},
+	{"soap", "🧼"}, {"sponge", "🧽"}, {"fire_extinguisher", "🧯"},
+	{"shopping_cart", "🛒"}, {"smoking", "🚬"}, {"coffin", "⚰️"},
+	{"funeral_urn", "⚱️"}, {"moyai", "🗿"},
+
+	// Symbols
+	{"atm", "🏧"}, {"put_litter_in_its_place", "🚮"},
+	{"potable_water", "🚰"}, {"wheelchair", "♿"}, {"mens", "🚹"},
+	{"womens", "🚺"}, {"restroom", "🚻"}, {"baby_symbol", "🚼"},
+	{"wc", "🚾"}, {"passport_control", "🛂"}, {"customs", "🛃"},
+	{"baggage_claim", "🛄"}, {"left_luggage", "🛅"}, {"warning", "⚠️"},
+	{"children_crossing", "🚸"}, {"no_entry", "⛔"},
+	{"no_entry_sign", "🚫"}, {"no_bicycles", "🚳"}, {"no_smoking", "🚭"},
+	{"do_not_litter", "🚯"}, {"non-potable_water", "🚱"},
+	{"no_pedestrians", "🚷"}, {"no_mobile_phones", "📵"},
+	{"underage", "🔞"}, {"radioactive", "☢️"}, {"biohazard", "☣️"},
+	{"arrow_up", "⬆️"}, {"arrow_upper_right", "↗️"}, {"arrow_right", "➡️"},
+	{"arrow_lower_right", "↘️"}, {"arrow_down", "⬇️"},
+	{"arrow_lower_left", "↙️"}, {"arrow_left", "⬅️"},
+	{"arrow_upper_left", "↖️"}, {"arrow_up_down", "↕️"},
+	{"left_right_arrow", "↔️"}, {"leftwards_arrow_with_hook", "↩️"},
+	{"arrow_right_hook", "↪️"}, {"arrow_heading_up", "⤴️"},
+	{"arrow_heading_down", "⤵️"}, {"arrows_clockwise", "🔃"},
+	{"arrows_counterclockwise", "🔄"}, {"back", "🔙"}, {"end", "🔚"},
+	{"on", "🔛"}, {"soon", "🔜"}, {"top", "🔝"},
+	{"place_of_worship", "🛐"}, {"atom_symbol", "⚛️"}, {"om", "🕉️"},
+	{"star_of_david", "✡️"}, {"wheel_of_dharma", "☸️"},
+	{"yin_yang", "☯️"}, {"latin_cross", "✝️"},
+	{"orthodox_cross", "☦️"}, {"star_and_crescent", "☪️"},
+	{"peace_symbol", "☮️"}, {"menorah", "🕎"}, {"six_pointed_star", "🔯"},
+	{"aries", "♈"}, {"taurus", "♉"}, {"gemini", "♊"}, {"cancer", "♋"},
+	{"leo", "♌"}, {"virgo", "♍"}, {"libra", "♎"}, {"scorpius", "♏"},
+	{"sagittarius", "♐"}, {"capricorn", "♑"}, {"aquarius", "♒"},
+	{"pisces", "♓"}, {"ophiuchus", "⛎"}, {"twisted_rightwards_arrows", "🔀"},
+	{"repeat", "🔁"}, {"repeat_one", "🔂"}, {"arrow_forward", "▶️"},
+	{"fast_forward", "⏩"}, {"next_track_button", "⏭️"},
+	{"play_or_pause_button", "⏯️"}, {"arrow_backward", "◀️"},
+	{"rewind", "⏪"}, {"previous_track_button", "⏮️"},
+	{"arrow_up_small", "🔼"}, {"arrow_double_up", "⏫"},
+	{"arrow_down_small", "🔽"}, {"arrow_double_down", "⏬"},
+	{"pause_button", "⏸️"}, {"stop_button", "⏹️"},
+	{"record_button", "⏺️"}, {"eject_button", "⏏️"}, {"cinema", "🎦"},
+	{"low_brightness", "🔅"}, {"high_brightness", "🔆"},
+	{"signal_strength", "📶"}, {"vibration_mode", "📳"},
+	{"mobile_phone_off", "📴"}, {"female_sign", "♀️"},
+	{"male_sign", "♂️"}, {"infinity", "♾️"}, {"recycle", "♻️"},
+	{"fleur_de_lis", "⚜️"}, {"trident", "🔱"}, {"name_badge", "📛"},
+	{"beginner", "🔰"}, {"o", "⭕"}, {"white_check_mark", "✅"},
+	{"ballot_box_with_check", "☑️"}, {"heavy_check_mark", "✔️"},
+	{"heavy_multiplication_x", "✖️"}, {"x", "❌"},
+	{"negative_squared_cross_mark", "❎"}, {"heavy_plus_sign", "➕"},
+	{"heavy_minus_sign", "➖"}, {"heavy_division_sign", "➗"},
+	{"curly_loop", "➰"}, {"loop", "➿"}, {"part_alternation_mark", "〽️"},
+	{"eight_spoked_asterisk", "✳️"}, {"eight_pointed_black_star", "✴️"},
+	{"sparkle", "❇️"}, {"bangbang", "‼️"}, {"interrobang", "⁉️"},
+	{"question", "❓"}, {"grey_question", "❔"},
+	{"grey_exclamation", "❕"}, {"exclamation", "❗"},
+	{"heavy_exclamation_mark", "❗"}, {"wavy_dash", "〰️"},
+	{"copyright", "©️"}, {"registered", "®️"}, {"tm", "™️"},
+	{"hash", "#️⃣"}, {"asterisk", "*️⃣"}, {"zero", "0️⃣"}, {"one", "1️⃣"},
+	{"two", "2️⃣"}, {"three", "3️⃣"}, {"four", "4️⃣"}, {"five", "5️⃣"},
+	{"six", "6️⃣"}, {"seven", "7️⃣"}, {"eight", "8️⃣"}, {"nine", "9️⃣"},
+	{"keycap_ten", "🔟"}, {"capital_abcd", "🔠"}, {"abcd", "🔡"},
+	{"1234", "🔢"}, {"symbols", "🔣"}, {"abc", "🔤"}, {"a", "🅰️"},
+	{"ab", "🆎"}, {"b", "🅱️"}, {"cl", "🆑"}, {"cool", "🆒"}, {"free", "🆓"},
+	{"information_source", "ℹ️"}, {"id", "🆔"}, {"m", "Ⓜ️"}, {"new", "🆕"},
+	{"ng", "🆖"}, {"o2", "🅾️"}, {"ok", "🆗"}, {"parking", "🅿️"},
+	{"sos", "🆘"}, {"up", "🆙"}, {"vs", "🆚"}, {"red_circle", "🔴"},
+	{"orange_circle", "🟠"}, {"yellow_circle", "🟡"},
+	{"green_circle", "🟢"}, {"large_blue_circle", "🔵"},
+	{"purple_circle", "🟣"}, {"brown_circle", "🟤"},
+	{"black_circle", "⚫"}, {"white_circle", "⚪"}, {"red_square", "🟥"},
+	{"orange_square", "🟧"}, {"yellow_square", "🟨"},
+	{"green_square", "🟩"}, {"blue_square", "🟦"},
+	{"purple_square", "🟪"}, {"brown_square", "🟫"},
+	{"black_large_square", "⬛"}, {"white_large_square", "⬜"},
+	{"large_orange_diamond", "🔶"}, {"large_blue_diamond", "🔷"},
+	{"small_orange_diamond", "🔸"}, {"small_blue_diamond", "🔹"},
+	{"small_red_triangle", "🔺"}, {"small_red_triangle_down", "🔻"},
+	{"diamond_shape_with_a_dot_inside", "💠"}, {"radio_button", "🔘"},
+	{"white_square_button", "🔳"}, {"black_square_button", "🔲"},
+
+	// Flags
+	{"checkered_flag", "🏁"}, {"triangular_flag_on_post", "🚩"},
+	{"crossed_flags", "🎌"}, {"black_flag", "🏴"}, {"white_flag", "🏳️"},
+	{"rainbow_flag", "🏳️‍🌈"}, {"pirate_flag", "🏴‍☠️"},
+	{"australia", "🇦🇺"}, {"brazil", "🇧🇷"}, {"canada", "🇨🇦"},
+	{"cn", "🇨🇳"}, {"de", "🇩🇪"}, {"es", "🇪🇸"}, {"eu", "🇪🇺"},
+	{"european_union", "🇪🇺"}, {"fr", "🇫🇷"}, {"gb", "🇬🇧"}, {"uk", "🇬🇧"},
+	{"india", "🇮🇳"}, {"it", "🇮🇹"}, {"jp", "🇯🇵"}, {"kr", "🇰🇷"},
+	{"mexico", "🇲🇽"}, {"netherlands", "🇳🇱"}, {"ru", "🇷🇺"},
+	{"sweden", "🇸🇪"}, {"switzerland", "🇨🇭"}, {"ukraine", "🇺🇦"},
+	{"us", "🇺🇸"},
+}
+
+// categories names the sections of table by their first shortcode, in table order.
+var categories = []struct {
+	name  string
+	first string
+}{
+	{"Smileys", "grinning"},
+	{"Hearts and marks", "kiss"},
+	{"Hands and people", "wave"},
+	{"Animals and nature", "monkey_face"},
+	{"Food and drink", "grapes"},
+	{"Travel and places", "earth_africa"},
+	{"Activities", "jack_o_lantern"},
+	{"Objects", "eyeglasses"},
+	{"Symbols", "atm"},
+	{"Flags", "checkered_flag"},
+}
+
+// Entry is one emoji of the bundled table with all of its shortcodes.
+type Entry struct {
+	Emoji string `json:"emoji"`
+	// Shortcode is the canonical shortcode, without colons
+	Shortcode string `json:"shortcode"`
+	// Aliases are the other shortcodes of the emoji, without colons
+	Aliases  []string `json:"aliases,omitempty"`
+	Category string   `json:"category"`
+}
+
+// Names returns the shortcode and aliases of the entry.
+func (e Entry) Names() []string {
+	return append([]string{e.Shortcode}, e.Aliases...)
+}
+
+// entries holds one Entry per emoji of table, in table order.
+var entries = func() []Entry {
+	var list []Entry
+	index := make(map[string]int, len(table))
+	category := ""
+	next := 0
+	for _, row := range table {
+		if next < len(categories) && row.name == categories[next].first {
+			category = categories[next].name
+			next++
+		}
+		key := withoutVariationSelector(row.emoji)
+		if i, ok := index[key]; ok {
+			list[i].Aliases = append(list[i].Aliases, row.name)
+			continue
+		}
+		index[key] = len(list)
+		list = append(list, Entry{Emoji: row.emoji, Shortcode: row.name, Category: category})
+	}
+	return list
+}()
+
+// Categories returns the names of the categories of the bundled table, in order.
+func Categories() []string {
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = category.name
+	}
+	return names
+}
+
+// Entries returns every emoji of the bundled table, in table order.
+func Entries() []Entry {
+	return append([]Entry(nil), entries...)
+}
+
+// Search returns the entries whose emoji is query, ignoring variation selectors, or
+// one of whose shortcodes contains query, which may be written with colons. An exact
+// shortcode match comes first.
+func Search(query string) []Entry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	name := strings.Trim(query, ":")
+	if query == "" {
+		return nil
+	}
+	var exact, partial []Entry
+	for _, entry := range entries {
+		names := entry.Names()
+		switch {
+		case withoutVariationSelector(entry.Emoji) == withoutVariationSelector(query),
+			slices.Contains(names, name):
+			exact = append(exact, entry)
+		case name != "" && slices.ContainsFunc(names, func(n string) bool { return strings.Contains(n, name) }):
+			partial = append(partial, entry)
+		}
+	}
+	return append(exact, partial...)
+}
+
+// Table returns the bundled shortcode table, mapping each shortcode name (without
+// colons) to the emoji it stands for.
+func Table() map[string]string {
+	shortcodes := make(map[string]string, len(table))
+	for _, entry := range table {
+		shortcodes[entry.name] = entry.emoji
+	}
+	return shortcodes
+}
+
+// aliasesByEmoji maps each emoji of the bundled table, without variation selectors,
+// to its shortcodes with colons, canonical shortcode first.
+var aliasesByEmoji = func() map[string][]string {
+	aliases := make(map[string][]string, len(table))
+	for _, entry := range table {
+		key := withoutVariationSelector(entry.emoji)
+		aliases[key] = append(aliases[key], ":"+entry.name+":")
+	}
+	return aliases
+}()
+
+// emojisByShortcode maps each shortcode of the bundled table, with colons, to its emoji.
+var emojisByShortcode = func() map[string]string {
+	emojis := make(map[string]string, len(table))
+	for _, entry := range table {
+		emojis[":"+entry.name+":"] = entry.emoji
+	}
+	return emojis
+}()
+
+// For returns the canonical shortcode of emoji, with colons, ignoring variation
+// selectors. It reports false for emojis without a shortcode.
+func For(emoji string) (string, bool) {
+	aliases := aliasesByEmoji[withoutVariationSelector(emoji)]
+	if len(aliases) == 0 {
+		return "", false
+	}
+	return aliases[0], true
+}
+
+// Equivalents returns the other ways of writing emoji, which may be an emoji or a
+// shortcode with colons: the emoji a shortcode stands for and every shortcode of that
+// emoji. It returns nil for text outside the bundled table.
+func Equivalents(emoji string) []string {
+	if unicode, ok := emojisByShortcode[emoji]; ok {
+		return append([]string{unicode}, aliasesByEmoji[withoutVariationSelector(unicode)]...)
+	}
+	return aliasesByEmoji[withoutVariationSelector(emoji)]
+}
+
+// withoutVariationSelector removes the text and emoji variation selectors from emoji.
+func withoutVariationSelector(emoji string) string {
+	return strings.NewReplacer("\uFE0F", "", "\uFE0E", "").Replace(emoji)
+}
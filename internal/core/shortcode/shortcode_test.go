@@ -0,0 +1,95 @@
+package shortcode
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable(t *testing.T) {
+	shortcodes := Table()
+	assert.Equal(t, "🚀", shortcodes["rocket"])
+	assert.Equal(t, "👍", shortcodes["+1"])
+	assert.Equal(t, "👍", shortcodes["thumbsup"])
+
+	valid := regexp.MustCompile(`^[a-z0-9_+-]+$`)
+	seen := make(map[string]bool, len(table))
+	for _, entry := range table {
+		assert.Regexp(t, valid, entry.name)
+		assert.NotEmpty(t, entry.emoji, entry.name)
+		assert.False(t, seen[entry.name], "duplicate shortcode %s", entry.name)
+		seen[entry.name] = true
+	}
+}
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		emoji string
+		want  string
+		ok    bool
+	}{
+		{"🚀", ":rocket:", true},
+		{"👍", ":+1:", true},
+		{"✋", ":raised_hand:", true},
+		{"❤️", ":heart:", true},
+		{"❤", ":heart:", true},
+		{"🧑‍💻", "", false},
+		{"text", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := For(tt.emoji)
+		assert.Equal(t, tt.ok, ok, tt.emoji)
+		assert.Equal(t, tt.want, got, tt.emoji)
+	}
+}
+
+func TestEquivalents(t *testing.T) {
+	assert.Equal(t, []string{":+1:", ":thumbsup:"}, Equivalents("👍"))
+	assert.Equal(t, []string{"👍", ":+1:", ":thumbsup:"}, Equivalents(":thumbsup:"))
+	assert.Equal(t, []string{"⚠️", ":warning:"}, Equivalents(":warning:"))
+	assert.Nil(t, Equivalents(":not_an_emoji:"))
+	assert.Nil(t, Equivalents("🧑‍💻"))
+}
+
+func TestEntries(t *testing.T) {
+	list := Entries()
+	require.NotEmpty(t, list)
+	assert.Equal(t, Entry{Emoji: "😀", Shortcode: "grinning", Category: "Smileys"}, list[0])
+	assert.Equal(t, "Flags", list[len(list)-1].Category)
+
+	known := make(map[string]bool)
+	for _, category := range Categories() {
+		known[category] = true
+	}
+	for _, entry := range list {
+		assert.True(t, known[entry.Category], "%s has category %q", entry.Shortcode, entry.Category)
+	}
+	assert.Len(t, Categories(), len(categories), "every category names a shortcode of the table")
+}
+
+func TestSearch(t *testing.T) {
+	results := Search("rocket")
+	require.NotEmpty(t, results)
+	assert.Equal(t, "🚀", results[0].Emoji)
+	assert.Equal(t, "Travel and places", results[0].Category)
+
+	results = Search(":thumbsup:")
+	require.Len(t, results, 1)
+	assert.Equal(t, "+1", results[0].Shortcode)
+	assert.Contains(t, results[0].Aliases, "thumbsup")
+
+	results = Search("❤")
+	require.NotEmpty(t, results)
+	assert.Equal(t, "heart", results[0].Shortcode)
+
+	// An exact shortcode match comes before partial ones
+	results = Search("cat")
+	require.Greater(t, len(results), 1)
+	assert.Equal(t, "cat", results[0].Shortcode)
+
+	assert.Empty(t, Search(""))
+	assert.Empty(t, Search("no_such_emoji_name"))
+}
@@ -55,7 +55,7 @@ func (ca *ConfigAnalyzer) analyzePolicySettings() PolicyAnalysis {
 	analysis := PolicyAnalysis{}
 
 	// Determine policy type
-	if ca.profile.MaxEmojiThreshold == 0 && len(ca.profile.EmojiAllowlist) == 0 {
+	if config.TotalLimit(ca.profile) == 0 && len(ca.profile.EmojiAllowlist) == 0 {
 		analysis.PolicyType = "zero-tolerance"
 		analysis.Strictness = "maximum"
 		analysis.Description = "NO emojis allowed anywhere in source code"
@@ -64,10 +64,10 @@ func (ca *ConfigAnalyzer) analyzePolicySettings() PolicyAnalysis {
 		analysis.Strictness = "moderate"
 		analysis.Description = fmt.Sprintf("Only %d specific emojis allowed", len(ca.profile.EmojiAllowlist))
 		analysis.AllowedEmojis = ca.profile.EmojiAllowlist
-	} else if ca.profile.MaxEmojiThreshold > 15 {
+	} else if config.TotalLimit(ca.profile) > 15 {
 		analysis.PolicyType = "permissive"
 		analysis.Strictness = "low"
-		analysis.Description = fmt.Sprintf("Allows up to %d emojis with warnings", ca.profile.MaxEmojiThreshold)
+		analysis.Description = fmt.Sprintf("Allows up to %d emojis with warnings", config.TotalLimit(ca.profile))
 	} else {
 		analysis.PolicyType = "custom"
 		analysis.Strictness = "variable"
@@ -75,7 +75,7 @@ func (ca *ConfigAnalyzer) analyzePolicySettings() PolicyAnalysis {
 	}
 
 	// Analyze threshold settings
-	analysis.Threshold = ca.profile.MaxEmojiThreshold
+	analysis.Threshold = config.TotalLimit(ca.profile)
 	analysis.FailBehavior = ca.profile.FailOnFound
 	analysis.ExitCode = ca.profile.ExitCodeOnFound
 
@@ -176,7 +176,7 @@ func (ca *ConfigAnalyzer) analyzeCodebaseImpact() ImpactAnalysis {
 	analysis.FileTypeBreakdown = fileTypes
 
 	// Estimate impact based on policy
-	if ca.profile.MaxEmojiThreshold == 0 && len(ca.profile.EmojiAllowlist) == 0 {
+	if config.TotalLimit(ca.profile) == 0 && len(ca.profile.EmojiAllowlist) == 0 {
 		analysis.EstimatedRemovals = emojiCount
 		analysis.ImpactLevel = "high"
 		analysis.ImpactDescription = fmt.Sprintf("Will remove all %d emojis found", emojiCount)
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// DensityReport ranks files and packages by emoji density, to point cleanup at the
+// places with the most emojis relative to their size.
+type DensityReport struct {
+	// Files lists the files containing emojis, densest first
+	Files []Density `json:"files"`
+	// Packages lists the directories containing emojis, densest first
+	Packages []Density `json:"packages"`
+}
+
+// Density is the emoji density of a file or package.
+type Density struct {
+	Name   string `json:"name"`
+	Emojis int    `json:"emojis"`
+	Lines  int    `json:"lines"`
+	// PerKLOC is the number of emojis per thousand lines
+	PerKLOC float64 `json:"per_kloc"`
+}
+
+// AnalyzeDensity computes the emoji density of every file and package (the directory
+// holding the files) with emojis. A package's density counts the lines of all of its
+// scanned files, including those without emojis. Files that failed to process are
+// ignored.
+func AnalyzeDensity(results []types.ProcessResult) DensityReport {
+	var report DensityReport
+	packages := make(map[string]*Density)
+
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		emojis, lines := len(result.DetectionResult.Emojis), result.DetectionResult.Lines
+
+		dir := filepath.Dir(result.FilePath)
+		pkg, ok := packages[dir]
+		if !ok {
+			pkg = &Density{Name: dir}
+			packages[dir] = pkg
+		}
+		pkg.Emojis += emojis
+		pkg.Lines += lines
+
+		if emojis > 0 {
+			report.Files = append(report.Files, Density{Name: result.FilePath, Emojis: emojis, Lines: lines, PerKLOC: perKLOC(emojis, lines)})
+		}
+	}
+
+	for _, pkg := range packages {
+		if pkg.Emojis > 0 {
+			pkg.PerKLOC = perKLOC(pkg.Emojis, pkg.Lines)
+			report.Packages = append(report.Packages, *pkg)
+		}
+	}
+
+	sortByDensity(report.Files)
+	sortByDensity(report.Packages)
+	return report
+}
+
+// Top returns a copy of the report with both rankings truncated to n entries; n <= 0
+// keeps everything.
+func (r DensityReport) Top(n int) DensityReport {
+	if n <= 0 {
+		return r
+	}
+	r.Files = truncate(r.Files, n)
+	r.Packages = truncate(r.Packages, n)
+	return r
+}
+
+// perKLOC returns emojis per thousand lines. Content with emojis has at least one line,
+// so lines is only 0 for results without line counts.
+func perKLOC(emojis, lines int) float64 {
+	if lines == 0 {
+		return 0
+	}
+	return float64(emojis) * 1000 / float64(lines)
+}
+
+// sortByDensity orders entries densest first, then by emoji count and name.
+func sortByDensity(entries []Density) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].PerKLOC != entries[j].PerKLOC {
+			return entries[i].PerKLOC > entries[j].PerKLOC
+		}
+		if entries[i].Emojis != entries[j].Emojis {
+			return entries[i].Emojis > entries[j].Emojis
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
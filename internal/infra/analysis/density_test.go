@@ -0,0 +1,46 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func densityResult(path string, lines int, emojis ...string) types.ProcessResult {
+	result := usageResult(path, emojis...)
+	result.DetectionResult.Lines = lines
+	return result
+}
+
+func TestAnalyzeDensity(t *testing.T) {
+	results := []types.ProcessResult{
+		densityResult("src/big.go", 2000, "🚀", "🚀", "✅", "✅"),
+		densityResult("src/clean.go", 2000),
+		densityResult("docs/guide.md", 100, "✅", "🎉"),
+		densityResult("docs/other.md", 100, "✅", "🎉"),
+		densityResult("cmd/main.go", 500, "🚀"),
+		{FilePath: "broken.go", Error: errors.New("unreadable")},
+	}
+
+	report := AnalyzeDensity(results)
+
+	require.Len(t, report.Files, 4)
+	assert.Equal(t, Density{Name: "docs/guide.md", Emojis: 2, Lines: 100, PerKLOC: 20}, report.Files[0])
+	assert.Equal(t, "docs/other.md", report.Files[1].Name)
+	assert.Equal(t, Density{Name: "src/big.go", Emojis: 4, Lines: 2000, PerKLOC: 2}, report.Files[2])
+	assert.Equal(t, Density{Name: "cmd/main.go", Emojis: 1, Lines: 500, PerKLOC: 2}, report.Files[3])
+
+	require.Len(t, report.Packages, 3)
+	assert.Equal(t, Density{Name: "docs", Emojis: 4, Lines: 200, PerKLOC: 20}, report.Packages[0])
+	assert.Equal(t, Density{Name: "cmd", Emojis: 1, Lines: 500, PerKLOC: 2}, report.Packages[1])
+	// Lines of files without emojis count towards their package
+	assert.Equal(t, Density{Name: "src", Emojis: 4, Lines: 4000, PerKLOC: 1}, report.Packages[2])
+
+	top := report.Top(1)
+	assert.Len(t, top.Files, 1)
+	assert.Len(t, top.Packages, 1)
+	assert.Len(t, report.Top(0).Files, 4)
+}
@@ -0,0 +1,255 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// UsageReport summarises emoji usage across scanned files.
+type UsageReport struct {
+	TotalFiles      int `json:"total_files"`
+	FilesWithEmojis int `json:"files_with_emojis"`
+	TotalEmojis     int `json:"total_emojis"`
+	UniqueEmojis    int `json:"unique_emojis"`
+
+	// Emojis lists each emoji, most used first
+	Emojis []EmojiCount `json:"emojis"`
+	// Directories groups usage by the directory containing each file
+	Directories []GroupCount `json:"directories"`
+	// FileTypes groups usage by file type (source, test, documentation, ...)
+	FileTypes []GroupCount `json:"file_types"`
+	// Categories groups usage by detection category (unicode, emoticon, custom)
+	Categories []GroupCount `json:"categories"`
+}
+
+// EmojiCount is the usage of a single emoji.
+type EmojiCount struct {
+	Emoji    string `json:"emoji"`
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+	Files    int    `json:"files"`
+}
+
+// GroupCount is the usage within one group of files.
+type GroupCount struct {
+	Name   string `json:"name"`
+	Emojis int    `json:"emojis"`
+	Files  int    `json:"files"`
+	// Top is the most used emoji in the group
+	Top string `json:"top"`
+}
+
+// UsageOptions controls how usage is grouped.
+type UsageOptions struct {
+	// DirectoryDepth truncates directories to their first n path elements; 0 keeps the
+	// full directory.
+	DirectoryDepth int
+}
+
+// AnalyzeUsage aggregates detection results into a usage report. Files that failed
+// to process are ignored.
+func AnalyzeUsage(results []types.ProcessResult, opts UsageOptions) UsageReport {
+	var report UsageReport
+	emojis := make(map[string]*EmojiCount)
+	emojiFiles := make(map[string]map[string]bool)
+	directories := newGrouping()
+	fileTypes := newGrouping()
+	categories := newGrouping()
+
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		report.TotalFiles++
+		if len(result.DetectionResult.Emojis) == 0 {
+			continue
+		}
+
+		report.FilesWithEmojis++
+		directory := truncateDir(filepath.Dir(result.FilePath), opts.DirectoryDepth)
+		fileType := CategorizeFile(result.FilePath)
+
+		for _, match := range result.DetectionResult.Emojis {
+			report.TotalEmojis++
+
+			usage, ok := emojis[match.Emoji]
+			if !ok {
+				usage = &EmojiCount{Emoji: match.Emoji, Category: string(match.Category)}
+				emojis[match.Emoji] = usage
+				emojiFiles[match.Emoji] = make(map[string]bool)
+			}
+			usage.Count++
+			emojiFiles[match.Emoji][result.FilePath] = true
+
+			category := string(match.Category)
+			if category == "" {
+				category = "unknown"
+			}
+			directories.add(directory, result.FilePath, match.Emoji)
+			fileTypes.add(fileType, result.FilePath, match.Emoji)
+			categories.add(category, result.FilePath, match.Emoji)
+		}
+	}
+
+	for emoji, usage := range emojis {
+		usage.Files = len(emojiFiles[emoji])
+		report.Emojis = append(report.Emojis, *usage)
+	}
+	sort.Slice(report.Emojis, func(i, j int) bool {
+		if report.Emojis[i].Count != report.Emojis[j].Count {
+			return report.Emojis[i].Count > report.Emojis[j].Count
+		}
+		return report.Emojis[i].Emoji < report.Emojis[j].Emoji
+	})
+
+	report.UniqueEmojis = len(report.Emojis)
+	report.Directories = directories.counts()
+	report.FileTypes = fileTypes.counts()
+	report.Categories = categories.counts()
+	return report
+}
+
+// Top returns a copy of the report with every list truncated to n entries; n <= 0
+// keeps everything.
+func (r UsageReport) Top(n int) UsageReport {
+	if n <= 0 {
+		return r
+	}
+	r.Emojis = truncate(r.Emojis, n)
+	r.Directories = truncate(r.Directories, n)
+	r.FileTypes = truncate(r.FileTypes, n)
+	r.Categories = truncate(r.Categories, n)
+	return r
+}
+
+// CategorizeFile classifies a file by its path and extension as test, ci,
+// documentation, markdown, config, source or other.
+func CategorizeFile(filePath string) string {
+	fileName := filepath.Base(filePath)
+	ext := filepath.Ext(fileName)
+	dir := filepath.Dir(filePath)
+
+	// Normalize path separators for cross-platform compatibility
+	normalizedPath := filepath.ToSlash(filePath)
+	normalizedDir := filepath.ToSlash(dir)
+
+	// Check for test files first (most specific)
+	if strings.Contains(fileName, "_test.") || strings.Contains(fileName, "test_") ||
+		strings.Contains(normalizedDir, "/test/") || strings.Contains(normalizedDir, "/tests/") ||
+		strings.Contains(normalizedDir, "/testdata/") || strings.Contains(normalizedDir, "/fixtures/") ||
+		strings.HasSuffix(normalizedDir, "/test") || strings.HasSuffix(normalizedDir, "/tests") ||
+		strings.HasSuffix(normalizedDir, "/testdata") || strings.HasSuffix(normalizedDir, "/fixtures") ||
+		strings.Contains(normalizedPath, "/test/") || strings.Contains(normalizedPath, "/tests/") {
+		return "test"
+	}
+
+	// Check for CI files
+	if strings.Contains(normalizedDir, ".github") || strings.Contains(normalizedDir, "scripts") ||
+		(ext == ".yml" || ext == ".yaml") && (strings.Contains(normalizedDir, ".github") || strings.Contains(fileName, "ci")) {
+		return "ci"
+	}
+
+	// Check for documentation
+	if ext == ".md" {
+		if strings.Contains(fileName, "README") || strings.Contains(fileName, "CHANGELOG") {
+			return "documentation"
+		}
+		if strings.Contains(normalizedDir, "docs") || strings.Contains(normalizedDir, "/doc/") {
+			return "markdown"
+		}
+		return "documentation" // Default for .md files
+	}
+
+	// Check for config files
+	if ext == ".yaml" || ext == ".yml" || ext == ".json" || ext == ".toml" {
+		return "config"
+	}
+
+	// Check for source code
+	sourceExts := map[string]bool{
+		".go": true, ".js": true, ".ts": true, ".py": true, ".java": true,
+		".c": true, ".cpp": true, ".h": true, ".hpp": true, ".rs": true,
+	}
+	if sourceExts[ext] {
+		return "source"
+	}
+
+	return "other"
+}
+
+// grouping tallies emojis, files and per-emoji counts for named groups.
+type grouping struct {
+	emojis map[string]int
+	files  map[string]map[string]bool
+	usage  map[string]map[string]int
+}
+
+func newGrouping() *grouping {
+	return &grouping{
+		emojis: make(map[string]int),
+		files:  make(map[string]map[string]bool),
+		usage:  make(map[string]map[string]int),
+	}
+}
+
+// add records one occurrence of emoji in file under group name.
+func (g *grouping) add(name, file, emoji string) {
+	if g.files[name] == nil {
+		g.files[name] = make(map[string]bool)
+		g.usage[name] = make(map[string]int)
+	}
+	g.emojis[name]++
+	g.files[name][file] = true
+	g.usage[name][emoji]++
+}
+
+// counts returns the groups, most emojis first.
+func (g *grouping) counts() []GroupCount {
+	counts := make([]GroupCount, 0, len(g.emojis))
+	for name, emojis := range g.emojis {
+		top, topCount := "", 0
+		for emoji, count := range g.usage[name] {
+			if count > topCount || (count == topCount && emoji < top) {
+				top, topCount = emoji, count
+			}
+		}
+		counts = append(counts, GroupCount{Name: name, Emojis: emojis, Files: len(g.files[name]), Top: top})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Emojis != counts[j].Emojis {
+			return counts[i].Emojis > counts[j].Emojis
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// truncateDir keeps the first depth elements of dir.
+func truncateDir(dir string, depth int) string {
+	dir = filepath.ToSlash(dir)
+	if depth <= 0 {
+		return dir
+	}
+
+	parts := strings.Split(dir, "/")
+	keep := depth
+	if parts[0] == "" {
+		// Absolute paths start with an empty element
+		keep++
+	}
+	if len(parts) > keep {
+		parts = parts[:keep]
+	}
+	return strings.Join(parts, "/")
+}
+
+func truncate[T any](items []T, n int) []T {
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func usageResult(path string, emojis ...string) types.ProcessResult {
+	result := types.ProcessResult{FilePath: path}
+	for _, emoji := range emojis {
+		category := types.CategoryUnicode
+		if emoji == ":)" {
+			category = types.CategoryEmoticon
+		}
+		result.DetectionResult.Emojis = append(result.DetectionResult.Emojis, types.EmojiMatch{Emoji: emoji, Category: category})
+	}
+	return result
+}
+
+func TestAnalyzeUsage(t *testing.T) {
+	results := []types.ProcessResult{
+		usageResult("src/pkg/a.go", "🚀", "🚀", ":)"),
+		usageResult("src/a_test.go", "🚀"),
+		usageResult("docs/guide.md", "✅"),
+		usageResult("src/empty.go"),
+		{FilePath: "broken.go", Error: errors.New("unreadable")},
+	}
+
+	report := AnalyzeUsage(results, UsageOptions{})
+
+	assert.Equal(t, 4, report.TotalFiles)
+	assert.Equal(t, 3, report.FilesWithEmojis)
+	assert.Equal(t, 5, report.TotalEmojis)
+	assert.Equal(t, 3, report.UniqueEmojis)
+
+	require.Len(t, report.Emojis, 3)
+	assert.Equal(t, EmojiCount{Emoji: "🚀", Category: "unicode", Count: 3, Files: 2}, report.Emojis[0])
+	assert.Equal(t, ":)", report.Emojis[1].Emoji, "ties are ordered by emoji")
+
+	assert.Equal(t, []GroupCount{
+		{Name: "src/pkg", Emojis: 3, Files: 1, Top: "🚀"},
+		{Name: "docs", Emojis: 1, Files: 1, Top: "✅"},
+		{Name: "src", Emojis: 1, Files: 1, Top: "🚀"},
+	}, report.Directories)
+	assert.Equal(t, "source", report.FileTypes[0].Name)
+	assert.Equal(t, []GroupCount{
+		{Name: "unicode", Emojis: 4, Files: 3, Top: "🚀"},
+		{Name: "emoticon", Emojis: 1, Files: 1, Top: ":)"},
+	}, report.Categories)
+
+	t.Run("directory depth", func(t *testing.T) {
+		report := AnalyzeUsage(results, UsageOptions{DirectoryDepth: 1})
+		require.Len(t, report.Directories, 2)
+		assert.Equal(t, GroupCount{Name: "src", Emojis: 4, Files: 2, Top: "🚀"}, report.Directories[0])
+	})
+
+	t.Run("top", func(t *testing.T) {
+		top := report.Top(1)
+		assert.Len(t, top.Emojis, 1)
+		assert.Len(t, top.Directories, 1)
+		assert.Equal(t, 3, top.UniqueEmojis, "totals are not truncated")
+		assert.Len(t, report.Top(0).Emojis, 3)
+	})
+}
+
+func TestTruncateDir(t *testing.T) {
+	assert.Equal(t, "a/b/c", truncateDir("a/b/c", 0))
+	assert.Equal(t, "a/b", truncateDir("a/b/c", 2))
+	assert.Equal(t, "a", truncateDir("a", 3))
+	assert.Equal(t, "/home", truncateDir("/home/user/project", 1))
+}
+
+func TestCategorizeFile(t *testing.T) {
+	tests := map[string]string{
+		"internal/app/main.go":      "source",
+		"internal/app/main_test.go": "test",
+		"README.md":                 "documentation",
+		"docs/guide.md":             "markdown",
+		".github/workflows/ci.yml":  "ci",
+		"config/settings.yaml":      "config",
+		"assets/logo.svg":           "other",
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, CategorizeFile(path), path)
+	}
+}
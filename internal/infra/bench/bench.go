@@ -0,0 +1,267 @@
+// Package bench measures the throughput of emoji detection and cleaning over a corpus
+// of files, for tracking performance across releases and sizing worker counts.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// SyntheticCorpus is the Name of corpora built by Synthetic.
+const SyntheticCorpus = "synthetic"
+
+// Benchmark names.
+const (
+	Detect = "detect"
+	Clean  = "clean"
+)
+
+// File is one file of a corpus, held in memory so benchmarks measure no disk I/O.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Corpus is the set of files the benchmarks process.
+type Corpus struct {
+	Name  string
+	Files []File
+	Bytes int64
+}
+
+// CorpusInfo describes a corpus in a report.
+type CorpusInfo struct {
+	Name  string `json:"name"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+// Info describes the corpus.
+func (c Corpus) Info() CorpusInfo {
+	return CorpusInfo{Name: c.Name, Files: len(c.Files), Bytes: c.Bytes}
+}
+
+// syntheticLines are the lines synthetic files are made of: source code, comments and
+// prose with and without emojis, in the proportions of a repository with a few.
+var syntheticLines = []string{
+	"func process(ctx context.Context, items []Item) error {",
+	"\tfor _, item := range items {",
+	"\t\tif err := item.Validate(); err != nil {",
+	"\t\t\treturn fmt.Errorf(\"invalid item %s: %w\", item.ID, err)",
+	"\t\t}",
+	"\t}",
+	"\treturn nil",
+	"}",
+	"// TODO: handle retries when the upstream service is unavailable",
+	"// Fixed the flaky test \U0001F389 thanks to the new clock",
+	"log.Info(\"deployment finished ✅\", \"duration\", elapsed)",
+	"## Getting started \U0001F680",
+	"Run `make install` and you're good to go :) :thumbsup:",
+	"const greeting = \"Hello, 世界\" // not an emoji",
+	"Status: \U0001F7E2 healthy, ⚠\ufe0f degraded, \U0001F534 down",
+	"Team: \U0001F469\u200d\U0001F4BB\U0001F468\U0001F3FD\u200d\U0001F52C \U0001F1F3\U0001F1F4",
+}
+
+// Synthetic builds a reproducible corpus of files files of about size bytes each,
+// mixing source code, comments and prose with and without emojis.
+func Synthetic(files, size int) Corpus {
+	random := rand.New(rand.NewSource(1)) // #nosec G404 - reproducible test data
+	corpus := Corpus{Name: SyntheticCorpus}
+	for i := 0; i < files; i++ {
+		var content strings.Builder
+		for content.Len() < size {
+			content.WriteString(syntheticLines[random.Intn(len(syntheticLines))])
+			content.WriteByte('\n')
+		}
+		corpus.add(File{Path: fmt.Sprintf("synthetic/file%04d.go", i), Content: []byte(content.String())})
+	}
+	return corpus
+}
+
+// Load reads the files at filePaths into a corpus named name.
+func Load(name string, filePaths []string) (Corpus, error) {
+	corpus := Corpus{Name: name}
+	for _, path := range filePaths {
+		content, err := os.ReadFile(path) // #nosec G304 - paths discovered by the caller
+		if err != nil {
+			return Corpus{}, err
+		}
+		corpus.add(File{Path: path, Content: content})
+	}
+	return corpus, nil
+}
+
+func (c *Corpus) add(file File) {
+	c.Files = append(c.Files, file)
+	c.Bytes += int64(len(file.Content))
+}
+
+// Options configure a benchmark run.
+type Options struct {
+	// Duration is how long each benchmark runs; it completes at least one operation.
+	Duration time.Duration
+	// Workers are the worker counts detection is measured with.
+	Workers []int
+}
+
+// Result is the measurement of one benchmark. An operation processes the whole corpus
+// once.
+type Result struct {
+	Name        string  `json:"name"`
+	Workers     int     `json:"workers"`
+	Operations  int     `json:"operations"`
+	NsPerOp     int64   `json:"ns_per_op"`
+	OpsPerSec   float64 `json:"ops_per_sec"`
+	FilesPerSec float64 `json:"files_per_sec"`
+	MBPerSec    float64 `json:"mb_per_sec"`
+	AllocsPerOp uint64  `json:"allocs_per_op"`
+	BytesPerOp  uint64  `json:"bytes_per_op"`
+}
+
+// Key identifies the benchmark of r across reports.
+func (r Result) Key() string {
+	return fmt.Sprintf("%s/workers=%d", r.Name, r.Workers)
+}
+
+// Report is the outcome of a benchmark run with the environment it ran in.
+type Report struct {
+	Version   string     `json:"version"`
+	GoVersion string     `json:"go_version"`
+	OS        string     `json:"os"`
+	Arch      string     `json:"arch"`
+	CPUs      int        `json:"cpus"`
+	Corpus    CorpusInfo `json:"corpus"`
+	Results   []Result   `json:"results"`
+}
+
+// Run measures detection with each worker count of opts and cleaning over corpus,
+// stopping early when ctx is cancelled.
+func Run(ctx context.Context, corpus Corpus, patterns types.EmojiPatterns, config types.ProcessingConfig, opts Options) Report {
+	report := Report{
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+		Corpus:    corpus.Info(),
+	}
+	patterns = processor.FilterPatterns(patterns, config)
+
+	for _, workers := range opts.Workers {
+		report.Results = append(report.Results, measure(ctx, Detect, workers, corpus, opts.Duration, func() {
+			detectAll(corpus.Files, patterns, config, workers)
+		}))
+	}
+	report.Results = append(report.Results, measure(ctx, Clean, 1, corpus, opts.Duration, func() {
+		for _, file := range corpus.Files {
+			_ = processor.CleanContent(string(file.Content), patterns, "")
+		}
+	}))
+	return report
+}
+
+// detectAll detects emojis in every file, spreading the files over workers goroutines.
+func detectAll(files []File, patterns types.EmojiPatterns, config types.ProcessingConfig, workers int) {
+	if workers <= 1 {
+		for _, file := range files {
+			_ = processor.DetectContent(file.Content, patterns, config)
+		}
+		return
+	}
+	jobs := make(chan []byte)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for content := range jobs {
+				_ = processor.DetectContent(content, patterns, config)
+			}
+		}()
+	}
+	for _, file := range files {
+		jobs <- file.Content
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// measure runs op until duration has passed and reports its throughput and
+// allocations.
+func measure(ctx context.Context, name string, workers int, corpus Corpus, duration time.Duration, op func()) Result {
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	operations := 0
+	start := time.Now()
+	for operations == 0 || (time.Since(start) < duration && ctx.Err() == nil) {
+		op()
+		operations++
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	seconds := elapsed.Seconds()
+	return Result{
+		Name:        name,
+		Workers:     workers,
+		Operations:  operations,
+		NsPerOp:     elapsed.Nanoseconds() / int64(operations),
+		OpsPerSec:   float64(operations) / seconds,
+		FilesPerSec: float64(operations*len(corpus.Files)) / seconds,
+		MBPerSec:    float64(int64(operations)*corpus.Bytes) / 1e6 / seconds,
+		AllocsPerOp: (after.Mallocs - before.Mallocs) / uint64(operations),
+		BytesPerOp:  (after.TotalAlloc - before.TotalAlloc) / uint64(operations),
+	}
+}
+
+// Regression is a benchmark slower than in the baseline report.
+type Regression struct {
+	Key      string  `json:"key"`
+	Baseline float64 `json:"baseline_mb_per_sec"`
+	Current  float64 `json:"current_mb_per_sec"`
+	// Change is the relative throughput change, -0.25 for a quarter slower.
+	Change float64 `json:"change"`
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: %.2f MB/s, %.1f%% slower than the baseline %.2f MB/s",
+		r.Key, r.Current, -r.Change*100, r.Baseline)
+}
+
+// Compare returns the benchmarks of current whose throughput dropped by more than
+// tolerance (0.1 for 10%) from baseline. Benchmarks missing from either report are
+// not compared.
+func Compare(baseline, current Report, tolerance float64) []Regression {
+	previous := make(map[string]Result, len(baseline.Results))
+	for _, result := range baseline.Results {
+		previous[result.Key()] = result
+	}
+
+	var regressions []Regression
+	for _, result := range current.Results {
+		before, ok := previous[result.Key()]
+		if !ok || before.MBPerSec <= 0 {
+			continue
+		}
+		change := result.MBPerSec/before.MBPerSec - 1
+		if change < -tolerance {
+			regressions = append(regressions, Regression{
+				Key:      result.Key(),
+				Baseline: before.MBPerSec,
+				Current:  result.MBPerSec,
+				Change:   change,
+			})
+		}
+	}
+	return regressions
+}
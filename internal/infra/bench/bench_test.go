@@ -0,0 +1,81 @@
+package bench
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynthetic(t *testing.T) {
+	corpus := Synthetic(3, 1024)
+	assert.Equal(t, SyntheticCorpus, corpus.Name)
+	require.Len(t, corpus.Files, 3)
+	for _, file := range corpus.Files {
+		assert.GreaterOrEqual(t, len(file.Content), 1024)
+	}
+	assert.Equal(t, corpus, Synthetic(3, 1024), "synthetic corpora are reproducible")
+
+	detection := detector.DetectEmojis(corpus.Files[0].Content, detector.DefaultEmojiPatterns()).Unwrap()
+	assert.NotZero(t, detection.TotalCount)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	require.NoError(t, os.WriteFile(path, []byte("// 🚀\n"), 0600))
+
+	corpus, err := Load("repo", []string{path})
+	require.NoError(t, err)
+	assert.Equal(t, CorpusInfo{Name: "repo", Files: 1, Bytes: 8}, corpus.Info())
+
+	_, err = Load("repo", []string{filepath.Join(dir, "missing.go")})
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	corpus := Synthetic(4, 512)
+	config := types.DefaultProcessingConfig()
+	report := Run(context.Background(), corpus, detector.DefaultEmojiPatterns(), config, Options{
+		Duration: time.Millisecond,
+		Workers:  []int{1, 2},
+	})
+
+	assert.Equal(t, corpus.Info(), report.Corpus)
+	require.Len(t, report.Results, 3)
+	assert.Equal(t, []string{"detect/workers=1", "detect/workers=2", "clean/workers=1"},
+		[]string{report.Results[0].Key(), report.Results[1].Key(), report.Results[2].Key()})
+	for _, result := range report.Results {
+		assert.Positive(t, result.Operations)
+		assert.Positive(t, result.NsPerOp)
+		assert.Positive(t, result.MBPerSec)
+		assert.InDelta(t, result.OpsPerSec*4, result.FilesPerSec, 1e-6)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	report := func(detect, clean float64) Report {
+		return Report{Results: []Result{
+			{Name: Detect, Workers: 1, MBPerSec: detect},
+			{Name: Clean, Workers: 1, MBPerSec: clean},
+		}}
+	}
+
+	assert.Empty(t, Compare(report(10, 5), report(9.5, 6), 0.1))
+
+	regressions := Compare(report(10, 5), report(8, 5), 0.1)
+	require.Len(t, regressions, 1)
+	assert.Equal(t, "detect/workers=1", regressions[0].Key)
+	assert.InDelta(t, -0.2, regressions[0].Change, 1e-9)
+	assert.Equal(t, "detect/workers=1: 8.00 MB/s, 20.0% slower than the baseline 10.00 MB/s", regressions[0].String())
+
+	t.Run("benchmarks missing from the baseline are not compared", func(t *testing.T) {
+		assert.Empty(t, Compare(Report{}, report(1, 1), 0))
+	})
+}
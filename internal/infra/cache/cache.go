@@ -0,0 +1,289 @@
+// Package cache provides an on-disk cache of detection results keyed by file content hash.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+const (
+	// formatVersion is bumped whenever the cache file layout or detection output changes
+	// in a way that makes existing entries invalid.
+	formatVersion = 4
+
+	// EnvDir overrides the default cache directory.
+	EnvDir = "ANTIMOJI_CACHE_DIR"
+
+	// maxEntryAge is how long an entry is kept without being used.
+	maxEntryAge = 30 * 24 * time.Hour
+)
+
+// cacheFileName matches the files written by Save, so Clear never removes anything else.
+var cacheFileName = regexp.MustCompile(`^[0-9a-f]{64}\.json$`)
+
+// entry is a cached detection result.
+type entry struct {
+	Result types.DetectionResult `json:"result"`
+	Used   time.Time             `json:"used"`
+}
+
+// cacheFile is the on-disk representation of a cache.
+type cacheFile struct {
+	Version     int              `json:"version"`
+	Fingerprint string           `json:"fingerprint"`
+	Entries     map[string]entry `json:"entries"`
+}
+
+// Cache stores detection results for a single configuration fingerprint.
+// It is safe for concurrent use.
+type Cache struct {
+	path        string
+	fingerprint string
+
+	mu      sync.Mutex
+	entries map[string]entry
+	dirty   bool
+	hits    int
+	misses  int
+}
+
+// DefaultDir returns the cache directory: $ANTIMOJI_CACHE_DIR if set, otherwise
+// antimoji inside the user cache directory (e.g. ~/.cache/antimoji).
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir, nil
+	}
+
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(base, "antimoji"), nil
+}
+
+// Fingerprint identifies the detection configuration. Results cached under one
+// fingerprint are never used for another, so changing profiles, patterns or emoji
+// data invalidates the cache.
+func Fingerprint(patterns types.EmojiPatterns, config types.ProcessingConfig) string {
+	data, _ := json.Marshal(struct {
+		Version    int
+		Patterns   types.EmojiPatterns
+		Unicode    bool
+		Emoticons  bool
+		Custom     bool
+		Shortcodes bool
+		Kaomoji    bool
+		Decorative bool
+		Escapes    bool
+		Evasion    bool
+		Hygiene    bool
+	}{formatVersion, patterns, config.EnableUnicode, config.EnableEmoticons, config.EnableCustom, config.EnableShortcodes,
+		config.EnableKaomoji, config.EnableDecorative, config.EnableEscapes, config.EnableEvasion, config.EnableHygiene})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Open loads the cache for fingerprint from dir. A missing or unreadable cache file
+// yields an empty cache, since the cache is only an optimisation.
+func Open(dir, fingerprint string) types.Result[*Cache] {
+	if dir == "" {
+		return types.Err[*Cache](errors.New("cache directory cannot be empty"))
+	}
+
+	c := &Cache{
+		path:        filepath.Join(dir, fingerprint+".json"),
+		fingerprint: fingerprint,
+		entries:     make(map[string]entry),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return types.Ok(c)
+		}
+		return types.Err[*Cache](fmt.Errorf("failed to read cache %s: %w", c.path, err))
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Version != formatVersion || file.Fingerprint != fingerprint {
+		// Corrupt or outdated cache; start over
+		c.dirty = true
+		return types.Ok(c)
+	}
+	if file.Entries != nil {
+		c.entries = file.Entries
+	}
+
+	return types.Ok(c)
+}
+
+// NewMemory returns an empty cache for fingerprint that is kept in memory only; Save
+// does nothing.
+func NewMemory(fingerprint string) *Cache {
+	return &Cache{fingerprint: fingerprint, entries: make(map[string]entry)}
+}
+
+// Get returns the cached detection result for a content hash.
+func (c *Cache) Get(contentHash string) (types.DetectionResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[contentHash]
+	if !ok {
+		c.misses++
+		return types.DetectionResult{}, false
+	}
+
+	c.hits++
+	e.Used = time.Now()
+	c.entries[contentHash] = e
+	c.dirty = true
+	return e.Result, true
+}
+
+// Put stores the detection result for a content hash.
+func (c *Cache) Put(contentHash string, result types.DetectionResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[contentHash] = entry{Result: result, Used: time.Now()}
+	c.dirty = true
+}
+
+// Stats returns the number of cache hits and misses since the cache was opened.
+func (c *Cache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Save writes the cache to disk if it changed, dropping entries that have not been
+// used recently.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty || c.path == "" {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-maxEntryAge)
+	for hash, e := range c.entries {
+		if e.Used.Before(cutoff) {
+			delete(c.entries, hash)
+		}
+	}
+
+	data, err := json.Marshal(cacheFile{Version: formatVersion, Fingerprint: c.fingerprint, Entries: c.entries})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file and rename so concurrent runs never see a partial cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".antimoji-cache-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary cache file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to replace cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// Clear removes all cache files from dir and returns how many were removed.
+// A missing directory is not an error.
+func Clear(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() || !cacheFileName.MatchString(e.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return removed, fmt.Errorf("failed to remove cache file: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Usage describes the cache files in a directory.
+type Usage struct {
+	// Files is the number of cache files
+	Files int
+	// Entries is the number of cached results in current files
+	Entries int
+	// Bytes is the total size of the cache files
+	Bytes int64
+	// Stale is the number of files that are corrupt or from another cache format;
+	// scans discard them
+	Stale int
+}
+
+// Inspect reports on the cache files in dir. A missing directory has no files.
+func Inspect(dir string) (Usage, error) {
+	var usage Usage
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return usage, nil
+		}
+		return usage, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !cacheFileName.MatchString(e.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name())) // #nosec G304 - names are matched against cacheFileName
+		if err != nil {
+			return usage, fmt.Errorf("failed to read cache file: %w", err)
+		}
+		usage.Files++
+		usage.Bytes += int64(len(data))
+
+		var file cacheFile
+		if err := json.Unmarshal(data, &file); err != nil || file.Version != formatVersion ||
+			file.Fingerprint+".json" != e.Name() {
+			usage.Stale++
+			continue
+		}
+		usage.Entries += len(file.Entries)
+	}
+
+	return usage, nil
+}
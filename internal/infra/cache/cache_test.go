@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPatterns() types.EmojiPatterns {
+	return types.EmojiPatterns{
+		UnicodeRanges:    []types.UnicodeRange{{Start: 0x1F600, End: 0x1F64F, Name: "Emoticons"}},
+		EmoticonPatterns: []string{":)"},
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	config := types.ProcessingConfig{EnableUnicode: true, EnableEmoticons: true}
+	base := Fingerprint(testPatterns(), config)
+
+	assert.Len(t, base, 64)
+	assert.Equal(t, base, Fingerprint(testPatterns(), config))
+
+	// Detection-relevant changes produce a different fingerprint
+	config.EnableEmoticons = false
+	assert.NotEqual(t, base, Fingerprint(testPatterns(), config))
+
+	patterns := testPatterns()
+	patterns.CustomPatterns = []string{":rocket:"}
+	assert.NotEqual(t, base, Fingerprint(patterns, types.ProcessingConfig{EnableUnicode: true, EnableEmoticons: true}))
+}
+
+func TestCache_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	fingerprint := Fingerprint(testPatterns(), types.ProcessingConfig{EnableUnicode: true})
+	result := types.DetectionResult{
+		Emojis:     []types.EmojiMatch{{Emoji: "😀", Start: 0, End: 4, Line: 1, Column: 1}},
+		TotalCount: 1, UniqueCount: 1, ProcessedBytes: 4, Success: true,
+	}
+
+	c := Open(dir, fingerprint).Unwrap()
+	_, ok := c.Get("hash")
+	assert.False(t, ok)
+	c.Put("hash", result)
+	require.NoError(t, c.Save())
+
+	reopened := Open(dir, fingerprint).Unwrap()
+	cached, ok := reopened.Get("hash")
+	require.True(t, ok)
+	assert.Equal(t, result, cached)
+
+	hits, misses := reopened.Stats()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 0, misses)
+
+	t.Run("other fingerprints do not see entries", func(t *testing.T) {
+		other := Open(dir, Fingerprint(testPatterns(), types.ProcessingConfig{})).Unwrap()
+		_, ok := other.Get("hash")
+		assert.False(t, ok)
+	})
+}
+
+func TestNewMemory(t *testing.T) {
+	c := NewMemory(Fingerprint(testPatterns(), types.ProcessingConfig{EnableUnicode: true}))
+	c.Put("hash", types.DetectionResult{TotalCount: 1})
+
+	cached, ok := c.Get("hash")
+	require.True(t, ok)
+	assert.Equal(t, 1, cached.TotalCount)
+	assert.NoError(t, c.Save(), "saving a memory cache does nothing")
+}
+
+func TestCache_CorruptFileStartsEmpty(t *testing.T) {
+	dir := t.TempDir()
+	fingerprint := Fingerprint(testPatterns(), types.ProcessingConfig{})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fingerprint+".json"), []byte("{not json"), 0600))
+
+	c := Open(dir, fingerprint).Unwrap()
+	_, ok := c.Get("hash")
+	assert.False(t, ok)
+
+	// Saving replaces the corrupt file
+	require.NoError(t, c.Save())
+	assert.True(t, Open(dir, fingerprint).IsOk())
+}
+
+func TestCache_SavePrunesUnusedEntries(t *testing.T) {
+	dir := t.TempDir()
+	fingerprint := Fingerprint(testPatterns(), types.ProcessingConfig{})
+
+	c := Open(dir, fingerprint).Unwrap()
+	c.Put("fresh", types.DetectionResult{})
+	c.entries["stale"] = entry{Used: time.Now().Add(-2 * maxEntryAge)}
+	require.NoError(t, c.Save())
+
+	reopened := Open(dir, fingerprint).Unwrap()
+	_, ok := reopened.Get("stale")
+	assert.False(t, ok)
+	_, ok = reopened.Get("fresh")
+	assert.True(t, ok)
+}
+
+func TestClear(t *testing.T) {
+	dir := t.TempDir()
+	for _, config := range []types.ProcessingConfig{{}, {EnableUnicode: true}} {
+		c := Open(dir, Fingerprint(testPatterns(), config)).Unwrap()
+		c.Put("hash", types.DetectionResult{})
+		require.NoError(t, c.Save())
+	}
+	unrelated := filepath.Join(dir, "notes.json")
+	require.NoError(t, os.WriteFile(unrelated, []byte("{}"), 0600))
+
+	removed, err := Clear(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	assert.FileExists(t, unrelated)
+
+	t.Run("missing directory", func(t *testing.T) {
+		removed, err := Clear(filepath.Join(dir, "missing"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, removed)
+	})
+}
+
+func TestInspect(t *testing.T) {
+	dir := t.TempDir()
+	c := Open(dir, Fingerprint(testPatterns(), types.ProcessingConfig{})).Unwrap()
+	c.Put("a", types.DetectionResult{})
+	c.Put("b", types.DetectionResult{})
+	require.NoError(t, c.Save())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, strings.Repeat("0", 64)+".json"), []byte("{not json"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.json"), []byte("{}"), 0600))
+
+	usage, err := Inspect(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage.Files)
+	assert.Equal(t, 2, usage.Entries)
+	assert.Equal(t, 1, usage.Stale)
+	assert.Positive(t, usage.Bytes)
+
+	t.Run("missing directory", func(t *testing.T) {
+		usage, err := Inspect(filepath.Join(dir, "missing"))
+		require.NoError(t, err)
+		assert.Equal(t, Usage{}, usage)
+	})
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv(EnvDir, "/tmp/antimoji-test-cache")
+
+	dir, err := DefaultDir()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/antimoji-test-cache", dir)
+}
@@ -0,0 +1,318 @@
+// Package containerimage reads the files shipped in container images, from a local
+// image tarball or an image pulled with docker.
+package containerimage
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoLayers indicates the tarball is neither a `docker save` archive nor an OCI image
+// layout, or lists no layers.
+var ErrNoLayers = errors.New("no image layers found")
+
+// maxMetadataSize bounds the manifests and indexes read into memory.
+const maxMetadataSize = 1 << 20
+
+// whiteoutPrefix marks the files a layer deletes from the layers below it.
+const whiteoutPrefix = ".wh."
+
+// File is a regular file added by a layer of an image.
+type File struct {
+	// Layer is the 1-based position of the layer, from the base of the image
+	Layer int
+	// Digest identifies the layer, e.g. sha256:4f5e...
+	Digest string
+	// Path is the absolute path of the file in the image
+	Path    string
+	Size    int64
+	Content []byte
+}
+
+// Layer describes a layer of an image.
+type Layer struct {
+	Digest string
+	// name is the entry of the layer in the tarball
+	name string
+}
+
+// Options controls which files Walk reads.
+type Options struct {
+	// MaxFileSize skips larger files, which are reported with a nil Content; zero reads
+	// every file
+	MaxFileSize int64
+}
+
+// saveImage writes the image ref to the tarball at dest; tests replace it.
+var saveImage = dockerSave
+
+// Walk calls fn for each regular file of each layer of the image ref: a path to an
+// image tarball written by `docker save` or in the OCI image layout, or an image
+// reference such as ghcr.io/org/app:tag that is pulled and saved with docker. Files
+// deleted by a later layer are still reported, since they are shipped in the image.
+// It returns the layers of the image.
+func Walk(ctx context.Context, ref string, opts Options, fn func(File) error) ([]Layer, error) {
+	tarball := ref
+	if _, err := os.Stat(ref); err != nil {
+		dir, err := os.MkdirTemp("", "antimoji-image-")
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+
+		tarball = filepath.Join(dir, "image.tar")
+		if err := saveImage(ctx, ref, tarball); err != nil {
+			return nil, fmt.Errorf("failed to pull %s: %w", ref, err)
+		}
+	}
+	return walkTarball(tarball, opts, fn)
+}
+
+// walkTarball reads the layers listed by the image tarball at file.
+func walkTarball(file string, opts Options, fn func(File) error) ([]Layer, error) {
+	metadata, err := readMetadata(file)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := metadata.layers()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	positions := make(map[string]int, len(layers))
+	for i, layer := range layers {
+		positions[layer.name] = i + 1
+	}
+
+	err = eachEntry(file, func(header *tar.Header, r io.Reader) error {
+		position, ok := positions[path.Clean(header.Name)]
+		if !ok {
+			return nil
+		}
+		if err := walkLayer(r, position, layers[position-1].Digest, opts, fn); err != nil {
+			return fmt.Errorf("layer %d (%s): %w", position, layers[position-1].Digest, err)
+		}
+		return nil
+	})
+	return layers, err
+}
+
+// walkLayer calls fn for the regular files of the layer tarball read from r, which may
+// be gzip-compressed.
+func walkLayer(r io.Reader, position int, digest string, opts Options, fn func(File) error) error {
+	buffered := bufio.NewReader(r)
+	var layer io.Reader = buffered
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = gz.Close() }()
+		layer = gz
+	}
+
+	entries := tar.NewReader(layer)
+	for {
+		header, err := entries.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg || strings.HasPrefix(path.Base(header.Name), whiteoutPrefix) {
+			continue
+		}
+
+		file := File{
+			Layer:  position,
+			Digest: digest,
+			Path:   path.Join("/", header.Name),
+			Size:   header.Size,
+		}
+		if opts.MaxFileSize <= 0 || header.Size <= opts.MaxFileSize {
+			if file.Content, err = io.ReadAll(entries); err != nil {
+				return err
+			}
+		}
+		if err := fn(file); err != nil {
+			return err
+		}
+	}
+}
+
+// metadata holds the manifests of an image tarball.
+type metadata struct {
+	// manifest is the manifest.json of `docker save`
+	manifest []byte
+	// index is the index.json of an OCI image layout
+	index []byte
+	// blobs are the small blobs of an OCI image layout, by digest
+	blobs map[string][]byte
+}
+
+// readMetadata reads the manifests of the image tarball at file.
+func readMetadata(file string) (metadata, error) {
+	m := metadata{blobs: make(map[string][]byte)}
+	err := eachEntry(file, func(header *tar.Header, r io.Reader) error {
+		name := path.Clean(header.Name)
+		var target *[]byte
+		switch {
+		case header.Size > maxMetadataSize || header.Typeflag != tar.TypeReg:
+			return nil
+		case name == "manifest.json":
+			target = &m.manifest
+		case name == "index.json":
+			target = &m.index
+		case strings.HasPrefix(name, "blobs/"):
+			content, err := io.ReadAll(r)
+			if err == nil {
+				m.blobs[blobDigest(name)] = content
+			}
+			return err
+		default:
+			return nil
+		}
+		content, err := io.ReadAll(r)
+		*target = content
+		return err
+	})
+	return m, err
+}
+
+// layers returns the layers of the image, from its base: those of the first image of
+// manifest.json or, without one, of the first manifest of index.json.
+func (m metadata) layers() ([]Layer, error) {
+	if m.manifest != nil {
+		var images []struct {
+			Layers []string `json:"Layers"`
+		}
+		if err := json.Unmarshal(m.manifest, &images); err != nil {
+			return nil, fmt.Errorf("invalid manifest.json: %w", err)
+		}
+		if len(images) == 0 || len(images[0].Layers) == 0 {
+			return nil, ErrNoLayers
+		}
+		layers := make([]Layer, len(images[0].Layers))
+		for i, name := range images[0].Layers {
+			layers[i] = Layer{Digest: layerDigest(name), name: path.Clean(name)}
+		}
+		return layers, nil
+	}
+
+	if m.index == nil {
+		return nil, ErrNoLayers
+	}
+	var manifest struct {
+		Manifests []struct {
+			Digest string `json:"digest"`
+		} `json:"manifests"`
+		Layers []struct {
+			Digest string `json:"digest"`
+		} `json:"layers"`
+	}
+	// Indexes nest until a manifest listing layers, e.g. for multi-platform images
+	content := m.index
+	for depth := 0; ; depth++ {
+		manifest.Manifests, manifest.Layers = nil, nil
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid OCI manifest: %w", err)
+		}
+		if len(manifest.Layers) > 0 {
+			break
+		}
+		if len(manifest.Manifests) == 0 || depth > 4 {
+			return nil, ErrNoLayers
+		}
+		next, ok := m.blobs[manifest.Manifests[0].Digest]
+		if !ok {
+			return nil, fmt.Errorf("%w: manifest %s is missing", ErrNoLayers, manifest.Manifests[0].Digest)
+		}
+		content = next
+	}
+
+	layers := make([]Layer, len(manifest.Layers))
+	for i, layer := range manifest.Layers {
+		algorithm, hex, _ := strings.Cut(layer.Digest, ":")
+		layers[i] = Layer{Digest: layer.Digest, name: path.Join("blobs", algorithm, hex)}
+	}
+	return layers, nil
+}
+
+// blobDigest returns the digest of the blob at name, e.g. sha256:abc for blobs/sha256/abc.
+func blobDigest(name string) string {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 {
+		return name
+	}
+	return parts[1] + ":" + parts[2]
+}
+
+// layerDigest returns the digest of the layer at name in a `docker save` tarball: that of
+// its blob, or the layer ID for archives written before Docker 25 (<id>/layer.tar).
+func layerDigest(name string) string {
+	name = path.Clean(name)
+	if strings.HasPrefix(name, "blobs/") {
+		return blobDigest(name)
+	}
+	if dir := path.Dir(name); dir != "." {
+		return dir
+	}
+	return name
+}
+
+// eachEntry calls fn for each entry of the tarball at file.
+func eachEntry(file string, fn func(*tar.Header, io.Reader) error) error {
+	f, err := os.Open(file) // #nosec G304 - image tarball given by the user
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	entries := tar.NewReader(f)
+	for {
+		header, err := entries.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if err := fn(header, entries); err != nil {
+			return err
+		}
+	}
+}
+
+// dockerSave pulls ref unless it is already present, then saves it to dest.
+func dockerSave(ctx context.Context, ref, dest string) error {
+	if err := runDocker(ctx, "image", "inspect", ref); err != nil {
+		if err := runDocker(ctx, "pull", ref); err != nil {
+			return err
+		}
+	}
+	return runDocker(ctx, "save", "-o", dest, ref)
+}
+
+// runDocker runs the docker CLI, including its stderr in errors.
+func runDocker(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "docker", args...) // #nosec G204 - arguments are constructed internally
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package containerimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tarEntry is a regular file written by writeTar.
+type tarEntry struct {
+	name    string
+	content []byte
+}
+
+func writeTar(t *testing.T, entries ...tarEntry) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(entry.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, content []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func jsonContent(t *testing.T, v interface{}) []byte {
+	content, err := json.Marshal(v)
+	require.NoError(t, err)
+	return content
+}
+
+func writeFile(t *testing.T, content []byte) string {
+	path := filepath.Join(t.TempDir(), "image.tar")
+	require.NoError(t, os.WriteFile(path, content, 0600))
+	return path
+}
+
+// walkAll returns the files of the image at ref by path, with their layer.
+func walkAll(t *testing.T, ref string, opts Options) ([]Layer, map[string]File) {
+	files := make(map[string]File)
+	layers, err := Walk(context.Background(), ref, opts, func(file File) error {
+		files[file.Path] = file
+		return nil
+	})
+	require.NoError(t, err)
+	return layers, files
+}
+
+func TestWalk_DockerSave(t *testing.T) {
+	base := writeTar(t, tarEntry{"etc/app.yaml", []byte("greeting: hi 🚀\n")}, tarEntry{"bin/app", []byte{0, 1, 2}})
+	top := gzipped(t, writeTar(t, tarEntry{"etc/.wh.old.conf", nil}, tarEntry{"app/run.sh", []byte("echo ✅\n")}))
+	image := writeFile(t, writeTar(t,
+		tarEntry{"abc123/layer.tar", base},
+		tarEntry{"def456/layer.tar", top},
+		tarEntry{"manifest.json", jsonContent(t, []map[string]interface{}{
+			{"Config": "config.json", "Layers": []string{"abc123/layer.tar", "def456/layer.tar"}},
+		})},
+	))
+
+	layers, files := walkAll(t, image, Options{})
+	assert.Equal(t, []string{"abc123", "def456"}, []string{layers[0].Digest, layers[1].Digest})
+	require.Len(t, files, 3)
+	assert.Equal(t, 1, files["/etc/app.yaml"].Layer)
+	assert.Equal(t, "greeting: hi 🚀\n", string(files["/etc/app.yaml"].Content))
+	assert.Equal(t, 2, files["/app/run.sh"].Layer)
+	assert.Equal(t, "def456", files["/app/run.sh"].Digest)
+	assert.NotContains(t, files, "/etc/.wh.old.conf")
+
+	t.Run("large files have no content", func(t *testing.T) {
+		_, files := walkAll(t, image, Options{MaxFileSize: 10})
+		assert.Nil(t, files["/etc/app.yaml"].Content)
+		assert.Equal(t, int64(len("greeting: hi 🚀\n")), files["/etc/app.yaml"].Size)
+		assert.NotNil(t, files["/app/run.sh"].Content)
+	})
+}
+
+func TestWalk_OCILayout(t *testing.T) {
+	layer := gzipped(t, writeTar(t, tarEntry{"usr/share/motd", []byte("welcome 🎉\n")}))
+	manifest := jsonContent(t, map[string]interface{}{
+		"layers": []map[string]string{{"digest": "sha256:1111"}},
+	})
+	platforms := jsonContent(t, map[string]interface{}{
+		"manifests": []map[string]string{{"digest": "sha256:2222"}},
+	})
+	image := writeFile(t, writeTar(t,
+		tarEntry{"oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`)},
+		tarEntry{"index.json", jsonContent(t, map[string]interface{}{
+			"manifests": []map[string]string{{"digest": "sha256:3333"}},
+		})},
+		tarEntry{"blobs/sha256/3333", platforms},
+		tarEntry{"blobs/sha256/2222", manifest},
+		tarEntry{"blobs/sha256/1111", layer},
+	))
+
+	layers, files := walkAll(t, image, Options{})
+	require.Len(t, layers, 1)
+	assert.Equal(t, "sha256:1111", layers[0].Digest)
+	assert.Equal(t, File{Layer: 1, Digest: "sha256:1111", Path: "/usr/share/motd", Size: 13, Content: []byte("welcome 🎉\n")}, files["/usr/share/motd"])
+}
+
+func TestWalk_NotAnImage(t *testing.T) {
+	_, err := Walk(context.Background(), writeFile(t, writeTar(t, tarEntry{"readme.txt", []byte("hi")})), Options{}, func(File) error { return nil })
+	assert.ErrorIs(t, err, ErrNoLayers)
+}
+
+func TestWalk_PullsReferences(t *testing.T) {
+	original := saveImage
+	defer func() { saveImage = original }()
+
+	layer := writeTar(t, tarEntry{"config.json", []byte("{}")})
+	var saved string
+	saveImage = func(_ context.Context, ref, dest string) error {
+		saved = ref
+		return os.WriteFile(dest, writeTar(t,
+			tarEntry{"blobs/sha256/aaaa", layer},
+			tarEntry{"manifest.json", jsonContent(t, []map[string]interface{}{{"Layers": []string{"blobs/sha256/aaaa"}}})},
+		), 0600)
+	}
+	layers, files := walkAll(t, "ghcr.io/org/app:1.0", Options{})
+	assert.Equal(t, "ghcr.io/org/app:1.0", saved)
+	assert.Equal(t, "sha256:aaaa", layers[0].Digest)
+	assert.Contains(t, files, "/config.json")
+
+	saveImage = func(context.Context, string, string) error { return errors.New("pull access denied") }
+	_, err := Walk(context.Background(), "ghcr.io/org/private:1.0", Options{}, func(File) error { return nil })
+	assert.EqualError(t, err, "failed to pull ghcr.io/org/private:1.0: pull access denied")
+}
@@ -0,0 +1,234 @@
+// Package daemon provides the Unix socket transport between antimoji clients and a
+// long-running antimoji daemon.
+//
+// A connection carries a single exchange: the client writes one JSON Request and the
+// server answers with one JSON Response. Requests are handled one at a time, so a
+// handler may change process-wide state such as the working directory.
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvSocket overrides the default socket path.
+const EnvSocket = "ANTIMOJI_SOCKET"
+
+// envRuntimeDir names the per-user runtime directory the default socket lives in.
+const envRuntimeDir = "XDG_RUNTIME_DIR"
+
+// ErrNotRunning indicates no daemon is listening on the socket.
+var ErrNotRunning = errors.New("no antimoji daemon is running")
+
+// Request asks the daemon to run a command as if it were started in Dir.
+type Request struct {
+	// Dir is the client's working directory
+	Dir string `json:"dir"`
+	// Args are the command-line arguments of the command, starting with its name
+	Args []string `json:"args"`
+	// Env holds the client's ANTIMOJI_* environment variables in os.Environ format
+	Env []string `json:"env,omitempty"`
+}
+
+// Response is the outcome of a Request.
+type Response struct {
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+	// Error is the message of the error the command failed with, empty on success
+	Error string `json:"error,omitempty"`
+	// ThresholdExceeded reports that the command failed because of a policy threshold
+	ThresholdExceeded bool `json:"threshold_exceeded,omitempty"`
+	// Class names the error class of the failure, such as "violations" or "io"
+	Class string `json:"class,omitempty"`
+}
+
+// Handler runs a request.
+type Handler func(ctx context.Context, req Request) Response
+
+// DefaultSocketPath returns the socket of the daemon for dir: $ANTIMOJI_SOCKET if set,
+// otherwise a path derived from dir in the user's socket directory, so that a daemon
+// started in a repository is found by clients running there.
+func DefaultSocketPath(dir string) string {
+	if path := os.Getenv(EnvSocket); path != "" {
+		return path
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	sum := sha256.Sum256([]byte(dir))
+	return filepath.Join(socketDir(), hex.EncodeToString(sum[:6])+".sock")
+}
+
+// socketDir returns the directory of the default sockets: antimoji in
+// $XDG_RUNTIME_DIR, or a per-user directory in the temporary directory.
+func socketDir() string {
+	if dir := os.Getenv(envRuntimeDir); dir != "" {
+		return filepath.Join(dir, "antimoji")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("antimoji-%d", os.Getuid()))
+}
+
+// Listen creates the socket at path, readable and writable only by the current user.
+// A missing parent directory is created accessible only by the current user. A socket
+// left behind by a daemon that exited is replaced; a live one is an error.
+func Listen(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory %s: %w", dir, err)
+	}
+	if dir == socketDir() {
+		// The directory name is predictable, so another user may have created it first
+		if err := checkPrivateDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := os.Lstat(path); err == nil {
+		conn, dialErr := net.DialTimeout("unix", path, time.Second)
+		if dialErr == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("a daemon is already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	}
+
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	return listener, nil
+}
+
+// checkPrivateDir returns an error unless dir is owned by the current user and
+// inaccessible to anyone else, where the platform reports file ownership.
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to inspect socket directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("socket directory %s is not a directory", dir)
+	}
+	uid, ok := fileOwner(info)
+	if !ok {
+		return nil
+	}
+	if uid != os.Getuid() {
+		return fmt.Errorf("socket directory %s is owned by uid %d, not the current user", dir, uid)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("socket directory %s is accessible to other users (mode %04o)", dir, info.Mode().Perm())
+	}
+	return nil
+}
+
+// Serve handles connections on listener with handler until ctx is done or no request
+// arrives for idleTimeout (zero waits forever). It closes listener before returning.
+func Serve(ctx context.Context, listener net.Listener, handler Handler, idleTimeout time.Duration) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var idle *time.Timer
+	if idleTimeout > 0 {
+		idle = time.AfterFunc(idleTimeout, cancel)
+		defer idle.Stop()
+	}
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { _ = conn.Close() }()
+
+			var req Request
+			if err := json.NewDecoder(conn).Decode(&req); err != nil {
+				_ = json.NewEncoder(conn).Encode(Response{Error: fmt.Sprintf("invalid request: %v", err)})
+				return
+			}
+
+			mu.Lock()
+			if idle != nil {
+				idle.Stop()
+			}
+			resp := handler(ctx, req)
+			if idle != nil {
+				idle.Reset(idleTimeout)
+			}
+			mu.Unlock()
+
+			_ = json.NewEncoder(conn).Encode(resp)
+		}()
+	}
+}
+
+// Call sends req to the daemon listening on path and waits for its response. It
+// returns an error wrapping ErrNotRunning when nothing accepts the connection, and
+// refuses a socket owned by another user.
+func Call(ctx context.Context, path string, req Request) (Response, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return Response{}, fmt.Errorf("%w on %s: %v", ErrNotRunning, path, err)
+	}
+	// Refuse a socket another user put in place to receive our requests
+	if uid, ok := fileOwner(info); ok && uid != os.Getuid() {
+		return Response{}, fmt.Errorf("daemon socket %s is owned by uid %d, not the current user", path, uid)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return Response{}, fmt.Errorf("%w on %s: %v", ErrNotRunning, path, err)
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("failed to send request to daemon: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("failed to read daemon response: %w", err)
+	}
+	return resp, nil
+}
+
+// Environ returns the ANTIMOJI_* variables of environ, the environment a Request
+// forwards to the daemon.
+func Environ(environ []string) []string {
+	var forwarded []string
+	for _, kv := range environ {
+		if strings.HasPrefix(kv, "ANTIMOJI_") {
+			forwarded = append(forwarded, kv)
+		}
+	}
+	return forwarded
+}
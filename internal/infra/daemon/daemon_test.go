@@ -0,0 +1,115 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// socketPath returns a socket path short enough for the platform limit.
+func socketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "amd")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "d.sock")
+}
+
+func TestServeAndCall(t *testing.T) {
+	path := socketPath(t)
+	listener, err := Listen(path)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, listener, func(_ context.Context, req Request) Response {
+			return Response{Stdout: req.Dir + " " + req.Args[0], ThresholdExceeded: true}
+		}, 0)
+	}()
+
+	resp, err := Call(context.Background(), path, Request{Dir: "/repo", Args: []string{"scan"}})
+	require.NoError(t, err)
+	assert.Equal(t, Response{Stdout: "/repo scan", ThresholdExceeded: true}, resp)
+
+	t.Run("a second daemon cannot take the socket", func(t *testing.T) {
+		_, err := Listen(path)
+		assert.ErrorContains(t, err, "already listening")
+	})
+
+	cancel()
+	require.NoError(t, <-done)
+	_, err = Call(context.Background(), path, Request{})
+	assert.ErrorIs(t, err, ErrNotRunning)
+}
+
+func TestServe_IdleTimeout(t *testing.T) {
+	listener, err := Listen(socketPath(t))
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(context.Background(), listener, func(context.Context, Request) Response { return Response{} }, 50*time.Millisecond)
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon did not stop when idle")
+	}
+}
+
+func TestListen_ReplacesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+
+	listener, err := Listen(path)
+	require.NoError(t, err)
+	assert.NoError(t, listener.Close())
+}
+
+func TestDefaultSocketPath(t *testing.T) {
+	t.Setenv(EnvSocket, "")
+	assert.Equal(t, DefaultSocketPath("/repo"), DefaultSocketPath("/repo/"))
+	assert.NotEqual(t, DefaultSocketPath("/repo"), DefaultSocketPath("/other"))
+
+	t.Setenv(envRuntimeDir, "/run/user/1000")
+	assert.Equal(t, "/run/user/1000/antimoji", filepath.Dir(DefaultSocketPath("/repo")))
+
+	t.Setenv(EnvSocket, "/run/antimoji.sock")
+	assert.Equal(t, "/run/antimoji.sock", DefaultSocketPath("/repo"))
+}
+
+func TestListen_SocketDirectory(t *testing.T) {
+	runtimeDir := filepath.Dir(socketPath(t))
+	t.Setenv(EnvSocket, "")
+	t.Setenv(envRuntimeDir, runtimeDir)
+	path := DefaultSocketPath("/repo")
+
+	listener, err := Listen(path)
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+	info, err := os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	t.Run("a directory others can enter is refused", func(t *testing.T) {
+		require.NoError(t, os.Chmod(filepath.Dir(path), 0755))
+		_, err := Listen(path)
+		assert.ErrorContains(t, err, "accessible to other users")
+	})
+}
+
+func TestEnviron(t *testing.T) {
+	assert.Equal(t, []string{"ANTIMOJI_PROFILE=ci"}, Environ([]string{"HOME=/root", "ANTIMOJI_PROFILE=ci", "XANTIMOJI_A=1"}))
+}
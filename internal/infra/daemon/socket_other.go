@@ -0,0 +1,26 @@
+//go:build !linux && !darwin
+
+package daemon
+
+import (
+	"net"
+	"os"
+)
+
+// listenPrivate listens on path and restricts the socket to the current user.
+func listenPrivate(path string) (net.Listener, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// fileOwner reports no owner: file ownership is not checked on this platform.
+func fileOwner(os.FileInfo) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin
+
+package daemon
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenPrivate listens on path with a umask that creates the socket readable and
+// writable only by the current user, leaving no window in which others can connect.
+func listenPrivate(path string) (net.Listener, error) {
+	mask := syscall.Umask(0177)
+	defer syscall.Umask(mask)
+	return net.Listen("unix", path)
+}
+
+// fileOwner returns the uid owning the file described by info.
+func fileOwner(info os.FileInfo) (int, bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(stat.Uid), true
+	}
+	return 0, false
+}
@@ -0,0 +1,133 @@
+package emojidata
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+//go:generate go run ./gen -input https://unicode.org/Public/emoji/latest/emoji-test.txt -output dataset.yaml
+
+// EmbeddedSource is the Source of the dataset compiled into the binary.
+const EmbeddedSource = "embedded"
+
+//go:embed dataset.yaml
+var embeddedDataset []byte
+
+// embedded parses the compiled-in dataset once.
+var embedded = sync.OnceValue(func() DataFile {
+	data := Parse(embeddedDataset).Unwrap()
+	data.Source = EmbeddedSource
+	return data
+})
+
+// Embedded returns the emoji dataset compiled into the binary, generated from the
+// emoji-test.txt file of a Unicode emoji release.
+func Embedded() DataFile {
+	return embedded()
+}
+
+// Dataset returns the emoji dataset of profile: the emoji_dataset file when one is
+// configured, verified like the emoji data sources, or else the embedded dataset.
+func Dataset(ctx context.Context, profile config.Profile) types.Result[DataFile] {
+	if profile.EmojiDataset == "" {
+		return types.Ok(Embedded())
+	}
+	result := Load(ctx, profile.EmojiDataset, LoadOptions{
+		PublicKey: profile.EmojiDataPublicKey,
+		Timeout:   30 * time.Second,
+	})
+	if result.IsOk() && result.Unwrap().Version == "" {
+		return types.Err[DataFile](fmt.Errorf("emoji dataset %s has no version", profile.EmojiDataset))
+	}
+	return result
+}
+
+// ParseEmojiTest builds a dataset from the emoji-test.txt file of a Unicode emoji
+// release. The dataset covers the emojis written as a single code point, which are
+// shown as emojis without a variation selector; sequences such as keycaps and flags
+// are made of code points covered elsewhere or that are not emojis on their own.
+func ParseEmojiTest(r io.Reader) (DataFile, error) {
+	data := DataFile{
+		Description: "Emojis with emoji presentation from Unicode emoji-test.txt",
+	}
+	groups := make(map[rune]string)
+	group := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			comment = strings.TrimSpace(comment)
+			switch {
+			case strings.HasPrefix(comment, "Version:"):
+				data.Version = strings.TrimSpace(strings.TrimPrefix(comment, "Version:"))
+			case strings.HasPrefix(comment, "Date:"):
+				date, _, _ := strings.Cut(strings.TrimPrefix(comment, "Date:"), ",")
+				data.Date = strings.TrimSpace(date)
+			case strings.HasPrefix(comment, "group:"):
+				group = strings.TrimSpace(strings.TrimPrefix(comment, "group:"))
+			}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		points, rest, ok := strings.Cut(line, ";")
+		if !ok {
+			return DataFile{}, fmt.Errorf("invalid emoji-test.txt line %q", line)
+		}
+		status, _, _ := strings.Cut(rest, "#")
+		status = strings.TrimSpace(status)
+		if status == "fully-qualified" {
+			data.Emojis++
+		}
+		fields := strings.Fields(points)
+		if len(fields) != 1 || (status != "fully-qualified" && status != "component") {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[0], 16, 32)
+		if err != nil {
+			return DataFile{}, fmt.Errorf("invalid code point %q in emoji-test.txt", fields[0])
+		}
+		groups[rune(value)] = group
+	}
+	if err := scanner.Err(); err != nil {
+		return DataFile{}, err
+	}
+	if data.Version == "" || len(groups) == 0 {
+		return DataFile{}, fmt.Errorf("not an emoji-test.txt file: no version or emojis")
+	}
+
+	points := make([]rune, 0, len(groups))
+	for point := range groups {
+		points = append(points, point)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i] < points[j] })
+
+	// Consecutive code points of the same group form one range
+	for i := 0; i < len(points); {
+		end := i
+		for end+1 < len(points) && points[end+1] == points[end]+1 && groups[points[end+1]] == groups[points[i]] {
+			end++
+		}
+		entry := RangeEntry{Start: fmt.Sprintf("U+%04X", points[i]), Name: groups[points[i]]}
+		if end > i {
+			entry.End = fmt.Sprintf("U+%04X", points[end])
+		}
+		data.Ranges = append(data.Ranges, entry)
+		i = end + 1
+	}
+	return data, nil
+}
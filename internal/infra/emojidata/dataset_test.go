@@ -0,0 +1,97 @@
+package emojidata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleEmojiTest = `# emoji-test.txt
+# Date: 2024-08-14, 23:51:54 GMT
+# Version: 16.0
+
+# group: Smileys & Emotion
+
+# subgroup: face-smiling
+1F600                                                  ; fully-qualified     # 😀 E1.0 grinning face
+1F601                                                  ; fully-qualified     # 😁 E0.6 beaming face with smiling eyes
+263A FE0F                                              ; fully-qualified     # ☺️ E0.6 smiling face
+263A                                                   ; unqualified         # ☺ E0.6 smiling face
+
+# group: People & Body
+1F602                                                  ; fully-qualified     # 😂 E0.6 face with tears of joy
+1F3FB                                                  ; component           # 🏻 E1.0 light skin tone
+
+# group: Symbols
+0023 FE0F 20E3                                         ; fully-qualified     # #️⃣ E0.6 keycap: #
+1FAE9                                                  ; fully-qualified     # 🫩 E16.0 face with bags under eyes
+
+#EOF
+`
+
+func TestParseEmojiTest(t *testing.T) {
+	data, err := ParseEmojiTest(strings.NewReader(sampleEmojiTest))
+	require.NoError(t, err)
+
+	assert.Equal(t, "16.0", data.Version)
+	assert.Equal(t, "2024-08-14", data.Date)
+	assert.Equal(t, 6, data.Emojis)
+	// Consecutive code points only share a range within a group, and sequences and
+	// emojis that need a variation selector are left out
+	assert.Equal(t, []RangeEntry{
+		{Start: "U+1F3FB", Name: "People & Body"},
+		{Start: "U+1F600", End: "U+1F601", Name: "Smileys & Emotion"},
+		{Start: "U+1F602", Name: "People & Body"},
+		{Start: "U+1FAE9", Name: "Symbols"},
+	}, data.Ranges)
+
+	_, err = ParseEmojiTest(strings.NewReader("hello\n"))
+	assert.Error(t, err)
+	_, err = ParseEmojiTest(strings.NewReader("# Version: 16.0\n"))
+	assert.Error(t, err)
+}
+
+func TestEmbedded(t *testing.T) {
+	data := Embedded()
+	assert.Equal(t, EmbeddedSource, data.Source)
+	assert.NotEmpty(t, data.Version)
+	assert.NotEmpty(t, data.Date)
+	assert.Positive(t, data.Emojis)
+
+	ranges, err := data.UnicodeRanges()
+	require.NoError(t, err)
+	assert.NotEmpty(t, ranges)
+}
+
+func TestDataset(t *testing.T) {
+	dir := t.TempDir()
+
+	result := Dataset(context.Background(), config.Profile{})
+	require.True(t, result.IsOk())
+	assert.Equal(t, Embedded(), result.Unwrap())
+
+	data, err := ParseEmojiTest(strings.NewReader(sampleEmojiTest))
+	require.NoError(t, err)
+	content := "version: \"16.0\"\nranges:\n  - start: U+1FAE9\n"
+	path := filepath.Join(dir, "dataset.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	result = Dataset(context.Background(), config.Profile{EmojiDataset: path})
+	require.True(t, result.IsOk(), "%v", result.Error())
+	assert.Equal(t, data.Version, result.Unwrap().Version)
+	assert.Equal(t, path, result.Unwrap().Source)
+
+	unversioned := filepath.Join(dir, "unversioned.yaml")
+	require.NoError(t, os.WriteFile(unversioned, []byte("ranges:\n  - start: U+1FAE9\n"), 0644))
+	result = Dataset(context.Background(), config.Profile{EmojiDataset: unversioned})
+	assert.ErrorContains(t, result.Error(), "has no version")
+
+	result = Dataset(context.Background(), config.Profile{EmojiDataset: "https://example.com/dataset.yaml"})
+	assert.ErrorContains(t, result.Error(), "requires a public key")
+}
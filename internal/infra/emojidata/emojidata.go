@@ -0,0 +1,303 @@
+// Package emojidata provides the embedded emoji dataset and loading of emoji data
+// files with signature verification.
+package emojidata
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/httpclient"
+	"github.com/antimoji/antimoji/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// SignatureSuffix is appended to a data source location to find its detached signature.
+const SignatureSuffix = ".sig"
+
+// maxDataFileSize bounds how much data is read from a single source.
+const maxDataFileSize = 4 * 1024 * 1024 // 4MB
+
+// DataFile is the on-disk format of a supplemental emoji data file.
+type DataFile struct {
+	// Version identifies the data release (e.g., a Unicode version or an internal revision)
+	Version string `yaml:"version" json:"version"`
+
+	// Description is a free-form description of the data file
+	Description string `yaml:"description" json:"description"`
+
+	// Date is the release date of the data, for datasets generated from Unicode files
+	Date string `yaml:"date,omitempty" json:"date,omitempty"`
+
+	// Emojis is the number of emojis the data describes, for datasets
+	Emojis int `yaml:"emojis,omitempty" json:"emojis,omitempty"`
+
+	// Ranges lists additional code point ranges to treat as emojis
+	Ranges []RangeEntry `yaml:"ranges" json:"ranges"`
+
+	// Source records where the data file was loaded from
+	Source string `yaml:"-" json:"-"`
+}
+
+// RangeEntry describes a code point range in a data file.
+// Code points may be written as "U+1FAE0", "0x1FAE0" or plain hexadecimal.
+type RangeEntry struct {
+	Start string `yaml:"start" json:"start"`
+	End   string `yaml:"end,omitempty" json:"end,omitempty"`
+	Name  string `yaml:"name" json:"name"`
+}
+
+// LoadOptions controls how data files are fetched and verified.
+type LoadOptions struct {
+	// PublicKey is the base64-encoded ed25519 key used to verify signatures.
+	// When set, every source must carry a valid detached signature.
+	PublicKey string
+
+	// HTTPClient is used for http(s) sources; when nil, a client with Timeout that
+	// honors the proxy environment and $ANTIMOJI_CA_FILE is used
+	HTTPClient *http.Client
+
+	// Timeout bounds remote fetches when HTTPClient is nil
+	Timeout time.Duration
+}
+
+// Load reads, verifies and parses a single data file from a local path or http(s) URL.
+func Load(ctx context.Context, source string, opts LoadOptions) types.Result[DataFile] {
+	if source == "" {
+		return types.Err[DataFile](fmt.Errorf("emoji data source cannot be empty"))
+	}
+
+	if isRemote(source) && opts.PublicKey == "" {
+		return types.Err[DataFile](fmt.Errorf("remote emoji data %s requires a public key for signature verification", source))
+	}
+
+	content, err := fetch(ctx, source, opts)
+	if err != nil {
+		return types.Err[DataFile](fmt.Errorf("failed to read emoji data %s: %w", source, err))
+	}
+
+	if opts.PublicKey != "" {
+		signature, err := fetch(ctx, source+SignatureSuffix, opts)
+		if err != nil {
+			return types.Err[DataFile](fmt.Errorf("failed to read signature for emoji data %s: %w", source, err))
+		}
+		if err := Verify(content, signature, opts.PublicKey); err != nil {
+			return types.Err[DataFile](fmt.Errorf("emoji data %s: %w", source, err))
+		}
+	}
+
+	return types.Map(Parse(content), func(data DataFile) DataFile {
+		data.Source = source
+		return data
+	})
+}
+
+// Parse parses the YAML (or JSON) content of a data file and validates its ranges.
+func Parse(content []byte) types.Result[DataFile] {
+	var data DataFile
+	if err := yaml.Unmarshal(content, &data); err != nil {
+		return types.Err[DataFile](fmt.Errorf("invalid emoji data file: %w", err))
+	}
+
+	if _, err := data.UnicodeRanges(); err != nil {
+		return types.Err[DataFile](err)
+	}
+
+	return types.Ok(data)
+}
+
+// Verify checks a base64-encoded detached ed25519 signature over content.
+func Verify(content, signature []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil {
+		return fmt.Errorf("invalid public key encoding: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key size: got %d bytes, want %d", len(key), ed25519.PublicKeySize)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), content, sig) {
+		return fmt.Errorf("signature verification failed")
+	}
+
+	return nil
+}
+
+// UnicodeRanges converts the data file ranges into detector ranges.
+func (d DataFile) UnicodeRanges() ([]types.UnicodeRange, error) {
+	ranges := make([]types.UnicodeRange, 0, len(d.Ranges))
+
+	for i, entry := range d.Ranges {
+		start, err := parseCodePoint(entry.Start)
+		if err != nil {
+			return nil, fmt.Errorf("ranges[%d].start: %w", i, err)
+		}
+
+		end := start
+		if entry.End != "" {
+			end, err = parseCodePoint(entry.End)
+			if err != nil {
+				return nil, fmt.Errorf("ranges[%d].end: %w", i, err)
+			}
+		}
+
+		if end < start {
+			return nil, fmt.Errorf("ranges[%d]: end U+%04X is before start U+%04X", i, end, start)
+		}
+
+		name := entry.Name
+		if name == "" {
+			name = "Supplemental"
+		}
+
+		ranges = append(ranges, types.UnicodeRange{Start: start, End: end, Name: name})
+	}
+
+	return ranges, nil
+}
+
+// Apply returns a copy of patterns extended with the ranges of the given data files.
+// Ranges already covered by an existing range are skipped.
+func Apply(patterns types.EmojiPatterns, files ...DataFile) types.EmojiPatterns {
+	extended := patterns
+	extended.UnicodeRanges = append([]types.UnicodeRange(nil), patterns.UnicodeRanges...)
+
+	for _, file := range files {
+		ranges, err := file.UnicodeRanges()
+		if err != nil {
+			continue // Parse already rejected invalid files
+		}
+		for _, r := range ranges {
+			if !isCovered(extended.UnicodeRanges, r) {
+				extended.UnicodeRanges = append(extended.UnicodeRanges, r)
+			}
+		}
+	}
+
+	return extended
+}
+
+// PatternsForProfile applies the emoji dataset of the profile and all data sources it
+// configures to patterns.
+func PatternsForProfile(ctx context.Context, patterns types.EmojiPatterns, profile config.Profile) (types.EmojiPatterns, error) {
+	dataset := Dataset(ctx, profile)
+	if dataset.IsErr() {
+		return patterns, dataset.Error()
+	}
+
+	opts := LoadOptions{
+		PublicKey: profile.EmojiDataPublicKey,
+		Timeout:   30 * time.Second,
+	}
+
+	files := make([]DataFile, 0, 1+len(profile.EmojiDataSources))
+	files = append(files, dataset.Unwrap())
+	for _, source := range profile.EmojiDataSources {
+		result := Load(ctx, source, opts)
+		if result.IsErr() {
+			return patterns, result.Error()
+		}
+		files = append(files, result.Unwrap())
+	}
+
+	return Apply(patterns, files...), nil
+}
+
+// fetch reads the raw bytes of a local or remote source.
+func fetch(ctx context.Context, source string, opts LoadOptions) ([]byte, error) {
+	if !isRemote(source) {
+		file, err := os.Open(source) // #nosec G304 - path comes from user configuration
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		return readLimited(file)
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		var err error
+		if client, err = httpclient.New(httpclient.CAFile(""), opts.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status: %s", resp.Status)
+	}
+
+	return readLimited(resp.Body)
+}
+
+// readLimited reads at most maxDataFileSize bytes and fails on larger inputs.
+func readLimited(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxDataFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxDataFileSize {
+		return nil, fmt.Errorf("emoji data exceeds %d bytes", maxDataFileSize)
+	}
+	return content, nil
+}
+
+// isRemote reports whether a source is an http(s) URL.
+func isRemote(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// parseCodePoint parses "U+1F600", "0x1F600" or "1F600" into a rune.
+func parseCodePoint(s string) (rune, error) {
+	trimmed := strings.TrimSpace(s)
+	upper := strings.ToUpper(trimmed)
+	upper = strings.TrimPrefix(upper, "U+")
+	upper = strings.TrimPrefix(upper, "0X")
+
+	value, err := strconv.ParseUint(upper, 16, 32)
+	if err != nil || upper == "" {
+		return 0, fmt.Errorf("invalid code point %q", s)
+	}
+	if value > 0x10FFFF {
+		return 0, fmt.Errorf("code point %q is outside the Unicode range", s)
+	}
+
+	return rune(value), nil
+}
+
+// isCovered reports whether r lies entirely inside one of ranges.
+func isCovered(ranges []types.UnicodeRange, r types.UnicodeRange) bool {
+	for _, existing := range ranges {
+		if existing.Start <= r.Start && existing.End >= r.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package emojidata
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleData = `version: "16.0"
+description: test data
+ranges:
+  - start: U+1FAE0
+    end: U+1FAE8
+    name: Faces
+  - start: 0xE000
+    name: Org Symbol
+`
+
+func newKeyPair(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(pub), priv
+}
+
+func sign(priv ed25519.PrivateKey, content []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, content)))
+}
+
+func TestParse(t *testing.T) {
+	t.Run("parses ranges", func(t *testing.T) {
+		data := Parse([]byte(sampleData)).Unwrap()
+		ranges, err := data.UnicodeRanges()
+		require.NoError(t, err)
+
+		assert.Equal(t, "16.0", data.Version)
+		assert.Equal(t, []types.UnicodeRange{
+			{Start: 0x1FAE0, End: 0x1FAE8, Name: "Faces"},
+			{Start: 0xE000, End: 0xE000, Name: "Org Symbol"},
+		}, ranges)
+	})
+
+	t.Run("rejects invalid code points", func(t *testing.T) {
+		result := Parse([]byte("ranges:\n  - start: nope\n"))
+		assert.True(t, result.IsErr())
+	})
+
+	t.Run("rejects inverted ranges", func(t *testing.T) {
+		result := Parse([]byte("ranges:\n  - start: U+2000\n    end: U+1000\n"))
+		assert.True(t, result.IsErr())
+	})
+}
+
+func TestLoad_LocalFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "emoji.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(sampleData), 0644))
+
+	t.Run("loads unsigned file without key", func(t *testing.T) {
+		result := Load(context.Background(), path, LoadOptions{})
+		require.True(t, result.IsOk(), "%v", result.Error())
+		assert.Equal(t, path, result.Unwrap().Source)
+	})
+
+	t.Run("requires signature when key configured", func(t *testing.T) {
+		key, _ := newKeyPair(t)
+		result := Load(context.Background(), path, LoadOptions{PublicKey: key})
+		assert.True(t, result.IsErr())
+	})
+
+	t.Run("accepts valid signature", func(t *testing.T) {
+		key, priv := newKeyPair(t)
+		require.NoError(t, os.WriteFile(path+SignatureSuffix, sign(priv, []byte(sampleData)), 0644))
+
+		result := Load(context.Background(), path, LoadOptions{PublicKey: key})
+		assert.True(t, result.IsOk(), "%v", result.Error())
+	})
+
+	t.Run("rejects tampered content", func(t *testing.T) {
+		key, priv := newKeyPair(t)
+		require.NoError(t, os.WriteFile(path+SignatureSuffix, sign(priv, []byte("other")), 0644))
+
+		result := Load(context.Background(), path, LoadOptions{PublicKey: key})
+		require.True(t, result.IsErr())
+		assert.Contains(t, result.Error().Error(), "signature verification failed")
+	})
+}
+
+func TestLoad_Remote(t *testing.T) {
+	key, priv := newKeyPair(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/emoji.yaml":
+			_, _ = w.Write([]byte(sampleData))
+		case "/emoji.yaml.sig":
+			_, _ = w.Write(sign(priv, []byte(sampleData)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("refuses remote source without key", func(t *testing.T) {
+		result := Load(context.Background(), server.URL+"/emoji.yaml", LoadOptions{})
+		assert.True(t, result.IsErr())
+	})
+
+	t.Run("loads signed remote source", func(t *testing.T) {
+		result := Load(context.Background(), server.URL+"/emoji.yaml", LoadOptions{PublicKey: key})
+		assert.True(t, result.IsOk(), "%v", result.Error())
+	})
+
+	t.Run("reports HTTP errors", func(t *testing.T) {
+		result := Load(context.Background(), server.URL+"/missing.yaml", LoadOptions{PublicKey: key})
+		assert.True(t, result.IsErr())
+	})
+}
+
+func TestPatternsForProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "emoji.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(sampleData), 0644))
+
+	base := detector.DefaultEmojiPatterns()
+	withDataset, err := PatternsForProfile(context.Background(), base, config.Profile{})
+	require.NoError(t, err)
+
+	t.Run("no sources applies the embedded dataset", func(t *testing.T) {
+		assert.Equal(t, Apply(base, Embedded()), withDataset)
+		assert.Greater(t, len(withDataset.UnicodeRanges), len(base.UnicodeRanges))
+
+		// U+2B50 lies outside the built-in blocks
+		assert.Zero(t, detector.DetectEmojis([]byte("⭐"), base).Unwrap().TotalCount)
+		assert.Equal(t, 1, detector.DetectEmojis([]byte("⭐"), withDataset).Unwrap().TotalCount)
+	})
+
+	t.Run("extends detection coverage", func(t *testing.T) {
+		profile := config.Profile{EmojiDataSources: []string{path}}
+		patterns, err := PatternsForProfile(context.Background(), base, profile)
+		require.NoError(t, err)
+		// U+1FAE0..U+1FAE8 is already covered by the built-in ranges
+		assert.Len(t, patterns.UnicodeRanges, len(withDataset.UnicodeRanges)+1)
+
+		detection := detector.DetectEmojis([]byte("private \uE000 use"), patterns).Unwrap()
+		assert.Equal(t, 1, detection.TotalCount)
+	})
+
+	t.Run("configured dataset replaces the embedded one", func(t *testing.T) {
+		profile := config.Profile{EmojiDataset: path}
+		patterns, err := PatternsForProfile(context.Background(), base, profile)
+		require.NoError(t, err)
+		assert.Len(t, patterns.UnicodeRanges, len(base.UnicodeRanges)+1)
+	})
+
+	t.Run("does not mutate base patterns", func(t *testing.T) {
+		before := len(base.UnicodeRanges)
+		_, err := PatternsForProfile(context.Background(), base, config.Profile{EmojiDataSources: []string{path}})
+		require.NoError(t, err)
+		assert.Len(t, base.UnicodeRanges, before)
+	})
+
+	t.Run("propagates load errors", func(t *testing.T) {
+		profile := config.Profile{EmojiDataSources: []string{filepath.Join(dir, "missing.yaml")}}
+		_, err := PatternsForProfile(context.Background(), base, profile)
+		assert.Error(t, err)
+
+		profile = config.Profile{EmojiDataset: filepath.Join(dir, "missing.yaml")}
+		_, err = PatternsForProfile(context.Background(), base, profile)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,78 @@
+// Command gen generates the embedded emoji dataset from the emoji-test.txt file of a
+// Unicode emoji release. It is run by go generate in the emojidata package:
+//
+//	go run ./gen -input https://unicode.org/Public/emoji/latest/emoji-test.txt -output dataset.yaml
+//
+// The output is also a valid emoji_dataset file for loading a newer release at runtime.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	input := flag.String("input", "", "emoji-test.txt path or http(s) URL")
+	output := flag.String("output", "dataset.yaml", "dataset file to write")
+	flag.Parse()
+
+	if err := run(*input, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, output string) error {
+	if input == "" {
+		return fmt.Errorf("-input is required")
+	}
+	source, err := open(input)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	data, err := emojidata.ParseEmojiTest(source)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "# Code generated by gen from emoji-test.txt %s; DO NOT EDIT.\n", data.Version)
+	fmt.Fprintln(&out, "# Derived from Unicode data, see https://www.unicode.org/terms_of_use.html")
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(output, out.Bytes(), 0644) // #nosec G306 - generated source file
+}
+
+// open opens a local file or downloads an http(s) URL.
+func open(input string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(input, "https://") && !strings.HasPrefix(input, "http://") {
+		return os.Open(input) // #nosec G304 - path given on the command line
+	}
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(input) // #nosec G107 - URL given on the command line
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%s: unexpected HTTP status: %s", input, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,174 @@
+package filtering
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrNoCodeOwners indicates the repository has no CODEOWNERS file.
+var ErrNoCodeOwners = errors.New("no CODEOWNERS file found")
+
+// codeOwnersLocations are where GitHub and GitLab look for the CODEOWNERS file, in
+// order, relative to the repository root.
+var codeOwnersLocations = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+	filepath.Join(".gitlab", "CODEOWNERS"),
+}
+
+// CodeOwners maps the files of a repository to their owners following its CODEOWNERS
+// file.
+type CodeOwners struct {
+	root  string
+	path  string
+	rules []codeOwnersRule
+}
+
+// codeOwnersRule is a single pattern line from a CODEOWNERS file.
+type codeOwnersRule struct {
+	pattern  *regexp.Regexp
+	basename bool
+	dirOnly  bool
+	// shallow rules such as docs/* own the files of a directory but not its
+	// subdirectories
+	shallow bool
+	owners  []string
+}
+
+// LoadCodeOwners reads the CODEOWNERS file of the git repository containing path, or of
+// path itself when it is not inside a repository. It returns ErrNoCodeOwners when there
+// is none.
+func LoadCodeOwners(path string) (*CodeOwners, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	root := findRepoRoot(abs)
+	if root == "" {
+		root = abs
+	}
+
+	for _, location := range codeOwnersLocations {
+		file := filepath.Join(root, location)
+		content, err := os.ReadFile(file) // #nosec G304 - CODEOWNERS of the scanned repository
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		return ParseCodeOwners(root, file, string(content)), nil
+	}
+	return nil, fmt.Errorf("%w in %s", ErrNoCodeOwners, root)
+}
+
+// ParseCodeOwners parses the CODEOWNERS content read from file, whose patterns are
+// relative to root. Lines that are not valid rules are skipped.
+func ParseCodeOwners(root, file, content string) *CodeOwners {
+	c := &CodeOwners{root: root, path: file}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		if rule, ok := parseCodeOwnersLine(scanner.Text()); ok {
+			c.rules = append(c.rules, rule)
+		}
+	}
+	return c
+}
+
+// parseCodeOwnersLine parses one line of a CODEOWNERS file: a gitignore-style pattern
+// followed by its owners, which may be none.
+func parseCodeOwnersLine(line string) (codeOwnersRule, bool) {
+	if i := strings.Index(line, " #"); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(line)
+	// Comments and GitLab section headers such as [Docs] carry no rule
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+		return codeOwnersRule{}, false
+	}
+
+	pattern := fields[0]
+	rule := codeOwnersRule{owners: fields[1:]}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	rule.shallow = strings.HasSuffix(pattern, "/*")
+	// A pattern without an inner slash matches a name at any depth
+	rule.basename = !strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return codeOwnersRule{}, false
+	}
+
+	compiled, err := regexp.Compile(gitignoreRegexp(pattern))
+	if err != nil {
+		return codeOwnersRule{}, false
+	}
+	rule.pattern = compiled
+	return rule, true
+}
+
+// Path returns the path of the CODEOWNERS file.
+func (c *CodeOwners) Path() string {
+	if c == nil {
+		return ""
+	}
+	return c.path
+}
+
+// Owners returns the owners of the file at path, decided by the last rule matching the
+// file or one of its directories, or nil when the file has no owner. A nil *CodeOwners
+// owns nothing.
+func (c *CodeOwners) Owners(path string) []string {
+	if c == nil {
+		return nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(c.root, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	rel = filepath.ToSlash(rel)
+
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].matches(rel) {
+			if len(c.rules[i].owners) == 0 {
+				return nil
+			}
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// matches reports whether the rule matches the file at rel or, unless it is shallow,
+// one of the directories containing it.
+func (r codeOwnersRule) matches(rel string) bool {
+	for candidate, isDir := rel, false; candidate != ""; candidate, isDir = parentDir(candidate), true {
+		if isDir && r.shallow {
+			return false
+		}
+		if r.dirOnly && !isDir {
+			continue
+		}
+		target := candidate
+		if r.basename {
+			target = target[strings.LastIndex(target, "/")+1:]
+		}
+		if r.pattern.MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package filtering
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwners_Owners(t *testing.T) {
+	root := t.TempDir()
+	owners := ParseCodeOwners(root, filepath.Join(root, "CODEOWNERS"), `# Default owners
+*                 @org/platform
+*.md              @org/docs   # prose
+/build/           @org/release
+docs/*            @alice @org/docs
+apps/**/test      @org/qa
+[Frontend]
+/web/             @org/frontend
+/web/vendor/
+`)
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"main.go", []string{"@org/platform"}},
+		{"README.md", []string{"@org/docs"}},
+		{"internal/notes.md", []string{"@org/docs"}},
+		{"build/release.sh", []string{"@org/release"}},
+		{"tools/build/gen.go", []string{"@org/platform"}},
+		{"docs/intro.md", []string{"@alice", "@org/docs"}},
+		{"docs/api/ref.go", []string{"@org/platform"}},
+		{"apps/cli/test/main_test.go", []string{"@org/qa"}},
+		{"web/app.ts", []string{"@org/frontend"}},
+		{"web/vendor/lib.js", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, owners.Owners(filepath.Join(root, filepath.FromSlash(tt.path))))
+		})
+	}
+
+	assert.Nil(t, owners.Owners(filepath.Join(filepath.Dir(root), "outside.go")))
+	assert.Nil(t, (*CodeOwners)(nil).Owners("main.go"))
+}
+
+func TestLoadCodeOwners(t *testing.T) {
+	t.Run("finds the file of the repository", func(t *testing.T) {
+		root := t.TempDir()
+		writeRepoFiles(t, root, map[string]string{
+			".git/HEAD":          "ref: refs/heads/main\n",
+			".github/CODEOWNERS": "*.go @org/go\n",
+			"CODEOWNERS":         "* @org/ignored\n",
+			"cmd/main.go":        "package main\n",
+		})
+
+		owners, err := LoadCodeOwners(filepath.Join(root, "cmd"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, ".github", "CODEOWNERS"), owners.Path())
+		assert.Equal(t, []string{"@org/go"}, owners.Owners(filepath.Join(root, "cmd", "main.go")))
+	})
+
+	t.Run("reports a missing file", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+
+		_, err := LoadCodeOwners(root)
+		assert.ErrorIs(t, err, ErrNoCodeOwners)
+	})
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/antimoji/antimoji/internal/config"
 )
@@ -17,65 +16,65 @@ type DiscoveryOptions struct {
 	ExcludePattern string // Command-line exclude override
 }
 
+// Discovery is the outcome of file discovery.
+type Discovery struct {
+	Files []string
+	// Symlinks lists the symbolic links that were found but not followed, except under
+	// the skip policy where they are ignored silently.
+	Symlinks []SkippedSymlink
+}
+
+// SkippedSymlink is a symbolic link discovery did not follow.
+type SkippedSymlink struct {
+	Path   string `json:"path"`
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
 // DiscoverFiles discovers files to process using the unified filtering engine.
 func DiscoverFiles(args []string, opts DiscoveryOptions, profile config.Profile) ([]string, error) {
+	discovery, err := Discover(args, opts, profile)
+	return discovery.Files, err
+}
+
+// Discover discovers files like DiscoverFiles and also reports the symlinks it did not
+// follow.
+func Discover(args []string, opts DiscoveryOptions, profile config.Profile) (Discovery, error) {
 	// Create filtering engine
 	engine := NewFileFilterEngine(profile).
 		WithCommandLineFilters(opts.IncludePattern, opts.ExcludePattern)
 
-	var filePaths []string
+	var discovery Discovery
 
 	for _, arg := range args {
 		stat, err := os.Stat(arg)
 		if err != nil {
 			// For non-existent files, include them so they show up as errors in results
-			filePaths = append(filePaths, arg)
+			discovery.Files = append(discovery.Files, arg)
 			continue
 		}
 
 		if stat.IsDir() {
-			if opts.Recursive {
-				err := filepath.WalkDir(arg, func(path string, d os.DirEntry, err error) error {
-					if err != nil {
-						return err
-					}
-
-					if d.IsDir() {
-						// Check if directory should be ignored using engine
-						// Test with a dummy file to check directory rules
-						decision := engine.ShouldInclude(filepath.Join(path, "dummy.go"))
-						if !decision.Include && (strings.Contains(decision.Rule, "directory") ||
-							strings.Contains(decision.Rule, "exclude") ||
-							strings.Contains(decision.Rule, "ignore")) {
-							return filepath.SkipDir
-						}
-						return nil
-					}
-
-					// Check if file should be included using engine
-					decision := engine.ShouldInclude(path)
-					if decision.Include {
-						filePaths = append(filePaths, path)
-					}
+			if !opts.Recursive {
+				return Discovery{}, fmt.Errorf("directory %s requires --recursive flag", arg)
+			}
 
-					return nil
-				})
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				return nil, fmt.Errorf("directory %s requires --recursive flag", arg)
+			w := newWalker(engine, repositoryIgnores(arg, profile), profile)
+			if err := w.walk(arg, stat); err != nil {
+				return Discovery{}, err
 			}
+			discovery.Files = append(discovery.Files, w.files...)
+			discovery.Symlinks = append(discovery.Symlinks, w.symlinks...)
 		} else {
 			// Single file - check with engine
 			decision := engine.ShouldInclude(arg)
 			if decision.Include {
-				filePaths = append(filePaths, arg)
+				discovery.Files = append(discovery.Files, arg)
 			}
 		}
 	}
 
-	return filePaths, nil
+	return discovery, nil
 }
 
 // AnalyzeDiscovery provides detailed analysis of file discovery decisions.
@@ -110,11 +109,34 @@ func AnalyzeDiscovery(args []string, opts DiscoveryOptions, profile config.Profi
 
 		if stat.IsDir() {
 			if opts.Recursive {
+				ignore := repositoryIgnores(arg, profile)
 				err := filepath.WalkDir(arg, func(path string, d os.DirEntry, err error) error {
 					if err != nil {
 						return err
 					}
 
+					if path != arg && ignore.Ignored(path, d.IsDir()) {
+						if d.IsDir() {
+							return filepath.SkipDir
+						}
+						analyses = append(analyses, FilterAnalysis{
+							FilePath: path,
+							Decision: FilterDecision{
+								Include: false,
+								Reason:  "ignored by .gitignore or .git/info/exclude",
+								Rule:    "gitignore",
+								Stage:   "discovery",
+							},
+							FileInfo: FileInfo{
+								Name:      filepath.Base(path),
+								Extension: filepath.Ext(path),
+								Directory: filepath.Dir(path),
+								FullPath:  path,
+							},
+						})
+						return nil
+					}
+
 					if !d.IsDir() {
 						analysis := analyzer.AnalyzeFile(path)
 						analyses = append(analyses, analysis)
@@ -134,3 +156,12 @@ func AnalyzeDiscovery(args []string, opts DiscoveryOptions, profile config.Profi
 
 	return analyses, nil
 }
+
+// repositoryIgnores returns the git ignore rules applying below dir, or nil when the
+// profile disables them or dir is not inside a git repository.
+func repositoryIgnores(dir string, profile config.Profile) *gitIgnore {
+	if !profile.RespectGitignore {
+		return nil
+	}
+	return newGitIgnore(dir)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
 )
 
 // FileFilterEngine provides unified file filtering with clear precedence rules.
@@ -188,7 +189,8 @@ type PatternMatcher interface {
 	MatchGlob(pattern, path string) bool
 }
 
-// GlobMatcher implements pattern matching using filepath.Match and enhanced glob support.
+// GlobMatcher implements pattern matching with pathmatch, which supports ** and treats
+// paths the same on every platform.
 type GlobMatcher struct {
 	regexCache map[string]*regexp.Regexp
 }
@@ -200,18 +202,19 @@ func NewGlobMatcher() *GlobMatcher {
 	}
 }
 
-// Match performs basic filepath.Match pattern matching.
+// Match reports whether target matches the glob pattern.
 func (gm *GlobMatcher) Match(pattern, target string) bool {
-	matched, err := filepath.Match(pattern, target)
-	return err == nil && matched
+	return pathmatch.Match(pattern, target)
 }
 
 // MatchPath performs path-aware pattern matching for directories.
 func (gm *GlobMatcher) MatchPath(pattern, path string) bool {
+	path = pathmatch.Normalize(path)
+
 	// Handle directory patterns
 	if strings.HasSuffix(pattern, "/") || !strings.Contains(pattern, ".") {
 		// Directory pattern - check if path contains this directory
-		cleanPattern := strings.TrimSuffix(pattern, "/")
+		cleanPattern := strings.TrimSuffix(pathmatch.NormalizePattern(pattern), "/")
 		return strings.Contains(path, cleanPattern) ||
 			strings.HasPrefix(path, cleanPattern+"/") ||
 			filepath.Base(path) == cleanPattern
@@ -221,9 +224,8 @@ func (gm *GlobMatcher) MatchPath(pattern, path string) bool {
 	return gm.Match(pattern, filepath.Base(path)) || gm.Match(pattern, path)
 }
 
-// MatchGlob performs advanced glob pattern matching with ** support.
+// MatchGlob performs glob pattern matching, falling back to directory matching.
 func (gm *GlobMatcher) MatchGlob(pattern, path string) bool {
-	// For now, use basic matching - can be enhanced later
 	return gm.Match(pattern, path) || gm.MatchPath(pattern, path)
 }
 
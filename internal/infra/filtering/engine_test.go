@@ -162,6 +162,9 @@ func TestGlobMatcher(t *testing.T) {
 		{"question mark no match", "test?.go", "test12.go", false},
 		{"exact match", "main.go", "main.go", true},
 		{"exact no match", "main.go", "test.go", false},
+		{"double star any depth", "vendor/**/*", "vendor/a/b/lib.go", true},
+		{"double star leading", "**/*.pb.go", "api/v1/service.pb.go", true},
+		{"double star anchored", "vendor/**/*", "src/vendor/lib.go", false},
 	}
 
 	for _, tt := range tests {
@@ -311,3 +314,27 @@ func TestFileFilterEngine_RealWorldScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestFileFilterEngine_PlatformPaths(t *testing.T) {
+	engine := NewFileFilterEngine(config.Profile{
+		ExcludePatterns:     []string{"vendor/**/*", "docs/**/*.md"},
+		DirectoryIgnoreList: []string{"third_party/generated"},
+	})
+
+	// Paths use the platform separator; patterns always use forward slashes
+	tests := []struct {
+		path          string
+		expectInclude bool
+	}{
+		{filepath.Join("vendor", "github.com", "pkg", "file.go"), false},
+		{filepath.Join("docs", "guide", "intro.md"), false},
+		{filepath.Join("docs", "guide", "diagram.svg"), true},
+		{filepath.Join("third_party", "generated", "api.go"), false},
+		{filepath.Join("cmd", "main.go"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expectInclude, engine.ShouldInclude(tt.path).Include)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package filtering
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/config"
+)
+
+// RuleCheck is one filter rule examined for a path, in the order ShouldInclude applies
+// them.
+type RuleCheck struct {
+	Rule string `json:"rule"`
+	// Pattern is the first pattern of the rule matching the path, empty when none does
+	Pattern string `json:"pattern,omitempty"`
+	Matched bool   `json:"matched"`
+}
+
+// Explanation describes every filtering decision made for a single path.
+type Explanation struct {
+	Path   string      `json:"path"`
+	Checks []RuleCheck `json:"checks"`
+	// Decision applies when the path is named on the command line
+	Decision FilterDecision `json:"decision"`
+	// Walk applies when the path is found by walking a directory, where git ignore
+	// rules and excluded parent directories also drop it
+	Walk FilterDecision `json:"walk"`
+}
+
+// Explain reports the filter rules that decide whether path is selected for the given
+// options and profile.
+func Explain(path string, opts DiscoveryOptions, profile config.Profile) Explanation {
+	engine := NewFileFilterEngine(profile).WithCommandLineFilters(opts.IncludePattern, opts.ExcludePattern)
+	ignore := repositoryIgnores(filepath.Dir(path), profile)
+
+	explanation := Explanation{
+		Path:     path,
+		Checks:   engine.checks(path),
+		Decision: engine.ShouldInclude(path),
+	}
+	if ignore != nil {
+		check := RuleCheck{Rule: "gitignore"}
+		if rule := ignore.match(path, false); rule != nil && !rule.negate {
+			check.Pattern = rule.source
+			check.Matched = true
+		}
+		explanation.Checks = append(explanation.Checks, check)
+	}
+
+	explanation.Walk = explanation.Decision
+	if explanation.Decision.Include {
+		if decision, excluded := walkExclusion(path, engine, ignore); excluded {
+			explanation.Walk = decision
+		}
+	}
+	return explanation
+}
+
+// checks lists the rule groups of ShouldInclude with the first pattern of each that
+// matches filePath. Command-line rules are listed only when set.
+func (ffe *FileFilterEngine) checks(filePath string) []RuleCheck {
+	fileName := filepath.Base(filePath)
+	dirPath := filepath.Dir(filePath)
+	matchFile := func(pattern string) bool {
+		return ffe.matcher.Match(pattern, fileName) || ffe.matcher.Match(pattern, filePath)
+	}
+	matchDir := func(pattern string) bool {
+		return ffe.matcher.MatchPath(pattern, dirPath) || ffe.matcher.MatchPath(pattern, filePath)
+	}
+	check := func(rule string, patterns []string, match func(string) bool) RuleCheck {
+		for _, pattern := range patterns {
+			if match(pattern) {
+				return RuleCheck{Rule: rule, Pattern: pattern, Matched: true}
+			}
+		}
+		return RuleCheck{Rule: rule}
+	}
+
+	var checks []RuleCheck
+	if ffe.cmdExclude != "" {
+		checks = append(checks, check("command_line.exclude", []string{ffe.cmdExclude}, matchFile))
+	}
+	if ffe.cmdInclude != "" {
+		checks = append(checks, check("command_line.include", []string{ffe.cmdInclude}, matchFile))
+	}
+	checks = append(checks,
+		check("profile.exclude_patterns", ffe.profile.ExcludePatterns, matchFile),
+		check("profile.file_ignore_list", ffe.profile.FileIgnoreList, matchFile),
+		check("profile.directory_ignore_list", ffe.profile.DirectoryIgnoreList, matchDir),
+	)
+	if len(ffe.profile.IncludePatterns) > 0 {
+		checks = append(checks, check("profile.include_patterns", ffe.profile.IncludePatterns, matchFile))
+	}
+	return checks
+}
+
+// walkExclusion reports why a directory walk would drop path although the filter
+// engine includes it: an ignored or excluded parent directory, or a git ignore rule.
+func walkExclusion(path string, engine *FileFilterEngine, ignore *gitIgnore) (FilterDecision, bool) {
+	root := ""
+	if ignore != nil {
+		root = ignore.root
+	}
+	for _, dir := range parentDirs(path, root) {
+		if rule := ignore.match(dir, true); rule != nil && !rule.negate {
+			return FilterDecision{
+				Reason: fmt.Sprintf("parent directory %s is ignored by %s", dir, rule.source),
+				Rule:   "gitignore",
+				Stage:  "discovery",
+			}, true
+		}
+		w := walker{engine: engine}
+		if w.skipDir(dir) {
+			decision := engine.ShouldInclude(filepath.Join(dir, "dummy.go"))
+			return FilterDecision{
+				Reason: fmt.Sprintf("parent directory %s is skipped: %s", dir, decision.Reason),
+				Rule:   decision.Rule,
+				Stage:  "discovery",
+			}, true
+		}
+	}
+
+	if rule := ignore.match(path, false); rule != nil && !rule.negate {
+		return FilterDecision{
+			Reason: fmt.Sprintf("ignored by %s", rule.source),
+			Rule:   "gitignore",
+			Stage:  "discovery",
+		}, true
+	}
+	return FilterDecision{}, false
+}
+
+// parentDirs returns the directories a walk passes through to reach path, outermost
+// first: those below the working directory when path is inside it, otherwise those
+// below root, the repository root, or only the file's own directory without one.
+func parentDirs(path, root string) []string {
+	stop := root
+	if cwd, err := os.Getwd(); err == nil && within(cwd, path) {
+		stop = cwd
+	}
+
+	var dirs []string
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		abs, err := filepath.Abs(dir)
+		if err != nil || abs == stop || !within(stop, abs) {
+			break
+		}
+		dirs = append([]string{dir}, dirs...)
+		if stop == "" || filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return dirs
+}
+
+// within reports whether path is dir or below it. Every path is within "".
+func within(dir, path string) bool {
+	if dir == "" {
+		return true
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(dir, abs)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
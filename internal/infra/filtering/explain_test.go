@@ -0,0 +1,84 @@
+package filtering
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	root := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{
+		".git/HEAD":         "ref: refs/heads/main\n",
+		".gitignore":        "# generated\n*.log\nout/\n",
+		"main.go":           "package main",
+		"app.log":           "log",
+		"out/gen.go":        "package out",
+		"vendor/lib/lib.go": "package lib",
+		"notes.md":          "# notes",
+	})
+
+	profile := config.Profile{
+		RespectGitignore:    true,
+		FileIgnoreList:      []string{"*.md"},
+		DirectoryIgnoreList: []string{"vendor"},
+	}
+
+	t.Run("included file", func(t *testing.T) {
+		explanation := Explain(filepath.Join(root, "main.go"), DiscoveryOptions{}, profile)
+
+		assert.True(t, explanation.Decision.Include)
+		assert.True(t, explanation.Walk.Include)
+		require.Len(t, explanation.Checks, 4)
+		for _, check := range explanation.Checks {
+			assert.False(t, check.Matched, check.Rule)
+		}
+	})
+
+	t.Run("profile rule names the pattern", func(t *testing.T) {
+		explanation := Explain(filepath.Join(root, "notes.md"), DiscoveryOptions{}, profile)
+
+		assert.False(t, explanation.Decision.Include)
+		assert.Equal(t, "profile.file_ignore_list", explanation.Decision.Rule)
+		assert.Contains(t, explanation.Checks, RuleCheck{Rule: "profile.file_ignore_list", Pattern: "*.md", Matched: true})
+		assert.Equal(t, explanation.Decision, explanation.Walk)
+	})
+
+	t.Run("gitignore only applies to directory walks", func(t *testing.T) {
+		explanation := Explain(filepath.Join(root, "app.log"), DiscoveryOptions{}, profile)
+
+		assert.True(t, explanation.Decision.Include)
+		assert.False(t, explanation.Walk.Include)
+		assert.Equal(t, "gitignore", explanation.Walk.Rule)
+		assert.Contains(t, explanation.Checks, RuleCheck{Rule: "gitignore", Pattern: ".gitignore:2: *.log", Matched: true})
+	})
+
+	t.Run("ignored parent directory", func(t *testing.T) {
+		explanation := Explain(filepath.Join(root, "out", "gen.go"), DiscoveryOptions{}, profile)
+
+		assert.True(t, explanation.Decision.Include)
+		assert.False(t, explanation.Walk.Include)
+		assert.Contains(t, explanation.Walk.Reason, ".gitignore:3: out/")
+	})
+
+	t.Run("command-line filters come first", func(t *testing.T) {
+		explanation := Explain(filepath.Join(root, "main.go"), DiscoveryOptions{ExcludePattern: "*.go"}, profile)
+
+		assert.False(t, explanation.Decision.Include)
+		assert.Equal(t, RuleCheck{Rule: "command_line.exclude", Pattern: "*.go", Matched: true}, explanation.Checks[0])
+	})
+
+	t.Run("agrees with discovery", func(t *testing.T) {
+		discovered, err := DiscoverFiles([]string{root}, DiscoveryOptions{Recursive: true}, profile)
+		require.NoError(t, err)
+
+		for _, name := range []string{"main.go", "app.log", "out/gen.go", "vendor/lib/lib.go", "notes.md"} {
+			path := filepath.Join(root, filepath.FromSlash(name))
+			assert.Equal(t, slices.Contains(discovered, path), Explain(path, DiscoveryOptions{}, profile).Walk.Include, name)
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package filtering
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// gitIgnore applies the .gitignore and .git/info/exclude rules of the repository
+// containing a discovery root. A nil *gitIgnore ignores nothing.
+type gitIgnore struct {
+	root   string
+	rules  []gitignoreRule
+	loaded map[string]bool
+}
+
+// gitignoreRule is a single pattern line from an ignore file.
+type gitignoreRule struct {
+	// base is the directory of the ignore file, relative to the repository root
+	base     string
+	pattern  *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	basename bool
+	// source locates the rule for explanations, e.g. "docs/.gitignore:3: *.log"
+	source string
+}
+
+// newGitIgnore returns the ignore rules for the git repository containing path, or nil
+// when path is not inside a repository.
+func newGitIgnore(path string) *gitIgnore {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+
+	root := findRepoRoot(abs)
+	if root == "" {
+		return nil
+	}
+
+	g := &gitIgnore{root: root, loaded: make(map[string]bool)}
+	// info/exclude has the lowest precedence, so its rules go first
+	g.load(filepath.Join(root, ".git", "info", "exclude"), "")
+	return g
+}
+
+// findRepoRoot walks up from dir to the nearest directory containing .git.
+func findRepoRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Ignored reports whether path is excluded by the repository's ignore rules. Callers
+// must not descend into ignored directories; like git, rules cannot re-include files
+// below an ignored directory.
+func (g *gitIgnore) Ignored(path string, isDir bool) bool {
+	rule := g.match(path, isDir)
+	return rule != nil && !rule.negate
+}
+
+// match returns the last rule matching path, which decides whether it is ignored, or
+// nil when no rule matches.
+func (g *gitIgnore) match(path string, isDir bool) *gitignoreRule {
+	if g == nil {
+		return nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(g.root, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	rel = filepath.ToSlash(rel)
+
+	g.enter(parentDir(rel))
+
+	var matched *gitignoreRule
+	for i, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		target := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			target = rel[len(rule.base)+1:]
+		}
+		if rule.basename {
+			target = target[strings.LastIndex(target, "/")+1:]
+		}
+		if rule.pattern.MatchString(target) {
+			matched = &g.rules[i]
+		}
+	}
+	return matched
+}
+
+// enter loads the .gitignore files of dir and of every directory between it and the
+// repository root, each only once.
+func (g *gitIgnore) enter(dir string) {
+	current := ""
+	for {
+		if !g.loaded[current] {
+			g.loaded[current] = true
+			g.load(filepath.Join(g.root, filepath.FromSlash(current), ".gitignore"), current)
+		}
+		if current == dir {
+			return
+		}
+		next, _, _ := strings.Cut(strings.TrimPrefix(dir, current+"/"), "/")
+		if current == "" {
+			current = next
+		} else {
+			current += "/" + next
+		}
+	}
+}
+
+// load appends the rules of the ignore file at path. Missing or unreadable files
+// contribute no rules.
+func (g *gitIgnore) load(path, base string) {
+	file, err := os.Open(path) // #nosec G304 - ignore files inside the scanned repository
+	if err != nil {
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	name := filepath.ToSlash(path)
+	if rel, err := filepath.Rel(g.root, path); err == nil {
+		name = filepath.ToSlash(rel)
+	}
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if rule, ok := parseGitignoreLine(scanner.Text(), base); ok {
+			rule.source = fmt.Sprintf("%s:%d: %s", name, line, strings.TrimSpace(scanner.Text()))
+			g.rules = append(g.rules, rule)
+		}
+	}
+}
+
+// parseGitignoreLine parses one line of an ignore file following gitignore(5).
+func parseGitignoreLine(line, base string) (gitignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	rule := gitignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	// A pattern without an inner slash matches a name at any depth
+	rule.basename = !strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+
+	pattern, err := regexp.Compile(gitignoreRegexp(line))
+	if err != nil {
+		return gitignoreRule{}, false
+	}
+	rule.pattern = pattern
+	return rule, true
+}
+
+// gitignoreRegexp translates a gitignore glob into an anchored regular expression.
+func gitignoreRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**") {
+				switch {
+				case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+					b.WriteString("(?:.*/)?")
+					i += 2
+				case i+2 == len(glob) && i > 0 && glob[i-1] == '/':
+					b.WriteString(".*")
+					i++
+				default:
+					b.WriteString("[^/]*")
+					i++
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			}
+		default:
+			if c >= 0x80 {
+				// Part of a multi-byte character; never special
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// parentDir returns the slash-separated parent of rel, "" for the repository root.
+func parentDir(rel string) string {
+	if i := strings.LastIndex(rel, "/"); i >= 0 {
+		return rel[:i]
+	}
+	return ""
+}
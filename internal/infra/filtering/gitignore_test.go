@@ -0,0 +1,134 @@
+package filtering
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRepoFiles(t *testing.T, root string, files map[string]string) {
+	for path, content := range files {
+		fullPath := filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0644))
+	}
+}
+
+func TestGitignoreRegexp(t *testing.T) {
+	tests := []struct {
+		glob    string
+		matches []string
+		misses  []string
+	}{
+		{"*.log", []string{"app.log", ".log"}, []string{"app.log.txt", "a/app.log"}},
+		{"build", []string{"build"}, []string{"builder"}},
+		{"doc/*.txt", []string{"doc/notes.txt"}, []string{"doc/server/arch.txt"}},
+		{"**/foo", []string{"foo", "a/b/foo"}, []string{"afoo"}},
+		{"a/**/b", []string{"a/b", "a/x/y/b"}, []string{"ab"}},
+		{"abc/**", []string{"abc/x", "abc/x/y"}, []string{"abc"}},
+		{"file[0-9].txt", []string{"file1.txt"}, []string{"filea.txt"}},
+		{"file[!0-9].txt", []string{"filea.txt"}, []string{"file1.txt"}},
+		{"🚀*", []string{"🚀.md"}, []string{"x🚀"}},
+	}
+
+	for _, tt := range tests {
+		rule, ok := parseGitignoreLine(tt.glob, "")
+		require.True(t, ok, tt.glob)
+		for _, path := range tt.matches {
+			assert.True(t, rule.pattern.MatchString(path), "%s should match %s", tt.glob, path)
+		}
+		for _, path := range tt.misses {
+			assert.False(t, rule.pattern.MatchString(path), "%s should not match %s", tt.glob, path)
+		}
+	}
+
+	for _, line := range []string{"", "   ", "# comment", "/"} {
+		_, ok := parseGitignoreLine(line, "")
+		assert.False(t, ok, "%q is not a rule", line)
+	}
+}
+
+func TestDiscoverFiles_RespectGitignore(t *testing.T) {
+	root := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{
+		".git/info/exclude":    "secret.go\n",
+		".gitignore":           "*.log\nout/\n/top.go\n!keep.log\n",
+		"main.go":              "package main",
+		"top.go":               "package main",
+		"debug.log":            "log",
+		"keep.log":             "log",
+		"secret.go":            "package main",
+		"out/gen.go":           "package out",
+		"pkg/top.go":           "package pkg",
+		"pkg/out":              "a file, not a directory",
+		"pkg/.gitignore":       "local.go\n",
+		"pkg/local.go":         "package pkg",
+		"pkg/sub/local.go":     "package sub",
+		"other/local.go":       "package other",
+		"other/nested/app.log": "log",
+	})
+
+	profile := config.DefaultConfig().Profiles["default"]
+	opts := DiscoveryOptions{Recursive: true}
+
+	t.Run("skips ignored files", func(t *testing.T) {
+		files, err := DiscoverFiles([]string{root}, opts, profile)
+		require.NoError(t, err)
+
+		var rel []string
+		for _, file := range files {
+			path, err := filepath.Rel(root, file)
+			require.NoError(t, err)
+			rel = append(rel, filepath.ToSlash(path))
+		}
+		assert.ElementsMatch(t, []string{
+			".gitignore", "main.go", "keep.log", "pkg/top.go", "pkg/out", "pkg/.gitignore", "other/local.go",
+		}, rel)
+	})
+
+	t.Run("applies root rules when scanning a subdirectory", func(t *testing.T) {
+		files, err := DiscoverFiles([]string{filepath.Join(root, "other")}, opts, profile)
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(root, "other", "local.go")}, files)
+	})
+
+	t.Run("scans explicitly named paths", func(t *testing.T) {
+		files, err := DiscoverFiles([]string{filepath.Join(root, "out"), filepath.Join(root, "debug.log")}, opts, profile)
+		require.NoError(t, err)
+		assert.Len(t, files, 2)
+	})
+
+	t.Run("disabled by profile", func(t *testing.T) {
+		disabled := profile
+		disabled.RespectGitignore = false
+		files, err := DiscoverFiles([]string{root}, opts, disabled)
+		require.NoError(t, err)
+		assert.Len(t, files, 14)
+	})
+
+	t.Run("outside a repository", func(t *testing.T) {
+		dir := t.TempDir()
+		writeRepoFiles(t, dir, map[string]string{".gitignore": "*.log\n", "app.log": "log"})
+		assert.Nil(t, newGitIgnore(dir))
+
+		files, err := DiscoverFiles([]string{dir}, opts, profile)
+		require.NoError(t, err)
+		assert.Len(t, files, 2)
+	})
+
+	t.Run("analysis reports ignored files", func(t *testing.T) {
+		analyses, err := AnalyzeDiscovery([]string{root}, opts, profile)
+		require.NoError(t, err)
+
+		rules := map[string]string{}
+		for _, analysis := range analyses {
+			rules[filepath.Base(analysis.FilePath)] = analysis.Decision.Rule
+		}
+		assert.Equal(t, "gitignore", rules["debug.log"])
+		assert.NotContains(t, rules, "gen.go")
+	})
+}
@@ -0,0 +1,172 @@
+package filtering
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/config"
+)
+
+// walker traverses a directory tree applying the filter engine, git ignore rules and
+// the profile's symlink policy.
+type walker struct {
+	engine *FileFilterEngine
+	ignore *gitIgnore
+	// policy is a config symlink policy, or empty when the profile sets neither
+	// symlink_policy nor follow_symlinks: symlinked files are then read but symlinked
+	// directories are not entered, as before symlink policies existed.
+	policy   string
+	maxDepth int
+
+	// visited holds every directory entered, to detect symlink cycles
+	visited  []os.FileInfo
+	files    []string
+	symlinks []SkippedSymlink
+}
+
+func newWalker(engine *FileFilterEngine, ignore *gitIgnore, profile config.Profile) *walker {
+	maxDepth := profile.MaxSymlinkDepth
+	if maxDepth <= 0 {
+		maxDepth = config.DefaultMaxSymlinkDepth
+	}
+	policy := profile.SymlinkPolicy
+	if policy == "" && profile.FollowSymlinks {
+		policy = config.SymlinkFollow
+	}
+	return &walker{
+		engine:   engine,
+		ignore:   ignore,
+		policy:   policy,
+		maxDepth: maxDepth,
+	}
+}
+
+// walk collects the files below root, which must be a directory.
+// Like paths named on the command line, root is scanned even when git ignores it.
+func (w *walker) walk(root string, info os.FileInfo) error {
+	if w.skipDir(root) {
+		return nil
+	}
+	w.visited = append(w.visited, info)
+	return w.walkDir(root, 0)
+}
+
+// walkDir visits the entries of dir in lexical order. depth counts the symlinked
+// directories followed to reach dir.
+func (w *walker) walkDir(dir string, depth int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if w.ignore.Ignored(path, entry.IsDir()) {
+			continue
+		}
+
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			if err := w.symlink(path, depth); err != nil {
+				return err
+			}
+		case entry.IsDir():
+			if w.skipDir(path) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			w.visited = append(w.visited, info)
+			if err := w.walkDir(path, depth); err != nil {
+				return err
+			}
+		default:
+			w.addFile(path)
+		}
+	}
+	return nil
+}
+
+// symlink applies the symlink policy to the link at path.
+func (w *walker) symlink(path string, depth int) error {
+	if w.policy == config.SymlinkSkip {
+		return nil
+	}
+
+	target, err := os.Readlink(path)
+	if err != nil {
+		return err
+	}
+	if w.policy == config.SymlinkReport {
+		w.skipSymlink(path, target, "symlink_policy is report")
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if w.policy == config.SymlinkFollow {
+			w.skipSymlink(path, target, "broken link")
+		} else {
+			// Reported as a read error, as before symlink policies existed
+			w.addFile(path)
+		}
+		return nil
+	}
+	if !info.IsDir() {
+		w.addFile(path)
+		return nil
+	}
+	if w.policy != config.SymlinkFollow {
+		return nil
+	}
+
+	if depth >= w.maxDepth {
+		w.skipSymlink(path, target, fmt.Sprintf("exceeds max_symlink_depth of %d", w.maxDepth))
+		return nil
+	}
+	if w.seen(info) {
+		w.skipSymlink(path, target, "directory already visited (symlink cycle)")
+		return nil
+	}
+	if w.skipDir(path) {
+		return nil
+	}
+
+	w.visited = append(w.visited, info)
+	return w.walkDir(path, depth+1)
+}
+
+// seen reports whether the directory described by info was already entered.
+func (w *walker) seen(info os.FileInfo) bool {
+	for _, visited := range w.visited {
+		if os.SameFile(visited, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipDir reports whether the filter engine excludes the directory at path.
+func (w *walker) skipDir(path string) bool {
+	// Test with a dummy file to check directory rules
+	decision := w.engine.ShouldInclude(filepath.Join(path, "dummy.go"))
+	return !decision.Include && (strings.Contains(decision.Rule, "directory") ||
+		strings.Contains(decision.Rule, "exclude") ||
+		strings.Contains(decision.Rule, "ignore"))
+}
+
+// addFile collects path when the filter engine includes it.
+func (w *walker) addFile(path string) {
+	if decision := w.engine.ShouldInclude(path); decision.Include {
+		w.files = append(w.files, path)
+	}
+}
+
+func (w *walker) skipSymlink(path, target, reason string) {
+	w.symlinks = append(w.symlinks, SkippedSymlink{Path: path, Target: target, Reason: reason})
+}
@@ -0,0 +1,111 @@
+package filtering
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSymlinkTree creates a tree with a followable directory link, a file link, a
+// broken link and two loops.
+func setupSymlinkTree(t *testing.T) string {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{"src/main.go": "package main"})
+	writeRepoFiles(t, outside, map[string]string{"lib/lib.go": "package lib"})
+
+	links := map[string]string{
+		"src/lib":      filepath.Join(outside, "lib"),
+		"src/main2.go": "main.go",
+		"src/broken":   "missing.go",
+		"src/loop":     "..",
+		"src/self":     "self",
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skip("Symlinks not supported on this system")
+		}
+	}
+	return root
+}
+
+func discoverRelative(t *testing.T, root string, profile config.Profile) ([]string, map[string]string) {
+	discovery, err := Discover([]string{root}, DiscoveryOptions{Recursive: true}, profile)
+	require.NoError(t, err)
+
+	var files []string
+	for _, file := range discovery.Files {
+		rel, err := filepath.Rel(root, file)
+		require.NoError(t, err)
+		files = append(files, filepath.ToSlash(rel))
+	}
+	skipped := map[string]string{}
+	for _, link := range discovery.Symlinks {
+		rel, err := filepath.Rel(root, link.Path)
+		require.NoError(t, err)
+		skipped[filepath.ToSlash(rel)] = link.Reason
+	}
+	return files, skipped
+}
+
+func TestDiscover_SymlinkPolicies(t *testing.T) {
+	root := setupSymlinkTree(t)
+
+	t.Run("follow detects cycles and broken links", func(t *testing.T) {
+		files, skipped := discoverRelative(t, root, config.Profile{SymlinkPolicy: config.SymlinkFollow})
+		assert.ElementsMatch(t, []string{"src/main.go", "src/main2.go", "src/lib/lib.go"}, files)
+		assert.Equal(t, "broken link", skipped["src/broken"])
+		assert.Contains(t, skipped["src/loop"], "cycle")
+		assert.Contains(t, skipped, "src/self")
+	})
+
+	t.Run("follow_symlinks selects follow", func(t *testing.T) {
+		files, _ := discoverRelative(t, root, config.Profile{FollowSymlinks: true})
+		assert.Contains(t, files, "src/lib/lib.go")
+	})
+
+	t.Run("skip ignores every link", func(t *testing.T) {
+		files, skipped := discoverRelative(t, root, config.Profile{SymlinkPolicy: config.SymlinkSkip, FollowSymlinks: true})
+		assert.Equal(t, []string{"src/main.go"}, files)
+		assert.Empty(t, skipped)
+	})
+
+	t.Run("report lists every link", func(t *testing.T) {
+		files, skipped := discoverRelative(t, root, config.Profile{SymlinkPolicy: config.SymlinkReport})
+		assert.Equal(t, []string{"src/main.go"}, files)
+		assert.Len(t, skipped, 5)
+	})
+
+	t.Run("unset reads file links only", func(t *testing.T) {
+		files, skipped := discoverRelative(t, root, config.Profile{})
+		assert.ElementsMatch(t, []string{"src/broken", "src/main.go", "src/main2.go", "src/self"}, files)
+		assert.Empty(t, skipped)
+	})
+}
+
+func TestDiscover_MaxSymlinkDepth(t *testing.T) {
+	root := t.TempDir()
+	chain := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{"a.go": "package a"})
+	writeRepoFiles(t, chain, map[string]string{"one/one.go": "package one", "two/two.go": "package two"})
+
+	for link, target := range map[string]string{
+		filepath.Join(root, "one"):         filepath.Join(chain, "one"),
+		filepath.Join(chain, "one", "two"): filepath.Join(chain, "two"),
+	} {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skip("Symlinks not supported on this system")
+		}
+	}
+
+	files, skipped := discoverRelative(t, root, config.Profile{SymlinkPolicy: config.SymlinkFollow, MaxSymlinkDepth: 1})
+	assert.ElementsMatch(t, []string{"a.go", "one/one.go"}, files)
+	assert.Equal(t, "exceeds max_symlink_depth of 1", skipped["one/two"])
+
+	files, _ = discoverRelative(t, root, config.Profile{SymlinkPolicy: config.SymlinkFollow})
+	assert.Contains(t, files, "one/two/two.go", "unset depth uses the default")
+}
@@ -0,0 +1,310 @@
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Encoding is a character encoding antimoji can read.
+type Encoding string
+
+// Supported encodings.
+const (
+	EncodingUTF8    Encoding = "utf-8"
+	EncodingUTF16LE Encoding = "utf-16le"
+	EncodingUTF16BE Encoding = "utf-16be"
+	EncodingLatin1  Encoding = "iso-8859-1"
+	// EncodingBinary marks content that is not text
+	EncodingBinary Encoding = "binary"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// ErrBinaryContent is returned when decoding content that is not text.
+var ErrBinaryContent = errors.New("content is not text")
+
+// ErrUnencodable is returned when text contains a character its encoding cannot hold.
+var ErrUnencodable = errors.New("cannot be encoded")
+
+// DetectEncoding detects the encoding of data, which is usually the start of a file,
+// using the default sniffing thresholds.
+func DetectEncoding(data []byte) Encoding {
+	encoding, _ := Sniff(data, types.DefaultSniffConfig())
+	return encoding
+}
+
+// Sniff detects the encoding of data, or returns EncodingBinary and the reason data is
+// not text. A byte order mark decides; without one, UTF-16 is recognised by its zero
+// bytes, then UTF-8 is preferred and Latin-1 accepted when the bytes look like text.
+func Sniff(data []byte, config types.SniffConfig) (Encoding, string) {
+	config = config.WithDefaults()
+
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return EncodingUTF8, ""
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return EncodingUTF16LE, ""
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return EncodingUTF16BE, ""
+	}
+
+	if encoding, ok := detectUTF16(data, config); ok {
+		return encoding, ""
+	}
+
+	reason := utf8BinaryReason(trimPartialRune(data), config)
+	if reason == "" {
+		return EncodingUTF8, ""
+	}
+	if reason == "invalid_utf8" && isLatin1Text(data, config) {
+		return EncodingLatin1, ""
+	}
+	return EncodingBinary, reason
+}
+
+// DecodedContent is file content converted to UTF-8 for detection.
+type DecodedContent struct {
+	// Text is the content as UTF-8, without its byte order mark
+	Text     []byte
+	Encoding Encoding
+
+	bom []byte
+	// stripBOM has Encode leave out the byte order mark
+	stripBOM bool
+	// offsets maps each byte of Text, and its end, to the offset in the original
+	// content; nil when Text is the original content
+	offsets []int
+}
+
+// Decode detects the encoding of data from its first bytes, like SniffFile with the
+// same config, and converts it to UTF-8. UTF-8 content is returned unchanged, but for
+// its byte order mark, so offsets in Text count characters of the text only.
+func Decode(data []byte, config types.SniffConfig) (DecodedContent, error) {
+	config = config.WithDefaults()
+	sample := data
+	if len(sample) > config.SampleSize {
+		sample = sample[:config.SampleSize]
+	}
+
+	encoding, _ := Sniff(sample, config)
+	switch encoding {
+	case EncodingUTF8:
+		if bytes.HasPrefix(data, bomUTF8) {
+			return DecodedContent{Text: data[len(bomUTF8):], Encoding: encoding, bom: bomUTF8}, nil
+		}
+		return DecodedContent{Text: data, Encoding: encoding}, nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		return decodeUTF16(data, encoding), nil
+	case EncodingLatin1:
+		return decodeLatin1(data), nil
+	default:
+		return DecodedContent{}, ErrBinaryContent
+	}
+}
+
+// OriginalOffset maps a byte offset in Text to the byte offset of the same character
+// in the original content.
+func (d DecodedContent) OriginalOffset(offset int) int {
+	if d.offsets == nil {
+		return offset + len(d.bom)
+	}
+	if offset < 0 {
+		return 0
+	}
+	if offset >= len(d.offsets) {
+		return d.offsets[len(d.offsets)-1]
+	}
+	return d.offsets[offset]
+}
+
+// HasBOM reports whether the content starts with a byte order mark.
+func (d DecodedContent) HasBOM() bool {
+	return len(d.bom) > 0
+}
+
+// StripBOM returns the content with Encode leaving out its UTF-8 byte order mark.
+// UTF-16 content keeps its mark, which tells its byte order.
+func (d DecodedContent) StripBOM() DecodedContent {
+	if d.Encoding == EncodingUTF8 {
+		d.stripBOM = true
+	}
+	return d
+}
+
+// BOMStripped reports whether Encode leaves out the byte order mark of the content.
+func (d DecodedContent) BOMStripped() bool {
+	return d.stripBOM && d.HasBOM()
+}
+
+// Encode converts UTF-8 text back to the original encoding, restoring any byte order
+// mark unless stripped. It fails when text contains characters the encoding cannot
+// represent.
+func (d DecodedContent) Encode(text []byte) ([]byte, error) {
+	bom := d.bom
+	if d.stripBOM {
+		bom = nil
+	}
+
+	switch d.Encoding {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		units := utf16.Encode([]rune(string(text)))
+		out := make([]byte, 0, len(bom)+2*len(units))
+		out = append(out, bom...)
+		for _, unit := range units {
+			if d.Encoding == EncodingUTF16LE {
+				out = append(out, byte(unit), byte(unit>>8))
+			} else {
+				out = append(out, byte(unit>>8), byte(unit))
+			}
+		}
+		return out, nil
+	case EncodingLatin1:
+		out := make([]byte, 0, len(text))
+		for _, r := range string(text) {
+			if r > 0xFF {
+				return nil, fmt.Errorf("character %q %w in %s", r, ErrUnencodable, d.Encoding)
+			}
+			out = append(out, byte(r))
+		}
+		return out, nil
+	default:
+		if len(bom) == 0 {
+			return text, nil
+		}
+		return append(append(make([]byte, 0, len(bom)+len(text)), bom...), text...), nil
+	}
+}
+
+// decodeUTF16 converts UTF-16 data to UTF-8. Unpaired surrogates become U+FFFD.
+func decodeUTF16(data []byte, encoding Encoding) DecodedContent {
+	decoded := DecodedContent{Encoding: encoding}
+	start := 0
+	if bytes.HasPrefix(data, bomUTF16LE) || bytes.HasPrefix(data, bomUTF16BE) {
+		decoded.bom = data[:2]
+		start = 2
+	}
+
+	unit := func(i int) uint16 {
+		if encoding == EncodingUTF16LE {
+			return uint16(data[i]) | uint16(data[i+1])<<8
+		}
+		return uint16(data[i])<<8 | uint16(data[i+1])
+	}
+
+	text := make([]byte, 0, len(data))
+	offsets := make([]int, 0, len(data)+1)
+	for i := start; i+1 < len(data); {
+		r, size := rune(unit(i)), 2
+		if utf16.IsSurrogate(r) {
+			r = utf8.RuneError
+			if i+3 < len(data) {
+				if pair := utf16.DecodeRune(rune(unit(i)), rune(unit(i+2))); pair != utf8.RuneError {
+					r, size = pair, 4
+				}
+			}
+		}
+		text, offsets = appendRune(text, offsets, r, i)
+		i += size
+	}
+
+	decoded.Text = text
+	decoded.offsets = append(offsets, len(data))
+	return decoded
+}
+
+// decodeLatin1 converts ISO-8859-1 data to UTF-8.
+func decodeLatin1(data []byte) DecodedContent {
+	text := make([]byte, 0, len(data))
+	offsets := make([]int, 0, len(data)+1)
+	for i, b := range data {
+		text, offsets = appendRune(text, offsets, rune(b), i)
+	}
+	return DecodedContent{Text: text, Encoding: EncodingLatin1, offsets: append(offsets, len(data))}
+}
+
+// appendRune appends r to text and records origin as the original offset of each of
+// its bytes.
+func appendRune(text []byte, offsets []int, r rune, origin int) ([]byte, []int) {
+	before := len(text)
+	text = utf8.AppendRune(text, r)
+	for range text[before:] {
+		offsets = append(offsets, origin)
+	}
+	return text, offsets
+}
+
+// detectUTF16 recognises UTF-16 without a byte order mark by the zero high bytes of
+// ASCII characters, which make up most source text.
+func detectUTF16(data []byte, config types.SniffConfig) (Encoding, bool) {
+	pairs := len(data) / 2
+	if pairs < 2 {
+		return "", false
+	}
+
+	evenZeros, oddZeros := 0, 0
+	for i := 0; i+1 < len(data); i += 2 {
+		if data[i] == 0 && data[i+1] != 0 {
+			evenZeros++
+		}
+		if data[i] != 0 && data[i+1] == 0 {
+			oddZeros++
+		}
+	}
+
+	var encoding Encoding
+	switch {
+	case oddZeros*10 >= pairs*4 && evenZeros == 0:
+		encoding = EncodingUTF16LE
+	case evenZeros*10 >= pairs*4 && oddZeros == 0:
+		encoding = EncodingUTF16BE
+	default:
+		return "", false
+	}
+
+	// The decoded text must itself look like text
+	if utf8BinaryReason(trimPartialRune(decodeUTF16(data[:2*pairs], encoding).Text), config) != "" {
+		return "", false
+	}
+	return encoding, true
+}
+
+// isLatin1Text reports whether data reads as ISO-8859-1 text: few control characters,
+// counting the C1 range that text files do not use. Latin-1 is a guess for any bytes,
+// so it allows at most 5% control characters whatever the configured ratio.
+func isLatin1Text(data []byte, config types.SniffConfig) bool {
+	if len(data) == 0 {
+		return false
+	}
+
+	controls := 0
+	for _, b := range data {
+		if (b < 0x20 && b != 0 && b != '\t' && b != '\n' && b != '\r') || (b >= 0x7F && b < 0xA0) {
+			controls++
+		}
+	}
+	ratio := float64(controls) / float64(len(data))
+	return ratio < 0.05 && ratio <= config.MaxControlRatio
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence cut off at the end of a sample.
+func trimPartialRune(data []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		start := len(data) - i
+		if utf8.RuneStart(data[start]) {
+			if !utf8.FullRune(data[start:]) {
+				return data[:start]
+			}
+			break
+		}
+	}
+	return data
+}
@@ -0,0 +1,182 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// utf16Bytes encodes s as UTF-16 with an optional byte order mark.
+func utf16Bytes(s string, bigEndian, bom bool) []byte {
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	out := make([]byte, 0, 2*len(units))
+	for _, unit := range units {
+		if bigEndian {
+			out = append(out, byte(unit>>8), byte(unit))
+		} else {
+			out = append(out, byte(unit), byte(unit>>8))
+		}
+	}
+	return out
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Encoding
+	}{
+		{"utf-8", []byte("hello 🚀\n"), EncodingUTF8},
+		{"utf-8 with bom", []byte("\xEF\xBB\xBFhello"), EncodingUTF8},
+		{"utf-8 cut mid-rune", []byte("hello 🚀")[:8], EncodingUTF8},
+		{"utf-16le bom", utf16Bytes("hello 🚀", false, true), EncodingUTF16LE},
+		{"utf-16be bom", utf16Bytes("hello 🚀", true, true), EncodingUTF16BE},
+		{"utf-16le without bom", utf16Bytes("package main\n", false, false), EncodingUTF16LE},
+		{"utf-16be without bom", utf16Bytes("package main\n", true, false), EncodingUTF16BE},
+		{"latin-1", []byte("caf\xe9 cr\xe8me br\xfbl\xe9e\n"), EncodingLatin1},
+		{"binary", []byte{0x00, 0x01, 0x02, 0x03, 0x89, 0x50, 0x4E, 0x47}, EncodingBinary},
+		{"c1 controls", []byte{0x81, 0x82, 0x83, 0x84, 0x85, 0x86}, EncodingBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectEncoding(tt.data))
+		})
+	}
+}
+
+func TestSniff(t *testing.T) {
+	t.Run("reports why content is binary", func(t *testing.T) {
+		encoding, reason := Sniff([]byte{0x89, 'P', 'N', 'G', 0x00, 0x1A}, types.SniffConfig{})
+		assert.Equal(t, EncodingBinary, encoding)
+		assert.Equal(t, "contains_null_bytes", reason)
+	})
+
+	t.Run("null ratio allows sparse nul bytes", func(t *testing.T) {
+		data := append([]byte("some text with a stray nul"), 0x00)
+		encoding, _ := Sniff(data, types.SniffConfig{MaxNullRatio: 0.1})
+		assert.Equal(t, EncodingUTF8, encoding)
+
+		encoding, reason := Sniff([]byte("ab\x00\x00"), types.SniffConfig{MaxNullRatio: 0.1})
+		assert.Equal(t, EncodingBinary, encoding)
+		assert.Equal(t, "high_null_bytes_50.0%", reason)
+	})
+
+	t.Run("control ratio", func(t *testing.T) {
+		data := []byte("ab\x1b\x1b")
+		encoding, reason := Sniff(data, types.SniffConfig{MaxControlRatio: 0.25})
+		assert.Equal(t, EncodingBinary, encoding)
+		assert.Equal(t, "high_control_chars_50.0%", reason)
+
+		encoding, _ = Sniff(data, types.SniffConfig{MaxControlRatio: 0.5})
+		assert.Equal(t, EncodingUTF8, encoding)
+	})
+}
+
+func TestSniffFile_SampleSize(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "data.txt")
+	content := append([]byte(strings.Repeat("a", 2048)), 0x00)
+	require.NoError(t, os.WriteFile(path, content, 0644))
+
+	encoding, _ := SniffFile(path, types.SniffConfig{})
+	assert.Equal(t, EncodingUTF8, encoding, "nul byte lies beyond the default sample")
+
+	encoding, reason := SniffFile(path, types.SniffConfig{SampleSize: 4096})
+	assert.Equal(t, EncodingBinary, encoding)
+	assert.Equal(t, "contains_null_bytes", reason)
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("utf-8 is unchanged", func(t *testing.T) {
+		data := []byte("hello 🚀")
+		decoded, err := Decode(data, types.SniffConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, data, decoded.Text)
+		assert.Equal(t, 6, decoded.OriginalOffset(6))
+	})
+
+	t.Run("utf-16 maps offsets and round-trips", func(t *testing.T) {
+		data := utf16Bytes("a🚀b", false, true)
+		decoded, err := Decode(data, types.SniffConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, "a🚀b", string(decoded.Text))
+
+		// BOM(2) + 'a'(2): the emoji starts at byte 4 and its surrogate pair ends at 8
+		assert.Equal(t, 2, decoded.OriginalOffset(0))
+		assert.Equal(t, 4, decoded.OriginalOffset(1))
+		assert.Equal(t, 8, decoded.OriginalOffset(5))
+		assert.Equal(t, len(data), decoded.OriginalOffset(len(decoded.Text)))
+
+		encoded, err := decoded.Encode([]byte("ab"))
+		require.NoError(t, err)
+		assert.Equal(t, utf16Bytes("ab", false, true), encoded)
+	})
+
+	t.Run("latin-1 round-trips", func(t *testing.T) {
+		data := []byte("caf\xe9 :)\n")
+		decoded, err := Decode(data, types.SniffConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, "café :)\n", string(decoded.Text))
+		assert.Equal(t, 5, decoded.OriginalOffset(6), "é is two bytes in UTF-8 but one in Latin-1")
+
+		encoded, err := decoded.Encode([]byte("café \n"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("caf\xe9 \n"), encoded)
+
+		_, err = decoded.Encode([]byte("🚀"))
+		assert.ErrorContains(t, err, "cannot be encoded in iso-8859-1")
+	})
+
+	t.Run("utf-8 byte order mark is kept out of the text", func(t *testing.T) {
+		data := []byte("\xef\xbb\xbfhi 🚀\n")
+		decoded, err := Decode(data, types.SniffConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, EncodingUTF8, decoded.Encoding)
+		assert.Equal(t, "hi 🚀\n", string(decoded.Text))
+		assert.True(t, decoded.HasBOM())
+		assert.Equal(t, 6, decoded.OriginalOffset(3))
+
+		encoded, err := decoded.Encode([]byte("hi \n"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("\xef\xbb\xbfhi \n"), encoded)
+
+		stripped := decoded.StripBOM()
+		assert.True(t, stripped.BOMStripped())
+		encoded, err = stripped.Encode([]byte("hi \n"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hi \n"), encoded)
+	})
+
+	t.Run("utf-16 keeps its byte order mark", func(t *testing.T) {
+		decoded, err := Decode(utf16Bytes("ab", false, true), types.SniffConfig{})
+		require.NoError(t, err)
+		stripped := decoded.StripBOM()
+		assert.False(t, stripped.BOMStripped())
+		encoded, err := stripped.Encode([]byte("ab"))
+		require.NoError(t, err)
+		assert.Equal(t, utf16Bytes("ab", false, true), encoded)
+	})
+
+	t.Run("binary is rejected", func(t *testing.T) {
+		_, err := Decode([]byte{0x00, 0x01, 0x02, 0x03}, types.SniffConfig{})
+		assert.ErrorIs(t, err, ErrBinaryContent)
+	})
+}
+
+func TestIsTextFile_Encodings(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "utf16.txt")
+	require.NoError(t, os.WriteFile(path, utf16Bytes("hello 🚀\n", false, true), 0644))
+	assert.True(t, IsTextFile(path))
+}
@@ -4,9 +4,13 @@ package fs
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	iofs "io/fs"
 	"os"
+	"syscall"
+	"time"
 	"unicode/utf8"
 
 	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
@@ -24,6 +28,52 @@ func ReadFile(filepath string) types.Result[[]byte] {
 	return types.Ok(data)
 }
 
+// ErrRetriesExhausted marks a read that still failed with a transient error after its
+// last retry.
+var ErrRetriesExhausted = errors.New("read retries exhausted")
+
+// readFile and sleep are replaced in tests to simulate flaky reads.
+var (
+	readFile = os.ReadFile
+	sleep    = time.Sleep
+)
+
+// ReadFileWithRetry reads the entire contents of a file like ReadFile, retrying a read
+// that fails with a transient error up to retry.Attempts times. The wait starts at
+// retry.Backoff and doubles after each retry; a read that still fails wraps
+// ErrRetriesExhausted.
+func ReadFileWithRetry(filepath string, retry types.RetryConfig) types.Result[[]byte] {
+	retry = retry.WithDefaults()
+	backoff := retry.Backoff
+
+	for attempt := 1; ; attempt++ {
+		data, err := readFile(filepath) // #nosec G304 - filepath is validated by caller
+		if err == nil {
+			return types.Ok(data)
+		}
+		if retry.Attempts <= 0 || !isTransient(err) {
+			return types.Err[[]byte](err)
+		}
+		if attempt > retry.Attempts {
+			return types.Err[[]byte](fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, attempt, err))
+		}
+
+		ctx := ctxutil.WithFilePath(ctxutil.NewComponentContext("read_file", "fs"), filepath)
+		logging.Debug(ctx, "Retrying failed read", "attempt", attempt, "backoff", backoff, "error", err)
+		sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTransient reports whether a read that failed with err may succeed when retried.
+// Missing files, denied access and directories fail the same way every time.
+func isTransient(err error) bool {
+	return !errors.Is(err, iofs.ErrNotExist) &&
+		!errors.Is(err, iofs.ErrPermission) &&
+		!errors.Is(err, iofs.ErrInvalid) &&
+		!errors.Is(err, syscall.EISDIR)
+}
+
 // ReadFileStream reads a file in chunks and returns a channel of byte slices.
 // This enables memory-efficient processing of large files.
 func ReadFileStream(filepath string, chunkSize int) types.Result[<-chan []byte] {
@@ -64,48 +114,58 @@ func ReadFileStream(filepath string, chunkSize int) types.Result[<-chan []byte]
 }
 
 // IsTextFile determines if a file contains text content by examining its contents.
-// It uses heuristics to detect binary vs text files.
+// It uses heuristics to detect binary vs text files; UTF-16 and Latin-1 files count
+// as text.
 func IsTextFile(filepath string) bool {
+	encoding, _ := SniffFile(filepath, types.DefaultSniffConfig())
+	return encoding != EncodingBinary
+}
+
+// SniffFile examines the start of the file at filepath and returns its encoding, or
+// EncodingBinary and the reason the file is not text.
+func SniffFile(filepath string, config types.SniffConfig) (Encoding, string) {
 	ctx := ctxutil.WithFilePath(ctxutil.NewComponentContext("is_text_file", "fs"), filepath)
+	config = config.WithDefaults()
 
 	file, err := os.Open(filepath) // #nosec G304 - filepath is validated by caller
 	if err != nil {
 		logging.Debug(ctx, "Failed to open file for text detection", "error", err)
-		return false
+		return EncodingBinary, "unreadable"
 	}
 	defer func() {
 		_ = file.Close() // Ignore error in cleanup
 	}()
 
 	// Read a sample of the file to determine if it's text
-	buffer := make([]byte, 1024)
-	n, err := file.Read(buffer)
-	if err != nil && err != io.EOF {
+	buffer := make([]byte, config.SampleSize)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 		logging.Debug(ctx, "Failed to read file for text detection", "error", err)
-		return false
+		return EncodingBinary, "unreadable"
 	}
 
 	if n == 0 {
 		logging.Debug(ctx, "Empty file detected as text", "bytes_read", n)
-		return true // Empty files are considered text
+		return EncodingUTF8, "" // Empty files are considered text
 	}
 
-	isText := isTextContent(buffer[:n])
+	encoding, reason := Sniff(buffer[:n], config)
 	logging.Debug(ctx, "File text detection completed",
-		"is_text", isText,
+		"is_text", encoding != EncodingBinary,
+		"encoding", encoding,
 		"bytes_analyzed", n,
 		"has_null_bytes", bytes.Contains(buffer[:n], []byte{0}),
 		"is_valid_utf8", utf8.Valid(buffer[:n]))
 
 	// Log detailed information for binary files that might contain emoji-like bytes
-	if !isText {
+	if encoding == EncodingBinary {
 		logging.Info(ctx, "Binary file detected - skipping emoji processing",
 			"file_path", filepath,
 			"bytes_analyzed", n,
-			"reason", getBinaryFileReason(buffer[:n]))
+			"reason", reason)
 	}
 
-	return isText
+	return encoding, reason
 }
 
 // GetFileInfo returns information about a file.
@@ -123,21 +183,41 @@ func GetFileInfo(filepath string) types.Result[types.FileInfo] {
 	return types.Ok(info)
 }
 
-// isTextContent determines if the given byte slice contains text content.
-// It uses UTF-8 aware analysis to properly handle emojis and other Unicode characters.
+// isTextContent determines if the given byte slice contains UTF-8 text content
+// under the default sniffing thresholds.
 func isTextContent(data []byte) bool {
+	return utf8BinaryReason(data, types.DefaultSniffConfig()) == ""
+}
+
+// getBinaryFileReason returns a description of why a file was detected as binary.
+func getBinaryFileReason(data []byte) string {
+	if reason := utf8BinaryReason(data, types.DefaultSniffConfig()); reason != "" {
+		return reason
+	}
+	return "unknown_binary_pattern"
+}
+
+// utf8BinaryReason returns why data is not UTF-8 text under config, or "" when it is.
+// It uses UTF-8 aware analysis to properly handle emojis and other Unicode characters.
+func utf8BinaryReason(data []byte, config types.SniffConfig) string {
 	if len(data) == 0 {
-		return true
+		return ""
 	}
 
 	// Check for null bytes (common in binary files)
-	if bytes.Contains(data, []byte{0}) {
-		return false
+	if nulls := bytes.Count(data, []byte{0}); nulls > 0 {
+		ratio := float64(nulls) / float64(len(data))
+		if config.MaxNullRatio <= 0 {
+			return "contains_null_bytes"
+		}
+		if ratio > config.MaxNullRatio {
+			return fmt.Sprintf("high_null_bytes_%.1f%%", ratio*100)
+		}
 	}
 
 	// Check if the content is valid UTF-8
 	if !utf8.Valid(data) {
-		return false
+		return "invalid_utf8"
 	}
 
 	// Count non-printable runes (not bytes) to properly handle UTF-8
@@ -147,11 +227,9 @@ func isTextContent(data []byte) bool {
 	// Process the data rune by rune instead of byte by byte
 	for len(data) > 0 {
 		r, size := utf8.DecodeRune(data)
-		if r == utf8.RuneError && size == 1 {
-			// Invalid UTF-8 sequence (shouldn't happen since we validated above)
-			nonPrintable++
-		} else if r < 32 && r != '\t' && r != '\n' && r != '\r' {
-			// Control characters (except tab, newline, carriage return)
+		if r != 0 && r < 32 && r != '\t' && r != '\n' && r != '\r' {
+			// Control characters (except tab, newline, carriage return); NUL is
+			// limited separately above
 			nonPrintable++
 		}
 		// Note: We don't count high Unicode runes (including emojis) as non-printable
@@ -161,44 +239,10 @@ func isTextContent(data []byte) bool {
 		data = data[size:]
 	}
 
-	// If more than 30% of runes are non-printable control characters, consider it binary
-	if totalRunes > 0 && float64(nonPrintable)/float64(totalRunes) > 0.30 {
-		return false
-	}
-
-	return true
-}
-
-// getBinaryFileReason returns a description of why a file was detected as binary.
-func getBinaryFileReason(data []byte) string {
-	if bytes.Contains(data, []byte{0}) {
-		return "contains_null_bytes"
-	}
-	if !utf8.Valid(data) {
-		return "invalid_utf8"
-	}
-
-	// Count non-printable control characters
-	nonPrintable := 0
-	totalRunes := 0
-
-	for len(data) > 0 {
-		r, size := utf8.DecodeRune(data)
-		if r == utf8.RuneError && size == 1 {
-			nonPrintable++
-		} else if r < 32 && r != '\t' && r != '\n' && r != '\r' {
-			nonPrintable++
-		}
-		totalRunes++
-		data = data[size:]
-	}
-
-	if totalRunes > 0 {
-		percentage := float64(nonPrintable) / float64(totalRunes) * 100
-		if percentage > 30 {
-			return fmt.Sprintf("high_control_chars_%.1f%%", percentage)
-		}
+	// Too many non-printable control characters means binary
+	if ratio := float64(nonPrintable) / float64(totalRunes); ratio > config.MaxControlRatio {
+		return fmt.Sprintf("high_control_chars_%.1f%%", ratio*100)
 	}
 
-	return "unknown_binary_pattern"
+	return ""
 }
@@ -3,14 +3,18 @@ package fs
 import (
 	"bytes"
 	"fmt"
+	iofs "io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/antimoji/antimoji/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReadFile(t *testing.T) {
@@ -91,6 +95,70 @@ func TestReadFile(t *testing.T) {
 	})
 }
 
+func TestReadFileWithRetry(t *testing.T) {
+	// flakyRead fails with errs in turn, then returns the content
+	flakyRead := func(t *testing.T, errs ...error) (*int, *[]time.Duration) {
+		reads := 0
+		var waits []time.Duration
+		originalRead, originalSleep := readFile, sleep
+		t.Cleanup(func() { readFile, sleep = originalRead, originalSleep })
+		readFile = func(name string) ([]byte, error) {
+			reads++
+			if reads <= len(errs) {
+				return nil, &iofs.PathError{Op: "read", Path: name, Err: errs[reads-1]}
+			}
+			return []byte("content"), nil
+		}
+		sleep = func(d time.Duration) { waits = append(waits, d) }
+		return &reads, &waits
+	}
+	retry := types.RetryConfig{Attempts: 3, Backoff: 10 * time.Millisecond}
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		reads, waits := flakyRead(t, syscall.EIO, syscall.ESTALE)
+
+		result := ReadFileWithRetry("share/file.txt", retry)
+		require.True(t, result.IsOk())
+		assert.Equal(t, "content", string(result.Unwrap()))
+		assert.Equal(t, 3, *reads)
+		assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, *waits)
+	})
+
+	t.Run("wraps the last error once retries are exhausted", func(t *testing.T) {
+		reads, waits := flakyRead(t, syscall.EIO, syscall.EIO, syscall.EIO, syscall.EIO)
+
+		result := ReadFileWithRetry("share/file.txt", retry)
+		require.True(t, result.IsErr())
+		assert.ErrorIs(t, result.Error(), ErrRetriesExhausted)
+		assert.ErrorIs(t, result.Error(), syscall.EIO)
+		assert.Contains(t, result.Error().Error(), "after 4 attempts")
+		assert.Equal(t, 4, *reads)
+		assert.Len(t, *waits, 3)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		for _, err := range []error{iofs.ErrNotExist, iofs.ErrPermission, syscall.EISDIR} {
+			reads, waits := flakyRead(t, err)
+
+			result := ReadFileWithRetry("share/file.txt", retry)
+			require.True(t, result.IsErr())
+			assert.ErrorIs(t, result.Error(), err)
+			assert.NotErrorIs(t, result.Error(), ErrRetriesExhausted)
+			assert.Equal(t, 1, *reads)
+			assert.Empty(t, *waits)
+		}
+	})
+
+	t.Run("reads once without retries", func(t *testing.T) {
+		reads, _ := flakyRead(t, syscall.EIO)
+
+		result := ReadFileWithRetry("share/file.txt", types.RetryConfig{})
+		require.True(t, result.IsErr())
+		assert.NotErrorIs(t, result.Error(), ErrRetriesExhausted)
+		assert.Equal(t, 1, *reads)
+	})
+}
+
 func TestReadFileStream(t *testing.T) {
 	tmpDir := t.TempDir()
 
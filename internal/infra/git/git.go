@@ -0,0 +1,388 @@
+// Package git provides read-only access to git history for scanning without checkouts.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commitMarker prefixes the per-commit header line emitted by logFormat.
+const commitMarker = "\x00antimoji-commit\x00"
+
+// logFormat emits commitMarker followed by NUL-separated commit fields. NUL bytes cannot be
+// passed as arguments, so they are written using git's %x00 placeholder.
+const logFormat = "%x00antimoji-commit%x00%H%x00%an%x00%ad%x00%s"
+
+// Commit describes a single commit.
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 8 {
+		return c.Hash[:8]
+	}
+	return c.Hash
+}
+
+// AddedLine is a line added to a file by a commit.
+type AddedLine struct {
+	Commit Commit `json:"commit"`
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+}
+
+// CommitMessage is the full message of a commit.
+type CommitMessage struct {
+	Commit  Commit `json:"commit"`
+	Message string `json:"message"`
+}
+
+// scissorsLine marks the start of the diff appended by `git commit --verbose`.
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// Repository runs read-only git commands in a working directory.
+type Repository struct {
+	dir string
+}
+
+// NewRepository creates a repository rooted at dir ("" for the current directory).
+func NewRepository(dir string) *Repository {
+	return &Repository{dir: dir}
+}
+
+// AddedLines returns every line added by the commits in revRange (e.g. "v1.0..HEAD"),
+// oldest commit first. Merge commits are skipped since their changes are attributed to
+// the commits being merged. paths optionally limits the scan to pathspecs.
+func (r *Repository) AddedLines(ctx context.Context, revRange string, paths []string) ([]AddedLine, error) {
+	if revRange == "" {
+		return nil, fmt.Errorf("revision range cannot be empty")
+	}
+
+	args := []string{
+		"-c", "core.quotepath=off",
+		"log", "--reverse", "--no-merges", "--no-color", "--no-ext-diff", "--no-renames",
+		"--date=iso-strict", "-p", "-U0",
+		"--format=" + logFormat,
+		revRange, "--",
+	}
+	args = append(args, paths...)
+
+	output, err := r.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLog(output)
+}
+
+// CommitMessages returns the messages of the commits in revRange, oldest first.
+func (r *Repository) CommitMessages(ctx context.Context, revRange string) ([]CommitMessage, error) {
+	if revRange == "" {
+		return nil, fmt.Errorf("revision range cannot be empty")
+	}
+
+	output, err := r.run(ctx, "log", "--reverse", "--date=iso-strict",
+		"--format="+logFormat+"%x00%B%x1e", revRange, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []CommitMessage
+	for _, record := range strings.Split(string(output), "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		if !strings.HasPrefix(record, commitMarker) {
+			return nil, fmt.Errorf("unexpected git log record: %q", record)
+		}
+
+		fields := strings.SplitN(strings.TrimPrefix(record, commitMarker), "\x00", 5)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected git log record: %q", record)
+		}
+		commit, err := parseCommitHeader(strings.Join(fields[:4], "\x00"))
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, CommitMessage{Commit: commit, Message: strings.TrimRight(fields[4], "\n")})
+	}
+
+	return messages, nil
+}
+
+// CurrentBranch returns the short name of the checked-out branch, including a branch with
+// no commits yet, or an empty string when HEAD is detached.
+func (r *Repository) CurrentBranch(ctx context.Context) (string, error) {
+	output, err := r.run(ctx, "branch", "--show-current")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Head returns the full hash of the checked-out commit.
+func (r *Repository) Head(ctx context.Context) (string, error) {
+	output, err := r.run(ctx, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// TopLevel returns the absolute path of the root of the working tree.
+func (r *Repository) TopLevel(ctx context.Context) (string, error) {
+	output, err := r.run(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// HooksDir returns the directory git runs hooks from, honouring core.hooksPath.
+func (r *Repository) HooksDir(ctx context.Context) (string, error) {
+	output, err := r.run(ctx, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.dir, dir)
+	}
+	return dir, nil
+}
+
+// ChangedFiles returns the files of the working tree that differ from rev, committed
+// or not, and the untracked files that are not ignored, as absolute paths.
+func (r *Repository) ChangedFiles(ctx context.Context, rev string) ([]string, error) {
+	if _, err := r.run(ctx, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown commit %q", rev)
+	}
+	changed, err := r.paths(ctx, "diff", "--name-only", "--no-renames", "-z", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := r.paths(ctx, "ls-files", "--others", "--exclude-standard", "--full-name", "-z", "--", ":/")
+	if err != nil {
+		return nil, err
+	}
+	return append(changed, untracked...), nil
+}
+
+// CommittedSince returns the files changed by the commits made since t, as absolute
+// paths.
+func (r *Repository) CommittedSince(ctx context.Context, t time.Time) ([]string, error) {
+	return r.paths(ctx, "log", "--since="+t.Format(time.RFC3339), "--name-only", "--no-renames", "--format=", "-z", "--")
+}
+
+// UncommittedFiles returns the tracked files modified in the working tree and the
+// untracked files that are not ignored, as absolute paths.
+func (r *Repository) UncommittedFiles(ctx context.Context) ([]string, error) {
+	return r.paths(ctx, "ls-files", "--modified", "--others", "--exclude-standard", "--full-name", "-z", "--", ":/")
+}
+
+// paths runs a git command listing NUL-separated paths relative to the root of the
+// working tree and returns them once each as absolute paths.
+func (r *Repository) paths(ctx context.Context, args ...string) ([]string, error) {
+	top, err := r.TopLevel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output, err := r.run(ctx, append([]string{"-c", "core.quotepath=off"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range strings.Split(string(output), "\x00") {
+		// git log separates the files of each commit with a newline
+		path = strings.Trim(path, "\n")
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, filepath.Join(top, filepath.FromSlash(path)))
+	}
+	return paths, nil
+}
+
+// CleanMessage strips what git strips from a message being edited: comment lines and
+// everything below the scissors line added by `git commit --verbose`.
+func CleanMessage(message string) string {
+	lines := strings.Split(message, "\n")
+	kept := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if strings.TrimRight(line, "\r") == scissorsLine {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	return strings.TrimRight(strings.Join(kept, "\n"), "\n")
+}
+
+// run executes git and returns stdout, including stderr in errors.
+func (r *Repository) run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - arguments are constructed internally
+	cmd.Dir = r.dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// parseLog parses `git log -p -U0` output produced with our commit marker format.
+func parseLog(output []byte) ([]AddedLine, error) {
+	var lines []AddedLine
+	var commit Commit
+	var path string
+	newLine := 0
+	inHunk := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, commitMarker):
+			parsed, err := parseCommitHeader(strings.TrimPrefix(line, commitMarker))
+			if err != nil {
+				return nil, err
+			}
+			commit = parsed
+			path = ""
+			inHunk = false
+
+		case strings.HasPrefix(line, "diff --git "):
+			path = ""
+			inHunk = false
+
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			target := strings.TrimPrefix(line, "+++ ")
+			if target == "/dev/null" {
+				path = ""
+			} else {
+				path = strings.TrimPrefix(target, "b/")
+			}
+
+		case strings.HasPrefix(line, "@@ "):
+			start, err := parseHunkStart(line)
+			if err != nil {
+				return nil, err
+			}
+			newLine = start
+			inHunk = true
+
+		case inHunk && strings.HasPrefix(line, "+"):
+			if path != "" {
+				lines = append(lines, AddedLine{
+					Commit: commit,
+					Path:   path,
+					Line:   newLine,
+					Text:   line[1:],
+				})
+			}
+			newLine++
+
+		case inHunk && strings.HasPrefix(line, " "):
+			newLine++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+
+	return lines, nil
+}
+
+// PatchAddedLines returns the lines the unified diff hunks of patch add to the file
+// at path, such as the patch of a pull request file in the GitHub API.
+func PatchAddedLines(path, patch string) ([]AddedLine, error) {
+	var lines []AddedLine
+	newLine := 0
+	inHunk := false
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			start, err := parseHunkStart(line)
+			if err != nil {
+				return nil, err
+			}
+			newLine = start
+			inHunk = true
+
+		case inHunk && strings.HasPrefix(line, "+"):
+			lines = append(lines, AddedLine{Path: path, Line: newLine, Text: strings.TrimSuffix(line[1:], "\r")})
+			newLine++
+
+		case inHunk && strings.HasPrefix(line, " "):
+			newLine++
+		}
+	}
+	return lines, nil
+}
+
+// parseCommitHeader parses "hash\x00author\x00date\x00subject".
+func parseCommitHeader(header string) (Commit, error) {
+	fields := strings.SplitN(header, "\x00", 4)
+	if len(fields) != 4 {
+		return Commit{}, fmt.Errorf("unexpected commit header: %q", header)
+	}
+
+	date, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return Commit{}, fmt.Errorf("invalid commit date %q: %w", fields[2], err)
+	}
+
+	return Commit{
+		Hash:    fields[0],
+		Author:  fields[1],
+		Date:    date,
+		Subject: fields[3],
+	}, nil
+}
+
+// parseHunkStart extracts the new-file start line from "@@ -a,b +c,d @@".
+func parseHunkStart(header string) (int, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, fmt.Errorf("unexpected hunk header: %q", header)
+	}
+
+	start := strings.TrimPrefix(fields[2], "+")
+	if idx := strings.IndexByte(start, ','); idx >= 0 {
+		start = start[:idx]
+	}
+
+	value, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected hunk header: %q", header)
+	}
+	return value, nil
+}
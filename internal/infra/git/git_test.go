@@ -0,0 +1,230 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLog(t *testing.T) {
+	output := commitMarker + "abc123\x00Jane\x002024-05-01T10:00:00Z\x00Add docs\n" +
+		"\n" +
+		"diff --git a/README.md b/README.md\n" +
+		"index 1111111..2222222 100644\n" +
+		"--- a/README.md\n" +
+		"+++ b/README.md\n" +
+		"@@ -2,0 +3,2 @@ intro\n" +
+		"+Ship it 🚀\n" +
+		"+++counter\n" +
+		"diff --git a/old.txt b/old.txt\n" +
+		"deleted file mode 100644\n" +
+		"--- a/old.txt\n" +
+		"+++ /dev/null\n" +
+		"@@ -1 +0,0 @@\n" +
+		"-gone 🎉\n"
+
+	lines, err := parseLog([]byte(output))
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+
+	assert.Equal(t, "abc123", lines[0].Commit.Hash)
+	assert.Equal(t, "Jane", lines[0].Commit.Author)
+	assert.Equal(t, "Add docs", lines[0].Commit.Subject)
+	assert.Equal(t, "README.md", lines[0].Path)
+	assert.Equal(t, 3, lines[0].Line)
+	assert.Equal(t, "Ship it 🚀", lines[0].Text)
+
+	// An added line starting with "++" must not be mistaken for a file header
+	assert.Equal(t, 4, lines[1].Line)
+	assert.Equal(t, "++counter", lines[1].Text)
+}
+
+func TestPatchAddedLines(t *testing.T) {
+	patch := "@@ -1,3 +1,4 @@\n" +
+		" keep\n" +
+		"-old 🎉\n" +
+		"+new 🚀\n" +
+		"+\n" +
+		" keep\n" +
+		"@@ -10 +11 @@ func main() {\n" +
+		"-x\n" +
+		"+y 🔥\n" +
+		"\\ No newline at end of file"
+
+	lines, err := PatchAddedLines("main.go", patch)
+	require.NoError(t, err)
+	assert.Equal(t, []AddedLine{
+		{Path: "main.go", Line: 2, Text: "new 🚀"},
+		{Path: "main.go", Line: 3, Text: ""},
+		{Path: "main.go", Line: 11, Text: "y 🔥"},
+	}, lines)
+
+	_, err = PatchAddedLines("main.go", "@@ nonsense @@\n+x")
+	assert.Error(t, err)
+}
+
+func TestRepository_AddedLines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Tester", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=Tester", "GIT_COMMITTER_EMAIL=t@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	gitCmd("init", "-q")
+	write("main.go", "package main\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "initial")
+	gitCmd("tag", "v1.0")
+
+	write("main.go", "package main\n\n// done ✅\n")
+	gitCmd("commit", "-q", "-am", "add comment")
+	write("notes.md", "first\nparty 🎉\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "add notes")
+
+	repo := NewRepository(dir)
+
+	t.Run("returns lines added in range", func(t *testing.T) {
+		lines, err := repo.AddedLines(context.Background(), "v1.0..HEAD", nil)
+		require.NoError(t, err)
+		require.Len(t, lines, 4)
+
+		assert.Equal(t, "add comment", lines[0].Commit.Subject)
+		assert.Equal(t, "main.go", lines[1].Path)
+		assert.Equal(t, 3, lines[1].Line)
+		assert.Equal(t, "// done ✅", lines[1].Text)
+
+		assert.Equal(t, "add notes", lines[3].Commit.Subject)
+		assert.Equal(t, "notes.md", lines[3].Path)
+		assert.Equal(t, 2, lines[3].Line)
+		assert.Equal(t, "Tester", lines[3].Commit.Author)
+	})
+
+	t.Run("limits to pathspecs", func(t *testing.T) {
+		lines, err := repo.AddedLines(context.Background(), "v1.0..HEAD", []string{"notes.md"})
+		require.NoError(t, err)
+		require.Len(t, lines, 2)
+		assert.Equal(t, "notes.md", lines[0].Path)
+	})
+
+	t.Run("rejects invalid range", func(t *testing.T) {
+		_, err := repo.AddedLines(context.Background(), "nope..HEAD", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects empty range", func(t *testing.T) {
+		_, err := repo.AddedLines(context.Background(), "", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("returns commit messages in range", func(t *testing.T) {
+		gitCmd("commit", "-q", "--allow-empty", "-m", "release 🚀", "-m", "body line\nsecond ✨")
+
+		messages, err := repo.CommitMessages(context.Background(), "v1.0..HEAD")
+		require.NoError(t, err)
+		require.Len(t, messages, 3)
+
+		assert.Equal(t, "add comment", messages[0].Message)
+		assert.Equal(t, "release 🚀", messages[2].Commit.Subject)
+		assert.Equal(t, "release 🚀\n\nbody line\nsecond ✨", messages[2].Message)
+	})
+
+	t.Run("returns current branch", func(t *testing.T) {
+		gitCmd("checkout", "-q", "-b", "feature/ship-it")
+
+		branch, err := repo.CurrentBranch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "feature/ship-it", branch)
+	})
+
+	t.Run("returns head commit", func(t *testing.T) {
+		head, err := repo.Head(context.Background())
+		require.NoError(t, err)
+		messages, err := repo.CommitMessages(context.Background(), "HEAD~1..HEAD")
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, messages[0].Commit.Hash, head)
+	})
+
+	t.Run("returns changed files", func(t *testing.T) {
+		top, err := filepath.EvalSymlinks(dir)
+		require.NoError(t, err)
+		write("main.go", "package main\n// edited\n")
+		write("draft.md", "wip\n")
+		write(".gitignore", "*.log\n")
+		write("debug.log", "ignored\n")
+
+		changed, err := repo.ChangedFiles(context.Background(), "v1.0")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			filepath.Join(top, "main.go"), filepath.Join(top, "notes.md"),
+			filepath.Join(top, "draft.md"), filepath.Join(top, ".gitignore"),
+		}, changed)
+
+		uncommitted, err := repo.UncommittedFiles(context.Background())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			filepath.Join(top, "main.go"), filepath.Join(top, "draft.md"), filepath.Join(top, ".gitignore"),
+		}, uncommitted)
+
+		committed, err := repo.CommittedSince(context.Background(), time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{filepath.Join(top, "main.go"), filepath.Join(top, "notes.md")}, committed)
+		committed, err = repo.CommittedSince(context.Background(), time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, committed)
+
+		_, err = repo.ChangedFiles(context.Background(), "nope")
+		assert.ErrorContains(t, err, `unknown commit "nope"`)
+
+		require.NoError(t, os.Remove(filepath.Join(dir, "draft.md")))
+		require.NoError(t, os.Remove(filepath.Join(dir, ".gitignore")))
+		require.NoError(t, os.Remove(filepath.Join(dir, "debug.log")))
+		gitCmd("checkout", "-q", "--", "main.go")
+	})
+
+	t.Run("returns top level and hooks directory", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+		sub := NewRepository(filepath.Join(dir, "sub"))
+
+		top, err := sub.TopLevel(context.Background())
+		require.NoError(t, err)
+		want, err := filepath.EvalSymlinks(dir)
+		require.NoError(t, err)
+		assert.Equal(t, want, top)
+
+		hooks, err := sub.HooksDir(context.Background())
+		require.NoError(t, err)
+		resolved, err := filepath.EvalSymlinks(hooks)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(want, ".git", "hooks"), resolved)
+	})
+}
+
+func TestCleanMessage(t *testing.T) {
+	message := "Fix parser 🐛\n\n# Please enter the commit message 🎉\nDetails\n" +
+		scissorsLine + "\ndiff --git a/x b/x\n+added 🚀\n"
+
+	assert.Equal(t, "Fix parser 🐛\n\nDetails", CleanMessage(message))
+	assert.Equal(t, "", CleanMessage("# only comments\n"))
+}
@@ -0,0 +1,342 @@
+// Package github is the client of the GitHub REST API the pull request bot uses: it
+// authenticates as a GitHub App installation or with a token, reads the files a pull
+// request changes, and posts reviews and commit statuses.
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIURL is the public GitHub REST API.
+	DefaultAPIURL = "https://api.github.com"
+
+	// apiTimeout bounds a single API request.
+	apiTimeout = 30 * time.Second
+
+	// maxResponseSize bounds API responses.
+	maxResponseSize = 10 << 20
+
+	// perPage is the page size of listed resources, the largest the API allows.
+	perPage = 100
+
+	// tokenMargin renews installation tokens this long before they expire.
+	tokenMargin = time.Minute
+)
+
+// Commit status states.
+const (
+	StatePending = "pending"
+	StateSuccess = "success"
+	StateFailure = "failure"
+	StateError   = "error"
+)
+
+// PullRequest is a pull request of a repository.
+type PullRequest struct {
+	Number int    `json:"number"`
+	State  string `json:"state"`
+	Head   struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+// PullRequestFile is a file a pull request changes.
+type PullRequestFile struct {
+	Filename string `json:"filename"`
+	Status   string `json:"status"`
+	// Patch is the unified diff of the file; the API leaves it out for binary files
+	// and very large diffs
+	Patch string `json:"patch"`
+}
+
+// ReviewComment is a comment on a line of a pull request diff.
+type ReviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side,omitempty"`
+	Body string `json:"body"`
+}
+
+// Review is a pull request review with its line comments.
+type Review struct {
+	CommitID string          `json:"commit_id,omitempty"`
+	Body     string          `json:"body,omitempty"`
+	Event    string          `json:"event"`
+	Comments []ReviewComment `json:"comments,omitempty"`
+}
+
+// Status is a commit status.
+type Status struct {
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// Client calls the GitHub API on behalf of a token or an App installation.
+type Client struct {
+	client *http.Client
+	apiURL string
+	token  func(ctx context.Context) (string, error)
+}
+
+// NewClient creates a client for the API at apiURL (the public API when "") that
+// authenticates with token.
+func NewClient(httpClient *http.Client, apiURL, token string) *Client {
+	return &Client{
+		client: httpClient,
+		apiURL: apiBase(apiURL),
+		token:  func(context.Context) (string, error) { return token, nil },
+	}
+}
+
+// apiBase returns the base URL of the API at apiURL, the public API when "".
+func apiBase(apiURL string) string {
+	if apiURL == "" {
+		return DefaultAPIURL
+	}
+	return strings.TrimSuffix(apiURL, "/")
+}
+
+// PullRequest returns pull request number of repo ("owner/name").
+func (c *Client) PullRequest(ctx context.Context, repo string, number int) (PullRequest, error) {
+	var pr PullRequest
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", repo, number), nil, &pr); err != nil {
+		return PullRequest{}, fmt.Errorf("failed to read pull request %s#%d: %w", repo, number, err)
+	}
+	return pr, nil
+}
+
+// PullRequestFiles returns the files pull request number of repo changes.
+func (c *Client) PullRequestFiles(ctx context.Context, repo string, number int) ([]PullRequestFile, error) {
+	var files []PullRequestFile
+	err := c.list(ctx, fmt.Sprintf("/repos/%s/pulls/%d/files", repo, number), func(page json.RawMessage) (int, error) {
+		var listed []PullRequestFile
+		err := json.Unmarshal(page, &listed)
+		files = append(files, listed...)
+		return len(listed), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the files of %s#%d: %w", repo, number, err)
+	}
+	return files, nil
+}
+
+// ReviewComments returns the line comments of pull request number of repo.
+func (c *Client) ReviewComments(ctx context.Context, repo string, number int) ([]ReviewComment, error) {
+	var comments []ReviewComment
+	err := c.list(ctx, fmt.Sprintf("/repos/%s/pulls/%d/comments", repo, number), func(page json.RawMessage) (int, error) {
+		var listed []ReviewComment
+		err := json.Unmarshal(page, &listed)
+		comments = append(comments, listed...)
+		return len(listed), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the review comments of %s#%d: %w", repo, number, err)
+	}
+	return comments, nil
+}
+
+// CreateReview posts review on pull request number of repo.
+func (c *Client) CreateReview(ctx context.Context, repo string, number int, review Review) error {
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls/%d/reviews", repo, number), review, nil); err != nil {
+		return fmt.Errorf("failed to review %s#%d: %w", repo, number, err)
+	}
+	return nil
+}
+
+// CreateStatus sets status on commit sha of repo.
+func (c *Client) CreateStatus(ctx context.Context, repo, sha string, status Status) error {
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/statuses/%s", repo, sha), status, nil); err != nil {
+		return fmt.Errorf("failed to set the status of %s@%s: %w", repo, sha, err)
+	}
+	return nil
+}
+
+// list calls fn with each page of the listing at path until a page is not full.
+func (c *Client) list(ctx context.Context, path string, fn func(page json.RawMessage) (int, error)) error {
+	for page := 1; ; page++ {
+		var raw json.RawMessage
+		if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s?per_page=%d&page=%d", path, perPage, page), nil, &raw); err != nil {
+			return err
+		}
+		n, err := fn(raw)
+		if err != nil {
+			return err
+		}
+		if n < perPage {
+			return nil
+		}
+	}
+}
+
+// do sends a request to the API endpoint path with body encoded as JSON, when not
+// nil, and decodes the JSON response into v, when not nil.
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+	return request(ctx, c.client, method, c.apiURL+path, token, body, v)
+}
+
+// request sends an API request authorized by token, when not "", and decodes its
+// response.
+func request(ctx context.Context, client *http.Client, method, url, token string, body, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&apiError)
+		if apiError.Message != "" {
+			return fmt.Errorf("unexpected status %s: %s", resp.Status, apiError.Message)
+		}
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v)
+}
+
+// App authenticates as a GitHub App and creates clients for its installations.
+type App struct {
+	client *http.Client
+	apiURL string
+	id     int64
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	tokens map[int64]installationToken
+}
+
+// installationToken is an installation access token with its expiry.
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewApp creates the GitHub App id of the API at apiURL (the public API when ""),
+// signing in with the PEM private key the App settings generate.
+func NewApp(httpClient *http.Client, apiURL string, id int64, privateKey []byte) (*App, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if pkcs8Err != nil || !ok {
+			return nil, fmt.Errorf("private key is not an RSA key: %w", err)
+		}
+		key = rsaKey
+	}
+	return &App{
+		client: httpClient,
+		apiURL: apiBase(apiURL),
+		id:     id,
+		key:    key,
+		tokens: make(map[int64]installationToken),
+	}, nil
+}
+
+// Installation returns a client acting as installation id of the App.
+func (a *App) Installation(id int64) *Client {
+	return &Client{
+		client: a.client,
+		apiURL: a.apiURL,
+		token:  func(ctx context.Context) (string, error) { return a.installationToken(ctx, id) },
+	}
+}
+
+// installationToken returns an access token of installation id, creating one when
+// none is cached or it is about to expire.
+func (a *App) installationToken(ctx context.Context, id int64) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if token, ok := a.tokens[id]; ok && time.Until(token.ExpiresAt) > tokenMargin {
+		return token.Token, nil
+	}
+
+	jwt, err := a.jwt(time.Now())
+	if err != nil {
+		return "", err
+	}
+	var token installationToken
+	url := a.apiURL + "/app/installations/" + strconv.FormatInt(id, 10) + "/access_tokens"
+	if err := request(ctx, a.client, http.MethodPost, url, jwt, nil, &token); err != nil {
+		return "", fmt.Errorf("failed to authenticate as installation %d: %w", id, err)
+	}
+	a.tokens[id] = token
+	return token.Token, nil
+}
+
+// jwt returns the JSON Web Token the App authenticates with at now, valid for nine
+// minutes of the ten the API allows.
+func (a *App) jwt(now time.Time) (string, error) {
+	encode := func(v any) string {
+		encoded, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(encoded)
+	}
+	unsigned := encode(map[string]string{"alg": "RS256", "typ": "JWT"}) + "." + encode(map[string]any{
+		// Issued a minute early for clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.id, 10),
+	})
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the App token: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
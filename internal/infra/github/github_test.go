@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	var review Review
+	var status Status
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch {
+		case r.URL.Path == "/repos/acme/api/pulls/7":
+			_, _ = w.Write([]byte(`{"number": 7, "state": "open", "head": {"sha": "abc"}}`))
+		case r.URL.Path == "/repos/acme/api/pulls/7/files":
+			// Two full pages and a last one
+			var files []PullRequestFile
+			count := perPage
+			if r.URL.Query().Get("page") == "3" {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				files = append(files, PullRequestFile{Filename: fmt.Sprintf("%s-%d.go", r.URL.Query().Get("page"), i)})
+			}
+			_ = json.NewEncoder(w).Encode(files)
+		case r.URL.Path == "/repos/acme/api/pulls/7/reviews":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/repos/acme/api/statuses/abc":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.Client(), server.URL+"/", "secret")
+	ctx := context.Background()
+
+	pr, err := client.PullRequest(ctx, "acme/api", 7)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", pr.Head.SHA)
+
+	files, err := client.PullRequestFiles(ctx, "acme/api", 7)
+	require.NoError(t, err)
+	assert.Len(t, files, 2*perPage+1)
+	assert.Equal(t, "3-0.go", files[len(files)-1].Filename)
+
+	comment := ReviewComment{Path: "a.go", Line: 3, Side: "RIGHT", Body: "no"}
+	require.NoError(t, client.CreateReview(ctx, "acme/api", 7, Review{CommitID: "abc", Event: "COMMENT", Comments: []ReviewComment{comment}}))
+	assert.Equal(t, []ReviewComment{comment}, review.Comments)
+
+	require.NoError(t, client.CreateStatus(ctx, "acme/api", "abc", Status{State: StateSuccess, Context: "antimoji"}))
+	assert.Equal(t, StateSuccess, status.State)
+
+	_, err = client.PullRequest(ctx, "acme/api", 8)
+	assert.ErrorContains(t, err, "404 Not Found: Not Found")
+}
+
+func TestApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	tokens := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/installations/42/access_tokens":
+			// The App signs in with a JWT it issued, signed with its key
+			jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			parts := strings.Split(jwt, ".")
+			require.Len(t, parts, 3)
+			signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+			require.NoError(t, err)
+			digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+			claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+			require.NoError(t, err)
+			assert.Contains(t, string(claims), `"iss":"12345"`)
+
+			tokens++
+			_ = json.NewEncoder(w).Encode(installationToken{Token: "installation", ExpiresAt: time.Now().Add(time.Hour)})
+		case "/repos/acme/api/pulls/1":
+			assert.Equal(t, "Bearer installation", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"number": 1, "head": {"sha": "abc"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	app, err := NewApp(server.Client(), server.URL, 12345, privateKey)
+	require.NoError(t, err)
+	client := app.Installation(42)
+	for i := 0; i < 2; i++ {
+		_, err = client.PullRequest(context.Background(), "acme/api", 1)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, tokens, "the installation token is reused until it expires")
+
+	_, err = app.Installation(7).PullRequest(context.Background(), "acme/api", 1)
+	assert.ErrorContains(t, err, "failed to authenticate as installation 7")
+
+	_, err = NewApp(server.Client(), "", 1, []byte("not a key"))
+	assert.Error(t, err)
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"action": "opened"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.NoError(t, VerifySignature([]byte("secret"), body, signature))
+	assert.ErrorIs(t, VerifySignature([]byte("other"), body, signature), ErrSignature)
+	assert.ErrorIs(t, VerifySignature([]byte("secret"), body, ""), ErrSignature)
+	assert.ErrorIs(t, VerifySignature([]byte("secret"), body, "sha256=zz"), ErrSignature)
+}
+
+func TestParsePullRequestEvent(t *testing.T) {
+	event, err := ParsePullRequestEvent([]byte(`{
+		"action": "synchronize",
+		"number": 7,
+		"pull_request": {"head": {"sha": "abc"}},
+		"repository": {"full_name": "acme/api"},
+		"installation": {"id": 42}
+	}`))
+	require.NoError(t, err)
+	assert.True(t, event.Changed())
+	assert.Equal(t, "acme/api", event.Repository.FullName)
+	assert.Equal(t, int64(42), event.Installation.ID)
+	assert.Equal(t, "abc", event.PullRequest.Head.SHA)
+
+	event.Action = "closed"
+	assert.False(t, event.Changed())
+
+	_, err = ParsePullRequestEvent([]byte(`{"action": "opened"}`))
+	assert.Error(t, err)
+}
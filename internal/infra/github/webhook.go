@@ -0,0 +1,74 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Webhook headers.
+const (
+	HeaderEvent     = "X-GitHub-Event"
+	HeaderSignature = "X-Hub-Signature-256"
+)
+
+// ErrSignature is returned for webhook deliveries not signed with the webhook secret.
+var ErrSignature = errors.New("invalid webhook signature")
+
+// VerifySignature checks that signature, the X-Hub-Signature-256 header of a webhook
+// delivery, is the HMAC of body with secret.
+func VerifySignature(secret, body []byte, signature string) error {
+	sum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrSignature
+	}
+	got, err := hex.DecodeString(sum)
+	if err != nil {
+		return ErrSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// PullRequestEvent is the payload of a pull_request webhook event.
+type PullRequestEvent struct {
+	Action      string      `json:"action"`
+	Number      int         `json:"number"`
+	PullRequest PullRequest `json:"pull_request"`
+	Repository  struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Installation struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+}
+
+// Changed reports whether the event changed the code of the pull request: it was
+// opened, reopened or pushed to.
+func (e PullRequestEvent) Changed() bool {
+	switch e.Action {
+	case "opened", "reopened", "synchronize":
+		return true
+	}
+	return false
+}
+
+// ParsePullRequestEvent parses the payload of a pull_request event.
+func ParsePullRequestEvent(body []byte) (PullRequestEvent, error) {
+	var event PullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return event, fmt.Errorf("invalid pull_request payload: %w", err)
+	}
+	if event.Repository.FullName == "" || event.Number == 0 || event.PullRequest.Head.SHA == "" {
+		return event, errors.New("invalid pull_request payload: repository, number or head missing")
+	}
+	return event, nil
+}
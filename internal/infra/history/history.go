@@ -0,0 +1,167 @@
+// Package history records scan summaries over time so that the reduction of emoji usage
+// can be tracked across commits.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// DefaultPath is the history file written by 'scan --record', relative to the working
+// directory.
+const DefaultPath = ".antimoji/history.jsonl"
+
+// maxLineSize bounds a single snapshot line when reading a history file.
+const maxLineSize = 1 << 20
+
+// Snapshot summarises one recorded scan.
+type Snapshot struct {
+	Time time.Time `json:"time"`
+	// Commit is the checked-out commit, or "" outside a git repository
+	Commit          string `json:"commit,omitempty"`
+	Files           int    `json:"files"`
+	FilesWithEmojis int    `json:"files_with_emojis"`
+	Emojis          int    `json:"emojis"`
+	// Categories counts emojis by detection category (unicode, emoticon, custom, ...)
+	Categories map[string]int `json:"categories"`
+}
+
+// NewSnapshot summarises scan results taken at the given time and commit. Files that
+// failed to process are left out.
+func NewSnapshot(results []types.ProcessResult, at time.Time, commit string) Snapshot {
+	snapshot := Snapshot{Time: at.UTC(), Commit: commit, Categories: map[string]int{}}
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		snapshot.Files++
+		if len(result.DetectionResult.Emojis) == 0 {
+			continue
+		}
+		snapshot.FilesWithEmojis++
+		for _, match := range result.DetectionResult.Emojis {
+			category := string(match.Category)
+			if category == "" {
+				category = "unknown"
+			}
+			snapshot.Emojis++
+			snapshot.Categories[category]++
+		}
+	}
+	return snapshot
+}
+
+// Append adds snapshot to the history file at path as one JSON line, creating the file
+// and its directory when missing.
+func Append(path string, snapshot Snapshot) error {
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) // #nosec G304 - history path is user-provided by design
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Read returns the snapshots in the history file at path, oldest first. Histories merged
+// from several branches may be out of order, so snapshots are sorted by time.
+func Read(path string) ([]Snapshot, error) {
+	file, err := os.Open(path) // #nosec G304 - history path is user-provided by design
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid snapshot: %w", path, line, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+// Point is a snapshot with its change from the previous one.
+type Point struct {
+	Snapshot
+	// Change is the difference in emojis from the previous snapshot
+	Change int `json:"change"`
+}
+
+// Trend describes how emoji usage changed across snapshots.
+type Trend struct {
+	Points []Point `json:"snapshots"`
+	// Change is the difference in emojis between the first and last snapshot
+	Change int `json:"change"`
+	// Categories is the difference per category between the first and last snapshot
+	Categories map[string]int `json:"categories"`
+}
+
+// Compute returns the trend of snapshots, which must be ordered oldest first.
+func Compute(snapshots []Snapshot) Trend {
+	trend := Trend{Points: make([]Point, 0, len(snapshots)), Categories: map[string]int{}}
+	for i, snapshot := range snapshots {
+		point := Point{Snapshot: snapshot}
+		if i > 0 {
+			point.Change = snapshot.Emojis - snapshots[i-1].Emojis
+		}
+		trend.Points = append(trend.Points, point)
+	}
+	if len(snapshots) == 0 {
+		return trend
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	trend.Change = last.Emojis - first.Emojis
+	for _, category := range CategoryNames(snapshots) {
+		trend.Categories[category] = last.Categories[category] - first.Categories[category]
+	}
+	return trend
+}
+
+// CategoryNames returns every category counted in snapshots, sorted.
+func CategoryNames(snapshots []Snapshot) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, snapshot := range snapshots {
+		for category := range snapshot.Categories {
+			if !seen[category] {
+				seen[category] = true
+				names = append(names, category)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,92 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	at := time.Date(2026, 10, 1, 9, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+	results := []types.ProcessResult{
+		{FilePath: "a.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{
+			{Emoji: "🎉", Category: types.CategoryUnicode},
+			{Emoji: ":)", Category: types.CategoryEmoticon},
+		}}},
+		{FilePath: "b.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{
+			{Emoji: "✅", Category: types.CategoryUnicode},
+		}}},
+		{FilePath: "clean.go"},
+		{FilePath: "broken.go", Error: os.ErrPermission},
+	}
+
+	snapshot := NewSnapshot(results, at, "abc123")
+
+	assert.Equal(t, at.UTC(), snapshot.Time)
+	assert.Equal(t, "abc123", snapshot.Commit)
+	assert.Equal(t, 3, snapshot.Files)
+	assert.Equal(t, 2, snapshot.FilesWithEmojis)
+	assert.Equal(t, 3, snapshot.Emojis)
+	assert.Equal(t, map[string]int{"unicode": 2, "emoticon": 1}, snapshot.Categories)
+}
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".antimoji", "history.jsonl")
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Read(path)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("appends one line per snapshot and reads them by time", func(t *testing.T) {
+		require.NoError(t, Append(path, Snapshot{Time: day(2), Commit: "b", Emojis: 5}))
+		require.NoError(t, Append(path, Snapshot{Time: day(1), Commit: "a", Emojis: 8}))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(content), "\n"))
+
+		snapshots, err := Read(path)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		assert.Equal(t, "a", snapshots[0].Commit)
+		assert.Equal(t, "b", snapshots[1].Commit)
+	})
+
+	t.Run("reports malformed lines", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "history.jsonl")
+		require.NoError(t, os.WriteFile(bad, []byte("{\"emojis\": 1}\n\nnot json\n"), 0644))
+
+		_, err := Read(bad)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "history.jsonl:3")
+	})
+}
+
+func TestCompute(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	snapshots := []Snapshot{
+		{Time: day(1), Emojis: 10, Categories: map[string]int{"unicode": 8, "emoticon": 2}},
+		{Time: day(2), Emojis: 12, Categories: map[string]int{"unicode": 10, "emoticon": 2}},
+		{Time: day(3), Emojis: 4, Categories: map[string]int{"unicode": 3, "custom": 1}},
+	}
+
+	trend := Compute(snapshots)
+
+	require.Len(t, trend.Points, 3)
+	assert.Equal(t, 0, trend.Points[0].Change)
+	assert.Equal(t, 2, trend.Points[1].Change)
+	assert.Equal(t, -8, trend.Points[2].Change)
+	assert.Equal(t, -6, trend.Change)
+	assert.Equal(t, map[string]int{"unicode": -5, "emoticon": -2, "custom": 1}, trend.Categories)
+	assert.Equal(t, []string{"custom", "emoticon", "unicode"}, CategoryNames(snapshots))
+
+	assert.Empty(t, Compute(nil).Points)
+}
@@ -0,0 +1,140 @@
+// Package httpclient builds the HTTP clients antimoji uses for every download, so that
+// they all honor the proxy environment and the certificate authorities a network needs.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// EnvCAFile names a PEM file of certificate authorities trusted in addition to the
+// system ones, for networks whose proxies re-sign TLS connections.
+const EnvCAFile = "ANTIMOJI_CA_FILE"
+
+// New returns a client with the given timeout (0 for none) that sends requests
+// through the proxies of the environment (see Proxy) and trusts the certificates in
+// caFile, when given, besides the system roots.
+func New(caFile string, timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = Proxy(os.Getenv)
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		roots, err := certPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.RootCAs = roots
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// CAFile returns the CA file of $ANTIMOJI_CA_FILE, or configured when it is not set.
+func CAFile(configured string) string {
+	if file := os.Getenv(EnvCAFile); file != "" {
+		return file
+	}
+	return configured
+}
+
+// certPool returns the system roots with the certificates of the PEM file added.
+func certPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile) // #nosec G304 - CA file is user-provided by design
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA file %s contains no PEM certificates", caFile)
+	}
+	return roots, nil
+}
+
+// Proxy returns the proxy function of a transport reading the proxy settings from
+// getenv on every request: HTTPS_PROXY for https URLs and HTTP_PROXY for http URLs,
+// unless the host matches NO_PROXY. The lower-case names are read when the upper-case
+// ones are unset. Loopback addresses are never proxied.
+func Proxy(getenv func(string) string) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		name := "HTTP_PROXY"
+		if req.URL.Scheme == "https" {
+			name = "HTTPS_PROXY"
+		}
+		proxy := lookup(getenv, name)
+		if proxy == "" || bypass(req.URL, lookup(getenv, "NO_PROXY")) {
+			return nil, nil
+		}
+
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Host == "" {
+			// A bare host:port is common in these variables
+			if proxyURL, err = url.Parse("http://" + proxy); err != nil {
+				return nil, fmt.Errorf("invalid proxy address %q: %w", proxy, err)
+			}
+		}
+		return proxyURL, nil
+	}
+}
+
+// lookup returns the variable name, or its lower-case form when name is unset.
+func lookup(getenv func(string) string, name string) string {
+	if value := getenv(name); value != "" {
+		return value
+	}
+	return getenv(strings.ToLower(name))
+}
+
+// bypass reports whether requests to u go around the proxy: loopback hosts and hosts
+// matching an entry of noProxy, a comma-separated list of host names (matching their
+// subdomains too, with or without a leading dot), IP addresses, CIDR ranges, any of
+// them with a :port, or * for every host.
+func bypass(u *url.URL, noProxy string) bool {
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if entryHost, entryPort, err := net.SplitHostPort(entry); err == nil {
+			if entryPort != port {
+				continue
+			}
+			entry = entryHost
+		}
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
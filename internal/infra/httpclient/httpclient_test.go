@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy(t *testing.T) {
+	env := map[string]string{
+		"HTTPS_PROXY": "proxy.corp:3128",
+		"http_proxy":  "http://plain-proxy.corp:8080",
+		"NO_PROXY":    "internal.corp, .svc:443, 10.0.0.0/8,ghe.corp:8443",
+	}
+	proxy := Proxy(func(name string) string { return env[name] })
+	proxyFor := func(rawURL string) string {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		require.NoError(t, err)
+		proxyURL, err := proxy(req)
+		require.NoError(t, err)
+		if proxyURL == nil {
+			return ""
+		}
+		return proxyURL.String()
+	}
+
+	assert.Equal(t, "http://proxy.corp:3128", proxyFor("https://api.github.com/repos"))
+	assert.Equal(t, "http://plain-proxy.corp:8080", proxyFor("http://example.com/"))
+	assert.Empty(t, proxyFor("https://internal.corp/config.yaml"))
+	assert.Empty(t, proxyFor("https://git.internal.corp/config.yaml"), "subdomains match")
+	assert.Empty(t, proxyFor("https://config.svc/"), "default port matches :443")
+	assert.Equal(t, "http://proxy.corp:3128", proxyFor("https://config.svc:8443/"))
+	assert.Empty(t, proxyFor("https://10.1.2.3/"))
+	assert.Empty(t, proxyFor("https://ghe.corp:8443/api/v3"))
+	assert.Equal(t, "http://proxy.corp:3128", proxyFor("https://ghe.corp/api/v3"))
+	assert.Empty(t, proxyFor("https://127.0.0.1:9000/"), "loopback is never proxied")
+
+	env["NO_PROXY"] = "*"
+	assert.Empty(t, proxyFor("https://api.github.com/repos"))
+}
+
+func TestNew_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := New("", 0)
+	require.NoError(t, err)
+	_, err = client.Get(server.URL)
+	assert.Error(t, err, "the test certificate is not trusted by default")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := server.TLS.Certificates[0].Certificate[0]
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600))
+	client, err = New(caFile, 0)
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0600))
+	_, err = New(caFile, 0)
+	assert.ErrorContains(t, err, "contains no PEM certificates")
+	_, err = New(filepath.Join(t.TempDir(), "missing.pem"), 0)
+	assert.ErrorContains(t, err, "failed to read CA file")
+}
+
+func TestCAFile(t *testing.T) {
+	t.Setenv(EnvCAFile, "")
+	assert.Equal(t, "config.pem", CAFile("config.pem"))
+	t.Setenv(EnvCAFile, "env.pem")
+	assert.Equal(t, "env.pem", CAFile("config.pem"))
+}
@@ -0,0 +1,189 @@
+// Package logtail reads the lines of logs as they are written, following files across
+// truncation and rotation.
+package logtail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultPollInterval is how often a followed file is checked for new content when
+// Options sets no interval.
+const DefaultPollInterval = 250 * time.Millisecond
+
+// Line is a complete line of a log, without its line ending.
+type Line struct {
+	// Number is the number of the line in its file or stream, counting from 1
+	Number int
+	Text   string
+}
+
+// Options controls how a file is read.
+type Options struct {
+	// Follow keeps reading as the file grows until the context ends, reopening it when
+	// it is truncated or replaced by rotation
+	Follow bool
+	// FromStart reads the lines already in a followed file too; otherwise only lines
+	// completed after following starts are read
+	FromStart bool
+	// PollInterval is how often a followed file is checked for new content
+	PollInterval time.Duration
+}
+
+// Read calls fn for each line of r until r ends, fn fails or ctx ends. A last line
+// without a line ending is included when r ends. Reads from r are not interrupted, so
+// ctx ending is only noticed between lines.
+func Read(ctx context.Context, r io.Reader, fn func(Line) error) error {
+	t := &tailer{reader: bufio.NewReader(r), fn: fn}
+	if err := t.drain(ctx, true); err != nil || ctx.Err() != nil {
+		return err
+	}
+	return t.flush()
+}
+
+// File calls fn for each line of the file at path until fn fails or, when following,
+// ctx ends. Lines already in the file are counted but skipped when following without
+// FromStart. A last line without a line ending is only included when not following,
+// or when the file is rotated.
+func File(ctx context.Context, path string, opts Options, fn func(Line) error) error {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	file, err := os.Open(path) // #nosec G304 - log file given on the command line
+	if err != nil {
+		return err
+	}
+	t := &tailer{file: file, reader: bufio.NewReader(file), fn: fn}
+	defer func() { _ = t.file.Close() }()
+
+	if !opts.Follow {
+		if err := t.drain(ctx, true); err != nil {
+			return err
+		}
+		return t.flush()
+	}
+	if err := t.drain(ctx, opts.FromStart); err != nil {
+		return err
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+		if err := t.follow(ctx, path); err != nil {
+			return err
+		}
+		if err := t.drain(ctx, true); err != nil {
+			return err
+		}
+		timer.Reset(interval)
+	}
+}
+
+// tailer reads the lines of one file or stream at a time.
+type tailer struct {
+	file   *os.File
+	reader *bufio.Reader
+	fn     func(Line) error
+	// number is that of the last line read
+	number int
+	// offset is the number of bytes read from the file
+	offset int64
+	// partial is the start of a line whose line ending is not written yet
+	partial string
+}
+
+// drain reads the complete lines available, passing them to fn when emit is set, until
+// ctx ends.
+func (t *tailer) drain(ctx context.Context, emit bool) error {
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		chunk, err := t.reader.ReadString('\n')
+		t.offset += int64(len(chunk))
+		t.partial += chunk
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		text := strings.TrimSuffix(strings.TrimSuffix(t.partial, "\n"), "\r")
+		t.partial = ""
+		t.number++
+		if emit {
+			if err := t.fn(Line{Number: t.number, Text: text}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// flush passes a last line without a line ending to fn.
+func (t *tailer) flush() error {
+	if t.partial == "" {
+		return nil
+	}
+	text := strings.TrimSuffix(t.partial, "\r")
+	t.partial = ""
+	t.number++
+	return t.fn(Line{Number: t.number, Text: text})
+}
+
+// follow switches to the file now at path when the followed one was rotated away,
+// after reading what was still written to it, and reads a truncated file from its
+// start again. A missing file is waited for.
+func (t *tailer) follow(ctx context.Context, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	current, err := t.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !os.SameFile(info, current) {
+		if err := t.drain(ctx, true); err != nil {
+			return err
+		}
+		if err := t.flush(); err != nil {
+			return err
+		}
+		file, err := os.Open(path) // #nosec G304 - log file given on the command line
+		if err != nil {
+			// The new file may not be readable yet
+			return nil
+		}
+		_ = t.file.Close()
+		t.reset(file)
+		return nil
+	}
+	if info.Size() < t.offset {
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		t.reset(t.file)
+	}
+	return nil
+}
+
+// reset starts reading file from its start.
+func (t *tailer) reset(file *os.File) {
+	t.file = file
+	t.reader.Reset(file)
+	t.number = 0
+	t.offset = 0
+	t.partial = ""
+}
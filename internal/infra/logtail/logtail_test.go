@@ -0,0 +1,145 @@
+package logtail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	var lines []Line
+	err := Read(context.Background(), strings.NewReader("one\r\ntwo\n\nlast"), func(line Line) error {
+		lines = append(lines, line)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Line{{1, "one"}, {2, "two"}, {3, ""}, {4, "last"}}, lines)
+
+	stop := errors.New("stop")
+	err = Read(context.Background(), strings.NewReader("a\nb\n"), func(Line) error { return stop })
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo"), 0644))
+
+	var lines []Line
+	err := File(context.Background(), path, Options{}, func(line Line) error {
+		lines = append(lines, line)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Line{{1, "one"}, {2, "two"}}, lines)
+
+	assert.Error(t, File(context.Background(), filepath.Join(t.TempDir(), "missing.log"), Options{}, nil))
+}
+
+// follow follows the file at path in the background and returns its lines as they
+// are read.
+func follow(t *testing.T, path string, opts Options) <-chan Line {
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan Line, 16)
+	done := make(chan error, 1)
+	opts.Follow = true
+	opts.PollInterval = 5 * time.Millisecond
+	go func() {
+		done <- File(ctx, path, opts, func(line Line) error {
+			lines <- line
+			return nil
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	return lines
+}
+
+// next returns the next line read, failing the test after a second.
+func next(t *testing.T, lines <-chan Line) Line {
+	t.Helper()
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(time.Second):
+		t.Fatal("no line read")
+		return Line{}
+	}
+}
+
+// appendTo appends content to the file at path.
+func appendTo(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+func TestFile_Follow(t *testing.T) {
+	t.Run("new lines only", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("old\nparti"), 0644))
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer func() { _ = file.Close() }()
+
+		var lines []Line
+		tail := &tailer{file: file, reader: bufio.NewReader(file), fn: func(line Line) error {
+			lines = append(lines, line)
+			return nil
+		}}
+		require.NoError(t, tail.drain(context.Background(), false))
+		appendTo(t, path, "al\nnew\n")
+		require.NoError(t, tail.follow(context.Background(), path))
+		require.NoError(t, tail.drain(context.Background(), true))
+		assert.Equal(t, []Line{{2, "partial"}, {3, "new"}}, lines)
+	})
+
+	t.Run("from start", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+		lines := follow(t, path, Options{FromStart: true})
+
+		assert.Equal(t, Line{1, "old"}, next(t, lines))
+		appendTo(t, path, "new\n")
+		assert.Equal(t, Line{2, "new"}, next(t, lines))
+	})
+
+	t.Run("truncation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("first line\n"), 0644))
+		lines := follow(t, path, Options{FromStart: true})
+		assert.Equal(t, Line{1, "first line"}, next(t, lines))
+
+		// The shorter content tells the truncation apart from growth
+		require.NoError(t, os.Truncate(path, 0))
+		appendTo(t, path, "again\n")
+		assert.Equal(t, Line{1, "again"}, next(t, lines))
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("before\n"), 0644))
+		lines := follow(t, path, Options{FromStart: true})
+
+		assert.Equal(t, Line{1, "before"}, next(t, lines))
+		require.NoError(t, os.Rename(path, filepath.Join(dir, "app.log.1")))
+		appendTo(t, filepath.Join(dir, "app.log.1"), "late")
+		require.NoError(t, os.WriteFile(path, []byte("after\n"), 0644))
+
+		assert.Equal(t, Line{2, "late"}, next(t, lines))
+		assert.Equal(t, Line{1, "after"}, next(t, lines))
+	})
+}
@@ -0,0 +1,127 @@
+// Package notify sends the summaries of runs to Slack incoming webhooks and to
+// webhooks accepting JSON, so that scheduled scans report their outcome without glue
+// scripts.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sink types.
+const (
+	TypeSlack   = "slack"
+	TypeWebhook = "webhook"
+)
+
+// sendTimeout bounds the delivery of a notification.
+const sendTimeout = 10 * time.Second
+
+// Summary is the summary of a run sent to the sinks.
+type Summary struct {
+	Operation string `json:"operation"`
+	Profile   string `json:"profile"`
+	// Target names what was scanned, such as the paths given
+	Target       string `json:"target,omitempty"`
+	Passed       bool   `json:"passed"`
+	FilesScanned int    `json:"files_scanned"`
+	FilesFailed  int    `json:"files_failed"`
+	// Violations counts the findings of severity error
+	Violations int `json:"violations"`
+	Warnings   int `json:"warnings"`
+	// TopFiles and TopEmojis list the top offenders, most findings first
+	TopFiles   []Offender `json:"top_files,omitempty"`
+	TopEmojis  []Offender `json:"top_emojis,omitempty"`
+	ReportURL  string     `json:"report_url,omitempty"`
+	DurationMS int64      `json:"duration_ms"`
+}
+
+// Offender is a file or emoji with its number of findings.
+type Offender struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Sink is a destination of summaries: a Slack incoming webhook, or a URL the summary
+// is posted to as JSON.
+type Sink struct {
+	Type string
+	URL  string
+}
+
+// Send posts summary to sink with client.
+func Send(ctx context.Context, client *http.Client, sink Sink, summary Summary) error {
+	var payload any = summary
+	switch sink.Type {
+	case TypeSlack:
+		payload = map[string]string{"text": SlackText(summary)}
+	case TypeWebhook:
+	default:
+		return fmt.Errorf("unknown notification type %q", sink.Type)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid %s webhook: %w", sink.Type, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to notify %s webhook: %w", sink.Type, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to notify %s webhook: unexpected status %s", sink.Type, resp.Status)
+	}
+	return nil
+}
+
+// SlackText formats summary as a Slack message in its mrkdwn markup.
+func SlackText(summary Summary) string {
+	outcome := "passed"
+	if !summary.Passed {
+		outcome = "failed"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*antimoji %s %s*", summary.Operation, outcome)
+	if summary.Target != "" {
+		fmt.Fprintf(&b, " for `%s`", summary.Target)
+	}
+	fmt.Fprintf(&b, " (profile `%s`)\n", summary.Profile)
+	fmt.Fprintf(&b, "%d violations and %d warnings in %d files", summary.Violations, summary.Warnings, summary.FilesScanned)
+	if summary.FilesFailed > 0 {
+		fmt.Fprintf(&b, ", %d files could not be read", summary.FilesFailed)
+	}
+	if len(summary.TopFiles) > 0 {
+		b.WriteString("\nTop files: " + offenders(summary.TopFiles, "`"))
+	}
+	if len(summary.TopEmojis) > 0 {
+		b.WriteString("\nTop emojis: " + offenders(summary.TopEmojis, ""))
+	}
+	if summary.ReportURL != "" {
+		fmt.Fprintf(&b, "\n<%s|Full report>", summary.ReportURL)
+	}
+	return b.String()
+}
+
+// offenders lists offenders as "name (count)", quoting each name with quote.
+func offenders(list []Offender, quote string) string {
+	items := make([]string, 0, len(list))
+	for _, offender := range list {
+		items = append(items, fmt.Sprintf("%s%s%s (%d)", quote, offender.Name, quote, offender.Count))
+	}
+	return strings.Join(items, ", ")
+}
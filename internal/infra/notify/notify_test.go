@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSummary() Summary {
+	return Summary{
+		Operation:    "scan",
+		Profile:      "ci",
+		Target:       "src",
+		FilesScanned: 12,
+		FilesFailed:  1,
+		Violations:   3,
+		Warnings:     1,
+		TopFiles:     []Offender{{Name: "src/a.go", Count: 2}, {Name: "src/b.md", Count: 1}},
+		TopEmojis:    []Offender{{Name: "🚀", Count: 3}},
+		ReportURL:    "https://ci.example.com/runs/1",
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	t.Run("slack", func(t *testing.T) {
+		require.NoError(t, Send(ctx, server.Client(), Sink{Type: TypeSlack, URL: server.URL}, testSummary()))
+		assert.Equal(t, SlackText(testSummary()), received["text"])
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		require.NoError(t, Send(ctx, server.Client(), Sink{Type: TypeWebhook, URL: server.URL}, testSummary()))
+		assert.Equal(t, "ci", received["profile"])
+		assert.Equal(t, false, received["passed"])
+		assert.Equal(t, float64(3), received["violations"])
+		assert.Len(t, received["top_files"], 2)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		err := Send(ctx, server.Client(), Sink{Type: TypeWebhook, URL: server.URL + "/broken"}, testSummary())
+		assert.ErrorContains(t, err, "unexpected status 500")
+		assert.Error(t, Send(ctx, server.Client(), Sink{Type: "email", URL: server.URL}, testSummary()))
+		assert.Error(t, Send(ctx, server.Client(), Sink{Type: TypeSlack, URL: "://"}, testSummary()))
+	})
+}
+
+func TestSlackText(t *testing.T) {
+	assert.Equal(t, "*antimoji scan failed* for `src` (profile `ci`)\n"+
+		"3 violations and 1 warnings in 12 files, 1 files could not be read\n"+
+		"Top files: `src/a.go` (2), `src/b.md` (1)\n"+
+		"Top emojis: 🚀 (3)\n"+
+		"<https://ci.example.com/runs/1|Full report>", SlackText(testSummary()))
+
+	assert.Equal(t, "*antimoji scan passed* (profile `default`)\n0 violations and 0 warnings in 4 files",
+		SlackText(Summary{Operation: "scan", Profile: "default", Passed: true, FilesScanned: 4}))
+}
@@ -0,0 +1,93 @@
+// Package pathmatch matches file paths against glob patterns the same way on every
+// platform.
+//
+// Patterns and paths use forward slashes. Paths are normalized with Normalize, so a
+// path such as vendor\lib\a.go on Windows matches vendor/**. Patterns support the
+// syntax of path.Match, plus "**" as a whole path element, which matches zero or more
+// elements: "vendor/**" matches everything below vendor, "**/*.pb.go" matches
+// generated files at any depth and "docs/**/*.md" matches Markdown files in docs and
+// its subdirectories.
+package pathmatch
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// doubleStar is the pattern element matching any number of path elements.
+const doubleStar = "**"
+
+// ErrBadPattern indicates a pattern is malformed.
+var ErrBadPattern = errors.New("invalid glob pattern")
+
+// Normalize returns path with forward slashes, without "." elements or repeated
+// separators. Backslashes are separators only on Windows, where filepath uses them.
+func Normalize(p string) string {
+	if p == "" {
+		return ""
+	}
+	return path.Clean(filepath.ToSlash(p))
+}
+
+// NormalizePattern returns pattern with forward slashes and without a leading "./".
+// On Windows backslashes are separators; elsewhere they escape the following
+// character, as in path.Match.
+func NormalizePattern(pattern string) string {
+	pattern = filepath.ToSlash(pattern)
+	for strings.HasPrefix(pattern, "./") {
+		pattern = strings.TrimLeft(pattern[2:], "/")
+	}
+	return pattern
+}
+
+// Validate reports whether pattern is well-formed.
+func Validate(pattern string) error {
+	for _, element := range strings.Split(NormalizePattern(pattern), "/") {
+		if _, err := path.Match(element, ""); err != nil {
+			return fmt.Errorf("%w: %q", ErrBadPattern, pattern)
+		}
+	}
+	return nil
+}
+
+// Match reports whether the path name matches pattern. Malformed patterns match
+// nothing; use Validate to reject them.
+func Match(pattern, name string) bool {
+	patternElements := strings.Split(NormalizePattern(pattern), "/")
+	nameElements := strings.Split(Normalize(name), "/")
+	return matchElements(patternElements, nameElements)
+}
+
+// matchElements matches the path elements of a name against those of a pattern.
+func matchElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == doubleStar {
+			// Collapse consecutive ** elements, then try every split of the name
+			for len(pattern) > 0 && pattern[0] == doubleStar {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchElements(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		matched, err := path.Match(pattern[0], name[0])
+		if err != nil || !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,63 @@
+package pathmatch
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"vendor/*", "vendor/lib.go", true},
+		{"vendor/*", "vendor/pkg/lib.go", false},
+		{"vendor/**", "vendor/pkg/lib.go", true},
+		{"vendor/**", "vendor", true},
+		{"vendor/**/*", "vendor/lib.go", true},
+		{"vendor/**/*", "vendor/a/b/c/lib.go", true},
+		{"vendor/**/*", "src/vendor/lib.go", false},
+		{"**/*.pb.go", "api.pb.go", true},
+		{"**/*.pb.go", "api/v1/api.pb.go", true},
+		{"**/*.generated.*", "ui/form.generated.ts", true},
+		{"docs/**/*.md", "docs/guide.md", true},
+		{"docs/**/*.md", "docs/a/b/guide.md", true},
+		{"docs/**/*.md", "docs/a/b/guide.txt", false},
+		{"a/**/**/b", "a/b", true},
+		{"**", "anything/at/all", true},
+		{"./vendor/*", "vendor/lib.go", true},
+		{"vendor/*", "./vendor//lib.go", true},
+		{"[", "[", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Match(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestMatch_PlatformSeparators(t *testing.T) {
+	name := filepath.Join("vendor", "pkg", "lib.go")
+	assert.True(t, Match("vendor/**/*.go", name))
+	assert.Equal(t, "vendor/pkg/lib.go", Normalize(name))
+
+	if runtime.GOOS == "windows" {
+		assert.True(t, Match(`vendor\**\*.go`, "vendor/pkg/lib.go"))
+	} else {
+		// Elsewhere a backslash escapes the next character
+		assert.True(t, Match(`\*.go`, "*.go"))
+		assert.False(t, Match(`\*.go`, "main.go"))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("vendor/**/*.go"))
+	assert.NoError(t, Validate("[a-z]*.md"))
+	assert.ErrorIs(t, Validate("src/[a-"), ErrBadPattern)
+}
@@ -0,0 +1,199 @@
+// Package quarantine moves files with emoji violations out of the scanned tree into a
+// quarantine directory, keeping their paths relative to the working directory, and
+// records them in a manifest there.
+package quarantine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ManifestFile is the name of the manifest in the quarantine directory.
+const ManifestFile = "manifest.json"
+
+// ErrAlreadyQuarantined indicates the quarantine directory already holds a file at the
+// path a file would be moved to.
+var ErrAlreadyQuarantined = errors.New("already quarantined")
+
+// Manifest lists the files moved into a quarantine directory.
+type Manifest struct {
+	Files []File `json:"files"`
+}
+
+// File is a file moved into quarantine.
+type File struct {
+	// Path is where the file was, as it was given
+	Path string `json:"path"`
+	// Quarantined is where the file is now, relative to the quarantine directory
+	Quarantined string `json:"quarantined"`
+	// Emojis is the number of emojis the policy does not allow in the file
+	Emojis int `json:"emojis"`
+	// SHA256 is the hash of the unchanged content
+	SHA256 string    `json:"sha256"`
+	Time   time.Time `json:"time"`
+}
+
+// Target returns the path, relative to the quarantine directory, that the file at path
+// moves to: its path relative to the working directory, or for files outside it, its
+// absolute path without the volume and leading separator.
+func Target(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		rel = strings.TrimLeft(strings.TrimPrefix(abs, filepath.VolumeName(abs)), string(filepath.Separator))
+	}
+	return rel, nil
+}
+
+// Contains reports whether path is inside the quarantine directory dir, whose files
+// must not be quarantined again.
+func Contains(dir, path string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Move moves the file at path into dir without changing its content or mode, and
+// returns its manifest entry. A file already at the target fails with
+// ErrAlreadyQuarantined.
+func Move(dir, path string, emojis int, t time.Time) (File, error) {
+	target, err := Target(path)
+	if err != nil {
+		return File{}, err
+	}
+	destination := filepath.Join(dir, target)
+	if _, err := os.Lstat(destination); err == nil {
+		return File{}, fmt.Errorf("%w: %s", ErrAlreadyQuarantined, destination)
+	}
+
+	content, err := os.ReadFile(path) // #nosec G304 - files selected by clean
+	if err != nil {
+		return File{}, err
+	}
+	sum := sha256.Sum256(content)
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0750); err != nil {
+		return File{}, err
+	}
+	if err := os.Rename(path, destination); err != nil {
+		// Renaming fails across file systems, where the file is copied instead
+		if err := copyFile(path, destination); err != nil {
+			return File{}, err
+		}
+		if err := os.Remove(path); err != nil {
+			_ = os.Remove(destination)
+			return File{}, err
+		}
+	}
+
+	return File{
+		Path:        path,
+		Quarantined: filepath.ToSlash(target),
+		Emojis:      emojis,
+		SHA256:      hex.EncodeToString(sum[:]),
+		Time:        t.UTC(),
+	}, nil
+}
+
+// copyFile copies the file at from to the new file to with the same mode.
+func copyFile(from, to string) error {
+	info, err := os.Stat(from)
+	if err != nil {
+		return err
+	}
+	source, err := os.Open(from) // #nosec G304 - files selected by clean
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	destination, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm()) // #nosec G304 - path in the quarantine directory
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		_ = destination.Close()
+		_ = os.Remove(to)
+		return err
+	}
+	if err := destination.Close(); err != nil {
+		_ = os.Remove(to)
+		return err
+	}
+	return nil
+}
+
+// Load reads the manifest of the quarantine directory dir. A directory without one
+// has an empty manifest.
+func Load(dir string) (Manifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ManifestFile)) // #nosec G304 - quarantine directory given on the command line
+	if errors.Is(err, fs.ErrNotExist) {
+		return Manifest{}, nil
+	}
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("invalid quarantine manifest %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+	return manifest, nil
+}
+
+// Record adds files to the manifest of the quarantine directory dir.
+func Record(dir string, files []File) error {
+	manifest, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, files...)
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".manifest-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(content, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, ManifestFile)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package quarantine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chdir makes dir the working directory for the rest of the test.
+func chdir(t *testing.T, dir string) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+}
+
+func TestTarget(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	target, err := Target(filepath.Join("src", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("src", "main.go"), target)
+
+	target, err = Target(filepath.Join(dir, "src", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("src", "main.go"), target)
+
+	outside := filepath.Join(filepath.Dir(dir), "other", "main.go")
+	target, err = Target(outside)
+	require.NoError(t, err)
+	assert.False(t, filepath.IsAbs(target))
+	assert.Equal(t, filepath.Join("other", "main.go"), target[len(target)-len(filepath.Join("other", "main.go")):])
+}
+
+func TestContains(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	assert.True(t, Contains("quarantine", filepath.Join("quarantine", "src", "main.go")))
+	assert.True(t, Contains("./quarantine", filepath.Join(dir, "quarantine", "main.go")))
+	assert.False(t, Contains("quarantine", filepath.Join("src", "main.go")))
+	assert.False(t, Contains("quarantine", "quarantine-notes.md"))
+}
+
+func TestMoveAndRecord(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	require.NoError(t, os.MkdirAll("src", 0755))
+	path := filepath.Join("src", "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("// Launch 🚀\n"), 0640))
+	when := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	file, err := Move("quarantine", path, 1, when)
+	require.NoError(t, err)
+	assert.Equal(t, path, file.Path)
+	assert.Equal(t, "src/main.go", file.Quarantined)
+	assert.Equal(t, 1, file.Emojis)
+	assert.Len(t, file.SHA256, 64)
+	assert.Equal(t, when, file.Time)
+
+	assert.NoFileExists(t, path)
+	moved := filepath.Join("quarantine", "src", "main.go")
+	content, err := os.ReadFile(moved)
+	require.NoError(t, err)
+	assert.Equal(t, "// Launch 🚀\n", string(content))
+	info, err := os.Stat(moved)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	require.NoError(t, Record("quarantine", []File{file}))
+
+	// A file at the same path cannot be quarantined again, and records add up
+	require.NoError(t, os.WriteFile(path, []byte("// Again 🎉\n"), 0644))
+	_, err = Move("quarantine", path, 1, when)
+	assert.ErrorIs(t, err, ErrAlreadyQuarantined)
+	assert.FileExists(t, path)
+
+	other := filepath.Join("src", "other.go")
+	require.NoError(t, os.WriteFile(other, []byte("// Done ✅\n"), 0644))
+	second, err := Move("quarantine", other, 1, when)
+	require.NoError(t, err)
+	require.NoError(t, Record("quarantine", []File{second}))
+
+	manifest, err := Load("quarantine")
+	require.NoError(t, err)
+	assert.Equal(t, []File{file, second}, manifest.Files)
+}
+
+func TestLoad_Missing(t *testing.T) {
+	manifest, err := Load(filepath.Join(t.TempDir(), "quarantine"))
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Files)
+}
@@ -0,0 +1,219 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Channel selects which releases an upgrade may install.
+type Channel string
+
+const (
+	// ChannelStable installs the newest release that is not a prerelease.
+	ChannelStable Channel = "stable"
+	// ChannelPrerelease installs the newest release, release candidates included.
+	ChannelPrerelease Channel = "prerelease"
+	// ChannelPinned installs the newest stable release a required constraint allows.
+	ChannelPinned Channel = "pinned"
+)
+
+// ParseChannel returns the channel named name; "" is the stable channel.
+func ParseChannel(name string) (Channel, error) {
+	switch channel := Channel(strings.ToLower(strings.TrimSpace(name))); channel {
+	case "":
+		return ChannelStable, nil
+	case ChannelStable, ChannelPrerelease, ChannelPinned:
+		return channel, nil
+	}
+	return "", fmt.Errorf("invalid upgrade channel %q: must be stable, prerelease or pinned", name)
+}
+
+// Constraint restricts the versions an upgrade may install, such as ">=1.2.0, <2.0.0".
+// The zero Constraint allows every version.
+type Constraint struct {
+	text  string
+	terms []term
+}
+
+// term compares a version with bound.
+type term struct {
+	op    string
+	bound string
+}
+
+// ParseConstraint parses a version constraint: terms separated by commas or spaces,
+// which must all hold. A term is a version compared with =, !=, >, >=, < or <=;
+// ~1.2.3 allows patch releases of 1.2 from 1.2.3, ^1.2.3 allows releases up to the
+// next major version (minor version for 0.x), and a partial version such as 1.2 or
+// 1.2.x allows the releases it names. An empty constraint allows every version.
+func ParseConstraint(text string) (Constraint, error) {
+	constraint := Constraint{text: strings.TrimSpace(text)}
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' })
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		// Allow a space between the operator and the version, as in ">= 1.2"
+		if strings.Trim(field, "=!<>~^") == "" && i+1 < len(fields) {
+			i++
+			field += fields[i]
+		}
+		terms, err := parseTerm(field)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid version constraint %q: %w", text, err)
+		}
+		constraint.terms = append(constraint.terms, terms...)
+	}
+	return constraint, nil
+}
+
+// parseTerm parses one constraint term into the comparisons it stands for.
+func parseTerm(field string) ([]term, error) {
+	op := field[:len(field)-len(strings.TrimLeft(field, "=!<>~^"))]
+	parts, err := versionParts(strings.TrimPrefix(field[len(op):], "v"))
+	if err != nil {
+		return nil, err
+	}
+	lower := fullVersion(parts)
+
+	switch op {
+	case "=", "":
+		if len(parts) == 3 {
+			return []term{{"=", strings.TrimPrefix(field[len(op):], "v")}}, nil
+		}
+		return []term{{">=", lower}, {"<", nextVersion(parts, len(parts)-1)}}, nil
+	case "!=", ">", ">=", "<", "<=":
+		return []term{{op, lower}}, nil
+	case "~":
+		if len(parts) == 1 {
+			return []term{{">=", lower}, {"<", nextVersion(parts, 0)}}, nil
+		}
+		return []term{{">=", lower}, {"<", nextVersion(parts, 1)}}, nil
+	case "^":
+		// The first non-zero part may not change
+		index := 0
+		for index < len(parts)-1 && parts[index] == "0" {
+			index++
+		}
+		return []term{{">=", lower}, {"<", nextVersion(parts, index)}}, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+// versionParts splits a full or partial version into its parts, dropping trailing x
+// and * wildcards. A full version keeps its prerelease in the last part.
+func versionParts(version string) ([]string, error) {
+	parts := strings.Split(version, ".")
+	for len(parts) > 1 && (parts[len(parts)-1] == "x" || parts[len(parts)-1] == "*") {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 3 && strings.Contains(parts[2], "-") {
+		// A prerelease such as 1.3.0-rc.1 contains dots of its own
+		parts = append(parts[:2], strings.Join(parts[2:], "."))
+	}
+	if len(parts) > 3 || version == "" {
+		return nil, fmt.Errorf("%q is not a version", version)
+	}
+	for i, part := range parts {
+		numeric := part
+		if i == 2 {
+			numeric, _, _ = strings.Cut(part, "-")
+		}
+		if numeric == "" || strings.Trim(numeric, "0123456789") != "" {
+			return nil, fmt.Errorf("%q is not a version", version)
+		}
+	}
+	return parts, nil
+}
+
+// fullVersion returns the version parts describe, completed with zeros.
+func fullVersion(parts []string) string {
+	full := []string{"0", "0", "0"}
+	copy(full, parts)
+	return strings.Join(full, ".")
+}
+
+// nextVersion returns the lowest prerelease of the version after parts when part
+// index is incremented, the upper bound that excludes the prereleases of that version.
+func nextVersion(parts []string, index int) string {
+	next := []string{"0", "0", "0"}
+	copy(next, parts[:index])
+	var n int
+	numeric, _, _ := strings.Cut(parts[index], "-")
+	_, _ = fmt.Sscan(numeric, &n)
+	next[index] = fmt.Sprint(n + 1)
+	return strings.Join(next, ".") + "-0"
+}
+
+// String returns the constraint as it was written.
+func (c Constraint) String() string {
+	return c.text
+}
+
+// IsZero reports whether c allows every version.
+func (c Constraint) IsZero() bool {
+	return len(c.terms) == 0
+}
+
+// Allows reports whether version satisfies every term of c.
+func (c Constraint) Allows(version string) bool {
+	for _, t := range c.terms {
+		cmp := CompareVersions(version, t.bound)
+		var ok bool
+		switch t.op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns the newest of releases the channel and constraint allow. Prereleases
+// are only selected on the prerelease channel, and the pinned channel requires a
+// constraint.
+func Select(releases []Release, channel Channel, constraint Constraint) (Release, error) {
+	if channel == ChannelPinned && constraint.IsZero() {
+		return Release{}, fmt.Errorf("the pinned channel needs a version constraint such as ~1.2")
+	}
+	var newest Release
+	found := false
+	for _, rel := range releases {
+		if rel.Prerelease && channel != ChannelPrerelease {
+			continue
+		}
+		if !constraint.Allows(rel.Tag) {
+			continue
+		}
+		if !found || CompareVersions(rel.Tag, newest.Tag) > 0 {
+			newest, found = rel, true
+		}
+	}
+	if !found {
+		if constraint.IsZero() {
+			return Release{}, fmt.Errorf("%w on the %s channel", ErrNotFound, channel)
+		}
+		return Release{}, fmt.Errorf("%w on the %s channel matching %s", ErrNotFound, channel, constraint)
+	}
+	return newest, nil
+}
+
+// Find returns the newest release the channel and constraint allow.
+func (c *Client) Find(ctx context.Context, channel Channel, constraint Constraint) (Release, error) {
+	releases, err := c.List(ctx)
+	if err != nil {
+		return Release{}, err
+	}
+	return Select(releases, channel, constraint)
+}
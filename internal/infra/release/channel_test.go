@@ -0,0 +1,98 @@
+package release
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		allowed    []string
+		denied     []string
+	}{
+		{"", []string{"v0.0.1", "v9.9.9-rc.1"}, nil},
+		{"v1.2.3", []string{"1.2.3"}, []string{"v1.2.4", "v1.2.3-rc.1"}},
+		{">=1.2.0, <2.0.0", []string{"v1.2.0", "v1.9.9"}, []string{"v1.1.9", "v2.0.0"}},
+		{">= 1.2 <2", []string{"v1.2.0"}, []string{"v2.0.0"}},
+		{"~1.2.3", []string{"v1.2.3", "v1.2.9"}, []string{"v1.2.2", "v1.3.0", "v1.3.0-rc.1"}},
+		{"^1.2.3", []string{"v1.2.3", "v1.9.0"}, []string{"v2.0.0", "v2.0.0-rc.1"}},
+		{"^0.9.1", []string{"v0.9.16"}, []string{"v0.10.0"}},
+		{"1.2.x", []string{"v1.2.0", "v1.2.7"}, []string{"v1.3.0"}},
+		{"1", []string{"v1.0.0", "v1.9.0"}, []string{"v2.0.0"}},
+		{">=1.3.0-rc.1", []string{"v1.3.0-rc.2", "v1.3.0"}, []string{"v1.3.0-rc.0"}},
+		{"!=1.2.5", []string{"v1.2.4"}, []string{"v1.2.5"}},
+	}
+	for _, tt := range tests {
+		constraint, err := ParseConstraint(tt.constraint)
+		require.NoError(t, err, tt.constraint)
+		for _, version := range tt.allowed {
+			assert.True(t, constraint.Allows(version), "%q allows %s", tt.constraint, version)
+		}
+		for _, version := range tt.denied {
+			assert.False(t, constraint.Allows(version), "%q denies %s", tt.constraint, version)
+		}
+	}
+
+	for _, invalid := range []string{"latest", ">=1.2.3.4", "=>1.2", "1.x.3"} {
+		_, err := ParseConstraint(invalid)
+		assert.ErrorContains(t, err, "invalid version constraint", invalid)
+	}
+}
+
+func TestParseChannel(t *testing.T) {
+	channel, err := ParseChannel("")
+	require.NoError(t, err)
+	assert.Equal(t, ChannelStable, channel)
+	channel, err = ParseChannel("Prerelease")
+	require.NoError(t, err)
+	assert.Equal(t, ChannelPrerelease, channel)
+	_, err = ParseChannel("nightly")
+	assert.ErrorContains(t, err, "invalid upgrade channel")
+}
+
+func TestSelect(t *testing.T) {
+	releases := []Release{
+		{Tag: "v1.3.0-rc.1", Prerelease: true},
+		{Tag: "v1.2.1"},
+		{Tag: "v2.0.0"},
+		{Tag: "v1.2.0"},
+	}
+	selected := func(channel Channel, constraint string) string {
+		parsed, err := ParseConstraint(constraint)
+		require.NoError(t, err)
+		rel, err := Select(releases, channel, parsed)
+		if err != nil {
+			return err.Error()
+		}
+		return rel.Tag
+	}
+
+	assert.Equal(t, "v2.0.0", selected(ChannelStable, ""))
+	assert.Equal(t, "v1.2.1", selected(ChannelStable, "<2"))
+	assert.Equal(t, "v1.3.0-rc.1", selected(ChannelPrerelease, "^1"))
+	assert.Equal(t, "v1.2.1", selected(ChannelPinned, "~1.2"))
+	assert.Equal(t, "the pinned channel needs a version constraint such as ~1.2", selected(ChannelPinned, ""))
+	assert.Equal(t, "release not found on the stable channel matching ^3", selected(ChannelStable, "^3"))
+}
+
+func TestClient_Find(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/"+Repository+"/releases", r.URL.Path)
+		_, _ = w.Write([]byte(`[{"tag_name": "v1.4.0", "draft": true}, {"tag_name": "v1.3.0-rc.1", "prerelease": true}, {"tag_name": "v1.2.0"}]`))
+	}))
+	defer server.Close()
+	client := NewClient().WithClient(server.Client()).WithAPIURL(server.URL)
+
+	rel, err := client.Find(context.Background(), ChannelStable, Constraint{})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", rel.Tag)
+	rel, err = client.Find(context.Background(), ChannelPrerelease, Constraint{})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.3.0-rc.1", rel.Tag, "drafts are never installed")
+}
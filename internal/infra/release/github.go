@@ -0,0 +1,208 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/httpclient"
+)
+
+const (
+	// DefaultAPIURL is the GitHub REST API the releases are listed from.
+	DefaultAPIURL = "https://api.github.com"
+
+	// Repository is the GitHub repository antimoji is released from.
+	Repository = "jamesainslie/antimoji"
+
+	// apiTimeout bounds a single API request.
+	apiTimeout = 30 * time.Second
+
+	// downloadTimeout bounds the download of a release archive.
+	downloadTimeout = 5 * time.Minute
+
+	// maxMetadataSize bounds API responses and checksums files.
+	maxMetadataSize = 1 << 20
+
+	// maxArchiveSize bounds the size of a release archive.
+	maxArchiveSize = 100 << 20
+)
+
+// ErrNotFound is returned when a release does not exist.
+var ErrNotFound = errors.New("release not found")
+
+// Release is a published antimoji release.
+type Release struct {
+	// Tag is the git tag of the release, such as v1.2.3
+	Tag        string
+	Prerelease bool
+	Assets     []Asset
+}
+
+// Version returns the version of the release without the leading v, as used in the
+// names of its artifacts.
+func (r Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// Client reads releases from the GitHub API and downloads their artifacts.
+type Client struct {
+	client *http.Client
+	apiURL string
+	token  string
+}
+
+// NewClient creates a client for the public GitHub API. A GITHUB_TOKEN in the
+// environment is sent to the API to raise its rate limit.
+func NewClient() *Client {
+	// Without a CA file the client cannot fail
+	client, _ := httpclient.New("", 0)
+	return &Client{
+		client: client,
+		apiURL: DefaultAPIURL,
+		token:  os.Getenv("GITHUB_TOKEN"),
+	}
+}
+
+// NewConfiguredClient creates a client for the GitHub API at apiURL (the public API
+// when "") that trusts the certificates of caFile, or of $ANTIMOJI_CA_FILE when set.
+func NewConfiguredClient(caFile, apiURL string) (*Client, error) {
+	httpClient, err := httpclient.New(httpclient.CAFile(caFile), 0)
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient().WithClient(httpClient)
+	if apiURL != "" {
+		client.WithAPIURL(apiURL)
+	}
+	return client, nil
+}
+
+// WithClient sets the HTTP client used for requests. TLS certificates are verified
+// by the client's transport.
+func (c *Client) WithClient(client *http.Client) *Client {
+	c.client = client
+	return c
+}
+
+// WithAPIURL sets the base URL of the GitHub API.
+func (c *Client) WithAPIURL(apiURL string) *Client {
+	c.apiURL = strings.TrimSuffix(apiURL, "/")
+	return c
+}
+
+// githubRelease is a release as returned by the GitHub API.
+type githubRelease struct {
+	TagName    string `json:"tag_name"`
+	Prerelease bool   `json:"prerelease"`
+	Draft      bool   `json:"draft"`
+	Assets     []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (r githubRelease) release() Release {
+	release := Release{Tag: r.TagName, Prerelease: r.Prerelease}
+	for _, asset := range r.Assets {
+		release.Assets = append(release.Assets, Asset{Name: asset.Name, URL: asset.URL})
+	}
+	return release
+}
+
+// Latest returns the latest stable release.
+func (c *Client) Latest(ctx context.Context) (Release, error) {
+	var release githubRelease
+	if err := c.get(ctx, "/repos/"+Repository+"/releases/latest", &release); err != nil {
+		return Release{}, fmt.Errorf("failed to find the latest release: %w", err)
+	}
+	return release.release(), nil
+}
+
+// List returns the published releases, newest first as the API orders them. Drafts
+// are left out.
+func (c *Client) List(ctx context.Context) ([]Release, error) {
+	var listed []githubRelease
+	if err := c.get(ctx, "/repos/"+Repository+"/releases?per_page=100", &listed); err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	releases := make([]Release, 0, len(listed))
+	for _, rel := range listed {
+		if !rel.Draft {
+			releases = append(releases, rel.release())
+		}
+	}
+	return releases, nil
+}
+
+// ByTag returns the release tagged tag.
+func (c *Client) ByTag(ctx context.Context, tag string) (Release, error) {
+	var release githubRelease
+	if err := c.get(ctx, "/repos/"+Repository+"/releases/tags/"+url.PathEscape(tag), &release); err != nil {
+		return Release{}, fmt.Errorf("failed to find release %s: %w", tag, err)
+	}
+	return release.release(), nil
+}
+
+// get decodes the JSON response of the API endpoint path into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxMetadataSize)).Decode(v)
+}
+
+// download writes the asset to w, failing when it is larger than limit bytes.
+func (c *Client) download(ctx context.Context, asset Asset, w io.Writer, limit int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: unexpected status %s", asset.Name, resp.Status)
+	}
+	n, err := io.Copy(w, io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	if n > limit {
+		return fmt.Errorf("failed to download %s: larger than %d bytes", asset.Name, limit)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package release
+
+import "os"
+
+// replace renames newBinary over exe, which unix systems allow while exe is running.
+func replace(newBinary, exe string) (bool, error) {
+	return false, os.Rename(newBinary, exe)
+}
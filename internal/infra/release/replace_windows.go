@@ -0,0 +1,46 @@
+//go:build windows
+
+package release
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// replace moves newBinary over exe. Windows does not overwrite a running executable
+// but lets it be renamed, so exe is moved aside to exe.old first; the old copy is
+// deleted on the next reboot. When even that fails, the replacement itself is left
+// for the next reboot.
+func replace(newBinary, exe string) (bool, error) {
+	old := exe + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(exe, old); err == nil {
+		if err := os.Rename(newBinary, exe); err != nil {
+			_ = os.Rename(old, exe)
+			return false, err
+		}
+		_ = moveOnReboot(old, "")
+		return false, nil
+	}
+	if err := moveOnReboot(newBinary, exe); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// moveOnReboot schedules from to be moved to to, or deleted when to is "", on the
+// next reboot.
+func moveOnReboot(from, to string) error {
+	fromPtr, err := windows.UTF16PtrFromString(from)
+	if err != nil {
+		return err
+	}
+	var toPtr *uint16
+	if to != "" {
+		if toPtr, err = windows.UTF16PtrFromString(to); err != nil {
+			return err
+		}
+	}
+	return windows.MoveFileEx(fromPtr, toPtr, windows.MOVEFILE_DELAY_UNTIL_REBOOT|windows.MOVEFILE_REPLACE_EXISTING)
+}
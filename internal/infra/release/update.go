@@ -0,0 +1,170 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+)
+
+// ErrNoArchive is returned when a release publishes no archive for a platform.
+var ErrNoArchive = errors.New("release publishes no archive for this platform")
+
+// ArchiveName returns the name goreleaser gives the archive of version for goos/goarch.
+func ArchiveName(version, goos, goarch string) string {
+	return fmt.Sprintf("antimoji_%s_%s_%s.tar.gz", version, goos, goarch)
+}
+
+// BinaryName returns the name of the antimoji binary inside the archives for goos.
+func BinaryName(goos string) string {
+	if goos == "windows" {
+		return "antimoji.exe"
+	}
+	return "antimoji"
+}
+
+// DownloadBinary downloads the archive of rel for goos/goarch, verifies it against the
+// checksums the release publishes and extracts the antimoji binary into a temporary
+// file in dir, whose path it returns. Releases without checksums are refused with
+// ErrUnverified.
+func (c *Client) DownloadBinary(ctx context.Context, rel Release, goos, goarch, dir string) (string, error) {
+	path, _, err := c.downloadBinary(ctx, rel, goos, goarch, dir, false)
+	return path, err
+}
+
+// DownloadUnverifiedBinary is DownloadBinary for releases that may publish no
+// checksum for the archive: it then installs the archive unverified and reports
+// verified false. An archive that does not match a published checksum is still refused.
+func (c *Client) DownloadUnverifiedBinary(ctx context.Context, rel Release, goos, goarch, dir string) (path string, verified bool, err error) {
+	return c.downloadBinary(ctx, rel, goos, goarch, dir, true)
+}
+
+// downloadBinary downloads and extracts the binary of rel, allowing an archive without
+// a published checksum when unverified is set.
+func (c *Client) downloadBinary(ctx context.Context, rel Release, goos, goarch, dir string, unverified bool) (string, bool, error) {
+	name := ArchiveName(rel.Version(), goos, goarch)
+	var archive Asset
+	for _, asset := range rel.Assets {
+		if asset.Name == name {
+			archive = asset
+		}
+	}
+	if archive.URL == "" {
+		return "", false, fmt.Errorf("%w: %s has no %s", ErrNoArchive, rel.Tag, name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+
+	checksums, err := c.checksums(ctx, rel)
+	if errors.Is(err, ErrUnverified) && unverified {
+		checksums, err = Checksums{}, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".antimoji-download-*")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	err = c.download(ctx, archive, tmp, maxArchiveSize)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", false, err
+	}
+	verified := true
+	err = checksums.VerifyFile(name, tmp.Name())
+	if errors.Is(err, ErrNoChecksum) && unverified {
+		verified, err = false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("refusing to install %s: %w", rel.Tag, err)
+	}
+	path, err := extractBinary(tmp.Name(), BinaryName(goos), dir)
+	return path, verified, err
+}
+
+// checksums downloads and parses the checksums rel publishes.
+func (c *Client) checksums(ctx context.Context, rel Release) (Checksums, error) {
+	checksumsAsset, err := ChecksumsAsset(rel.Assets)
+	if err != nil {
+		return nil, fmt.Errorf("refusing to install %s: %w", rel.Tag, err)
+	}
+	var data bytes.Buffer
+	if err := c.download(ctx, checksumsAsset, &data, maxMetadataSize); err != nil {
+		return nil, err
+	}
+	checksums, err := ParseChecksums(data.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("refusing to install %s: %w", rel.Tag, err)
+	}
+	return checksums, nil
+}
+
+// extractBinary copies the file named binary out of the tar.gz archive into a new
+// executable temporary file in dir and returns its path.
+func extractBinary(archive, binary, dir string) (string, error) {
+	file, err := os.Open(archive) // #nosec G304 - path of a verified download
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read release archive: %w", err)
+	}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return "", fmt.Errorf("release archive contains no %s", binary)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read release archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || path.Base(header.Name) != binary {
+			continue
+		}
+
+		out, err := os.CreateTemp(dir, ".antimoji-new-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to extract %s: %w", binary, err)
+		}
+		_, err = io.Copy(out, io.LimitReader(reader, maxArchiveSize)) // #nosec G110 - bounded by maxArchiveSize
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Chmod(out.Name(), 0755) // #nosec G302 - the binary must be executable
+		}
+		if err != nil {
+			_ = os.Remove(out.Name())
+			return "", fmt.Errorf("failed to extract %s: %w", binary, err)
+		}
+		return out.Name(), nil
+	}
+}
+
+// Replace moves the binary at newBinary over the executable at exe. The move is atomic
+// where the platform allows replacing a running executable. When it does not, the
+// replacement is scheduled for the next reboot and deferred is set.
+func Replace(newBinary, exe string) (deferred bool, err error) {
+	if info, err := os.Stat(exe); err == nil {
+		// Keep the permissions the installed binary was given
+		if err := os.Chmod(newBinary, info.Mode().Perm()); err != nil {
+			return false, err
+		}
+	}
+	return replace(newBinary, exe)
+}
@@ -0,0 +1,157 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archive returns a tar.gz holding the files, given as name and content pairs, as
+// goreleaser packages a release.
+func archive(t *testing.T, files ...string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for i := 0; i < len(files); i += 2 {
+		name, content := files[i], files[i+1]
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// releaseServer serves the v1.2.3 release of antimoji for linux/amd64 with the
+// checksums file checksums returns for its archive, or none when that is "".
+func releaseServer(t *testing.T, checksums func(tarball []byte) string) *Client {
+	tarball := archive(t, "README.md", "docs", "antimoji", "new binary")
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/repos/"+Repository+"/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		assets := `{"name": "antimoji_1.2.3_linux_amd64.tar.gz", "browser_download_url": "` + server.URL + `/download/archive"}`
+		if checksums(tarball) != "" {
+			assets += `, {"name": "antimoji_1.2.3_checksums.txt", "browser_download_url": "` + server.URL + `/download/checksums"}`
+		}
+		_, _ = w.Write([]byte(`{"tag_name": "v1.2.3", "prerelease": false, "assets": [` + assets + `]}`))
+	})
+	mux.HandleFunc("/download/archive", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(tarball) })
+	mux.HandleFunc("/download/checksums", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(checksums(tarball))) })
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return NewClient().WithClient(server.Client()).WithAPIURL(server.URL)
+}
+
+// unpublished publishes no checksums.
+func unpublished([]byte) string { return "" }
+
+// published publishes the checksum of the archive.
+func published(tarball []byte) string {
+	return sum(string(tarball)) + "  antimoji_1.2.3_linux_amd64.tar.gz\n"
+}
+
+func TestClient_Latest(t *testing.T) {
+	client := releaseServer(t, unpublished)
+	rel, err := client.Latest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", rel.Tag)
+	assert.Equal(t, "1.2.3", rel.Version())
+	require.Len(t, rel.Assets, 1)
+	assert.Equal(t, "antimoji_1.2.3_linux_amd64.tar.gz", rel.Assets[0].Name)
+
+	_, err = client.ByTag(context.Background(), "v9.9.9")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_DownloadBinary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("installs a verified binary", func(t *testing.T) {
+		client := releaseServer(t, published)
+		rel, err := client.Latest(ctx)
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		exe := filepath.Join(dir, "antimoji")
+		require.NoError(t, os.WriteFile(exe, []byte("old binary"), 0700))
+
+		newBinary, err := client.DownloadBinary(ctx, rel, "linux", "amd64", dir)
+		require.NoError(t, err)
+		deferred, err := Replace(newBinary, exe)
+		require.NoError(t, err)
+		assert.False(t, deferred)
+
+		content, err := os.ReadFile(exe)
+		require.NoError(t, err)
+		assert.Equal(t, "new binary", string(content))
+		info, err := os.Stat(exe)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0700), info.Mode().Perm(), "keeps the permissions of the installed binary")
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "leaves no temporary files behind")
+	})
+
+	t.Run("refuses tampered and unverified archives", func(t *testing.T) {
+		client := releaseServer(t, func([]byte) string { return published([]byte("tampered")) })
+		rel, err := client.Latest(ctx)
+		require.NoError(t, err)
+		_, err = client.DownloadBinary(ctx, rel, "linux", "amd64", t.TempDir())
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+		client = releaseServer(t, unpublished)
+		rel, err = client.Latest(ctx)
+		require.NoError(t, err)
+		_, err = client.DownloadBinary(ctx, rel, "linux", "amd64", t.TempDir())
+		assert.ErrorIs(t, err, ErrUnverified)
+	})
+
+	t.Run("installs unverified archives only when asked", func(t *testing.T) {
+		client := releaseServer(t, unpublished)
+		rel, err := client.Latest(ctx)
+		require.NoError(t, err)
+		newBinary, verified, err := client.DownloadUnverifiedBinary(ctx, rel, "linux", "amd64", t.TempDir())
+		require.NoError(t, err)
+		assert.False(t, verified)
+		content, err := os.ReadFile(newBinary)
+		require.NoError(t, err)
+		assert.Equal(t, "new binary", string(content))
+
+		client = releaseServer(t, published)
+		rel, err = client.Latest(ctx)
+		require.NoError(t, err)
+		_, verified, err = client.DownloadUnverifiedBinary(ctx, rel, "linux", "amd64", t.TempDir())
+		require.NoError(t, err)
+		assert.True(t, verified)
+
+		client = releaseServer(t, func([]byte) string { return published([]byte("tampered")) })
+		rel, err = client.Latest(ctx)
+		require.NoError(t, err)
+		_, _, err = client.DownloadUnverifiedBinary(ctx, rel, "linux", "amd64", t.TempDir())
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("needs an archive for the platform", func(t *testing.T) {
+		client := releaseServer(t, unpublished)
+		rel, err := client.Latest(ctx)
+		require.NoError(t, err)
+		_, err = client.DownloadBinary(ctx, rel, "plan9", "386", t.TempDir())
+		assert.ErrorIs(t, err, ErrNoArchive)
+	})
+}
+
+func TestBinaryName(t *testing.T) {
+	assert.Equal(t, "antimoji.exe", BinaryName("windows"))
+	assert.Equal(t, "antimoji", BinaryName("darwin"))
+	assert.Equal(t, "antimoji_1.2.3_windows_amd64.tar.gz", ArchiveName("1.2.3", "windows", "amd64"))
+}
@@ -0,0 +1,97 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// EnvUpdateCheck turns the update check on or off when set to a boolean, whatever
+	// the configuration says; CI jobs set it to false.
+	EnvUpdateCheck = "ANTIMOJI_UPDATE_CHECK"
+
+	// UpdateCheckFile is the file in the user cache directory recording the last check.
+	UpdateCheckFile = "update-check.json"
+
+	// checkInterval is how long the result of an update check is reused.
+	checkInterval = 24 * time.Hour
+)
+
+// updateState is the result of the last update check.
+type updateState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	// Query is the channel and constraint that were checked
+	Query  string `json:"query"`
+	Latest string `json:"latest"`
+}
+
+// UpdateChecker finds the newest release at most once a day, recording the result in
+// a state file so that other runs reuse it.
+type UpdateChecker struct {
+	client *Client
+	path   string
+	now    func() time.Time
+}
+
+// NewUpdateChecker creates a checker asking client and recording its results in the
+// file at path.
+func NewUpdateChecker(client *Client, path string) *UpdateChecker {
+	return &UpdateChecker{client: client, path: path, now: time.Now}
+}
+
+// Newest returns the tag of the newest release the channel and constraint allow. The
+// releases are only listed when the state file holds no result for them from the last
+// day; failures are recorded too, so an unreachable API is not asked again until the
+// next day.
+func (u *UpdateChecker) Newest(ctx context.Context, channel Channel, constraint Constraint) (string, error) {
+	query := fmt.Sprintf("%s %s", channel, constraint)
+	if state, err := u.load(); err == nil && state.Query == query && u.now().Sub(state.CheckedAt) < checkInterval {
+		return state.Latest, nil
+	}
+
+	newest, err := u.client.Find(ctx, channel, constraint)
+	state := updateState{CheckedAt: u.now(), Query: query, Latest: newest.Tag}
+	if saveErr := u.save(state); err == nil {
+		err = saveErr
+	}
+	return newest.Tag, err
+}
+
+// load reads the state file.
+func (u *UpdateChecker) load() (updateState, error) {
+	var state updateState
+	data, err := os.ReadFile(u.path) // #nosec G304 - file in the user cache directory
+	if err != nil {
+		return state, err
+	}
+	return state, json.Unmarshal(data, &state)
+}
+
+// save replaces the state file atomically, so concurrent runs never read half of it.
+func (u *UpdateChecker) save(state updateState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(u.path), 0750); err != nil {
+		return fmt.Errorf("failed to record update check: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(u.path), ".update-check-*")
+	if err != nil {
+		return fmt.Errorf("failed to record update check: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to record update check: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to record update check: %w", err)
+	}
+	return os.Rename(tmp.Name(), u.path)
+}
@@ -0,0 +1,56 @@
+package release
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateChecker_Newest(t *testing.T) {
+	var requests atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			http.Error(w, "rate limited", http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"tag_name": "v1.3.0-rc.1", "prerelease": true}, {"tag_name": "v1.2.0"}]`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	checker := NewUpdateChecker(NewClient().WithClient(server.Client()).WithAPIURL(server.URL), filepath.Join(t.TempDir(), UpdateCheckFile))
+	checker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	newest, err := checker.Newest(ctx, ChannelStable, Constraint{})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", newest)
+
+	now = now.Add(23 * time.Hour)
+	newest, err = checker.Newest(ctx, ChannelStable, Constraint{})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", newest)
+	assert.Equal(t, int32(1), requests.Load(), "a check from the last day is reused")
+
+	newest, err = checker.Newest(ctx, ChannelPrerelease, Constraint{})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.3.0-rc.1", newest, "another channel is checked again")
+
+	now = now.Add(25 * time.Hour)
+	failing.Store(true)
+	_, err = checker.Newest(ctx, ChannelPrerelease, Constraint{})
+	assert.ErrorContains(t, err, "unexpected status 403")
+	newest, err = checker.Newest(ctx, ChannelPrerelease, Constraint{})
+	require.NoError(t, err)
+	assert.Empty(t, newest)
+	assert.Equal(t, int32(3), requests.Load(), "failures are not retried until the next day")
+}
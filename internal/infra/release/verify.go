@@ -0,0 +1,101 @@
+// Package release finds and verifies the artifacts of antimoji releases, so that a
+// binary is only replaced by one whose checksum the release publishes.
+package release
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrUnverified is returned when a release publishes no checksums, so its artifacts
+	// cannot be verified.
+	ErrUnverified = errors.New("release publishes no checksums")
+
+	// ErrNoChecksum is returned when the checksums of a release do not list an artifact.
+	ErrNoChecksum = errors.New("artifact is not listed in the release checksums")
+
+	// ErrChecksumMismatch is returned when an artifact does not match its published checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string
+	URL  string
+}
+
+// ChecksumsAsset returns the checksums file among assets: the
+// antimoji_<version>_checksums.txt written by goreleaser, or a SHA256SUMS file.
+func ChecksumsAsset(assets []Asset) (Asset, error) {
+	for _, asset := range assets {
+		if strings.HasSuffix(asset.Name, "_checksums.txt") || asset.Name == "SHA256SUMS" {
+			return asset, nil
+		}
+	}
+	return Asset{}, ErrUnverified
+}
+
+// Checksums maps artifact names to their SHA-256 checksums in lower-case hex.
+type Checksums map[string]string
+
+// ParseChecksums parses a checksums file in the format of sha256sum: one
+// "<hex>  <name>" line per artifact, the name marked with * in binary mode.
+func ParseChecksums(data []byte) (Checksums, error) {
+	checksums := Checksums{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 || len(fields[0]) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid checksums line %d: %q", line, text)
+		}
+		if _, err := hex.DecodeString(fields[0]); err != nil {
+			return nil, fmt.Errorf("invalid checksums line %d: %q", line, text)
+		}
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(checksums) == 0 {
+		return nil, fmt.Errorf("%w: the checksums file is empty", ErrUnverified)
+	}
+	return checksums, nil
+}
+
+// Verify checks that content is the artifact name as published in c.
+func (c Checksums) Verify(name string, content io.Reader) error {
+	expected, ok := c[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoChecksum, name)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("%w for %s: expected sha256 %s, got %s", ErrChecksumMismatch, name, expected, actual)
+	}
+	return nil
+}
+
+// VerifyFile checks that the file at path is the artifact name as published in c.
+func (c Checksums) VerifyFile(name, path string) error {
+	file, err := os.Open(path) // #nosec G304 - path of a downloaded artifact
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	return c.Verify(name, file)
+}
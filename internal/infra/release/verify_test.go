@@ -0,0 +1,63 @@
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sum(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestChecksumsAsset(t *testing.T) {
+	asset, err := ChecksumsAsset([]Asset{
+		{Name: "antimoji_1.2.3_linux_amd64.tar.gz"},
+		{Name: "antimoji_1.2.3_checksums.txt", URL: "https://example.com/checksums"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/checksums", asset.URL)
+
+	asset, err = ChecksumsAsset([]Asset{{Name: "SHA256SUMS"}})
+	require.NoError(t, err)
+	assert.Equal(t, "SHA256SUMS", asset.Name)
+
+	_, err = ChecksumsAsset([]Asset{{Name: "antimoji_1.2.3_linux_amd64.tar.gz"}})
+	assert.ErrorIs(t, err, ErrUnverified)
+}
+
+func TestParseChecksums(t *testing.T) {
+	data := sum("a") + "  antimoji_1.2.3_linux_amd64.tar.gz\n\n" + strings.ToUpper(sum("b")) + " *antimoji_1.2.3_darwin_arm64.tar.gz\n"
+	checksums, err := ParseChecksums([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, Checksums{
+		"antimoji_1.2.3_linux_amd64.tar.gz":  sum("a"),
+		"antimoji_1.2.3_darwin_arm64.tar.gz": sum("b"),
+	}, checksums)
+
+	_, err = ParseChecksums([]byte("not-a-checksum  antimoji.tar.gz\n"))
+	assert.ErrorContains(t, err, "invalid checksums line 1")
+	_, err = ParseChecksums(nil)
+	assert.ErrorIs(t, err, ErrUnverified)
+}
+
+func TestChecksums_Verify(t *testing.T) {
+	checksums := Checksums{"antimoji.tar.gz": sum("release")}
+
+	assert.NoError(t, checksums.Verify("antimoji.tar.gz", strings.NewReader("release")))
+	err := checksums.Verify("antimoji.tar.gz", strings.NewReader("tampered"))
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "expected sha256 "+sum("release"))
+	assert.ErrorIs(t, checksums.Verify("other.tar.gz", strings.NewReader("release")), ErrNoChecksum)
+
+	path := filepath.Join(t.TempDir(), "download")
+	require.NoError(t, os.WriteFile(path, []byte("release"), 0600))
+	assert.NoError(t, checksums.VerifyFile("antimoji.tar.gz", path))
+}
@@ -0,0 +1,91 @@
+package release
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two semantic versions such as v1.2.3 or 1.3.0-rc.1 and
+// returns -1, 0 or +1. Build metadata is ignored and a prerelease sorts before its
+// release. Versions that do not parse sort before those that do.
+func CompareVersions(a, b string) int {
+	coreA, preA, okA := splitVersion(a)
+	coreB, preB, okB := splitVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := range coreA {
+		if c := compareInts(coreA[i], coreB[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case preA == "" && preB == "":
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	return comparePrerelease(preA, preB)
+}
+
+// splitVersion returns the major, minor and patch numbers and the prerelease of version.
+func splitVersion(version string) ([3]int, string, bool) {
+	var core [3]int
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, pre, _ := strings.Cut(version, "-")
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return core, "", false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return core, "", false
+		}
+		core[i] = n
+	}
+	return core, pre, true
+}
+
+// comparePrerelease compares prereleases identifier by identifier, numerically where
+// both are numbers.
+func comparePrerelease(a, b string) int {
+	idsA, idsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(idsA) && i < len(idsB); i++ {
+		numA, errA := strconv.Atoi(idsA[i])
+		numB, errB := strconv.Atoi(idsB[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareInts(numA, numB)
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(idsA[i], idsB[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(idsA), len(idsB))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,28 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"v1.2.3", "v1.10.0", -1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.3.0-rc.1", "v1.3.0", -1},
+		{"v1.3.0-rc.2", "v1.3.0-rc.10", -1},
+		{"v1.3.0-beta", "v1.3.0-alpha", 1},
+		{"v1.3.0+build.5", "v1.3.0", 0},
+		{"0.9.16-refactor", "v0.9.16", -1},
+		{"dev", "v0.0.1", -1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b), "%s vs %s", tt.a, tt.b)
+		assert.Equal(t, -tt.want, CompareVersions(tt.b, tt.a), "%s vs %s", tt.b, tt.a)
+	}
+}
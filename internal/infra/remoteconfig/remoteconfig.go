@@ -0,0 +1,264 @@
+// Package remoteconfig fetches shared configuration files over HTTPS, keeping a local
+// copy of each so that later runs work offline.
+package remoteconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvOffline forces cache-only resolution when set to a true value.
+	EnvOffline = "ANTIMOJI_OFFLINE"
+
+	// maxSize bounds the size of a remote configuration file.
+	maxSize = 1 << 20
+
+	// fetchTimeout bounds a single download.
+	fetchTimeout = 30 * time.Second
+
+	githubPrefix = "github:"
+	sha256Suffix = "#sha256="
+)
+
+var (
+	// ErrChecksumMismatch is returned when content does not match its pinned checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrNotCached is returned in offline mode for a reference that was never fetched.
+	ErrNotCached = errors.New("not in the local cache")
+)
+
+// Reference is a parsed remote configuration location.
+type Reference struct {
+	// URL is the HTTPS address of the file
+	URL string
+	// SHA256 is the pinned checksum in lower-case hex, or "" when not pinned
+	SHA256 string
+}
+
+// IsRemote reports whether ref names a remote configuration rather than a local path.
+func IsRemote(ref string) bool {
+	return strings.HasPrefix(ref, githubPrefix) ||
+		strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://")
+}
+
+// Parse parses a remote reference: an https:// URL or github:owner/repo//path@ref, either
+// optionally followed by #sha256=<hex> to pin the content. The ref of a GitHub reference
+// defaults to HEAD. Plain http:// is rejected, since the content could be altered in transit.
+func Parse(ref string) (Reference, error) {
+	return parse(ref, "https://raw.githubusercontent.com")
+}
+
+func parse(ref, githubBase string) (Reference, error) {
+	location, checksum, pinned := strings.Cut(ref, sha256Suffix)
+	var parsed Reference
+	if pinned {
+		checksum = strings.ToLower(checksum)
+		if len(checksum) != sha256.Size*2 || strings.Trim(checksum, "0123456789abcdef") != "" {
+			return Reference{}, fmt.Errorf("invalid remote config %q: sha256 must be %d hex digits", ref, sha256.Size*2)
+		}
+		parsed.SHA256 = checksum
+	}
+
+	if rest, ok := strings.CutPrefix(location, githubPrefix); ok {
+		repo, file, ok := strings.Cut(rest, "//")
+		version := "HEAD"
+		if at := strings.LastIndex(file, "@"); at >= 0 {
+			file, version = file[:at], file[at+1:]
+		}
+		if !ok || strings.Count(repo, "/") != 1 || strings.HasPrefix(repo, "/") || strings.HasSuffix(repo, "/") || file == "" || version == "" {
+			return Reference{}, fmt.Errorf("invalid remote config %q: expected github:owner/repo//path[@ref]", ref)
+		}
+		parsed.URL = strings.TrimSuffix(githubBase, "/") + "/" + repo + "/" + url.PathEscape(version) + "/" + strings.TrimPrefix(file, "/")
+		return parsed, nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return Reference{}, fmt.Errorf("invalid remote config %q: %w", ref, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return Reference{}, fmt.Errorf("invalid remote config %q: only https:// URLs are supported", ref)
+	}
+	parsed.URL = u.String()
+	return parsed, nil
+}
+
+// Fetcher downloads remote configuration files and caches them on disk.
+type Fetcher struct {
+	client     *http.Client
+	cacheDir   string
+	offline    bool
+	githubBase string
+	token      string
+}
+
+// New creates a fetcher caching files in cacheDir. When offline is set, only cached
+// copies are used.
+func New(cacheDir string, offline bool) *Fetcher {
+	return &Fetcher{
+		client:     &http.Client{Timeout: fetchTimeout},
+		cacheDir:   cacheDir,
+		offline:    offline,
+		githubBase: "https://raw.githubusercontent.com",
+		token:      os.Getenv("GITHUB_TOKEN"),
+	}
+}
+
+// WithClient sets the HTTP client used for downloads. TLS certificates are verified
+// by the client's transport.
+func (f *Fetcher) WithClient(client *http.Client) *Fetcher {
+	f.client = client
+	return f
+}
+
+// Offline reports whether the fetcher only uses cached copies.
+func (f *Fetcher) Offline() bool {
+	return f.offline
+}
+
+// Result is a fetched configuration file.
+type Result struct {
+	Content []byte
+	// Stale is set when the download failed and the cached copy was used instead
+	Stale error
+}
+
+// Fetch returns the content of a remote reference. A successful download refreshes the
+// cache; when the download fails, the cached copy is used and the failure reported in
+// Result.Stale. Content that does not match a pinned checksum is never used or cached.
+func (f *Fetcher) Fetch(ctx context.Context, ref string) (Result, error) {
+	parsed, err := parse(ref, f.githubBase)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if f.offline {
+		content, err := f.cached(parsed)
+		if err != nil {
+			return Result{}, fmt.Errorf("%s (offline): %w", parsed.URL, err)
+		}
+		return Result{Content: content}, nil
+	}
+
+	content, err := f.download(ctx, parsed)
+	if err == nil {
+		err = verify(parsed, content)
+	}
+	if err != nil {
+		if errors.Is(err, ErrChecksumMismatch) {
+			return Result{}, fmt.Errorf("%s: %w", parsed.URL, err)
+		}
+		cached, cacheErr := f.cached(parsed)
+		if cacheErr != nil {
+			return Result{}, fmt.Errorf("failed to fetch %s: %w", parsed.URL, err)
+		}
+		return Result{Content: cached, Stale: err}, nil
+	}
+
+	if err := f.store(parsed, content); err != nil {
+		return Result{}, err
+	}
+	return Result{Content: content}, nil
+}
+
+// download fetches the content of parsed over HTTPS.
+func (f *Fetcher) download(ctx context.Context, parsed Reference) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Private repositories need a token; send it only to the GitHub content host
+	if f.token != "" && strings.HasPrefix(parsed.URL, f.githubBase+"/") {
+		req.Header.Set("Authorization", "token "+f.token)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxSize {
+		return nil, fmt.Errorf("larger than %d bytes", maxSize)
+	}
+	return content, nil
+}
+
+// cached returns the cached copy of parsed, verified against its pinned checksum.
+func (f *Fetcher) cached(parsed Reference) ([]byte, error) {
+	content, err := os.ReadFile(f.cachePath(parsed)) // #nosec G304 - path is derived from a hash
+	if os.IsNotExist(err) {
+		return nil, ErrNotCached
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(parsed, content); err != nil {
+		return nil, fmt.Errorf("cached copy: %w", err)
+	}
+	return content, nil
+}
+
+// store writes content to the cache, replacing any previous copy atomically.
+func (f *Fetcher) store(parsed Reference, content []byte) error {
+	path := f.cachePath(parsed)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create config cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".antimoji-config-*")
+	if err != nil {
+		return fmt.Errorf("failed to cache %s: %w", parsed.URL, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to cache %s: %w", parsed.URL, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to cache %s: %w", parsed.URL, err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// cachePath returns the cache file for parsed, named after a hash of its URL.
+func (f *Fetcher) cachePath(parsed Reference) string {
+	sum := sha256.Sum256([]byte(parsed.URL))
+	return filepath.Join(f.cacheDir, hex.EncodeToString(sum[:])+".yaml")
+}
+
+// verify checks content against the pinned checksum of parsed, if any.
+func verify(parsed Reference, content []byte) error {
+	if parsed.SHA256 == "" {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); actual != parsed.SHA256 {
+		return fmt.Errorf("%w: expected sha256 %s, got %s", ErrChecksumMismatch, parsed.SHA256, actual)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package remoteconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = "profiles:\n  default:\n    max_emoji_threshold: 0\n"
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParse(t *testing.T) {
+	sum := checksum(testConfig)
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    Reference
+		wantErr string
+	}{
+		{"https URL", "https://example.com/antimoji.yaml", Reference{URL: "https://example.com/antimoji.yaml"}, ""},
+		{"pinned URL", "https://example.com/a.yaml#sha256=" + sum, Reference{URL: "https://example.com/a.yaml", SHA256: sum}, ""},
+		{"github with ref", "github:org/policies//antimoji/base.yaml@v1.2.0",
+			Reference{URL: "https://raw.githubusercontent.com/org/policies/v1.2.0/antimoji/base.yaml"}, ""},
+		{"github default ref", "github:org/policies//base.yaml",
+			Reference{URL: "https://raw.githubusercontent.com/org/policies/HEAD/base.yaml"}, ""},
+		{"plain http", "http://example.com/a.yaml", Reference{}, "only https://"},
+		{"github without path", "github:org/policies", Reference{}, "expected github:owner/repo//path"},
+		{"short checksum", "https://example.com/a.yaml#sha256=abc", Reference{}, "64 hex digits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.ref)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsRemote(t *testing.T) {
+	assert.True(t, IsRemote("https://example.com/a.yaml"))
+	assert.True(t, IsRemote("github:org/repo//a.yaml"))
+	assert.False(t, IsRemote(".antimoji.yaml"))
+	assert.False(t, IsRemote("configs/https.yaml"))
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	body := testConfig
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	fetcher := New(dir, false).WithClient(server.Client())
+	ref := server.URL + "/antimoji.yaml"
+
+	t.Run("downloads and caches", func(t *testing.T) {
+		result, err := fetcher.Fetch(ctx, ref)
+		require.NoError(t, err)
+		assert.Equal(t, testConfig, string(result.Content))
+		assert.NoError(t, result.Stale)
+
+		offline, err := New(dir, true).Fetch(ctx, ref)
+		require.NoError(t, err)
+		assert.Equal(t, testConfig, string(offline.Content))
+	})
+
+	t.Run("verifies pinned checksums", func(t *testing.T) {
+		_, err := fetcher.Fetch(ctx, ref+"#sha256="+checksum(testConfig))
+		require.NoError(t, err)
+
+		_, err = fetcher.Fetch(ctx, ref+"#sha256="+checksum("something else"))
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("falls back to the cache", func(t *testing.T) {
+		body = "changed"
+		defer func() { body = testConfig }()
+
+		_, err := fetcher.Fetch(ctx, ref+"#sha256="+checksum(testConfig))
+		assert.ErrorIs(t, err, ErrChecksumMismatch, "a mismatching download is never replaced by the cache")
+
+		failing := New(dir, false).WithClient(&http.Client{Transport: http.DefaultTransport})
+		result, err := failing.Fetch(ctx, ref)
+		require.NoError(t, err, "the test server's certificate is untrusted, so the download fails")
+		assert.Equal(t, testConfig, string(result.Content))
+		assert.ErrorContains(t, result.Stale, "certificate")
+	})
+
+	t.Run("offline without a cached copy", func(t *testing.T) {
+		_, err := New(t.TempDir(), true).Fetch(ctx, ref)
+		assert.ErrorIs(t, err, ErrNotCached)
+	})
+
+	t.Run("reports HTTP errors", func(t *testing.T) {
+		_, err := fetcher.Fetch(ctx, server.URL+"/missing.yaml")
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("resolves github references", func(t *testing.T) {
+		github := New(t.TempDir(), false).WithClient(server.Client())
+		github.githubBase = server.URL
+		result, err := github.Fetch(ctx, "github:org/policies//antimoji.yaml@main")
+		require.NoError(t, err)
+		assert.Equal(t, testConfig, string(result.Content))
+	})
+}
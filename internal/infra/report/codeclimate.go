@@ -0,0 +1,148 @@
+package report
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// CodeClimateCheckName identifies antimoji findings in Code Climate reports.
+const CodeClimateCheckName = "antimoji/emoji"
+
+// CodeClimateIssue is a finding in the Code Climate JSON format read by GitLab code
+// quality reports and reviewdog.
+type CodeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    CodeClimateLocation `json:"location"`
+}
+
+// CodeClimateLocation is the file and line range of an issue.
+type CodeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeClimateLines `json:"lines"`
+}
+
+// CodeClimateLines is a line range, 1-based and inclusive.
+type CodeClimateLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// CodeClimate converts the findings in results to Code Climate issues, in result order.
+// Paths are made relative to the working directory, where CI jobs run. Results with an
+// error are skipped.
+//
+// Fingerprints identify an issue across runs, so code quality tools can tell new
+// findings from resolved ones. They hash the path, the emoji and the trimmed text of its
+// line rather than the line number, so editing other parts of the file keeps them; the
+// occurrence index tells identical findings on identical lines apart.
+func CodeClimate(results []types.ProcessResult) []CodeClimateIssue {
+	issues := []CodeClimateIssue{}
+	eachFinding(results, func(path string, match types.EmojiMatch, text string, occurrence int) {
+		issues = append(issues, CodeClimateIssue{
+			Type:        "issue",
+			CheckName:   CodeClimateCheckName,
+			Description: fmt.Sprintf("Emoji %s (%s) found", match.Emoji, match.Category),
+			Categories:  []string{"Style"},
+			Severity:    "minor",
+			Fingerprint: fingerprint(path, match.Emoji, text, occurrence),
+			Location: CodeClimateLocation{
+				Path:  path,
+				Lines: CodeClimateLines{Begin: match.Line, End: match.Line},
+			},
+		})
+	})
+	return issues
+}
+
+// eachFinding calls fn for each finding in results, skipping results with an error,
+// with the path of its file relative to the working directory, the trimmed text of its
+// line and its occurrence among the findings of the same emoji on identical lines of
+// the file.
+func eachFinding(results []types.ProcessResult, fn func(path string, match types.EmojiMatch, text string, occurrence int)) {
+	for _, result := range results {
+		if result.Error != nil || len(result.DetectionResult.Emojis) == 0 {
+			continue
+		}
+
+		path := issuePath(result.FilePath)
+		lines := sourceLines(result.FilePath)
+		seen := make(map[string]int)
+		for _, match := range result.DetectionResult.Emojis {
+			text := ""
+			if match.Line > 0 && match.Line <= len(lines) {
+				text = strings.TrimSpace(lines[match.Line-1])
+			}
+			key := match.Emoji + "\x00" + text
+			occurrence := seen[key]
+			seen[key]++
+			fn(path, match, text, occurrence)
+		}
+	}
+}
+
+// WriteCodeClimate writes the issues for results as a JSON array.
+func WriteCodeClimate(w io.Writer, results []types.ProcessResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(CodeClimate(results)); err != nil {
+		return fmt.Errorf("failed to write Code Climate report: %w", err)
+	}
+	return nil
+}
+
+// WriteCodeClimateFile writes the issues for results to path.
+func WriteCodeClimateFile(path string, results []types.ProcessResult) error {
+	file, err := os.Create(path) // #nosec G304 - report path is user-provided by design
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if err := WriteCodeClimate(file, results); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// fingerprint returns the stable identifier of a finding.
+func fingerprint(path, emoji, line string, occurrence int) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{CodeClimateCheckName, path, emoji, line, fmt.Sprint(occurrence)}, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// issuePath returns filePath slash-separated and relative to the working directory
+// when it is inside it.
+func issuePath(filePath string) string {
+	if abs, err := filepath.Abs(filePath); err == nil {
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				filePath = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(filePath))
+}
+
+// sourceLines returns the lines of the file at path, or nil when it cannot be read.
+func sourceLines(path string) []string {
+	content, err := os.ReadFile(path) // #nosec G304 - files from the scan results
+	if err != nil {
+		return nil
+	}
+	return strings.Split(string(bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))), "\n")
+}
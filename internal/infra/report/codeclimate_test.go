@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeClimate(t *testing.T) {
+	issues := CodeClimate(testResults())
+
+	require.Len(t, issues, 3, "errors are not issues")
+	assert.Equal(t, CodeClimateIssue{
+		Type:        "issue",
+		CheckName:   CodeClimateCheckName,
+		Description: "Emoji 🚀 (unicode) found",
+		Categories:  []string{"Style"},
+		Severity:    "minor",
+		Fingerprint: issues[0].Fingerprint,
+		Location:    CodeClimateLocation{Path: "src/a.go", Lines: CodeClimateLines{Begin: 3, End: 3}},
+	}, issues[0])
+	assert.Len(t, issues[0].Fingerprint, 32)
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+
+	assert.Equal(t, []CodeClimateIssue{}, CodeClimate(nil))
+}
+
+func TestCodeClimate_StableFingerprints(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	detect := func(content string, lines ...int) []CodeClimateIssue {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+		matches := make([]types.EmojiMatch, len(lines))
+		for i, line := range lines {
+			matches[i] = types.EmojiMatch{Emoji: "🚀", Line: line, Category: types.CategoryUnicode}
+		}
+		return CodeClimate([]types.ProcessResult{{FilePath: file, DetectionResult: types.DetectionResult{Emojis: matches}}})
+	}
+
+	before := detect("// 🚀 launch\n// 🚀 launch\n", 1, 2)
+	after := detect("package main\n\n// 🚀 launch\n// 🚀 launch\n", 3, 4)
+
+	require.Len(t, after, 2)
+	assert.Equal(t, before[0].Fingerprint, after[0].Fingerprint, "moving a finding keeps its fingerprint")
+	assert.Equal(t, before[1].Fingerprint, after[1].Fingerprint)
+	assert.NotEqual(t, after[0].Fingerprint, after[1].Fingerprint, "identical lines are told apart")
+	assert.Equal(t, 3, after[0].Location.Lines.Begin)
+
+	changed := detect("// 🚀 liftoff\n", 1)
+	assert.NotContains(t, []string{before[0].Fingerprint, before[1].Fingerprint}, changed[0].Fingerprint)
+}
+
+func TestWriteCodeClimate(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCodeClimate(&buf, nil))
+	assert.Equal(t, "[]\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteCodeClimate(&buf, testResults()))
+	var issues []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+	require.Len(t, issues, 3)
+	assert.Equal(t, "antimoji/emoji", issues[0]["check_name"])
+	assert.Contains(t, buf.String(), `"description": "Emoji :) (emoticon) found"`)
+}
@@ -0,0 +1,144 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// FindingsSchemaVersion is the version of the findings-json schema. It changes only
+// when fields are removed or change meaning, or when fingerprints are computed
+// differently; new fields keep the version.
+const FindingsSchemaVersion = "1"
+
+// FindingsTool names antimoji as the producer of findings-json reports.
+const FindingsTool = "antimoji"
+
+// FindingsReport is the findings-json export of a scan, read by vulnerability
+// management and tracking tools that deduplicate findings across runs.
+type FindingsReport struct {
+	SchemaVersion string          `json:"schema_version"`
+	Tool          string          `json:"tool"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+	Findings      []FindingRecord `json:"findings"`
+}
+
+// FindingRecord is a finding with its stable fingerprint.
+type FindingRecord struct {
+	// Fingerprint identifies the finding across runs, as long as its line keeps its
+	// text, whatever lines are added or removed around it
+	Fingerprint string `json:"fingerprint"`
+	// ContextHash hashes the text of the line, normalized by collapsing whitespace
+	ContextHash string `json:"context_hash"`
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	Emoji       string `json:"emoji"`
+	Category    string `json:"category"`
+	// Occurrence tells identical findings on identical lines of the file apart, from 0
+	Occurrence int `json:"occurrence"`
+}
+
+// Findings converts the findings in results to findings-json records, in result order.
+// Paths are made relative to the working directory as in Code Climate reports, and
+// results with an error are skipped.
+func Findings(results []types.ProcessResult, generatedAt time.Time) FindingsReport {
+	report := FindingsReport{
+		SchemaVersion: FindingsSchemaVersion,
+		Tool:          FindingsTool,
+		GeneratedAt:   generatedAt,
+		Findings:      []FindingRecord{},
+	}
+	// Lines differing only in whitespace share a context hash, so occurrences are
+	// counted per hash
+	seen := make(map[string]int)
+	eachFinding(results, func(path string, match types.EmojiMatch, text string, _ int) {
+		contextHash := hashFields(normalizeContext(text))
+		key := path + "\x00" + contextHash + "\x00" + match.Emoji
+		occurrence := seen[key]
+		seen[key]++
+		report.Findings = append(report.Findings, FindingRecord{
+			Fingerprint: hashFields(FindingsTool, path, contextHash, match.Emoji, fmt.Sprint(occurrence)),
+			ContextHash: contextHash,
+			Path:        path,
+			Line:        match.Line,
+			Column:      match.Column,
+			Emoji:       match.Emoji,
+			Category:    string(match.Category),
+			Occurrence:  occurrence,
+		})
+	})
+	return report
+}
+
+// WriteFindings writes the findings-json report for results.
+func WriteFindings(w io.Writer, results []types.ProcessResult, generatedAt time.Time) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(Findings(results, generatedAt)); err != nil {
+		return fmt.Errorf("failed to write findings report: %w", err)
+	}
+	return nil
+}
+
+// WriteFindingsFile writes the findings-json report for results to path.
+func WriteFindingsFile(path string, results []types.ProcessResult, generatedAt time.Time) error {
+	file, err := os.Create(path) // #nosec G304 - report path is user-provided by design
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if err := WriteFindings(file, results, generatedAt); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadFindingsFile reads a findings-json report written by WriteFindingsFile, such as
+// the baseline of a later scan. Reports of another schema version are rejected, as their
+// fingerprints may be computed differently.
+func ReadFindingsFile(path string) (FindingsReport, error) {
+	content, err := os.ReadFile(path) // #nosec G304 - report path is user-provided by design
+	if err != nil {
+		return FindingsReport{}, fmt.Errorf("failed to read findings report: %w", err)
+	}
+	var report FindingsReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		return FindingsReport{}, fmt.Errorf("invalid findings report %s: %w", path, err)
+	}
+	if report.SchemaVersion != FindingsSchemaVersion {
+		return FindingsReport{}, fmt.Errorf("findings report %s has schema version %q, expected %q",
+			path, report.SchemaVersion, FindingsSchemaVersion)
+	}
+	return report, nil
+}
+
+// Fingerprints returns the set of fingerprints of the report's findings.
+func (r FindingsReport) Fingerprints() map[string]bool {
+	fingerprints := make(map[string]bool, len(r.Findings))
+	for _, finding := range r.Findings {
+		fingerprints[finding.Fingerprint] = true
+	}
+	return fingerprints
+}
+
+// normalizeContext returns the text of a line with its whitespace collapsed, so
+// reindenting or realigning the line keeps the fingerprints of its findings.
+func normalizeContext(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// hashFields returns a hex SHA-256 prefix of the NUL-joined fields.
+func hashFields(fields ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
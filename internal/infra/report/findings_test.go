@@ -0,0 +1,91 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindings(t *testing.T) {
+	generatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	findings := Findings(testResults(), generatedAt)
+
+	assert.Equal(t, FindingsSchemaVersion, findings.SchemaVersion)
+	assert.Equal(t, FindingsTool, findings.Tool)
+	assert.Equal(t, generatedAt, findings.GeneratedAt)
+	require.Len(t, findings.Findings, 3, "errors are not findings")
+	first := findings.Findings[0]
+	assert.Equal(t, "src/a.go", first.Path)
+	assert.Equal(t, "🚀", first.Emoji)
+	assert.Equal(t, "unicode", first.Category)
+	assert.Len(t, first.Fingerprint, 32)
+	assert.Len(t, first.ContextHash, 32)
+
+	assert.Equal(t, []FindingRecord{}, Findings(nil, generatedAt).Findings)
+}
+
+func TestFindings_StableFingerprints(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	detect := func(content string, lines ...int) []FindingRecord {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+		matches := make([]types.EmojiMatch, len(lines))
+		for i, line := range lines {
+			matches[i] = types.EmojiMatch{Emoji: "🚀", Line: line, Category: types.CategoryUnicode}
+		}
+		return Findings([]types.ProcessResult{{FilePath: file, DetectionResult: types.DetectionResult{Emojis: matches}}}, time.Time{}).Findings
+	}
+
+	before := detect("// 🚀 launch\n// 🚀 launch\n", 1, 2)
+	after := detect("package main\n\n\t//   🚀 launch \n// 🚀  launch\n", 3, 4)
+
+	require.Len(t, after, 2)
+	assert.Equal(t, before[0].Fingerprint, after[0].Fingerprint, "moving or reindenting a finding keeps its fingerprint")
+	assert.Equal(t, before[1].Fingerprint, after[1].Fingerprint)
+	assert.Equal(t, after[0].ContextHash, after[1].ContextHash)
+	assert.NotEqual(t, after[0].Fingerprint, after[1].Fingerprint, "identical lines are told apart")
+	assert.Equal(t, []int{0, 1}, []int{after[0].Occurrence, after[1].Occurrence})
+
+	changed := detect("// 🚀 liftoff\n", 1)
+	assert.NotContains(t, []string{before[0].Fingerprint, before[1].Fingerprint}, changed[0].Fingerprint)
+}
+
+func TestWriteFindingsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "findings.json")
+	require.NoError(t, WriteFindingsFile(path, testResults(), time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.NewDecoder(bytes.NewReader(content)).Decode(&decoded))
+	assert.Equal(t, "1", decoded["schema_version"])
+	assert.Equal(t, "2026-03-01T12:00:00Z", decoded["generated_at"])
+	assert.Len(t, decoded["findings"], 3)
+	assert.Contains(t, string(content), `"emoji": "🚀"`)
+}
+
+func TestReadFindingsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "findings.json")
+	require.NoError(t, WriteFindingsFile(path, testResults(), time.Now()))
+
+	report, err := ReadFindingsFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, Findings(testResults(), report.GeneratedAt).Findings, report.Findings)
+	assert.Len(t, report.Fingerprints(), 3)
+
+	other := filepath.Join(dir, "other.json")
+	require.NoError(t, os.WriteFile(other, []byte(`{"schema_version": "2", "findings": []}`), 0600))
+	_, err = ReadFindingsFile(other)
+	assert.ErrorContains(t, err, `schema version "2"`)
+
+	_, err = ReadFindingsFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
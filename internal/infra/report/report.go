@@ -0,0 +1,216 @@
+// Package report renders scan results as shareable reports.
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Options controls how a report is built.
+type Options struct {
+	// Title is shown in the page heading
+	Title string
+	// SourceURL is prepended to file paths to link findings to their source, e.g.
+	// https://github.com/org/repo/blob/main/; lines are appended as #L<n>. When empty,
+	// findings link to the files relative to the report.
+	SourceURL string
+	// ReportDir is the directory the report is written to, used for relative links
+	ReportDir string
+	// GeneratedAt is the report timestamp (defaults to now)
+	GeneratedAt time.Time
+}
+
+// Report is the data behind a rendered report.
+type Report struct {
+	Title       string
+	GeneratedAt time.Time
+
+	TotalFiles      int
+	FilesWithEmojis int
+	TotalEmojis     int
+	Errors          int
+
+	// Files lists files with findings or errors, most emojis first
+	Files []FileReport
+	// Frequency counts occurrences per emoji, most frequent first
+	Frequency []Count
+	// Categories counts occurrences per detection category, most frequent first
+	Categories []Count
+}
+
+// FileReport is the drill-down for a single file.
+type FileReport struct {
+	Path     string
+	Error    string
+	Findings []Finding
+}
+
+// Finding is a single emoji occurrence.
+type Finding struct {
+	Emoji    string
+	Category string
+	Line     int
+	Column   int
+	Link     string
+}
+
+// Count is a labelled tally with its share of the largest tally, for bar charts.
+type Count struct {
+	Label   string
+	Count   int
+	Percent int
+}
+
+// Build aggregates scan results into a report.
+func Build(results []types.ProcessResult, opts Options) Report {
+	report := Report{
+		Title:       opts.Title,
+		GeneratedAt: opts.GeneratedAt,
+		TotalFiles:  len(results),
+	}
+	if report.Title == "" {
+		report.Title = "Antimoji scan report"
+	}
+	if report.GeneratedAt.IsZero() {
+		report.GeneratedAt = time.Now()
+	}
+
+	frequency := make(map[string]int)
+	categories := make(map[string]int)
+	for _, result := range results {
+		if result.Error != nil {
+			report.Errors++
+			report.Files = append(report.Files, FileReport{Path: result.FilePath, Error: result.Error.Error()})
+			continue
+		}
+		if len(result.DetectionResult.Emojis) == 0 {
+			continue
+		}
+
+		report.FilesWithEmojis++
+		file := FileReport{Path: result.FilePath}
+		for _, match := range result.DetectionResult.Emojis {
+			frequency[match.Emoji]++
+			categories[string(match.Category)]++
+			file.Findings = append(file.Findings, Finding{
+				Emoji:    match.Emoji,
+				Category: string(match.Category),
+				Line:     match.Line,
+				Column:   match.Column,
+				Link:     sourceLink(result.FilePath, match.Line, opts),
+			})
+		}
+		report.TotalEmojis += len(file.Findings)
+		report.Files = append(report.Files, file)
+	}
+
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		if len(report.Files[i].Findings) != len(report.Files[j].Findings) {
+			return len(report.Files[i].Findings) > len(report.Files[j].Findings)
+		}
+		return report.Files[i].Path < report.Files[j].Path
+	})
+	report.Frequency = tally(frequency)
+	report.Categories = tally(categories)
+
+	return report
+}
+
+// WriteHTML renders report as a self-contained HTML page.
+func WriteHTML(w io.Writer, report Report) error {
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
+
+// WriteHTMLFile renders report to path.
+func WriteHTMLFile(path string, report Report) error {
+	file, err := os.Create(path) // #nosec G304 - report path is user-provided by design
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if err := WriteHTML(file, report); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// sourceLink returns the link for a finding at line of filePath.
+func sourceLink(filePath string, line int, opts Options) string {
+	fragment := "#L" + strconv.Itoa(line)
+
+	if opts.SourceURL != "" {
+		rel := filepath.ToSlash(filepath.Clean(filePath))
+		rel = strings.TrimPrefix(rel, "./")
+		return strings.TrimSuffix(opts.SourceURL, "/") + "/" + escapePath(rel) + fragment
+	}
+
+	target := filePath
+	if abs, err := filepath.Abs(filePath); err == nil {
+		if dir, err := filepath.Abs(opts.ReportDir); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				target = rel
+			}
+		}
+	}
+	return escapePath(filepath.ToSlash(target)) + fragment
+}
+
+// escapePath percent-encodes each segment of a slash-separated path.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return path.Join(segments...)
+}
+
+// tally converts counts to a slice sorted by count, then label.
+func tally(counts map[string]int) []Count {
+	tallies := make([]Count, 0, len(counts))
+	max := 0
+	for label, count := range counts {
+		tallies = append(tallies, Count{Label: label, Count: count})
+		if count > max {
+			max = count
+		}
+	}
+
+	sort.Slice(tallies, func(i, j int) bool {
+		if tallies[i].Count != tallies[j].Count {
+			return tallies[i].Count > tallies[j].Count
+		}
+		return tallies[i].Label < tallies[j].Label
+	})
+	for i := range tallies {
+		tallies[i].Percent = tallies[i].Count * 100 / max
+	}
+	return tallies
+}
+
+//go:embed report.html.tmpl
+var htmlSource string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"plural": func(n int, word string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, word)
+		}
+		return fmt.Sprintf("%d %ss", n, word)
+	},
+}).Parse(htmlSource))
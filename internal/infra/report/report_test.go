@@ -0,0 +1,90 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResults() []types.ProcessResult {
+	return []types.ProcessResult{
+		{FilePath: "src/a.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{
+			{Emoji: "🚀", Line: 3, Column: 4, Category: types.CategoryUnicode},
+		}}},
+		{FilePath: "src/b.md", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{
+			{Emoji: "🚀", Line: 1, Column: 1, Category: types.CategoryUnicode},
+			{Emoji: ":)", Line: 2, Column: 5, Category: types.CategoryEmoticon},
+		}}},
+		{FilePath: "src/clean.go"},
+		{FilePath: "src/broken.bin", Error: errors.New("permission denied")},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	report := Build(testResults(), Options{SourceURL: "https://example.com/repo/blob/main/"})
+
+	assert.Equal(t, 4, report.TotalFiles)
+	assert.Equal(t, 2, report.FilesWithEmojis)
+	assert.Equal(t, 3, report.TotalEmojis)
+	assert.Equal(t, 1, report.Errors)
+
+	// Most findings first, errors last
+	require.Len(t, report.Files, 3)
+	assert.Equal(t, "src/b.md", report.Files[0].Path)
+	assert.Equal(t, "src/a.go", report.Files[1].Path)
+	assert.Equal(t, "permission denied", report.Files[2].Error)
+
+	assert.Equal(t, []Count{{Label: "🚀", Count: 2, Percent: 100}, {Label: ":)", Count: 1, Percent: 50}}, report.Frequency)
+	assert.Equal(t, []Count{{Label: "unicode", Count: 2, Percent: 100}, {Label: "emoticon", Count: 1, Percent: 50}}, report.Categories)
+	assert.Equal(t, "https://example.com/repo/blob/main/src/a.go#L3", report.Files[1].Findings[0].Link)
+}
+
+func TestSourceLink(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "docs", "read me.md")
+
+	assert.Equal(t, "docs/read%20me.md#L7", sourceLink(file, 7, Options{ReportDir: dir}))
+	assert.Equal(t, "../docs/read%20me.md#L7", sourceLink(file, 7, Options{ReportDir: filepath.Join(dir, "out")}))
+	assert.Equal(t, "https://host/x/docs/a.go#L1", sourceLink("./docs/a.go", 1, Options{SourceURL: "https://host/x"}))
+}
+
+func TestWriteHTML(t *testing.T) {
+	report := Build(testResults(), Options{
+		Title:       "Nightly <audit>",
+		GeneratedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+
+	var out bytes.Buffer
+	require.NoError(t, WriteHTML(&out, report))
+	html := out.String()
+
+	assert.Contains(t, html, "<title>Nightly &lt;audit&gt;</title>")
+	assert.Contains(t, html, "2025-01-02 03:04:05 UTC")
+	assert.Contains(t, html, "src/b.md")
+	assert.Contains(t, html, "2 emojis")
+	assert.Contains(t, html, "1 emoji<")
+	assert.Contains(t, html, "permission denied")
+	assert.Contains(t, html, "width: 50%")
+	assert.NotContains(t, html, "<script")
+
+	t.Run("empty", func(t *testing.T) {
+		out.Reset()
+		require.NoError(t, WriteHTML(&out, Build(nil, Options{})))
+		assert.Contains(t, out.String(), "No emojis found.")
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.html")
+		require.NoError(t, WriteHTMLFile(path, report))
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, html, string(content))
+	})
+}
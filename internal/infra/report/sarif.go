@@ -0,0 +1,140 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// SARIFVersion is the version of the SARIF format written, the one read by GitHub code
+// scanning.
+const SARIFVersion = "2.1.0"
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFLog is a SARIF log with the single run of a scan.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is the run of antimoji and its results.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes antimoji and the one rule its results refer to.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component that produced the results.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is a reporting descriptor.
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+// SARIFMessage is a plain text message.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a finding.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+	// PartialFingerprints holds the Code Climate fingerprint, which code scanning uses
+	// to track the finding across runs
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+// SARIFLocation is the physical location of a result.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a file and a region in it.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+// SARIFArtifactLocation is the path of a file relative to the repository root.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is the line and column of a result, both 1-based.
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SARIF converts the findings in results to a SARIF log, in result order. Paths are made
+// relative to the working directory as in Code Climate reports, and results with an
+// error are skipped.
+func SARIF(results []types.ProcessResult) SARIFLog {
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           FindingsTool,
+			InformationURI: "https://github.com/antimoji/antimoji",
+			Rules: []SARIFRule{{
+				ID:               CodeClimateCheckName,
+				ShortDescription: SARIFMessage{Text: "Emoji found in source file"},
+			}},
+		}},
+		Results: []SARIFResult{},
+	}
+	eachFinding(results, func(path string, match types.EmojiMatch, text string, occurrence int) {
+		run.Results = append(run.Results, SARIFResult{
+			RuleID:  CodeClimateCheckName,
+			Level:   "warning",
+			Message: SARIFMessage{Text: fmt.Sprintf("Emoji %s (%s) found", match.Display(), match.Category)},
+			Locations: []SARIFLocation{{PhysicalLocation: SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: path},
+				Region:           SARIFRegion{StartLine: match.Line, StartColumn: match.Column},
+			}}},
+			PartialFingerprints: map[string]string{"antimoji/v1": fingerprint(path, match.Emoji, text, occurrence)},
+		})
+	})
+	return SARIFLog{Schema: sarifSchema, Version: SARIFVersion, Runs: []SARIFRun{run}}
+}
+
+// WriteSARIF writes the SARIF log for results.
+func WriteSARIF(w io.Writer, results []types.ProcessResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(SARIF(results)); err != nil {
+		return fmt.Errorf("failed to write SARIF report: %w", err)
+	}
+	return nil
+}
+
+// WriteSARIFFile writes the SARIF log for results to path.
+func WriteSARIFFile(path string, results []types.ProcessResult) error {
+	file, err := os.Create(path) // #nosec G304 - report path is user-provided by design
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if err := WriteSARIF(file, results); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSARIF(t *testing.T) {
+	log := SARIF(testResults())
+
+	assert.Equal(t, SARIFVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "antimoji", run.Tool.Driver.Name)
+	assert.Equal(t, CodeClimateCheckName, run.Tool.Driver.Rules[0].ID)
+
+	require.Len(t, run.Results, 3, "errors are not results")
+	result := run.Results[0]
+	assert.Equal(t, "Emoji 🚀 (unicode) found", result.Message.Text)
+	assert.Equal(t, SARIFPhysicalLocation{
+		ArtifactLocation: SARIFArtifactLocation{URI: "src/a.go"},
+		Region:           SARIFRegion{StartLine: 3, StartColumn: 4},
+	}, result.Locations[0].PhysicalLocation)
+	assert.Equal(t, CodeClimate(testResults())[0].Fingerprint, result.PartialFingerprints["antimoji/v1"])
+
+	assert.Equal(t, []SARIFResult{}, SARIF(nil).Runs[0].Results)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, testResults()))
+
+	var log map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log["version"])
+	assert.Contains(t, buf.String(), `"$schema": "https://json.schemastore.org/sarif-2.1.0.json"`)
+	assert.Contains(t, buf.String(), `"text": "Emoji :) (emoticon) found"`)
+}
@@ -0,0 +1,269 @@
+// Package undo records the files each clean run modified, with their backups, so the
+// whole run can be reverted at once.
+package undo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDir holds the manifests of clean runs, relative to the working directory.
+const DefaultDir = ".antimoji/undo"
+
+var (
+	// ErrRunNotFound indicates no manifest was recorded for the run.
+	ErrRunNotFound = errors.New("clean run not found")
+	// ErrAlreadyUndone indicates the run was already reverted.
+	ErrAlreadyUndone = errors.New("clean run already undone")
+	// ErrModifiedSinceRun indicates files changed after the run cleaned them, so
+	// restoring them would lose those changes.
+	ErrModifiedSinceRun = errors.New("files changed since the clean run")
+)
+
+// runIDPattern matches the IDs NewRunID returns, keeping run IDs from naming paths
+// outside the manifest directory.
+var runIDPattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z-]*$`)
+
+// Manifest records the files a clean run modified.
+type Manifest struct {
+	RunID string    `json:"run_id"`
+	Time  time.Time `json:"time"`
+	Files []File    `json:"files"`
+	// UndoneAt is when the run was reverted
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+}
+
+// File is a file modified by a clean run.
+type File struct {
+	// Path is the absolute path of the file
+	Path string `json:"path"`
+	// Backup is the absolute path of the copy made before the file was cleaned
+	Backup string `json:"backup"`
+	// OriginalHash and CleanedHash are the SHA-256 of the content before and after
+	// the run
+	OriginalHash string `json:"original_sha256"`
+	CleanedHash  string `json:"cleaned_sha256"`
+}
+
+// NewRunID returns a new run ID for a run started at t, e.g. 20260301-120000-4f2a.
+func NewRunID(t time.Time) string {
+	suffix := make([]byte, 2)
+	_, _ = rand.Read(suffix)
+	return t.Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// NewFile records that the file at path was cleaned after being backed up to backup.
+func NewFile(path, backup string) (File, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return File{}, err
+	}
+	absBackup, err := filepath.Abs(backup)
+	if err != nil {
+		return File{}, err
+	}
+	original, err := HashFile(absBackup)
+	if err != nil {
+		return File{}, err
+	}
+	cleaned, err := HashFile(abs)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: abs, Backup: absBackup, OriginalHash: original, CleanedHash: cleaned}, nil
+}
+
+// HashFile returns the hex SHA-256 of the content of the file at path.
+func HashFile(path string) (string, error) {
+	content, err := os.ReadFile(path) // #nosec G304 - files recorded by clean
+	if err != nil {
+		return "", err
+	}
+	return hashContent(content), nil
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Record writes the manifest to <dir>/<run-id>.json, creating dir when missing.
+func Record(dir string, manifest Manifest) error {
+	if !runIDPattern.MatchString(manifest.RunID) {
+		return fmt.Errorf("invalid run ID %q", manifest.RunID)
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create undo directory: %w", err)
+	}
+	return writeAtomic(manifestPath(dir, manifest.RunID), append(content, '\n'), 0600)
+}
+
+// Load reads the manifest of the run runID from dir.
+func Load(dir, runID string) (Manifest, error) {
+	if !runIDPattern.MatchString(runID) {
+		return Manifest{}, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	content, err := os.ReadFile(manifestPath(dir, runID)) // #nosec G304 - run ID validated above
+	if errors.Is(err, fs.ErrNotExist) {
+		return Manifest{}, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("invalid manifest for run %s: %w", runID, err)
+	}
+	return manifest, nil
+}
+
+// List returns the manifests recorded in dir, oldest first. A missing dir has none.
+func List(dir string) ([]Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []Manifest
+	for _, entry := range entries {
+		runID, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		manifest, err := Load(dir, runID)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+	sort.SliceStable(manifests, func(i, j int) bool { return manifests[i].Time.Before(manifests[j].Time) })
+	return manifests, nil
+}
+
+// Undo restores every file of the run runID from its backup, or none of them. Files
+// changed since the run fail with ErrModifiedSinceRun unless force is set. The manifest
+// is kept, marked as undone at t.
+func Undo(dir, runID string, force bool, t time.Time) (Manifest, error) {
+	manifest, err := Load(dir, runID)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if manifest.UndoneAt != nil {
+		return manifest, fmt.Errorf("%w: %s on %s", ErrAlreadyUndone, runID, manifest.UndoneAt.Format(time.RFC3339))
+	}
+
+	// Check everything before changing anything
+	originals := make([][]byte, len(manifest.Files))
+	currents := make([][]byte, len(manifest.Files))
+	var modified []string
+	for i, file := range manifest.Files {
+		original, err := os.ReadFile(file.Backup)
+		if err != nil {
+			return manifest, fmt.Errorf("backup of %s: %w", file.Path, err)
+		}
+		if hashContent(original) != file.OriginalHash {
+			return manifest, fmt.Errorf("backup %s changed since the clean run", file.Backup)
+		}
+		originals[i] = original
+
+		current, err := os.ReadFile(file.Path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return manifest, err
+		}
+		if err != nil || hashContent(current) != file.CleanedHash {
+			modified = append(modified, file.Path)
+		}
+		currents[i] = current
+	}
+	if len(modified) > 0 && !force {
+		return manifest, fmt.Errorf("%w: %s (use --force to restore them anyway)", ErrModifiedSinceRun, strings.Join(modified, ", "))
+	}
+
+	for i, file := range manifest.Files {
+		if err := writeAtomic(file.Path, originals[i], fileMode(file.Path, file.Backup)); err != nil {
+			rollback(manifest.Files[:i], currents)
+			return manifest, fmt.Errorf("failed to restore %s: %w", file.Path, err)
+		}
+	}
+
+	undoneAt := t.UTC()
+	manifest.UndoneAt = &undoneAt
+	if err := Record(dir, manifest); err != nil {
+		return manifest, fmt.Errorf("files restored, but the manifest could not be updated: %w", err)
+	}
+	return manifest, nil
+}
+
+// rollback puts back the content the restored files had before Undo.
+func rollback(restored []File, contents [][]byte) {
+	for i, file := range restored {
+		if contents[i] == nil {
+			_ = os.Remove(file.Path)
+			continue
+		}
+		_ = writeAtomic(file.Path, contents[i], fileMode(file.Path, file.Backup))
+	}
+}
+
+// fileMode returns the permissions of the file at path, or of its backup when it no
+// longer exists.
+func fileMode(path, backup string) os.FileMode {
+	for _, candidate := range []string{path, backup} {
+		if info, err := os.Stat(candidate); err == nil {
+			return info.Mode().Perm()
+		}
+	}
+	return 0644
+}
+
+// writeAtomic replaces the file at path with content through a temporary file in the
+// same directory.
+func writeAtomic(path string, content []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".antimoji-undo-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// manifestPath returns the manifest file of the run runID in dir.
+func manifestPath(dir, runID string) string {
+	return filepath.Join(dir, runID+".json")
+}
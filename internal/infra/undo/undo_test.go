@@ -0,0 +1,132 @@
+package undo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cleanedFile writes a cleaned file and its backup in a temp dir and records them.
+func cleanedFile(t *testing.T, dir, name string) File {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path+".bak", []byte("launch 🚀\n"), 0644))
+	require.NoError(t, os.WriteFile(path, []byte("launch \n"), 0644))
+	file, err := NewFile(path, path+".bak")
+	require.NoError(t, err)
+	return file
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestRecordLoadList(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "undo")
+	files := t.TempDir()
+	later := Manifest{RunID: "20260302-090000-bbbb", Time: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), Files: []File{cleanedFile(t, files, "a.go")}}
+	earlier := Manifest{RunID: "20260301-090000-aaaa", Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), Files: []File{cleanedFile(t, files, "b.go")}}
+	require.NoError(t, Record(dir, later))
+	require.NoError(t, Record(dir, earlier))
+
+	loaded, err := Load(dir, later.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, later, loaded)
+
+	manifests, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	assert.Equal(t, earlier.RunID, manifests[0].RunID)
+	assert.Equal(t, later.RunID, manifests[1].RunID)
+
+	t.Run("missing directory has no runs", func(t *testing.T) {
+		manifests, err := List(filepath.Join(t.TempDir(), "missing"))
+		require.NoError(t, err)
+		assert.Empty(t, manifests)
+	})
+
+	t.Run("run IDs cannot name other paths", func(t *testing.T) {
+		_, err := Load(dir, "../undo/"+later.RunID)
+		assert.ErrorIs(t, err, ErrRunNotFound)
+		assert.Error(t, Record(dir, Manifest{RunID: "../escape"}))
+	})
+}
+
+func TestNewRunID(t *testing.T) {
+	id := NewRunID(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	assert.Regexp(t, `^20260301-120000-[0-9a-f]{4}$`, id)
+	assert.Regexp(t, runIDPattern, id)
+}
+
+func TestUndo(t *testing.T) {
+	now := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+	setup := func(t *testing.T) (string, Manifest) {
+		dir := filepath.Join(t.TempDir(), "undo")
+		files := t.TempDir()
+		manifest := Manifest{RunID: "20260301-120000-abcd", Time: now.Add(-time.Hour),
+			Files: []File{cleanedFile(t, files, "a.go"), cleanedFile(t, files, "b.go")}}
+		require.NoError(t, Record(dir, manifest))
+		return dir, manifest
+	}
+
+	t.Run("restores every file and marks the run", func(t *testing.T) {
+		dir, manifest := setup(t)
+		undone, err := Undo(dir, manifest.RunID, false, now)
+		require.NoError(t, err)
+		for _, file := range manifest.Files {
+			assert.Equal(t, "launch 🚀\n", readFile(t, file.Path))
+		}
+		require.NotNil(t, undone.UndoneAt)
+		assert.Equal(t, now, *undone.UndoneAt)
+
+		_, err = Undo(dir, manifest.RunID, false, now)
+		assert.ErrorIs(t, err, ErrAlreadyUndone)
+	})
+
+	t.Run("refuses files changed since the run unless forced", func(t *testing.T) {
+		dir, manifest := setup(t)
+		require.NoError(t, os.WriteFile(manifest.Files[1].Path, []byte("edited\n"), 0644))
+
+		_, err := Undo(dir, manifest.RunID, false, now)
+		assert.ErrorIs(t, err, ErrModifiedSinceRun)
+		assert.ErrorContains(t, err, manifest.Files[1].Path)
+		assert.Equal(t, "launch \n", readFile(t, manifest.Files[0].Path))
+
+		_, err = Undo(dir, manifest.RunID, true, now)
+		require.NoError(t, err)
+		assert.Equal(t, "launch 🚀\n", readFile(t, manifest.Files[1].Path))
+	})
+
+	t.Run("restores nothing when a backup is missing", func(t *testing.T) {
+		dir, manifest := setup(t)
+		require.NoError(t, os.Remove(manifest.Files[1].Backup))
+
+		_, err := Undo(dir, manifest.RunID, false, now)
+		require.Error(t, err)
+		assert.Equal(t, "launch \n", readFile(t, manifest.Files[0].Path))
+
+		loaded, err := Load(dir, manifest.RunID)
+		require.NoError(t, err)
+		assert.Nil(t, loaded.UndoneAt)
+	})
+
+	t.Run("restores nothing when a backup changed", func(t *testing.T) {
+		dir, manifest := setup(t)
+		require.NoError(t, os.WriteFile(manifest.Files[1].Backup, []byte("other\n"), 0644))
+
+		_, err := Undo(dir, manifest.RunID, true, now)
+		assert.ErrorContains(t, err, "changed since the clean run")
+		assert.Equal(t, "launch \n", readFile(t, manifest.Files[0].Path))
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		dir, _ := setup(t)
+		_, err := Undo(dir, "20200101-000000-0000", false, now)
+		assert.ErrorIs(t, err, ErrRunNotFound)
+	})
+}
@@ -0,0 +1,174 @@
+// Package lang identifies the language of files from their name or shebang line, and
+// describes the comments, strings, escapes and code fences of each language.
+package lang
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Registry maps file extensions, file names and shebang interpreters to languages.
+type Registry struct {
+	languages     []types.Language
+	byName        map[string]int
+	byExtension   map[string]int
+	byFilename    map[string]int
+	byInterpreter map[string]int
+}
+
+// NewRegistry returns a registry holding languages, registered in order.
+func NewRegistry(languages ...types.Language) *Registry {
+	r := &Registry{
+		byName:        make(map[string]int),
+		byExtension:   make(map[string]int),
+		byFilename:    make(map[string]int),
+		byInterpreter: make(map[string]int),
+	}
+	for _, language := range languages {
+		r.Register(language)
+	}
+	return r
+}
+
+// Default returns a new registry holding the built-in languages.
+func Default() *Registry {
+	return NewRegistry(builtin...)
+}
+
+// Register adds language to the registry. A language with the name of a registered one
+// replaces it, and its extensions, file names and interpreters take precedence over
+// those of other languages.
+func (r *Registry) Register(language types.Language) {
+	index, ok := r.byName[language.Name]
+	if ok {
+		r.languages[index] = language
+		for _, keys := range []map[string]int{r.byExtension, r.byFilename, r.byInterpreter} {
+			for key, i := range keys {
+				if i == index {
+					delete(keys, key)
+				}
+			}
+		}
+	} else {
+		index = len(r.languages)
+		r.languages = append(r.languages, language)
+		r.byName[language.Name] = index
+	}
+
+	for _, extension := range language.Extensions {
+		r.byExtension[strings.ToLower(extension)] = index
+	}
+	for _, filename := range language.Filenames {
+		r.byFilename[filename] = index
+	}
+	for _, interpreter := range language.Interpreters {
+		r.byInterpreter[interpreter] = index
+	}
+}
+
+// With returns a copy of the registry with languages registered as by Register.
+func (r *Registry) With(languages ...types.Language) *Registry {
+	clone := NewRegistry(r.languages...)
+	for _, language := range languages {
+		clone.Register(language)
+	}
+	return clone
+}
+
+// Language returns the registered language called name.
+func (r *Registry) Language(name string) (types.Language, bool) {
+	index, ok := r.byName[name]
+	if !ok {
+		return types.Language{}, false
+	}
+	return r.languages[index], true
+}
+
+// Languages returns the registered languages sorted by name.
+func (r *Registry) Languages() []types.Language {
+	languages := append([]types.Language(nil), r.languages...)
+	sort.Slice(languages, func(i, j int) bool { return languages[i].Name < languages[j].Name })
+	return languages
+}
+
+// Lookup returns the language of the file at filePath: by its whole file name, then by
+// its extension, then by the interpreter on the shebang line of content.
+func (r *Registry) Lookup(filePath string, content []byte) (types.Language, bool) {
+	base := filepath.Base(filePath)
+	if index, ok := r.byFilename[base]; ok {
+		return r.languages[index], true
+	}
+	if index, ok := r.byExtension[strings.ToLower(filepath.Ext(base))]; ok {
+		return r.languages[index], true
+	}
+
+	interpreter := Interpreter(content)
+	if interpreter == "" {
+		return types.Language{}, false
+	}
+	index, ok := r.byInterpreter[interpreter]
+	if !ok {
+		// python3.12 is python
+		index, ok = r.byInterpreter[strings.TrimRight(interpreter, "0123456789.")]
+	}
+	if !ok {
+		return types.Language{}, false
+	}
+	return r.languages[index], true
+}
+
+// Interpreter returns the name of the interpreter on the shebang line at the start of
+// content, e.g. python3 for "#!/usr/bin/env python3", or "" without a shebang.
+func Interpreter(content []byte) string {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return ""
+	}
+	line := content[2:]
+	if end := bytes.IndexByte(line, '\n'); end != -1 {
+		line = line[:end]
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter != "env" {
+		return interpreter
+	}
+	// env takes options, such as -S, before the command
+	for _, field := range fields[1:] {
+		if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+			return path.Base(field)
+		}
+	}
+	return ""
+}
+
+// escapeNames are the configuration names of the escape syntaxes.
+var escapeNames = map[string]types.EscapeSyntax{
+	"html_entity":    types.EscapeHTMLEntity,
+	"utf16":          types.EscapeUTF16,
+	"unicode_braces": types.EscapeUnicodeBraces,
+	"unicode_long":   types.EscapeUnicodeLong,
+}
+
+// ParseEscapes returns the escape syntaxes named by names: html_entity, utf16,
+// unicode_braces and unicode_long.
+func ParseEscapes(names []string) (types.EscapeSyntax, error) {
+	var escapes types.EscapeSyntax
+	for _, name := range names {
+		syntax, ok := escapeNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown escape syntax %q (must be html_entity, utf16, unicode_braces or unicode_long)", name)
+		}
+		escapes |= syntax
+	}
+	return escapes, nil
+}
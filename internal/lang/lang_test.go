@@ -0,0 +1,118 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Lookup(t *testing.T) {
+	registry := Default()
+	name := func(filePath, content string) string {
+		language, _ := registry.Lookup(filePath, []byte(content))
+		return language.Name
+	}
+
+	assert.Equal(t, "go", name("cmd/main.go", ""))
+	assert.Equal(t, "markdown", name("docs/Guide.MARKDOWN", ""))
+	assert.Equal(t, "mdx", name("page.mdx", ""))
+	assert.Equal(t, "makefile", name("build/Makefile", ""))
+	assert.Equal(t, "ruby", name("Gemfile", ""))
+	assert.Equal(t, "python", name("bin/tool", "#!/usr/bin/env python3\nprint()\n"))
+	assert.Equal(t, "python", name("bin/tool", "#!/usr/bin/python3.12"))
+	assert.Equal(t, "shell", name("bin/run", "#!/bin/bash -e\n"))
+	assert.Equal(t, "javascript", name("bin/cli", "#!/usr/bin/env -S node --no-warnings\n"))
+	// The extension wins over the shebang
+	assert.Equal(t, "go", name("gen.go", "#!/usr/bin/env python3\n"))
+	assert.Equal(t, "", name("notes.txt", ""))
+	assert.Equal(t, "", name("md", ""))
+	assert.Equal(t, "", name("bin/tool", "#!/usr/bin/env awk\n"))
+}
+
+func TestRegistry_LanguageProperties(t *testing.T) {
+	registry := Default()
+	lookup := func(filePath string) types.Language {
+		language, _ := registry.Lookup(filePath, nil)
+		return language
+	}
+
+	assert.True(t, lookup("README.md").CodeFences)
+	assert.False(t, lookup("main.go").CodeFences)
+
+	assert.Equal(t, types.EscapeUTF16, lookup("package.json").Escapes)
+	assert.Equal(t, types.EscapeHTMLEntity, lookup("site/Index.HTML").Escapes)
+	assert.Equal(t, types.EscapeUTF16|types.EscapeUnicodeBraces, lookup("app.ts").Escapes)
+	assert.Zero(t, lookup("Makefile").Escapes)
+
+	assert.Equal(t, []string{"#"}, lookup("script.py").Comments.Line)
+	assert.Equal(t, []types.BlockComment{{Start: "<!--", End: "-->"}}, lookup("README.md").Comments.Block)
+	assert.True(t, lookup("data.json").Comments.IsZero())
+
+	for _, language := range registry.Languages() {
+		assert.NotEmpty(t, language.Name)
+		assert.True(t, len(language.Extensions)+len(language.Filenames)+len(language.Interpreters) > 0, language.Name)
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	base := Default()
+	registry := base.With(
+		types.Language{Name: "hcl", Extensions: []string{".tf", ".HCL"}, Comments: types.CommentSyntax{Line: []string{"#", "//"}}},
+		// Replaces the built-in language, dropping its other extensions
+		types.Language{Name: "python", Extensions: []string{".py"}, Interpreters: []string{"python"}, Comments: types.CommentSyntax{Line: []string{"#"}}},
+		// Takes .h from C
+		types.Language{Name: "objc", Extensions: []string{".m", ".h"}},
+	)
+
+	language, ok := registry.Lookup("main.tf", nil)
+	require.True(t, ok)
+	assert.Equal(t, "hcl", language.Name)
+	_, ok = registry.Lookup("config.hcl", nil)
+	assert.True(t, ok)
+
+	_, ok = registry.Lookup("stubs.pyi", nil)
+	assert.False(t, ok)
+	language, _ = registry.Lookup("tool.py", nil)
+	assert.Zero(t, language.Escapes)
+
+	language, _ = registry.Lookup("defs.h", nil)
+	assert.Equal(t, "objc", language.Name)
+	language, _ = registry.Lookup("main.c", nil)
+	assert.Equal(t, "c", language.Name)
+
+	// The original registry is unchanged
+	_, ok = base.Lookup("main.tf", nil)
+	assert.False(t, ok)
+	language, _ = base.Lookup("defs.h", nil)
+	assert.Equal(t, "c", language.Name)
+
+	python, ok := registry.Language("python")
+	require.True(t, ok)
+	assert.Equal(t, []string{".py"}, python.Extensions)
+	_, ok = registry.Language("cobol")
+	assert.False(t, ok)
+}
+
+func TestInterpreter(t *testing.T) {
+	assert.Equal(t, "python3", Interpreter([]byte("#!/usr/bin/env python3\n")))
+	assert.Equal(t, "node", Interpreter([]byte("#!/usr/bin/env -S NODE_ENV=test node\n")))
+	assert.Equal(t, "sh", Interpreter([]byte("#!/bin/sh")))
+	assert.Equal(t, "", Interpreter([]byte("#!\n")))
+	assert.Equal(t, "", Interpreter([]byte("# comment\n")))
+	assert.Equal(t, "", Interpreter(nil))
+}
+
+func TestParseEscapes(t *testing.T) {
+	escapes, err := ParseEscapes([]string{"html_entity", "unicode_long"})
+	require.NoError(t, err)
+	assert.Equal(t, types.EscapeHTMLEntity|types.EscapeUnicodeLong, escapes)
+
+	escapes, err = ParseEscapes(nil)
+	require.NoError(t, err)
+	assert.Zero(t, escapes)
+
+	_, err = ParseEscapes([]string{"octal"})
+	assert.ErrorContains(t, err, `unknown escape syntax "octal"`)
+}
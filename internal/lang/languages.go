@@ -0,0 +1,116 @@
+package lang
+
+import "github.com/antimoji/antimoji/internal/types"
+
+// Common comment and string syntaxes.
+var (
+	cComments    = types.CommentSyntax{Line: []string{"//"}, Block: []types.BlockComment{{Start: "/*", End: "*/"}}}
+	hashComments = types.CommentSyntax{Line: []string{"#"}}
+	xmlComments  = types.CommentSyntax{Block: []types.BlockComment{{Start: "<!--", End: "-->"}}}
+	// webComments are those of files mixing markup and script, such as Vue components
+	webComments = types.CommentSyntax{Line: []string{"//"}, Block: []types.BlockComment{{Start: "/*", End: "*/"}, {Start: "<!--", End: "-->"}}}
+
+	doubleQuotes = []string{`"`}
+	quotes       = []string{`"`, "'"}
+	jsQuotes     = []string{`"`, "'", "`"}
+)
+
+// Escape syntaxes shared by several languages.
+const (
+	// cEscapes are the escapes of C-family string literals
+	cEscapes = types.EscapeUTF16 | types.EscapeUnicodeLong
+	// jsEscapes are the escapes of JavaScript and TypeScript string literals
+	jsEscapes = types.EscapeUTF16 | types.EscapeUnicodeBraces
+)
+
+// builtin lists the languages known without configuration.
+var builtin = []types.Language{
+	// Markup
+	{Name: "markdown", Extensions: []string{".md", ".markdown"}, Comments: xmlComments,
+		Escapes: types.EscapeHTMLEntity, CodeFences: true},
+	{Name: "mdx", Extensions: []string{".mdx"}, Comments: webComments,
+		Escapes: types.EscapeHTMLEntity | jsEscapes, CodeFences: true},
+	{Name: "html", Extensions: []string{".html", ".htm", ".xhtml"}, Comments: xmlComments,
+		StringDelimiters: quotes, Escapes: types.EscapeHTMLEntity},
+	{Name: "xml", Extensions: []string{".xml", ".svg"}, Comments: xmlComments,
+		StringDelimiters: quotes, Escapes: types.EscapeHTMLEntity},
+	{Name: "vue", Extensions: []string{".vue"}, Comments: webComments,
+		StringDelimiters: jsQuotes, Escapes: types.EscapeHTMLEntity | jsEscapes},
+	{Name: "svelte", Extensions: []string{".svelte"}, Comments: webComments,
+		StringDelimiters: jsQuotes, Escapes: types.EscapeHTMLEntity | jsEscapes},
+	{Name: "erb", Extensions: []string{".erb"}, Comments: xmlComments,
+		StringDelimiters: quotes, Escapes: types.EscapeHTMLEntity | jsEscapes},
+	{Name: "php", Extensions: []string{".php"}, Interpreters: []string{"php"},
+		Comments:         types.CommentSyntax{Line: []string{"//", "#"}, Block: []types.BlockComment{{Start: "/*", End: "*/"}}},
+		StringDelimiters: quotes, Escapes: types.EscapeHTMLEntity | types.EscapeUnicodeBraces},
+	{Name: "css", Extensions: []string{".css"}, Comments: types.CommentSyntax{Block: cComments.Block},
+		StringDelimiters: quotes},
+	{Name: "scss", Extensions: []string{".scss", ".less"}, Comments: cComments, StringDelimiters: quotes},
+
+	// JavaScript and JSON
+	{Name: "javascript", Extensions: []string{".js", ".mjs", ".cjs"}, Interpreters: []string{"node", "nodejs"},
+		Comments: cComments, StringDelimiters: jsQuotes, Escapes: jsEscapes},
+	{Name: "jsx", Extensions: []string{".jsx"}, Comments: webComments,
+		StringDelimiters: jsQuotes, Escapes: types.EscapeHTMLEntity | jsEscapes},
+	{Name: "typescript", Extensions: []string{".ts", ".mts", ".cts"}, Interpreters: []string{"ts-node"},
+		Comments: cComments, StringDelimiters: jsQuotes, Escapes: jsEscapes},
+	{Name: "tsx", Extensions: []string{".tsx"}, Comments: webComments,
+		StringDelimiters: jsQuotes, Escapes: types.EscapeHTMLEntity | jsEscapes},
+	// JSON has no comments, so suppression markers count anywhere in it
+	{Name: "json", Extensions: []string{".json"}, StringDelimiters: doubleQuotes, Escapes: types.EscapeUTF16},
+	{Name: "jsonc", Extensions: []string{".jsonc", ".json5"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: types.EscapeUTF16},
+
+	// JVM and .NET
+	{Name: "java", Extensions: []string{".java"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: types.EscapeUTF16},
+	{Name: "kotlin", Extensions: []string{".kt", ".kts"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: types.EscapeUTF16},
+	{Name: "scala", Extensions: []string{".scala"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: types.EscapeUTF16},
+	{Name: "dart", Extensions: []string{".dart"}, Interpreters: []string{"dart"}, Comments: cComments,
+		StringDelimiters: quotes, Escapes: jsEscapes},
+	{Name: "csharp", Extensions: []string{".cs"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: cEscapes},
+
+	// Languages with braced escapes
+	{Name: "rust", Extensions: []string{".rs"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: types.EscapeUnicodeBraces},
+	{Name: "swift", Extensions: []string{".swift"}, Interpreters: []string{"swift"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: types.EscapeUnicodeBraces},
+	{Name: "ruby", Extensions: []string{".rb"}, Filenames: []string{"Gemfile", "Rakefile"}, Interpreters: []string{"ruby"},
+		Comments:         types.CommentSyntax{Line: []string{"#"}, Block: []types.BlockComment{{Start: "=begin", End: "=end"}}},
+		StringDelimiters: quotes, Escapes: jsEscapes},
+	{Name: "lua", Extensions: []string{".lua"}, Interpreters: []string{"lua"},
+		Comments:         types.CommentSyntax{Line: []string{"--"}, Block: []types.BlockComment{{Start: "--[[", End: "]]"}}},
+		StringDelimiters: quotes, Escapes: types.EscapeUnicodeBraces},
+
+	// C family, Go and Python
+	{Name: "c", Extensions: []string{".c", ".h"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: cEscapes},
+	{Name: "cpp", Extensions: []string{".cc", ".cpp", ".cxx", ".hh", ".hpp"}, Comments: cComments,
+		StringDelimiters: doubleQuotes, Escapes: cEscapes},
+	{Name: "go", Extensions: []string{".go"}, Comments: cComments,
+		StringDelimiters: []string{`"`, "`"}, Escapes: cEscapes},
+	{Name: "python", Extensions: []string{".py", ".pyi"}, Interpreters: []string{"python"},
+		Comments: hashComments, StringDelimiters: quotes, Escapes: cEscapes},
+
+	// Scripts and build files
+	{Name: "shell", Extensions: []string{".sh", ".bash", ".zsh"},
+		Interpreters: []string{"sh", "bash", "zsh", "dash", "ksh"}, Comments: hashComments, StringDelimiters: quotes},
+	{Name: "perl", Extensions: []string{".pl", ".pm"}, Interpreters: []string{"perl"},
+		Comments: hashComments, StringDelimiters: quotes},
+	{Name: "makefile", Extensions: []string{".mk"}, Filenames: []string{"Makefile", "makefile", "GNUmakefile"},
+		Comments: hashComments},
+	{Name: "dockerfile", Extensions: []string{".dockerfile"}, Filenames: []string{"Dockerfile", "Containerfile"},
+		Comments: hashComments, StringDelimiters: doubleQuotes},
+	{Name: "sql", Extensions: []string{".sql"},
+		Comments:         types.CommentSyntax{Line: []string{"--"}, Block: cComments.Block},
+		StringDelimiters: []string{"'"}},
+
+	// Configuration
+	{Name: "toml", Extensions: []string{".toml"}, Comments: hashComments,
+		StringDelimiters: quotes, Escapes: cEscapes},
+	{Name: "yaml", Extensions: []string{".yaml", ".yml"}, Comments: hashComments,
+		StringDelimiters: quotes, Escapes: cEscapes},
+}
@@ -3,6 +3,10 @@ package context
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
 	"time"
 )
 
@@ -22,8 +26,21 @@ const (
 	UserIDKey ContextKey = "user_id"
 	// SessionIDKey holds session identification
 	SessionIDKey ContextKey = "session_id"
+	// CorrelationIDKey holds the correlation ID of the file being processed
+	CorrelationIDKey ContextKey = "correlation_id"
 )
 
+// runID makes correlation IDs unique to this process, so that files with the same
+// path in different runs can be told apart in collected logs.
+var runID = newRunID()
+
+// newRunID returns a random identifier for this process.
+func newRunID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // WithOperation adds an operation name to the context.
 func WithOperation(ctx context.Context, operation string) context.Context {
 	return context.WithValue(ctx, OperationKey, operation)
@@ -39,6 +56,20 @@ func WithFilePath(ctx context.Context, filePath string) context.Context {
 	return context.WithValue(ctx, FilePathKey, filePath)
 }
 
+// WithCorrelationID adds a correlation ID to the context, overriding the one derived
+// from its file path.
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, CorrelationIDKey, correlationID)
+}
+
+// FileCorrelationID returns the correlation ID of filePath for this process. The ID
+// is derived from the path rather than stored, so discovery, detection and
+// modification log the same ID for a file without passing it along.
+func FileCorrelationID(filePath string) string {
+	sum := sha256.Sum256([]byte(runID + "\x00" + filepath.Clean(filePath)))
+	return hex.EncodeToString(sum[:6])
+}
+
 // WithRequestID adds a request ID to the context.
 func WithRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, RequestIDKey, requestID)
@@ -78,6 +109,18 @@ func GetFilePath(ctx context.Context) string {
 	return ""
 }
 
+// GetCorrelationID retrieves the correlation ID from context, which defaults to the
+// correlation ID of the file path in the context.
+func GetCorrelationID(ctx context.Context) string {
+	if id, ok := ctx.Value(CorrelationIDKey).(string); ok {
+		return id
+	}
+	if path := GetFilePath(ctx); path != "" {
+		return FileCorrelationID(path)
+	}
+	return ""
+}
+
 // GetRequestID retrieves the request ID from context.
 func GetRequestID(ctx context.Context) string {
 	if id, ok := ctx.Value(RequestIDKey).(string); ok {
@@ -102,6 +145,10 @@ func ExtractContextFields(ctx context.Context) []interface{} {
 		fields = append(fields, "file_path", path)
 	}
 
+	if id := GetCorrelationID(ctx); id != "" {
+		fields = append(fields, "correlation_id", id)
+	}
+
 	if reqID := GetRequestID(ctx); reqID != "" {
 		fields = append(fields, "request_id", reqID)
 	}
@@ -64,18 +64,37 @@ func TestExtractContextFields(t *testing.T) {
 
 	fields := ExtractContextFields(ctx)
 
-	// Should have 8 fields (4 keys + 4 values)
-	assert.Len(t, fields, 8)
+	// Should have 10 fields (5 keys + 5 values), the file path adding its correlation ID
+	assert.Len(t, fields, 10)
 	assert.Contains(t, fields, "operation")
 	assert.Contains(t, fields, "test-op")
 	assert.Contains(t, fields, "component")
 	assert.Contains(t, fields, "test-comp")
 	assert.Contains(t, fields, "file_path")
 	assert.Contains(t, fields, "/test/file")
+	assert.Contains(t, fields, "correlation_id")
+	assert.Contains(t, fields, FileCorrelationID("/test/file"))
 	assert.Contains(t, fields, "request_id")
 	assert.Contains(t, fields, "req-456")
 }
 
+func TestFileCorrelationID(t *testing.T) {
+	id := FileCorrelationID("src/main.go")
+	assert.Len(t, id, 12)
+	assert.Equal(t, id, FileCorrelationID("./src/main.go"), "equivalent paths share an ID")
+	assert.NotEqual(t, id, FileCorrelationID("src/other.go"))
+}
+
+func TestGetCorrelationID(t *testing.T) {
+	assert.Equal(t, "", GetCorrelationID(context.Background()))
+
+	ctx := WithFilePath(context.Background(), "src/main.go")
+	assert.Equal(t, FileCorrelationID("src/main.go"), GetCorrelationID(ctx))
+
+	ctx = WithCorrelationID(ctx, "explicit")
+	assert.Equal(t, "explicit", GetCorrelationID(ctx))
+}
+
 func TestExtractContextFields_EmptyContext(t *testing.T) {
 	ctx := context.Background()
 
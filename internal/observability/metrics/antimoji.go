@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the per-file processing histogram.
+var latencyBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// Metrics are the application metrics reported by antimoji. All methods are safe on a
+// nil receiver, so commands can record unconditionally.
+type Metrics struct {
+	registry *Registry
+
+	filesScanned    *Counter
+	emojisFound     *Counter
+	scanErrors      *Counter
+	cleanOperations *Counter
+	cacheHits       *Counter
+	cacheMisses     *Counter
+	fileLatency     *Histogram
+}
+
+// New creates the application metrics in a fresh registry.
+func New() *Metrics {
+	r := NewRegistry()
+	m := &Metrics{
+		registry:        r,
+		filesScanned:    r.NewCounter("antimoji_files_scanned_total", "Files processed by scan and clean."),
+		emojisFound:     r.NewCounter("antimoji_emojis_found_total", "Emojis detected in processed files."),
+		scanErrors:      r.NewCounter("antimoji_file_errors_total", "Files that could not be processed."),
+		cleanOperations: r.NewCounter("antimoji_clean_operations_total", "Files modified by clean."),
+		cacheHits:       r.NewCounter("antimoji_cache_hits_total", "Result cache lookups that reused a cached result."),
+		cacheMisses:     r.NewCounter("antimoji_cache_misses_total", "Result cache lookups that required processing."),
+		fileLatency:     r.NewHistogram("antimoji_file_processing_seconds", "Time spent detecting emojis in a single file.", latencyBuckets),
+	}
+	r.NewGaugeFunc("antimoji_cache_hit_ratio", "Share of result cache lookups served from the cache.", func() float64 {
+		hits, misses := m.cacheHits.Value(), m.cacheMisses.Value()
+		if hits+misses == 0 {
+			return 0
+		}
+		return float64(hits) / float64(hits+misses)
+	})
+	return m
+}
+
+// Registry returns the registry holding the metrics.
+func (m *Metrics) Registry() *Registry {
+	if m == nil {
+		return nil
+	}
+	return m.registry
+}
+
+// ObserveResults records processed files, detected emojis and per-file latency.
+func (m *Metrics) ObserveResults(results []types.ProcessResult) {
+	if m == nil {
+		return
+	}
+	for _, result := range results {
+		m.filesScanned.Inc()
+		if result.Error != nil {
+			m.scanErrors.Inc()
+			continue
+		}
+		m.emojisFound.Add(uint64(result.DetectionResult.TotalCount)) // #nosec G115 - counts are never negative
+		m.fileLatency.Observe(result.DetectionResult.Duration.Seconds())
+	}
+}
+
+// ObserveClean records a clean run over files, of which modified were rewritten and
+// failed could not be processed.
+func (m *Metrics) ObserveClean(files, modified, failed, emojisRemoved int) {
+	if m == nil {
+		return
+	}
+	m.filesScanned.Add(uint64(files))        // #nosec G115 - counts are never negative
+	m.cleanOperations.Add(uint64(modified))  // #nosec G115 - counts are never negative
+	m.scanErrors.Add(uint64(failed))         // #nosec G115 - counts are never negative
+	m.emojisFound.Add(uint64(emojisRemoved)) // #nosec G115 - counts are never negative
+}
+
+// ObserveEmojis records emojis detected in content that is not a file, such as the
+// lines of a tailed log.
+func (m *Metrics) ObserveEmojis(count int) {
+	if m == nil {
+		return
+	}
+	m.emojisFound.Add(uint64(count)) // #nosec G115 - counts are never negative
+}
+
+// ObserveCache records result cache hits and misses.
+func (m *Metrics) ObserveCache(hits, misses int) {
+	if m == nil {
+		return
+	}
+	m.cacheHits.Add(uint64(hits))     // #nosec G115 - counts are never negative
+	m.cacheMisses.Add(uint64(misses)) // #nosec G115 - counts are never negative
+}
+
+// Server serves a registry over HTTP at /metrics.
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// Serve starts serving registry on addr (e.g. ":9090") in the background.
+func Serve(addr string, registry *Registry) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+
+	s := &Server{
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		listener: listener,
+	}
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	return s, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops the server, waiting for in-flight scrapes until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
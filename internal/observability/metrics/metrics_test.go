@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	counter := r.NewCounter("test_events_total", "Events seen.\nSecond line.")
+	counter.Add(3)
+	counter.Inc()
+	histogram := r.NewHistogram("test_latency_seconds", "Latency.", []float64{1, 0.5})
+	histogram.Observe(0.25)
+	histogram.Observe(0.75)
+	histogram.Observe(2)
+	r.NewGaugeFunc("test_ratio", "Ratio.", func() float64 { return 0.5 })
+
+	var out bytes.Buffer
+	require.NoError(t, r.WriteText(&out))
+
+	assert.Equal(t, `# HELP test_events_total Events seen.\nSecond line.
+# TYPE test_events_total counter
+test_events_total 4
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.5"} 1
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 3
+test_latency_seconds_count 3
+# HELP test_ratio Ratio.
+# TYPE test_ratio gauge
+test_ratio 0.5
+`, out.String())
+
+	assert.Panics(t, func() { r.NewCounter("test_events_total", "again") })
+}
+
+func TestMetrics_Observe(t *testing.T) {
+	m := New()
+	m.ObserveResults([]types.ProcessResult{
+		{FilePath: "a.go", DetectionResult: types.DetectionResult{TotalCount: 2, Duration: time.Millisecond}},
+		{FilePath: "b.go", Error: io.ErrUnexpectedEOF},
+	})
+	m.ObserveClean(3, 1, 0, 4)
+	m.ObserveCache(3, 1)
+	m.ObserveEmojis(2)
+
+	assert.Equal(t, uint64(5), m.filesScanned.Value())
+	assert.Equal(t, uint64(8), m.emojisFound.Value())
+	assert.Equal(t, uint64(1), m.scanErrors.Value())
+	assert.Equal(t, uint64(1), m.cleanOperations.Value())
+	assert.Equal(t, uint64(1), m.fileLatency.Count())
+
+	var out bytes.Buffer
+	require.NoError(t, m.Registry().WriteText(&out))
+	assert.Contains(t, out.String(), "antimoji_cache_hit_ratio 0.75\n")
+
+	t.Run("nil metrics are a no-op", func(t *testing.T) {
+		var none *Metrics
+		assert.NotPanics(t, func() {
+			none.ObserveResults([]types.ProcessResult{{FilePath: "a.go"}})
+			none.ObserveClean(1, 1, 0, 1)
+			none.ObserveCache(1, 0)
+			none.ObserveEmojis(1)
+		})
+		assert.Nil(t, none.Registry())
+	})
+}
+
+func TestServe(t *testing.T) {
+	m := New()
+	m.ObserveCache(1, 0)
+
+	server, err := Serve("127.0.0.1:0", m.Registry())
+	require.NoError(t, err)
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	resp, err := http.Get("http://" + server.Addr() + "/metrics")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ContentType, resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "antimoji_cache_hits_total 1\n")
+
+	_, err = Serve(server.Addr(), m.Registry())
+	assert.Error(t, err, "address already in use")
+}
@@ -0,0 +1,265 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/infra/httpclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// exporter sends spans to an OTLP/HTTP collector using the JSON encoding.
+type exporter struct {
+	url    string
+	client *http.Client
+}
+
+// newExporter creates an exporter posting to url through the proxies of the environment
+// and trusting the certificate authorities of $ANTIMOJI_CA_FILE.
+func newExporter(url string) (*exporter, error) {
+	client, err := httpclient.New(httpclient.CAFile(""), 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP client: %w", err)
+	}
+	return &exporter{url: url, client: client}, nil
+}
+
+// ExportSpans sends a batch of spans to the collector.
+func (e *exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(encodeSpans(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to export spans: collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Shutdown releases the exporter; there is nothing to flush.
+func (e *exporter) Shutdown(context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// The types below mirror the OTLP JSON encoding of ExportTraceServiceRequest.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string     `json:"stringValue,omitempty"`
+	BoolValue   *bool       `json:"boolValue,omitempty"`
+	IntValue    *string     `json:"intValue,omitempty"`
+	DoubleValue *float64    `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpValues `json:"arrayValue,omitempty"`
+}
+
+type otlpValues struct {
+	Values []otlpValue `json:"values"`
+}
+
+// encodeSpans groups spans by resource and instrumentation scope.
+func encodeSpans(spans []sdktrace.ReadOnlySpan) otlpRequest {
+	var request otlpRequest
+	resources := make(map[attribute.Distinct]int)
+	scopes := make(map[attribute.Distinct]map[instrumentation.Scope]int)
+
+	for _, span := range spans {
+		key := span.Resource().Equivalent()
+		ri, ok := resources[key]
+		if !ok {
+			ri = len(request.ResourceSpans)
+			resources[key] = ri
+			scopes[key] = make(map[instrumentation.Scope]int)
+			request.ResourceSpans = append(request.ResourceSpans, otlpResourceSpans{
+				Resource: otlpResource{Attributes: encodeAttributes(span.Resource().Attributes())},
+			})
+		}
+
+		scope := span.InstrumentationScope()
+		scope.Attributes = attribute.Set{} // not comparable across spans; unused by Antimoji
+		si, ok := scopes[key][scope]
+		if !ok {
+			si = len(request.ResourceSpans[ri].ScopeSpans)
+			scopes[key][scope] = si
+			request.ResourceSpans[ri].ScopeSpans = append(request.ResourceSpans[ri].ScopeSpans, otlpScopeSpans{
+				Scope: otlpScope{Name: scope.Name, Version: scope.Version},
+			})
+		}
+
+		scopeSpans := &request.ResourceSpans[ri].ScopeSpans[si]
+		scopeSpans.Spans = append(scopeSpans.Spans, encodeSpan(span))
+	}
+
+	return request
+}
+
+// encodeSpan converts a finished span to its OTLP form.
+func encodeSpan(span sdktrace.ReadOnlySpan) otlpSpan {
+	encoded := otlpSpan{
+		TraceID:           span.SpanContext().TraceID().String(),
+		SpanID:            span.SpanContext().SpanID().String(),
+		Name:              span.Name(),
+		Kind:              int(span.SpanKind()),
+		StartTimeUnixNano: unixNano(span.StartTime()),
+		EndTimeUnixNano:   unixNano(span.EndTime()),
+		Attributes:        encodeAttributes(span.Attributes()),
+	}
+	if span.Parent().IsValid() {
+		encoded.ParentSpanID = span.Parent().SpanID().String()
+	}
+
+	for _, event := range span.Events() {
+		encoded.Events = append(encoded.Events, otlpEvent{
+			TimeUnixNano: unixNano(event.Time),
+			Name:         event.Name,
+			Attributes:   encodeAttributes(event.Attributes),
+		})
+	}
+
+	// OTLP numbers status codes differently from the Go API
+	switch span.Status().Code {
+	case codes.Ok:
+		encoded.Status = otlpStatus{Code: 1}
+	case codes.Error:
+		encoded.Status = otlpStatus{Code: 2, Message: span.Status().Description}
+	}
+
+	return encoded
+}
+
+// encodeAttributes converts attributes to OTLP key/value pairs.
+func encodeAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	encoded := make([]otlpKeyValue, len(attrs))
+	for i, attr := range attrs {
+		encoded[i] = otlpKeyValue{Key: string(attr.Key), Value: encodeValue(attr.Value)}
+	}
+	return encoded
+}
+
+// encodeValue converts an attribute value to its OTLP form.
+func encodeValue(v attribute.Value) otlpValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return otlpValue{DoubleValue: &f}
+	case attribute.STRINGSLICE:
+		values := make([]otlpValue, 0, len(v.AsStringSlice()))
+		for _, s := range v.AsStringSlice() {
+			values = append(values, encodeValue(attribute.StringValue(s)))
+		}
+		return otlpValue{ArrayValue: &otlpValues{Values: values}}
+	case attribute.BOOLSLICE:
+		values := make([]otlpValue, 0, len(v.AsBoolSlice()))
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, encodeValue(attribute.BoolValue(b)))
+		}
+		return otlpValue{ArrayValue: &otlpValues{Values: values}}
+	case attribute.INT64SLICE:
+		values := make([]otlpValue, 0, len(v.AsInt64Slice()))
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, encodeValue(attribute.Int64Value(i)))
+		}
+		return otlpValue{ArrayValue: &otlpValues{Values: values}}
+	case attribute.FLOAT64SLICE:
+		values := make([]otlpValue, 0, len(v.AsFloat64Slice()))
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, encodeValue(attribute.Float64Value(f)))
+		}
+		return otlpValue{ArrayValue: &otlpValues{Values: values}}
+	default:
+		s := v.Emit()
+		return otlpValue{StringValue: &s}
+	}
+}
+
+// unixNano formats t as OTLP JSON expects 64-bit integers: a decimal string.
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,98 @@
+// Package tracing provides OpenTelemetry tracing for the Antimoji processing pipeline.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of Antimoji spans.
+const tracerName = "github.com/antimoji/antimoji"
+
+// EndpointEnv is the standard OpenTelemetry variable used when no endpoint flag is given.
+const EndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+// Config holds the tracing configuration.
+type Config struct {
+	// Endpoint is the OTLP/HTTP collector, e.g. http://localhost:4318. Spans are sent
+	// to <Endpoint>/v1/traces unless the endpoint already names that path.
+	Endpoint string
+	// ServiceName is recorded on the trace resource
+	ServiceName string
+	// ServiceVersion is recorded on the trace resource
+	ServiceVersion string
+}
+
+// Setup installs a global tracer provider exporting to cfg.Endpoint and returns a
+// function that flushes and stops it. Without an endpoint tracing stays disabled and
+// spans cost next to nothing.
+func Setup(cfg Config) (shutdown func(context.Context) error, err error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	endpoint, err := tracesURL(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := newExporter(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracesURL resolves the OTLP/HTTP traces URL for endpoint.
+func tracesURL(endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("invalid OTLP endpoint %q", endpoint)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid OTLP endpoint %q: scheme must be http or https", endpoint)
+	}
+
+	if !strings.HasSuffix(parsed.Path, "/v1/traces") {
+		parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/v1/traces"
+	}
+	return parsed.String(), nil
+}
@@ -0,0 +1,162 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/infra/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracesURL(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"http://localhost:4318":            "http://localhost:4318/v1/traces",
+		"localhost:4318":                   "http://localhost:4318/v1/traces",
+		"https://otel.example.com/prefix/": "https://otel.example.com/prefix/v1/traces",
+		"http://collector/v1/traces":       "http://collector/v1/traces",
+	} {
+		got, err := tracesURL(endpoint)
+		require.NoError(t, err, endpoint)
+		assert.Equal(t, want, got)
+	}
+
+	for _, endpoint := range []string{"ftp://collector", "http://"} {
+		_, err := tracesURL(endpoint)
+		assert.Error(t, err, endpoint)
+	}
+}
+
+func TestSetup_ExportsSpans(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []otlpRequest
+		paths    []string
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request otlpRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		mu.Lock()
+		requests = append(requests, request)
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	shutdown, err := Setup(Config{Endpoint: collector.URL, ServiceName: "antimoji", ServiceVersion: "test"})
+	require.NoError(t, err)
+
+	ctx, root := Start(context.Background(), "scan", attribute.StringSlice("antimoji.paths", []string{"."}))
+	_, child := Start(ctx, "detection", attribute.Int("antimoji.files", 3))
+	child.AddEvent("slow file", trace.WithAttributes(attribute.String("antimoji.file", "big.go")))
+	child.End()
+	End(root, errors.New("threshold exceeded"))
+
+	require.NoError(t, shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requests, 1)
+	assert.Equal(t, []string{"/v1/traces"}, paths)
+
+	resourceSpans := requests[0].ResourceSpans
+	require.Len(t, resourceSpans, 1)
+	assert.Contains(t, resourceSpans[0].Resource.Attributes, otlpKeyValue{Key: "service.name", Value: encodeValue(attribute.StringValue("antimoji"))})
+
+	require.Len(t, resourceSpans[0].ScopeSpans, 1)
+	assert.Equal(t, tracerName, resourceSpans[0].ScopeSpans[0].Scope.Name)
+
+	spans := map[string]otlpSpan{}
+	for _, span := range resourceSpans[0].ScopeSpans[0].Spans {
+		spans[span.Name] = span
+	}
+	require.Len(t, spans, 2)
+
+	scan, detection := spans["scan"], spans["detection"]
+	assert.Equal(t, scan.TraceID, detection.TraceID)
+	assert.Equal(t, scan.SpanID, detection.ParentSpanID)
+	assert.Empty(t, scan.ParentSpanID)
+	assert.Equal(t, otlpStatus{Code: 2, Message: "threshold exceeded"}, scan.Status)
+	assert.Equal(t, "3", *detection.Attributes[0].Value.IntValue)
+	require.Len(t, detection.Events, 1)
+	assert.Equal(t, "slow file", detection.Events[0].Name)
+}
+
+func TestSetup_CAFile(t *testing.T) {
+	received := make(chan struct{}, 1)
+	collector := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+	}))
+	defer collector.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: collector.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, cert, 0600))
+	t.Setenv(httpclient.EnvCAFile, caFile)
+
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	shutdown, err := Setup(Config{Endpoint: collector.URL, ServiceName: "antimoji"})
+	require.NoError(t, err)
+	_, span := Start(context.Background(), "scan")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+	select {
+	case <-received:
+	default:
+		t.Fatal("the collector received no spans")
+	}
+
+	t.Run("a missing CA file is an error", func(t *testing.T) {
+		t.Setenv(httpclient.EnvCAFile, filepath.Join(t.TempDir(), "missing.pem"))
+		_, err := Setup(Config{Endpoint: collector.URL})
+		assert.Error(t, err)
+	})
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(Config{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(Config{Endpoint: "ftp://collector"})
+	assert.Error(t, err)
+}
+
+func TestEncodeValue(t *testing.T) {
+	encoded, err := json.Marshal([]otlpValue{
+		encodeValue(attribute.BoolValue(true)),
+		encodeValue(attribute.Float64Value(1.5)),
+		encodeValue(attribute.StringSliceValue([]string{"a", "b"})),
+		encodeValue(attribute.Int64SliceValue([]int64{7})),
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"boolValue": true},
+		{"doubleValue": 1.5},
+		{"arrayValue": {"values": [{"stringValue": "a"}, {"stringValue": "b"}]}},
+		{"arrayValue": {"values": [{"intValue": "7"}]}}
+	]`, string(encoded))
+}
@@ -0,0 +1,268 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+	"github.com/antimoji/antimoji/internal/infra/report"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Budget fields of the profile, as named in BudgetViolation.
+const (
+	BudgetTotal     = "max_total"
+	BudgetNew       = "max_new"
+	BudgetPerFile   = "max_per_file"
+	BudgetDirectory = "directory_thresholds"
+	BudgetEmoji     = "emoji_thresholds"
+	BudgetOwner     = "owner_thresholds"
+	BudgetEvasion   = "evasion_threshold"
+	BudgetPolicy    = "policy"
+	BudgetExemption = "exemptions"
+	BudgetRule      = "custom_rules"
+)
+
+// BudgetViolation is a total, new, per-file, per-directory, per-owner or per-emoji
+// threshold or a custom rule that a set of results exceeds, or an expired exemption
+// that still covers some of them.
+type BudgetViolation struct {
+	// Budget is the profile field setting the limit
+	Budget string `json:"budget"`
+	// Scope is the file, directory, owner or emoji the limit applies to, the category
+	// for evasion_threshold, the name of a custom rule, or empty for max_total and
+	// max_new
+	Scope string `json:"scope"`
+	Found int    `json:"found"`
+	Limit int    `json:"limit"`
+	// Expires and Reason are those of an expired exemption; Reason is also the message
+	// of a custom rule
+	Expires string `json:"expires,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// String describes the violation, e.g. "max_per_file: main.go has 4 emojis (limit 3)".
+func (v BudgetViolation) String() string {
+	switch v.Budget {
+	case BudgetExemption:
+		return fmt.Sprintf("exemption expired on %s: %s has %d emojis (reason: %s)", v.Expires, v.Scope, v.Found, v.Reason)
+	case BudgetTotal:
+		return fmt.Sprintf("%s: %d emojis (limit %d)", v.Budget, v.Found, v.Limit)
+	case BudgetNew:
+		return fmt.Sprintf("%s: %d emojis not in the baseline (limit %d)", v.Budget, v.Found, v.Limit)
+	case BudgetEmoji:
+		return fmt.Sprintf("%s: %s found %d times (limit %d)", v.Budget, v.Scope, v.Found, v.Limit)
+	case BudgetEvasion:
+		return fmt.Sprintf("%s: %d evasion findings (limit %d)", v.Budget, v.Found, v.Limit)
+	case BudgetRule:
+		description := fmt.Sprintf("%s: %s matched %d emojis (limit %d)", v.Budget, v.Scope, v.Found, v.Limit)
+		if v.Reason != "" {
+			description += ": " + v.Reason
+		}
+		return description
+	default:
+		return fmt.Sprintf("%s: %s has %d emojis (limit %d)", v.Budget, v.Scope, v.Found, v.Limit)
+	}
+}
+
+// Budgets checks the error-level violations in results against the profile's max_new
+// when there is a baseline, its per-file, per-directory, per-owner, per-emoji and evasion
+// thresholds and the organization policy's path rules, and returns those exceeded:
+// violations outside the baseline, then files in result order, then
+// directories, owners and emojis sorted, then evasion findings, then rules in policy
+// order, then the custom rules and the expired exemptions that still cover violations
+// in profile order. Directory thresholds are keyed by paths relative to the working
+// directory; "." covers every file. Owner thresholds are keyed by CODEOWNERS owners,
+// and Unowned covers the files without one. Custom rules see warnings too.
+func (e *Engine) Budgets(results []types.ProcessResult) []BudgetViolation {
+	var exceeded []BudgetViolation
+	if e.opts.Baseline != nil {
+		if found := e.newViolations(results); found > e.profile.MaxNew {
+			exceeded = append(exceeded, BudgetViolation{Budget: BudgetNew, Found: found, Limit: e.profile.MaxNew})
+		}
+	}
+
+	ruleCounts := make([]int, len(e.opts.Rules))
+	customCounts := make([]ruleCount, len(e.rules))
+	expiredCounts := make([]int, len(e.exemptions))
+	dirCounts := make(map[string]int, len(e.profile.DirectoryThresholds))
+	ownerCounts := make(map[string]int, len(e.profile.OwnerThresholds))
+	emojiCounts := make(map[string]int, len(e.profile.EmojiThresholds))
+	evasions := 0
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		violations := e.FileViolations(result.FilePath, result.DetectionResult.Emojis)
+		if len(e.rules) > 0 && len(violations) > 0 {
+			e.countRules(customCounts, result.FilePath, violations)
+		}
+		violations = e.withoutWarnings(violations)
+		if len(violations) == 0 {
+			continue
+		}
+
+		if e.profile.MaxPerFile > 0 && len(violations) > e.profile.MaxPerFile {
+			exceeded = append(exceeded, BudgetViolation{
+				Budget: BudgetPerFile,
+				Scope:  result.FilePath,
+				Found:  len(violations),
+				Limit:  e.profile.MaxPerFile,
+			})
+		}
+
+		file := budgetPath(result.FilePath)
+		e.expiredCounts(expiredCounts, file, violations)
+		for i, rule := range e.opts.Rules {
+			if matchesRule(rule, file) {
+				ruleCounts[i] += len(violations)
+			}
+		}
+		for dir := range e.profile.DirectoryThresholds {
+			if withinBudgetDir(budgetPath(dir), file) {
+				dirCounts[dir] += len(violations)
+			}
+		}
+		if len(e.profile.OwnerThresholds) > 0 {
+			for _, owner := range e.fileOwners(result.FilePath) {
+				if _, ok := e.profile.OwnerThresholds[owner]; ok {
+					ownerCounts[owner] += len(violations)
+				}
+			}
+		}
+		for _, match := range violations {
+			if _, ok := e.profile.EmojiThresholds[match.Emoji]; ok {
+				emojiCounts[match.Emoji]++
+			}
+			if match.Category == types.CategoryEvasion {
+				evasions++
+			}
+		}
+	}
+
+	exceeded = append(exceeded, overBudget(BudgetDirectory, dirCounts, e.profile.DirectoryThresholds)...)
+	exceeded = append(exceeded, overBudget(BudgetOwner, ownerCounts, e.profile.OwnerThresholds)...)
+	exceeded = append(exceeded, overBudget(BudgetEmoji, emojiCounts, e.profile.EmojiThresholds)...)
+	if e.profile.EvasionDetection && evasions > e.profile.EvasionThreshold {
+		exceeded = append(exceeded, BudgetViolation{
+			Budget: BudgetEvasion,
+			Scope:  string(types.CategoryEvasion),
+			Found:  evasions,
+			Limit:  e.profile.EvasionThreshold,
+		})
+	}
+	for i, rule := range e.opts.Rules {
+		if ruleCounts[i] > rule.MaxEmojis {
+			exceeded = append(exceeded, BudgetViolation{
+				Budget: BudgetPolicy,
+				Scope:  strings.Join(rule.Paths, ", "),
+				Found:  ruleCounts[i],
+				Limit:  rule.MaxEmojis,
+			})
+		}
+	}
+	exceeded = append(exceeded, e.exceededRules(customCounts)...)
+	for i, x := range e.exemptions {
+		if expiredCounts[i] > 0 {
+			exceeded = append(exceeded, BudgetViolation{
+				Budget:  BudgetExemption,
+				Scope:   x.String(),
+				Found:   expiredCounts[i],
+				Expires: x.Expires,
+				Reason:  x.Reason,
+			})
+		}
+	}
+	return exceeded
+}
+
+// newViolations counts the error-level violations in results whose findings-json
+// fingerprint is not in the baseline.
+func (e *Engine) newViolations(results []types.ProcessResult) int {
+	violating := make([]types.ProcessResult, 0, len(results))
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		result.DetectionResult.Emojis = e.withoutWarnings(e.FileViolations(result.FilePath, result.DetectionResult.Emojis))
+		violating = append(violating, result)
+	}
+
+	found := 0
+	for _, finding := range report.Findings(violating, time.Time{}).Findings {
+		if !e.opts.Baseline[finding.Fingerprint] {
+			found++
+		}
+	}
+	return found
+}
+
+// matchesRule reports whether file matches one of the rule's path patterns.
+func matchesRule(rule config.PolicyRule, file string) bool {
+	for _, pattern := range rule.Paths {
+		if matchesPath(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPath reports whether file matches pattern. Patterns without a slash, such as
+// *.go, match the file name in any directory.
+func matchesPath(pattern, file string) bool {
+	return pathmatch.Match(pattern, file) || (!strings.Contains(pattern, "/") && pathmatch.Match(pattern, path.Base(file)))
+}
+
+// BudgetError returns an error wrapping ErrThresholdExceeded that lists exceeded, or nil
+// when it is empty.
+func BudgetError(exceeded []BudgetViolation) error {
+	if len(exceeded) == 0 {
+		return nil
+	}
+	descriptions := make([]string, len(exceeded))
+	for i, violation := range exceeded {
+		descriptions[i] = violation.String()
+	}
+	return fmt.Errorf("%w: %s", ErrThresholdExceeded, strings.Join(descriptions, "; "))
+}
+
+// overBudget returns the scopes whose count is over their limit, sorted by scope.
+func overBudget(budget string, counts, limits map[string]int) []BudgetViolation {
+	scopes := make([]string, 0, len(counts))
+	for scope, count := range counts {
+		if count > limits[scope] {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+
+	exceeded := make([]BudgetViolation, 0, len(scopes))
+	for _, scope := range scopes {
+		exceeded = append(exceeded, BudgetViolation{Budget: budget, Scope: scope, Found: counts[scope], Limit: limits[scope]})
+	}
+	return exceeded
+}
+
+// budgetPath returns p as a clean slash-separated path, relative to the working
+// directory when p is an absolute path inside it.
+func budgetPath(p string) string {
+	if filepath.IsAbs(p) {
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, p); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				p = rel
+			}
+		}
+	}
+	return path.Clean(filepath.ToSlash(p))
+}
+
+// withinBudgetDir reports whether file is below dir.
+func withinBudgetDir(dir, file string) bool {
+	return dir == "." || strings.HasPrefix(file, dir+"/")
+}
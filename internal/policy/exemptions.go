@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// now returns the current time; tests replace it to expire exemptions.
+var now = time.Now
+
+// exemption is an exemption of the profile, with its state when the engine was created.
+type exemption struct {
+	config.Exemption
+	// emoji matches the exempted emoji with or without variation selectors; nil exempts
+	// every emoji
+	emoji   *allowlist.Allowlist
+	expired bool
+}
+
+// newExemptions prepares the exemptions of profile, expiring those whose date has
+// passed at t. Exemptions without a valid date are expired; config validation reports
+// them.
+func newExemptions(profile config.Profile, t time.Time) []exemption {
+	exemptions := make([]exemption, 0, len(profile.Exemptions))
+	for _, x := range profile.Exemptions {
+		expiresAt, err := x.ExpiresAt()
+		prepared := exemption{Exemption: x, expired: err != nil || !t.Before(expiresAt)}
+		if x.Emoji != "" {
+			prepared.emoji = allowlist.NewAllowlist([]string{x.Emoji}).Unwrap()
+		}
+		exemptions = append(exemptions, prepared)
+	}
+	return exemptions
+}
+
+// covers reports whether the exemption applies to match in file, a path as returned by
+// budgetPath, whether or not it has expired.
+func (x exemption) covers(file string, match types.EmojiMatch) bool {
+	if x.Line > 0 && match.Line != x.Line {
+		return false
+	}
+	if x.emoji != nil && !x.emoji.IsAllowed(match.Emoji) {
+		return false
+	}
+	return matchesPath(x.Path, file)
+}
+
+// exempted reports whether an exemption that has not expired allows match in file.
+func (e *Engine) exempted(file string, match types.EmojiMatch) bool {
+	for _, x := range e.exemptions {
+		if !x.expired && x.covers(file, match) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileViolations returns the matches in the file at path that count against the
+// policy of the profile the file is routed to: those the allowlist does not allow and
+// no current exemption covers.
+func (e *Engine) FileViolations(path string, matches []types.EmojiMatch) []types.EmojiMatch {
+	if routed := e.For(path); routed != e {
+		return routed.FileViolations(path, matches)
+	}
+	violations := e.Violations(matches)
+	if len(e.exemptions) == 0 {
+		return violations
+	}
+
+	file := budgetPath(path)
+	kept := violations[:0]
+	for _, match := range violations {
+		if !e.exempted(file, match) {
+			kept = append(kept, match)
+		}
+	}
+	return kept
+}
+
+// expiredCounts counts the violations in file covered by each expired exemption,
+// indexed like the exemptions of the profile.
+func (e *Engine) expiredCounts(counts []int, file string, violations []types.EmojiMatch) {
+	for i, x := range e.exemptions {
+		if !x.expired {
+			continue
+		}
+		for _, match := range violations {
+			if x.covers(file, match) {
+				counts[i]++
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_Exemptions(t *testing.T) {
+	original := now
+	now = func() time.Time { return time.Date(2026, 3, 31, 18, 0, 0, 0, time.Local) }
+	defer func() { now = original }()
+
+	match := func(emoji string, line int) types.EmojiMatch {
+		return types.EmojiMatch{Emoji: emoji, Line: line, Category: types.CategoryUnicode}
+	}
+	results := []types.ProcessResult{
+		{FilePath: "docs/legacy/guide.md", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("🚀", 1), match("🎉", 2)}}},
+		{FilePath: "cmd/main.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("✅", 3), match("✅", 7)}}},
+		{FilePath: "internal/old.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("😂", 1)}}},
+	}
+
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.Exemptions = []config.Exemption{
+		// Still applies on its last day
+		{Path: "docs/legacy/**", Expires: "2026-03-31", Reason: "docs migration"},
+		{Path: "main.go", Emoji: "✅", Line: 3, Expires: "2027-01-01", Reason: "release banner"},
+		{Path: "internal/**", Expires: "2026-03-30", Reason: "refactor"},
+		{Path: "*.py", Expires: "2025-12-31", Reason: "python port"},
+	}
+	engine := newEngine(t, profile, Options{})
+
+	t.Run("allows findings until expiry", func(t *testing.T) {
+		applied := engine.Apply(results)
+		require.Len(t, applied, 3)
+		assert.Empty(t, applied[0].DetectionResult.Emojis)
+		assert.Equal(t, []types.EmojiMatch{match("✅", 7)}, applied[1].DetectionResult.Emojis)
+		assert.Equal(t, 1, applied[1].DetectionResult.TotalCount)
+		assert.Equal(t, []types.EmojiMatch{match("😂", 1)}, applied[2].DetectionResult.Emojis)
+	})
+
+	t.Run("fails on findings of expired exemptions", func(t *testing.T) {
+		exceeded := engine.Budgets(engine.Apply(results))
+		assert.Equal(t, []BudgetViolation{
+			{Budget: BudgetExemption, Scope: "internal/**", Found: 1, Expires: "2026-03-30", Reason: "refactor"},
+		}, exceeded)
+
+		err := BudgetError(exceeded)
+		assert.ErrorIs(t, err, ErrThresholdExceeded)
+		assert.ErrorContains(t, err, "exemption expired on 2026-03-30: internal/** has 1 emojis (reason: refactor)")
+	})
+
+	t.Run("describes narrowed exemptions", func(t *testing.T) {
+		assert.Equal(t, "main.go (✅, line 3)", profile.Exemptions[1].String())
+		assert.Equal(t, "docs/legacy/**", profile.Exemptions[0].String())
+	})
+}
@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"sort"
+
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Unowned is the owner of files no CODEOWNERS rule assigns.
+const Unowned = "(unowned)"
+
+// OwnerGroup is the violations found in the files of one CODEOWNERS owner.
+type OwnerGroup struct {
+	Owner  string `json:"owner"`
+	Files  int    `json:"files"`
+	Emojis int    `json:"emojis"`
+}
+
+// CodeOwners returns the CODEOWNERS rules of the repository containing the working
+// directory, reading them on first use.
+func (e *Engine) CodeOwners() (*filtering.CodeOwners, error) {
+	if e.owners == nil {
+		owners, err := filtering.LoadCodeOwners(".")
+		if err != nil {
+			return nil, err
+		}
+		e.owners = owners
+	}
+	return e.owners, nil
+}
+
+// fileOwners returns the owners of the file at path, or Unowned.
+func (e *Engine) fileOwners(path string) []string {
+	if owners := e.owners.Owners(path); len(owners) > 0 {
+		return owners
+	}
+	return []string{Unowned}
+}
+
+// GroupByOwner totals the violations in results per CODEOWNERS owner, counting a file
+// with several owners toward each of them. Only files with violations are counted, and
+// the groups are sorted by decreasing violations, then by owner.
+func (e *Engine) GroupByOwner(results []types.ProcessResult) ([]OwnerGroup, error) {
+	if _, err := e.CodeOwners(); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*OwnerGroup)
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		violations := len(e.FileViolations(result.FilePath, result.DetectionResult.Emojis))
+		if violations == 0 {
+			continue
+		}
+		for _, owner := range e.fileOwners(result.FilePath) {
+			group, ok := groups[owner]
+			if !ok {
+				group = &OwnerGroup{Owner: owner}
+				groups[owner] = group
+			}
+			group.Files++
+			group.Emojis += violations
+		}
+	}
+
+	sorted := make([]OwnerGroup, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Emojis != sorted[j].Emojis {
+			return sorted[i].Emojis > sorted[j].Emojis
+		}
+		return sorted[i].Owner < sorted[j].Owner
+	})
+	return sorted, nil
+}
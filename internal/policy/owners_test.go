@@ -0,0 +1,85 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chdirOwnedRepo changes into a repository owned by the teams of its CODEOWNERS file.
+func chdirOwnedRepo(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"),
+		[]byte("*.go @org/backend\n/docs/ @org/docs @org/backend\n/docs/drafts/\n"), 0600))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+}
+
+func TestEngine_GroupByOwner(t *testing.T) {
+	match := func(emoji string) types.EmojiMatch {
+		return types.EmojiMatch{Emoji: emoji, Category: types.CategoryUnicode}
+	}
+	results := []types.ProcessResult{
+		{FilePath: "main.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("🚀"), match("✅")}}},
+		{FilePath: "docs/guide.md", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("🎉")}}},
+		{FilePath: "docs/drafts/plan.md", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("🎉"), match("😂")}}},
+		{FilePath: "clean.go"},
+		{FilePath: "broken.go", Error: os.ErrPermission, DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("😂")}}},
+	}
+	profile := config.DefaultConfig().Profiles["default"]
+
+	t.Run("totals violations per owner", func(t *testing.T) {
+		chdirOwnedRepo(t)
+
+		groups, err := newEngine(t, profile, Options{}).GroupByOwner(results)
+		require.NoError(t, err)
+		assert.Equal(t, []OwnerGroup{
+			{Owner: "@org/backend", Files: 2, Emojis: 3},
+			{Owner: Unowned, Files: 1, Emojis: 2},
+			{Owner: "@org/docs", Files: 1, Emojis: 1},
+		}, groups)
+	})
+
+	t.Run("checks owner thresholds", func(t *testing.T) {
+		chdirOwnedRepo(t)
+		budgeted := profile
+		budgeted.EmojiAllowlist = []string{"✅"}
+		budgeted.OwnerThresholds = map[string]int{"@org/backend": 1, "@org/docs": 1, Unowned: 1}
+
+		assert.Equal(t, []BudgetViolation{
+			{Budget: BudgetOwner, Scope: Unowned, Found: 2, Limit: 1},
+			{Budget: BudgetOwner, Scope: "@org/backend", Found: 2, Limit: 1},
+		}, newEngine(t, budgeted, Options{}).Budgets(results))
+	})
+
+	t.Run("requires a CODEOWNERS file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(dir))
+		defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+		_, err = newEngine(t, profile, Options{}).GroupByOwner(results)
+		assert.ErrorIs(t, err, filtering.ErrNoCodeOwners)
+
+		budgeted := profile
+		budgeted.OwnerThresholds = map[string]int{"@org/docs": 0}
+		_, err = New(context.Background(), budgeted, Options{})
+		assert.ErrorIs(t, err, filtering.ErrNoCodeOwners)
+		assert.ErrorContains(t, err, "owner_thresholds")
+	})
+}
@@ -0,0 +1,283 @@
+// Package policy decides what counts as an emoji violation. Scan, clean and the history
+// scans all go through one Engine for file selection, emoji patterns, the allowlist and
+// the threshold, so a file clean leaves alone is never one scan fails on.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/core/detector"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/emojidata"
+	"github.com/antimoji/antimoji/internal/infra/filtering"
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// ErrThresholdExceeded indicates more violations were found than the threshold allows.
+var ErrThresholdExceeded = errors.New("emoji threshold exceeded")
+
+// NoThreshold disables the threshold check. A threshold of 0 or more tolerates that
+// many violations, so 0 tolerates none.
+const NoThreshold = -1
+
+// ResolveThreshold returns the threshold of a run with one meaning for every command:
+// threshold when --threshold was given (negative meaning NoThreshold), otherwise the
+// profile's limit when it sets one, otherwise fallback, the command's own default.
+func ResolveThreshold(threshold int, set bool, limit, fallback int) int {
+	switch {
+	case set && threshold < 0:
+		return NoThreshold
+	case set:
+		return threshold
+	case limit > 0:
+		return limit
+	}
+	return fallback
+}
+
+// Options are the per-run settings that shape the policy on top of the profile.
+type Options struct {
+	// Operation names the command for logging, e.g. "scan" or "clean"
+	Operation string
+
+	// Recursive descends into directories during file selection
+	Recursive bool
+
+	// IncludePattern and ExcludePattern are command-line filters applied on top of the
+	// profile's include and exclude patterns
+	IncludePattern string
+	ExcludePattern string
+
+	// IgnoreAllowlist counts allowlisted emojis as violations
+	IgnoreAllowlist bool
+
+	// Only limits violations to these emojis and Except exempts these emojis, on top of
+	// the allowlist
+	Only   []string
+	Except []string
+
+	// Threshold is the number of violations tolerated across the run, the profile's
+	// max_total unless --threshold overrides it; NoThreshold tolerates any number
+	Threshold int
+
+	// Baseline holds the fingerprints of the findings of an earlier findings-json report.
+	// When it is not nil, Budgets limits the violations outside it to the profile's max_new.
+	Baseline map[string]bool
+
+	// Rules are the organization policy's path budgets, checked by Budgets whatever
+	// the profile says
+	Rules []config.PolicyRule
+}
+
+// Engine applies one profile and set of options to file selection, detection and
+// threshold evaluation.
+type Engine struct {
+	profile   config.Profile
+	opts      Options
+	allowlist *allowlist.Allowlist
+	// owners are the CODEOWNERS rules, loaded by CodeOwners
+	owners *filtering.CodeOwners
+	// exemptions allow findings until their expiry date
+	exemptions []exemption
+	// severities make findings warnings instead of errors
+	severities severities
+	// rules are the profile's custom rules, which see files in languages
+	rules     []customRule
+	languages types.LanguageLookup
+	// router sends the files the routing rules match to other engines, see RouteRules
+	router *Router
+}
+
+// New creates the engine for profile, building the allowlist it applies.
+func New(ctx context.Context, profile config.Profile, opts Options) (*Engine, error) {
+	if err := pathmatch.Validate(opts.IncludePattern); err != nil {
+		return nil, fmt.Errorf("--include: %w", err)
+	}
+	if err := pathmatch.Validate(opts.ExcludePattern); err != nil {
+		return nil, fmt.Errorf("--exclude: %w", err)
+	}
+
+	emojiAllowlist, err := allowlist.CreateAllowlistForProcessing(ctx, profile, allowlist.ProcessingOptions{
+		IgnoreAllowlist:  opts.IgnoreAllowlist,
+		RespectAllowlist: !opts.IgnoreAllowlist,
+		Operation:        opts.Operation,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create allowlist: %w", err)
+	}
+	if len(opts.Only) > 0 || len(opts.Except) > 0 {
+		for _, emoji := range opts.Except {
+			if containsEmoji(opts.Only, emoji) {
+				return nil, fmt.Errorf("%s cannot be in both --only and --except", emoji)
+			}
+		}
+		emojiAllowlist = allowlist.Restrict(emojiAllowlist, opts.Only, opts.Except)
+	}
+
+	engine := &Engine{
+		profile:    profile,
+		opts:       opts,
+		allowlist:  emojiAllowlist,
+		exemptions: newExemptions(profile, now()),
+		severities: newSeverities(profile),
+	}
+	if len(profile.OwnerThresholds) > 0 {
+		if _, err := engine.CodeOwners(); err != nil {
+			return nil, fmt.Errorf("owner_thresholds: %w", err)
+		}
+	}
+	if len(profile.CustomRules) > 0 {
+		if engine.rules, err = newCustomRules(profile); err != nil {
+			return nil, fmt.Errorf("custom_rules: %w", err)
+		}
+		engine.languages = ruleLanguages(profile)
+		if usesOwners(engine.rules) {
+			if _, err := engine.CodeOwners(); err != nil {
+				return nil, fmt.Errorf("custom_rules: %w", err)
+			}
+		}
+	}
+	return engine, nil
+}
+
+// containsEmoji reports whether emojis lists emoji, ignoring variation selectors.
+func containsEmoji(emojis []string, emoji string) bool {
+	return allowlist.NewAllowlist(emojis).Unwrap().IsAllowed(emoji)
+}
+
+// Profile returns the profile the engine applies.
+func (e *Engine) Profile() config.Profile {
+	return e.profile
+}
+
+// Allowlist returns the allowlist applied to detections, or nil when every emoji is a
+// violation.
+func (e *Engine) Allowlist() *allowlist.Allowlist {
+	return e.allowlist
+}
+
+// ProcessingConfig returns the detection settings of the profile.
+func (e *Engine) ProcessingConfig() types.ProcessingConfig {
+	return config.ToProcessingConfig(e.profile)
+}
+
+// SelectFiles discovers the files under args that are checked. Each selected file is
+// logged with its correlation ID, which detection and modification log it under too.
+func (e *Engine) SelectFiles(args []string) (filtering.Discovery, error) {
+	discovery, err := filtering.Discover(args, filtering.DiscoveryOptions{
+		Recursive:      e.opts.Recursive,
+		IncludePattern: e.opts.IncludePattern,
+		ExcludePattern: e.opts.ExcludePattern,
+	}, e.profile)
+	if err != nil {
+		return discovery, err
+	}
+
+	ctx := ctxutil.NewComponentContext(e.opts.Operation, "discovery")
+	if logging.GetGlobalLogger().IsEnabled(logging.LevelDebug) {
+		for _, path := range discovery.Files {
+			logging.Debug(ctxutil.WithFilePath(ctx, path), "File selected")
+		}
+	}
+	return discovery, nil
+}
+
+// FileFilter returns the filter SelectFiles applies to each path, for paths that are
+// not on disk such as files in git history.
+func (e *Engine) FileFilter() *filtering.FileFilterEngine {
+	return filtering.NewFileFilterEngine(e.profile).
+		WithCommandLineFilters(e.opts.IncludePattern, e.opts.ExcludePattern)
+}
+
+// Explain reports the filter rules that decide whether SelectFiles selects path.
+func (e *Engine) Explain(path string) filtering.Explanation {
+	return filtering.Explain(path, filtering.DiscoveryOptions{
+		Recursive:      e.opts.Recursive,
+		IncludePattern: e.opts.IncludePattern,
+		ExcludePattern: e.opts.ExcludePattern,
+	}, e.profile)
+}
+
+// Patterns returns the emoji patterns enabled by the profile, including supplemental
+// emoji data.
+func (e *Engine) Patterns(ctx context.Context) (types.EmojiPatterns, error) {
+	patterns, err := emojidata.PatternsForProfile(ctx, detector.DefaultEmojiPatterns(), e.profile)
+	if err != nil {
+		return types.EmojiPatterns{}, fmt.Errorf("failed to load emoji data: %w", err)
+	}
+	return processor.FilterPatterns(patterns, e.ProcessingConfig()), nil
+}
+
+// IsViolation reports whether emoji counts against the policy.
+func (e *Engine) IsViolation(emoji string) bool {
+	return e.allowlist == nil || !e.allowlist.IsAllowed(emoji)
+}
+
+// Violations returns the matches that count against the policy.
+func (e *Engine) Violations(matches []types.EmojiMatch) []types.EmojiMatch {
+	violations := make([]types.EmojiMatch, 0, len(matches))
+	for _, match := range matches {
+		if e.IsViolation(match.Emoji) {
+			violations = append(violations, match)
+		}
+	}
+	return violations
+}
+
+// Applies reports whether Apply changes results, which callers may skip it otherwise.
+func (e *Engine) Applies() bool {
+	return e.allowlist != nil || len(e.exemptions) > 0 || e.severities.configured() || e.Router().Routes()
+}
+
+// Apply reduces the detections of each result to its violations, leaving out the
+// findings of current exemptions, and marks the violations that are only warnings,
+// each under the engine its file is routed to. Results with an error are returned
+// unchanged.
+func (e *Engine) Apply(results []types.ProcessResult) []types.ProcessResult {
+	applied := make([]types.ProcessResult, 0, len(results))
+	for _, result := range results {
+		engine := e.For(result.FilePath)
+		if result.Error == nil && engine.Applies() {
+			violations := engine.FileViolations(result.FilePath, result.DetectionResult.Emojis)
+			unique := make(map[string]struct{}, len(violations))
+			for i, match := range violations {
+				unique[match.Emoji] = struct{}{}
+				if engine.Severity(match) == types.SeverityWarn {
+					violations[i].Severity = types.SeverityWarn
+				}
+			}
+			result.DetectionResult.Emojis = violations
+			result.DetectionResult.TotalCount = len(violations)
+			result.DetectionResult.UniqueCount = len(unique)
+		}
+		applied = append(applied, result)
+	}
+	return applied
+}
+
+// Threshold returns the number of violations tolerated, or NoThreshold.
+func (e *Engine) Threshold() int {
+	return e.opts.Threshold
+}
+
+// TotalViolation describes violations against the threshold, e.g.
+// "max_total: 12 emojis (limit 10)".
+func (e *Engine) TotalViolation(violations int) BudgetViolation {
+	return BudgetViolation{Budget: BudgetTotal, Found: violations, Limit: e.opts.Threshold}
+}
+
+// Evaluate returns an error wrapping ErrThresholdExceeded when violations is over the
+// threshold.
+func (e *Engine) Evaluate(violations int) error {
+	if e.opts.Threshold < 0 || violations <= e.opts.Threshold {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrThresholdExceeded, e.TotalViolation(violations))
+}
@@ -0,0 +1,296 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/processor"
+	"github.com/antimoji/antimoji/internal/infra/pathmatch"
+	"github.com/antimoji/antimoji/internal/infra/report"
+	ctxutil "github.com/antimoji/antimoji/internal/observability/context"
+	"github.com/antimoji/antimoji/internal/observability/logging"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEngine(t *testing.T, profile config.Profile, opts Options) *Engine {
+	t.Helper()
+	engine, err := New(context.Background(), profile, opts)
+	require.NoError(t, err)
+	return engine
+}
+
+func TestEngine_Apply(t *testing.T) {
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.EmojiAllowlist = []string{"✅"}
+
+	results := []types.ProcessResult{
+		{
+			FilePath: "test1.go",
+			DetectionResult: types.DetectionResult{
+				TotalCount:  4,
+				UniqueCount: 3,
+				Emojis: []types.EmojiMatch{
+					{Emoji: "✅", Start: 0, End: 3, Line: 1, Column: 1, Category: types.CategoryUnicode},
+					{Emoji: "❌", Start: 10, End: 13, Line: 1, Column: 11, Category: types.CategoryUnicode},
+					{Emoji: "🚀", Start: 20, End: 24, Line: 2, Column: 1, Category: types.CategoryUnicode},
+					{Emoji: "🚀", Start: 30, End: 34, Line: 3, Column: 1, Category: types.CategoryUnicode},
+				},
+			},
+		},
+		{
+			FilePath:        "missing.go",
+			Error:           os.ErrNotExist,
+			DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{{Emoji: "✅"}}},
+		},
+	}
+
+	t.Run("keeps only violations", func(t *testing.T) {
+		applied := newEngine(t, profile, Options{}).Apply(results)
+
+		require.Len(t, applied, 2)
+		assert.Equal(t, 3, applied[0].DetectionResult.TotalCount)
+		assert.Equal(t, 2, applied[0].DetectionResult.UniqueCount)
+		assert.Equal(t, "❌", applied[0].DetectionResult.Emojis[0].Emoji)
+		assert.Equal(t, results[1], applied[1], "error results are unchanged")
+		assert.Len(t, results[0].DetectionResult.Emojis, 4, "input is not modified")
+	})
+
+	t.Run("ignoring the allowlist keeps everything", func(t *testing.T) {
+		engine := newEngine(t, profile, Options{IgnoreAllowlist: true})
+
+		assert.Nil(t, engine.Allowlist())
+		assert.True(t, engine.IsViolation("✅"))
+		assert.Equal(t, results, engine.Apply(results))
+	})
+
+	t.Run("handles empty results", func(t *testing.T) {
+		assert.Empty(t, newEngine(t, profile, Options{}).Apply(nil))
+	})
+
+	t.Run("only and except narrow the violations", func(t *testing.T) {
+		applied := newEngine(t, profile, Options{Only: []string{"🚀", "✅"}}).Apply(results)
+		assert.Equal(t, 2, applied[0].DetectionResult.TotalCount, "allowlisted ✅ stays allowed")
+		assert.Equal(t, "🚀", applied[0].DetectionResult.Emojis[0].Emoji)
+
+		applied = newEngine(t, profile, Options{Except: []string{"🚀"}}).Apply(results)
+		require.Len(t, applied[0].DetectionResult.Emojis, 1)
+		assert.Equal(t, "❌", applied[0].DetectionResult.Emojis[0].Emoji)
+
+		applied = newEngine(t, profile, Options{IgnoreAllowlist: true, Only: []string{"✅"}}).Apply(results)
+		require.Len(t, applied[0].DetectionResult.Emojis, 1)
+		assert.Equal(t, "✅", applied[0].DetectionResult.Emojis[0].Emoji)
+	})
+
+	t.Run("rejects an emoji in both only and except", func(t *testing.T) {
+		_, err := New(context.Background(), profile, Options{Only: []string{"🚀"}, Except: []string{"🚀"}})
+		assert.ErrorContains(t, err, "cannot be in both --only and --except")
+	})
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	profile := config.DefaultConfig().Profiles["default"]
+
+	assert.NoError(t, newEngine(t, profile, Options{Threshold: NoThreshold}).Evaluate(100))
+	assert.NoError(t, newEngine(t, profile, Options{Threshold: 2}).Evaluate(2))
+
+	err := newEngine(t, profile, Options{Threshold: 2}).Evaluate(3)
+	assert.ErrorIs(t, err, ErrThresholdExceeded)
+	assert.ErrorContains(t, err, "max_total: 3 emojis (limit 2)")
+
+	assert.ErrorIs(t, newEngine(t, profile, Options{Threshold: 0}).Evaluate(1), ErrThresholdExceeded)
+}
+
+func TestResolveThreshold(t *testing.T) {
+	assert.Equal(t, 0, ResolveThreshold(0, true, 5, NoThreshold), "--threshold 0 tolerates none")
+	assert.Equal(t, NoThreshold, ResolveThreshold(-3, true, 5, 0), "a negative --threshold is no limit")
+	assert.Equal(t, 2, ResolveThreshold(2, true, 5, 0))
+	assert.Equal(t, 5, ResolveThreshold(0, false, 5, 0), "max_total without --threshold")
+	assert.Equal(t, NoThreshold, ResolveThreshold(0, false, 0, NoThreshold))
+	assert.Equal(t, 0, ResolveThreshold(0, false, 0, 0))
+}
+
+func TestEngine_Patterns(t *testing.T) {
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.TextEmoticons = false
+
+	patterns, err := newEngine(t, profile, Options{}).Patterns(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, patterns.UnicodeRanges)
+	assert.Empty(t, patterns.EmoticonPatterns, "disabled pattern kinds are never violations")
+}
+
+func TestEngine_SelectFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("# notes\n"), 0600))
+
+	engine := newEngine(t, config.DefaultConfig().Profiles["default"], Options{Recursive: true, ExcludePattern: "*.md"})
+
+	discovery, err := engine.SelectFiles([]string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "main.go")}, discovery.Files)
+	assert.False(t, engine.FileFilter().ShouldInclude(filepath.Join(dir, "notes.md")).Include)
+}
+
+func TestEngine_SelectFiles_DoubleStar(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs", "guide"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "guide", "intro.md"), []byte("# intro\n"), 0600))
+
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.ExcludePatterns = []string{"docs/**/*"}
+	engine := newEngine(t, profile, Options{Recursive: true})
+
+	// Paths are relative to the working directory, like the patterns of a config file
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	discovery, err := engine.SelectFiles([]string{"."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.go"}, discovery.Files)
+}
+
+func TestNew_InvalidPattern(t *testing.T) {
+	_, err := New(context.Background(), config.DefaultConfig().Profiles["default"], Options{ExcludePattern: "[a-"})
+	assert.ErrorIs(t, err, pathmatch.ErrBadPattern)
+	assert.Contains(t, err.Error(), "--exclude")
+}
+
+func TestEngine_Budgets(t *testing.T) {
+	match := func(emoji string) types.EmojiMatch {
+		return types.EmojiMatch{Emoji: emoji, Category: types.CategoryUnicode}
+	}
+	results := []types.ProcessResult{
+		{FilePath: "src/main.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("✅"), match("✅"), match("😂")}}},
+		{FilePath: "docs/guide.md", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("🚀"), match("🚀")}}},
+		{FilePath: "docs/api/ref.md", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("✅")}}},
+		{FilePath: "broken.go", Error: os.ErrPermission, DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("😂")}}},
+	}
+
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.MaxPerFile = 2
+	profile.DirectoryThresholds = map[string]int{"docs": 2, "docs/api/": 1, "src": 5, ".": 5}
+	profile.EmojiThresholds = map[string]int{"✅": 2, "😂": 0}
+
+	t.Run("reports exceeded budgets in order", func(t *testing.T) {
+		exceeded := newEngine(t, profile, Options{}).Budgets(results)
+
+		assert.Equal(t, []BudgetViolation{
+			{Budget: BudgetPerFile, Scope: "src/main.go", Found: 3, Limit: 2},
+			{Budget: BudgetDirectory, Scope: ".", Found: 6, Limit: 5},
+			{Budget: BudgetDirectory, Scope: "docs", Found: 3, Limit: 2},
+			{Budget: BudgetEmoji, Scope: "✅", Found: 3, Limit: 2},
+			{Budget: BudgetEmoji, Scope: "😂", Found: 1, Limit: 0},
+		}, exceeded)
+
+		err := BudgetError(exceeded)
+		assert.ErrorIs(t, err, ErrThresholdExceeded)
+		assert.ErrorContains(t, err, "max_per_file: src/main.go has 3 emojis (limit 2)")
+		assert.ErrorContains(t, err, "emoji_thresholds: 😂 found 1 times (limit 0)")
+	})
+
+	t.Run("counts only violations", func(t *testing.T) {
+		allowing := profile
+		allowing.EmojiAllowlist = []string{"✅"}
+		allowing.EmojiThresholds = map[string]int{"😂": 1}
+
+		assert.Empty(t, newEngine(t, allowing, Options{}).Budgets(results))
+	})
+
+	t.Run("organization policy rules", func(t *testing.T) {
+		rules := []config.PolicyRule{
+			{Paths: []string{"*.go"}, MaxEmojis: 0},
+			{Paths: []string{"docs/**"}, MaxEmojis: 3},
+			{Paths: []string{"docs/api/*.md", "src/**"}, MaxEmojis: 2},
+		}
+		exceeded := newEngine(t, config.DefaultConfig().Profiles["default"], Options{Rules: rules}).Budgets(results)
+
+		assert.Equal(t, []BudgetViolation{
+			{Budget: BudgetPolicy, Scope: "*.go", Found: 3, Limit: 0},
+			{Budget: BudgetPolicy, Scope: "docs/api/*.md, src/**", Found: 4, Limit: 2},
+		}, exceeded)
+	})
+
+	t.Run("evasion threshold", func(t *testing.T) {
+		evasion := types.EmojiMatch{Emoji: "\u200b", Category: types.CategoryEvasion}
+		withEvasion := append([]types.ProcessResult{
+			{FilePath: "src/auth.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{evasion, evasion}}},
+		}, results...)
+
+		detecting := config.DefaultConfig().Profiles["default"]
+		detecting.EvasionDetection = true
+		detecting.EvasionThreshold = 1
+		exceeded := newEngine(t, detecting, Options{}).Budgets(withEvasion)
+		assert.Equal(t, []BudgetViolation{{Budget: BudgetEvasion, Scope: "evasion", Found: 2, Limit: 1}}, exceeded)
+		assert.ErrorContains(t, BudgetError(exceeded), "evasion_threshold: 2 evasion findings (limit 1)")
+
+		detecting.EvasionThreshold = 2
+		assert.Empty(t, newEngine(t, detecting, Options{}).Budgets(withEvasion))
+	})
+
+	t.Run("new violations against a baseline", func(t *testing.T) {
+		baseline := report.Findings(results[:2], time.Time{}).Fingerprints()
+		limiting := config.DefaultConfig().Profiles["default"]
+
+		exceeded := newEngine(t, limiting, Options{Baseline: baseline}).Budgets(results)
+		assert.Equal(t, []BudgetViolation{{Budget: BudgetNew, Found: 1, Limit: 0}}, exceeded)
+		assert.ErrorContains(t, BudgetError(exceeded), "max_new: 1 emojis not in the baseline (limit 0)")
+
+		limiting.MaxNew = 1
+		assert.Empty(t, newEngine(t, limiting, Options{Baseline: baseline}).Budgets(results))
+	})
+
+	t.Run("no budgets", func(t *testing.T) {
+		assert.Empty(t, newEngine(t, config.DefaultConfig().Profiles["default"], Options{}).Budgets(results))
+		assert.NoError(t, BudgetError(nil))
+	})
+}
+
+func TestEngine_FileCorrelation(t *testing.T) {
+	var logs bytes.Buffer
+	logger, err := logging.NewLogger(&logging.Config{Level: logging.LevelDebug, Format: logging.FormatJSON, Output: &logs})
+	require.NoError(t, err)
+	previous := logging.GetGlobalLogger()
+	logging.SetGlobalLogger(logger)
+	t.Cleanup(func() { logging.SetGlobalLogger(previous) })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("// ship it 🚀\n"), 0600))
+
+	engine := newEngine(t, config.DefaultConfig().Profiles["default"], Options{Operation: "clean", Recursive: true})
+	discovery, err := engine.SelectFiles([]string{dir})
+	require.NoError(t, err)
+	require.Equal(t, []string{path}, discovery.Files)
+	patterns, err := engine.Patterns(context.Background())
+	require.NoError(t, err)
+	processor.ProcessFiles(discovery.Files, patterns, engine.ProcessingConfig())
+	processor.ModifyFiles(discovery.Files, patterns, processor.DefaultModifyConfig(), nil)
+
+	// Discovery, detection and modification all log the file under one ID
+	messages := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry["file_path"] != path {
+			continue
+		}
+		assert.Equal(t, ctxutil.FileCorrelationID(path), entry["correlation_id"], entry["msg"])
+		messages[entry["msg"].(string)] = true
+	}
+	assert.True(t, messages["File selected"])
+	assert.True(t, messages["Emoji detection completed"])
+	assert.True(t, messages["File modification completed successfully"])
+}
@@ -0,0 +1,165 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// Route checks the files matching Paths under the profile named Profile with Engine.
+type Route struct {
+	Paths   []string
+	Profile string
+	Engine  *Engine
+}
+
+// RouteGroup is the files of a run that are checked under one profile, with their
+// results once detected.
+type RouteGroup struct {
+	Profile string
+	Engine  *Engine
+	Files   []string
+	Results []types.ProcessResult
+}
+
+// Router picks the engine each file is checked under: that of the first route with a
+// path matching the file, or the engine of the profile the command selected. Paths are
+// matched like budget paths, relative to the working directory.
+type Router struct {
+	profile string
+	engine  *Engine
+	routes  []Route
+}
+
+// NewRouter routes files to routes, and the others to engine, the engine of profile.
+func NewRouter(profile string, engine *Engine, routes []Route) *Router {
+	return &Router{profile: profile, engine: engine, routes: routes}
+}
+
+// Routes reports whether any file can be checked under another profile.
+func (r *Router) Routes() bool {
+	return len(r.routes) > 0
+}
+
+// Route returns the profile the file at filePath is checked under and its engine.
+func (r *Router) Route(filePath string) (string, *Engine) {
+	file := budgetPath(filePath)
+	for _, route := range r.routes {
+		for _, pattern := range route.Paths {
+			if matchesPath(pattern, file) {
+				return route.Profile, route.Engine
+			}
+		}
+	}
+	return r.profile, r.engine
+}
+
+// Group splits paths by the profile they are checked under, keeping their order within
+// each group. The selected profile comes first, then the routed ones in rule order;
+// profiles no path is routed to are left out.
+func (r *Router) Group(paths []string) []RouteGroup {
+	groups, index := r.groups()
+	for _, path := range paths {
+		profile, _ := r.Route(path)
+		groups[index[profile]].Files = append(groups[index[profile]].Files, path)
+	}
+	return nonEmptyGroups(groups)
+}
+
+// GroupResults splits results by the profile their files are checked under, like Group,
+// except that the group of the selected profile is kept without results: what a run
+// finds outside files, like emojis in names, counts against it.
+func (r *Router) GroupResults(results []types.ProcessResult) []RouteGroup {
+	groups, index := r.groups()
+	for _, result := range results {
+		profile, _ := r.Route(result.FilePath)
+		group := &groups[index[profile]]
+		group.Files = append(group.Files, result.FilePath)
+		group.Results = append(group.Results, result)
+	}
+	return append(groups[:1], nonEmptyGroups(groups[1:])...)
+}
+
+// groups returns an empty group for each profile, indexed by profile name.
+func (r *Router) groups() ([]RouteGroup, map[string]int) {
+	groups := []RouteGroup{{Profile: r.profile, Engine: r.engine}}
+	index := map[string]int{r.profile: 0}
+	for _, route := range r.routes {
+		if _, ok := index[route.Profile]; !ok {
+			index[route.Profile] = len(groups)
+			groups = append(groups, RouteGroup{Profile: route.Profile, Engine: route.Engine})
+		}
+	}
+	return groups, index
+}
+
+// nonEmptyGroups returns the groups with files.
+func nonEmptyGroups(groups []RouteGroup) []RouteGroup {
+	nonEmpty := groups[:0]
+	for _, group := range groups {
+		if len(group.Files) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return nonEmpty
+}
+
+// Resolver returns the profile named name, resolved as the command resolves the profile
+// it selects, and the options of its engine.
+type Resolver func(name string) (config.Profile, Options, error)
+
+// RouteRules routes the files that rules match to the engines of the profiles they
+// name, so that FileViolations, Apply and For check each file under its own profile.
+// The other files stay with e, the engine of the selected profile named profile. The
+// engine of each routed profile is built once from what resolve returns.
+func (e *Engine) RouteRules(ctx context.Context, profile string, rules []config.RoutingRule, resolve Resolver) error {
+	engines := map[string]*Engine{profile: e}
+	routes := make([]Route, 0, len(rules))
+	for _, rule := range rules {
+		routed, ok := engines[rule.Profile]
+		if !ok {
+			routedProfile, opts, err := resolve(rule.Profile)
+			if err != nil {
+				return err
+			}
+			if routed, err = New(ctx, routedProfile, opts); err != nil {
+				return fmt.Errorf("profile %s: %w", rule.Profile, err)
+			}
+			engines[rule.Profile] = routed
+		}
+		routes = append(routes, Route{Paths: rule.Paths, Profile: rule.Profile, Engine: routed})
+	}
+	e.router = NewRouter(profile, e, routes)
+	return nil
+}
+
+// Router returns the router of the engine, which sends every file to the engine itself
+// unless RouteRules routed some elsewhere.
+func (e *Engine) Router() *Router {
+	if e.router == nil {
+		return NewRouter("", e, nil)
+	}
+	return e.router
+}
+
+// For returns the engine the file at filePath is checked under.
+func (e *Engine) For(filePath string) *Engine {
+	if e.router == nil {
+		return e
+	}
+	_, engine := e.router.Route(filePath)
+	return engine
+}
+
+// Apply reduces each of results with the engine its file is routed to, like
+// Engine.Apply.
+func (r *Router) Apply(results []types.ProcessResult) []types.ProcessResult {
+	applied := make([]types.ProcessResult, 0, len(results))
+	for _, result := range results {
+		_, engine := r.Route(result.FilePath)
+		applied = append(applied, engine.Apply([]types.ProcessResult{result})...)
+	}
+	return applied
+}
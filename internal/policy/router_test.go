@@ -0,0 +1,90 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter(t *testing.T) {
+	strict := newEngine(t, config.DefaultConfig().Profiles["default"], Options{})
+	permissive := config.DefaultConfig().Profiles["default"]
+	permissive.EmojiAllowlist = []string{"🚀"}
+	docs := newEngine(t, permissive, Options{})
+	router := NewRouter("default", strict, []Route{
+		{Paths: []string{"docs/**", "*.md"}, Profile: "docs", Engine: docs},
+		{Paths: []string{"docs/api/**"}, Profile: "api", Engine: strict},
+	})
+	require.True(t, router.Routes())
+
+	t.Run("the first matching route wins", func(t *testing.T) {
+		for file, want := range map[string]string{
+			"docs/guide.md":     "docs",
+			"docs/api/index.md": "docs",
+			"./README.md":       "docs",
+			"main.go":           "default",
+		} {
+			profile, _ := router.Route(file)
+			assert.Equal(t, want, profile, file)
+		}
+	})
+
+	t.Run("groups keep the selected profile first", func(t *testing.T) {
+		groups := router.Group([]string{"docs/a.md", "main.go", "README.md"})
+		require.Len(t, groups, 2)
+		assert.Equal(t, "default", groups[0].Profile)
+		assert.Equal(t, []string{"main.go"}, groups[0].Files)
+		assert.Equal(t, "docs", groups[1].Profile)
+		assert.Equal(t, []string{"docs/a.md", "README.md"}, groups[1].Files)
+
+		results := router.GroupResults([]types.ProcessResult{{FilePath: "docs/a.md"}})
+		require.Len(t, results, 2, "the selected profile is kept without results")
+		assert.Empty(t, results[0].Results)
+		assert.Len(t, results[1].Results, 1)
+	})
+
+	t.Run("each result is applied with its engine", func(t *testing.T) {
+		rocket := types.DetectionResult{Emojis: []types.EmojiMatch{{Emoji: "🚀"}}, TotalCount: 1}
+		applied := router.Apply([]types.ProcessResult{
+			{FilePath: "docs/a.md", DetectionResult: rocket},
+			{FilePath: "main.go", DetectionResult: rocket},
+		})
+		assert.Equal(t, 0, applied[0].DetectionResult.TotalCount)
+		assert.Equal(t, 1, applied[1].DetectionResult.TotalCount)
+	})
+
+	assert.False(t, NewRouter("default", strict, nil).Routes())
+}
+
+func TestEngine_RouteRules(t *testing.T) {
+	strict := newEngine(t, config.DefaultConfig().Profiles["default"], Options{})
+	permissive := config.DefaultConfig().Profiles["default"]
+	permissive.EmojiAllowlist = []string{"🚀"}
+	resolved := 0
+	require.NoError(t, strict.RouteRules(context.Background(), "default", []config.RoutingRule{
+		{Paths: []string{"docs/**"}, Profile: "permissive"},
+		{Paths: []string{"*.md"}, Profile: "permissive"},
+	}, func(name string) (config.Profile, Options, error) {
+		resolved++
+		return permissive, Options{}, nil
+	}))
+	assert.Equal(t, 1, resolved, "each profile is resolved once")
+	assert.True(t, strict.Router().Routes())
+	assert.NotSame(t, strict, strict.For("docs/a.go"))
+	assert.Same(t, strict, strict.For("main.go"))
+
+	rocket := []types.EmojiMatch{{Emoji: "🚀"}}
+	assert.Empty(t, strict.FileViolations("README.md", rocket))
+	assert.Len(t, strict.FileViolations("main.go", rocket), 1)
+
+	err := strict.RouteRules(context.Background(), "default", []config.RoutingRule{{Paths: []string{"x/**"}, Profile: "missing"}},
+		func(name string) (config.Profile, Options, error) {
+			return config.Profile{}, Options{}, errors.New("profile not found")
+		})
+	assert.ErrorContains(t, err, "profile not found")
+}
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/rules"
+	"github.com/antimoji/antimoji/internal/lang"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// customRule is a custom rule of the profile with its compiled expression.
+type customRule struct {
+	config.CustomRule
+	rule *rules.Rule
+}
+
+// newCustomRules compiles the custom rules of profile.
+func newCustomRules(profile config.Profile) ([]customRule, error) {
+	compiled := make([]customRule, 0, len(profile.CustomRules))
+	for _, rule := range profile.CustomRules {
+		program, err := rules.Compile(rule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("custom rule %s: %w", rule.Name, err)
+		}
+		compiled = append(compiled, customRule{CustomRule: rule, rule: program})
+	}
+	return compiled, nil
+}
+
+// ruleLanguages returns the languages of profile, which custom rules see files in.
+func ruleLanguages(profile config.Profile) types.LanguageLookup {
+	if languages := config.ToProcessingConfig(profile).Languages; languages != nil {
+		return languages
+	}
+	return lang.Default()
+}
+
+// usesOwners reports whether any of the rules reads the CODEOWNERS owners of files.
+func usesOwners(compiled []customRule) bool {
+	for _, rule := range compiled {
+		if strings.Contains(rule.Expression, "owners") {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleCount is the number of violations a custom rule matched, with the first error
+// evaluating it.
+type ruleCount struct {
+	found int
+	err   error
+}
+
+// countRules adds the violations of the file at filePath that each custom rule
+// matches to counts. A violation the rule fails to evaluate on counts as matched, so
+// that a broken rule fails rather than passes.
+func (e *Engine) countRules(counts []ruleCount, filePath string, violations []types.EmojiMatch) {
+	file := e.ruleFile(filePath, len(violations))
+	for _, match := range violations {
+		finding := rules.Finding{
+			Emoji:     match.Emoji,
+			Category:  string(match.Category),
+			Severity:  e.Severity(match),
+			Line:      match.Line,
+			Column:    match.Column,
+			InComment: match.InComment,
+		}
+		for i, rule := range e.rules {
+			matched, err := rule.rule.Matches(finding, file)
+			if err != nil && counts[i].err == nil {
+				counts[i].err = err
+			}
+			if matched || err != nil {
+				counts[i].found++
+			}
+		}
+	}
+}
+
+// ruleFile describes the file at filePath, holding violations violations, to custom
+// rules.
+func (e *Engine) ruleFile(filePath string, violations int) rules.File {
+	file := budgetPath(filePath)
+	language, _ := e.languages.Lookup(filePath, nil)
+	return rules.File{
+		Path:     file,
+		Name:     path.Base(file),
+		Dir:      path.Dir(file),
+		Ext:      path.Ext(file),
+		Language: language.Name,
+		Owners:   e.owners.Owners(filePath),
+		Findings: violations,
+	}
+}
+
+// exceededRules returns the custom rules whose counts are over their max, in profile
+// order.
+func (e *Engine) exceededRules(counts []ruleCount) []BudgetViolation {
+	var exceeded []BudgetViolation
+	for i, rule := range e.rules {
+		if counts[i].found <= rule.Max {
+			continue
+		}
+		reason := rule.Message
+		if counts[i].err != nil {
+			reason = fmt.Sprintf("failed to evaluate: %v", counts[i].err)
+		}
+		exceeded = append(exceeded, BudgetViolation{
+			Budget: BudgetRule,
+			Scope:  rule.Name,
+			Found:  counts[i].found,
+			Limit:  rule.Max,
+			Reason: reason,
+		})
+	}
+	return exceeded
+}
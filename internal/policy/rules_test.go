@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_CustomRules(t *testing.T) {
+	chdirOwnedRepo(t)
+	match := func(emoji string, line int, inComment bool) types.EmojiMatch {
+		return types.EmojiMatch{Emoji: emoji, Category: types.CategoryUnicode, Line: line, InComment: inComment}
+	}
+	results := []types.ProcessResult{
+		{FilePath: "src/pay.go", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{
+			match("🚀", 1, false), match("✅", 2, true), match("🎉", 3, false),
+		}}},
+		{FilePath: "docs/guide.md", DetectionResult: types.DetectionResult{Emojis: []types.EmojiMatch{match("🚀", 1, false)}}},
+	}
+
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.Severity = map[string]string{"🎉": types.SeverityWarn}
+	profile.CustomRules = []config.CustomRule{
+		{Name: "backend-code", Expression: `"@org/backend" in file.owners && !finding.in_comment`, Message: "no emojis in backend code"},
+		{Name: "go-warnings", Expression: `file.language == "go" && finding.severity == "warn"`, Max: 1},
+		{Name: "busy-docs", Expression: `file.dir == "docs" && file.findings > 1`},
+	}
+
+	exceeded := newEngine(t, profile, Options{}).Budgets(results)
+	assert.Equal(t, []BudgetViolation{
+		{Budget: BudgetRule, Scope: "backend-code", Found: 3, Limit: 0, Reason: "no emojis in backend code"},
+	}, exceeded)
+	assert.ErrorContains(t, BudgetError(exceeded), "custom_rules: backend-code matched 3 emojis (limit 0): no emojis in backend code")
+
+	t.Run("rules that fail to evaluate fail", func(t *testing.T) {
+		failing := profile
+		failing.CustomRules = []config.CustomRule{{Name: "first-owner", Expression: `file.owners[2] == "@org/docs"`}}
+		exceeded := newEngine(t, failing, Options{}).Budgets(results)
+		require.Len(t, exceeded, 1)
+		assert.Equal(t, 4, exceeded[0].Found)
+		assert.Contains(t, exceeded[0].Reason, "failed to evaluate")
+	})
+
+	t.Run("rejects rules that do not compile", func(t *testing.T) {
+		invalid := profile
+		invalid.CustomRules = []config.CustomRule{{Name: "typo", Expression: `finding.emojii == "🚀"`}}
+		_, err := New(context.Background(), invalid, Options{})
+		assert.ErrorContains(t, err, "custom_rules: custom rule typo")
+	})
+}
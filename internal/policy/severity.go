@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"slices"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/core/allowlist"
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// severities is the severity map of a profile, split into emojis and categories.
+type severities struct {
+	// warn and errors match the emojis of each severity with or without variation
+	// selectors
+	warn, errors *allowlist.Allowlist
+	categories   map[types.EmojiCategory]string
+}
+
+// newSeverities prepares the severity map of profile. Keys naming a category set the
+// severity of the category, the others that of an emoji.
+func newSeverities(profile config.Profile) severities {
+	var warn, errors []string
+	categories := make(map[types.EmojiCategory]string)
+	for key, severity := range profile.Severity {
+		switch {
+		case slices.Contains(types.Categories(), types.EmojiCategory(key)):
+			categories[types.EmojiCategory(key)] = severity
+		case severity == types.SeverityWarn:
+			warn = append(warn, key)
+		default:
+			errors = append(errors, key)
+		}
+	}
+	return severities{
+		warn:       allowlist.NewAllowlist(warn).Unwrap(),
+		errors:     allowlist.NewAllowlist(errors).Unwrap(),
+		categories: categories,
+	}
+}
+
+// configured reports whether any finding can have a severity other than error.
+func (s severities) configured() bool {
+	if s.warn != nil && !s.warn.IsEmpty() {
+		return true
+	}
+	for _, severity := range s.categories {
+		if severity == types.SeverityWarn {
+			return true
+		}
+	}
+	return false
+}
+
+// Severity returns the severity of match under the profile: that of its emoji, else
+// that of its category, else types.SeverityError.
+func (e *Engine) Severity(match types.EmojiMatch) string {
+	switch {
+	case !e.severities.configured():
+		return types.SeverityError
+	case e.severities.errors.IsAllowed(match.Emoji):
+		return types.SeverityError
+	case e.severities.warn.IsAllowed(match.Emoji):
+		return types.SeverityWarn
+	}
+	if severity, ok := e.severities.categories[match.Category]; ok {
+		return severity
+	}
+	return types.SeverityError
+}
+
+// withoutWarnings returns the matches whose severity is error.
+func (e *Engine) withoutWarnings(matches []types.EmojiMatch) []types.EmojiMatch {
+	if !e.severities.configured() {
+		return matches
+	}
+	errors := make([]types.EmojiMatch, 0, len(matches))
+	for _, match := range matches {
+		if e.Severity(match) != types.SeverityWarn {
+			errors = append(errors, match)
+		}
+	}
+	return errors
+}
+
+// CountSeverities counts the findings of results reduced by Apply that are errors and
+// those that are warnings. Results with an error are not counted.
+func CountSeverities(results []types.ProcessResult) (errors, warnings int) {
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		for _, match := range result.DetectionResult.Emojis {
+			if match.IsWarning() {
+				warnings++
+			}
+		}
+		errors += result.DetectionResult.TotalCount
+	}
+	return errors - warnings, warnings
+}
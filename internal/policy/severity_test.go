@@ -0,0 +1,55 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/antimoji/antimoji/internal/config"
+	"github.com/antimoji/antimoji/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_Severity(t *testing.T) {
+	profile := config.DefaultConfig().Profiles["default"]
+	profile.Severity = map[string]string{
+		"emoticon": types.SeverityWarn,
+		"✅":        types.SeverityWarn,
+		":)":       types.SeverityError,
+	}
+	engine := newEngine(t, profile, Options{Operation: "scan"})
+
+	unicode := func(emoji string) types.EmojiMatch {
+		return types.EmojiMatch{Emoji: emoji, Category: types.CategoryUnicode}
+	}
+	emoticon := func(emoji string) types.EmojiMatch {
+		return types.EmojiMatch{Emoji: emoji, Category: types.CategoryEmoticon}
+	}
+
+	assert.Equal(t, types.SeverityWarn, engine.Severity(unicode("✅")))
+	assert.Equal(t, types.SeverityWarn, engine.Severity(unicode("✅\ufe0f")), "variation selectors are ignored")
+	assert.Equal(t, types.SeverityError, engine.Severity(unicode("😂")))
+	assert.Equal(t, types.SeverityWarn, engine.Severity(emoticon(":D")))
+	assert.Equal(t, types.SeverityError, engine.Severity(emoticon(":)")), "emojis take precedence over categories")
+
+	results := engine.Apply([]types.ProcessResult{{
+		FilePath: "main.go",
+		DetectionResult: types.DetectionResult{
+			Emojis:     []types.EmojiMatch{unicode("✅"), unicode("😂"), emoticon(":D")},
+			TotalCount: 3,
+		},
+	}})
+	errors, warnings := CountSeverities(results)
+	assert.Equal(t, 1, errors)
+	assert.Equal(t, 2, warnings)
+	assert.True(t, results[0].DetectionResult.Emojis[0].IsWarning())
+	assert.False(t, results[0].DetectionResult.Emojis[1].IsWarning())
+
+	profile.MaxPerFile = 1
+	engine = newEngine(t, profile, Options{Operation: "scan"})
+	assert.Empty(t, engine.Budgets(results), "warnings do not count against budgets")
+}
+
+func TestEngine_SeverityDefault(t *testing.T) {
+	engine := newEngine(t, config.DefaultConfig().Profiles["default"], Options{Operation: "scan"})
+	assert.False(t, engine.Applies())
+	assert.Equal(t, types.SeverityError, engine.Severity(types.EmojiMatch{Emoji: "✅", Category: types.CategoryUnicode}))
+}
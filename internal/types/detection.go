@@ -1,7 +1,13 @@
 // Package types provides core types for emoji detection and processing.
 package types
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
 
 // EmojiMatch represents a detected emoji in text content.
 type EmojiMatch struct {
@@ -20,9 +26,17 @@ type EmojiMatch struct {
 	// Column is the column position where the emoji starts (1-based)
 	Column int `json:"column"`
 
-	// Category describes the type of emoji (Unicode, Emoticon, Custom)
+	// Category describes the type of emoji (Unicode, Emoticon, Custom, Shortcode, Kaomoji,
+	// Decorative, Escaped, Evasion, Hygiene)
 	Category EmojiCategory `json:"category"`
 
+	// Severity is SeverityWarn for findings the policy only warns about; findings
+	// without one are errors
+	Severity string `json:"severity,omitempty"`
+
+	// InComment is set for matches inside a comment when the patterns MarkComments
+	InComment bool `json:"in_comment,omitempty"`
+
 	// DebugInfo contains debugging information about the detected emoji
 	DebugInfo map[string]interface{} `json:"debug_info,omitempty"`
 }
@@ -39,8 +53,65 @@ const (
 
 	// CategoryCustom represents custom emoji patterns (e.g., , )
 	CategoryCustom EmojiCategory = "custom"
+
+	// CategoryShortcode represents GitHub and GitLab shortcodes (e.g., :rocket:, :tada:)
+	CategoryShortcode EmojiCategory = "shortcode"
+
+	// CategoryKaomoji represents Japanese-style text faces (e.g., ¯\_(ツ)_/¯, (^_^))
+	CategoryKaomoji EmojiCategory = "kaomoji"
+
+	// CategoryDecorative represents decorative symbols written as text (e.g., ★, ♥, ►)
+	CategoryDecorative EmojiCategory = "decorative"
+
+	// CategoryEscaped represents emojis written as escapes (e.g., &#x1F600;, \uD83D\uDE00);
+	// the match reports the decoded emoji
+	CategoryEscaped EmojiCategory = "escaped"
+
+	// CategoryEvasion represents characters that hide or imitate emojis: stray invisible
+	// characters (e.g., U+200B, U+FE0F) and pictographs outside the emoji ranges (e.g., 🅰, ⯑)
+	CategoryEvasion EmojiCategory = "evasion"
+
+	// CategoryHygiene represents invisible characters that hide code or text: zero width
+	// spaces, byte order marks after the start of a file and bidirectional controls
+	CategoryHygiene EmojiCategory = "hygiene"
 )
 
+// Categories returns every emoji category.
+func Categories() []EmojiCategory {
+	return []EmojiCategory{
+		CategoryUnicode, CategoryEmoticon, CategoryCustom, CategoryShortcode, CategoryKaomoji,
+		CategoryDecorative, CategoryEscaped, CategoryEvasion, CategoryHygiene,
+	}
+}
+
+// Severities of findings, set per category or emoji by a profile's severity map.
+const (
+	// SeverityError findings count against thresholds and budgets and fail the run
+	SeverityError = "error"
+	// SeverityWarn findings are reported but never fail the run
+	SeverityWarn = "warn"
+)
+
+// IsWarning reports whether the policy only warns about the match.
+func (m EmojiMatch) IsWarning() bool {
+	return m.Severity == SeverityWarn
+}
+
+// Display returns the match as shown in messages: the emoji, or the code points of
+// the invisible characters of hygiene and evasion findings, such as U+200B.
+func (m EmojiMatch) Display() string {
+	first, _ := utf8.DecodeRuneInString(m.Emoji)
+	if (m.Category != CategoryHygiene && m.Category != CategoryEvasion) ||
+		(unicode.IsGraphic(first) && !unicode.Is(unicode.Variation_Selector, first)) {
+		return m.Emoji
+	}
+	points := make([]string, 0, len(m.Emoji))
+	for _, r := range m.Emoji {
+		points = append(points, fmt.Sprintf("U+%04X", r))
+	}
+	return strings.Join(points, " ")
+}
+
 // DetectionResult contains the results of emoji detection on content.
 type DetectionResult struct {
 	// Emojis contains all detected emoji matches
@@ -61,6 +132,9 @@ type DetectionResult struct {
 	// Success indicates if detection completed successfully
 	Success bool `json:"success"`
 
+	// Lines is the number of lines in the content analyzed
+	Lines int `json:"lines,omitempty"`
+
 	// ContentSize is the size of the content analyzed in bytes
 	ContentSize int `json:"content_size,omitempty"`
 
@@ -69,6 +143,20 @@ type DetectionResult struct {
 
 	// PatternsApplied is the number of patterns applied during detection
 	PatternsApplied int `json:"patterns_applied,omitempty"`
+
+	// SuppressedRegions are the regions between antimoji:off and antimoji:on markers,
+	// whose emojis are not reported
+	SuppressedRegions []SuppressedRegion `json:"suppressed_regions,omitempty"`
+}
+
+// SuppressedRegion is a region of content where detection is disabled.
+type SuppressedRegion struct {
+	// StartLine is the line of the antimoji:off marker
+	StartLine int `json:"start_line"`
+	// EndLine is the line of the antimoji:on marker, or the last line without one
+	EndLine int `json:"end_line"`
+	// Suppressed is the number of emojis found in the region and not reported
+	Suppressed int `json:"suppressed"`
 }
 
 // Reset clears the DetectionResult for reuse.
@@ -77,8 +165,10 @@ func (dr *DetectionResult) Reset() {
 	dr.TotalCount = 0
 	dr.UniqueCount = 0
 	dr.ProcessedBytes = 0
+	dr.Lines = 0
 	dr.Duration = 0
 	dr.Success = false
+	dr.SuppressedRegions = nil
 }
 
 // AddEmoji adds an emoji match to the detection result.
@@ -108,6 +198,120 @@ type EmojiPatterns struct {
 
 	// CustomPatterns contains patterns for custom emoji syntax
 	CustomPatterns []string
+
+	// Shortcodes maps shortcode names (without colons) to the emojis they stand for;
+	// their :name: aliases are detected
+	Shortcodes map[string]string
+
+	// KaomojiPatterns contains literal kaomoji; when non-empty, kaomoji built from
+	// typical Unicode face characters are detected as well
+	KaomojiPatterns []string
+
+	// Escapes are the escape syntaxes decoded to find escaped emojis; zero decodes none.
+	// They depend on the file type, so they are set per file rather than by default.
+	Escapes EscapeSyntax
+
+	// Comments is the comment syntax of the file's language. When set, antimoji:off and
+	// antimoji:on markers only count inside comments; like Escapes, it is set per file.
+	Comments CommentSyntax
+
+	// StringDelimiters are the string quotes of the file's language, so that comment
+	// markers inside string literals are not taken for comments
+	StringDelimiters []string
+
+	// MarkComments sets InComment on the matches inside comments; FilterPatterns
+	// copies it from ProcessingConfig.MarkComments
+	MarkComments bool
+
+	// DecorativeRanges contains Unicode ranges of decorative symbols. They take
+	// precedence over UnicodeRanges unless followed by the emoji variation selector.
+	DecorativeRanges []UnicodeRange
+
+	// EvasionRanges contains Unicode ranges of invisible characters and of pictographs
+	// that evade emoji detection. Invisible characters only match outside emoji
+	// sequences and the scripts that need them; pictographs only match where no other
+	// range does.
+	EvasionRanges []UnicodeRange
+
+	// HygieneRanges contains Unicode ranges of invisible characters reported one by
+	// one, such as zero width spaces and bidirectional controls. A byte order mark at
+	// the start of the content is not reported.
+	HygieneRanges []UnicodeRange
+}
+
+// EscapeSyntax is a set of syntaxes that spell a code point as an escape.
+type EscapeSyntax int
+
+const (
+	// EscapeHTMLEntity is a numeric character reference: &#x1F600; or &#128512;
+	EscapeHTMLEntity EscapeSyntax = 1 << iota
+	// EscapeUTF16 is a four-digit escape, with surrogate pairs for emojis outside the
+	// Basic Multilingual Plane: \u2705 or \uD83D\uDE00 (JavaScript, JSON, Java)
+	EscapeUTF16
+	// EscapeUnicodeBraces is a braced escape: \u{1F600} (JavaScript, Rust, Swift, Ruby)
+	EscapeUnicodeBraces
+	// EscapeUnicodeLong is an eight-digit escape: \U0001F600 (Python, Go, C)
+	EscapeUnicodeLong
+)
+
+// Language describes how files of a programming or markup language are written.
+type Language struct {
+	// Name identifies the language, e.g. python
+	Name string
+	// Extensions are the file extensions of the language, with the leading dot
+	Extensions []string
+	// Filenames are whole file names of the language, e.g. Makefile
+	Filenames []string
+	// Interpreters are the shebang interpreters of scripts in the language, e.g. python3
+	Interpreters []string
+	// Comments is the comment syntax of the language
+	Comments CommentSyntax
+	// StringDelimiters are the quotes that open and close string literals
+	StringDelimiters []string
+	// Escapes are the escape syntaxes the language uses to spell code points
+	Escapes EscapeSyntax
+	// CodeFences marks Markdown-like languages with fenced code blocks and inline code
+	CodeFences bool
+}
+
+// StructuredPolicy selects the parts of YAML, JSON and TOML files whose emojis are left
+// alone. Comments are always checked, and files that fail to parse are handled as plain
+// text.
+type StructuredPolicy struct {
+	// AllowKeys leaves emojis in mapping keys and table names alone
+	AllowKeys bool
+	// AllowValues leaves emojis in scalar values alone
+	AllowValues bool
+}
+
+// IsZero reports whether the policy checks structured files like any other.
+func (p StructuredPolicy) IsZero() bool {
+	return !p.AllowKeys && !p.AllowValues
+}
+
+// CommentSyntax describes the comments of a language.
+type CommentSyntax struct {
+	// Line are the markers that start a comment running to the end of the line
+	Line []string
+	// Block are the delimiters of comments that may span lines
+	Block []BlockComment
+}
+
+// IsZero reports whether the syntax has no comments.
+func (c CommentSyntax) IsZero() bool {
+	return len(c.Line) == 0 && len(c.Block) == 0
+}
+
+// BlockComment holds the delimiters of a block comment, e.g. /* and */.
+type BlockComment struct {
+	Start string
+	End   string
+}
+
+// LanguageLookup identifies the language of a file from its path and, for scripts
+// without an extension, the shebang line at the start of its content.
+type LanguageLookup interface {
+	Lookup(filePath string, content []byte) (Language, bool)
 }
 
 // UnicodeRange represents a range of Unicode code points for emoji detection.
@@ -133,11 +337,139 @@ type ProcessingConfig struct {
 	// EnableCustom controls custom pattern detection
 	EnableCustom bool
 
+	// EnableShortcodes controls shortcode detection
+	EnableShortcodes bool
+
+	// EnableKaomoji controls kaomoji detection
+	EnableKaomoji bool
+
+	// EnableDecorative controls decorative symbol detection
+	EnableDecorative bool
+
+	// EnableEvasion controls detection of invisible characters and lookalike
+	// pictographs used to evade emoji detection
+	EnableEvasion bool
+
+	// EnableHygiene controls detection of zero width spaces, misplaced byte order marks
+	// and bidirectional controls
+	EnableHygiene bool
+
+	// EnableEscapes controls detection of emojis written as escapes, using the escape
+	// syntaxes of each file's type
+	EnableEscapes bool
+
+	// NormalizeShortcodes selects how cleaning rewrites shortcodes and the emojis they
+	// stand for before removing emojis
+	NormalizeShortcodes ShortcodeNormalization
+
+	// PreserveMarkdownCode leaves emojis in the fenced code blocks and inline code spans
+	// of Markdown files undetected
+	PreserveMarkdownCode bool
+
+	// Structured selects the parts of YAML, JSON and TOML files whose emojis go
+	// undetected
+	Structured StructuredPolicy
+
+	// MarkComments marks the matches inside comments, which custom rules tell from
+	// code
+	MarkComments bool
+
+	// Languages identifies the language of each file, which decides its comments,
+	// escapes and code fences; nil uses the built-in languages
+	Languages LanguageLookup
+
 	// MaxFileSize limits the size of files to process (in bytes)
 	MaxFileSize int64
 
 	// BufferSize controls the size of read buffers
 	BufferSize int
+
+	// Sniff controls how files are classified as text or binary
+	Sniff SniffConfig
+
+	// Retry controls how reads failing with a transient error are retried
+	Retry RetryConfig
+}
+
+// ShortcodeNormalization selects how cleaning rewrites emoji shortcodes.
+type ShortcodeNormalization int
+
+const (
+	// NormalizeNone leaves shortcodes and emojis as they are written
+	NormalizeNone ShortcodeNormalization = iota
+	// NormalizeToUnicode rewrites shortcodes as the emojis they stand for
+	NormalizeToUnicode
+	// NormalizeToShortcode rewrites emojis that have a shortcode as that shortcode
+	NormalizeToShortcode
+)
+
+// SniffConfig controls how the start of a file is examined to tell text from binary.
+// Zero values use the defaults of DefaultSniffConfig.
+type SniffConfig struct {
+	// SampleSize is how many leading bytes are examined
+	SampleSize int
+
+	// MaxNullRatio is the largest share of NUL bytes a text file may contain (0-1);
+	// UTF-16 is recognised before this check
+	MaxNullRatio float64
+
+	// MaxControlRatio is the largest share of control characters other than tab,
+	// newline and carriage return a text file may contain (0-1)
+	MaxControlRatio float64
+}
+
+// DefaultSniffConfig returns the default text/binary classification settings.
+func DefaultSniffConfig() SniffConfig {
+	return SniffConfig{
+		SampleSize:      1024,
+		MaxNullRatio:    0,
+		MaxControlRatio: 0.30,
+	}
+}
+
+// WithDefaults returns c with zero fields replaced by their defaults.
+func (c SniffConfig) WithDefaults() SniffConfig {
+	defaults := DefaultSniffConfig()
+	if c.SampleSize <= 0 {
+		c.SampleSize = defaults.SampleSize
+	}
+	if c.MaxNullRatio <= 0 {
+		c.MaxNullRatio = defaults.MaxNullRatio
+	}
+	if c.MaxControlRatio <= 0 {
+		c.MaxControlRatio = defaults.MaxControlRatio
+	}
+	return c
+}
+
+// DefaultRetryBackoff is the wait before the first retry of a failed read when a
+// RetryConfig leaves Backoff unset.
+const DefaultRetryBackoff = 100 * time.Millisecond
+
+// RetryConfig controls how reads that fail with a transient error, as network file
+// systems report sporadically, are retried.
+type RetryConfig struct {
+	// Attempts is how many times a failed read is retried; zero reads once
+	Attempts int
+
+	// Backoff is the wait before the first retry, doubled before each further one
+	Backoff time.Duration
+}
+
+// DefaultRetryConfig returns the default retry settings.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		Attempts: 2,
+		Backoff:  DefaultRetryBackoff,
+	}
+}
+
+// WithDefaults returns c with an unset backoff replaced by DefaultRetryBackoff.
+func (c RetryConfig) WithDefaults() RetryConfig {
+	if c.Backoff <= 0 {
+		c.Backoff = DefaultRetryBackoff
+	}
+	return c
 }
 
 // DefaultProcessingConfig returns a default configuration for emoji detection.
@@ -148,6 +480,8 @@ func DefaultProcessingConfig() ProcessingConfig {
 		EnableCustom:    true,
 		MaxFileSize:     100 * 1024 * 1024, // 100MB
 		BufferSize:      64 * 1024,         // 64KB
+		Sniff:           DefaultSniffConfig(),
+		Retry:           DefaultRetryConfig(),
 	}
 }
 
@@ -164,4 +498,8 @@ type ProcessResult struct {
 	Error           error           `json:"error,omitempty"`
 	Modified        bool            `json:"modified"`
 	BackupPath      string          `json:"backup_path,omitempty"`
+	// Encoding is set for text files that are not UTF-8, e.g. utf-16le
+	Encoding string `json:"encoding,omitempty"`
+	// BinaryReason is set when the file was skipped as binary, e.g. invalid_utf8
+	BinaryReason string `json:"binary_reason,omitempty"`
 }
@@ -284,6 +284,25 @@ func BenchmarkUnicodeRange_Contains(b *testing.B) {
 }
 
 // Example usage for documentation
+func TestEmojiMatch_Display(t *testing.T) {
+	tests := []struct {
+		name  string
+		match EmojiMatch
+		want  string
+	}{
+		{"emoji", EmojiMatch{Emoji: "😀", Category: CategoryUnicode}, "😀"},
+		{"visible pictograph", EmojiMatch{Emoji: "\U0001F170\ufe0f", Category: CategoryEvasion}, "\U0001F170\ufe0f"},
+		{"zero width space", EmojiMatch{Emoji: "\u200b", Category: CategoryHygiene}, "U+200B"},
+		{"run of invisible characters", EmojiMatch{Emoji: "\u200b\u200c", Category: CategoryEvasion}, "U+200B U+200C"},
+		{"variation selector", EmojiMatch{Emoji: "\ufe0f", Category: CategoryEvasion}, "U+FE0F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.match.Display())
+		})
+	}
+}
+
 func ExampleDetectionResult_AddEmoji() {
 	result := DetectionResult{}
 
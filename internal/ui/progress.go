@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// progressInterval is how often the progress line is redrawn at most.
+const progressInterval = 100 * time.Millisecond
+
+// progressPathWidth is the number of path characters shown on the progress line.
+const progressPathWidth = 40
+
+// ProgressReporter renders a single, continuously rewritten line with the files done,
+// throughput, estimated time left and the current path of a batch of files. It is safe
+// for concurrent use, so it can be fed directly by the processing workers.
+type ProgressReporter struct {
+	mu      sync.Mutex
+	out     io.Writer
+	total   int
+	files   int
+	bytes   int64
+	current string
+	start   time.Time
+	drawn   time.Time
+	now     func() time.Time
+	visible bool
+}
+
+// NewProgressReporter creates a reporter for a batch of total files that draws on out,
+// normally a terminal's stderr.
+func NewProgressReporter(out io.Writer, total int) *ProgressReporter {
+	return newProgressReporter(out, total, time.Now)
+}
+
+// newProgressReporter creates a reporter reading the time from now.
+func newProgressReporter(out io.Writer, total int, now func() time.Time) *ProgressReporter {
+	return &ProgressReporter{out: out, total: total, now: now, start: now()}
+}
+
+// FileDone records a finished file of size bytes, redrawing the line when it is due.
+// Its signature matches processor.ProgressFunc.
+func (p *ProgressReporter) FileDone(path string, size int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.files++
+	p.bytes += size
+	p.current = path
+
+	now := p.now()
+	if p.visible && now.Sub(p.drawn) < progressInterval && p.files < p.total {
+		return
+	}
+	p.drawn = now
+	p.visible = true
+	_, _ = fmt.Fprintf(p.out, "\r\033[K%s", p.line(now))
+}
+
+// Finish clears the progress line so that later output starts on a clean line.
+func (p *ProgressReporter) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.visible {
+		_, _ = fmt.Fprint(p.out, "\r\033[K")
+		p.visible = false
+	}
+}
+
+// line formats the progress at now.
+func (p *ProgressReporter) line(now time.Time) string {
+	elapsed := now.Sub(p.start).Seconds()
+	var filesPerSec, bytesPerSec float64
+	if elapsed > 0 {
+		filesPerSec = float64(p.files) / elapsed
+		bytesPerSec = float64(p.bytes) / elapsed
+	}
+
+	eta := "--"
+	if filesPerSec > 0 {
+		remaining := time.Duration(float64(p.total-p.files) / filesPerSec * float64(time.Second))
+		eta = remaining.Round(time.Second).String()
+	}
+
+	return fmt.Sprintf("%d/%d files  %.0f files/s  %s/s  ETA %s  %s",
+		p.files, p.total, filesPerSec, humanize.Bytes(uint64(bytesPerSec)), eta, shortenPath(p.current, progressPathWidth))
+}
+
+// shortenPath keeps the end of path, which names the file, within width characters.
+func shortenPath(path string, width int) string {
+	runes := []rune(path)
+	if len(runes) <= width {
+		return path
+	}
+	return "…" + strings.TrimLeft(string(runes[len(runes)-width+1:]), "/")
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressReporter(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	reporter := newProgressReporter(&out, 4, clock)
+
+	now = now.Add(time.Second)
+	reporter.FileDone("src/a.go", 1000)
+	assert.Equal(t, "\r\033[K1/4 files  1 files/s  1.0 kB/s  ETA 3s  src/a.go", out.String())
+
+	// Redraws are throttled until the last file
+	out.Reset()
+	reporter.FileDone("src/b.go", 1000)
+	assert.Empty(t, out.String())
+
+	now = now.Add(time.Second)
+	reporter.FileDone("src/c.go", 2000)
+	assert.Equal(t, "\r\033[K3/4 files  2 files/s  2.0 kB/s  ETA 1s  src/c.go", out.String())
+
+	out.Reset()
+	reporter.FileDone("src/d.go", 0)
+	assert.Contains(t, out.String(), "4/4 files")
+	assert.Contains(t, out.String(), "ETA 0s")
+
+	out.Reset()
+	reporter.Finish()
+	assert.Equal(t, "\r\033[K", out.String())
+	reporter.Finish()
+	assert.Equal(t, "\r\033[K", out.String(), "a cleared line is not cleared again")
+}
+
+func TestProgressReporter_FinishWithoutFiles(t *testing.T) {
+	var out bytes.Buffer
+	NewProgressReporter(&out, 10).Finish()
+	assert.Empty(t, out.String())
+}
+
+func TestShortenPath(t *testing.T) {
+	assert.Equal(t, "short.go", shortenPath("short.go", 10))
+	long := strings.Repeat("dir/", 20) + "file.go"
+	short := shortenPath(long, 20)
+	assert.True(t, strings.HasPrefix(short, "…"))
+	assert.True(t, strings.HasSuffix(short, "file.go"))
+	assert.LessOrEqual(t, len([]rune(short)), 20)
+}
@@ -0,0 +1,64 @@
+// Package ui provides interactive prompting utilities for the Antimoji CLI.
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prompter asks the user questions on a line-oriented terminal.
+type Prompter struct {
+	reader *bufio.Reader
+	writer io.Writer
+}
+
+// NewPrompter creates a prompter reading answers from in and writing questions to out.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{
+		reader: bufio.NewReader(in),
+		writer: out,
+	}
+}
+
+// Printf writes formatted text to the prompter output.
+func (p *Prompter) Printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(p.writer, format, args...)
+}
+
+// Ask shows a question and returns the trimmed answer.
+// io.EOF is returned when the input is exhausted.
+func (p *Prompter) Ask(question string) (string, error) {
+	_, _ = fmt.Fprint(p.writer, question)
+
+	line, err := p.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// Choose asks until the answer matches one of the given single-letter choices.
+// The first choice is used when the user just presses enter.
+func (p *Prompter) Choose(question string, choices []string) (string, error) {
+	for {
+		answer, err := p.Ask(fmt.Sprintf("%s [%s] ", question, strings.Join(choices, "/")))
+		if err != nil {
+			return "", err
+		}
+
+		answer = strings.ToLower(answer)
+		if answer == "" && len(choices) > 0 {
+			return choices[0], nil
+		}
+		for _, choice := range choices {
+			if answer == choice {
+				return choice, nil
+			}
+		}
+
+		_, _ = fmt.Fprintf(p.writer, "Please answer one of: %s\n", strings.Join(choices, ", "))
+	}
+}
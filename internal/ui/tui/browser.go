@@ -0,0 +1,458 @@
+// Package tui implements the interactive results browser of 'antimoji scan --tui'.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// File is a file with emoji violations listed by the browser.
+type File struct {
+	Path    string
+	Matches []types.EmojiMatch
+}
+
+// Actions are the operations the browser runs for the user. The browser reloads a file
+// through Load after changing it, so its list always shows the current violations.
+type Actions struct {
+	// Load reads the file at path and detects its violations again
+	Load func(path string) (content string, matches []types.EmojiMatch, err error)
+	// Clean removes the violations from the file at path
+	Clean func(path string) error
+	// Allow adds emoji to the allowlist of the configuration
+	Allow func(emoji string) error
+	// Edit returns the command opening the file at path on line in the user's editor
+	Edit func(path string, line int) *exec.Cmd
+}
+
+// pane is the part of the browser that the cursor keys move in.
+type pane int
+
+const (
+	filesPane pane = iota
+	matchesPane
+)
+
+// Default terminal size until the first window size message arrives.
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// chromeLines are the lines of the header, separator, status and help.
+const chromeLines = 4
+
+var (
+	headerStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle  = lipgloss.NewStyle().Reverse(true)
+	emojiStyle     = lipgloss.NewStyle().Bold(true).Underline(true)
+	dimStyle       = lipgloss.NewStyle().Faint(true)
+	separatorStyle = lipgloss.NewStyle().Faint(true)
+)
+
+// Model is the bubbletea model of the results browser.
+type Model struct {
+	files   []File
+	actions Actions
+	// contents holds the loaded file contents shown in the preview
+	contents map[string]string
+
+	cursor int // selected file
+	match  int // selected match of the selected file
+	focus  pane
+	// fileOffset and matchOffset are the first file and match on screen
+	fileOffset  int
+	matchOffset int
+
+	width  int
+	height int
+	status string
+}
+
+// editedMsg reports that the editor opened on path has exited.
+type editedMsg struct {
+	path string
+	err  error
+}
+
+// New returns a browser over files.
+func New(files []File, actions Actions) Model {
+	m := Model{
+		files:    files,
+		actions:  actions,
+		contents: make(map[string]string),
+		width:    defaultWidth,
+		height:   defaultHeight,
+	}
+	m.load()
+	return m
+}
+
+// Run shows the browser until the user quits.
+func Run(files []File, actions Actions, in io.Reader, out io.Writer) error {
+	program := tea.NewProgram(New(files, actions), tea.WithAltScreen(), tea.WithInput(in), tea.WithOutput(out))
+	_, err := program.Run()
+	return err
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+	case editedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Editor failed: %v", msg.err)
+		} else {
+			m.status = ""
+		}
+		m.reload(msg.path)
+	case tea.KeyMsg:
+		return m.key(msg)
+	}
+	return m, nil
+}
+
+// key handles a key press.
+func (m Model) key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.paneHeight())
+	case "pgdown":
+		m.move(m.paneHeight())
+	case "tab", "enter":
+		if m.focus == filesPane && len(m.files) > 0 {
+			m.focus = matchesPane
+		} else {
+			m.focus = filesPane
+		}
+	case "c":
+		file, ok := m.selected()
+		if !ok || m.actions.Clean == nil {
+			break
+		}
+		if err := m.actions.Clean(file.Path); err != nil {
+			m.status = fmt.Sprintf("Clean failed: %v", err)
+			break
+		}
+		m.status = fmt.Sprintf("Cleaned %s", file.Path)
+		m.reload(file.Path)
+	case "a":
+		match, ok := m.selectedMatch()
+		if !ok || m.actions.Allow == nil {
+			break
+		}
+		if err := m.actions.Allow(match.Emoji); err != nil {
+			m.status = fmt.Sprintf("Allowlist update failed: %v", err)
+			break
+		}
+		m.status = fmt.Sprintf("Added %s to the allowlist", match.Emoji)
+		m.dropEmoji(match.Emoji)
+	case "e":
+		file, ok := m.selected()
+		if !ok || m.actions.Edit == nil {
+			break
+		}
+		line := 1
+		if match, ok := m.selectedMatch(); ok {
+			line = match.Line
+		}
+		path := file.Path
+		return m, tea.ExecProcess(m.actions.Edit(path, line), func(err error) tea.Msg {
+			return editedMsg{path: path, err: err}
+		})
+	}
+	return m, nil
+}
+
+// selected returns the selected file.
+func (m Model) selected() (File, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.files) {
+		return File{}, false
+	}
+	return m.files[m.cursor], true
+}
+
+// selectedMatch returns the selected match, the first of the file unless the preview
+// has the focus.
+func (m Model) selectedMatch() (types.EmojiMatch, bool) {
+	file, ok := m.selected()
+	if !ok || len(file.Matches) == 0 {
+		return types.EmojiMatch{}, false
+	}
+	if m.focus == matchesPane && m.match < len(file.Matches) {
+		return file.Matches[m.match], true
+	}
+	return file.Matches[0], true
+}
+
+// move moves the cursor of the focused pane by delta, loading a newly selected file.
+func (m *Model) move(delta int) {
+	if m.focus == matchesPane {
+		if file, ok := m.selected(); ok {
+			m.match = clamp(m.match+delta, len(file.Matches))
+		}
+	} else if cursor := clamp(m.cursor+delta, len(m.files)); cursor != m.cursor {
+		m.cursor = cursor
+		m.match = 0
+		m.load()
+	}
+	m.scroll()
+}
+
+// load reads the selected file for the preview unless it is loaded already.
+func (m *Model) load() {
+	file, ok := m.selected()
+	if !ok {
+		return
+	}
+	if _, loaded := m.contents[file.Path]; !loaded {
+		m.reload(file.Path)
+	}
+}
+
+// reload reads path again after it was changed, dropping it from the list when no
+// violations are left.
+func (m *Model) reload(path string) {
+	if m.actions.Load == nil {
+		return
+	}
+	content, matches, err := m.actions.Load(path)
+	if err != nil {
+		m.status = fmt.Sprintf("Cannot read %s: %v", path, err)
+		return
+	}
+	m.contents[path] = content
+	for i := range m.files {
+		if m.files[i].Path == path {
+			m.files[i].Matches = matches
+		}
+	}
+	m.prune()
+}
+
+// dropEmoji removes the matches of an emoji that became allowed.
+func (m *Model) dropEmoji(emoji string) {
+	for i := range m.files {
+		kept := m.files[i].Matches[:0:0]
+		for _, match := range m.files[i].Matches {
+			if match.Emoji != emoji {
+				kept = append(kept, match)
+			}
+		}
+		m.files[i].Matches = kept
+	}
+	m.prune()
+}
+
+// prune removes the files without violations and keeps the cursors in range.
+func (m *Model) prune() {
+	selected, _ := m.selected()
+	kept := m.files[:0]
+	for i, file := range m.files {
+		if len(file.Matches) > 0 {
+			kept = append(kept, file)
+		} else if i < m.cursor {
+			m.cursor--
+		}
+	}
+	m.files = kept
+	m.cursor = clamp(m.cursor, len(m.files))
+	if file, ok := m.selected(); !ok || file.Path != selected.Path {
+		m.match = 0
+		m.focus = filesPane
+	}
+	if file, ok := m.selected(); ok {
+		m.match = clamp(m.match, len(file.Matches))
+	}
+	m.load()
+	m.scroll()
+}
+
+// paneHeight is the number of rows of the file list; the preview gets the rest.
+func (m Model) paneHeight() int {
+	rows := (m.height - chromeLines) / 2
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// previewHeight is the number of rows of the preview, including its title.
+func (m Model) previewHeight() int {
+	rows := m.height - chromeLines - m.paneHeight()
+	if rows < 2 {
+		rows = 2
+	}
+	return rows
+}
+
+// scroll keeps the cursors on screen.
+func (m *Model) scroll() {
+	m.fileOffset = follow(m.fileOffset, m.cursor, m.paneHeight())
+	m.matchOffset = follow(m.matchOffset, m.match, m.previewHeight()-1)
+}
+
+// follow returns the offset of a window of size rows that shows index.
+func follow(offset, index, rows int) int {
+	if rows < 1 {
+		rows = 1
+	}
+	if index < offset {
+		return index
+	}
+	if index >= offset+rows {
+		return index - rows + 1
+	}
+	return offset
+}
+
+// clamp limits index to [0, n).
+func clamp(index, n int) int {
+	if index >= n {
+		index = n - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	return index
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	var b strings.Builder
+
+	total := 0
+	for _, file := range m.files {
+		total += len(file.Matches)
+	}
+	b.WriteString(headerStyle.Render(fmt.Sprintf("antimoji scan: %d emojis in %d files", total, len(m.files))))
+	b.WriteString("\n")
+
+	rows := m.paneHeight()
+	for i := m.fileOffset; i < m.fileOffset+rows; i++ {
+		if i < len(m.files) {
+			b.WriteString(m.fileRow(i))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(separatorStyle.Render(strings.Repeat("─", m.width)))
+	b.WriteString("\n")
+
+	b.WriteString(m.preview())
+
+	b.WriteString(m.status)
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("↑/↓ move  tab files/matches  c clean file  a allow emoji  e edit  q quit"))
+	return b.String()
+}
+
+// fileRow renders the file at index i in the list.
+func (m Model) fileRow(i int) string {
+	file := m.files[i]
+	count := fmt.Sprintf("%d", len(file.Matches))
+	row := truncate(file.Path, m.width-len(count)-3)
+	row = " " + row + strings.Repeat(" ", max(1, m.width-len(count)-lipgloss.Width(row)-2)) + count
+	if i == m.cursor {
+		if m.focus == filesPane {
+			return selectedStyle.Render(row)
+		}
+		return headerStyle.Render(row)
+	}
+	return row
+}
+
+// preview renders the matches of the selected file with their lines.
+func (m Model) preview() string {
+	var b strings.Builder
+	rows := m.previewHeight()
+	file, ok := m.selected()
+	if !ok {
+		b.WriteString("No emojis left.\n")
+		return b.String() + strings.Repeat("\n", rows-1)
+	}
+
+	b.WriteString(headerStyle.Render(file.Path))
+	b.WriteString("\n")
+	content := m.contents[file.Path]
+	for i := m.matchOffset; i < m.matchOffset+rows-1; i++ {
+		if i < len(file.Matches) {
+			row := m.matchRow(content, file.Matches[i])
+			if m.focus == matchesPane && i == m.match {
+				row = selectedStyle.Render(">") + row[1:]
+			}
+			b.WriteString(row)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// matchRow renders a match as its position and its line with the emoji highlighted.
+func (m Model) matchRow(content string, match types.EmojiMatch) string {
+	position := fmt.Sprintf(" %5d:%-4d ", match.Line, match.Column)
+	width := m.width - len(position)
+
+	// Offsets index the UTF-8 content; files in other encodings show only the emoji
+	if match.Start < 0 || match.End > len(content) || match.Start >= match.End || content[match.Start:match.End] != match.Emoji {
+		return position + emojiStyle.Render(match.Emoji)
+	}
+	lineStart := strings.LastIndexByte(content[:match.Start], '\n') + 1
+	lineEnd := len(content)
+	if end := strings.IndexByte(content[match.End:], '\n'); end >= 0 {
+		lineEnd = match.End + end
+	}
+	before := strings.TrimLeft(content[lineStart:match.Start], " \t")
+	after := strings.TrimRight(content[match.End:lineEnd], " \t\r")
+
+	// Keep the emoji on screen: shorten the text before it, then the text after it
+	room := width - lipgloss.Width(match.Emoji)
+	if lipgloss.Width(before) > room/2 {
+		before = "…" + tail(before, room/2-1)
+	}
+	after = truncate(after, room-lipgloss.Width(before))
+	return position + before + emojiStyle.Render(match.Emoji) + after
+}
+
+// truncate shortens s to at most width cells, marking the cut with an ellipsis.
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return ""
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// tail returns the end of s that fits in width cells.
+func tail(s string, width int) string {
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes)) > width {
+		runes = runes[1:]
+	}
+	return string(runes)
+}
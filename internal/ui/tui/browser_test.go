@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/antimoji/antimoji/internal/types"
+)
+
+// testFiles returns two files with violations and their contents.
+func testFiles() ([]File, map[string]string) {
+	contents := map[string]string{
+		"a.go": "package a\n// ship it 🚀\n// done ✅\n",
+		"b.md": "# Notes 🚀\n",
+	}
+	files := []File{
+		{Path: "a.go", Matches: []types.EmojiMatch{
+			{Emoji: "🚀", Start: 21, End: 25, Line: 2, Column: 10},
+			{Emoji: "✅", Start: 34, End: 37, Line: 3, Column: 9},
+		}},
+		{Path: "b.md", Matches: []types.EmojiMatch{
+			{Emoji: "🚀", Start: 8, End: 12, Line: 1, Column: 9},
+		}},
+	}
+	return files, contents
+}
+
+// testActions returns actions over an in-memory copy of files.
+func testActions(files []File, contents map[string]string) (Actions, *[]string) {
+	var calls []string
+	matches := make(map[string][]types.EmojiMatch)
+	for _, file := range files {
+		matches[file.Path] = file.Matches
+	}
+	return Actions{
+		Load: func(path string) (string, []types.EmojiMatch, error) {
+			content, ok := contents[path]
+			if !ok {
+				return "", nil, errors.New("no such file")
+			}
+			return content, matches[path], nil
+		},
+		Clean: func(path string) error {
+			calls = append(calls, "clean "+path)
+			matches[path] = nil
+			return nil
+		},
+		Allow: func(emoji string) error {
+			calls = append(calls, "allow "+emoji)
+			return nil
+		},
+		Edit: func(path string, line int) *exec.Cmd {
+			return exec.Command("true")
+		},
+	}, &calls
+}
+
+func press(m tea.Model, keys ...string) tea.Model {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestModel_View(t *testing.T) {
+	files, contents := testFiles()
+	actions, _ := testActions(files, contents)
+	m := New(files, actions)
+
+	view := m.View()
+	assert.Contains(t, view, "3 emojis in 2 files")
+	assert.Contains(t, view, "a.go")
+	assert.Contains(t, view, "b.md")
+	assert.Contains(t, view, "// ship it")
+	assert.Contains(t, view, "2:10")
+
+	// Moving down previews the second file
+	view = press(m, "down").View()
+	assert.Contains(t, view, "# Notes")
+}
+
+func TestModel_Clean(t *testing.T) {
+	files, contents := testFiles()
+	actions, calls := testActions(files, contents)
+
+	m := press(New(files, actions), "c").(Model)
+	assert.Equal(t, []string{"clean a.go"}, *calls)
+	require.Len(t, m.files, 1)
+	assert.Equal(t, "b.md", m.files[0].Path)
+	assert.Contains(t, m.View(), "Cleaned a.go")
+}
+
+func TestModel_AllowSelectedMatch(t *testing.T) {
+	files, contents := testFiles()
+	actions, calls := testActions(files, contents)
+
+	// Focus the matches of a.go, select the second and allow it
+	m := press(New(files, actions), "tab", "down", "a").(Model)
+	assert.Equal(t, []string{"allow ✅"}, *calls)
+	require.Len(t, m.files, 2)
+	assert.Len(t, m.files[0].Matches, 1)
+
+	// Allowing the rocket removes it from every file, leaving none
+	m = press(m, "a").(Model)
+	assert.Empty(t, m.files)
+	assert.Contains(t, m.View(), "No emojis left.")
+}
+
+func TestModel_ActionErrors(t *testing.T) {
+	files, contents := testFiles()
+	actions, _ := testActions(files, contents)
+	actions.Clean = func(string) error { return errors.New("read-only") }
+
+	m := press(New(files, actions), "c").(Model)
+	assert.Len(t, m.files, 2)
+	assert.Contains(t, m.View(), "Clean failed: read-only")
+}
+
+func TestModel_Quit(t *testing.T) {
+	files, contents := testFiles()
+	actions, _ := testActions(files, contents)
+
+	_, cmd := New(files, actions).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "long…", truncate("long text", 5))
+	assert.Equal(t, "", truncate("long text", 1))
+	assert.True(t, strings.HasSuffix(tail("abcdef", 3), "def"))
+}